@@ -80,6 +80,9 @@ type ToolCall struct {
 	Arguments    map[string]interface{} `json:"arguments,omitempty"`    // 工具调用参数
 	Result       string                 `json:"result,omitempty"`       // 工具执行结果
 	Timestamp    time.Time              `json:"timestamp"`              // 调用时间戳
+
+	// 开启操作校验时，操作后页面是否发生了预期的变化
+	Verification *ActionVerification `json:"verification,omitempty"`
 }
 
 // ChatSession 聊天会话
@@ -89,6 +92,29 @@ type ChatSession struct {
 	Messages    []ChatMessage `json:"messages"`
 	CreatedAt   time.Time     `json:"created_at"`
 	UpdatedAt   time.Time     `json:"updated_at"`
+
+	// 需要人工批准的工具调用类别（models.ApprovalCategories）
+	ApprovalCategories []string `json:"approval_categories,omitempty"`
+	// 只读会话：只能使用打开和读取页面的工具
+	ReadOnly bool `json:"read_only,omitempty"`
+	// 操作校验：改变页面的工具调用后截图并让 LLM 确认预期的变化已发生
+	VerifyActions bool `json:"verify_actions,omitempty"`
+	// 会话使用的系统提示词 ID（已保存的提示词），为空时使用默认的 Agent 系统提示词
+	SystemPromptID string `json:"system_prompt_id,omitempty"`
+}
+
+// dbModel 会话的数据库模型（调用方持有 am.mu）
+func (s *ChatSession) dbModel() *models.AgentSession {
+	return &models.AgentSession{
+		ID:                 s.ID,
+		LLMConfigID:        s.LLMConfigID,
+		CreatedAt:          s.CreatedAt,
+		UpdatedAt:          s.UpdatedAt,
+		ApprovalCategories: s.ApprovalCategories,
+		ReadOnly:           s.ReadOnly,
+		VerifyActions:      s.VerifyActions,
+		SystemPromptID:     s.SystemPromptID,
+	}
 }
 
 // StreamChunk 流式响应数据块
@@ -98,6 +124,9 @@ type StreamChunk struct {
 	ToolCall  *ToolCall `json:"tool_call,omitempty"`
 	Error     string    `json:"error,omitempty"`
 	MessageID string    `json:"message_id,omitempty"`
+
+	// Type 为 approval_required 时，等待用户批准的工具调用
+	Approval *PendingApproval `json:"approval,omitempty"`
 }
 
 // MCPTool 实现 interfaces.Tool 接口,用于调用本地 MCP 服务
@@ -106,6 +135,15 @@ type MCPTool struct {
 	description string
 	inputSchema map[string]interface{}
 	mcpServer   browsermcp.IMCPServer
+
+	// authorize 执行前检查只读会话的限制和人工批准，返回错误时不执行
+	authorize func(ctx context.Context, name string, args map[string]interface{}) error
+	// readOnly 会话是否为只读会话，只读会话的调用由 MCP 服务再次检查
+	readOnly func(sessionID string) bool
+	// verify 执行成功后校验操作结果，返回（可能追加了校验说明的）工具结果
+	verify func(ctx context.Context, name string, args map[string]interface{}, result string) string
+	// siteNotes 执行成功后返回当前页面匹配的站点知识包，追加到工具结果中
+	siteNotes func(ctx context.Context, name string, args map[string]interface{}, result interface{}) string
 }
 
 func (t *MCPTool) Name() string {
@@ -132,6 +170,12 @@ func (t *MCPTool) Execute(ctx context.Context, input string) (string, error) {
 		return "", fmt.Errorf("failed to parse input parameters: %w", err)
 	}
 
+	if t.authorize != nil {
+		if err := t.authorize(ctx, t.name, args); err != nil {
+			return "", err
+		}
+	}
+
 	// 为浏览器操作创建更长超时的 context（浏览器启动和导航需要更多时间）
 	// Executor 工具（browser_*）使用 120 秒超时，其他工具使用原有 context
 	execCtx := ctx
@@ -149,6 +193,15 @@ func (t *MCPTool) Execute(ctx context.Context, input string) (string, error) {
 		}
 		defer cancel()
 		logger.Info(ctx, "Using extended timeout (120s) for browser tool: %s", t.name)
+
+		// 每个 Agent 会话使用独立的浏览器会话（开启会话隔离时互不干扰）
+		if conversationID, ok := memory.GetConversationID(ctx); ok {
+			execCtx = executor.WithSession(execCtx, executor.AgentSessionID(conversationID))
+		}
+	}
+
+	if conversationID, ok := memory.GetConversationID(ctx); ok && t.readOnly != nil && t.readOnly(conversationID) {
+		execCtx = executor.WithReadOnly(execCtx)
 	}
 
 	// 调用 MCP 服务器执行脚本
@@ -202,6 +255,12 @@ func (t *MCPTool) Execute(ctx context.Context, input string) (string, error) {
 		return string(resultJSON), nil
 	}
 
+	if t.verify != nil {
+		responseText = t.verify(execCtx, t.name, args, responseText)
+	}
+	if t.siteNotes != nil {
+		responseText += t.siteNotes(execCtx, t.name, args, result)
+	}
 	return responseText, nil
 }
 
@@ -271,6 +330,12 @@ type AgentManager struct {
 	ctx              context.Context
 	cancel           context.CancelFunc
 	mcpWatcher       *time.Ticker // MCP 命令监听器
+
+	approvals     *approvalGate   // 工具调用的人工批准
+	verifications verificationLog // 工具调用的操作校验结果
+	routing       llmRouter       // 把简单步骤交给小模型的 LLM 路由
+	scriptIndex   scriptIndex     // 脚本推荐使用的脚本向量缓存
+	siteNotes     siteNotesLog    // 会话已注入的站点知识包
 }
 
 // NewAgentManager 创建 Agent 管理器
@@ -285,6 +350,7 @@ func NewAgentManager(db *storage.BoltDB, mcpServer browsermcp.IMCPServer) (*Agen
 		toolReg:   tools.NewRegistry(),
 		ctx:       ctx,
 		cancel:    cancel,
+		approvals: newApprovalGate(),
 	}
 
 	// 从数据库加载默认 LLM 配置
@@ -292,6 +358,11 @@ func NewAgentManager(db *storage.BoltDB, mcpServer browsermcp.IMCPServer) (*Agen
 		logger.Warn(ctx, "Failed to load LLM configuration: %v (Please configure in LLM Management page)", err)
 	}
 
+	// 加载 LLM 路由策略
+	if err := am.ReloadLLMRouting(); err != nil {
+		logger.Warn(ctx, "Failed to load LLM routing policy: %v", err)
+	}
+
 	// 从数据库加载持久化的会话
 	if err := am.loadSessionsFromDB(); err != nil {
 		logger.Warn(ctx, "Failed to load session: %v", err)
@@ -367,6 +438,8 @@ func (am *AgentManager) initMCPTools() error {
 			description: script.MCPCommandDescription,
 			inputSchema: script.MCPInputSchema,
 			mcpServer:   am.mcpServer,
+			authorize:   am.authorizeToolCall,
+			readOnly:    am.sessionReadOnly,
 		}
 
 		// 包装工具以添加 instructions 参数和捕获执行结果
@@ -459,6 +532,10 @@ func (am *AgentManager) initExecutorTools() error {
 			description: meta.Description,
 			inputSchema: buildInputSchemaFromMetadata(meta),
 			mcpServer:   am.mcpServer,
+			authorize:   am.authorizeToolCall,
+			readOnly:    am.sessionReadOnly,
+			verify:      am.verifyToolCall,
+			siteNotes:   am.siteKnowledgeNotes,
 		}
 
 		// 包装工具以添加 instructions 参数和捕获执行结果
@@ -650,8 +727,13 @@ func (am *AgentManager) ensureAgentInstances(sessionID, llmConfigID string) (*Ag
 		return nil, fmt.Errorf("LLM client is not available")
 	}
 
-	// 创建 Agent 实例
-	agentInstances, err := am.createAgentInstances(llmClient)
+	// 创建 Agent 实例，只读会话只提供只读工具
+	toolList := am.toolReg.List()
+	readOnly := am.sessionReadOnly(sessionID)
+	if readOnly {
+		toolList = filterReadOnlyTools(toolList)
+	}
+	agentInstances, err := am.createAgentInstances(llmClient, am.sessionSystemPrompt(sessionID), toolList, !readOnly)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create agent instances: %w", err)
 	}
@@ -661,9 +743,8 @@ func (am *AgentManager) ensureAgentInstances(sessionID, llmConfigID string) (*Ag
 	am.agents[sessionID] = agentInstances
 	am.mu.Unlock()
 
-	tools := am.toolReg.List()
-	logger.Info(am.ctx, "✓ Created Agent instances for session %s on demand (simple: %d, medium: %d, complex: %d, eval: %d), tools: %d",
-		sessionID, maxIterationsSimple, maxIterationsMedium, maxIterationsComplex, maxIterationsEval, len(tools))
+	logger.Info(am.ctx, "✓ Created Agent instances for session %s on demand (simple: %d, medium: %d, complex: %d, eval: %d), tools: %d, read-only: %v",
+		sessionID, maxIterationsSimple, maxIterationsMedium, maxIterationsComplex, maxIterationsEval, len(toolList), readOnly)
 
 	return agentInstances, nil
 }
@@ -713,6 +794,16 @@ func (am *AgentManager) loadSessionsFromDB() error {
 					toolCall.Arguments = args
 				}
 
+				// 加载操作校验结果
+				if v, ok := tc["verification"].(map[string]interface{}); ok {
+					if data, err := json.Marshal(v); err == nil {
+						var verification ActionVerification
+						if json.Unmarshal(data, &verification) == nil {
+							toolCall.Verification = &verification
+						}
+					}
+				}
+
 				// 加载 timestamp
 				if tsStr, ok := tc["timestamp"].(string); ok {
 					if ts, err := time.Parse(time.RFC3339, tsStr); err == nil {
@@ -739,6 +830,11 @@ func (am *AgentManager) loadSessionsFromDB() error {
 			Messages:    messages,
 			CreatedAt:   dbSession.CreatedAt,
 			UpdatedAt:   dbSession.UpdatedAt,
+
+			ApprovalCategories: dbSession.ApprovalCategories,
+			ReadOnly:           dbSession.ReadOnly,
+			VerifyActions:      dbSession.VerifyActions,
+			SystemPromptID:     dbSession.SystemPromptID,
 		}
 
 		am.sessions[session.ID] = session
@@ -750,23 +846,28 @@ func (am *AgentManager) loadSessionsFromDB() error {
 	return nil
 }
 
-// createAgentInstance 创建指定 maxIterations 的 Agent 实例（使用指定的 LLM client）
-func (am *AgentManager) createAgentInstance(llmClient interfaces.LLM, maxIter int) (*agent.Agent, error) {
+// createAgentInstance 创建指定 maxIterations 的 Agent 实例（使用指定的 LLM client、系统提示词和工具）；
+// withLazyMCP 为 false 时不接入外部 MCP 服务
+func (am *AgentManager) createAgentInstance(llmClient interfaces.LLM, maxIter int, systemPrompt string, toolList []interfaces.Tool, withLazyMCP bool) (*agent.Agent, error) {
 	mem := memory.NewConversationBuffer()
 
 	// 获取LazyMCP配置
-	lazyMCPConfigs, err := am.GetLazyMCPConfigs()
-	if err != nil {
-		logger.Warn(am.ctx, "Failed to get lazy MCP configs: %v", err)
-		lazyMCPConfigs = []agent.LazyMCPConfig{}
+	lazyMCPConfigs := []agent.LazyMCPConfig{}
+	if withLazyMCP {
+		var err error
+		lazyMCPConfigs, err = am.GetLazyMCPConfigs()
+		if err != nil {
+			logger.Warn(am.ctx, "Failed to get lazy MCP configs: %v", err)
+			lazyMCPConfigs = []agent.LazyMCPConfig{}
+		}
 	}
 
 	ag, err := agent.NewAgent(
 		agent.WithLLM(llmClient),
 		agent.WithMemory(mem),
-		agent.WithTools(am.toolReg.List()...),
+		agent.WithTools(toolList...),
 		agent.WithLazyMCPConfigs(lazyMCPConfigs),
-		agent.WithSystemPrompt(am.GetSystemPrompt()),
+		agent.WithSystemPrompt(systemPrompt),
 		agent.WithRequirePlanApproval(false),
 		agent.WithMaxIterations(maxIter),
 		agent.WithLogger(NewAgentLogger()),
@@ -799,22 +900,22 @@ func (am *AgentManager) createEvalAgent(llmClient interfaces.LLM) (*agent.Agent,
 	return ag, nil
 }
 
-// createAgentInstances 为会话创建所有类型的 Agent 实例（使用指定的 LLM client）
-func (am *AgentManager) createAgentInstances(llmClient interfaces.LLM) (*AgentInstances, error) {
+// createAgentInstances 为会话创建所有类型的 Agent 实例（使用指定的 LLM client、系统提示词和工具）
+func (am *AgentManager) createAgentInstances(llmClient interfaces.LLM, systemPrompt string, toolList []interfaces.Tool, withLazyMCP bool) (*AgentInstances, error) {
 	// 创建简单任务 Agent
-	simpleAgent, err := am.createAgentInstance(llmClient, maxIterationsSimple)
+	simpleAgent, err := am.createAgentInstance(llmClient, maxIterationsSimple, systemPrompt, toolList, withLazyMCP)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create simple agent: %w", err)
 	}
 
 	// 创建中等任务 Agent
-	mediumAgent, err := am.createAgentInstance(llmClient, maxIterationsMedium)
+	mediumAgent, err := am.createAgentInstance(llmClient, maxIterationsMedium, systemPrompt, toolList, withLazyMCP)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create medium agent: %w", err)
 	}
 
 	// 创建复杂任务 Agent
-	complexAgent, err := am.createAgentInstance(llmClient, maxIterationsComplex)
+	complexAgent, err := am.createAgentInstance(llmClient, maxIterationsComplex, systemPrompt, toolList, withLazyMCP)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create complex agent: %w", err)
 	}
@@ -1028,8 +1129,8 @@ Response format (JSON only, no explanation, no markdown):
 
 	logger.Info(ctx, "[TaskEval] Evaluating task complexity for message: %s", userMessage)
 
-	// 使用评估 Agent
-	response, err := agentInstances.EvalAgent.Run(evalCtx, evalPrompt)
+	// 使用评估 Agent（路由策略可以把任务评估交给小模型）
+	response, err := am.evalAgentFor(agentInstances).Run(evalCtx, evalPrompt)
 	if err != nil {
 		logger.Warn(ctx, "[TaskEval] Failed to evaluate task complexity: %v, defaulting to no tools", err)
 		return &TaskComplexity{
@@ -1342,8 +1443,15 @@ needTools:
 	agentCtx := multitenancy.WithOrgID(ctx, "browserwing")
 	agentCtx = context.WithValue(agentCtx, memory.ConversationIDKey, sessionID)
 
+	// 推荐与任务相似的脚本工具
+	agentTask := userMessage
+	if recommendations := am.recommendScripts(ctx, sessionID, userMessage); len(recommendations) > 0 {
+		logger.Info(ctx, "[ScriptRecommend] Recommended %d script(s) for session %s: %v", len(recommendations), sessionID, recommendations)
+		agentTask = withScriptRecommendations(userMessage, recommendations)
+	}
+
 	// 使用 Agent 流式处理消息
-	streamEvents, err := ag.RunStream(agentCtx, userMessage)
+	streamEvents, err := ag.RunStream(agentCtx, agentTask)
 	if err != nil {
 		streamChan <- StreamChunk{
 			Type:  "error",
@@ -1355,12 +1463,22 @@ needTools:
 	// 处理流式事件
 	toolCallMap := make(map[string]*ToolCall) // 用于跟踪工具调用状态
 
+	// 对话进行期间把需要批准的工具调用转发给客户端
+	approvalRequests, unsubscribe := am.approvals.subscribe(sessionID)
+	defer unsubscribe()
+
 	for {
 		select {
 		case <-ctx.Done():
 			// 客户端取消请求，停止处理
 			logger.Info(ctx, "Request cancelled by client, stopping message processing")
 			return ctx.Err()
+		case approval := <-approvalRequests:
+			streamChan <- StreamChunk{
+				Type:      "approval_required",
+				Approval:  &approval,
+				MessageID: assistantMsg.ID,
+			}
 		case event, ok := <-streamEvents:
 			if !ok {
 				// 流式事件通道已关闭，处理完成
@@ -1447,6 +1565,11 @@ needTools:
 					}
 				}
 
+				// 开启操作校验时附加校验结果
+				if v := am.verifications.take(sessionID, tc.Name); v != nil {
+					toolCall.Verification = v
+				}
+
 				// 发送工具调用状态
 				streamChan <- StreamChunk{
 					Type:     "tool_call",
@@ -1535,7 +1658,7 @@ processingComplete:
 		logger.Info(ctx, "Saving tool call to DB: name=%s, status=%s, instructions=%s, args=%+v, result_len=%d",
 			tc.ToolName, tc.Status, tc.Instructions, tc.Arguments, len(tc.Result))
 
-		toolCallsData = append(toolCallsData, map[string]interface{}{
+		toolCallData := map[string]interface{}{
 			"tool_name":    tc.ToolName,
 			"status":       tc.Status,
 			"message":      tc.Message,
@@ -1543,7 +1666,11 @@ processingComplete:
 			"arguments":    tc.Arguments,
 			"result":       tc.Result,
 			"timestamp":    tc.Timestamp.Format(time.RFC3339),
-		})
+		}
+		if tc.Verification != nil {
+			toolCallData["verification"] = tc.Verification
+		}
+		toolCallsData = append(toolCallsData, toolCallData)
 	}
 	// 保存助手消息到数据库（跳过AI控制临时会话）
 	if len(sessionID) < 11 || sessionID[:11] != "ai_control_" {
@@ -1560,12 +1687,9 @@ processingComplete:
 		}
 
 		// 更新会话时间戳
-		dbSession := &models.AgentSession{
-			ID:          sessionID,
-			LLMConfigID: session.LLMConfigID, // ✅ 保留 LLMConfigID
-			CreatedAt:   session.CreatedAt,
-			UpdatedAt:   session.UpdatedAt,
-		}
+		am.mu.RLock()
+		dbSession := session.dbModel()
+		am.mu.RUnlock()
 		if err := am.db.SaveAgentSession(dbSession); err != nil {
 			logger.Warn(am.ctx, "Failed to update session timestamp: %v", err)
 		}
@@ -1609,6 +1733,14 @@ func (am *AgentManager) DeleteSession(sessionID string) error {
 
 	delete(am.sessions, sessionID)
 	delete(am.agents, sessionID)
+	am.approvals.forget(sessionID)
+	am.verifications.forget(sessionID)
+	am.siteNotes.forget(sessionID)
+
+	// 释放会话占用的浏览器页面
+	if am.mcpServer != nil {
+		am.mcpServer.ReleaseSession(executor.AgentSessionID(sessionID))
+	}
 
 	// 从数据库删除
 	if err := am.db.DeleteAgentSession(sessionID); err != nil {
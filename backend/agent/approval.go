@@ -0,0 +1,406 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/browserwing/browserwing/executor"
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// approvalTimeout 等待人工批准的最长时间，超时视为拒绝
+const approvalTimeout = 5 * time.Minute
+
+// clickInspectTimeout 检查点击目标的最长时间
+const clickInspectTimeout = 10 * time.Second
+
+// downloadExtensions 打开时按下载处理的文件扩展名
+var downloadExtensions = map[string]bool{
+	".pdf": true, ".zip": true, ".rar": true, ".7z": true, ".tar": true, ".gz": true, ".tgz": true,
+	".exe": true, ".msi": true, ".dmg": true, ".pkg": true, ".deb": true, ".rpm": true, ".apk": true,
+	".csv": true, ".xls": true, ".xlsx": true, ".doc": true, ".docx": true, ".ppt": true, ".pptx": true,
+	".iso": true, ".bin": true,
+}
+
+// clickTarget 点击目标的效果（browser_inspect_element 返回的 click_target）
+type clickTarget struct {
+	SubmitsForm bool   `json:"submits_form"` // 提交按钮（button/input type=submit 或 image，且属于表单）
+	Href        string `json:"href"`         // 所在链接的地址
+	Download    bool   `json:"download"`     // 链接带 download 属性
+}
+
+// PendingApproval 等待人工批准的工具调用
+type PendingApproval struct {
+	ID        string                 `json:"id"`
+	SessionID string                 `json:"session_id"`
+	Category  string                 `json:"category"`
+	ToolName  string                 `json:"tool_name"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+	Reason    string                 `json:"reason"`
+	CreatedAt time.Time              `json:"created_at"`
+	ExpiresAt time.Time              `json:"expires_at"`
+}
+
+type pendingApproval struct {
+	PendingApproval
+	host     string    // 批准后记住的域名
+	decision chan bool // 缓冲为 1，只写入一次
+}
+
+// approvalGate 工具调用的人工批准：挂起需要批准的调用，通知正在进行的对话，等待批准或拒绝
+type approvalGate struct {
+	mu          sync.Mutex
+	pending     map[string]*pendingApproval
+	subscribers map[string]chan PendingApproval // sessionID -> 正在进行的对话
+	hosts       map[string]map[string]bool      // sessionID -> 已批准的域名
+	timeout     time.Duration
+}
+
+func newApprovalGate() *approvalGate {
+	return &approvalGate{
+		pending:     make(map[string]*pendingApproval),
+		subscribers: make(map[string]chan PendingApproval),
+		hosts:       make(map[string]map[string]bool),
+		timeout:     approvalTimeout,
+	}
+}
+
+// isDownloadURL 地址是否指向可下载的文件
+func isDownloadURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && downloadExtensions[strings.ToLower(path.Ext(u.Path))]
+}
+
+// clickIdentifier 点击工具的目标元素，按坐标点击时为空
+func clickIdentifier(name string, args map[string]interface{}) string {
+	key := "identifier"
+	if name == "browser_hover_then_click" {
+		key = "target"
+	}
+	identifier, _ := args[key].(string)
+	return identifier
+}
+
+// needsClickTarget 是否需要先检查点击目标（会话要求批准表单提交或下载时）
+func needsClickTarget(categories []string, name string, args map[string]interface{}) bool {
+	if (name != "browser_click" && name != "browser_hover_then_click") || clickIdentifier(name, args) == "" {
+		return false
+	}
+	for _, c := range categories {
+		if c == models.ApprovalFormSubmit || c == models.ApprovalDownload {
+			return true
+		}
+	}
+	return false
+}
+
+// classify 判断工具调用是否属于会话要求批准的类别，返回类别、说明和批准后记住的域名
+// target 为点击目标的检查结果，needsClickTarget 为 true 但无法检查时为 nil，按可能提交表单或下载处理
+func (g *approvalGate) classify(sessionID string, categories []string, name string, args map[string]interface{}, target *clickTarget) (category, reason, host string) {
+	if len(categories) == 0 {
+		return "", "", ""
+	}
+	required := make(map[string]bool, len(categories))
+	for _, c := range categories {
+		required[c] = true
+	}
+
+	// 执行 JavaScript 和录制的脚本可以做任何事（点击、提交、跳转），启用任一类别时都需要批准
+	if !strings.HasPrefix(name, "browser_") {
+		return models.ApprovalScript, "run script " + name, ""
+	}
+	predicate, _ := args["predicate"].(string)
+	if name == "browser_evaluate" || (name == "browser_wait_for" && strings.TrimSpace(predicate) != "") {
+		return models.ApprovalScript, "run JavaScript in the page", ""
+	}
+
+	switch name {
+	case "browser_navigate", "browser_tabs":
+		rawURL, _ := args["url"].(string)
+		if rawURL == "" || (name == "browser_tabs" && args["action"] != "new") {
+			return "", "", ""
+		}
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return "", "", ""
+		}
+		if required[models.ApprovalDownload] && isDownloadURL(rawURL) {
+			return models.ApprovalDownload, "open downloadable file " + rawURL, ""
+		}
+		host := strings.ToLower(u.Hostname())
+		if required[models.ApprovalNavigation] && host != "" && !g.hostApproved(sessionID, host) {
+			return models.ApprovalNavigation, "navigate to new domain " + host, host
+		}
+	case "browser_fill_form":
+		if submit, _ := args["submit"].(bool); submit && required[models.ApprovalFormSubmit] {
+			return models.ApprovalFormSubmit, "fill and submit a form", ""
+		}
+	case "browser_press_key":
+		key, _ := args["key"].(string)
+		if strings.Contains(strings.ToLower(key), "enter") && required[models.ApprovalFormSubmit] {
+			return models.ApprovalFormSubmit, "press " + key + ", which may submit a form", ""
+		}
+	case "browser_click", "browser_hover_then_click", "browser_click_at":
+		identifier := clickIdentifier(name, args)
+		if needsClickTarget(categories, name, args) {
+			switch {
+			case target == nil:
+				category := models.ApprovalFormSubmit
+				if !required[category] {
+					category = models.ApprovalDownload
+				}
+				return category, "click " + identifier + ", which could not be checked for a form submission or download", ""
+			case target.SubmitsForm && required[models.ApprovalFormSubmit]:
+				return models.ApprovalFormSubmit, "click " + identifier + ", which submits a form", ""
+			case (target.Download || isDownloadURL(target.Href)) && required[models.ApprovalDownload]:
+				return models.ApprovalDownload, "click " + identifier + ", which downloads " + target.Href, ""
+			}
+		}
+		if required[models.ApprovalClick] {
+			if identifier == "" {
+				identifier = fmt.Sprintf("(%v, %v)", args["x"], args["y"])
+			}
+			return models.ApprovalClick, "click " + identifier, ""
+		}
+	}
+	return "", "", ""
+}
+
+func (g *approvalGate) hostApproved(sessionID, host string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.hosts[sessionID][host]
+}
+
+// request 挂起工具调用直到批准，拒绝、超时或对话被取消时返回错误
+func (g *approvalGate) request(ctx context.Context, approval PendingApproval, host string) error {
+	now := time.Now()
+	approval.ID = uuid.New().String()
+	approval.CreatedAt = now
+	approval.ExpiresAt = now.Add(g.timeout)
+	p := &pendingApproval{PendingApproval: approval, host: host, decision: make(chan bool, 1)}
+
+	g.mu.Lock()
+	g.pending[p.ID] = p
+	if subscriber, ok := g.subscribers[p.SessionID]; ok {
+		select {
+		case subscriber <- p.PendingApproval:
+		default:
+		}
+	}
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.pending, p.ID)
+		g.mu.Unlock()
+	}()
+
+	timer := time.NewTimer(g.timeout)
+	defer timer.Stop()
+	select {
+	case approved := <-p.decision:
+		if !approved {
+			return fmt.Errorf("tool call was denied by the user (%s: %s)", p.Category, p.Reason)
+		}
+		if p.host != "" {
+			g.mu.Lock()
+			if g.hosts[p.SessionID] == nil {
+				g.hosts[p.SessionID] = make(map[string]bool)
+			}
+			g.hosts[p.SessionID][p.host] = true
+			g.mu.Unlock()
+		}
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("tool call was not approved within %s (%s: %s)", g.timeout, p.Category, p.Reason)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// resolve 批准或拒绝挂起的工具调用
+func (g *approvalGate) resolve(sessionID, approvalID string, approved bool) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	p, ok := g.pending[approvalID]
+	if !ok || p.SessionID != sessionID {
+		return fmt.Errorf("pending approval not found: %s", approvalID)
+	}
+	delete(g.pending, approvalID)
+	p.decision <- approved
+	return nil
+}
+
+// list 会话中等待批准的工具调用，按创建时间排序
+func (g *approvalGate) list(sessionID string) []PendingApproval {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	approvals := []PendingApproval{}
+	for _, p := range g.pending {
+		if p.SessionID == sessionID {
+			approvals = append(approvals, p.PendingApproval)
+		}
+	}
+	sort.Slice(approvals, func(i, j int) bool { return approvals[i].CreatedAt.Before(approvals[j].CreatedAt) })
+	return approvals
+}
+
+// subscribe 在对话进行期间接收新的批准请求，返回的函数取消订阅
+func (g *approvalGate) subscribe(sessionID string) (<-chan PendingApproval, func()) {
+	ch := make(chan PendingApproval, 10)
+	g.mu.Lock()
+	g.subscribers[sessionID] = ch
+	g.mu.Unlock()
+	return ch, func() {
+		g.mu.Lock()
+		if g.subscribers[sessionID] == ch {
+			delete(g.subscribers, sessionID)
+		}
+		g.mu.Unlock()
+	}
+}
+
+// forget 会话删除时拒绝其挂起的调用并清除已批准的域名
+func (g *approvalGate) forget(sessionID string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for id, p := range g.pending {
+		if p.SessionID == sessionID {
+			delete(g.pending, id)
+			p.decision <- false
+		}
+	}
+	delete(g.hosts, sessionID)
+}
+
+// checkToolApproval 工具调用属于会话要求批准的类别时，挂起直到用户批准
+func (am *AgentManager) checkToolApproval(ctx context.Context, name string, args map[string]interface{}) error {
+	sessionID, ok := memory.GetConversationID(ctx)
+	if !ok {
+		return nil
+	}
+	am.mu.RLock()
+	var categories []string
+	if session, ok := am.sessions[sessionID]; ok {
+		categories = session.ApprovalCategories
+	}
+	am.mu.RUnlock()
+	if len(categories) == 0 {
+		return nil
+	}
+
+	var target *clickTarget
+	if needsClickTarget(categories, name, args) {
+		target = am.inspectClickTarget(ctx, sessionID, name, args)
+	}
+	category, reason, host := am.approvals.classify(sessionID, categories, name, args, target)
+	if category == "" {
+		return nil
+	}
+	arguments := make(map[string]interface{}, len(args))
+	for k, v := range args {
+		if k != "instructions" {
+			arguments[k] = v
+		}
+	}
+	logger.Info(ctx, "Tool call %s in session %s is waiting for approval: %s", name, sessionID, reason)
+	return am.approvals.request(ctx, PendingApproval{
+		SessionID: sessionID,
+		Category:  category,
+		ToolName:  name,
+		Arguments: arguments,
+		Reason:    reason,
+	}, host)
+}
+
+// inspectClickTarget 点击前通过 browser_inspect_element 检查目标是否为提交按钮或下载链接，无法检查时返回 nil
+func (am *AgentManager) inspectClickTarget(ctx context.Context, sessionID, name string, args map[string]interface{}) *clickTarget {
+	if am.mcpServer == nil {
+		return nil
+	}
+	inspectCtx, cancel := context.WithTimeout(ctx, clickInspectTimeout)
+	defer cancel()
+	inspectCtx = executor.WithSession(inspectCtx, executor.AgentSessionID(sessionID))
+	if tabID, _ := args["tab_id"].(string); tabID != "" {
+		inspectCtx = executor.WithTab(inspectCtx, tabID)
+	}
+
+	result, err := am.mcpServer.CallTool(inspectCtx, "browser_inspect_element", map[string]interface{}{
+		"identifier": clickIdentifier(name, args),
+	})
+	if err != nil {
+		logger.Warn(ctx, "Failed to inspect click target for approval: %v", err)
+		return nil
+	}
+	response, _ := result.(map[string]interface{})
+	data, _ := response["data"].(map[string]interface{})
+	raw, err := json.Marshal(data["click_target"])
+	if err != nil || data["click_target"] == nil {
+		return nil
+	}
+	var target clickTarget
+	if err := json.Unmarshal(raw, &target); err != nil {
+		return nil
+	}
+	return &target
+}
+
+// SetApprovalCategories 设置会话中需要人工批准的工具调用类别，空列表表示不需要批准
+func (am *AgentManager) SetApprovalCategories(sessionID string, categories []string) error {
+	normalized := []string{}
+	seen := make(map[string]bool)
+	for _, c := range categories {
+		valid := false
+		for _, known := range models.ApprovalCategories {
+			valid = valid || c == known
+		}
+		if !valid {
+			return fmt.Errorf("unknown approval category %q, expected one of %s", c, strings.Join(models.ApprovalCategories, ", "))
+		}
+		if !seen[c] {
+			seen[c] = true
+			normalized = append(normalized, c)
+		}
+	}
+
+	am.mu.Lock()
+	session, ok := am.sessions[sessionID]
+	if !ok {
+		am.mu.Unlock()
+		return fmt.Errorf("Session not found: %s", sessionID)
+	}
+	session.ApprovalCategories = normalized
+	dbSession := session.dbModel()
+	am.mu.Unlock()
+
+	if am.db != nil {
+		if err := am.db.SaveAgentSession(dbSession); err != nil {
+			return fmt.Errorf("failed to save session: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListPendingApprovals 列出会话中等待批准的工具调用
+func (am *AgentManager) ListPendingApprovals(sessionID string) ([]PendingApproval, error) {
+	if _, err := am.GetSession(sessionID); err != nil {
+		return nil, err
+	}
+	return am.approvals.list(sessionID), nil
+}
+
+// ResolveApproval 批准或拒绝会话中挂起的工具调用
+func (am *AgentManager) ResolveApproval(sessionID, approvalID string, approved bool) error {
+	return am.approvals.resolve(sessionID, approvalID, approved)
+}
@@ -0,0 +1,144 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+)
+
+func TestClassifyToolCall(t *testing.T) {
+	g := newApprovalGate()
+	all := models.ApprovalCategories
+	cases := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"browser_navigate", map[string]interface{}{"url": "https://shop.example.com/cart"}, models.ApprovalNavigation},
+		{"browser_navigate", map[string]interface{}{"url": "https://example.com/report.PDF"}, models.ApprovalDownload},
+		{"browser_tabs", map[string]interface{}{"action": "new", "url": "https://other.example.com"}, models.ApprovalNavigation},
+		{"browser_tabs", map[string]interface{}{"action": "switch", "url": "https://other.example.com"}, ""},
+		{"browser_fill_form", map[string]interface{}{"submit": true}, models.ApprovalFormSubmit},
+		{"browser_fill_form", map[string]interface{}{"submit": false}, ""},
+		{"browser_press_key", map[string]interface{}{"key": "ctrl+Enter"}, models.ApprovalFormSubmit},
+		{"browser_press_key", map[string]interface{}{"key": "Tab"}, ""},
+		{"browser_click", map[string]interface{}{"identifier": "@e3"}, models.ApprovalClick},
+		{"browser_snapshot", nil, ""},
+	}
+	for _, c := range cases {
+		if got, _, _ := g.classify("s1", all, c.name, c.args, &clickTarget{}); got != c.want {
+			t.Errorf("%s %v: expected %q, got %q", c.name, c.args, c.want, got)
+		}
+	}
+	if got, _, _ := g.classify("s1", []string{models.ApprovalFormSubmit}, "browser_click", map[string]interface{}{"identifier": "@e3"}, &clickTarget{}); got != "" {
+		t.Errorf("categories the session doesn't require should pass, got %q", got)
+	}
+}
+
+func TestClassifyClickTargetsAndScripts(t *testing.T) {
+	g := newApprovalGate()
+	submitOnly := []string{models.ApprovalFormSubmit}
+	downloadOnly := []string{models.ApprovalDownload}
+	click := map[string]interface{}{"identifier": "@e3"}
+
+	cases := []struct {
+		categories []string
+		name       string
+		args       map[string]interface{}
+		target     *clickTarget
+		want       string
+	}{
+		{submitOnly, "browser_click", click, &clickTarget{SubmitsForm: true}, models.ApprovalFormSubmit},
+		{submitOnly, "browser_hover_then_click", map[string]interface{}{"trigger": "#menu", "target": "#buy"}, &clickTarget{SubmitsForm: true}, models.ApprovalFormSubmit},
+		{submitOnly, "browser_click", click, &clickTarget{Href: "https://example.com/next"}, ""},
+		{downloadOnly, "browser_click", click, &clickTarget{Href: "https://example.com/export", Download: true}, models.ApprovalDownload},
+		{downloadOnly, "browser_click", click, &clickTarget{Href: "https://example.com/files/report.xlsx"}, models.ApprovalDownload},
+		{downloadOnly, "browser_click", click, &clickTarget{SubmitsForm: true}, ""},
+		// 无法检查点击目标时按可能提交表单处理
+		{submitOnly, "browser_click", click, nil, models.ApprovalFormSubmit},
+		{downloadOnly, "browser_click", click, nil, models.ApprovalDownload},
+		// 启用任一类别时，执行 JavaScript 和脚本工具都需要批准
+		{downloadOnly, "browser_evaluate", map[string]interface{}{"script": "1"}, nil, models.ApprovalScript},
+		{downloadOnly, "browser_wait_for", map[string]interface{}{"predicate": "document.forms[0].submit() || true"}, nil, models.ApprovalScript},
+		{downloadOnly, "browser_wait_for", map[string]interface{}{"text": "Done"}, nil, ""},
+		{downloadOnly, "checkout_order", nil, nil, models.ApprovalScript},
+		{nil, "browser_evaluate", map[string]interface{}{"script": "1"}, nil, ""},
+	}
+	for _, c := range cases {
+		if got, _, _ := g.classify("s1", c.categories, c.name, c.args, c.target); got != c.want {
+			t.Errorf("%v %s %v %+v: expected %q, got %q", c.categories, c.name, c.args, c.target, c.want, got)
+		}
+	}
+	if needsClickTarget([]string{models.ApprovalClick}, "browser_click", click) || needsClickTarget(submitOnly, "browser_click_at", map[string]interface{}{"x": 1.0, "y": 2.0}) {
+		t.Error("click targets should only be inspected for form_submit or download with an element identifier")
+	}
+}
+
+func TestToolApproval(t *testing.T) {
+	logger.InitLogger(&logger.LoggerConfig{Level: "error"})
+	am := &AgentManager{sessions: map[string]*ChatSession{"s1": {ID: "s1"}}, approvals: newApprovalGate()}
+	if err := am.SetApprovalCategories("s1", []string{"teleport"}); err == nil {
+		t.Error("unknown categories should be rejected")
+	}
+	if err := am.SetApprovalCategories("s1", []string{models.ApprovalNavigation, models.ApprovalNavigation}); err != nil {
+		t.Fatal(err)
+	}
+	ctx := memory.WithConversationID(context.Background(), "s1")
+	events, unsubscribe := am.approvals.subscribe("s1")
+	defer unsubscribe()
+
+	navigate := func() chan error {
+		done := make(chan error, 1)
+		go func() {
+			done <- am.checkToolApproval(ctx, "browser_navigate", map[string]interface{}{"url": "https://example.com/a", "instructions": "open it"})
+		}()
+		return done
+	}
+
+	// 拒绝
+	done := navigate()
+	approval := <-events
+	if approval.Category != models.ApprovalNavigation || approval.Arguments["instructions"] != nil {
+		t.Errorf("unexpected approval request %+v", approval)
+	}
+	if pending, _ := am.ListPendingApprovals("s1"); len(pending) != 1 {
+		t.Errorf("expected 1 pending approval, got %v", pending)
+	}
+	if err := am.ResolveApproval("other", approval.ID, true); err == nil {
+		t.Error("approvals of another session should not be resolvable")
+	}
+	if err := am.ResolveApproval("s1", approval.ID, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err == nil || !strings.Contains(err.Error(), "denied") {
+		t.Errorf("expected a denial, got %v", err)
+	}
+
+	// 批准后记住域名
+	done = navigate()
+	approval = <-events
+	if err := am.ResolveApproval("s1", approval.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("expected approval, got %v", err)
+	}
+	if err := am.checkToolApproval(ctx, "browser_navigate", map[string]interface{}{"url": "https://example.com/b"}); err != nil {
+		t.Errorf("an approved domain should not need approval again, got %v", err)
+	}
+
+	// 超时视为拒绝
+	am.approvals.timeout = 10 * time.Millisecond
+	err := am.checkToolApproval(ctx, "browser_navigate", map[string]interface{}{"url": "https://new.example.org"})
+	if err == nil || !strings.Contains(err.Error(), "not approved") {
+		t.Errorf("expected a timeout, got %v", err)
+	}
+	if pending, _ := am.ListPendingApprovals("s1"); len(pending) != 0 {
+		t.Errorf("expired approvals should be removed, got %v", pending)
+	}
+}
@@ -25,13 +25,45 @@ func NewHandler(manager *AgentManager) *Handler {
 // CreateSession 创建新会话
 func (h *Handler) CreateSession(c *gin.Context) {
 	var req struct {
-		LLMConfigID string `json:"llm_config_id"` // LLM 配置 ID
+		LLMConfigID        string   `json:"llm_config_id"`       // LLM 配置 ID
+		ApprovalCategories []string `json:"approval_categories"` // 需要人工批准的工具调用类别
+		ReadOnly           bool     `json:"read_only"`           // 只读会话，只能使用打开和读取页面的工具
+		VerifyActions      bool     `json:"verify_actions"`      // 改变页面的工具调用后截图并校验操作结果
+		SystemPromptID     string   `json:"system_prompt_id"`    // 作为系统提示词的已保存提示词 ID
 	}
 
 	// 尝试读取请求体（可选）
 	c.ShouldBindJSON(&req)
 
 	session := h.manager.CreateSession(req.LLMConfigID)
+	if len(req.ApprovalCategories) > 0 {
+		if err := h.manager.SetApprovalCategories(session.ID, req.ApprovalCategories); err != nil {
+			h.manager.DeleteSession(session.ID)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidApprovalCategories", "detail": err.Error()})
+			return
+		}
+	}
+	if req.ReadOnly {
+		if err := h.manager.SetSessionReadOnly(session.ID); err != nil {
+			h.manager.DeleteSession(session.ID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error.createSessionFailed", "detail": err.Error()})
+			return
+		}
+	}
+	if req.SystemPromptID != "" {
+		if err := h.manager.SetSessionSystemPrompt(session.ID, req.SystemPromptID); err != nil {
+			h.manager.DeleteSession(session.ID)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.promptNotFound", "detail": err.Error()})
+			return
+		}
+	}
+	if req.VerifyActions {
+		if err := h.manager.SetVerifyActions(session.ID, true); err != nil {
+			h.manager.DeleteSession(session.ID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error.createSessionFailed", "detail": err.Error()})
+			return
+		}
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"session": session,
@@ -77,6 +109,128 @@ func (h *Handler) DeleteSession(c *gin.Context) {
 	})
 }
 
+// ExportSession 导出会话记录，format 为 json（默认）或 markdown
+func (h *Handler) ExportSession(c *gin.Context) {
+	transcript, err := h.manager.ExportSession(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=agent-session-%s.json", transcript.SessionID))
+		c.JSON(http.StatusOK, transcript)
+	case "markdown", "md":
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=agent-session-%s.md", transcript.SessionID))
+		c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(transcript.RenderMarkdown()))
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidTranscriptFormat"})
+	}
+}
+
+// ReplayTranscript 导入会话记录并按顺序重放其中的工具调用，返回与记录对比的结果
+func (h *Handler) ReplayTranscript(c *gin.Context) {
+	var transcript SessionTranscript
+	if err := c.ShouldBindJSON(&transcript); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidTranscript", "detail": err.Error()})
+		return
+	}
+	if transcript.Version > TranscriptVersion {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidTranscript", "detail": fmt.Sprintf("unsupported transcript version %d", transcript.Version)})
+		return
+	}
+
+	report := h.manager.ReplayTranscript(c.Request.Context(), &transcript, c.Query("stop_on_divergence") == "true")
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// SetApprovalSettings 设置会话中需要人工批准的工具调用类别
+func (h *Handler) SetApprovalSettings(c *gin.Context) {
+	var req struct {
+		Categories []string `json:"categories"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidApprovalCategories", "detail": err.Error()})
+		return
+	}
+
+	sessionID := c.Param("id")
+	if _, err := h.manager.GetSession(sessionID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.manager.SetApprovalCategories(sessionID, req.Categories); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidApprovalCategories", "detail": err.Error()})
+		return
+	}
+
+	session, _ := h.manager.GetSession(sessionID)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "agent.approvalSettingsSaved",
+		"session": session,
+	})
+}
+
+// SetVerificationSettings 开启或关闭会话的操作校验
+func (h *Handler) SetVerificationSettings(c *gin.Context) {
+	var req struct {
+		Enabled *bool `json:"enabled"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.Enabled == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+		return
+	}
+
+	sessionID := c.Param("id")
+	if err := h.manager.SetVerifyActions(sessionID, *req.Enabled); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, _ := h.manager.GetSession(sessionID)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "agent.verificationSettingsSaved",
+		"session": session,
+	})
+}
+
+// ListApprovals 列出会话中等待批准的工具调用
+func (h *Handler) ListApprovals(c *gin.Context) {
+	approvals, err := h.manager.ListPendingApprovals(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"approvals": approvals,
+		"count":     len(approvals),
+	})
+}
+
+// ResolveApproval 批准或拒绝挂起的工具调用
+func (h *Handler) ResolveApproval(c *gin.Context) {
+	var req struct {
+		Approved *bool `json:"approved" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams", "detail": err.Error()})
+		return
+	}
+
+	if err := h.manager.ResolveApproval(c.Param("id"), c.Param("approvalId"), *req.Approved); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.approvalNotFound", "detail": err.Error()})
+		return
+	}
+
+	message := "agent.toolCallDenied"
+	if *req.Approved {
+		message = "agent.toolCallApproved"
+	}
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
 // SendMessage 发送消息 (SSE 流式响应)
 func (h *Handler) SendMessage(c *gin.Context) {
 	sessionID := c.Param("id")
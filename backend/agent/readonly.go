@@ -0,0 +1,74 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/browserwing/browserwing/executor"
+)
+
+// checkReadOnly 只读会话中不可用的工具调用返回错误
+// 只读会话只能打开和读取页面（工具列表见 executor.CheckReadOnlyTool），
+// 脚本工具、外部 MCP 服务和文件、Git、Python 等预设工具都不可用
+func checkReadOnly(name string, args map[string]interface{}) error {
+	return executor.CheckReadOnlyTool(name, args)
+}
+
+// filterReadOnlyTools 只读会话的工具集
+func filterReadOnlyTools(tools []interfaces.Tool) []interfaces.Tool {
+	allowed := make([]interfaces.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if executor.IsReadOnlyTool(tool.Name()) {
+			allowed = append(allowed, tool)
+		}
+	}
+	return allowed
+}
+
+// sessionReadOnly 会话是否为只读会话
+func (am *AgentManager) sessionReadOnly(sessionID string) bool {
+	am.mu.RLock()
+	defer am.mu.RUnlock()
+	session, ok := am.sessions[sessionID]
+	return ok && session.ReadOnly
+}
+
+// SetSessionReadOnly 把会话设为只读，只能在发送第一条消息之前设置，设置后不能取消
+func (am *AgentManager) SetSessionReadOnly(sessionID string) error {
+	am.mu.Lock()
+	session, ok := am.sessions[sessionID]
+	if !ok {
+		am.mu.Unlock()
+		return fmt.Errorf("Session not found: %s", sessionID)
+	}
+	if session.ReadOnly {
+		am.mu.Unlock()
+		return nil
+	}
+	if len(session.Messages) > 0 || am.agents[sessionID] != nil {
+		am.mu.Unlock()
+		return fmt.Errorf("read-only mode can only be enabled before the first message")
+	}
+	session.ReadOnly = true
+	dbSession := session.dbModel()
+	am.mu.Unlock()
+
+	if am.db != nil {
+		if err := am.db.SaveAgentSession(dbSession); err != nil {
+			return fmt.Errorf("failed to save session: %w", err)
+		}
+	}
+	return nil
+}
+
+// authorizeToolCall 执行工具前的检查：只读会话只允许只读工具，需要批准的调用等待用户批准
+func (am *AgentManager) authorizeToolCall(ctx context.Context, name string, args map[string]interface{}) error {
+	if sessionID, ok := memory.GetConversationID(ctx); ok && am.sessionReadOnly(sessionID) {
+		if err := checkReadOnly(name, args); err != nil {
+			return err
+		}
+	}
+	return am.checkToolApproval(ctx, name, args)
+}
@@ -0,0 +1,75 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+)
+
+func TestReadOnlySession(t *testing.T) {
+	am := &AgentManager{
+		sessions:  map[string]*ChatSession{"s1": {ID: "s1"}, "s2": {ID: "s2", Messages: []ChatMessage{{Role: "user"}}}},
+		agents:    map[string]*AgentInstances{},
+		approvals: newApprovalGate(),
+	}
+	if err := am.SetSessionReadOnly("s2"); err == nil {
+		t.Error("read-only mode should not be enabled after the first message")
+	}
+	if err := am.SetSessionReadOnly("s1"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := memory.WithConversationID(context.Background(), "s1")
+	allowed := []struct {
+		name string
+		args map[string]interface{}
+	}{
+		{"browser_navigate", map[string]interface{}{"url": "https://example.com"}},
+		{"browser_snapshot", nil},
+		{"browser_extract", nil},
+		{"browser_take_screenshot", nil},
+		{"browser_tabs", map[string]interface{}{"action": "list"}},
+	}
+	for _, c := range allowed {
+		if err := am.authorizeToolCall(ctx, c.name, c.args); err != nil {
+			t.Errorf("%s should be allowed, got %v", c.name, err)
+		}
+	}
+	denied := []struct {
+		name string
+		args map[string]interface{}
+	}{
+		{"browser_click", map[string]interface{}{"identifier": "@e1"}},
+		{"browser_type", nil},
+		{"browser_evaluate", nil},
+		{"browser_fill_form", nil},
+		{"browser_tabs", map[string]interface{}{"action": "close"}},
+		{"browser_wait_for", map[string]interface{}{"predicate": "document.forms[0].submit() || true"}},
+		{"my_script_command", nil},
+	}
+	for _, c := range denied {
+		if err := am.authorizeToolCall(ctx, c.name, c.args); err == nil {
+			t.Errorf("%s %v should be denied in a read-only session", c.name, c.args)
+		}
+	}
+	// 其他会话不受影响
+	other := memory.WithConversationID(context.Background(), "s2")
+	if err := am.authorizeToolCall(other, "browser_click", nil); err != nil {
+		t.Errorf("normal sessions should not be restricted, got %v", err)
+	}
+
+	tools := filterReadOnlyTools([]interfaces.Tool{&echoTool{}, &toolNamed{name: "browser_snapshot"}, &toolNamed{name: "browser_click"}})
+	if len(tools) != 1 || tools[0].Name() != "browser_snapshot" {
+		t.Errorf("expected only browser_snapshot, got %d tools", len(tools))
+	}
+}
+
+// toolNamed 指定名字的测试工具
+type toolNamed struct {
+	echoTool
+	name string
+}
+
+func (t *toolNamed) Name() string { return t.name }
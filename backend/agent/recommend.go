@@ -0,0 +1,282 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+)
+
+const (
+	// maxScriptRecommendations 每个任务最多推荐的脚本数
+	maxScriptRecommendations = 3
+	// minScriptSimilarity 推荐脚本所需的最低余弦相似度
+	minScriptSimilarity = 0.45
+	// embeddingTimeout 单次嵌入请求的超时时间
+	embeddingTimeout = 30 * time.Second
+)
+
+// ScriptRecommendation 与 Agent 任务相似的脚本工具
+type ScriptRecommendation struct {
+	Tool     string  `json:"tool"` // 脚本的 MCP 命令名称，即 Agent 调用的工具名
+	ScriptID string  `json:"script_id"`
+	Name     string  `json:"name"`
+	URL      string  `json:"url,omitempty"`
+	Score    float64 `json:"score"`
+}
+
+// scriptEmbedder 通过 OpenAI 兼容的 /embeddings 接口计算文本向量
+type scriptEmbedder struct {
+	key     string // 配置 ID 和模型，嵌入模型变化时缓存的脚本向量失效
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+// createScriptEmbedder 创建路由策略中脚本推荐的嵌入模型
+func (am *AgentManager) createScriptEmbedder(policy *models.LLMRoutingPolicy) (*scriptEmbedder, error) {
+	cfg, err := am.db.GetLLMConfig(policy.EmbeddingConfigID)
+	if err != nil {
+		return nil, fmt.Errorf("LLM config for script recommendation not found: %s", policy.EmbeddingConfigID)
+	}
+	if !cfg.IsActive {
+		return nil, fmt.Errorf("LLM config for script recommendation is not active: %s", policy.EmbeddingConfigID)
+	}
+	provider := strings.ToLower(cfg.Provider)
+	baseURL := getProviderBaseURL(provider, cfg.BaseURL)
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+	apiKey := cfg.APIKey
+	if provider == "ollama" && apiKey == "" {
+		apiKey = "ollama"
+	}
+	return &scriptEmbedder{
+		key:     cfg.ID + "/" + policy.EmbeddingModel,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   policy.EmbeddingModel,
+		client:  &http.Client{Timeout: embeddingTimeout},
+	}, nil
+}
+
+// embed 批量计算文本向量，结果与输入顺序一致
+func (e *scriptEmbedder) embed(ctx context.Context, texts []string) ([][]float64, error) {
+	body, err := json.Marshal(map[string]interface{}{"model": e.model, "input": texts})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+"/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+e.apiKey)
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("embedding request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedding response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("embedding request failed with status %d: %s", resp.StatusCode, truncateText(string(data), 200))
+	}
+
+	var parsed struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float64 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("invalid embedding response: %w", err)
+	}
+	vectors := make([][]float64, len(texts))
+	for _, d := range parsed.Data {
+		if d.Index >= 0 && d.Index < len(vectors) {
+			vectors[d.Index] = d.Embedding
+		}
+	}
+	for i, v := range vectors {
+		if len(v) == 0 {
+			return nil, fmt.Errorf("embedding response is missing input %d", i)
+		}
+	}
+	return vectors, nil
+}
+
+// scriptIndex 缓存脚本的向量，脚本的名称、描述或 URL 变化时重新计算
+type scriptIndex struct {
+	mu      sync.Mutex
+	key     string
+	entries map[string]scriptVector // 脚本 ID -> 向量
+}
+
+type scriptVector struct {
+	text   string
+	vector []float64
+}
+
+// vectors 返回脚本的向量，只为新增或变化的脚本请求嵌入接口
+func (idx *scriptIndex) vectors(ctx context.Context, embedder *scriptEmbedder, scripts []*models.Script) (map[string][]float64, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if idx.key != embedder.key || idx.entries == nil {
+		idx.key = embedder.key
+		idx.entries = make(map[string]scriptVector)
+	}
+
+	var missing []*models.Script
+	var texts []string
+	current := make(map[string]bool, len(scripts))
+	for _, script := range scripts {
+		current[script.ID] = true
+		text := scriptEmbeddingText(script)
+		if entry, ok := idx.entries[script.ID]; !ok || entry.text != text {
+			missing = append(missing, script)
+			texts = append(texts, text)
+		}
+	}
+	if len(missing) > 0 {
+		vectors, err := embedder.embed(ctx, texts)
+		if err != nil {
+			return nil, err
+		}
+		for i, script := range missing {
+			idx.entries[script.ID] = scriptVector{text: texts[i], vector: vectors[i]}
+		}
+	}
+
+	result := make(map[string][]float64, len(scripts))
+	for id, entry := range idx.entries {
+		if !current[id] {
+			delete(idx.entries, id)
+			continue
+		}
+		result[id] = entry.vector
+	}
+	return result, nil
+}
+
+// scriptEmbeddingText 用于计算脚本向量的文本：名称、描述和起始 URL
+func scriptEmbeddingText(script *models.Script) string {
+	parts := []string{script.Name}
+	for _, s := range []string{script.Description, script.MCPCommandDescription, script.URL} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// recommendScripts 找出与任务相似、且会话可以作为工具调用的脚本，按相似度从高到低排列。
+// 未配置嵌入模型、只读会话或计算失败时返回 nil
+func (am *AgentManager) recommendScripts(ctx context.Context, sessionID, task string) []ScriptRecommendation {
+	am.routing.mu.RLock()
+	embedder := am.routing.embedder
+	am.routing.mu.RUnlock()
+	if embedder == nil || am.sessionReadOnly(sessionID) {
+		return nil
+	}
+
+	scripts, err := am.db.ListScripts()
+	if err != nil {
+		logger.Warn(ctx, "[ScriptRecommend] Failed to list scripts: %v", err)
+		return nil
+	}
+	// 只推荐已注册为 Agent 工具的脚本，被禁用的脚本工具不会注册
+	var tools []*models.Script
+	for _, script := range scripts {
+		if !script.IsMCPCommand || script.MCPCommandName == "" {
+			continue
+		}
+		if _, ok := am.toolReg.Get(script.MCPCommandName); ok {
+			tools = append(tools, script)
+		}
+	}
+	if len(tools) == 0 {
+		return nil
+	}
+
+	vectors, err := am.scriptIndex.vectors(ctx, embedder, tools)
+	if err != nil {
+		logger.Warn(ctx, "[ScriptRecommend] Failed to embed scripts: %v", err)
+		return nil
+	}
+	taskVectors, err := embedder.embed(ctx, []string{task})
+	if err != nil {
+		logger.Warn(ctx, "[ScriptRecommend] Failed to embed task: %v", err)
+		return nil
+	}
+
+	var recommendations []ScriptRecommendation
+	for _, script := range tools {
+		score := cosineSimilarity(taskVectors[0], vectors[script.ID])
+		if score < minScriptSimilarity {
+			continue
+		}
+		recommendations = append(recommendations, ScriptRecommendation{
+			Tool:     script.MCPCommandName,
+			ScriptID: script.ID,
+			Name:     script.Name,
+			URL:      script.URL,
+			Score:    score,
+		})
+	}
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return recommendations[i].Score > recommendations[j].Score
+	})
+	if len(recommendations) > maxScriptRecommendations {
+		recommendations = recommendations[:maxScriptRecommendations]
+	}
+	return recommendations
+}
+
+// withScriptRecommendations 在发给 Agent 的任务后附上推荐的脚本工具，让 Agent 优先调用确定性的脚本而不是逐步操作页面
+func withScriptRecommendations(task string, recommendations []ScriptRecommendation) string {
+	if len(recommendations) == 0 {
+		return task
+	}
+	var b strings.Builder
+	b.WriteString(task)
+	b.WriteString("\n\n[Saved scripts that match this task. If one of them fits, call its tool instead of browsing step by step, and browse manually only for what the script does not cover.]\n")
+	for _, r := range recommendations {
+		fmt.Fprintf(&b, "- %s: %s", r.Tool, r.Name)
+		if r.URL != "" {
+			fmt.Fprintf(&b, " (%s)", r.URL)
+		}
+		fmt.Fprintf(&b, ", similarity %.2f\n", r.Score)
+	}
+	return b.String()
+}
+
+func cosineSimilarity(a, b []float64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
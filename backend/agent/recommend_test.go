@@ -0,0 +1,120 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/tools"
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/storage"
+)
+
+// embeddingServer 按关键词生成向量的测试嵌入接口
+func embeddingServer(t *testing.T, inputs *int) *httptest.Server {
+	vocab := []string{"invoice", "weather", "login"}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input []string `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || r.URL.Path != "/embeddings" {
+			t.Errorf("unexpected embedding request %s: %v", r.URL.Path, err)
+		}
+		*inputs += len(req.Input)
+		data := []map[string]interface{}{}
+		for i, text := range req.Input {
+			vector := make([]float64, len(vocab)+1)
+			vector[len(vocab)] = 0.1
+			for j, word := range vocab {
+				if strings.Contains(strings.ToLower(text), word) {
+					vector[j] = 1
+				}
+			}
+			data = append(data, map[string]interface{}{"index": i, "embedding": vector})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+}
+
+func TestRecommendScripts(t *testing.T) {
+	logger.InitLogger(&logger.LoggerConfig{Level: "error"})
+	inputs := 0
+	server := embeddingServer(t, &inputs)
+	defer server.Close()
+
+	db, err := storage.NewBoltDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	for _, script := range []*models.Script{
+		{ID: "1", Name: "Download invoice", URL: "https://billing.example.com", IsMCPCommand: true, MCPCommandName: "download_invoice"},
+		{ID: "2", Name: "Check weather", URL: "https://weather.example.com", IsMCPCommand: true, MCPCommandName: "check_weather"},
+		{ID: "3", Name: "Invoice draft", URL: "https://billing.example.com/draft"},
+	} {
+		if err := db.SaveScript(script); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.SaveLLMConfig(&models.LLMConfigModel{ID: "emb", Name: "emb", Provider: "openai", APIKey: "k", Model: "gpt-4o-mini", BaseURL: server.URL, IsActive: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveLLMRoutingPolicy(&models.LLMRoutingPolicy{EmbeddingConfigID: "emb", EmbeddingModel: "text-embedding-3-small"}); err != nil {
+		t.Fatal(err)
+	}
+
+	am := &AgentManager{
+		db:        db,
+		ctx:       context.Background(),
+		sessions:  map[string]*ChatSession{"s1": {ID: "s1"}, "ro": {ID: "ro", ReadOnly: true}},
+		agents:    map[string]*AgentInstances{},
+		toolReg:   tools.NewRegistry(),
+		approvals: newApprovalGate(),
+	}
+	for _, name := range []string{"download_invoice", "check_weather"} {
+		am.toolReg.Register(&MCPTool{name: name})
+	}
+	ctx := context.Background()
+
+	// 未配置嵌入模型时不推荐
+	if got := am.recommendScripts(ctx, "s1", "get my latest invoice"); got != nil {
+		t.Fatalf("expected no recommendations before the policy is loaded, got %v", got)
+	}
+	if err := am.ReloadLLMRouting(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := am.recommendScripts(ctx, "s1", "Get my latest invoice")
+	if len(got) != 1 || got[0].Tool != "download_invoice" || got[0].ScriptID != "1" {
+		t.Fatalf("expected the invoice script tool, got %+v", got)
+	}
+	if inputs != 3 {
+		t.Errorf("expected 2 scripts and 1 task to be embedded, got %d inputs", inputs)
+	}
+
+	// 脚本向量被缓存，只重新计算变化的脚本
+	am.recommendScripts(ctx, "s1", "what is the weather")
+	if inputs != 4 {
+		t.Errorf("script vectors should be cached, got %d inputs", inputs)
+	}
+	if err := db.SaveScript(&models.Script{ID: "2", Name: "Check weather and login", IsMCPCommand: true, MCPCommandName: "check_weather"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := am.recommendScripts(ctx, "s1", "login"); len(got) != 1 || got[0].Tool != "check_weather" || inputs != 6 {
+		t.Errorf("expected the changed script to be embedded again, got %+v after %d inputs", got, inputs)
+	}
+
+	if got := am.recommendScripts(ctx, "ro", "Get my latest invoice"); got != nil {
+		t.Errorf("read-only sessions cannot call scripts, got %v", got)
+	}
+
+	task := withScriptRecommendations("Get my latest invoice", []ScriptRecommendation{{Tool: "download_invoice", Name: "Download invoice", URL: "https://billing.example.com", Score: 0.9}})
+	if !strings.HasPrefix(task, "Get my latest invoice\n\n") || !strings.Contains(task, "- download_invoice: Download invoice (https://billing.example.com), similarity 0.90") {
+		t.Errorf("unexpected task with recommendations:\n%s", task)
+	}
+}
@@ -0,0 +1,103 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+)
+
+// llmRouter Agent 的 LLM 路由：路由策略、小模型的 client、使用小模型的任务评估 Agent 和脚本推荐的嵌入模型
+type llmRouter struct {
+	mu        sync.RWMutex
+	policy    *models.LLMRoutingPolicy
+	client    interfaces.LLM
+	evalAgent *agent.Agent
+	embedder  *scriptEmbedder
+}
+
+// ReloadLLMRouting 从数据库重新加载 LLM 路由策略，立即对所有会话生效
+func (am *AgentManager) ReloadLLMRouting() error {
+	policy, err := am.db.GetLLMRoutingPolicy()
+	if err != nil {
+		return fmt.Errorf("failed to load LLM routing policy: %w", err)
+	}
+
+	// 小模型或嵌入模型不可用时，对应的步骤回退到会话的模型或不推荐脚本
+	var errs []error
+	var client interfaces.LLM
+	var evalAgent *agent.Agent
+	if policy.CheapConfigID != "" {
+		if client, evalAgent, err = am.createCheapLLM(policy); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	var embedder *scriptEmbedder
+	if policy.EmbeddingConfigID != "" {
+		if embedder, err = am.createScriptEmbedder(policy); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	am.routing.mu.Lock()
+	am.routing.policy = policy
+	am.routing.client = client
+	am.routing.evalAgent = evalAgent
+	am.routing.embedder = embedder
+	am.routing.mu.Unlock()
+
+	if client != nil {
+		logger.Info(am.ctx, "✓ LLM routing loaded: %v use %s", policy.CheapSteps, policy.CheapConfigID)
+	}
+	if embedder != nil {
+		logger.Info(am.ctx, "✓ Script recommendation enabled: %s (%s)", policy.EmbeddingModel, policy.EmbeddingConfigID)
+	}
+	return errors.Join(errs...)
+}
+
+// createCheapLLM 创建路由策略中小模型的 client，路由任务评估时同时创建评估 Agent
+func (am *AgentManager) createCheapLLM(policy *models.LLMRoutingPolicy) (interfaces.LLM, *agent.Agent, error) {
+	cfg, err := am.db.GetLLMConfig(policy.CheapConfigID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("LLM config of the routing policy not found: %s", policy.CheapConfigID)
+	}
+	if !cfg.IsActive {
+		return nil, nil, fmt.Errorf("LLM config of the routing policy is not active: %s", policy.CheapConfigID)
+	}
+	client, err := CreateLLMClient(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create LLM client for routing: %w", err)
+	}
+	if !policy.Routes(models.LLMStepTaskEvaluation) {
+		return client, nil, nil
+	}
+	evalAgent, err := am.createEvalAgent(client)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create eval agent for routing: %w", err)
+	}
+	return client, evalAgent, nil
+}
+
+// cheapLLM 路由策略把步骤交给小模型时返回小模型，否则返回 nil
+func (am *AgentManager) cheapLLM(step string) interfaces.LLM {
+	am.routing.mu.RLock()
+	defer am.routing.mu.RUnlock()
+	if am.routing.client == nil || !am.routing.policy.Routes(step) {
+		return nil
+	}
+	return am.routing.client
+}
+
+// evalAgentFor 会话的任务评估 Agent：路由任务评估时使用小模型
+func (am *AgentManager) evalAgentFor(instances *AgentInstances) *agent.Agent {
+	am.routing.mu.RLock()
+	defer am.routing.mu.RUnlock()
+	if am.routing.evalAgent != nil {
+		return am.routing.evalAgent
+	}
+	return instances.EvalAgent
+}
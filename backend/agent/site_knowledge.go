@@ -0,0 +1,185 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+)
+
+// pageURLTools 可能改变当前页面 URL 的工具，执行后读取页面 URL 匹配站点知识包
+var pageURLTools = map[string]bool{
+	"browser_navigate": true,
+	"browser_tabs":     true,
+}
+
+// reloginCooldown 同一会话再次执行同一个登录脚本的最短间隔，期间仍要求登录时交给用户处理
+const reloginCooldown = 5 * time.Minute
+
+// siteNotesLog 记录会话已注入的站点知识包，每个知识包在会话中只注入一次，更新后重新注入
+type siteNotesLog struct {
+	mu       sync.Mutex
+	injected map[string]map[string]time.Time // sessionID -> 知识包 ID -> 注入时知识包的更新时间
+	loggedIn map[string]map[string]time.Time // sessionID -> 登录脚本 ID -> 最近执行时间
+}
+
+// markNew 知识包尚未注入会话（或注入后被更新）时记录并返回 true
+func (l *siteNotesLog) markNew(sessionID string, pack *models.SiteKnowledge) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.injected == nil {
+		l.injected = make(map[string]map[string]time.Time)
+	}
+	if l.injected[sessionID] == nil {
+		l.injected[sessionID] = make(map[string]time.Time)
+	}
+	if updatedAt, ok := l.injected[sessionID][pack.ID]; ok && updatedAt.Equal(pack.UpdatedAt) {
+		return false
+	}
+	l.injected[sessionID][pack.ID] = pack.UpdatedAt
+	return true
+}
+
+// markLogin 会话在冷却时间内没有执行过该登录脚本时记录并返回 true
+func (l *siteNotesLog) markLogin(sessionID, scriptID string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.loggedIn == nil {
+		l.loggedIn = make(map[string]map[string]time.Time)
+	}
+	if l.loggedIn[sessionID] == nil {
+		l.loggedIn[sessionID] = make(map[string]time.Time)
+	}
+	if last, ok := l.loggedIn[sessionID][scriptID]; ok && now.Sub(last) < reloginCooldown {
+		return false
+	}
+	l.loggedIn[sessionID][scriptID] = now
+	return true
+}
+
+func (l *siteNotesLog) forget(sessionID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.injected, sessionID)
+	delete(l.loggedIn, sessionID)
+}
+
+// siteKnowledgeNotes 打开或切换页面后，返回当前 URL 匹配、且会话中尚未注入的站点知识包，
+// 让 Agent 在操作复杂站点前了解登录选择器、已知问题和访问频率限制
+// 页面要求登录且知识包指定了登录脚本时，先执行登录脚本
+func (am *AgentManager) siteKnowledgeNotes(ctx context.Context, name string, args map[string]interface{}, result interface{}) string {
+	if !pageURLTools[name] && !mutatingTools[name] {
+		return ""
+	}
+	sessionID, ok := memory.GetConversationID(ctx)
+	if !ok || am.db == nil {
+		return ""
+	}
+	packs, err := am.db.ListSiteKnowledge()
+	if err != nil {
+		logger.Warn(ctx, "Failed to list site knowledge: %v", err)
+		return ""
+	}
+	enabled := packs[:0]
+	for _, pack := range packs {
+		if pack.Enabled {
+			enabled = append(enabled, pack)
+		}
+	}
+	if len(enabled) == 0 {
+		return ""
+	}
+
+	// 点击等操作可能跳转到其他站点（如登录页），以页面实际的 URL 为准
+	pageURL := resultDataString(result, "url")
+	info, err := am.mcpServer.CallTool(ctx, "browser_get_page_info", map[string]interface{}{})
+	if err == nil {
+		if u := resultDataString(info, "url"); u != "" {
+			pageURL = u
+		}
+	}
+	if pageURL == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(am.reloginNotes(ctx, sessionID, name, args, info, pageURL, enabled))
+	for _, pack := range enabled {
+		if pack.Content == "" || !pack.MatchesURL(pageURL) || !am.siteNotes.markNew(sessionID, pack) {
+			continue
+		}
+		logger.Info(ctx, "Injecting site knowledge %q for %s into session %s", pack.Name, pageURL, sessionID)
+		fmt.Fprintf(&b, "\n\nSITE NOTES (%s, for %s): follow these notes when working on this site.\n%s", pack.Name, strings.Join(pack.Domains, ", "), pack.Content)
+	}
+	return b.String()
+}
+
+// reloginNotes 页面要求登录（page info 中有 logged_out）且匹配的知识包指定了登录脚本时，执行登录脚本，
+// 再重新打开要导航到的页面；同一登录脚本冷却时间内再次需要登录或脚本失败时，提示 Agent 让用户登录
+func (am *AgentManager) reloginNotes(ctx context.Context, sessionID, name string, args map[string]interface{}, info interface{}, pageURL string, packs []*models.SiteKnowledge) string {
+	resultMap, _ := info.(map[string]interface{})
+	data, _ := resultMap["data"].(map[string]interface{})
+	if data["logged_out"] == nil {
+		return ""
+	}
+	var loggedOut struct {
+		Status int    `json:"status"`
+		Reason string `json:"reason"`
+	}
+	if raw, err := json.Marshal(data["logged_out"]); err == nil {
+		json.Unmarshal(raw, &loggedOut)
+	}
+	target, _ := args["url"].(string)
+	if name != "browser_navigate" {
+		target = ""
+	}
+	// Agent 有意打开登录页时不算退出登录
+	if target != "" && loggedOut.Status != 401 && samePageURL(target, pageURL) {
+		return ""
+	}
+
+	scriptID := models.LoginScriptFor(packs, pageURL)
+	if scriptID == "" {
+		return ""
+	}
+	runner, ok := am.mcpServer.(interface {
+		RunScript(ctx context.Context, scriptID string) error
+	})
+	if !ok {
+		return ""
+	}
+	if !am.siteNotes.markLogin(sessionID, scriptID, time.Now()) {
+		return fmt.Sprintf("\n\nLOGIN_REQUIRED: %s still asks to log in after the site's login script ran. Ask the user to log in in the browser window, then continue. Do not guess credentials.", pageURL)
+	}
+
+	logger.Info(ctx, "Session %s was logged out at %s (%s), running login script %s", sessionID, pageURL, loggedOut.Reason, scriptID)
+	if err := runner.RunScript(ctx, scriptID); err != nil {
+		logger.Warn(ctx, "Login script %s failed: %v", scriptID, err)
+		return fmt.Sprintf("\n\nLOGIN_REQUIRED: %s asks to log in and the site's login script failed: %v. Ask the user to log in in the browser window, then continue. Do not guess credentials.", pageURL, err)
+	}
+	if target == "" {
+		return "\n\nLOGGED IN AGAIN: the page asked to log in, so the site's login script ran. Navigate back to the page you were working on and continue."
+	}
+	if _, err := am.mcpServer.CallTool(ctx, "browser_navigate", map[string]interface{}{"url": target}); err != nil {
+		logger.Warn(ctx, "Failed to reopen %s after logging in: %v", target, err)
+		return fmt.Sprintf("\n\nLOGGED IN AGAIN: the page asked to log in, so the site's login script ran. Navigate to %s again and continue.", target)
+	}
+	return fmt.Sprintf("\n\nLOGGED IN AGAIN: the page asked to log in, so the site's login script ran and %s was opened again. Take a new snapshot before continuing.", target)
+}
+
+// samePageURL 两个 URL 的域名和路径是否相同
+func samePageURL(a, b string) bool {
+	ua, errA := url.Parse(a)
+	ub, errB := url.Parse(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return strings.EqualFold(ua.Host, ub.Host) && strings.TrimSuffix(ua.Path, "/") == strings.TrimSuffix(ub.Path, "/")
+}
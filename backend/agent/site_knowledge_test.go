@@ -0,0 +1,152 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	browsermcp "github.com/browserwing/browserwing/mcp"
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/storage"
+)
+
+// urlServer 返回固定页面 URL 的测试 MCP 服务
+type urlServer struct {
+	browsermcp.IMCPServer
+	url string
+}
+
+func (s *urlServer) CallTool(ctx context.Context, name string, args map[string]interface{}) (interface{}, error) {
+	return map[string]interface{}{"data": map[string]interface{}{"url": s.url}}, nil
+}
+
+func TestSiteKnowledgeNotes(t *testing.T) {
+	logger.InitLogger(&logger.LoggerConfig{Level: "error"})
+	db, err := storage.NewBoltDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	pack := &models.SiteKnowledge{ID: "shop", Name: "Shop", Domains: []string{"shop.example.com"}, Content: "Log in with #email, not the SSO button.", Enabled: true, UpdatedAt: time.Now()}
+	for _, k := range []*models.SiteKnowledge{
+		pack,
+		{ID: "off", Name: "Disabled", Domains: []string{"example.com"}, Content: "Disabled notes.", UpdatedAt: time.Now()},
+	} {
+		if err := db.SaveSiteKnowledge(k); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	server := &urlServer{url: "https://blog.example.com/"}
+	am := &AgentManager{db: db, mcpServer: server}
+	ctx := memory.WithConversationID(context.Background(), "s1")
+
+	if got := am.siteKnowledgeNotes(ctx, "browser_navigate", nil, nil); got != "" {
+		t.Errorf("pages of other sites should get no notes, got %q", got)
+	}
+
+	// 点击跳转后以页面实际的 URL 为准
+	server.url = "https://shop.example.com/login"
+	got := am.siteKnowledgeNotes(ctx, "browser_click", nil, nil)
+	if !strings.Contains(got, "SITE NOTES (Shop, for shop.example.com)") || !strings.Contains(got, "#email") || strings.Contains(got, "Disabled notes") {
+		t.Errorf("unexpected notes %q", got)
+	}
+	if got := am.siteKnowledgeNotes(ctx, "browser_navigate", nil, nil); got != "" {
+		t.Errorf("notes should only be injected once per session, got %q", got)
+	}
+	if got := am.siteKnowledgeNotes(ctx, "browser_snapshot", nil, nil); got != "" {
+		t.Errorf("read tools should not inject notes, got %q", got)
+	}
+
+	// 知识包更新后重新注入
+	pack.Content = "Use the new login form."
+	pack.UpdatedAt = pack.UpdatedAt.Add(time.Second)
+	if err := db.SaveSiteKnowledge(pack); err != nil {
+		t.Fatal(err)
+	}
+	if got := am.siteKnowledgeNotes(ctx, "browser_navigate", nil, nil); !strings.Contains(got, "Use the new login form.") {
+		t.Errorf("updated notes should be injected again, got %q", got)
+	}
+	if got := am.siteKnowledgeNotes(memory.WithConversationID(context.Background(), "s2"), "browser_navigate", nil, nil); got == "" {
+		t.Error("other sessions should get the notes too")
+	}
+}
+
+// loginServer 页面要求登录的测试 MCP 服务，执行登录脚本后不再要求登录
+type loginServer struct {
+	browsermcp.IMCPServer
+	url       string
+	loggedOut bool
+	runErr    error
+	runs      []string
+	navigated []string
+}
+
+func (s *loginServer) CallTool(ctx context.Context, name string, args map[string]interface{}) (interface{}, error) {
+	if name == "browser_navigate" {
+		s.navigated = append(s.navigated, args["url"].(string))
+		return map[string]interface{}{}, nil
+	}
+	data := map[string]interface{}{"url": s.url}
+	if s.loggedOut {
+		data["logged_out"] = map[string]interface{}{"reason": "login page: /login"}
+	}
+	return map[string]interface{}{"data": data}, nil
+}
+
+func (s *loginServer) RunScript(ctx context.Context, scriptID string) error {
+	s.runs = append(s.runs, scriptID)
+	if s.runErr == nil {
+		s.loggedOut = false
+	}
+	return s.runErr
+}
+
+func TestReloginNotes(t *testing.T) {
+	logger.InitLogger(&logger.LoggerConfig{Level: "error"})
+	db, err := storage.NewBoltDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := db.SaveSiteKnowledge(&models.SiteKnowledge{ID: "shop", Name: "Shop", Domains: []string{"shop.example.com"}, Enabled: true, LoginScriptID: "login", UpdatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	server := &loginServer{url: "https://shop.example.com/login?next=/orders", loggedOut: true}
+	am := &AgentManager{db: db, mcpServer: server}
+	ctx := memory.WithConversationID(context.Background(), "s1")
+
+	// 打开的页面被重定向到登录页：执行登录脚本后重新打开
+	got := am.siteKnowledgeNotes(ctx, "browser_navigate", map[string]interface{}{"url": "https://shop.example.com/orders"}, nil)
+	if !strings.Contains(got, "LOGGED IN AGAIN") || len(server.runs) != 1 || server.runs[0] != "login" {
+		t.Fatalf("expected the login script to run, got %q (runs %v)", got, server.runs)
+	}
+	if len(server.navigated) != 1 || server.navigated[0] != "https://shop.example.com/orders" {
+		t.Errorf("expected the requested page to be opened again, got %v", server.navigated)
+	}
+
+	// 有意打开登录页时不执行
+	server.loggedOut = true
+	if got := am.siteKnowledgeNotes(ctx, "browser_navigate", map[string]interface{}{"url": "https://shop.example.com/login"}, nil); strings.Contains(got, "LOG") {
+		t.Errorf("opening the login page on purpose should not log in, got %q", got)
+	}
+
+	// 冷却时间内再次要求登录时交给用户
+	got = am.siteKnowledgeNotes(ctx, "browser_click", nil, nil)
+	if !strings.Contains(got, "LOGIN_REQUIRED") || len(server.runs) != 1 {
+		t.Errorf("expected LOGIN_REQUIRED without running the script again, got %q (runs %v)", got, server.runs)
+	}
+
+	// 登录脚本失败
+	server.runErr = errors.New("element not found: #email")
+	got = am.siteKnowledgeNotes(memory.WithConversationID(context.Background(), "s2"), "browser_click", nil, nil)
+	if !strings.Contains(got, "LOGIN_REQUIRED") || !strings.Contains(got, "#email") || len(server.navigated) != 1 {
+		t.Errorf("expected LOGIN_REQUIRED with the script error, got %q", got)
+	}
+}
@@ -0,0 +1,56 @@
+package agent
+
+import (
+	"fmt"
+
+	"github.com/browserwing/browserwing/pkg/logger"
+)
+
+// sessionSystemPrompt 会话的系统提示词：会话选择的提示词不存在时回退到默认的 Agent 系统提示词
+func (am *AgentManager) sessionSystemPrompt(sessionID string) string {
+	am.mu.RLock()
+	var promptID string
+	if session, ok := am.sessions[sessionID]; ok {
+		promptID = session.SystemPromptID
+	}
+	am.mu.RUnlock()
+
+	if promptID != "" {
+		prompt, err := am.db.GetPrompt(promptID)
+		if err == nil && prompt.Content != "" {
+			return prompt.Content
+		}
+		logger.Warn(am.ctx, "System prompt %s of session %s is not available (%v), using the default", promptID, sessionID, err)
+	}
+	return am.GetSystemPrompt()
+}
+
+// SetSessionSystemPrompt 选择已保存的提示词作为会话的系统提示词，只能在发送第一条消息之前设置
+func (am *AgentManager) SetSessionSystemPrompt(sessionID, promptID string) error {
+	if _, err := am.db.GetPrompt(promptID); err != nil {
+		return fmt.Errorf("prompt not found: %s", promptID)
+	}
+
+	am.mu.Lock()
+	session, ok := am.sessions[sessionID]
+	if !ok {
+		am.mu.Unlock()
+		return fmt.Errorf("Session not found: %s", sessionID)
+	}
+	if session.SystemPromptID == promptID {
+		am.mu.Unlock()
+		return nil
+	}
+	if len(session.Messages) > 0 || am.agents[sessionID] != nil {
+		am.mu.Unlock()
+		return fmt.Errorf("the system prompt can only be chosen before the first message")
+	}
+	session.SystemPromptID = promptID
+	dbSession := session.dbModel()
+	am.mu.Unlock()
+
+	if err := am.db.SaveAgentSession(dbSession); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package agent
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/storage"
+)
+
+func TestSessionSystemPrompt(t *testing.T) {
+	logger.InitLogger(&logger.LoggerConfig{Level: "error"})
+	db, err := storage.NewBoltDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := db.SavePrompt(&models.Prompt{ID: "shopping", Name: "Shopping", Content: "You are a careful shopping assistant.", Type: models.PromptTypeCustom}); err != nil {
+		t.Fatal(err)
+	}
+
+	am := &AgentManager{
+		db:        db,
+		ctx:       context.Background(),
+		sessions:  map[string]*ChatSession{"s1": {ID: "s1"}, "s2": {ID: "s2", Messages: []ChatMessage{{Role: "user"}}}},
+		agents:    map[string]*AgentInstances{},
+		approvals: newApprovalGate(),
+	}
+	if err := am.SetSessionSystemPrompt("s1", "missing"); err == nil {
+		t.Error("unknown prompts should be rejected")
+	}
+	if err := am.SetSessionSystemPrompt("s2", "shopping"); err == nil {
+		t.Error("the system prompt should not change after the first message")
+	}
+	if err := am.SetSessionSystemPrompt("s1", "shopping"); err != nil {
+		t.Fatal(err)
+	}
+	if got := am.sessionSystemPrompt("s1"); got != "You are a careful shopping assistant." {
+		t.Errorf("expected the stored prompt, got %q", got)
+	}
+	if saved, err := db.GetAgentSession("s1"); err != nil || saved.SystemPromptID != "shopping" {
+		t.Errorf("expected the prompt to be saved with the session, got %+v, %v", saved, err)
+	}
+
+	// 提示词被删除后回退到默认提示词
+	if err := db.DeletePrompt("shopping"); err != nil {
+		t.Fatal(err)
+	}
+	if got := am.sessionSystemPrompt("s1"); got != defSystemPrompt {
+		t.Errorf("expected the default prompt, got %q", got)
+	}
+}
@@ -0,0 +1,301 @@
+package agent
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/browserwing/browserwing/executor"
+	"github.com/google/uuid"
+)
+
+const (
+	// TranscriptVersion 会话记录的格式版本
+	TranscriptVersion = 1
+	// maxTranscriptScreenshotSize 嵌入会话记录的单张截图上限，超出时只保留路径
+	maxTranscriptScreenshotSize = 10 << 20
+	// screenshotToolName 截图工具，只嵌入它保存的截图
+	screenshotToolName = "browser_take_screenshot"
+)
+
+// 重放结果
+const (
+	ReplayMatched  = "matched"  // 结果与记录相同
+	ReplayChanged  = "changed"  // 调用成功与否与记录相同，结果不同
+	ReplayDiverged = "diverged" // 记录成功而重放失败，或相反
+	ReplayMissing  = "missing"  // 工具已不存在
+	ReplaySkipped  = "skipped"  // 之前的调用失败后停止重放
+)
+
+// screenshotPathPattern 截图工具结果中保存的文件路径
+var screenshotPathPattern = regexp.MustCompile(`saved to: (\S+)`)
+
+// SessionTranscript 导出的会话记录：消息、工具调用（参数和结果）及嵌入的截图，不依赖服务器上的其他数据
+type SessionTranscript struct {
+	Version     int                    `json:"version"`
+	ExportedAt  time.Time              `json:"exported_at"`
+	SessionID   string                 `json:"session_id"`
+	LLMConfigID string                 `json:"llm_config_id,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+	Messages    []ChatMessage          `json:"messages"`
+	Screenshots []TranscriptScreenshot `json:"screenshots,omitempty"`
+}
+
+// TranscriptScreenshot 嵌入的截图，Message 和 ToolCall 为产生它的工具调用的下标
+type TranscriptScreenshot struct {
+	Message  int    `json:"message"`
+	ToolCall int    `json:"tool_call"`
+	Path     string `json:"path"`
+	MimeType string `json:"mime_type,omitempty"`
+	Data     string `json:"data,omitempty"` // base64，文件已不存在或过大时为空
+	Error    string `json:"error,omitempty"`
+}
+
+// ReplayStep 一次工具调用的重放结果
+type ReplayStep struct {
+	Message        int                    `json:"message"`
+	ToolCall       int                    `json:"tool_call"`
+	ToolName       string                 `json:"tool_name"`
+	Arguments      map[string]interface{} `json:"arguments,omitempty"`
+	Outcome        string                 `json:"outcome"`
+	RecordedStatus string                 `json:"recorded_status"`
+	RecordedResult string                 `json:"recorded_result,omitempty"`
+	Result         string                 `json:"result,omitempty"`
+	Error          string                 `json:"error,omitempty"`
+	Duration       int64                  `json:"duration"` // 毫秒
+}
+
+// ReplayReport 会话记录的重放报告
+type ReplayReport struct {
+	SessionID string       `json:"session_id"`
+	StartTime time.Time    `json:"start_time"`
+	Duration  int64        `json:"duration"` // 毫秒
+	Total     int          `json:"total"`
+	Matched   int          `json:"matched"`
+	Changed   int          `json:"changed"`
+	Diverged  int          `json:"diverged"`
+	Missing   int          `json:"missing"`
+	Skipped   int          `json:"skipped"`
+	Steps     []ReplayStep `json:"steps"`
+}
+
+// ExportSession 导出会话记录，截图工具保存的截图以 base64 嵌入
+func (am *AgentManager) ExportSession(sessionID string) (*SessionTranscript, error) {
+	am.mu.RLock()
+	session, ok := am.sessions[sessionID]
+	if !ok {
+		am.mu.RUnlock()
+		return nil, fmt.Errorf("Session not found: %s", sessionID)
+	}
+	// 复制消息和工具调用，避免与正在进行的对话并发读写
+	messages := make([]ChatMessage, len(session.Messages))
+	for i, msg := range session.Messages {
+		messages[i] = msg
+		messages[i].ToolCalls = make([]*ToolCall, len(msg.ToolCalls))
+		for j, tc := range msg.ToolCalls {
+			copied := *tc
+			messages[i].ToolCalls[j] = &copied
+		}
+	}
+	transcript := &SessionTranscript{
+		Version:     TranscriptVersion,
+		ExportedAt:  time.Now(),
+		SessionID:   session.ID,
+		LLMConfigID: session.LLMConfigID,
+		CreatedAt:   session.CreatedAt,
+		Messages:    messages,
+	}
+	am.mu.RUnlock()
+
+	for i, msg := range transcript.Messages {
+		for j, tc := range msg.ToolCalls {
+			if tc.ToolName != screenshotToolName || tc.Status != "success" {
+				continue
+			}
+			for _, match := range screenshotPathPattern.FindAllStringSubmatch(tc.Result, -1) {
+				transcript.Screenshots = append(transcript.Screenshots, embedScreenshot(i, j, match[1]))
+			}
+		}
+	}
+	return transcript, nil
+}
+
+// embedScreenshot 读取截图文件并以 base64 嵌入
+func embedScreenshot(message, toolCall int, path string) TranscriptScreenshot {
+	shot := TranscriptScreenshot{Message: message, ToolCall: toolCall, Path: path}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png":
+		shot.MimeType = "image/png"
+	case ".jpg", ".jpeg":
+		shot.MimeType = "image/jpeg"
+	case ".webp":
+		shot.MimeType = "image/webp"
+	default:
+		shot.Error = "not an image file"
+		return shot
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		shot.Error = "file not found"
+		return shot
+	}
+	if info.Size() > maxTranscriptScreenshotSize {
+		shot.Error = fmt.Sprintf("file too large (%d bytes)", info.Size())
+		return shot
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		shot.Error = err.Error()
+		return shot
+	}
+	shot.Data = base64.StdEncoding.EncodeToString(data)
+	return shot
+}
+
+// RenderMarkdown 把会话记录渲染为 Markdown，截图以 data URI 嵌入
+func (t *SessionTranscript) RenderMarkdown() string {
+	shots := make(map[[2]int][]TranscriptScreenshot)
+	for _, shot := range t.Screenshots {
+		key := [2]int{shot.Message, shot.ToolCall}
+		shots[key] = append(shots[key], shot)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Agent session %s\n\n", t.SessionID)
+	fmt.Fprintf(&b, "- Created: %s\n", t.CreatedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "- Exported: %s\n", t.ExportedAt.Format(time.RFC3339))
+	if t.LLMConfigID != "" {
+		fmt.Fprintf(&b, "- LLM config: %s\n", t.LLMConfigID)
+	}
+
+	for i, msg := range t.Messages {
+		fmt.Fprintf(&b, "\n## %s · %s\n\n", msg.Role, msg.Timestamp.Format(time.RFC3339))
+		if msg.Content != "" {
+			b.WriteString(msg.Content)
+			b.WriteString("\n")
+		}
+		for j, tc := range msg.ToolCalls {
+			fmt.Fprintf(&b, "\n### Tool call %d: `%s` (%s)\n\n", j+1, tc.ToolName, tc.Status)
+			if tc.Instructions != "" {
+				fmt.Fprintf(&b, "%s\n\n", tc.Instructions)
+			}
+			if len(tc.Arguments) > 0 {
+				args, _ := json.MarshalIndent(tc.Arguments, "", "  ")
+				fmt.Fprintf(&b, "Arguments:\n\n```json\n%s\n```\n\n", args)
+			}
+			if tc.Result != "" {
+				fmt.Fprintf(&b, "Result:\n\n```\n%s\n```\n", strings.TrimRight(tc.Result, "\n"))
+			}
+			for _, shot := range shots[[2]int{i, j}] {
+				if shot.Data == "" {
+					fmt.Fprintf(&b, "\nScreenshot `%s` not embedded: %s\n", shot.Path, shot.Error)
+					continue
+				}
+				fmt.Fprintf(&b, "\n![%s](data:%s;base64,%s)\n", filepath.Base(shot.Path), shot.MimeType, shot.Data)
+			}
+		}
+	}
+	return b.String()
+}
+
+// ReplayTranscript 按记录的顺序和参数依次重新执行会话记录中的工具调用，不经过 LLM，
+// 并与记录的结果对比；浏览器工具在独立的浏览器会话中执行，结束后释放。
+// stopOnDivergence 为 true 时，第一次与记录不一致（或工具不存在）后其余调用不再执行
+func (am *AgentManager) ReplayTranscript(ctx context.Context, transcript *SessionTranscript, stopOnDivergence bool) *ReplayReport {
+	report := &ReplayReport{SessionID: transcript.SessionID, StartTime: time.Now(), Steps: []ReplayStep{}}
+
+	replayID := "replay_" + uuid.New().String()
+	ctx = memory.WithConversationID(ctx, replayID)
+	if am.mcpServer != nil {
+		defer am.mcpServer.ReleaseSession(executor.AgentSessionID(replayID))
+	}
+
+	stopped := false
+	for i, msg := range transcript.Messages {
+		for j, tc := range msg.ToolCalls {
+			step := ReplayStep{
+				Message:        i,
+				ToolCall:       j,
+				ToolName:       tc.ToolName,
+				Arguments:      tc.Arguments,
+				RecordedStatus: tc.Status,
+				RecordedResult: tc.Result,
+			}
+			if stopped {
+				step.Outcome = ReplaySkipped
+			} else {
+				am.replayToolCall(ctx, tc, &step)
+				stopped = stopOnDivergence && (step.Outcome == ReplayDiverged || step.Outcome == ReplayMissing)
+			}
+			report.add(step)
+		}
+	}
+	report.Duration = time.Since(report.StartTime).Milliseconds()
+	return report
+}
+
+// replayToolCall 执行一次记录的工具调用并判断结果
+func (am *AgentManager) replayToolCall(ctx context.Context, tc *ToolCall, step *ReplayStep) {
+	tool, ok := am.toolReg.Get(tc.ToolName)
+	if !ok {
+		step.Outcome = ReplayMissing
+		step.Error = "tool is not available"
+		return
+	}
+
+	args := tc.Arguments
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	input, err := json.Marshal(args)
+	if err != nil {
+		step.Outcome = ReplayDiverged
+		step.Error = err.Error()
+		return
+	}
+
+	start := time.Now()
+	result, err := tool.Execute(ctx, string(input))
+	step.Duration = time.Since(start).Milliseconds()
+	step.Result = result
+	if err != nil {
+		step.Error = err.Error()
+	}
+
+	recordedFailed := tc.Status == "error"
+	switch {
+	case (err != nil) != recordedFailed:
+		step.Outcome = ReplayDiverged
+	case err == nil && result == tc.Result:
+		step.Outcome = ReplayMatched
+	case err != nil && tc.Result == "Error: "+err.Error():
+		step.Outcome = ReplayMatched
+	default:
+		step.Outcome = ReplayChanged
+	}
+}
+
+func (r *ReplayReport) add(step ReplayStep) {
+	r.Total++
+	switch step.Outcome {
+	case ReplayMatched:
+		r.Matched++
+	case ReplayChanged:
+		r.Changed++
+	case ReplayDiverged:
+		r.Diverged++
+	case ReplayMissing:
+		r.Missing++
+	case ReplaySkipped:
+		r.Skipped++
+	}
+	r.Steps = append(r.Steps, step)
+}
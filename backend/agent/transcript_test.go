@@ -0,0 +1,104 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/tools"
+	"github.com/browserwing/browserwing/pkg/logger"
+)
+
+// echoTool 返回参数中的 text，text 为 fail 时返回错误
+type echoTool struct{ calls []string }
+
+func (t *echoTool) Name() string        { return "echo" }
+func (t *echoTool) Description() string { return "echo" }
+func (t *echoTool) Parameters() map[string]interfaces.ParameterSpec {
+	return map[string]interfaces.ParameterSpec{}
+}
+func (t *echoTool) Run(ctx context.Context, input string) (string, error) {
+	return t.Execute(ctx, input)
+}
+func (t *echoTool) Execute(ctx context.Context, input string) (string, error) {
+	var args struct{ Text string }
+	if err := json.Unmarshal([]byte(input), &args); err != nil {
+		return "", err
+	}
+	t.calls = append(t.calls, args.Text)
+	if args.Text == "fail" {
+		return "", fmt.Errorf("failed")
+	}
+	return args.Text, nil
+}
+
+func TestExportAndReplayTranscript(t *testing.T) {
+	logger.InitLogger(&logger.LoggerConfig{Level: "error"})
+	shot := filepath.Join(t.TempDir(), "shot.png")
+	if err := os.WriteFile(shot, []byte("png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	echo := &echoTool{}
+	am := &AgentManager{sessions: map[string]*ChatSession{}, toolReg: tools.NewRegistry()}
+	am.toolReg.Register(echo)
+	call := func(name, status, text, result string) *ToolCall {
+		return &ToolCall{ToolName: name, Status: status, Arguments: map[string]interface{}{"text": text}, Result: result}
+	}
+	am.sessions["s1"] = &ChatSession{ID: "s1", CreatedAt: time.Now(), Messages: []ChatMessage{
+		{Role: "user", Content: "hello"},
+		{Role: "assistant", Content: "done", ToolCalls: []*ToolCall{
+			call("echo", "success", "a", "a"),
+			call("echo", "success", "b", "old"),
+			call(screenshotToolName, "success", "", "Successfully captured screenshot (3 bytes) and saved to: "+shot),
+			call("echo", "error", "ok", "Error: failed"),
+			call("echo", "success", "c", "c"),
+		}},
+	}}
+
+	transcript, err := am.ExportSession("s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transcript.Screenshots) != 1 || transcript.Screenshots[0].Data != "cG5n" || transcript.Screenshots[0].ToolCall != 2 {
+		t.Errorf("expected the screenshot to be embedded, got %+v", transcript.Screenshots)
+	}
+	if md := transcript.RenderMarkdown(); !strings.Contains(md, "data:image/png;base64,cG5n") || !strings.Contains(md, "`echo` (error)") {
+		t.Errorf("unexpected markdown:\n%s", md)
+	}
+
+	// 通过 JSON 导入，与导出的文件相同
+	raw, _ := json.Marshal(transcript)
+	var imported SessionTranscript
+	if err := json.Unmarshal(raw, &imported); err != nil {
+		t.Fatal(err)
+	}
+
+	report := am.ReplayTranscript(context.Background(), &imported, false)
+	outcomes := []string{}
+	for _, step := range report.Steps {
+		outcomes = append(outcomes, step.Outcome)
+	}
+	want := []string{ReplayMatched, ReplayChanged, ReplayMissing, ReplayDiverged, ReplayMatched}
+	if strings.Join(outcomes, ",") != strings.Join(want, ",") {
+		t.Errorf("expected outcomes %v, got %v", want, outcomes)
+	}
+	if report.Total != 5 || report.Matched != 2 || report.Missing != 1 {
+		t.Errorf("unexpected report counts %+v", report)
+	}
+	if strings.Join(echo.calls, ",") != "a,b,ok,c" {
+		t.Errorf("tool calls should be replayed in order, got %v", echo.calls)
+	}
+
+	echo.calls = nil
+	report = am.ReplayTranscript(context.Background(), &imported, true)
+	if report.Skipped != 2 || strings.Join(echo.calls, ",") != "a,b" {
+		t.Errorf("replay should stop at the first divergence, got %+v, calls %v", report, echo.calls)
+	}
+}
@@ -0,0 +1,232 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+)
+
+const (
+	// verificationSettleDelay 操作后等待页面更新的时间
+	verificationSettleDelay = 500 * time.Millisecond
+	// maxVerificationSnapshot 校验提示词中页面快照的最大长度
+	maxVerificationSnapshot = 12000
+	// maxVerificationResult 校验提示词中工具结果的最大长度
+	maxVerificationResult = 2000
+)
+
+// mutatingTools 会改变页面状态的工具，开启操作校验时执行成功后检查预期的变化是否发生
+var mutatingTools = map[string]bool{
+	"browser_click":            true,
+	"browser_type":             true,
+	"browser_select":           true,
+	"browser_fill_form":        true,
+	"browser_press_key":        true,
+	"browser_choose_option":    true,
+	"browser_fill_date":        true,
+	"browser_drag":             true,
+	"browser_hover_then_click": true,
+	"browser_click_at":         true,
+	"browser_drag_box":         true,
+	"browser_file_upload":      true,
+	"browser_handle_dialog":    true,
+}
+
+// ActionVerification 操作后的校验结果
+type ActionVerification struct {
+	Verified   bool      `json:"verified"`
+	Reason     string    `json:"reason,omitempty"`
+	Screenshot string    `json:"screenshot,omitempty"` // 操作后的截图路径，作为校验依据保留
+	Error      string    `json:"error,omitempty"`      // 校验本身失败时的原因，此时不判定操作结果
+	CheckedAt  time.Time `json:"checked_at"`
+}
+
+// verificationLog 暂存工具调用的校验结果，直到对话把它附加到对应的工具调用上
+type verificationLog struct {
+	mu      sync.Mutex
+	results map[string]map[string]*ActionVerification // sessionID -> 工具名 -> 最近一次校验结果
+}
+
+func (l *verificationLog) record(sessionID, toolName string, v *ActionVerification) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.results == nil {
+		l.results = make(map[string]map[string]*ActionVerification)
+	}
+	if l.results[sessionID] == nil {
+		l.results[sessionID] = make(map[string]*ActionVerification)
+	}
+	l.results[sessionID][toolName] = v
+}
+
+// take 取出并清除工具最近一次的校验结果
+func (l *verificationLog) take(sessionID, toolName string) *ActionVerification {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	v := l.results[sessionID][toolName]
+	delete(l.results[sessionID], toolName)
+	return v
+}
+
+func (l *verificationLog) forget(sessionID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.results, sessionID)
+}
+
+// SetVerifyActions 开启或关闭会话的操作校验
+func (am *AgentManager) SetVerifyActions(sessionID string, enabled bool) error {
+	am.mu.Lock()
+	session, ok := am.sessions[sessionID]
+	if !ok {
+		am.mu.Unlock()
+		return fmt.Errorf("Session not found: %s", sessionID)
+	}
+	session.VerifyActions = enabled
+	dbSession := session.dbModel()
+	am.mu.Unlock()
+
+	if am.db != nil {
+		if err := am.db.SaveAgentSession(dbSession); err != nil {
+			return fmt.Errorf("failed to save session: %w", err)
+		}
+	}
+	return nil
+}
+
+// verifyToolCall 开启操作校验的会话中，改变页面的工具调用成功后截图并让 LLM 判断预期的变化是否发生。
+// 未发生时在工具结果后追加说明，让 Agent 检查页面后用修正的操作重试，而不是继续后面的步骤
+func (am *AgentManager) verifyToolCall(ctx context.Context, name string, args map[string]interface{}, result string) string {
+	if !mutatingTools[name] {
+		return result
+	}
+	sessionID, ok := memory.GetConversationID(ctx)
+	if !ok {
+		return result
+	}
+	am.mu.RLock()
+	session := am.sessions[sessionID]
+	enabled := session != nil && session.VerifyActions
+	var instances *AgentInstances
+	if enabled {
+		instances = am.agents[sessionID]
+	}
+	am.mu.RUnlock()
+	if !enabled || instances == nil || instances.LLMClient == nil {
+		return result
+	}
+
+	time.Sleep(verificationSettleDelay)
+	v := &ActionVerification{CheckedAt: time.Now()}
+	defer am.verifications.record(sessionID, name, v)
+
+	screenshot, err := am.mcpServer.CallTool(ctx, screenshotToolName, map[string]interface{}{})
+	if err != nil {
+		logger.Warn(ctx, "Failed to capture verification screenshot after %s: %v", name, err)
+	} else {
+		v.Screenshot = resultDataString(screenshot, "path")
+	}
+
+	snapshot, err := am.mcpServer.CallTool(ctx, "browser_snapshot", map[string]interface{}{})
+	if err != nil {
+		v.Error = fmt.Sprintf("failed to read the page: %v", err)
+		logger.Warn(ctx, "Verification of %s skipped: %s", name, v.Error)
+		return result
+	}
+
+	// 路由策略可以把操作校验交给小模型
+	llmClient := instances.LLMClient
+	if cheap := am.cheapLLM(models.LLMStepActionVerification); cheap != nil {
+		llmClient = cheap
+	}
+	response, err := llmClient.Generate(ctx, buildVerificationPrompt(name, args, result, resultDataString(snapshot, "accessibility_snapshot")))
+	if err != nil {
+		v.Error = fmt.Sprintf("verification request failed: %v", err)
+		logger.Warn(ctx, "Verification of %s skipped: %s", name, v.Error)
+		return result
+	}
+	if err := parseVerification(response, v); err != nil {
+		v.Error = err.Error()
+		logger.Warn(ctx, "Verification of %s skipped: %s", name, v.Error)
+		return result
+	}
+
+	if v.Verified {
+		logger.Info(ctx, "Verified %s in session %s: %s", name, sessionID, v.Reason)
+		return result
+	}
+	logger.Warn(ctx, "Verification failed for %s in session %s: %s", name, sessionID, v.Reason)
+	note := "\n\nVERIFICATION FAILED: the expected change did not occur after this action. " + v.Reason
+	if v.Screenshot != "" {
+		note += "\nScreenshot after the action: " + v.Screenshot
+	}
+	return result + note + "\nInspect the page with browser_snapshot and retry with a corrected action before continuing."
+}
+
+// buildVerificationPrompt 校验提示词：操作意图、参数和结果，以及操作后的页面快照
+func buildVerificationPrompt(name string, args map[string]interface{}, result, snapshot string) string {
+	instructions, _ := args["instructions"].(string)
+	arguments := make(map[string]interface{}, len(args))
+	for k, v := range args {
+		if k != "instructions" {
+			arguments[k] = v
+		}
+	}
+	argsJSON, _ := json.Marshal(arguments)
+
+	return fmt.Sprintf(`A browser automation agent just performed an action. Decide whether the expected change actually occurred on the page.
+
+Action: %s
+Arguments: %s
+Intent: %s
+Tool result:
+%s
+
+Page after the action:
+%s
+
+Answer with JSON only: {"verified": true or false, "reason": "one sentence"}.
+Answer false only when the page shows the action missed its intent, e.g. the wrong element was clicked, the value was not entered, or the expected dialog, page or state change is absent.`,
+		name, argsJSON, instructions, truncateText(result, maxVerificationResult), truncateText(snapshot, maxVerificationSnapshot))
+}
+
+// parseVerification 解析 LLM 返回的校验结果
+func parseVerification(response string, v *ActionVerification) error {
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start < 0 || end < start {
+		return fmt.Errorf("verification response is not JSON: %s", truncateText(response, 200))
+	}
+	var parsed struct {
+		Verified *bool  `json:"verified"`
+		Reason   string `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(response[start:end+1]), &parsed); err != nil || parsed.Verified == nil {
+		return fmt.Errorf("invalid verification response: %s", truncateText(response, 200))
+	}
+	v.Verified = *parsed.Verified
+	v.Reason = parsed.Reason
+	return nil
+}
+
+// resultDataString 从 MCP 工具结果的 data 字段读取字符串
+func resultDataString(result interface{}, key string) string {
+	resultMap, _ := result.(map[string]interface{})
+	data, _ := resultMap["data"].(map[string]interface{})
+	value, _ := data[key].(string)
+	return value
+}
+
+func truncateText(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max] + "...(truncated)"
+}
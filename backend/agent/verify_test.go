@@ -0,0 +1,109 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/Ingenimax/agent-sdk-go/pkg/memory"
+	browsermcp "github.com/browserwing/browserwing/mcp"
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+)
+
+// pageServer 返回固定截图和快照的测试 MCP 服务
+type pageServer struct {
+	browsermcp.IMCPServer
+	calls []string
+}
+
+func (s *pageServer) CallTool(ctx context.Context, name string, args map[string]interface{}) (interface{}, error) {
+	s.calls = append(s.calls, name)
+	switch name {
+	case screenshotToolName:
+		return map[string]interface{}{"data": map[string]interface{}{"path": "/tmp/after.png"}}, nil
+	default:
+		return map[string]interface{}{"data": map[string]interface{}{"accessibility_snapshot": `button "Checkout"`}}, nil
+	}
+}
+
+// verdictLLM 返回固定校验结论的测试 LLM
+type verdictLLM struct {
+	interfaces.LLM
+	response string
+	prompt   string
+}
+
+func (l *verdictLLM) Generate(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	l.prompt = prompt
+	return l.response, nil
+}
+
+func TestVerifyToolCall(t *testing.T) {
+	logger.InitLogger(&logger.LoggerConfig{Level: "error"})
+	llm := &verdictLLM{response: "```json\n{\"verified\": false, \"reason\": \"The cart is still empty.\"}\n```"}
+	server := &pageServer{}
+	am := &AgentManager{
+		mcpServer: server,
+		sessions:  map[string]*ChatSession{"s1": {ID: "s1"}},
+		agents:    map[string]*AgentInstances{"s1": {LLMClient: llm}},
+		approvals: newApprovalGate(),
+	}
+	ctx := memory.WithConversationID(context.Background(), "s1")
+	args := map[string]interface{}{"identifier": "@e4", "instructions": "add the item to the cart"}
+
+	// 未开启时不校验
+	if got := am.verifyToolCall(ctx, "browser_click", args, "clicked"); got != "clicked" || len(server.calls) != 0 {
+		t.Fatalf("verification should be off by default, got %q, calls %v", got, server.calls)
+	}
+
+	if err := am.SetVerifyActions("s1", true); err != nil {
+		t.Fatal(err)
+	}
+	got := am.verifyToolCall(ctx, "browser_click", args, "clicked")
+	if !strings.HasPrefix(got, "clicked") || !strings.Contains(got, "VERIFICATION FAILED") || !strings.Contains(got, "/tmp/after.png") {
+		t.Errorf("expected a verification failure note, got %q", got)
+	}
+	if !strings.Contains(llm.prompt, "add the item to the cart") || !strings.Contains(llm.prompt, `button "Checkout"`) || strings.Contains(llm.prompt, `"instructions"`) {
+		t.Errorf("unexpected verification prompt:\n%s", llm.prompt)
+	}
+	v := am.verifications.take("s1", "browser_click")
+	if v == nil || v.Verified || v.Reason != "The cart is still empty." || v.Screenshot != "/tmp/after.png" {
+		t.Errorf("unexpected verification %+v", v)
+	}
+	if am.verifications.take("s1", "browser_click") != nil {
+		t.Error("a verification should only be taken once")
+	}
+
+	// 只校验改变页面的工具
+	server.calls = nil
+	if got := am.verifyToolCall(ctx, "browser_snapshot", nil, "tree"); got != "tree" || len(server.calls) != 0 {
+		t.Errorf("read-only tools should not be verified, got %q, calls %v", got, server.calls)
+	}
+
+	// 校验通过时结果不变
+	llm.response = `{"verified": true, "reason": "The item is in the cart."}`
+	if got := am.verifyToolCall(ctx, "browser_click", args, "clicked"); got != "clicked" {
+		t.Errorf("expected the unchanged result, got %q", got)
+	}
+
+	// 无法解析的回复不判定结果
+	llm.response = "I think so"
+	if got := am.verifyToolCall(ctx, "browser_type", args, "typed"); got != "typed" {
+		t.Errorf("expected the unchanged result, got %q", got)
+	}
+	if v := am.verifications.take("s1", "browser_type"); v == nil || v.Error == "" {
+		t.Errorf("expected a verification error, got %+v", v)
+	}
+
+	// 路由策略把操作校验交给小模型
+	cheap := &verdictLLM{response: `{"verified": true, "reason": "ok"}`}
+	am.routing.client = cheap
+	am.routing.policy = &models.LLMRoutingPolicy{CheapConfigID: "small", CheapSteps: []string{models.LLMStepActionVerification}}
+	llm.prompt = ""
+	am.verifyToolCall(ctx, "browser_click", args, "clicked")
+	if cheap.prompt == "" || llm.prompt != "" {
+		t.Error("action verification should be routed to the small model")
+	}
+}
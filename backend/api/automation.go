@@ -0,0 +1,318 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/services/browser"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// 自动化触发接口是面向低代码平台（n8n、Node-RED 等）的稳定契约：
+// 列出脚本及其参数定义，异步发起执行，再通过轮询或回调获取结果。
+// 字段只增不改，平台节点可以长期依赖
+
+const (
+	automationRunRetention = 24 * time.Hour // 已结束的执行保留时长
+	automationRunLimit     = 1000           // 最多保留的执行数量
+)
+
+// automationScript 自动化触发接口中的脚本摘要
+type automationScript struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Group       string   `json:"group"`
+	Tags        []string `json:"tags"`
+	URL         string   `json:"url"`
+	// 执行参数的 JSON Schema
+	Parameters map[string]interface{} `json:"parameters"`
+}
+
+// automationRunRequest 发起自动化执行的请求
+type automationRunRequest struct {
+	Params      map[string]string `json:"params"`
+	Environment *string           `json:"environment"`  // 执行环境名称或环境标签，未指定时使用脚本配置
+	RunTags     []string          `json:"run_tags"`     // 执行标签，未指定时使用脚本配置
+	InstanceID  string            `json:"instance_id"`  // 浏览器实例，空字符串表示当前实例
+	CallbackURL string            `json:"callback_url"` // 执行结束后接收 POST 通知的地址
+}
+
+// automationRunStore 内存中的自动化执行记录，服务重启后丢失；结果同时记录在脚本执行记录中
+type automationRunStore struct {
+	mu   sync.Mutex
+	runs map[string]*models.AutomationRun
+}
+
+func newAutomationRunStore() *automationRunStore {
+	return &automationRunStore{runs: make(map[string]*models.AutomationRun)}
+}
+
+// add 保存新的执行，并清理过期或超出数量的已结束执行
+func (s *automationRunStore) add(run *models.AutomationRun) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var finished []*models.AutomationRun
+	for id, r := range s.runs {
+		if !r.Finished() {
+			continue
+		}
+		if time.Since(*r.FinishedAt) > automationRunRetention {
+			delete(s.runs, id)
+			continue
+		}
+		finished = append(finished, r)
+	}
+	if excess := len(s.runs) + 1 - automationRunLimit; excess > 0 {
+		sort.Slice(finished, func(i, j int) bool { return finished[i].FinishedAt.Before(*finished[j].FinishedAt) })
+		for i := 0; i < excess && i < len(finished); i++ {
+			delete(s.runs, finished[i].ID)
+		}
+	}
+	s.runs[run.ID] = run
+}
+
+// get 返回执行的副本
+func (s *automationRunStore) get(id string) (models.AutomationRun, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run, ok := s.runs[id]
+	if !ok {
+		return models.AutomationRun{}, false
+	}
+	return *run, true
+}
+
+// update 在锁内修改执行并返回修改后的副本，执行已被清理时不做修改
+func (s *automationRunStore) update(id string, fn func(run *models.AutomationRun)) (models.AutomationRun, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run, ok := s.runs[id]
+	if !ok {
+		return models.AutomationRun{}, false
+	}
+	fn(run)
+	return *run, true
+}
+
+// ListAutomationScripts 列出可触发的脚本及其参数定义
+func (h *Handler) ListAutomationScripts(c *gin.Context) {
+	scripts, err := h.db.ListScripts()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.getScriptsFailed"})
+		return
+	}
+
+	group := c.Query("group")
+	tag := c.Query("tag")
+	items := make([]automationScript, 0, len(scripts))
+	for _, script := range scripts {
+		if group != "" && script.Group != group {
+			continue
+		}
+		if tag != "" && !slices.Contains(script.Tags, tag) {
+			continue
+		}
+		items = append(items, newAutomationScript(script))
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+
+	c.JSON(http.StatusOK, gin.H{"data": items})
+}
+
+// GetAutomationScript 获取单个脚本的参数定义
+func (h *Handler) GetAutomationScript(c *gin.Context) {
+	script, err := h.db.GetScript(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.scriptNotFound"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": newAutomationScript(script)})
+}
+
+// StartAutomationRun 异步执行脚本，立即返回执行记录，调用方轮询 GetAutomationRun 或等待回调
+func (h *Handler) StartAutomationRun(c *gin.Context) {
+	var req automationRunRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams", "detail": err.Error()})
+			return
+		}
+	}
+
+	if req.InstanceID == "" {
+		req.InstanceID = c.Query("instance_id")
+	}
+	if req.InstanceID == "" {
+		req.InstanceID = c.GetHeader("X-Instance-ID")
+	}
+
+	script, err := h.db.GetScript(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.scriptNotFound"})
+		return
+	}
+
+	if req.CallbackURL != "" {
+		if err := h.validateWebhookURL(c.Request.Context(), req.CallbackURL); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidWebhookUrl", "detail": err.Error()})
+			return
+		}
+	}
+
+	if err := browser.ValidateScriptParams(script, req.Params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams", "detail": err.Error()})
+		return
+	}
+
+	scriptToRun, err := h.prepareScriptRun(script, playScriptRequest{
+		Params:      req.Params,
+		Environment: req.Environment,
+		RunTags:     req.RunTags,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.applyEnvironmentFailed", "detail": err.Error()})
+		return
+	}
+
+	run := &models.AutomationRun{
+		ID:          uuid.New().String(),
+		ScriptID:    script.ID,
+		ScriptName:  script.Name,
+		Status:      models.AutomationRunRunning,
+		Params:      req.Params,
+		Environment: scriptToRun.Environment,
+		CallbackURL: req.CallbackURL,
+		StartedAt:   time.Now(),
+	}
+	h.automationRuns.add(run)
+	snapshot, _ := h.automationRuns.get(run.ID)
+
+	// 执行与请求的生命周期无关
+	go h.runAutomation(context.Background(), run.ID, scriptToRun, req.InstanceID)
+
+	c.JSON(http.StatusAccepted, gin.H{"data": snapshot})
+}
+
+// GetAutomationRun 查询自动化执行的状态和结果
+func (h *Handler) GetAutomationRun(c *gin.Context) {
+	run, ok := h.automationRuns.get(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.automationRunNotFound"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": run})
+}
+
+// runAutomation 执行脚本并记录结果，设置了回调地址时发送通知
+func (h *Handler) runAutomation(ctx context.Context, runID string, script *models.Script, instanceID string) {
+	result, err := h.playAutomationScript(ctx, script, instanceID)
+
+	finished, ok := h.automationRuns.update(runID, func(run *models.AutomationRun) {
+		now := time.Now()
+		run.FinishedAt = &now
+		run.Result = result
+		switch {
+		case err != nil:
+			run.Status = models.AutomationRunFailed
+			run.Error = err.Error()
+		case result != nil && !result.Success:
+			run.Status = models.AutomationRunFailed
+			run.Error = result.Message
+		default:
+			run.Status = models.AutomationRunSucceeded
+		}
+	})
+
+	if !ok || finished.CallbackURL == "" {
+		return
+	}
+	if err := h.sendAutomationCallback(ctx, &finished); err != nil {
+		logger.Warn(ctx, "Failed to send automation callback for run %s: %v", runID, err)
+		h.automationRuns.update(runID, func(run *models.AutomationRun) {
+			run.CallbackError = err.Error()
+		})
+	}
+}
+
+// playAutomationScript 确保浏览器实例运行并回放脚本
+func (h *Handler) playAutomationScript(ctx context.Context, script *models.Script, instanceID string) (*models.PlayResult, error) {
+	if !h.browserManager.IsInstanceRunning(instanceID) {
+		logger.Info(ctx, "Browser not running, starting...")
+		if err := h.browserManager.StartInstance(ctx, instanceID); err != nil {
+			return nil, fmt.Errorf("failed to start browser: %w", err)
+		}
+	}
+
+	result, page, err := h.browserManager.PlayScript(ctx, script, instanceID)
+	if err != nil {
+		return result, err
+	}
+	if err := h.browserManager.CloseActivePage(ctx, page); err != nil {
+		logger.Warn(ctx, "Failed to close page: %v", err)
+	}
+	return result, nil
+}
+
+// sendAutomationCallback 把结束的执行 POST 到回调地址，非 2xx 响应视为失败
+func (h *Handler) sendAutomationCallback(ctx context.Context, run *models.AutomationRun) error {
+	guard := h.browserManager.NetworkGuard()
+	// 提交时检查过，发送前再检查一次，防止 DNS 解析结果变化
+	if err := guard.Check(ctx, run.CallbackURL); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"event": "automation.run.finished",
+		"run":   run,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, run.CallbackURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := guard.HTTPClient(15 * time.Second).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("callback returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func newAutomationScript(script *models.Script) automationScript {
+	tags := script.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return automationScript{
+		ID:          script.ID,
+		Name:        script.Name,
+		Description: script.Description,
+		Group:       script.Group,
+		Tags:        tags,
+		URL:         script.URL,
+		Parameters:  browser.ScriptParameterSchema(script),
+	}
+}
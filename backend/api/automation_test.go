@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/browserwing/browserwing/config"
+	"github.com/browserwing/browserwing/mcp"
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/services/browser"
+	"github.com/browserwing/browserwing/storage"
+)
+
+func TestAutomationRunStoreEviction(t *testing.T) {
+	store := newAutomationRunStore()
+	old := time.Now().Add(-automationRunRetention - time.Hour)
+	store.runs["expired"] = &models.AutomationRun{ID: "expired", Status: models.AutomationRunSucceeded, FinishedAt: &old}
+	store.runs["running"] = &models.AutomationRun{ID: "running", Status: models.AutomationRunRunning}
+	for i := 0; i < automationRunLimit; i++ {
+		finished := time.Now().Add(time.Duration(i) * time.Second)
+		id := fmt.Sprintf("run-%d", i)
+		store.runs[id] = &models.AutomationRun{ID: id, Status: models.AutomationRunFailed, FinishedAt: &finished}
+	}
+
+	store.add(&models.AutomationRun{ID: "new", Status: models.AutomationRunRunning})
+
+	if len(store.runs) != automationRunLimit {
+		t.Errorf("expected %d runs, got %d", automationRunLimit, len(store.runs))
+	}
+	for _, id := range []string{"expired", "run-0", "run-1"} {
+		if _, ok := store.get(id); ok {
+			t.Errorf("run %s should have been evicted", id)
+		}
+	}
+	for _, id := range []string{"running", "run-2", "new"} {
+		if _, ok := store.get(id); !ok {
+			t.Errorf("run %s should be kept", id)
+		}
+	}
+	if _, ok := store.update("missing", func(*models.AutomationRun) {}); ok {
+		t.Error("update of a missing run should report false")
+	}
+}
+
+func TestAutomationEndpoints(t *testing.T) {
+	db, err := storage.NewBoltDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	script := &models.Script{
+		ID:      "s1",
+		Name:    "Search",
+		URL:     "https://example.com/search?q=${keyword}",
+		Actions: []models.ScriptAction{{Type: "click", Selector: "#go"}},
+	}
+	if err := db.SaveScript(script); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{Auth: &config.AuthConfig{}}
+	browserMgr := browser.NewManager(cfg, db, nil)
+	handler := NewHandler(db, browserMgr, cfg, nil)
+	handler.SetMCPServer(mcp.NewMCPServer(db, browserMgr))
+	r := SetupRouter(handler, nil, nil, false, false)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/automation/scripts", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("list: expected 200, got %d: %s", w.Code, w.Body)
+	}
+	var list struct {
+		Data []automationScript `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Data) != 1 || list.Data[0].ID != "s1" {
+		t.Fatalf("unexpected scripts: %+v", list.Data)
+	}
+	if required, _ := list.Data[0].Parameters["required"].([]interface{}); len(required) != 1 || required[0] != "keyword" {
+		t.Errorf("unexpected required parameters: %v", list.Data[0].Parameters["required"])
+	}
+
+	for _, tc := range []struct {
+		method, path, body string
+		code               int
+	}{
+		{http.MethodGet, "/api/v1/automation/scripts/missing", "", http.StatusNotFound},
+		{http.MethodPost, "/api/v1/automation/scripts/missing/runs", "", http.StatusNotFound},
+		{http.MethodPost, "/api/v1/automation/scripts/s1/runs", `{"callback_url":"ftp://example.com"}`, http.StatusBadRequest},
+		{http.MethodGet, "/api/v1/automation/runs/missing", "", http.StatusNotFound},
+	} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		if w.Code != tc.code {
+			t.Errorf("%s %s: expected %d, got %d", tc.method, tc.path, tc.code, w.Code)
+		}
+	}
+}
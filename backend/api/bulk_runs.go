@@ -0,0 +1,468 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/services/browser"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// 批量执行：同一个脚本按多行参数（CSV 或 JSON 数组）各执行一次，
+// 按并发数排队执行，结束后可以把所有行的结果合并导出为 CSV
+
+const (
+	bulkRunMaxRows        = 10000 // 单次批量执行最多的行数
+	bulkRunMaxConcurrency = 10    // 最大并发数
+	bulkRunLimit          = 100   // 最多保留的批量执行数量
+)
+
+// bulkRunParams 一行执行参数；JSON 中的数字、布尔值按原文转换为字符串，null 视为空字符串
+type bulkRunParams map[string]string
+
+func (p *bulkRunParams) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	params := make(bulkRunParams, len(raw))
+	for name, value := range raw {
+		switch {
+		case bytes.Equal(value, []byte("null")):
+			params[name] = ""
+		case len(value) > 0 && value[0] == '"':
+			var s string
+			if err := json.Unmarshal(value, &s); err != nil {
+				return err
+			}
+			params[name] = s
+		case len(value) > 0 && (value[0] == '{' || value[0] == '['):
+			return fmt.Errorf("parameter %s must be a string, number or boolean", name)
+		default:
+			params[name] = string(value)
+		}
+	}
+	*p = params
+	return nil
+}
+
+// bulkRunRequest 发起批量执行的 JSON 请求；以 text/csv 提交时请求体为带表头的 CSV，其余字段通过查询参数传递
+type bulkRunRequest struct {
+	Rows        []bulkRunParams `json:"rows"`        // 每行一组执行参数
+	Concurrency int             `json:"concurrency"` // 同时执行的行数，默认 1，最大 10
+	Environment *string         `json:"environment"` // 执行环境名称或环境标签，未指定时使用脚本配置
+	RunTags     []string        `json:"run_tags"`    // 执行标签，未指定时使用脚本配置
+	InstanceID  string          `json:"instance_id"` // 浏览器实例，空字符串表示当前实例
+}
+
+// bulkRunStore 内存中的批量执行记录，服务重启后丢失；每行的结果同时记录在脚本执行记录中
+type bulkRunStore struct {
+	mu   sync.Mutex
+	runs map[string]*models.BulkRun
+}
+
+func newBulkRunStore() *bulkRunStore {
+	return &bulkRunStore{runs: make(map[string]*models.BulkRun)}
+}
+
+// add 保存新的批量执行，并清理过期或超出数量的已结束批量执行
+func (s *bulkRunStore) add(run *models.BulkRun) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var finished []*models.BulkRun
+	for id, r := range s.runs {
+		if !r.Finished() {
+			continue
+		}
+		if time.Since(*r.FinishedAt) > automationRunRetention {
+			delete(s.runs, id)
+			continue
+		}
+		finished = append(finished, r)
+	}
+	if excess := len(s.runs) + 1 - bulkRunLimit; excess > 0 {
+		sort.Slice(finished, func(i, j int) bool { return finished[i].FinishedAt.Before(*finished[j].FinishedAt) })
+		for i := 0; i < excess && i < len(finished); i++ {
+			delete(s.runs, finished[i].ID)
+		}
+	}
+	s.runs[run.ID] = run
+}
+
+// get 返回批量执行的副本
+func (s *bulkRunStore) get(id string) (models.BulkRun, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run, ok := s.runs[id]
+	if !ok {
+		return models.BulkRun{}, false
+	}
+	return snapshotBulkRun(run), true
+}
+
+// startRow 把排队中的行标记为执行中，行已取消或批量执行已被清理时返回 false
+func (s *bulkRunStore) startRow(id string, index int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run, ok := s.runs[id]
+	if !ok || run.Rows[index].Status != models.BulkRunRowQueued {
+		return false
+	}
+	now := time.Now()
+	run.Rows[index].Status = models.BulkRunRowRunning
+	run.Rows[index].StartedAt = &now
+	return true
+}
+
+// finishRow 记录一行的执行结果，所有行结束时批量执行结束
+func (s *bulkRunStore) finishRow(id string, index int, result *models.PlayResult, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run, ok := s.runs[id]
+	if !ok || run.Rows[index].Finished() {
+		return
+	}
+
+	now := time.Now()
+	row := &run.Rows[index]
+	row.FinishedAt = &now
+	row.Result = result
+	switch {
+	case err != nil:
+		row.Status = models.BulkRunRowFailed
+		row.Error = err.Error()
+	case result != nil && !result.Success:
+		row.Status = models.BulkRunRowFailed
+		row.Error = result.Message
+	default:
+		row.Status = models.BulkRunRowSucceeded
+	}
+	if row.Status == models.BulkRunRowSucceeded {
+		run.Succeeded++
+	} else {
+		run.Failed++
+	}
+	finishBulkRunIfDone(run, now)
+}
+
+// cancel 取消批量执行：尚未开始的行不再执行，执行中的行继续到结束
+func (s *bulkRunStore) cancel(id string) (models.BulkRun, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run, ok := s.runs[id]
+	if !ok {
+		return models.BulkRun{}, false
+	}
+	if !run.Finished() {
+		run.Cancelled = true
+		for i := range run.Rows {
+			if run.Rows[i].Status == models.BulkRunRowQueued {
+				run.Rows[i].Status = models.BulkRunRowCancelled
+			}
+		}
+		finishBulkRunIfDone(run, time.Now())
+	}
+	return snapshotBulkRun(run), true
+}
+
+// finishBulkRunIfDone 所有行都结束时记录批量执行的结束时间
+func finishBulkRunIfDone(run *models.BulkRun, now time.Time) {
+	for i := range run.Rows {
+		if !run.Rows[i].Finished() {
+			return
+		}
+	}
+	run.FinishedAt = &now
+}
+
+// snapshotBulkRun 复制批量执行，行在锁外序列化时不受后续修改影响
+func snapshotBulkRun(run *models.BulkRun) models.BulkRun {
+	snapshot := *run
+	snapshot.Rows = slices.Clone(run.Rows)
+	return snapshot
+}
+
+// StartBulkRun 用多行参数批量执行脚本，立即返回批量执行记录，调用方轮询 GetBulkRun 查看进度
+func (h *Handler) StartBulkRun(c *gin.Context) {
+	var req bulkRunRequest
+	var columns []string
+	if c.ContentType() == "text/csv" {
+		var err error
+		if columns, req.Rows, err = parseBulkRunCSV(c.Request.Body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams", "detail": err.Error()})
+			return
+		}
+		req.Concurrency, _ = strconv.Atoi(c.Query("concurrency"))
+		if env, ok := c.GetQuery("environment"); ok {
+			req.Environment = &env
+		}
+		if tags := c.Query("run_tags"); tags != "" {
+			req.RunTags = strings.Split(tags, ",")
+		}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams", "detail": err.Error()})
+		return
+	}
+
+	if req.InstanceID == "" {
+		req.InstanceID = c.Query("instance_id")
+	}
+	if req.InstanceID == "" {
+		req.InstanceID = c.GetHeader("X-Instance-ID")
+	}
+	if len(req.Rows) == 0 || len(req.Rows) > bulkRunMaxRows {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams", "detail": fmt.Sprintf("rows must contain 1 to %d parameter sets", bulkRunMaxRows)})
+		return
+	}
+	if req.Concurrency <= 0 {
+		req.Concurrency = 1
+	}
+	if req.Concurrency > bulkRunMaxConcurrency {
+		req.Concurrency = bulkRunMaxConcurrency
+	}
+	if columns == nil {
+		columns = bulkRunColumns(req.Rows)
+	}
+
+	script, err := h.db.GetScript(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.scriptNotFound"})
+		return
+	}
+
+	// 执行前检查所有行，任何一行参数无效都不开始执行
+	run := &models.BulkRun{
+		ID:          uuid.New().String(),
+		ScriptID:    script.ID,
+		ScriptName:  script.Name,
+		Concurrency: req.Concurrency,
+		Columns:     columns,
+		Total:       len(req.Rows),
+		Rows:        make([]models.BulkRunRow, len(req.Rows)),
+		StartedAt:   time.Now(),
+	}
+	scripts := make([]*models.Script, len(req.Rows))
+	for i, params := range req.Rows {
+		if err := browser.ValidateScriptParams(script, params); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams", "detail": fmt.Sprintf("row %d: %v", i+1, err)})
+			return
+		}
+		scriptToRun, err := h.prepareScriptRun(script, playScriptRequest{
+			Params:      params,
+			Environment: req.Environment,
+			RunTags:     req.RunTags,
+		})
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.applyEnvironmentFailed", "detail": fmt.Sprintf("row %d: %v", i+1, err)})
+			return
+		}
+		scripts[i] = scriptToRun
+		run.Environment = scriptToRun.Environment
+		run.Rows[i] = models.BulkRunRow{Index: i + 1, Params: params, Status: models.BulkRunRowQueued}
+	}
+	h.bulkRuns.add(run)
+	snapshot, _ := h.bulkRuns.get(run.ID)
+
+	// 执行与请求的生命周期无关
+	go h.runBulk(context.Background(), run.ID, scripts, req.InstanceID, req.Concurrency)
+
+	c.JSON(http.StatusAccepted, gin.H{"data": snapshot})
+}
+
+// GetBulkRun 查询批量执行的进度和每行的结果
+func (h *Handler) GetBulkRun(c *gin.Context) {
+	run, ok := h.bulkRuns.get(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.bulkRunNotFound"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": run})
+}
+
+// CancelBulkRun 取消批量执行中尚未开始的行
+func (h *Handler) CancelBulkRun(c *gin.Context) {
+	run, ok := h.bulkRuns.cancel(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.bulkRunNotFound"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": run})
+}
+
+// ExportBulkRunResults 导出批量执行的合并结果：默认为 CSV（每行一条，参数列在前、抓取的数据列在后），format=json 时返回行列表
+func (h *Handler) ExportBulkRunResults(c *gin.Context) {
+	run, ok := h.bulkRuns.get(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.bulkRunNotFound"})
+		return
+	}
+	if c.Query("format") == "json" {
+		c.JSON(http.StatusOK, gin.H{"data": run.Rows})
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := writeBulkRunCSV(&buf, &run); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.exportBulkRunFailed", "detail": err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="bulk-run-%s.csv"`, run.ID))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+// runBulk 按并发数依次执行每一行，已取消的行跳过
+func (h *Handler) runBulk(ctx context.Context, runID string, scripts []*models.Script, instanceID string, concurrency int) {
+	// 先启动浏览器实例，避免并发执行的行重复启动
+	if !h.browserManager.IsInstanceRunning(instanceID) {
+		logger.Info(ctx, "Browser not running, starting...")
+		if err := h.browserManager.StartInstance(ctx, instanceID); err != nil {
+			err = fmt.Errorf("failed to start browser: %w", err)
+			for i := range scripts {
+				h.bulkRuns.finishRow(runID, i, nil, err)
+			}
+			return
+		}
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, script := range scripts {
+		sem <- struct{}{}
+		if !h.bulkRuns.startRow(runID, i) {
+			<-sem
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			result, err := h.playAutomationScript(ctx, script, instanceID)
+			h.bulkRuns.finishRow(runID, i, result, err)
+		}()
+	}
+	wg.Wait()
+}
+
+// parseBulkRunCSV 解析带表头的 CSV，表头为参数名，每行一组参数
+func parseBulkRunCSV(r io.Reader) ([]string, []bulkRunParams, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil, fmt.Errorf("CSV must start with a header row of parameter names")
+	}
+
+	header := records[0]
+	header[0] = strings.TrimPrefix(header[0], "\ufeff") // Excel 导出的 UTF-8 BOM
+	seen := make(map[string]bool, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			return nil, nil, fmt.Errorf("CSV header column %d must be a unique, non-empty parameter name", i+1)
+		}
+		seen[name] = true
+		header[i] = name
+	}
+
+	rows := make([]bulkRunParams, 0, len(records)-1)
+	for _, record := range records[1:] {
+		params := make(bulkRunParams, len(header))
+		for i, name := range header {
+			params[name] = record[i]
+		}
+		rows = append(rows, params)
+	}
+	return header, rows, nil
+}
+
+// bulkRunColumns JSON 提交时的参数列：所有行参数名的并集，按名称排序
+func bulkRunColumns(rows []bulkRunParams) []string {
+	seen := make(map[string]bool)
+	columns := []string{}
+	for _, params := range rows {
+		for name := range params {
+			if !seen[name] {
+				seen[name] = true
+				columns = append(columns, name)
+			}
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// writeBulkRunCSV 写出合并结果：行号、状态、错误、参数列，以及所有行抓取数据键的并集（非字符串值写为 JSON）
+func writeBulkRunCSV(w io.Writer, run *models.BulkRun) error {
+	seen := make(map[string]bool)
+	var dataColumns []string
+	for _, row := range run.Rows {
+		if row.Result == nil {
+			continue
+		}
+		for key := range row.Result.ExtractedData {
+			if !seen[key] {
+				seen[key] = true
+				dataColumns = append(dataColumns, key)
+			}
+		}
+	}
+	sort.Strings(dataColumns)
+
+	writer := csv.NewWriter(w)
+	header := append([]string{"row", "status", "error"}, run.Columns...)
+	if err := writer.Write(append(header, dataColumns...)); err != nil {
+		return err
+	}
+	for _, row := range run.Rows {
+		record := []string{strconv.Itoa(row.Index), string(row.Status), row.Error}
+		for _, name := range run.Columns {
+			record = append(record, row.Params[name])
+		}
+		for _, key := range dataColumns {
+			var value interface{}
+			if row.Result != nil {
+				value = row.Result.ExtractedData[key]
+			}
+			record = append(record, csvValue(value))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvValue 抓取数据的单元格内容：字符串原样输出，其他值输出为 JSON
+func csvValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
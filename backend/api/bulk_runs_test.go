@@ -0,0 +1,144 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/browserwing/browserwing/config"
+	"github.com/browserwing/browserwing/mcp"
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/services/browser"
+	"github.com/browserwing/browserwing/storage"
+)
+
+func TestBulkRunRows(t *testing.T) {
+	columns, rows, err := parseBulkRunCSV(strings.NewReader("\ufeffsku, region\nA-1,eu\n\"B,2\", us\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(columns, []string{"sku", "region"}) {
+		t.Errorf("unexpected columns %v", columns)
+	}
+	if len(rows) != 2 || rows[1]["sku"] != "B,2" || rows[1]["region"] != "us" {
+		t.Errorf("unexpected rows %v", rows)
+	}
+	for _, invalid := range []string{"", "sku,sku\n1,2\n", "sku,\n1,2\n", "sku,region\n1\n"} {
+		if _, _, err := parseBulkRunCSV(strings.NewReader(invalid)); err == nil {
+			t.Errorf("CSV %q should be rejected", invalid)
+		}
+	}
+
+	// JSON 中的数字、布尔值按原文转换，null 为空字符串
+	var req bulkRunRequest
+	if err := json.Unmarshal([]byte(`{"rows":[{"sku":10000001,"gift":true,"note":null,"name":"x"}]}`), &req); err != nil {
+		t.Fatal(err)
+	}
+	want := bulkRunParams{"sku": "10000001", "gift": "true", "note": "", "name": "x"}
+	if !reflect.DeepEqual(req.Rows[0], want) {
+		t.Errorf("expected %v, got %v", want, req.Rows[0])
+	}
+	if err := json.Unmarshal([]byte(`{"rows":[{"sku":[1]}]}`), &req); err == nil {
+		t.Error("array parameter should be rejected")
+	}
+	if got := bulkRunColumns(req.Rows); !reflect.DeepEqual(got, []string{"gift", "name", "note", "sku"}) {
+		t.Errorf("unexpected columns %v", got)
+	}
+}
+
+func TestBulkRunStore(t *testing.T) {
+	store := newBulkRunStore()
+	run := &models.BulkRun{ID: "b1", Columns: []string{"sku"}, Total: 3}
+	for i, sku := range []string{"A", "B", "C"} {
+		run.Rows = append(run.Rows, models.BulkRunRow{Index: i + 1, Params: map[string]string{"sku": sku}, Status: models.BulkRunRowQueued})
+	}
+	store.add(run)
+
+	if !store.startRow("b1", 0) || !store.startRow("b1", 1) {
+		t.Fatal("queued rows should start")
+	}
+	store.finishRow("b1", 0, &models.PlayResult{Success: true, ExtractedData: map[string]interface{}{"price": "9.90", "stock": 3}}, nil)
+	snapshot, _ := store.cancel("b1")
+	if snapshot.Rows[2].Status != models.BulkRunRowCancelled || snapshot.Finished() {
+		t.Fatalf("queued row should be cancelled while row 2 still runs: %+v", snapshot)
+	}
+	if store.startRow("b1", 2) {
+		t.Error("cancelled row should not start")
+	}
+	store.finishRow("b1", 1, &models.PlayResult{Success: false, Message: "timeout"}, nil)
+
+	finished, _ := store.get("b1")
+	if !finished.Finished() || finished.Succeeded != 1 || finished.Failed != 1 {
+		t.Fatalf("unexpected bulk run %+v", finished)
+	}
+
+	var buf bytes.Buffer
+	if err := writeBulkRunCSV(&buf, &finished); err != nil {
+		t.Fatal(err)
+	}
+	want := "row,status,error,sku,price,stock\n1,succeeded,,A,9.90,3\n2,failed,timeout,B,,\n3,cancelled,,C,,\n"
+	if buf.String() != want {
+		t.Errorf("unexpected CSV:\n%s", buf.String())
+	}
+}
+
+func TestBulkRunEndpoints(t *testing.T) {
+	db, err := storage.NewBoltDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	script := &models.Script{
+		ID:            "s1",
+		Name:          "Lookup",
+		URL:           "https://example.com/item/${sku}",
+		Actions:       []models.ScriptAction{{Type: "click", Selector: "#go"}},
+		ParamUISchema: map[string]models.ParamUIField{"sku": {Pattern: "[A-Z]-[0-9]+"}},
+	}
+	if err := db.SaveScript(script); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{Auth: &config.AuthConfig{}}
+	logger.InitLogger(&logger.LoggerConfig{Level: "error"})
+	browserMgr := browser.NewManager(cfg, db, nil)
+	handler := NewHandler(db, browserMgr, cfg, nil)
+	handler.SetMCPServer(mcp.NewMCPServer(db, browserMgr))
+	r := SetupRouter(handler, nil, nil, false, false)
+
+	for _, tc := range []struct {
+		name, path, contentType, body string
+		code                          int
+	}{
+		{"missing script", "/api/v1/automation/scripts/missing/bulk-runs", "application/json", `{"rows":[{"sku":"A-1"}]}`, http.StatusNotFound},
+		{"no rows", "/api/v1/automation/scripts/s1/bulk-runs", "application/json", `{"rows":[]}`, http.StatusBadRequest},
+		{"invalid row", "/api/v1/automation/scripts/s1/bulk-runs", "application/json", `{"rows":[{"sku":"A-1"},{"sku":"nope"}]}`, http.StatusBadRequest},
+		{"invalid CSV row", "/api/v1/automation/scripts/s1/bulk-runs", "text/csv", "sku\nA-1\nnope\n", http.StatusBadRequest},
+		{"header only CSV", "/api/v1/automation/scripts/s1/bulk-runs", "text/csv", "sku\n", http.StatusBadRequest},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", tc.contentType)
+			r.ServeHTTP(w, req)
+			if w.Code != tc.code {
+				t.Fatalf("expected %d, got %d: %s", tc.code, w.Code, w.Body)
+			}
+		})
+	}
+
+	for _, path := range []string{"/api/v1/automation/bulk-runs/missing", "/api/v1/automation/bulk-runs/missing/results"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("%s: expected 404, got %d", path, w.Code)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/scheduler"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// calendarDefaultDays 未指定结束时间时日历覆盖的天数
+	calendarDefaultDays = 14
+	// calendarMaxDays 日历最多覆盖的天数
+	calendarMaxDays = 90
+	// calendarRunsPerTask 每个任务最多列出的计划执行次数，避免高频任务撑大日历
+	calendarRunsPerTask = 500
+)
+
+// scheduledRunsResponse 计划执行日历
+type scheduledRunsResponse struct {
+	From      time.Time             `json:"from"`
+	To        time.Time             `json:"to"`
+	Truncated bool                  `json:"truncated"` // 有任务的执行次数超过上限，只列出了前面的部分
+	Runs      []models.ScheduledRun `json:"runs"`
+}
+
+// calendarRange 解析日历的时间范围：from、to 为 RFC 3339 时间，也可以用 days 指定天数
+func calendarRange(c *gin.Context) (time.Time, time.Time, error) {
+	from := time.Now()
+	if value := c.Query("from"); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from: %w", err)
+		}
+		from = t
+	}
+	// 按服务器本地时区计算，与调度器解析 cron 表达式的时区一致
+	from = from.In(time.Local)
+
+	to := from.AddDate(0, 0, calendarDefaultDays)
+	if value := c.Query("days"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 1 || days > calendarMaxDays {
+			return time.Time{}, time.Time{}, fmt.Errorf("days must be between 1 and %d", calendarMaxDays)
+		}
+		to = from.AddDate(0, 0, days)
+	}
+	if value := c.Query("to"); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to: %w", err)
+		}
+		to = t.In(time.Local)
+	}
+
+	if !to.After(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("to must be after from")
+	}
+	if to.After(from.AddDate(0, 0, calendarMaxDays)) {
+		return time.Time{}, time.Time{}, fmt.Errorf("the range must not exceed %d days", calendarMaxDays)
+	}
+	return from, to, nil
+}
+
+// ListScheduledRuns 以 JSON 返回时间范围内已启用定时任务的计划执行
+func (h *Handler) ListScheduledRuns(c *gin.Context) {
+	from, to, err := calendarRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams", "detail": err.Error()})
+		return
+	}
+	runs, truncated, err := scheduler.Calendar(h.db, c.Query("task_id"), from, to, calendarRunsPerTask)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.getTaskListFailed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": scheduledRunsResponse{From: from, To: to, Truncated: truncated, Runs: runs}})
+}
+
+// ScheduledRunsICal 以 iCalendar 格式返回计划执行，供日历应用订阅
+// 日历应用无法设置请求头，认证开启时通过 key 查询参数传递 API Key
+func (h *Handler) ScheduledRunsICal(c *gin.Context) {
+	from, to, err := calendarRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams", "detail": err.Error()})
+		return
+	}
+	runs, _, err := scheduler.Calendar(h.db, c.Query("task_id"), from, to, calendarRunsPerTask)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.getTaskListFailed"})
+		return
+	}
+	c.Header("Content-Disposition", `inline; filename="browserwing-scheduled-runs.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", buildICal(runs, time.Now()))
+}
+
+// buildICal 生成 iCalendar（RFC 5545）文档，每次计划执行对应一个 VEVENT
+func buildICal(runs []models.ScheduledRun, now time.Time) []byte {
+	var b strings.Builder
+	writeICalLine(&b, "BEGIN:VCALENDAR")
+	writeICalLine(&b, "VERSION:2.0")
+	writeICalLine(&b, "PRODID:-//BrowserWing//Scheduled Runs//EN")
+	writeICalLine(&b, "CALSCALE:GREGORIAN")
+	writeICalLine(&b, "METHOD:PUBLISH")
+	writeICalLine(&b, "X-WR-CALNAME:BrowserWing scheduled runs")
+	writeICalLine(&b, "X-PUBLISHED-TTL:PT1H")
+
+	stamp := icalTime(now)
+	for _, run := range runs {
+		description := []string{
+			"Type: " + string(run.ExecutionType),
+			"Schedule: " + string(run.ScheduleType) + " " + run.Schedule,
+		}
+		if run.ScriptName != "" {
+			description = append(description, "Script: "+run.ScriptName)
+		}
+		if run.Description != "" {
+			description = append(description, "", run.Description)
+		}
+
+		writeICalLine(&b, "BEGIN:VEVENT")
+		writeICalLine(&b, fmt.Sprintf("UID:%s-%d@browserwing", run.TaskID, run.StartTime.Unix()))
+		writeICalLine(&b, "DTSTAMP:"+stamp)
+		writeICalLine(&b, "DTSTART:"+icalTime(run.StartTime))
+		writeICalLine(&b, "DTEND:"+icalTime(run.EndTime))
+		writeICalLine(&b, "SUMMARY:"+icalText(run.TaskName))
+		writeICalLine(&b, "DESCRIPTION:"+icalText(strings.Join(description, "\n")))
+		writeICalLine(&b, "CATEGORIES:"+icalText(string(run.ExecutionType)))
+		writeICalLine(&b, "TRANSP:TRANSPARENT")
+		writeICalLine(&b, "END:VEVENT")
+	}
+	writeICalLine(&b, "END:VCALENDAR")
+	return []byte(b.String())
+}
+
+// icalTime UTC 格式的 iCalendar 时间
+func icalTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// icalText 转义 iCalendar 文本值中的特殊字符
+func icalText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(s)
+}
+
+// writeICalLine 写入一行内容，超过 75 字节时折行（续行以空格开头），不拆分 UTF-8 字符
+func writeICalLine(b *strings.Builder, line string) {
+	const maxLineBytes = 75
+	width := 0
+	limit := maxLineBytes
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			width = 0
+			limit = maxLineBytes - 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+}
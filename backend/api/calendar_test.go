@@ -0,0 +1,121 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/browserwing/browserwing/config"
+	"github.com/browserwing/browserwing/mcp"
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/services/browser"
+	"github.com/browserwing/browserwing/storage"
+)
+
+func TestBuildICal(t *testing.T) {
+	start := time.Date(2026, 3, 4, 9, 30, 0, 0, time.UTC)
+	ical := string(buildICal([]models.ScheduledRun{{
+		TaskID:        "t1",
+		TaskName:      "Price watch; shop, daily",
+		Description:   strings.Repeat("监控价格变化", 10),
+		ExecutionType: models.ExecutionTypeMonitor,
+		ScheduleType:  models.ScheduleTypeCron,
+		Schedule:      "0 30 9 * * *",
+		StartTime:     start,
+		EndTime:       start.Add(2 * time.Minute),
+	}}, start.Add(-time.Hour)))
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:t1-1772616600@browserwing\r\n",
+		"DTSTART:20260304T093000Z\r\n",
+		"DTEND:20260304T093200Z\r\n",
+		`SUMMARY:Price watch\; shop\, daily` + "\r\n",
+		`DESCRIPTION:Type: monitor\nSchedule: cron 0 30 9 * * *\n\n`,
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ical, want) {
+			t.Errorf("ical missing %q:\n%s", want, ical)
+		}
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(ical, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line longer than 75 bytes: %q", line)
+		}
+	}
+	// 折行后去掉续行前缀可以还原原始内容
+	if unfolded := strings.ReplaceAll(ical, "\r\n ", ""); !strings.Contains(unfolded, strings.Repeat("监控价格变化", 10)) {
+		t.Errorf("folded description cannot be unfolded:\n%s", ical)
+	}
+}
+
+func TestCalendarEndpoints(t *testing.T) {
+	db, err := storage.NewBoltDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	for _, task := range []*models.ScheduledTask{
+		{ID: "t1", Name: "hourly", Enabled: true, ScheduleType: models.ScheduleTypeCron, ScheduleConfig: "@hourly", ExecutionType: models.ExecutionTypeScript},
+		{ID: "t2", Name: "disabled", Enabled: false, ScheduleType: models.ScheduleTypeCron, ScheduleConfig: "@hourly", ExecutionType: models.ExecutionTypeScript},
+	} {
+		if err := db.CreateScheduledTask(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.CreateApiKey(&models.ApiKey{ID: "k1", Name: "calendar", Key: "secret", UserID: "u1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{Auth: &config.AuthConfig{Enabled: true, AppKey: "test"}}
+	browserMgr := browser.NewManager(cfg, db, nil)
+	handler := NewHandler(db, browserMgr, cfg, nil)
+	handler.SetMCPServer(mcp.NewMCPServer(db, browserMgr))
+	r := SetupRouter(handler, nil, nil, false, false)
+
+	for _, tc := range []struct {
+		name, path string
+		header     bool
+		code       int
+		want       string
+	}{
+		{"json with header key", "/api/v1/calendar/runs?from=2026-03-04T10:00:00Z&days=1", true, http.StatusOK, `"task_name":"hourly"`},
+		{"ics with query key", "/api/v1/calendar/runs.ics?key=secret&from=2026-03-04T10:00:00Z&days=1", false, http.StatusOK, "SUMMARY:hourly"},
+		{"json ignores query key", "/api/v1/calendar/runs?key=secret", false, http.StatusUnauthorized, ""},
+		{"ics without key", "/api/v1/calendar/runs.ics", false, http.StatusUnauthorized, ""},
+		{"range too long", "/api/v1/calendar/runs?days=365", true, http.StatusBadRequest, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.header {
+				req.Header.Set("X-BrowserWing-Key", "secret")
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tc.code {
+				t.Fatalf("expected %d, got %d: %s", tc.code, w.Code, w.Body)
+			}
+			if !strings.Contains(w.Body.String(), tc.want) {
+				t.Errorf("response missing %q: %s", tc.want, w.Body)
+			}
+			if tc.want != "" && strings.Contains(w.Body.String(), "disabled") {
+				t.Errorf("disabled task should not be listed: %s", w.Body)
+			}
+		})
+	}
+
+	// 24 小时内每小时一次
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/calendar/runs.ics?key=secret&from=2026-03-04T10:00:00Z&days=1", nil)
+	r.ServeHTTP(w, req)
+	if n := strings.Count(w.Body.String(), "BEGIN:VEVENT"); n != 24 {
+		t.Errorf("expected 24 events, got %d", n)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/calendar; charset=utf-8" {
+		t.Errorf("unexpected content type %q", ct)
+	}
+}
@@ -0,0 +1,317 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/gin-gonic/gin"
+)
+
+// maxDataChanges 对比结果中最多列出的抓取数据差异
+const maxDataChanges = 200
+
+// CompareScriptExecutions 对比同一脚本的两次执行（步骤结果、耗时、抓取数据和截图）；
+// 未指定 base 时与该脚本在目标执行之前最近一次成功的执行对比
+func (h *Handler) CompareScriptExecutions(c *gin.Context) {
+	target, err := h.db.GetScriptExecution(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.executionRecordNotFound"})
+		return
+	}
+
+	var base *models.ScriptExecution
+	if baseID := c.Query("base"); baseID != "" {
+		if base, err = h.db.GetScriptExecution(baseID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "error.executionRecordNotFound"})
+			return
+		}
+		if base.ScriptID != target.ScriptID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.executionsFromDifferentScripts"})
+			return
+		}
+	} else {
+		recent, err := h.db.RecentScriptExecutions(target.ScriptID, 0)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error.compareExecutionsFailed", "detail": err.Error()})
+			return
+		}
+		for _, execution := range recent {
+			if execution.ID != target.ID && execution.Success && execution.StartTime.Before(target.StartTime) {
+				base = execution
+				break
+			}
+		}
+		if base == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "error.noBaseExecution", "detail": "no earlier successful execution of this script"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": compareExecutions(base, target)})
+}
+
+// compareExecutions 计算两次执行的差异
+func compareExecutions(base, target *models.ScriptExecution) *models.ExecutionComparison {
+	result := &models.ExecutionComparison{
+		ScriptID: target.ScriptID,
+		Base:     executionBrief(base),
+		Target:   executionBrief(target),
+		Summary:  []string{},
+	}
+	summarize := func(format string, args ...interface{}) {
+		result.Summary = append(result.Summary, fmt.Sprintf(format, args...))
+	}
+
+	if base.Success != target.Success {
+		if target.Success {
+			summarize("result: failed → succeeded")
+		} else {
+			summarize("result: succeeded → failed: %s", target.ErrorMsg)
+		}
+	}
+	// 耗时变化至少 1 秒且超过一半时才列出
+	if diff := target.Duration - base.Duration; base.Duration > 0 && abs64(diff) >= 1000 && abs64(diff)*2 >= base.Duration {
+		summarize("duration: %s → %s", formatMillis(base.Duration), formatMillis(target.Duration))
+	}
+
+	result.Steps = compareSteps(base.Steps, target.Steps)
+	for _, step := range result.Steps {
+		if !step.Changed {
+			continue
+		}
+		line := fmt.Sprintf("step %d (%s): %s → %s", step.Index, step.Type, stepStatusText(step.BaseStatus), stepStatusText(step.TargetStatus))
+		if step.TargetError != "" {
+			line += ": " + step.TargetError
+		}
+		summarize("%s", line)
+	}
+
+	if result.Base.ItemCount != nil && result.Target.ItemCount != nil && *result.Base.ItemCount != *result.Target.ItemCount {
+		summarize("item count: %d → %d", *result.Base.ItemCount, *result.Target.ItemCount)
+	}
+
+	baseData, baseShots := splitScreenshots(base.ExtractedData)
+	targetData, targetShots := splitScreenshots(target.ExtractedData)
+	diff := &dataDiff{changes: []models.DataChange{}, counts: map[string]int{}}
+	diff.compare("", baseData, targetData)
+	result.Data = diff.changes
+	result.DataTruncated = diff.total > len(diff.changes)
+	if diff.total > 0 {
+		summarize("extracted data: %d changed, %d added, %d removed",
+			diff.counts[models.DataChanged], diff.counts[models.DataAdded], diff.counts[models.DataRemoved])
+	}
+
+	result.Screenshots = compareScreenshots(baseShots, targetShots)
+	for _, shot := range result.Screenshots {
+		if shot.Identical != nil && !*shot.Identical {
+			summarize("screenshot %s changed", shot.Variable)
+		}
+	}
+	return result
+}
+
+// executionBrief 执行概况
+func executionBrief(execution *models.ScriptExecution) models.ExecutionBrief {
+	brief := models.ExecutionBrief{
+		ID:           execution.ID,
+		StartTime:    execution.StartTime,
+		Success:      execution.Success,
+		ErrorMsg:     execution.ErrorMsg,
+		Duration:     execution.Duration,
+		SuccessSteps: execution.SuccessSteps,
+		FailedSteps:  execution.FailedSteps,
+	}
+	if execution.Metrics != nil {
+		count := execution.Metrics.ItemCount
+		brief.ItemCount = &count
+	}
+	return brief
+}
+
+// compareSteps 按步骤序号对齐两次执行的步骤结果
+func compareSteps(base, target []models.StepOutcome) []models.StepComparison {
+	byIndex := make(map[int]*models.StepComparison)
+	steps := []models.StepComparison{}
+	get := func(index int) *models.StepComparison {
+		if step, ok := byIndex[index]; ok {
+			return step
+		}
+		step := &models.StepComparison{Index: index}
+		byIndex[index] = step
+		return step
+	}
+	for _, outcome := range base {
+		step := get(outcome.Index)
+		step.Type = outcome.Type
+		step.BaseStatus = outcome.Status
+		step.BaseDuration = outcome.Duration
+	}
+	baseTypes := make(map[int]string, len(byIndex))
+	for index, step := range byIndex {
+		baseTypes[index] = step.Type
+	}
+	for _, outcome := range target {
+		step := get(outcome.Index)
+		step.Type = outcome.Type
+		step.TargetStatus = outcome.Status
+		step.TargetDuration = outcome.Duration
+		step.TargetError = outcome.Error
+	}
+
+	for index, step := range byIndex {
+		baseType, inBase := baseTypes[index]
+		step.Changed = step.BaseStatus != step.TargetStatus || (inBase && step.TargetStatus != "" && baseType != step.Type)
+		steps = append(steps, *step)
+	}
+	sort.Slice(steps, func(i, j int) bool { return steps[i].Index < steps[j].Index })
+	return steps
+}
+
+// stepStatusText 步骤状态的说明，没有执行的步骤为 not run
+func stepStatusText(status string) string {
+	if status == "" {
+		return "not run"
+	}
+	return status
+}
+
+// dataDiff 逐个值比较抓取数据，记录前 maxDataChanges 个差异和各类差异的总数
+type dataDiff struct {
+	changes []models.DataChange
+	counts  map[string]int
+	total   int
+}
+
+func (d *dataDiff) add(path, change string, base, target interface{}) {
+	d.total++
+	d.counts[change]++
+	if len(d.changes) < maxDataChanges {
+		d.changes = append(d.changes, models.DataChange{Path: path, Change: change, Base: base, Target: target})
+	}
+}
+
+// compare 递归比较：对象按字段、数组按下标比较，其他值整体比较
+func (d *dataDiff) compare(path string, base, target interface{}) {
+	baseObj, baseIsObj := base.(map[string]interface{})
+	targetObj, targetIsObj := target.(map[string]interface{})
+	if baseIsObj && targetIsObj {
+		keys := make([]string, 0, len(baseObj)+len(targetObj))
+		for key := range baseObj {
+			keys = append(keys, key)
+		}
+		for key := range targetObj {
+			if _, ok := baseObj[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child := key
+			if path != "" {
+				child = path + "." + key
+			}
+			baseValue, inBase := baseObj[key]
+			targetValue, inTarget := targetObj[key]
+			switch {
+			case !inBase:
+				d.add(child, models.DataAdded, nil, targetValue)
+			case !inTarget:
+				d.add(child, models.DataRemoved, baseValue, nil)
+			default:
+				d.compare(child, baseValue, targetValue)
+			}
+		}
+		return
+	}
+
+	baseList, baseIsList := base.([]interface{})
+	targetList, targetIsList := target.([]interface{})
+	if baseIsList && targetIsList {
+		for i := 0; i < len(baseList) || i < len(targetList); i++ {
+			child := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(baseList):
+				d.add(child, models.DataAdded, nil, targetList[i])
+			case i >= len(targetList):
+				d.add(child, models.DataRemoved, baseList[i], nil)
+			default:
+				d.compare(child, baseList[i], targetList[i])
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(base, target) {
+		d.add(path, models.DataChanged, base, target)
+	}
+}
+
+// splitScreenshots 把抓取数据转换为通用的 JSON 值，并分出截图步骤保存的截图（变量名到文件路径）
+func splitScreenshots(extracted map[string]interface{}) (map[string]interface{}, map[string]string) {
+	data := map[string]interface{}{}
+	if raw, err := json.Marshal(extracted); err == nil {
+		_ = json.Unmarshal(raw, &data)
+	}
+	shots := map[string]string{}
+	for name, value := range data {
+		if obj, ok := value.(map[string]interface{}); ok {
+			path, hasPath := obj["path"].(string)
+			if _, hasName := obj["fileName"]; hasPath && hasName && obj["format"] != nil {
+				shots[name] = path
+				delete(data, name)
+			}
+		}
+	}
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	return data, shots
+}
+
+// compareScreenshots 按变量名对齐两次执行的截图，两个文件都还在时比较内容
+func compareScreenshots(base, target map[string]string) []models.ScreenshotComparison {
+	names := make([]string, 0, len(base)+len(target))
+	for name := range base {
+		names = append(names, name)
+	}
+	for name := range target {
+		if _, ok := base[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	shots := make([]models.ScreenshotComparison, 0, len(names))
+	for _, name := range names {
+		shot := models.ScreenshotComparison{Variable: name, BasePath: base[name], TargetPath: target[name]}
+		if shot.BasePath != "" && shot.TargetPath != "" {
+			baseFile, baseErr := os.ReadFile(shot.BasePath)
+			targetFile, targetErr := os.ReadFile(shot.TargetPath)
+			if baseErr == nil && targetErr == nil {
+				identical := bytes.Equal(baseFile, targetFile)
+				shot.Identical = &identical
+			}
+		}
+		shots = append(shots, shot)
+	}
+	return shots
+}
+
+// formatMillis 把毫秒数格式化为便于阅读的时长，如 1.5s
+func formatMillis(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).String()
+}
+
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/browserwing/browserwing/config"
+	"github.com/browserwing/browserwing/mcp"
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/services/browser"
+	"github.com/browserwing/browserwing/storage"
+)
+
+func TestCompareExecutions(t *testing.T) {
+	dir := t.TempDir()
+	shot := func(name, content string) map[string]interface{} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return map[string]interface{}{"path": path, "fileName": name, "format": "png", "size": len(content)}
+	}
+
+	base := &models.ScriptExecution{
+		ID: "s1-1", ScriptID: "s1", Success: true, Duration: 2000,
+		Steps: []models.StepOutcome{
+			{Index: 1, Type: "navigate", Status: models.StepStatusSuccess, Duration: 800},
+			{Index: 2, Type: "click", Status: models.StepStatusSuccess, Duration: 100},
+			{Index: 3, Type: "execute_js", Status: models.StepStatusSuccess, Duration: 50},
+		},
+		ExtractedData: map[string]interface{}{
+			"items": []interface{}{map[string]interface{}{"title": "a", "price": 5}, map[string]interface{}{"title": "b", "price": 7}},
+			"page":  "1",
+			"shot":  shot("base.png", "old"),
+		},
+		Metrics: &models.ExtractionMetrics{ItemCount: 3},
+	}
+	target := &models.ScriptExecution{
+		ID: "s1-2", ScriptID: "s1", Success: false, ErrorMsg: "all operations failed", Duration: 9000,
+		Steps: []models.StepOutcome{
+			{Index: 1, Type: "navigate", Status: models.StepStatusSuccess, Duration: 900},
+			{Index: 2, Type: "click", Status: models.StepStatusFailed, Error: "element not found", Duration: 8000},
+		},
+		ExtractedData: map[string]interface{}{
+			"items": []interface{}{map[string]interface{}{"title": "a", "price": 6}},
+			"total": 1,
+			"shot":  shot("target.png", "new"),
+		},
+		Metrics: &models.ExtractionMetrics{ItemCount: 2},
+	}
+
+	result := compareExecutions(base, target)
+	wantSummary := []string{
+		"result: succeeded → failed: all operations failed",
+		"duration: 2s → 9s",
+		"step 2 (click): success → failed: element not found",
+		"step 3 (execute_js): success → not run",
+		"item count: 3 → 2",
+		"extracted data: 1 changed, 1 added, 2 removed",
+		"screenshot shot changed",
+	}
+	if !reflect.DeepEqual(result.Summary, wantSummary) {
+		t.Errorf("unexpected summary:\n%q", result.Summary)
+	}
+	if len(result.Steps) != 3 || result.Steps[0].Changed || !result.Steps[1].Changed || result.Steps[2].TargetStatus != "" {
+		t.Errorf("unexpected steps %+v", result.Steps)
+	}
+	wantData := []models.DataChange{
+		{Path: "items[0].price", Change: models.DataChanged, Base: float64(5), Target: float64(6)},
+		{Path: "items[1]", Change: models.DataRemoved, Base: map[string]interface{}{"title": "b", "price": float64(7)}},
+		{Path: "page", Change: models.DataRemoved, Base: "1"},
+		{Path: "total", Change: models.DataAdded, Target: float64(1)},
+	}
+	if !reflect.DeepEqual(result.Data, wantData) {
+		t.Errorf("unexpected data changes %+v", result.Data)
+	}
+	if len(result.Screenshots) != 1 || result.Screenshots[0].Identical == nil || *result.Screenshots[0].Identical {
+		t.Errorf("unexpected screenshots %+v", result.Screenshots)
+	}
+
+	if same := compareExecutions(base, base); len(same.Summary) != 0 || len(same.Data) != 0 {
+		t.Errorf("an execution compared with itself should have no changes: %+v", same)
+	}
+}
+
+func TestCompareExecutionsEndpoint(t *testing.T) {
+	db, err := storage.NewBoltDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	start := time.Now().Add(-time.Hour)
+	for i, execution := range []*models.ScriptExecution{
+		{ID: "s1-1", ScriptID: "s1", Success: true},
+		{ID: "s1-2", ScriptID: "s1", Success: true},
+		{ID: "s1-3", ScriptID: "s1", Success: false},
+		{ID: "s2-1", ScriptID: "s2", Success: true},
+	} {
+		execution.StartTime = start.Add(time.Duration(i) * time.Minute)
+		if err := db.SaveScriptExecution(execution); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{Auth: &config.AuthConfig{}}
+	logger.InitLogger(&logger.LoggerConfig{Level: "error"})
+	browserMgr := browser.NewManager(cfg, db, nil)
+	handler := NewHandler(db, browserMgr, cfg, nil)
+	handler.SetMCPServer(mcp.NewMCPServer(db, browserMgr))
+	r := SetupRouter(handler, nil, nil, false, false)
+
+	for _, tc := range []struct {
+		path     string
+		code     int
+		wantBase string
+	}{
+		{"/api/v1/script-executions/s1-3/compare", http.StatusOK, "s1-2"},
+		{"/api/v1/script-executions/s1-3/compare?base=s1-1", http.StatusOK, "s1-1"},
+		{"/api/v1/script-executions/s1-3/compare?base=s2-1", http.StatusBadRequest, ""},
+		{"/api/v1/script-executions/s1-1/compare", http.StatusNotFound, ""},
+		{"/api/v1/script-executions/missing/compare", http.StatusNotFound, ""},
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if w.Code != tc.code {
+			t.Errorf("%s: expected %d, got %d: %s", tc.path, tc.code, w.Code, w.Body)
+			continue
+		}
+		if tc.wantBase == "" {
+			continue
+		}
+		var resp struct {
+			Data models.ExecutionComparison `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Data.Base.ID != tc.wantBase {
+			t.Errorf("%s: expected base %s, got %+v (%v)", tc.path, tc.wantBase, resp.Data.Base, err)
+		}
+	}
+}
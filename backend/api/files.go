@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/config"
+	"github.com/browserwing/browserwing/pkg/artifacts"
+	"github.com/browserwing/browserwing/storage"
+	"github.com/gin-gonic/gin"
+)
+
+// authEnabled 是否开启了认证
+func authEnabled(cfg *config.Config) bool {
+	return cfg != nil && cfg.Auth != nil && cfg.Auth.Enabled
+}
+
+// filePathParam 取出 /files/*filepath 中的产物路径（与执行记录中保存的路径一致）
+func filePathParam(c *gin.Context) string {
+	return strings.TrimPrefix(c.Param("filepath"), "/")
+}
+
+// fileURL 返回产物文件的访问地址；开启认证时附带有效期内的签名，
+// 前端的 <img> 等标签无法携带认证请求头，凭签名访问
+func (h *Handler) fileURL(path string) string {
+	url := "/files/" + path
+	if !authEnabled(h.config) {
+		return url
+	}
+	expires := time.Now().Add(h.config.Storage.SignedURLTTL())
+	return url + "?" + artifacts.SignedQuery(h.config.Auth.AppKey, path, expires)
+}
+
+// SignedFileMiddleware /files/ 下产物文件的访问控制：开启认证时需要有效的签名，
+// 没有签名或签名无效、过期时按 JWT 或 ApiKey 认证
+func SignedFileMiddleware(cfg *config.Config, db *storage.BoltDB) gin.HandlerFunc {
+	fallback := JWTOrApiKeyAuthenticationMiddleware(cfg, db)
+	return func(c *gin.Context) {
+		if !authEnabled(cfg) {
+			c.Next()
+			return
+		}
+		if artifacts.VerifySignedPath(cfg.Auth.AppKey, filePathParam(c), c.Query("expires"), c.Query("sig"), time.Now()) {
+			c.Next()
+			return
+		}
+		fallback(c)
+	}
+}
+
+// ServeFile 提供执行录像等产物文件，只允许读取录制输出目录中的文件（路径与执行记录中保存的相同）
+func (h *Handler) ServeFile(c *gin.Context) {
+	sandbox, err := artifacts.NewSandbox(h.db.GetDefaultRecordingConfig().OutputDir)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.fileNotFound"})
+		return
+	}
+	path, err := filepath.Abs(filepath.FromSlash(filePathParam(c)))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.fileNotFound"})
+		return
+	}
+	full, err := sandbox.Contains(path)
+	if err == nil {
+		// 不提供目录列表
+		if info, statErr := os.Stat(full); statErr != nil || info.IsDir() {
+			err = os.ErrNotExist
+		}
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.fileNotFound"})
+		return
+	}
+	c.File(full)
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/browserwing/browserwing/config"
+	"github.com/browserwing/browserwing/mcp"
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/services/browser"
+	"github.com/browserwing/browserwing/storage"
+)
+
+func TestServeSignedFiles(t *testing.T) {
+	dir := t.TempDir()
+	db, err := storage.NewBoltDB(filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	recordings := filepath.Join(dir, "recordings")
+	video := filepath.Join(recordings, "run.gif")
+	outside := filepath.Join(dir, "secret.txt")
+	if err := os.MkdirAll(recordings, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{video, filepath.Join(recordings, "other.gif"), outside} {
+		if err := os.WriteFile(path, []byte("GIF89a"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	recordingConfig := models.GetDefaultRecordingConfig()
+	recordingConfig.OutputDir = recordings
+	if err := db.SaveRecordingConfig(recordingConfig); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.CreateApiKey(&models.ApiKey{ID: "k1", Name: "files", Key: "secret", UserID: "u1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{Auth: &config.AuthConfig{Enabled: true, AppKey: "test"}}
+	logger.InitLogger(&logger.LoggerConfig{Level: "error"})
+	browserMgr := browser.NewManager(cfg, db, nil)
+	handler := NewHandler(db, browserMgr, cfg, nil)
+	handler.SetMCPServer(mcp.NewMCPServer(db, browserMgr))
+	r := SetupRouter(handler, nil, nil, false, false)
+
+	signed := handler.fileURL(video)
+	query := signed[strings.Index(signed, "?"):]
+	for _, tc := range []struct {
+		name, path string
+		header     bool
+		code       int
+	}{
+		{"signed url", signed, false, http.StatusOK},
+		{"without signature", "/files/" + video, false, http.StatusUnauthorized},
+		{"api key header", "/files/" + video, true, http.StatusOK},
+		{"signature of another file", "/files/" + filepath.Join(recordings, "other.gif") + query, false, http.StatusUnauthorized},
+		{"outside recordings", handler.fileURL(outside), false, http.StatusNotFound},
+		{"directory listing", handler.fileURL(recordings), false, http.StatusNotFound},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.header {
+				req.Header.Set("X-BrowserWing-Key", "secret")
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tc.code {
+				t.Fatalf("expected %d, got %d: %s", tc.code, w.Code, w.Body)
+			}
+		})
+	}
+}
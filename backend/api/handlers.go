@@ -3,8 +3,12 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -19,11 +23,14 @@ import (
 	"github.com/browserwing/browserwing/llm"
 	"github.com/browserwing/browserwing/models"
 	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/scheduler"
 	"github.com/browserwing/browserwing/services/browser"
+	"github.com/browserwing/browserwing/services/notify"
 	"github.com/browserwing/browserwing/storage"
 	"github.com/gin-gonic/gin"
 	"github.com/go-rod/rod/lib/proto"
 	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
 	"github.com/mark3labs/mcp-go/server"
 )
 
@@ -42,9 +49,11 @@ type Handler struct {
 	executor       *executor2.Executor // Executor 实例
 	config         *config.Config
 	llmManager     *llm.Manager
-	mcpServer      MCPHTTPHandler // MCP 服务器（使用 interface{} 避免循环依赖）
-	agentManager   interface{}    // Agent 管理器（用于 LLM 配置更新后的热加载）
-	scheduler      interface{}    // 定时任务调度器
+	mcpServer      MCPHTTPHandler      // MCP 服务器（使用 interface{} 避免循环依赖）
+	agentManager   interface{}         // Agent 管理器（用于 LLM 配置更新后的热加载）
+	scheduler      interface{}         // 定时任务调度器
+	automationRuns *automationRunStore // 自动化触发接口发起的执行
+	bulkRuns       *bulkRunStore       // 批量执行
 }
 
 func NewHandler(
@@ -60,6 +69,8 @@ func NewHandler(
 		config:         cfg,
 		llmManager:     llmMgr,
 		mcpServer:      nil, // 将在主程序中设置
+		automationRuns: newAutomationRunStore(),
+		bulkRuns:       newBulkRunStore(),
 	}
 }
 
@@ -414,7 +425,8 @@ func (h *Handler) BatchDeleteCookies(c *gin.Context) {
 // StartRecording 开始录制操作
 func (h *Handler) StartRecording(c *gin.Context) {
 	var req struct {
-		InstanceID string `json:"instance_id"` // 指定实例ID，空字符串表示使用当前实例
+		InstanceID     string `json:"instance_id"`     // 指定实例ID，空字符串表示使用当前实例
+		RecordRequests bool   `json:"record_requests"` // 记录每个步骤触发的 XHR/Fetch 请求，回放时验证
 	}
 	// 尝试解析请求体，如果失败或为空则使用默认值
 	_ = c.ShouldBindJSON(&req)
@@ -424,7 +436,7 @@ func (h *Handler) StartRecording(c *gin.Context) {
 		return
 	}
 
-	if err := h.browserManager.StartRecording(c.Request.Context(), req.InstanceID); err != nil {
+	if err := h.browserManager.StartRecording(c.Request.Context(), req.InstanceID, req.RecordRequests); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.startRecordingFailed"})
 		return
 	}
@@ -475,6 +487,9 @@ func (h *Handler) SaveScript(c *gin.Context) {
 		MCPCommandDescription string                  `json:"mcp_command_description"`
 		MCPInputSchema        map[string]interface{}  `json:"mcp_input_schema"`
 		Variables             map[string]string       `json:"variables"`
+
+		// 参数表单的展示和校验设置
+		ParamUISchema map[string]models.ParamUIField `json:"param_ui_schema"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -505,6 +520,7 @@ func (h *Handler) SaveScript(c *gin.Context) {
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
 		Variables:       req.Variables,
+		ParamUISchema:   req.ParamUISchema,
 	}
 
 	// 如果提供了 MCP 相关字段，则设置
@@ -530,8 +546,9 @@ func (h *Handler) SaveScript(c *gin.Context) {
 	h.syncMCPRegistration(c, script)
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "success.scriptSaved",
-		"script":  script,
+		"message":  "success.scriptSaved",
+		"script":   script,
+		"warnings": browser.LintScript(script),
 	})
 }
 
@@ -622,6 +639,35 @@ func (h *Handler) GetScript(c *gin.Context) {
 	c.JSON(http.StatusOK, script)
 }
 
+// updateScriptRequest 更新脚本的请求，指针和 nil 字段表示不修改
+type updateScriptRequest struct {
+	Name                  string                     `json:"name"`
+	Description           string                     `json:"description"`
+	URL                   string                     `json:"url"`
+	Actions               []models.ScriptAction      `json:"actions"`
+	Tags                  []string                   `json:"tags"`
+	IsMCPCommand          *bool                      `json:"is_mcp_command"`
+	MCPCommandName        *string                    `json:"mcp_command_name"`
+	MCPCommandDescription *string                    `json:"mcp_command_description"`
+	MCPInputSchema        map[string]interface{}     `json:"mcp_input_schema"`
+	Variables             map[string]string          `json:"variables"`
+	Incognito             *bool                      `json:"incognito"`
+	Headers               map[string]string          `json:"headers"`
+	UserAgent             *string                    `json:"user_agent"`
+	Performance           *models.PerformanceOptions `json:"performance"`
+	Environment           *string                    `json:"environment"`
+	RunTags               []string                   `json:"run_tags"`
+
+	// 参数表单的展示和校验设置
+	ParamUISchema map[string]models.ParamUIField `json:"param_ui_schema"`
+	// 抓取数据的后处理，expression 为空时移除
+	Transform *models.DataTransform `json:"transform"`
+	// 抓取数据应符合的 JSON Schema，为空对象时移除
+	OutputSchema map[string]interface{} `json:"output_schema"`
+	// 回放的超时设置，各项都不大于 0 时移除
+	Timeouts *models.ScriptTimeouts `json:"timeouts"`
+}
+
 // UpdateScript 更新脚本
 func (h *Handler) UpdateScript(c *gin.Context) {
 	id := c.Param("id")
@@ -633,18 +679,7 @@ func (h *Handler) UpdateScript(c *gin.Context) {
 		return
 	}
 
-	var req struct {
-		Name                  string                 `json:"name"`
-		Description           string                 `json:"description"`
-		URL                   string                 `json:"url"`
-		Actions               []models.ScriptAction  `json:"actions"`
-		Tags                  []string               `json:"tags"`
-		IsMCPCommand          *bool                  `json:"is_mcp_command"`
-		MCPCommandName        *string                `json:"mcp_command_name"`
-		MCPCommandDescription *string                `json:"mcp_command_description"`
-		MCPInputSchema        map[string]interface{} `json:"mcp_input_schema"`
-		Variables             map[string]string      `json:"variables"`
-	}
+	var req updateScriptRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
@@ -671,9 +706,48 @@ func (h *Handler) UpdateScript(c *gin.Context) {
 	if req.Variables != nil {
 		script.Variables = req.Variables
 	}
+	if req.ParamUISchema != nil {
+		script.ParamUISchema = req.ParamUISchema
+	}
 	if req.Tags != nil {
 		script.Tags = req.Tags
 	}
+	if req.Incognito != nil {
+		script.Incognito = *req.Incognito
+	}
+	if req.Headers != nil {
+		script.Headers = req.Headers
+	}
+	if req.UserAgent != nil {
+		script.UserAgent = *req.UserAgent
+	}
+	if req.Performance != nil {
+		script.Performance = req.Performance
+	}
+	if req.Environment != nil {
+		script.Environment = *req.Environment
+	}
+	if req.RunTags != nil {
+		script.RunTags = req.RunTags
+	}
+	if req.Transform != nil {
+		script.Transform = req.Transform
+		if !req.Transform.Enabled() {
+			script.Transform = nil
+		}
+	}
+	if req.OutputSchema != nil {
+		script.OutputSchema = req.OutputSchema
+		if len(req.OutputSchema) == 0 {
+			script.OutputSchema = nil
+		}
+	}
+	if req.Timeouts != nil {
+		script.Timeouts = req.Timeouts
+		if req.Timeouts.ElementSeconds <= 0 && req.Timeouts.NavigationSeconds <= 0 {
+			script.Timeouts = nil
+		}
+	}
 
 	// 如果提供了 MCP 相关字段，则更新（使用指针类型来区分未提供和提供了false）
 	if req.IsMCPCommand != nil {
@@ -698,11 +772,32 @@ func (h *Handler) UpdateScript(c *gin.Context) {
 	h.syncMCPRegistration(c, script)
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "success.scriptUpdated",
-		"script":  script,
+		"message":  "success.scriptUpdated",
+		"script":   script,
+		"warnings": browser.LintScript(script),
 	})
 }
 
+// LintScript 静态检查已保存的脚本
+func (h *Handler) LintScript(c *gin.Context) {
+	script, err := h.db.GetScript(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.scriptNotFound"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": browser.LintScript(script)})
+}
+
+// LintScriptDraft 静态检查未保存的脚本（编辑器中的草稿）
+func (h *Handler) LintScriptDraft(c *gin.Context) {
+	var script models.Script
+	if err := c.ShouldBindJSON(&script); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": browser.LintScript(&script)})
+}
+
 // DeleteScript 删除脚本
 func (h *Handler) DeleteScript(c *gin.Context) {
 	id := c.Param("id")
@@ -717,9 +812,25 @@ func (h *Handler) DeleteScript(c *gin.Context) {
 		return
 	}
 
+	if err := h.db.DeleteScriptHealthReport(id); err != nil {
+		logger.Warn(c.Request.Context(), "Failed to delete health report of script %s: %v", id, err)
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "success.scriptDeleted"})
 }
 
+// playScriptRequest 回放脚本的请求，请求体可以为空
+type playScriptRequest struct {
+	Params     map[string]string `json:"params"`
+	InstanceID string            `json:"instance_id"` // 指定实例ID，空字符串表示使用当前实例
+	Incognito  *bool             `json:"incognito"`   // 是否在无痕上下文中执行，未指定时使用脚本配置
+	// 本次执行的性能采集选项，未指定时使用脚本配置
+	Performance *models.PerformanceOptions `json:"performance"`
+	// 本次执行的环境（执行环境名称或环境标签）和执行标签（匹配步骤的 run_on/skip_on），未指定时使用脚本配置
+	Environment *string  `json:"environment"`
+	RunTags     []string `json:"run_tags"`
+}
+
 // PlayScript 回放脚本
 func (h *Handler) PlayScript(c *gin.Context) {
 	id := c.Param("id")
@@ -729,10 +840,7 @@ func (h *Handler) PlayScript(c *gin.Context) {
 	}
 
 	// 解析请求体中的参数
-	var req struct {
-		Params     map[string]string `json:"params"`
-		InstanceID string            `json:"instance_id"` // 指定实例ID，空字符串表示使用当前实例
-	}
+	var req playScriptRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		// 如果没有请求体或解析失败,使用空参数
 		req.Params = make(map[string]string)
@@ -758,8 +866,64 @@ func (h *Handler) PlayScript(c *gin.Context) {
 		return
 	}
 
+	// 按参数表单设置校验执行参数
+	if err := browser.ValidateScriptParams(script, req.Params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams", "detail": err.Error()})
+		return
+	}
+
+	scriptToRun, err := h.prepareScriptRun(script, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.applyEnvironmentFailed", "detail": err.Error()})
+		return
+	}
+
+	// 执行回放
+	result, page, err := h.browserManager.PlayScript(c.Request.Context(), scriptToRun, instanceID)
+	if err != nil {
+		logger.Error(c.Request.Context(), "Failed to play script: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":  "error.playScriptFailed",
+			"result": result,
+		})
+		return
+	}
+
+	// 关闭页面
+	if err := h.browserManager.CloseActivePage(c.Request.Context(), page); err != nil {
+		logger.Warn(c.Request.Context(), "Failed to close page: %v", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "success.scriptPlaybackCompleted",
+		"script":  script.Name,
+		"result":  result,
+	})
+}
+
+// prepareScriptRun 按执行请求生成待回放的脚本副本：应用执行选项和执行环境，
+// 合并预设变量与外部参数并替换占位符。执行环境应用失败时返回错误
+func (h *Handler) prepareScriptRun(script *models.Script, req playScriptRequest) (*models.Script, error) {
 	// 创建脚本副本并合并参数
 	scriptToRun := script.Copy()
+	if req.Incognito != nil {
+		scriptToRun.Incognito = *req.Incognito
+	}
+	if req.Performance != nil {
+		scriptToRun.Performance = req.Performance
+	}
+	if req.Environment != nil {
+		scriptToRun.Environment = *req.Environment
+		// 同名的执行环境存在时应用其基础 URL 和变量，否则环境名只作为执行标签
+		if env, err := h.db.GetEnvironmentByName(*req.Environment); err == nil {
+			if err := env.Apply(scriptToRun); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if req.RunTags != nil {
+		scriptToRun.RunTags = req.RunTags
+	}
 
 	// 合并参数：先使用脚本预设变量，再用外部传入的参数覆盖
 	mergedParams := make(map[string]string)
@@ -791,14 +955,12 @@ func (h *Handler) PlayScript(c *gin.Context) {
 			scriptToRun.URL = replacePlaceholders(scriptToRun.URL, mergedParams)
 		}
 
-		// 复制 actions 数组以避免修改原始数据
-		scriptToRun.Actions = make([]models.ScriptAction, len(script.Actions))
-		copy(scriptToRun.Actions, script.Actions)
-
 		// 替换所有 action 中的占位符
 		for i := range scriptToRun.Actions {
 			scriptToRun.Actions[i].Selector = replacePlaceholders(scriptToRun.Actions[i].Selector, mergedParams)
 			scriptToRun.Actions[i].XPath = replacePlaceholders(scriptToRun.Actions[i].XPath, mergedParams)
+			scriptToRun.Actions[i].TargetSelector = replacePlaceholders(scriptToRun.Actions[i].TargetSelector, mergedParams)
+			scriptToRun.Actions[i].TargetXPath = replacePlaceholders(scriptToRun.Actions[i].TargetXPath, mergedParams)
 			scriptToRun.Actions[i].Value = replacePlaceholders(scriptToRun.Actions[i].Value, mergedParams)
 			scriptToRun.Actions[i].URL = replacePlaceholders(scriptToRun.Actions[i].URL, mergedParams)
 			scriptToRun.Actions[i].JSCode = replacePlaceholders(scriptToRun.Actions[i].JSCode, mergedParams)
@@ -814,27 +976,7 @@ func (h *Handler) PlayScript(c *gin.Context) {
 		}
 	}
 
-	// 执行回放
-	result, page, err := h.browserManager.PlayScript(c.Request.Context(), scriptToRun, req.InstanceID)
-	if err != nil {
-		logger.Error(c.Request.Context(), "Failed to play script: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.playScriptFailed",
-			"result": result,
-		})
-		return
-	}
-
-	// 关闭页面
-	if err := h.browserManager.CloseActivePage(c.Request.Context(), page); err != nil {
-		logger.Warn(c.Request.Context(), "Failed to close page: %v", err)
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"message": "success.scriptPlaybackCompleted",
-		"script":  script.Name,
-		"result":  result,
-	})
+	return scriptToRun, nil
 }
 
 // GetPlayResult 获取上次脚本回放的抓取数据
@@ -958,6 +1100,7 @@ func (h *Handler) UpdateLLMConfig(c *gin.Context) {
 			}
 		}
 	}
+	h.reloadAgentLLMRouting(c.Request.Context())
 
 	c.JSON(http.StatusOK, req)
 }
@@ -971,10 +1114,54 @@ func (h *Handler) DeleteLLMConfig(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.deleteLLMConfigFailed"})
 		return
 	}
+	h.reloadAgentLLMRouting(c.Request.Context())
 
 	c.JSON(http.StatusOK, gin.H{"message": "success.llmConfigDeleted"})
 }
 
+// GetLLMRouting 获取 LLM 路由策略
+func (h *Handler) GetLLMRouting(c *gin.Context) {
+	policy, err := h.llmManager.RoutingPolicy()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.getLLMRoutingFailed", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"policy": policy,
+		"steps":  models.LLMCheapSteps,
+	})
+}
+
+// UpdateLLMRouting 设置 LLM 路由策略：把简单的步骤交给便宜的小模型
+func (h *Handler) UpdateLLMRouting(c *gin.Context) {
+	var req models.LLMRoutingPolicy
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+		return
+	}
+
+	if err := h.llmManager.SetRoutingPolicy(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidLLMRouting", "detail": err.Error()})
+		return
+	}
+	h.reloadAgentLLMRouting(c.Request.Context())
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "success.llmRoutingSaved",
+		"policy":  req,
+	})
+}
+
+// reloadAgentLLMRouting 通知 Agent 重新加载 LLM 路由策略
+func (h *Handler) reloadAgentLLMRouting(ctx context.Context) {
+	if am, ok := h.agentManager.(interface{ ReloadLLMRouting() error }); ok {
+		if err := am.ReloadLLMRouting(); err != nil {
+			logger.Warn(ctx, "Agent failed to reload LLM routing: %v", err)
+		}
+	}
+}
+
 // TestLLMConfig 测试 LLM 配置连接
 func (h *Handler) TestLLMConfig(c *gin.Context) {
 	var req models.LLMConfigModel
@@ -1067,6 +1254,10 @@ func (h *Handler) ListBrowserConfigs(c *gin.Context) {
 		configs = []models.BrowserConfig{*defaultConfig}
 	}
 
+	for i := range configs {
+		configs[i] = configs[i].Redacted()
+	}
+
 	c.JSON(200, gin.H{
 		"configs": configs,
 		"count":   len(configs),
@@ -1083,7 +1274,7 @@ func (h *Handler) GetBrowserConfig(c *gin.Context) {
 		return
 	}
 
-	c.JSON(200, config)
+	c.JSON(200, config.Redacted())
 }
 
 // CreateBrowserConfig 创建浏览器配置
@@ -1093,9 +1284,22 @@ func (h *Handler) CreateBrowserConfig(c *gin.Context) {
 		c.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
+	if config.FloatButton != nil {
+		if err := config.FloatButton.Validate(); err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if config.Stealth != nil {
+		if err := config.Stealth.Validate(); err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+	}
 
 	// 生成ID
 	config.ID = fmt.Sprintf("config_%d", time.Now().Unix())
+	config.KeepSecretsFrom(nil)
 
 	if err := h.db.SaveBrowserConfig(&config); err != nil {
 		c.JSON(500, gin.H{"error": err.Error()})
@@ -1104,7 +1308,7 @@ func (h *Handler) CreateBrowserConfig(c *gin.Context) {
 
 	c.JSON(200, gin.H{
 		"message": "browser.config.createSuccess",
-		"config":  config,
+		"config":  config.Redacted(),
 	})
 }
 
@@ -1117,8 +1321,25 @@ func (h *Handler) UpdateBrowserConfig(c *gin.Context) {
 		c.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
+	if config.FloatButton != nil {
+		if err := config.FloatButton.Validate(); err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if config.Stealth != nil {
+		if err := config.Stealth.Validate(); err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+	}
 
 	config.ID = id
+	if existing, err := h.db.GetBrowserConfig(id); err == nil {
+		config.KeepSecretsFrom(existing)
+	} else {
+		config.KeepSecretsFrom(nil)
+	}
 
 	if err := h.db.SaveBrowserConfig(&config); err != nil {
 		c.JSON(500, gin.H{"error": err.Error()})
@@ -1127,7 +1348,7 @@ func (h *Handler) UpdateBrowserConfig(c *gin.Context) {
 
 	c.JSON(200, gin.H{
 		"message": "browser.config.updateSuccess",
-		"config":  config,
+		"config":  config.Redacted(),
 	})
 }
 
@@ -1177,7 +1398,7 @@ func (h *Handler) GenerateMCPConfig(c *gin.Context) {
 	actionsJSON := fmt.Sprintf("Script Variables: %+v\nActions: %s", script.Variables, script.GetActionsWithoutSemanticInfoJSON())
 
 	// 调用 LLM
-	extractor, err := h.llmManager.GetDefault()
+	extractor, err := h.llmManager.ForStep(models.LLMStepMCPInfo)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get LLM extractor: " + err.Error()})
 		return
@@ -1203,6 +1424,9 @@ func (h *Handler) ToggleScriptMCPCommand(c *gin.Context) {
 		MCPCommandName        string                 `json:"mcp_command_name"`
 		MCPCommandDescription string                 `json:"mcp_command_description"`
 		MCPInputSchema        map[string]interface{} `json:"mcp_input_schema"`
+
+		// 参数表单的展示和校验设置，未提供时不修改
+		ParamUISchema map[string]models.ParamUIField `json:"param_ui_schema"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -1241,6 +1465,9 @@ func (h *Handler) ToggleScriptMCPCommand(c *gin.Context) {
 	script.MCPCommandName = req.MCPCommandName
 	script.MCPCommandDescription = req.MCPCommandDescription
 	script.MCPInputSchema = req.MCPInputSchema
+	if req.ParamUISchema != nil {
+		script.ParamUISchema = req.ParamUISchema
+	}
 
 	if err := h.db.UpdateScript(script); err != nil {
 		c.JSON(500, gin.H{"error": "error.updateScriptFailed"})
@@ -1370,13 +1597,16 @@ func (h *Handler) GetPrompt(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": prompt})
 }
 
+// promptRequest 创建/更新提示词的请求
+type promptRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	Content     string `json:"content" binding:"required"`
+}
+
 // CreatePrompt 创建提示词
 func (h *Handler) CreatePrompt(c *gin.Context) {
-	var req struct {
-		Name        string `json:"name" binding:"required"`
-		Description string `json:"description"`
-		Content     string `json:"content" binding:"required"`
-	}
+	var req promptRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
@@ -1405,11 +1635,7 @@ func (h *Handler) CreatePrompt(c *gin.Context) {
 func (h *Handler) UpdatePrompt(c *gin.Context) {
 	id := c.Param("id")
 
-	var req struct {
-		Name        string `json:"name" binding:"required"`
-		Description string `json:"description"`
-		Content     string `json:"content" binding:"required"`
-	}
+	var req promptRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
@@ -1499,121 +1725,1031 @@ func (h *Handler) ResetPrompt(c *gin.Context) {
 	})
 }
 
-// ============= 脚本批量操作相关 API =============
+// ============= 页面组件相关 API =============
 
-// BatchSetGroup 批量设置脚本分组
-func (h *Handler) BatchSetGroup(c *gin.Context) {
-	var req struct {
-		ScriptIDs []string `json:"script_ids" binding:"required"`
-		Group     string   `json:"group" binding:"required"`
-	}
+// pageComponentRequest 创建/更新页面组件的请求
+type pageComponentRequest struct {
+	Name        string                             `json:"name" binding:"required"` // 组件名，如 login
+	Site        string                             `json:"site"`                    // 适用的站点域名，为空时适用于所有站点
+	Description string                             `json:"description"`             // 组件描述
+	Elements    map[string]models.ComponentElement `json:"elements"`                // 元素名 -> 定位器
+}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
-		return
+// validatePageComponent 校验页面组件，返回错误码；同一站点下组件名不能重复
+func (h *Handler) validatePageComponent(id string, req *pageComponentRequest) string {
+	if strings.Contains(req.Name, ".") {
+		return "error.invalidComponentName"
 	}
-
-	successCount := 0
-	for _, id := range req.ScriptIDs {
-		script, err := h.db.GetScript(id)
-		if err != nil {
-			continue
+	for name, element := range req.Elements {
+		if name == "" || (element.Selector == "" && element.XPath == "") {
+			return "error.invalidComponentElement"
 		}
-		script.Group = req.Group
-		script.UpdatedAt = time.Now()
-		if err := h.db.UpdateScript(script); err != nil {
-			continue
+	}
+
+	components, err := h.db.ListPageComponents()
+	if err != nil {
+		return "error.getPageComponentsFailed"
+	}
+	for _, component := range components {
+		if component.ID != id && component.Name == req.Name && strings.EqualFold(component.Site, req.Site) {
+			return "error.pageComponentExists"
 		}
-		successCount++
 	}
+	return ""
+}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "script.messages.batchGroupSuccess",
-		"count":   successCount,
-	})
+// ListPageComponents 列出所有页面组件
+func (h *Handler) ListPageComponents(c *gin.Context) {
+	components, err := h.db.ListPageComponents()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.getPageComponentsFailed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": components})
 }
 
-// BatchAddTags 批量添加标签
-func (h *Handler) BatchAddTags(c *gin.Context) {
-	var req struct {
-		ScriptIDs []string `json:"script_ids" binding:"required"`
-		Tags      []string `json:"tags" binding:"required"`
+// GetPageComponent 获取单个页面组件
+func (h *Handler) GetPageComponent(c *gin.Context) {
+	component, err := h.db.GetPageComponent(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.pageComponentNotFound"})
+		return
 	}
+	c.JSON(http.StatusOK, gin.H{"data": component})
+}
 
+// CreatePageComponent 创建页面组件
+func (h *Handler) CreatePageComponent(c *gin.Context) {
+	var req pageComponentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
 		return
 	}
+	if code := h.validatePageComponent("", &req); code != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": code})
+		return
+	}
 
-	successCount := 0
-	for _, id := range req.ScriptIDs {
-		script, err := h.db.GetScript(id)
-		if err != nil {
-			continue
-		}
-
-		// 合并标签，去重
-		tagMap := make(map[string]bool)
-		for _, tag := range script.Tags {
-			tagMap[tag] = true
-		}
-		for _, tag := range req.Tags {
-			tagMap[tag] = true
-		}
-
-		newTags := make([]string, 0, len(tagMap))
-		for tag := range tagMap {
-			newTags = append(newTags, tag)
-		}
-
-		script.Tags = newTags
-		script.UpdatedAt = time.Now()
-		if err := h.db.UpdateScript(script); err != nil {
-			continue
-		}
-		successCount++
+	component := &models.PageComponent{
+		ID:          uuid.New().String(),
+		Name:        req.Name,
+		Site:        req.Site,
+		Description: req.Description,
+		Elements:    req.Elements,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	if err := h.db.SavePageComponent(component); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.savePageComponentFailed"})
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "script.messages.batchTagsSuccess",
-		"count":   successCount,
-	})
+	c.JSON(http.StatusCreated, gin.H{"data": component})
 }
 
-// BatchDeleteScripts 批量删除脚本
-func (h *Handler) BatchDeleteScripts(c *gin.Context) {
-	var req struct {
-		ScriptIDs []string `json:"script_ids" binding:"required"`
-	}
+// UpdatePageComponent 更新页面组件，引用它的脚本在下次回放时使用新的定位器
+func (h *Handler) UpdatePageComponent(c *gin.Context) {
+	id := c.Param("id")
 
+	var req pageComponentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
 		return
 	}
 
-	successCount := 0
-	for _, id := range req.ScriptIDs {
-		if err := h.db.DeleteScript(id); err != nil {
-			continue
-		}
-		if err := h.db.DeleteToolConfigByScriptID(id); err != nil {
-			continue
-		}
-		successCount++
+	component, err := h.db.GetPageComponent(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.pageComponentNotFound"})
+		return
+	}
+	if code := h.validatePageComponent(id, &req); code != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": code})
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "script.messages.batchDeleteSuccess",
-		"count":   successCount,
-	})
-}
+	component.Name = req.Name
+	component.Site = req.Site
+	component.Description = req.Description
+	component.Elements = req.Elements
+	component.UpdatedAt = time.Now()
+	if err := h.db.SavePageComponent(component); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.savePageComponentFailed"})
+		return
+	}
 
-// ============= 脚本执行记录相关 API =============
+	c.JSON(http.StatusOK, gin.H{"data": component})
+}
 
-// ListScriptExecutions 列出脚本执行记录（支持分页和搜索）
-func (h *Handler) ListScriptExecutions(c *gin.Context) {
-	// 获取分页参数
-	page := 1
+// DeletePageComponent 删除页面组件
+func (h *Handler) DeletePageComponent(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := h.db.GetPageComponent(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.pageComponentNotFound"})
+		return
+	}
+	if err := h.db.DeletePageComponent(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.deletePageComponentFailed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success.pageComponentDeleted"})
+}
+
+// GetPageComponentUsages 列出引用该页面组件的脚本步骤，用于评估修改定位器的影响范围
+func (h *Handler) GetPageComponentUsages(c *gin.Context) {
+	component, err := h.db.GetPageComponent(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.pageComponentNotFound"})
+		return
+	}
+	scripts, err := h.db.ListScripts()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.getScriptListFailed"})
+		return
+	}
+
+	type usage struct {
+		ScriptID   string `json:"script_id"`
+		ScriptName string `json:"script_name"`
+		Step       int    `json:"step"`      // 步骤序号（从 1 开始）
+		Reference  string `json:"reference"` // 引用，如 login.username
+	}
+	usages := []usage{}
+	prefix := component.Name + "."
+	for _, script := range scripts {
+		for i, action := range script.Actions {
+			if strings.HasPrefix(action.Component, prefix) {
+				usages = append(usages, usage{ScriptID: script.ID, ScriptName: script.Name, Step: i + 1, Reference: action.Component})
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": usages})
+}
+
+// ============= 执行环境相关 API =============
+
+// environmentRequest 创建/更新执行环境的请求
+type environmentRequest struct {
+	Name        string            `json:"name" binding:"required"` // 环境名称，如 staging
+	Description string            `json:"description"`             // 环境描述
+	BaseURL     string            `json:"base_url"`                // 基础 URL，如 https://staging.example.com
+	Variables   map[string]string `json:"variables"`               // 参数默认值
+	Credentials map[string]string `json:"credentials"`             // 变量名 -> 系统环境变量名（以 BROWSERWING_CRED_ 开头）
+}
+
+// validateEnvironment 校验执行环境，返回错误码；环境名称不能重复（不区分大小写）
+func (h *Handler) validateEnvironment(id string, req *environmentRequest) string {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return "error.invalidParams"
+	}
+	if req.BaseURL != "" {
+		if u, err := url.Parse(req.BaseURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return "error.invalidEnvironmentBaseURL"
+		}
+	}
+	for name, envVar := range req.Credentials {
+		if name == "" || !models.IsCredentialEnvVar(envVar) {
+			return "error.invalidEnvironmentCredential"
+		}
+	}
+
+	envs, err := h.db.ListEnvironments()
+	if err != nil {
+		return "error.getEnvironmentsFailed"
+	}
+	for _, env := range envs {
+		if env.ID != id && strings.EqualFold(env.Name, req.Name) {
+			return "error.environmentExists"
+		}
+	}
+	return ""
+}
+
+// ListEnvironments 列出所有执行环境
+func (h *Handler) ListEnvironments(c *gin.Context) {
+	envs, err := h.db.ListEnvironments()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.getEnvironmentsFailed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": envs})
+}
+
+// GetEnvironment 获取单个执行环境
+// credentials_set 表示凭据引用的环境变量是否都已在服务端设置，便于在执行前发现配置问题
+func (h *Handler) GetEnvironment(c *gin.Context) {
+	env, err := h.db.GetEnvironment(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.environmentNotFound"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": env, "credentials_set": env.CredentialsSet()})
+}
+
+// CreateEnvironment 创建执行环境
+func (h *Handler) CreateEnvironment(c *gin.Context) {
+	var req environmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+		return
+	}
+	if code := h.validateEnvironment("", &req); code != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": code})
+		return
+	}
+
+	env := &models.Environment{
+		ID:          uuid.New().String(),
+		Name:        req.Name,
+		Description: req.Description,
+		BaseURL:     req.BaseURL,
+		Variables:   req.Variables,
+		Credentials: req.Credentials,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	if err := h.db.SaveEnvironment(env); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.saveEnvironmentFailed"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": env})
+}
+
+// UpdateEnvironment 更新执行环境
+func (h *Handler) UpdateEnvironment(c *gin.Context) {
+	id := c.Param("id")
+
+	var req environmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+		return
+	}
+
+	env, err := h.db.GetEnvironment(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.environmentNotFound"})
+		return
+	}
+	if code := h.validateEnvironment(id, &req); code != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": code})
+		return
+	}
+
+	env.Name = req.Name
+	env.Description = req.Description
+	env.BaseURL = req.BaseURL
+	env.Variables = req.Variables
+	env.Credentials = req.Credentials
+	env.UpdatedAt = time.Now()
+	if err := h.db.SaveEnvironment(env); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.saveEnvironmentFailed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": env})
+}
+
+// DeleteEnvironment 删除执行环境
+func (h *Handler) DeleteEnvironment(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := h.db.GetEnvironment(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.environmentNotFound"})
+		return
+	}
+	if err := h.db.DeleteEnvironment(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.deleteEnvironmentFailed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success.environmentDeleted"})
+}
+
+// ============= 站点知识包相关 API =============
+
+// siteKnowledgeRequest 创建或更新站点知识包的请求
+type siteKnowledgeRequest struct {
+	Name    string   `json:"name" binding:"required"`    // 知识包名称
+	Domains []string `json:"domains" binding:"required"` // 匹配的域名，同时匹配其子域名
+	Content string   `json:"content"`                    // 提示内容，如登录选择器、已知问题、访问频率限制
+	Enabled *bool    `json:"enabled"`                    // 是否启用，默认启用
+
+	LoginScriptID string `json:"login_script_id"` // 检测到已退出登录时执行的登录脚本
+}
+
+// validateSiteKnowledge 校验站点知识包并规范域名，返回错误码
+func validateSiteKnowledge(req *siteKnowledgeRequest) string {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Content = strings.TrimSpace(req.Content)
+	req.LoginScriptID = strings.TrimSpace(req.LoginScriptID)
+	if req.Name == "" || (req.Content == "" && req.LoginScriptID == "") {
+		return "error.invalidParams"
+	}
+	domains := []string{}
+	seen := make(map[string]bool)
+	for _, d := range req.Domains {
+		domain := models.NormalizeDomain(d)
+		if domain == "" || strings.ContainsAny(domain, " *") {
+			return "error.invalidSiteKnowledgeDomain"
+		}
+		if !seen[domain] {
+			seen[domain] = true
+			domains = append(domains, domain)
+		}
+	}
+	if len(domains) == 0 {
+		return "error.invalidSiteKnowledgeDomain"
+	}
+	req.Domains = domains
+	return ""
+}
+
+// ListSiteKnowledge 列出所有站点知识包
+func (h *Handler) ListSiteKnowledge(c *gin.Context) {
+	packs, err := h.db.ListSiteKnowledge()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.getSiteKnowledgeFailed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": packs})
+}
+
+// GetSiteKnowledge 获取单个站点知识包
+func (h *Handler) GetSiteKnowledge(c *gin.Context) {
+	pack, err := h.db.GetSiteKnowledge(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.siteKnowledgeNotFound"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": pack})
+}
+
+// CreateSiteKnowledge 创建站点知识包
+func (h *Handler) CreateSiteKnowledge(c *gin.Context) {
+	var req siteKnowledgeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+		return
+	}
+	if code := validateSiteKnowledge(&req); code != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": code})
+		return
+	}
+	if req.LoginScriptID != "" {
+		if _, err := h.db.GetScript(req.LoginScriptID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidLoginScript"})
+			return
+		}
+	}
+
+	pack := &models.SiteKnowledge{
+		ID:        uuid.New().String(),
+		Name:      req.Name,
+		Domains:   req.Domains,
+		Content:   req.Content,
+		Enabled:   req.Enabled == nil || *req.Enabled,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+
+		LoginScriptID: req.LoginScriptID,
+	}
+	if err := h.db.SaveSiteKnowledge(pack); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.saveSiteKnowledgeFailed"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": pack})
+}
+
+// UpdateSiteKnowledge 更新站点知识包
+func (h *Handler) UpdateSiteKnowledge(c *gin.Context) {
+	id := c.Param("id")
+
+	var req siteKnowledgeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+		return
+	}
+
+	pack, err := h.db.GetSiteKnowledge(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.siteKnowledgeNotFound"})
+		return
+	}
+	if code := validateSiteKnowledge(&req); code != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": code})
+		return
+	}
+	if req.LoginScriptID != "" {
+		if _, err := h.db.GetScript(req.LoginScriptID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidLoginScript"})
+			return
+		}
+	}
+
+	pack.Name = req.Name
+	pack.Domains = req.Domains
+	pack.Content = req.Content
+	pack.LoginScriptID = req.LoginScriptID
+	if req.Enabled != nil {
+		pack.Enabled = *req.Enabled
+	}
+	pack.UpdatedAt = time.Now()
+	if err := h.db.SaveSiteKnowledge(pack); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.saveSiteKnowledgeFailed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": pack})
+}
+
+// DeleteSiteKnowledge 删除站点知识包
+func (h *Handler) DeleteSiteKnowledge(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := h.db.GetSiteKnowledge(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.siteKnowledgeNotFound"})
+		return
+	}
+	if err := h.db.DeleteSiteKnowledge(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.deleteSiteKnowledgeFailed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success.siteKnowledgeDeleted"})
+}
+
+// ============= 通知渠道、规则与执行报告摘要相关 API =============
+
+// validateNotificationChannel 校验通知渠道，返回错误码和详情
+func (h *Handler) validateNotificationChannel(ctx context.Context, channel *models.NotificationChannel) (string, string) {
+	channel.Name = strings.TrimSpace(channel.Name)
+	if channel.Name == "" {
+		return "error.invalidParams", "name is required"
+	}
+	if err := notify.Validate(channel); err != nil {
+		return "error.invalidNotificationChannel", err.Error()
+	}
+	if channel.WebhookURL != "" {
+		if err := h.validateWebhookURL(ctx, channel.WebhookURL); err != nil {
+			return "error.invalidWebhookUrl", err.Error()
+		}
+	}
+	return "", ""
+}
+
+// ListNotificationChannels 列出所有通知渠道
+func (h *Handler) ListNotificationChannels(c *gin.Context) {
+	channels, err := h.db.ListNotificationChannels()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.getNotificationChannelsFailed"})
+		return
+	}
+	for i := range channels {
+		redacted := channels[i].Redacted()
+		channels[i] = &redacted
+	}
+	c.JSON(http.StatusOK, gin.H{"data": channels})
+}
+
+// GetNotificationChannel 获取单个通知渠道
+func (h *Handler) GetNotificationChannel(c *gin.Context) {
+	channel, err := h.db.GetNotificationChannel(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.notificationChannelNotFound"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": channel.Redacted()})
+}
+
+// CreateNotificationChannel 创建通知渠道
+func (h *Handler) CreateNotificationChannel(c *gin.Context) {
+	var channel models.NotificationChannel
+	if err := c.ShouldBindJSON(&channel); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+		return
+	}
+	channel.KeepSecretsFrom(nil)
+	if code, detail := h.validateNotificationChannel(c.Request.Context(), &channel); code != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": code, "detail": detail})
+		return
+	}
+
+	channel.ID = uuid.New().String()
+	channel.CreatedAt = time.Now()
+	channel.UpdatedAt = time.Now()
+	if err := h.db.SaveNotificationChannel(&channel); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.saveNotificationChannelFailed"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": channel.Redacted()})
+}
+
+// UpdateNotificationChannel 更新通知渠道
+func (h *Handler) UpdateNotificationChannel(c *gin.Context) {
+	id := c.Param("id")
+	existing, err := h.db.GetNotificationChannel(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.notificationChannelNotFound"})
+		return
+	}
+
+	var channel models.NotificationChannel
+	if err := c.ShouldBindJSON(&channel); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+		return
+	}
+	channel.KeepSecretsFrom(existing)
+	if code, detail := h.validateNotificationChannel(c.Request.Context(), &channel); code != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": code, "detail": detail})
+		return
+	}
+
+	channel.ID = id
+	channel.CreatedAt = existing.CreatedAt
+	channel.UpdatedAt = time.Now()
+	if err := h.db.SaveNotificationChannel(&channel); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.saveNotificationChannelFailed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": channel.Redacted()})
+}
+
+// DeleteNotificationChannel 删除通知渠道，同时从引用它的通知规则和执行报告摘要中移除
+func (h *Handler) DeleteNotificationChannel(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := h.db.GetNotificationChannel(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.notificationChannelNotFound"})
+		return
+	}
+
+	rules, err := h.db.ListNotificationRules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.getNotificationRulesFailed"})
+		return
+	}
+	for _, rule := range rules {
+		if !slices.Contains(rule.ChannelIDs, id) {
+			continue
+		}
+		rule.ChannelIDs = slices.DeleteFunc(rule.ChannelIDs, func(channelID string) bool { return channelID == id })
+		rule.UpdatedAt = time.Now()
+		if err := h.db.SaveNotificationRule(rule); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error.saveNotificationRuleFailed"})
+			return
+		}
+	}
+
+	digests, err := h.db.ListNotificationDigests()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.getNotificationDigestsFailed"})
+		return
+	}
+	for _, digest := range digests {
+		if !slices.Contains(digest.ChannelIDs, id) {
+			continue
+		}
+		digest.ChannelIDs = slices.DeleteFunc(digest.ChannelIDs, func(channelID string) bool { return channelID == id })
+		digest.UpdatedAt = time.Now()
+		if err := h.db.SaveNotificationDigest(digest); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error.saveNotificationDigestFailed"})
+			return
+		}
+	}
+
+	if err := h.db.DeleteNotificationChannel(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.deleteNotificationChannelFailed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success.notificationChannelDeleted"})
+}
+
+// TestNotificationChannel 向渠道发送一条测试消息（渠道未启用时也发送）
+func (h *Handler) TestNotificationChannel(c *gin.Context) {
+	channel, err := h.db.GetNotificationChannel(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.notificationChannelNotFound"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+	msg := notify.Message{
+		Title: "BrowserWing test notification",
+		Lines: []string{"Channel: " + channel.Name, "Sent: " + time.Now().Format(time.RFC3339)},
+	}
+	if err := notify.Send(ctx, h.browserManager.NetworkGuard(), channel, msg); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "error.sendNotificationFailed", "detail": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success.notificationSent"})
+}
+
+// notificationEvents 可以配置通知规则的事件
+var notificationEvents = []models.NotificationEvent{
+	models.NotificationEventScriptFailed,
+	models.NotificationEventTaskCompleted,
+	models.NotificationEventTaskFailed,
+	models.NotificationEventMonitorChanged,
+	models.NotificationEventBlocked,
+	models.NotificationEventSchemaDrift,
+	models.NotificationEventAnomaly,
+}
+
+// validateNotificationRule 校验通知规则，返回错误码和详情
+func (h *Handler) validateNotificationRule(rule *models.NotificationRule) (string, string) {
+	rule.Name = strings.TrimSpace(rule.Name)
+	if rule.Name == "" {
+		return "error.invalidParams", "name is required"
+	}
+	if !slices.Contains(notificationEvents, rule.Event) {
+		return "error.invalidNotificationEvent", fmt.Sprintf("unknown event %q", rule.Event)
+	}
+	if len(rule.ChannelIDs) == 0 {
+		return "error.invalidParams", "at least one channel is required"
+	}
+	for _, id := range rule.ChannelIDs {
+		if _, err := h.db.GetNotificationChannel(id); err != nil {
+			return "error.notificationChannelNotFound", id
+		}
+	}
+	if rule.ScriptID != "" {
+		if _, err := h.db.GetScript(rule.ScriptID); err != nil {
+			return "error.scriptNotFound", rule.ScriptID
+		}
+	}
+	if rule.TaskID != "" {
+		if _, err := h.db.GetScheduledTask(rule.TaskID); err != nil {
+			return "error.taskNotFound", rule.TaskID
+		}
+	}
+	return "", ""
+}
+
+// ListNotificationRules 列出所有通知规则
+func (h *Handler) ListNotificationRules(c *gin.Context) {
+	rules, err := h.db.ListNotificationRules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.getNotificationRulesFailed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rules})
+}
+
+// GetNotificationRule 获取单个通知规则
+func (h *Handler) GetNotificationRule(c *gin.Context) {
+	rule, err := h.db.GetNotificationRule(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.notificationRuleNotFound"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": rule})
+}
+
+// CreateNotificationRule 创建通知规则
+func (h *Handler) CreateNotificationRule(c *gin.Context) {
+	var rule models.NotificationRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+		return
+	}
+	if code, detail := h.validateNotificationRule(&rule); code != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": code, "detail": detail})
+		return
+	}
+
+	rule.ID = uuid.New().String()
+	rule.CreatedAt = time.Now()
+	rule.UpdatedAt = time.Now()
+	if err := h.db.SaveNotificationRule(&rule); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.saveNotificationRuleFailed"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": rule})
+}
+
+// UpdateNotificationRule 更新通知规则
+func (h *Handler) UpdateNotificationRule(c *gin.Context) {
+	id := c.Param("id")
+	existing, err := h.db.GetNotificationRule(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.notificationRuleNotFound"})
+		return
+	}
+
+	var rule models.NotificationRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+		return
+	}
+	if code, detail := h.validateNotificationRule(&rule); code != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": code, "detail": detail})
+		return
+	}
+
+	rule.ID = id
+	rule.CreatedAt = existing.CreatedAt
+	rule.UpdatedAt = time.Now()
+	if err := h.db.SaveNotificationRule(&rule); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.saveNotificationRuleFailed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": rule})
+}
+
+// DeleteNotificationRule 删除通知规则
+func (h *Handler) DeleteNotificationRule(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := h.db.GetNotificationRule(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.notificationRuleNotFound"})
+		return
+	}
+	if err := h.db.DeleteNotificationRule(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.deleteNotificationRuleFailed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success.notificationRuleDeleted"})
+}
+
+// validateNotificationDigest 校验执行报告摘要配置，返回错误码和详情
+func (h *Handler) validateNotificationDigest(digest *models.NotificationDigest) (string, string) {
+	digest.Name = strings.TrimSpace(digest.Name)
+	if digest.Name == "" {
+		return "error.invalidParams", "name is required"
+	}
+	if digest.Period != models.DigestPeriodDaily && digest.Period != models.DigestPeriodWeekly {
+		return "error.invalidParams", fmt.Sprintf("unknown period %q", digest.Period)
+	}
+	if digest.Hour < 0 || digest.Hour > 23 {
+		return "error.invalidParams", "hour must be between 0 and 23"
+	}
+	if digest.Weekday < time.Sunday || digest.Weekday > time.Saturday {
+		return "error.invalidParams", "weekday must be between 0 (Sunday) and 6 (Saturday)"
+	}
+	if len(digest.ChannelIDs) == 0 {
+		return "error.invalidParams", "at least one channel is required"
+	}
+	for _, id := range digest.ChannelIDs {
+		if _, err := h.db.GetNotificationChannel(id); err != nil {
+			return "error.notificationChannelNotFound", id
+		}
+	}
+	for _, id := range digest.ScriptIDs {
+		if _, err := h.db.GetScript(id); err != nil {
+			return "error.scriptNotFound", id
+		}
+	}
+	return "", ""
+}
+
+// ListNotificationDigests 列出所有执行报告摘要
+func (h *Handler) ListNotificationDigests(c *gin.Context) {
+	digests, err := h.db.ListNotificationDigests()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.getNotificationDigestsFailed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": digests})
+}
+
+// GetNotificationDigest 获取单个执行报告摘要
+func (h *Handler) GetNotificationDigest(c *gin.Context) {
+	digest, err := h.db.GetNotificationDigest(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.notificationDigestNotFound"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": digest})
+}
+
+// CreateNotificationDigest 创建执行报告摘要，从下一个计划发送时间开始发送
+func (h *Handler) CreateNotificationDigest(c *gin.Context) {
+	var digest models.NotificationDigest
+	if err := c.ShouldBindJSON(&digest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+		return
+	}
+	if code, detail := h.validateNotificationDigest(&digest); code != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": code, "detail": detail})
+		return
+	}
+
+	digest.ID = uuid.New().String()
+	digest.LastSentAt = nil
+	digest.CreatedAt = time.Now()
+	digest.UpdatedAt = time.Now()
+	if err := h.db.SaveNotificationDigest(&digest); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.saveNotificationDigestFailed"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": digest})
+}
+
+// UpdateNotificationDigest 更新执行报告摘要
+func (h *Handler) UpdateNotificationDigest(c *gin.Context) {
+	id := c.Param("id")
+	existing, err := h.db.GetNotificationDigest(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.notificationDigestNotFound"})
+		return
+	}
+
+	var digest models.NotificationDigest
+	if err := c.ShouldBindJSON(&digest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+		return
+	}
+	if code, detail := h.validateNotificationDigest(&digest); code != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": code, "detail": detail})
+		return
+	}
+
+	digest.ID = id
+	digest.LastSentAt = existing.LastSentAt
+	digest.CreatedAt = existing.CreatedAt
+	digest.UpdatedAt = time.Now()
+	if err := h.db.SaveNotificationDigest(&digest); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.saveNotificationDigestFailed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": digest})
+}
+
+// DeleteNotificationDigest 删除执行报告摘要
+func (h *Handler) DeleteNotificationDigest(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := h.db.GetNotificationDigest(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.notificationDigestNotFound"})
+		return
+	}
+	if err := h.db.DeleteNotificationDigest(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.deleteNotificationDigestFailed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success.notificationDigestDeleted"})
+}
+
+// PreviewNotificationDigest 以 HTML 返回截至当前时间的一个周期的摘要报告
+func (h *Handler) PreviewNotificationDigest(c *gin.Context) {
+	digest, err := h.db.GetNotificationDigest(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.notificationDigestNotFound"})
+		return
+	}
+	report, err := notify.BuildDigest(h.db, digest, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.buildDigestFailed", "detail": err.Error()})
+		return
+	}
+	html, err := report.HTML()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.buildDigestFailed", "detail": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(html))
+}
+
+// SendNotificationDigest 立即发送截至当前时间的一个周期的摘要报告，不影响定期发送
+func (h *Handler) SendNotificationDigest(c *gin.Context) {
+	digest, err := h.db.GetNotificationDigest(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.notificationDigestNotFound"})
+		return
+	}
+	report, err := notify.BuildDigest(h.db, digest, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.buildDigestFailed", "detail": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Minute)
+	defer cancel()
+	if err := notify.SendDigest(ctx, h.db, h.browserManager.NetworkGuard(), digest, report); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "error.sendNotificationFailed", "detail": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success.notificationSent"})
+}
+
+// ============= 脚本批量操作相关 API =============
+
+// BatchSetGroup 批量设置脚本分组
+func (h *Handler) BatchSetGroup(c *gin.Context) {
+	var req struct {
+		ScriptIDs []string `json:"script_ids" binding:"required"`
+		Group     string   `json:"group" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+		return
+	}
+
+	successCount := 0
+	for _, id := range req.ScriptIDs {
+		script, err := h.db.GetScript(id)
+		if err != nil {
+			continue
+		}
+		script.Group = req.Group
+		script.UpdatedAt = time.Now()
+		if err := h.db.UpdateScript(script); err != nil {
+			continue
+		}
+		successCount++
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "script.messages.batchGroupSuccess",
+		"count":   successCount,
+	})
+}
+
+// BatchAddTags 批量添加标签
+func (h *Handler) BatchAddTags(c *gin.Context) {
+	var req struct {
+		ScriptIDs []string `json:"script_ids" binding:"required"`
+		Tags      []string `json:"tags" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+		return
+	}
+
+	successCount := 0
+	for _, id := range req.ScriptIDs {
+		script, err := h.db.GetScript(id)
+		if err != nil {
+			continue
+		}
+
+		// 合并标签，去重
+		tagMap := make(map[string]bool)
+		for _, tag := range script.Tags {
+			tagMap[tag] = true
+		}
+		for _, tag := range req.Tags {
+			tagMap[tag] = true
+		}
+
+		newTags := make([]string, 0, len(tagMap))
+		for tag := range tagMap {
+			newTags = append(newTags, tag)
+		}
+
+		script.Tags = newTags
+		script.UpdatedAt = time.Now()
+		if err := h.db.UpdateScript(script); err != nil {
+			continue
+		}
+		successCount++
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "script.messages.batchTagsSuccess",
+		"count":   successCount,
+	})
+}
+
+// BatchDeleteScripts 批量删除脚本
+func (h *Handler) BatchDeleteScripts(c *gin.Context) {
+	var req struct {
+		ScriptIDs []string `json:"script_ids" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams"})
+		return
+	}
+
+	successCount := 0
+	for _, id := range req.ScriptIDs {
+		if err := h.db.DeleteScript(id); err != nil {
+			continue
+		}
+		if err := h.db.DeleteToolConfigByScriptID(id); err != nil {
+			continue
+		}
+		successCount++
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "script.messages.batchDeleteSuccess",
+		"count":   successCount,
+	})
+}
+
+// ============= 脚本执行记录相关 API =============
+
+// ListScriptExecutions 列出脚本执行记录（支持分页和搜索）
+func (h *Handler) ListScriptExecutions(c *gin.Context) {
+	// 获取分页参数
+	page := 1
 	pageSize := 20
 	if pageStr := c.Query("page"); pageStr != "" {
 		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
@@ -1657,7 +2793,7 @@ func (h *Handler) ListScriptExecutions(c *gin.Context) {
 		}
 
 		if exec.VideoPath != "" {
-			exec.VideoPath = "/files/" + exec.VideoPath
+			exec.VideoPath = h.fileURL(exec.VideoPath)
 		}
 
 		filteredExecutions = append(filteredExecutions, exec)
@@ -1747,6 +2883,28 @@ func (h *Handler) GetRecordingConfig(c *gin.Context) {
 	c.JSON(200, config)
 }
 
+// GetStorageUsage 获取录像、下载、截图目录的占用情况及配额
+func (h *Handler) GetStorageUsage(c *gin.Context) {
+	usage, err := h.browserManager.ArtifactUsage(c.Request.Context())
+	if err != nil {
+		c.JSON(500, gin.H{"error": "error.getStorageUsageFailed", "detail": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"usage": usage})
+}
+
+// CleanupStorage 立即按配额清理产物目录
+func (h *Handler) CleanupStorage(c *gin.Context) {
+	ctx := c.Request.Context()
+	evictions := h.browserManager.EnforceArtifactQuotas(ctx)
+	usage, err := h.browserManager.ArtifactUsage(ctx)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "error.getStorageUsageFailed", "detail": err.Error()})
+		return
+	}
+	c.JSON(200, gin.H{"evicted": evictions, "usage": usage})
+}
+
 // UpdateRecordingConfig 更新录制配置
 func (h *Handler) UpdateRecordingConfig(c *gin.Context) {
 	var req models.RecordingConfig
@@ -1767,6 +2925,14 @@ func (h *Handler) UpdateRecordingConfig(c *gin.Context) {
 		c.JSON(400, gin.H{"error": "error.qualityRange"})
 		return
 	}
+	if req.MaxWidth < 0 || req.MaxHeight < 0 {
+		c.JSON(400, gin.H{"error": "error.invalidParams"})
+		return
+	}
+	if r := req.Region; r != nil && (r.Width <= 0 || r.Height <= 0 || r.X < 0 || r.Y < 0) {
+		c.JSON(400, gin.H{"error": "error.invalidCaptureRegion"})
+		return
+	}
 	if req.Format == "" {
 		req.Format = "mp4"
 	}
@@ -2843,6 +4009,7 @@ func (h *Handler) CreateApiKey(c *gin.Context) {
 		UserID:      userID.(string),
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
+		URLPolicy:   req.URLPolicy,
 	}
 
 	if err := h.db.CreateApiKey(apiKey); err != nil {
@@ -3448,12 +4615,9 @@ func generateSkillMD(scripts []*models.Script, host string, isExportAll bool, sc
 
 // ============= Executor HTTP API =============
 
-// ExecutorHelp 获取所有可用命令的帮助信息
-func (h *Handler) ExecutorHelp(c *gin.Context) {
-	// 支持查询特定命令
-	command := c.Query("command")
-
-	commands := []map[string]interface{}{
+// executorCommands Executor HTTP API 的命令列表（参数、示例和返回值），同时用于生成 OpenAPI 文档
+func executorCommands() []map[string]interface{} {
+	return []map[string]interface{}{
 		{
 			"name":        "navigate",
 			"method":      "POST",
@@ -3540,6 +4704,11 @@ func (h *Handler) ExecutorHelp(c *gin.Context) {
 					"description": "Clear existing content first",
 					"default":     true,
 				},
+				"ime": map[string]interface{}{
+					"type":        "boolean",
+					"required":    false,
+					"description": "Type through IME composition events (compositionstart/update/end) for CJK inputs",
+				},
 			},
 			"example": map[string]interface{}{
 				"identifier": "#email-input",
@@ -3570,6 +4739,86 @@ func (h *Handler) ExecutorHelp(c *gin.Context) {
 				"value":      "United States",
 			},
 		},
+		{
+			"name":        "fill-date",
+			"method":      "POST",
+			"endpoint":    "/api/v1/executor/fill-date",
+			"description": "Fill a date input: sets the value with proper events or navigates the calendar widget (flatpickr, jQuery UI, Ant Design, Element UI, MUI, react-datepicker...) to the target date",
+			"parameters": map[string]interface{}{
+				"identifier": map[string]interface{}{
+					"type":        "string",
+					"required":    true,
+					"description": "Date input identifier",
+				},
+				"date": map[string]interface{}{
+					"type":        "string",
+					"required":    true,
+					"description": "YYYY-MM-DD, YYYY-MM-DD HH:mm, today, tomorrow, or relative like +7d, -1m",
+					"example":     "2025-03-15",
+				},
+				"strategy": map[string]interface{}{
+					"type":        "string",
+					"required":    false,
+					"description": "auto, value (set input value) or calendar (click through the widget)",
+					"default":     "auto",
+				},
+				"format": map[string]interface{}{
+					"type":        "string",
+					"required":    false,
+					"description": "Date format for text inputs, e.g. MM/DD/YYYY (default: from placeholder, otherwise YYYY-MM-DD)",
+				},
+			},
+			"example": map[string]interface{}{
+				"identifier": "#checkin",
+				"date":       "+7d",
+			},
+			"returns": "Strategy used, detected widget and the resulting input value",
+		},
+		{
+			"name":        "choose-option",
+			"method":      "POST",
+			"endpoint":    "/api/v1/executor/choose-option",
+			"description": "Choose an option from a custom dropdown (Select2, Ant Design, Element UI, MUI, react-select, etc.): opens it, types to filter and clicks the matching option",
+			"parameters": map[string]interface{}{
+				"identifier": map[string]interface{}{
+					"type":        "string",
+					"required":    true,
+					"description": "Dropdown trigger or the underlying <select> element",
+				},
+				"value": map[string]interface{}{
+					"type":        "string",
+					"required":    true,
+					"description": "Text of the option to choose",
+				},
+				"exact": map[string]interface{}{
+					"type":        "boolean",
+					"required":    false,
+					"description": "Require the option text to match exactly",
+				},
+				"filter": map[string]interface{}{
+					"type":        "boolean",
+					"required":    false,
+					"description": "Type the value into the dropdown search box to filter options",
+					"default":     true,
+				},
+				"option_selector": map[string]interface{}{
+					"type":        "string",
+					"required":    false,
+					"description": "CSS selector for option elements, overriding the built-in widget rules",
+				},
+				"timeout": map[string]interface{}{
+					"type":        "number",
+					"required":    false,
+					"description": "Seconds to wait for the matching option",
+					"default":     10,
+				},
+			},
+			"example": map[string]interface{}{
+				"identifier": "#country-select",
+				"value":      "Germany",
+			},
+			"returns": "Detected widget framework, chosen option text and the text now displayed by the dropdown",
+		},
 		{
 			"name":        "extract",
 			"method":      "POST",
@@ -3652,16 +4901,48 @@ func (h *Handler) ExecutorHelp(c *gin.Context) {
 				"identifier": ".dropdown-trigger",
 			},
 		},
+		{
+			"name":        "hover-then-click",
+			"method":      "POST",
+			"endpoint":    "/api/v1/executor/hover-then-click",
+			"description": "Hover a trigger element, wait for the revealed target and click it, retrying the hover if needed",
+			"parameters": map[string]interface{}{
+				"trigger": map[string]interface{}{
+					"type":        "string",
+					"required":    true,
+					"description": "Element to hover",
+				},
+				"target": map[string]interface{}{
+					"type":        "string",
+					"required":    true,
+					"description": "Element to click once revealed",
+				},
+				"timeout": map[string]interface{}{
+					"type":        "number",
+					"required":    false,
+					"description": "Seconds to wait for the target per attempt (default: 5)",
+				},
+				"retries": map[string]interface{}{
+					"type":        "number",
+					"required":    false,
+					"description": "Maximum number of attempts (default: 3)",
+				},
+			},
+			"example": map[string]interface{}{
+				"trigger": ".nav-products",
+				"target":  ".nav-products .submenu a[href='/pricing']",
+			},
+		},
 		{
 			"name":        "press-key",
 			"method":      "POST",
 			"endpoint":    "/api/v1/executor/press-key",
-			"description": "Press a keyboard key",
+			"description": "Press a keyboard key, a shortcut or a chain of shortcuts",
 			"parameters": map[string]interface{}{
 				"key": map[string]interface{}{
 					"type":        "string",
 					"required":    true,
-					"description": "Key to press: Enter, Tab, Escape, ArrowDown, etc.",
+					"description": "Key to press (Enter, Tab, Escape, ArrowDown, etc.), a shortcut (ctrl+s, mod+shift+p) or a chain (ctrl+k then p). mod is Cmd on macOS and Ctrl elsewhere",
 					"example":     "Enter",
 				},
 				"ctrl": map[string]interface{}{
@@ -3674,18 +4955,57 @@ func (h *Handler) ExecutorHelp(c *gin.Context) {
 					"required":    false,
 					"description": "Hold Shift key",
 				},
+				"mod": map[string]interface{}{
+					"type":        "boolean",
+					"required":    false,
+					"description": "Hold the platform modifier (Cmd on macOS, Ctrl elsewhere)",
+				},
+			},
+			"example": map[string]interface{}{
+				"key": "Enter",
+			},
+		},
+		{
+			"name":        "scroll-to-bottom",
+			"method":      "POST",
+			"endpoint":    "/api/v1/executor/scroll-to-bottom",
+			"description": "Scroll to the bottom of the page",
+			"parameters":  map[string]interface{}{},
+			"example":     map[string]interface{}{},
+		},
+		{
+			"name":        "scroll",
+			"method":      "POST",
+			"endpoint":    "/api/v1/executor/scroll",
+			"description": "Scroll the page or a scrollable container to an edge or by a relative distance, vertically or horizontally",
+			"parameters": map[string]interface{}{
+				"container": map[string]interface{}{
+					"type":        "string",
+					"required":    false,
+					"description": "Scrollable container identifier (scrolls the page when omitted)",
+					"example":     ".chat-messages",
+				},
+				"to": map[string]interface{}{
+					"type":        "string",
+					"required":    false,
+					"description": "Scroll to an edge: top, bottom, left, right (overrides deltas)",
+				},
+				"delta_x": map[string]interface{}{
+					"type":        "number",
+					"required":    false,
+					"description": "Horizontal scroll distance in pixels (negative scrolls left)",
+				},
+				"delta_y": map[string]interface{}{
+					"type":        "number",
+					"required":    false,
+					"description": "Vertical scroll distance in pixels (negative scrolls up)",
+				},
 			},
 			"example": map[string]interface{}{
-				"key": "Enter",
+				"container": ".chat-messages",
+				"delta_y":   -500,
 			},
-		},
-		{
-			"name":        "scroll-to-bottom",
-			"method":      "POST",
-			"endpoint":    "/api/v1/executor/scroll-to-bottom",
-			"description": "Scroll to the bottom of the page",
-			"parameters":  map[string]interface{}{},
-			"example":     map[string]interface{}{},
+			"returns": "Scroll position (scroll_x, scroll_y), max scroll and at_top/at_bottom/at_left/at_right flags",
 		},
 		{
 			"name":        "go-back",
@@ -3739,6 +5059,29 @@ func (h *Handler) ExecutorHelp(c *gin.Context) {
 			},
 			"returns": "Input value",
 		},
+		{
+			"name":        "inspect",
+			"method":      "POST",
+			"endpoint":    "/api/v1/executor/inspect",
+			"description": "Inspect an element: attributes, computed styles, bounding box, visibility/interactability verdicts, intercepting element and ancestor chain",
+			"parameters": map[string]interface{}{
+				"identifier": map[string]interface{}{
+					"type":        "string",
+					"required":    true,
+					"description": "Element identifier",
+				},
+				"styles": map[string]interface{}{
+					"type":        "array",
+					"required":    false,
+					"description": "Additional computed style properties to return",
+				},
+			},
+			"example": map[string]interface{}{
+				"identifier": "#submit",
+			},
+			"returns": "Element inspection report",
+			"note":    "Use this to debug why a click is intercepted or an element is considered hidden.",
+		},
 		{
 			"name":        "snapshot",
 			"method":      "GET",
@@ -3806,6 +5149,11 @@ func (h *Handler) ExecutorHelp(c *gin.Context) {
 					"description": "Image format: png or jpeg",
 					"default":     "png",
 				},
+				"file_name": map[string]interface{}{
+					"type":        "string",
+					"required":    false,
+					"description": "File name to save as, inside the configured screenshots directory (directory parts are stripped)",
+				},
 			},
 			"returns": "Base64 encoded image data",
 		},
@@ -4001,7 +5349,7 @@ func (h *Handler) ExecutorHelp(c *gin.Context) {
 				"file_paths": map[string]interface{}{
 					"type":        "array",
 					"required":    true,
-					"description": "Array of file paths to upload (absolute paths)",
+					"description": "Array of file paths to upload (absolute paths, or upload:<id> handles from POST /api/v1/uploads)",
 					"example":     []string{"/path/to/file1.pdf", "/path/to/file2.jpg"},
 				},
 			},
@@ -4019,8 +5367,8 @@ func (h *Handler) ExecutorHelp(c *gin.Context) {
 			"parameters": map[string]interface{}{
 				"from_identifier": map[string]interface{}{
 					"type":        "string",
-					"required":    true,
-					"description": "Source element identifier to drag",
+					"required":    false,
+					"description": "Source element identifier to drag (optional in html5 mode when dropping files)",
 					"example":     "#drag-item",
 				},
 				"to_identifier": map[string]interface{}{
@@ -4029,6 +5377,21 @@ func (h *Handler) ExecutorHelp(c *gin.Context) {
 					"description": "Target element identifier to drop onto",
 					"example":     "#drop-zone",
 				},
+				"mode": map[string]interface{}{
+					"type":        "string",
+					"required":    false,
+					"description": "Drag mode: mouse (default, raw mouse moves) or html5 (synthesized dragstart/dragover/drop events with a DataTransfer)",
+				},
+				"data": map[string]interface{}{
+					"type":        "object",
+					"required":    false,
+					"description": "html5 mode: DataTransfer data as MIME type to value",
+				},
+				"files": map[string]interface{}{
+					"type":        "array",
+					"required":    false,
+					"description": "html5 mode: local file paths to drop onto the target",
+				},
 			},
 			"example": map[string]interface{}{
 				"from_identifier": "#drag-item",
@@ -4046,6 +5409,14 @@ func (h *Handler) ExecutorHelp(c *gin.Context) {
 			"note":        "Use with caution. After closing, you may need to switch to another tab.",
 		},
 	}
+}
+
+// ExecutorHelp 获取所有可用命令的帮助信息
+func (h *Handler) ExecutorHelp(c *gin.Context) {
+	// 支持查询特定命令
+	command := c.Query("command")
+
+	commands := executorCommands()
 
 	// 如果指定了特定命令，只返回该命令的信息
 	if command != "" {
@@ -4072,6 +5443,12 @@ func (h *Handler) ExecutorHelp(c *gin.Context) {
 			"jwt":     "Authorization: Bearer <token>",
 			"api_key": "X-BrowserWing-Key: <api-key>",
 		},
+		"target": map[string]interface{}{
+			"description": "Every endpoint accepts an explicit target; requests on the same target run one at a time, different targets run concurrently",
+			"instance_id": "Query ?instance_id= or header X-Instance-ID (browser instance, default: current instance)",
+			"tab_id":      "Query ?tab_id= or header X-Tab-ID (tab TargetID from /tabs, default: active tab)",
+			"session_id":  "Query ?session_id= or header X-Session-ID (isolates active tab and RefIDs per caller)",
+		},
 		"workflow": []string{
 			"1. Call GET /snapshot to understand page structure",
 			"2. Use element RefIDs (@e1, @e2) or CSS selectors for operations",
@@ -4151,20 +5528,26 @@ func (h *Handler) ExecutorNavigate(c *gin.Context) {
 		URL       string `json:"url" binding:"required"`
 		WaitUntil string `json:"wait_until"` // load, domcontentloaded, networkidle
 		Timeout   int    `json:"timeout"`    // 秒
+		Snapshot  string `json:"snapshot"`   // 导航后的快照：include（默认）、skip、defer
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidRequest"})
 		return
 	}
+	snapshot, err := executor2.ParseSnapshotMode(req.Snapshot)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidRequest", "detail": err.Error()})
+		return
+	}
 
 	// 创建 executor 实例
 	executor := h.executor.WithContext(c.Request.Context())
 
 	// 设置选项
 	var opts *executor2.NavigateOptions
-	if req.WaitUntil != "" || req.Timeout > 0 {
-		opts = &executor2.NavigateOptions{}
+	if req.WaitUntil != "" || req.Timeout > 0 || snapshot != executor2.SnapshotInclude {
+		opts = &executor2.NavigateOptions{Snapshot: snapshot}
 		if req.WaitUntil != "" {
 			opts.WaitUntil = req.WaitUntil
 		}
@@ -4177,8 +5560,9 @@ func (h *Handler) ExecutorNavigate(c *gin.Context) {
 	result, err := executor.Navigate(c.Request.Context(), req.URL, opts)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.navigationFailed",
-			"detail": err.Error(),
+			"error":      "error.navigationFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4195,12 +5579,19 @@ func (h *Handler) ExecutorClick(c *gin.Context) {
 		Timeout     int    `json:"timeout"` // 秒
 		Button      string `json:"button"`  // left, right, middle
 		ClickCount  int    `json:"click_count"`
+		TabID       string `json:"tab_id"`   // 指定标签页（可选）
+		Snapshot    string `json:"snapshot"` // 点击后的快照：include（默认）、skip、defer
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidRequest"})
 		return
 	}
+	snapshot, err := executor2.ParseSnapshotMode(req.Snapshot)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidRequest", "detail": err.Error()})
+		return
+	}
 
 	executor := h.executor.WithContext(c.Request.Context())
 
@@ -4209,16 +5600,18 @@ func (h *Handler) ExecutorClick(c *gin.Context) {
 		WaitEnabled: req.WaitEnabled,
 		Button:      req.Button,
 		ClickCount:  req.ClickCount,
+		Snapshot:    snapshot,
 	}
 	if req.Timeout > 0 {
 		opts.Timeout = time.Duration(req.Timeout) * time.Second
 	}
 
-	result, err := executor.Click(c.Request.Context(), req.Identifier, opts)
+	result, err := executor.Click(executor2.WithTab(c.Request.Context(), req.TabID), req.Identifier, opts)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.clickFailed",
-			"detail": err.Error(),
+			"error":      "error.clickFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4233,20 +5626,30 @@ func (h *Handler) ExecutorType(c *gin.Context) {
 		Text        string `json:"text" binding:"required"`
 		Clear       bool   `json:"clear"`
 		WaitVisible bool   `json:"wait_visible"`
-		Timeout     int    `json:"timeout"` // 秒
-		Delay       int    `json:"delay"`   // 毫秒
+		Timeout     int    `json:"timeout"`  // 秒
+		Delay       int    `json:"delay"`    // 毫秒
+		TabID       string `json:"tab_id"`   // 指定标签页（可选）
+		IME         bool   `json:"ime"`      // 通过输入法组合事件输入
+		Snapshot    string `json:"snapshot"` // 输入后的快照：include（默认）、skip、defer
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidRequest"})
 		return
 	}
+	snapshot, err := executor2.ParseSnapshotMode(req.Snapshot)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidRequest", "detail": err.Error()})
+		return
+	}
 
 	executor := h.executor.WithContext(c.Request.Context())
 
 	opts := &executor2.TypeOptions{
 		Clear:       req.Clear,
 		WaitVisible: req.WaitVisible,
+		IME:         req.IME,
+		Snapshot:    snapshot,
 	}
 	if req.Timeout > 0 {
 		opts.Timeout = time.Duration(req.Timeout) * time.Second
@@ -4255,11 +5658,342 @@ func (h *Handler) ExecutorType(c *gin.Context) {
 		opts.Delay = time.Duration(req.Delay) * time.Millisecond
 	}
 
-	result, err := executor.Type(c.Request.Context(), req.Identifier, req.Text, opts)
+	result, err := executor.Type(executor2.WithTab(c.Request.Context(), req.TabID), req.Identifier, req.Text, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "error.typeFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ExecutorSelect 选择下拉框选项
+func (h *Handler) ExecutorSelect(c *gin.Context) {
+	var req struct {
+		Identifier  string `json:"identifier" binding:"required"`
+		Value       string `json:"value" binding:"required"`
+		WaitVisible bool   `json:"wait_visible"`
+		Timeout     int    `json:"timeout"`  // 秒
+		Snapshot    string `json:"snapshot"` // 选择后的快照：include（默认）、skip、defer
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidRequest"})
+		return
+	}
+	snapshot, err := executor2.ParseSnapshotMode(req.Snapshot)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidRequest", "detail": err.Error()})
+		return
+	}
+
+	executor := h.executor.WithContext(c.Request.Context())
+
+	opts := &executor2.SelectOptions{
+		WaitVisible: req.WaitVisible,
+		Snapshot:    snapshot,
+	}
+	if req.Timeout > 0 {
+		opts.Timeout = time.Duration(req.Timeout) * time.Second
+	}
+
+	result, err := executor.Select(c.Request.Context(), req.Identifier, req.Value, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "error.selectFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ExecutorChooseOption 选择自定义下拉框的选项
+func (h *Handler) ExecutorChooseOption(c *gin.Context) {
+	var req struct {
+		Identifier     string `json:"identifier" binding:"required"`
+		Value          string `json:"value" binding:"required"`
+		Exact          bool   `json:"exact"`
+		Filter         *bool  `json:"filter"`          // 默认 true
+		OptionSelector string `json:"option_selector"` // 选项元素的 CSS 选择器（可选）
+		Timeout        int    `json:"timeout"`         // 秒
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidRequest"})
+		return
+	}
+
+	executor := h.executor.WithContext(c.Request.Context())
+
+	opts := &executor2.ChooseOptionOptions{
+		Exact:          req.Exact,
+		SkipFilter:     req.Filter != nil && !*req.Filter,
+		OptionSelector: req.OptionSelector,
+	}
+	if req.Timeout > 0 {
+		opts.Timeout = time.Duration(req.Timeout) * time.Second
+	}
+
+	result, err := executor.ChooseOption(c.Request.Context(), req.Identifier, req.Value, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "error.chooseOptionFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ExecutorFillDate 填写日期输入框或日期选择器
+func (h *Handler) ExecutorFillDate(c *gin.Context) {
+	var req struct {
+		Identifier string `json:"identifier" binding:"required"`
+		Date       string `json:"date" binding:"required"` // YYYY-MM-DD、today、+7d 等
+		Strategy   string `json:"strategy"`                // auto, value, calendar
+		Format     string `json:"format"`                  // 文本输入框的日期格式，如 MM/DD/YYYY
+		Timeout    int    `json:"timeout"`                 // 秒
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidRequest"})
+		return
+	}
+
+	executor := h.executor.WithContext(c.Request.Context())
+
+	opts := &executor2.FillDateOptions{
+		Strategy: executor2.DateFillStrategy(req.Strategy),
+		Format:   req.Format,
+	}
+	if req.Timeout > 0 {
+		opts.Timeout = time.Duration(req.Timeout) * time.Second
+	}
+
+	result, err := executor.FillDate(c.Request.Context(), req.Identifier, req.Date, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "error.fillDateFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ExecutorGetText 获取元素文本
+func (h *Handler) ExecutorGetText(c *gin.Context) {
+	var req struct {
+		Identifier string `json:"identifier" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidRequest"})
+		return
+	}
+
+	executor := h.executor.WithContext(c.Request.Context())
+	result, err := executor.GetText(c.Request.Context(), req.Identifier)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "error.getTextFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ExecutorInspectElement 检查元素的属性、计算样式、可见性和可交互性
+func (h *Handler) ExecutorInspectElement(c *gin.Context) {
+	var req struct {
+		Identifier string   `json:"identifier" binding:"required"`
+		Styles     []string `json:"styles"` // 额外返回的计算样式属性
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidRequest"})
+		return
+	}
+
+	executor := h.executor.WithContext(c.Request.Context())
+	result, err := executor.InspectElement(c.Request.Context(), req.Identifier, &executor2.InspectOptions{Styles: req.Styles})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "error.inspectElementFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ExecutorAudit 审计当前页面的性能、SEO 和可访问性
+func (h *Handler) ExecutorAudit(c *gin.Context) {
+	var req struct {
+		Categories []string `json:"categories"` // 审计类别：performance、seo、accessibility，为空表示全部
+	}
+	// 请求体可选
+	_ = c.ShouldBindJSON(&req)
+
+	executor := h.executor.WithContext(c.Request.Context())
+	result, err := executor.Audit(c.Request.Context(), &executor2.AuditOptions{Categories: req.Categories})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "error.auditFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ExecutorA11yScan 使用 axe-core 扫描当前页面的可访问性违规
+func (h *Handler) ExecutorA11yScan(c *gin.Context) {
+	var req struct {
+		Tags      []string `json:"tags"`       // 只运行带这些标签的规则，如 wcag2a、wcag2aa
+		Selector  string   `json:"selector"`   // 限定扫描区域的 CSS 选择器
+		MinImpact string   `json:"min_impact"` // 最低影响等级：minor、moderate、serious、critical
+		MaxNodes  int      `json:"max_nodes"`  // 每条违规最多返回的元素数量
+	}
+	// 请求体可选
+	_ = c.ShouldBindJSON(&req)
+
+	executor := h.executor.WithContext(c.Request.Context())
+	result, err := executor.A11yScan(c.Request.Context(), &browser.A11yScanOptions{
+		Tags:      req.Tags,
+		Include:   req.Selector,
+		MinImpact: req.MinImpact,
+		MaxNodes:  req.MaxNodes,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "error.a11yScanFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ExecutorCheckLinks 检查当前页面（或站点栏目）中的失效链接
+func (h *Handler) ExecutorCheckLinks(c *gin.Context) {
+	var req struct {
+		Selector    string `json:"selector"`    // 限定链接范围的 CSS 选择器
+		SameOrigin  bool   `json:"same_origin"` // 只检查同源链接
+		Depth       int    `json:"depth"`       // 继续抓取栏目内页面的层数
+		Section     string `json:"section"`     // 栏目路径前缀
+		Concurrency int    `json:"concurrency"` // 并发请求数
+		Timeout     int    `json:"timeout"`     // 单个请求超时（秒）
+		MaxLinks    int    `json:"max_links"`   // 最多检查的链接数
+		OnlyBroken  bool   `json:"only_broken"` // 只返回失效的链接
+	}
+	// 请求体可选
+	_ = c.ShouldBindJSON(&req)
+
+	executor := h.executor.WithContext(c.Request.Context())
+	result, err := executor.CheckLinks(c.Request.Context(), &executor2.LinkCheckOptions{
+		Selector:    req.Selector,
+		SameOrigin:  req.SameOrigin,
+		Depth:       req.Depth,
+		Section:     req.Section,
+		Concurrency: req.Concurrency,
+		Timeout:     time.Duration(req.Timeout) * time.Second,
+		MaxLinks:    req.MaxLinks,
+		OnlyBroken:  req.OnlyBroken,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "error.checkLinksFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ExecutorTranslatePage 使用 LLM 翻译当前页面的可见文本
+func (h *Handler) ExecutorTranslatePage(c *gin.Context) {
+	var req struct {
+		TargetLanguage string `json:"target_language" binding:"required"` // 目标语言
+		SourceLanguage string `json:"source_language"`                    // 源语言（可选）
+		Selector       string `json:"selector"`                           // 限定翻译范围的 CSS 选择器
+		Overlay        bool   `json:"overlay"`                            // 在页面中显示译文
+		MaxSegments    int    `json:"max_segments"`                       // 最多翻译的段落数
+		LLMConfig      string `json:"llm_config"`                         // LLM 配置名称
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidRequest"})
+		return
+	}
+
+	executor := h.executor.WithContext(c.Request.Context())
+	result, err := executor.TranslatePage(c.Request.Context(), &executor2.TranslateOptions{
+		TargetLanguage: req.TargetLanguage,
+		SourceLanguage: req.SourceLanguage,
+		Selector:       req.Selector,
+		Overlay:        req.Overlay,
+		MaxSegments:    req.MaxSegments,
+		LLMConfig:      req.LLMConfig,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "error.translatePageFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ExecutorReadImageText 通过 OCR 识别元素或页面截图中的文字
+func (h *Handler) ExecutorReadImageText(c *gin.Context) {
+	var req struct {
+		Identifier   string `json:"identifier"`    // 要识别的元素，为空时识别当前视口
+		FullPage     bool   `json:"full_page"`     // 识别整个页面
+		IncludeWords bool   `json:"include_words"` // 返回单词位置和置信度
+	}
+	// 请求体可选
+	_ = c.ShouldBindJSON(&req)
+
+	executor := h.executor.WithContext(c.Request.Context())
+	result, err := executor.ReadImageText(c.Request.Context(), &executor2.ReadImageTextOptions{
+		Identifier:   req.Identifier,
+		FullPage:     req.FullPage,
+		IncludeWords: req.IncludeWords,
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.typeFailed",
-			"detail": err.Error(),
+		status := http.StatusInternalServerError
+		if errors.Is(err, executor2.ErrCaptchaNotSupported) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"error":      "error.readImageTextFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4267,13 +6001,16 @@ func (h *Handler) ExecutorType(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
-// ExecutorSelect 选择下拉框选项
-func (h *Handler) ExecutorSelect(c *gin.Context) {
+// ExecutorClickAt 在坐标处（或区域中心）点击
+func (h *Handler) ExecutorClickAt(c *gin.Context) {
 	var req struct {
-		Identifier  string `json:"identifier" binding:"required"`
-		Value       string `json:"value" binding:"required"`
-		WaitVisible bool   `json:"wait_visible"`
-		Timeout     int    `json:"timeout"` // 秒
+		X          *float64 `json:"x" binding:"required"`
+		Y          *float64 `json:"y" binding:"required"`
+		Width      float64  `json:"width"`       // 区域宽度，与 height 同时指定时点击区域中心
+		Height     float64  `json:"height"`      // 区域高度
+		Space      string   `json:"space"`       // 坐标空间：viewport、page、screenshot、fullpage_screenshot
+		Button     string   `json:"button"`      // 鼠标按键：left、right、middle
+		ClickCount int      `json:"click_count"` // 点击次数
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -4282,19 +6019,20 @@ func (h *Handler) ExecutorSelect(c *gin.Context) {
 	}
 
 	executor := h.executor.WithContext(c.Request.Context())
-
-	opts := &executor2.SelectOptions{
-		WaitVisible: req.WaitVisible,
-	}
-	if req.Timeout > 0 {
-		opts.Timeout = time.Duration(req.Timeout) * time.Second
-	}
-
-	result, err := executor.Select(c.Request.Context(), req.Identifier, req.Value, opts)
+	result, err := executor.ClickAt(c.Request.Context(), &executor2.ClickAtOptions{
+		X:          *req.X,
+		Y:          *req.Y,
+		Width:      req.Width,
+		Height:     req.Height,
+		Space:      req.Space,
+		Button:     req.Button,
+		ClickCount: req.ClickCount,
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.selectFailed",
-			"detail": err.Error(),
+			"error":      "error.clickAtFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4302,10 +6040,16 @@ func (h *Handler) ExecutorSelect(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
-// ExecutorGetText 获取元素文本
-func (h *Handler) ExecutorGetText(c *gin.Context) {
+// ExecutorDragBox 按坐标拖拽
+func (h *Handler) ExecutorDragBox(c *gin.Context) {
 	var req struct {
-		Identifier string `json:"identifier" binding:"required"`
+		FromX  *float64 `json:"from_x" binding:"required"`
+		FromY  *float64 `json:"from_y" binding:"required"`
+		ToX    *float64 `json:"to_x" binding:"required"`
+		ToY    *float64 `json:"to_y" binding:"required"`
+		Space  string   `json:"space"`   // 坐标空间
+		Steps  int      `json:"steps"`   // 中间移动次数
+		HoldMs int      `json:"hold_ms"` // 按下后停顿毫秒数
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -4314,11 +6058,20 @@ func (h *Handler) ExecutorGetText(c *gin.Context) {
 	}
 
 	executor := h.executor.WithContext(c.Request.Context())
-	result, err := executor.GetText(c.Request.Context(), req.Identifier)
+	result, err := executor.DragBox(c.Request.Context(), &executor2.DragBoxOptions{
+		FromX:  *req.FromX,
+		FromY:  *req.FromY,
+		ToX:    *req.ToX,
+		ToY:    *req.ToY,
+		Space:  req.Space,
+		Steps:  req.Steps,
+		HoldMs: req.HoldMs,
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.getTextFailed",
-			"detail": err.Error(),
+			"error":      "error.dragBoxFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4341,8 +6094,9 @@ func (h *Handler) ExecutorGetValue(c *gin.Context) {
 	result, err := executor.GetValue(c.Request.Context(), req.Identifier)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.getValueFailed",
-			"detail": err.Error(),
+			"error":      "error.getValueFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4353,9 +6107,15 @@ func (h *Handler) ExecutorGetValue(c *gin.Context) {
 // ExecutorWaitFor 等待元素
 func (h *Handler) ExecutorWaitFor(c *gin.Context) {
 	var req struct {
-		Identifier string `json:"identifier" binding:"required"`
-		State      string `json:"state"`   // visible, hidden, enabled
-		Timeout    int    `json:"timeout"` // 秒
+		Identifier  string `json:"identifier"`
+		State       string `json:"state"`        // visible, hidden, enabled
+		Timeout     int    `json:"timeout"`      // 秒
+		Text        string `json:"text"`         // 元素文本包含的内容
+		URL         string `json:"url"`          // 页面 URL 匹配的模式
+		NetworkIdle bool   `json:"network_idle"` // 等待网络空闲
+		IdleTime    int    `json:"idle_time"`    // 网络空闲持续时间（毫秒）
+		MaxInflight int    `json:"max_inflight"` // 网络空闲时允许的进行中请求数
+		Predicate   string `json:"predicate"`    // 自定义 JS 条件
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -4366,7 +6126,13 @@ func (h *Handler) ExecutorWaitFor(c *gin.Context) {
 	executor := h.executor.WithContext(c.Request.Context())
 
 	opts := &executor2.WaitForOptions{
-		State: req.State,
+		State:       req.State,
+		Text:        req.Text,
+		URL:         req.URL,
+		NetworkIdle: req.NetworkIdle,
+		IdleTime:    time.Duration(req.IdleTime) * time.Millisecond,
+		MaxInflight: req.MaxInflight,
+		Predicate:   req.Predicate,
 	}
 	if req.Timeout > 0 {
 		opts.Timeout = time.Duration(req.Timeout) * time.Second
@@ -4375,8 +6141,9 @@ func (h *Handler) ExecutorWaitFor(c *gin.Context) {
 	result, err := executor.WaitFor(c.Request.Context(), req.Identifier, opts)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.waitForFailed",
-			"detail": err.Error(),
+			"error":      "error.waitForFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4412,8 +6179,9 @@ func (h *Handler) ExecutorExtract(c *gin.Context) {
 	result, err := executor.Extract(c.Request.Context(), opts)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.extractFailed",
-			"detail": err.Error(),
+			"error":      "error.extractFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4446,8 +6214,43 @@ func (h *Handler) ExecutorHover(c *gin.Context) {
 	result, err := executor.Hover(c.Request.Context(), req.Identifier, opts)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.hoverFailed",
-			"detail": err.Error(),
+			"error":      "error.hoverFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ExecutorHoverThenClick 悬停触发元素后点击出现的目标元素
+func (h *Handler) ExecutorHoverThenClick(c *gin.Context) {
+	var req struct {
+		Trigger string `json:"trigger" binding:"required"`
+		Target  string `json:"target" binding:"required"`
+		Timeout int    `json:"timeout"` // 每次尝试等待目标出现的秒数
+		Retries int    `json:"retries"` // 最多尝试次数
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidRequest"})
+		return
+	}
+
+	executor := h.executor.WithContext(c.Request.Context())
+
+	opts := &executor2.HoverThenClickOptions{Retries: req.Retries}
+	if req.Timeout > 0 {
+		opts.Timeout = time.Duration(req.Timeout) * time.Second
+	}
+
+	result, err := executor.HoverThenClick(c.Request.Context(), req.Trigger, req.Target, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "error.hoverThenClickFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4461,8 +6264,49 @@ func (h *Handler) ExecutorScrollToBottom(c *gin.Context) {
 	result, err := executor.ScrollToBottom(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.scrollFailed",
-			"detail": err.Error(),
+			"error":      "error.scrollFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ExecutorScroll 滚动页面或指定容器（滚动到边缘、相对滚动、水平滚动）
+func (h *Handler) ExecutorScroll(c *gin.Context) {
+	var req struct {
+		Container string `json:"container"` // 可滚动容器（可选，为空时滚动页面）
+		To        string `json:"to"`        // top, bottom, left, right
+		DeltaX    int    `json:"delta_x"`   // 水平相对滚动距离（像素）
+		DeltaY    int    `json:"delta_y"`   // 垂直相对滚动距离（像素）
+		Timeout   int    `json:"timeout"`   // 秒
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidRequest"})
+		return
+	}
+
+	executor := h.executor.WithContext(c.Request.Context())
+
+	opts := &executor2.ScrollOptions{
+		Container: req.Container,
+		To:        req.To,
+		DeltaX:    req.DeltaX,
+		DeltaY:    req.DeltaY,
+	}
+	if req.Timeout > 0 {
+		opts.Timeout = time.Duration(req.Timeout) * time.Second
+	}
+
+	result, err := executor.Scroll(c.Request.Context(), opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":      "error.scrollFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4476,8 +6320,9 @@ func (h *Handler) ExecutorGoBack(c *gin.Context) {
 	result, err := executor.GoBack(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.goBackFailed",
-			"detail": err.Error(),
+			"error":      "error.goBackFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4491,8 +6336,9 @@ func (h *Handler) ExecutorGoForward(c *gin.Context) {
 	result, err := executor.GoForward(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.goForwardFailed",
-			"detail": err.Error(),
+			"error":      "error.goForwardFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4506,8 +6352,9 @@ func (h *Handler) ExecutorReload(c *gin.Context) {
 	result, err := executor.Reload(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.reloadFailed",
-			"detail": err.Error(),
+			"error":      "error.reloadFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4521,6 +6368,7 @@ func (h *Handler) ExecutorScreenshot(c *gin.Context) {
 		FullPage bool   `json:"full_page"`
 		Quality  int    `json:"quality"` // 1-100
 		Format   string `json:"format"`  // png, jpeg
+		FileName string `json:"file_name"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -4534,13 +6382,15 @@ func (h *Handler) ExecutorScreenshot(c *gin.Context) {
 		FullPage: req.FullPage,
 		Quality:  req.Quality,
 		Format:   req.Format,
+		FileName: req.FileName,
 	}
 
 	result, err := executor.Screenshot(c.Request.Context(), opts)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.screenshotFailed",
-			"detail": err.Error(),
+			"error":      "error.screenshotFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4561,10 +6411,19 @@ func (h *Handler) ExecutorEvaluate(c *gin.Context) {
 
 	executor := h.executor.WithContext(c.Request.Context())
 	result, err := executor.Evaluate(c.Request.Context(), req.Script)
+	if errors.Is(err, executor2.ErrEvaluateNotAllowed) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":      "error.evaluateNotAllowed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.evaluateFailed",
-			"detail": err.Error(),
+			"error":      "error.evaluateFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4575,11 +6434,12 @@ func (h *Handler) ExecutorEvaluate(c *gin.Context) {
 // ExecutorPressKey 按键
 func (h *Handler) ExecutorPressKey(c *gin.Context) {
 	var req struct {
-		Key   string `json:"key" binding:"required"` // enter, tab, escape, etc.
+		Key   string `json:"key" binding:"required"` // enter, tab, escape, ctrl+s, ctrl+k then p, etc.
 		Ctrl  bool   `json:"ctrl"`
 		Shift bool   `json:"shift"`
 		Alt   bool   `json:"alt"`
 		Meta  bool   `json:"meta"`
+		Mod   bool   `json:"mod"` // macOS 上为 Command，其他平台为 Ctrl
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -4594,13 +6454,15 @@ func (h *Handler) ExecutorPressKey(c *gin.Context) {
 		Shift: req.Shift,
 		Alt:   req.Alt,
 		Meta:  req.Meta,
+		Mod:   req.Mod,
 	}
 
 	result, err := executor.PressKey(c.Request.Context(), req.Key, opts)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.pressKeyFailed",
-			"detail": err.Error(),
+			"error":      "error.pressKeyFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4624,8 +6486,9 @@ func (h *Handler) ExecutorResize(c *gin.Context) {
 	result, err := executor.Resize(c.Request.Context(), req.Width, req.Height)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.resizeFailed",
-			"detail": err.Error(),
+			"error":      "error.resizeFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4639,8 +6502,9 @@ func (h *Handler) ExecutorGetPageInfo(c *gin.Context) {
 	result, err := executor.GetPageInfo(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.getPageInfoFailed",
-			"detail": err.Error(),
+			"error":      "error.getPageInfoFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4654,8 +6518,9 @@ func (h *Handler) ExecutorGetPageContent(c *gin.Context) {
 	result, err := executor.GetPageContent(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.getPageContentFailed",
-			"detail": err.Error(),
+			"error":      "error.getPageContentFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4669,8 +6534,9 @@ func (h *Handler) ExecutorGetPageText(c *gin.Context) {
 	result, err := executor.GetPageText(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.getPageTextFailed",
-			"detail": err.Error(),
+			"error":      "error.getPageTextFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4684,16 +6550,57 @@ func (h *Handler) ExecutorGetAccessibilitySnapshot(c *gin.Context) {
 	snapshot, err := executor.GetAccessibilitySnapshot(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.getAccessibilitySnapshotFailed",
-			"detail": err.Error(),
+			"error":      "error.getAccessibilitySnapshotFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success":  true,
-		"snapshot": snapshot.SerializeToSimpleText(),
-	})
+	// ?geometry=true 时附加元素边界框和视口可见性
+	var geometry *executor2.SnapshotGeometry
+	if c.Query("geometry") == "true" {
+		geometry, err = executor.GetSnapshotGeometry(c.Request.Context(), snapshot)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":      "error.getAccessibilitySnapshotFailed",
+				"detail":     err.Error(),
+				"error_code": browser.ClassifyError(err),
+			})
+			return
+		}
+	}
+
+	// ?cursor= 或 ?max_bytes= 时分页返回，next_cursor 为空表示已是最后一页
+	cursor, maxBytesArg := c.Query("cursor"), c.Query("max_bytes")
+	if cursor == "" && maxBytesArg == "" {
+		response := gin.H{
+			"success":  true,
+			"snapshot": snapshot.SerializeWithGeometry(geometry),
+		}
+		if geometry != nil {
+			response["geometry"] = geometry
+		}
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	maxBytes, _ := strconv.Atoi(maxBytesArg)
+	chunk, err := snapshot.SerializeChunk(geometry, cursor, maxBytes)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams", "detail": err.Error()})
+		return
+	}
+	response := gin.H{
+		"success":     true,
+		"snapshot":    chunk.Text,
+		"next_cursor": chunk.NextCursor,
+		"restarted":   chunk.Restarted,
+	}
+	if geometry != nil {
+		response["geometry"] = geometry
+	}
+	c.JSON(http.StatusOK, response)
 }
 
 // ExecutorGetClickableElements 获取可点击元素
@@ -4702,8 +6609,9 @@ func (h *Handler) ExecutorGetClickableElements(c *gin.Context) {
 	elements, err := executor.GetClickableElements(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.getClickableElementsFailed",
-			"detail": err.Error(),
+			"error":      "error.getClickableElementsFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4721,8 +6629,9 @@ func (h *Handler) ExecutorGetInputElements(c *gin.Context) {
 	elements, err := executor.GetInputElements(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.getInputElementsFailed",
-			"detail": err.Error(),
+			"error":      "error.getInputElementsFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4749,8 +6658,9 @@ func (h *Handler) ExecutorBatch(c *gin.Context) {
 	result, err := executor.ExecuteBatch(c.Request.Context(), req.Operations)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.batchExecutionFailed",
-			"detail": err.Error(),
+			"error":      "error.batchExecutionFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4763,6 +6673,7 @@ func (h *Handler) ExecutorTabs(c *gin.Context) {
 	var req struct {
 		Action string `json:"action" binding:"required"` // list, new, switch, close
 		URL    string `json:"url"`                       // for new action
+		TabID  string `json:"tab_id"`                    // for switch/close action (preferred)
 		Index  int    `json:"index"`                     // for switch/close action
 	}
 
@@ -4776,14 +6687,16 @@ func (h *Handler) ExecutorTabs(c *gin.Context) {
 	opts := &executor2.TabsOptions{
 		Action: executor2.TabsAction(req.Action),
 		URL:    req.URL,
+		TabID:  req.TabID,
 		Index:  req.Index,
 	}
 
 	result, err := executor.Tabs(c.Request.Context(), opts)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.tabsOperationFailed",
-			"detail": err.Error(),
+			"error":      "error.tabsOperationFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4817,8 +6730,9 @@ func (h *Handler) ExecutorFillForm(c *gin.Context) {
 	result, err := executor.FillForm(c.Request.Context(), opts)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.fillFormFailed",
-			"detail": err.Error(),
+			"error":      "error.fillFormFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4826,14 +6740,99 @@ func (h *Handler) ExecutorFillForm(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+// ExecutorCollect 滚动或翻页采集列表条目，stream=true 时以 SSE 流式返回部分结果
+func (h *Handler) ExecutorCollect(c *gin.Context) {
+	var req struct {
+		ItemSelector  string   `json:"item_selector" binding:"required"`
+		Fields        []string `json:"fields"`
+		Mode          string   `json:"mode"` // scroll 或 next_page
+		NextSelector  string   `json:"next_selector"`
+		DedupeKey     string   `json:"dedupe_key"`
+		MaxItems      int      `json:"max_items"`
+		MaxDuration   int      `json:"max_duration"` // 秒
+		MaxIdleRounds int      `json:"max_idle_rounds"`
+		Stream        bool     `json:"stream"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidRequest"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	executor := h.executor.WithContext(ctx)
+
+	opts := &executor2.CollectOptions{
+		ItemSelector:  req.ItemSelector,
+		Fields:        req.Fields,
+		Mode:          executor2.CollectMode(req.Mode),
+		NextSelector:  req.NextSelector,
+		DedupeKey:     req.DedupeKey,
+		MaxItems:      req.MaxItems,
+		MaxIdleRounds: req.MaxIdleRounds,
+	}
+	if req.MaxDuration > 0 {
+		opts.MaxDuration = time.Duration(req.MaxDuration) * time.Second
+	}
+
+	if !req.Stream {
+		result, err := executor.Collect(ctx, opts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":      "error.collectFailed",
+				"detail":     err.Error(),
+				"error_code": browser.ClassifyError(err),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, result)
+		return
+	}
+
+	flusher, ok := c.Writer.(http.Flusher)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.streamingNotSupported"})
+		return
+	}
+
+	// 设置 SSE 响应头
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // 禁用 nginx 缓冲
+
+	send := func(event gin.H) {
+		data, err := json.Marshal(event)
+		if err != nil {
+			logger.Warn(ctx, "Failed to serialize collect event: %v", err)
+			return
+		}
+		// SSE 格式: data: {json}\n\n
+		fmt.Fprintf(c.Writer, "data: %s\n\n", string(data))
+		flusher.Flush()
+	}
+
+	opts.OnItems = func(items []map[string]interface{}, total int) {
+		send(gin.H{"type": "items", "items": items, "total": total})
+	}
+
+	result, err := executor.Collect(ctx, opts)
+	if err != nil {
+		send(gin.H{"type": "error", "error": "error.collectFailed", "detail": err.Error()})
+		return
+	}
+	send(gin.H{"type": "done", "result": result})
+}
+
 // ExecutorConsoleMessages 获取控制台消息
 func (h *Handler) ExecutorConsoleMessages(c *gin.Context) {
 	executor := h.executor.WithContext(c.Request.Context())
 	result, err := executor.GetConsoleMessages(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.getConsoleMessagesFailed",
-			"detail": err.Error(),
+			"error":      "error.getConsoleMessagesFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4847,8 +6846,9 @@ func (h *Handler) ExecutorNetworkRequests(c *gin.Context) {
 	result, err := executor.GetNetworkRequests(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.getNetworkRequestsFailed",
-			"detail": err.Error(),
+			"error":      "error.getNetworkRequestsFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4872,8 +6872,9 @@ func (h *Handler) ExecutorHandleDialog(c *gin.Context) {
 	result, err := executor.HandleDialog(c.Request.Context(), req.Accept, req.Text)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.handleDialogFailed",
-			"detail": err.Error(),
+			"error":      "error.handleDialogFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4897,8 +6898,9 @@ func (h *Handler) ExecutorFileUpload(c *gin.Context) {
 	result, err := executor.FileUpload(c.Request.Context(), req.Identifier, req.FilePaths)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.fileUploadFailed",
-			"detail": err.Error(),
+			"error":      "error.fileUploadFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4909,8 +6911,11 @@ func (h *Handler) ExecutorFileUpload(c *gin.Context) {
 // ExecutorDrag 拖拽元素
 func (h *Handler) ExecutorDrag(c *gin.Context) {
 	var req struct {
-		FromIdentifier string `json:"from_identifier" binding:"required"`
-		ToIdentifier   string `json:"to_identifier" binding:"required"`
+		FromIdentifier string            `json:"from_identifier"`
+		ToIdentifier   string            `json:"to_identifier" binding:"required"`
+		Mode           string            `json:"mode"`  // mouse（默认）或 html5
+		Data           map[string]string `json:"data"`  // html5 模式下的 DataTransfer 数据
+		Files          []string          `json:"files"` // html5 模式下拖放的本地文件
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -4919,11 +6924,16 @@ func (h *Handler) ExecutorDrag(c *gin.Context) {
 	}
 
 	executor := h.executor.WithContext(c.Request.Context())
-	result, err := executor.Drag(c.Request.Context(), req.FromIdentifier, req.ToIdentifier)
+	result, err := executor.Drag(c.Request.Context(), req.FromIdentifier, req.ToIdentifier, &executor2.DragOptions{
+		Mode:  executor2.DragMode(req.Mode),
+		Data:  req.Data,
+		Files: req.Files,
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.dragFailed",
-			"detail": err.Error(),
+			"error":      "error.dragFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -4937,8 +6947,9 @@ func (h *Handler) ExecutorClosePage(c *gin.Context) {
 	result, err := executor.ClosePage(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "error.closePageFailed",
-			"detail": err.Error(),
+			"error":      "error.closePageFailed",
+			"detail":     err.Error(),
+			"error_code": browser.ClassifyError(err),
 		})
 		return
 	}
@@ -5178,9 +7189,12 @@ func generateExecutorSkillMD(host string) string {
 	sb.WriteString("- `POST /click` - Click element (supports: RefID `@e1`, CSS selector, XPath, text content)\n")
 	sb.WriteString("- `POST /type` - Type text into input (supports: RefID `@e3`, CSS selector, XPath)\n")
 	sb.WriteString("- `POST /select` - Select dropdown option\n")
+	sb.WriteString("- `POST /fill-date` - Fill a date input or pick the date from its calendar (`date`: YYYY-MM-DD, today, +7d; `strategy`: auto/value/calendar)\n")
+	sb.WriteString("- `POST /choose-option` - Choose an option from a custom dropdown (Select2, Ant Design, MUI, react-select...)\n")
 	sb.WriteString("- `POST /hover` - Hover over element\n")
+	sb.WriteString("- `POST /hover-then-click` - Hover a trigger and click the revealed menu item\n")
 	sb.WriteString("- `POST /wait` - Wait for element state (visible, hidden, enabled)\n")
-	sb.WriteString("- `POST /press-key` - Press keyboard key (Enter, Tab, Ctrl+S, etc.)\n\n")
+	sb.WriteString("- `POST /press-key` - Press keyboard key, shortcut or chain (Enter, ctrl+s, mod+k, \"ctrl+k then p\"; mod = Cmd on macOS, Ctrl elsewhere)\n\n")
 
 	// 数据提取类
 	sb.WriteString("### Data Extraction\n")
@@ -5195,7 +7209,8 @@ func generateExecutorSkillMD(host string) string {
 	sb.WriteString("### Page Analysis\n")
 	sb.WriteString("- `GET /snapshot` - Get accessibility snapshot (⭐ **ALWAYS call after navigation**)\n")
 	sb.WriteString("- `GET /clickable-elements` - Get all clickable elements\n")
-	sb.WriteString("- `GET /input-elements` - Get all input elements\n\n")
+	sb.WriteString("- `GET /input-elements` - Get all input elements\n")
+	sb.WriteString("- `POST /inspect` - Inspect element styles, visibility and click interception\n\n")
 
 	// 高级功能类
 	sb.WriteString("### Advanced\n")
@@ -5203,6 +7218,7 @@ func generateExecutorSkillMD(host string) string {
 	sb.WriteString("- `POST /evaluate` - Execute JavaScript code\n")
 	sb.WriteString("- `POST /batch` - Execute multiple operations in sequence\n")
 	sb.WriteString("- `POST /scroll-to-bottom` - Scroll to page bottom\n")
+	sb.WriteString("- `POST /scroll` - Scroll the page or a container (`container`) to an edge (`to`: top/bottom/left/right) or by `delta_x`/`delta_y` pixels\n")
 	sb.WriteString("- `POST /resize` - Resize browser window\n")
 	sb.WriteString("- `POST /tabs` - Manage browser tabs (list, new, switch, close)\n")
 	sb.WriteString("- `POST /fill-form` - Intelligently fill multiple form fields at once\n\n")
@@ -5213,7 +7229,7 @@ func generateExecutorSkillMD(host string) string {
 	sb.WriteString("- `GET /network-requests` - Get network requests made by the page\n")
 	sb.WriteString("- `POST /handle-dialog` - Configure JavaScript dialog (alert, confirm, prompt) handling\n")
 	sb.WriteString("- `POST /file-upload` - Upload files to input elements\n")
-	sb.WriteString("- `POST /drag` - Drag and drop elements\n")
+	sb.WriteString("- `POST /drag` - Drag and drop elements (mode `html5` for HTML5 dnd libraries and file drops)\n")
 	sb.WriteString("- `POST /close-page` - Close the current page/tab\n\n")
 
 	// 元素定位方式
@@ -5471,6 +7487,20 @@ func (h *Handler) CreateBrowserInstance(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidRequest", "detail": err.Error()})
 		return
 	}
+	if err := instance.Window.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidWindowPlacement", "detail": err.Error()})
+		return
+	}
+	if err := instance.LeakProtection.Validate(instance.Proxy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidLeakProtection", "detail": err.Error()})
+		return
+	}
+	for _, path := range instance.Extensions {
+		if err := browser.ValidateExtensionDir(path); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidExtension", "detail": err.Error()})
+			return
+		}
+	}
 
 	// 生成ID
 	if instance.ID == "" {
@@ -5547,6 +7577,20 @@ func (h *Handler) UpdateBrowserInstance(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidRequest", "detail": err.Error()})
 		return
 	}
+	if err := instance.Window.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidWindowPlacement", "detail": err.Error()})
+		return
+	}
+	if err := instance.LeakProtection.Validate(instance.Proxy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidLeakProtection", "detail": err.Error()})
+		return
+	}
+	for _, path := range instance.Extensions {
+		if err := browser.ValidateExtensionDir(path); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidExtension", "detail": err.Error()})
+			return
+		}
+	}
 
 	instance.ID = id
 	if err := h.db.UpdateBrowserInstance(id, &instance); err != nil {
@@ -5611,6 +7655,32 @@ func (h *Handler) StopBrowserInstance(c *gin.Context) {
 	})
 }
 
+// SetBrowserInstanceHeadless 重启运行中的实例并切换 Headless 模式（保留用户数据目录和 Cookie）
+func (h *Handler) SetBrowserInstanceHeadless(c *gin.Context) {
+	id := c.Param("id")
+
+	var req struct {
+		Headless *bool `json:"headless"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.Headless == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidRequest", "detail": "headless is required"})
+		return
+	}
+
+	ctx := context.Background()
+	result, err := h.browserManager.SetInstanceHeadless(ctx, id, *req.Headless)
+	if err != nil {
+		logger.Error(ctx, "Failed to switch headless mode: %s", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.restartBrowserFailed", "detail": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "success.browserRestarted",
+		"result":  result,
+	})
+}
+
 // SwitchBrowserInstance 切换当前活动实例
 func (h *Handler) SwitchBrowserInstance(c *gin.Context) {
 	id := c.Param("id")
@@ -5639,6 +7709,78 @@ func (h *Handler) GetCurrentBrowserInstance(c *gin.Context) {
 	})
 }
 
+// thumbnailUpgrader 缩略图流的 WebSocket 升级器（只接受同源或 allowed_origins 中的来源）
+func (h *Handler) thumbnailUpgrader() *websocket.Upgrader {
+	return &websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool { return checkWebSocketOrigin(h.config, r) },
+	}
+}
+
+// parseThumbnailOptions 从查询参数解析缩略图选项：width（最大宽度）、quality（JPEG 质量）
+func parseThumbnailOptions(c *gin.Context) browser.ThumbnailOptions {
+	opts := browser.ThumbnailOptions{}
+	if w, err := strconv.Atoi(c.Query("width")); err == nil {
+		opts.MaxWidth = w
+	}
+	if q, err := strconv.Atoi(c.Query("quality")); err == nil {
+		opts.Quality = q
+	}
+	return opts
+}
+
+// GetInstanceThumbnail 获取实例活动页面的单张缩略图（image/jpeg）
+func (h *Handler) GetInstanceThumbnail(c *gin.Context) {
+	thumb, err := h.browserManager.CaptureThumbnail(c.Request.Context(), c.Param("id"), parseThumbnailOptions(c))
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "error.thumbnailFailed", "detail": err.Error()})
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "image/jpeg", thumb.Data)
+}
+
+// StreamInstanceThumbnails 通过 WebSocket 推送实例活动页面的低帧率缩略图
+// 查询参数：fps（0.1-2，默认 1）、width、quality；画面未变化时不推送
+func (h *Handler) StreamInstanceThumbnails(c *gin.Context) {
+	instanceID := c.Param("id")
+	fps := 1.0
+	if f, err := strconv.ParseFloat(c.Query("fps"), 64); err == nil && f > 0 {
+		fps = min(max(f, 0.1), 2)
+	}
+	opts := parseThumbnailOptions(c)
+
+	conn, err := h.thumbnailUpgrader().Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logger.Warn(c.Request.Context(), "Failed to upgrade thumbnail stream: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	// 读取客户端消息以响应关闭帧，连接断开时停止推送
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	interval := time.Duration(float64(time.Second) / fps)
+	err = h.browserManager.StreamThumbnails(ctx, instanceID, interval, opts, func(thumb *browser.Thumbnail) error {
+		conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+		return conn.WriteJSON(gin.H{"type": "thumbnail", "thumbnail": thumb})
+	})
+	if err != nil && ctx.Err() == nil {
+		conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+		_ = conn.WriteJSON(gin.H{"type": "error", "error": "error.thumbnailFailed", "detail": err.Error()})
+	}
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+}
+
 // SetScheduler 设置调度器
 func (h *Handler) SetScheduler(scheduler interface{}) {
 	h.scheduler = scheduler
@@ -5646,6 +7788,14 @@ func (h *Handler) SetScheduler(scheduler interface{}) {
 
 // ================== Scheduled Tasks API ==================
 
+// validateWebhookURL 检查 Webhook 地址：必须是 http(s) 地址，且不能指向内网
+func (h *Handler) validateWebhookURL(ctx context.Context, webhookURL string) error {
+	if !strings.HasPrefix(webhookURL, "http://") && !strings.HasPrefix(webhookURL, "https://") {
+		return fmt.Errorf("webhook url must start with http:// or https://")
+	}
+	return h.browserManager.NetworkGuard().Check(ctx, webhookURL)
+}
+
 // CreateScheduledTask 创建定时任务
 func (h *Handler) CreateScheduledTask(c *gin.Context) {
 	var task models.ScheduledTask
@@ -5681,14 +7831,46 @@ func (h *Handler) CreateScheduledTask(c *gin.Context) {
 	}
 
 	// 根据执行类型验证配置
-	if task.ExecutionType == models.ExecutionTypeScript && task.ScriptID == "" {
+	if (task.ExecutionType == models.ExecutionTypeScript || task.ExecutionType == models.ExecutionTypeCrawl) && task.ScriptID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "error.scriptIdRequired"})
 		return
 	}
+	if task.ExecutionType == models.ExecutionTypeCrawl && len(task.CrawlURLs) == 0 && task.CrawlSitemapURL == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.crawlUrlsRequired"})
+		return
+	}
 	if task.ExecutionType == models.ExecutionTypeAgent && task.AgentPrompt == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "error.agentPromptRequired"})
 		return
 	}
+	if task.ExecutionType == models.ExecutionTypeScreenshot && len(task.ScreenshotURLs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.screenshotUrlsRequired"})
+		return
+	}
+	if task.ExecutionType == models.ExecutionTypeHealthCheck {
+		for _, id := range task.HealthCheckScriptIDs {
+			if _, err := h.db.GetScript(id); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "error.scriptNotFound"})
+				return
+			}
+		}
+	}
+	if task.ExecutionType == models.ExecutionTypeMonitor {
+		if task.MonitorURL == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.monitorUrlRequired"})
+			return
+		}
+		if task.MonitorThreshold < 0 || task.MonitorThreshold > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidMonitorThreshold"})
+			return
+		}
+		if task.MonitorWebhookURL != "" {
+			if err := h.validateWebhookURL(c.Request.Context(), task.MonitorWebhookURL); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidWebhookUrl", "detail": err.Error()})
+				return
+			}
+		}
+	}
 
 	// 如果有脚本ID，加载脚本名称
 	if task.ScriptID != "" {
@@ -5821,14 +8003,46 @@ func (h *Handler) UpdateScheduledTask(c *gin.Context) {
 	}
 
 	// 根据执行类型验证配置
-	if task.ExecutionType == models.ExecutionTypeScript && task.ScriptID == "" {
+	if (task.ExecutionType == models.ExecutionTypeScript || task.ExecutionType == models.ExecutionTypeCrawl) && task.ScriptID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "error.scriptIdRequired"})
 		return
 	}
+	if task.ExecutionType == models.ExecutionTypeCrawl && len(task.CrawlURLs) == 0 && task.CrawlSitemapURL == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.crawlUrlsRequired"})
+		return
+	}
 	if task.ExecutionType == models.ExecutionTypeAgent && task.AgentPrompt == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "error.agentPromptRequired"})
 		return
 	}
+	if task.ExecutionType == models.ExecutionTypeScreenshot && len(task.ScreenshotURLs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.screenshotUrlsRequired"})
+		return
+	}
+	if task.ExecutionType == models.ExecutionTypeHealthCheck {
+		for _, id := range task.HealthCheckScriptIDs {
+			if _, err := h.db.GetScript(id); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "error.scriptNotFound"})
+				return
+			}
+		}
+	}
+	if task.ExecutionType == models.ExecutionTypeMonitor {
+		if task.MonitorURL == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.monitorUrlRequired"})
+			return
+		}
+		if task.MonitorThreshold < 0 || task.MonitorThreshold > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidMonitorThreshold"})
+			return
+		}
+		if task.MonitorWebhookURL != "" {
+			if err := h.validateWebhookURL(c.Request.Context(), task.MonitorWebhookURL); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidWebhookUrl", "detail": err.Error()})
+				return
+			}
+		}
+	}
 
 	// 如果有脚本ID，加载脚本名称
 	if task.ScriptID != "" {
@@ -5893,6 +8107,16 @@ func (h *Handler) DeleteScheduledTask(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.deleteTaskFailed", "details": err.Error()})
 		return
 	}
+	if err := h.db.DeleteMonitorSnapshot(id); err != nil {
+		logger.Warn(c.Request.Context(), "Failed to delete monitor snapshot for task %s: %v", id, err)
+	}
+	if shots, err := h.db.ListPageScreenshots(id, ""); err == nil {
+		for _, shot := range shots {
+			if err := scheduler.RemovePageScreenshot(h.db, &shot); err != nil {
+				logger.Warn(c.Request.Context(), "Failed to delete screenshot %s: %v", shot.ID, err)
+			}
+		}
+	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "success.taskDeleted"})
 }
@@ -5936,6 +8160,47 @@ func (h *Handler) ToggleScheduledTask(c *gin.Context) {
 
 // ================== Task Executions API ==================
 
+// ListTaskScreenshots 获取截图存档任务的截图时间线（按 URL 分组，最新的在前）
+func (h *Handler) ListTaskScreenshots(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := h.db.GetScheduledTask(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.taskNotFound"})
+		return
+	}
+
+	shots, err := h.db.ListPageScreenshots(id, c.Query("url"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.getScreenshotsFailed", "details": err.Error()})
+		return
+	}
+
+	timeline := make(map[string][]models.PageScreenshot)
+	for _, shot := range shots {
+		timeline[shot.URL] = append(timeline[shot.URL], shot)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"screenshots": shots,
+		"timeline":    timeline,
+		"total":       len(shots),
+	})
+}
+
+// GetTaskScreenshotImage 获取截图存档中的图片文件
+func (h *Handler) GetTaskScreenshotImage(c *gin.Context) {
+	shot, err := h.db.GetPageScreenshot(c.Param("shot_id"))
+	if err != nil || shot.TaskID != c.Param("id") {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.screenshotNotFound"})
+		return
+	}
+	if _, err := os.Stat(shot.Path); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.screenshotNotFound"})
+		return
+	}
+
+	c.File(shot.Path)
+}
+
 // ListTaskExecutions 列出任务执行记录
 func (h *Handler) ListTaskExecutions(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
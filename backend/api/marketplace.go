@@ -0,0 +1,254 @@
+package api
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/browserwing/browserwing/config"
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/services/marketplace"
+	"github.com/gin-gonic/gin"
+)
+
+// marketplaceRequestTimeout 访问脚本市场仓库的超时
+const marketplaceRequestTimeout = time.Minute
+
+// marketplaceInfo 脚本市场配置概要（不包含私钥和 Token）
+type marketplaceInfo struct {
+	RegistryURL      string   `json:"registry_url"`
+	TrustedKeys      []string `json:"trusted_keys"`
+	AllowUnsigned    bool     `json:"allow_unsigned"`
+	SigningPublicKey string   `json:"signing_public_key,omitempty"` // 本实例发布脚本包使用的公钥，提供给其他实例加入可信列表
+	CanPublish       bool     `json:"can_publish"`
+}
+
+// marketplaceBundle 仓库中的脚本包及本地安装状态
+type marketplaceBundle struct {
+	models.BundleIndexEntry
+	InstalledVersion string `json:"installed_version,omitempty"`
+}
+
+// installBundleRequest 安装脚本包：从仓库安装时指定 id，安装导出的脚本包文件时传入 bundle
+type installBundleRequest struct {
+	ID     string               `json:"id"`
+	Bundle *models.SignedBundle `json:"bundle"`
+}
+
+// packBundleRequest 导出或发布脚本包
+type packBundleRequest struct {
+	marketplace.PackOptions
+	ScriptIDs []string `json:"script_ids" binding:"required"`
+}
+
+// marketplaceConfig 脚本市场配置，未配置时返回空配置
+func (h *Handler) marketplaceConfig() *config.MarketplaceConfig {
+	if h.config == nil || h.config.Marketplace == nil {
+		return &config.MarketplaceConfig{}
+	}
+	return h.config.Marketplace
+}
+
+// marketplaceRegistry 创建仓库客户端，未配置仓库地址时返回错误码
+func (h *Handler) marketplaceRegistry() (*marketplace.Registry, string, error) {
+	cfg := h.marketplaceConfig()
+	if cfg.RegistryURL == "" {
+		return nil, "error.marketplaceNotConfigured", errors.New("marketplace.registry_url is not configured")
+	}
+	registry, err := marketplace.NewRegistry(cfg.RegistryURL, cfg.PublishToken)
+	if err != nil {
+		return nil, "error.marketplaceNotConfigured", err
+	}
+	return registry, "", nil
+}
+
+// GetMarketplaceInfo 获取脚本市场配置概要
+func (h *Handler) GetMarketplaceInfo(c *gin.Context) {
+	cfg := h.marketplaceConfig()
+	info := marketplaceInfo{
+		RegistryURL:   cfg.RegistryURL,
+		TrustedKeys:   cfg.TrustedKeys,
+		AllowUnsigned: cfg.AllowUnsigned,
+		CanPublish:    cfg.RegistryURL != "" && cfg.PublishToken != "",
+	}
+	if info.TrustedKeys == nil {
+		info.TrustedKeys = []string{}
+	}
+	if key, err := marketplace.ParseSigningKey(cfg.SigningKey); err == nil && key != nil {
+		info.SigningPublicKey = base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))
+	}
+	c.JSON(http.StatusOK, gin.H{"data": info})
+}
+
+// ListMarketplaceBundles 列出仓库中的脚本包，并标记已安装的版本
+func (h *Handler) ListMarketplaceBundles(c *gin.Context) {
+	registry, code, err := h.marketplaceRegistry()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": code, "detail": err.Error()})
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), marketplaceRequestTimeout)
+	defer cancel()
+	entries, err := registry.Index(ctx)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "error.registryRequestFailed", "detail": err.Error()})
+		return
+	}
+
+	installed, err := h.db.ListInstalledBundles()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.getInstalledBundlesFailed"})
+		return
+	}
+	versions := make(map[string]string, len(installed))
+	for _, bundle := range installed {
+		versions[bundle.ID] = bundle.Version
+	}
+
+	bundles := make([]marketplaceBundle, 0, len(entries))
+	for _, entry := range entries {
+		bundles = append(bundles, marketplaceBundle{BundleIndexEntry: entry, InstalledVersion: versions[entry.ID]})
+	}
+	c.JSON(http.StatusOK, gin.H{"data": bundles, "registry": registry.URL()})
+}
+
+// InstallMarketplaceBundle 安装或升级脚本包
+// 签名无效的脚本包始终拒绝；未签名或签名者不在可信列表中的脚本包只在 allow_unsigned 开启时安装
+func (h *Handler) InstallMarketplaceBundle(c *gin.Context) {
+	var req installBundleRequest
+	if err := c.ShouldBindJSON(&req); err != nil || (req.ID == "") == (req.Bundle == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams", "detail": "either id or bundle is required"})
+		return
+	}
+
+	signed, source := req.Bundle, ""
+	if req.ID != "" {
+		registry, code, err := h.marketplaceRegistry()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": code, "detail": err.Error()})
+			return
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), marketplaceRequestTimeout)
+		defer cancel()
+		entry, err := registry.Find(ctx, req.ID)
+		if err == nil {
+			signed, source, err = registry.Download(ctx, entry)
+		}
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "error.registryRequestFailed", "detail": err.Error()})
+			return
+		}
+	}
+
+	cfg := h.marketplaceConfig()
+	verification, err := marketplace.Verify(signed, cfg.TrustedKeys)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidBundle", "detail": err.Error()})
+		return
+	}
+	if req.ID != "" && verification.Bundle.ID != req.ID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidBundle", "detail": "the downloaded bundle has id " + verification.Bundle.ID})
+		return
+	}
+	if !verification.Trusted && !cfg.AllowUnsigned {
+		c.JSON(http.StatusForbidden, gin.H{"error": "error.untrustedBundle", "detail": marketplace.ErrUntrusted.Error()})
+		return
+	}
+
+	installed, err := marketplace.Install(h.db, verification, source)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.installBundleFailed", "detail": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": installed})
+}
+
+// ListInstalledBundles 列出已安装的脚本包
+func (h *Handler) ListInstalledBundles(c *gin.Context) {
+	bundles, err := h.db.ListInstalledBundles()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.getInstalledBundlesFailed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": bundles})
+}
+
+// UninstallBundle 卸载脚本包，删除安装时创建的脚本
+func (h *Handler) UninstallBundle(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := h.db.GetInstalledBundle(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.bundleNotFound"})
+		return
+	}
+	if err := marketplace.Uninstall(h.db, id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.uninstallBundleFailed", "detail": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success.bundleUninstalled"})
+}
+
+// packBundle 按请求打包并签名脚本包，返回 HTTP 状态码、错误码和详情
+func (h *Handler) packBundle(c *gin.Context) (*models.SignedBundle, int, string, string) {
+	var req packBundleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, http.StatusBadRequest, "error.invalidParams", err.Error()
+	}
+
+	scripts := make([]*models.Script, 0, len(req.ScriptIDs))
+	for _, id := range req.ScriptIDs {
+		script, err := h.db.GetScript(id)
+		if err != nil {
+			return nil, http.StatusNotFound, "error.scriptNotFound", id
+		}
+		scripts = append(scripts, script)
+	}
+	bundle, err := marketplace.Pack(scripts, req.PackOptions)
+	if err != nil {
+		return nil, http.StatusBadRequest, "error.invalidParams", err.Error()
+	}
+
+	key, err := marketplace.ParseSigningKey(h.marketplaceConfig().SigningKey)
+	if err != nil {
+		return nil, http.StatusInternalServerError, "error.marketplaceNotConfigured", err.Error()
+	}
+	signed, err := marketplace.Sign(bundle, key)
+	if err != nil {
+		return nil, http.StatusInternalServerError, "error.exportBundleFailed", err.Error()
+	}
+	return signed, 0, "", ""
+}
+
+// ExportBundle 把脚本导出为脚本包（配置了签名私钥时签名），可以直接分享文件或在其他实例上安装
+func (h *Handler) ExportBundle(c *gin.Context) {
+	signed, status, code, detail := h.packBundle(c)
+	if signed == nil {
+		c.JSON(status, gin.H{"error": code, "detail": detail})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": signed})
+}
+
+// PublishBundle 把脚本打包、签名并发布到仓库
+func (h *Handler) PublishBundle(c *gin.Context) {
+	registry, code, err := h.marketplaceRegistry()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": code, "detail": err.Error()})
+		return
+	}
+	signed, status, code, detail := h.packBundle(c)
+	if signed == nil {
+		c.JSON(status, gin.H{"error": code, "detail": detail})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), marketplaceRequestTimeout)
+	defer cancel()
+	if err := registry.Publish(ctx, signed); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "error.registryRequestFailed", "detail": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success.bundlePublished", "data": signed})
+}
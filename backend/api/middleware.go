@@ -1,6 +1,9 @@
 package api
 
 import (
+	"sync"
+
+	executor2 "github.com/browserwing/browserwing/executor"
 	"github.com/browserwing/browserwing/pkg/logger"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -25,3 +28,70 @@ func TraceIDMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// targetLock 执行目标的互斥锁，refs 为持有或等待该锁的请求数
+type targetLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// executorTargetLocks 各执行目标的互斥锁（key 为实例、标签页和会话），同一目标上的操作依次执行
+// 没有请求持有或等待时删除，避免随标签页、会话 ID 不断增长
+var executorTargetLocks = struct {
+	sync.Mutex
+	byKey map[string]*targetLock
+}{byKey: make(map[string]*targetLock)}
+
+// lockExecutorTarget 获取执行目标的锁，返回释放函数
+func lockExecutorTarget(key string) func() {
+	executorTargetLocks.Lock()
+	lock, ok := executorTargetLocks.byKey[key]
+	if !ok {
+		lock = &targetLock{}
+		executorTargetLocks.byKey[key] = lock
+	}
+	lock.refs++
+	executorTargetLocks.Unlock()
+
+	lock.mu.Lock()
+	return func() {
+		lock.mu.Unlock()
+		executorTargetLocks.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(executorTargetLocks.byKey, key)
+		}
+		executorTargetLocks.Unlock()
+	}
+}
+
+// ExecutorTargetMiddleware 解析 Executor 操作的目标（实例、标签页、会话）并写入请求 context，
+// 同时串行化同一目标上的操作，避免多个外部调用方并发操作同一个标签页时步骤相互穿插。
+// 目标通过查询参数 instance_id、tab_id、session_id 或请求头 X-Instance-ID、X-Tab-ID、X-Session-ID 指定，
+// 都未指定时作用于当前实例的活动页面，不做串行化；请求体中的 tab_id 优先于这里指定的标签页
+func ExecutorTargetMiddleware() gin.HandlerFunc {
+	param := func(c *gin.Context, query, header string) string {
+		if v := c.Query(query); v != "" {
+			return v
+		}
+		return c.GetHeader(header)
+	}
+
+	return func(c *gin.Context) {
+		instanceID := param(c, "instance_id", "X-Instance-ID")
+		tabID := param(c, "tab_id", "X-Tab-ID")
+		sessionID := param(c, "session_id", "X-Session-ID")
+
+		ctx := executor2.WithInstance(c.Request.Context(), instanceID)
+		ctx = executor2.WithTab(ctx, tabID)
+		ctx = executor2.WithSession(ctx, sessionID)
+		c.Request = c.Request.WithContext(ctx)
+
+		if instanceID != "" || tabID != "" || sessionID != "" {
+			unlock := lockExecutorTarget(instanceID + "|" + tabID + "|" + sessionID)
+			defer unlock()
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLockExecutorTarget(t *testing.T) {
+	unlock := lockExecutorTarget("i1|t1|")
+
+	acquired := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		release := lockExecutorTarget("i1|t1|")
+		close(acquired)
+		release()
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second request on the same target should wait")
+	case <-time.After(50 * time.Millisecond):
+	}
+	// 其他目标不受影响
+	lockExecutorTarget("i1|t2|")()
+
+	unlock()
+	wg.Wait()
+
+	executorTargetLocks.Lock()
+	defer executorTargetLocks.Unlock()
+	if len(executorTargetLocks.byKey) != 0 {
+		t.Errorf("expected released locks to be removed, got %d", len(executorTargetLocks.byKey))
+	}
+}
@@ -0,0 +1,958 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/browserwing/browserwing/agent"
+	executor2 "github.com/browserwing/browserwing/executor"
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/services/browser"
+	"github.com/browserwing/browserwing/services/templates"
+	"github.com/gin-gonic/gin"
+)
+
+// openAPIObject 文档中的内联 JSON 对象，值为字段的模型（Go 值）
+type openAPIObject map[string]interface{}
+
+// openAPIParam 查询参数
+type openAPIParam struct {
+	Name        string
+	Type        string // string, integer, boolean
+	Description string
+}
+
+// openAPIOperation 路由的请求和响应模型，未登记的路由只生成路径、参数和通用的 JSON 对象
+type openAPIOperation struct {
+	Summary  string
+	Query    []openAPIParam
+	Request  interface{} // 请求体模型，nil 表示没有请求体或未描述
+	Optional bool        // 请求体可以省略
+	Form     bool        // 请求体为 multipart/form-data 表单
+	Response interface{} // 成功响应模型，nil 表示未描述的 JSON 对象
+	Status   int         // 成功响应状态码，默认 200
+}
+
+// 常用的查询参数
+var (
+	pageParams = []openAPIParam{
+		{Name: "page", Type: "integer", Description: "Page number, starting from 1"},
+		{Name: "page_size", Type: "integer", Description: "Page size"},
+	}
+	searchParam = openAPIParam{Name: "search", Type: "string", Description: "Search by name"}
+	// 计划执行日历的时间范围
+	calendarParams = []openAPIParam{
+		{Name: "from", Type: "string", Description: "Start of the range (RFC 3339), default: now"},
+		{Name: "to", Type: "string", Description: "End of the range (RFC 3339), default: from + days"},
+		{Name: "days", Type: "integer", Description: "Number of days to cover when to is omitted, default 14, max 90"},
+		{Name: "task_id", Type: "string", Description: "Only list runs of this scheduled task"},
+	}
+)
+
+// messageResponse 只包含提示信息的响应
+var messageResponse = openAPIObject{"message": ""}
+
+// openAPIOperations 按 "METHOD 路由" 登记的请求和响应模型
+var openAPIOperations = map[string]openAPIOperation{
+	"GET /health": {Summary: "Health check", Response: openAPIObject{"status": ""}},
+
+	// 认证
+	"POST /api/v1/auth/login": {Request: models.LoginRequest{}, Response: models.LoginResponse{}},
+	"GET /api/v1/auth/check":  {Response: openAPIObject{"enabled": false}},
+
+	// 提示词
+	"GET /api/v1/prompts":        {Response: openAPIObject{"data": []models.Prompt{}}},
+	"GET /api/v1/prompts/:id":    {Response: openAPIObject{"data": models.Prompt{}}},
+	"POST /api/v1/prompts":       {Request: promptRequest{}, Response: openAPIObject{"data": models.Prompt{}}, Status: http.StatusCreated},
+	"PUT /api/v1/prompts/:id":    {Request: promptRequest{}, Response: openAPIObject{"data": models.Prompt{}}},
+	"DELETE /api/v1/prompts/:id": {Response: messageResponse},
+
+	// 页面组件
+	"GET /api/v1/page-components":        {Response: openAPIObject{"data": []models.PageComponent{}}},
+	"GET /api/v1/page-components/:id":    {Response: openAPIObject{"data": models.PageComponent{}}},
+	"POST /api/v1/page-components":       {Request: pageComponentRequest{}, Response: openAPIObject{"data": models.PageComponent{}}, Status: http.StatusCreated},
+	"PUT /api/v1/page-components/:id":    {Request: pageComponentRequest{}, Response: openAPIObject{"data": models.PageComponent{}}},
+	"DELETE /api/v1/page-components/:id": {Response: messageResponse},
+
+	// 执行环境
+	"GET /api/v1/environments":        {Response: openAPIObject{"data": []models.Environment{}}},
+	"GET /api/v1/environments/:id":    {Response: openAPIObject{"data": models.Environment{}, "credentials_set": true}},
+	"POST /api/v1/environments":       {Request: environmentRequest{}, Response: openAPIObject{"data": models.Environment{}}, Status: http.StatusCreated},
+	"PUT /api/v1/environments/:id":    {Request: environmentRequest{}, Response: openAPIObject{"data": models.Environment{}}},
+	"DELETE /api/v1/environments/:id": {Response: messageResponse},
+
+	// 站点知识包
+	"GET /api/v1/site-knowledge":        {Response: openAPIObject{"data": []models.SiteKnowledge{}}},
+	"GET /api/v1/site-knowledge/:id":    {Response: openAPIObject{"data": models.SiteKnowledge{}}},
+	"POST /api/v1/site-knowledge":       {Request: siteKnowledgeRequest{}, Response: openAPIObject{"data": models.SiteKnowledge{}}, Status: http.StatusCreated},
+	"PUT /api/v1/site-knowledge/:id":    {Request: siteKnowledgeRequest{}, Response: openAPIObject{"data": models.SiteKnowledge{}}},
+	"DELETE /api/v1/site-knowledge/:id": {Response: messageResponse},
+
+	// 通知
+	"GET /api/v1/notifications/channels":        {Response: openAPIObject{"data": []models.NotificationChannel{}}},
+	"GET /api/v1/notifications/channels/:id":    {Response: openAPIObject{"data": models.NotificationChannel{}}},
+	"POST /api/v1/notifications/channels":       {Request: models.NotificationChannel{}, Response: openAPIObject{"data": models.NotificationChannel{}}, Status: http.StatusCreated},
+	"PUT /api/v1/notifications/channels/:id":    {Request: models.NotificationChannel{}, Response: openAPIObject{"data": models.NotificationChannel{}}},
+	"DELETE /api/v1/notifications/channels/:id": {Response: messageResponse},
+	"POST /api/v1/notifications/channels/:id/test": {
+		Summary:  "Send a test message to a notification channel",
+		Response: messageResponse,
+	},
+	"GET /api/v1/notifications/rules":          {Response: openAPIObject{"data": []models.NotificationRule{}}},
+	"GET /api/v1/notifications/rules/:id":      {Response: openAPIObject{"data": models.NotificationRule{}}},
+	"POST /api/v1/notifications/rules":         {Request: models.NotificationRule{}, Response: openAPIObject{"data": models.NotificationRule{}}, Status: http.StatusCreated},
+	"PUT /api/v1/notifications/rules/:id":      {Request: models.NotificationRule{}, Response: openAPIObject{"data": models.NotificationRule{}}},
+	"DELETE /api/v1/notifications/rules/:id":   {Response: messageResponse},
+	"GET /api/v1/notifications/digests":        {Response: openAPIObject{"data": []models.NotificationDigest{}}},
+	"GET /api/v1/notifications/digests/:id":    {Response: openAPIObject{"data": models.NotificationDigest{}}},
+	"POST /api/v1/notifications/digests":       {Request: models.NotificationDigest{}, Response: openAPIObject{"data": models.NotificationDigest{}}, Status: http.StatusCreated},
+	"PUT /api/v1/notifications/digests/:id":    {Request: models.NotificationDigest{}, Response: openAPIObject{"data": models.NotificationDigest{}}},
+	"DELETE /api/v1/notifications/digests/:id": {Response: messageResponse},
+	"POST /api/v1/notifications/digests/:id/send": {
+		Summary:  "Send a digest for the period ending now to its channels",
+		Response: messageResponse,
+	},
+
+	// 注入界面的自定义语言
+	"GET /api/v1/ui-locales":           {Response: openAPIObject{"data": []models.UILocale{}, "builtin": []string{}}},
+	"GET /api/v1/ui-locales/:language": {Response: openAPIObject{"data": models.UILocale{}}},
+	"GET /api/v1/ui-locales/:language/texts": {
+		Summary:  "Get the effective recorder, float button and player texts for a language",
+		Response: openAPIObject{"data": uiLocaleTexts{}},
+	},
+	"PUT /api/v1/ui-locales/:language": {
+		Summary:  "Create or replace a custom language or text overrides for the injected UI",
+		Request:  models.UILocale{},
+		Response: openAPIObject{"data": models.UILocale{}},
+	},
+	"DELETE /api/v1/ui-locales/:language": {Response: messageResponse},
+
+	// 脚本模板
+	"GET /api/v1/templates": {
+		Query:    []openAPIParam{{Name: "category", Type: "string", Description: "Filter by category: search, login, crawl or form"}},
+		Response: openAPIObject{"data": []scriptTemplate{}},
+	},
+	"GET /api/v1/templates/:id": {Response: openAPIObject{"data": scriptTemplate{}}},
+	"POST /api/v1/templates/:id/install": {
+		Summary:  "Create a script from a built-in template; params are saved as the script's default variables",
+		Request:  templates.InstallOptions{},
+		Response: openAPIObject{"message": "", "script": models.Script{}},
+		Status:   http.StatusCreated,
+	},
+
+	// 脚本市场
+	"GET /api/v1/marketplace/info":    {Response: openAPIObject{"data": marketplaceInfo{}}},
+	"GET /api/v1/marketplace/bundles": {Summary: "List the bundles published in the configured registry", Response: openAPIObject{"data": []marketplaceBundle{}, "registry": ""}},
+	"POST /api/v1/marketplace/install": {
+		Summary:  "Install or upgrade a bundle from the registry (by id) or from an exported bundle file",
+		Request:  installBundleRequest{},
+		Response: openAPIObject{"data": models.InstalledBundle{}},
+	},
+	"GET /api/v1/marketplace/installed":        {Response: openAPIObject{"data": []models.InstalledBundle{}}},
+	"DELETE /api/v1/marketplace/installed/:id": {Summary: "Uninstall a bundle and delete its scripts", Response: messageResponse},
+	"POST /api/v1/marketplace/export":          {Summary: "Pack scripts into a (signed) bundle", Request: packBundleRequest{}, Response: openAPIObject{"data": models.SignedBundle{}}},
+	"POST /api/v1/marketplace/publish":         {Summary: "Pack, sign and publish scripts to the registry", Request: packBundleRequest{}, Response: openAPIObject{"message": "", "data": models.SignedBundle{}}},
+
+	// 浏览器实例
+	"GET /api/v1/browser/instances":           {Response: openAPIObject{"instances": []models.BrowserInstance{}}},
+	"GET /api/v1/browser/instances/current":   {Response: openAPIObject{"instance": models.BrowserInstance{}}},
+	"GET /api/v1/browser/instances/:id":       {Response: openAPIObject{"instance": models.BrowserInstance{}}},
+	"POST /api/v1/browser/instances":          {Request: models.BrowserInstance{}, Response: openAPIObject{"message": "", "instance": models.BrowserInstance{}}},
+	"PUT /api/v1/browser/instances/:id":       {Request: models.BrowserInstance{}, Response: openAPIObject{"message": "", "instance": models.BrowserInstance{}}},
+	"DELETE /api/v1/browser/instances/:id":    {Response: messageResponse},
+	"POST /api/v1/browser/instances/:id/stop": {Response: messageResponse},
+
+	// 浏览器配置
+	"GET /api/v1/browser-configs":     {Response: openAPIObject{"configs": []models.BrowserConfig{}, "count": 0}},
+	"GET /api/v1/browser-configs/:id": {Response: models.BrowserConfig{}},
+	"POST /api/v1/browser-configs":    {Request: models.BrowserConfig{}, Response: openAPIObject{"message": "", "config": models.BrowserConfig{}}},
+	"PUT /api/v1/browser-configs/:id": {Request: models.BrowserConfig{}, Response: openAPIObject{"message": "", "config": models.BrowserConfig{}}},
+
+	// 脚本
+	"GET /api/v1/scripts": {
+		Query: append(pageParams,
+			openAPIParam{Name: "group", Type: "string", Description: "Filter by group"},
+			openAPIParam{Name: "tag", Type: "string", Description: "Filter by tag"}),
+		Response: openAPIObject{"scripts": []models.Script{}, "total": 0, "page": 0, "page_size": 0},
+	},
+	"GET /api/v1/scripts/:id":      {Response: models.Script{}},
+	"POST /api/v1/scripts":         {Request: models.Script{}, Response: openAPIObject{"message": "", "script": models.Script{}, "warnings": []browser.LintIssue{}}},
+	"PUT /api/v1/scripts/:id":      {Request: updateScriptRequest{}, Response: openAPIObject{"message": "", "script": models.Script{}, "warnings": []browser.LintIssue{}}},
+	"DELETE /api/v1/scripts/:id":   {Response: messageResponse},
+	"GET /api/v1/scripts/:id/lint": {Response: openAPIObject{"data": []browser.LintIssue{}}},
+	"POST /api/v1/scripts/lint":    {Request: models.Script{}, Response: openAPIObject{"data": []browser.LintIssue{}}},
+	"GET /api/v1/scripts/:id/state": {
+		Summary:  "Get the incremental scraping state of a script (seen item count, cursor)",
+		Response: openAPIObject{"data": models.ScriptStateSummary{}},
+	},
+	"PUT /api/v1/scripts/:id/state": {
+		Summary:  "Set the cursor or clear the seen items of a script",
+		Request:  updateScriptStateRequest{},
+		Response: openAPIObject{"data": models.ScriptStateSummary{}},
+	},
+	"DELETE /api/v1/scripts/:id/state": {
+		Summary:  "Reset the incremental scraping state of a script",
+		Response: messageResponse,
+	},
+	"GET /api/v1/scripts/:id/metrics": {
+		Summary:  "List extraction metrics (item count, null rate) and anomalies of recent executions, newest first",
+		Query:    []openAPIParam{{Name: "limit", Type: "integer", Description: "Number of executions, default 50, max 500"}},
+		Response: openAPIObject{"data": []models.ScriptMetricsPoint{}},
+	},
+	"GET /api/v1/scripts/health": {
+		Summary:  "List the latest selector health report of every checked script, unhealthy first",
+		Response: openAPIObject{"data": []models.ScriptHealthReport{}},
+	},
+	"GET /api/v1/scripts/:id/health": {
+		Summary:  "Get the latest selector health report of a script",
+		Response: openAPIObject{"data": models.ScriptHealthReport{}},
+	},
+	"POST /api/v1/scripts/:id/health": {
+		Summary:  "Open the script's pages now and check that its recorded selectors still resolve",
+		Request:  checkScriptHealthRequest{},
+		Optional: true,
+		Response: openAPIObject{"data": models.ScriptHealthReport{}},
+	},
+	"GET /api/v1/scripts/play/result": {
+		Summary:  "Get data extracted by the last playback",
+		Response: openAPIObject{"data": map[string]interface{}{}},
+	},
+	"POST /api/v1/scripts/:id/play": {
+		Summary: "Play a script",
+		Query: []openAPIParam{
+			{Name: "instance_id", Type: "string", Description: "Browser instance to run on (also X-Instance-ID header), default: current instance"},
+		},
+		Request:  playScriptRequest{},
+		Optional: true,
+		Response: openAPIObject{"message": "", "script": "", "result": models.PlayResult{}},
+	},
+
+	// 自动化触发接口
+	"GET /api/v1/automation/scripts": {
+		Summary: "List scripts with their parameter schemas",
+		Query: []openAPIParam{
+			{Name: "group", Type: "string", Description: "Filter by group"},
+			{Name: "tag", Type: "string", Description: "Filter by tag"},
+		},
+		Response: openAPIObject{"data": []automationScript{}},
+	},
+	"GET /api/v1/automation/scripts/:id": {
+		Summary:  "Get a script with its parameter schema",
+		Response: openAPIObject{"data": automationScript{}},
+	},
+	"POST /api/v1/automation/scripts/:id/runs": {
+		Summary:  "Start a script run in the background; poll the run or pass callback_url",
+		Request:  automationRunRequest{},
+		Optional: true,
+		Response: openAPIObject{"data": models.AutomationRun{}},
+		Status:   http.StatusAccepted,
+	},
+	"GET /api/v1/automation/runs/:id": {
+		Summary:  "Get the status and result of a script run",
+		Response: openAPIObject{"data": models.AutomationRun{}},
+	},
+	"POST /api/v1/automation/scripts/:id/bulk-runs": {
+		Summary: "Run a script once per parameter row (JSON rows or a text/csv body with a header row) in the background",
+		Query: []openAPIParam{
+			{Name: "concurrency", Type: "integer", Description: "Rows run at the same time for a CSV body, default 1, max 10"},
+			{Name: "environment", Type: "string", Description: "Environment for a CSV body"},
+			{Name: "run_tags", Type: "string", Description: "Comma-separated run tags for a CSV body"},
+			{Name: "instance_id", Type: "string", Description: "Browser instance to run on (also X-Instance-ID header), default: current instance"},
+		},
+		Request:  bulkRunRequest{},
+		Response: openAPIObject{"data": models.BulkRun{}},
+		Status:   http.StatusAccepted,
+	},
+	"GET /api/v1/automation/bulk-runs/:id": {
+		Summary:  "Get the progress and per-row results of a bulk run",
+		Response: openAPIObject{"data": models.BulkRun{}},
+	},
+	"POST /api/v1/automation/bulk-runs/:id/cancel": {
+		Summary:  "Cancel the rows of a bulk run that have not started",
+		Response: openAPIObject{"data": models.BulkRun{}},
+	},
+	"GET /api/v1/automation/bulk-runs/:id/results": {
+		Summary: "Export the combined results of a bulk run (CSV, or JSON rows with format=json)",
+		Query: []openAPIParam{
+			{Name: "format", Type: "string", Description: "csv (default) or json"},
+		},
+	},
+
+	// 计划执行日历
+	"GET /api/v1/calendar/runs": {
+		Summary:  "List upcoming runs of enabled scheduled tasks",
+		Query:    calendarParams,
+		Response: openAPIObject{"data": scheduledRunsResponse{}},
+	},
+	"GET /api/v1/calendar/runs.ics": {
+		Summary: "iCalendar feed of upcoming scheduled runs (text/calendar)",
+		Query: append([]openAPIParam{
+			{Name: "key", Type: "string", Description: "API key, for calendar apps that cannot send the X-BrowserWing-Key header"},
+		}, calendarParams...),
+	},
+
+	// 上传文件
+	"POST /api/v1/uploads": {
+		Summary: "Upload a file (multipart field \"file\") and get a handle to use in upload_file file_paths",
+		Request: struct {
+			File []byte `json:"file"`
+		}{},
+		Form:     true,
+		Response: openAPIObject{"data": models.UploadedFile{}},
+		Status:   http.StatusCreated,
+	},
+	"GET /api/v1/uploads":        {Response: openAPIObject{"data": []models.UploadedFile{}}},
+	"DELETE /api/v1/uploads/:id": {Response: messageResponse},
+
+	// 脚本执行记录
+	"GET /api/v1/script-executions": {
+		Query: append(pageParams,
+			openAPIParam{Name: "script_id", Type: "string", Description: "Filter by script ID"},
+			openAPIParam{Name: "search", Type: "string", Description: "Search by script name"},
+			openAPIParam{Name: "success", Type: "string", Description: "Filter by result: true, false"}),
+		Response: openAPIObject{"executions": []models.ScriptExecution{}, "total": 0, "page": 0, "page_size": 0},
+	},
+	"GET /api/v1/script-executions/:id":    {Response: models.ScriptExecution{}},
+	"DELETE /api/v1/script-executions/:id": {Response: messageResponse},
+	"GET /api/v1/script-executions/:id/compare": {
+		Summary:  "Compare an execution with another execution of the same script (step outcomes, durations, extracted data, screenshots)",
+		Query:    []openAPIParam{{Name: "base", Type: "string", Description: "ID of the execution to compare with, default: the latest earlier successful execution of the script"}},
+		Response: openAPIObject{"data": models.ExecutionComparison{}},
+	},
+
+	// 定时任务
+	"GET /api/v1/scheduled-tasks": {
+		Query:    append(pageParams, searchParam),
+		Response: openAPIObject{"tasks": []models.ScheduledTask{}, "total": 0, "page": 0, "page_size": 0},
+	},
+	"GET /api/v1/scheduled-tasks/:id":    {Response: openAPIObject{"task": models.ScheduledTask{}}},
+	"POST /api/v1/scheduled-tasks":       {Request: models.ScheduledTask{}, Response: openAPIObject{"message": "", "task": models.ScheduledTask{}}},
+	"PUT /api/v1/scheduled-tasks/:id":    {Request: models.ScheduledTask{}, Response: openAPIObject{"message": "", "task": models.ScheduledTask{}}},
+	"DELETE /api/v1/scheduled-tasks/:id": {Response: messageResponse},
+
+	// 任务执行记录
+	"GET /api/v1/task-executions": {
+		Query: append(pageParams,
+			openAPIParam{Name: "task_id", Type: "string", Description: "Filter by task ID"},
+			openAPIParam{Name: "search", Type: "string", Description: "Search by task name"},
+			openAPIParam{Name: "success", Type: "string", Description: "Filter by result: all, true, false"}),
+		Response: openAPIObject{"executions": []models.TaskExecution{}, "total": 0, "page": 0, "page_size": 0},
+	},
+	"GET /api/v1/task-executions/:id":    {Response: openAPIObject{"execution": models.TaskExecution{}}},
+	"DELETE /api/v1/task-executions/:id": {Response: messageResponse},
+
+	// LLM 配置
+	"GET /api/v1/llm-configs":        {Response: openAPIObject{"configs": []models.LLMConfigModel{}}},
+	"GET /api/v1/llm-configs/:id":    {Response: models.LLMConfigModel{}},
+	"POST /api/v1/llm-configs":       {Request: models.LLMConfigModel{}, Response: models.LLMConfigModel{}},
+	"PUT /api/v1/llm-configs/:id":    {Request: models.LLMConfigModel{}, Response: models.LLMConfigModel{}},
+	"DELETE /api/v1/llm-configs/:id": {Response: messageResponse},
+
+	// LLM 路由策略
+	"GET /api/v1/llm-routing": {
+		Summary:  "Get the LLM routing policy and the steps that can be routed to a small model",
+		Response: openAPIObject{"policy": models.LLMRoutingPolicy{}, "steps": []string{}},
+	},
+	"PUT /api/v1/llm-routing": {
+		Summary:  "Route cheap steps (task evaluation, action verification, translation, MCP info) to a small model. An empty cheap_config_id turns routing off; empty cheap_steps routes all of them",
+		Request:  models.LLMRoutingPolicy{},
+		Response: openAPIObject{"message": "", "policy": models.LLMRoutingPolicy{}},
+	},
+
+	// 录制配置
+	"GET /api/v1/recording-config": {Response: models.RecordingConfig{}},
+	"PUT /api/v1/recording-config": {Request: models.RecordingConfig{}, Response: openAPIObject{"message": "", "config": models.RecordingConfig{}}},
+
+	// 工具配置
+	"GET /api/v1/tool-configs": {
+		Query: append(pageParams, searchParam,
+			openAPIParam{Name: "type", Type: "string", Description: "Filter by tool type: preset, script"}),
+		Response: openAPIObject{"data": []models.ToolConfig{}, "total": 0},
+	},
+	"GET /api/v1/tool-configs/:id": {Response: models.ToolConfig{}},
+	"PUT /api/v1/tool-configs/:id": {Request: models.ToolConfig{}, Response: models.ToolConfig{}},
+
+	// MCP 服务
+	"GET /api/v1/mcp-services":     {Response: openAPIObject{"data": []models.MCPService{}}},
+	"GET /api/v1/mcp-services/:id": {Response: models.MCPService{}},
+	"POST /api/v1/mcp-services":    {Request: models.MCPService{}, Response: openAPIObject{"message": "", "service": models.MCPService{}}},
+	"PUT /api/v1/mcp-services/:id": {Request: models.MCPService{}, Response: openAPIObject{"message": "", "service": models.MCPService{}}},
+
+	// 用户和 API 密钥
+	"GET /api/v1/users":              {Response: []models.User{}},
+	"GET /api/v1/users/:id":          {Response: models.User{}},
+	"POST /api/v1/users":             {Request: models.CreateUserRequest{}, Response: models.User{}},
+	"PUT /api/v1/users/:id/password": {Request: models.UpdatePasswordRequest{}, Response: messageResponse},
+	"DELETE /api/v1/users/:id":       {Response: messageResponse},
+	"GET /api/v1/api-keys":           {Response: []models.ApiKey{}},
+	"GET /api/v1/api-keys/:id":       {Response: models.ApiKey{}},
+	"POST /api/v1/api-keys":          {Request: models.CreateApiKeyRequest{}, Response: models.ApiKey{}},
+	"DELETE /api/v1/api-keys/:id":    {Response: messageResponse},
+
+	// Agent 会话
+	"POST /api/v1/agent/sessions": {
+		Summary:  "Create an agent chat session. system_prompt_id uses a stored prompt as the system prompt, read_only limits the agent to tools that open and read pages, verify_actions checks each page-changing tool call",
+		Request:  openAPIObject{"llm_config_id": "", "system_prompt_id": "", "approval_categories": []string{}, "read_only": false, "verify_actions": false},
+		Optional: true,
+		Response: openAPIObject{"session": agent.ChatSession{}},
+	},
+
+	// Agent 会话记录
+	"GET /api/v1/agent/sessions/:id/export": {
+		Summary:  "Export an agent session as a self-contained transcript (messages, tool calls with arguments and results, embedded screenshots)",
+		Query:    []openAPIParam{{Name: "format", Type: "string", Description: "json (default) or markdown"}},
+		Response: agent.SessionTranscript{},
+	},
+	"POST /api/v1/agent/transcripts/replay": {
+		Summary:  "Replay the tool calls of an exported transcript in order, without the LLM, and compare the results with the recorded ones",
+		Query:    []openAPIParam{{Name: "stop_on_divergence", Type: "boolean", Description: "Stop after the first call whose outcome differs from the transcript"}},
+		Request:  agent.SessionTranscript{},
+		Response: openAPIObject{"data": agent.ReplayReport{}},
+	},
+
+	// Agent 工具调用批准
+	"PUT /api/v1/agent/sessions/:id/approval-settings": {
+		Summary:  "Set the tool call categories that need human approval in a session: navigation, form_submit, download, click",
+		Request:  openAPIObject{"categories": []string{}},
+		Response: openAPIObject{"message": "", "session": agent.ChatSession{}},
+	},
+	"GET /api/v1/agent/sessions/:id/approvals": {
+		Summary:  "List the tool calls of a session that are waiting for approval",
+		Response: openAPIObject{"approvals": []agent.PendingApproval{}, "count": 0},
+	},
+	"POST /api/v1/agent/sessions/:id/approvals/:approvalId": {
+		Summary:  "Approve or deny a tool call that is waiting for approval",
+		Request:  openAPIObject{"approved": true},
+		Response: messageResponse,
+	},
+
+	// Agent 操作校验
+	"PUT /api/v1/agent/sessions/:id/verification": {
+		Summary:  "Turn action verification on or off for a session. After each page-changing tool call the agent takes a screenshot and asks the LLM whether the expected change occurred, and retries when it did not",
+		Request:  openAPIObject{"enabled": true},
+		Response: openAPIObject{"message": "", "session": agent.ChatSession{}},
+	},
+}
+
+// openAPIExcludedPaths 不属于 REST API 的路由（MCP 协议端点），不写入文档
+var openAPIExcludedPaths = []string{"/api/v1/mcp/sse"}
+
+// executorTargetParams Executor API 的操作目标参数（也可以用 X-Instance-ID 等请求头指定）
+var executorTargetParams = []openAPIParam{
+	{Name: "instance_id", Type: "string", Description: "Browser instance (also X-Instance-ID header), default: current instance"},
+	{Name: "tab_id", Type: "string", Description: "Tab TargetID (also X-Tab-ID header), default: active tab"},
+	{Name: "session_id", Type: "string", Description: "Session ID (also X-Session-ID header)"},
+}
+
+// OpenAPISpec 返回所有路由的 OpenAPI 3 文档，首次请求时根据已注册的路由生成
+func OpenAPISpec(r *gin.Engine) gin.HandlerFunc {
+	var (
+		once sync.Once
+		doc  []byte
+		err  error
+	)
+	return func(c *gin.Context) {
+		once.Do(func() {
+			doc, err = json.Marshal(OpenAPIDocument(r))
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error.generateOpenAPIFailed", "detail": err.Error()})
+			return
+		}
+		c.Data(http.StatusOK, "application/json; charset=utf-8", doc)
+	}
+}
+
+// OpenAPIDocument 根据 r 中已注册的路由生成 OpenAPI 3 文档，也用于生成 Python/TypeScript 客户端
+func OpenAPIDocument(r *gin.Engine) map[string]interface{} {
+	return buildOpenAPISpec(r.Routes())
+}
+
+// buildOpenAPISpec 根据 gin 路由生成 OpenAPI 3 文档
+func buildOpenAPISpec(routes gin.RoutesInfo) map[string]interface{} {
+	schemas := newSchemaRegistry()
+	schemas.components["Error"] = map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"error":  map[string]interface{}{"type": "string", "description": "Error code, e.g. error.scriptNotFound"},
+			"detail": map[string]interface{}{"type": "string"},
+		},
+	}
+
+	commands := make(map[string]map[string]interface{})
+	for _, cmd := range executorCommands() {
+		commands[cmd["method"].(string)+" "+cmd["endpoint"].(string)] = cmd
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+
+	paths := make(map[string]interface{})
+	operationIDs := make(map[string]bool)
+	for _, route := range routes {
+		if !documentedRoute(route.Path) {
+			continue
+		}
+		key := route.Method + " " + route.Path
+		doc := openAPIOperations[key]
+
+		operationID := handlerOperationID(route.Handler)
+		if operationID == "" || operationIDs[operationID] {
+			// 匿名处理函数或多个路由共用同一处理函数（如兼容旧路由）时按路由生成
+			operationID = pathOperationID(route.Method, route.Path)
+		}
+		operationIDs[operationID] = true
+
+		op := map[string]interface{}{
+			"operationId": operationID,
+			"tags":        []string{routeTag(route.Path)},
+			"summary":     splitCamelCase(strings.TrimPrefix(operationID, "Executor")),
+		}
+		if doc.Summary != "" {
+			op["summary"] = doc.Summary
+		}
+
+		openAPIPath, params := convertRoutePath(route.Path)
+		for _, q := range doc.Query {
+			params = append(params, queryParameter(q))
+		}
+
+		status := doc.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		responseDescription := "Success"
+		var requestBody, responseSchema interface{}
+		bodyRequired := false
+		if doc.Request != nil {
+			requestBody = schemas.valueSchema(doc.Request)
+			bodyRequired = !doc.Optional
+		}
+		if doc.Response != nil {
+			responseSchema = schemas.valueSchema(doc.Response)
+		}
+
+		if strings.HasPrefix(route.Path, "/api/v1/executor/") {
+			for _, p := range executorTargetParams {
+				params = append(params, queryParameter(p))
+			}
+			if cmd, ok := commands[key]; ok {
+				op["summary"] = cmd["description"]
+				if note, ok := cmd["note"].(string); ok {
+					op["description"] = note
+				}
+				if returns, ok := cmd["returns"].(string); ok {
+					responseDescription = returns
+				}
+				parameters, _ := cmd["parameters"].(map[string]interface{})
+				if route.Method == http.MethodGet {
+					params = append(params, commandQueryParameters(parameters)...)
+				} else if requestBody == nil {
+					body := commandRequestSchema(parameters)
+					if example, ok := cmd["example"]; ok {
+						body["example"] = example
+					}
+					_, bodyRequired = body["required"]
+					requestBody = body
+				}
+			}
+			if responseSchema == nil && route.Path != "/api/v1/executor/help" && route.Path != "/api/v1/executor/export/skill" {
+				responseSchema = schemas.valueSchema(executor2.OperationResult{})
+			}
+		}
+
+		if len(params) > 0 {
+			op["parameters"] = params
+		}
+		if requestBody != nil {
+			contentType := "application/json"
+			if doc.Form {
+				// 表单中的 []byte 字段是文件，而不是 base64 字符串
+				contentType = "multipart/form-data"
+				if props, ok := requestBody.(map[string]interface{})["properties"].(map[string]interface{}); ok {
+					for _, prop := range props {
+						if prop, ok := prop.(map[string]interface{}); ok && prop["format"] == "byte" {
+							prop["format"] = "binary"
+						}
+					}
+				}
+			}
+			op["requestBody"] = map[string]interface{}{
+				"required": bodyRequired,
+				"content":  map[string]interface{}{contentType: map[string]interface{}{"schema": requestBody}},
+			}
+		} else if route.Method == http.MethodPost || route.Method == http.MethodPut {
+			op["requestBody"] = map[string]interface{}{
+				"content": map[string]interface{}{"application/json": map[string]interface{}{"schema": map[string]interface{}{"type": "object"}}},
+			}
+		}
+		if responseSchema == nil {
+			responseSchema = map[string]interface{}{"type": "object"}
+		}
+		op["responses"] = map[string]interface{}{
+			strconv.Itoa(status): map[string]interface{}{
+				"description": responseDescription,
+				"content":     map[string]interface{}{"application/json": map[string]interface{}{"schema": responseSchema}},
+			},
+			"default": map[string]interface{}{
+				"description": "Error",
+				"content": map[string]interface{}{"application/json": map[string]interface{}{
+					"schema": map[string]interface{}{"$ref": "#/components/schemas/Error"},
+				}},
+			},
+		}
+		if security := routeSecurity(route.Path); security != nil {
+			op["security"] = security
+		}
+
+		item, _ := paths[openAPIPath].(map[string]interface{})
+		if item == nil {
+			item = make(map[string]interface{})
+			paths[openAPIPath] = item
+		}
+		item[strings.ToLower(route.Method)] = op
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "BrowserWing API",
+			"version":     "v1",
+			"description": "REST API of BrowserWing: scripts, scheduled tasks, browser instances and the executor API for driving the browser step by step.",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": schemas.components,
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+				"apiKeyAuth": map[string]interface{}{"type": "apiKey", "in": "header", "name": "X-BrowserWing-Key"},
+			},
+		},
+	}
+}
+
+// documentedRoute 是否写入文档：只包含 API 路由和健康检查
+func documentedRoute(path string) bool {
+	for _, excluded := range openAPIExcludedPaths {
+		if strings.HasPrefix(path, excluded) {
+			return false
+		}
+	}
+	return strings.HasPrefix(path, "/api/") || path == "/health"
+}
+
+// routeSecurity 路由的认证方式，与 SetupRouter 中各路由组使用的中间件一致
+func routeSecurity(path string) []map[string][]string {
+	bearer := map[string][]string{"bearerAuth": {}}
+	apiKey := map[string][]string{"apiKeyAuth": {}}
+	switch {
+	case !strings.HasPrefix(path, "/api/v1/"), strings.HasPrefix(path, "/api/v1/auth/"):
+		return nil
+	case strings.HasPrefix(path, "/api/v1/executor/"), strings.HasPrefix(path, "/api/v1/automation/"),
+		strings.HasPrefix(path, "/api/v1/calendar/"), path == "/api/v1/scripts/:id/play":
+		return []map[string][]string{bearer, apiKey}
+	default:
+		return []map[string][]string{bearer}
+	}
+}
+
+// routeTag 按 /api/v1 之后的第一段路径分组
+func routeTag(path string) string {
+	rest := strings.TrimPrefix(path, "/api/v1/")
+	if rest == path {
+		return "system"
+	}
+	tag, _, _ := strings.Cut(rest, "/")
+	return tag
+}
+
+// convertRoutePath 把 gin 路由参数（:id、*path）转换为 OpenAPI 路径参数
+func convertRoutePath(path string) (string, []interface{}) {
+	segments := strings.Split(path, "/")
+	var params []interface{}
+	for i, segment := range segments {
+		if segment == "" || (segment[0] != ':' && segment[0] != '*') {
+			continue
+		}
+		name := segment[1:]
+		segments[i] = "{" + name + "}"
+		params = append(params, map[string]interface{}{
+			"name":     name,
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]interface{}{"type": "string"},
+		})
+	}
+	return strings.Join(segments, "/"), params
+}
+
+func queryParameter(p openAPIParam) map[string]interface{} {
+	param := map[string]interface{}{
+		"name":   p.Name,
+		"in":     "query",
+		"schema": map[string]interface{}{"type": p.Type},
+	}
+	if p.Description != "" {
+		param["description"] = p.Description
+	}
+	return param
+}
+
+// handlerMethodPattern 匹配方法值处理函数的名称，如 github.com/.../api.(*Handler).ListScripts-fm
+var handlerMethodPattern = regexp.MustCompile(`\.([A-Za-z0-9_]+)-fm$`)
+
+// handlerOperationID 用处理函数的方法名作为 operationId，匿名函数返回空字符串
+func handlerOperationID(handler string) string {
+	if m := handlerMethodPattern.FindStringSubmatch(handler); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// pathOperationID 按请求方法和路由生成 operationId，如 GET /api/v1/executor/semantic-tree -> getExecutorSemanticTree
+func pathOperationID(method, path string) string {
+	var sb strings.Builder
+	sb.WriteString(strings.ToLower(method))
+	for _, part := range strings.FieldsFunc(strings.TrimPrefix(path, "/api/v1"), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		sb.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return sb.String()
+}
+
+// splitCamelCase 把方法名转换为摘要，如 ListScriptExecutions -> List script executions
+func splitCamelCase(name string) string {
+	var words []string
+	start := 0
+	runes := []rune(name)
+	for i := 1; i < len(runes); i++ {
+		// 连续大写字母（如 MCP、URL）作为一个单词
+		if unicode.IsUpper(runes[i]) && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	words = append(words, string(runes[start:]))
+	for i := 1; i < len(words); i++ {
+		if w := []rune(words[i]); len(w) < 2 || !unicode.IsUpper(w[1]) {
+			words[i] = strings.ToLower(words[i])
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// commandRequestSchema 把 Executor 命令的参数说明转换为请求体 schema
+func commandRequestSchema(parameters map[string]interface{}) map[string]interface{} {
+	properties := make(map[string]interface{}, len(parameters))
+	var required []string
+	for name, raw := range parameters {
+		param, _ := raw.(map[string]interface{})
+		properties[name] = commandParameterSchema(param)
+		if isRequired, _ := param["required"].(bool); isRequired {
+			required = append(required, name)
+		}
+	}
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	return schema
+}
+
+// commandQueryParameters 把 GET 命令的参数说明转换为查询参数
+func commandQueryParameters(parameters map[string]interface{}) []interface{} {
+	names := make([]string, 0, len(parameters))
+	for name := range parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	params := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		param, _ := parameters[name].(map[string]interface{})
+		p := map[string]interface{}{
+			"name":   name,
+			"in":     "query",
+			"schema": commandParameterSchema(param),
+		}
+		if description, ok := param["description"]; ok {
+			p["description"] = description
+		}
+		if isRequired, _ := param["required"].(bool); isRequired {
+			p["required"] = true
+		}
+		params = append(params, p)
+	}
+	return params
+}
+
+func commandParameterSchema(param map[string]interface{}) map[string]interface{} {
+	schema := make(map[string]interface{})
+	if t, ok := param["type"].(string); ok && t != "" {
+		schema["type"] = t
+		if t == "array" {
+			schema["items"] = map[string]interface{}{}
+		}
+	}
+	for _, key := range []string{"description", "default", "example"} {
+		if v, ok := param[key]; ok {
+			schema[key] = v
+		}
+	}
+	return schema
+}
+
+// schemaRegistry 根据 Go 类型生成 JSON Schema，具名结构体登记到 components/schemas 并通过 $ref 引用
+type schemaRegistry struct {
+	components map[string]interface{}
+	names      map[reflect.Type]string
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{
+		components: make(map[string]interface{}),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	durationType   = reflect.TypeOf(time.Duration(0))
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// valueSchema 生成模型的 schema，openAPIObject 按字段逐个生成，其他值按类型生成
+func (s *schemaRegistry) valueSchema(v interface{}) map[string]interface{} {
+	object, ok := v.(openAPIObject)
+	if !ok {
+		return s.schemaOf(reflect.TypeOf(v))
+	}
+	properties := make(map[string]interface{}, len(object))
+	for name, field := range object {
+		properties[name] = s.valueSchema(field)
+	}
+	return map[string]interface{}{"type": "object", "properties": properties}
+}
+
+func (s *schemaRegistry) schemaOf(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case durationType:
+		return map[string]interface{}{"type": "integer", "format": "int64", "description": "Duration in nanoseconds"}
+	case rawMessageType:
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": s.schemaOf(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": s.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + s.register(t)}
+	default:
+		// interface{} 等任意值
+		return map[string]interface{}{}
+	}
+}
+
+// register 登记具名结构体，返回 schema 名称；不同包的同名类型加包名前缀
+func (s *schemaRegistry) register(t reflect.Type) string {
+	if name, ok := s.names[t]; ok {
+		return name
+	}
+	// 未导出的请求类型（如 environmentRequest）首字母大写，便于生成客户端类型
+	name := strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+	if _, exists := s.components[name]; exists {
+		pkg := path.Base(t.PkgPath())
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+	// 先登记名称再生成字段，支持自引用的类型
+	s.names[t] = name
+	s.components[name] = s.structSchema(t)
+	return name
+}
+
+// structSchema 按 json 标签生成结构体的 schema，binding:"required" 的字段为必填
+func (s *schemaRegistry) structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+	s.collectFields(t, properties, &required)
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	return schema
+}
+
+func (s *schemaRegistry) collectFields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		// 匿名嵌入且没有 json 名称的结构体，字段提升到外层
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				s.collectFields(ft, properties, required)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = s.schemaOf(field.Type)
+		if strings.Contains(field.Tag.Get("binding"), "required") {
+			*required = append(*required, name)
+		}
+	}
+}
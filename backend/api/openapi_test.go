@@ -0,0 +1,153 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/browserwing/browserwing/config"
+	"github.com/browserwing/browserwing/mcp"
+	"github.com/browserwing/browserwing/services/browser"
+	"github.com/browserwing/browserwing/storage"
+)
+
+func TestConvertRoutePath(t *testing.T) {
+	path, params := convertRoutePath("/api/v1/scheduled-tasks/:id/screenshots/:shot_id/image")
+	if path != "/api/v1/scheduled-tasks/{id}/screenshots/{shot_id}/image" {
+		t.Fatalf("unexpected path %s", path)
+	}
+	if len(params) != 2 {
+		t.Fatalf("expected 2 path parameters, got %d", len(params))
+	}
+	if name := params[1].(map[string]interface{})["name"]; name != "shot_id" {
+		t.Errorf("expected shot_id, got %v", name)
+	}
+}
+
+func TestSplitCamelCase(t *testing.T) {
+	cases := map[string]string{
+		"ListScripts":          "List scripts",
+		"GetMCPStatus":         "Get MCP status",
+		"CreateLLMConfig":      "Create LLM config",
+		"ListScriptExecutions": "List script executions",
+		"Login":                "Login",
+	}
+	for in, want := range cases {
+		if got := splitCamelCase(in); got != want {
+			t.Errorf("splitCamelCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestHandlerOperationID(t *testing.T) {
+	if got := handlerOperationID("github.com/browserwing/browserwing/api.(*Handler).ListScripts-fm"); got != "ListScripts" {
+		t.Errorf("unexpected operation id %q", got)
+	}
+	if got := handlerOperationID("github.com/browserwing/browserwing/api.SetupRouter.func1"); got != "" {
+		t.Errorf("expected empty operation id for anonymous handler, got %q", got)
+	}
+	if got := pathOperationID("GET", "/api/v1/executor/semantic-tree"); got != "getExecutorSemanticTree" {
+		t.Errorf("unexpected path operation id %q", got)
+	}
+}
+
+func TestSchemaRegistry(t *testing.T) {
+	type node struct {
+		Name     string  `json:"name" binding:"required"`
+		Children []*node `json:"children,omitempty"`
+		Secret   string  `json:"-"`
+	}
+	schemas := newSchemaRegistry()
+	schema := schemas.valueSchema(openAPIObject{"data": []node{}})
+
+	items := schema["properties"].(map[string]interface{})["data"].(map[string]interface{})["items"]
+	if !reflect.DeepEqual(items, map[string]interface{}{"$ref": "#/components/schemas/Node"}) {
+		t.Fatalf("unexpected items schema %v", items)
+	}
+	component := schemas.components["Node"].(map[string]interface{})
+	properties := component["properties"].(map[string]interface{})
+	if _, ok := properties["Secret"]; ok {
+		t.Error("fields tagged json:\"-\" should be skipped")
+	}
+	if _, ok := properties["children"]; !ok {
+		t.Error("self-referencing field is missing")
+	}
+	if !reflect.DeepEqual(component["required"], []string{"name"}) {
+		t.Errorf("unexpected required fields %v", component["required"])
+	}
+}
+
+func TestOpenAPISpecEndpoint(t *testing.T) {
+	db, err := storage.NewBoltDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+
+	cfg := &config.Config{}
+	browserMgr := browser.NewManager(cfg, db, nil)
+	handler := NewHandler(db, browserMgr, cfg, nil)
+	handler.SetMCPServer(mcp.NewMCPServer(db, browserMgr))
+	r := SetupRouter(handler, nil, nil, false, false)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var doc struct {
+		OpenAPI    string                                       `json:"openapi"`
+		Paths      map[string]map[string]map[string]interface{} `json:"paths"`
+		Components struct {
+			Schemas map[string]interface{} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if doc.OpenAPI != "3.0.3" {
+		t.Errorf("unexpected openapi version %q", doc.OpenAPI)
+	}
+
+	// 所有 API 路由都在文档中，operationId 唯一
+	operationIDs := make(map[string]bool)
+	for _, route := range r.Routes() {
+		if !documentedRoute(route.Path) {
+			continue
+		}
+		path, _ := convertRoutePath(route.Path)
+		op, ok := doc.Paths[path][map[string]string{
+			http.MethodGet: "get", http.MethodPost: "post", http.MethodPut: "put", http.MethodDelete: "delete",
+		}[route.Method]]
+		if !ok {
+			t.Errorf("route %s %s is missing", route.Method, route.Path)
+			continue
+		}
+		id := op["operationId"].(string)
+		if operationIDs[id] {
+			t.Errorf("duplicate operationId %s", id)
+		}
+		operationIDs[id] = true
+	}
+	if _, ok := doc.Paths["/api/v1/mcp/sse"]; ok {
+		t.Error("MCP SSE endpoint should not be documented")
+	}
+
+	play := doc.Paths["/api/v1/scripts/{id}/play"]["post"]
+	body, _ := json.Marshal(play["requestBody"])
+	if !regexp.MustCompile(`"\$ref":"#/components/schemas/PlayScriptRequest"`).Match(body) {
+		t.Errorf("play request body does not reference PlayScriptRequest: %s", body)
+	}
+
+	// 引用的 schema 都存在
+	for _, m := range regexp.MustCompile(`"#/components/schemas/([^"]+)"`).FindAllSubmatch(w.Body.Bytes(), -1) {
+		if _, ok := doc.Components.Schemas[string(m[1])]; !ok {
+			t.Errorf("schema %s is referenced but not defined", m[1])
+		}
+	}
+}
@@ -4,14 +4,17 @@ import (
 	"io"
 	"io/fs"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/browserwing/browserwing/config"
+	"github.com/browserwing/browserwing/pkg/urlpolicy"
 	"github.com/browserwing/browserwing/storage"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/websocket"
 )
 
 func SetupRouter(handler *Handler, agentHandler interface{}, frontendFS fs.FS, embedMode, isDebug bool) *gin.Engine {
@@ -28,21 +31,34 @@ func SetupRouter(handler *Handler, agentHandler interface{}, frontendFS fs.FS, e
 	// TraceID 中间件 - 必须在其他中间件之前
 	r.Use(TraceIDMiddleware())
 
-	// CORS配置 - 允许所有来源（因为录制时可能访问任何网站）
-	r.Use(cors.New(cors.Config{
-		AllowAllOrigins:  true,
+	// CORS配置 - 未配置 allowed_origins 时允许所有来源（因为录制时可能访问任何网站）
+	corsConfig := cors.Config{
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Trace-ID"},
 		ExposeHeaders:    []string{"Content-Length", "X-Trace-ID"},
 		AllowCredentials: false, // AllowAllOrigins 为 true 时必须设置为 false
-	}))
+	}
+	if origins := allowedOrigins(handler.config); len(origins) > 0 {
+		corsConfig.AllowOrigins = origins
+	} else {
+		corsConfig.AllowAllOrigins = true
+	}
+	r.Use(cors.New(corsConfig))
 
 	// 健康检查
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
-	r.Static("/files/recordings", "./recordings")
+	// OpenAPI 文档（不需要认证），用于生成各语言的类型化客户端
+	r.GET("/openapi.json", OpenAPISpec(r))
+
+	// 执行录像等产物文件，开启认证时凭签名地址或 JWT、ApiKey 访问
+	files := r.Group("/files")
+	files.Use(SignedFileMiddleware(handler.config, handler.db))
+	{
+		files.GET("/*filepath", handler.ServeFile)
+	}
 
 	// 认证相关API（不需要认证）
 	auth := r.Group("/api/v1/auth")
@@ -66,6 +82,90 @@ func SetupRouter(handler *Handler, agentHandler interface{}, frontendFS fs.FS, e
 			prompts.POST("/:id/reset", handler.ResetPrompt) // 重置系统提示词
 		}
 
+		// 页面组件（Page Object：站点上具名元素的定位器，脚本通过 组件名.元素名 引用）
+		pageComponents := api.Group("/page-components")
+		{
+			pageComponents.GET("", handler.ListPageComponents)
+			pageComponents.GET("/:id", handler.GetPageComponent)
+			pageComponents.POST("", handler.CreatePageComponent)
+			pageComponents.PUT("/:id", handler.UpdatePageComponent)
+			pageComponents.DELETE("/:id", handler.DeletePageComponent)
+			pageComponents.GET("/:id/usages", handler.GetPageComponentUsages) // 引用该组件的脚本步骤
+		}
+
+		// 执行环境（基础 URL、凭据引用和参数默认值，执行脚本或定时任务时按名称选择）
+		environments := api.Group("/environments")
+		{
+			environments.GET("", handler.ListEnvironments)
+			environments.GET("/:id", handler.GetEnvironment)
+			environments.POST("", handler.CreateEnvironment)
+			environments.PUT("/:id", handler.UpdateEnvironment)
+			environments.DELETE("/:id", handler.DeleteEnvironment)
+		}
+
+		// 站点知识包（登录选择器、已知问题、访问频率限制等，Agent 打开匹配域名的页面时注入上下文）
+		siteKnowledge := api.Group("/site-knowledge")
+		{
+			siteKnowledge.GET("", handler.ListSiteKnowledge)
+			siteKnowledge.GET("/:id", handler.GetSiteKnowledge)
+			siteKnowledge.POST("", handler.CreateSiteKnowledge)
+			siteKnowledge.PUT("/:id", handler.UpdateSiteKnowledge)
+			siteKnowledge.DELETE("/:id", handler.DeleteSiteKnowledge)
+		}
+
+		// 通知渠道（Slack、Discord、Telegram、邮件）、路由规则（脚本失败、任务完成、内容变化时通知哪些渠道）和每日/每周执行报告摘要
+		notifications := api.Group("/notifications")
+		{
+			notifications.GET("/channels", handler.ListNotificationChannels)
+			notifications.GET("/channels/:id", handler.GetNotificationChannel)
+			notifications.POST("/channels", handler.CreateNotificationChannel)
+			notifications.PUT("/channels/:id", handler.UpdateNotificationChannel)
+			notifications.DELETE("/channels/:id", handler.DeleteNotificationChannel)
+			notifications.POST("/channels/:id/test", handler.TestNotificationChannel) // 发送测试消息
+			notifications.GET("/rules", handler.ListNotificationRules)
+			notifications.GET("/rules/:id", handler.GetNotificationRule)
+			notifications.POST("/rules", handler.CreateNotificationRule)
+			notifications.PUT("/rules/:id", handler.UpdateNotificationRule)
+			notifications.DELETE("/rules/:id", handler.DeleteNotificationRule)
+			notifications.GET("/digests", handler.ListNotificationDigests)
+			notifications.GET("/digests/:id", handler.GetNotificationDigest)
+			notifications.POST("/digests", handler.CreateNotificationDigest)
+			notifications.PUT("/digests/:id", handler.UpdateNotificationDigest)
+			notifications.DELETE("/digests/:id", handler.DeleteNotificationDigest)
+			notifications.GET("/digests/:id/preview", handler.PreviewNotificationDigest) // HTML 报告预览
+			notifications.POST("/digests/:id/send", handler.SendNotificationDigest)      // 立即发送
+		}
+
+		// 注入界面（录制器、浮动按钮、回放指示器）的自定义语言
+		uiLocales := api.Group("/ui-locales")
+		{
+			uiLocales.GET("", handler.ListUILocales)
+			uiLocales.GET("/:language", handler.GetUILocale)
+			uiLocales.GET("/:language/texts", handler.GetUILocaleTexts) // 生效的全部文本
+			uiLocales.PUT("/:language", handler.SaveUILocale)
+			uiLocales.DELETE("/:language", handler.DeleteUILocale)
+		}
+
+		// 脚本模板
+		scriptTemplates := api.Group("/templates")
+		{
+			scriptTemplates.GET("", handler.ListScriptTemplates)
+			scriptTemplates.GET("/:id", handler.GetScriptTemplate)
+			scriptTemplates.POST("/:id/install", handler.InstallScriptTemplate) // 用模板创建脚本
+		}
+
+		// 脚本市场
+		marketplaceAPI := api.Group("/marketplace")
+		{
+			marketplaceAPI.GET("/info", handler.GetMarketplaceInfo)
+			marketplaceAPI.GET("/bundles", handler.ListMarketplaceBundles)    // 仓库中的脚本包
+			marketplaceAPI.POST("/install", handler.InstallMarketplaceBundle) // 从仓库或脚本包文件安装
+			marketplaceAPI.GET("/installed", handler.ListInstalledBundles)
+			marketplaceAPI.DELETE("/installed/:id", handler.UninstallBundle)
+			marketplaceAPI.POST("/export", handler.ExportBundle)   // 导出脚本包
+			marketplaceAPI.POST("/publish", handler.PublishBundle) // 发布到仓库
+		}
+
 		// 浏览器相关
 		browserAPI := api.Group("/browser")
 		{
@@ -94,6 +194,9 @@ func SetupRouter(handler *Handler, agentHandler interface{}, frontendFS fs.FS, e
 			browserAPI.POST("/instances/:id/start", handler.StartBrowserInstance)
 			browserAPI.POST("/instances/:id/stop", handler.StopBrowserInstance)
 			browserAPI.POST("/instances/:id/switch", handler.SwitchBrowserInstance)
+			browserAPI.POST("/instances/:id/headless", handler.SetBrowserInstanceHeadless) // 切换 Headless 模式并重启（保留登录状态）
+			browserAPI.GET("/instances/:id/thumbnail", handler.GetInstanceThumbnail)         // 活动页面缩略图（JPEG）
+			browserAPI.GET("/instances/:id/thumbnails/ws", handler.StreamInstanceThumbnails) // 活动页面缩略图流（WebSocket）
 		}
 
 		// Cookie 管理
@@ -118,6 +221,21 @@ func SetupRouter(handler *Handler, agentHandler interface{}, frontendFS fs.FS, e
 			scripts.PUT("/:id", handler.UpdateScript)
 			scripts.DELETE("/:id", handler.DeleteScript)
 			scripts.GET("/play/result", handler.GetPlayResult) // 获取回放抓取的数据
+			scripts.POST("/lint", handler.LintScriptDraft)     // 静态检查未保存的脚本
+			scripts.GET("/:id/lint", handler.LintScript)       // 静态检查已保存的脚本
+
+			// 增量抓取状态
+			scripts.GET("/:id/state", handler.GetScriptState)      // 查询已输出的条目数和游标
+			scripts.PUT("/:id/state", handler.UpdateScriptState)   // 设置游标或清空已输出的条目
+			scripts.DELETE("/:id/state", handler.ResetScriptState) // 清空状态
+
+			// 抓取指标和异常
+			scripts.GET("/:id/metrics", handler.GetScriptMetrics)
+
+			// 选择器健康检查
+			scripts.GET("/health", handler.ListScriptHealth)       // 所有脚本最近一次的报告
+			scripts.GET("/:id/health", handler.GetScriptHealth)    // 最近一次的报告
+			scripts.POST("/:id/health", handler.CheckScriptHealth) // 立即检查
 
 			// MCP 命令相关
 			scripts.POST("/:id/mcp/generate", handler.GenerateMCPConfig) // AI 生成 MCP 配置
@@ -140,11 +258,44 @@ func SetupRouter(handler *Handler, agentHandler interface{}, frontendFS fs.FS, e
 			scriptsPlay.POST("/:id/play", handler.PlayScript)
 		}
 
+		// 自动化触发接口（n8n、Node-RED 等低代码平台）使用JWT或ApiKey认证
+		automation := r.Group("/api/v1/automation")
+		automation.Use(JWTOrApiKeyAuthenticationMiddleware(handler.config, handler.db))
+		{
+			automation.GET("/scripts", handler.ListAutomationScripts)        // 列出脚本及参数定义
+			automation.GET("/scripts/:id", handler.GetAutomationScript)      // 获取脚本参数定义
+			automation.POST("/scripts/:id/runs", handler.StartAutomationRun) // 异步执行脚本
+			automation.GET("/runs/:id", handler.GetAutomationRun)            // 查询执行状态和结果
+
+			automation.POST("/scripts/:id/bulk-runs", handler.StartBulkRun)        // 按多行参数批量执行
+			automation.GET("/bulk-runs/:id", handler.GetBulkRun)                   // 查询批量执行进度
+			automation.POST("/bulk-runs/:id/cancel", handler.CancelBulkRun)        // 取消尚未开始的行
+			automation.GET("/bulk-runs/:id/results", handler.ExportBulkRunResults) // 导出合并结果
+		}
+
+		// 定时任务计划执行日历，使用JWT或ApiKey认证；iCal 订阅地址可以通过 key 查询参数传递 ApiKey
+		calendar := r.Group("/api/v1/calendar")
+		calendar.Use(JWTOrApiKeyAuthenticationMiddleware(handler.config, handler.db))
+		{
+			calendar.GET("/runs", handler.ListScheduledRuns)     // 计划执行（JSON）
+			calendar.GET("/runs.ics", handler.ScheduledRunsICal) // 计划执行（iCalendar 订阅）
+		}
+
+		// 上传文件到受管存储区，远程触发执行时在 upload_file 中以句柄引用，使用JWT或ApiKey认证
+		uploads := r.Group("/api/v1/uploads")
+		uploads.Use(JWTOrApiKeyAuthenticationMiddleware(handler.config, handler.db))
+		{
+			uploads.POST("", handler.UploadFile)          // 上传文件（multipart 字段 file）
+			uploads.GET("", handler.ListUploads)          // 列出上传的文件
+			uploads.DELETE("/:id", handler.DeleteUpload) // 删除上传的文件
+		}
+
 		// 脚本执行记录相关
 		executions := api.Group("/script-executions")
 		{
 			executions.GET("", handler.ListScriptExecutions)                      // 列出执行记录（支持分页和搜索）
 			executions.GET("/:id", handler.GetScriptExecution)                    // 获取单个执行记录
+			executions.GET("/:id/compare", handler.CompareScriptExecutions)       // 与同一脚本的另一次执行对比
 			executions.DELETE("/:id", handler.DeleteScriptExecution)              // 删除执行记录
 			executions.POST("/batch/delete", handler.BatchDeleteScriptExecutions) // 批量删除
 		}
@@ -166,6 +317,8 @@ func SetupRouter(handler *Handler, agentHandler interface{}, frontendFS fs.FS, e
 			scheduledTasks.PUT("/:id", handler.UpdateScheduledTask)      // 更新定时任务
 			scheduledTasks.DELETE("/:id", handler.DeleteScheduledTask)   // 删除定时任务
 			scheduledTasks.POST("/:id/toggle", handler.ToggleScheduledTask) // 启用/禁用定时任务
+			scheduledTasks.GET("/:id/screenshots", handler.ListTaskScreenshots)                 // 截图存档时间线
+			scheduledTasks.GET("/:id/screenshots/:shot_id/image", handler.GetTaskScreenshotImage) // 截图存档图片
 		}
 
 		// 任务执行记录相关
@@ -196,10 +349,18 @@ func SetupRouter(handler *Handler, agentHandler interface{}, frontendFS fs.FS, e
 			llmConfigs.POST("/test", handler.TestLLMConfig)
 		}
 
+		// LLM 路由策略：把简单的步骤交给小模型
+		api.GET("/llm-routing", handler.GetLLMRouting)
+		api.PUT("/llm-routing", handler.UpdateLLMRouting)
+
 		// 录制配置管理
 		api.GET("/recording-config", handler.GetRecordingConfig)
 		api.PUT("/recording-config", handler.UpdateRecordingConfig)
 
+		// 产物存储占用与配额清理
+		api.GET("/storage/usage", handler.GetStorageUsage)
+		api.POST("/storage/cleanup", handler.CleanupStorage)
+
 		// 工具配置管理
 		toolConfigs := api.Group("/tool-configs")
 		{
@@ -243,8 +404,9 @@ func SetupRouter(handler *Handler, agentHandler interface{}, frontendFS fs.FS, e
 		}
 
 		// Executor HTTP API（使用 JWT 或 ApiKey 认证，支持外部调用）
+		// 通过 instance_id、tab_id、session_id（查询参数或 X-Instance-ID 等请求头）指定操作目标，同一目标上的请求依次执行
 		executorAPI := r.Group("/api/v1/executor")
-		executorAPI.Use(JWTOrApiKeyAuthenticationMiddleware(handler.config, handler.db))
+		executorAPI.Use(JWTOrApiKeyAuthenticationMiddleware(handler.config, handler.db), ExecutorTargetMiddleware())
 		{
 			// 帮助和命令列表
 			executorAPI.GET("/help", handler.ExecutorHelp)                // 获取所有可用命令和使用说明
@@ -255,9 +417,13 @@ func SetupRouter(handler *Handler, agentHandler interface{}, frontendFS fs.FS, e
 			executorAPI.POST("/click", handler.ExecutorClick)                     // 点击元素
 			executorAPI.POST("/type", handler.ExecutorType)                       // 输入文本
 			executorAPI.POST("/select", handler.ExecutorSelect)                   // 选择下拉框
+			executorAPI.POST("/choose-option", handler.ExecutorChooseOption)      // 选择自定义下拉框选项
+			executorAPI.POST("/fill-date", handler.ExecutorFillDate)              // 填写日期（设置值或操作日历控件）
 			executorAPI.POST("/hover", handler.ExecutorHover)                     // 鼠标悬停
+			executorAPI.POST("/hover-then-click", handler.ExecutorHoverThenClick) // 悬停后点击（悬停菜单）
 			executorAPI.POST("/wait", handler.ExecutorWaitFor)                    // 等待元素
 			executorAPI.POST("/scroll-to-bottom", handler.ExecutorScrollToBottom) // 滚动到底部
+			executorAPI.POST("/scroll", handler.ExecutorScroll)                   // 滚动页面或容器（相对、水平）
 			executorAPI.POST("/go-back", handler.ExecutorGoBack)                  // 后退
 			executorAPI.POST("/go-forward", handler.ExecutorGoForward)            // 前进
 			executorAPI.POST("/reload", handler.ExecutorReload)                   // 刷新页面
@@ -267,6 +433,14 @@ func SetupRouter(handler *Handler, agentHandler interface{}, frontendFS fs.FS, e
 			// 数据提取和获取
 			executorAPI.POST("/get-text", handler.ExecutorGetText)           // 获取元素文本
 			executorAPI.POST("/get-value", handler.ExecutorGetValue)         // 获取元素值
+			executorAPI.POST("/inspect", handler.ExecutorInspectElement)     // 检查元素（样式、可见性、遮挡）
+			executorAPI.POST("/audit", handler.ExecutorAudit)                // 页面审计（性能、SEO、可访问性）
+			executorAPI.POST("/a11y-scan", handler.ExecutorA11yScan)         // 可访问性扫描（axe-core WCAG 违规）
+			executorAPI.POST("/check-links", handler.ExecutorCheckLinks)     // 失效链接检查
+			executorAPI.POST("/translate", handler.ExecutorTranslatePage)    // 页面翻译（LLM）
+			executorAPI.POST("/read-image-text", handler.ExecutorReadImageText) // 图片文字识别（OCR）
+			executorAPI.POST("/click-at", handler.ExecutorClickAt)              // 坐标点击
+			executorAPI.POST("/drag-box", handler.ExecutorDragBox)              // 坐标拖拽
 			executorAPI.POST("/extract", handler.ExecutorExtract)            // 提取数据
 			executorAPI.GET("/page-info", handler.ExecutorGetPageInfo)       // 获取页面信息
 			executorAPI.GET("/page-content", handler.ExecutorGetPageContent) // 获取页面内容
@@ -286,6 +460,7 @@ func SetupRouter(handler *Handler, agentHandler interface{}, frontendFS fs.FS, e
 			// 标签页管理和表单填写
 			executorAPI.POST("/tabs", handler.ExecutorTabs)           // 标签页管理（list, new, switch, close）
 			executorAPI.POST("/fill-form", handler.ExecutorFillForm) // 批量填写表单
+			executorAPI.POST("/collect", handler.ExecutorCollect)    // 滚动/翻页采集列表条目
 
 			// 调试和监控
 			executorAPI.GET("/console-messages", handler.ExecutorConsoleMessages)     // 获取控制台消息
@@ -307,6 +482,12 @@ func SetupRouter(handler *Handler, agentHandler interface{}, frontendFS fs.FS, e
 				SetLLMConfig(c *gin.Context)
 				ReloadLLM(c *gin.Context)
 				GetMCPStatus(c *gin.Context)
+				ExportSession(c *gin.Context)
+				ReplayTranscript(c *gin.Context)
+				SetApprovalSettings(c *gin.Context)
+				ListApprovals(c *gin.Context)
+				ResolveApproval(c *gin.Context)
+				SetVerificationSettings(c *gin.Context)
 			}
 
 			if ah, ok := agentHandler.(AgentHandlerInterface); ok {
@@ -320,6 +501,15 @@ func SetupRouter(handler *Handler, agentHandler interface{}, frontendFS fs.FS, e
 					agentAPI.POST("/llm/set", ah.SetLLMConfig)              // 设置 LLM 配置
 					agentAPI.POST("/llm/reload", ah.ReloadLLM)              // 重新加载 LLM 配置
 					agentAPI.GET("/mcp/status", ah.GetMCPStatus)            // 获取 MCP 状态
+
+					agentAPI.GET("/sessions/:id/export", ah.ExportSession)    // 导出会话记录
+					agentAPI.POST("/transcripts/replay", ah.ReplayTranscript) // 导入会话记录并重放工具调用
+
+					agentAPI.PUT("/sessions/:id/approval-settings", ah.SetApprovalSettings)  // 设置需要批准的工具调用类别
+					agentAPI.GET("/sessions/:id/approvals", ah.ListApprovals)                // 等待批准的工具调用
+					agentAPI.POST("/sessions/:id/approvals/:approvalId", ah.ResolveApproval) // 批准或拒绝工具调用
+
+					agentAPI.PUT("/sessions/:id/verification", ah.SetVerificationSettings) // 开启或关闭操作校验
 				}
 			}
 		}
@@ -343,11 +533,12 @@ func SetupRouter(handler *Handler, agentHandler interface{}, frontendFS fs.FS, e
 						c.JSON(http.StatusUnauthorized, gin.H{"error": "error.unauthorized"})
 						return
 					}
-					_, err := handler.db.GetApiKeyByKey(apiKey)
+					key, err := handler.db.GetApiKeyByKey(apiKey)
 					if err != nil {
 						c.JSON(http.StatusUnauthorized, gin.H{"error": "error.invalidApiKey"})
 						return
 					}
+					c.Request = c.Request.WithContext(urlpolicy.WithPolicy(c.Request.Context(), key.URLPolicy))
 				}
 				handler.mcpServer.ServeSteamableHTTP(c.Writer, c.Request)
 				return
@@ -401,11 +592,12 @@ func SetupRouter(handler *Handler, agentHandler interface{}, frontendFS fs.FS, e
 						c.JSON(http.StatusUnauthorized, gin.H{"error": "error.unauthorized"})
 						return
 					}
-					_, err := handler.db.GetApiKeyByKey(apiKey)
+					key, err := handler.db.GetApiKeyByKey(apiKey)
 					if err != nil {
 						c.JSON(http.StatusUnauthorized, gin.H{"error": "error.invalidApiKey"})
 						return
 					}
+					c.Request = c.Request.WithContext(urlpolicy.WithPolicy(c.Request.Context(), key.URLPolicy))
 				}
 				handler.mcpServer.ServeSteamableHTTP(c.Writer, c.Request)
 				return
@@ -439,6 +631,46 @@ func GenerateJWT(userID, username string, config *config.Config) (string, error)
 	return token.SignedString([]byte(config.Auth.AppKey))
 }
 
+// queryTokenRoutes 允许通过 token 查询参数认证的 WebSocket 路由
+var queryTokenRoutes = map[string]bool{
+	"/api/v1/browser/instances/:id/thumbnails/ws": true,
+}
+
+// queryKeyRoutes 允许通过 key 查询参数传递 API Key 的路由（日历应用等无法设置请求头的订阅客户端）
+var queryKeyRoutes = map[string]bool{
+	"/api/v1/calendar/runs.ics": true,
+}
+
+// allowedOrigins 返回配置的跨域来源
+func allowedOrigins(cfg *config.Config) []string {
+	if cfg == nil || cfg.Server == nil {
+		return nil
+	}
+	return cfg.Server.AllowedOrigins
+}
+
+// checkWebSocketOrigin 检查 WebSocket 握手的 Origin：配置了 allowed_origins 时必须在列表中，否则必须与请求同源
+// 没有 Origin 头的请求来自非浏览器客户端，不受跨站攻击影响，直接放行
+func checkWebSocketOrigin(cfg *config.Config, r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if origins := allowedOrigins(cfg); len(origins) > 0 {
+		for _, allowed := range origins {
+			if strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+				return true
+			}
+		}
+		return false
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
 // JWTAuthenticationMiddleware JWT认证中间件
 func JWTAuthenticationMiddleware(config *config.Config, db *storage.BoltDB) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -448,6 +680,10 @@ func JWTAuthenticationMiddleware(config *config.Config, db *storage.BoltDB) gin.
 		}
 
 		tokenString := c.GetHeader("Authorization")
+		// 浏览器的 WebSocket 无法设置请求头，仅对缩略图流允许通过 token 查询参数传递
+		if tokenString == "" && queryTokenRoutes[c.FullPath()] && websocket.IsWebSocketUpgrade(c.Request) {
+			tokenString = c.Query("token")
+		}
 		if tokenString == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "error.unauthorized"})
 			c.Abort()
@@ -520,6 +756,8 @@ func ApiKeyAuthenticationMiddleware(config *config.Config, db *storage.BoltDB) g
 		// 将用户信息存入上下文
 		c.Set("user_id", key.UserID)
 		c.Set("api_key_id", key.ID)
+		// 将密钥的 URL 访问策略存入请求上下文，供 Executor/MCP 工具检查
+		c.Request = c.Request.WithContext(urlpolicy.WithPolicy(c.Request.Context(), key.URLPolicy))
 		c.Next()
 	}
 }
@@ -534,12 +772,16 @@ func JWTOrApiKeyAuthenticationMiddleware(config *config.Config, db *storage.Bolt
 
 		// 先尝试API Key认证
 		apiKey := c.GetHeader("X-BrowserWing-Key")
+		if apiKey == "" && queryKeyRoutes[c.FullPath()] {
+			apiKey = c.Query("key")
+		}
 		if apiKey != "" {
 			key, err := db.GetApiKeyByKey(apiKey)
 			if err == nil {
 				// API Key验证成功
 				c.Set("user_id", key.UserID)
 				c.Set("api_key_id", key.ID)
+				c.Request = c.Request.WithContext(urlpolicy.WithPolicy(c.Request.Context(), key.URLPolicy))
 				c.Next()
 				return
 			}
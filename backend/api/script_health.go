@@ -0,0 +1,71 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// checkScriptHealthRequest 立即检查脚本选择器的请求，请求体可以为空
+type checkScriptHealthRequest struct {
+	InstanceID string `json:"instance_id"` // 指定实例ID，空字符串表示使用当前实例
+}
+
+// ListScriptHealth 列出所有脚本最近一次的选择器健康报告，不健康的排在前面
+func (h *Handler) ListScriptHealth(c *gin.Context) {
+	reports, err := h.db.ListScriptHealthReports()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.getScriptHealthFailed", "detail": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": reports})
+}
+
+// GetScriptHealth 查询脚本最近一次的选择器健康报告
+func (h *Handler) GetScriptHealth(c *gin.Context) {
+	if _, err := h.db.GetScript(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.scriptNotFound"})
+		return
+	}
+	report, err := h.db.GetScriptHealthReport(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.scriptHealthNotFound"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// CheckScriptHealth 立即打开脚本的页面检查录制的选择器，保存并返回报告
+func (h *Handler) CheckScriptHealth(c *gin.Context) {
+	var req checkScriptHealthRequest
+	_ = c.ShouldBindJSON(&req)
+	instanceID := req.InstanceID
+	if instanceID == "" {
+		instanceID = c.Query("instance_id")
+	}
+
+	script, err := h.db.GetScript(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.scriptNotFound"})
+		return
+	}
+
+	if !h.browserManager.IsInstanceRunning(instanceID) {
+		logger.Info(c, "Browser not running, starting...")
+		if err := h.browserManager.StartInstance(c, instanceID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "error.checkScriptHealthFailed", "detail": err.Error()})
+			return
+		}
+	}
+
+	report, err := h.browserManager.CheckScriptHealth(c.Request.Context(), script, instanceID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.checkScriptHealthFailed", "detail": err.Error()})
+		return
+	}
+	if err := h.db.SaveScriptHealthReport(report); err != nil {
+		logger.Warn(c.Request.Context(), "Failed to save health report of script %s: %v", script.ID, err)
+	}
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
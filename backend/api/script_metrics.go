@@ -0,0 +1,51 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	scriptMetricsDefaultLimit = 50  // 默认返回的执行数
+	scriptMetricsMaxLimit     = 500 // 最多返回的执行数
+)
+
+// GetScriptMetrics 查询脚本最近执行的抓取指标（条目数、空值比例）和发现的异常，按开始时间倒序
+func (h *Handler) GetScriptMetrics(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := h.db.GetScript(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.scriptNotFound"})
+		return
+	}
+
+	limit := scriptMetricsDefaultLimit
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > scriptMetricsMaxLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams", "detail": fmt.Sprintf("limit must be between 1 and %d", scriptMetricsMaxLimit)})
+			return
+		}
+		limit = n
+	}
+
+	executions, err := h.db.RecentScriptExecutions(id, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.getScriptMetricsFailed", "detail": err.Error()})
+		return
+	}
+	points := make([]models.ScriptMetricsPoint, 0, len(executions))
+	for _, execution := range executions {
+		points = append(points, models.ScriptMetricsPoint{
+			ExecutionID: execution.ID,
+			StartTime:   execution.StartTime,
+			Success:     execution.Success,
+			Metrics:     execution.Metrics,
+			Anomalies:   execution.Anomalies,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"data": points})
+}
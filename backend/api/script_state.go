@@ -0,0 +1,66 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// updateScriptStateRequest 修改脚本增量抓取状态的请求
+type updateScriptStateRequest struct {
+	Cursor    *string `json:"cursor"`     // 新的游标，未指定时不修改
+	ClearSeen bool    `json:"clear_seen"` // 清空已输出过的条目，下次执行重新输出全部条目
+}
+
+// GetScriptState 查询脚本的增量抓取状态（已输出的条目数、游标和上次执行时间）
+func (h *Handler) GetScriptState(c *gin.Context) {
+	if _, err := h.db.GetScript(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.scriptNotFound"})
+		return
+	}
+	state, err := h.db.GetScriptState(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.getScriptStateFailed", "detail": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": state.Summary()})
+}
+
+// UpdateScriptState 设置游标或清空已输出的条目，例如从指定位置重新抓取
+func (h *Handler) UpdateScriptState(c *gin.Context) {
+	var req updateScriptStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams", "detail": err.Error()})
+		return
+	}
+	if _, err := h.db.GetScript(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.scriptNotFound"})
+		return
+	}
+
+	state, err := h.db.GetScriptState(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.getScriptStateFailed", "detail": err.Error()})
+		return
+	}
+	if req.Cursor != nil {
+		state.Cursor = *req.Cursor
+	}
+	if req.ClearSeen {
+		state.SeenKeys = nil
+	}
+	if err := h.db.SaveScriptState(state); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.saveScriptStateFailed", "detail": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": state.Summary()})
+}
+
+// ResetScriptState 清空脚本的增量抓取状态，下次执行输出全部条目
+func (h *Handler) ResetScriptState(c *gin.Context) {
+	if err := h.db.DeleteScriptState(c.Param("id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.saveScriptStateFailed", "detail": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success.scriptStateReset"})
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/services/templates"
+	"github.com/gin-gonic/gin"
+)
+
+// scriptTemplate 模板及其参数定义
+type scriptTemplate struct {
+	models.ScriptTemplate
+	// 安装和执行参数的 JSON Schema
+	Parameters map[string]interface{} `json:"parameters"`
+}
+
+func newScriptTemplate(tpl *models.ScriptTemplate) scriptTemplate {
+	return scriptTemplate{ScriptTemplate: *tpl, Parameters: templates.Parameters(tpl)}
+}
+
+// ListScriptTemplates 列出内置脚本模板
+func (h *Handler) ListScriptTemplates(c *gin.Context) {
+	list := templates.List()
+	result := make([]scriptTemplate, 0, len(list))
+	for i := range list {
+		if category := c.Query("category"); category != "" && list[i].Category != category {
+			continue
+		}
+		result = append(result, newScriptTemplate(&list[i]))
+	}
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// GetScriptTemplate 获取脚本模板
+func (h *Handler) GetScriptTemplate(c *gin.Context) {
+	tpl, ok := templates.Get(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.templateNotFound"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": newScriptTemplate(tpl)})
+}
+
+// InstallScriptTemplate 用模板创建脚本，参数值保存为脚本的预设变量
+func (h *Handler) InstallScriptTemplate(c *gin.Context) {
+	tpl, ok := templates.Get(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.templateNotFound"})
+		return
+	}
+
+	var opts templates.InstallOptions
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&opts); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams", "detail": err.Error()})
+			return
+		}
+	}
+	script, err := templates.Instantiate(tpl, opts)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams", "detail": err.Error()})
+		return
+	}
+	if err := h.db.SaveScript(script); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.saveScriptFailed"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "success.templateInstalled", "script": script})
+}
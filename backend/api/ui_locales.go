@@ -0,0 +1,141 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/services/browser"
+	"github.com/gin-gonic/gin"
+)
+
+// uiLanguagePattern 语言代码格式（BCP 47 的常见形式，如 fr、pt-BR、zh-Hant-HK）
+var uiLanguagePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// uiComponents 可以自定义文本的界面组件
+var uiComponents = []string{browser.UIRecorder, browser.UIFloatButton, browser.UIPlayer}
+
+// uiLocaleTexts 组件在某个语言下生效的全部文本
+type uiLocaleTexts struct {
+	Language    string            `json:"language"`
+	Recorder    map[string]string `json:"recorder"`
+	FloatButton map[string]string `json:"float_button"`
+	Player      map[string]string `json:"player"`
+}
+
+// validateUILocale 校验语言代码、基础语言和文本键，避免拼写错误的键静默失效
+// 部分文本通过 innerHTML 显示在页面上，因此不允许包含 < 和 >
+func validateUILocale(locale *models.UILocale) error {
+	if !uiLanguagePattern.MatchString(locale.Language) {
+		return fmt.Errorf("invalid language code %q", locale.Language)
+	}
+	if locale.Base != "" && !browser.IsBuiltinUILanguage(locale.Base) {
+		return fmt.Errorf("base must be a built-in language, got %q", locale.Base)
+	}
+	texts := map[string]map[string]string{
+		browser.UIRecorder:    locale.Recorder,
+		browser.UIFloatButton: locale.FloatButton,
+		browser.UIPlayer:      locale.Player,
+	}
+	for _, component := range uiComponents {
+		known := make(map[string]bool)
+		for _, key := range browser.UITextKeys(component) {
+			known[key] = true
+		}
+		var unknown []string
+		for key, text := range texts[component] {
+			if !known[key] {
+				unknown = append(unknown, key)
+			} else if strings.ContainsAny(text, "<>") {
+				return fmt.Errorf("%s text %s must not contain < or >", component, key)
+			}
+		}
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			return fmt.Errorf("unknown %s text keys: %s", component, strings.Join(unknown, ", "))
+		}
+	}
+	return nil
+}
+
+// ListUILocales 列出自定义的界面语言和内置语言
+func (h *Handler) ListUILocales(c *gin.Context) {
+	locales, err := h.db.ListUILocales()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.getUILocalesFailed", "detail": err.Error()})
+		return
+	}
+	builtin := make([]string, 0, len(browser.RecorderI18n))
+	for language := range browser.RecorderI18n {
+		builtin = append(builtin, language)
+	}
+	sort.Strings(builtin)
+	c.JSON(http.StatusOK, gin.H{"data": locales, "builtin": builtin})
+}
+
+// GetUILocale 获取自定义的界面语言
+func (h *Handler) GetUILocale(c *gin.Context) {
+	locale, err := h.db.GetUILocale(c.Param("language"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.uiLocaleNotFound"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": locale})
+}
+
+// GetUILocaleTexts 获取某个语言下生效的全部界面文本（内置文本叠加自定义文本），可作为翻译的起点
+func (h *Handler) GetUILocaleTexts(c *gin.Context) {
+	language := c.Param("language")
+	c.JSON(http.StatusOK, gin.H{"data": uiLocaleTexts{
+		Language:    language,
+		Recorder:    browser.UITexts(browser.UIRecorder, language),
+		FloatButton: browser.UITexts(browser.UIFloatButton, language),
+		Player:      browser.UITexts(browser.UIPlayer, language),
+	}})
+}
+
+// SaveUILocale 创建或替换界面语言，立即对之后注入的录制器、浮动按钮和回放指示器生效
+func (h *Handler) SaveUILocale(c *gin.Context) {
+	var locale models.UILocale
+	if err := c.ShouldBindJSON(&locale); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams", "detail": err.Error()})
+		return
+	}
+	locale.Language = c.Param("language")
+	if err := validateUILocale(&locale); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams", "detail": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	locale.CreatedAt = now
+	if existing, err := h.db.GetUILocale(locale.Language); err == nil {
+		locale.CreatedAt = existing.CreatedAt
+	}
+	locale.UpdatedAt = now
+	if err := h.db.SaveUILocale(&locale); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.saveUILocaleFailed", "detail": err.Error()})
+		return
+	}
+	browser.RegisterUILocale(&locale)
+	c.JSON(http.StatusOK, gin.H{"data": locale})
+}
+
+// DeleteUILocale 删除界面语言，内置语言恢复为内置文本
+func (h *Handler) DeleteUILocale(c *gin.Context) {
+	language := c.Param("language")
+	if _, err := h.db.GetUILocale(language); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.uiLocaleNotFound"})
+		return
+	}
+	if err := h.db.DeleteUILocale(language); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.deleteUILocaleFailed", "detail": err.Error()})
+		return
+	}
+	browser.UnregisterUILocale(language)
+	c.JSON(http.StatusOK, gin.H{"message": "success.uiLocaleDeleted"})
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UploadFile 上传文件到受管存储区（multipart 表单字段 file），返回在 upload_file 的 file_paths 中引用的句柄
+func (h *Handler) UploadFile(c *gin.Context) {
+	maxBytes := h.config.Storage.MaxUploadBytes()
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "error.uploadTooLarge", "detail": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams", "detail": err.Error()})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "error.invalidParams", "detail": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	uploaded, err := h.browserManager.SaveUpload(header.Filename, file)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.uploadFailed", "detail": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": uploaded})
+}
+
+// ListUploads 列出上传的文件
+func (h *Handler) ListUploads(c *gin.Context) {
+	files, err := h.browserManager.ListUploads()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error.listUploadsFailed", "detail": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": files})
+}
+
+// DeleteUpload 删除上传的文件
+func (h *Handler) DeleteUpload(c *gin.Context) {
+	if err := h.browserManager.DeleteUpload(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "error.fileNotFound", "detail": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success.uploadDeleted"})
+}
@@ -0,0 +1,22 @@
+// gen-bundle-key 生成脚本市场签名用的 Ed25519 密钥对
+//
+// 用法（在 backend 目录下）：go run ./cmd/gen-bundle-key
+// 私钥填入发布实例的 marketplace.signing_key，公钥加入安装实例的 marketplace.trusted_keys
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"log"
+)
+
+func main() {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		log.Fatalf("failed to generate key: %v", err)
+	}
+	fmt.Printf("signing_key = %q\n", base64.StdEncoding.EncodeToString(privateKey.Seed()))
+	fmt.Printf("public key  = %q\n", base64.StdEncoding.EncodeToString(publicKey))
+}
@@ -0,0 +1,72 @@
+// gen-clients 生成 OpenAPI 文档以及 Python/TypeScript 客户端的类型定义和接口列表
+//
+// 用法（在 backend 目录下）：go run ./cmd/gen-clients -out ../clients
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/browserwing/browserwing/agent"
+	"github.com/browserwing/browserwing/api"
+	"github.com/browserwing/browserwing/config"
+	"github.com/browserwing/browserwing/mcp"
+	"github.com/browserwing/browserwing/pkg/clientgen"
+	"github.com/browserwing/browserwing/services/browser"
+	"github.com/browserwing/browserwing/storage"
+	"github.com/gin-gonic/gin"
+)
+
+func main() {
+	out := flag.String("out", "../clients", "Output directory of the client packages")
+	flag.Parse()
+
+	gin.SetMode(gin.ReleaseMode)
+
+	// 路由注册需要完整的处理器，使用临时数据库，不启动浏览器和任何服务
+	tmpDir, err := os.MkdirTemp("", "browserwing-gen-clients")
+	if err != nil {
+		log.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	db, err := storage.NewBoltDB(filepath.Join(tmpDir, "browserwing.db"))
+	if err != nil {
+		log.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	cfg := &config.Config{}
+	browserMgr := browser.NewManager(cfg, db, nil)
+	handler := api.NewHandler(db, browserMgr, cfg, nil)
+	handler.SetMCPServer(mcp.NewMCPServer(db, browserMgr))
+	router := api.SetupRouter(handler, agent.NewHandler(nil), nil, false, false)
+
+	doc, err := json.MarshalIndent(api.OpenAPIDocument(router), "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to generate OpenAPI document: %v", err)
+	}
+	spec, err := clientgen.Parse(doc)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	files := map[string][]byte{
+		"openapi.json":                 append(doc, '\n'),
+		"typescript/src/models.ts":     clientgen.TypeScript(spec),
+		"python/browserwing/models.py": clientgen.Python(spec),
+	}
+	for name, data := range files {
+		path := filepath.Join(*out, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			log.Fatalf("Failed to create directory for %s: %v", path, err)
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			log.Fatalf("Failed to write %s: %v", path, err)
+		}
+		log.Printf("✓ Generated %s", path)
+	}
+}
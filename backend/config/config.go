@@ -1,7 +1,12 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/browserwing/browserwing/pkg/logger"
 	"github.com/pelletier/go-toml/v2"
@@ -17,6 +22,13 @@ type Config struct {
 	AssetsDir string               `json:"assets_dir,omitempty" yaml:"assets_dir,omitempty" toml:"assets_dir,omitempty"`
 	Log       *logger.LoggerConfig `json:"log,omitempty" yaml:"log,omitempty" toml:"log,omitempty"`
 	Auth      *AuthConfig          `json:"auth,omitempty" yaml:"auth,omitempty" toml:"auth,omitempty"`
+	Security  *SecurityConfig      `json:"security,omitempty" yaml:"security,omitempty" toml:"security,omitempty"`
+	Storage   *StorageConfig       `json:"storage,omitempty" yaml:"storage,omitempty" toml:"storage,omitempty"`
+	OCR       *OCRConfig           `json:"ocr,omitempty" yaml:"ocr,omitempty" toml:"ocr,omitempty"`
+	// 脚本市场（社区脚本包仓库）
+	Marketplace *MarketplaceConfig `json:"marketplace,omitempty" yaml:"marketplace,omitempty" toml:"marketplace,omitempty"`
+	// 浏览器操作的默认超时
+	Timeouts *TimeoutsConfig `json:"timeouts,omitempty" yaml:"timeouts,omitempty" toml:"timeouts,omitempty"`
 }
 
 type ServerConfig struct {
@@ -25,6 +37,10 @@ type ServerConfig struct {
 
 	MCPHost string `json:"mcp_host" toml:"mcp_host"`
 	MCPPort string `json:"mcp_port" toml:"mcp_port"`
+
+	// AllowedOrigins 允许跨域访问 API 的来源（如 "https://app.example.com"）
+	// 为空时 HTTP 接口允许所有来源，WebSocket 只接受同源连接
+	AllowedOrigins []string `json:"allowed_origins,omitempty" toml:"allowed_origins,omitempty"`
 }
 
 type DatabaseConfig struct {
@@ -43,6 +59,86 @@ type BrowserConfig struct {
 	BinPath     string `json:"bin_path" toml:"bin_path"`
 	UserDataDir string `json:"user_data_dir" toml:"user_data_dir"`
 	ControlURL  string `json:"control_url,omitempty" toml:"control_url,omitempty"` // 远程 Chrome DevTools URL，例如：ws://192.168.1.100:9222 或 http://192.168.1.100:9222
+	// MCP 会话隔离模式：shared（默认，所有会话共用活动页面）、page（每个会话独立页面）、context（每个会话独立浏览器上下文）
+	SessionIsolation string `json:"session_isolation,omitempty" toml:"session_isolation,omitempty"`
+	// 可访问性扫描使用的 axe-core 脚本（远程 URL 或本地文件路径），为空时从 jsDelivr 下载并缓存到 ./data/axe-core
+	AxeCore string `json:"axe_core,omitempty" toml:"axe_core,omitempty"`
+	// axe-core 脚本的 SRI 哈希（sha384-...），远程脚本必须配置，注入页面前校验（缓存的脚本同样校验）
+	AxeCoreSHA384 string `json:"axe_core_sha384,omitempty" toml:"axe_core_sha384,omitempty"`
+	// 录制脚本和浮动按钮注入页面主环境（旧行为），默认注入独立的隔离环境，避免与页面脚本互相干扰
+	MainWorldInjection bool `json:"main_world_injection,omitempty" toml:"main_world_injection,omitempty"`
+	// 广告/跟踪器拦截使用的过滤列表（是否启用由浏览器配置的 block_ads 决定）
+	AdBlock *AdBlockConfig `json:"adblock,omitempty" toml:"adblock,omitempty"`
+}
+
+// AdBlockConfig 广告/跟踪器拦截的过滤列表配置
+type AdBlockConfig struct {
+	// 过滤列表（Adblock Plus 语法），支持远程 URL 和本地文件路径，为空时使用 EasyList 和 EasyPrivacy
+	FilterLists []string `json:"filter_lists,omitempty" toml:"filter_lists,omitempty"`
+	// 远程列表的缓存目录，默认 ./data/filterlists
+	CacheDir string `json:"cache_dir,omitempty" toml:"cache_dir,omitempty"`
+	// 远程列表的缓存有效期（小时），默认 24
+	RefreshHours int `json:"refresh_hours,omitempty" toml:"refresh_hours,omitempty"`
+}
+
+// AxeCoreSource 获取 axe-core 脚本来源，未配置时返回空（由调用方使用默认来源）
+func (b *BrowserConfig) AxeCoreSource() string {
+	if b == nil {
+		return ""
+	}
+	return b.AxeCore
+}
+
+// AxeCoreIntegrity 获取 axe-core 脚本的 SRI 哈希，未配置时返回空
+func (b *BrowserConfig) AxeCoreIntegrity() string {
+	if b == nil {
+		return ""
+	}
+	return strings.TrimSpace(b.AxeCoreSHA384)
+}
+
+// AdBlockCacheDir 获取过滤列表缓存目录
+func (b *BrowserConfig) AdBlockCacheDir() string {
+	if b == nil || b.AdBlock == nil || b.AdBlock.CacheDir == "" {
+		return "./data/filterlists"
+	}
+	return b.AdBlock.CacheDir
+}
+
+// AdBlockRefreshInterval 获取过滤列表缓存有效期
+func (b *BrowserConfig) AdBlockRefreshInterval() time.Duration {
+	if b == nil || b.AdBlock == nil || b.AdBlock.RefreshHours <= 0 {
+		return 24 * time.Hour
+	}
+	return time.Duration(b.AdBlock.RefreshHours) * time.Hour
+}
+
+// AdBlockFilterLists 获取配置的过滤列表，未配置时返回 nil（由调用方使用默认列表）
+func (b *BrowserConfig) AdBlockFilterLists() []string {
+	if b == nil || b.AdBlock == nil {
+		return nil
+	}
+	return b.AdBlock.FilterLists
+}
+
+// 会话隔离模式
+const (
+	SessionIsolationShared  = "shared"
+	SessionIsolationPage    = "page"
+	SessionIsolationContext = "context"
+)
+
+// SessionIsolationMode 获取会话隔离模式，未配置或无效时返回 shared
+func (b *BrowserConfig) SessionIsolationMode() string {
+	if b == nil {
+		return SessionIsolationShared
+	}
+	switch b.SessionIsolation {
+	case SessionIsolationPage, SessionIsolationContext:
+		return b.SessionIsolation
+	default:
+		return SessionIsolationShared
+	}
 }
 
 func Load(path string) (*Config, error) {
@@ -104,6 +200,8 @@ func Load(path string) (*Config, error) {
 				DefaultUsername: "admin",
 				DefaultPassword: "admin123",
 			},
+			Security: defaultSecurityConfig(),
+			Storage:  defaultStorageConfig(),
 		}
 		// 如果错误是文件不存在，则将defConfig写到本地的path位置
 		if os.IsNotExist(err) {
@@ -143,6 +241,16 @@ func Load(path string) (*Config, error) {
 		}
 	}
 
+	if cfg.Security == nil {
+		cfg.Security = defaultSecurityConfig()
+	}
+	if err := cfg.Security.Evaluate.Compile(); err != nil {
+		return nil, fmt.Errorf("invalid security.evaluate config: %w", err)
+	}
+	if cfg.Storage == nil {
+		cfg.Storage = defaultStorageConfig()
+	}
+
 	// 兼容处理：如果没有配置 LLMs 数组，但配置了单个 LLM，则转换为数组
 	if len(cfg.LLMs) == 0 && cfg.LLM != nil {
 		cfg.LLMs = []LLMConfig{*cfg.LLM}
@@ -200,3 +308,288 @@ type AuthConfig struct {
 	DefaultUsername string `json:"default_username" toml:"default_username"`
 	DefaultPassword string `json:"default_password" toml:"default_password"`
 }
+
+// SecurityConfig 安全相关配置
+type SecurityConfig struct {
+	// 是否阻止访问内网地址（RFC1918、回环、链路本地、云元数据服务等），默认开启
+	BlockPrivateNetworks *bool `json:"block_private_networks,omitempty" toml:"block_private_networks,omitempty"`
+	// 内网访问例外，支持域名规则（如 intranet.example.com）、IP 或 CIDR
+	AllowedPrivateHosts []string `json:"allowed_private_hosts,omitempty" toml:"allowed_private_hosts,omitempty"`
+	// Evaluate（执行 JavaScript）沙箱策略
+	Evaluate *EvaluateConfig `json:"evaluate,omitempty" toml:"evaluate,omitempty"`
+}
+
+// EvaluateConfig Evaluate 操作的沙箱策略，未配置时不做限制
+type EvaluateConfig struct {
+	// 完全禁用 Evaluate（MCP 中也不再注册 browser_evaluate 工具）
+	Disabled bool `json:"disabled" toml:"disabled"`
+	// 脚本最大长度（字符数），0 表示不限制
+	MaxScriptLength int `json:"max_script_length,omitempty" toml:"max_script_length,omitempty"`
+	// 允许执行的脚本签名（正则表达式，需完整匹配规范化空白后的脚本），为空表示不限制
+	AllowedSignatures []string `json:"allowed_signatures,omitempty" toml:"allowed_signatures,omitempty"`
+	// 只读模式：拒绝明显修改 DOM 的脚本
+	// 仅对脚本源码做静态检查，属于提示性限制：通过别名或动态属性名（如 d['inner'+'HTML']）可以绕过，
+	// 需要强制限制时请使用 AllowedSignatures 白名单
+	ReadOnly bool `json:"read_only" toml:"read_only"`
+
+	signatures []*regexp.Regexp // 加载配置时编译的签名规则
+}
+
+var evaluateWhitespace = regexp.MustCompile(`\s+`)
+
+// NormalizeEvaluateScript 规范化脚本空白字符，便于签名匹配
+func NormalizeEvaluateScript(script string) string {
+	return evaluateWhitespace.ReplaceAllString(strings.TrimSpace(script), " ")
+}
+
+// Compile 编译 AllowedSignatures，任一规则无效时返回错误（加载配置时调用，避免错误规则被静默忽略）
+func (e *EvaluateConfig) Compile() error {
+	if e == nil {
+		return nil
+	}
+	signatures, err := compileSignatures(e.AllowedSignatures)
+	if err != nil {
+		return err
+	}
+	e.signatures = signatures
+	return nil
+}
+
+// Signatures 获取编译后的签名规则；未经 Compile 的配置会即时编译
+func (e *EvaluateConfig) Signatures() ([]*regexp.Regexp, error) {
+	if e.signatures != nil || len(e.AllowedSignatures) == 0 {
+		return e.signatures, nil
+	}
+	return compileSignatures(e.AllowedSignatures)
+}
+
+func compileSignatures(rules []string) ([]*regexp.Regexp, error) {
+	signatures := make([]*regexp.Regexp, 0, len(rules))
+	for _, sig := range rules {
+		re, err := regexp.Compile(`^(?:` + NormalizeEvaluateScript(sig) + `)$`)
+		if err != nil {
+			return nil, fmt.Errorf("invalid evaluate signature %q: %w", sig, err)
+		}
+		signatures = append(signatures, re)
+	}
+	return signatures, nil
+}
+
+// IsPrivateNetworkBlocked 是否启用内网访问防护（未配置时默认开启）
+func (s *SecurityConfig) IsPrivateNetworkBlocked() bool {
+	if s == nil || s.BlockPrivateNetworks == nil {
+		return true
+	}
+	return *s.BlockPrivateNetworks
+}
+
+// PrivateHostAllowlist 获取允许访问的内网例外列表
+func (s *SecurityConfig) PrivateHostAllowlist() []string {
+	if s == nil {
+		return nil
+	}
+	return s.AllowedPrivateHosts
+}
+
+// EvaluatePolicy 获取 Evaluate 沙箱策略，未配置时返回 nil
+func (s *SecurityConfig) EvaluatePolicy() *EvaluateConfig {
+	if s == nil {
+		return nil
+	}
+	return s.Evaluate
+}
+
+// IsEvaluateDisabled 是否完全禁用 Evaluate
+func (s *SecurityConfig) IsEvaluateDisabled() bool {
+	p := s.EvaluatePolicy()
+	return p != nil && p.Disabled
+}
+
+func defaultSecurityConfig() *SecurityConfig {
+	blockPrivateNetworks := true
+	return &SecurityConfig{
+		BlockPrivateNetworks: &blockPrivateNetworks,
+	}
+}
+
+// StorageConfig 截图、下载文件等产物的存储配置
+type StorageConfig struct {
+	// 截图根目录，默认 ./screenshots
+	ScreenshotsDir string `json:"screenshots_dir,omitempty" toml:"screenshots_dir,omitempty"`
+	// 下载文件根目录，默认 ./downloads
+	DownloadsDir string `json:"downloads_dir,omitempty" toml:"downloads_dir,omitempty"`
+	// 上传文件根目录（拖放、上传操作只能读取该目录下的文件），默认 ./uploads
+	UploadsDir string `json:"uploads_dir,omitempty" toml:"uploads_dir,omitempty"`
+	// 根目录下的子目录模板，支持 {script}、{script_id}、{date}、{time}、{execution}、{task}
+	// 例如 "{script}/{date}/{execution}"，为空时直接保存在根目录下
+	PathTemplate string `json:"path_template,omitempty" toml:"path_template,omitempty"`
+	// 各目录的容量配额（MB），超出后按修改时间从旧到新删除文件，0 表示不限制
+	DownloadsQuotaMB   int64 `json:"downloads_quota_mb,omitempty" toml:"downloads_quota_mb,omitempty"`
+	ScreenshotsQuotaMB int64 `json:"screenshots_quota_mb,omitempty" toml:"screenshots_quota_mb,omitempty"`
+	RecordingsQuotaMB  int64 `json:"recordings_quota_mb,omitempty" toml:"recordings_quota_mb,omitempty"`
+	// 执行工作目录的根目录：每次执行在其中创建临时目录，存放上传前下载的文件、录屏帧等中间文件，
+	// 执行结束后删除，默认为系统临时目录下的 browserwing
+	WorkDir string `json:"work_dir,omitempty" toml:"work_dir,omitempty"`
+	// 执行失败时保留工作目录（路径记录在执行记录的 work_dir 中），便于排查
+	KeepWorkDirOnFailure bool `json:"keep_work_dir_on_failure,omitempty" toml:"keep_work_dir_on_failure,omitempty"`
+	// 上传接口单个文件的大小上限（MB），默认 100
+	MaxUploadMB int64 `json:"max_upload_mb,omitempty" toml:"max_upload_mb,omitempty"`
+	// 开启认证时 /files/ 下产物（执行录像等）签名地址的有效期（分钟），默认 60
+	SignedURLTTLMinutes int `json:"signed_url_ttl_minutes,omitempty" toml:"signed_url_ttl_minutes,omitempty"`
+}
+
+// OCRConfig 图片文字识别（read_image_text）配置
+type OCRConfig struct {
+	// 识别后端：tesseract（默认，调用本地 tesseract 命令）或 http（调用 OCR 服务接口）
+	Backend string `json:"backend,omitempty" toml:"backend,omitempty"`
+	// tesseract 可执行文件路径，默认从 PATH 中查找
+	TesseractPath string `json:"tesseract_path,omitempty" toml:"tesseract_path,omitempty"`
+	// tesseract 识别语言，多个语言用 + 连接，如 "eng+chi_sim"，默认 eng
+	Languages string `json:"languages,omitempty" toml:"languages,omitempty"`
+	// http 后端的接口地址：以 POST 发送 PNG 图片，返回 {"text": "...", "words": [...]}
+	APIURL string `json:"api_url,omitempty" toml:"api_url,omitempty"`
+	// http 后端的 API Key（以 Bearer Token 发送）
+	APIKey string `json:"api_key,omitempty" toml:"api_key,omitempty"`
+	// 单次识别超时（秒），默认 60
+	TimeoutSeconds int `json:"timeout_seconds,omitempty" toml:"timeout_seconds,omitempty"`
+}
+
+// TimeoutsConfig 浏览器操作的默认超时（秒），未配置或不大于 0 时使用默认值
+// 内网慢速应用可以调大，快速抓取可以调小；调用时显式传入的超时和脚本的 timeouts 设置优先
+type TimeoutsConfig struct {
+	// 等待元素出现（点击、输入、选择等操作查找元素）的超时，默认 10
+	ElementSeconds int `json:"element_seconds,omitempty" toml:"element_seconds,omitempty"`
+	// 打开页面（导航并等待加载）的超时，默认 60
+	NavigationSeconds int `json:"navigation_seconds,omitempty" toml:"navigation_seconds,omitempty"`
+	// 导航后获取页面快照的超时，默认 10
+	SnapshotSeconds int `json:"snapshot_seconds,omitempty" toml:"snapshot_seconds,omitempty"`
+}
+
+// Element 获取等待元素出现的超时
+func (t *TimeoutsConfig) Element() time.Duration {
+	if t == nil || t.ElementSeconds <= 0 {
+		return 10 * time.Second
+	}
+	return time.Duration(t.ElementSeconds) * time.Second
+}
+
+// Navigation 获取打开页面的超时
+func (t *TimeoutsConfig) Navigation() time.Duration {
+	if t == nil || t.NavigationSeconds <= 0 {
+		return 60 * time.Second
+	}
+	return time.Duration(t.NavigationSeconds) * time.Second
+}
+
+// Snapshot 获取导航后获取页面快照的超时
+func (t *TimeoutsConfig) Snapshot() time.Duration {
+	if t == nil || t.SnapshotSeconds <= 0 {
+		return 10 * time.Second
+	}
+	return time.Duration(t.SnapshotSeconds) * time.Second
+}
+
+// MarketplaceConfig 脚本市场配置：从仓库浏览和安装社区脚本包，或把本实例的脚本发布到仓库
+type MarketplaceConfig struct {
+	// 仓库地址，索引位于 {registry_url}/index.json，发布地址为 {registry_url}/bundles
+	RegistryURL string `json:"registry_url,omitempty" toml:"registry_url,omitempty"`
+	// 可信发布者的 Ed25519 公钥（base64），只允许安装由这些公钥签名的脚本包
+	TrustedKeys []string `json:"trusted_keys,omitempty" toml:"trusted_keys,omitempty"`
+	// 允许安装未签名或签名者不在可信列表中的脚本包（签名无效的脚本包始终拒绝）
+	AllowUnsigned bool `json:"allow_unsigned,omitempty" toml:"allow_unsigned,omitempty"`
+	// 本实例导出和发布脚本包时使用的 Ed25519 私钥（base64 编码的 32 字节种子），为空时不签名
+	SigningKey string `json:"signing_key,omitempty" toml:"signing_key,omitempty"`
+	// 发布到仓库时使用的 Bearer Token
+	PublishToken string `json:"publish_token,omitempty" toml:"publish_token,omitempty"`
+}
+
+// 产物目录类型
+const (
+	ArtifactDownloads   = "downloads"
+	ArtifactScreenshots = "screenshots"
+	ArtifactRecordings  = "recordings"
+)
+
+// QuotaBytes 获取指定产物目录的配额（字节），0 表示不限制
+func (s *StorageConfig) QuotaBytes(kind string) int64 {
+	if s == nil {
+		return 0
+	}
+	var mb int64
+	switch kind {
+	case ArtifactDownloads:
+		mb = s.DownloadsQuotaMB
+	case ArtifactScreenshots:
+		mb = s.ScreenshotsQuotaMB
+	case ArtifactRecordings:
+		mb = s.RecordingsQuotaMB
+	}
+	if mb <= 0 {
+		return 0
+	}
+	return mb * 1024 * 1024
+}
+
+// ScreenshotsRoot 获取截图根目录
+func (s *StorageConfig) ScreenshotsRoot() string {
+	if s == nil || s.ScreenshotsDir == "" {
+		return "./screenshots"
+	}
+	return s.ScreenshotsDir
+}
+
+// DownloadsRoot 获取下载文件根目录
+func (s *StorageConfig) DownloadsRoot() string {
+	if s == nil || s.DownloadsDir == "" {
+		return "./downloads"
+	}
+	return s.DownloadsDir
+}
+
+// UploadsRoot 获取上传文件根目录
+func (s *StorageConfig) UploadsRoot() string {
+	if s == nil || s.UploadsDir == "" {
+		return "./uploads"
+	}
+	return s.UploadsDir
+}
+
+// WorkRoot 获取执行工作目录的根目录
+func (s *StorageConfig) WorkRoot() string {
+	if s == nil || s.WorkDir == "" {
+		return filepath.Join(os.TempDir(), "browserwing")
+	}
+	return s.WorkDir
+}
+
+// MaxUploadBytes 获取上传接口单个文件的大小上限（字节）
+func (s *StorageConfig) MaxUploadBytes() int64 {
+	if s == nil || s.MaxUploadMB <= 0 {
+		return 100 << 20
+	}
+	return s.MaxUploadMB << 20
+}
+
+// SignedURLTTL 获取产物签名地址的有效期
+func (s *StorageConfig) SignedURLTTL() time.Duration {
+	if s == nil || s.SignedURLTTLMinutes <= 0 {
+		return time.Hour
+	}
+	return time.Duration(s.SignedURLTTLMinutes) * time.Minute
+}
+
+// SubdirTemplate 获取子目录模板
+func (s *StorageConfig) SubdirTemplate() string {
+	if s == nil {
+		return ""
+	}
+	return s.PathTemplate
+}
+
+func defaultStorageConfig() *StorageConfig {
+	return &StorageConfig{
+		ScreenshotsDir: "./screenshots",
+		DownloadsDir:   "./downloads",
+		UploadsDir:     "./uploads",
+	}
+}
@@ -0,0 +1,69 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/services/browser"
+)
+
+// A11yScan 使用 axe-core 扫描当前页面的 WCAG 违规
+func (e *Executor) A11yScan(ctx context.Context, opts *browser.A11yScanOptions) (*OperationResult, error) {
+	page := e.activePage(ctx)
+	if page == nil {
+		return nil, fmt.Errorf("no active page")
+	}
+
+	result, err := e.Browser.ScanAccessibility(ctx, page, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, v := range result.Violations {
+		counts[v.Impact]++
+	}
+	var summary []string
+	for _, impact := range []string{"critical", "serious", "moderate", "minor"} {
+		if counts[impact] > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", counts[impact], impact))
+		}
+	}
+	message := fmt.Sprintf("No accessibility violations found on %s", result.URL)
+	if len(result.Violations) > 0 {
+		message = fmt.Sprintf("Found %d accessibility violations on %s (%s)", len(result.Violations), result.URL, strings.Join(summary, ", "))
+	}
+
+	return &OperationResult{
+		Success: true,
+		Message: message,
+		Data: map[string]interface{}{
+			"url":        result.URL,
+			"engine":     result.Engine,
+			"violations": result.Violations,
+			"passes":     result.Passes,
+			"incomplete": result.Incomplete,
+		},
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// ParseA11yScanArguments 解析 browser_a11y_scan 工具参数
+func ParseA11yScanArguments(args map[string]interface{}) *browser.A11yScanOptions {
+	opts := &browser.A11yScanOptions{}
+	if tags, ok := args["tags"].([]interface{}); ok {
+		for _, t := range tags {
+			if tag, ok := t.(string); ok && tag != "" {
+				opts.Tags = append(opts.Tags, tag)
+			}
+		}
+	}
+	opts.Include, _ = args["selector"].(string)
+	opts.MinImpact, _ = args["min_impact"].(string)
+	if maxNodes, ok := args["max_nodes"].(float64); ok {
+		opts.MaxNodes = int(maxNodes)
+	}
+	return opts
+}
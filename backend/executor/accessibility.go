@@ -3,6 +3,10 @@ package executor
 import (
 	"context"
 	"fmt"
+	"iter"
+	"math"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/browserwing/browserwing/pkg/logger"
@@ -481,102 +485,155 @@ func (tree *AccessibilitySnapshot) GetInputElements() []*AccessibilityNode {
 
 // SerializeToSimpleText 将语义树序列化为简单文本（用于 LLM）
 func (tree *AccessibilitySnapshot) SerializeToSimpleText() string {
+	return tree.SerializeWithGeometry(nil)
+}
+
+// SerializeWithGeometry 序列化为简单文本，geometry 不为空时在每个元素后附加边界框和视口可见性
+func (tree *AccessibilitySnapshot) SerializeWithGeometry(geometry *SnapshotGeometry) string {
 	var builder strings.Builder
-	
-	// 标题和说明
-	builder.WriteString("=== Interactive Elements ===\n")
-	builder.WriteString("Use RefIDs (e.g., @e1, @e2) as identifiers for interactions.\n\n")
-
-	// 按类型分组
-	clickable := tree.GetClickableElements()
-	inputs := tree.GetInputElements()
-
-	// 可点击元素
-	if len(clickable) > 0 {
-		builder.WriteString("CLICKABLE:\n")
-		for _, node := range clickable {
-			// 生成标签（限制长度避免混淆）
-			label := node.Label
-			if label == "" {
-				label = node.Text
-			}
-			if label == "" {
-				label = node.Description
-			}
-			if label == "" {
-				label = fmt.Sprintf("<%s>", node.Role)
+	for line := range tree.simpleTextLines(geometry) {
+		builder.WriteString(line)
+	}
+	return builder.String()
+}
+
+// simpleTextLines 逐行生成简单文本（每行以换行符结尾），分页和流式输出不需要先拼出完整文本
+func (tree *AccessibilitySnapshot) simpleTextLines(geometry *SnapshotGeometry) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		// 标题和说明
+		header := "=== Interactive Elements ===\n" +
+			"Use RefIDs (e.g., @e1, @e2) as identifiers for interactions.\n"
+		if geometry != nil {
+			header += fmt.Sprintf("Boxes are viewport CSS pixels (viewport %.0fx%.0f, scrolled to %.0f,%.0f); \"offscreen\" elements need scrolling.\n",
+				geometry.ViewportWidth, geometry.ViewportHeight, geometry.ScrollX, geometry.ScrollY)
+		}
+		for _, line := range strings.SplitAfter(header+"\n", "\n") {
+			if line != "" && !yield(line) {
+				return
 			}
-			
-			// 截断过长的标签
-			if len(label) > 50 {
-				label = label[:47] + "..."
+		}
+
+		// 按类型分组，组内按 RefID 编号排序，多次序列化（分页）的顺序保持一致
+		clickable := sortByRefID(tree.GetClickableElements())
+		inputs := sortByRefID(tree.GetInputElements())
+
+		// 可点击元素
+		if len(clickable) > 0 {
+			if !yield("CLICKABLE:\n") {
+				return
 			}
+			for _, node := range clickable {
+				// 生成标签（限制长度避免混淆）
+				label := node.Label
+				if label == "" {
+					label = node.Text
+				}
+				if label == "" {
+					label = node.Description
+				}
+				if label == "" {
+					label = fmt.Sprintf("<%s>", node.Role)
+				}
 
-			// 清晰格式：RefID 在前，用破折号分隔
-			if node.RefID != "" {
-				builder.WriteString(fmt.Sprintf("  @%s - %s", node.RefID, label))
-				
+				// 截断过长的标签
+				if len(label) > 50 {
+					label = label[:47] + "..."
+				}
+
+				// 清晰格式：RefID 在前，用破折号分隔
+				if node.RefID == "" {
+					continue
+				}
+				line := fmt.Sprintf("  @%s - %s", node.RefID, label)
 				// 角色信息简化
 				if node.Role != "" && node.Role != "StaticText" {
-					builder.WriteString(fmt.Sprintf(" (%s)", node.Role))
+					line += fmt.Sprintf(" (%s)", node.Role)
 				}
-				builder.WriteString("\n")
+				if !yield(line + geometry.describe(node.RefID) + "\n") {
+					return
+				}
+			}
+			if !yield("\n") {
+				return
 			}
 		}
-		builder.WriteString("\n")
-	}
 
-	// 输入元素
-	if len(inputs) > 0 {
-		builder.WriteString("INPUT:\n")
-		for _, node := range inputs {
-			// 生成标签
-			label := node.Label
-			if label == "" {
-				label = node.Placeholder
-			}
-			if label == "" {
-				label = node.Description
-			}
-			if label == "" {
-				label = fmt.Sprintf("<%s>", node.Role)
-			}
-			
-			// 截断过长的标签
-			if len(label) > 50 {
-				label = label[:47] + "..."
+		// 输入元素
+		if len(inputs) > 0 {
+			if !yield("INPUT:\n") {
+				return
 			}
+			for _, node := range inputs {
+				// 生成标签
+				label := node.Label
+				if label == "" {
+					label = node.Placeholder
+				}
+				if label == "" {
+					label = node.Description
+				}
+				if label == "" {
+					label = fmt.Sprintf("<%s>", node.Role)
+				}
 
-			// 清晰格式
-			if node.RefID != "" {
-				builder.WriteString(fmt.Sprintf("  @%s - %s", node.RefID, label))
-				
+				// 截断过长的标签
+				if len(label) > 50 {
+					label = label[:47] + "..."
+				}
+
+				// 清晰格式
+				if node.RefID == "" {
+					continue
+				}
+				line := fmt.Sprintf("  @%s - %s", node.RefID, label)
 				// 角色信息
 				if node.Role != "" {
-					builder.WriteString(fmt.Sprintf(" (%s)", node.Role))
+					line += fmt.Sprintf(" (%s)", node.Role)
 				}
-				
 				// 占位符和值
 				if node.Placeholder != "" && node.Placeholder != label {
-					builder.WriteString(fmt.Sprintf(" [placeholder: %s]", node.Placeholder))
+					line += fmt.Sprintf(" [placeholder: %s]", node.Placeholder)
 				}
 				if node.Value != "" {
-					builder.WriteString(fmt.Sprintf(" [value: %s]", node.Value))
+					line += fmt.Sprintf(" [value: %s]", node.Value)
+				}
+				if !yield(line + geometry.describe(node.RefID) + "\n") {
+					return
 				}
-				builder.WriteString("\n")
+			}
+			if !yield("\n") {
+				return
+			}
+		}
+
+		// 重要提示
+		for _, line := range []string{
+			"USAGE:\n",
+			"  • Click: {\"identifier\": \"@e1\"}  ✓ Correct\n",
+			"  • Type:  {\"identifier\": \"@e5\", \"text\": \"hello\"}  ✓ Correct\n",
+			"  • DO NOT use text labels as identifiers  ✗ Wrong\n",
+			"  • ALWAYS use the RefID format (@e1, @e2, etc.)  ✓ Required\n",
+		} {
+			if !yield(line) {
+				return
 			}
 		}
-		builder.WriteString("\n")
 	}
-	
-	// 重要提示
-	builder.WriteString("USAGE:\n")
-	builder.WriteString("  • Click: {\"identifier\": \"@e1\"}  ✓ Correct\n")
-	builder.WriteString("  • Type:  {\"identifier\": \"@e5\", \"text\": \"hello\"}  ✓ Correct\n")
-	builder.WriteString("  • DO NOT use text labels as identifiers  ✗ Wrong\n")
-	builder.WriteString("  • ALWAYS use the RefID format (@e1, @e2, etc.)  ✓ Required\n")
+}
 
-	return builder.String()
+// sortByRefID 按 RefID 编号排序（e2 在 e10 之前），没有 RefID 的节点排在最后
+func sortByRefID(nodes []*AccessibilityNode) []*AccessibilityNode {
+	number := func(node *AccessibilityNode) int {
+		n, err := strconv.Atoi(strings.TrimPrefix(node.RefID, "e"))
+		if err != nil {
+			return math.MaxInt
+		}
+		return n
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return number(nodes[i]) < number(nodes[j])
+	})
+	return nodes
 }
 
 // HighlightElement 在页面上高亮显示元素（用于调试）
@@ -613,7 +670,7 @@ func WaitForElement(ctx context.Context, page *rod.Page, selector string, opts *
 	// 等待元素
 	elem, err := page.Timeout(timeout).Element(selector)
 	if err != nil {
-		return fmt.Errorf("element not found: %s", selector)
+		return elementNotFound("element not found: %s", selector)
 	}
 
 	// 根据状态等待
@@ -0,0 +1,392 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// 审计类别
+const (
+	AuditPerformance   = "performance"
+	AuditSEO           = "seo"
+	AuditAccessibility = "accessibility"
+)
+
+// AllAuditCategories 默认审计的类别
+var AllAuditCategories = []string{AuditPerformance, AuditSEO, AuditAccessibility}
+
+// AuditOptions 页面审计选项
+type AuditOptions struct {
+	Categories []string // 审计类别，为空表示全部
+}
+
+// AuditCheck 单项检查结果
+type AuditCheck struct {
+	ID       string      `json:"id"`
+	Category string      `json:"category"`
+	Title    string      `json:"title"`
+	Score    float64     `json:"score"` // 0-1
+	Weight   float64     `json:"weight"`
+	Value    interface{} `json:"value,omitempty"`   // 测量值
+	Details  string      `json:"details,omitempty"` // 未通过时的说明
+}
+
+// auditFacts 页面中采集的原始数据
+type auditFacts struct {
+	URL    string `json:"url"`
+	Status int    `json:"status"`
+	Timing struct {
+		TTFB float64  `json:"ttfb"`
+		DCL  float64  `json:"dcl"`
+		Load float64  `json:"load"`
+		FCP  *float64 `json:"fcp"`
+		LCP  *float64 `json:"lcp"`
+		CLS  float64  `json:"cls"`
+	} `json:"timing"`
+	Requests      int     `json:"requests"`
+	TransferBytes float64 `json:"transfer_bytes"`
+	DOMNodes      int     `json:"dom_nodes"`
+	SEO           struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Viewport    bool   `json:"viewport"`
+		Canonical   bool   `json:"canonical"`
+		NoIndex     bool   `json:"noindex"`
+		H1Count     int    `json:"h1_count"`
+		Links       int    `json:"links"`
+		VagueLinks  int    `json:"vague_links"`
+	} `json:"seo"`
+	A11y struct {
+		Lang            string `json:"lang"`
+		Images          int    `json:"images"`
+		ImagesNoAlt     int    `json:"images_no_alt"`
+		Inputs          int    `json:"inputs"`
+		InputsUnlabeled int    `json:"inputs_unlabeled"`
+		Buttons         int    `json:"buttons"`
+		ButtonsUnnamed  int    `json:"buttons_unnamed"`
+		LinksUnnamed    int    `json:"links_unnamed"`
+		DuplicateIDs    int    `json:"duplicate_ids"`
+		HeadingSkips    int    `json:"heading_skips"`
+		ZoomDisabled    bool   `json:"zoom_disabled"`
+		FramesUntitled  int    `json:"frames_untitled"`
+	} `json:"a11y"`
+}
+
+// auditScript 采集审计所需的页面数据（LCP 和 CLS 通过带 buffered 的 PerformanceObserver 读取）
+const auditScript = `() => new Promise(resolve => {
+	const observed = { lcp: null, cls: 0 };
+	const observers = [];
+	const observe = (type, cb) => {
+		try {
+			const o = new PerformanceObserver(list => list.getEntries().forEach(cb));
+			o.observe({ type, buffered: true });
+			observers.push(o);
+		} catch (e) {}
+	};
+	observe('largest-contentful-paint', e => { observed.lcp = e.startTime; });
+	observe('layout-shift', e => { if (!e.hadRecentInput) observed.cls += e.value; });
+
+	setTimeout(() => {
+		observers.forEach(o => o.disconnect());
+		const nav = performance.getEntriesByType('navigation')[0] || {};
+		const fcp = performance.getEntriesByName('first-contentful-paint')[0];
+		const resources = performance.getEntriesByType('resource');
+		let transfer = nav.transferSize || 0;
+		resources.forEach(r => { transfer += r.transferSize || 0; });
+
+		const text = el => (el.innerText || el.textContent || '').trim();
+		const accessibleName = el => (el.getAttribute('aria-label') || '').trim() || (el.getAttribute('title') || '').trim() ||
+			text(el) || (el.querySelector('img[alt]:not([alt=""])') ? 'img' : '') ||
+			(() => { const id = el.getAttribute('aria-labelledby'); const ref = id && document.getElementById(id); return ref ? text(ref) : ''; })();
+		const meta = name => { const m = document.querySelector('meta[name="' + name + '" i]'); return m ? (m.getAttribute('content') || '') : null; };
+
+		const links = Array.from(document.querySelectorAll('a[href]'));
+		const vague = /^(click here|here|more|read more|learn more|link|this|点击这里|点击此处|更多|详情|这里)$/i;
+
+		const inputs = Array.from(document.querySelectorAll('input:not([type=hidden]):not([type=submit]):not([type=button]):not([type=reset]):not([type=image]), select, textarea'));
+		const labeled = el => (el.id && document.querySelector('label[for="' + CSS.escape(el.id) + '"]')) || el.closest('label') ||
+			(el.getAttribute('aria-label') || '').trim() || el.getAttribute('aria-labelledby') || (el.getAttribute('title') || '').trim();
+
+		const ids = {};
+		document.querySelectorAll('[id]').forEach(el => { ids[el.id] = (ids[el.id] || 0) + 1; });
+
+		let skips = 0, last = 0;
+		document.querySelectorAll('h1, h2, h3, h4, h5, h6').forEach(h => {
+			const level = parseInt(h.tagName[1], 10);
+			if (last && level > last + 1) skips++;
+			last = level;
+		});
+
+		const viewport = meta('viewport');
+		const robots = meta('robots') || '';
+		const images = Array.from(document.querySelectorAll('img'));
+		const buttons = Array.from(document.querySelectorAll('button, [role=button], input[type=submit], input[type=button]'));
+
+		resolve({
+			url: location.href,
+			status: nav.responseStatus || 0,
+			timing: {
+				ttfb: nav.responseStart || 0,
+				dcl: nav.domContentLoadedEventEnd || 0,
+				load: nav.loadEventEnd || 0,
+				fcp: fcp ? fcp.startTime : null,
+				lcp: observed.lcp,
+				cls: observed.cls,
+			},
+			requests: resources.length + 1,
+			transfer_bytes: transfer,
+			dom_nodes: document.getElementsByTagName('*').length,
+			seo: {
+				title: document.title || '',
+				description: meta('description') || '',
+				viewport: viewport !== null,
+				canonical: !!document.querySelector('link[rel=canonical][href]'),
+				noindex: /noindex/i.test(robots),
+				h1_count: document.querySelectorAll('h1').length,
+				links: links.length,
+				vague_links: links.filter(a => vague.test(text(a))).length,
+			},
+			a11y: {
+				lang: document.documentElement.getAttribute('lang') || '',
+				images: images.length,
+				images_no_alt: images.filter(img => !img.hasAttribute('alt') && img.getAttribute('role') !== 'presentation').length,
+				inputs: inputs.length,
+				inputs_unlabeled: inputs.filter(el => !labeled(el) && !(el.getAttribute('placeholder') || '').trim()).length,
+				buttons: buttons.length,
+				buttons_unnamed: buttons.filter(b => !accessibleName(b) && !(b.value || '').trim()).length,
+				links_unnamed: links.filter(a => !accessibleName(a)).length,
+				duplicate_ids: Object.values(ids).filter(n => n > 1).length,
+				heading_skips: skips,
+				zoom_disabled: !!viewport && (/user-scalable\s*=\s*(no|0)/i.test(viewport) || /maximum-scale\s*=\s*1(\.0)?\b/i.test(viewport)),
+				frames_untitled: Array.from(document.querySelectorAll('iframe')).filter(f => !(f.getAttribute('title') || '').trim()).length,
+			},
+		});
+	}, 300);
+})`
+
+// Audit 对当前页面做基础的性能、SEO 和可访问性审计，返回各类别 0-100 的得分和每项检查结果
+// 性能阈值参考 Lighthouse / Core Web Vitals；这是基于页面内数据的轻量实现，结果与 Lighthouse 不完全一致
+func (e *Executor) Audit(ctx context.Context, opts *AuditOptions) (*OperationResult, error) {
+	page := e.activePage(ctx)
+	if page == nil {
+		return nil, fmt.Errorf("no active page")
+	}
+	if opts == nil {
+		opts = &AuditOptions{}
+	}
+	categories, err := normalizeAuditCategories(opts.Categories)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := page.Context(ctx).Timeout(30 * time.Second).Eval(auditScript)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect audit data: %w", err)
+	}
+	var facts auditFacts
+	if err := res.Value.Unmarshal(&facts); err != nil {
+		return nil, fmt.Errorf("failed to parse audit data: %w", err)
+	}
+
+	checks := auditChecks(&facts, categories)
+	scores := auditScores(checks, categories)
+
+	var summary []string
+	for _, c := range categories {
+		summary = append(summary, fmt.Sprintf("%s %d", c, scores[c]))
+	}
+	return &OperationResult{
+		Success: true,
+		Message: fmt.Sprintf("Audit of %s: %s", facts.URL, strings.Join(summary, ", ")),
+		Data: map[string]interface{}{
+			"url":    facts.URL,
+			"scores": scores,
+			"checks": checks,
+		},
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// normalizeAuditCategories 校验审计类别，为空时返回全部类别
+func normalizeAuditCategories(categories []string) ([]string, error) {
+	if len(categories) == 0 {
+		return AllAuditCategories, nil
+	}
+	var result []string
+	seen := make(map[string]bool)
+	for _, c := range categories {
+		c = strings.ToLower(strings.TrimSpace(c))
+		switch c {
+		case AuditPerformance, AuditSEO, AuditAccessibility:
+		default:
+			return nil, fmt.Errorf("unknown audit category: %s (supported: %s)", c, strings.Join(AllAuditCategories, ", "))
+		}
+		if !seen[c] {
+			seen[c] = true
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+// metricScore 按阈值给测量值打分：不超过 good 得 1 分，good 到 poor 之间线性降到 0.5，超过 poor 后在 2 倍 poor 处降到 0
+func metricScore(value, good, poor float64) float64 {
+	switch {
+	case value <= good:
+		return 1
+	case value <= poor:
+		return 1 - 0.5*(value-good)/(poor-good)
+	case value >= 2*poor:
+		return 0
+	default:
+		return 0.5 - 0.5*(value-poor)/poor
+	}
+}
+
+// ratioScore 按未通过的比例打分（total 为 0 时视为通过）
+func ratioScore(failed, total int) float64 {
+	if total <= 0 || failed <= 0 {
+		return 1
+	}
+	return math.Max(0, 1-float64(failed)/float64(total))
+}
+
+func boolScore(ok bool) float64 {
+	if ok {
+		return 1
+	}
+	return 0
+}
+
+// auditChecks 根据页面数据生成各项检查结果
+func auditChecks(f *auditFacts, categories []string) []AuditCheck {
+	enabled := make(map[string]bool)
+	for _, c := range categories {
+		enabled[c] = true
+	}
+	var checks []AuditCheck
+	add := func(c AuditCheck) {
+		if !enabled[c.Category] {
+			return
+		}
+		c.Score = math.Round(c.Score*100) / 100
+		checks = append(checks, c)
+	}
+
+	// 性能（时间单位毫秒）
+	if f.Timing.FCP != nil {
+		add(AuditCheck{ID: "first-contentful-paint", Category: AuditPerformance, Title: "First Contentful Paint", Weight: 10,
+			Value: math.Round(*f.Timing.FCP), Score: metricScore(*f.Timing.FCP, 1800, 3000)})
+	}
+	if f.Timing.LCP != nil {
+		add(AuditCheck{ID: "largest-contentful-paint", Category: AuditPerformance, Title: "Largest Contentful Paint", Weight: 25,
+			Value: math.Round(*f.Timing.LCP), Score: metricScore(*f.Timing.LCP, 2500, 4000)})
+	}
+	add(AuditCheck{ID: "cumulative-layout-shift", Category: AuditPerformance, Title: "Cumulative Layout Shift", Weight: 25,
+		Value: math.Round(f.Timing.CLS*1000) / 1000, Score: metricScore(f.Timing.CLS, 0.1, 0.25)})
+	add(AuditCheck{ID: "server-response-time", Category: AuditPerformance, Title: "Time to First Byte", Weight: 10,
+		Value: math.Round(f.Timing.TTFB), Score: metricScore(f.Timing.TTFB, 800, 1800)})
+	if f.Timing.Load > 0 {
+		add(AuditCheck{ID: "load-time", Category: AuditPerformance, Title: "Page load time", Weight: 10,
+			Value: math.Round(f.Timing.Load), Score: metricScore(f.Timing.Load, 3000, 6000)})
+	}
+	add(AuditCheck{ID: "total-byte-weight", Category: AuditPerformance, Title: "Total transfer size", Weight: 10,
+		Value: int64(f.TransferBytes), Score: metricScore(f.TransferBytes, 1600*1024, 4000*1024)})
+	add(AuditCheck{ID: "dom-size", Category: AuditPerformance, Title: "DOM size", Weight: 5,
+		Value: f.DOMNodes, Score: metricScore(float64(f.DOMNodes), 800, 1400)})
+	add(AuditCheck{ID: "request-count", Category: AuditPerformance, Title: "Number of requests", Weight: 5,
+		Value: f.Requests, Score: metricScore(float64(f.Requests), 50, 150)})
+
+	// SEO
+	titleLen := len([]rune(strings.TrimSpace(f.SEO.Title)))
+	add(AuditCheck{ID: "document-title", Category: AuditSEO, Title: "Document has a title", Weight: 20,
+		Value: f.SEO.Title, Score: boolScore(titleLen > 0), Details: detailIf(titleLen == 0, "the page has no <title>")})
+	add(AuditCheck{ID: "title-length", Category: AuditSEO, Title: "Title length is reasonable (10-70 characters)", Weight: 5,
+		Value: titleLen, Score: boolScore(titleLen >= 10 && titleLen <= 70)})
+	descLen := len([]rune(strings.TrimSpace(f.SEO.Description)))
+	add(AuditCheck{ID: "meta-description", Category: AuditSEO, Title: "Document has a meta description", Weight: 15,
+		Value: descLen, Score: boolScore(descLen > 0), Details: detailIf(descLen == 0, `missing <meta name="description">`)})
+	add(AuditCheck{ID: "viewport", Category: AuditSEO, Title: "Has a viewport meta tag", Weight: 10,
+		Score: boolScore(f.SEO.Viewport), Details: detailIf(!f.SEO.Viewport, `missing <meta name="viewport">`)})
+	add(AuditCheck{ID: "is-crawlable", Category: AuditSEO, Title: "Page is not blocked from indexing", Weight: 20,
+		Score: boolScore(!f.SEO.NoIndex), Details: detailIf(f.SEO.NoIndex, "robots meta contains noindex")})
+	if f.Status > 0 {
+		add(AuditCheck{ID: "http-status-code", Category: AuditSEO, Title: "Page has a successful HTTP status code", Weight: 10,
+			Value: f.Status, Score: boolScore(f.Status < 400)})
+	}
+	add(AuditCheck{ID: "single-h1", Category: AuditSEO, Title: "Page has exactly one <h1>", Weight: 5,
+		Value: f.SEO.H1Count, Score: boolScore(f.SEO.H1Count == 1)})
+	add(AuditCheck{ID: "canonical", Category: AuditSEO, Title: "Has a canonical link", Weight: 5,
+		Score: boolScore(f.SEO.Canonical)})
+	add(AuditCheck{ID: "link-text", Category: AuditSEO, Title: "Links have descriptive text", Weight: 5,
+		Value: f.SEO.VagueLinks, Score: ratioScore(f.SEO.VagueLinks, f.SEO.Links),
+		Details: detailIf(f.SEO.VagueLinks > 0, fmt.Sprintf("%d links use generic text such as 'click here'", f.SEO.VagueLinks))})
+	add(AuditCheck{ID: "image-alt-seo", Category: AuditSEO, Title: "Images have alt text", Weight: 5,
+		Value: f.A11y.ImagesNoAlt, Score: ratioScore(f.A11y.ImagesNoAlt, f.A11y.Images)})
+
+	// 可访问性
+	add(AuditCheck{ID: "html-has-lang", Category: AuditAccessibility, Title: "<html> has a lang attribute", Weight: 10,
+		Value: f.A11y.Lang, Score: boolScore(f.A11y.Lang != "")})
+	add(AuditCheck{ID: "image-alt", Category: AuditAccessibility, Title: "Images have alt attributes", Weight: 15,
+		Value: f.A11y.ImagesNoAlt, Score: ratioScore(f.A11y.ImagesNoAlt, f.A11y.Images),
+		Details: detailIf(f.A11y.ImagesNoAlt > 0, fmt.Sprintf("%d of %d images have no alt attribute", f.A11y.ImagesNoAlt, f.A11y.Images))})
+	add(AuditCheck{ID: "label", Category: AuditAccessibility, Title: "Form fields have labels", Weight: 15,
+		Value: f.A11y.InputsUnlabeled, Score: ratioScore(f.A11y.InputsUnlabeled, f.A11y.Inputs),
+		Details: detailIf(f.A11y.InputsUnlabeled > 0, fmt.Sprintf("%d of %d form fields have no label", f.A11y.InputsUnlabeled, f.A11y.Inputs))})
+	add(AuditCheck{ID: "button-name", Category: AuditAccessibility, Title: "Buttons have an accessible name", Weight: 15,
+		Value: f.A11y.ButtonsUnnamed, Score: ratioScore(f.A11y.ButtonsUnnamed, f.A11y.Buttons),
+		Details: detailIf(f.A11y.ButtonsUnnamed > 0, fmt.Sprintf("%d of %d buttons have no accessible name", f.A11y.ButtonsUnnamed, f.A11y.Buttons))})
+	add(AuditCheck{ID: "link-name", Category: AuditAccessibility, Title: "Links have an accessible name", Weight: 10,
+		Value: f.A11y.LinksUnnamed, Score: ratioScore(f.A11y.LinksUnnamed, f.SEO.Links),
+		Details: detailIf(f.A11y.LinksUnnamed > 0, fmt.Sprintf("%d links have no accessible name", f.A11y.LinksUnnamed))})
+	add(AuditCheck{ID: "duplicate-id", Category: AuditAccessibility, Title: "IDs are unique", Weight: 5,
+		Value: f.A11y.DuplicateIDs, Score: boolScore(f.A11y.DuplicateIDs == 0)})
+	add(AuditCheck{ID: "heading-order", Category: AuditAccessibility, Title: "Heading levels increase by one", Weight: 5,
+		Value: f.A11y.HeadingSkips, Score: boolScore(f.A11y.HeadingSkips == 0)})
+	add(AuditCheck{ID: "meta-viewport-zoom", Category: AuditAccessibility, Title: "Zooming is not disabled", Weight: 10,
+		Score: boolScore(!f.A11y.ZoomDisabled), Details: detailIf(f.A11y.ZoomDisabled, "viewport disables user scaling")})
+	add(AuditCheck{ID: "frame-title", Category: AuditAccessibility, Title: "Frames have a title", Weight: 5,
+		Value: f.A11y.FramesUntitled, Score: boolScore(f.A11y.FramesUntitled == 0)})
+
+	return checks
+}
+
+// auditScores 按权重汇总各类别得分（0-100）
+func auditScores(checks []AuditCheck, categories []string) map[string]int {
+	sums := make(map[string]float64)
+	weights := make(map[string]float64)
+	for _, c := range checks {
+		sums[c.Category] += c.Score * c.Weight
+		weights[c.Category] += c.Weight
+	}
+	scores := make(map[string]int, len(categories))
+	for _, c := range categories {
+		if weights[c] > 0 {
+			scores[c] = int(math.Round(sums[c] / weights[c] * 100))
+		}
+	}
+	return scores
+}
+
+func detailIf(cond bool, msg string) string {
+	if cond {
+		return msg
+	}
+	return ""
+}
+
+// ParseAuditArguments 解析 browser_audit 工具参数
+func ParseAuditArguments(args map[string]interface{}) *AuditOptions {
+	opts := &AuditOptions{}
+	if categories, ok := args["categories"].([]interface{}); ok {
+		for _, c := range categories {
+			if name, ok := c.(string); ok {
+				opts.Categories = append(opts.Categories, name)
+			}
+		}
+	}
+	return opts
+}
@@ -0,0 +1,70 @@
+package executor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMetricScore(t *testing.T) {
+	tests := []struct {
+		value float64
+		want  float64
+	}{
+		{1000, 1},
+		{2500, 1},
+		{3250, 0.75},
+		{4000, 0.5},
+		{6000, 0.25},
+		{9000, 0},
+	}
+	for _, tt := range tests {
+		if got := metricScore(tt.value, 2500, 4000); got != tt.want {
+			t.Errorf("metricScore(%v) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeAuditCategories(t *testing.T) {
+	got, err := normalizeAuditCategories(nil)
+	if err != nil || !reflect.DeepEqual(got, AllAuditCategories) {
+		t.Errorf("empty categories = %v, %v; want all", got, err)
+	}
+
+	got, err = normalizeAuditCategories([]string{" SEO ", "seo", "accessibility"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{AuditSEO, AuditAccessibility}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if _, err := normalizeAuditCategories([]string{"pwa"}); err == nil {
+		t.Error("expected error for unknown category")
+	}
+}
+
+func TestAuditScores(t *testing.T) {
+	facts := &auditFacts{}
+	facts.SEO.Title = "Example product page"
+	facts.SEO.Viewport = true
+	facts.SEO.H1Count = 1
+	facts.A11y.Lang = "en"
+	facts.A11y.Images = 4
+	facts.A11y.ImagesNoAlt = 2
+
+	checks := auditChecks(facts, []string{AuditAccessibility})
+	for _, c := range checks {
+		if c.Category != AuditAccessibility {
+			t.Fatalf("unexpected %s check %s", c.Category, c.ID)
+		}
+	}
+
+	scores := auditScores(checks, []string{AuditAccessibility})
+	if _, ok := scores[AuditSEO]; ok {
+		t.Error("seo should not be scored when not requested")
+	}
+	// 只有 image-alt（权重 15）得 0.5 分，总权重 90
+	if got, want := scores[AuditAccessibility], 92; got != want {
+		t.Errorf("accessibility score = %d, want %d", got, want)
+	}
+}
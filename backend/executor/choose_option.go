@@ -0,0 +1,289 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/input"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// ChooseOptionOptions 自定义下拉框选择选项
+type ChooseOptionOptions struct {
+	Exact          bool          // 选项文本需完全匹配（默认优先完全匹配，其次包含匹配）
+	SkipFilter     bool          // 不在搜索框中输入文本过滤选项
+	OptionSelector string        // 选项元素的 CSS 选择器（可选，覆盖内置的组件库规则）
+	Timeout        time.Duration // 查找触发元素和等待选项出现的超时时间
+}
+
+// 各组件库的下拉选项选择器，按顺序匹配，专属规则优先于通用的 ARIA 规则
+var dropdownOptionSelectors = []string{
+	".select2-container--open .select2-results__option",
+	".chosen-with-drop .chosen-results li.active-result",
+	".choices.is-open .choices__item--choice",
+	".ts-dropdown .option",
+	".ant-select-dropdown:not(.ant-select-dropdown-hidden) .ant-select-item-option",
+	".el-select-dropdown .el-select-dropdown__item",
+	".MuiAutocomplete-popper .MuiAutocomplete-option",
+	".MuiMenu-paper [role=option]",
+	".vs__dropdown-menu .vs__dropdown-option",
+	".ng-dropdown-panel .ng-option",
+	"[class*=react-select][class*=__option]",
+	"[role=listbox] [role=option]",
+	"[role=option]",
+	".dropdown-menu.show .dropdown-item",
+}
+
+// detectDropdownFramework 根据 DOM 结构判断下拉框所属的组件库
+const detectDropdownFramework = `function () {
+	const el = this;
+	if (el.tagName === 'SELECT') {
+		const next = el.nextElementSibling;
+		if (el.classList.contains('select2-hidden-accessible')) return 'select2';
+		if (next && next.classList.contains('chosen-container')) return 'chosen';
+		if (el.tomselect) return 'tom-select';
+		if (el.closest('.choices')) return 'choices';
+		return 'native';
+	}
+	const rules = [
+		['select2', '.select2-container'],
+		['chosen', '.chosen-container'],
+		['choices', '.choices'],
+		['tom-select', '.ts-wrapper'],
+		['ant-design', '.ant-select'],
+		['element-ui', '.el-select'],
+		['mui', '.MuiAutocomplete-root, .MuiSelect-root, .MuiSelect-select'],
+		['vue-select', '.v-select'],
+		['ng-select', 'ng-select'],
+		['react-select', '[class*="react-select"]'],
+		['aria-combobox', '[role=combobox], [aria-haspopup=listbox]'],
+	];
+	for (const [name, selector] of rules) {
+		if (el.closest(selector)) return name;
+	}
+	return 'generic';
+}`
+
+// dropdownTrigger 返回用于展开下拉框的元素：组件库隐藏了原生 select 时，返回组件渲染的触发元素
+const dropdownTrigger = `function () {
+	const el = this;
+	if (el.tagName !== 'SELECT') return el;
+	const next = el.nextElementSibling;
+	if (el.classList.contains('select2-hidden-accessible') && next) {
+		return next.querySelector('.select2-selection') || next;
+	}
+	if (next && next.classList.contains('chosen-container')) {
+		return next.querySelector('.chosen-single, .chosen-choices') || next;
+	}
+	if (el.tomselect) return el.tomselect.control;
+	const choices = el.closest('.choices');
+	if (choices) return choices.querySelector('.choices__inner') || choices;
+	return el;
+}`
+
+// ChooseOption 选择自定义下拉框（Select2、Ant Design、Element UI、MUI、react-select 等）的选项：
+// 点击展开、在搜索框中输入过滤、点击匹配的选项；原生 <select> 直接委托给 Select
+func (e *Executor) ChooseOption(ctx context.Context, identifier string, value string, opts *ChooseOptionOptions) (*OperationResult, error) {
+	page := e.activePage(ctx)
+	if page == nil {
+		return nil, fmt.Errorf("no active page")
+	}
+	if identifier == "" || value == "" {
+		return nil, fmt.Errorf("identifier and value are required")
+	}
+	if opts == nil {
+		opts = &ChooseOptionOptions{}
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = e.timeouts().Element()
+	}
+	deadline := time.Now().Add(opts.Timeout)
+
+	elem, err := e.findElementWithTimeout(ctx, page, identifier, opts.Timeout)
+	if err != nil {
+		return &OperationResult{
+			Success:   false,
+			Error:     fmt.Sprintf("Element not found: %s", identifier),
+			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
+		}, err
+	}
+
+	res, err := elem.Eval(detectDropdownFramework)
+	if err != nil {
+		return &OperationResult{
+			Success:   false,
+			Error:     fmt.Sprintf("Failed to inspect dropdown: %s", err.Error()),
+			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
+		}, err
+	}
+	framework := res.Value.Str()
+	if framework == "native" {
+		return e.Select(ctx, identifier, value, &SelectOptions{WaitVisible: true, Timeout: opts.Timeout})
+	}
+
+	trigger, err := elem.ElementByJS(rod.Eval(dropdownTrigger))
+	if err != nil {
+		return &OperationResult{
+			Success:   false,
+			Error:     fmt.Sprintf("Failed to locate dropdown trigger: %s", err.Error()),
+			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
+		}, err
+	}
+
+	// 展开下拉框
+	if err := trigger.ScrollIntoView(); err != nil {
+		logger.Warn(ctx, "[ChooseOption] Failed to scroll trigger into view: %v", err)
+	}
+	if err := trigger.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return &OperationResult{
+			Success:   false,
+			Error:     fmt.Sprintf("Failed to open dropdown: %s", err.Error()),
+			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
+		}, err
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	// 在搜索框中输入文本过滤选项
+	filtered := false
+	if !opts.SkipFilter {
+		if search := findDropdownSearchInput(page, trigger); search != nil {
+			_ = search.SelectAllText()
+			if err := search.Input(value); err != nil {
+				logger.Warn(ctx, "[ChooseOption] Failed to type filter text: %v", err)
+			} else {
+				filtered = true
+			}
+		}
+	}
+
+	// 等待匹配的选项出现
+	listbox := dropdownListboxID(page, trigger)
+	var option *rod.Element
+	err = pollUntil(ctx, deadline, func() (bool, error) {
+		found, err := page.Sleeper(rod.NotFoundSleeper).ElementByJS(rod.Eval(`(value, exact, custom, listbox, selectors) => {
+			const norm = (s) => (s || '').replace(/\s+/g, ' ').trim().toLowerCase();
+			const want = norm(value);
+			let scope = document;
+			if (!custom && listbox) {
+				const lb = document.getElementById(listbox);
+				if (lb) scope = lb;
+			}
+			const visible = (el) => {
+				const r = el.getBoundingClientRect();
+				const s = getComputedStyle(el);
+				return r.width > 0 && r.height > 0 && s.visibility !== 'hidden' && s.display !== 'none';
+			};
+			const disabled = (el) => el.getAttribute('aria-disabled') === 'true' ||
+				/(^|[\s_-])(is-)?disabled/.test(typeof el.className === 'string' ? el.className : '');
+			const candidates = [];
+			for (const sel of (custom ? [custom] : selectors)) {
+				for (const el of scope.querySelectorAll(sel)) {
+					if (!candidates.includes(el) && visible(el) && !disabled(el)) candidates.push(el);
+				}
+				// 优先使用组件库专属规则匹配到的选项
+				if (candidates.length > 0) break;
+			}
+			const text = (el) => norm(el.innerText || el.textContent);
+			const exactMatch = candidates.find((el) => text(el) === want || norm(el.getAttribute('data-value')) === want);
+			if (exactMatch || exact) return exactMatch || null;
+			return candidates.find((el) => text(el).includes(want)) || null;
+		}`, value, opts.Exact, opts.OptionSelector, listbox, dropdownOptionSelectors))
+		if err != nil {
+			if errors.Is(err, &rod.ElementNotFoundError{}) {
+				return false, nil
+			}
+			return false, err
+		}
+		option = found
+		return true, nil
+	})
+	if err != nil {
+		// 收起下拉框
+		_ = page.Keyboard.Type(input.Escape)
+		return &OperationResult{
+			Success:   false,
+			Error:     fmt.Sprintf("Option %q not found in %s dropdown: %s", value, framework, err.Error()),
+			Timestamp: time.Now(),
+			ErrorCode: models.ErrorCodeElementNotFound,
+		}, models.WithErrorCode(models.ErrorCodeElementNotFound, fmt.Errorf("option %q not found: %w", value, err))
+	}
+
+	optionText, _ := option.Text()
+	if err := option.ScrollIntoView(); err != nil {
+		logger.Warn(ctx, "[ChooseOption] Failed to scroll option into view: %v", err)
+	}
+	if err := option.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return &OperationResult{
+			Success:   false,
+			Error:     fmt.Sprintf("Failed to click option: %s", err.Error()),
+			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
+		}, err
+	}
+
+	displayed, _ := trigger.Text()
+	logger.Info(ctx, "[ChooseOption] Chose %q in %s dropdown %s", optionText, framework, identifier)
+
+	return &OperationResult{
+		Success:   true,
+		Message:   fmt.Sprintf("Successfully chose option: %s", optionText),
+		Timestamp: time.Now(),
+		Data: map[string]interface{}{
+			"framework":      framework,
+			"option_text":    optionText,
+			"filtered":       filtered,
+			"displayed_text": displayed,
+		},
+	}, nil
+}
+
+// findDropdownSearchInput 查找下拉框展开后可输入的搜索框
+func findDropdownSearchInput(page *rod.Page, trigger *rod.Element) *rod.Element {
+	search, err := page.Sleeper(rod.NotFoundSleeper).ElementByJS(rod.Eval(`(trigger) => {
+		const isText = (el) => el && el.tagName === 'INPUT' && !el.readOnly && !el.disabled &&
+			!['hidden', 'checkbox', 'radio', 'button', 'submit'].includes(el.type);
+		const visible = (el) => {
+			const r = el.getBoundingClientRect();
+			return r.width > 0 && r.height > 0;
+		};
+		const active = document.activeElement;
+		if (isText(active) && visible(active)) return active;
+		if (isText(trigger)) return trigger;
+		const inner = trigger.querySelector('input');
+		if (isText(inner) && visible(inner)) return inner;
+		for (const sel of ['.select2-container--open .select2-search__field', '.chosen-with-drop .chosen-search-input',
+			'.ts-dropdown .dropdown-input', '.choices.is-open input[type=search]']) {
+			const el = document.querySelector(sel);
+			if (isText(el) && visible(el)) return el;
+		}
+		return null;
+	}`, trigger.Object))
+	if err != nil {
+		return nil
+	}
+	return search
+}
+
+// dropdownListboxID 获取下拉框关联的选项列表 ID（aria-controls / aria-owns），用于限定查找范围
+func dropdownListboxID(page *rod.Page, trigger *rod.Element) string {
+	res, err := page.Evaluate(rod.Eval(`(trigger) => {
+		const ids = [trigger, document.activeElement, trigger.querySelector('[aria-controls], [aria-owns]')]
+			.filter(Boolean)
+			.map((el) => el.getAttribute('aria-controls') || el.getAttribute('aria-owns'))
+			.filter((id) => id && document.getElementById(id));
+		return ids[0] || '';
+	}`, trigger.Object))
+	if err != nil {
+		return ""
+	}
+	return res.Value.Str()
+}
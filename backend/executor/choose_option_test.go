@@ -0,0 +1,23 @@
+package executor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseChooseOptionArguments(t *testing.T) {
+	opts := ParseChooseOptionArguments(map[string]interface{}{
+		"exact":           true,
+		"filter":          false,
+		"option_selector": ".my-option",
+		"timeout":         5.0,
+	})
+	if !opts.Exact || !opts.SkipFilter || opts.OptionSelector != ".my-option" || opts.Timeout != 5*time.Second {
+		t.Errorf("unexpected options: %+v", opts)
+	}
+
+	defaults := ParseChooseOptionArguments(map[string]interface{}{})
+	if defaults.Exact || defaults.SkipFilter || defaults.Timeout != 0 {
+		t.Errorf("unexpected defaults: %+v", defaults)
+	}
+}
@@ -0,0 +1,278 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// CollectMode 采集翻页方式
+type CollectMode string
+
+const (
+	CollectModeScroll   CollectMode = "scroll"    // 滚动加载（无限滚动）
+	CollectModeNextPage CollectMode = "next_page" // 点击下一页
+)
+
+// CollectOptions 采集选项
+type CollectOptions struct {
+	ItemSelector  string        // 每个条目的 CSS 选择器
+	Fields        []string      // 提取的字段：text, html, href, src, value、属性名，或 name=子选择器[@属性]
+	Mode          CollectMode   // 翻页方式：scroll 或 next_page
+	NextSelector  string        // 下一页按钮的 CSS 选择器（mode=next_page 时必需）
+	DedupeKey     string        // 去重字段，为空时按整个条目去重
+	MaxItems      int           // 最多采集的条目数
+	MaxDuration   time.Duration // 最长采集时间
+	MaxIdleRounds int           // 连续多少轮没有新内容时停止
+	Delay         time.Duration // 每次滚动或翻页后等待新内容的时间
+
+	// OnItems 每轮采集到新条目时回调，用于流式返回部分结果
+	OnItems func(items []map[string]interface{}, total int)
+}
+
+// 采集停止原因
+const (
+	collectStopMaxItems    = "max_items"
+	collectStopMaxDuration = "max_duration"
+	collectStopNoNewItems  = "no_new_content"
+	collectStopNoNextPage  = "no_next_page"
+	collectStopCancelled   = "cancelled"
+)
+
+// Collect 通过滚动或点击下一页持续采集列表条目，直到满足停止条件
+// 停止条件：达到最大条目数、连续多轮没有新内容、超过最长时间或没有下一页
+func (e *Executor) Collect(ctx context.Context, opts *CollectOptions) (*OperationResult, error) {
+	page := e.activePage(ctx)
+	if page == nil {
+		return nil, fmt.Errorf("no active page")
+	}
+
+	if opts == nil || opts.ItemSelector == "" {
+		return &OperationResult{
+			Success:   false,
+			Error:     "item selector is required",
+			Timestamp: time.Now(),
+		}, fmt.Errorf("item selector is required")
+	}
+	if opts.Mode == "" {
+		opts.Mode = CollectModeScroll
+	}
+	if opts.Mode != CollectModeScroll && opts.Mode != CollectModeNextPage {
+		return nil, fmt.Errorf("unknown collect mode: %s", opts.Mode)
+	}
+	if opts.Mode == CollectModeNextPage && opts.NextSelector == "" {
+		return nil, fmt.Errorf("next selector is required when mode is next_page")
+	}
+	if len(opts.Fields) == 0 {
+		opts.Fields = []string{"text"}
+	}
+	if opts.MaxItems <= 0 {
+		opts.MaxItems = 500
+	}
+	if opts.MaxDuration <= 0 {
+		opts.MaxDuration = 2 * time.Minute
+	}
+	if opts.MaxIdleRounds <= 0 {
+		opts.MaxIdleRounds = 3
+	}
+	if opts.Delay <= 0 {
+		opts.Delay = 1500 * time.Millisecond
+	}
+
+	logger.Info(ctx, "[Collect] Collecting %s (mode: %s, max items: %d, max duration: %v)",
+		opts.ItemSelector, opts.Mode, opts.MaxItems, opts.MaxDuration)
+
+	deadline := time.Now().Add(opts.MaxDuration)
+	seen := make(map[string]bool)
+	items := make([]map[string]interface{}, 0)
+	rounds := 0
+	idleRounds := 0
+	stopReason := ""
+
+	for stopReason == "" {
+		rounds++
+
+		pageItems, err := extractCollectItems(page, opts.ItemSelector, opts.Fields)
+		if err != nil {
+			logger.Warn(ctx, "[Collect] Failed to extract items in round %d: %v", rounds, err)
+		}
+
+		newItems := make([]map[string]interface{}, 0)
+		for _, item := range pageItems {
+			key := collectItemKey(item, opts.DedupeKey)
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			newItems = append(newItems, item)
+			if len(items)+len(newItems) >= opts.MaxItems {
+				break
+			}
+		}
+		items = append(items, newItems...)
+
+		if len(newItems) > 0 {
+			idleRounds = 0
+			logger.Info(ctx, "[Collect] Round %d: %d new items (total %d)", rounds, len(newItems), len(items))
+			if opts.OnItems != nil {
+				opts.OnItems(newItems, len(items))
+			}
+		} else {
+			idleRounds++
+		}
+
+		switch {
+		case len(items) >= opts.MaxItems:
+			stopReason = collectStopMaxItems
+		case idleRounds >= opts.MaxIdleRounds:
+			stopReason = collectStopNoNewItems
+		case time.Now().After(deadline):
+			stopReason = collectStopMaxDuration
+		case ctx.Err() != nil:
+			stopReason = collectStopCancelled
+		}
+		if stopReason != "" {
+			break
+		}
+
+		// 加载更多内容
+		if opts.Mode == CollectModeScroll {
+			if err := scrollForMore(page, opts.ItemSelector); err != nil {
+				logger.Warn(ctx, "[Collect] Failed to scroll: %v", err)
+			}
+		} else {
+			clicked, err := clickNextPage(ctx, page, opts.NextSelector)
+			if err != nil {
+				logger.Warn(ctx, "[Collect] Failed to click next page: %v", err)
+			}
+			if !clicked {
+				stopReason = collectStopNoNextPage
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			stopReason = collectStopCancelled
+		case <-time.After(opts.Delay):
+		}
+	}
+
+	logger.Info(ctx, "[Collect] Collected %d items in %d rounds (stop reason: %s)", len(items), rounds, stopReason)
+
+	return &OperationResult{
+		Success:   true,
+		Message:   fmt.Sprintf("Collected %d items in %d rounds (stopped: %s)", len(items), rounds, stopReason),
+		Timestamp: time.Now(),
+		Data: map[string]interface{}{
+			"items":       items,
+			"count":       len(items),
+			"rounds":      rounds,
+			"stop_reason": stopReason,
+		},
+	}, nil
+}
+
+// extractCollectItems 一次性提取页面上所有条目的字段
+func extractCollectItems(page *rod.Page, selector string, fields []string) ([]map[string]interface{}, error) {
+	result, err := page.Eval(`(selector, fields) => {
+		const read = (el, name) => {
+			if (!el) return null;
+			switch (name) {
+				case 'text': return (el.innerText || el.textContent || '').trim();
+				case 'html': return el.outerHTML;
+				case 'value': return el.value !== undefined ? String(el.value) : null;
+				case 'href': return el.href || el.getAttribute('href');
+				case 'src': return el.src || el.getAttribute('src');
+				default: return el.getAttribute(name);
+			}
+		};
+		return Array.from(document.querySelectorAll(selector)).map(el => {
+			const item = {};
+			for (const field of fields) {
+				const eq = field.indexOf('=');
+				if (eq < 0) {
+					const v = read(el, field);
+					if (v !== null && v !== undefined) item[field] = v;
+					continue;
+				}
+				// name=子选择器[@属性]
+				const name = field.slice(0, eq);
+				let sub = field.slice(eq + 1);
+				let attr = 'text';
+				const at = sub.lastIndexOf('@');
+				if (at > 0) {
+					attr = sub.slice(at + 1);
+					sub = sub.slice(0, at);
+				}
+				let target = null;
+				try { target = sub ? el.querySelector(sub) : el; } catch (e) {}
+				const v = read(target, attr);
+				if (v !== null && v !== undefined) item[name] = v;
+			}
+			return item;
+		});
+	}`, selector, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []map[string]interface{}
+	if err := result.Value.Unmarshal(&items); err != nil {
+		return nil, fmt.Errorf("failed to parse items: %w", err)
+	}
+	return items, nil
+}
+
+// collectItemKey 生成条目的去重键
+func collectItemKey(item map[string]interface{}, dedupeKey string) string {
+	if len(item) == 0 {
+		return ""
+	}
+	if dedupeKey != "" {
+		if v, ok := item[dedupeKey]; ok && v != nil && v != "" {
+			return fmt.Sprintf("%v", v)
+		}
+	}
+	data, _ := json.Marshal(item)
+	return string(data)
+}
+
+// scrollForMore 滚动到最后一个条目和页面底部，触发加载更多内容
+func scrollForMore(page *rod.Page, selector string) error {
+	_, err := page.Eval(`(selector) => {
+		const items = document.querySelectorAll(selector);
+		if (items.length > 0) {
+			items[items.length - 1].scrollIntoView({ block: 'end' });
+		}
+		window.scrollTo(0, document.documentElement.scrollHeight || document.body.scrollHeight);
+	}`, selector)
+	return err
+}
+
+// clickNextPage 点击下一页按钮，按钮不存在或已禁用时返回 false
+func clickNextPage(ctx context.Context, page *rod.Page, selector string) (bool, error) {
+	has, elem, err := page.Has(selector)
+	if err != nil || !has {
+		return false, err
+	}
+
+	disabled, err := elem.Eval(`() => this.disabled === true || this.getAttribute('aria-disabled') === 'true' || this.classList.contains('disabled')`)
+	if err == nil && disabled.Value.Bool() {
+		logger.Info(ctx, "[Collect] Next page button is disabled")
+		return false, nil
+	}
+
+	if err := elem.ScrollIntoView(); err != nil {
+		logger.Warn(ctx, "[Collect] Failed to scroll next page button into view: %v", err)
+	}
+	if err := elem.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return false, err
+	}
+	return true, nil
+}
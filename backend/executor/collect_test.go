@@ -0,0 +1,21 @@
+package executor
+
+import "testing"
+
+func TestCollectItemKey(t *testing.T) {
+	a := map[string]interface{}{"title": "Item", "href": "https://example.com/1"}
+	b := map[string]interface{}{"title": "Item", "href": "https://example.com/2"}
+
+	if collectItemKey(a, "title") != collectItemKey(b, "title") {
+		t.Errorf("expected items with the same dedupe key to collide")
+	}
+	if collectItemKey(a, "") == collectItemKey(b, "") {
+		t.Errorf("expected different items to have different keys without dedupe key")
+	}
+	if collectItemKey(a, "missing") == collectItemKey(b, "missing") {
+		t.Errorf("expected fallback to the whole item when dedupe field is missing")
+	}
+	if collectItemKey(map[string]interface{}{}, "") != "" {
+		t.Errorf("expected empty item to have empty key")
+	}
+}
@@ -0,0 +1,251 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// 坐标空间
+const (
+	CoordViewport           = "viewport"            // 视口 CSS 像素（默认）
+	CoordPage               = "page"                // 文档 CSS 像素，目标不在视口内时自动滚动
+	CoordScreenshot         = "screenshot"          // 视口截图的图片像素（按 devicePixelRatio 换算）
+	CoordFullPageScreenshot = "fullpage_screenshot" // 整页截图的图片像素
+)
+
+// ClickAtOptions 坐标点击选项
+// 指定 Width 和 Height 时 (X, Y) 为区域左上角，点击区域中心（用于按截图中框选的区域点击）
+type ClickAtOptions struct {
+	X, Y          float64
+	Width, Height float64
+	Space         string // 坐标空间，默认 viewport
+	Button        string // left（默认）、right、middle
+	ClickCount    int    // 点击次数，2 为双击，默认 1
+}
+
+// DragBoxOptions 坐标拖拽选项（框选、平移地图、在画布上绘制等）
+type DragBoxOptions struct {
+	FromX, FromY float64
+	ToX, ToY     float64
+	Space        string // 坐标空间，默认 viewport
+	Steps        int    // 移动过程中的中间点数量，默认 10
+	HoldMs       int    // 按下后开始移动前的停顿（毫秒），部分画布应用需要
+}
+
+// resolvePointScript 把指定坐标空间中的点换算为视口坐标，页面坐标不在视口内时滚动到视口中央
+const resolvePointScript = `(x, y, space) => {
+	const dpr = window.devicePixelRatio || 1;
+	if (space === 'screenshot' || space === 'fullpage_screenshot') {
+		x /= dpr;
+		y /= dpr;
+	}
+	if (space === 'page' || space === 'fullpage_screenshot') {
+		const vw = window.innerWidth, vh = window.innerHeight;
+		let vx = x - window.scrollX, vy = y - window.scrollY;
+		if (vx < 0 || vy < 0 || vx >= vw || vy >= vh) {
+			window.scrollTo(x - vw / 2, y - vh / 2);
+			vx = x - window.scrollX;
+			vy = y - window.scrollY;
+		}
+		x = vx;
+		y = vy;
+	}
+	const inside = x >= 0 && y >= 0 && x < window.innerWidth && y < window.innerHeight;
+	const el = inside ? document.elementFromPoint(x, y) : null;
+	let target = '';
+	if (el) {
+		target = el.tagName.toLowerCase();
+		if (el.id) target += '#' + el.id;
+		const text = (el.innerText || el.getAttribute('aria-label') || '').trim().slice(0, 50);
+		if (text) target += ' "' + text + '"';
+	}
+	return { x, y, inside, target };
+}`
+
+// resolvedPoint 换算后的视口坐标
+type resolvedPoint struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Inside bool    `json:"inside"`
+	Target string  `json:"target"` // 该位置最上层的元素描述
+}
+
+// resolvePoint 换算坐标并检查是否在视口内
+func resolvePoint(ctx context.Context, page *rod.Page, x, y float64, space string) (*resolvedPoint, error) {
+	switch space {
+	case "":
+		space = CoordViewport
+	case CoordViewport, CoordPage, CoordScreenshot, CoordFullPageScreenshot:
+	default:
+		return nil, fmt.Errorf("unknown coordinate space: %s (supported: viewport, page, screenshot, fullpage_screenshot)", space)
+	}
+
+	res, err := page.Context(ctx).Eval(resolvePointScript, x, y, space)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve coordinates: %w", err)
+	}
+	var p resolvedPoint
+	if err := res.Value.Unmarshal(&p); err != nil {
+		return nil, fmt.Errorf("failed to resolve coordinates: %w", err)
+	}
+	if !p.Inside {
+		return nil, fmt.Errorf("point (%.0f, %.0f) in %s space is outside the viewport", x, y, space)
+	}
+	return &p, nil
+}
+
+// parseMouseButton 解析鼠标按键
+func parseMouseButton(button string) (proto.InputMouseButton, error) {
+	switch strings.ToLower(button) {
+	case "", "left":
+		return proto.InputMouseButtonLeft, nil
+	case "right":
+		return proto.InputMouseButtonRight, nil
+	case "middle":
+		return proto.InputMouseButtonMiddle, nil
+	default:
+		return "", fmt.Errorf("unknown mouse button: %s (supported: left, right, middle)", button)
+	}
+}
+
+// ClickAt 在坐标处点击，用于 canvas 应用（地图、设计工具）等没有可定位 DOM 元素的场景
+func (e *Executor) ClickAt(ctx context.Context, opts *ClickAtOptions) (*OperationResult, error) {
+	page := e.activePage(ctx)
+	if page == nil {
+		return nil, fmt.Errorf("no active page")
+	}
+	if opts == nil {
+		return nil, fmt.Errorf("coordinates are required")
+	}
+	button, err := parseMouseButton(opts.Button)
+	if err != nil {
+		return nil, err
+	}
+	clickCount := opts.ClickCount
+	if clickCount <= 0 {
+		clickCount = 1
+	}
+
+	x, y := opts.X, opts.Y
+	if opts.Width > 0 && opts.Height > 0 {
+		x += opts.Width / 2
+		y += opts.Height / 2
+	}
+	point, err := resolvePoint(ctx, page, x, y, opts.Space)
+	if err != nil {
+		return nil, err
+	}
+
+	mouse := page.Context(ctx).Mouse
+	if err := mouse.MoveTo(proto.Point{X: point.X, Y: point.Y}); err != nil {
+		return nil, fmt.Errorf("failed to move mouse: %w", err)
+	}
+	if err := mouse.Click(button, clickCount); err != nil {
+		return nil, fmt.Errorf("failed to click: %w", err)
+	}
+
+	message := fmt.Sprintf("Clicked at viewport (%.0f, %.0f)", point.X, point.Y)
+	if point.Target != "" {
+		message += " on " + point.Target
+	}
+	return &OperationResult{
+		Success: true,
+		Message: message,
+		Data: map[string]interface{}{
+			"x":      point.X,
+			"y":      point.Y,
+			"target": point.Target,
+		},
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// DragBox 按下鼠标从起点拖动到终点，用于框选、平移地图或在画布上绘制
+func (e *Executor) DragBox(ctx context.Context, opts *DragBoxOptions) (*OperationResult, error) {
+	page := e.activePage(ctx)
+	if page == nil {
+		return nil, fmt.Errorf("no active page")
+	}
+	if opts == nil {
+		return nil, fmt.Errorf("coordinates are required")
+	}
+	steps := opts.Steps
+	if steps <= 0 {
+		steps = 10
+	}
+
+	from, err := resolvePoint(ctx, page, opts.FromX, opts.FromY, opts.Space)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start point: %w", err)
+	}
+	// 起点换算后页面可能已滚动，终点需要在起点换算之后计算
+	to, err := resolvePoint(ctx, page, opts.ToX, opts.ToY, opts.Space)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end point: %w", err)
+	}
+
+	mouse := page.Context(ctx).Mouse
+	if err := mouse.MoveTo(proto.Point{X: from.X, Y: from.Y}); err != nil {
+		return nil, fmt.Errorf("failed to move to start point: %w", err)
+	}
+	if err := mouse.Down(proto.InputMouseButtonLeft, 1); err != nil {
+		return nil, fmt.Errorf("failed to press mouse: %w", err)
+	}
+	if opts.HoldMs > 0 {
+		time.Sleep(time.Duration(opts.HoldMs) * time.Millisecond)
+	}
+	if err := mouse.MoveLinear(proto.Point{X: to.X, Y: to.Y}, steps); err != nil {
+		_ = mouse.Up(proto.InputMouseButtonLeft, 1)
+		return nil, fmt.Errorf("failed to move to end point: %w", err)
+	}
+	if err := mouse.Up(proto.InputMouseButtonLeft, 1); err != nil {
+		return nil, fmt.Errorf("failed to release mouse: %w", err)
+	}
+
+	return &OperationResult{
+		Success: true,
+		Message: fmt.Sprintf("Dragged from (%.0f, %.0f) to (%.0f, %.0f)", from.X, from.Y, to.X, to.Y),
+		Data: map[string]interface{}{
+			"from": map[string]float64{"x": from.X, "y": from.Y},
+			"to":   map[string]float64{"x": to.X, "y": to.Y},
+		},
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// ParseClickAtArguments 解析 browser_click_at 工具参数
+func ParseClickAtArguments(args map[string]interface{}) *ClickAtOptions {
+	opts := &ClickAtOptions{}
+	opts.X, _ = args["x"].(float64)
+	opts.Y, _ = args["y"].(float64)
+	opts.Width, _ = args["width"].(float64)
+	opts.Height, _ = args["height"].(float64)
+	opts.Space, _ = args["space"].(string)
+	opts.Button, _ = args["button"].(string)
+	if v, ok := args["click_count"].(float64); ok {
+		opts.ClickCount = int(v)
+	}
+	return opts
+}
+
+// ParseDragBoxArguments 解析 browser_drag_box 工具参数
+func ParseDragBoxArguments(args map[string]interface{}) *DragBoxOptions {
+	opts := &DragBoxOptions{}
+	opts.FromX, _ = args["from_x"].(float64)
+	opts.FromY, _ = args["from_y"].(float64)
+	opts.ToX, _ = args["to_x"].(float64)
+	opts.ToY, _ = args["to_y"].(float64)
+	opts.Space, _ = args["space"].(string)
+	if v, ok := args["steps"].(float64); ok {
+		opts.Steps = int(v)
+	}
+	if v, ok := args["hold_ms"].(float64); ok {
+		opts.HoldMs = int(v)
+	}
+	return opts
+}
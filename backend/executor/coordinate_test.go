@@ -0,0 +1,38 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+func TestParseMouseButton(t *testing.T) {
+	cases := map[string]proto.InputMouseButton{
+		"":       proto.InputMouseButtonLeft,
+		"left":   proto.InputMouseButtonLeft,
+		"Right":  proto.InputMouseButtonRight,
+		"middle": proto.InputMouseButtonMiddle,
+	}
+	for in, want := range cases {
+		got, err := parseMouseButton(in)
+		if err != nil || got != want {
+			t.Errorf("parseMouseButton(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := parseMouseButton("back"); err == nil {
+		t.Error("expected error for unknown button")
+	}
+}
+
+func TestParseClickAtArguments(t *testing.T) {
+	opts := ParseClickAtArguments(map[string]interface{}{
+		"x": 100.0, "y": 40.0, "width": 20.0, "height": 10.0,
+		"space": "screenshot", "button": "right", "click_count": 2.0,
+	})
+	if opts.X != 100 || opts.Y != 40 || opts.Width != 20 || opts.Height != 10 {
+		t.Errorf("unexpected coordinates: %+v", opts)
+	}
+	if opts.Space != CoordScreenshot || opts.Button != "right" || opts.ClickCount != 2 {
+		t.Errorf("unexpected options: %+v", opts)
+	}
+}
@@ -0,0 +1,190 @@
+package executor
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/browserwing/browserwing/pkg/artifacts"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+)
+
+// 拖放文件的总大小上限
+const maxDropFilesSize = 50 << 20
+
+// DragMode 拖拽方式
+type DragMode string
+
+const (
+	DragModeMouse DragMode = "mouse" // 模拟鼠标按下、移动、松开（默认）
+	DragModeHTML5 DragMode = "html5" // 派发 HTML5 拖放事件（dragstart/dragover/drop），携带 DataTransfer
+)
+
+// DragOptions 拖拽选项
+type DragOptions struct {
+	Mode  DragMode          // 拖拽方式：mouse 或 html5
+	Data  map[string]string // html5 模式下写入 DataTransfer 的数据（MIME 类型 -> 内容）
+	Files []string          // html5 模式下拖放的文件路径，必须位于上传目录内（此时源元素可以为空，模拟从系统拖入文件）
+}
+
+// dropFile 传给页面的拖放文件
+type dropFile struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Data string `json:"data"` // base64 编码的文件内容
+}
+
+// dragHTML5 通过派发 HTML5 拖放事件完成拖拽，适用于基于 HTML5 dnd 的拖拽库和文件拖放区域
+func (e *Executor) dragHTML5(ctx context.Context, page *rod.Page, fromIdentifier, toIdentifier string, opts *DragOptions) (*OperationResult, error) {
+	if fromIdentifier == "" && len(opts.Files) == 0 {
+		return nil, fmt.Errorf("source element is required unless files are dropped")
+	}
+
+	files := []dropFile{}
+	if len(opts.Files) > 0 {
+		sandbox, err := e.Browser.UploadSandbox()
+		var paths []string
+		if err == nil {
+			paths, err = e.Browser.ResolveUploadPaths(opts.Files)
+		}
+		if err == nil {
+			files, err = loadDropFiles(sandbox, paths)
+		}
+		if err != nil {
+			return &OperationResult{
+				Success:   false,
+				Error:     err.Error(),
+				Timestamp: time.Now(),
+				ErrorCode: errorCode(err),
+			}, err
+		}
+	}
+
+	var source interface{} // 为空时传 null
+	if fromIdentifier != "" {
+		fromElem, err := e.findElementWithTimeout(ctx, page, fromIdentifier, e.timeouts().Element())
+		if err != nil {
+			return &OperationResult{
+				Success:   false,
+				Error:     fmt.Sprintf("Failed to find source element: %s", err.Error()),
+				Timestamp: time.Now(),
+				ErrorCode: errorCode(err),
+			}, err
+		}
+		source = fromElem.Object
+	}
+
+	toElem, err := e.findElementWithTimeout(ctx, page, toIdentifier, e.timeouts().Element())
+	if err != nil {
+		return &OperationResult{
+			Success:   false,
+			Error:     fmt.Sprintf("Failed to find target element: %s", err.Error()),
+			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
+		}, err
+	}
+	if err := toElem.ScrollIntoView(); err != nil {
+		logger.Warn(ctx, "Failed to scroll drop target into view: %v", err)
+	}
+
+	data := opts.Data
+	if data == nil {
+		data = map[string]string{}
+	}
+
+	res, err := page.Evaluate(rod.Eval(`(source, target, data, files) => {
+		const dt = new DataTransfer();
+		for (const [type, value] of Object.entries(data)) dt.setData(type, value);
+		for (const f of files) {
+			const bin = atob(f.data);
+			const bytes = new Uint8Array(bin.length);
+			for (let i = 0; i < bin.length; i++) bytes[i] = bin.charCodeAt(i);
+			dt.items.add(new File([bytes], f.name, { type: f.type }));
+		}
+		const fire = (el, type) => {
+			const r = el.getBoundingClientRect();
+			const ev = new DragEvent(type, {
+				bubbles: true,
+				cancelable: true,
+				composed: true,
+				dataTransfer: dt,
+				clientX: r.left + r.width / 2,
+				clientY: r.top + r.height / 2,
+			});
+			return el.dispatchEvent(ev);
+		};
+
+		if (source) {
+			fire(source, 'dragstart');
+			fire(source, 'drag');
+		}
+		fire(target, 'dragenter');
+		// dragover 被 preventDefault 表示目标接受放置
+		const accepted = !fire(target, 'dragover');
+		fire(target, 'drop');
+		if (source) fire(source, 'dragend');
+		return { drop_accepted: accepted, types: Array.from(dt.types), files: dt.files.length };
+	}`, source, toElem.Object, data, files))
+	if err != nil {
+		return &OperationResult{
+			Success:   false,
+			Error:     fmt.Sprintf("Failed to dispatch drag events: %s", err.Error()),
+			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
+		}, err
+	}
+
+	var resultData map[string]interface{}
+	if err := res.Value.Unmarshal(&resultData); err != nil {
+		return nil, fmt.Errorf("failed to parse drag result: %w", err)
+	}
+
+	message := "Successfully dispatched HTML5 drag and drop"
+	if accepted, _ := resultData["drop_accepted"].(bool); !accepted {
+		message += " (target did not accept the drop in dragover)"
+	}
+	logger.Info(ctx, "%s: %s -> %s", message, fromIdentifier, toIdentifier)
+
+	return &OperationResult{
+		Success:   true,
+		Message:   message,
+		Timestamp: time.Now(),
+		Data:      resultData,
+	}, nil
+}
+
+// loadDropFiles 读取要拖放的文件；路径由客户端提供，只允许读取上传目录内的文件
+func loadDropFiles(sandbox *artifacts.Sandbox, paths []string) ([]dropFile, error) {
+	files := make([]dropFile, 0, len(paths))
+	total := 0
+	for _, path := range paths {
+		resolved, err := sandbox.Contains(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot drop file %s: %w", path, err)
+		}
+		content, err := os.ReadFile(resolved)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %s: %w", path, err)
+		}
+		total += len(content)
+		if total > maxDropFilesSize {
+			return nil, fmt.Errorf("dropped files exceed %d MB", maxDropFilesSize>>20)
+		}
+
+		mimeType := mime.TypeByExtension(filepath.Ext(path))
+		if mimeType == "" {
+			mimeType = "application/octet-stream"
+		}
+		files = append(files, dropFile{
+			Name: filepath.Base(path),
+			Type: mimeType,
+			Data: base64.StdEncoding.EncodeToString(content),
+		})
+	}
+	return files, nil
+}
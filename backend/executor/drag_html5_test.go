@@ -0,0 +1,63 @@
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/browserwing/browserwing/pkg/artifacts"
+)
+
+func TestLoadDropFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.csv")
+	if err := os.WriteFile(path, []byte("a,b"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sandbox, err := artifacts.NewSandbox(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := loadDropFiles(sandbox, []string{"report.csv"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(files) != 1 || files[0].Name != "report.csv" || files[0].Data != "YSxi" {
+		t.Errorf("unexpected files: %+v", files)
+	}
+	if files[0].Type != "text/csv" && files[0].Type != "text/csv; charset=utf-8" {
+		t.Errorf("unexpected mime type: %s", files[0].Type)
+	}
+
+	if _, err := loadDropFiles(sandbox, []string{path}); err != nil {
+		t.Errorf("absolute path inside the upload directory should be allowed: %v", err)
+	}
+	if _, err := loadDropFiles(sandbox, []string{filepath.Join(dir, "missing.txt")}); err == nil {
+		t.Errorf("expected error for missing file")
+	}
+	if _, err := loadDropFiles(sandbox, []string{"/etc/passwd"}); err == nil {
+		t.Errorf("expected error for file outside the upload directory")
+	}
+	if _, err := loadDropFiles(sandbox, []string{"../../etc/passwd"}); err == nil {
+		t.Errorf("expected error for path escaping the upload directory")
+	}
+}
+
+func TestParseDragArguments(t *testing.T) {
+	opts := ParseDragArguments(map[string]interface{}{
+		"mode":  "html5",
+		"data":  map[string]interface{}{"text/plain": "hello", "application/x-count": 3.0},
+		"files": []interface{}{"/tmp/a.png", ""},
+	})
+	if opts.Mode != DragModeHTML5 {
+		t.Errorf("mode = %s", opts.Mode)
+	}
+	if opts.Data["text/plain"] != "hello" || opts.Data["application/x-count"] != "3" {
+		t.Errorf("data = %v", opts.Data)
+	}
+	if len(opts.Files) != 1 || opts.Files[0] != "/tmp/a.png" {
+		t.Errorf("files = %v", opts.Files)
+	}
+}
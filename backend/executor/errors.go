@@ -0,0 +1,18 @@
+package executor
+
+import (
+	"fmt"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/services/browser"
+)
+
+// errorCode 返回操作失败的分类码，填入 OperationResult.ErrorCode
+func errorCode(err error) models.ErrorCode {
+	return browser.ClassifyError(err)
+}
+
+// elementNotFound 返回标注为 ELEMENT_NOT_FOUND 的错误
+func elementNotFound(format string, args ...interface{}) error {
+	return models.WithErrorCode(models.ErrorCodeElementNotFound, fmt.Errorf(format, args...))
+}
@@ -0,0 +1,65 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/browserwing/browserwing/config"
+)
+
+// ErrEvaluateNotAllowed 脚本被 Evaluate 沙箱策略拒绝
+var ErrEvaluateNotAllowed = errors.New("evaluate not allowed")
+
+// 只读模式下禁止的 DOM 修改操作
+// 这是对脚本源码的静态检查，只能拦截直接写法，无法识别别名和动态属性名，仅作提示性限制
+var domMutationPatterns = regexp.MustCompile(
+	`\.(innerHTML|outerHTML|innerText|outerText|textContent|nodeValue|value|checked|src|href)\s*(=[^=]|\+=)` +
+		`|\.style(\.\w+)?\s*=[^=]` +
+		`|\.(appendChild|insertBefore|removeChild|replaceChild|replaceWith|replaceChildren|insertAdjacentHTML|insertAdjacentElement|insertAdjacentText|setAttribute|setAttributeNS|removeAttribute|toggleAttribute|append|prepend|before|after|remove|setProperty|removeProperty)\s*\(` +
+		`|\.classList\.(add|remove|toggle|replace)\s*\(` +
+		`|\bdocument\.(write|writeln|open|close|execCommand)\s*\(`,
+)
+
+// checkEvaluatePolicy 检查脚本是否满足 Evaluate 沙箱策略，policy 为 nil 时不做限制
+func checkEvaluatePolicy(policy *config.EvaluateConfig, script string) error {
+	if policy == nil {
+		return nil
+	}
+
+	if policy.Disabled {
+		return fmt.Errorf("%w: evaluate is disabled by security policy", ErrEvaluateNotAllowed)
+	}
+
+	if policy.MaxScriptLength > 0 && len([]rune(script)) > policy.MaxScriptLength {
+		return fmt.Errorf("%w: script length %d exceeds limit %d", ErrEvaluateNotAllowed, len([]rune(script)), policy.MaxScriptLength)
+	}
+
+	if len(policy.AllowedSignatures) > 0 {
+		// 规则无效时拒绝执行，而不是跳过规则
+		signatures, err := policy.Signatures()
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrEvaluateNotAllowed, err)
+		}
+		normalized := config.NormalizeEvaluateScript(script)
+		matched := false
+		for _, re := range signatures {
+			if re.MatchString(normalized) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%w: script does not match any allowed signature", ErrEvaluateNotAllowed)
+		}
+	}
+
+	if policy.ReadOnly {
+		if m := domMutationPatterns.FindString(script); m != "" {
+			return fmt.Errorf("%w: read-only mode forbids DOM mutation (found %q)", ErrEvaluateNotAllowed, strings.TrimSpace(m))
+		}
+	}
+
+	return nil
+}
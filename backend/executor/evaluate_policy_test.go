@@ -0,0 +1,55 @@
+package executor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/browserwing/browserwing/config"
+)
+
+func TestCheckEvaluatePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *config.EvaluateConfig
+		script  string
+		wantErr bool
+	}{
+		{"no policy", nil, "document.body.innerHTML = ''", false},
+		{"disabled", &config.EvaluateConfig{Disabled: true}, "return 1", true},
+		{"within length", &config.EvaluateConfig{MaxScriptLength: 30}, "return document.title", false},
+		{"too long", &config.EvaluateConfig{MaxScriptLength: 10}, "return document.title", true},
+		{"signature match", &config.EvaluateConfig{AllowedSignatures: []string{`return document\.title;?`}}, "  return   document.title; ", false},
+		{"signature mismatch", &config.EvaluateConfig{AllowedSignatures: []string{`return document\.title;?`}}, "return document.cookie", true},
+		{"invalid signature fails closed", &config.EvaluateConfig{AllowedSignatures: []string{`return (document`}}, "return document.cookie", true},
+		{"read-only query", &config.EvaluateConfig{ReadOnly: true}, "return document.querySelector('a').href === location.href", false},
+		{"read-only assignment", &config.EvaluateConfig{ReadOnly: true}, "document.body.innerHTML = '<b>x</b>'", true},
+		{"read-only method", &config.EvaluateConfig{ReadOnly: true}, "document.body.appendChild(document.createElement('div'))", true},
+		{"read-only style", &config.EvaluateConfig{ReadOnly: true}, "el.style.display = 'none'", true},
+		{"read-only document write", &config.EvaluateConfig{ReadOnly: true}, "document.write('x')", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkEvaluatePolicy(tt.policy, tt.script)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkEvaluatePolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrEvaluateNotAllowed) {
+				t.Errorf("expected ErrEvaluateNotAllowed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestEvaluateConfigCompile(t *testing.T) {
+	if err := (&config.EvaluateConfig{AllowedSignatures: []string{`return document\.title;?`}}).Compile(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := (&config.EvaluateConfig{AllowedSignatures: []string{`return (document`}}).Compile(); err == nil {
+		t.Error("expected error for invalid signature")
+	}
+	var policy *config.EvaluateConfig
+	if err := policy.Compile(); err != nil {
+		t.Errorf("nil policy should compile: %v", err)
+	}
+}
@@ -6,43 +6,55 @@ import (
 	"sync"
 	"time"
 
+	"github.com/browserwing/browserwing/config"
 	"github.com/browserwing/browserwing/pkg/logger"
 	"github.com/browserwing/browserwing/services/browser"
 	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
 )
 
 // Executor 提供通用的浏览器自动化能力
 // 类似 agent-browser，提供语义化的浏览器操作接口
 type Executor struct {
 	Browser *browser.Manager
-	ctx     context.Context
 
-	// RefID 缓存（用于稳定的元素引用）
+	// RefID 缓存（用于稳定的元素引用），按会话和标签页分别缓存，
+	// 避免一个会话用另一个会话（或另一个标签页）的快照解析 RefID
 	// 参考 agent-browser: 使用语义化定位器而非 BackendNodeID
-	refIDMutex     sync.RWMutex
-	refIDMap       map[string]*RefData // refID -> 语义化定位器数据
-	refIDCounter   int
-	refIDSnapshot  *AccessibilitySnapshot
-	refIDTimestamp time.Time
-	refIDTTL       time.Duration
+	refIDMutex sync.RWMutex
+	refCaches  map[refCacheKey]*refIDCache
+	refIDTTL   time.Duration
+
+	// 各会话（MCP 客户端、Agent 任务）的标签页状态，未记录的会话使用全局活动页面
+	tabMutex       sync.Mutex
+	sessions       map[string]*sessionState
+	captures       map[proto.TargetTargetID]*browser.ResponseCapture // 各标签页的响应捕获
+	wsTaps         map[proto.TargetTargetID]*webSocketTap            // 各标签页的 WebSocket 帧监听
+	xhrRecorders   map[proto.TargetTargetID]*xhrRecorder             // 各标签页的 XHR/fetch 请求记录
+	dialogHandlers map[proto.TargetTargetID]func()                   // 各标签页的对话框处理订阅（取消订阅函数）
 }
 
 // NewExecutor 创建 Executor 实例
 func NewExecutor(browser *browser.Manager) *Executor {
 	return &Executor{
-		Browser:  browser,
-		ctx:      context.Background(),
-		refIDMap: make(map[string]*RefData),
-		refIDTTL: 300 * time.Second, // 默认 300 秒 TTL（5分钟），更长的缓存时间
+		Browser:     browser,
+		refCaches:   make(map[refCacheKey]*refIDCache),
+		refIDTTL:    300 * time.Second, // 默认 300 秒 TTL（5分钟），更长的缓存时间
+		sessions:    make(map[string]*sessionState),
 	}
 }
 
-// WithContext 设置上下文
+// WithContext 保留用于兼容，直接返回 e
+// Executor 被所有请求共享，不能保存单个请求的上下文；操作的上下文（实例、标签页、会话）通过各方法的 ctx 参数传递
 func (e *Executor) WithContext(ctx context.Context) *Executor {
-	e.ctx = ctx
 	return e
 }
 
+// timeouts 获取配置的默认超时（未配置时其方法返回默认值）
+func (e *Executor) timeouts() *config.TimeoutsConfig {
+	return e.Browser.GetTimeouts()
+}
+
 // ========== 页面管理 ==========
 
 // GetPage 获取当前活动页面
@@ -69,21 +81,36 @@ func (e *Executor) GetPage() *Page {
 
 // GetAccessibilitySnapshot 获取页面的可访问性快照（带 RefID 缓存）
 func (e *Executor) GetAccessibilitySnapshot(ctx context.Context) (*AccessibilitySnapshot, error) {
-	page := e.Browser.GetActivePage()
+	return e.accessibilitySnapshot(ctx, false)
+}
+
+// accessibilitySnapshot 获取可访问性快照，force 为 true 时忽略缓存重新获取
+// 重新获取时 RefID 接着旧缓存编号，旧 RefID 不会被复用到其他元素上
+func (e *Executor) accessibilitySnapshot(ctx context.Context, force bool) (*AccessibilitySnapshot, error) {
+	page := e.activePage(ctx)
 	if page == nil {
 		return nil, fmt.Errorf("no active page")
 	}
 
+	key := refCacheKey{session: sessionIDFromContext(ctx), target: page.TargetID}
+	// 当前文档标识，页面导航后缓存的快照失效
+	document := documentIdentity(page.Context(ctx))
+
 	// 检查缓存
 	e.refIDMutex.RLock()
-	cacheValid := e.refIDSnapshot != nil && time.Since(e.refIDTimestamp) < e.refIDTTL
-	if cacheValid {
+	cache := e.refCaches[key]
+	cacheValid := cache.validFor(page, document, e.refIDTTL)
+	if !force && cacheValid {
 		logger.Info(ctx, "[GetAccessibilitySnapshot] Using cached snapshot (age: %v, %d refs)", 
-			time.Since(e.refIDTimestamp), len(e.refIDMap))
-		cachedSnapshot := e.refIDSnapshot
+			time.Since(cache.timestamp), len(cache.refs))
+		cachedSnapshot := cache.snapshot
 		e.refIDMutex.RUnlock()
 		return cachedSnapshot, nil
 	}
+	counter := 0
+	if cache != nil {
+		counter = cache.counter
+	}
 	e.refIDMutex.RUnlock()
 
 	// 获取新快照
@@ -94,24 +121,60 @@ func (e *Executor) GetAccessibilitySnapshot(ctx context.Context) (*Accessibility
 	}
 
 	// 生成 RefID 并缓存
-	e.refIDMutex.Lock()
-	defer e.refIDMutex.Unlock()
+	cache = &refIDCache{refs: make(map[string]*RefData), counter: counter, targetID: page.TargetID, document: document}
+	cache.assignRefIDs(snapshot)
+	cache.snapshot = snapshot
+	cache.timestamp = time.Now()
 
-	e.refIDMap = make(map[string]*RefData)
-	e.refIDCounter = 0
-	e.assignRefIDs(snapshot)
-	e.refIDSnapshot = snapshot
-	e.refIDTimestamp = time.Now()
+	e.refIDMutex.Lock()
+	e.refCaches[key] = cache
+	e.refIDMutex.Unlock()
 
 	logger.Info(ctx, "[GetAccessibilitySnapshot] Cached new snapshot with %d refs (TTL: %v)",
-		len(e.refIDMap), e.refIDTTL)
+		len(cache.refs), e.refIDTTL)
 
 	return snapshot, nil
 }
 
+// refCacheKey RefID 缓存的键：会话 ID + 标签页 TargetID
+type refCacheKey struct {
+	session string
+	target  proto.TargetTargetID
+}
+
+// refIDCache 一个会话在一个标签页上的 RefID 缓存
+type refIDCache struct {
+	refs      map[string]*RefData // refID -> 语义化定位器数据
+	counter   int
+	snapshot  *AccessibilitySnapshot
+	timestamp time.Time
+	targetID  proto.TargetTargetID // 生成快照的标签页
+	document  string               // 生成快照时的文档标识（见 documentIdentity）
+	stale     bool                 // 操作推迟了快照提取，下次需要快照时重新获取（RefID 仍可解析）
+}
+
+// validFor 判断缓存的快照能否用于指定页面：必须来自同一个标签页、同一个文档、未过期且未被标记为过时
+func (c *refIDCache) validFor(page *rod.Page, document string, ttl time.Duration) bool {
+	return c != nil && c.snapshot != nil && !c.stale && c.targetID == page.TargetID &&
+		sameDocument(c.document, document) && time.Since(c.timestamp) < ttl
+}
+
+// lookupRef 查找会话在指定标签页上的 RefID 定位器数据，同时返回缓存的年龄和文档标识
+func (e *Executor) lookupRef(ctx context.Context, page *rod.Page, refID string) (*RefData, refLookupInfo, bool) {
+	e.refIDMutex.RLock()
+	defer e.refIDMutex.RUnlock()
+
+	cache := e.refCaches[refCacheKey{session: sessionIDFromContext(ctx), target: page.TargetID}]
+	if cache == nil || cache.targetID != page.TargetID {
+		return nil, refLookupInfo{}, false
+	}
+	refData, found := cache.refs[refID]
+	return refData, refLookupInfo{age: time.Since(cache.timestamp), document: cache.document, cached: true}, found
+}
+
 // assignRefIDs 为快照中的元素分配 RefID（参考 agent-browser 的实现）
 // 使用 role+name+nth 而非 BackendNodeID，以提高稳定性
-func (e *Executor) assignRefIDs(snapshot *AccessibilitySnapshot) {
+func (e *refIDCache) assignRefIDs(snapshot *AccessibilitySnapshot) {
 	// 跟踪 role:name 组合，用于处理重复元素
 	roleNameCounter := make(map[string]int) // "button:Submit" -> 0, 1, 2...
 	
@@ -127,8 +190,8 @@ func (e *Executor) assignRefIDs(snapshot *AccessibilitySnapshot) {
 		roleNameCounter[key]++
 		
 		// 分配 RefID
-		e.refIDCounter++
-		refID := fmt.Sprintf("e%d", e.refIDCounter)
+		e.counter++
+		refID := fmt.Sprintf("e%d", e.counter)
 		node.RefID = refID
 		
 		// 存储语义化定位器数据（参考 agent-browser）
@@ -158,7 +221,7 @@ func (e *Executor) assignRefIDs(snapshot *AccessibilitySnapshot) {
 			}
 		}
 		
-		e.refIDMap[refID] = refData
+		e.refs[refID] = refData
 		clickableCount++
 		
 		// 记录前10个元素用于调试
@@ -187,8 +250,8 @@ func (e *Executor) assignRefIDs(snapshot *AccessibilitySnapshot) {
 		roleNameCounter[key]++
 		
 		// 分配 RefID
-		e.refIDCounter++
-		refID := fmt.Sprintf("e%d", e.refIDCounter)
+		e.counter++
+		refID := fmt.Sprintf("e%d", e.counter)
 		node.RefID = refID
 		
 		// 存储语义化定位器数据
@@ -215,7 +278,7 @@ func (e *Executor) assignRefIDs(snapshot *AccessibilitySnapshot) {
 			}
 		}
 		
-		e.refIDMap[refID] = refData
+		e.refs[refID] = refData
 		inputCount++
 		
 		if inputCount <= 5 {
@@ -224,17 +287,27 @@ func (e *Executor) assignRefIDs(snapshot *AccessibilitySnapshot) {
 		}
 	}
 	logger.Info(context.Background(), "[assignRefIDs] Assigned %d RefIDs to input elements", inputCount)
-	logger.Info(context.Background(), "[assignRefIDs] Total RefIDs in map: %d (using semantic locators)", len(e.refIDMap))
+	logger.Info(context.Background(), "[assignRefIDs] Total RefIDs in map: %d (using semantic locators)", len(e.refs))
 }
 
-// InvalidateRefIDCache 清除 RefID 缓存
+// InvalidateRefIDCache 清除所有会话的 RefID 缓存
 func (e *Executor) InvalidateRefIDCache() {
 	e.refIDMutex.Lock()
 	defer e.refIDMutex.Unlock()
 
-	e.refIDMap = make(map[string]*RefData)
-	e.refIDCounter = 0
-	e.refIDSnapshot = nil
+	e.refCaches = make(map[refCacheKey]*refIDCache)
+}
+
+// invalidateRefIDs 清除匹配条件的 RefID 缓存
+func (e *Executor) invalidateRefIDs(match func(key refCacheKey) bool) {
+	e.refIDMutex.Lock()
+	defer e.refIDMutex.Unlock()
+
+	for key := range e.refCaches {
+		if match(key) {
+			delete(e.refCaches, key)
+		}
+	}
 }
 
 // RefreshAccessibilitySnapshot 刷新可访问性快照
@@ -260,7 +333,7 @@ func (e *Executor) FindElementByLabel(ctx context.Context, label string) (*Acces
 
 	node := snapshot.FindElementByLabel(label)
 	if node == nil {
-		return nil, fmt.Errorf("element not found with label: %s", label)
+		return nil, elementNotFound("element not found with label: %s", label)
 	}
 
 	return node, nil
@@ -306,6 +379,7 @@ func (e *Executor) ClickByLabel(ctx context.Context, label string) (*OperationRe
 			Success:   false,
 			Error:     err.Error(),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -320,6 +394,7 @@ func (e *Executor) TypeByLabel(ctx context.Context, label string, text string) (
 			Success:   false,
 			Error:     err.Error(),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -334,6 +409,7 @@ func (e *Executor) SelectByLabel(ctx context.Context, label string, value string
 			Success:   false,
 			Error:     err.Error(),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -344,7 +420,7 @@ func (e *Executor) SelectByLabel(ctx context.Context, label string, value string
 
 // GetPageInfo 获取页面信息（增强版，参考 playwright-mcp 和 agent-browser）
 func (e *Executor) GetPageInfo(ctx context.Context) (*OperationResult, error) {
-	page := e.Browser.GetActivePage()
+	page := e.activePage(ctx)
 	if page == nil {
 		return &OperationResult{
 			Success:   false,
@@ -398,6 +474,11 @@ func (e *Executor) GetPageInfo(ctx context.Context) (*OperationResult, error) {
 		pageInfo["elementCounts"] = stats.Value.Val()
 	}
 
+	// 已退出登录时标出（页面出现登录表单或返回 401）
+	if loggedOut, err := browser.DetectLoggedOut(ctx, page); err == nil && loggedOut != nil {
+		pageInfo["logged_out"] = loggedOut
+	}
+
 	// 5. 滚动信息
 	scrollInfo, err := page.Eval(`() => ({
 		scrollX: window.scrollX || window.pageXOffset || 0,
@@ -491,7 +572,7 @@ func (e *Executor) GetPageInfo(ctx context.Context) (*OperationResult, error) {
 
 // GetPageContent 获取页面内容
 func (e *Executor) GetPageContent(ctx context.Context) (*OperationResult, error) {
-	page := e.Browser.GetActivePage()
+	page := e.activePage(ctx)
 	if page == nil {
 		return &OperationResult{
 			Success:   false,
@@ -506,6 +587,7 @@ func (e *Executor) GetPageContent(ctx context.Context) (*OperationResult, error)
 			Success:   false,
 			Error:     err.Error(),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -521,7 +603,7 @@ func (e *Executor) GetPageContent(ctx context.Context) (*OperationResult, error)
 
 // GetPageText 获取页面文本
 func (e *Executor) GetPageText(ctx context.Context) (*OperationResult, error) {
-	page := e.Browser.GetActivePage()
+	page := e.activePage(ctx)
 	if page == nil {
 		return &OperationResult{
 			Success:   false,
@@ -537,6 +619,7 @@ func (e *Executor) GetPageText(ctx context.Context) (*OperationResult, error) {
 			Success:   false,
 			Error:     err.Error(),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -587,26 +670,52 @@ func (e *Executor) ExecuteBatch(ctx context.Context, operations []Operation) (*B
 		switch op.Type {
 		case "navigate":
 			url, _ := op.Params["url"].(string)
-			result, err = e.Navigate(ctx, url, nil)
+			opts := e.defaultNavigateOptions()
+			opts.Snapshot = ParseSnapshotArgument(op.Params)
+			result, err = e.Navigate(ctx, url, opts)
 
 		case "click":
 			identifier, _ := op.Params["identifier"].(string)
-			result, err = e.Click(ctx, identifier, nil)
+			opts := e.defaultClickOptions()
+			opts.Snapshot = ParseSnapshotArgument(op.Params)
+			result, err = e.Click(ctx, identifier, opts)
 
 		case "type":
 			identifier, _ := op.Params["identifier"].(string)
 			text, _ := op.Params["text"].(string)
-			result, err = e.Type(ctx, identifier, text, nil)
+			opts := e.defaultTypeOptions()
+			opts.Snapshot = ParseSnapshotArgument(op.Params)
+			result, err = e.Type(ctx, identifier, text, opts)
 
 		case "select":
 			identifier, _ := op.Params["identifier"].(string)
 			value, _ := op.Params["value"].(string)
-			result, err = e.Select(ctx, identifier, value, nil)
+			opts := e.defaultSelectOptions()
+			opts.Snapshot = ParseSnapshotArgument(op.Params)
+			result, err = e.Select(ctx, identifier, value, opts)
 
 		case "wait":
 			identifier, _ := op.Params["identifier"].(string)
 			result, err = e.WaitFor(ctx, identifier, nil)
 
+		case "hover_then_click":
+			trigger, _ := op.Params["trigger"].(string)
+			target, _ := op.Params["target"].(string)
+			result, err = e.HoverThenClick(ctx, trigger, target, nil)
+
+		case "choose_option":
+			identifier, _ := op.Params["identifier"].(string)
+			value, _ := op.Params["value"].(string)
+			result, err = e.ChooseOption(ctx, identifier, value, ParseChooseOptionArguments(op.Params))
+
+		case "fill_date":
+			identifier, _ := op.Params["identifier"].(string)
+			date, _ := op.Params["date"].(string)
+			result, err = e.FillDate(ctx, identifier, date, ParseFillDateArguments(op.Params))
+
+		case "scroll":
+			result, err = e.Scroll(ctx, ParseScrollArguments(op.Params))
+
 		default:
 			result = &OperationResult{
 				Success:   false,
@@ -659,7 +768,7 @@ type BatchResult struct {
 
 // EnsurePageReady 确保页面就绪
 func (e *Executor) EnsurePageReady(ctx context.Context) error {
-	page := e.Browser.GetActivePage()
+	page := e.activePage(ctx)
 	if page == nil {
 		return fmt.Errorf("no active page")
 	}
@@ -684,7 +793,7 @@ func (e *Executor) HighlightElementByLabel(ctx context.Context, label string) er
 		return err
 	}
 
-	page := e.Browser.GetActivePage()
+	page := e.activePage(ctx)
 	if page == nil {
 		return fmt.Errorf("no active page")
 	}
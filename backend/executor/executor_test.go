@@ -0,0 +1,100 @@
+package executor
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod"
+)
+
+func TestLookupRefIsolatedBySessionAndTab(t *testing.T) {
+	e := NewExecutor(nil)
+	tabA := &rod.Page{TargetID: "tab-a"}
+	tabB := &rod.Page{TargetID: "tab-b"}
+	ctxA := WithSession(context.Background(), "session-a")
+	ctxB := WithSession(context.Background(), "session-b")
+
+	e.refCaches[refCacheKey{session: "session-a", target: tabA.TargetID}] = &refIDCache{
+		refs:     map[string]*RefData{"e1": {Role: "button", Name: "Submit"}},
+		targetID: tabA.TargetID,
+	}
+
+	if _, _, found := e.lookupRef(ctxA, tabA, "e1"); !found {
+		t.Fatal("expected e1 to resolve in its own session and tab")
+	}
+	if _, _, found := e.lookupRef(ctxB, tabA, "e1"); found {
+		t.Error("e1 resolved against another session's snapshot")
+	}
+	if _, _, found := e.lookupRef(ctxA, tabB, "e1"); found {
+		t.Error("e1 resolved against another tab's snapshot")
+	}
+
+	e.invalidateRefIDs(func(key refCacheKey) bool { return key.session == "session-a" })
+	if _, _, found := e.lookupRef(ctxA, tabA, "e1"); found {
+		t.Error("e1 still resolves after the session's cache was invalidated")
+	}
+}
+
+func TestRefIDCacheValidFor(t *testing.T) {
+	tabA := &rod.Page{TargetID: "tab-a"}
+	tabB := &rod.Page{TargetID: "tab-b"}
+	cache := &refIDCache{snapshot: &AccessibilitySnapshot{}, timestamp: time.Now(), targetID: tabA.TargetID}
+
+	if !cache.validFor(tabA, "", time.Minute) {
+		t.Error("fresh snapshot of the same tab should be reused")
+	}
+	if cache.validFor(tabB, "", time.Minute) {
+		t.Error("snapshot of another tab must not be reused")
+	}
+	cache.document = "loader-1 https://example.com/a"
+	if !cache.validFor(tabA, "loader-1 https://example.com/a", time.Minute) {
+		t.Error("snapshot of the same document should be reused")
+	}
+	cache.stale = true
+	if cache.validFor(tabA, "loader-1 https://example.com/a", time.Minute) {
+		t.Error("snapshot marked stale by a deferred action must be refreshed")
+	}
+	cache.stale = false
+	if cache.validFor(tabA, "loader-2 https://example.com/a", time.Minute) {
+		t.Error("snapshot taken before a navigation must not be reused")
+	}
+	cache.timestamp = time.Now().Add(-2 * time.Minute)
+	if cache.validFor(tabA, "loader-1 https://example.com/a", time.Minute) {
+		t.Error("expired snapshot must not be reused")
+	}
+	var missing *refIDCache
+	if missing.validFor(tabA, "", time.Minute) {
+		t.Error("nil cache must not be valid")
+	}
+}
+
+func TestIsRefID(t *testing.T) {
+	for _, s := range []string{"e1", "e42", "e123456789"} {
+		if !isRefID(s) {
+			t.Errorf("isRefID(%q) = false, want true", s)
+		}
+	}
+	for _, s := range []string{"", "e", "email", "e1a", "button", "e12345678901"} {
+		if isRefID(s) {
+			t.Errorf("isRefID(%q) = true, want false", s)
+		}
+	}
+}
+
+func TestStaleRefErrorIncludesRefreshedSnapshot(t *testing.T) {
+	e := NewExecutor(nil)
+	if reason := e.staleRefReason(&rod.Page{}, refLookupInfo{}, false); reason == "" {
+		t.Error("a page without a snapshot should report the RefID as stale")
+	}
+
+	err := &StaleRefError{RefID: "e3", Reason: "the page has navigated since the last snapshot", Snapshot: "CLICKABLE:\n  @e41 - Next (button)\n"}
+	if msg := err.Error(); !strings.Contains(msg, "refID e3 is stale") || !strings.Contains(msg, "@e41 - Next") {
+		t.Errorf("unexpected message: %s", msg)
+	}
+	err.Snapshot = ""
+	if msg := err.Error(); !strings.Contains(msg, "run browser_snapshot") {
+		t.Errorf("without a refreshed snapshot the agent should be told to take one: %s", msg)
+	}
+}
@@ -0,0 +1,91 @@
+package executor
+
+import (
+	"context"
+
+	"github.com/go-rod/rod"
+)
+
+// extractBatchScript 一次提取所有匹配元素的数据，取值方式与 extractElementData 一致：
+// text 与 rod 的 Element.Text 相同（输入框取值或占位符、下拉框取选中项），html 为 outerHTML，
+// property 和 value 字段转换为字符串（字符串原样返回，不存在时为空，其他值序列化为 JSON）
+// type=attribute 且属性不存在时该元素的结果为 null
+const extractBatchScript = `(selector, type, attr, fields) => {
+	const text = (el) => {
+		switch (el.tagName) {
+			case 'INPUT':
+			case 'TEXTAREA':
+				return el.value || el.placeholder;
+			case 'SELECT':
+				return Array.from(el.selectedOptions).map(o => o.innerText).join();
+			default:
+				return el.innerText;
+		}
+	};
+	const str = (v) => {
+		if (typeof v === 'string') return v;
+		if (v === undefined || v === null) return '';
+		try { return JSON.stringify(v); } catch (e) { return String(v); }
+	};
+
+	return Array.from(document.querySelectorAll(selector)).map(el => {
+		const data = {};
+		switch (type) {
+			case 'text':
+				data.text = text(el);
+				break;
+			case 'html':
+				data.html = el.outerHTML;
+				break;
+			case 'attribute':
+				if (attr) {
+					const value = el.getAttribute(attr);
+					if (value === null) return null;
+					data[attr] = value;
+				}
+				break;
+			case 'property':
+				if (attr) data[attr] = str(el[attr]);
+				break;
+			default:
+				if (!fields || !fields.length) {
+					data.text = text(el);
+					break;
+				}
+				for (const field of fields) {
+					switch (field) {
+						case 'text': data.text = text(el); break;
+						case 'html': data.html = el.outerHTML; break;
+						case 'value': data.value = str(el.value); break;
+						case 'href':
+						case 'src': {
+							const value = el.getAttribute(field);
+							if (value !== null) data[field] = value;
+							break;
+						}
+					}
+				}
+		}
+		return data;
+	});
+}`
+
+// extractAll 在一次 Eval 中提取所有匹配元素的数据，避免逐个元素、逐个字段往返 CDP
+func extractAll(ctx context.Context, page *rod.Page, opts *ExtractOptions) ([]map[string]interface{}, error) {
+	fields := opts.Fields
+	if fields == nil {
+		fields = []string{}
+	}
+	res, err := page.Context(ctx).Eval(extractBatchScript, opts.Selector, opts.Type, opts.Attr, fields)
+	if err != nil {
+		return nil, err
+	}
+	var results []map[string]interface{}
+	if err := res.Value.Unmarshal(&results); err != nil {
+		return nil, err
+	}
+	if results == nil {
+		results = []map[string]interface{}{}
+	}
+	return results, nil
+}
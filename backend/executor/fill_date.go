@@ -0,0 +1,478 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// DateFillStrategy 日期填写方式
+type DateFillStrategy string
+
+const (
+	DateFillAuto     DateFillStrategy = "auto"     // 原生日期输入框或可编辑输入框设置值，否则操作日历控件（默认）
+	DateFillValue    DateFillStrategy = "value"    // 设置输入框的值并派发 input/change 事件（识别 flatpickr、jQuery UI 等组件的 API）
+	DateFillCalendar DateFillStrategy = "calendar" // 打开日历控件，翻到目标月份后点击日期
+)
+
+// FillDateOptions 日期填写选项
+type FillDateOptions struct {
+	Strategy DateFillStrategy // 填写方式
+	Format   string           // 文本输入框的日期格式，如 YYYY-MM-DD、MM/DD/YYYY（默认根据 placeholder 推断）
+	Timeout  time.Duration    // 查找元素和操作日历的超时时间
+}
+
+// dateInputInfo 日期输入框信息
+type dateInputInfo struct {
+	Tag         string `json:"tag"`
+	Type        string `json:"type"`
+	ReadOnly    bool   `json:"readonly"`
+	Placeholder string `json:"placeholder"`
+	Widget      string `json:"widget"` // 识别到的日期组件：flatpickr、jquery-ui、bootstrap-datepicker
+}
+
+// 原生日期类输入框
+var nativeDateInputTypes = map[string]bool{
+	"date": true, "datetime-local": true, "month": true, "week": true, "time": true,
+}
+
+// 形如 YYYY-MM-DD、mm/dd/yyyy 的 placeholder
+var dateFormatPlaceholder = regexp.MustCompile(`^[YyMmDd]{1,4}([-/. ][YyMmDd]{1,4}){2}$`)
+
+// 相对日期，如 +7d、-1m
+var relativeDatePattern = regexp.MustCompile(`^([+-]\d+)\s*([dwmy])$`)
+
+// FillDate 填写日期输入框：date 支持 YYYY-MM-DD、YYYY-MM-DD HH:mm 以及 today、tomorrow、+7d 等相对日期
+func (e *Executor) FillDate(ctx context.Context, identifier string, date string, opts *FillDateOptions) (*OperationResult, error) {
+	page := e.activePage(ctx)
+	if page == nil {
+		return nil, fmt.Errorf("no active page")
+	}
+	if opts == nil {
+		opts = &FillDateOptions{}
+	}
+	if opts.Strategy == "" {
+		opts.Strategy = DateFillAuto
+	}
+	switch opts.Strategy {
+	case DateFillAuto, DateFillValue, DateFillCalendar:
+	default:
+		return nil, fmt.Errorf("invalid date fill strategy: %s", opts.Strategy)
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 15 * time.Second
+	}
+
+	target, hasTime, err := parseTargetDate(date, time.Now())
+	if err != nil {
+		return &OperationResult{
+			Success:   false,
+			Error:     err.Error(),
+			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
+		}, err
+	}
+
+	elem, err := e.findElementWithTimeout(ctx, page, identifier, opts.Timeout)
+	if err != nil {
+		return &OperationResult{
+			Success:   false,
+			Error:     fmt.Sprintf("Element not found: %s", identifier),
+			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
+		}, err
+	}
+
+	res, err := elem.Eval(`function () {
+		const el = this;
+		let widget = '';
+		if (el._flatpickr) widget = 'flatpickr';
+		else if (window.jQuery && el.classList.contains('hasDatepicker')) widget = 'jquery-ui';
+		else if (window.jQuery && window.jQuery(el).data('datepicker')) widget = 'bootstrap-datepicker';
+		return {
+			tag: el.tagName.toLowerCase(),
+			type: (el.getAttribute('type') || '').toLowerCase(),
+			readonly: !!el.readOnly,
+			placeholder: el.getAttribute('placeholder') || '',
+			widget,
+		};
+	}`)
+	if err != nil {
+		return &OperationResult{
+			Success:   false,
+			Error:     fmt.Sprintf("Failed to inspect date input: %s", err.Error()),
+			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
+		}, err
+	}
+	var info dateInputInfo
+	if err := res.Value.Unmarshal(&info); err != nil {
+		return nil, fmt.Errorf("failed to parse date input info: %w", err)
+	}
+
+	editable := (info.Tag == "input" || info.Tag == "textarea") && !info.ReadOnly
+	strategy := opts.Strategy
+	if strategy == DateFillAuto {
+		strategy = DateFillValue
+		if !editable && !nativeDateInputTypes[info.Type] && info.Widget == "" {
+			strategy = DateFillCalendar
+		}
+	}
+
+	var value string
+	if strategy == DateFillValue {
+		value, err = setDateInputValue(elem, info, target, hasTime, opts.Format)
+		if err != nil && opts.Strategy == DateFillAuto {
+			logger.Warn(ctx, "[FillDate] Setting value failed, falling back to calendar: %v", err)
+			strategy = DateFillCalendar
+		} else if err != nil {
+			return &OperationResult{
+				Success:   false,
+				Error:     fmt.Sprintf("Failed to set date value: %s", err.Error()),
+				Timestamp: time.Now(),
+				ErrorCode: errorCode(err),
+			}, err
+		}
+	}
+
+	if strategy == DateFillCalendar {
+		if err := fillDateByCalendar(ctx, page, elem, target, time.Now().Add(opts.Timeout)); err != nil {
+			return &OperationResult{
+				Success:   false,
+				Error:     fmt.Sprintf("Failed to pick date from calendar: %s", err.Error()),
+				Timestamp: time.Now(),
+				ErrorCode: errorCode(err),
+			}, err
+		}
+		if current, err := elem.Property("value"); err == nil {
+			value = current.Str()
+		}
+	}
+
+	logger.Info(ctx, "[FillDate] Filled %s with %s using %s strategy (value: %q)", identifier, target.Format("2006-01-02"), strategy, value)
+
+	return &OperationResult{
+		Success:   true,
+		Message:   fmt.Sprintf("Successfully filled date %s into %s", target.Format("2006-01-02"), identifier),
+		Timestamp: time.Now(),
+		Data: map[string]interface{}{
+			"date":     target.Format("2006-01-02"),
+			"strategy": string(strategy),
+			"widget":   info.Widget,
+			"value":    value,
+		},
+	}, nil
+}
+
+// setDateInputValue 按输入框类型格式化日期并设置值，返回设置后的输入框值
+func setDateInputValue(elem *rod.Element, info dateInputInfo, target time.Time, hasTime bool, format string) (string, error) {
+	iso := target.Format("2006-01-02")
+	if hasTime {
+		iso = target.Format("2006-01-02T15:04")
+	}
+
+	var value string
+	switch {
+	case nativeDateInputTypes[info.Type]:
+		value = nativeDateValue(target, info.Type)
+	case info.Widget != "":
+		value = iso
+	default:
+		if format == "" && dateFormatPlaceholder.MatchString(info.Placeholder) {
+			format = info.Placeholder
+		}
+		if format == "" {
+			format = "YYYY-MM-DD"
+			if hasTime {
+				format = "YYYY-MM-DD HH:mm"
+			}
+		}
+		value = formatDate(target, format)
+	}
+
+	res, err := elem.Eval(`function (value, iso, widget) {
+		const el = this;
+		const $ = window.jQuery;
+		// 日期组件需要通过自身 API 更新内部状态
+		if (widget === 'flatpickr') {
+			el._flatpickr.setDate(iso, true);
+			return el.value;
+		}
+		if (widget === 'jquery-ui') {
+			$(el).datepicker('setDate', new Date(iso)).trigger('change');
+			return el.value;
+		}
+		if (widget === 'bootstrap-datepicker') {
+			$(el).datepicker('update', new Date(iso)).trigger('change');
+			return el.value;
+		}
+		// 使用原生 setter，兼容 React 等受控组件
+		const proto = el instanceof HTMLTextAreaElement ? HTMLTextAreaElement.prototype : HTMLInputElement.prototype;
+		const setter = Object.getOwnPropertyDescriptor(proto, 'value').set;
+		el.focus();
+		setter.call(el, value);
+		el.dispatchEvent(new Event('input', { bubbles: true }));
+		el.dispatchEvent(new Event('change', { bubbles: true }));
+		el.dispatchEvent(new FocusEvent('blur'));
+		return el.value;
+	}`, value, iso, info.Widget)
+	if err != nil {
+		return "", err
+	}
+
+	current := res.Value.Str()
+	if current == "" {
+		return "", fmt.Errorf("input rejected value %q", value)
+	}
+	return current, nil
+}
+
+// fillDateByCalendar 打开日历控件，逐月翻页到目标月份后点击目标日期
+func fillDateByCalendar(ctx context.Context, page *rod.Page, elem *rod.Element, target time.Time, deadline time.Time) error {
+	if err := elem.ScrollIntoView(); err != nil {
+		logger.Warn(ctx, "[FillDate] Failed to scroll input into view: %v", err)
+	}
+	if err := elem.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return fmt.Errorf("failed to open calendar: %w", err)
+	}
+
+	var last map[string]interface{}
+	err := pollUntil(ctx, deadline, func() (bool, error) {
+		res, err := page.Eval(calendarStepScript, target.Year(), int(target.Month()), target.Day(), target.Format("2006-01-02"))
+		if err != nil {
+			return false, err
+		}
+		last = nil
+		if err := res.Value.Unmarshal(&last); err != nil {
+			return false, err
+		}
+		switch last["status"] {
+		case "clicked":
+			return true, nil
+		case "moved", "no_calendar":
+			return false, nil
+		default:
+			return false, fmt.Errorf("%v (shown: %v, header: %v)", last["status"], last["shown"], last["header"])
+		}
+	})
+	if err != nil && last != nil && last["status"] == "no_calendar" {
+		return fmt.Errorf("no supported calendar widget opened: %w", err)
+	}
+	return err
+}
+
+// calendarStepScript 日历控件的一步操作：读取当前显示的月份，不是目标月份时点击上/下一月，否则点击目标日期
+// 支持 flatpickr、jQuery UI、bootstrap-datepicker、Ant Design、Element UI、react-datepicker、MUI、Pikaday
+// 以及带 role=grid 的通用日历
+const calendarStepScript = `(year, month, day, iso) => {
+	const visible = (el) => {
+		if (!el) return false;
+		const r = el.getBoundingClientRect();
+		const s = getComputedStyle(el);
+		return r.width > 0 && r.height > 0 && s.visibility !== 'hidden' && s.display !== 'none';
+	};
+	const first = (root, selectors) => {
+		for (const sel of selectors) {
+			const el = Array.from(root.querySelectorAll(sel)).find(visible);
+			if (el) return el;
+		}
+		return null;
+	};
+	const fire = (el) => {
+		for (const type of ['mousedown', 'mouseup', 'click']) {
+			el.dispatchEvent(new MouseEvent(type, { bubbles: true, cancelable: true, view: window }));
+		}
+	};
+	const readable = (node) => {
+		if (node.tagName === 'SELECT') return node.selectedOptions[0] ? node.selectedOptions[0].text : '';
+		if (node.tagName === 'INPUT') return node.value;
+		let text = '';
+		for (const child of node.childNodes) {
+			text += ' ' + (child.nodeType === 3 ? child.textContent : child.nodeType === 1 ? readable(child) : '');
+		}
+		return text;
+	};
+
+	const cal = first(document, [
+		'.flatpickr-calendar.open', '#ui-datepicker-div', '.datepicker-dropdown',
+		'.ant-picker-dropdown:not(.ant-picker-dropdown-hidden)', '.el-picker-panel', '.react-datepicker',
+		'.MuiDateCalendar-root', '.MuiPickersPopper-root', '.pika-single:not(.is-hidden)',
+		'[role=dialog] [role=grid]', '[role=grid]',
+	]);
+	if (!cal) return { status: 'no_calendar' };
+
+	const header = first(cal, [
+		'.flatpickr-current-month', '.ui-datepicker-title', '.datepicker-days .datepicker-switch',
+		'.ant-picker-header-view', '.el-date-picker__header', '.react-datepicker__current-month',
+		'.MuiPickersCalendarHeader-label', '.pika-title', '[aria-live=polite]',
+	]);
+	let text = header ? readable(header) : '';
+	if (!text.trim()) text = cal.getAttribute('aria-label') || '';
+	text = text.replace(/\s+/g, ' ').trim();
+
+	let shownYear = 0, shownMonth = 0, m;
+	const months = ['jan', 'feb', 'mar', 'apr', 'may', 'jun', 'jul', 'aug', 'sep', 'oct', 'nov', 'dec'];
+	const monthIndex = months.findIndex((name) => new RegExp('\\b' + name, 'i').test(text));
+	const yearMatch = text.match(/\b(\d{4})\b/);
+	if ((m = text.match(/(\d{4})\s*年\s*(\d{1,2})\s*月/))) {
+		shownYear = +m[1]; shownMonth = +m[2];
+	} else if (monthIndex >= 0 && yearMatch) {
+		shownYear = +yearMatch[1]; shownMonth = monthIndex + 1;
+	} else if ((m = text.match(/(\d{4})\s*[-/.]\s*(\d{1,2})/))) {
+		shownYear = +m[1]; shownMonth = +m[2];
+	}
+	if (!shownYear) return { status: 'unknown_month', header: text.slice(0, 100) };
+	const shown = shownYear + '-' + String(shownMonth).padStart(2, '0');
+
+	const diff = (year - shownYear) * 12 + (month - shownMonth);
+	if (diff !== 0) {
+		const btn = first(cal, diff > 0 ? [
+			'.flatpickr-next-month', '.ui-datepicker-next', '.datepicker-days .next', '.ant-picker-header-next-btn',
+			'.el-date-picker__header .el-icon-arrow-right', '.react-datepicker__navigation--next', '.pika-next',
+			'[aria-label*="next month" i]', '[title*="next month" i]', '[aria-label*="下个月"]', '[title*="下个月"]',
+		] : [
+			'.flatpickr-prev-month', '.ui-datepicker-prev', '.datepicker-days .prev', '.ant-picker-header-prev-btn',
+			'.el-date-picker__header .el-icon-arrow-left', '.react-datepicker__navigation--previous', '.pika-prev',
+			'[aria-label*="previous month" i]', '[title*="previous month" i]', '[aria-label*="上个月"]', '[title*="上个月"]',
+		]);
+		if (!btn) return { status: 'no_navigation', shown };
+		fire(btn);
+		return { status: 'moved', shown };
+	}
+
+	const cells = Array.from(cal.querySelectorAll([
+		'.flatpickr-day', '.ui-datepicker-calendar td[data-handler]', '.datepicker-days td.day', '.ant-picker-cell',
+		'.el-date-table td', '.react-datepicker__day', '.MuiPickersDay-root', '.pika-button', '[role=gridcell]',
+	].join(','))).filter(visible);
+	const outside = /prevMonthDay|nextMonthDay|\b(old|new)\b|outside-?month|dayOutsideMonth|prev-month|next-month|disabled/i;
+	const className = (el) => (el && typeof el.className === 'string' ? el.className : '');
+	let cell = cells.find((el) => el.getAttribute('title') === iso || el.getAttribute('data-date') === iso ||
+		(el.getAttribute('aria-label') || '').includes(iso));
+	if (!cell) {
+		cell = cells.find((el) => {
+			const cls = className(el) + ' ' + className(el.parentElement);
+			if (outside.test(cls) || el.getAttribute('aria-disabled') === 'true') return false;
+			if (cls.includes('ant-picker-cell') && !cls.includes('ant-picker-cell-in-view')) return false;
+			return (el.innerText || el.textContent || '').trim() === String(day);
+		});
+	}
+	if (!cell) return { status: 'day_not_found', shown };
+	fire(cell.querySelector('a, button') || cell);
+	return { status: 'clicked', shown };
+}`
+
+// parseTargetDate 解析目标日期，支持绝对日期和 today、tomorrow、yesterday、+7d、-1m 等相对日期
+func parseTargetDate(s string, now time.Time) (time.Time, bool, error) {
+	s = strings.TrimSpace(s)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch strings.ToLower(s) {
+	case "":
+		return time.Time{}, false, fmt.Errorf("date is required")
+	case "today":
+		return today, false, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), false, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), false, nil
+	}
+
+	if m := relativeDatePattern.FindStringSubmatch(strings.ToLower(s)); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		switch m[2] {
+		case "d":
+			return today.AddDate(0, 0, n), false, nil
+		case "w":
+			return today.AddDate(0, 0, 7*n), false, nil
+		case "m":
+			return today.AddDate(0, n, 0), false, nil
+		default:
+			return today.AddDate(n, 0, 0), false, nil
+		}
+	}
+
+	layouts := []struct {
+		layout  string
+		hasTime bool
+	}{
+		{"2006-01-02", false},
+		{"2006/01/02", false},
+		{"2006-01-02T15:04", true},
+		{"2006-01-02 15:04", true},
+		{"2006-01-02T15:04:05", true},
+		{"2006-01-02 15:04:05", true},
+		{time.RFC3339, true},
+	}
+	for _, l := range layouts {
+		if t, err := time.ParseInLocation(l.layout, s, now.Location()); err == nil {
+			return t, l.hasTime, nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("unsupported date %q (use YYYY-MM-DD, YYYY-MM-DD HH:mm, today or +7d)", s)
+}
+
+// nativeDateValue 原生日期类输入框要求的值格式
+func nativeDateValue(t time.Time, inputType string) string {
+	switch inputType {
+	case "datetime-local":
+		return t.Format("2006-01-02T15:04")
+	case "month":
+		return t.Format("2006-01")
+	case "week":
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case "time":
+		return t.Format("15:04")
+	default:
+		return t.Format("2006-01-02")
+	}
+}
+
+// 日期格式占位符，按长度从长到短匹配
+var dateFormatTokens = []struct {
+	token  string
+	layout string
+}{
+	{"YYYY", "2006"},
+	{"YY", "06"},
+	{"MM", "01"},
+	{"M", "1"},
+	{"DD", "02"},
+	{"D", "2"},
+	{"HH", "15"},
+	{"mm", "04"},
+	{"ss", "05"},
+}
+
+// formatDate 按 YYYY-MM-DD 风格的格式输出日期
+// 格式中没有小时时不区分大小写（兼容 mm/dd/yyyy 这类 placeholder）
+func formatDate(t time.Time, format string) string {
+	if !strings.ContainsAny(format, "Hh") {
+		format = strings.ToUpper(format)
+	}
+
+	var layout strings.Builder
+	for i := 0; i < len(format); {
+		matched := false
+		for _, tok := range dateFormatTokens {
+			if strings.HasPrefix(format[i:], tok.token) {
+				layout.WriteString(tok.layout)
+				i += len(tok.token)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			layout.WriteByte(format[i])
+			i++
+		}
+	}
+	return t.Format(layout.String())
+}
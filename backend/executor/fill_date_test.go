@@ -0,0 +1,80 @@
+package executor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTargetDate(t *testing.T) {
+	now := time.Date(2024, 1, 31, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		input    string
+		want     string
+		hasTime  bool
+		wantFail bool
+	}{
+		{input: "2024-03-05", want: "2024-03-05 00:00"},
+		{input: "2024/03/05", want: "2024-03-05 00:00"},
+		{input: "2024-03-05 09:15", want: "2024-03-05 09:15", hasTime: true},
+		{input: "today", want: "2024-01-31 00:00"},
+		{input: "Tomorrow", want: "2024-02-01 00:00"},
+		{input: "+7d", want: "2024-02-07 00:00"},
+		{input: "-1w", want: "2024-01-24 00:00"},
+		{input: "+1y", want: "2025-01-31 00:00"},
+		{input: "03/05/2024", wantFail: true},
+		{input: "", wantFail: true},
+	}
+
+	for _, tt := range tests {
+		got, hasTime, err := parseTargetDate(tt.input, now)
+		if tt.wantFail {
+			if err == nil {
+				t.Errorf("parseTargetDate(%q) expected error", tt.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseTargetDate(%q) unexpected error: %v", tt.input, err)
+			continue
+		}
+		if got.Format("2006-01-02 15:04") != tt.want || hasTime != tt.hasTime {
+			t.Errorf("parseTargetDate(%q) = %s (time=%v), want %s (time=%v)", tt.input, got.Format("2006-01-02 15:04"), hasTime, tt.want, tt.hasTime)
+		}
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	date := time.Date(2024, 3, 5, 9, 7, 0, 0, time.UTC)
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"YYYY-MM-DD", "2024-03-05"},
+		{"mm/dd/yyyy", "03/05/2024"},
+		{"DD.MM.YYYY", "05.03.2024"},
+		{"D/M/YY", "5/3/24"},
+		{"YYYY-MM-DD HH:mm", "2024-03-05 09:07"},
+	}
+	for _, tt := range tests {
+		if got := formatDate(date, tt.format); got != tt.want {
+			t.Errorf("formatDate(%q) = %s, want %s", tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestNativeDateValue(t *testing.T) {
+	date := time.Date(2024, 3, 5, 9, 7, 0, 0, time.UTC)
+	for inputType, want := range map[string]string{
+		"date":           "2024-03-05",
+		"datetime-local": "2024-03-05T09:07",
+		"month":          "2024-03",
+		"week":           "2024-W10",
+		"time":           "09:07",
+	} {
+		if got := nativeDateValue(date, inputType); got != want {
+			t.Errorf("nativeDateValue(%s) = %s, want %s", inputType, got, want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// HoverThenClickOptions 悬停后点击选项
+type HoverThenClickOptions struct {
+	Timeout time.Duration // 每次尝试中等待目标元素出现的超时时间
+	Retries int           // 最多尝试次数
+}
+
+// HoverThenClick 悬停触发元素，等待目标元素出现后点击（用于悬停展开的菜单）
+// 悬停与点击在同一次尝试中完成，目标未出现或点击失败时重新悬停并重试
+func (e *Executor) HoverThenClick(ctx context.Context, trigger string, target string, opts *HoverThenClickOptions) (*OperationResult, error) {
+	page := e.activePage(ctx)
+	if page == nil {
+		return nil, fmt.Errorf("no active page")
+	}
+	if trigger == "" || target == "" {
+		return nil, fmt.Errorf("trigger and target are required")
+	}
+
+	if opts == nil {
+		opts = &HoverThenClickOptions{}
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	if opts.Retries <= 0 {
+		opts.Retries = 3
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= opts.Retries; attempt++ {
+		if ctx.Err() != nil {
+			lastErr = ctx.Err()
+			break
+		}
+		if attempt > 1 {
+			logger.Warn(ctx, "[HoverThenClick] Attempt %d/%d after error: %v", attempt, opts.Retries, lastErr)
+			// 移开鼠标让菜单收起，再重新悬停
+			_ = page.Mouse.MoveTo(proto.Point{X: 0, Y: 0})
+			time.Sleep(300 * time.Millisecond)
+		}
+
+		triggerElem, err := e.findElementWithTimeout(ctx, page, trigger, opts.Timeout)
+		if err != nil {
+			lastErr = fmt.Errorf("trigger not found: %s", trigger)
+			continue
+		}
+		if err := triggerElem.ScrollIntoView(); err != nil {
+			logger.Warn(ctx, "[HoverThenClick] Failed to scroll trigger into view: %v", err)
+		}
+		if err := triggerElem.Hover(); err != nil {
+			lastErr = fmt.Errorf("failed to hover trigger: %w", err)
+			continue
+		}
+
+		targetElem, err := e.findElementWithTimeout(ctx, page, target, opts.Timeout)
+		if err != nil {
+			lastErr = fmt.Errorf("target not revealed: %s", target)
+			continue
+		}
+		if err := targetElem.Timeout(opts.Timeout).WaitVisible(); err != nil {
+			lastErr = fmt.Errorf("target not visible: %s", target)
+			continue
+		}
+
+		if err := targetElem.Click(proto.InputMouseButtonLeft, 1); err != nil {
+			lastErr = fmt.Errorf("failed to click target: %w", err)
+			continue
+		}
+
+		logger.Info(ctx, "[HoverThenClick] Clicked %s after hovering %s (attempt %d)", target, trigger, attempt)
+		return &OperationResult{
+			Success:   true,
+			Message:   fmt.Sprintf("Hovered %s and clicked %s", trigger, target),
+			Timestamp: time.Now(),
+			Data: map[string]interface{}{
+				"attempts": attempt,
+			},
+		}, nil
+	}
+
+	return &OperationResult{
+		Success:   false,
+		Error:     fmt.Sprintf("Failed to hover %s and click %s after %d attempts: %v", trigger, target, opts.Retries, lastErr),
+		Timestamp: time.Now(),
+		ErrorCode: errorCode(lastErr),
+	}, lastErr
+}
@@ -0,0 +1,196 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/pkg/logger"
+)
+
+// 默认返回的计算样式属性
+var defaultInspectStyles = []string{
+	"display", "visibility", "opacity", "position", "z-index",
+	"pointer-events", "overflow", "width", "height",
+	"color", "background-color", "font-size", "cursor", "transform",
+}
+
+// InspectOptions 元素检查选项
+type InspectOptions struct {
+	Styles  []string      // 额外返回的计算样式属性
+	Timeout time.Duration // 查找元素的超时时间
+}
+
+// InspectElement 检查元素：返回属性、计算样式、位置尺寸、可见/可交互判断及祖先链
+// 用于排查点击被遮挡、元素被判定为隐藏等问题
+func (e *Executor) InspectElement(ctx context.Context, identifier string, opts *InspectOptions) (*OperationResult, error) {
+	page := e.activePage(ctx)
+	if page == nil {
+		return nil, fmt.Errorf("no active page")
+	}
+	if identifier == "" {
+		return nil, fmt.Errorf("identifier is required")
+	}
+	if opts == nil {
+		opts = &InspectOptions{}
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = e.timeouts().Element()
+	}
+
+	elem, err := e.findElementWithTimeout(ctx, page, identifier, opts.Timeout)
+	if err != nil {
+		return &OperationResult{
+			Success:   false,
+			Error:     fmt.Sprintf("Element not found: %s", identifier),
+			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
+		}, err
+	}
+
+	styles := append([]string{}, defaultInspectStyles...)
+	for _, s := range opts.Styles {
+		if s = strings.TrimSpace(s); s != "" {
+			styles = append(styles, s)
+		}
+	}
+
+	res, err := elem.Eval(`function (styles) {
+		const el = this;
+		const describe = (node) => {
+			if (!node || node.nodeType !== 1) return null;
+			let desc = node.tagName.toLowerCase();
+			if (node.id) desc += '#' + node.id;
+			if (typeof node.className === 'string' && node.className.trim()) {
+				desc += '.' + node.className.trim().split(/\s+/).slice(0, 3).join('.');
+			}
+			return desc;
+		};
+
+		const attributes = {};
+		for (const attr of Array.from(el.attributes)) attributes[attr.name] = attr.value;
+
+		const cs = getComputedStyle(el);
+		const computed = {};
+		for (const name of styles) computed[name] = cs.getPropertyValue(name);
+
+		const rect = el.getBoundingClientRect();
+		const vw = window.innerWidth, vh = window.innerHeight;
+		const inViewport = rect.bottom > 0 && rect.right > 0 && rect.top < vh && rect.left < vw;
+
+		const reasons = [];
+		if (!el.isConnected) reasons.push('element is detached from the document');
+		if (rect.width === 0 || rect.height === 0) reasons.push('element has zero size');
+		if (cs.display === 'none') reasons.push('display is none');
+		if (cs.visibility === 'hidden' || cs.visibility === 'collapse') reasons.push('visibility is ' + cs.visibility);
+		if (parseFloat(cs.opacity) === 0) reasons.push('opacity is 0');
+
+		// 祖先链：记录可能导致隐藏或遮挡的样式
+		const ancestors = [];
+		for (let node = el.parentElement; node; node = node.parentElement) {
+			const s = getComputedStyle(node);
+			const entry = {
+				element: describe(node),
+				display: s.display,
+				visibility: s.visibility,
+				opacity: s.opacity,
+				overflow: s.overflow,
+				position: s.position,
+				z_index: s.zIndex,
+				pointer_events: s.pointerEvents,
+			};
+			ancestors.push(entry);
+			if (s.display === 'none') reasons.push('ancestor ' + entry.element + ' has display none');
+			if (parseFloat(s.opacity) === 0) reasons.push('ancestor ' + entry.element + ' has opacity 0');
+			if (s.overflow !== 'visible') {
+				const r = node.getBoundingClientRect();
+				if (rect.bottom <= r.top || rect.top >= r.bottom || rect.right <= r.left || rect.left >= r.right) {
+					reasons.push('clipped by ancestor ' + entry.element + ' (overflow: ' + s.overflow + ')');
+				}
+			}
+		}
+		const visible = reasons.length === 0;
+
+		// 可交互判断：可见、未禁用、可接收指针事件、中心点未被其他元素遮挡
+		const blockers = [];
+		if (el.disabled === true) blockers.push('element is disabled');
+		if (el.getAttribute('aria-disabled') === 'true') blockers.push('aria-disabled is true');
+		if (cs.pointerEvents === 'none') blockers.push('pointer-events is none');
+		if (el.closest('[inert]')) blockers.push('element is inside an inert subtree');
+
+		let interceptedBy = null;
+		let hitTarget = null;
+		if (visible) {
+			const x = rect.left + rect.width / 2;
+			const y = rect.top + rect.height / 2;
+			if (!inViewport) {
+				blockers.push('element center is outside the viewport (scroll into view first)');
+			} else {
+				const hit = document.elementFromPoint(x, y);
+				hitTarget = describe(hit);
+				if (hit && hit !== el && !el.contains(hit)) {
+					interceptedBy = {
+						element: describe(hit),
+						outer_html: hit.outerHTML.slice(0, 300),
+						z_index: getComputedStyle(hit).zIndex,
+						position: getComputedStyle(hit).position,
+					};
+					blockers.push('click at element center would be intercepted by ' + describe(hit));
+				}
+			}
+		}
+
+		// 点击的效果：是否提交表单、打开哪个链接、链接是否为下载
+		const submitter = el.closest('button, input');
+		const link = el.closest('a[href], area[href]');
+		const clickTarget = {
+			submits_form: !!(submitter && (submitter.type === 'submit' || submitter.type === 'image') && submitter.form),
+			href: link ? link.href : '',
+			download: !!(link && link.hasAttribute('download')),
+		};
+
+		return {
+			element: describe(el),
+			tag: el.tagName.toLowerCase(),
+			attributes,
+			text: (el.innerText || el.textContent || '').trim().slice(0, 500),
+			computed_styles: computed,
+			bounding_box: { x: rect.x, y: rect.y, width: rect.width, height: rect.height },
+			viewport: { width: vw, height: vh, scroll_x: window.scrollX, scroll_y: window.scrollY },
+			in_viewport: inViewport,
+			visible,
+			hidden_reasons: reasons,
+			interactable: visible && blockers.length === 0,
+			interaction_blockers: blockers,
+			hit_target: hitTarget,
+			intercepted_by: interceptedBy,
+			ancestors,
+			click_target: clickTarget,
+		};
+	}`, styles)
+	if err != nil {
+		return &OperationResult{
+			Success:   false,
+			Error:     fmt.Sprintf("Failed to inspect element: %s", err.Error()),
+			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
+		}, err
+	}
+
+	var data map[string]interface{}
+	if err := res.Value.Unmarshal(&data); err != nil {
+		return nil, fmt.Errorf("failed to parse inspection result: %w", err)
+	}
+
+	visible, _ := data["visible"].(bool)
+	interactable, _ := data["interactable"].(bool)
+	logger.Info(ctx, "Inspected element %s: visible=%v, interactable=%v", identifier, visible, interactable)
+
+	return &OperationResult{
+		Success:   true,
+		Message:   fmt.Sprintf("Inspected %v: visible=%v, interactable=%v", data["element"], visible, interactable),
+		Timestamp: time.Now(),
+		Data:      data,
+	}, nil
+}
@@ -0,0 +1,355 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/input"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// 组合键序列中相邻两步之间的间隔
+const keySequenceStepDelay = 50 * time.Millisecond
+
+// 修饰键别名，mod 表示平台主修饰键（macOS 为 Command，其他平台为 Ctrl）
+var modifierAliases = map[string]string{
+	"ctrl":             "ctrl",
+	"control":          "ctrl",
+	"shift":            "shift",
+	"alt":              "alt",
+	"option":           "alt",
+	"opt":              "alt",
+	"meta":             "meta",
+	"cmd":              "meta",
+	"command":          "meta",
+	"win":              "meta",
+	"super":            "meta",
+	"mod":              "mod",
+	"cmdorctrl":        "mod",
+	"commandorcontrol": "mod",
+	"primary":          "mod",
+}
+
+// 命名按键（不区分大小写）
+var namedKeys = map[string]input.Key{
+	"enter":      input.Enter,
+	"return":     input.Enter,
+	"tab":        input.Tab,
+	"escape":     input.Escape,
+	"esc":        input.Escape,
+	"backspace":  input.Backspace,
+	"delete":     input.Delete,
+	"del":        input.Delete,
+	"insert":     input.Insert,
+	"arrowup":    input.ArrowUp,
+	"up":         input.ArrowUp,
+	"arrowdown":  input.ArrowDown,
+	"down":       input.ArrowDown,
+	"arrowleft":  input.ArrowLeft,
+	"left":       input.ArrowLeft,
+	"arrowright": input.ArrowRight,
+	"right":      input.ArrowRight,
+	"home":       input.Home,
+	"end":        input.End,
+	"pageup":     input.PageUp,
+	"pagedown":   input.PageDown,
+	"space":      input.Space,
+	"plus":       input.Key('+'),
+	"f1":         input.F1,
+	"f2":         input.F2,
+	"f3":         input.F3,
+	"f4":         input.F4,
+	"f5":         input.F5,
+	"f6":         input.F6,
+	"f7":         input.F7,
+	"f8":         input.F8,
+	"f9":         input.F9,
+	"f10":        input.F10,
+	"f11":        input.F11,
+	"f12":        input.F12,
+}
+
+// lookupKey 按名称查找按键，支持命名键（Enter、F5 等）和单个可打印 ASCII 字符
+func lookupKey(name string) (input.Key, bool) {
+	if len(name) == 1 && name[0] >= ' ' && name[0] <= '~' {
+		return input.Key(name[0]), true
+	}
+	key, ok := namedKeys[strings.ToLower(name)]
+	return key, ok
+}
+
+// KeyChord 组合键序列中的一步，如 ctrl+shift+p
+type KeyChord struct {
+	Ctrl  bool
+	Shift bool
+	Alt   bool
+	Meta  bool
+	Mod   bool   // 平台主修饰键，按下时解析为 Meta（macOS）或 Ctrl
+	Key   string // 按键名称
+
+	code input.Key
+}
+
+// String 返回组合键的文本形式
+func (c KeyChord) String() string {
+	var parts []string
+	if c.Mod {
+		parts = append(parts, "Mod")
+	}
+	if c.Ctrl {
+		parts = append(parts, "Ctrl")
+	}
+	if c.Alt {
+		parts = append(parts, "Alt")
+	}
+	if c.Shift {
+		parts = append(parts, "Shift")
+	}
+	if c.Meta {
+		parts = append(parts, "Meta")
+	}
+	return strings.Join(append(parts, c.Key), "+")
+}
+
+// resolve 按平台将 Mod 解析为 Meta 或 Ctrl
+func (c KeyChord) resolve(mac bool) KeyChord {
+	if c.Mod {
+		c.Mod = false
+		if mac {
+			c.Meta = true
+		} else {
+			c.Ctrl = true
+		}
+	}
+	return c
+}
+
+// modifierKeys 需要按住的修饰键
+func (c KeyChord) modifierKeys() []input.Key {
+	var keys []input.Key
+	if c.Ctrl {
+		keys = append(keys, input.ControlLeft)
+	}
+	if c.Shift {
+		keys = append(keys, input.ShiftLeft)
+	}
+	if c.Alt {
+		keys = append(keys, input.AltLeft)
+	}
+	if c.Meta {
+		keys = append(keys, input.MetaLeft)
+	}
+	return keys
+}
+
+// isKeySequence 判断按键描述是否为组合键或按键序列（而不是单个按键）
+func isKeySequence(key string) bool {
+	return len(key) > 1 && strings.ContainsAny(key, "+, ")
+}
+
+// ParseKeySequence 解析按键序列，步骤之间用空格、逗号或 then 分隔，每一步为 修饰键+按键
+// 例如 "ctrl+k then p"、"mod+shift+p"、"g g"
+func ParseKeySequence(sequence string) ([]KeyChord, error) {
+	var chords []KeyChord
+	for _, token := range strings.Fields(strings.ReplaceAll(sequence, ",", " ")) {
+		if strings.EqualFold(token, "then") {
+			continue
+		}
+		chord, err := parseKeyChord(token)
+		if err != nil {
+			return nil, err
+		}
+		chords = append(chords, chord)
+	}
+	if len(chords) == 0 {
+		return nil, fmt.Errorf("empty key sequence")
+	}
+	return chords, nil
+}
+
+// parseKeyChord 解析单个组合键，如 ctrl+shift+p、ctrl++
+func parseKeyChord(token string) (KeyChord, error) {
+	var chord KeyChord
+	parts := strings.Split(token, "+")
+	key := parts[len(parts)-1]
+	mods := parts[:len(parts)-1]
+	// 以 + 结尾表示按键本身是 +（如 ctrl++）
+	if key == "" && len(mods) > 0 && mods[len(mods)-1] == "" {
+		key = "+"
+		mods = mods[:len(mods)-1]
+	}
+
+	for _, m := range mods {
+		switch modifierAliases[strings.ToLower(m)] {
+		case "ctrl":
+			chord.Ctrl = true
+		case "shift":
+			chord.Shift = true
+		case "alt":
+			chord.Alt = true
+		case "meta":
+			chord.Meta = true
+		case "mod":
+			chord.Mod = true
+		default:
+			return chord, fmt.Errorf("unknown modifier %q in %q", m, token)
+		}
+	}
+
+	code, ok := lookupKey(key)
+	if !ok {
+		return chord, fmt.Errorf("unknown key %q in %q", key, token)
+	}
+	chord.Key = key
+	chord.code = code
+	return chord, nil
+}
+
+// pressChord 按住修饰键后按下并释放按键，最后逆序释放修饰键
+func pressChord(keyboard *rod.Keyboard, chord KeyChord) error {
+	mods := chord.modifierKeys()
+	pressed := 0
+	defer func() {
+		for i := pressed - 1; i >= 0; i-- {
+			_ = keyboard.Release(mods[i])
+		}
+	}()
+	for _, m := range mods {
+		if err := keyboard.Press(m); err != nil {
+			return err
+		}
+		pressed++
+	}
+	return keyboard.Type(chord.code)
+}
+
+// isMacPlatform 判断页面所在平台是否为 macOS，无法判断时按本机系统
+func isMacPlatform(page *rod.Page) bool {
+	res, err := page.Eval(`() => /Mac|iPhone|iPad|iPod/i.test(
+		(navigator.userAgentData && navigator.userAgentData.platform) || navigator.platform || '')`)
+	if err != nil {
+		return runtime.GOOS == "darwin"
+	}
+	return res.Value.Bool()
+}
+
+// pressKeySequence 依次按下序列中的每个组合键，opts 中的修饰键会叠加到每一步
+func (e *Executor) pressKeySequence(ctx context.Context, page *rod.Page, sequence string, opts *PressKeyOptions) (*OperationResult, error) {
+	chords, err := ParseKeySequence(sequence)
+	if err != nil {
+		return &OperationResult{
+			Success:   false,
+			Error:     fmt.Sprintf("Invalid key sequence: %s", err.Error()),
+			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
+		}, err
+	}
+
+	mac := isMacPlatform(page)
+	steps := make([]string, 0, len(chords))
+	for i, chord := range chords {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return &OperationResult{
+					Success:   false,
+					Error:     fmt.Sprintf("Key sequence interrupted after %d steps: %s", i, ctx.Err().Error()),
+					Timestamp: time.Now(),
+					ErrorCode: errorCode(ctx.Err()),
+				}, ctx.Err()
+			case <-time.After(keySequenceStepDelay):
+			}
+		}
+
+		chord.Ctrl = chord.Ctrl || opts.Ctrl
+		chord.Shift = chord.Shift || opts.Shift
+		chord.Alt = chord.Alt || opts.Alt
+		chord.Meta = chord.Meta || opts.Meta
+		chord.Mod = chord.Mod || opts.Mod
+		chord = chord.resolve(mac)
+
+		if err := pressChord(page.Keyboard, chord); err != nil {
+			return &OperationResult{
+				Success:   false,
+				Error:     fmt.Sprintf("Failed to press %s: %s", chord, err.Error()),
+				Timestamp: time.Now(),
+				ErrorCode: errorCode(err),
+			}, err
+		}
+		steps = append(steps, chord.String())
+	}
+
+	return &OperationResult{
+		Success:   true,
+		Message:   fmt.Sprintf("Successfully pressed keys: %s", strings.Join(steps, " then ")),
+		Timestamp: time.Now(),
+		Data: map[string]interface{}{
+			"steps": steps,
+			"mac":   mac,
+		},
+	}, nil
+}
+
+// imeSegment 输入法输入的文本片段
+type imeSegment struct {
+	Text    string
+	Compose bool // 是否通过输入法组合输入（非 ASCII 字符）
+}
+
+// splitIMESegments 将文本拆分为 ASCII 片段和需要组合输入的非 ASCII 片段
+func splitIMESegments(text string) []imeSegment {
+	var segments []imeSegment
+	var current []rune
+	compose := false
+	for _, r := range text {
+		c := r > 0x7f
+		if len(current) > 0 && c != compose {
+			segments = append(segments, imeSegment{Text: string(current), Compose: compose})
+			current = current[:0]
+		}
+		compose = c
+		current = append(current, r)
+	}
+	if len(current) > 0 {
+		segments = append(segments, imeSegment{Text: string(current), Compose: compose})
+	}
+	return segments
+}
+
+// typeWithIME 模拟输入法输入：非 ASCII 片段逐字更新组合文本后提交，
+// 页面会依次收到 compositionstart、compositionupdate、compositionend 事件
+func typeWithIME(ctx context.Context, page *rod.Page, text string, delay time.Duration) error {
+	for _, segment := range splitIMESegments(text) {
+		if segment.Compose {
+			runes := []rune(segment.Text)
+			for i := 1; i <= len(runes); i++ {
+				pos := len(utf16.Encode(runes[:i]))
+				err := proto.InputImeSetComposition{
+					Text:           string(runes[:i]),
+					SelectionStart: pos,
+					SelectionEnd:   pos,
+				}.Call(page)
+				if err != nil {
+					return fmt.Errorf("failed to update composition: %w", err)
+				}
+				if delay > 0 {
+					select {
+					case <-ctx.Done():
+						return ctx.Err()
+					case <-time.After(delay):
+					}
+				}
+			}
+		}
+		// 提交组合文本（ASCII 片段直接插入）
+		if err := (proto.InputInsertText{Text: segment.Text}).Call(page); err != nil {
+			return fmt.Errorf("failed to insert text: %w", err)
+		}
+	}
+	return nil
+}
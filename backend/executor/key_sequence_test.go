@@ -0,0 +1,82 @@
+package executor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseKeySequence(t *testing.T) {
+	tests := []struct {
+		sequence string
+		want     []string
+		wantErr  bool
+	}{
+		{sequence: "ctrl+k then p", want: []string{"Ctrl+k", "p"}},
+		{sequence: "Mod+Shift+P", want: []string{"Mod+Shift+P"}},
+		{sequence: "cmd+s", want: []string{"Meta+s"}},
+		{sequence: "g g", want: []string{"g", "g"}},
+		{sequence: "ctrl+a, Delete", want: []string{"Ctrl+a", "Delete"}},
+		{sequence: "ctrl++", want: []string{"Ctrl++"}},
+		{sequence: "alt+F4", want: []string{"Alt+F4"}},
+		{sequence: "then", wantErr: true},
+		{sequence: "hyper+k", wantErr: true},
+		{sequence: "ctrl+foo", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		chords, err := ParseKeySequence(tt.sequence)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseKeySequence(%q) expected error", tt.sequence)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseKeySequence(%q) unexpected error: %v", tt.sequence, err)
+			continue
+		}
+		got := make([]string, len(chords))
+		for i, c := range chords {
+			got[i] = c.String()
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseKeySequence(%q) = %v, want %v", tt.sequence, got, tt.want)
+		}
+	}
+}
+
+func TestKeyChordResolve(t *testing.T) {
+	chord := KeyChord{Mod: true, Key: "k"}
+	if got := chord.resolve(true).String(); got != "Meta+k" {
+		t.Errorf("mac: got %s", got)
+	}
+	if got := chord.resolve(false).String(); got != "Ctrl+k" {
+		t.Errorf("other: got %s", got)
+	}
+}
+
+func TestIsKeySequence(t *testing.T) {
+	for key, want := range map[string]bool{
+		"Enter":         false,
+		"+":             false,
+		" ":             false,
+		"ctrl+s":        true,
+		"ctrl+k then p": true,
+	} {
+		if got := isKeySequence(key); got != want {
+			t.Errorf("isKeySequence(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestSplitIMESegments(t *testing.T) {
+	got := splitIMESegments("hello 世界, ok")
+	want := []imeSegment{
+		{Text: "hello ", Compose: false},
+		{Text: "世界", Compose: true},
+		{Text: ", ok", Compose: false},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitIMESegments = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,454 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"golang.org/x/net/html"
+)
+
+// 抓取页面内容时读取的最大字节数（用于解析下一层链接）
+const maxLinkCheckPageSize = 5 << 20
+
+// stateChangingLinkPattern 请求后可能退出登录或修改账户状态的链接（路径或查询参数中的关键词），不检查
+// 检查请求携带浏览器的 Cookie，对这类在 GET 请求上执行操作的链接发请求会退出当前会话或退订邮件
+var stateChangingLinkPattern = regexp.MustCompile(`(?i)(^|[/_.?&=-])(log[-_]?out|log[-_]?off|sign[-_]?out|unsubscribe|opt[-_]?out|deactivate|delete|remove)([/_.?&=-]|$)`)
+
+// LinkCheckOptions 链接检查选项
+type LinkCheckOptions struct {
+	Selector    string        // 只检查该 CSS 选择器范围内的链接，为空时检查整个页面
+	SameOrigin  bool          // 只检查与当前页面同源的链接
+	Depth       int           // 继续抓取站点栏目内页面的层数，0 表示只检查当前页面上的链接
+	Section     string        // 栏目路径前缀（Depth > 0 时生效），默认为当前页面所在目录
+	Concurrency int           // 并发请求数，默认 5，最大 20
+	Timeout     time.Duration // 单个请求超时，默认 15 秒
+	MaxLinks    int           // 最多检查的链接数，默认 200
+	OnlyBroken  bool          // 结果中只返回失效的链接
+}
+
+// LinkStatus 单个链接的检查结果
+type LinkStatus struct {
+	URL        string   `json:"url"`
+	Status     int      `json:"status,omitempty"`     // HTTP 状态码（跟随重定向后）
+	Broken     bool     `json:"broken"`               // 状态码 >= 400 或请求失败
+	Error      string   `json:"error,omitempty"`      // 请求失败原因
+	Redirected string   `json:"redirected,omitempty"` // 发生重定向时的最终地址
+	Method     string   `json:"method"`               // 实际使用的请求方法（HEAD 不被支持时改用 GET）
+	DurationMs int64    `json:"duration_ms"`          // 请求耗时
+	Skipped    bool     `json:"skipped,omitempty"`    // 可能退出登录或修改账户状态（如 logout、unsubscribe），未发送请求
+	FoundOn    []string `json:"found_on,omitempty"`   // 出现该链接的页面
+	Text       string   `json:"text,omitempty"`       // 链接文字（当前页面上的链接）
+}
+
+// linkChecker 在服务端发起请求检查链接，请求携带浏览器中对应站点的 Cookie 和 User-Agent，
+// 因此可以检查需要登录的页面
+type linkChecker struct {
+	client    *http.Client
+	userAgent string
+	cookies   func(ctx context.Context, rawURL string) string // 获取 URL 对应的 Cookie 请求头
+	allow     func(ctx context.Context, rawURL string) error  // URL 访问策略检查
+	opts      *LinkCheckOptions
+	origin    string // 当前页面的源（scheme://host）
+	section   string // 栏目路径前缀
+
+	mu    sync.Mutex
+	links map[string]*LinkStatus
+	order []string
+}
+
+// pageLink 页面中的链接
+type pageLink struct {
+	URL  string `json:"url"`
+	Text string `json:"text"`
+}
+
+// collectLinksScript 收集页面（或指定区域）中的链接
+const collectLinksScript = `(selector) => {
+	const root = selector ? document.querySelector(selector) : document;
+	if (!root) throw new Error('element not found: ' + selector);
+	return Array.from(root.querySelectorAll('a[href], area[href]')).map(a => ({
+		url: a.href,
+		text: (a.innerText || a.getAttribute('aria-label') || a.title || '').trim().slice(0, 100),
+	}));
+}`
+
+// CheckLinks 检查当前页面（或站点栏目）中的链接，报告每个链接的 HTTP 状态
+func (e *Executor) CheckLinks(ctx context.Context, opts *LinkCheckOptions) (*OperationResult, error) {
+	page := e.activePage(ctx)
+	if page == nil {
+		return nil, fmt.Errorf("no active page")
+	}
+	if opts == nil {
+		opts = &LinkCheckOptions{}
+	}
+	normalizeLinkCheckOptions(opts)
+
+	info, err := page.Info()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get page info: %w", err)
+	}
+	pageURL, err := url.Parse(info.URL)
+	if err != nil || (pageURL.Scheme != "http" && pageURL.Scheme != "https") {
+		return nil, fmt.Errorf("link check requires an http(s) page, current page is %s", info.URL)
+	}
+
+	res, err := page.Context(ctx).Eval(collectLinksScript, opts.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect links: %w", err)
+	}
+	var found []pageLink
+	if err := res.Value.Unmarshal(&found); err != nil {
+		return nil, fmt.Errorf("failed to parse links: %w", err)
+	}
+
+	userAgent := ""
+	if ua, err := page.Eval(`() => navigator.userAgent`); err == nil {
+		userAgent = ua.Value.Str()
+	}
+
+	checker := &linkChecker{
+		client:    e.Browser.NetworkGuard().HTTPClient(opts.Timeout),
+		userAgent: userAgent,
+		cookies: func(ctx context.Context, rawURL string) string {
+			return browserCookieHeader(page, rawURL)
+		},
+		allow: func(ctx context.Context, rawURL string) error {
+			return e.Browser.CheckURLPolicy(ctx, "", rawURL)
+		},
+		opts:    opts,
+		origin:  pageURL.Scheme + "://" + pageURL.Host,
+		section: linkCheckSection(pageURL, opts.Section),
+	}
+	results := checker.run(ctx, info.URL, found)
+
+	broken := 0
+	statuses := make(map[string]int)
+	var reported []*LinkStatus
+	for _, l := range results {
+		if l.Broken {
+			broken++
+		}
+		if l.Skipped {
+			statuses["skipped"]++
+		} else if l.Status > 0 {
+			statuses[fmt.Sprintf("%d", l.Status)]++
+		} else {
+			statuses["error"]++
+		}
+		if !opts.OnlyBroken || l.Broken {
+			reported = append(reported, l)
+		}
+	}
+	// 失效的链接排在前面
+	sort.SliceStable(reported, func(i, j int) bool { return reported[i].Broken && !reported[j].Broken })
+
+	return &OperationResult{
+		Success: true,
+		Message: fmt.Sprintf("Checked %d links on %s, %d broken", len(results), info.URL, broken),
+		Data: map[string]interface{}{
+			"page":     info.URL,
+			"checked":  len(results),
+			"broken":   broken,
+			"statuses": statuses,
+			"links":    reported,
+		},
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// normalizeLinkCheckOptions 填充默认值并限制取值范围
+func normalizeLinkCheckOptions(opts *LinkCheckOptions) {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 5
+	}
+	if opts.Concurrency > 20 {
+		opts.Concurrency = 20
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 15 * time.Second
+	}
+	if opts.MaxLinks <= 0 {
+		opts.MaxLinks = 200
+	}
+	if opts.Depth < 0 {
+		opts.Depth = 0
+	}
+}
+
+// linkCheckSection 计算栏目路径前缀，未指定时使用当前页面所在目录
+func linkCheckSection(pageURL *url.URL, section string) string {
+	if section != "" {
+		if !strings.HasPrefix(section, "/") {
+			section = "/" + section
+		}
+		return section
+	}
+	dir := pageURL.Path
+	if dir == "" {
+		return "/"
+	}
+	if !strings.HasSuffix(dir, "/") {
+		dir = path.Dir(dir)
+	}
+	if !strings.HasSuffix(dir, "/") {
+		dir += "/"
+	}
+	return dir
+}
+
+// browserCookieHeader 从浏览器中读取 URL 对应的 Cookie 并拼接为请求头
+func browserCookieHeader(page *rod.Page, rawURL string) string {
+	res, err := proto.NetworkGetCookies{Urls: []string{rawURL}}.Call(page)
+	if err != nil {
+		return ""
+	}
+	parts := make([]string, 0, len(res.Cookies))
+	for _, c := range res.Cookies {
+		parts = append(parts, c.Name+"="+c.Value)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// run 按层检查链接：先检查当前页面上的链接，Depth > 0 时再解析栏目内页面中的链接继续检查
+func (c *linkChecker) run(ctx context.Context, pageURL string, found []pageLink) []*LinkStatus {
+	c.links = make(map[string]*LinkStatus)
+	c.order = nil
+
+	var level []*LinkStatus
+	for _, l := range found {
+		if s := c.add(l.URL, pageURL); s != nil {
+			if s.Text == "" {
+				s.Text = l.Text
+			}
+			level = append(level, s)
+		}
+	}
+
+	for depth := 0; len(level) > 0 && ctx.Err() == nil; depth++ {
+		var next []*LinkStatus
+		var nextMu sync.Mutex
+		sem := make(chan struct{}, c.opts.Concurrency)
+		var wg sync.WaitGroup
+		for _, l := range level {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(l *LinkStatus) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				children := c.check(ctx, l, depth < c.opts.Depth && c.inSection(l.URL))
+				for _, child := range children {
+					if s := c.add(child, l.URL); s != nil {
+						nextMu.Lock()
+						next = append(next, s)
+						nextMu.Unlock()
+					}
+				}
+			}(l)
+		}
+		wg.Wait()
+		level = next
+	}
+
+	results := make([]*LinkStatus, 0, len(c.order))
+	for _, u := range c.order {
+		results = append(results, c.links[u])
+	}
+	return results
+}
+
+// add 记录链接，返回新加入的链接（已记录、不需要检查或超过数量限制时返回 nil）
+func (c *linkChecker) add(rawURL, foundOn string) *LinkStatus {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil
+	}
+	u.Fragment = ""
+	normalized := u.String()
+	if c.opts.SameOrigin && u.Scheme+"://"+u.Host != c.origin {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if existing, ok := c.links[normalized]; ok {
+		if len(existing.FoundOn) < 10 && !containsString(existing.FoundOn, foundOn) {
+			existing.FoundOn = append(existing.FoundOn, foundOn)
+		}
+		return nil
+	}
+	if len(c.order) >= c.opts.MaxLinks {
+		return nil
+	}
+	s := &LinkStatus{URL: normalized, FoundOn: []string{foundOn}}
+	c.links[normalized] = s
+	c.order = append(c.order, normalized)
+	return s
+}
+
+// inSection 判断链接是否属于需要继续抓取的栏目（与当前页面同源且路径在栏目前缀下）
+func (c *linkChecker) inSection(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return u.Scheme+"://"+u.Host == c.origin && strings.HasPrefix(u.Path, c.section)
+}
+
+// check 请求链接并记录状态；crawl 为 true 时使用 GET 并返回页面中的链接
+// 优先使用 HEAD，服务器不支持 HEAD（返回 405/501 等）时改用 GET；可能退出登录或修改账户状态的链接不请求
+func (c *linkChecker) check(ctx context.Context, l *LinkStatus, crawl bool) []string {
+	if isStateChangingLink(l.URL) {
+		l.Skipped = true
+		return nil
+	}
+	if err := c.allow(ctx, l.URL); err != nil {
+		l.Broken = true
+		l.Error = err.Error()
+		return nil
+	}
+
+	start := time.Now()
+	method := http.MethodHead
+	if crawl {
+		method = http.MethodGet
+	}
+	resp, err := c.do(ctx, method, l.URL)
+	if err == nil && method == http.MethodHead && headUnsupported(resp.StatusCode) {
+		resp.Body.Close()
+		method = http.MethodGet
+		resp, err = c.do(ctx, method, l.URL)
+	}
+	l.Method = method
+	l.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		l.Broken = true
+		l.Error = err.Error()
+		return nil
+	}
+	defer resp.Body.Close()
+
+	l.Status = resp.StatusCode
+	l.Broken = resp.StatusCode >= 400
+	if final := resp.Request.URL.String(); final != l.URL {
+		l.Redirected = final
+	}
+
+	if !crawl || l.Broken || !strings.Contains(resp.Header.Get("Content-Type"), "html") {
+		return nil
+	}
+	// 重定向到栏目外的页面不再继续抓取
+	if l.Redirected != "" && !c.inSection(l.Redirected) {
+		return nil
+	}
+	return extractLinks(io.LimitReader(resp.Body, maxLinkCheckPageSize), resp.Request.URL)
+}
+
+// do 发送请求，携带浏览器的 Cookie 和 User-Agent
+func (c *linkChecker) do(ctx context.Context, method, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if c.cookies != nil {
+		if cookie := c.cookies(ctx, rawURL); cookie != "" {
+			req.Header.Set("Cookie", cookie)
+		}
+	}
+	return c.client.Do(req)
+}
+
+// isStateChangingLink 链接的路径或查询参数是否像退出登录、退订等会修改状态的操作
+func isStateChangingLink(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return stateChangingLinkPattern.MatchString(u.EscapedPath()) || stateChangingLinkPattern.MatchString("?"+u.RawQuery)
+}
+
+// headUnsupported 判断 HEAD 请求的状态码是否表示服务器不支持 HEAD（需要用 GET 重试）
+func headUnsupported(status int) bool {
+	switch status {
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented, http.StatusForbidden, http.StatusNotFound, http.StatusBadRequest:
+		return true
+	}
+	return false
+}
+
+// extractLinks 解析 HTML 中的链接，相对地址基于 base 解析
+func extractLinks(r io.Reader, base *url.URL) []string {
+	var links []string
+	tokenizer := html.NewTokenizer(r)
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return links
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := tokenizer.TagName()
+			tag := string(name)
+			if !hasAttr || (tag != "a" && tag != "area" && tag != "base") {
+				continue
+			}
+			for {
+				key, val, more := tokenizer.TagAttr()
+				if string(key) == "href" {
+					if ref, err := base.Parse(strings.TrimSpace(string(val))); err == nil {
+						if tag == "base" {
+							base = ref
+						} else {
+							links = append(links, ref.String())
+						}
+					}
+				}
+				if !more {
+					break
+				}
+			}
+		}
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseLinkCheckArguments 解析 browser_check_links 工具参数
+func ParseLinkCheckArguments(args map[string]interface{}) *LinkCheckOptions {
+	opts := &LinkCheckOptions{}
+	opts.Selector, _ = args["selector"].(string)
+	opts.SameOrigin, _ = args["same_origin"].(bool)
+	opts.Section, _ = args["section"].(string)
+	opts.OnlyBroken, _ = args["only_broken"].(bool)
+	if v, ok := args["depth"].(float64); ok {
+		opts.Depth = int(v)
+	}
+	if v, ok := args["concurrency"].(float64); ok {
+		opts.Concurrency = int(v)
+	}
+	if v, ok := args["timeout"].(float64); ok {
+		opts.Timeout = time.Duration(v) * time.Second
+	}
+	if v, ok := args["max_links"].(float64); ok {
+		opts.MaxLinks = int(v)
+	}
+	return opts
+}
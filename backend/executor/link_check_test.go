@@ -0,0 +1,141 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestLinkCheckerRun(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/docs/{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<a href="child">child</a><a href="/outside">outside</a>`)
+	})
+	mux.HandleFunc("/docs/child", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<a href="/docs/missing">missing</a>`)
+	})
+	mux.HandleFunc("/no-head", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if r.Header.Get("Cookie") != "session=abc" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	})
+	mux.HandleFunc("/outside", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<a href="/never-crawled">x</a>`)
+	})
+	mux.HandleFunc("/", http.NotFound)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	pageURL, _ := url.Parse(server.URL + "/docs/index")
+	opts := &LinkCheckOptions{Depth: 1}
+	normalizeLinkCheckOptions(opts)
+	checker := &linkChecker{
+		client:  &http.Client{Timeout: 5 * time.Second},
+		cookies: func(ctx context.Context, rawURL string) string { return "session=abc" },
+		allow:   func(ctx context.Context, rawURL string) error { return nil },
+		opts:    opts,
+		origin:  server.URL,
+		section: linkCheckSection(pageURL, ""),
+	}
+
+	results := checker.run(context.Background(), pageURL.String(), []pageLink{
+		{URL: server.URL + "/docs/"},
+		{URL: server.URL + "/docs/#top"},
+		{URL: server.URL + "/no-head"},
+		{URL: "mailto:someone@example.com"},
+	})
+
+	byURL := make(map[string]*LinkStatus)
+	for _, l := range results {
+		byURL[l.URL] = l
+	}
+	expect := map[string]int{
+		server.URL + "/docs/":      200,
+		server.URL + "/no-head":    200,
+		server.URL + "/docs/child": 200,
+		server.URL + "/outside":    200,
+	}
+	if len(byURL) != len(expect) {
+		t.Fatalf("checked %d links, want %d: %v", len(byURL), len(expect), byURL)
+	}
+	for u, status := range expect {
+		l, ok := byURL[u]
+		if !ok {
+			t.Errorf("%s was not checked", u)
+			continue
+		}
+		if l.Status != status || l.Broken {
+			t.Errorf("%s: status %d broken %v, want %d", u, l.Status, l.Broken, status)
+		}
+	}
+	if byURL[server.URL+"/no-head"].Method != http.MethodGet {
+		t.Error("expected GET fallback when HEAD is not allowed")
+	}
+	// depth 为 1：第二层页面中的链接不再检查
+	if _, ok := byURL[server.URL+"/docs/missing"]; ok {
+		t.Error("links beyond the crawl depth should not be checked")
+	}
+
+	opts.Depth = 2
+	results = checker.run(context.Background(), pageURL.String(), []pageLink{{URL: server.URL + "/docs/"}})
+	var missing *LinkStatus
+	for _, l := range results {
+		if l.URL == server.URL+"/docs/missing" {
+			missing = l
+		}
+		if l.URL == server.URL+"/never-crawled" {
+			t.Error("pages outside the section should not be crawled")
+		}
+	}
+	if missing == nil || !missing.Broken || missing.Status != http.StatusNotFound {
+		t.Errorf("expected /docs/missing to be reported as broken, got %+v", missing)
+	}
+}
+
+func TestLinkCheckSection(t *testing.T) {
+	tests := []struct {
+		page, section, want string
+	}{
+		{"https://a.com/docs/intro", "", "/docs/"},
+		{"https://a.com/docs/", "", "/docs/"},
+		{"https://a.com", "", "/"},
+		{"https://a.com/docs/intro", "blog", "/blog"},
+	}
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.page)
+		if got := linkCheckSection(u, tt.section); got != tt.want {
+			t.Errorf("linkCheckSection(%s, %q) = %q, want %q", tt.page, tt.section, got, tt.want)
+		}
+	}
+}
+
+func TestIsStateChangingLink(t *testing.T) {
+	for rawURL, want := range map[string]bool{
+		"https://example.com/logout":                       true,
+		"https://example.com/account/sign-out?next=/":      true,
+		"https://example.com/user/logOff":                  true,
+		"https://example.com/newsletter/unsubscribe/abc":   true,
+		"https://example.com/index.php?action=logout":      true,
+		"https://example.com/items/42/delete":              true,
+		"https://example.com/docs/logging-out-of-your-app": false,
+		"https://example.com/blog/removed-features":        false,
+		"https://example.com/docs/":                        false,
+	} {
+		if got := isStateChangingLink(rawURL); got != want {
+			t.Errorf("%s: got %v, want %v", rawURL, got, want)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/services/browser"
+	"github.com/go-rod/rod"
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+)
+
+// mcpErrorSnapshotLimit MCP 错误中附带的页面快照最大字符数，避免一次失败占满 Agent 的上下文
+const mcpErrorSnapshotLimit = 4000
+
+// mcpErrorPageTimeout 出错后读取页面地址和快照的超时时间
+const mcpErrorPageTimeout = 5 * time.Second
+
+// MCPToolError MCP 工具失败时返回的结构化错误
+// Agent 根据分类码、出错时的页面和建议的下一步操作一次恢复，不需要反复试探
+type MCPToolError struct {
+	Tool     string           `json:"tool"`
+	Error    string           `json:"error"`
+	Code     models.ErrorCode `json:"code,omitempty"`
+	URL      string           `json:"url,omitempty"`      // 出错时页面的地址
+	Snapshot string           `json:"snapshot,omitempty"` // 出错时页面的可访问性快照（超出长度时截断）
+	Hints    []string         `json:"hints,omitempty"`    // 建议的下一步操作
+}
+
+// NewMCPToolError 根据工具返回的错误创建结构化错误
+func NewMCPToolError(tool string, err error) *MCPToolError {
+	return &MCPToolError{
+		Tool:  tool,
+		Error: err.Error(),
+		Code:  browser.ClassifyError(err),
+	}
+}
+
+// needsPage 是否需要附带出错时的页面信息，会话已失效时页面无法读取
+func (e *MCPToolError) needsPage() bool {
+	return e.Code != models.ErrorCodeSessionLost
+}
+
+// setSnapshot 附带出错时的页面快照，超出长度时截断
+func (e *MCPToolError) setSnapshot(snapshot *AccessibilitySnapshot) {
+	if snapshot == nil {
+		return
+	}
+	text := []rune(snapshot.SerializeToSimpleText())
+	if len(text) > mcpErrorSnapshotLimit {
+		text = append(text[:mcpErrorSnapshotLimit], []rune("\n... (truncated, call browser_snapshot for the full page)")...)
+	}
+	e.Snapshot = string(text)
+}
+
+// Result 转换为 MCP 工具结果，未设置建议时按失败原因生成
+// structuredContent 为结构化错误，文本内容是同样的 JSON，只读取文本的客户端也能解析
+func (e *MCPToolError) Result() *mcpgo.CallToolResult {
+	if e.Hints == nil {
+		e.Hints = mcpRecoveryHints(e.Code, e.Snapshot != "")
+	}
+	data, _ := json.Marshal(e)
+	result := mcpgo.NewToolResultStructured(e, string(data))
+	result.IsError = true
+	return result
+}
+
+// mcpRecoveryHints 按失败原因给出建议的下一步操作
+func mcpRecoveryHints(code models.ErrorCode, hasSnapshot bool) []string {
+	inspect := "Call browser_snapshot to see the current page"
+	if hasSnapshot {
+		inspect = "Read the snapshot in this error to see the current page"
+	}
+
+	switch code {
+	case models.ErrorCodeElementNotFound:
+		hints := []string{"Call browser_snapshot, then retry with the element's RefID (e.g. @e3) instead of a selector or label"}
+		if hasSnapshot {
+			hints[0] = "Find the element in the snapshot in this error and retry with its RefID (e.g. @e3) instead of a selector or label"
+		}
+		return append(hints,
+			"If the element is not there yet, call browser_wait_for or browser_scroll to load it, then take a new snapshot")
+	case models.ErrorCodeTimeout:
+		return []string{
+			inspect + " and check whether it is still loading or shows an error",
+			"Retry with a larger timeout, or call browser_wait_for with a condition the page actually reaches",
+		}
+	case models.ErrorCodeSessionLost:
+		return []string{
+			"The page was closed or crashed. Call browser_tabs with action \"list\" and switch to an open tab, or call browser_navigate to open the page again",
+		}
+	case models.ErrorCodeNavigationBlocked:
+		return []string{
+			"The URL is blocked by the access policy or the browser, retrying will fail again. Use another URL or ask the user to allow it",
+		}
+	case models.ErrorCodeCaptchaDetected:
+		return []string{
+			"The page requires a CAPTCHA. Ask the user to solve it in the browser window, then continue. Do not try to solve it",
+		}
+	case models.ErrorCodeLoginRequired:
+		return []string{
+			"The site logged the browser out and no login script could sign it back in. Ask the user to log in in the browser window, then continue. Do not guess credentials",
+		}
+	case models.ErrorCodeRequestMissing:
+		return []string{
+			"A step ran but did not send the request it sent when recorded, so it probably had no effect. " + inspect + " for a validation message or a changed form before retrying",
+		}
+	default:
+		return []string{inspect + " before retrying"}
+	}
+}
+
+// toolError 返回工具失败的结构化结果，附带当前页面的地址和快照
+// 快照强制重新获取，其中的 RefID 可以直接用于重试
+func (r *MCPToolRegistry) toolError(ctx context.Context, request mcpgo.CallToolRequest, err error) *mcpgo.CallToolResult {
+	detail := NewMCPToolError(request.Params.Name, err)
+	if !detail.needsPage() {
+		return detail.Result()
+	}
+	page := r.executor.activePage(ctx)
+	if page == nil {
+		return detail.Result()
+	}
+
+	pageCtx, cancel := context.WithTimeout(ctx, mcpErrorPageTimeout)
+	defer cancel()
+	if info, infoErr := page.Context(pageCtx).Info(); infoErr == nil {
+		detail.URL = info.URL
+	}
+	if snapshot, snapErr := r.executor.accessibilitySnapshot(pageCtx, true); snapErr == nil {
+		detail.setSnapshot(snapshot)
+	}
+	return detail.Result()
+}
+
+// ScriptToolError 返回脚本命令失败的结构化结果，附带回放页面的地址和快照，page 可为 nil
+// 回放页面不是 Executor 的活动页面，快照中没有 RefID
+func ScriptToolError(ctx context.Context, tool string, err error, page *rod.Page) *mcpgo.CallToolResult {
+	detail := NewMCPToolError(tool, err)
+	if detail.Code == models.ErrorCodeElementNotFound {
+		detail.Hints = []string{
+			"The script's selectors no longer match the page. Compare them with the page; the script needs to be edited or re-recorded",
+		}
+	}
+	if page == nil || !detail.needsPage() {
+		return detail.Result()
+	}
+
+	pageCtx, cancel := context.WithTimeout(ctx, mcpErrorPageTimeout)
+	defer cancel()
+	page = page.Context(pageCtx)
+	if info, infoErr := page.Info(); infoErr == nil {
+		detail.URL = info.URL
+	}
+	if snapshot, snapErr := GetAccessibilitySnapshot(pageCtx, page); snapErr == nil {
+		detail.setSnapshot(snapshot)
+	}
+	return detail.Result()
+}
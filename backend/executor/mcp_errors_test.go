@@ -0,0 +1,54 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/go-rod/rod/lib/proto"
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestMCPToolErrorResult(t *testing.T) {
+	detail := NewMCPToolError("browser_click", fmt.Errorf("click failed: %w", elementNotFound("element not found: %s", "Buy")))
+	detail.URL = "https://shop.example.com/cart"
+
+	elements := map[string]*AccessibilityNode{}
+	for i := 0; i < 200; i++ {
+		id := fmt.Sprint(i)
+		elements[id] = &AccessibilityNode{ID: id, RefID: "e" + id, BackendNodeID: proto.DOMBackendNodeID(i + 1), Role: "link", Label: strings.Repeat("x", 40), Metadata: map[string]interface{}{}}
+	}
+	detail.setSnapshot(&AccessibilitySnapshot{Elements: elements})
+
+	result := detail.Result()
+	if !result.IsError {
+		t.Fatalf("result should be an error")
+	}
+	text := result.Content[0].(mcpgo.TextContent).Text
+	var got MCPToolError
+	if err := json.Unmarshal([]byte(text), &got); err != nil {
+		t.Fatalf("text content is not JSON: %v\n%s", err, text)
+	}
+	if got.Code != models.ErrorCodeElementNotFound || got.Tool != "browser_click" || got.URL != detail.URL {
+		t.Errorf("unexpected error detail: %+v", got)
+	}
+	if !strings.HasSuffix(got.Snapshot, "call browser_snapshot for the full page)") || len([]rune(got.Snapshot)) > mcpErrorSnapshotLimit+100 {
+		t.Errorf("snapshot not truncated: %d chars", len([]rune(got.Snapshot)))
+	}
+	if len(got.Hints) == 0 || !strings.Contains(got.Hints[0], "RefID") {
+		t.Errorf("missing RefID hint: %v", got.Hints)
+	}
+}
+
+func TestMCPRecoveryHints(t *testing.T) {
+	for _, code := range []models.ErrorCode{"", models.ErrorCodeElementNotFound, models.ErrorCodeTimeout, models.ErrorCodeSessionLost, models.ErrorCodeNavigationBlocked, models.ErrorCodeCaptchaDetected} {
+		if hints := mcpRecoveryHints(code, false); len(hints) == 0 {
+			t.Errorf("no hints for %q", code)
+		}
+	}
+	if hints := mcpRecoveryHints(models.ErrorCodeTimeout, false); !strings.Contains(hints[0], "browser_snapshot") {
+		t.Errorf("timeout without snapshot should suggest browser_snapshot: %v", hints)
+	}
+}
@@ -79,9 +79,11 @@ func (r *MCPToolRegistry) RegisterAllTools() error {
 		return fmt.Errorf("failed to register screenshot tool: %w", err)
 	}
 
-	// 注册执行脚本工具
-	if err := r.registerEvaluateTool(); err != nil {
-		return fmt.Errorf("failed to register evaluate tool: %w", err)
+	// 注册执行脚本工具（安全策略禁用时不注册）
+	if !r.executor.Browser.GetSecurityConfig().IsEvaluateDisabled() {
+		if err := r.registerEvaluateTool(); err != nil {
+			return fmt.Errorf("failed to register evaluate tool: %w", err)
+		}
 	}
 
 	// 注册按键工具
@@ -99,6 +101,21 @@ func (r *MCPToolRegistry) RegisterAllTools() error {
 		return fmt.Errorf("failed to register drag tool: %w", err)
 	}
 
+	// 注册自定义下拉框选择工具
+	if err := r.registerChooseOptionTool(); err != nil {
+		return fmt.Errorf("failed to register choose option tool: %w", err)
+	}
+
+	// 注册日期填写工具
+	if err := r.registerFillDateTool(); err != nil {
+		return fmt.Errorf("failed to register fill date tool: %w", err)
+	}
+
+	// 注册悬停后点击工具
+	if err := r.registerHoverThenClickTool(); err != nil {
+		return fmt.Errorf("failed to register hover then click tool: %w", err)
+	}
+
 	// 注册关闭页面工具
 	if err := r.registerClosePageTool(); err != nil {
 		return fmt.Errorf("failed to register close page tool: %w", err)
@@ -124,6 +141,64 @@ func (r *MCPToolRegistry) RegisterAllTools() error {
 		return fmt.Errorf("failed to register network requests tool: %w", err)
 	}
 
+	// 注册响应捕获工具
+	if err := r.registerCaptureResponseTool(); err != nil {
+		return fmt.Errorf("failed to register capture response tool: %w", err)
+	}
+
+	// 注册 WebSocket 观察工具
+	if err := r.registerWebSocketTool(); err != nil {
+		return fmt.Errorf("failed to register websocket tool: %w", err)
+	}
+
+	// 注册 XHR 重放工具
+	if err := r.registerXHRReplayTool(); err != nil {
+		return fmt.Errorf("failed to register xhr replay tool: %w", err)
+	}
+
+	// 注册采集工具
+	if err := r.registerCollectTool(); err != nil {
+		return fmt.Errorf("failed to register collect tool: %w", err)
+	}
+
+	// 注册元素检查工具
+	if err := r.registerInspectElementTool(); err != nil {
+		return fmt.Errorf("failed to register inspect element tool: %w", err)
+	}
+
+	// 注册页面审计工具
+	if err := r.registerAuditTool(); err != nil {
+		return fmt.Errorf("failed to register audit tool: %w", err)
+	}
+
+	// 注册可访问性扫描工具
+	if err := r.registerA11yScanTool(); err != nil {
+		return fmt.Errorf("failed to register a11y scan tool: %w", err)
+	}
+
+	// 注册链接检查工具
+	if err := r.registerCheckLinksTool(); err != nil {
+		return fmt.Errorf("failed to register check links tool: %w", err)
+	}
+
+	// 注册页面翻译工具
+	if err := r.registerTranslatePageTool(); err != nil {
+		return fmt.Errorf("failed to register translate page tool: %w", err)
+	}
+
+	// 注册图片文字识别工具
+	if err := r.registerReadImageTextTool(); err != nil {
+		return fmt.Errorf("failed to register read image text tool: %w", err)
+	}
+
+	// 注册坐标点击和拖拽工具
+	if err := r.registerClickAtTool(); err != nil {
+		return fmt.Errorf("failed to register click at tool: %w", err)
+	}
+	if err := r.registerDragBoxTool(); err != nil {
+		return fmt.Errorf("failed to register drag box tool: %w", err)
+	}
+
 	// 注册标签页管理工具
 	if err := r.registerTabsTool(); err != nil {
 		return fmt.Errorf("failed to register tabs tool: %w", err)
@@ -137,6 +212,9 @@ func (r *MCPToolRegistry) RegisterAllTools() error {
 	return nil
 }
 
+// snapshotParamDescription 导航、点击、输入、选择工具的 snapshot 参数说明
+const snapshotParamDescription = "Page snapshot after the action: include (default) returns the updated snapshot with RefIDs; skip returns none and keeps the previous RefIDs; defer returns none and refreshes the snapshot the next time one is needed. Use skip or defer for known sequences of steps to save time on large pages"
+
 // registerNavigateTool 注册导航工具
 func (r *MCPToolRegistry) registerNavigateTool() error {
 	tool := mcpgo.NewTool(
@@ -144,6 +222,7 @@ func (r *MCPToolRegistry) registerNavigateTool() error {
 		mcpgo.WithDescription("Navigate to a URL in the browser"),
 		mcpgo.WithString("url", mcpgo.Required(), mcpgo.Description("The URL to navigate to")),
 		mcpgo.WithString("wait_until", mcpgo.Description("Wait condition: load, domcontentloaded, networkidle (default: load)")),
+		mcpgo.WithString("snapshot", mcpgo.Enum("include", "skip", "defer"), mcpgo.Description(snapshotParamDescription)),
 	)
 
 	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
@@ -157,25 +236,26 @@ func (r *MCPToolRegistry) registerNavigateTool() error {
 		select {
 		case <-ctx.Done():
 			logger.Info(ctx, "[MCP Handler] Context already done: %v", ctx.Err())
-			return mcpgo.NewToolResultError(fmt.Sprintf("context error: %v", ctx.Err())), nil
+			return r.toolError(ctx, request, fmt.Errorf("context error: %w", ctx.Err())), nil
 		default:
 			logger.Info(ctx, "[MCP Handler] Context is active")
 		}
 
 		opts := &NavigateOptions{
 			WaitUntil: "load",
-			Timeout:   60 * time.Second, // 设置默认超时
+			Timeout:   r.executor.timeouts().Navigation(),
 		}
 		if waitUntil, ok := args["wait_until"].(string); ok && waitUntil != "" {
 			opts.WaitUntil = waitUntil
 		}
+		opts.Snapshot = ParseSnapshotArgument(args)
 		logger.Info(ctx, "[MCP Handler] Options: WaitUntil=%s, Timeout=%v", opts.WaitUntil, opts.Timeout)
 
 		logger.Info(ctx, "[MCP Handler] Calling executor.Navigate...")
 		result, err := r.executor.Navigate(ctx, url, opts)
 		if err != nil {
 			logger.Info(ctx, "[MCP Handler] Navigate failed: %v", err)
-			return mcpgo.NewToolResultError(err.Error()), nil
+			return r.toolError(ctx, request, err), nil
 		}
 		logger.Info(ctx, "[MCP Handler] Navigate succeeded")
 
@@ -202,26 +282,31 @@ func (r *MCPToolRegistry) registerClickTool() error {
 		mcpgo.WithDescription("Click an element on the page. Returns success message and updated page snapshot with RefIDs. Can use RefID (@e1), CSS selector, XPath, or element label/text."),
 		mcpgo.WithString("identifier", mcpgo.Required(), mcpgo.Description("Element identifier: RefID (@e1 from snapshot), CSS selector, XPath, label, or text")),
 		mcpgo.WithBoolean("wait_visible", mcpgo.Description("Wait for element to be visible (default: true)")),
+		mcpgo.WithString("tab_id", mcpgo.Description("Act on the tab with this ID (from browser_tabs list) instead of the active tab")),
+		mcpgo.WithString("snapshot", mcpgo.Enum("include", "skip", "defer"), mcpgo.Description(snapshotParamDescription)),
 	)
 
 	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
 		args := request.Params.Arguments.(map[string]interface{})
 		identifier, _ := args["identifier"].(string)
+		tabID, _ := args["tab_id"].(string)
+		ctx = WithTab(ctx, tabID)
 
 		opts := &ClickOptions{
 			WaitVisible: true,
 			WaitEnabled: true,
-			Timeout:     10 * time.Second,
+			Timeout:     r.executor.timeouts().Element(),
 			Button:      "left",
 			ClickCount:  1,
 		}
 		if waitVisible, ok := args["wait_visible"].(bool); ok {
 			opts.WaitVisible = waitVisible
 		}
+		opts.Snapshot = ParseSnapshotArgument(args)
 
 		result, err := r.executor.Click(ctx, identifier, opts)
 		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
+			return r.toolError(ctx, request, err), nil
 		}
 
 		// 构建返回文本，包含消息和可访问性快照
@@ -248,26 +333,35 @@ func (r *MCPToolRegistry) registerTypeTool() error {
 		mcpgo.WithString("identifier", mcpgo.Required(), mcpgo.Description("Element identifier: RefID (@e3 from snapshot), CSS selector, XPath, label, or placeholder")),
 		mcpgo.WithString("text", mcpgo.Required(), mcpgo.Description("Text to type")),
 		mcpgo.WithBoolean("clear", mcpgo.Description("Clear existing text before typing (default: true)")),
+		mcpgo.WithBoolean("ime", mcpgo.Description("Type through IME composition events (compositionstart/update/end), for CJK inputs that listen to composition")),
+		mcpgo.WithString("tab_id", mcpgo.Description("Act on the tab with this ID (from browser_tabs list) instead of the active tab")),
+		mcpgo.WithString("snapshot", mcpgo.Enum("include", "skip", "defer"), mcpgo.Description(snapshotParamDescription)),
 	)
 
 	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
 		args := request.Params.Arguments.(map[string]interface{})
 		identifier, _ := args["identifier"].(string)
 		text, _ := args["text"].(string)
+		tabID, _ := args["tab_id"].(string)
+		ctx = WithTab(ctx, tabID)
 
 		opts := &TypeOptions{
 			Clear:       true,
 			WaitVisible: true,
-			Timeout:     10 * time.Second,
+			Timeout:     r.executor.timeouts().Element(),
 			Delay:       0,
 		}
 		if clear, ok := args["clear"].(bool); ok {
 			opts.Clear = clear
 		}
+		if ime, ok := args["ime"].(bool); ok {
+			opts.IME = ime
+		}
+		opts.Snapshot = ParseSnapshotArgument(args)
 
 		result, err := r.executor.Type(ctx, identifier, text, opts)
 		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
+			return r.toolError(ctx, request, err), nil
 		}
 
 		// 构建返回文本，包含消息和可访问性快照
@@ -293,6 +387,7 @@ func (r *MCPToolRegistry) registerSelectTool() error {
 		mcpgo.WithDescription("Select an option from a dropdown menu. Returns success message and updated page snapshot with RefIDs."),
 		mcpgo.WithString("identifier", mcpgo.Required(), mcpgo.Description("Select element identifier: RefID (@e5 from snapshot), CSS selector, or XPath")),
 		mcpgo.WithString("value", mcpgo.Required(), mcpgo.Description("Option value or text to select")),
+		mcpgo.WithString("snapshot", mcpgo.Enum("include", "skip", "defer"), mcpgo.Description(snapshotParamDescription)),
 	)
 
 	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
@@ -302,12 +397,13 @@ func (r *MCPToolRegistry) registerSelectTool() error {
 
 		opts := &SelectOptions{
 			WaitVisible: true,
-			Timeout:     10 * time.Second,
+			Timeout:     r.executor.timeouts().Element(),
+			Snapshot:    ParseSnapshotArgument(args),
 		}
 
 		result, err := r.executor.Select(ctx, identifier, value, opts)
 		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
+			return r.toolError(ctx, request, err), nil
 		}
 
 		// 构建返回文本，包含消息和可访问性快照
@@ -355,7 +451,7 @@ func (r *MCPToolRegistry) registerExtractTool() error {
 
 		result, err := r.executor.Extract(ctx, opts)
 		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
+			return r.toolError(ctx, request, err), nil
 		}
 
 		// 序列化结果为 JSON
@@ -374,6 +470,9 @@ func (r *MCPToolRegistry) registerAccessibilitySnapshotTool() error {
 		mcpgo.WithDescription("Get the accessibility snapshot of the current page. Returns a tree structure representing the page's accessibility tree, which is cleaner than raw DOM and better for LLMs to understand."),
 		mcpgo.WithBoolean("simple", mcpgo.Description("Return simplified text format suitable for LLMs (default: true)")),
 		mcpgo.WithNumber("max_depth", mcpgo.Description("Maximum depth of the tree (default: unlimited)")),
+		mcpgo.WithBoolean("geometry", mcpgo.Description("Include each element's bounding box (viewport CSS pixels, same space as browser_click_at) and whether it is hidden or offscreen, for layout reasoning like \"the topmost visible card\" (default: false)")),
+		mcpgo.WithString("cursor", mcpgo.Description("Cursor from a previous chunk's \"more elements\" line, to read the next chunk of a large page (simple format only)")),
+		mcpgo.WithNumber("max_bytes", mcpgo.Description(fmt.Sprintf("Maximum size of one chunk in bytes (simple format only, default: %d)", DefaultSnapshotChunkSize))),
 	)
 
 	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
@@ -386,16 +485,34 @@ func (r *MCPToolRegistry) registerAccessibilitySnapshotTool() error {
 
 		snapshot, err := r.executor.GetAccessibilitySnapshot(ctx)
 		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
+			return r.toolError(ctx, request, err), nil
+		}
+
+		// 几何信息需显式开启
+		var geometry *SnapshotGeometry
+		if withGeometry, _ := args["geometry"].(bool); withGeometry {
+			geometry, err = r.executor.GetSnapshotGeometry(ctx, snapshot)
+			if err != nil {
+				return r.toolError(ctx, request, err), nil
+			}
 		}
 
 		if simple {
-			// 返回简化的文本格式
-			text := snapshot.SerializeToSimpleText()
-			return mcpgo.NewToolResultText(text), nil
+			// 返回简化的文本格式，大页面分页返回，避免数 MB 的结果压垮传输
+			cursor, _ := args["cursor"].(string)
+			maxBytes, _ := args["max_bytes"].(float64)
+			chunk, err := snapshot.SerializeChunk(geometry, cursor, int(maxBytes))
+			if err != nil {
+				return mcpgo.NewToolResultError(err.Error()), nil
+			}
+			return mcpgo.NewToolResultText(chunk.Text), nil
 		}
 
 		// 返回完整的 JSON 格式
+		if geometry != nil {
+			data, _ := json.Marshal(map[string]interface{}{"snapshot": snapshot, "geometry": geometry})
+			return mcpgo.NewToolResultText(string(data)), nil
+		}
 		data, _ := json.Marshal(snapshot)
 		return mcpgo.NewToolResultText(string(data)), nil
 	}
@@ -426,7 +543,7 @@ For interactive elements, use browser_snapshot to get refs for precise interacti
 	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
 		result, err := r.executor.GetPageInfo(ctx)
 		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
+			return r.toolError(ctx, request, err), nil
 		}
 
 		data, _ := json.Marshal(result.Data)
@@ -441,47 +558,307 @@ For interactive elements, use browser_snapshot to get refs for precise interacti
 func (r *MCPToolRegistry) registerWaitForTool() error {
 	tool := mcpgo.NewTool(
 		"browser_wait_for",
-		mcpgo.WithDescription("Wait for an element to appear or change state"),
-		mcpgo.WithString("identifier", mcpgo.Required(), mcpgo.Description("Element identifier")),
+		mcpgo.WithDescription("Wait for an element to appear or change state, for its text, for the URL to change, for the network to become idle, or for a custom JS condition. All given conditions must be met."),
+		mcpgo.WithString("identifier", mcpgo.Description("Element identifier (required unless url, network_idle or predicate is set)")),
 		mcpgo.WithString("state", mcpgo.Description("Wait state: visible, hidden, enabled (default: visible)")),
+		mcpgo.WithString("text", mcpgo.Description("Wait until the element's text contains this string")),
+		mcpgo.WithString("url", mcpgo.Description("Wait until the page URL matches this pattern (supports * wildcards, otherwise substring match)")),
+		mcpgo.WithBoolean("network_idle", mcpgo.Description("Wait until the network is idle")),
+		mcpgo.WithNumber("idle_time", mcpgo.Description("How long the network must stay idle, in milliseconds (default: 500)")),
+		mcpgo.WithNumber("max_inflight", mcpgo.Description("Maximum in-flight requests still considered idle (default: 0)")),
+		mcpgo.WithString("predicate", mcpgo.Description("JS expression or function that must return truthy, e.g. document.querySelectorAll('.row').length >= 10")),
 		mcpgo.WithNumber("timeout", mcpgo.Description("Timeout in seconds (default: 30)")),
 	)
 
 	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
 		args := request.Params.Arguments.(map[string]interface{})
 		identifier, _ := args["identifier"].(string)
+		opts := ParseWaitForArguments(args)
 
-		opts := &WaitForOptions{
-			State:   "visible",
-			Timeout: 30 * time.Second,
+		result, err := r.executor.WaitFor(ctx, identifier, opts)
+		if err != nil {
+			return r.toolError(ctx, request, err), nil
 		}
 
-		if state, ok := args["state"].(string); ok && state != "" {
-			opts.State = state
+		return mcpgo.NewToolResultText(result.Message), nil
+	}
+
+	r.mcpServer.AddTool(tool, handler)
+	return nil
+}
+
+// registerInspectElementTool 注册元素检查工具
+func (r *MCPToolRegistry) registerInspectElementTool() error {
+	tool := mcpgo.NewTool(
+		"browser_inspect_element",
+		mcpgo.WithDescription("Inspect an element to debug why it cannot be clicked or is considered hidden. Returns its attributes, computed styles, bounding box, visibility and interactability verdicts with reasons, the element intercepting clicks at its center (if any), its ancestor chain, and what a click would do (submits a form, opens a link, downloads)."),
+		mcpgo.WithString("identifier", mcpgo.Required(), mcpgo.Description("Element identifier (RefID like @e1, CSS selector, XPath, or text)")),
+		mcpgo.WithArray("styles", mcpgo.Description("Additional computed style properties to return"), mcpgo.WithStringItems()),
+	)
+
+	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		args := request.Params.Arguments.(map[string]interface{})
+		identifier, _ := args["identifier"].(string)
+
+		result, err := r.executor.InspectElement(ctx, identifier, ParseInspectArguments(args))
+		if err != nil {
+			return r.toolError(ctx, request, err), nil
 		}
 
-		if timeout, ok := args["timeout"].(float64); ok && timeout > 0 {
-			opts.Timeout = time.Duration(timeout) * time.Second
+		data, _ := json.Marshal(result.Data)
+		return mcpgo.NewToolResultText(fmt.Sprintf("%s\n\n%s", result.Message, string(data))), nil
+	}
+
+	r.mcpServer.AddTool(tool, handler)
+	return nil
+}
+
+// registerAuditTool 注册页面审计工具
+func (r *MCPToolRegistry) registerAuditTool() error {
+	tool := mcpgo.NewTool(
+		"browser_audit",
+		mcpgo.WithDescription("Run a basic Lighthouse-style audit of the current page. Returns 0-100 scores for performance (FCP, LCP, CLS, TTFB, page weight, DOM size), SEO (title, meta description, viewport, indexability, headings, link text) and accessibility (lang, alt text, labels, accessible names, duplicate IDs, heading order, zoom), plus every check with its measured value and a short explanation when it fails."),
+		mcpgo.WithArray("categories", mcpgo.Description("Categories to audit: 'performance', 'seo', 'accessibility' (default: all)"), mcpgo.WithStringItems()),
+	)
+
+	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		args, _ := request.Params.Arguments.(map[string]interface{})
+
+		result, err := r.executor.Audit(ctx, ParseAuditArguments(args))
+		if err != nil {
+			return r.toolError(ctx, request, err), nil
 		}
 
-		result, err := r.executor.WaitFor(ctx, identifier, opts)
+		data, _ := json.Marshal(result.Data)
+		return mcpgo.NewToolResultText(fmt.Sprintf("%s\n\n%s", result.Message, string(data))), nil
+	}
+
+	r.mcpServer.AddTool(tool, handler)
+	return nil
+}
+
+// registerA11yScanTool 注册可访问性扫描工具
+func (r *MCPToolRegistry) registerA11yScanTool() error {
+	tool := mcpgo.NewTool(
+		"browser_a11y_scan",
+		mcpgo.WithDescription("Scan the current page for WCAG accessibility violations using axe-core. Returns each violation with its rule id, impact (minor/moderate/serious/critical), help text and URL, WCAG tags and the offending elements (selector, HTML, failure summary), sorted by impact. Only the main document is scanned, not iframes."),
+		mcpgo.WithArray("tags", mcpgo.Description("Only run rules with these tags, e.g. ['wcag2a', 'wcag2aa', 'wcag21aa', 'best-practice'] (default: all rules)"), mcpgo.WithStringItems()),
+		mcpgo.WithString("selector", mcpgo.Description("CSS selector limiting the scan to part of the page (default: whole page)")),
+		mcpgo.WithString("min_impact", mcpgo.Description("Only report violations with at least this impact: 'minor', 'moderate', 'serious', 'critical'")),
+		mcpgo.WithNumber("max_nodes", mcpgo.Description("Maximum number of elements reported per violation (default: 10)")),
+	)
+
+	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		args, _ := request.Params.Arguments.(map[string]interface{})
+
+		result, err := r.executor.A11yScan(ctx, ParseA11yScanArguments(args))
 		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
+			return r.toolError(ctx, request, err), nil
 		}
 
-		return mcpgo.NewToolResultText(result.Message), nil
+		data, _ := json.Marshal(result.Data)
+		return mcpgo.NewToolResultText(fmt.Sprintf("%s\n\n%s", result.Message, string(data))), nil
+	}
+
+	r.mcpServer.AddTool(tool, handler)
+	return nil
+}
+
+// registerCheckLinksTool 注册链接检查工具
+func (r *MCPToolRegistry) registerCheckLinksTool() error {
+	tool := mcpgo.NewTool(
+		"browser_check_links",
+		mcpgo.WithDescription("Check the links on the current page for broken targets. Links are requested with HEAD (falling back to GET) from the server using the browser's cookies and user agent, so pages behind a login can be checked. Links that look like they log out or change account state (logout, sign-out, unsubscribe, delete, ...) are skipped and not requested. Set depth to also crawl pages in the same site section and check their links. Returns each link's status code, redirect target, error and the pages it was found on, broken links first."),
+		mcpgo.WithString("selector", mcpgo.Description("CSS selector limiting the links to part of the page (default: whole page)")),
+		mcpgo.WithBoolean("same_origin", mcpgo.Description("Only check links on the same origin as the current page (default: false)")),
+		mcpgo.WithNumber("depth", mcpgo.Description("How many levels of same-section pages to crawl (default: 0, only links on the current page)")),
+		mcpgo.WithString("section", mcpgo.Description("Path prefix of the site section to crawl when depth > 0 (default: directory of the current page)")),
+		mcpgo.WithNumber("concurrency", mcpgo.Description("Number of concurrent requests (default: 5, max: 20)")),
+		mcpgo.WithNumber("timeout", mcpgo.Description("Timeout per request in seconds (default: 15)")),
+		mcpgo.WithNumber("max_links", mcpgo.Description("Maximum number of links to check (default: 200)")),
+		mcpgo.WithBoolean("only_broken", mcpgo.Description("Only return broken links in the result (default: false)")),
+	)
+
+	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		args, _ := request.Params.Arguments.(map[string]interface{})
+
+		result, err := r.executor.CheckLinks(ctx, ParseLinkCheckArguments(args))
+		if err != nil {
+			return r.toolError(ctx, request, err), nil
+		}
+
+		data, _ := json.Marshal(result.Data)
+		return mcpgo.NewToolResultText(fmt.Sprintf("%s\n\n%s", result.Message, string(data))), nil
+	}
+
+	r.mcpServer.AddTool(tool, handler)
+	return nil
+}
+
+// registerTranslatePageTool 注册页面翻译工具
+func (r *MCPToolRegistry) registerTranslatePageTool() error {
+	tool := mcpgo.NewTool(
+		"browser_translate_page",
+		mcpgo.WithDescription("Translate the visible text of the current page with the configured LLM. Returns bilingual output as a list of segments (id, original, translation) in reading order. Set overlay=true to also show each translation below its original paragraph in the page."),
+		mcpgo.WithString("target_language", mcpgo.Required(), mcpgo.Description("Language to translate into, e.g. 'English', 'Simplified Chinese', 'ja'")),
+		mcpgo.WithString("source_language", mcpgo.Description("Source language (optional, detected automatically)")),
+		mcpgo.WithString("selector", mcpgo.Description("CSS selector limiting translation to part of the page (default: whole page)")),
+		mcpgo.WithBoolean("overlay", mcpgo.Description("Show the translations in the page below the original text (default: false)")),
+		mcpgo.WithNumber("max_segments", mcpgo.Description("Maximum number of text segments to translate (default: 200)")),
+		mcpgo.WithString("llm_config", mcpgo.Description("Name of the LLM config to use (default: the default LLM config)")),
+	)
+
+	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		args, _ := request.Params.Arguments.(map[string]interface{})
+
+		result, err := r.executor.TranslatePage(ctx, ParseTranslateArguments(args))
+		if err != nil {
+			return r.toolError(ctx, request, err), nil
+		}
+
+		data, _ := json.Marshal(result.Data)
+		return mcpgo.NewToolResultText(fmt.Sprintf("%s\n\n%s", result.Message, string(data))), nil
+	}
+
+	r.mcpServer.AddTool(tool, handler)
+	return nil
+}
+
+// registerReadImageTextTool 注册图片文字识别工具
+func (r *MCPToolRegistry) registerReadImageTextTool() error {
+	tool := mcpgo.NewTool(
+		"browser_read_image_text",
+		mcpgo.WithDescription("Extract text from rendered pixels with OCR, for canvas charts, images and image-only pages where DOM extraction returns nothing. Captures the given element (or the viewport / full page) and returns the recognized text, optionally with each word's confidence and position. CAPTCHA images are not supported."),
+		mcpgo.WithString("identifier", mcpgo.Description("Element to read, e.g. a canvas or img (RefID like @e1, CSS selector, XPath, or text). Default: the current viewport")),
+		mcpgo.WithBoolean("full_page", mcpgo.Description("When no identifier is given, read the full page instead of the viewport (default: false)")),
+		mcpgo.WithBoolean("include_words", mcpgo.Description("Include each recognized word with its confidence and pixel position (default: false)")),
+		mcpgo.WithNumber("timeout", mcpgo.Description("Timeout in seconds for finding the element (default: 10)")),
+	)
+
+	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		args, _ := request.Params.Arguments.(map[string]interface{})
+
+		result, err := r.executor.ReadImageText(ctx, ParseReadImageTextArguments(args))
+		if err != nil {
+			return r.toolError(ctx, request, err), nil
+		}
+
+		data, _ := json.Marshal(result.Data)
+		return mcpgo.NewToolResultText(fmt.Sprintf("%s\n\n%s", result.Message, string(data))), nil
 	}
 
 	r.mcpServer.AddTool(tool, handler)
 	return nil
 }
 
+// registerClickAtTool 注册坐标点击工具
+func (r *MCPToolRegistry) registerClickAtTool() error {
+	tool := mcpgo.NewTool(
+		"browser_click_at",
+		mcpgo.WithDescription("Click at a coordinate instead of an element, for canvas apps (maps, design tools, games) and vision-guided automation where no DOM element is addressable. Give x/y, or a region (x, y, width, height) to click its center - e.g. a box located in a screenshot. Returns the element found at that point."),
+		mcpgo.WithNumber("x", mcpgo.Required(), mcpgo.Description("X coordinate (left edge of the region when width/height are given)")),
+		mcpgo.WithNumber("y", mcpgo.Required(), mcpgo.Description("Y coordinate (top edge of the region when width/height are given)")),
+		mcpgo.WithNumber("width", mcpgo.Description("Region width; with height, clicks the region's center")),
+		mcpgo.WithNumber("height", mcpgo.Description("Region height; with width, clicks the region's center")),
+		mcpgo.WithString("space", mcpgo.Description("Coordinate space: 'viewport' (CSS pixels, default), 'page' (document CSS pixels, scrolls as needed), 'screenshot' (pixels of a viewport screenshot), 'fullpage_screenshot' (pixels of a full-page screenshot)")),
+		mcpgo.WithString("button", mcpgo.Description("Mouse button: 'left' (default), 'right' or 'middle'")),
+		mcpgo.WithNumber("click_count", mcpgo.Description("Number of clicks, 2 for double click (default: 1)")),
+	)
+
+	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		args, _ := request.Params.Arguments.(map[string]interface{})
+
+		result, err := r.executor.ClickAt(ctx, ParseClickAtArguments(args))
+		if err != nil {
+			return r.toolError(ctx, request, err), nil
+		}
+
+		data, _ := json.Marshal(result.Data)
+		return mcpgo.NewToolResultText(fmt.Sprintf("%s\n\n%s", result.Message, string(data))), nil
+	}
+
+	r.mcpServer.AddTool(tool, handler)
+	return nil
+}
+
+// registerDragBoxTool 注册坐标拖拽工具
+func (r *MCPToolRegistry) registerDragBoxTool() error {
+	tool := mcpgo.NewTool(
+		"browser_drag_box",
+		mcpgo.WithDescription("Press the mouse at one coordinate and drag to another, e.g. to draw a selection box on a canvas, pan a map or move a shape. Use browser_drag for dragging DOM elements."),
+		mcpgo.WithNumber("from_x", mcpgo.Required(), mcpgo.Description("Start X coordinate")),
+		mcpgo.WithNumber("from_y", mcpgo.Required(), mcpgo.Description("Start Y coordinate")),
+		mcpgo.WithNumber("to_x", mcpgo.Required(), mcpgo.Description("End X coordinate")),
+		mcpgo.WithNumber("to_y", mcpgo.Required(), mcpgo.Description("End Y coordinate")),
+		mcpgo.WithString("space", mcpgo.Description("Coordinate space: 'viewport' (default), 'page', 'screenshot' or 'fullpage_screenshot'")),
+		mcpgo.WithNumber("steps", mcpgo.Description("Intermediate mouse move events during the drag (default: 10)")),
+		mcpgo.WithNumber("hold_ms", mcpgo.Description("Pause in milliseconds after pressing before moving (default: 0)")),
+	)
+
+	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		args, _ := request.Params.Arguments.(map[string]interface{})
+
+		result, err := r.executor.DragBox(ctx, ParseDragBoxArguments(args))
+		if err != nil {
+			return r.toolError(ctx, request, err), nil
+		}
+
+		data, _ := json.Marshal(result.Data)
+		return mcpgo.NewToolResultText(fmt.Sprintf("%s\n\n%s", result.Message, string(data))), nil
+	}
+
+	r.mcpServer.AddTool(tool, handler)
+	return nil
+}
+
+// ParseInspectArguments 解析 browser_inspect_element 工具参数
+func ParseInspectArguments(args map[string]interface{}) *InspectOptions {
+	opts := &InspectOptions{}
+	if styles, ok := args["styles"].([]interface{}); ok {
+		for _, s := range styles {
+			if name, ok := s.(string); ok {
+				opts.Styles = append(opts.Styles, name)
+			}
+		}
+	}
+	return opts
+}
+
+// ParseWaitForArguments 解析 browser_wait_for 工具参数
+func ParseWaitForArguments(args map[string]interface{}) *WaitForOptions {
+	opts := &WaitForOptions{
+		State:   "visible",
+		Timeout: 30 * time.Second,
+	}
+
+	if state, ok := args["state"].(string); ok && state != "" {
+		opts.State = state
+	}
+	if timeout, ok := args["timeout"].(float64); ok && timeout > 0 {
+		opts.Timeout = time.Duration(timeout) * time.Second
+	}
+	opts.Text, _ = args["text"].(string)
+	opts.URL, _ = args["url"].(string)
+	opts.Predicate, _ = args["predicate"].(string)
+	opts.NetworkIdle, _ = args["network_idle"].(bool)
+	if idleTime, ok := args["idle_time"].(float64); ok && idleTime > 0 {
+		opts.IdleTime = time.Duration(idleTime) * time.Millisecond
+	}
+	if maxInflight, ok := args["max_inflight"].(float64); ok && maxInflight > 0 {
+		opts.MaxInflight = int(maxInflight)
+	}
+	return opts
+}
+
 // registerScrollTool 注册滚动工具
 func (r *MCPToolRegistry) registerScrollTool() error {
 	tool := mcpgo.NewTool(
 		"browser_scroll",
-		mcpgo.WithDescription("Scroll the page or to an element"),
-		mcpgo.WithString("direction", mcpgo.Description("Scroll direction: bottom, top, or element identifier")),
+		mcpgo.WithDescription("Scroll the page, a scrollable container, or to an element. Supports relative deltas and horizontal scrolling"),
+		mcpgo.WithString("direction", mcpgo.Description("Scroll direction: bottom, top, left, right, or element identifier")),
+		mcpgo.WithString("container", mcpgo.Description("Scrollable container to scroll instead of the page (RefID, CSS selector, XPath)")),
+		mcpgo.WithNumber("delta_x", mcpgo.Description("Scroll horizontally by this many pixels (negative scrolls left)")),
+		mcpgo.WithNumber("delta_y", mcpgo.Description("Scroll vertically by this many pixels (negative scrolls up)")),
 	)
 
 	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
@@ -491,6 +868,15 @@ func (r *MCPToolRegistry) registerScrollTool() error {
 		var result *OperationResult
 		var err error
 
+		// 指定容器、相对距离或水平方向时使用通用滚动
+		if opts := ParseScrollArguments(args); opts.IsCustom() {
+			result, err = r.executor.Scroll(ctx, opts)
+			if err != nil {
+				return r.toolError(ctx, request, err), nil
+			}
+			return mcpgo.NewToolResultText(result.Message), nil
+		}
+
 		switch direction {
 		case "bottom":
 			result, err = r.executor.ScrollToBottom(ctx)
@@ -513,7 +899,7 @@ func (r *MCPToolRegistry) registerScrollTool() error {
 			if page != nil {
 				elem, findErr := r.executor.findElement(ctx, page, direction)
 				if findErr != nil {
-					return mcpgo.NewToolResultError(findErr.Error()), findErr
+					return r.toolError(ctx, request, findErr), findErr
 				}
 				err = ScrollToElement(ctx, elem)
 				if err == nil {
@@ -526,7 +912,7 @@ func (r *MCPToolRegistry) registerScrollTool() error {
 		}
 
 		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
+			return r.toolError(ctx, request, err), nil
 		}
 
 		return mcpgo.NewToolResultText(result.Message), nil
@@ -543,6 +929,7 @@ func (r *MCPToolRegistry) registerScreenshotTool() error {
 		mcpgo.WithDescription("Take a screenshot of the current page"),
 		mcpgo.WithBoolean("full_page", mcpgo.Description("Capture full page (default: false)")),
 		mcpgo.WithString("format", mcpgo.Description("Image format: png or jpeg (default: png)")),
+		mcpgo.WithString("file_name", mcpgo.Description("File name to save the screenshot as, inside the configured screenshots directory (optional)")),
 	)
 
 	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
@@ -560,10 +947,11 @@ func (r *MCPToolRegistry) registerScreenshotTool() error {
 		if format, ok := args["format"].(string); ok && format != "" {
 			opts.Format = format
 		}
+		opts.FileName, _ = args["file_name"].(string)
 
 		result, err := r.executor.Screenshot(ctx, opts)
 		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
+			return r.toolError(ctx, request, err), nil
 		}
 
 		// 构建返回消息，包含路径信息
@@ -615,7 +1003,7 @@ Note: Always use 'return' to return values. The result will be serialized as JSO
 
 		result, err := r.executor.Evaluate(ctx, script)
 		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
+			return r.toolError(ctx, request, err), nil
 		}
 
 		// 返回执行结果
@@ -633,12 +1021,13 @@ Note: Always use 'return' to return values. The result will be serialized as JSO
 func (r *MCPToolRegistry) registerPressKeyTool() error {
 	tool := mcpgo.NewTool(
 		"browser_press_key",
-		mcpgo.WithDescription("Press a keyboard key"),
-		mcpgo.WithString("key", mcpgo.Required(), mcpgo.Description("Key to press (e.g., Enter, Tab, ArrowUp, etc.)")),
+		mcpgo.WithDescription("Press a keyboard key, a shortcut or a chain of shortcuts"),
+		mcpgo.WithString("key", mcpgo.Required(), mcpgo.Description("Key to press (e.g., Enter, Tab, ArrowUp), a shortcut (ctrl+s, mod+shift+p) or a chain separated by 'then' (ctrl+k then p). 'mod' is Cmd on macOS and Ctrl elsewhere")),
 		mcpgo.WithBoolean("ctrl", mcpgo.Description("Hold Ctrl key")),
 		mcpgo.WithBoolean("shift", mcpgo.Description("Hold Shift key")),
 		mcpgo.WithBoolean("alt", mcpgo.Description("Hold Alt key")),
 		mcpgo.WithBoolean("meta", mcpgo.Description("Hold Meta key (Command/Windows)")),
+		mcpgo.WithBoolean("mod", mcpgo.Description("Hold the platform modifier (Command on macOS, Ctrl elsewhere)")),
 	)
 
 	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
@@ -658,10 +1047,13 @@ func (r *MCPToolRegistry) registerPressKeyTool() error {
 		if meta, ok := args["meta"].(bool); ok {
 			opts.Meta = meta
 		}
+		if mod, ok := args["mod"].(bool); ok {
+			opts.Mod = mod
+		}
 
 		result, err := r.executor.PressKey(ctx, key, opts)
 		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
+			return r.toolError(ctx, request, err), nil
 		}
 
 		return mcpgo.NewToolResultText(result.Message), nil
@@ -699,7 +1091,7 @@ func (r *MCPToolRegistry) registerResizeTool() error {
 
 		result, err := r.executor.Resize(ctx, width, height)
 		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
+			return r.toolError(ctx, request, err), nil
 		}
 
 		return mcpgo.NewToolResultText(result.Message), nil
@@ -713,9 +1105,12 @@ func (r *MCPToolRegistry) registerResizeTool() error {
 func (r *MCPToolRegistry) registerDragTool() error {
 	tool := mcpgo.NewTool(
 		"browser_drag",
-		mcpgo.WithDescription("Drag an element to another element"),
-		mcpgo.WithString("from_identifier", mcpgo.Required(), mcpgo.Description("Source element identifier")),
+		mcpgo.WithDescription("Drag an element to another element. Use mode='html5' for HTML5 drag-and-drop libraries (dispatches dragstart/dragover/drop with a DataTransfer) or to drop local files onto a drop zone."),
+		mcpgo.WithString("from_identifier", mcpgo.Description("Source element identifier (optional in html5 mode when dropping files)")),
 		mcpgo.WithString("to_identifier", mcpgo.Required(), mcpgo.Description("Target element identifier")),
+		mcpgo.WithString("mode", mcpgo.Description("Drag mode: 'mouse' (default, raw mouse moves) or 'html5' (synthesized drag events)")),
+		mcpgo.WithObject("data", mcpgo.Description("html5 mode: DataTransfer data as MIME type to value, e.g. {\"text/plain\": \"hello\"}")),
+		mcpgo.WithArray("files", mcpgo.Description("html5 mode: files to drop onto the target, as paths inside the server upload directory"), mcpgo.WithStringItems()),
 	)
 
 	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
@@ -723,9 +1118,153 @@ func (r *MCPToolRegistry) registerDragTool() error {
 		fromIdentifier, _ := args["from_identifier"].(string)
 		toIdentifier, _ := args["to_identifier"].(string)
 
-		result, err := r.executor.Drag(ctx, fromIdentifier, toIdentifier)
+		result, err := r.executor.Drag(ctx, fromIdentifier, toIdentifier, ParseDragArguments(args))
+		if err != nil {
+			return r.toolError(ctx, request, err), nil
+		}
+
+		return mcpgo.NewToolResultText(result.Message), nil
+	}
+
+	r.mcpServer.AddTool(tool, handler)
+	return nil
+}
+
+// ParseDragArguments 解析 browser_drag 工具参数
+func ParseDragArguments(args map[string]interface{}) *DragOptions {
+	opts := &DragOptions{}
+	if mode, ok := args["mode"].(string); ok {
+		opts.Mode = DragMode(mode)
+	}
+	if data, ok := args["data"].(map[string]interface{}); ok {
+		opts.Data = make(map[string]string, len(data))
+		for k, v := range data {
+			opts.Data[k] = fmt.Sprintf("%v", v)
+		}
+	}
+	if files, ok := args["files"].([]interface{}); ok {
+		for _, f := range files {
+			if path, ok := f.(string); ok && path != "" {
+				opts.Files = append(opts.Files, path)
+			}
+		}
+	}
+	return opts
+}
+
+// registerChooseOptionTool 注册自定义下拉框选择工具
+func (r *MCPToolRegistry) registerChooseOptionTool() error {
+	tool := mcpgo.NewTool(
+		"browser_choose_option",
+		mcpgo.WithDescription("Choose an option from a custom (non-native) dropdown such as Select2, Ant Design, Element UI, MUI or react-select: opens it, types to filter when a search box is present, and clicks the matching option. Native <select> elements are handled like browser_select"),
+		mcpgo.WithString("identifier", mcpgo.Required(), mcpgo.Description("Dropdown identifier: the visible trigger or the underlying <select> (RefID, CSS selector, XPath)")),
+		mcpgo.WithString("value", mcpgo.Required(), mcpgo.Description("Text of the option to choose")),
+		mcpgo.WithBoolean("exact", mcpgo.Description("Require the option text to match exactly (default: exact match preferred, otherwise contains)")),
+		mcpgo.WithBoolean("filter", mcpgo.Description("Type the value into the dropdown search box to filter options (default: true)")),
+		mcpgo.WithString("option_selector", mcpgo.Description("CSS selector for option elements, overriding the built-in widget rules")),
+		mcpgo.WithNumber("timeout", mcpgo.Description("Seconds to wait for the dropdown and matching option (default: 10)")),
+	)
+
+	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		args := request.Params.Arguments.(map[string]interface{})
+		identifier, _ := args["identifier"].(string)
+		value, _ := args["value"].(string)
+
+		result, err := r.executor.ChooseOption(ctx, identifier, value, ParseChooseOptionArguments(args))
 		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
+			return r.toolError(ctx, request, err), nil
+		}
+
+		return mcpgo.NewToolResultText(result.Message), nil
+	}
+
+	r.mcpServer.AddTool(tool, handler)
+	return nil
+}
+
+// ParseChooseOptionArguments 解析 browser_choose_option 工具参数
+func ParseChooseOptionArguments(args map[string]interface{}) *ChooseOptionOptions {
+	opts := &ChooseOptionOptions{}
+	opts.Exact, _ = args["exact"].(bool)
+	if filter, ok := args["filter"].(bool); ok {
+		opts.SkipFilter = !filter
+	}
+	opts.OptionSelector, _ = args["option_selector"].(string)
+	if timeout, ok := args["timeout"].(float64); ok && timeout > 0 {
+		opts.Timeout = time.Duration(timeout) * time.Second
+	}
+	return opts
+}
+
+// registerFillDateTool 注册日期填写工具
+func (r *MCPToolRegistry) registerFillDateTool() error {
+	tool := mcpgo.NewTool(
+		"browser_fill_date",
+		mcpgo.WithDescription("Fill a date input or date picker. Sets the value with proper input/change events (native date inputs, flatpickr, jQuery UI, plain text inputs) or opens the calendar widget and navigates to the target date"),
+		mcpgo.WithString("identifier", mcpgo.Required(), mcpgo.Description("Date input identifier: RefID, CSS selector, XPath, or label")),
+		mcpgo.WithString("date", mcpgo.Required(), mcpgo.Description("Target date: YYYY-MM-DD, YYYY-MM-DD HH:mm, today, tomorrow, or relative like +7d, -1m, +1y")),
+		mcpgo.WithString("strategy", mcpgo.Description("How to fill: auto (default), value (set input value), calendar (click through the calendar widget)")),
+		mcpgo.WithString("format", mcpgo.Description("Date format for text inputs, e.g. MM/DD/YYYY or DD.MM.YYYY (default: from placeholder, otherwise YYYY-MM-DD)")),
+		mcpgo.WithNumber("timeout", mcpgo.Description("Seconds to wait for the input and calendar (default: 15)")),
+	)
+
+	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		args := request.Params.Arguments.(map[string]interface{})
+		identifier, _ := args["identifier"].(string)
+		date, _ := args["date"].(string)
+
+		result, err := r.executor.FillDate(ctx, identifier, date, ParseFillDateArguments(args))
+		if err != nil {
+			return r.toolError(ctx, request, err), nil
+		}
+
+		return mcpgo.NewToolResultText(result.Message), nil
+	}
+
+	r.mcpServer.AddTool(tool, handler)
+	return nil
+}
+
+// ParseFillDateArguments 解析 browser_fill_date 工具参数
+func ParseFillDateArguments(args map[string]interface{}) *FillDateOptions {
+	opts := &FillDateOptions{}
+	if strategy, ok := args["strategy"].(string); ok {
+		opts.Strategy = DateFillStrategy(strategy)
+	}
+	opts.Format, _ = args["format"].(string)
+	if timeout, ok := args["timeout"].(float64); ok && timeout > 0 {
+		opts.Timeout = time.Duration(timeout) * time.Second
+	}
+	return opts
+}
+
+// registerHoverThenClickTool 注册悬停后点击工具
+func (r *MCPToolRegistry) registerHoverThenClickTool() error {
+	tool := mcpgo.NewTool(
+		"browser_hover_then_click",
+		mcpgo.WithDescription("Hover a trigger element, wait for the revealed target (e.g. an item in a hover menu) and click it in one step, retrying the hover if the target does not appear"),
+		mcpgo.WithString("trigger", mcpgo.Required(), mcpgo.Description("Identifier of the element to hover")),
+		mcpgo.WithString("target", mcpgo.Required(), mcpgo.Description("Identifier of the element to click once revealed")),
+		mcpgo.WithNumber("timeout", mcpgo.Description("Seconds to wait for the target on each attempt (default: 5)")),
+		mcpgo.WithNumber("retries", mcpgo.Description("Maximum number of attempts (default: 3)")),
+	)
+
+	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		args := request.Params.Arguments.(map[string]interface{})
+		trigger, _ := args["trigger"].(string)
+		target, _ := args["target"].(string)
+
+		opts := &HoverThenClickOptions{}
+		if timeout, ok := args["timeout"].(float64); ok && timeout > 0 {
+			opts.Timeout = time.Duration(timeout) * time.Second
+		}
+		if retries, ok := args["retries"].(float64); ok && retries > 0 {
+			opts.Retries = int(retries)
+		}
+
+		result, err := r.executor.HoverThenClick(ctx, trigger, target, opts)
+		if err != nil {
+			return r.toolError(ctx, request, err), nil
 		}
 
 		return mcpgo.NewToolResultText(result.Message), nil
@@ -745,7 +1284,7 @@ func (r *MCPToolRegistry) registerClosePageTool() error {
 	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
 		result, err := r.executor.ClosePage(ctx)
 		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
+			return r.toolError(ctx, request, err), nil
 		}
 
 		return mcpgo.NewToolResultText(result.Message), nil
@@ -761,7 +1300,7 @@ func (r *MCPToolRegistry) registerFileUploadTool() error {
 		"browser_file_upload",
 		mcpgo.WithDescription("Upload files to a file input element"),
 		mcpgo.WithString("identifier", mcpgo.Required(), mcpgo.Description("File input element identifier")),
-		mcpgo.WithArray("file_paths", mcpgo.Required(), mcpgo.Description("Array of file paths to upload")),
+		mcpgo.WithArray("file_paths", mcpgo.Required(), mcpgo.Description("Array of file paths to upload (absolute paths, or upload:<id> handles from POST /api/v1/uploads)")),
 	)
 
 	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
@@ -783,7 +1322,7 @@ func (r *MCPToolRegistry) registerFileUploadTool() error {
 
 		result, err := r.executor.FileUpload(ctx, identifier, filePaths)
 		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
+			return r.toolError(ctx, request, err), nil
 		}
 
 		return mcpgo.NewToolResultText(result.Message), nil
@@ -816,7 +1355,7 @@ func (r *MCPToolRegistry) registerHandleDialogTool() error {
 
 		result, err := r.executor.HandleDialog(ctx, accept, text)
 		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
+			return r.toolError(ctx, request, err), nil
 		}
 
 		return mcpgo.NewToolResultText(result.Message), nil
@@ -836,7 +1375,7 @@ func (r *MCPToolRegistry) registerGetConsoleMessagesTool() error {
 	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
 		result, err := r.executor.GetConsoleMessages(ctx)
 		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
+			return r.toolError(ctx, request, err), nil
 		}
 
 		return mcpgo.NewToolResultText(result.Message), nil
@@ -856,7 +1395,7 @@ func (r *MCPToolRegistry) registerGetNetworkRequestsTool() error {
 	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
 		result, err := r.executor.GetNetworkRequests(ctx)
 		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
+			return r.toolError(ctx, request, err), nil
 		}
 
 		return mcpgo.NewToolResultText(result.Message), nil
@@ -866,6 +1405,229 @@ func (r *MCPToolRegistry) registerGetNetworkRequestsTool() error {
 	return nil
 }
 
+// registerCaptureResponseTool 注册响应捕获工具
+func (r *MCPToolRegistry) registerCaptureResponseTool() error {
+	tool := mcpgo.NewTool(
+		"browser_capture_response",
+		mcpgo.WithDescription("Capture response bodies (e.g. JSON API payloads) of requests whose URL matches a pattern. Call with action='start' before the action that triggers the request, then action='get' to read the body. Often more robust than scraping the rendered page."),
+		mcpgo.WithString("action", mcpgo.Required(), mcpgo.Description("Capture action: 'start', 'get', or 'stop'")),
+		mcpgo.WithString("url_pattern", mcpgo.Description("URL pattern, supports * wildcard; without wildcard it matches as a substring (required when action='start')")),
+		mcpgo.WithString("method", mcpgo.Description("Optional HTTP method filter for 'get' (e.g. GET, POST)")),
+		mcpgo.WithNumber("timeout", mcpgo.Description("Seconds to wait for a matching response when action='get' (default: 10)")),
+	)
+
+	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		args := request.Params.Arguments.(map[string]interface{})
+		action, _ := args["action"].(string)
+
+		opts := &CaptureResponseOptions{
+			Action: CaptureResponseAction(action),
+		}
+		if pattern, ok := args["url_pattern"].(string); ok {
+			opts.URLPattern = pattern
+		}
+		if method, ok := args["method"].(string); ok {
+			opts.Method = method
+		}
+		if timeout, ok := args["timeout"].(float64); ok && timeout > 0 {
+			opts.Timeout = time.Duration(timeout) * time.Second
+		}
+
+		result, err := r.executor.CaptureResponse(ctx, opts)
+		if err != nil {
+			return r.toolError(ctx, request, err), nil
+		}
+
+		if opts.Action == CaptureResponseGet {
+			// 序列化响应体为 JSON
+			data, _ := json.Marshal(result.Data["body"])
+			return mcpgo.NewToolResultText(fmt.Sprintf("%s\n\n%s", result.Message, string(data))), nil
+		}
+		return mcpgo.NewToolResultText(result.Message), nil
+	}
+
+	r.mcpServer.AddTool(tool, handler)
+	return nil
+}
+
+// registerWebSocketTool 注册 WebSocket 观察工具
+func (r *MCPToolRegistry) registerWebSocketTool() error {
+	tool := mcpgo.NewTool(
+		"browser_websocket",
+		mcpgo.WithDescription("Observe WebSocket traffic on the current page. Call with action='start' before the page opens its connections, then action='list' to read sent/received frames, 'export' to also save them to a file, and 'stop' when done."),
+		mcpgo.WithString("action", mcpgo.Required(), mcpgo.Description("WebSocket action: 'start', 'list', 'export', or 'stop'")),
+		mcpgo.WithString("url", mcpgo.Description("Filter frames by connection URL, supports * wildcard; without wildcard it matches as a substring")),
+		mcpgo.WithNumber("opcode", mcpgo.Description("Filter frames by opcode: 1 for text, 2 for binary (default: all)")),
+		mcpgo.WithNumber("limit", mcpgo.Description("Maximum number of most recent frames to return (default: all)")),
+	)
+
+	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		args := request.Params.Arguments.(map[string]interface{})
+		action, _ := args["action"].(string)
+
+		opts := &WebSocketOptions{
+			Action: WebSocketAction(action),
+		}
+		if url, ok := args["url"].(string); ok {
+			opts.URL = url
+		}
+		if opcode, ok := args["opcode"].(float64); ok {
+			opts.Opcode = int(opcode)
+		}
+		if limit, ok := args["limit"].(float64); ok {
+			opts.Limit = int(limit)
+		}
+
+		result, err := r.executor.WebSocket(ctx, opts)
+		if err != nil {
+			return r.toolError(ctx, request, err), nil
+		}
+
+		if opts.Action == WebSocketActionList || opts.Action == WebSocketActionExport {
+			// 序列化帧列表为 JSON
+			data, _ := json.Marshal(result.Data["frames"])
+			return mcpgo.NewToolResultText(fmt.Sprintf("%s\n\n%s", result.Message, string(data))), nil
+		}
+		return mcpgo.NewToolResultText(result.Message), nil
+	}
+
+	r.mcpServer.AddTool(tool, handler)
+	return nil
+}
+
+// registerXHRReplayTool 注册 XHR 重放工具
+func (r *MCPToolRegistry) registerXHRReplayTool() error {
+	tool := mcpgo.NewTool(
+		"browser_xhr_replay",
+		mcpgo.WithDescription("List the page's recent XHR/fetch (including GraphQL) calls and re-issue one with modified variables using the page's cookies. Call with action='start' before the UI triggers the requests, 'list' to see them, then 'replay' with request_id and variables (e.g. next page number) to pull API data directly."),
+		mcpgo.WithString("action", mcpgo.Required(), mcpgo.Description("Action: 'start', 'list', 'replay', or 'stop'")),
+		mcpgo.WithString("url", mcpgo.Description("Filter listed requests by URL, supports * wildcard; without wildcard it matches as a substring")),
+		mcpgo.WithString("request_id", mcpgo.Description("ID of the request to replay (from 'list', required when action='replay')")),
+		mcpgo.WithObject("variables", mcpgo.Description("Variables to override when replaying: merged into GraphQL variables, JSON body fields, or URL query parameters")),
+	)
+
+	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		args := request.Params.Arguments.(map[string]interface{})
+		action, _ := args["action"].(string)
+
+		opts := &XHRReplayOptions{
+			Action: XHRReplayAction(action),
+		}
+		if url, ok := args["url"].(string); ok {
+			opts.URL = url
+		}
+		if requestID, ok := args["request_id"].(string); ok {
+			opts.RequestID = requestID
+		}
+		if variables, ok := args["variables"].(map[string]interface{}); ok {
+			opts.Variables = variables
+		}
+
+		result, err := r.executor.XHRReplay(ctx, opts)
+		if err != nil {
+			return r.toolError(ctx, request, err), nil
+		}
+
+		switch opts.Action {
+		case XHRReplayList:
+			data, _ := json.Marshal(result.Data["requests"])
+			return mcpgo.NewToolResultText(fmt.Sprintf("%s\n\n%s", result.Message, string(data))), nil
+		case XHRReplayReplay:
+			data, _ := json.Marshal(result.Data["body"])
+			return mcpgo.NewToolResultText(fmt.Sprintf("%s\n\n%s", result.Message, string(data))), nil
+		}
+		return mcpgo.NewToolResultText(result.Message), nil
+	}
+
+	r.mcpServer.AddTool(tool, handler)
+	return nil
+}
+
+// registerCollectTool 注册采集工具
+func (r *MCPToolRegistry) registerCollectTool() error {
+	tool := mcpgo.NewTool(
+		"browser_collect",
+		mcpgo.WithDescription("Collect list items across infinite scroll or pagination. Scrolls (or clicks the next-page button) until max items, no new content, or max duration is reached, deduplicating items. Partial results are streamed as progress notifications when the client provides a progress token."),
+		mcpgo.WithString("item_selector", mcpgo.Required(), mcpgo.Description("CSS selector matching each item")),
+		mcpgo.WithArray("fields", mcpgo.WithStringItems(), mcpgo.Description("Fields to extract per item: text, html, href, src, value, an attribute name, or 'name=sub-selector[@attr]' (default: [\"text\"])")),
+		mcpgo.WithString("mode", mcpgo.Description("How to load more items: 'scroll' (default) or 'next_page'")),
+		mcpgo.WithString("next_selector", mcpgo.Description("CSS selector of the next-page button (required when mode='next_page')")),
+		mcpgo.WithString("dedupe_key", mcpgo.Description("Field used to deduplicate items (default: the whole item)")),
+		mcpgo.WithNumber("max_items", mcpgo.Description("Maximum number of items to collect (default: 500)")),
+		mcpgo.WithNumber("max_duration", mcpgo.Description("Maximum duration in seconds (default: 120)")),
+		mcpgo.WithNumber("max_idle_rounds", mcpgo.Description("Stop after this many rounds without new items (default: 3)")),
+	)
+
+	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		args := request.Params.Arguments.(map[string]interface{})
+
+		opts := ParseCollectArguments(args)
+
+		// 客户端提供了 progress token 时，通过进度通知流式返回部分结果
+		if request.Params.Meta != nil && request.Params.Meta.ProgressToken != nil {
+			if srv := server.ServerFromContext(ctx); srv != nil {
+				token := request.Params.Meta.ProgressToken
+				opts.OnItems = func(items []map[string]interface{}, total int) {
+					data, _ := json.Marshal(items)
+					err := srv.SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+						"progressToken": token,
+						"progress":      total,
+						"message":       string(data),
+					})
+					if err != nil {
+						logger.Warn(ctx, "Failed to send collect progress: %v", err)
+					}
+				}
+			}
+		}
+
+		result, err := r.executor.Collect(ctx, opts)
+		if err != nil {
+			return r.toolError(ctx, request, err), nil
+		}
+
+		data, _ := json.Marshal(result.Data["items"])
+		return mcpgo.NewToolResultText(fmt.Sprintf("%s\n\n%s", result.Message, string(data))), nil
+	}
+
+	r.mcpServer.AddTool(tool, handler)
+	return nil
+}
+
+// ParseCollectArguments 解析采集工具参数（MCP 工具调用的 arguments）
+func ParseCollectArguments(args map[string]interface{}) *CollectOptions {
+	opts := &CollectOptions{}
+	if selector, ok := args["item_selector"].(string); ok {
+		opts.ItemSelector = selector
+	}
+	if fields, ok := args["fields"].([]interface{}); ok {
+		for _, f := range fields {
+			if field, ok := f.(string); ok && field != "" {
+				opts.Fields = append(opts.Fields, field)
+			}
+		}
+	}
+	if mode, ok := args["mode"].(string); ok {
+		opts.Mode = CollectMode(mode)
+	}
+	if next, ok := args["next_selector"].(string); ok {
+		opts.NextSelector = next
+	}
+	if key, ok := args["dedupe_key"].(string); ok {
+		opts.DedupeKey = key
+	}
+	if maxItems, ok := args["max_items"].(float64); ok {
+		opts.MaxItems = int(maxItems)
+	}
+	if maxDuration, ok := args["max_duration"].(float64); ok {
+		opts.MaxDuration = time.Duration(maxDuration) * time.Second
+	}
+	if idle, ok := args["max_idle_rounds"].(float64); ok {
+		opts.MaxIdleRounds = int(idle)
+	}
+	return opts
+}
+
 // GetToolMetadata 获取所有工具的元数据（用于文档生成）
 func (r *MCPToolRegistry) GetToolMetadata() []ToolMetadata {
 	return GetExecutorToolsMetadata()
@@ -881,6 +1643,7 @@ func GetExecutorToolsMetadata() []ToolMetadata {
 			Parameters: []ToolParameter{
 				{Name: "url", Type: "string", Required: true, Description: "The URL to navigate to"},
 				{Name: "wait_until", Type: "string", Required: false, Description: "Wait condition: load, domcontentloaded, networkidle"},
+				{Name: "snapshot", Type: "string", Required: false, Description: "include (default), skip or defer the page snapshot after the action"},
 			},
 		},
 		{
@@ -890,6 +1653,8 @@ func GetExecutorToolsMetadata() []ToolMetadata {
 			Parameters: []ToolParameter{
 				{Name: "identifier", Type: "string", Required: true, Description: "Element identifier"},
 				{Name: "wait_visible", Type: "boolean", Required: false, Description: "Wait for element to be visible"},
+				{Name: "tab_id", Type: "string", Required: false, Description: "Act on a specific tab instead of the active one"},
+				{Name: "snapshot", Type: "string", Required: false, Description: "include (default), skip or defer the page snapshot after the action"},
 			},
 		},
 		{
@@ -900,6 +1665,9 @@ func GetExecutorToolsMetadata() []ToolMetadata {
 				{Name: "identifier", Type: "string", Required: true, Description: "Element identifier"},
 				{Name: "text", Type: "string", Required: true, Description: "Text to type"},
 				{Name: "clear", Type: "boolean", Required: false, Description: "Clear existing text"},
+				{Name: "ime", Type: "boolean", Required: false, Description: "Type through IME composition events"},
+				{Name: "tab_id", Type: "string", Required: false, Description: "Act on a specific tab instead of the active one"},
+				{Name: "snapshot", Type: "string", Required: false, Description: "include (default), skip or defer the page snapshot after the action"},
 			},
 		},
 		{
@@ -909,6 +1677,32 @@ func GetExecutorToolsMetadata() []ToolMetadata {
 			Parameters: []ToolParameter{
 				{Name: "identifier", Type: "string", Required: true, Description: "Select element identifier"},
 				{Name: "value", Type: "string", Required: true, Description: "Option value or text"},
+				{Name: "snapshot", Type: "string", Required: false, Description: "include (default), skip or defer the page snapshot after the action"},
+			},
+		},
+		{
+			Name:        "browser_fill_date",
+			Description: "Fill a date input or pick the date from its calendar widget",
+			Category:    "Interaction",
+			Parameters: []ToolParameter{
+				{Name: "identifier", Type: "string", Required: true, Description: "Date input identifier"},
+				{Name: "date", Type: "string", Required: true, Description: "YYYY-MM-DD, YYYY-MM-DD HH:mm, today, tomorrow or +7d"},
+				{Name: "strategy", Type: "string", Required: false, Description: "auto (default), value or calendar"},
+				{Name: "format", Type: "string", Required: false, Description: "Date format for text inputs, e.g. MM/DD/YYYY"},
+				{Name: "timeout", Type: "number", Required: false, Description: "Seconds to wait for the input and calendar"},
+			},
+		},
+		{
+			Name:        "browser_choose_option",
+			Description: "Choose an option from a custom dropdown (Select2, Ant Design, MUI, react-select, etc.)",
+			Category:    "Interaction",
+			Parameters: []ToolParameter{
+				{Name: "identifier", Type: "string", Required: true, Description: "Dropdown trigger or underlying <select>"},
+				{Name: "value", Type: "string", Required: true, Description: "Text of the option to choose"},
+				{Name: "exact", Type: "boolean", Required: false, Description: "Require exact text match"},
+				{Name: "filter", Type: "boolean", Required: false, Description: "Type the value into the search box to filter (default: true)"},
+				{Name: "option_selector", Type: "string", Required: false, Description: "CSS selector for option elements"},
+				{Name: "timeout", Type: "number", Required: false, Description: "Seconds to wait for the matching option"},
 			},
 		},
 		{
@@ -927,6 +1721,9 @@ func GetExecutorToolsMetadata() []ToolMetadata {
 			Category:    "Analysis",
 			Parameters: []ToolParameter{
 				{Name: "max_depth", Type: "number", Required: false, Description: "Maximum depth of the tree (default: unlimited)"},
+				{Name: "geometry", Type: "boolean", Required: false, Description: "Include bounding boxes and viewport visibility (default: false)"},
+				{Name: "cursor", Type: "string", Required: false, Description: "Cursor for the next chunk of a large page"},
+				{Name: "max_bytes", Type: "number", Required: false, Description: "Maximum chunk size in bytes"},
 			},
 		},
 		{
@@ -937,20 +1734,29 @@ func GetExecutorToolsMetadata() []ToolMetadata {
 		},
 		{
 			Name:        "browser_wait_for",
-			Description: "Wait for element state",
+			Description: "Wait for element state, text, URL, network idle or JS condition",
 			Category:    "Synchronization",
 			Parameters: []ToolParameter{
-				{Name: "identifier", Type: "string", Required: true, Description: "Element identifier"},
+				{Name: "identifier", Type: "string", Required: false, Description: "Element identifier (required unless url, network_idle or predicate is set)"},
 				{Name: "state", Type: "string", Required: false, Description: "Wait state: visible, hidden, enabled"},
+				{Name: "text", Type: "string", Required: false, Description: "Text the element must contain"},
+				{Name: "url", Type: "string", Required: false, Description: "URL pattern the page must match"},
+				{Name: "network_idle", Type: "boolean", Required: false, Description: "Wait for network idle"},
+				{Name: "idle_time", Type: "number", Required: false, Description: "Network idle duration in milliseconds"},
+				{Name: "max_inflight", Type: "number", Required: false, Description: "In-flight requests allowed when idle"},
+				{Name: "predicate", Type: "string", Required: false, Description: "Custom JS condition"},
 				{Name: "timeout", Type: "number", Required: false, Description: "Timeout in seconds"},
 			},
 		},
 		{
 			Name:        "browser_scroll",
-			Description: "Scroll the page",
+			Description: "Scroll the page or a scrollable container",
 			Category:    "Navigation",
 			Parameters: []ToolParameter{
-				{Name: "direction", Type: "string", Required: false, Description: "Direction: bottom, top, or element identifier"},
+				{Name: "direction", Type: "string", Required: false, Description: "Direction: bottom, top, left, right, or element identifier"},
+				{Name: "container", Type: "string", Required: false, Description: "Scrollable container to scroll instead of the page"},
+				{Name: "delta_x", Type: "number", Required: false, Description: "Horizontal scroll distance in pixels"},
+				{Name: "delta_y", Type: "number", Required: false, Description: "Vertical scroll distance in pixels"},
 			},
 		},
 		{
@@ -960,6 +1766,7 @@ func GetExecutorToolsMetadata() []ToolMetadata {
 			Parameters: []ToolParameter{
 				{Name: "full_page", Type: "boolean", Required: false, Description: "Capture full page"},
 				{Name: "format", Type: "string", Required: false, Description: "Image format: png or jpeg"},
+				{Name: "file_name", Type: "string", Required: false, Description: "File name inside the screenshots directory"},
 			},
 		},
 		{
@@ -972,14 +1779,15 @@ func GetExecutorToolsMetadata() []ToolMetadata {
 		},
 		{
 			Name:        "browser_press_key",
-			Description: "Press a keyboard key",
+			Description: "Press a keyboard key, a shortcut or a chain of shortcuts",
 			Category:    "Interaction",
 			Parameters: []ToolParameter{
-				{Name: "key", Type: "string", Required: true, Description: "Key to press (Enter, Tab, ArrowUp, etc.)"},
+				{Name: "key", Type: "string", Required: true, Description: "Key (Enter, Tab, ArrowUp), shortcut (ctrl+s) or chain (ctrl+k then p)"},
 				{Name: "ctrl", Type: "boolean", Required: false, Description: "Hold Ctrl key"},
 				{Name: "shift", Type: "boolean", Required: false, Description: "Hold Shift key"},
 				{Name: "alt", Type: "boolean", Required: false, Description: "Hold Alt key"},
 				{Name: "meta", Type: "boolean", Required: false, Description: "Hold Meta key"},
+				{Name: "mod", Type: "boolean", Required: false, Description: "Hold Cmd on macOS, Ctrl elsewhere"},
 			},
 		},
 		{
@@ -996,8 +1804,22 @@ func GetExecutorToolsMetadata() []ToolMetadata {
 			Description: "Drag an element to another element",
 			Category:    "Interaction",
 			Parameters: []ToolParameter{
-				{Name: "from_identifier", Type: "string", Required: true, Description: "Source element identifier"},
+				{Name: "from_identifier", Type: "string", Required: false, Description: "Source element identifier (optional when dropping files)"},
 				{Name: "to_identifier", Type: "string", Required: true, Description: "Target element identifier"},
+				{Name: "mode", Type: "string", Required: false, Description: "Drag mode: 'mouse' (default) or 'html5'"},
+				{Name: "data", Type: "object", Required: false, Description: "html5 mode: DataTransfer data (MIME type to value)"},
+				{Name: "files", Type: "array", Required: false, Description: "html5 mode: files to drop, as paths inside the server upload directory"},
+			},
+		},
+		{
+			Name:        "browser_hover_then_click",
+			Description: "Hover a trigger and click the revealed target in one step",
+			Category:    "Interaction",
+			Parameters: []ToolParameter{
+				{Name: "trigger", Type: "string", Required: true, Description: "Element to hover"},
+				{Name: "target", Type: "string", Required: true, Description: "Element to click once revealed"},
+				{Name: "timeout", Type: "number", Required: false, Description: "Seconds to wait for the target per attempt"},
+				{Name: "retries", Type: "number", Required: false, Description: "Maximum number of attempts"},
 			},
 		},
 		{
@@ -1012,7 +1834,7 @@ func GetExecutorToolsMetadata() []ToolMetadata {
 			Category:    "Interaction",
 			Parameters: []ToolParameter{
 				{Name: "identifier", Type: "string", Required: true, Description: "File input element identifier"},
-				{Name: "file_paths", Type: "array", Required: true, Description: "Array of file paths to upload"},
+				{Name: "file_paths", Type: "array", Required: true, Description: "Array of file paths to upload (absolute paths, or upload:<id> handles from POST /api/v1/uploads)"},
 			},
 		},
 		{
@@ -1036,6 +1858,149 @@ func GetExecutorToolsMetadata() []ToolMetadata {
 			Category:    "Debug",
 			Parameters:  []ToolParameter{},
 		},
+		{
+			Name:        "browser_capture_response",
+			Description: "Capture response bodies of requests whose URL matches a pattern",
+			Category:    "Data",
+			Parameters: []ToolParameter{
+				{Name: "action", Type: "string", Required: true, Description: "Action: 'start', 'get', or 'stop'"},
+				{Name: "url_pattern", Type: "string", Required: false, Description: "URL pattern with * wildcard, or substring (required for 'start')"},
+				{Name: "method", Type: "string", Required: false, Description: "HTTP method filter for 'get'"},
+				{Name: "timeout", Type: "number", Required: false, Description: "Seconds to wait for a response when action='get' (default: 10)"},
+			},
+		},
+		{
+			Name:        "browser_websocket",
+			Description: "Observe WebSocket frames on the current page",
+			Category:    "Debug",
+			Parameters: []ToolParameter{
+				{Name: "action", Type: "string", Required: true, Description: "Action: 'start', 'list', 'export', or 'stop'"},
+				{Name: "url", Type: "string", Required: false, Description: "Filter by connection URL (* wildcard or substring)"},
+				{Name: "opcode", Type: "number", Required: false, Description: "Filter by opcode: 1 text, 2 binary"},
+				{Name: "limit", Type: "number", Required: false, Description: "Maximum number of most recent frames to return"},
+			},
+		},
+		{
+			Name:        "browser_xhr_replay",
+			Description: "List recent XHR/fetch calls and replay one with modified variables using the page's cookies",
+			Category:    "Data",
+			Parameters: []ToolParameter{
+				{Name: "action", Type: "string", Required: true, Description: "Action: 'start', 'list', 'replay', or 'stop'"},
+				{Name: "url", Type: "string", Required: false, Description: "Filter listed requests by URL (* wildcard or substring)"},
+				{Name: "request_id", Type: "string", Required: false, Description: "Request ID to replay (required for 'replay')"},
+				{Name: "variables", Type: "object", Required: false, Description: "Variables to override (GraphQL variables, JSON body fields, or query parameters)"},
+			},
+		},
+		{
+			Name:        "browser_collect",
+			Description: "Collect deduplicated list items across infinite scroll or pagination",
+			Category:    "Data",
+			Parameters: []ToolParameter{
+				{Name: "item_selector", Type: "string", Required: true, Description: "CSS selector matching each item"},
+				{Name: "fields", Type: "array", Required: false, Description: "Fields per item: text, html, href, src, value, attribute name, or name=sub-selector[@attr]"},
+				{Name: "mode", Type: "string", Required: false, Description: "'scroll' (default) or 'next_page'"},
+				{Name: "next_selector", Type: "string", Required: false, Description: "Next-page button selector (required for 'next_page')"},
+				{Name: "dedupe_key", Type: "string", Required: false, Description: "Field used to deduplicate items"},
+				{Name: "max_items", Type: "number", Required: false, Description: "Maximum number of items (default: 500)"},
+				{Name: "max_duration", Type: "number", Required: false, Description: "Maximum duration in seconds (default: 120)"},
+				{Name: "max_idle_rounds", Type: "number", Required: false, Description: "Rounds without new items before stopping (default: 3)"},
+			},
+		},
+		{
+			Name:        "browser_inspect_element",
+			Description: "Inspect an element's attributes, computed styles, bounding box, visibility, interactability and ancestors",
+			Category:    "Debug",
+			Parameters: []ToolParameter{
+				{Name: "identifier", Type: "string", Required: true, Description: "Element identifier"},
+				{Name: "styles", Type: "array", Required: false, Description: "Additional computed style properties to return"},
+			},
+		},
+		{
+			Name:        "browser_audit",
+			Description: "Audit the current page for performance, SEO and accessibility and return scored JSON",
+			Category:    "Debug",
+			Parameters: []ToolParameter{
+				{Name: "categories", Type: "array", Required: false, Description: "Categories to audit: performance, seo, accessibility (default: all)"},
+			},
+		},
+		{
+			Name:        "browser_a11y_scan",
+			Description: "Scan the current page for WCAG accessibility violations with axe-core",
+			Category:    "Debug",
+			Parameters: []ToolParameter{
+				{Name: "tags", Type: "array", Required: false, Description: "Only run rules with these tags, e.g. wcag2a, wcag2aa, best-practice"},
+				{Name: "selector", Type: "string", Required: false, Description: "CSS selector limiting the scan to part of the page"},
+				{Name: "min_impact", Type: "string", Required: false, Description: "Minimum impact to report: minor, moderate, serious, critical"},
+				{Name: "max_nodes", Type: "number", Required: false, Description: "Maximum elements reported per violation (default: 10)"},
+			},
+		},
+		{
+			Name:        "browser_check_links",
+			Description: "Check links on the current page (or site section) and report HTTP status codes using the browser's session",
+			Category:    "Debug",
+			Parameters: []ToolParameter{
+				{Name: "selector", Type: "string", Required: false, Description: "CSS selector limiting the links to part of the page"},
+				{Name: "same_origin", Type: "boolean", Required: false, Description: "Only check same-origin links"},
+				{Name: "depth", Type: "number", Required: false, Description: "Levels of same-section pages to crawl (default: 0)"},
+				{Name: "section", Type: "string", Required: false, Description: "Path prefix of the section to crawl"},
+				{Name: "concurrency", Type: "number", Required: false, Description: "Concurrent requests (default: 5, max: 20)"},
+				{Name: "timeout", Type: "number", Required: false, Description: "Timeout per request in seconds (default: 15)"},
+				{Name: "max_links", Type: "number", Required: false, Description: "Maximum links to check (default: 200)"},
+				{Name: "only_broken", Type: "boolean", Required: false, Description: "Only return broken links"},
+			},
+		},
+		{
+			Name:        "browser_translate_page",
+			Description: "Translate the visible page text with the configured LLM and return bilingual segments, optionally overlaying translations in the page",
+			Category:    "Data",
+			Parameters: []ToolParameter{
+				{Name: "target_language", Type: "string", Required: true, Description: "Language to translate into"},
+				{Name: "source_language", Type: "string", Required: false, Description: "Source language (detected automatically when empty)"},
+				{Name: "selector", Type: "string", Required: false, Description: "CSS selector limiting translation to part of the page"},
+				{Name: "overlay", Type: "boolean", Required: false, Description: "Show translations in the page below the original text"},
+				{Name: "max_segments", Type: "number", Required: false, Description: "Maximum text segments to translate (default: 200)"},
+				{Name: "llm_config", Type: "string", Required: false, Description: "LLM config name (default: the default config)"},
+			},
+		},
+		{
+			Name:        "browser_read_image_text",
+			Description: "Read text from canvas, images or image-only pages with OCR (CAPTCHAs excluded)",
+			Category:    "Data",
+			Parameters: []ToolParameter{
+				{Name: "identifier", Type: "string", Required: false, Description: "Element to read (default: the viewport)"},
+				{Name: "full_page", Type: "boolean", Required: false, Description: "Read the full page when no identifier is given"},
+				{Name: "include_words", Type: "boolean", Required: false, Description: "Include word confidences and positions"},
+				{Name: "timeout", Type: "number", Required: false, Description: "Timeout in seconds for finding the element (default: 10)"},
+			},
+		},
+		{
+			Name:        "browser_click_at",
+			Description: "Click at a coordinate or the center of a screenshot region (canvas apps, vision-guided agents)",
+			Category:    "Interaction",
+			Parameters: []ToolParameter{
+				{Name: "x", Type: "number", Required: true, Description: "X coordinate (region left edge when width/height are given)"},
+				{Name: "y", Type: "number", Required: true, Description: "Y coordinate (region top edge when width/height are given)"},
+				{Name: "width", Type: "number", Required: false, Description: "Region width; clicks the region's center"},
+				{Name: "height", Type: "number", Required: false, Description: "Region height; clicks the region's center"},
+				{Name: "space", Type: "string", Required: false, Description: "Coordinate space: viewport (default), page, screenshot, fullpage_screenshot"},
+				{Name: "button", Type: "string", Required: false, Description: "Mouse button: left (default), right, middle"},
+				{Name: "click_count", Type: "number", Required: false, Description: "Number of clicks (default: 1)"},
+			},
+		},
+		{
+			Name:        "browser_drag_box",
+			Description: "Drag the mouse between two coordinates (selection boxes, panning maps, drawing on canvas)",
+			Category:    "Interaction",
+			Parameters: []ToolParameter{
+				{Name: "from_x", Type: "number", Required: true, Description: "Start X coordinate"},
+				{Name: "from_y", Type: "number", Required: true, Description: "Start Y coordinate"},
+				{Name: "to_x", Type: "number", Required: true, Description: "End X coordinate"},
+				{Name: "to_y", Type: "number", Required: true, Description: "End Y coordinate"},
+				{Name: "space", Type: "string", Required: false, Description: "Coordinate space: viewport (default), page, screenshot, fullpage_screenshot"},
+				{Name: "steps", Type: "number", Required: false, Description: "Intermediate mouse moves (default: 10)"},
+				{Name: "hold_ms", Type: "number", Required: false, Description: "Pause after pressing before moving (ms)"},
+			},
+		},
 		{
 			Name:        "browser_tabs",
 			Description: "Manage browser tabs (list, create, switch, close)",
@@ -1043,6 +2008,7 @@ func GetExecutorToolsMetadata() []ToolMetadata {
 			Parameters: []ToolParameter{
 				{Name: "action", Type: "string", Required: true, Description: "Action: 'list', 'new', 'switch', or 'close'"},
 				{Name: "url", Type: "string", Required: false, Description: "URL for new tab (when action='new')"},
+				{Name: "tab_id", Type: "string", Required: false, Description: "Stable tab ID for switch/close (preferred over index)"},
 				{Name: "index", Type: "number", Required: false, Description: "Tab index for switch/close (0-based)"},
 			},
 		},
@@ -1094,7 +2060,8 @@ func (r *MCPToolRegistry) registerTabsTool() error {
 		mcpgo.WithDescription("Manage browser tabs. Supports listing, creating, switching, and closing tabs."),
 		mcpgo.WithString("action", mcpgo.Required(), mcpgo.Description("Tab action: 'list', 'new', 'switch', or 'close'")),
 		mcpgo.WithString("url", mcpgo.Description("URL for new tab (required when action='new')")),
-		mcpgo.WithNumber("index", mcpgo.Description("Tab index for switch/close (0-based, required when action='switch' or 'close')")),
+		mcpgo.WithString("tab_id", mcpgo.Description("Stable tab ID from 'list' for switch/close (preferred over index)")),
+		mcpgo.WithNumber("index", mcpgo.Description("Tab index for switch/close (0-based, used when tab_id is not provided)")),
 	)
 
 	handler := func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
@@ -1110,14 +2077,17 @@ func (r *MCPToolRegistry) registerTabsTool() error {
 			opts.URL = url
 		}
 
-		// 处理 index 参数（action=switch 或 close 时需要）
+		// 处理 tab_id / index 参数（action=switch 或 close 时需要，优先使用 tab_id）
+		if tabID, ok := args["tab_id"].(string); ok {
+			opts.TabID = tabID
+		}
 		if indexFloat, ok := args["index"].(float64); ok {
 			opts.Index = int(indexFloat)
 		}
 
 		result, err := r.executor.Tabs(ctx, opts)
 		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
+			return r.toolError(ctx, request, err), nil
 		}
 
 		// 根据操作类型返回不同的响应
@@ -1131,7 +2101,7 @@ func (r *MCPToolRegistry) registerTabsTool() error {
 					if tab.Active {
 						activeIndicator = " (active)"
 					}
-					tabsText += fmt.Sprintf("\n[%d] %s - %s%s", tab.Index, tab.Title, tab.URL, activeIndicator)
+					tabsText += fmt.Sprintf("\n[%d] id=%s %s - %s%s", tab.Index, tab.ID, tab.Title, tab.URL, activeIndicator)
 				}
 				return mcpgo.NewToolResultText(fmt.Sprintf("%s\n\nTabs:%s", result.Message, tabsText)), nil
 			}
@@ -1139,12 +2109,12 @@ func (r *MCPToolRegistry) registerTabsTool() error {
 			// 创建新标签页
 			index := result.Data["index"]
 			url := result.Data["url"]
-			return mcpgo.NewToolResultText(fmt.Sprintf("%s\n\nTab Index: %v\nURL: %v", result.Message, index, url)), nil
+			return mcpgo.NewToolResultText(fmt.Sprintf("%s\n\nTab ID: %v\nTab Index: %v\nURL: %v", result.Message, result.Data["id"], index, url)), nil
 		case TabsActionSwitch:
 			// 切换标签页
 			index := result.Data["index"]
 			url := result.Data["url"]
-			return mcpgo.NewToolResultText(fmt.Sprintf("%s\n\nTab Index: %v\nURL: %v", result.Message, index, url)), nil
+			return mcpgo.NewToolResultText(fmt.Sprintf("%s\n\nTab ID: %v\nTab Index: %v\nURL: %v", result.Message, result.Data["id"], index, url)), nil
 		case TabsActionClose:
 			// 关闭标签页
 			return mcpgo.NewToolResultText(result.Message), nil
@@ -1172,7 +2142,7 @@ func (r *MCPToolRegistry) registerFillFormTool() error {
 
 		opts := &FillFormOptions{
 			Submit:  false,
-			Timeout: 10 * time.Second,
+			Timeout: r.executor.timeouts().Element(),
 		}
 
 		// 处理 fields 参数
@@ -1210,7 +2180,7 @@ func (r *MCPToolRegistry) registerFillFormTool() error {
 
 		result, err := r.executor.FillForm(ctx, opts)
 		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
+			return r.toolError(ctx, request, err), nil
 		}
 
 		// 构建详细的响应消息
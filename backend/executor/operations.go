@@ -2,16 +2,21 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/artifacts"
 	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/services/browser"
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/input"
 	"github.com/go-rod/rod/lib/proto"
+	"github.com/google/uuid"
 )
 
 // Navigate 导航到指定 URL
@@ -25,23 +30,31 @@ func (e *Executor) Navigate(ctx context.Context, url string, opts *NavigateOptio
 				Success:   false,
 				Error:     err.Error(),
 				Timestamp: time.Now(),
+				ErrorCode: errorCode(err),
 			}, err
 		}
 		logger.Info(ctx, "[Navigate] Browser started")
 	}
 	logger.Info(ctx, "[Navigate] Browser is running")
 
+	// 检查 URL 访问策略（实例策略 + 调用方策略）
+	if err := e.Browser.CheckURLPolicy(ctx, "", url); err != nil {
+		return &OperationResult{
+			Success:   false,
+			Error:     err.Error(),
+			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
+		}, err
+	}
+
 	if opts == nil {
-		opts = &NavigateOptions{
-			WaitUntil: "load",
-			Timeout:   60 * time.Second, // 增加默认超时到60秒
-		}
+		opts = e.defaultNavigateOptions()
 	}
 	logger.Info(ctx, "[Navigate] Using timeout: %v, wait_until: %s", opts.Timeout, opts.WaitUntil)
 
 	// 获取或创建页面
 	logger.Info(ctx, "[Navigate] Getting active page...")
-	page := e.Browser.GetActivePage()
+	page := e.activePage(ctx)
 	
 	// 检查 page 是否有效
 	needNewPage := false
@@ -66,19 +79,20 @@ func (e *Executor) Navigate(ctx context.Context, url string, opts *NavigateOptio
 	if needNewPage {
 		logger.Info(ctx, "[Navigate] Creating new page...")
 		// 通过 OpenPage 创建新页面（会自动导航）
-		// 使用当前实例（传空字符串），norecord=true
-		err := e.Browser.OpenPage(url, "", "", true)
+		// 使用 context 指定的实例（未指定时为当前实例），norecord=true
+		err := e.Browser.OpenPage(url, "", instanceIDFromContext(ctx), true)
 		if err != nil {
 			logger.Error(ctx, "[Navigate] Failed to open page: %s", err.Error())
 			return &OperationResult{
 				Success:   false,
 				Error:     err.Error(),
 				Timestamp: time.Now(),
+				ErrorCode: errorCode(err),
 			}, err
 		}
 		logger.Info(ctx, "[Navigate] Page opened successfully")
 
-		page = e.Browser.GetActivePage()
+		page = e.openedPage(ctx)
 		if page == nil {
 			logger.Error(ctx, "[Navigate] Failed to get active page after opening")
 			return &OperationResult{
@@ -102,21 +116,23 @@ func (e *Executor) Navigate(ctx context.Context, url string, opts *NavigateOptio
 			// 如果是 session 错误，尝试重新创建 page
 			if isSessionError(err) {
 				logger.Warn(ctx, "[Navigate] Session error detected, retrying with new page...")
-				err := e.Browser.OpenPage(url, "", "", true)
+				err := e.Browser.OpenPage(url, "", instanceIDFromContext(ctx), true)
 				if err != nil {
 					return &OperationResult{
 						Success:   false,
 						Error:     fmt.Sprintf("Navigation failed and retry failed: %s", err.Error()),
 						Timestamp: time.Now(),
+						ErrorCode: errorCode(err),
 					}, err
 				}
-				page = e.Browser.GetActivePage()
+				page = e.openedPage(ctx)
 				logger.Info(ctx, "[Navigate] Retry successful with new page")
 			} else {
 				return &OperationResult{
 					Success:   false,
 					Error:     err.Error(),
 					Timestamp: time.Now(),
+					ErrorCode: errorCode(err),
 				}, err
 			}
 		} else {
@@ -138,31 +154,51 @@ func (e *Executor) Navigate(ctx context.Context, url string, opts *NavigateOptio
 		logger.Info(ctx, "[Navigate] Page load completed")
 	}
 
+	// 识别验证码和反爬拦截页面，调用方可据此暂停、更换代理或告警
+	if block, err := browser.DetectBlockPage(ctx, page); err == nil && block != nil {
+		blockErr := block.Err()
+		logger.Warn(ctx, "[Navigate] %v", blockErr)
+		return &OperationResult{
+			Success:   false,
+			Error:     blockErr.Error(),
+			Timestamp: time.Now(),
+			ErrorCode: models.ErrorCodeCaptchaDetected,
+			Data: map[string]interface{}{
+				"url":   url,
+				"block": block,
+			},
+		}, blockErr
+	}
+
 	logger.Info(ctx, "[Navigate] Successfully navigated to %s", url)
 
-	// 获取页面语义树（带超时控制）
+	// 获取页面语义树（带超时控制），调用方可跳过或推迟
 	// 注意：这里同步调用，但用带超时的 context
 	var accessibilitySnapshotText string
 
-	logger.Info(ctx, "[Navigate] Starting semantic tree extraction...")
-	// 创建一个带超时的 context（10秒超时）
-	treeCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	// 直接调用，不使用 goroutine 避免资源竞争
-	snapshot, err := e.GetAccessibilitySnapshot(treeCtx)
-	if err != nil {
-		if err == context.DeadlineExceeded {
-			logger.Warn(ctx, "[Navigate] Accessibility snapshot extraction timed out after 10s")
-		} else if err != context.Canceled {
-			logger.Warn(ctx, "[Navigate] Failed to extract accessibility snapshot: %s", err.Error())
-		}
-		// 不影响导航成功，只是没有可访问性快照
-	} else if snapshot != nil {
-		accessibilitySnapshotText = snapshot.SerializeToSimpleText()
-		logger.Info(ctx, "[Navigate] Successfully extracted accessibility snapshot with %d elements", len(snapshot.Elements))
+	if opts.Snapshot != SnapshotInclude {
+		e.snapshotAfter(ctx, opts.Snapshot)
 	} else {
-		logger.Warn(ctx, "[Navigate] Accessibility snapshot is nil")
+		logger.Info(ctx, "[Navigate] Starting semantic tree extraction...")
+		// 创建一个带超时的 context（默认 10 秒，可在配置的 timeouts 中修改）
+		treeCtx, cancel := context.WithTimeout(context.Background(), e.timeouts().Snapshot())
+		defer cancel()
+
+		// 直接调用，不使用 goroutine 避免资源竞争
+		snapshot, err := e.GetAccessibilitySnapshot(treeCtx)
+		if err != nil {
+			if err == context.DeadlineExceeded {
+				logger.Warn(ctx, "[Navigate] Accessibility snapshot extraction timed out after %v", e.timeouts().Snapshot())
+			} else if err != context.Canceled {
+				logger.Warn(ctx, "[Navigate] Failed to extract accessibility snapshot: %s", err.Error())
+			}
+			// 不影响导航成功，只是没有可访问性快照
+		} else if snapshot != nil {
+			accessibilitySnapshotText = snapshot.SerializeToSimpleText()
+			logger.Info(ctx, "[Navigate] Successfully extracted accessibility snapshot with %d elements", len(snapshot.Elements))
+		} else {
+			logger.Warn(ctx, "[Navigate] Accessibility snapshot is nil")
+		}
 	}
 
 	result := &OperationResult{
@@ -184,20 +220,14 @@ func (e *Executor) Navigate(ctx context.Context, url string, opts *NavigateOptio
 
 // Click 点击元素
 func (e *Executor) Click(ctx context.Context, identifier string, opts *ClickOptions) (*OperationResult, error) {
-	page := e.Browser.GetActivePage()
+	page := e.activePage(ctx)
 	if page == nil {
 		logger.Error(ctx, "Failed to get active page")
 		return nil, fmt.Errorf("no active page")
 	}
 
 	if opts == nil {
-		opts = &ClickOptions{
-			WaitVisible: true,
-			WaitEnabled: true,
-			Timeout:     10 * time.Second,
-			Button:      "left",
-			ClickCount:  1,
-		}
+		opts = e.defaultClickOptions()
 	}
 
 	// 查找元素（带超时）
@@ -207,6 +237,7 @@ func (e *Executor) Click(ctx context.Context, identifier string, opts *ClickOpti
 			Success:   false,
 			Error:     fmt.Sprintf("Element not found: %s", identifier),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -227,6 +258,7 @@ func (e *Executor) Click(ctx context.Context, identifier string, opts *ClickOpti
 				Success:   false,
 				Error:     fmt.Sprintf("Element not visible: %s (timeout after %v)", identifier, opts.Timeout),
 				Timestamp: time.Now(),
+				ErrorCode: errorCode(err),
 			}, err
 		}
 	}
@@ -239,6 +271,7 @@ func (e *Executor) Click(ctx context.Context, identifier string, opts *ClickOpti
 				Success:   false,
 				Error:     fmt.Sprintf("Element not enabled: %s (timeout after %v)", identifier, opts.Timeout),
 				Timestamp: time.Now(),
+				ErrorCode: errorCode(err),
 			}, err
 		}
 	}
@@ -249,6 +282,7 @@ func (e *Executor) Click(ctx context.Context, identifier string, opts *ClickOpti
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to scroll to element: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -302,6 +336,7 @@ func (e *Executor) Click(ctx context.Context, identifier string, opts *ClickOpti
 				Success:   false,
 				Error:     fmt.Sprintf("Both enhanced JS and normal click failed: %s", err.Error()),
 				Timestamp: time.Now(),
+				ErrorCode: errorCode(err),
 			}, err
 		}
 		logger.Info(ctx, "[Click] Normal click succeeded")
@@ -309,15 +344,8 @@ func (e *Executor) Click(ctx context.Context, identifier string, opts *ClickOpti
 		logger.Info(ctx, "[Click] ✓ Enhanced JavaScript click succeeded: %s", identifier)
 	}
 
-	// 同时返回当前的页面可访问性快照
-	snapshot, err := e.GetAccessibilitySnapshot(ctx)
-	if err != nil {
-		logger.Error(ctx, "Failed to get accessibility snapshot: %s", err.Error())
-	}
-	var accessibilitySnapshotText string
-	if snapshot != nil {
-		accessibilitySnapshotText = snapshot.SerializeToSimpleText()
-	}
+	// 同时返回当前的页面可访问性快照（可跳过或推迟）
+	accessibilitySnapshotText := e.snapshotAfter(ctx, opts.Snapshot)
 
 	return &OperationResult{
 		Success:   true,
@@ -331,18 +359,13 @@ func (e *Executor) Click(ctx context.Context, identifier string, opts *ClickOpti
 
 // Type 在元素中输入文本
 func (e *Executor) Type(ctx context.Context, identifier string, text string, opts *TypeOptions) (*OperationResult, error) {
-	page := e.Browser.GetActivePage()
+	page := e.activePage(ctx)
 	if page == nil {
 		return nil, fmt.Errorf("no active page")
 	}
 
 	if opts == nil {
-		opts = &TypeOptions{
-			Clear:       true,
-			WaitVisible: true,
-			Timeout:     10 * time.Second,
-			Delay:       0,
-		}
+		opts = e.defaultTypeOptions()
 	}
 
 	// 查找元素（带超时）
@@ -352,6 +375,7 @@ func (e *Executor) Type(ctx context.Context, identifier string, text string, opt
 			Success:   false,
 			Error:     fmt.Sprintf("Element not found: %s", identifier),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -363,6 +387,7 @@ func (e *Executor) Type(ctx context.Context, identifier string, text string, opt
 				Success:   false,
 				Error:     fmt.Sprintf("Element not visible: %s (timeout after %v)", identifier, opts.Timeout),
 				Timestamp: time.Now(),
+				ErrorCode: errorCode(err),
 			}, err
 		}
 	}
@@ -373,6 +398,7 @@ func (e *Executor) Type(ctx context.Context, identifier string, text string, opt
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to focus element: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -384,7 +410,17 @@ func (e *Executor) Type(ctx context.Context, identifier string, text string, opt
 	}
 
 	// 输入文本
-	if opts.Delay > 0 {
+	if opts.IME {
+		// 通过输入法组合事件输入
+		if err := typeWithIME(ctx, page, text, opts.Delay); err != nil {
+			return &OperationResult{
+				Success:   false,
+				Error:     fmt.Sprintf("Failed to input text: %s", err.Error()),
+				Timestamp: time.Now(),
+				ErrorCode: errorCode(err),
+			}, err
+		}
+	} else if opts.Delay > 0 {
 		// 逐字符输入
 		for _, char := range text {
 			if err := elem.Input(string(char)); err != nil {
@@ -392,6 +428,7 @@ func (e *Executor) Type(ctx context.Context, identifier string, text string, opt
 					Success:   false,
 					Error:     fmt.Sprintf("Failed to input text: %s", err.Error()),
 					Timestamp: time.Now(),
+					ErrorCode: errorCode(err),
 				}, err
 			}
 			time.Sleep(opts.Delay)
@@ -403,19 +440,13 @@ func (e *Executor) Type(ctx context.Context, identifier string, text string, opt
 				Success:   false,
 				Error:     fmt.Sprintf("Failed to input text: %s", err.Error()),
 				Timestamp: time.Now(),
+				ErrorCode: errorCode(err),
 			}, err
 		}
 	}
 
-	// 同时返回当前的页面可访问性快照
-	snapshot, err := e.GetAccessibilitySnapshot(ctx)
-	if err != nil {
-		logger.Error(ctx, "Failed to get accessibility snapshot: %s", err.Error())
-	}
-	var accessibilitySnapshotText string
-	if snapshot != nil {
-		accessibilitySnapshotText = snapshot.SerializeToSimpleText()
-	}
+	// 同时返回当前的页面可访问性快照（可跳过或推迟）
+	accessibilitySnapshotText := e.snapshotAfter(ctx, opts.Snapshot)
 
 	return &OperationResult{
 		Success:   true,
@@ -430,16 +461,13 @@ func (e *Executor) Type(ctx context.Context, identifier string, text string, opt
 
 // Select 选择下拉框选项
 func (e *Executor) Select(ctx context.Context, identifier string, value string, opts *SelectOptions) (*OperationResult, error) {
-	page := e.Browser.GetActivePage()
+	page := e.activePage(ctx)
 	if page == nil {
 		return nil, fmt.Errorf("no active page")
 	}
 
 	if opts == nil {
-		opts = &SelectOptions{
-			WaitVisible: true,
-			Timeout:     10 * time.Second,
-		}
+		opts = e.defaultSelectOptions()
 	}
 
 	// 查找元素（带超时）
@@ -449,6 +477,7 @@ func (e *Executor) Select(ctx context.Context, identifier string, value string,
 			Success:   false,
 			Error:     fmt.Sprintf("Element not found: %s", identifier),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -460,6 +489,7 @@ func (e *Executor) Select(ctx context.Context, identifier string, value string,
 				Success:   false,
 				Error:     fmt.Sprintf("Element not visible: %s (timeout after %v)", identifier, opts.Timeout),
 				Timestamp: time.Now(),
+				ErrorCode: errorCode(err),
 			}, err
 		}
 	}
@@ -470,18 +500,12 @@ func (e *Executor) Select(ctx context.Context, identifier string, value string,
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to select option: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
-	// 同时返回当前的页面可访问性快照
-	snapshot, err := e.GetAccessibilitySnapshot(ctx)
-	if err != nil {
-		logger.Error(ctx, "Failed to get accessibility snapshot: %s", err.Error())
-	}
-	var accessibilitySnapshotText string
-	if snapshot != nil {
-		accessibilitySnapshotText = snapshot.SerializeToSimpleText()
-	}
+	// 同时返回当前的页面可访问性快照（可跳过或推迟）
+	accessibilitySnapshotText := e.snapshotAfter(ctx, opts.Snapshot)
 
 	return &OperationResult{
 		Success:   true,
@@ -496,18 +520,19 @@ func (e *Executor) Select(ctx context.Context, identifier string, value string,
 
 // GetText 获取元素文本
 func (e *Executor) GetText(ctx context.Context, identifier string) (*OperationResult, error) {
-	page := e.Browser.GetActivePage()
+	page := e.activePage(ctx)
 	if page == nil {
 		return nil, fmt.Errorf("no active page")
 	}
 
 	// 使用默认10秒超时
-	elem, err := e.findElementWithTimeout(ctx, page, identifier, 10*time.Second)
+	elem, err := e.findElementWithTimeout(ctx, page, identifier, e.timeouts().Element())
 	if err != nil {
 		return &OperationResult{
 			Success:   false,
 			Error:     fmt.Sprintf("Element not found: %s", identifier),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -517,6 +542,7 @@ func (e *Executor) GetText(ctx context.Context, identifier string) (*OperationRe
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to get text: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -532,18 +558,19 @@ func (e *Executor) GetText(ctx context.Context, identifier string) (*OperationRe
 
 // GetValue 获取元素值
 func (e *Executor) GetValue(ctx context.Context, identifier string) (*OperationResult, error) {
-	page := e.Browser.GetActivePage()
+	page := e.activePage(ctx)
 	if page == nil {
 		return nil, fmt.Errorf("no active page")
 	}
 
 	// 使用默认10秒超时
-	elem, err := e.findElementWithTimeout(ctx, page, identifier, 10*time.Second)
+	elem, err := e.findElementWithTimeout(ctx, page, identifier, e.timeouts().Element())
 	if err != nil {
 		return &OperationResult{
 			Success:   false,
 			Error:     fmt.Sprintf("Element not found: %s", identifier),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -553,6 +580,7 @@ func (e *Executor) GetValue(ctx context.Context, identifier string) (*OperationR
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to get value: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -566,9 +594,10 @@ func (e *Executor) GetValue(ctx context.Context, identifier string) (*OperationR
 	}, nil
 }
 
-// WaitFor 等待元素
+// WaitFor 等待元素或页面条件
+// 设置了 URL、网络空闲或 JS 条件时 identifier 可以为空；多个条件全部满足才算成功
 func (e *Executor) WaitFor(ctx context.Context, identifier string, opts *WaitForOptions) (*OperationResult, error) {
-	page := e.Browser.GetActivePage()
+	page := e.activePage(ctx)
 	if page == nil {
 		return nil, fmt.Errorf("no active page")
 	}
@@ -579,18 +608,95 @@ func (e *Executor) WaitFor(ctx context.Context, identifier string, opts *WaitFor
 			State:   "visible",
 		}
 	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 30 * time.Second
+	}
 
-	// 查找元素（带超时）
-	elem, err := e.findElementWithTimeout(ctx, page, identifier, opts.Timeout)
-	if err != nil {
+	if identifier == "" && !opts.hasPageConditions() {
+		return nil, fmt.Errorf("identifier is required unless url, network_idle or predicate is set")
+	}
+	if identifier == "" && opts.Text != "" {
+		return nil, fmt.Errorf("identifier is required when waiting for text")
+	}
+	if opts.Predicate != "" {
+		if err := checkEvaluatePolicy(e.Browser.GetSecurityConfig().EvaluatePolicy(), opts.Predicate); err != nil {
+			logger.Warn(ctx, "Wait predicate rejected by sandbox policy: %v", err)
+			return &OperationResult{
+				Success:   false,
+				Error:     err.Error(),
+				Timestamp: time.Now(),
+				ErrorCode: errorCode(err),
+			}, err
+		}
+	}
+
+	deadline := time.Now().Add(opts.Timeout)
+	fail := func(err error) (*OperationResult, error) {
 		return &OperationResult{
 			Success:   false,
-			Error:     fmt.Sprintf("Element not found: %s", identifier),
+			Error:     fmt.Sprintf("Wait failed: %s (timeout after %v)", err.Error(), opts.Timeout),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
-	elem = elem.Timeout(opts.Timeout)
+	if identifier != "" {
+		if err := e.waitForElementState(ctx, page, identifier, opts, deadline); err != nil {
+			return fail(err)
+		}
+	}
+
+	if opts.URL != "" {
+		if err := waitForURL(ctx, page, opts.URL, deadline); err != nil {
+			return fail(err)
+		}
+	}
+
+	if opts.Predicate != "" {
+		if err := waitForPredicate(ctx, page, opts.Predicate, deadline); err != nil {
+			return fail(err)
+		}
+	}
+
+	if opts.NetworkIdle {
+		idleTime := opts.IdleTime
+		if idleTime <= 0 {
+			idleTime = 500 * time.Millisecond
+		}
+		if err := waitForNetworkIdle(ctx, page, idleTime, opts.MaxInflight, deadline); err != nil {
+			return fail(err)
+		}
+	}
+
+	message := "Successfully waited for conditions"
+	if identifier != "" {
+		message = fmt.Sprintf("Successfully waited for element: %s", identifier)
+	}
+	return &OperationResult{
+		Success:   true,
+		Message:   message,
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// waitForElementState 等待元素达到指定状态，并可选地等待其文本包含指定内容
+func (e *Executor) waitForElementState(ctx context.Context, page *rod.Page, identifier string, opts *WaitForOptions, deadline time.Time) error {
+	lookupTimeout := time.Until(deadline)
+	if opts.State == "hidden" && opts.Text == "" && lookupTimeout > 2*time.Second {
+		// 等待消失时元素可能已经不存在，只做短暂查找
+		lookupTimeout = 2 * time.Second
+	}
+
+	// 查找元素（带超时）
+	elem, err := e.findElementWithTimeout(ctx, page, identifier, lookupTimeout)
+	if err != nil {
+		if opts.State == "hidden" && opts.Text == "" {
+			return nil
+		}
+		return elementNotFound("element not found: %s", identifier)
+	}
+
+	elem = elem.Timeout(time.Until(deadline))
 
 	switch opts.State {
 	case "visible":
@@ -602,25 +708,19 @@ func (e *Executor) WaitFor(ctx context.Context, identifier string, opts *WaitFor
 	default:
 		err = elem.WaitLoad()
 	}
-
 	if err != nil {
-		return &OperationResult{
-			Success:   false,
-			Error:     fmt.Sprintf("Wait failed for state '%s': %s (timeout after %v)", opts.State, err.Error(), opts.Timeout),
-			Timestamp: time.Now(),
-		}, err
+		return fmt.Errorf("state '%s' not reached: %w", opts.State, err)
 	}
 
-	return &OperationResult{
-		Success:   true,
-		Message:   fmt.Sprintf("Successfully waited for element: %s", identifier),
-		Timestamp: time.Now(),
-	}, nil
+	if opts.Text != "" {
+		return waitForText(ctx, elem.CancelTimeout(), opts.Text, deadline)
+	}
+	return nil
 }
 
 // Extract 提取数据
 func (e *Executor) Extract(ctx context.Context, opts *ExtractOptions) (*OperationResult, error) {
-	page := e.Browser.GetActivePage()
+	page := e.activePage(ctx)
 	if page == nil {
 		return nil, fmt.Errorf("no active page")
 	}
@@ -636,24 +736,16 @@ func (e *Executor) Extract(ctx context.Context, opts *ExtractOptions) (*Operatio
 	var result interface{}
 
 	if opts.Multiple {
-		// 提取多个元素
-		elements, err := page.Elements(opts.Selector)
+		// 提取多个元素，所有元素的字段在一次调用中取回
+		results, err := extractAll(ctx, page, opts)
 		if err != nil {
 			return &OperationResult{
 				Success:   false,
 				Error:     fmt.Sprintf("Failed to find elements: %s", err.Error()),
 				Timestamp: time.Now(),
+				ErrorCode: errorCode(err),
 			}, err
 		}
-
-		results := make([]map[string]interface{}, 0, len(elements))
-		for _, elem := range elements {
-			data, err := e.extractElementData(elem, opts)
-			if err != nil {
-				continue
-			}
-			results = append(results, data)
-		}
 		result = results
 	} else {
 		// 提取单个元素
@@ -663,6 +755,7 @@ func (e *Executor) Extract(ctx context.Context, opts *ExtractOptions) (*Operatio
 				Success:   false,
 				Error:     fmt.Sprintf("Failed to find element: %s", err.Error()),
 				Timestamp: time.Now(),
+				ErrorCode: errorCode(err),
 			}, err
 		}
 
@@ -672,6 +765,7 @@ func (e *Executor) Extract(ctx context.Context, opts *ExtractOptions) (*Operatio
 				Success:   false,
 				Error:     fmt.Sprintf("Failed to extract data: %s", err.Error()),
 				Timestamp: time.Now(),
+				ErrorCode: errorCode(err),
 			}, err
 		}
 		result = data
@@ -766,7 +860,7 @@ func (e *Executor) extractElementData(elem *rod.Element, opts *ExtractOptions) (
 
 // findElement 查找元素（支持多种方式），带超时支持
 func (e *Executor) findElement(ctx context.Context, page *rod.Page, identifier string) (*rod.Element, error) {
-	return e.findElementWithTimeout(ctx, page, identifier, 10*time.Second)
+	return e.findElementWithTimeout(ctx, page, identifier, e.timeouts().Element())
 }
 
 // findElementWithTimeout 查找元素（支持多种方式），带自定义超时
@@ -788,11 +882,16 @@ func (e *Executor) findElementWithTimeout(ctx context.Context, page *rod.Page, i
 	}
 
 	// 0. 尝试 RefID 格式：@e1, @e2, e1, e2（优先级最高，最稳定）
-	if strings.HasPrefix(identifier, "@") || (len(identifier) > 0 && identifier[0] == 'e' && len(identifier) <= 10) {
-		refID := strings.TrimPrefix(identifier, "@")
-		if elem, err := e.findElementByRefID(ctx, page, refID); err == nil && elem != nil {
+	if refID := strings.TrimPrefix(identifier, "@"); isRefID(refID) {
+		elem, err := e.findElementByRefID(ctx, page, refID)
+		if err == nil && elem != nil {
 			return elem, nil
 		}
+		// RefID 失效时直接返回带新映射的错误，无需再按选择器或文本查找
+		var staleErr *StaleRefError
+		if errors.As(err, &staleErr) {
+			return nil, err
+		}
 	}
 
 	// 1. 尝试作为 CSS 选择器
@@ -867,7 +966,7 @@ func (e *Executor) findElementWithTimeout(ctx context.Context, page *rod.Page, i
 		return elem, nil
 	}
 
-	return nil, fmt.Errorf("element not found: %s (timeout after %v)", identifier, timeout)
+	return nil, elementNotFound("element not found: %s (timeout after %v)", identifier, timeout)
 }
 
 // findElementByRefID 通过 RefID 查找元素（如 e1, e2, e3）
@@ -876,14 +975,13 @@ func (e *Executor) findElementByRefID(ctx context.Context, page *rod.Page, refID
 	logger.Info(ctx, "[findElementByRefID] Looking up refID: %s", refID)
 	
 	// 查找 RefID 对应的定位器数据
-	e.refIDMutex.RLock()
-	refData, found := e.refIDMap[refID]
-	cacheAge := time.Since(e.refIDTimestamp)
-	e.refIDMutex.RUnlock()
+	refData, info, found := e.lookupRef(ctx, page, refID)
+	cacheAge := info.age
 	
-	if !found {
-		logger.Warn(ctx, "[findElementByRefID] RefID %s not found in cache (age: %v)", refID, cacheAge)
-		return nil, fmt.Errorf("refID %s not found (cache may be stale, run browser_snapshot first)", refID)
+	// 缓存缺失、页面已导航或 RefID 不存在时自动刷新快照，并在错误中返回新的映射
+	if reason := e.staleRefReason(page, info, found); reason != "" {
+		logger.Warn(ctx, "[findElementByRefID] RefID %s unusable (age: %v): %s", refID, cacheAge, reason)
+		return nil, e.refreshStaleRef(ctx, refID, reason)
 	}
 	
 	logger.Info(ctx, "[findElementByRefID] Found refData for %s: role=%s, name=%s, backendID=%d, href=%s (cache age: %v)", 
@@ -948,7 +1046,7 @@ func (e *Executor) findElementByRefID(ctx context.Context, page *rod.Page, refID
 	if len(elements) == 0 {
 		logger.Warn(ctx, "[findElementByRefID] No elements found for refID %s (role=%s, name=%s) even after fallback", 
 			refID, refData.Role, refData.Name)
-		return nil, fmt.Errorf("element not found for refID %s (page may have changed, run browser_snapshot again)", refID)
+		return nil, e.refreshStaleRef(ctx, refID, "the element is no longer on the page")
 	}
 	
 	logger.Info(ctx, "[findElementByRefID] Found %d matching elements, selecting nth=%d", len(elements), refData.Nth)
@@ -956,7 +1054,7 @@ func (e *Executor) findElementByRefID(ctx context.Context, page *rod.Page, refID
 	// 选择第 nth 个匹配的元素
 	if refData.Nth >= len(elements) {
 		logger.Error(ctx, "[findElementByRefID] nth=%d out of range (only found %d elements)", refData.Nth, len(elements))
-		return nil, fmt.Errorf("refID %s: nth=%d out of range (found %d elements)", refID, refData.Nth, len(elements))
+		return nil, e.refreshStaleRef(ctx, refID, fmt.Sprintf("nth=%d out of range, found %d matching elements", refData.Nth, len(elements)))
 	}
 	
 	elem := elements[refData.Nth]
@@ -1067,7 +1165,7 @@ func min(a, b int) int {
 
 // Hover 鼠标悬停
 func (e *Executor) Hover(ctx context.Context, identifier string, opts *HoverOptions) (*OperationResult, error) {
-	page := e.Browser.GetActivePage()
+	page := e.activePage(ctx)
 	if page == nil {
 		return nil, fmt.Errorf("no active page")
 	}
@@ -1075,7 +1173,7 @@ func (e *Executor) Hover(ctx context.Context, identifier string, opts *HoverOpti
 	if opts == nil {
 		opts = &HoverOptions{
 			WaitVisible: true,
-			Timeout:     10 * time.Second,
+			Timeout:     e.timeouts().Element(),
 		}
 	}
 
@@ -1086,6 +1184,7 @@ func (e *Executor) Hover(ctx context.Context, identifier string, opts *HoverOpti
 			Success:   false,
 			Error:     fmt.Sprintf("Element not found: %s", identifier),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -1097,6 +1196,7 @@ func (e *Executor) Hover(ctx context.Context, identifier string, opts *HoverOpti
 				Success:   false,
 				Error:     fmt.Sprintf("Element not visible: %s", identifier),
 				Timestamp: time.Now(),
+				ErrorCode: errorCode(err),
 			}, err
 		}
 	}
@@ -1107,6 +1207,7 @@ func (e *Executor) Hover(ctx context.Context, identifier string, opts *HoverOpti
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to hover: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -1119,7 +1220,7 @@ func (e *Executor) Hover(ctx context.Context, identifier string, opts *HoverOpti
 
 // ScrollToBottom 滚动到页面底部
 func (e *Executor) ScrollToBottom(ctx context.Context) (*OperationResult, error) {
-	page := e.Browser.GetActivePage()
+	page := e.activePage(ctx)
 	if page == nil {
 		return nil, fmt.Errorf("no active page")
 	}
@@ -1131,6 +1232,7 @@ func (e *Executor) ScrollToBottom(ctx context.Context) (*OperationResult, error)
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to scroll: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -1143,7 +1245,7 @@ func (e *Executor) ScrollToBottom(ctx context.Context) (*OperationResult, error)
 
 // GoBack 后退
 func (e *Executor) GoBack(ctx context.Context) (*OperationResult, error) {
-	page := e.Browser.GetActivePage()
+	page := e.activePage(ctx)
 	if page == nil {
 		return nil, fmt.Errorf("no active page")
 	}
@@ -1153,6 +1255,7 @@ func (e *Executor) GoBack(ctx context.Context) (*OperationResult, error) {
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to go back: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -1165,7 +1268,7 @@ func (e *Executor) GoBack(ctx context.Context) (*OperationResult, error) {
 
 // GoForward 前进
 func (e *Executor) GoForward(ctx context.Context) (*OperationResult, error) {
-	page := e.Browser.GetActivePage()
+	page := e.activePage(ctx)
 	if page == nil {
 		return nil, fmt.Errorf("no active page")
 	}
@@ -1175,6 +1278,7 @@ func (e *Executor) GoForward(ctx context.Context) (*OperationResult, error) {
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to go forward: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -1187,7 +1291,7 @@ func (e *Executor) GoForward(ctx context.Context) (*OperationResult, error) {
 
 // Reload 刷新页面
 func (e *Executor) Reload(ctx context.Context) (*OperationResult, error) {
-	page := e.Browser.GetActivePage()
+	page := e.activePage(ctx)
 	if page == nil {
 		return nil, fmt.Errorf("no active page")
 	}
@@ -1197,6 +1301,7 @@ func (e *Executor) Reload(ctx context.Context) (*OperationResult, error) {
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to reload: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -1209,7 +1314,7 @@ func (e *Executor) Reload(ctx context.Context) (*OperationResult, error) {
 
 // Screenshot 截图
 func (e *Executor) Screenshot(ctx context.Context, opts *ScreenshotOptions) (*OperationResult, error) {
-	page := e.Browser.GetActivePage()
+	page := e.activePage(ctx)
 	if page == nil {
 		return nil, fmt.Errorf("no active page")
 	}
@@ -1249,11 +1354,12 @@ func (e *Executor) Screenshot(ctx context.Context, opts *ScreenshotOptions) (*Op
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to take screenshot: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
 	// 保存截图到文件
-	screenshotPath, saveErr := e.saveScreenshot(ctx, data, opts.Format)
+	screenshotPath, saveErr := e.saveScreenshot(ctx, data, opts.Format, opts.FileName)
 	if saveErr != nil {
 		logger.Warn(ctx, "Failed to save screenshot to file: %v", saveErr)
 	}
@@ -1284,7 +1390,17 @@ func (e *Executor) Screenshot(ctx context.Context, opts *ScreenshotOptions) (*Op
 
 // Evaluate 执行 JavaScript 代码
 func (e *Executor) Evaluate(ctx context.Context, script string) (*OperationResult, error) {
-	page := e.Browser.GetActivePage()
+	if err := checkEvaluatePolicy(e.Browser.GetSecurityConfig().EvaluatePolicy(), script); err != nil {
+		logger.Warn(ctx, "Evaluate rejected by sandbox policy: %v", err)
+		return &OperationResult{
+			Success:   false,
+			Error:     err.Error(),
+			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
+		}, err
+	}
+
+	page := e.activePage(ctx)
 	if page == nil {
 		return nil, fmt.Errorf("no active page")
 	}
@@ -1297,6 +1413,7 @@ func (e *Executor) Evaluate(ctx context.Context, script string) (*OperationResul
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to execute script: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -1312,7 +1429,7 @@ func (e *Executor) Evaluate(ctx context.Context, script string) (*OperationResul
 
 // PressKey 按键
 func (e *Executor) PressKey(ctx context.Context, key string, opts *PressKeyOptions) (*OperationResult, error) {
-	page := e.Browser.GetActivePage()
+	page := e.activePage(ctx)
 	if page == nil {
 		return nil, fmt.Errorf("no active page")
 	}
@@ -1321,75 +1438,13 @@ func (e *Executor) PressKey(ctx context.Context, key string, opts *PressKeyOptio
 		opts = &PressKeyOptions{}
 	}
 
-	// 创建 keyboard
-	keyboard := page.Keyboard
-
-	// 按下修饰键
-	if opts.Ctrl {
-		keyboard.Press(input.ControlLeft)
-		defer keyboard.Release(input.ControlLeft)
-	}
-	if opts.Shift {
-		keyboard.Press(input.ShiftLeft)
-		defer keyboard.Release(input.ShiftLeft)
-	}
-	if opts.Alt {
-		keyboard.Press(input.AltLeft)
-		defer keyboard.Release(input.AltLeft)
-	}
-	if opts.Meta {
-		keyboard.Press(input.MetaLeft)
-		defer keyboard.Release(input.MetaLeft)
-	}
-
-	// 按下并释放目标键
-	var keyCode input.Key
-	switch strings.ToLower(key) {
-	case "enter", "return":
-		keyCode = input.Enter
-	case "tab":
-		keyCode = input.Tab
-	case "escape", "esc":
-		keyCode = input.Escape
-	case "backspace":
-		keyCode = input.Backspace
-	case "delete":
-		keyCode = input.Delete
-	case "arrowup", "up":
-		keyCode = input.ArrowUp
-	case "arrowdown", "down":
-		keyCode = input.ArrowDown
-	case "arrowleft", "left":
-		keyCode = input.ArrowLeft
-	case "arrowright", "right":
-		keyCode = input.ArrowRight
-	case "home":
-		keyCode = input.Home
-	case "end":
-		keyCode = input.End
-	case "pageup":
-		keyCode = input.PageUp
-	case "pagedown":
-		keyCode = input.PageDown
-	case "space":
-		keyCode = input.Space
-	default:
-		// 单个字符
-		if len(key) == 1 {
-			err := keyboard.Type(input.Key(key[0]))
-			if err != nil {
-				return &OperationResult{
-					Success:   false,
-					Error:     fmt.Sprintf("Failed to press key: %s", err.Error()),
-					Timestamp: time.Now(),
-				}, err
-			}
-			return &OperationResult{
-				Success:   true,
-				Message:   fmt.Sprintf("Successfully pressed key: %s", key),
-				Timestamp: time.Now(),
-			}, nil
-		}
+	// 组合键或按键序列（如 "ctrl+k then p"）
+	if isKeySequence(key) {
+		return e.pressKeySequence(ctx, page, key, opts)
+	}
+
+	keyCode, ok := lookupKey(key)
+	if !ok {
 		return &OperationResult{
 			Success:   false,
 			Error:     fmt.Sprintf("Unknown key: %s", key),
@@ -1397,25 +1452,39 @@ func (e *Executor) PressKey(ctx context.Context, key string, opts *PressKeyOptio
 		}, fmt.Errorf("unknown key: %s", key)
 	}
 
-	if err := keyboard.Press(keyCode); err != nil {
+	chord := KeyChord{
+		Ctrl:  opts.Ctrl,
+		Shift: opts.Shift,
+		Alt:   opts.Alt,
+		Meta:  opts.Meta,
+		Mod:   opts.Mod,
+		Key:   key,
+		code:  keyCode,
+	}
+	if chord.Mod {
+		chord = chord.resolve(isMacPlatform(page))
+	}
+
+	// 按住修饰键，按下并释放目标键
+	if err := pressChord(page.Keyboard, chord); err != nil {
 		return &OperationResult{
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to press key: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
-	keyboard.Release(keyCode)
 
 	return &OperationResult{
 		Success:   true,
-		Message:   fmt.Sprintf("Successfully pressed key: %s", key),
+		Message:   fmt.Sprintf("Successfully pressed key: %s", chord),
 		Timestamp: time.Now(),
 	}, nil
 }
 
 // Resize 调整浏览器窗口大小
 func (e *Executor) Resize(ctx context.Context, width, height int) (*OperationResult, error) {
-	page := e.Browser.GetActivePage()
+	page := e.activePage(ctx)
 	if page == nil {
 		return nil, fmt.Errorf("no active page")
 	}
@@ -1429,6 +1498,7 @@ func (e *Executor) Resize(ctx context.Context, width, height int) (*OperationRes
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to resize window: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -1441,7 +1511,7 @@ func (e *Executor) Resize(ctx context.Context, width, height int) (*OperationRes
 
 // GetConsoleMessages 获取控制台消息
 func (e *Executor) GetConsoleMessages(ctx context.Context) (*OperationResult, error) {
-	page := e.Browser.GetActivePage()
+	page := e.activePage(ctx)
 	if page == nil {
 		return nil, fmt.Errorf("no active page")
 	}
@@ -1449,8 +1519,9 @@ func (e *Executor) GetConsoleMessages(ctx context.Context) (*OperationResult, er
 	// 收集控制台消息
 	messages := []map[string]interface{}{}
 
-	// 监听控制台事件
-	go page.EachEvent(func(e *proto.RuntimeConsoleAPICalled) {
+	// 监听控制台事件，收集结束后停止监听
+	watchCtx, cancel := context.WithCancel(ctx)
+	wait := page.Context(watchCtx).EachEvent(func(e *proto.RuntimeConsoleAPICalled) {
 		msg := map[string]interface{}{
 			"type":      e.Type,
 			"timestamp": time.Now().Format(time.RFC3339),
@@ -1462,10 +1533,17 @@ func (e *Executor) GetConsoleMessages(ctx context.Context) (*OperationResult, er
 			}
 		}
 		messages = append(messages, msg)
-	})()
+	})
+	done := make(chan struct{})
+	go func() {
+		wait()
+		close(done)
+	}()
 
 	// 等待一小段时间以收集消息
 	time.Sleep(100 * time.Millisecond)
+	cancel()
+	<-done
 
 	return &OperationResult{
 		Success:   true,
@@ -1479,24 +1557,37 @@ func (e *Executor) GetConsoleMessages(ctx context.Context) (*OperationResult, er
 
 // HandleDialog 处理对话框（alert, confirm, prompt）
 func (e *Executor) HandleDialog(ctx context.Context, accept bool, text string) (*OperationResult, error) {
-	page := e.Browser.GetActivePage()
+	page := e.activePage(ctx)
 	if page == nil {
 		return nil, fmt.Errorf("no active page")
 	}
 
-	// 设置对话框处理器
-	go page.EachEvent(func(e *proto.PageJavascriptDialogOpening) {
+	// 设置对话框处理器，通过实例的事件总线接收对话框事件，同一标签页只保留最后一次设置的处理方式
+	events := e.Browser.PageEvents(page)
+	if events == nil {
+		return nil, fmt.Errorf("page does not belong to a running browser instance")
+	}
+	tabID := page.TargetID
+	unsubscribe := events.Subscribe(func(ev browser.BrowserEvent) {
+		if ev.TargetID != tabID && ev.SessionID != page.SessionID {
+			return
+		}
+		if ev.Type == browser.EventTargetDestroyed {
+			e.stopDialogHandler(tabID)
+			return
+		}
 		if accept {
-			proto.PageHandleJavaScriptDialog{
+			_ = proto.PageHandleJavaScriptDialog{
 				Accept:     true,
 				PromptText: text,
 			}.Call(page)
 		} else {
-			proto.PageHandleJavaScriptDialog{
+			_ = proto.PageHandleJavaScriptDialog{
 				Accept: false,
 			}.Call(page)
 		}
-	})()
+	}, browser.EventDialogOpening, browser.EventTargetDestroyed)
+	e.setDialogHandler(tabID, unsubscribe)
 
 	return &OperationResult{
 		Success:   true,
@@ -1505,28 +1596,61 @@ func (e *Executor) HandleDialog(ctx context.Context, accept bool, text string) (
 	}, nil
 }
 
+// setDialogHandler 记录标签页的对话框处理订阅，替换之前的设置
+func (e *Executor) setDialogHandler(tabID proto.TargetTargetID, unsubscribe func()) {
+	e.tabMutex.Lock()
+	if e.dialogHandlers == nil {
+		e.dialogHandlers = make(map[proto.TargetTargetID]func())
+	}
+	previous := e.dialogHandlers[tabID]
+	e.dialogHandlers[tabID] = unsubscribe
+	e.tabMutex.Unlock()
+
+	if previous != nil {
+		previous()
+	}
+}
+
+// stopDialogHandler 取消标签页的对话框处理订阅
+func (e *Executor) stopDialogHandler(tabID proto.TargetTargetID) {
+	e.tabMutex.Lock()
+	unsubscribe := e.dialogHandlers[tabID]
+	delete(e.dialogHandlers, tabID)
+	e.tabMutex.Unlock()
+
+	if unsubscribe != nil {
+		unsubscribe()
+	}
+}
+
 // FileUpload 上传文件
 func (e *Executor) FileUpload(ctx context.Context, identifier string, filePaths []string) (*OperationResult, error) {
-	page := e.Browser.GetActivePage()
+	page := e.activePage(ctx)
 	if page == nil {
 		return nil, fmt.Errorf("no active page")
 	}
 
-	elem, err := e.findElementWithTimeout(ctx, page, identifier, 10*time.Second)
+	elem, err := e.findElementWithTimeout(ctx, page, identifier, e.timeouts().Element())
 	if err != nil {
 		return &OperationResult{
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to find element: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
-	err = elem.SetFiles(filePaths)
+	// 通过上传接口保存的文件以句柄（upload:<id>）引用
+	localPaths, err := e.Browser.ResolveUploadPaths(filePaths)
+	if err == nil {
+		err = elem.SetFiles(localPaths)
+	}
 	if err != nil {
 		return &OperationResult{
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to upload files: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -1538,27 +1662,36 @@ func (e *Executor) FileUpload(ctx context.Context, identifier string, filePaths
 }
 
 // Drag 拖拽元素
-func (e *Executor) Drag(ctx context.Context, fromIdentifier, toIdentifier string) (*OperationResult, error) {
-	page := e.Browser.GetActivePage()
+func (e *Executor) Drag(ctx context.Context, fromIdentifier, toIdentifier string, opts *DragOptions) (*OperationResult, error) {
+	page := e.activePage(ctx)
 	if page == nil {
 		return nil, fmt.Errorf("no active page")
 	}
 
-	fromElem, err := e.findElementWithTimeout(ctx, page, fromIdentifier, 10*time.Second)
+	if opts != nil && opts.Mode == DragModeHTML5 {
+		return e.dragHTML5(ctx, page, fromIdentifier, toIdentifier, opts)
+	}
+	if opts != nil && opts.Mode != "" && opts.Mode != DragModeMouse {
+		return nil, fmt.Errorf("unknown drag mode: %s", opts.Mode)
+	}
+
+	fromElem, err := e.findElementWithTimeout(ctx, page, fromIdentifier, e.timeouts().Element())
 	if err != nil {
 		return &OperationResult{
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to find source element: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
-	toElem, err := e.findElementWithTimeout(ctx, page, toIdentifier, 10*time.Second)
+	toElem, err := e.findElementWithTimeout(ctx, page, toIdentifier, e.timeouts().Element())
 	if err != nil {
 		return &OperationResult{
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to find target element: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -1569,6 +1702,7 @@ func (e *Executor) Drag(ctx context.Context, fromIdentifier, toIdentifier string
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to get source element shape: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -1578,6 +1712,7 @@ func (e *Executor) Drag(ctx context.Context, fromIdentifier, toIdentifier string
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to get target element shape: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -1600,6 +1735,7 @@ func (e *Executor) Drag(ctx context.Context, fromIdentifier, toIdentifier string
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to move to source: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -1609,6 +1745,7 @@ func (e *Executor) Drag(ctx context.Context, fromIdentifier, toIdentifier string
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to mouse down: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -1618,6 +1755,7 @@ func (e *Executor) Drag(ctx context.Context, fromIdentifier, toIdentifier string
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to move to target: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -1627,6 +1765,7 @@ func (e *Executor) Drag(ctx context.Context, fromIdentifier, toIdentifier string
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to mouse up: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -1639,7 +1778,7 @@ func (e *Executor) Drag(ctx context.Context, fromIdentifier, toIdentifier string
 
 // ClosePage 关闭当前页面
 func (e *Executor) ClosePage(ctx context.Context) (*OperationResult, error) {
-	page := e.Browser.GetActivePage()
+	page := e.activePage(ctx)
 	if page == nil {
 		return nil, fmt.Errorf("no active page")
 	}
@@ -1650,6 +1789,7 @@ func (e *Executor) ClosePage(ctx context.Context) (*OperationResult, error) {
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to close page: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -1662,7 +1802,7 @@ func (e *Executor) ClosePage(ctx context.Context) (*OperationResult, error) {
 
 // GetNetworkRequests 获取网络请求（需要先启用网络监控）
 func (e *Executor) GetNetworkRequests(ctx context.Context) (*OperationResult, error) {
-	page := e.Browser.GetActivePage()
+	page := e.activePage(ctx)
 	if page == nil {
 		return nil, fmt.Errorf("no active page")
 	}
@@ -1674,13 +1814,15 @@ func (e *Executor) GetNetworkRequests(ctx context.Context) (*OperationResult, er
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to enable network monitoring: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
 	requests := []map[string]interface{}{}
 
-	// 监听网络请求
-	go page.EachEvent(func(e *proto.NetworkRequestWillBeSent) {
+	// 监听网络请求，收集结束后停止监听
+	watchCtx, cancel := context.WithCancel(ctx)
+	wait := page.Context(watchCtx).EachEvent(func(e *proto.NetworkRequestWillBeSent) {
 		req := map[string]interface{}{
 			"url":       e.Request.URL,
 			"method":    e.Request.Method,
@@ -1688,10 +1830,17 @@ func (e *Executor) GetNetworkRequests(ctx context.Context) (*OperationResult, er
 			"type":      e.Type,
 		}
 		requests = append(requests, req)
-	})()
+	})
+	done := make(chan struct{})
+	go func() {
+		wait()
+		close(done)
+	}()
 
 	// 等待一段时间收集请求
 	time.Sleep(100 * time.Millisecond)
+	cancel()
+	<-done
 
 	return &OperationResult{
 		Success:   true,
@@ -1813,12 +1962,14 @@ const (
 type TabsOptions struct {
 	Action TabsAction // 操作类型：list, new, switch, close
 	URL    string     // 新建标签页时的 URL（action=new 时必需）
-	Index  int        // 标签页索引（action=switch 或 close 时使用，0-based）
+	TabID  string     // 标签页 ID（action=switch 或 close 时优先使用，不随标签页开关变化）
+	Index  int        // 标签页索引（action=switch 或 close 时使用，0-based，未指定 TabID 时生效）
 }
 
 // TabInfo 标签页信息
 type TabInfo struct {
-	Index  int    `json:"index"`   // 标签页索引（0-based）
+	ID     string `json:"id"`      // 标签页 ID（稳定，基于 TargetID）
+	Index  int    `json:"index"`   // 标签页索引（0-based，随标签页开关变化）
 	Title  string `json:"title"`   // 页面标题
 	URL    string `json:"url"`     // 页面 URL
 	Active bool   `json:"active"`  // 是否为当前活动标签页
@@ -1827,7 +1978,7 @@ type TabInfo struct {
 
 // Tabs 标签页管理
 func (e *Executor) Tabs(ctx context.Context, opts *TabsOptions) (*OperationResult, error) {
-	page := e.Browser.GetActivePage()
+	page := e.activePage(ctx)
 	if page == nil {
 		return nil, fmt.Errorf("no active page")
 	}
@@ -1836,6 +1987,10 @@ func (e *Executor) Tabs(ctx context.Context, opts *TabsOptions) (*OperationResul
 	if browser == nil {
 		return nil, fmt.Errorf("no browser instance")
 	}
+	// 隔离模式下新标签页创建在会话独立的浏览器上下文中
+	if incognito := e.sessionContext(ctx); incognito != nil {
+		browser = incognito
+	}
 
 	switch opts.Action {
 	case TabsActionList:
@@ -1846,9 +2001,9 @@ func (e *Executor) Tabs(ctx context.Context, opts *TabsOptions) (*OperationResul
 		}
 		return e.newTab(ctx, browser, opts.URL)
 	case TabsActionSwitch:
-		return e.switchTab(ctx, browser, opts.Index)
+		return e.switchTab(ctx, browser, opts.TabID, opts.Index)
 	case TabsActionClose:
-		return e.closeTab(ctx, browser, opts.Index)
+		return e.closeTab(ctx, browser, opts.TabID, opts.Index)
 	default:
 		return nil, fmt.Errorf("unknown tabs action: %s", opts.Action)
 	}
@@ -1856,12 +2011,13 @@ func (e *Executor) Tabs(ctx context.Context, opts *TabsOptions) (*OperationResul
 
 // listTabs 列出所有标签页
 func (e *Executor) listTabs(ctx context.Context, browser *rod.Browser, currentPage *rod.Page) (*OperationResult, error) {
-	pages, err := browser.Pages()
+	pages, err := pageTabs(browser)
 	if err != nil {
 		return &OperationResult{
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to get tabs: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
@@ -1873,16 +2029,12 @@ func (e *Executor) listTabs(ctx context.Context, browser *rod.Browser, currentPa
 			continue
 		}
 
-		// 只列出 type="page" 的标签页，排除扩展、devtools 等
-		if info.Type != "page" {
-			continue
-		}
-
 		tab := TabInfo{
+			ID:     string(p.TargetID),
 			Index:  i,
 			Title:  info.Title,
 			URL:    info.URL,
-			Active: p == currentPage,
+			Active: p.TargetID == currentPage.TargetID,
 			Type:   string(info.Type),
 		}
 		tabs = append(tabs, tab)
@@ -1903,14 +2055,39 @@ func (e *Executor) listTabs(ctx context.Context, browser *rod.Browser, currentPa
 func (e *Executor) newTab(ctx context.Context, browser *rod.Browser, url string) (*OperationResult, error) {
 	logger.Info(ctx, "Creating new tab with URL: %s", url)
 
-	newPage, err := browser.Page(proto.TargetCreateTarget{URL: url})
+	if url != "" {
+		if err := e.Browser.CheckURLPolicy(ctx, "", url); err != nil {
+			return &OperationResult{
+				Success:   false,
+				Error:     err.Error(),
+				Timestamp: time.Now(),
+				ErrorCode: errorCode(err),
+			}, err
+		}
+	}
+
+	// 先创建空白页并安装请求防护，再导航到目标地址
+	newPage, err := browser.Page(proto.TargetCreateTarget{URL: "about:blank"})
 	if err != nil {
 		return &OperationResult{
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to create new tab: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
+	e.trackSessionTab(ctx, newPage)
+
+	if url != "" {
+		if err := newPage.Navigate(url); err != nil {
+			return &OperationResult{
+				Success:   false,
+				Error:     fmt.Sprintf("Failed to navigate new tab: %s", err.Error()),
+				Timestamp: time.Now(),
+				ErrorCode: errorCode(err),
+			}, err
+		}
+	}
 
 	// 等待页面加载
 	if err := newPage.WaitLoad(); err != nil {
@@ -1924,10 +2101,10 @@ func (e *Executor) newTab(ctx context.Context, browser *rod.Browser, url string)
 	}
 
 	// 获取新标签页的索引
-	pages, _ := browser.Pages()
+	pages, _ := pageTabs(browser)
 	newIndex := -1
 	for i, p := range pages {
-		if p == newPage {
+		if p.TargetID == newPage.TargetID {
 			newIndex = i
 			break
 		}
@@ -1935,9 +2112,10 @@ func (e *Executor) newTab(ctx context.Context, browser *rod.Browser, url string)
 
 	return &OperationResult{
 		Success:   true,
-		Message:   fmt.Sprintf("Successfully created new tab at index %d", newIndex),
+		Message:   fmt.Sprintf("Successfully created new tab %s at index %d", newPage.TargetID, newIndex),
 		Timestamp: time.Now(),
 		Data: map[string]interface{}{
+			"id":    string(newPage.TargetID),
 			"index": newIndex,
 			"url":   url,
 			"title": info.Title,
@@ -1945,39 +2123,28 @@ func (e *Executor) newTab(ctx context.Context, browser *rod.Browser, url string)
 	}, nil
 }
 
-// switchTab 切换到指定标签页
-func (e *Executor) switchTab(ctx context.Context, browser *rod.Browser, index int) (*OperationResult, error) {
-	pages, err := browser.Pages()
+// switchTab 切换到指定标签页（MCP 会话内只切换当前会话的活动标签页）
+func (e *Executor) switchTab(ctx context.Context, browser *rod.Browser, tabID string, index int) (*OperationResult, error) {
+	pages, err := pageTabs(browser)
 	if err != nil {
 		return &OperationResult{
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to get tabs: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
-	// 过滤只保留 type="page" 的标签页
-	var pageTabs []*rod.Page
-	for _, p := range pages {
-		info, err := p.Info()
-		if err != nil {
-			continue
-		}
-		if info.Type == "page" {
-			pageTabs = append(pageTabs, p)
-		}
-	}
-
-	if index < 0 || index >= len(pageTabs) {
+	targetPage, index, err := resolveTab(pages, tabID, index)
+	if err != nil {
 		return &OperationResult{
 			Success:   false,
-			Error:     fmt.Sprintf("Tab index %d is out of range (0-%d)", index, len(pageTabs)-1),
+			Error:     err.Error(),
 			Timestamp: time.Now(),
-		}, fmt.Errorf("invalid tab index: %d", index)
+			ErrorCode: errorCode(err),
+		}, err
 	}
 
-	targetPage := pageTabs[index]
-
 	// 激活目标标签页
 	_, err = targetPage.Activate()
 	if err != nil {
@@ -1985,19 +2152,22 @@ func (e *Executor) switchTab(ctx context.Context, browser *rod.Browser, index in
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to activate tab: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
+	e.setActiveTab(ctx, targetPage)
 
 	// 获取标签页信息
 	info, _ := targetPage.Info()
 
-	logger.Info(ctx, "Switched to tab %d: %s", index, info.URL)
+	logger.Info(ctx, "Switched to tab %s (index %d): %s", targetPage.TargetID, index, info.URL)
 
 	return &OperationResult{
 		Success:   true,
-		Message:   fmt.Sprintf("Successfully switched to tab %d", index),
+		Message:   fmt.Sprintf("Successfully switched to tab %s", targetPage.TargetID),
 		Timestamp: time.Now(),
 		Data: map[string]interface{}{
+			"id":    string(targetPage.TargetID),
 			"index": index,
 			"url":   info.URL,
 			"title": info.Title,
@@ -2006,37 +2176,26 @@ func (e *Executor) switchTab(ctx context.Context, browser *rod.Browser, index in
 }
 
 // closeTab 关闭指定标签页
-func (e *Executor) closeTab(ctx context.Context, browser *rod.Browser, index int) (*OperationResult, error) {
-	pages, err := browser.Pages()
+func (e *Executor) closeTab(ctx context.Context, browser *rod.Browser, tabID string, index int) (*OperationResult, error) {
+	pages, err := pageTabs(browser)
 	if err != nil {
 		return &OperationResult{
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to get tabs: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
 
-	// 过滤只保留 type="page" 的标签页
-	var pageTabs []*rod.Page
-	for _, p := range pages {
-		info, err := p.Info()
-		if err != nil {
-			continue
-		}
-		if info.Type == "page" {
-			pageTabs = append(pageTabs, p)
-		}
-	}
-
-	if index < 0 || index >= len(pageTabs) {
+	targetPage, index, err := resolveTab(pages, tabID, index)
+	if err != nil {
 		return &OperationResult{
 			Success:   false,
-			Error:     fmt.Sprintf("Tab index %d is out of range (0-%d)", index, len(pageTabs)-1),
+			Error:     err.Error(),
 			Timestamp: time.Now(),
-		}, fmt.Errorf("invalid tab index: %d", index)
+			ErrorCode: errorCode(err),
+		}, err
 	}
-
-	targetPage := pageTabs[index]
 	info, _ := targetPage.Info()
 
 	// 关闭标签页
@@ -2046,16 +2205,19 @@ func (e *Executor) closeTab(ctx context.Context, browser *rod.Browser, index int
 			Success:   false,
 			Error:     fmt.Sprintf("Failed to close tab: %s", err.Error()),
 			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
 		}, err
 	}
+	e.forgetTab(targetPage.TargetID)
 
-	logger.Info(ctx, "Closed tab %d: %s", index, info.URL)
+	logger.Info(ctx, "Closed tab %s (index %d): %s", targetPage.TargetID, index, info.URL)
 
 	return &OperationResult{
 		Success:   true,
-		Message:   fmt.Sprintf("Successfully closed tab %d", index),
+		Message:   fmt.Sprintf("Successfully closed tab %s", targetPage.TargetID),
 		Timestamp: time.Now(),
 		Data: map[string]interface{}{
+			"id":    string(targetPage.TargetID),
 			"index": index,
 			"url":   info.URL,
 			"title": info.Title,
@@ -2079,7 +2241,7 @@ type FillFormOptions struct {
 
 // FillForm 批量填写表单
 func (e *Executor) FillForm(ctx context.Context, opts *FillFormOptions) (*OperationResult, error) {
-	page := e.Browser.GetActivePage()
+	page := e.activePage(ctx)
 	if page == nil {
 		return nil, fmt.Errorf("no active page")
 	}
@@ -2089,7 +2251,7 @@ func (e *Executor) FillForm(ctx context.Context, opts *FillFormOptions) (*Operat
 	}
 
 	if opts.Timeout == 0 {
-		opts.Timeout = 10 * time.Second
+		opts.Timeout = e.timeouts().Element()
 	}
 
 	logger.Info(ctx, "Filling form with %d fields", len(opts.Fields))
@@ -2174,7 +2336,7 @@ func (e *Executor) fillSingleField(ctx context.Context, page *rod.Page, field Fo
 	}
 
 	if elem == nil || err != nil {
-		return fmt.Errorf("element not found with name '%s'", field.Name)
+		return elementNotFound("element not found with name '%s'", field.Name)
 	}
 
 	// 根据元素类型填写值
@@ -2348,22 +2510,34 @@ func (e *Executor) submitForm(ctx context.Context, page *rod.Page) error {
 	return fmt.Errorf("no submit button found")
 }
 
-// saveScreenshot 将截图数据保存到文件
-func (e *Executor) saveScreenshot(ctx context.Context, data []byte, format string) (string, error) {
-	// 创建 screenshots 目录
-	screenshotsDir := "screenshots"
-	if err := os.MkdirAll(screenshotsDir, 0755); err != nil {
+// saveScreenshot 将截图数据保存到截图目录（按配置的路径模板分子目录）
+func (e *Executor) saveScreenshot(ctx context.Context, data []byte, format string, name string) (string, error) {
+	sandbox, err := e.Browser.ScreenshotSandbox()
+	if err != nil {
+		return "", err
+	}
+	subdir := e.Browser.ArtifactSubdir(artifacts.TemplateVars{})
+	if _, err := sandbox.Dir(subdir); err != nil {
 		return "", fmt.Errorf("failed to create screenshots directory: %w", err)
 	}
 
-	// 生成文件名：screenshot_YYYYMMDD_HHMMSS.{format}
-	timestamp := time.Now().Format("20060102_150405")
+	// 生成文件名：screenshot_YYYYMMDD_HHMMSS_<随机后缀>.{format}，避免同一秒内的截图互相覆盖
+	timestamp := fmt.Sprintf("%s_%s", time.Now().Format("20060102_150405"), uuid.NewString()[:8])
 	extension := format
 	if extension == "jpg" {
 		extension = "jpeg"
 	}
 	filename := fmt.Sprintf("screenshot_%s.%s", timestamp, extension)
-	filepath := filepath.Join(screenshotsDir, filename)
+	if name != "" {
+		filename = artifacts.SanitizeFileName(name, filename)
+		if filepath.Ext(filename) == "" {
+			filename += "." + extension
+		}
+	}
+	filepath, err := sandbox.Resolve(subdir, filename)
+	if err != nil {
+		return "", err
+	}
 
 	// 保存文件
 	if err := os.WriteFile(filepath, data, 0644); err != nil {
@@ -2371,5 +2545,6 @@ func (e *Executor) saveScreenshot(ctx context.Context, data []byte, format strin
 	}
 
 	logger.Info(ctx, "Screenshot saved to: %s", filepath)
+	e.Browser.EnforceArtifactQuotas(ctx, filepath)
 	return filepath, nil
 }
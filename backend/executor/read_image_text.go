@@ -0,0 +1,127 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/ocr"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// ErrCaptchaNotSupported 目标元素是验证码，不进行文字识别
+var ErrCaptchaNotSupported = models.WithErrorCode(models.ErrorCodeCaptchaDetected, errors.New("reading CAPTCHA images is not supported"))
+
+// ReadImageTextOptions 图片文字识别选项
+type ReadImageTextOptions struct {
+	Identifier   string        // 要识别的元素（canvas、img 等），为空时识别当前视口
+	FullPage     bool          // 未指定元素时识别整个页面而不只是视口
+	IncludeWords bool          // 返回每个单词的置信度和位置
+	Timeout      time.Duration // 查找元素的超时时间
+}
+
+// captchaCheckScript 判断元素（或其近层祖先）是否为验证码
+const captchaCheckScript = `function () {
+	const re = /captcha|turnstile/i;
+	for (let el = this, depth = 0; el && el.nodeType === 1 && depth < 4; el = el.parentElement, depth++) {
+		const values = [el.id, typeof el.className === 'string' ? el.className : '',
+			el.getAttribute('src'), el.getAttribute('alt'), el.getAttribute('name'),
+			el.getAttribute('title'), el.getAttribute('aria-label')];
+		if (values.some(v => v && re.test(v))) return true;
+	}
+	return false;
+}`
+
+// ReadImageText 截取元素或页面并通过 OCR 识别其中的文字
+// 用于 canvas 图表、纯图片页面等无法从 DOM 提取文字的内容；验证码不在支持范围内
+func (e *Executor) ReadImageText(ctx context.Context, opts *ReadImageTextOptions) (*OperationResult, error) {
+	page := e.activePage(ctx)
+	if page == nil {
+		return nil, fmt.Errorf("no active page")
+	}
+	if opts == nil {
+		opts = &ReadImageTextOptions{}
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = e.timeouts().Element()
+	}
+
+	engine, err := ocr.New(e.Browser.GetOCRConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	var image []byte
+	source := "viewport"
+	if opts.Identifier != "" {
+		source = "element"
+		elem, err := e.findElementWithTimeout(ctx, page, opts.Identifier, opts.Timeout)
+		if err != nil {
+			return &OperationResult{
+				Success:   false,
+				Error:     fmt.Sprintf("Element not found: %s", opts.Identifier),
+				Timestamp: time.Now(),
+				ErrorCode: errorCode(err),
+			}, err
+		}
+		res, err := elem.Eval(captchaCheckScript)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect element: %w", err)
+		}
+		if res.Value.Bool() {
+			return nil, ErrCaptchaNotSupported
+		}
+		image, err = elem.Screenshot(proto.PageCaptureScreenshotFormatPng, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to capture element: %w", err)
+		}
+	} else {
+		if opts.FullPage {
+			source = "full_page"
+		}
+		image, err = page.Context(ctx).Screenshot(opts.FullPage, &proto.PageCaptureScreenshot{
+			Format: proto.PageCaptureScreenshotFormatPng,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to take screenshot: %w", err)
+		}
+	}
+
+	result, err := engine.Recognize(ctx, image)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]interface{}{
+		"text":   result.Text,
+		"source": source,
+	}
+	if opts.IncludeWords {
+		data["words"] = result.Words
+	}
+	message := fmt.Sprintf("Recognized %d characters from %s", len([]rune(result.Text)), source)
+	if result.Text == "" {
+		message = fmt.Sprintf("No text recognized from %s", source)
+	}
+
+	return &OperationResult{
+		Success:   true,
+		Message:   message,
+		Data:      data,
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// ParseReadImageTextArguments 解析 browser_read_image_text 工具参数
+func ParseReadImageTextArguments(args map[string]interface{}) *ReadImageTextOptions {
+	opts := &ReadImageTextOptions{}
+	opts.Identifier, _ = args["identifier"].(string)
+	opts.FullPage, _ = args["full_page"].(bool)
+	opts.IncludeWords, _ = args["include_words"].(bool)
+	if v, ok := args["timeout"].(float64); ok && v > 0 {
+		opts.Timeout = time.Duration(v) * time.Second
+	}
+	return opts
+}
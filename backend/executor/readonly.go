@@ -0,0 +1,110 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ReadOnlyHeader MCP 客户端在请求头中设置为 true 时，该连接只能使用只读工具
+const ReadOnlyHeader = "X-BrowserWing-Read-Only"
+
+const readOnlyKey contextKey = "read_only"
+
+// readOnlyTools 只读会话可用的工具：打开和读取页面，不点击、输入、提交或执行脚本
+var readOnlyTools = map[string]bool{
+	"browser_navigate":         true,
+	"browser_snapshot":         true,
+	"browser_extract":          true,
+	"browser_take_screenshot":  true,
+	"browser_get_page_info":    true,
+	"browser_wait_for":         true, // 不允许 predicate（自定义 JS 条件）
+	"browser_scroll":           true,
+	"browser_resize":           true,
+	"browser_tabs":             true, // 只允许 list、new、switch
+	"browser_console_messages": true,
+	"browser_network_requests": true,
+	"browser_inspect_element":  true,
+	"browser_a11y_scan":        true,
+	"browser_audit":            true,
+	"browser_read_image_text":  true,
+}
+
+// WithReadOnly 标记本次调用来自只读会话，MCP 工具注册表和 CallTool 只允许只读工具
+func WithReadOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readOnlyKey, true)
+}
+
+// IsReadOnly 本次调用是否来自只读会话
+func IsReadOnly(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	readOnly, _ := ctx.Value(readOnlyKey).(bool)
+	return readOnly
+}
+
+// IsReadOnlyTool 工具是否可以在只读会话中使用（部分参数可能仍被 CheckReadOnlyTool 拒绝）
+func IsReadOnlyTool(name string) bool {
+	return readOnlyTools[name]
+}
+
+// CheckReadOnlyTool 只读会话中不可用的工具调用返回错误
+func CheckReadOnlyTool(name string, args map[string]interface{}) error {
+	if !readOnlyTools[name] {
+		return fmt.Errorf("tool %s is not available in a read-only session", name)
+	}
+	switch name {
+	case "browser_tabs":
+		switch args["action"] {
+		case "list", "new", "switch":
+		default:
+			return fmt.Errorf("browser_tabs action %v is not available in a read-only session", args["action"])
+		}
+	case "browser_wait_for":
+		// predicate 在页面中执行任意 JS，可以点击、提交或跳转
+		if predicate, _ := args["predicate"].(string); strings.TrimSpace(predicate) != "" {
+			return fmt.Errorf("browser_wait_for predicate is not available in a read-only session")
+		}
+	}
+	return nil
+}
+
+// ReadOnlyHTTPContext 请求头 X-BrowserWing-Read-Only 为 true 时把 MCP 连接标记为只读（用于 Streamable HTTP 和 SSE 服务）
+func ReadOnlyHTTPContext(ctx context.Context, r *http.Request) context.Context {
+	switch strings.ToLower(r.Header.Get(ReadOnlyHeader)) {
+	case "true", "1", "yes":
+		return WithReadOnly(ctx)
+	}
+	return ctx
+}
+
+// ReadOnlyToolMiddleware MCP 工具调用的只读检查，只读连接调用其他工具时返回错误结果
+func ReadOnlyToolMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		if IsReadOnly(ctx) {
+			if err := CheckReadOnlyTool(request.Params.Name, request.GetArguments()); err != nil {
+				return mcpgo.NewToolResultError(err.Error()), nil
+			}
+		}
+		return next(ctx, request)
+	}
+}
+
+// ReadOnlyToolFilter 只读连接的工具列表只包含只读工具
+func ReadOnlyToolFilter(ctx context.Context, tools []mcpgo.Tool) []mcpgo.Tool {
+	if !IsReadOnly(ctx) {
+		return tools
+	}
+	allowed := make([]mcpgo.Tool, 0, len(readOnlyTools))
+	for _, tool := range tools {
+		if readOnlyTools[tool.Name] {
+			allowed = append(allowed, tool)
+		}
+	}
+	return allowed
+}
@@ -0,0 +1,61 @@
+package executor
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestCheckReadOnlyTool(t *testing.T) {
+	if err := CheckReadOnlyTool("browser_wait_for", map[string]interface{}{"url": "*/done"}); err != nil {
+		t.Errorf("waiting for a URL should be allowed, got %v", err)
+	}
+	if err := CheckReadOnlyTool("browser_wait_for", map[string]interface{}{"predicate": "document.querySelector('form').submit() || true"}); err == nil {
+		t.Error("a JS predicate should be denied in a read-only session")
+	}
+	if err := CheckReadOnlyTool("browser_tabs", map[string]interface{}{"action": "close"}); err == nil {
+		t.Error("closing tabs should be denied in a read-only session")
+	}
+	if err := CheckReadOnlyTool("browser_evaluate", nil); err == nil {
+		t.Error("browser_evaluate should be denied in a read-only session")
+	}
+}
+
+func TestReadOnlyToolMiddleware(t *testing.T) {
+	called := 0
+	handler := ReadOnlyToolMiddleware(func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		called++
+		return mcpgo.NewToolResultText("ok"), nil
+	})
+	call := func(ctx context.Context, name string) *mcpgo.CallToolResult {
+		request := mcpgo.CallToolRequest{}
+		request.Params.Name = name
+		request.Params.Arguments = map[string]interface{}{"identifier": "@e1"}
+		result, err := handler(ctx, request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	// 请求头标记的只读连接
+	r := httptest.NewRequest("POST", "/api/v1/mcp/message", nil)
+	r.Header.Set(ReadOnlyHeader, "true")
+	readOnly := ReadOnlyHTTPContext(context.Background(), r)
+	if result := call(readOnly, "browser_click"); !result.IsError || called != 0 {
+		t.Errorf("browser_click should be rejected on a read-only connection")
+	}
+	if result := call(readOnly, "browser_snapshot"); result.IsError || called != 1 {
+		t.Errorf("browser_snapshot should be allowed on a read-only connection")
+	}
+	if result := call(context.Background(), "browser_click"); result.IsError || called != 2 {
+		t.Errorf("normal connections should not be restricted")
+	}
+
+	tools := ReadOnlyToolFilter(readOnly, []mcpgo.Tool{{Name: "browser_snapshot"}, {Name: "browser_click"}, {Name: "my_script"}})
+	if len(tools) != 1 || tools[0].Name != "browser_snapshot" {
+		t.Errorf("expected only browser_snapshot to be listed, got %+v", tools)
+	}
+}
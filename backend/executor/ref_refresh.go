@@ -0,0 +1,98 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// refLookupInfo RefID 查找时缓存的状态
+type refLookupInfo struct {
+	age      time.Duration // 缓存年龄
+	document string        // 生成快照时的文档标识
+	cached   bool          // 会话在该标签页上是否有缓存
+}
+
+// StaleRefError RefID 已失效（页面已导航、缓存过期或元素已消失）
+// 返回前已自动刷新快照，Snapshot 为新的 RefID 映射，Agent 可直接用新 RefID 重试
+type StaleRefError struct {
+	RefID    string
+	Reason   string
+	Snapshot string // 刷新后的快照文本，刷新失败时为空
+}
+
+func (e *StaleRefError) Error() string {
+	msg := fmt.Sprintf("refID %s is stale (%s)", e.RefID, e.Reason)
+	if e.Snapshot == "" {
+		return msg + ", run browser_snapshot first"
+	}
+	return msg + "; the snapshot was refreshed automatically, retry with the new RefIDs below:\n\n" + e.Snapshot
+}
+
+// documentIdentity 返回页面当前文档的标识：主框架的 loaderId 加 URL
+// 跨文档导航会改变 loaderId，单页应用的路由切换（包括 hash 路由）会改变 URL；获取失败时返回空字符串
+func documentIdentity(page *rod.Page) string {
+	tree, err := proto.PageGetFrameTree{}.Call(page)
+	if err != nil || tree.FrameTree == nil || tree.FrameTree.Frame == nil {
+		return ""
+	}
+	frame := tree.FrameTree.Frame
+	identity := string(frame.LoaderID) + " " + frame.URL
+	if frame.URLFragment != "" {
+		identity += frame.URLFragment
+	}
+	return identity
+}
+
+// isRefID 判断是否为 RefID 格式（e1、e2...）
+func isRefID(s string) bool {
+	if len(s) < 2 || len(s) > 10 || s[0] != 'e' {
+		return false
+	}
+	for _, c := range s[1:] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// sameDocument 判断两个文档标识是否指向同一个文档，任一方未知时视为相同
+func sameDocument(a, b string) bool {
+	return a == "" || b == "" || a == b
+}
+
+// staleRefReason 判断缓存中的 RefID 是否已经不可用，返回原因；可用时返回空字符串
+// 过期但文档未变的缓存仍可尝试（定位时会校验元素），只有在定位失败后才刷新
+func (e *Executor) staleRefReason(page *rod.Page, info refLookupInfo, found bool) string {
+	switch {
+	case !info.cached:
+		return "no snapshot has been taken on this page"
+	case !sameDocument(info.document, documentIdentity(page)):
+		return "the page has navigated since the last snapshot"
+	case !found:
+		if info.age >= e.refIDTTL {
+			return fmt.Sprintf("snapshot expired %v ago", (info.age - e.refIDTTL).Round(time.Second))
+		}
+		return "not in the current snapshot"
+	}
+	return ""
+}
+
+// refreshStaleRef 重新获取快照，返回附带新映射的 StaleRefError
+func (e *Executor) refreshStaleRef(ctx context.Context, refID, reason string) error {
+	logger.Info(ctx, "[refreshStaleRef] RefID %s is stale (%s), refreshing snapshot", refID, reason)
+
+	staleErr := &StaleRefError{RefID: refID, Reason: reason}
+	snapshot, err := e.accessibilitySnapshot(ctx, true)
+	if err != nil {
+		logger.Warn(ctx, "[refreshStaleRef] Failed to refresh snapshot: %v", err)
+		return staleErr
+	}
+	staleErr.Snapshot = snapshot.SerializeToSimpleText()
+	return staleErr
+}
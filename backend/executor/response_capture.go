@@ -0,0 +1,138 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/services/browser"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// CaptureResponseAction 响应捕获操作类型
+type CaptureResponseAction string
+
+const (
+	CaptureResponseStart CaptureResponseAction = "start"
+	CaptureResponseGet   CaptureResponseAction = "get"
+	CaptureResponseStop  CaptureResponseAction = "stop"
+)
+
+// CaptureResponseOptions 响应捕获选项
+type CaptureResponseOptions struct {
+	Action     CaptureResponseAction // 操作类型：start, get, stop
+	URLPattern string                // URL 模式（支持 * 通配符，不含通配符时按子串匹配）
+	Method     string                // 可选的 HTTP 方法过滤（action=get 时使用）
+	Timeout    time.Duration         // 等待响应的超时时间（action=get 时使用）
+}
+
+// CaptureResponse 捕获当前页面中 URL 匹配的响应体
+// 先 start 开始监听，再执行触发请求的操作，最后 get 取出响应体
+func (e *Executor) CaptureResponse(ctx context.Context, opts *CaptureResponseOptions) (*OperationResult, error) {
+	page := e.activePage(ctx)
+	if page == nil {
+		return nil, fmt.Errorf("no active page")
+	}
+
+	switch opts.Action {
+	case CaptureResponseStart:
+		if opts.URLPattern == "" {
+			return nil, fmt.Errorf("url_pattern is required")
+		}
+
+		e.tabMutex.Lock()
+		if e.captures == nil {
+			e.captures = make(map[proto.TargetTargetID]*browser.ResponseCapture)
+		}
+		capture := e.captures[page.TargetID]
+		if capture == nil {
+			capture = browser.NewResponseCapture()
+			e.captures[page.TargetID] = capture
+		}
+		e.tabMutex.Unlock()
+
+		capture.AddPattern(opts.URLPattern)
+		// 监听需独立于本次调用的 context，直到 stop 或页面关闭
+		if err := capture.Watch(context.Background(), page); err != nil {
+			return &OperationResult{
+				Success:   false,
+				Error:     err.Error(),
+				Timestamp: time.Now(),
+				ErrorCode: errorCode(err),
+			}, err
+		}
+
+		logger.Info(ctx, "Started capturing responses matching %s on tab %s", opts.URLPattern, page.TargetID)
+		return &OperationResult{
+			Success:   true,
+			Message:   fmt.Sprintf("Capturing responses matching %s", opts.URLPattern),
+			Timestamp: time.Now(),
+			Data: map[string]interface{}{
+				"url_pattern": opts.URLPattern,
+				"tab_id":      string(page.TargetID),
+			},
+		}, nil
+
+	case CaptureResponseGet:
+		e.tabMutex.Lock()
+		capture := e.captures[page.TargetID]
+		e.tabMutex.Unlock()
+		if capture == nil {
+			return nil, fmt.Errorf("response capture is not started on this tab, call with action='start' first")
+		}
+
+		timeout := opts.Timeout
+		if timeout <= 0 {
+			timeout = 10 * time.Second
+		}
+
+		resp, err := capture.Wait(ctx, opts.URLPattern, opts.Method, timeout)
+		if err != nil {
+			return &OperationResult{
+				Success:   false,
+				Error:     err.Error(),
+				Timestamp: time.Now(),
+				ErrorCode: errorCode(err),
+			}, err
+		}
+
+		return &OperationResult{
+			Success:   true,
+			Message:   fmt.Sprintf("Captured response: %s %s (%d)", resp.Method, resp.URL, resp.Status),
+			Timestamp: time.Now(),
+			Data: map[string]interface{}{
+				"url":       resp.URL,
+				"method":    resp.Method,
+				"status":    resp.Status,
+				"mime_type": resp.MimeType,
+				"body":      resp.Body,
+				"truncated": resp.Truncated,    // 响应体超过大小上限被截断
+				"dropped":   capture.Dropped(), // 因数量上限被丢弃的未取出响应数
+			},
+		}, nil
+
+	case CaptureResponseStop:
+		e.stopCapture(page.TargetID)
+		return &OperationResult{
+			Success:   true,
+			Message:   "Response capture stopped",
+			Timestamp: time.Now(),
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown capture action: %s", opts.Action)
+	}
+}
+
+// stopCapture 停止标签页上的响应捕获
+func (e *Executor) stopCapture(tabID proto.TargetTargetID) {
+	e.tabMutex.Lock()
+	capture := e.captures[tabID]
+	delete(e.captures, tabID)
+	e.tabMutex.Unlock()
+
+	if capture != nil {
+		capture.Stop()
+	}
+}
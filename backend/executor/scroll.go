@@ -0,0 +1,156 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+)
+
+// ScrollOptions 滚动选项
+type ScrollOptions struct {
+	Container string        // 可滚动容器的标识（为空时滚动整个页面）
+	To        string        // 滚动到边缘：top, bottom, left, right（设置后忽略 DeltaX/DeltaY）
+	DeltaX    int           // 水平相对滚动距离（像素，负数向左）
+	DeltaY    int           // 垂直相对滚动距离（像素，负数向上）
+	Timeout   time.Duration // 查找容器的超时时间
+}
+
+// IsCustom 是否需要通用滚动（指定容器、相对距离或水平方向），否则沿用原有的页面滚动
+func (o *ScrollOptions) IsCustom() bool {
+	return o.Container != "" || o.DeltaX != 0 || o.DeltaY != 0 || o.To == "left" || o.To == "right"
+}
+
+// validate 检查滚动参数
+func (o *ScrollOptions) validate() error {
+	switch o.To {
+	case "", "top", "bottom", "left", "right":
+	default:
+		return fmt.Errorf("invalid scroll target %q (expected top, bottom, left or right)", o.To)
+	}
+	if o.To == "" && o.DeltaX == 0 && o.DeltaY == 0 {
+		return fmt.Errorf("scroll target or delta is required")
+	}
+	return nil
+}
+
+// Scroll 滚动页面或指定的可滚动容器：支持滚动到边缘、按相对距离滚动以及水平滚动
+// 返回滚动后的位置，以及是否已到达边缘
+func (e *Executor) Scroll(ctx context.Context, opts *ScrollOptions) (*OperationResult, error) {
+	page := e.activePage(ctx)
+	if page == nil {
+		return nil, fmt.Errorf("no active page")
+	}
+	if opts == nil {
+		opts = &ScrollOptions{}
+	}
+	if err := opts.validate(); err != nil {
+		return &OperationResult{
+			Success:   false,
+			Error:     err.Error(),
+			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
+		}, err
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = e.timeouts().Element()
+	}
+
+	var container interface{} // 为空时传 null，滚动整个页面
+	if opts.Container != "" {
+		elem, err := e.findElementWithTimeout(ctx, page, opts.Container, opts.Timeout)
+		if err != nil {
+			return &OperationResult{
+				Success:   false,
+				Error:     fmt.Sprintf("Scroll container not found: %s", opts.Container),
+				Timestamp: time.Now(),
+				ErrorCode: errorCode(err),
+			}, err
+		}
+		container = elem.Object
+	}
+
+	res, err := page.Evaluate(rod.Eval(`(container, to, dx, dy) => {
+		const el = container || document.scrollingElement || document.documentElement;
+		const before = { x: el.scrollLeft, y: el.scrollTop };
+		const maxX = el.scrollWidth - el.clientWidth;
+		const maxY = el.scrollHeight - el.clientHeight;
+		switch (to) {
+			case 'top': el.scrollTo({ top: 0, behavior: 'instant' }); break;
+			case 'bottom': el.scrollTo({ top: el.scrollHeight, behavior: 'instant' }); break;
+			case 'left': el.scrollTo({ left: 0, behavior: 'instant' }); break;
+			case 'right': el.scrollTo({ left: el.scrollWidth, behavior: 'instant' }); break;
+			default: el.scrollBy({ left: dx, top: dy, behavior: 'instant' });
+		}
+		const x = el.scrollLeft, y = el.scrollTop;
+		return {
+			scroll_x: x,
+			scroll_y: y,
+			max_scroll_x: maxX,
+			max_scroll_y: maxY,
+			moved_x: x - before.x,
+			moved_y: y - before.y,
+			scrollable: maxX > 0 || maxY > 0,
+			at_top: y <= 0,
+			at_bottom: y >= maxY - 1,
+			at_left: x <= 0,
+			at_right: x >= maxX - 1,
+		};
+	}`, container, opts.To, opts.DeltaX, opts.DeltaY))
+	if err != nil {
+		return &OperationResult{
+			Success:   false,
+			Error:     fmt.Sprintf("Failed to scroll: %s", err.Error()),
+			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
+		}, err
+	}
+
+	var data map[string]interface{}
+	if err := res.Value.Unmarshal(&data); err != nil {
+		return nil, fmt.Errorf("failed to parse scroll result: %w", err)
+	}
+
+	target := "page"
+	if opts.Container != "" {
+		target = opts.Container
+	}
+	action := "to " + opts.To
+	if opts.To == "" {
+		action = fmt.Sprintf("by (%d, %d)", opts.DeltaX, opts.DeltaY)
+	}
+	message := fmt.Sprintf("Scrolled %s %s, position (%v, %v)", target, action, data["scroll_x"], data["scroll_y"])
+	if scrollable, _ := data["scrollable"].(bool); !scrollable {
+		message += " (target is not scrollable)"
+	}
+	logger.Info(ctx, "%s", message)
+
+	return &OperationResult{
+		Success:   true,
+		Message:   message,
+		Timestamp: time.Now(),
+		Data:      data,
+	}, nil
+}
+
+// ParseScrollArguments 从 MCP 工具参数中解析滚动选项（direction 为 top/bottom/left/right 时作为滚动目标）
+func ParseScrollArguments(args map[string]interface{}) *ScrollOptions {
+	opts := &ScrollOptions{}
+	opts.Container, _ = args["container"].(string)
+	if direction, ok := args["direction"].(string); ok {
+		switch d := strings.ToLower(strings.TrimSpace(direction)); d {
+		case "top", "bottom", "left", "right":
+			opts.To = d
+		}
+	}
+	if dx, ok := args["delta_x"].(float64); ok {
+		opts.DeltaX = int(dx)
+	}
+	if dy, ok := args["delta_y"].(float64); ok {
+		opts.DeltaY = int(dy)
+	}
+	return opts
+}
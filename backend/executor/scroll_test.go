@@ -0,0 +1,59 @@
+package executor
+
+import "testing"
+
+func TestParseScrollArguments(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   map[string]interface{}
+		want   ScrollOptions
+		custom bool
+	}{
+		{
+			name: "page bottom keeps legacy scroll",
+			args: map[string]interface{}{"direction": "bottom"},
+			want: ScrollOptions{To: "bottom"},
+		},
+		{
+			name: "element identifier is not a scroll target",
+			args: map[string]interface{}{"direction": "#footer"},
+			want: ScrollOptions{},
+		},
+		{
+			name:   "horizontal",
+			args:   map[string]interface{}{"direction": "Right"},
+			want:   ScrollOptions{To: "right"},
+			custom: true,
+		},
+		{
+			name:   "container delta",
+			args:   map[string]interface{}{"container": ".list", "delta_y": -300.0, "delta_x": 40.0},
+			want:   ScrollOptions{Container: ".list", DeltaX: 40, DeltaY: -300},
+			custom: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseScrollArguments(tt.args)
+			if *got != tt.want {
+				t.Errorf("got %+v, want %+v", *got, tt.want)
+			}
+			if got.IsCustom() != tt.custom {
+				t.Errorf("IsCustom() = %v, want %v", got.IsCustom(), tt.custom)
+			}
+		})
+	}
+}
+
+func TestScrollOptionsValidate(t *testing.T) {
+	if err := (&ScrollOptions{To: "middle"}).validate(); err == nil {
+		t.Errorf("expected error for invalid target")
+	}
+	if err := (&ScrollOptions{Container: ".list"}).validate(); err == nil {
+		t.Errorf("expected error when neither target nor delta is set")
+	}
+	if err := (&ScrollOptions{DeltaX: 100}).validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
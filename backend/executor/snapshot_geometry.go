@@ -0,0 +1,164 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// geometryObjectGroup 计算几何信息时解析出的 JS 对象所属分组，结束后统一释放
+const geometryObjectGroup = "browserwing-geometry"
+
+// geometryBatchSize 单次 JS 调用测量的元素数量
+const geometryBatchSize = 100
+
+// NodeGeometry 元素的几何信息
+// 坐标为视口 CSS 像素，与 browser_click_at 的默认坐标空间一致
+type NodeGeometry struct {
+	X          float64 `json:"x"`
+	Y          float64 `json:"y"`
+	Width      float64 `json:"width"`
+	Height     float64 `json:"height"`
+	Visible    bool    `json:"visible"`     // 有尺寸且未被 CSS 隐藏
+	InViewport bool    `json:"in_viewport"` // 与当前视口相交
+}
+
+// SnapshotGeometry 快照中元素的几何信息
+// 几何信息随滚动和布局变化，不随快照缓存，每次请求时重新测量
+type SnapshotGeometry struct {
+	ScrollX        float64                  `json:"scroll_x"`
+	ScrollY        float64                  `json:"scroll_y"`
+	ViewportWidth  float64                  `json:"viewport_width"`
+	ViewportHeight float64                  `json:"viewport_height"`
+	Nodes          map[string]*NodeGeometry `json:"nodes"` // RefID -> 几何信息
+}
+
+// measureGeometryScript 测量传入元素的边界框和可见性，文本节点按其内容范围测量
+const measureGeometryScript = `function (...nodes) {
+	const vw = window.innerWidth, vh = window.innerHeight;
+	const round = v => Math.round(v * 10) / 10;
+	const rects = nodes.map(node => {
+		if (!node) return null;
+		let rect, el = node;
+		if (node.nodeType === 1) {
+			rect = node.getBoundingClientRect();
+		} else {
+			const range = document.createRange();
+			range.selectNodeContents(node);
+			rect = range.getBoundingClientRect();
+			el = node.parentElement;
+		}
+		const style = el ? getComputedStyle(el) : null;
+		const visible = rect.width > 0 && rect.height > 0 && !!style &&
+			style.display !== 'none' && style.visibility !== 'hidden' && parseFloat(style.opacity) > 0;
+		const inViewport = visible && rect.bottom > 0 && rect.right > 0 && rect.top < vh && rect.left < vw;
+		return { x: round(rect.x), y: round(rect.y), width: round(rect.width), height: round(rect.height),
+			visible, in_viewport: inViewport };
+	});
+	return { scroll_x: window.scrollX, scroll_y: window.scrollY, viewport_width: vw, viewport_height: vh, rects };
+}`
+
+// CollectSnapshotGeometry 测量带 RefID 的节点的边界框和视口可见性
+func CollectSnapshotGeometry(ctx context.Context, page *rod.Page, nodes []*AccessibilityNode) (*SnapshotGeometry, error) {
+	page = page.Context(ctx)
+	defer func() {
+		_ = proto.RuntimeReleaseObjectGroup{ObjectGroup: geometryObjectGroup}.Call(page)
+	}()
+
+	geometry := &SnapshotGeometry{Nodes: make(map[string]*NodeGeometry)}
+
+	// 解析为 JS 对象，已从页面移除的节点直接跳过
+	var refIDs []string
+	var args []*proto.RuntimeCallArgument
+	for _, node := range nodes {
+		if node.RefID == "" || node.BackendNodeID == 0 {
+			continue
+		}
+		if _, ok := geometry.Nodes[node.RefID]; ok {
+			continue
+		}
+		res, err := proto.DOMResolveNode{
+			BackendNodeID: node.BackendNodeID,
+			ObjectGroup:   geometryObjectGroup,
+		}.Call(page)
+		if err != nil || res.Object == nil || res.Object.ObjectID == "" {
+			continue
+		}
+		geometry.Nodes[node.RefID] = nil
+		refIDs = append(refIDs, node.RefID)
+		args = append(args, &proto.RuntimeCallArgument{ObjectID: res.Object.ObjectID})
+	}
+
+	for start := 0; start < len(args); start += geometryBatchSize {
+		end := min(start+geometryBatchSize, len(args))
+
+		res, err := proto.RuntimeCallFunctionOn{
+			FunctionDeclaration: measureGeometryScript,
+			ObjectID:            args[start].ObjectID,
+			Arguments:           args[start:end],
+			ReturnByValue:       true,
+		}.Call(page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to measure elements: %w", err)
+		}
+		if res.ExceptionDetails != nil {
+			return nil, fmt.Errorf("failed to measure elements: %s", res.ExceptionDetails.Text)
+		}
+
+		var batch struct {
+			ScrollX        float64         `json:"scroll_x"`
+			ScrollY        float64         `json:"scroll_y"`
+			ViewportWidth  float64         `json:"viewport_width"`
+			ViewportHeight float64         `json:"viewport_height"`
+			Rects          []*NodeGeometry `json:"rects"`
+		}
+		if err := res.Result.Value.Unmarshal(&batch); err != nil {
+			return nil, fmt.Errorf("failed to parse element geometry: %w", err)
+		}
+		geometry.ScrollX, geometry.ScrollY = batch.ScrollX, batch.ScrollY
+		geometry.ViewportWidth, geometry.ViewportHeight = batch.ViewportWidth, batch.ViewportHeight
+		for i, rect := range batch.Rects {
+			if start+i < len(refIDs) {
+				geometry.Nodes[refIDs[start+i]] = rect
+			}
+		}
+	}
+
+	for refID, g := range geometry.Nodes {
+		if g == nil {
+			delete(geometry.Nodes, refID)
+		}
+	}
+	return geometry, nil
+}
+
+// GetSnapshotGeometry 测量快照中可交互元素（序列化输出的元素）的几何信息
+func (e *Executor) GetSnapshotGeometry(ctx context.Context, snapshot *AccessibilitySnapshot) (*SnapshotGeometry, error) {
+	page := e.activePage(ctx)
+	if page == nil {
+		return nil, fmt.Errorf("no active page")
+	}
+	nodes := append(snapshot.GetClickableElements(), snapshot.GetInputElements()...)
+	return CollectSnapshotGeometry(ctx, page, nodes)
+}
+
+// describe 生成节点几何信息的文本描述，如 " [x=10 y=20 w=100 h=30]" 或 " [hidden]"
+func (g *SnapshotGeometry) describe(refID string) string {
+	if g == nil {
+		return ""
+	}
+	node, ok := g.Nodes[refID]
+	if !ok {
+		return ""
+	}
+	if !node.Visible {
+		return " [hidden]"
+	}
+	text := fmt.Sprintf(" [x=%.0f y=%.0f w=%.0f h=%.0f", node.X, node.Y, node.Width, node.Height)
+	if !node.InViewport {
+		text += " offscreen"
+	}
+	return text + "]"
+}
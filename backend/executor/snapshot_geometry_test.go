@@ -0,0 +1,40 @@
+package executor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSerializeWithGeometry(t *testing.T) {
+	snapshot := &AccessibilitySnapshot{Elements: map[string]*AccessibilityNode{
+		"1": {ID: "1", RefID: "e1", BackendNodeID: 11, Role: "button", Label: "Buy", Metadata: map[string]interface{}{}},
+		"2": {ID: "2", RefID: "e2", BackendNodeID: 12, Role: "link", Label: "Footer", Metadata: map[string]interface{}{}},
+		"3": {ID: "3", RefID: "e3", BackendNodeID: 13, Role: "textbox", Label: "Search", Metadata: map[string]interface{}{}},
+	}}
+	geometry := &SnapshotGeometry{
+		ViewportWidth:  1280,
+		ViewportHeight: 720,
+		ScrollY:        100,
+		Nodes: map[string]*NodeGeometry{
+			"e1": {X: 10, Y: 20, Width: 80, Height: 30, Visible: true, InViewport: true},
+			"e2": {X: 0, Y: 2400, Width: 60, Height: 20, Visible: true},
+			"e3": {},
+		},
+	}
+
+	text := snapshot.SerializeWithGeometry(geometry)
+	for _, want := range []string{
+		"viewport 1280x720, scrolled to 0,100",
+		"@e1 - Buy (button) [x=10 y=20 w=80 h=30]\n",
+		"@e2 - Footer (link) [x=0 y=2400 w=60 h=20 offscreen]\n",
+		"@e3 - Search (textbox) [hidden]\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("serialized snapshot missing %q:\n%s", want, text)
+		}
+	}
+
+	if plain := snapshot.SerializeToSimpleText(); strings.Contains(plain, "[x=") || strings.Contains(plain, "viewport") {
+		t.Errorf("geometry should be opt-in:\n%s", plain)
+	}
+}
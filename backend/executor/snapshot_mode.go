@@ -0,0 +1,106 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/browserwing/browserwing/pkg/logger"
+)
+
+// SnapshotMode 操作（导航、点击、输入、选择）完成后是否提取页面的可访问性快照
+// 大页面提取一次快照需要数秒，确定性的长脚本不需要每步都返回快照
+type SnapshotMode string
+
+const (
+	SnapshotInclude SnapshotMode = ""      // 默认：提取快照并随结果返回
+	SnapshotSkip    SnapshotMode = "skip"  // 不提取，之前快照中的 RefID 继续有效
+	SnapshotDefer   SnapshotMode = "defer" // 不提取，并把缓存的快照标记为过时，下次需要快照时重新获取
+)
+
+// ParseSnapshotMode 解析快照提取方式，支持 include（默认）、skip、defer
+func ParseSnapshotMode(value string) (SnapshotMode, error) {
+	switch value {
+	case "", "include":
+		return SnapshotInclude, nil
+	case string(SnapshotSkip), string(SnapshotDefer):
+		return SnapshotMode(value), nil
+	}
+	return "", fmt.Errorf("invalid snapshot mode %q (want include, skip or defer)", value)
+}
+
+// snapshotModeParam 读取批量操作和 MCP 参数中的 snapshot，无效值按默认处理
+func ParseSnapshotArgument(params map[string]interface{}) SnapshotMode {
+	value, _ := params["snapshot"].(string)
+	mode, err := ParseSnapshotMode(value)
+	if err != nil {
+		return SnapshotInclude
+	}
+	return mode
+}
+
+// snapshotAfter 操作完成后按提取方式返回快照文本，跳过或推迟时返回空
+func (e *Executor) snapshotAfter(ctx context.Context, mode SnapshotMode) string {
+	switch mode {
+	case SnapshotSkip:
+		return ""
+	case SnapshotDefer:
+		e.markSnapshotStale(ctx)
+		return ""
+	}
+
+	snapshot, err := e.GetAccessibilitySnapshot(ctx)
+	if err != nil {
+		logger.Error(ctx, "Failed to get accessibility snapshot: %s", err.Error())
+		return ""
+	}
+	return snapshot.SerializeToSimpleText()
+}
+
+// markSnapshotStale 把当前页面缓存的快照标记为过时，已分配的 RefID 仍可解析
+func (e *Executor) markSnapshotStale(ctx context.Context) {
+	page := e.activePage(ctx)
+	if page == nil {
+		return
+	}
+	e.refIDMutex.Lock()
+	defer e.refIDMutex.Unlock()
+	if cache := e.refCaches[refCacheKey{session: sessionIDFromContext(ctx), target: page.TargetID}]; cache != nil {
+		cache.stale = true
+	}
+}
+
+// defaultNavigateOptions 导航的默认选项
+func (e *Executor) defaultNavigateOptions() *NavigateOptions {
+	return &NavigateOptions{
+		WaitUntil: "load",
+		Timeout:   e.timeouts().Navigation(),
+	}
+}
+
+// defaultClickOptions 点击的默认选项
+func (e *Executor) defaultClickOptions() *ClickOptions {
+	return &ClickOptions{
+		WaitVisible: true,
+		WaitEnabled: true,
+		Timeout:     e.timeouts().Element(),
+		Button:      "left",
+		ClickCount:  1,
+	}
+}
+
+// defaultTypeOptions 输入的默认选项
+func (e *Executor) defaultTypeOptions() *TypeOptions {
+	return &TypeOptions{
+		Clear:       true,
+		WaitVisible: true,
+		Timeout:     e.timeouts().Element(),
+	}
+}
+
+// defaultSelectOptions 选择的默认选项
+func (e *Executor) defaultSelectOptions() *SelectOptions {
+	return &SelectOptions{
+		WaitVisible: true,
+		Timeout:     e.timeouts().Element(),
+	}
+}
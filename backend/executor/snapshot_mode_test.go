@@ -0,0 +1,29 @@
+package executor
+
+import "testing"
+
+func TestParseSnapshotMode(t *testing.T) {
+	for value, want := range map[string]SnapshotMode{
+		"":        SnapshotInclude,
+		"include": SnapshotInclude,
+		"skip":    SnapshotSkip,
+		"defer":   SnapshotDefer,
+	} {
+		if got, err := ParseSnapshotMode(value); err != nil || got != want {
+			t.Errorf("ParseSnapshotMode(%q) = %q, %v; want %q", value, got, err, want)
+		}
+	}
+	if _, err := ParseSnapshotMode("lazy"); err == nil {
+		t.Error("unknown snapshot mode should fail")
+	}
+
+	if got := ParseSnapshotArgument(map[string]interface{}{"snapshot": "skip"}); got != SnapshotSkip {
+		t.Errorf("argument skip: got %q", got)
+	}
+	if got := ParseSnapshotArgument(map[string]interface{}{"snapshot": "lazy"}); got != SnapshotInclude {
+		t.Errorf("invalid argument should fall back to include, got %q", got)
+	}
+	if got := ParseSnapshotArgument(map[string]interface{}{}); got != SnapshotInclude {
+		t.Errorf("missing argument should default to include, got %q", got)
+	}
+}
@@ -0,0 +1,96 @@
+package executor
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// 快照分页的单页大小（字节）
+const (
+	DefaultSnapshotChunkSize = 40000   // 默认单页大小，大页面的完整快照可能有数 MB
+	minSnapshotChunkSize     = 2000    // 单页最小值，至少容纳标题和若干元素
+	maxSnapshotChunkSize     = 1 << 20 // 单页最大值
+)
+
+// SnapshotChunk 分页序列化的一页快照
+type SnapshotChunk struct {
+	Text       string `json:"text"`
+	NextCursor string `json:"next_cursor,omitempty"` // 下一页的游标，为空表示已是最后一页
+	Restarted  bool   `json:"restarted,omitempty"`   // 游标对应的快照已变化（页面已更新），从第一页重新开始
+}
+
+// SerializeChunk 按大小分页序列化简单文本，cursor 为空时返回第一页
+// 游标记录行号和快照指纹，快照变化后旧游标从第一页重新开始，避免拼接出两个版本的 RefID
+func (tree *AccessibilitySnapshot) SerializeChunk(geometry *SnapshotGeometry, cursor string, maxBytes int) (*SnapshotChunk, error) {
+	maxBytes = normalizeSnapshotChunkSize(maxBytes)
+	offset, fingerprint, err := parseSnapshotCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := fnv.New64a()
+	for line := range tree.simpleTextLines(geometry) {
+		hash.Write([]byte(line))
+	}
+	current := strconv.FormatUint(hash.Sum64(), 36)
+
+	chunk := &SnapshotChunk{}
+	if cursor != "" && fingerprint != current {
+		chunk.Restarted = true
+		offset = 0
+	}
+
+	var builder strings.Builder
+	switch {
+	case chunk.Restarted:
+		builder.WriteString("(The page changed since the previous chunk; starting over. Discard RefIDs from earlier chunks.)\n")
+	case offset > 0:
+		builder.WriteString(fmt.Sprintf("... continued from line %d\n", offset+1))
+	}
+
+	index, written := 0, 0
+	for line := range tree.simpleTextLines(geometry) {
+		if index >= offset {
+			if written > 0 && builder.Len()+len(line) > maxBytes {
+				chunk.NextCursor = fmt.Sprintf("%d.%s", index, current)
+				break
+			}
+			builder.WriteString(line)
+			written++
+		}
+		index++
+	}
+	if chunk.NextCursor != "" {
+		builder.WriteString(fmt.Sprintf("... (more elements: call browser_snapshot with cursor %q for the next chunk)\n", chunk.NextCursor))
+	}
+	chunk.Text = builder.String()
+	return chunk, nil
+}
+
+// normalizeSnapshotChunkSize 限制单页大小，未设置时使用默认值
+func normalizeSnapshotChunkSize(maxBytes int) int {
+	switch {
+	case maxBytes <= 0:
+		return DefaultSnapshotChunkSize
+	case maxBytes < minSnapshotChunkSize:
+		return minSnapshotChunkSize
+	case maxBytes > maxSnapshotChunkSize:
+		return maxSnapshotChunkSize
+	}
+	return maxBytes
+}
+
+// parseSnapshotCursor 解析分页游标（行号.快照指纹）
+func parseSnapshotCursor(cursor string) (int, string, error) {
+	if cursor == "" {
+		return 0, "", nil
+	}
+	line, fingerprint, ok := strings.Cut(cursor, ".")
+	offset, err := strconv.Atoi(line)
+	if !ok || err != nil || offset < 0 || fingerprint == "" {
+		return 0, "", fmt.Errorf("invalid snapshot cursor %q", cursor)
+	}
+	return offset, fingerprint, nil
+}
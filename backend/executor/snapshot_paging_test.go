@@ -0,0 +1,99 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+func pagingSnapshot(n int) *AccessibilitySnapshot {
+	elements := map[string]*AccessibilityNode{}
+	for i := 1; i <= n; i++ {
+		id := fmt.Sprint(i)
+		elements[id] = &AccessibilityNode{
+			ID:            id,
+			RefID:         fmt.Sprintf("e%d", i),
+			BackendNodeID: proto.DOMBackendNodeID(i),
+			Role:          "link",
+			Label:         fmt.Sprintf("Result %d", i),
+			Metadata:      map[string]interface{}{},
+		}
+	}
+	return &AccessibilitySnapshot{Elements: elements}
+}
+
+func TestSerializeChunk(t *testing.T) {
+	snapshot := pagingSnapshot(500)
+	full := snapshot.SerializeToSimpleText()
+
+	// 逐页读取，拼接后与完整文本的元素行一致
+	var pages []string
+	cursor := ""
+	for i := 0; ; i++ {
+		if i > 100 {
+			t.Fatal("paging did not terminate")
+		}
+		chunk, err := snapshot.SerializeChunk(nil, cursor, minSnapshotChunkSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if chunk.Restarted {
+			t.Fatal("unchanged snapshot should not restart")
+		}
+		if len(chunk.Text) > minSnapshotChunkSize+200 {
+			t.Errorf("chunk %d too large: %d bytes", i, len(chunk.Text))
+		}
+		pages = append(pages, chunk.Text)
+		if chunk.NextCursor == "" {
+			break
+		}
+		cursor = chunk.NextCursor
+	}
+	if len(pages) < 2 {
+		t.Fatalf("expected several chunks, got %d", len(pages))
+	}
+
+	var joined strings.Builder
+	for _, page := range pages {
+		for _, line := range strings.SplitAfter(page, "\n") {
+			if strings.HasPrefix(line, "... ") {
+				continue
+			}
+			joined.WriteString(line)
+		}
+	}
+	if joined.String() != full {
+		t.Errorf("joined chunks differ from the full snapshot")
+	}
+	if !strings.Contains(full, "@e2 - Result 2 (link)\n  @e3 - Result 3 (link)\n") {
+		t.Errorf("elements should be ordered by RefID")
+	}
+
+	// 快照变化后旧游标从第一页重新开始
+	chunk, err := pagingSnapshot(400).SerializeChunk(nil, pagesCursor(t, snapshot), minSnapshotChunkSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !chunk.Restarted || !strings.Contains(chunk.Text, "=== Interactive Elements ===") {
+		t.Errorf("stale cursor should restart from the first chunk:\n%s", chunk.Text)
+	}
+
+	if _, err := snapshot.SerializeChunk(nil, "garbage", 0); err == nil {
+		t.Error("invalid cursor should fail")
+	}
+
+	// 默认大小足够容纳小页面
+	if chunk, _ := pagingSnapshot(3).SerializeChunk(nil, "", 0); chunk.NextCursor != "" || chunk.Text != pagingSnapshot(3).SerializeToSimpleText() {
+		t.Errorf("small snapshot should fit in one chunk:\n%s", chunk.Text)
+	}
+}
+
+func pagesCursor(t *testing.T, snapshot *AccessibilitySnapshot) string {
+	chunk, err := snapshot.SerializeChunk(nil, "", minSnapshotChunkSize)
+	if err != nil || chunk.NextCursor == "" {
+		t.Fatalf("expected a next cursor: %v", err)
+	}
+	return chunk.NextCursor
+}
@@ -0,0 +1,365 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/browserwing/browserwing/config"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// context key
+type contextKey string
+
+const tabIDKey contextKey = "tab_id"
+
+// WithTab 指定本次操作作用的标签页（TabID 即页面的 TargetID）
+func WithTab(ctx context.Context, tabID string) context.Context {
+	if tabID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, tabIDKey, tabID)
+}
+
+// tabIDFromContext 从 context 中获取指定的标签页 ID
+func tabIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if id, ok := ctx.Value(tabIDKey).(string); ok {
+		return id
+	}
+	return ""
+}
+
+const instanceIDKey contextKey = "instance_id"
+
+// WithInstance 指定本次操作作用的浏览器实例，未同时指定标签页时使用该实例的活动页面
+func WithInstance(ctx context.Context, instanceID string) context.Context {
+	if instanceID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, instanceIDKey, instanceID)
+}
+
+// instanceIDFromContext 从 context 中获取指定的浏览器实例 ID
+func instanceIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if id, ok := ctx.Value(instanceIDKey).(string); ok {
+		return id
+	}
+	return ""
+}
+
+const sessionIDKey contextKey = "session_id"
+
+// WithSession 指定本次操作所属的会话（非 MCP 调用方使用，例如 Agent 任务）
+func WithSession(ctx context.Context, sessionID string) context.Context {
+	if sessionID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, sessionIDKey, sessionID)
+}
+
+// AgentSessionID 生成 Agent 会话对应的 Executor 会话 ID
+func AgentSessionID(conversationID string) string {
+	return "agent:" + conversationID
+}
+
+// sessionIDFromContext 获取会话 ID：优先使用显式指定的会话，其次是 MCP 会话，都没有时返回空字符串
+func sessionIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if id, ok := ctx.Value(sessionIDKey).(string); ok && id != "" {
+		return id
+	}
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+	return ""
+}
+
+// sessionState 会话的标签页状态
+type sessionState struct {
+	activeTab proto.TargetTargetID   // 活动标签页
+	ownedTabs []proto.TargetTargetID // 为会话隔离而创建的标签页，会话结束时关闭
+	incognito *rod.Browser           // 会话独立的浏览器上下文（session_isolation=context 时）
+}
+
+// activePage 获取本次操作的目标页面
+// 优先级：context 指定的实例（及其标签页）> context 指定的标签页 > 会话的活动标签页（隔离模式下按需创建）> 全局活动页面
+func (e *Executor) activePage(ctx context.Context) *rod.Page {
+	if instanceID := instanceIDFromContext(ctx); instanceID != "" {
+		page, err := e.instancePage(instanceID, tabIDFromContext(ctx))
+		if err != nil {
+			logger.Warn(ctx, "Failed to get page of instance %s: %v", instanceID, err)
+			return nil
+		}
+		return page
+	}
+
+	if tabID := tabIDFromContext(ctx); tabID != "" {
+		page, err := e.findTab(tabID)
+		if err != nil {
+			logger.Warn(ctx, "Failed to find tab %s: %v", tabID, err)
+			return nil
+		}
+		return page
+	}
+
+	if sessionID := sessionIDFromContext(ctx); sessionID != "" {
+		if page := e.sessionPage(ctx, sessionID); page != nil {
+			return page
+		}
+	}
+
+	return e.Browser.GetActivePage()
+}
+
+// sessionPage 获取会话的活动页面，隔离模式下会话没有可用页面时自动创建
+// 共享模式下没有记录时返回 nil（使用全局活动页面）
+func (e *Executor) sessionPage(ctx context.Context, sessionID string) *rod.Page {
+	e.tabMutex.Lock()
+	defer e.tabMutex.Unlock()
+
+	state := e.sessions[sessionID]
+	if state != nil && state.activeTab != "" {
+		if page, err := e.findTab(string(state.activeTab)); err == nil {
+			return page
+		}
+		// 标签页已关闭
+		state.activeTab = ""
+	}
+
+	mode := e.Browser.GetSessionIsolation()
+	if mode == config.SessionIsolationShared {
+		return nil
+	}
+
+	if state == nil {
+		state = &sessionState{}
+		e.sessions[sessionID] = state
+	}
+
+	if mode == config.SessionIsolationContext && state.incognito == nil {
+		incognito, err := e.Browser.NewBrowserContext(ctx)
+		if err != nil {
+			logger.Warn(ctx, "Failed to create browser context for session %s: %v", sessionID, err)
+			return nil
+		}
+		state.incognito = incognito
+	}
+
+	page, err := e.Browser.NewIsolatedPage(ctx, state.incognito)
+	if err != nil {
+		logger.Warn(ctx, "Failed to create page for session %s: %v", sessionID, err)
+		return nil
+	}
+	state.activeTab = page.TargetID
+	state.ownedTabs = append(state.ownedTabs, page.TargetID)
+
+	logger.Info(ctx, "Session %s is using isolated page %s (mode: %s)", sessionID, page.TargetID, mode)
+	return page
+}
+
+// sessionContext 获取会话独立的浏览器上下文，没有时返回 nil
+func (e *Executor) sessionContext(ctx context.Context) *rod.Browser {
+	sessionID := sessionIDFromContext(ctx)
+	if sessionID == "" {
+		return nil
+	}
+	e.tabMutex.Lock()
+	defer e.tabMutex.Unlock()
+	if state := e.sessions[sessionID]; state != nil {
+		return state.incognito
+	}
+	return nil
+}
+
+// trackSessionTab 隔离模式下记录会话新建的标签页，会话结束时一并关闭
+func (e *Executor) trackSessionTab(ctx context.Context, page *rod.Page) {
+	sessionID := sessionIDFromContext(ctx)
+	if sessionID == "" || e.Browser.GetSessionIsolation() == config.SessionIsolationShared {
+		return
+	}
+	e.tabMutex.Lock()
+	defer e.tabMutex.Unlock()
+	if state := e.sessions[sessionID]; state != nil {
+		state.ownedTabs = append(state.ownedTabs, page.TargetID)
+	}
+}
+
+// setActiveTab 设置活动标签页：会话内只影响当前会话，否则更新全局活动页面
+func (e *Executor) setActiveTab(ctx context.Context, page *rod.Page) {
+	if sessionID := sessionIDFromContext(ctx); sessionID != "" {
+		e.tabMutex.Lock()
+		state := e.sessions[sessionID]
+		if state == nil {
+			state = &sessionState{}
+			e.sessions[sessionID] = state
+		}
+		state.activeTab = page.TargetID
+		e.tabMutex.Unlock()
+		// 切换标签页后，之前快照中的 RefID 不再有效
+		e.invalidateRefIDs(func(key refCacheKey) bool { return key.session == sessionID })
+		return
+	}
+	e.Browser.SetActivePage(page)
+	e.invalidateRefIDs(func(key refCacheKey) bool { return key.session == "" })
+}
+
+// forgetTab 清理所有会话中对已关闭标签页的引用，并停止该标签页上的响应捕获、WebSocket 监听、请求记录和对话框处理
+func (e *Executor) forgetTab(tabID proto.TargetTargetID) {
+	e.stopCapture(tabID)
+	e.stopWebSocketTap(tabID)
+	e.stopXHRRecorder(tabID)
+	e.stopDialogHandler(tabID)
+	e.invalidateRefIDs(func(key refCacheKey) bool { return key.target == tabID })
+
+	e.tabMutex.Lock()
+	defer e.tabMutex.Unlock()
+	for _, state := range e.sessions {
+		if state.activeTab == tabID {
+			state.activeTab = ""
+		}
+		for i, id := range state.ownedTabs {
+			if id == tabID {
+				state.ownedTabs = append(state.ownedTabs[:i], state.ownedTabs[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// ForgetSession 结束会话：清理活动标签页记录，并关闭为该会话隔离创建的页面和浏览器上下文
+func (e *Executor) ForgetSession(sessionID string) {
+	e.tabMutex.Lock()
+	state := e.sessions[sessionID]
+	delete(e.sessions, sessionID)
+	e.tabMutex.Unlock()
+
+	e.invalidateRefIDs(func(key refCacheKey) bool { return key.session == sessionID })
+
+	if state == nil {
+		return
+	}
+
+	ctx := context.Background()
+	for _, tabID := range state.ownedTabs {
+		e.stopCapture(tabID)
+		e.stopWebSocketTap(tabID)
+		e.stopXHRRecorder(tabID)
+		page, err := e.findTab(string(tabID))
+		if err != nil {
+			continue
+		}
+		if err := page.Close(); err != nil {
+			logger.Warn(ctx, "Failed to close page %s of session %s: %v", tabID, sessionID, err)
+		}
+	}
+	if state.incognito != nil {
+		if err := state.incognito.Close(); err != nil {
+			logger.Warn(ctx, "Failed to dispose browser context of session %s: %v", sessionID, err)
+		}
+	}
+}
+
+// findTab 根据标签页 ID 查找页面
+func (e *Executor) findTab(tabID string) (*rod.Page, error) {
+	if page := e.Browser.GetActivePage(); page != nil && string(page.TargetID) == tabID {
+		return page, nil
+	}
+
+	browser, err := e.Browser.GetBrowser()
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := browser.Pages()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tabs: %w", err)
+	}
+	for _, p := range pages {
+		if string(p.TargetID) == tabID {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("tab %s not found", tabID)
+}
+
+// openedPage 获取 OpenPage 新打开的页面：指定了实例时为该实例的活动页面，否则为全局活动页面
+func (e *Executor) openedPage(ctx context.Context) *rod.Page {
+	if instanceID := instanceIDFromContext(ctx); instanceID != "" {
+		page, _ := e.instancePage(instanceID, "")
+		return page
+	}
+	return e.Browser.GetActivePage()
+}
+
+// instancePage 获取指定实例中的标签页，tabID 为空时返回实例的活动页面
+func (e *Executor) instancePage(instanceID, tabID string) (*rod.Page, error) {
+	browser, page, err := e.Browser.GetInstanceBrowser(instanceID)
+	if err != nil {
+		return nil, err
+	}
+	if tabID == "" {
+		if page == nil {
+			return nil, fmt.Errorf("instance %s has no active page", instanceID)
+		}
+		return page, nil
+	}
+
+	pages, err := browser.Pages()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tabs: %w", err)
+	}
+	for _, p := range pages {
+		if string(p.TargetID) == tabID {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("tab %s not found in instance %s", tabID, instanceID)
+}
+
+// pageTabs 获取所有 type="page" 的标签页（排除扩展、devtools 等）
+func pageTabs(browser *rod.Browser) ([]*rod.Page, error) {
+	pages, err := browser.Pages()
+	if err != nil {
+		return nil, err
+	}
+
+	tabs := make([]*rod.Page, 0, len(pages))
+	for _, p := range pages {
+		info, err := p.Info()
+		if err != nil {
+			continue
+		}
+		if info.Type == "page" {
+			tabs = append(tabs, p)
+		}
+	}
+	return tabs, nil
+}
+
+// resolveTab 根据标签页 ID（优先）或索引定位标签页，返回页面及其当前索引
+func resolveTab(tabs []*rod.Page, tabID string, index int) (*rod.Page, int, error) {
+	if tabID != "" {
+		for i, p := range tabs {
+			if string(p.TargetID) == tabID {
+				return p, i, nil
+			}
+		}
+		return nil, -1, fmt.Errorf("tab %s not found", tabID)
+	}
+
+	if index < 0 || index >= len(tabs) {
+		return nil, -1, fmt.Errorf("tab index %d is out of range (0-%d)", index, len(tabs)-1)
+	}
+	return tabs[index], index, nil
+}
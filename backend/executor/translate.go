@@ -0,0 +1,223 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/browserwing/browserwing/llm"
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+)
+
+// 单次请求 LLM 翻译的段落数量和字符数上限
+const (
+	maxTranslateBatchSegments = 40
+	maxTranslateBatchChars    = 3000
+)
+
+// TranslateOptions 页面翻译选项
+type TranslateOptions struct {
+	TargetLanguage string // 目标语言（必需），如 "English"、"简体中文"
+	SourceLanguage string // 源语言（可选，为空时自动识别）
+	Selector       string // 只翻译该 CSS 选择器范围内的文本，为空时翻译整个页面
+	Overlay        bool   // 在页面中每个段落下方显示译文
+	MaxSegments    int    // 最多翻译的段落数，默认 200
+	LLMConfig      string // 使用的 LLM 配置名称，为空时使用默认配置
+}
+
+// TranslatedSegment 翻译后的段落
+type TranslatedSegment struct {
+	ID          int    `json:"id"`
+	Original    string `json:"original"`
+	Translation string `json:"translation"`
+}
+
+// textSegment 页面中的可见文本段落
+type textSegment struct {
+	ID   int    `json:"id"`
+	Text string `json:"text"`
+}
+
+// collectTextSegmentsScript 按块级元素收集页面中可见的文本段落，并为段落所在元素标记 ID（用于显示译文）
+const collectTextSegmentsScript = `(opts) => {
+	const root = opts.selector ? document.querySelector(opts.selector) : document.body;
+	if (!root) throw new Error('element not found: ' + opts.selector);
+	const skip = new Set(['SCRIPT', 'STYLE', 'NOSCRIPT', 'TEMPLATE', 'CODE', 'PRE', 'TEXTAREA', 'SVG', 'IFRAME', 'CANVAS']);
+	const inline = new Set(['inline', 'inline-block', 'contents']);
+	const blockOf = el => {
+		for (; el && el !== root; el = el.parentElement) {
+			if (!inline.has(getComputedStyle(el).display)) return el;
+		}
+		return root;
+	};
+	const walker = document.createTreeWalker(root, NodeFilter.SHOW_TEXT, {
+		acceptNode(node) {
+			if (!node.nodeValue.trim()) return NodeFilter.FILTER_REJECT;
+			for (let el = node.parentElement; el; el = el.parentElement) {
+				if (skip.has(el.tagName.toUpperCase()) || el.classList.contains('browserwing-translation')) return NodeFilter.FILTER_REJECT;
+				if (el === root) break;
+			}
+			return NodeFilter.FILTER_ACCEPT;
+		},
+	});
+	const blocks = new Map();
+	for (let node; (node = walker.nextNode());) {
+		const block = blockOf(node.parentElement);
+		if (!blocks.has(block)) blocks.set(block, []);
+		blocks.get(block).push(node.nodeValue);
+	}
+	const segments = [];
+	for (const [block, parts] of blocks) {
+		if (segments.length >= opts.maxSegments) break;
+		const rect = block.getBoundingClientRect();
+		const style = getComputedStyle(block);
+		if (!rect.width || !rect.height || style.visibility === 'hidden') continue;
+		const text = parts.join('').replace(/\s+/g, ' ').trim();
+		if (text.length < 2 || !/\p{L}/u.test(text)) continue;
+		const id = segments.length;
+		block.setAttribute('data-browserwing-tid', String(id));
+		segments.push({ id, text: text.slice(0, 2000) });
+	}
+	return segments;
+}`
+
+// overlayTranslationsScript 在段落所在元素末尾显示译文（重复调用时替换之前的译文）
+const overlayTranslationsScript = `(items) => {
+	document.querySelectorAll('.browserwing-translation').forEach(el => el.remove());
+	for (const item of items) {
+		const el = document.querySelector('[data-browserwing-tid="' + item.id + '"]');
+		if (!el) continue;
+		const t = document.createElement('div');
+		t.className = 'browserwing-translation';
+		t.textContent = item.translation;
+		t.style.cssText = 'color:#2563eb;font-size:0.95em;margin-top:2px;line-height:1.5;';
+		el.appendChild(t);
+	}
+	return true;
+}`
+
+// TranslatePage 提取当前页面的可见文本，通过 LLM 翻译并返回原文与译文对照，可选在页面中显示译文
+func (e *Executor) TranslatePage(ctx context.Context, opts *TranslateOptions) (*OperationResult, error) {
+	if opts == nil || opts.TargetLanguage == "" {
+		return nil, fmt.Errorf("target_language is required")
+	}
+	page := e.activePage(ctx)
+	if page == nil {
+		return nil, fmt.Errorf("no active page")
+	}
+
+	extractor, err := e.translationLLM(opts.LLMConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	maxSegments := opts.MaxSegments
+	if maxSegments <= 0 {
+		maxSegments = 200
+	}
+	res, err := page.Context(ctx).Eval(collectTextSegmentsScript, map[string]interface{}{
+		"selector":    opts.Selector,
+		"maxSegments": maxSegments,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract page text: %w", err)
+	}
+	var segments []textSegment
+	if err := res.Value.Unmarshal(&segments); err != nil {
+		return nil, fmt.Errorf("failed to parse page text: %w", err)
+	}
+
+	translated := make([]TranslatedSegment, 0, len(segments))
+	for _, batch := range batchTextSegments(segments) {
+		texts := make([]string, len(batch))
+		for i, s := range batch {
+			texts[i] = s.Text
+		}
+		translations, err := extractor.Translate(ctx, llm.TranslateRequest{
+			Texts:          texts,
+			TargetLanguage: opts.TargetLanguage,
+			SourceLanguage: opts.SourceLanguage,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("translation failed after %d of %d segments: %w", len(translated), len(segments), err)
+		}
+		for i, s := range batch {
+			translated = append(translated, TranslatedSegment{ID: s.ID, Original: s.Text, Translation: translations[i]})
+		}
+	}
+
+	info, _ := page.Info()
+	pageURL := ""
+	if info != nil {
+		pageURL = info.URL
+	}
+
+	if opts.Overlay && len(translated) > 0 {
+		if _, err := page.Context(ctx).Eval(overlayTranslationsScript, translated); err != nil {
+			logger.Warn(ctx, "Failed to overlay translations: %v", err)
+		}
+	}
+
+	return &OperationResult{
+		Success: true,
+		Message: fmt.Sprintf("Translated %d text segments to %s", len(translated), opts.TargetLanguage),
+		Data: map[string]interface{}{
+			"url":             pageURL,
+			"target_language": opts.TargetLanguage,
+			"overlay":         opts.Overlay,
+			"segments":        translated,
+		},
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// translationLLM 获取翻译使用的 LLM
+func (e *Executor) translationLLM(name string) (*llm.Extractor, error) {
+	manager := e.Browser.LLMManager()
+	if manager == nil {
+		return nil, fmt.Errorf("LLM is not configured")
+	}
+	if name != "" {
+		extractor, ok := manager.Get(name)
+		if !ok {
+			return nil, fmt.Errorf("LLM config not found: %s", name)
+		}
+		return extractor, nil
+	}
+	return manager.ForStep(models.LLMStepTranslation)
+}
+
+// batchTextSegments 按段落数量和字符数把段落分批，每批请求一次 LLM
+func batchTextSegments(segments []textSegment) [][]textSegment {
+	var batches [][]textSegment
+	var current []textSegment
+	chars := 0
+	for _, s := range segments {
+		n := len([]rune(s.Text))
+		if len(current) > 0 && (len(current) >= maxTranslateBatchSegments || chars+n > maxTranslateBatchChars) {
+			batches = append(batches, current)
+			current, chars = nil, 0
+		}
+		current = append(current, s)
+		chars += n
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+	return batches
+}
+
+// ParseTranslateArguments 解析 browser_translate_page 工具参数
+func ParseTranslateArguments(args map[string]interface{}) *TranslateOptions {
+	opts := &TranslateOptions{}
+	opts.TargetLanguage, _ = args["target_language"].(string)
+	opts.SourceLanguage, _ = args["source_language"].(string)
+	opts.Selector, _ = args["selector"].(string)
+	opts.Overlay, _ = args["overlay"].(bool)
+	opts.LLMConfig, _ = args["llm_config"].(string)
+	if v, ok := args["max_segments"].(float64); ok {
+		opts.MaxSegments = int(v)
+	}
+	return opts
+}
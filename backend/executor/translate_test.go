@@ -0,0 +1,27 @@
+package executor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBatchTextSegments(t *testing.T) {
+	var segments []textSegment
+	for i := 0; i < maxTranslateBatchSegments+5; i++ {
+		segments = append(segments, textSegment{ID: i, Text: "hello"})
+	}
+	batches := batchTextSegments(segments)
+	if len(batches) != 2 || len(batches[0]) != maxTranslateBatchSegments || len(batches[1]) != 5 {
+		t.Errorf("unexpected batch sizes: %d batches", len(batches))
+	}
+
+	long := strings.Repeat("a", maxTranslateBatchChars)
+	batches = batchTextSegments([]textSegment{{ID: 0, Text: "short"}, {ID: 1, Text: long}, {ID: 2, Text: long}})
+	if len(batches) != 3 {
+		t.Errorf("expected long segments to get their own batch, got %d batches", len(batches))
+	}
+
+	if batches := batchTextSegments(nil); len(batches) != 0 {
+		t.Errorf("expected no batches for no segments, got %d", len(batches))
+	}
+}
@@ -3,6 +3,8 @@ package executor
 import (
 	"time"
 
+	"github.com/browserwing/browserwing/models"
+
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/proto"
 )
@@ -77,6 +79,7 @@ type OperationResult struct {
 	Message   string                 `json:"message"`
 	Data      map[string]interface{} `json:"data,omitempty"`
 	Error     string                 `json:"error,omitempty"`
+	ErrorCode models.ErrorCode       `json:"error_code,omitempty"` // 失败原因分类码（ELEMENT_NOT_FOUND、TIMEOUT 等），无法归类时为空
 	Timestamp time.Time              `json:"timestamp"`
 }
 
@@ -84,6 +87,7 @@ type OperationResult struct {
 type NavigateOptions struct {
 	WaitUntil string        // 等待条件：load, domcontentloaded, networkidle
 	Timeout   time.Duration // 超时时间
+	Snapshot  SnapshotMode  // 导航后的快照提取方式
 }
 
 // ClickOptions 点击选项
@@ -93,6 +97,7 @@ type ClickOptions struct {
 	Timeout     time.Duration // 超时时间
 	Button      string        // 鼠标按钮：left, right, middle
 	ClickCount  int           // 点击次数
+	Snapshot    SnapshotMode  // 点击后的快照提取方式
 }
 
 // TypeOptions 输入选项
@@ -101,18 +106,29 @@ type TypeOptions struct {
 	WaitVisible bool          // 等待元素可见
 	Timeout     time.Duration // 超时时间
 	Delay       time.Duration // 每个字符之间的延迟
+	IME         bool          // 通过输入法组合事件输入（compositionstart/update/end），用于中日韩输入框
+	Snapshot    SnapshotMode  // 输入后的快照提取方式
 }
 
 // SelectOptions 选择选项
 type SelectOptions struct {
 	WaitVisible bool          // 等待元素可见
 	Timeout     time.Duration // 超时时间
+	Snapshot    SnapshotMode  // 选择后的快照提取方式
 }
 
 // WaitForOptions 等待选项
 type WaitForOptions struct {
 	Timeout time.Duration // 超时时间
 	State   string        // 等待状态：visible, hidden, attached, detached
+
+	// 以下条件可组合使用，全部满足才算等待成功
+	Text        string        // 元素文本包含的内容（需要 identifier）
+	URL         string        // 页面 URL 匹配的模式（支持 * 通配符，不含通配符时按子串匹配）
+	NetworkIdle bool          // 等待网络空闲
+	IdleTime    time.Duration // 网络空闲需持续的时间（默认 500ms）
+	MaxInflight int           // 网络空闲时允许的进行中请求数（默认 0）
+	Predicate   string        // 自定义 JS 条件，返回真值时满足（表达式或函数）
 }
 
 // ScreenshotOptions 截图选项
@@ -120,6 +136,7 @@ type ScreenshotOptions struct {
 	FullPage bool   // 是否截取完整页面
 	Quality  int    // 质量 (0-100)
 	Format   string // 格式：png, jpeg
+	FileName string // 保存的文件名（可选，会去掉目录部分，只保存在截图目录内）
 }
 
 // ExtractOptions 提取选项
@@ -143,5 +160,6 @@ type PressKeyOptions struct {
 	Shift bool // Shift 键
 	Alt   bool // Alt 键
 	Meta  bool // Meta 键 (Command on Mac, Windows key on Windows)
+	Mod   bool // 平台主修饰键：macOS 上为 Command，其他平台为 Ctrl
 }
 
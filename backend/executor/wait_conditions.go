@@ -0,0 +1,180 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/browserwing/browserwing/services/browser"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// 条件轮询间隔
+const waitPollInterval = 200 * time.Millisecond
+
+// hasPageConditions 是否设置了页面级等待条件（URL、网络空闲、JS 条件）
+func (o *WaitForOptions) hasPageConditions() bool {
+	return o.URL != "" || o.NetworkIdle || o.Predicate != ""
+}
+
+// pollUntil 轮询 check 直到返回 true、出错或超过截止时间
+func pollUntil(ctx context.Context, deadline time.Time, check func() (bool, error)) error {
+	for {
+		ok, err := check()
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("condition not met before timeout")
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(waitPollInterval):
+		}
+	}
+}
+
+// waitForText 等待元素文本包含指定内容
+func waitForText(ctx context.Context, elem *rod.Element, text string, deadline time.Time) error {
+	var last string
+	err := pollUntil(ctx, deadline, func() (bool, error) {
+		current, err := elem.Text()
+		if err != nil {
+			return false, err
+		}
+		last = current
+		return strings.Contains(current, text), nil
+	})
+	if err != nil {
+		return fmt.Errorf("text %q not found in element (last text: %q): %w", text, truncateText(last, 200), err)
+	}
+	return nil
+}
+
+// waitForURL 等待页面 URL 匹配模式（支持 * 通配符，不含通配符时按子串匹配）
+func waitForURL(ctx context.Context, page *rod.Page, pattern string, deadline time.Time) error {
+	var last string
+	err := pollUntil(ctx, deadline, func() (bool, error) {
+		info, err := page.Info()
+		if err != nil {
+			return false, err
+		}
+		last = info.URL
+		return browser.MatchURLPattern(pattern, info.URL), nil
+	})
+	if err != nil {
+		return fmt.Errorf("URL did not match %q (current: %s): %w", pattern, last, err)
+	}
+	return nil
+}
+
+// waitForPredicate 等待 JS 条件返回真值
+// predicate 可以是表达式（如 document.querySelectorAll('.row').length >= 10），也可以是函数
+func waitForPredicate(ctx context.Context, page *rod.Page, predicate string, deadline time.Time) error {
+	script := wrapPredicate(predicate)
+	err := pollUntil(ctx, deadline, func() (bool, error) {
+		res, err := page.Eval(script)
+		if err != nil {
+			return false, fmt.Errorf("failed to evaluate predicate: %w", err)
+		}
+		return res.Value.Bool(), nil
+	})
+	if err != nil {
+		return fmt.Errorf("predicate not satisfied: %w", err)
+	}
+	return nil
+}
+
+// wrapPredicate 将条件表达式包装为返回布尔值的函数
+func wrapPredicate(predicate string) string {
+	predicate = strings.TrimSpace(predicate)
+	if strings.HasPrefix(predicate, "()") ||
+		strings.HasPrefix(predicate, "function") ||
+		strings.HasPrefix(predicate, "async ") {
+		return fmt.Sprintf("async () => !!(await (%s)())", predicate)
+	}
+	return fmt.Sprintf("() => !!(%s)", predicate)
+}
+
+// networkIdleTracker 统计进行中的请求数
+type networkIdleTracker struct {
+	mu         sync.Mutex
+	inflight   map[proto.NetworkRequestID]bool
+	lastChange time.Time
+}
+
+func newNetworkIdleTracker() *networkIdleTracker {
+	return &networkIdleTracker{
+		inflight:   make(map[proto.NetworkRequestID]bool),
+		lastChange: time.Now(),
+	}
+}
+
+func (t *networkIdleTracker) start(id proto.NetworkRequestID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.inflight[id] = true
+	t.lastChange = time.Now()
+}
+
+func (t *networkIdleTracker) finish(id proto.NetworkRequestID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.inflight[id] {
+		delete(t.inflight, id)
+		t.lastChange = time.Now()
+	}
+}
+
+// idle 进行中的请求数不超过 maxInflight 且持续了 idleTime
+func (t *networkIdleTracker) idle(maxInflight int, idleTime time.Duration, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.inflight) <= maxInflight && now.Sub(t.lastChange) >= idleTime
+}
+
+// waitForNetworkIdle 等待进行中的请求数不超过 maxInflight 并持续 idleTime
+// 只统计开始等待之后发出的请求
+func waitForNetworkIdle(ctx context.Context, page *rod.Page, idleTime time.Duration, maxInflight int, deadline time.Time) error {
+	if err := (proto.NetworkEnable{}).Call(page); err != nil {
+		return fmt.Errorf("failed to enable network monitoring: %w", err)
+	}
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	tracker := newNetworkIdleTracker()
+	go page.Context(watchCtx).EachEvent(
+		func(ev *proto.NetworkRequestWillBeSent) {
+			tracker.start(ev.RequestID)
+		},
+		func(ev *proto.NetworkLoadingFinished) {
+			tracker.finish(ev.RequestID)
+		},
+		func(ev *proto.NetworkLoadingFailed) {
+			tracker.finish(ev.RequestID)
+		},
+	)()
+
+	err := pollUntil(ctx, deadline, func() (bool, error) {
+		return tracker.idle(maxInflight, idleTime, time.Now()), nil
+	})
+	if err != nil {
+		return fmt.Errorf("network did not become idle: %w", err)
+	}
+	return nil
+}
+
+func truncateText(s string, max int) string {
+	if len(s) > max {
+		return s[:max] + "..."
+	}
+	return s
+}
@@ -0,0 +1,49 @@
+package executor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWrapPredicate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"document.title === 'x'", "() => !!(document.title === 'x')"},
+		{"  () => window.ready ", "async () => !!(await (() => window.ready)())"},
+		{"function() { return 1 }", "async () => !!(await (function() { return 1 })())"},
+	}
+	for _, tt := range tests {
+		if got := wrapPredicate(tt.in); got != tt.want {
+			t.Errorf("wrapPredicate(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNetworkIdleTracker(t *testing.T) {
+	tracker := newNetworkIdleTracker()
+	start := tracker.lastChange
+
+	if !tracker.idle(0, 500*time.Millisecond, start.Add(time.Second)) {
+		t.Errorf("expected idle with no requests")
+	}
+
+	tracker.start("1")
+	tracker.start("2")
+	now := tracker.lastChange.Add(time.Second)
+	if tracker.idle(0, 500*time.Millisecond, now) {
+		t.Errorf("expected busy with 2 in-flight requests")
+	}
+	if tracker.idle(1, 500*time.Millisecond, now) {
+		t.Errorf("expected busy with 2 in-flight requests and threshold 1")
+	}
+
+	tracker.finish("1")
+	if !tracker.idle(1, 500*time.Millisecond, tracker.lastChange.Add(time.Second)) {
+		t.Errorf("expected idle with 1 in-flight request and threshold 1")
+	}
+	if tracker.idle(1, 500*time.Millisecond, tracker.lastChange.Add(100*time.Millisecond)) {
+		t.Errorf("expected not idle before idle time elapsed")
+	}
+}
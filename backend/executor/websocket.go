@@ -0,0 +1,268 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/browserwing/browserwing/pkg/artifacts"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/services/browser"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/google/uuid"
+)
+
+// 每个标签页最多保留的 WebSocket 帧数，超出后丢弃最早的帧
+const maxWebSocketFrames = 1000
+
+// WebSocketAction WebSocket 观察操作类型
+type WebSocketAction string
+
+const (
+	WebSocketActionStart WebSocketAction = "start"
+	WebSocketActionList   WebSocketAction = "list"
+	WebSocketActionExport WebSocketAction = "export"
+	WebSocketActionStop   WebSocketAction = "stop"
+)
+
+// WebSocketOptions WebSocket 观察选项
+type WebSocketOptions struct {
+	Action WebSocketAction // 操作类型：start, list, export, stop
+	URL    string          // 按连接 URL 过滤（支持 * 通配符，不含通配符时按子串匹配）
+	Opcode int             // 按操作码过滤（1=文本，2=二进制，0=不过滤）
+	Limit  int             // 最多返回的帧数（最新的帧，0=全部）
+}
+
+// WebSocketFrame WebSocket 帧
+type WebSocketFrame struct {
+	URL       string    `json:"url"`       // 连接 URL
+	Direction string    `json:"direction"` // sent 或 received
+	Opcode    int       `json:"opcode"`    // 操作码
+	Data      string    `json:"data"`      // 帧内容（二进制帧为 base64）
+	Timestamp time.Time `json:"timestamp"` // 捕获时间
+}
+
+// webSocketTap 标签页的 WebSocket 帧监听
+type webSocketTap struct {
+	mu     sync.Mutex
+	urls   map[proto.NetworkRequestID]string // 连接 ID -> URL
+	frames []WebSocketFrame
+	cancel context.CancelFunc
+}
+
+// record 记录一帧
+func (t *webSocketTap) record(requestID proto.NetworkRequestID, direction string, frame *proto.NetworkWebSocketFrame) {
+	if frame == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.frames = append(t.frames, WebSocketFrame{
+		URL:       t.urls[requestID],
+		Direction: direction,
+		Opcode:    int(frame.Opcode),
+		Data:      frame.PayloadData,
+		Timestamp: time.Now(),
+	})
+	if len(t.frames) > maxWebSocketFrames {
+		t.frames = t.frames[len(t.frames)-maxWebSocketFrames:]
+	}
+}
+
+// filter 按 URL 和操作码过滤帧，返回最新的 limit 帧
+func (t *webSocketTap) filter(url string, opcode int, limit int) []WebSocketFrame {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	frames := make([]WebSocketFrame, 0, len(t.frames))
+	for _, f := range t.frames {
+		if url != "" && !browser.MatchURLPattern(url, f.URL) {
+			continue
+		}
+		if opcode != 0 && f.Opcode != opcode {
+			continue
+		}
+		frames = append(frames, f)
+	}
+	if limit > 0 && len(frames) > limit {
+		frames = frames[len(frames)-limit:]
+	}
+	return frames
+}
+
+// connections 返回已知的连接 URL
+func (t *webSocketTap) connections() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	urls := make([]string, 0, len(t.urls))
+	for _, u := range t.urls {
+		urls = append(urls, u)
+	}
+	return urls
+}
+
+// WebSocket 观察当前页面的 WebSocket 流量
+// 先 start 开始监听（之后建立的连接才能获取 URL），再 list 查看帧（export 保存到文件），stop 停止监听
+func (e *Executor) WebSocket(ctx context.Context, opts *WebSocketOptions) (*OperationResult, error) {
+	page := e.activePage(ctx)
+	if page == nil {
+		return nil, fmt.Errorf("no active page")
+	}
+
+	switch opts.Action {
+	case WebSocketActionStart:
+		if err := e.startWebSocketTap(ctx, page); err != nil {
+			return &OperationResult{
+				Success:   false,
+				Error:     err.Error(),
+				Timestamp: time.Now(),
+				ErrorCode: errorCode(err),
+			}, err
+		}
+		return &OperationResult{
+			Success:   true,
+			Message:   "Started observing WebSocket traffic",
+			Timestamp: time.Now(),
+			Data: map[string]interface{}{
+				"tab_id": string(page.TargetID),
+			},
+		}, nil
+
+	case WebSocketActionList, WebSocketActionExport:
+		e.tabMutex.Lock()
+		tap := e.wsTaps[page.TargetID]
+		e.tabMutex.Unlock()
+		if tap == nil {
+			return nil, fmt.Errorf("websocket observation is not started on this tab, call with action='start' first")
+		}
+
+		frames := tap.filter(opts.URL, opts.Opcode, opts.Limit)
+		resultData := map[string]interface{}{
+			"frames":      frames,
+			"count":       len(frames),
+			"connections": tap.connections(),
+		}
+
+		message := fmt.Sprintf("Retrieved %d WebSocket frames", len(frames))
+		if opts.Action == WebSocketActionExport {
+			framesPath, err := e.saveWebSocketFrames(ctx, page.TargetID, frames)
+			if err != nil {
+				return &OperationResult{
+					Success:   false,
+					Error:     err.Error(),
+					Timestamp: time.Now(),
+					ErrorCode: errorCode(err),
+				}, err
+			}
+			resultData["path"] = framesPath
+			message = fmt.Sprintf("Exported %d WebSocket frames to: %s", len(frames), framesPath)
+		}
+
+		return &OperationResult{
+			Success:   true,
+			Message:   message,
+			Timestamp: time.Now(),
+			Data:      resultData,
+		}, nil
+
+	case WebSocketActionStop:
+		e.stopWebSocketTap(page.TargetID)
+		return &OperationResult{
+			Success:   true,
+			Message:   "Stopped observing WebSocket traffic",
+			Timestamp: time.Now(),
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown websocket action: %s", opts.Action)
+	}
+}
+
+// startWebSocketTap 开始监听页面的 WebSocket 帧，已在监听时不重复监听
+func (e *Executor) startWebSocketTap(ctx context.Context, page *rod.Page) error {
+	e.tabMutex.Lock()
+	if e.wsTaps == nil {
+		e.wsTaps = make(map[proto.TargetTargetID]*webSocketTap)
+	}
+	if _, exists := e.wsTaps[page.TargetID]; exists {
+		e.tabMutex.Unlock()
+		return nil
+	}
+	// 监听需独立于本次调用的 context，直到 stop 或页面关闭
+	tapCtx, cancel := context.WithCancel(context.Background())
+	tap := &webSocketTap{
+		urls:   make(map[proto.NetworkRequestID]string),
+		cancel: cancel,
+	}
+	e.wsTaps[page.TargetID] = tap
+	e.tabMutex.Unlock()
+
+	if err := (proto.NetworkEnable{}).Call(page); err != nil {
+		e.stopWebSocketTap(page.TargetID)
+		return fmt.Errorf("failed to enable network monitoring: %w", err)
+	}
+
+	go page.Context(tapCtx).EachEvent(
+		func(ev *proto.NetworkWebSocketCreated) {
+			tap.mu.Lock()
+			tap.urls[ev.RequestID] = ev.URL
+			tap.mu.Unlock()
+		},
+		func(ev *proto.NetworkWebSocketFrameSent) {
+			tap.record(ev.RequestID, "sent", ev.Response)
+		},
+		func(ev *proto.NetworkWebSocketFrameReceived) {
+			tap.record(ev.RequestID, "received", ev.Response)
+		},
+	)()
+
+	logger.Info(ctx, "Started observing WebSocket traffic on tab %s", page.TargetID)
+	return nil
+}
+
+// stopWebSocketTap 停止标签页上的 WebSocket 监听
+func (e *Executor) stopWebSocketTap(tabID proto.TargetTargetID) {
+	e.tabMutex.Lock()
+	tap := e.wsTaps[tabID]
+	delete(e.wsTaps, tabID)
+	e.tabMutex.Unlock()
+
+	if tap != nil {
+		tap.cancel()
+	}
+}
+
+// saveWebSocketFrames 将 WebSocket 帧导出到下载目录（按配置的路径模板分子目录）
+func (e *Executor) saveWebSocketFrames(ctx context.Context, tabID proto.TargetTargetID, frames []WebSocketFrame) (string, error) {
+	sandbox, err := e.Browser.DownloadSandbox()
+	if err != nil {
+		return "", err
+	}
+	subdir := e.Browser.ArtifactSubdir(artifacts.TemplateVars{})
+	if _, err := sandbox.Dir(subdir); err != nil {
+		return "", fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(frames, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal websocket frames: %w", err)
+	}
+
+	// 文件名：ws_frames_YYYYMMDD_HHMMSS_<随机后缀>.json，同一秒内多次导出不会互相覆盖
+	filename := fmt.Sprintf("ws_frames_%s_%s.json", time.Now().Format("20060102_150405"), uuid.NewString()[:8])
+	path, err := sandbox.Resolve(subdir, filename)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write websocket frames file: %w", err)
+	}
+
+	logger.Info(ctx, "WebSocket frames of tab %s exported to: %s", tabID, path)
+	return path, nil
+}
@@ -0,0 +1,384 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/services/browser"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// 每个标签页最多保留的 XHR/fetch 请求数
+const maxRecordedRequests = 100
+
+// 浏览器 fetch 不允许设置的请求头（由浏览器自动填充）
+var forbiddenReplayHeaders = map[string]bool{
+	"cookie":            true,
+	"host":              true,
+	"origin":            true,
+	"referer":           true,
+	"content-length":    true,
+	"connection":        true,
+	"accept-encoding":   true,
+	"user-agent":        true,
+	"transfer-encoding": true,
+}
+
+// XHRReplayAction XHR 重放操作类型
+type XHRReplayAction string
+
+const (
+	XHRReplayStart  XHRReplayAction = "start"
+	XHRReplayList   XHRReplayAction = "list"
+	XHRReplayReplay XHRReplayAction = "replay"
+	XHRReplayStop   XHRReplayAction = "stop"
+)
+
+// XHRReplayOptions XHR 重放选项
+type XHRReplayOptions struct {
+	Action    XHRReplayAction        // 操作类型：start, list, replay, stop
+	URL       string                 // 按 URL 过滤（action=list 时使用，支持 * 通配符）
+	RequestID string                 // 要重放的请求 ID（action=replay 时必需）
+	Variables map[string]interface{} // 重放时修改的变量（GraphQL variables、JSON 请求体字段或 URL 查询参数）
+}
+
+// RecordedRequest 记录的 XHR/fetch 请求
+type RecordedRequest struct {
+	ID       string            `json:"id"`
+	Type     string            `json:"type"` // XHR 或 Fetch
+	Method   string            `json:"method"`
+	URL      string            `json:"url"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	PostData string            `json:"post_data,omitempty"`
+	Status   int               `json:"status,omitempty"`
+}
+
+// xhrRecorder 标签页的 XHR/fetch 请求记录
+type xhrRecorder struct {
+	mu       sync.Mutex
+	requests []*RecordedRequest
+	cancel   context.CancelFunc
+}
+
+// find 根据 ID 查找请求
+func (r *xhrRecorder) find(id string) *RecordedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, req := range r.requests {
+		if req.ID == id {
+			copied := *req
+			return &copied
+		}
+	}
+	return nil
+}
+
+// list 按 URL 过滤请求，最新的在前
+func (r *xhrRecorder) list(pattern string) []RecordedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	requests := make([]RecordedRequest, 0, len(r.requests))
+	for i := len(r.requests) - 1; i >= 0; i-- {
+		req := r.requests[i]
+		if pattern != "" && !browser.MatchURLPattern(pattern, req.URL) {
+			continue
+		}
+		requests = append(requests, *req)
+	}
+	return requests
+}
+
+// XHRReplay 列出页面最近的 XHR/fetch 请求，并可修改变量后以页面身份（携带 Cookie）重新发起
+// 适合在界面登录后直接拉取分页 API 数据
+func (e *Executor) XHRReplay(ctx context.Context, opts *XHRReplayOptions) (*OperationResult, error) {
+	page := e.activePage(ctx)
+	if page == nil {
+		return nil, fmt.Errorf("no active page")
+	}
+
+	switch opts.Action {
+	case XHRReplayStart:
+		if err := e.startXHRRecorder(ctx, page); err != nil {
+			return &OperationResult{
+				Success:   false,
+				Error:     err.Error(),
+				Timestamp: time.Now(),
+				ErrorCode: errorCode(err),
+			}, err
+		}
+		return &OperationResult{
+			Success:   true,
+			Message:   "Started recording XHR/fetch requests",
+			Timestamp: time.Now(),
+		}, nil
+
+	case XHRReplayList:
+		recorder := e.xhrRecorderFor(page.TargetID)
+		if recorder == nil {
+			return nil, fmt.Errorf("request recording is not started on this tab, call with action='start' first")
+		}
+		requests := recorder.list(opts.URL)
+		return &OperationResult{
+			Success:   true,
+			Message:   fmt.Sprintf("Found %d XHR/fetch requests", len(requests)),
+			Timestamp: time.Now(),
+			Data: map[string]interface{}{
+				"requests": requests,
+			},
+		}, nil
+
+	case XHRReplayReplay:
+		recorder := e.xhrRecorderFor(page.TargetID)
+		if recorder == nil {
+			return nil, fmt.Errorf("request recording is not started on this tab, call with action='start' first")
+		}
+		req := recorder.find(opts.RequestID)
+		if req == nil {
+			return nil, fmt.Errorf("request %s not found", opts.RequestID)
+		}
+		return e.replayRequest(ctx, page, req, opts.Variables)
+
+	case XHRReplayStop:
+		e.stopXHRRecorder(page.TargetID)
+		return &OperationResult{
+			Success:   true,
+			Message:   "Stopped recording XHR/fetch requests",
+			Timestamp: time.Now(),
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown xhr replay action: %s", opts.Action)
+	}
+}
+
+// replayRequest 在页面中使用 fetch 重新发起请求（携带页面 Cookie）
+func (e *Executor) replayRequest(ctx context.Context, page *rod.Page, req *RecordedRequest, variables map[string]interface{}) (*OperationResult, error) {
+	replayURL, body, err := applyReplayVariables(req, variables)
+	if err != nil {
+		return &OperationResult{
+			Success:   false,
+			Error:     err.Error(),
+			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
+		}, err
+	}
+
+	if err := e.Browser.CheckURLPolicy(ctx, "", replayURL); err != nil {
+		return &OperationResult{
+			Success:   false,
+			Error:     err.Error(),
+			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
+		}, err
+	}
+
+	headers := make(map[string]string, len(req.Headers))
+	for name, value := range req.Headers {
+		lower := strings.ToLower(name)
+		if forbiddenReplayHeaders[lower] || strings.HasPrefix(lower, "sec-") || strings.HasPrefix(lower, ":") {
+			continue
+		}
+		headers[name] = value
+	}
+
+	logger.Info(ctx, "Replaying request %s: %s %s", req.ID, req.Method, replayURL)
+
+	result, err := page.Context(ctx).Eval(`async (url, method, headers, body) => {
+		const init = { method: method, headers: headers, credentials: 'include' };
+		if (body && method !== 'GET' && method !== 'HEAD') {
+			init.body = body;
+		}
+		const resp = await fetch(url, init);
+		const text = await resp.text();
+		return {
+			status: resp.status,
+			statusText: resp.statusText,
+			contentType: resp.headers.get('content-type') || '',
+			body: text
+		};
+	}`, replayURL, req.Method, headers, body)
+	if err != nil {
+		return &OperationResult{
+			Success:   false,
+			Error:     fmt.Sprintf("Failed to replay request: %s", err.Error()),
+			Timestamp: time.Now(),
+			ErrorCode: errorCode(err),
+		}, err
+	}
+
+	status := result.Value.Get("status").Int()
+	text := result.Value.Get("body").Str()
+
+	var responseBody interface{} = text
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(text), &parsed); err == nil {
+		responseBody = parsed
+	}
+
+	return &OperationResult{
+		Success:   status < 400,
+		Message:   fmt.Sprintf("Replayed %s %s (%d)", req.Method, replayURL, status),
+		Timestamp: time.Now(),
+		Data: map[string]interface{}{
+			"url":          replayURL,
+			"method":       req.Method,
+			"status":       status,
+			"content_type": result.Value.Get("contentType").Str(),
+			"body":         responseBody,
+		},
+	}, nil
+}
+
+// applyReplayVariables 将变量应用到请求上，返回新的 URL 和请求体
+// GraphQL 请求合并到 variables 字段；其他 JSON 请求体合并到顶层字段；没有请求体时设置为 URL 查询参数
+func applyReplayVariables(req *RecordedRequest, variables map[string]interface{}) (string, string, error) {
+	if len(variables) == 0 {
+		return req.URL, req.PostData, nil
+	}
+
+	if strings.TrimSpace(req.PostData) != "" {
+		var body map[string]interface{}
+		if err := json.Unmarshal([]byte(req.PostData), &body); err != nil {
+			return "", "", fmt.Errorf("request body is not a JSON object, variables cannot be applied")
+		}
+
+		target := body
+		if gqlVars, ok := body["variables"].(map[string]interface{}); ok {
+			target = gqlVars
+		} else if _, isGraphQL := body["query"]; isGraphQL {
+			target = make(map[string]interface{})
+			body["variables"] = target
+		}
+		for k, v := range variables {
+			target[k] = v
+		}
+
+		data, err := json.Marshal(body)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to encode request body: %w", err)
+		}
+		return req.URL, string(data), nil
+	}
+
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid request url: %w", err)
+	}
+	query := u.Query()
+
+	// GraphQL GET 请求：变量位于 variables 查询参数（JSON）
+	if raw := query.Get("variables"); raw != "" {
+		var gqlVars map[string]interface{}
+		if err := json.Unmarshal([]byte(raw), &gqlVars); err == nil {
+			for k, v := range variables {
+				gqlVars[k] = v
+			}
+			data, _ := json.Marshal(gqlVars)
+			query.Set("variables", string(data))
+			u.RawQuery = query.Encode()
+			return u.String(), "", nil
+		}
+	}
+
+	for k, v := range variables {
+		query.Set(k, fmt.Sprintf("%v", v))
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), "", nil
+}
+
+// startXHRRecorder 开始记录页面的 XHR/fetch 请求，已在记录时不重复记录
+func (e *Executor) startXHRRecorder(ctx context.Context, page *rod.Page) error {
+	e.tabMutex.Lock()
+	if e.xhrRecorders == nil {
+		e.xhrRecorders = make(map[proto.TargetTargetID]*xhrRecorder)
+	}
+	if _, exists := e.xhrRecorders[page.TargetID]; exists {
+		e.tabMutex.Unlock()
+		return nil
+	}
+	// 记录需独立于本次调用的 context，直到 stop 或页面关闭
+	recCtx, cancel := context.WithCancel(context.Background())
+	recorder := &xhrRecorder{cancel: cancel}
+	e.xhrRecorders[page.TargetID] = recorder
+	e.tabMutex.Unlock()
+
+	if err := (proto.NetworkEnable{}).Call(page); err != nil {
+		e.stopXHRRecorder(page.TargetID)
+		return fmt.Errorf("failed to enable network monitoring: %w", err)
+	}
+
+	go page.Context(recCtx).EachEvent(
+		func(ev *proto.NetworkRequestWillBeSent) {
+			if ev.Type != proto.NetworkResourceTypeXHR && ev.Type != proto.NetworkResourceTypeFetch {
+				return
+			}
+			req := &RecordedRequest{
+				ID:       string(ev.RequestID),
+				Type:     string(ev.Type),
+				Method:   ev.Request.Method,
+				URL:      ev.Request.URL,
+				Headers:  make(map[string]string, len(ev.Request.Headers)),
+				PostData: ev.Request.PostData,
+			}
+			for name, value := range ev.Request.Headers {
+				req.Headers[name] = value.String()
+			}
+			// 请求体过大时事件中不包含，需要单独获取
+			if req.PostData == "" && ev.Request.HasPostData {
+				if data, err := (proto.NetworkGetRequestPostData{RequestID: ev.RequestID}).Call(page); err == nil {
+					req.PostData = data.PostData
+				}
+			}
+
+			recorder.mu.Lock()
+			recorder.requests = append(recorder.requests, req)
+			if len(recorder.requests) > maxRecordedRequests {
+				recorder.requests = recorder.requests[len(recorder.requests)-maxRecordedRequests:]
+			}
+			recorder.mu.Unlock()
+		},
+		func(ev *proto.NetworkResponseReceived) {
+			if ev.Type != proto.NetworkResourceTypeXHR && ev.Type != proto.NetworkResourceTypeFetch {
+				return
+			}
+			recorder.mu.Lock()
+			for i := len(recorder.requests) - 1; i >= 0; i-- {
+				if recorder.requests[i].ID == string(ev.RequestID) {
+					recorder.requests[i].Status = ev.Response.Status
+					break
+				}
+			}
+			recorder.mu.Unlock()
+		},
+	)()
+
+	logger.Info(ctx, "Started recording XHR/fetch requests on tab %s", page.TargetID)
+	return nil
+}
+
+// xhrRecorderFor 获取标签页的请求记录
+func (e *Executor) xhrRecorderFor(tabID proto.TargetTargetID) *xhrRecorder {
+	e.tabMutex.Lock()
+	defer e.tabMutex.Unlock()
+	return e.xhrRecorders[tabID]
+}
+
+// stopXHRRecorder 停止标签页上的请求记录
+func (e *Executor) stopXHRRecorder(tabID proto.TargetTargetID) {
+	e.tabMutex.Lock()
+	recorder := e.xhrRecorders[tabID]
+	delete(e.xhrRecorders, tabID)
+	e.tabMutex.Unlock()
+
+	if recorder != nil {
+		recorder.cancel()
+	}
+}
@@ -0,0 +1,56 @@
+package executor
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+)
+
+func TestApplyReplayVariables(t *testing.T) {
+	t.Run("graphql variables", func(t *testing.T) {
+		req := &RecordedRequest{
+			URL:      "https://example.com/graphql",
+			PostData: `{"query":"query Items($page: Int) { items(page: $page) { id } }","variables":{"page":1,"size":20}}`,
+		}
+		_, body, err := applyReplayVariables(req, map[string]interface{}{"page": 2})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var got struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.Unmarshal([]byte(body), &got); err != nil {
+			t.Fatalf("invalid body: %v", err)
+		}
+		if got.Variables["page"] != float64(2) || got.Variables["size"] != float64(20) {
+			t.Errorf("variables = %v, want page=2 size=20", got.Variables)
+		}
+	})
+
+	t.Run("json body fields", func(t *testing.T) {
+		req := &RecordedRequest{URL: "https://example.com/api/list", PostData: `{"offset":0}`}
+		_, body, err := applyReplayVariables(req, map[string]interface{}{"offset": 50})
+		if err != nil || body != `{"offset":50}` {
+			t.Errorf("body = %s, err = %v", body, err)
+		}
+	})
+
+	t.Run("query parameters", func(t *testing.T) {
+		req := &RecordedRequest{URL: "https://example.com/api/list?page=1&q=go"}
+		got, _, err := applyReplayVariables(req, map[string]interface{}{"page": 3})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		u, _ := url.Parse(got)
+		if u.Query().Get("page") != "3" || u.Query().Get("q") != "go" {
+			t.Errorf("url = %s, want page=3 and q=go", got)
+		}
+	})
+
+	t.Run("non json body", func(t *testing.T) {
+		req := &RecordedRequest{URL: "https://example.com/form", PostData: "a=1&b=2"}
+		if _, _, err := applyReplayVariables(req, map[string]interface{}{"a": 2}); err == nil {
+			t.Errorf("expected error for non-JSON body")
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+// RoutingPolicy 获取 LLM 路由策略
+func (m *Manager) RoutingPolicy() (*models.LLMRoutingPolicy, error) {
+	return m.db.GetLLMRoutingPolicy()
+}
+
+// SetRoutingPolicy 校验并保存 LLM 路由策略。小模型必须是已启用的配置；
+// 未指定步骤时所有可路由的步骤都使用小模型
+func (m *Manager) SetRoutingPolicy(policy *models.LLMRoutingPolicy) error {
+	steps := []string{}
+	seen := make(map[string]bool)
+	for _, step := range policy.CheapSteps {
+		known := false
+		for _, s := range models.LLMCheapSteps {
+			known = known || s == step
+		}
+		if !known {
+			return fmt.Errorf("unknown step %q, expected one of %v", step, models.LLMCheapSteps)
+		}
+		if !seen[step] {
+			seen[step] = true
+			steps = append(steps, step)
+		}
+	}
+
+	if policy.CheapConfigID != "" {
+		cfg, err := m.db.GetLLMConfig(policy.CheapConfigID)
+		if err != nil {
+			return fmt.Errorf("LLM config not found: %s", policy.CheapConfigID)
+		}
+		if !cfg.IsActive {
+			return fmt.Errorf("LLM config %s is not active", policy.CheapConfigID)
+		}
+		if len(steps) == 0 {
+			steps = append(steps, models.LLMCheapSteps...)
+		}
+	}
+
+	policy.CheapSteps = steps
+
+	// 脚本推荐的嵌入模型通过 OpenAI 兼容的 /embeddings 接口调用
+	if policy.EmbeddingConfigID == "" {
+		policy.EmbeddingModel = ""
+	} else {
+		cfg, err := m.db.GetLLMConfig(policy.EmbeddingConfigID)
+		if err != nil {
+			return fmt.Errorf("LLM config not found: %s", policy.EmbeddingConfigID)
+		}
+		if !cfg.IsActive {
+			return fmt.Errorf("LLM config %s is not active", policy.EmbeddingConfigID)
+		}
+		if provider := strings.ToLower(cfg.Provider); provider == "claude" || provider == "anthropic" {
+			return fmt.Errorf("LLM config %s does not provide an embeddings API", policy.EmbeddingConfigID)
+		}
+		if policy.EmbeddingModel = strings.TrimSpace(policy.EmbeddingModel); policy.EmbeddingModel == "" {
+			return fmt.Errorf("an embedding model is required for script recommendation")
+		}
+	}
+	return m.db.SaveLLMRoutingPolicy(policy)
+}
+
+// ForStep 获取步骤使用的 Extractor：路由策略把步骤交给小模型且小模型可用时返回小模型，否则返回默认配置
+func (m *Manager) ForStep(step string) (*Extractor, error) {
+	if policy, err := m.db.GetLLMRoutingPolicy(); err == nil && policy.Routes(step) {
+		if extractor, ok := m.Get(policy.CheapConfigID); ok {
+			return extractor, nil
+		}
+	}
+	return m.GetDefault()
+}
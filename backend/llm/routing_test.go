@@ -0,0 +1,74 @@
+package llm
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/storage"
+)
+
+func TestRoutingPolicy(t *testing.T) {
+	db, err := storage.NewBoltDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	m := NewManager(db)
+	for _, cfg := range []*models.LLMConfigModel{
+		{ID: "large", Name: "large", Provider: "openai", APIKey: "k", Model: "gpt-4o", IsDefault: true, IsActive: true},
+		{ID: "small", Name: "small", Provider: "openai", APIKey: "k", Model: "gpt-4o-mini", IsActive: true},
+		{ID: "off", Name: "off", Provider: "openai", APIKey: "k", Model: "gpt-4o-mini"},
+	} {
+		if err := m.Add(cfg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 未设置策略时使用默认模型
+	if e, err := m.ForStep(models.LLMStepTranslation); err != nil || e.config.Name != "large" {
+		t.Fatalf("expected the default model, got %v, %v", e, err)
+	}
+
+	if err := m.SetRoutingPolicy(&models.LLMRoutingPolicy{CheapConfigID: "small", CheapSteps: []string{"planning"}}); err == nil {
+		t.Error("unknown steps should be rejected")
+	}
+	if err := m.SetRoutingPolicy(&models.LLMRoutingPolicy{CheapConfigID: "off"}); err == nil {
+		t.Error("inactive configs should be rejected")
+	}
+	if err := m.SetRoutingPolicy(&models.LLMRoutingPolicy{CheapConfigID: "small"}); err != nil {
+		t.Fatal(err)
+	}
+	policy, _ := m.RoutingPolicy()
+	if len(policy.CheapSteps) != len(models.LLMCheapSteps) {
+		t.Errorf("an empty step list should route all cheap steps, got %v", policy.CheapSteps)
+	}
+
+	if err := m.SetRoutingPolicy(&models.LLMRoutingPolicy{EmbeddingConfigID: "small"}); err == nil {
+		t.Error("script recommendation should require an embedding model")
+	}
+	if err := m.SetRoutingPolicy(&models.LLMRoutingPolicy{EmbeddingConfigID: "small", EmbeddingModel: " text-embedding-3-small "}); err != nil {
+		t.Fatal(err)
+	}
+	if policy, _ := m.RoutingPolicy(); policy.EmbeddingModel != "text-embedding-3-small" {
+		t.Errorf("expected a trimmed embedding model, got %q", policy.EmbeddingModel)
+	}
+
+	if err := m.SetRoutingPolicy(&models.LLMRoutingPolicy{CheapConfigID: "small", CheapSteps: []string{models.LLMStepTranslation}}); err != nil {
+		t.Fatal(err)
+	}
+	if e, _ := m.ForStep(models.LLMStepTranslation); e == nil || e.config.Name != "small" {
+		t.Errorf("translation should use the small model, got %v", e)
+	}
+	if e, _ := m.ForStep(models.LLMStepMCPInfo); e == nil || e.config.Name != "large" {
+		t.Errorf("steps outside the policy should use the default model, got %v", e)
+	}
+
+	// 小模型被删除后回退到默认模型
+	if err := m.Delete("small"); err != nil {
+		t.Fatal(err)
+	}
+	if e, _ := m.ForStep(models.LLMStepTranslation); e == nil || e.config.Name != "large" {
+		t.Errorf("expected a fallback to the default model, got %v", e)
+	}
+}
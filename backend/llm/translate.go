@@ -0,0 +1,88 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gotoailab/llmhub"
+)
+
+// TranslateRequest 翻译请求
+type TranslateRequest struct {
+	Texts          []string // 待翻译的文本段落
+	TargetLanguage string   // 目标语言，如 "English"、"简体中文"
+	SourceLanguage string   // 源语言（可选，为空时由模型自动识别）
+}
+
+// Translate 将一组文本段落翻译为目标语言，返回与输入一一对应的译文
+func (e *Extractor) Translate(ctx context.Context, req TranslateRequest) ([]string, error) {
+	if len(req.Texts) == 0 {
+		return nil, nil
+	}
+	if req.TargetLanguage == "" {
+		return nil, fmt.Errorf("target language is required")
+	}
+
+	resp, err := e.llmClient.ChatCompletions(ctx, llmhub.ChatCompletionRequest{
+		Messages: []llmhub.ChatMessage{
+			{Role: "user", Content: buildTranslatePrompt(req)},
+		},
+		Temperature: floatPtr(0.2),
+		MaxTokens:   intPtr(4000),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to call LLM: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("LLM did not return any results")
+	}
+	content, ok := resp.Choices[0].Message.Content.(string)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse LLM response content")
+	}
+
+	return parseTranslations(content, len(req.Texts))
+}
+
+// buildTranslatePrompt 构建翻译提示词，输入和输出都使用 JSON 字符串数组以保证段落一一对应
+func buildTranslatePrompt(req TranslateRequest) string {
+	input, _ := json.Marshal(req.Texts)
+
+	var sb strings.Builder
+	sb.WriteString("你是一个专业的网页翻译。请把下面 JSON 数组中的每个文本段落翻译为")
+	sb.WriteString(req.TargetLanguage)
+	sb.WriteString("。")
+	if req.SourceLanguage != "" {
+		sb.WriteString("源语言为")
+		sb.WriteString(req.SourceLanguage)
+		sb.WriteString("。")
+	}
+	sb.WriteString("\n\n要求：\n")
+	sb.WriteString("1. 只输出一个 JSON 字符串数组，不要输出任何解释\n")
+	sb.WriteString(fmt.Sprintf("2. 数组长度必须为 %d，第 i 个元素是第 i 个段落的译文\n", len(req.Texts)))
+	sb.WriteString("3. 已经是目标语言的段落、代码、网址、数字和专有名词保持原样\n\n")
+	sb.WriteString("段落：\n")
+	sb.Write(input)
+	return sb.String()
+}
+
+// parseTranslations 解析模型返回的译文数组（允许包含 markdown 代码块）
+func parseTranslations(content string, expected int) ([]string, error) {
+	content = strings.TrimSpace(content)
+	start := strings.Index(content, "[")
+	end := strings.LastIndex(content, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("LLM response does not contain a JSON array")
+	}
+
+	var translations []string
+	if err := json.Unmarshal([]byte(content[start:end+1]), &translations); err != nil {
+		return nil, fmt.Errorf("failed to parse translations: %w", err)
+	}
+	if len(translations) != expected {
+		return nil, fmt.Errorf("LLM returned %d translations for %d segments", len(translations), expected)
+	}
+	return translations, nil
+}
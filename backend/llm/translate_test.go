@@ -0,0 +1,20 @@
+package llm
+
+import "testing"
+
+func TestParseTranslations(t *testing.T) {
+	got, err := parseTranslations("```json\n[\"Hello\", \"World [1]\"]\n```", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got[0] != "Hello" || got[1] != "World [1]" {
+		t.Errorf("unexpected translations: %v", got)
+	}
+
+	if _, err := parseTranslations(`["only one"]`, 2); err == nil {
+		t.Error("expected error for mismatched segment count")
+	}
+	if _, err := parseTranslations("no json here", 1); err == nil {
+		t.Error("expected error when the response has no array")
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/browserwing/browserwing/pkg/logger"
 	"github.com/browserwing/browserwing/scheduler"
 	"github.com/browserwing/browserwing/services/browser"
+	"github.com/browserwing/browserwing/services/notify"
 	"github.com/browserwing/browserwing/storage"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
@@ -98,14 +99,6 @@ func main() {
 		log.Println("✓ System prompts checked and updated")
 	}
 
-	// 初始化默认浏览器实例
-	err = initDefaultBrowserInstance(db, cfg)
-	if err != nil {
-		log.Printf("Warning: Failed to initialize default browser instance: %v", err)
-	} else {
-		log.Println("✓ Default browser instance initialized successfully")
-	}
-
 	// 初始化默认用户（如果启用了认证）
 	if cfg.Auth.Enabled {
 		err = initDefaultUser(db, cfg)
@@ -116,6 +109,13 @@ func main() {
 		}
 	}
 
+	// 加载注入界面的自定义语言
+	if locales, err := db.ListUILocales(); err != nil {
+		log.Printf("Warning: Failed to load UI locales: %v", err)
+	} else {
+		browser.SetUILocales(locales)
+	}
+
 	// 初始化 LLM 管理器
 	llmManager := llm.NewManager(db)
 	// 从配置文件加载 LLM 配置
@@ -128,6 +128,9 @@ func main() {
 
 	// 初始化浏览器管理器
 	browserManager := browser.NewManager(cfg, db, llmManager)
+	if err := browserManager.EnsureDefaultInstance(context.Background()); err != nil {
+		log.Printf("Warning: Failed to initialize default browser instance: %v", err)
+	}
 	log.Println("✓ Browser manager initialized successfully")
 
 	// 初始化 MCP 服务器 (使用 mcp-go 库)
@@ -179,6 +182,13 @@ func main() {
 
 	// 初始化定时任务调度器
 	taskScheduler := scheduler.NewScheduler(db, taskExecutor)
+
+	// 通知：脚本失败、任务完成和内容变化按通知规则发送到 Slack/Discord/Telegram/邮件，并定期发送执行报告摘要
+	notifier := notify.NewService(db, browserManager.NetworkGuard())
+	browserManager.SetNotifier(notifier)
+	taskScheduler.SetNotifier(notifier)
+	notifier.StartDigests()
+
 	err = taskScheduler.Start()
 	if err != nil {
 		log.Printf("Warning: Failed to start scheduler: %v", err)
@@ -202,7 +212,7 @@ func main() {
 	router := api.SetupRouter(handler, agentHandler, frontendFS, embedMode, cfg.Debug)
 
 	// 设置优雅退出
-	setupGracefulShutdown(browserManager, db, mcpServer, agentManager, taskScheduler)
+	setupGracefulShutdown(browserManager, db, mcpServer, agentManager, taskScheduler, notifier)
 
 	// 启动服务器
 	addr := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
@@ -224,7 +234,7 @@ func main() {
 }
 
 // setupGracefulShutdown 设置优雅退出，自动关闭浏览器
-func setupGracefulShutdown(browserManager *browser.Manager, db *storage.BoltDB, mcpServer mcp.IMCPServer, agentManager *agent.AgentManager, taskScheduler interface{}) {
+func setupGracefulShutdown(browserManager *browser.Manager, db *storage.BoltDB, mcpServer mcp.IMCPServer, agentManager *agent.AgentManager, taskScheduler interface{}, notifier *notify.Service) {
 	sigChan := make(chan os.Signal, 1)
 	// 监听 SIGINT (Ctrl+C) 和 SIGTERM
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
@@ -250,6 +260,11 @@ func setupGracefulShutdown(browserManager *browser.Manager, db *storage.BoltDB,
 			}
 		}
 
+		// 停止执行报告摘要的发送
+		if notifier != nil {
+			notifier.StopDigests()
+		}
+
 		// 停止 Agent 管理器
 		if agentManager != nil {
 			log.Println("Stopping Agent manager...")
@@ -264,14 +279,11 @@ func setupGracefulShutdown(browserManager *browser.Manager, db *storage.BoltDB,
 			log.Println("✓ MCP server stopped")
 		}
 
-		// 检查并关闭浏览器
-		if browserManager.IsRunning() {
-			log.Println("Browser is running, closing...")
-			if err := browserManager.Stop(); err != nil {
-				log.Printf("Failed to close browser: %v", err)
-			} else {
-				log.Println("✓ Browser closed")
-			}
+		// 关闭所有运行中的浏览器实例
+		if running := browserManager.ListRunningInstances(); len(running) > 0 {
+			log.Printf("%d browser instance(s) running, closing...", len(running))
+			browserManager.StopAll(context.Background())
+			log.Println("✓ Browser closed")
 		} else {
 			log.Println("Browser is not running, no need to close")
 		}
@@ -286,6 +298,9 @@ func setupGracefulShutdown(browserManager *browser.Manager, db *storage.BoltDB,
 			}
 		}
 
+		// 推送额外日志输出中缓冲的日志
+		logger.Close()
+
 		// 等待或超时
 		select {
 		case <-ctx.Done():
@@ -316,150 +331,6 @@ func openBrowser(url string) {
 	_ = cmd.Start() // 不阻塞，忽略错误（有些环境可能没有 GUI）
 }
 
-// initDefaultBrowserInstance 初始化默认浏览器实例
-func initDefaultBrowserInstance(db *storage.BoltDB, cfg *config.Config) error {
-	// 检查是否已存在默认实例
-	defaultInstance, err := db.GetDefaultBrowserInstance()
-	if err == nil && defaultInstance != nil {
-		log.Printf("Default browser instance already exists: %s (ID: %s)", defaultInstance.Name, defaultInstance.ID)
-
-		// 同步配置文件中的浏览器配置到默认实例
-		if cfg.Browser != nil {
-			needUpdate := false
-
-			// 检查并更新 ControlURL
-			if cfg.Browser.ControlURL != "" && defaultInstance.ControlURL != cfg.Browser.ControlURL {
-				log.Printf("Syncing control URL from config: %s -> %s", defaultInstance.ControlURL, cfg.Browser.ControlURL)
-				defaultInstance.ControlURL = cfg.Browser.ControlURL
-				// 如果配置了远程 URL，切换为 remote 类型
-				if defaultInstance.Type != "remote" {
-					defaultInstance.Type = "remote"
-					log.Printf("Switching instance type to remote due to control URL")
-				}
-				needUpdate = true
-			} else if cfg.Browser.ControlURL == "" && defaultInstance.Type == "remote" {
-				// 如果配置中移除了 ControlURL，但实例仍是 remote 类型，切换回 local
-				log.Printf("Control URL removed from config, switching to local mode")
-				defaultInstance.Type = "local"
-				defaultInstance.ControlURL = ""
-				needUpdate = true
-			}
-
-			// 检查并更新 BinPath（仅 local 模式）
-			if defaultInstance.Type == "local" && cfg.Browser.BinPath != "" && defaultInstance.BinPath != cfg.Browser.BinPath {
-				log.Printf("Syncing bin path from config: %s -> %s", defaultInstance.BinPath, cfg.Browser.BinPath)
-				defaultInstance.BinPath = cfg.Browser.BinPath
-				needUpdate = true
-			}
-
-			// 检查并更新 UserDataDir（仅 local 模式）
-			if defaultInstance.Type == "local" && cfg.Browser.UserDataDir != "" && defaultInstance.UserDataDir != cfg.Browser.UserDataDir {
-				log.Printf("Syncing user data dir from config: %s -> %s", defaultInstance.UserDataDir, cfg.Browser.UserDataDir)
-				defaultInstance.UserDataDir = cfg.Browser.UserDataDir
-				needUpdate = true
-			}
-
-			// 如果有配置变化，保存实例
-			if needUpdate {
-				log.Printf("Updating default browser instance with config changes")
-				return db.SaveBrowserInstance(defaultInstance)
-			}
-		}
-
-		return nil
-	}
-
-	// 查找默认 Chrome 路径
-	var binPath string
-	var userDataDir string
-
-	// 创建默认实例
-	useStealth := true
-	headless := false
-
-	// 根据环境自动设置 headless
-	display := os.Getenv("DISPLAY")
-	waylandDisplay := os.Getenv("WAYLAND_DISPLAY")
-	if runtime.GOOS == "linux" && display == "" && waylandDisplay == "" {
-		headless = true
-		log.Println("Detected headless environment, enabling headless mode for default instance")
-	}
-
-	browserType := "local"
-	controlURL := ""
-
-	if cfg.Browser.ControlURL != "" {
-		browserType = "remote"
-		controlURL = cfg.Browser.ControlURL
-	} else {
-
-		// 获取默认浏览器路径（参考 config.go 的逻辑）
-		commonPaths := []string{
-			"/usr/bin/google-chrome",
-			"/usr/bin/chromium-browser",
-			"/usr/bin/chromium",
-			"/usr/bin/google-chrome-stable",
-			"/Applications/Google Chrome.app/Contents/MacOS/Google Chrome",
-			"C:\\Program Files\\Google\\Chrome\\Application\\chrome.exe",
-			"C:\\Program Files (x86)\\Google\\Chrome\\Application\\chrome.exe",
-		}
-
-		for _, path := range commonPaths {
-			if _, err := os.Stat(path); err == nil {
-				binPath = path
-				log.Printf("Found browser at: %s", binPath)
-				break
-			}
-		}
-
-		// 如果配置中有指定路径，优先使用配置的路径
-		if cfg.Browser != nil && cfg.Browser.BinPath != "" {
-			binPath = cfg.Browser.BinPath
-			log.Printf("Using browser path from config: %s", binPath)
-		}
-
-		// 设置默认用户数据目录
-		homeDir, _ := os.UserHomeDir()
-		if homeDir != "" {
-			userDataDir = filepath.Join(homeDir, ".browserwing", "default-profile")
-		}
-	}
-
-	instance := &models.BrowserInstance{
-		ID:          "default",
-		Name:        "默认浏览器",
-		Description: "系统默认浏览器实例",
-		Type:        browserType,
-		ControlURL:  controlURL,
-		BinPath:     binPath,
-		UserDataDir: userDataDir,
-		UserAgent:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36",
-		UseStealth:  &useStealth,
-		Headless:    &headless,
-		LaunchArgs: []string{
-			"disable-blink-features=AutomationControlled",
-			"excludeSwitches=enable-automation",
-			"no-first-run",
-			"no-default-browser-check",
-			"window-size=1920,1080",
-			"start-maximized",
-		},
-		IsDefault: true,
-		IsActive:  false,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	// 保存到数据库
-	if err := db.SaveBrowserInstance(instance); err != nil {
-		return fmt.Errorf("failed to save default browser instance: %w", err)
-	}
-
-	log.Printf("Created default browser instance: %s (BinPath: %s, UserDataDir: %s)",
-		instance.Name, instance.BinPath, instance.UserDataDir)
-	return nil
-}
-
 // initDefaultUser 初始化默认用户
 func initDefaultUser(db *storage.BoltDB, cfg *config.Config) error {
 	// 检查是否已存在用户
@@ -14,6 +14,7 @@ type IMCPServer interface {
 	UnregisterScript(scriptID string)
 	GetStatus() map[string]interface{}
 	CallTool(ctx context.Context, name string, arguments map[string]interface{}) (interface{}, error)
+	ReleaseSession(sessionID string)
 }
 
 // 确保两个实现都满足接口
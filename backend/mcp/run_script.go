@@ -0,0 +1,55 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/browserwing/browserwing/config"
+	"github.com/browserwing/browserwing/pkg/logger"
+)
+
+// RunScript 使用脚本的预设变量执行脚本（用于 Agent 会话过期后执行站点的登录脚本）
+// 会话使用独立的浏览器上下文时，脚本登录的 Cookie 不会带到会话中，直接返回错误
+func (s *MCPServer) RunScript(ctx context.Context, scriptID string) error {
+	if s.browserMgr.GetSessionIsolation() == config.SessionIsolationContext {
+		return fmt.Errorf("scripts cannot sign in sessions that use isolated browser contexts")
+	}
+	script, err := s.storage.GetScript(scriptID)
+	if err != nil {
+		return fmt.Errorf("failed to load script %s: %w", scriptID, err)
+	}
+
+	if !s.browserMgr.IsRunning() {
+		logger.Info(ctx, "Browser not running, starting...")
+		if err := s.browserMgr.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start browser: %w", err)
+		}
+	}
+
+	// 替换预设变量的占位符
+	scriptToRun := script.Copy()
+	scriptToRun.URL = s.replacePlaceholders(scriptToRun.URL, scriptToRun.Variables)
+	for i := range scriptToRun.Actions {
+		scriptToRun.Actions[i].Selector = s.replacePlaceholders(scriptToRun.Actions[i].Selector, scriptToRun.Variables)
+		scriptToRun.Actions[i].XPath = s.replacePlaceholders(scriptToRun.Actions[i].XPath, scriptToRun.Variables)
+		scriptToRun.Actions[i].TargetSelector = s.replacePlaceholders(scriptToRun.Actions[i].TargetSelector, scriptToRun.Variables)
+		scriptToRun.Actions[i].TargetXPath = s.replacePlaceholders(scriptToRun.Actions[i].TargetXPath, scriptToRun.Variables)
+		scriptToRun.Actions[i].Value = s.replacePlaceholders(scriptToRun.Actions[i].Value, scriptToRun.Variables)
+		scriptToRun.Actions[i].URL = s.replacePlaceholders(scriptToRun.Actions[i].URL, scriptToRun.Variables)
+		scriptToRun.Actions[i].JSCode = s.replacePlaceholders(scriptToRun.Actions[i].JSCode, scriptToRun.Variables)
+	}
+
+	playResult, page, err := s.browserMgr.PlayScript(ctx, scriptToRun, "")
+	if page != nil {
+		if err := s.browserMgr.CloseActivePage(ctx, page); err != nil {
+			logger.Warn(ctx, "Failed to close page: %v", err)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to execute script %s: %w", script.Name, err)
+	}
+	if !playResult.Success {
+		return fmt.Errorf("script %s failed: %s", script.Name, playResult.Message)
+	}
+	return nil
+}
@@ -53,11 +53,23 @@ func NewMCPServer(storage *storage.BoltDB, browserMgr *browser.Manager) *MCPServ
 		cancel:        cancel,
 	}
 
+	// 会话结束时清理该会话的活动标签页记录
+	hooks := &server.Hooks{}
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		if s.executor != nil {
+			s.executor.ForgetSession(session.SessionID())
+		}
+	})
+
 	// 创建 mcp-go server
 	s.mcpServer = server.NewMCPServer(
 		"browserwing",
 		"1.0.0",
 		server.WithToolCapabilities(true),
+		server.WithHooks(hooks),
+		// 只读连接（请求头 X-BrowserWing-Read-Only: true）只能列出和调用只读工具
+		server.WithToolFilter(executor.ReadOnlyToolFilter),
+		server.WithToolHandlerMiddleware(executor.ReadOnlyToolMiddleware),
 	)
 
 	// 创建 Streamable HTTP server
@@ -65,6 +77,7 @@ func NewMCPServer(storage *storage.BoltDB, browserMgr *browser.Manager) *MCPServ
 		s.mcpServer,
 		server.WithEndpointPath("/api/v1/mcp/message"),
 		server.WithStateful(true),
+		server.WithHTTPContextFunc(executor.ReadOnlyHTTPContext),
 	)
 
 	// 创建 SSE server
@@ -72,6 +85,7 @@ func NewMCPServer(storage *storage.BoltDB, browserMgr *browser.Manager) *MCPServ
 		s.mcpServer,
 		server.WithSSEEndpoint("/api/v1/mcp/sse"),
 		server.WithMessageEndpoint("/api/v1/mcp/sse_message"),
+		server.WithSSEContextFunc(executor.ReadOnlyHTTPContext),
 	)
 
 	// 初始化 Executor 和工具注册表
@@ -87,6 +101,7 @@ func (s *MCPServer) StartStreamableHTTPServer(port string) error {
 			s.mcpServer,
 			server.WithEndpointPath("/mcp"),
 			server.WithStateful(true),
+			server.WithHTTPContextFunc(executor.ReadOnlyHTTPContext),
 		)
 		if err := newServer.Start(port); err != nil {
 			logger.Error(s.ctx, "Failed to start streamable HTTP server: %v", err)
@@ -189,14 +204,20 @@ func (s *MCPServer) registerTool(script *models.Script) error {
 						propType = t
 					}
 
+					// 参数表单设置（标题、占位提示、校验正则、可选值等）
+					propOpts := []mcpgo.PropertyOption{mcpgo.Description(desc)}
+					if field, ok := script.ParamUISchema[propName]; ok {
+						propOpts = append(propOpts, paramUIOption(field))
+					}
+
 					// 根据类型添加参数
 					switch propType {
 					case "string":
-						opts = append(opts, mcpgo.WithString(propName, mcpgo.Description(desc)))
+						opts = append(opts, mcpgo.WithString(propName, propOpts...))
 					case "number", "integer":
-						opts = append(opts, mcpgo.WithNumber(propName, mcpgo.Description(desc)))
+						opts = append(opts, mcpgo.WithNumber(propName, propOpts...))
 					case "boolean":
-						opts = append(opts, mcpgo.WithBoolean(propName, mcpgo.Description(desc)))
+						opts = append(opts, mcpgo.WithBoolean(propName, propOpts...))
 					}
 				}
 			}
@@ -213,6 +234,15 @@ func (s *MCPServer) registerTool(script *models.Script) error {
 	return nil
 }
 
+// paramUIOption 将参数表单设置合并到 MCP 工具的参数定义中
+func paramUIOption(field models.ParamUIField) mcpgo.PropertyOption {
+	return func(schema map[string]any) {
+		for k, v := range browser.ParamUIKeywords(field) {
+			schema[k] = v
+		}
+	}
+}
+
 // createToolHandler 创建工具处理器
 func (s *MCPServer) createToolHandler(script *models.Script) func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
 	return func(ctx context.Context, request mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
@@ -255,6 +285,11 @@ func (s *MCPServer) createToolHandler(script *models.Script) func(ctx context.Co
 			}
 		}
 
+		// 按参数表单设置校验参数
+		if err := browser.ValidateScriptParams(script, params); err != nil {
+			return mcpgo.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+		}
+
 		// 替换 URL 中的占位符
 		if urlParam, ok := params["url"]; ok && urlParam != "" {
 			scriptToRun.URL = urlParam
@@ -266,6 +301,8 @@ func (s *MCPServer) createToolHandler(script *models.Script) func(ctx context.Co
 		for i := range scriptToRun.Actions {
 			scriptToRun.Actions[i].Selector = s.replacePlaceholders(scriptToRun.Actions[i].Selector, params)
 			scriptToRun.Actions[i].XPath = s.replacePlaceholders(scriptToRun.Actions[i].XPath, params)
+			scriptToRun.Actions[i].TargetSelector = s.replacePlaceholders(scriptToRun.Actions[i].TargetSelector, params)
+			scriptToRun.Actions[i].TargetXPath = s.replacePlaceholders(scriptToRun.Actions[i].TargetXPath, params)
 			scriptToRun.Actions[i].Value = s.replacePlaceholders(scriptToRun.Actions[i].Value, params)
 			scriptToRun.Actions[i].URL = s.replacePlaceholders(scriptToRun.Actions[i].URL, params)
 			scriptToRun.Actions[i].JSCode = s.replacePlaceholders(scriptToRun.Actions[i].JSCode, params)
@@ -278,7 +315,7 @@ func (s *MCPServer) createToolHandler(script *models.Script) func(ctx context.Co
 		// 执行脚本（使用当前实例，传空字符串）
 		playResult, page, err := s.browserMgr.PlayScript(ctx, scriptToRun, "")
 		if err != nil {
-			return mcpgo.NewToolResultError(fmt.Sprintf("Failed to execute script: %v", err)), nil
+			return executor.ScriptToolError(ctx, request.Params.Name, fmt.Errorf("failed to execute script: %w", err), page), nil
 		}
 
 		// 关闭页面
@@ -333,9 +370,14 @@ func (s *MCPServer) replacePlaceholders(text string, params map[string]string) s
 		result = strings.ReplaceAll(result, placeholder, value)
 	}
 
-	// 清理未替换的占位符
+	// 清理未替换的占位符，引用增量抓取状态的 ${state.*} 在回放时替换
 	re := regexp.MustCompile(`\$\{([^}]+)\}`)
-	result = re.ReplaceAllString(result, "")
+	result = re.ReplaceAllStringFunc(result, func(match string) string {
+		if browser.IsStatePlaceholder(match[2 : len(match)-1]) {
+			return match
+		}
+		return ""
+	})
 
 	return result
 }
@@ -403,6 +445,13 @@ func (s *MCPServer) GetStatus() map[string]interface{} {
 
 // CallTool 直接调用工具（用于 Agent）
 func (s *MCPServer) CallTool(ctx context.Context, name string, arguments map[string]interface{}) (interface{}, error) {
+	// 只读会话（executor.WithReadOnly）只能调用只读工具，与 MCP 连接使用相同的检查
+	if executor.IsReadOnly(ctx) {
+		if err := executor.CheckReadOnlyTool(name, arguments); err != nil {
+			return nil, err
+		}
+	}
+
 	// 检查是否是 Executor 工具（以 "browser_" 开头）
 	if strings.HasPrefix(name, "browser_") {
 		return s.callExecutorTool(ctx, name, arguments)
@@ -454,6 +503,8 @@ func (s *MCPServer) CallTool(ctx context.Context, name string, arguments map[str
 	for i := range scriptToRun.Actions {
 		scriptToRun.Actions[i].Selector = s.replacePlaceholders(scriptToRun.Actions[i].Selector, params)
 		scriptToRun.Actions[i].XPath = s.replacePlaceholders(scriptToRun.Actions[i].XPath, params)
+		scriptToRun.Actions[i].TargetSelector = s.replacePlaceholders(scriptToRun.Actions[i].TargetSelector, params)
+		scriptToRun.Actions[i].TargetXPath = s.replacePlaceholders(scriptToRun.Actions[i].TargetXPath, params)
 		scriptToRun.Actions[i].Value = s.replacePlaceholders(scriptToRun.Actions[i].Value, params)
 		scriptToRun.Actions[i].URL = s.replacePlaceholders(scriptToRun.Actions[i].URL, params)
 		scriptToRun.Actions[i].JSCode = s.replacePlaceholders(scriptToRun.Actions[i].JSCode, params)
@@ -499,6 +550,11 @@ func (s *MCPServer) CallTool(ctx context.Context, name string, arguments map[str
 	return result, nil
 }
 
+// ReleaseSession 释放会话占用的页面（会话隔离模式下为其创建的页面和浏览器上下文）
+func (s *MCPServer) ReleaseSession(sessionID string) {
+	s.executor.ForgetSession(sessionID)
+}
+
 func (s *MCPServer) ServeSteamableHTTP(w http.ResponseWriter, r *http.Request) {
 	logger.Info(r.Context(), "ServeHTTP: Method=%s, Path=%s, RemoteAddr=%s", r.Method, r.URL.Path, r.RemoteAddr)
 	s.streamableHTTPServer.ServeHTTP(w, r)
@@ -527,7 +583,8 @@ func (s *MCPServer) callExecutorTool(ctx context.Context, name string, arguments
 		waitUntil, _ := arguments["wait_until"].(string)
 
 		opts := &executor.NavigateOptions{
-			Timeout: 60 * time.Second, // 设置默认超时为 60 秒
+			Timeout:  60 * time.Second, // 设置默认超时为 60 秒
+			Snapshot: executor.ParseSnapshotArgument(arguments),
 		}
 		if waitUntil != "" {
 			opts.WaitUntil = waitUntil
@@ -550,10 +607,13 @@ func (s *MCPServer) callExecutorTool(ctx context.Context, name string, arguments
 	case "browser_click":
 		identifier, _ := arguments["identifier"].(string)
 		waitVisible, _ := arguments["wait_visible"].(bool)
+		tabID, _ := arguments["tab_id"].(string)
+		ctx = executor.WithTab(ctx, tabID)
 
 		opts := &executor.ClickOptions{
 			WaitVisible: waitVisible,
 			Timeout:     30 * time.Second, // 设置默认超时为 30 秒
+			Snapshot:    executor.ParseSnapshotArgument(arguments),
 		}
 
 		result, err := s.executor.Click(ctx, identifier, opts)
@@ -573,14 +633,20 @@ func (s *MCPServer) callExecutorTool(ctx context.Context, name string, arguments
 	case "browser_type":
 		identifier, _ := arguments["identifier"].(string)
 		text, _ := arguments["text"].(string)
+		tabID, _ := arguments["tab_id"].(string)
+		ctx = executor.WithTab(ctx, tabID)
 		clear := true
 		if clearArg, ok := arguments["clear"].(bool); ok {
 			clear = clearArg
 		}
 
+		ime, _ := arguments["ime"].(bool)
+
 		opts := &executor.TypeOptions{
-			Clear:   clear,
-			Timeout: 30 * time.Second, // 设置默认超时为 30 秒
+			Clear:    clear,
+			Timeout:  30 * time.Second, // 设置默认超时为 30 秒
+			IME:      ime,
+			Snapshot: executor.ParseSnapshotArgument(arguments),
 		}
 
 		result, err := s.executor.Type(ctx, identifier, text, opts)
@@ -602,7 +668,8 @@ func (s *MCPServer) callExecutorTool(ctx context.Context, name string, arguments
 		value, _ := arguments["value"].(string)
 
 		opts := &executor.SelectOptions{
-			Timeout: 30 * time.Second, // 设置默认超时为 30 秒
+			Timeout:  30 * time.Second, // 设置默认超时为 30 秒
+			Snapshot: executor.ParseSnapshotArgument(arguments),
 		}
 
 		result, err := s.executor.Select(ctx, identifier, value, opts)
@@ -626,10 +693,13 @@ func (s *MCPServer) callExecutorTool(ctx context.Context, name string, arguments
 			format = "png"
 		}
 
+		fileName, _ := arguments["file_name"].(string)
+
 		opts := &executor.ScreenshotOptions{
 			FullPage: fullPage,
 			Format:   format,
 			Quality:  80,
+			FileName: fileName,
 		}
 
 		result, err := s.executor.Screenshot(ctx, opts)
@@ -683,6 +753,14 @@ func (s *MCPServer) callExecutorTool(ctx context.Context, name string, arguments
 			return nil, err
 		}
 
+		var geometry *executor.SnapshotGeometry
+		if withGeometry, _ := arguments["geometry"].(bool); withGeometry {
+			geometry, err = s.executor.GetSnapshotGeometry(ctx, snapshot)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		response := map[string]interface{}{
 			"success": true,
 			"message": "Successfully retrieved accessibility snapshot",
@@ -690,12 +768,16 @@ func (s *MCPServer) callExecutorTool(ctx context.Context, name string, arguments
 
 		if simple {
 			response["data"] = map[string]interface{}{
-				"accessibility_snapshot": snapshot.SerializeToSimpleText(),
+				"accessibility_snapshot": snapshot.SerializeWithGeometry(geometry),
 			}
 		} else {
-			response["data"] = map[string]interface{}{
+			data := map[string]interface{}{
 				"accessibility_snapshot": snapshot,
 			}
+			if geometry != nil {
+				data["geometry"] = geometry
+			}
+			response["data"] = data
 		}
 
 		return response, nil
@@ -745,19 +827,7 @@ func (s *MCPServer) callExecutorTool(ctx context.Context, name string, arguments
 
 	case "browser_wait_for":
 		identifier, _ := arguments["identifier"].(string)
-		state, _ := arguments["state"].(string)
-		if state == "" {
-			state = "visible"
-		}
-
-		opts := &executor.WaitForOptions{
-			State:   state,
-			Timeout: 30 * time.Second, // 设置默认超时为 30 秒
-		}
-
-		if timeout, ok := arguments["timeout"].(float64); ok && timeout > 0 {
-			opts.Timeout = time.Duration(timeout) * time.Second
-		}
+		opts := executor.ParseWaitForArguments(arguments)
 
 		result, err := s.executor.WaitFor(ctx, identifier, opts)
 		if err != nil {
@@ -775,6 +845,18 @@ func (s *MCPServer) callExecutorTool(ctx context.Context, name string, arguments
 
 	case "browser_scroll":
 		direction, _ := arguments["direction"].(string)
+		// 指定容器、相对距离或水平方向时使用通用滚动
+		if opts := executor.ParseScrollArguments(arguments); opts.IsCustom() {
+			result, err := s.executor.Scroll(ctx, opts)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{
+				"success": result.Success,
+				"message": result.Message,
+				"data":    result.Data,
+			}, nil
+		}
 		if direction == "" || direction == "bottom" {
 			result, err := s.executor.ScrollToBottom(ctx)
 			if err != nil {
@@ -828,12 +910,14 @@ func (s *MCPServer) callExecutorTool(ctx context.Context, name string, arguments
 		shift, _ := arguments["shift"].(bool)
 		alt, _ := arguments["alt"].(bool)
 		meta, _ := arguments["meta"].(bool)
+		mod, _ := arguments["mod"].(bool)
 
 		opts := &executor.PressKeyOptions{
 			Ctrl:  ctrl,
 			Shift: shift,
 			Alt:   alt,
 			Meta:  meta,
+			Mod:   mod,
 		}
 
 		result, err := s.executor.PressKey(ctx, key, opts)
@@ -883,7 +967,7 @@ func (s *MCPServer) callExecutorTool(ctx context.Context, name string, arguments
 		fromIdentifier, _ := arguments["from_identifier"].(string)
 		toIdentifier, _ := arguments["to_identifier"].(string)
 
-		result, err := s.executor.Drag(ctx, fromIdentifier, toIdentifier)
+		result, err := s.executor.Drag(ctx, fromIdentifier, toIdentifier, executor.ParseDragArguments(arguments))
 		if err != nil {
 			return nil, err
 		}
@@ -897,6 +981,65 @@ func (s *MCPServer) callExecutorTool(ctx context.Context, name string, arguments
 		}
 		return response, nil
 
+	case "browser_fill_date":
+		identifier, _ := arguments["identifier"].(string)
+		date, _ := arguments["date"].(string)
+
+		result, err := s.executor.FillDate(ctx, identifier, date, executor.ParseFillDateArguments(arguments))
+		if err != nil {
+			return nil, err
+		}
+		response := map[string]interface{}{
+			"success": result.Success,
+			"message": result.Message,
+		}
+		if len(result.Data) > 0 {
+			response["data"] = result.Data
+		}
+		return response, nil
+
+	case "browser_choose_option":
+		identifier, _ := arguments["identifier"].(string)
+		value, _ := arguments["value"].(string)
+
+		result, err := s.executor.ChooseOption(ctx, identifier, value, executor.ParseChooseOptionArguments(arguments))
+		if err != nil {
+			return nil, err
+		}
+		response := map[string]interface{}{
+			"success": result.Success,
+			"message": result.Message,
+		}
+		if len(result.Data) > 0 {
+			response["data"] = result.Data
+		}
+		return response, nil
+
+	case "browser_hover_then_click":
+		trigger, _ := arguments["trigger"].(string)
+		target, _ := arguments["target"].(string)
+
+		opts := &executor.HoverThenClickOptions{}
+		if timeout, ok := arguments["timeout"].(float64); ok && timeout > 0 {
+			opts.Timeout = time.Duration(timeout) * time.Second
+		}
+		if retries, ok := arguments["retries"].(float64); ok && retries > 0 {
+			opts.Retries = int(retries)
+		}
+
+		result, err := s.executor.HoverThenClick(ctx, trigger, target, opts)
+		if err != nil {
+			return nil, err
+		}
+		response := map[string]interface{}{
+			"success": result.Success,
+			"message": result.Message,
+		}
+		if len(result.Data) > 0 {
+			response["data"] = result.Data
+		}
+		return response, nil
+
 	case "browser_close":
 		result, err := s.executor.ClosePage(ctx)
 		if err != nil {
@@ -995,6 +1138,199 @@ func (s *MCPServer) callExecutorTool(ctx context.Context, name string, arguments
 		}
 		return response, nil
 
+	case "browser_capture_response":
+		action, _ := arguments["action"].(string)
+
+		opts := &executor.CaptureResponseOptions{
+			Action: executor.CaptureResponseAction(action),
+		}
+		if pattern, ok := arguments["url_pattern"].(string); ok {
+			opts.URLPattern = pattern
+		}
+		if method, ok := arguments["method"].(string); ok {
+			opts.Method = method
+		}
+		if timeout, ok := arguments["timeout"].(float64); ok && timeout > 0 {
+			opts.Timeout = time.Duration(timeout) * time.Second
+		}
+
+		result, err := s.executor.CaptureResponse(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		response := map[string]interface{}{
+			"success": result.Success,
+			"message": result.Message,
+		}
+		if len(result.Data) > 0 {
+			response["data"] = result.Data
+		}
+		return response, nil
+
+	case "browser_websocket":
+		action, _ := arguments["action"].(string)
+
+		opts := &executor.WebSocketOptions{
+			Action: executor.WebSocketAction(action),
+		}
+		if url, ok := arguments["url"].(string); ok {
+			opts.URL = url
+		}
+		if opcode, ok := arguments["opcode"].(float64); ok {
+			opts.Opcode = int(opcode)
+		}
+		if limit, ok := arguments["limit"].(float64); ok {
+			opts.Limit = int(limit)
+		}
+
+		result, err := s.executor.WebSocket(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		response := map[string]interface{}{
+			"success": result.Success,
+			"message": result.Message,
+		}
+		if len(result.Data) > 0 {
+			response["data"] = result.Data
+		}
+		return response, nil
+
+	case "browser_xhr_replay":
+		action, _ := arguments["action"].(string)
+
+		opts := &executor.XHRReplayOptions{
+			Action: executor.XHRReplayAction(action),
+		}
+		if url, ok := arguments["url"].(string); ok {
+			opts.URL = url
+		}
+		if requestID, ok := arguments["request_id"].(string); ok {
+			opts.RequestID = requestID
+		}
+		if variables, ok := arguments["variables"].(map[string]interface{}); ok {
+			opts.Variables = variables
+		}
+
+		result, err := s.executor.XHRReplay(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		response := map[string]interface{}{
+			"success": result.Success,
+			"message": result.Message,
+		}
+		if len(result.Data) > 0 {
+			response["data"] = result.Data
+		}
+		return response, nil
+
+	case "browser_collect":
+		result, err := s.executor.Collect(ctx, executor.ParseCollectArguments(arguments))
+		if err != nil {
+			return nil, err
+		}
+		response := map[string]interface{}{
+			"success": result.Success,
+			"message": result.Message,
+		}
+		if len(result.Data) > 0 {
+			response["data"] = result.Data
+		}
+		return response, nil
+
+	case "browser_inspect_element":
+		identifier, _ := arguments["identifier"].(string)
+		result, err := s.executor.InspectElement(ctx, identifier, executor.ParseInspectArguments(arguments))
+		if err != nil {
+			return nil, err
+		}
+		response := map[string]interface{}{
+			"success": result.Success,
+			"message": result.Message,
+		}
+		if len(result.Data) > 0 {
+			response["data"] = result.Data
+		}
+		return response, nil
+
+	case "browser_audit":
+		result, err := s.executor.Audit(ctx, executor.ParseAuditArguments(arguments))
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"success": result.Success,
+			"message": result.Message,
+			"data":    result.Data,
+		}, nil
+
+	case "browser_a11y_scan":
+		result, err := s.executor.A11yScan(ctx, executor.ParseA11yScanArguments(arguments))
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"success": result.Success,
+			"message": result.Message,
+			"data":    result.Data,
+		}, nil
+
+	case "browser_check_links":
+		result, err := s.executor.CheckLinks(ctx, executor.ParseLinkCheckArguments(arguments))
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"success": result.Success,
+			"message": result.Message,
+			"data":    result.Data,
+		}, nil
+
+	case "browser_translate_page":
+		result, err := s.executor.TranslatePage(ctx, executor.ParseTranslateArguments(arguments))
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"success": result.Success,
+			"message": result.Message,
+			"data":    result.Data,
+		}, nil
+
+	case "browser_read_image_text":
+		result, err := s.executor.ReadImageText(ctx, executor.ParseReadImageTextArguments(arguments))
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"success": result.Success,
+			"message": result.Message,
+			"data":    result.Data,
+		}, nil
+
+	case "browser_click_at":
+		result, err := s.executor.ClickAt(ctx, executor.ParseClickAtArguments(arguments))
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"success": result.Success,
+			"message": result.Message,
+			"data":    result.Data,
+		}, nil
+
+	case "browser_drag_box":
+		result, err := s.executor.DragBox(ctx, executor.ParseDragBoxArguments(arguments))
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"success": result.Success,
+			"message": result.Message,
+			"data":    result.Data,
+		}, nil
+
 	case "browser_tabs":
 		action, _ := arguments["action"].(string)
 
@@ -1007,7 +1343,10 @@ func (s *MCPServer) callExecutorTool(ctx context.Context, name string, arguments
 			opts.URL = url
 		}
 
-		// 处理 index 参数
+		// 处理 tab_id / index 参数（优先使用 tab_id）
+		if tabID, ok := arguments["tab_id"].(string); ok {
+			opts.TabID = tabID
+		}
 		if indexFloat, ok := arguments["index"].(float64); ok {
 			opts.Index = int(indexFloat)
 		}
@@ -8,8 +8,29 @@ type AgentSession struct {
 	LLMConfigID string    `json:"llm_config_id"` // 会话使用的LLM配置ID
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+
+	// 需要人工批准的工具调用类别，见 ApprovalCategories
+	ApprovalCategories []string `json:"approval_categories,omitempty"`
+	// 只读会话：Agent 只能使用打开和读取页面的工具
+	ReadOnly bool `json:"read_only,omitempty"`
+	// 操作校验：改变页面的工具调用后截图并让 LLM 确认预期的变化已发生
+	VerifyActions bool `json:"verify_actions,omitempty"`
+	// 会话使用的系统提示词 ID（已保存的提示词），为空时使用默认的 Agent 系统提示词
+	SystemPromptID string `json:"system_prompt_id,omitempty"`
 }
 
+// 需要人工批准的工具调用类别
+const (
+	ApprovalNavigation = "navigation"  // 打开会话中尚未批准过的域名
+	ApprovalFormSubmit = "form_submit" // 提交表单
+	ApprovalDownload   = "download"    // 打开可下载的文件
+	ApprovalClick      = "click"       // 所有点击
+	ApprovalScript     = "script"      // 执行 JavaScript 或录制的脚本（启用任一类别时都需要批准，不能单独选择）
+)
+
+// ApprovalCategories 支持的工具调用批准类别
+var ApprovalCategories = []string{ApprovalNavigation, ApprovalFormSubmit, ApprovalDownload, ApprovalClick}
+
 // AgentMessage Agent 聊天消息
 type AgentMessage struct {
 	ID        string                   `json:"id"`
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// AutomationRunStatus 自动化触发执行的状态
+type AutomationRunStatus string
+
+const (
+	AutomationRunRunning   AutomationRunStatus = "running"   // 执行中
+	AutomationRunSucceeded AutomationRunStatus = "succeeded" // 执行成功
+	AutomationRunFailed    AutomationRunStatus = "failed"    // 执行失败
+)
+
+// AutomationRun 通过自动化触发接口（n8n、Node-RED 等低代码平台）发起的一次异步脚本执行
+// 调用方轮询执行状态，或提供回调地址在执行结束时接收通知
+type AutomationRun struct {
+	ID          string              `json:"id"`
+	ScriptID    string              `json:"script_id"`
+	ScriptName  string              `json:"script_name"`
+	Status      AutomationRunStatus `json:"status"`
+	Params      map[string]string   `json:"params,omitempty"`      // 执行参数
+	Environment string              `json:"environment,omitempty"` // 执行环境
+	Result      *PlayResult         `json:"result,omitempty"`      // 回放结果（结束后），抓取的数据在 result.extracted_data
+	Error       string              `json:"error,omitempty"`       // 失败原因
+	CallbackURL string              `json:"callback_url,omitempty"`
+	// 回调发送失败的原因，回调成功或未设置回调时为空
+	CallbackError string     `json:"callback_error,omitempty"`
+	StartedAt     time.Time  `json:"started_at"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+}
+
+// Finished 执行是否已结束
+func (r *AutomationRun) Finished() bool {
+	return r.Status != AutomationRunRunning
+}
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
 
 // BrowserConfig 浏览器配置
 type BrowserConfig struct {
@@ -19,6 +23,133 @@ type BrowserConfig struct {
 	LaunchArgs []string `json:"launch_args"` // 启动参数，为空使用默认
 	Proxy      string   `json:"proxy"`       // 代理地址，为空使用默认
 
+	// 反检测选项（伪装程度、语言、WebRTC），只在使用 Stealth 模式时生效；nil 表示沿用默认配置的设置（默认 full）
+	Stealth *StealthOptions `json:"stealth,omitempty"`
+
+	// 广告/跟踪器拦截（使用 [browser.adblock] 中配置的过滤列表），nil 表示沿用默认配置的设置（默认不拦截）
+	BlockAds *bool `json:"block_ads,omitempty"`
+
+	// 站点认证（用于 HTTP 认证或客户端证书保护的内网应用）
+	HTTPAuth   *HTTPAuthCredentials `json:"http_auth,omitempty"`   // HTTP Basic/Digest 认证凭据
+	ClientCert *ClientCertificate   `json:"client_cert,omitempty"` // 客户端 TLS 证书自动选择规则（通过 AutoSelectCertificateForUrls 策略下发）
+
+	// 浮动录制按钮的外观，或不在页面中注入浮动按钮；nil 表示沿用默认配置的设置
+	FloatButton *FloatButtonOptions `json:"float_button,omitempty"`
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+// HTTPAuthCredentials HTTP Basic/Digest 认证凭据
+// 密码只写不读：API 响应中会被清空，并通过 HasPassword 标识是否已设置
+type HTTPAuthCredentials struct {
+	Username    string `json:"username"`
+	Password    string `json:"password,omitempty"`
+	HasPassword bool   `json:"has_password,omitempty"` // 仅用于响应，表示已保存密码
+}
+
+// Redacted 返回去除认证密码的副本，用于 API 响应
+func (c BrowserConfig) Redacted() BrowserConfig {
+	if c.HTTPAuth != nil {
+		auth := *c.HTTPAuth
+		auth.HasPassword = auth.Password != ""
+		auth.Password = ""
+		c.HTTPAuth = &auth
+	}
+	return c
+}
+
+// KeepSecretsFrom 更新配置时未提交新密码（且用户名未变）则保留原密码
+func (c *BrowserConfig) KeepSecretsFrom(old *BrowserConfig) {
+	if c.HTTPAuth != nil {
+		c.HTTPAuth.HasPassword = false
+	}
+	if c.HTTPAuth == nil || c.HTTPAuth.Password != "" || old == nil || old.HTTPAuth == nil {
+		return
+	}
+	if c.HTTPAuth.Username == old.HTTPAuth.Username {
+		c.HTTPAuth.Password = old.HTTPAuth.Password
+	}
+}
+
+// ClientCertificate 客户端 TLS 证书自动选择规则
+// 证书需预先导入浏览器使用的证书库（Linux 下为 NSS 数据库 ~/.pki/nssdb，Windows/macOS 为系统证书库）
+type ClientCertificate struct {
+	Pattern   string `json:"pattern"`              // 适用的站点，例如 https://[*.]example.com
+	IssuerCN  string `json:"issuer_cn,omitempty"`  // 按颁发者 CN 选择证书
+	SubjectCN string `json:"subject_cn,omitempty"` // 按使用者 CN 选择证书
+}
+
+// 反检测伪装程度
+const (
+	StealthLevelWebdriver = "webdriver" // 只隐藏 navigator.webdriver
+	StealthLevelBasic     = "basic"     // 另外伪装插件列表和 window.chrome
+	StealthLevelFull      = "full"      // go-rod/stealth 的全部补丁（默认）
+)
+
+// languageTagPattern 语言标签，如 en、en-US、zh-Hans-CN
+var languageTagPattern = regexp.MustCompile(`^[A-Za-z]{2,8}(-[A-Za-z0-9]{1,8})*$`)
+
+// StealthOptions 反检测选项
+// 部分网站需要更强的伪装，另一些网站的脚本在全部补丁下会出错，可以按网站调整
+type StealthOptions struct {
+	Level       string   `json:"level,omitempty"`        // webdriver、basic、full（默认）
+	Languages   []string `json:"languages,omitempty"`    // 伪装的 navigator.languages 和 Accept-Language，如 ["de-DE", "de"]；为空时不修改
+	BlockWebRTC bool     `json:"block_webrtc,omitempty"` // WebRTC 只使用中继地址，避免泄露本机和代理后的真实 IP
+}
+
+// Validate 检查伪装程度和语言标签
+func (o *StealthOptions) Validate() error {
+	switch o.Level {
+	case "", StealthLevelWebdriver, StealthLevelBasic, StealthLevelFull:
+	default:
+		return fmt.Errorf("invalid stealth level %q", o.Level)
+	}
+	for _, language := range o.Languages {
+		if !languageTagPattern.MatchString(language) {
+			return fmt.Errorf("invalid language %q", language)
+		}
+	}
+	return nil
+}
+
+// 浮动录制按钮位置
+const (
+	FloatButtonTopRight    = "top-right"
+	FloatButtonTopLeft     = "top-left"
+	FloatButtonBottomRight = "bottom-right"
+	FloatButtonBottomLeft  = "bottom-left"
+)
+
+// cssColorPattern 允许的 CSS 颜色写法（颜色会拼接到内联样式中，不允许其他字符）
+var cssColorPattern = regexp.MustCompile(`^(#[0-9a-fA-F]{3,8}|[a-zA-Z]{3,30}|(rgb|rgba|hsl|hsla)\([0-9.,%\s]+\))$`)
+
+// FloatButtonOptions 浮动录制按钮选项
+// 浮动按钮会出现在页面截图中，也可能遮挡或干扰部分应用，可以按网站关闭
+type FloatButtonOptions struct {
+	Disabled        bool   `json:"disabled,omitempty"`         // 不注入浮动按钮（仍可从管理界面开始录制）
+	Position        string `json:"position,omitempty"`         // top-right（默认）、top-left、bottom-right、bottom-left
+	OffsetX         int    `json:"offset_x,omitempty"`         // 与页面左右边缘的距离（像素），0 表示默认的 20
+	OffsetY         int    `json:"offset_y,omitempty"`         // 与页面上下边缘的距离（像素），0 表示默认的 20
+	AccentColor     string `json:"accent_color,omitempty"`     // 录制按钮颜色，如 #2563eb
+	BackgroundColor string `json:"background_color,omitempty"` // 面板背景色
+	TextColor       string `json:"text_color,omitempty"`       // 标题文字颜色
+}
+
+// Validate 检查位置和颜色
+func (o *FloatButtonOptions) Validate() error {
+	switch o.Position {
+	case "", FloatButtonTopRight, FloatButtonTopLeft, FloatButtonBottomRight, FloatButtonBottomLeft:
+	default:
+		return fmt.Errorf("invalid float button position %q", o.Position)
+	}
+	if o.OffsetX < 0 || o.OffsetY < 0 {
+		return fmt.Errorf("float button offsets must not be negative")
+	}
+	for _, color := range []string{o.AccentColor, o.BackgroundColor, o.TextColor} {
+		if color != "" && !cssColorPattern.MatchString(color) {
+			return fmt.Errorf("invalid color %q", color)
+		}
+	}
+	return nil
+}
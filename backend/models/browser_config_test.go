@@ -0,0 +1,54 @@
+package models
+
+import "testing"
+
+func TestFloatButtonOptionsValidate(t *testing.T) {
+	valid := []FloatButtonOptions{
+		{},
+		{Disabled: true},
+		{Position: FloatButtonBottomLeft, OffsetX: 8, OffsetY: 48},
+		{AccentColor: "#2563eb", BackgroundColor: "rgba(15, 23, 42, 0.9)", TextColor: "white"},
+	}
+	for _, opts := range valid {
+		if err := opts.Validate(); err != nil {
+			t.Errorf("Validate(%+v) = %v", opts, err)
+		}
+	}
+
+	invalid := []FloatButtonOptions{
+		{Position: "center"},
+		{OffsetX: -1},
+		{AccentColor: "red; display: none"},
+		{BackgroundColor: "url(https://example.com/x.png)"},
+		{TextColor: "#12345g"},
+	}
+	for _, opts := range invalid {
+		if err := opts.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want error", opts)
+		}
+	}
+}
+
+func TestStealthOptionsValidate(t *testing.T) {
+	valid := []StealthOptions{
+		{},
+		{Level: StealthLevelWebdriver, BlockWebRTC: true},
+		{Level: StealthLevelFull, Languages: []string{"de-DE", "de", "zh-Hans-CN"}},
+	}
+	for _, opts := range valid {
+		if err := opts.Validate(); err != nil {
+			t.Errorf("Validate(%+v) = %v", opts, err)
+		}
+	}
+
+	invalid := []StealthOptions{
+		{Level: "paranoid"},
+		{Languages: []string{"en-US,en"}},
+		{Languages: []string{"en'];alert(1);//"}},
+	}
+	for _, opts := range invalid {
+		if err := opts.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want error", opts)
+		}
+	}
+}
@@ -1,6 +1,12 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/pkg/urlpolicy"
+)
 
 // BrowserInstance 浏览器实例
 type BrowserInstance struct {
@@ -26,7 +32,63 @@ type BrowserInstance struct {
 	Headless   *bool    `json:"headless,omitempty"`    // 是否使用 Headless 模式
 	LaunchArgs []string `json:"launch_args,omitempty"` // 启动参数
 	Proxy      string   `json:"proxy,omitempty"`       // 代理地址
+	Extensions []string `json:"extensions,omitempty"`  // 加载的解压扩展目录（包含 manifest.json）
+
+	// 代理泄露防护：避免 WebRTC 和 DNS 查询绕过代理暴露真实 IP（仅本地模式且设置了代理时生效）
+	LeakProtection *ProxyLeakProtection `json:"leak_protection,omitempty"`
+
+	// 访问策略：限制该实例可以访问的域名
+	URLPolicy *urlpolicy.Policy `json:"url_policy,omitempty"`
+
+	// 窗口布局：多个可见实例平铺时指定窗口位置、显示器等（仅本地非 Headless 模式生效）
+	Window *WindowPlacement `json:"window,omitempty"`
 
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+// ProxyLeakProtection 代理泄露防护
+// 依赖代理出口做地理位置测试时，WebRTC 和本地 DNS 查询会绕过代理，让网站看到真实 IP 或 DNS 服务器所在地区
+type ProxyLeakProtection struct {
+	BlockWebRTC bool `json:"block_webrtc,omitempty"` // WebRTC 不使用未经代理的 UDP，无法通过 STUN 获取真实 IP
+	ProxyDNS    bool `json:"proxy_dns,omitempty"`    // 禁止本地 DNS 解析和预解析，域名全部由代理解析（不支持 SOCKS4 代理）
+}
+
+// Validate 检查泄露防护是否适用于实例的代理
+func (p *ProxyLeakProtection) Validate(proxy string) error {
+	if p == nil || !p.ProxyDNS {
+		return nil
+	}
+	if strings.HasPrefix(strings.ToLower(proxy), "socks4") {
+		return fmt.Errorf("proxy_dns requires an HTTP or SOCKS5 proxy, SOCKS4 proxies cannot resolve host names")
+	}
+	return nil
+}
+
+// WindowPlacement 浏览器窗口布局配置
+type WindowPlacement struct {
+	X           *int `json:"x,omitempty"`             // 窗口左上角相对所选显示器的横坐标
+	Y           *int `json:"y,omitempty"`             // 窗口左上角相对所选显示器的纵坐标
+	Width       int  `json:"width,omitempty"`         // 窗口宽度（0 表示显示器可用宽度的 90%）
+	Height      int  `json:"height,omitempty"`        // 窗口高度（0 表示显示器可用高度的 90%）
+	Monitor     int  `json:"monitor,omitempty"`       // 显示器序号（从 0 开始，0 为主显示器）
+	AlwaysOnTop bool `json:"always_on_top,omitempty"` // 窗口置顶
+	Kiosk       bool `json:"kiosk,omitempty"`         // Kiosk 模式（全屏、无地址栏）
+}
+
+// Validate 检查窗口布局配置
+func (w *WindowPlacement) Validate() error {
+	if w == nil {
+		return nil
+	}
+	if w.Width < 0 || w.Height < 0 {
+		return fmt.Errorf("window width and height must not be negative")
+	}
+	if (w.Width > 0 && w.Width < 100) || (w.Height > 0 && w.Height < 100) {
+		return fmt.Errorf("window width and height must be at least 100 pixels")
+	}
+	if w.Monitor < 0 {
+		return fmt.Errorf("monitor index must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// BulkRunRowStatus 批量执行中单行的状态
+type BulkRunRowStatus string
+
+const (
+	BulkRunRowQueued    BulkRunRowStatus = "queued"    // 排队中
+	BulkRunRowRunning   BulkRunRowStatus = "running"   // 执行中
+	BulkRunRowSucceeded BulkRunRowStatus = "succeeded" // 执行成功
+	BulkRunRowFailed    BulkRunRowStatus = "failed"    // 执行失败
+	BulkRunRowCancelled BulkRunRowStatus = "cancelled" // 批量执行取消时尚未开始
+)
+
+// BulkRunRow 批量执行中的一行参数及其执行结果
+type BulkRunRow struct {
+	Index      int               `json:"index"` // 行号，从 1 开始
+	Params     map[string]string `json:"params"`
+	Status     BulkRunRowStatus  `json:"status"`
+	Result     *PlayResult       `json:"result,omitempty"` // 回放结果（结束后）
+	Error      string            `json:"error,omitempty"`  // 失败原因
+	StartedAt  *time.Time        `json:"started_at,omitempty"`
+	FinishedAt *time.Time        `json:"finished_at,omitempty"`
+}
+
+// Finished 该行是否已结束
+func (r *BulkRunRow) Finished() bool {
+	return r.Status != BulkRunRowQueued && r.Status != BulkRunRowRunning
+}
+
+// BulkRun 用多行参数（CSV 或 JSON 数组）批量执行同一个脚本，每行一次执行，按并发数排队
+type BulkRun struct {
+	ID          string   `json:"id"`
+	ScriptID    string   `json:"script_id"`
+	ScriptName  string   `json:"script_name"`
+	Concurrency int      `json:"concurrency"`           // 同时执行的行数
+	Environment string   `json:"environment,omitempty"` // 执行环境
+	Columns     []string `json:"columns"`               // 参数列，导出结果时按此顺序
+	Cancelled   bool     `json:"cancelled,omitempty"`   // 是否已取消
+
+	Total     int `json:"total"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+
+	Rows       []BulkRunRow `json:"rows"`
+	StartedAt  time.Time    `json:"started_at"`
+	FinishedAt *time.Time   `json:"finished_at,omitempty"` // 所有行结束的时间
+}
+
+// Finished 批量执行是否已结束
+func (r *BulkRun) Finished() bool {
+	return r.FinishedAt != nil
+}
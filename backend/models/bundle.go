@@ -0,0 +1,53 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ScriptBundle 可分享的脚本包，从脚本市场安装或发布到脚本市场
+type ScriptBundle struct {
+	ID          string    `json:"id"` // 仓库内唯一标识，只能包含小写字母、数字、点、下划线和连字符
+	Name        string    `json:"name"`
+	Version     string    `json:"version"`
+	Description string    `json:"description,omitempty"`
+	Author      string    `json:"author,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	Scripts     []Script  `json:"scripts"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// SignedBundle 带签名的脚本包，签名针对 Bundle 的原始 JSON 字节
+type SignedBundle struct {
+	Bundle    json.RawMessage `json:"bundle"`
+	PublicKey string          `json:"public_key,omitempty"` // 发布者的 Ed25519 公钥（base64），未签名时为空
+	Signature string          `json:"signature,omitempty"`  // Ed25519 签名（base64），未签名时为空
+}
+
+// BundleIndexEntry 脚本市场索引（index.json）中的一个脚本包
+type BundleIndexEntry struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Version     string    `json:"version"`
+	Description string    `json:"description,omitempty"`
+	Author      string    `json:"author,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	Scripts     int       `json:"scripts"` // 包含的脚本数量
+	URL         string    `json:"url"`     // 下载地址，相对地址基于仓库地址解析
+	PublicKey   string    `json:"public_key,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// InstalledBundle 已安装的脚本包，记录安装时创建的脚本以便升级和卸载
+type InstalledBundle struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Version     string    `json:"version"`
+	Author      string    `json:"author,omitempty"`
+	Source      string    `json:"source,omitempty"`     // 下载地址，直接上传安装时为空
+	PublicKey   string    `json:"public_key,omitempty"` // 签名者公钥，未签名时为空
+	Verified    bool      `json:"verified"`             // 签名者是否在可信公钥列表中
+	ScriptIDs   []string  `json:"script_ids"`
+	InstalledAt time.Time `json:"installed_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -0,0 +1,123 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Environment 执行环境（如 staging、production）
+// 执行脚本或定时任务时按名称选择环境，脚本无需修改即可在不同环境中运行
+type Environment struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`        // 环境名称，唯一，同时作为执行标签匹配步骤的 run_on/skip_on
+	Description string            `json:"description"` // 环境描述
+	BaseURL     string            `json:"base_url"`    // 基础 URL，替换脚本中与起始 URL 同域名的地址的协议和域名（含端口）
+	Variables   map[string]string `json:"variables"`   // 参数默认值，覆盖脚本预设变量，执行时传入的参数优先
+	// 凭据引用：变量名 -> 服务端进程的系统环境变量名（必须以 BROWSERWING_CRED_ 开头），执行时读取，凭据本身不保存在数据库中
+	Credentials map[string]string `json:"credentials"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// CredentialEnvPrefix 凭据引用的系统环境变量名前缀
+// 只允许读取专门为凭据设置的环境变量，避免能编辑环境的用户把 OPENAI_API_KEY 等服务端密钥填入页面
+const CredentialEnvPrefix = "BROWSERWING_CRED_"
+
+// IsCredentialEnvVar 环境变量名是否可以作为凭据引用
+func IsCredentialEnvVar(envVar string) bool {
+	return strings.HasPrefix(envVar, CredentialEnvPrefix) && len(envVar) > len(CredentialEnvPrefix)
+}
+
+// CredentialsSet 凭据引用的环境变量是否都已设置（不返回具体缺少哪个，避免探测服务端的环境变量）
+func (e *Environment) CredentialsSet() bool {
+	for _, envVar := range e.Credentials {
+		if !IsCredentialEnvVar(envVar) {
+			return false
+		}
+		if _, ok := os.LookupEnv(envVar); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// ResolveVariables 返回环境的变量，包括从系统环境变量读取的凭据
+func (e *Environment) ResolveVariables() (map[string]string, error) {
+	variables := make(map[string]string, len(e.Variables)+len(e.Credentials))
+	for k, v := range e.Variables {
+		variables[k] = v
+	}
+	for name, envVar := range e.Credentials {
+		if !IsCredentialEnvVar(envVar) {
+			return nil, fmt.Errorf("credential %s: environment variable name must start with %s", name, CredentialEnvPrefix)
+		}
+		value, ok := os.LookupEnv(envVar)
+		if !ok {
+			return nil, fmt.Errorf("credential %s: environment variable %s is not set", name, envVar)
+		}
+		variables[name] = value
+	}
+	return variables, nil
+}
+
+// Apply 把环境应用到脚本：设置执行环境、覆盖预设变量、替换基础 URL
+// 需在替换 ${变量名} 占位符之前调用，这样环境变量才能作为参数默认值生效
+func (e *Environment) Apply(script *Script) error {
+	variables, err := e.ResolveVariables()
+	if err != nil {
+		return err
+	}
+
+	script.Environment = e.Name
+	if script.Variables == nil {
+		script.Variables = make(map[string]string, len(variables))
+	}
+	for k, v := range variables {
+		script.Variables[k] = v
+	}
+
+	if e.BaseURL != "" {
+		return rebaseScript(script, e.BaseURL)
+	}
+	return nil
+}
+
+// rebaseScript 把脚本起始 URL 及步骤中同域名的 URL 的协议和域名替换为 baseURL 的协议和域名
+// 起始 URL 没有域名（为空或是占位符）时不做替换
+func rebaseScript(script *Script, baseURL string) error {
+	base, err := url.Parse(baseURL)
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return fmt.Errorf("invalid base URL: %s", baseURL)
+	}
+
+	origin, err := url.Parse(script.URL)
+	if err != nil || origin.Host == "" {
+		return nil
+	}
+	originHost := origin.Host
+
+	// 直接替换字符串前缀，避免重新编码导致路径中的 ${变量名} 占位符失效
+	rebase := func(rawURL string) string {
+		u, err := url.Parse(rawURL)
+		if err != nil || u.Host != originHost {
+			return rawURL
+		}
+		prefix := u.Scheme + "://" + u.Host
+		if !strings.HasPrefix(rawURL, prefix) {
+			return rawURL
+		}
+		return base.Scheme + "://" + base.Host + rawURL[len(prefix):]
+	}
+
+	script.URL = rebase(script.URL)
+	actions := make([]ScriptAction, len(script.Actions))
+	for i, action := range script.Actions {
+		action.URL = rebase(action.URL)
+		actions[i] = action
+	}
+	script.Actions = actions
+	return nil
+}
@@ -0,0 +1,67 @@
+package models
+
+import "testing"
+
+func TestEnvironmentApply(t *testing.T) {
+	t.Setenv("BROWSERWING_CRED_TEST_STAGING_PASSWORD", "s3cret")
+
+	env := &Environment{
+		Name:        "staging",
+		BaseURL:     "https://staging.example.com:8443",
+		Variables:   map[string]string{"username": "qa"},
+		Credentials: map[string]string{"password": "BROWSERWING_CRED_TEST_STAGING_PASSWORD"},
+	}
+	script := &Script{
+		URL:       "https://www.example.com/login",
+		Variables: map[string]string{"username": "default", "keyword": "shoes"},
+		Actions: []ScriptAction{
+			{Type: "navigate", URL: "https://www.example.com/search/${keyword}?page=1"},
+			{Type: "navigate", URL: "https://cdn.other.com/asset"},
+			{Type: "click", Selector: "#submit"},
+		},
+	}
+
+	if err := env.Apply(script); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if script.Environment != "staging" {
+		t.Errorf("environment = %q", script.Environment)
+	}
+	if script.URL != "https://staging.example.com:8443/login" {
+		t.Errorf("url = %q", script.URL)
+	}
+	if got := script.Actions[0].URL; got != "https://staging.example.com:8443/search/${keyword}?page=1" {
+		t.Errorf("same-host step url = %q", got)
+	}
+	if got := script.Actions[1].URL; got != "https://cdn.other.com/asset" {
+		t.Errorf("other-host step url should be kept, got %q", got)
+	}
+	want := map[string]string{"username": "qa", "password": "s3cret", "keyword": "shoes"}
+	for k, v := range want {
+		if script.Variables[k] != v {
+			t.Errorf("variable %s = %q, want %q", k, script.Variables[k], v)
+		}
+	}
+}
+
+func TestEnvironmentApplyMissingCredential(t *testing.T) {
+	env := &Environment{Name: "prod", Credentials: map[string]string{"password": "BROWSERWING_CRED_TEST_UNSET"}}
+	if err := env.Apply(&Script{}); err == nil {
+		t.Error("expected error for unset credential variable")
+	}
+}
+
+func TestEnvironmentCredentialPrefix(t *testing.T) {
+	t.Setenv("BW_TEST_SERVER_SECRET", "key")
+
+	env := &Environment{Name: "prod", Credentials: map[string]string{"token": "BW_TEST_SERVER_SECRET"}}
+	if err := env.Apply(&Script{}); err == nil {
+		t.Error("expected error for an environment variable without the credential prefix")
+	}
+	if env.CredentialsSet() {
+		t.Error("credentials without the prefix should not count as set")
+	}
+	if IsCredentialEnvVar(CredentialEnvPrefix) {
+		t.Error("the bare prefix is not a valid credential variable")
+	}
+}
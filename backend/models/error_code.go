@@ -0,0 +1,47 @@
+package models
+
+import "errors"
+
+// ErrorCode 操作失败原因的分类码，客户端和 Agent 按分类码决定如何恢复，不需要匹配错误信息的文字
+type ErrorCode string
+
+const (
+	ErrorCodeElementNotFound   ErrorCode = "ELEMENT_NOT_FOUND"  // 找不到要操作的元素（选择器失效、RefID 过期、页面尚未渲染）
+	ErrorCodeTimeout           ErrorCode = "TIMEOUT"            // 等待页面、元素或条件超时
+	ErrorCodeSessionLost       ErrorCode = "SESSION_LOST"       // 页面已关闭、崩溃或与浏览器的连接断开
+	ErrorCodeNavigationBlocked ErrorCode = "NAVIGATION_BLOCKED" // 地址被 URL 访问策略、内网防护或浏览器拦截
+	ErrorCodeCaptchaDetected   ErrorCode = "CAPTCHA_DETECTED"   // 页面要求完成验证码
+	ErrorCodeLoginRequired     ErrorCode = "LOGIN_REQUIRED"     // 已退出登录，且没有登录脚本或执行登录脚本后仍未登录
+	ErrorCodeRequestMissing    ErrorCode = "REQUEST_MISSING"    // 步骤执行后没有发出录制时的请求，或请求的状态码类别不同
+)
+
+// CodedError 带分类码的错误
+type CodedError struct {
+	Code ErrorCode
+	Err  error
+}
+
+func (e *CodedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CodedError) Unwrap() error {
+	return e.Err
+}
+
+// WithErrorCode 为错误标注分类码，err 为 nil 或 code 为空时原样返回
+func WithErrorCode(code ErrorCode, err error) error {
+	if err == nil || code == "" {
+		return err
+	}
+	return &CodedError{Code: code, Err: err}
+}
+
+// ErrorCodeOf 返回错误链中最外层标注的分类码，没有标注时返回空
+func ErrorCodeOf(err error) ErrorCode {
+	var coded *CodedError
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	return ""
+}
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// 抓取数据中值的变化类型
+const (
+	DataAdded   = "added"
+	DataRemoved = "removed"
+	DataChanged = "changed"
+)
+
+// ExecutionComparison 同一脚本两次执行的差异：Base 为作为参照的执行（如昨天成功的执行），Target 为要检查的执行
+type ExecutionComparison struct {
+	ScriptID      string                 `json:"script_id"`
+	Base          ExecutionBrief         `json:"base"`
+	Target        ExecutionBrief         `json:"target"`
+	Summary       []string               `json:"summary"`                  // 主要变化的说明，没有变化时为空列表
+	Steps         []StepComparison       `json:"steps"`                    // 两次执行的各步骤，按步骤序号排列
+	Data          []DataChange           `json:"data"`                     // 抓取数据（不含截图）的差异
+	DataTruncated bool                   `json:"data_truncated,omitempty"` // 差异过多，data 只包含前面的一部分
+	Screenshots   []ScreenshotComparison `json:"screenshots"`              // 截图步骤保存的截图
+}
+
+// ExecutionBrief 对比中单次执行的概况
+type ExecutionBrief struct {
+	ID           string    `json:"id"`
+	StartTime    time.Time `json:"start_time"`
+	Success      bool      `json:"success"`
+	ErrorMsg     string    `json:"error_msg,omitempty"`
+	Duration     int64     `json:"duration"` // 耗时（毫秒）
+	SuccessSteps int       `json:"success_steps"`
+	FailedSteps  int       `json:"failed_steps"`
+	ItemCount    *int      `json:"item_count,omitempty"` // 抓取的条目数（执行记录了抓取指标时）
+}
+
+// StepComparison 同一步骤在两次执行中的结果；某次执行没有执行到该步骤（或执行记录没有步骤结果）时对应的状态为空
+type StepComparison struct {
+	Index          int    `json:"index"` // 步骤序号，从 1 开始
+	Type           string `json:"type"`
+	BaseStatus     string `json:"base_status,omitempty"`
+	TargetStatus   string `json:"target_status,omitempty"`
+	BaseDuration   int64  `json:"base_duration"`   // 耗时（毫秒）
+	TargetDuration int64  `json:"target_duration"` // 耗时（毫秒）
+	TargetError    string `json:"target_error,omitempty"`
+	Changed        bool   `json:"changed"` // 两次执行的步骤类型或结果不同
+}
+
+// DataChange 抓取数据中一个值的变化
+type DataChange struct {
+	Path   string      `json:"path"`   // 值的位置，如 items[2].price
+	Change string      `json:"change"` // 见 Data* 常量
+	Base   interface{} `json:"base,omitempty"`
+	Target interface{} `json:"target,omitempty"`
+}
+
+// ScreenshotComparison 同一截图变量在两次执行中保存的截图
+type ScreenshotComparison struct {
+	Variable   string `json:"variable"`
+	BasePath   string `json:"base_path,omitempty"`
+	TargetPath string `json:"target_path,omitempty"`
+	Identical  *bool  `json:"identical,omitempty"` // 两张截图的内容是否相同，任一文件已不存在时为空
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// ExtractionMetrics 一次执行抓取结果的统计指标，与脚本的历史执行比较，发现执行成功但结果可疑的情况
+type ExtractionMetrics struct {
+	ItemCount int     `json:"item_count"` // 条目数：数组变量按元素个数计，其他非空变量各计 1
+	NullRate  float64 `json:"null_rate"`  // 空值比例（0-1）：条目及对象条目的各字段中 null 和空字符串所占的比例
+}
+
+// ScriptMetricsPoint 脚本抓取指标历史中的一次执行
+type ScriptMetricsPoint struct {
+	ExecutionID string             `json:"execution_id"`
+	StartTime   time.Time          `json:"start_time"`
+	Success     bool               `json:"success"`
+	Metrics     *ExtractionMetrics `json:"metrics,omitempty"`
+	Anomalies   []string           `json:"anomalies,omitempty"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// 可以路由到小模型的 LLM 调用步骤。Agent 调用工具完成任务、生成抓取和填表脚本等需要推理的步骤
+// 始终使用会话选择的模型或默认模型
+const (
+	LLMStepTaskEvaluation     = "task_evaluation"     // Agent 判断任务是否需要工具及复杂度
+	LLMStepActionVerification = "action_verification" // Agent 操作后校验页面是否发生预期的变化
+	LLMStepTranslation        = "translation"         // 页面翻译
+	LLMStepMCPInfo            = "mcp_info"            // 根据脚本生成 MCP 命令名称、描述和参数
+)
+
+// LLMCheapSteps 可以路由到小模型的步骤
+var LLMCheapSteps = []string{LLMStepTaskEvaluation, LLMStepActionVerification, LLMStepTranslation, LLMStepMCPInfo}
+
+// LLMRoutingPolicy LLM 路由策略：把简单的步骤交给便宜的小模型，其余步骤使用默认模型
+type LLMRoutingPolicy struct {
+	CheapConfigID string    `json:"cheap_config_id"` // 小模型的 LLM 配置 ID，为空表示不路由
+	CheapSteps    []string  `json:"cheap_steps"`     // 使用小模型的步骤，见 LLMCheapSteps
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	// 脚本推荐：用嵌入模型找出和 Agent 任务相似的脚本，优先调用脚本工具。配置 ID 为空表示不推荐
+	EmbeddingConfigID string `json:"embedding_config_id,omitempty"` // 提供 OpenAI 兼容嵌入接口的 LLM 配置 ID
+	EmbeddingModel    string `json:"embedding_model,omitempty"`     // 嵌入模型，如 text-embedding-3-small
+}
+
+// Routes 步骤是否应使用小模型
+func (p *LLMRoutingPolicy) Routes(step string) bool {
+	if p == nil || p.CheapConfigID == "" {
+		return false
+	}
+	for _, s := range p.CheapSteps {
+		if s == step {
+			return true
+		}
+	}
+	return false
+}
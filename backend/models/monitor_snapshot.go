@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// MonitorSnapshot 内容监控任务最近一次抓取的内容（用于与下一次运行对比）
+type MonitorSnapshot struct {
+	TaskID    string    `json:"task_id"`    // 关联的定时任务 ID
+	URL       string    `json:"url"`        // 监控的页面 URL
+	Selector  string    `json:"selector"`   // 监控区域的 CSS 选择器
+	Content   string    `json:"content"`    // 规范化后的内容
+	CheckedAt time.Time `json:"checked_at"` // 最近检查时间
+	ChangedAt time.Time `json:"changed_at"` // 最近一次内容变化的时间
+}
@@ -0,0 +1,174 @@
+package models
+
+import "time"
+
+// NotificationChannelType 通知渠道类型
+type NotificationChannelType string
+
+const (
+	NotificationChannelSlack    NotificationChannelType = "slack"    // Slack Incoming Webhook
+	NotificationChannelDiscord  NotificationChannelType = "discord"  // Discord Webhook
+	NotificationChannelTelegram NotificationChannelType = "telegram" // Telegram Bot
+	NotificationChannelEmail    NotificationChannelType = "email"    // SMTP 邮件
+)
+
+// NotificationEvent 触发通知的事件
+type NotificationEvent string
+
+const (
+	NotificationEventScriptFailed   NotificationEvent = "script.failed"   // 脚本执行失败（手动、API 或定时任务执行）
+	NotificationEventTaskCompleted  NotificationEvent = "task.completed"  // 定时任务执行完成（无论成功或失败）
+	NotificationEventTaskFailed     NotificationEvent = "task.failed"     // 定时任务执行失败
+	NotificationEventMonitorChanged NotificationEvent = "monitor.changed" // 监控任务检测到内容变化
+	NotificationEventBlocked        NotificationEvent = "page.blocked"    // 脚本或定时任务被验证码、反爬页面拦截
+	NotificationEventSchemaDrift    NotificationEvent = "schema.drift"    // 脚本抓取的数据不符合 output_schema（如网站改版导致字段缺失）
+	NotificationEventAnomaly        NotificationEvent = "data.anomaly"    // 脚本执行成功，但条目数、空值比例与历史执行相比异常
+)
+
+// NotificationChannel 通知渠道配置
+// Webhook 地址、Bot Token 和 SMTP 密码只写不读：API 响应中会被清空，并通过 Has* 标识是否已设置
+type NotificationChannel struct {
+	ID      string                  `json:"id"`
+	Name    string                  `json:"name"`
+	Type    NotificationChannelType `json:"type"`
+	Enabled bool                    `json:"enabled"`
+
+	// Slack / Discord
+	WebhookURL    string `json:"webhook_url,omitempty"`     // Webhook 地址（地址中包含密钥）
+	HasWebhookURL bool   `json:"has_webhook_url,omitempty"` // 仅用于响应，表示已保存 Webhook 地址
+
+	// Telegram
+	BotToken    string `json:"bot_token,omitempty"`     // Bot Token（从 @BotFather 获取）
+	HasBotToken bool   `json:"has_bot_token,omitempty"` // 仅用于响应，表示已保存 Bot Token
+	ChatID      string `json:"chat_id,omitempty"`       // 接收消息的聊天 ID 或 @频道名
+
+	// Email
+	SMTPHost        string   `json:"smtp_host,omitempty"`
+	SMTPPort        int      `json:"smtp_port,omitempty"`     // 默认 587；465 使用 TLS 直连，其他端口在服务器支持时使用 STARTTLS
+	SMTPUsername    string   `json:"smtp_username,omitempty"` // 为空时不认证
+	SMTPPassword    string   `json:"smtp_password,omitempty"`
+	HasSMTPPassword bool     `json:"has_smtp_password,omitempty"` // 仅用于响应，表示已保存 SMTP 密码
+	From            string   `json:"from,omitempty"`              // 发件人地址
+	To              []string `json:"to,omitempty"`                // 收件人地址
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Redacted 返回去除 Webhook 地址、Bot Token 和 SMTP 密码的副本，用于 API 响应
+func (c NotificationChannel) Redacted() NotificationChannel {
+	c.HasWebhookURL = c.WebhookURL != ""
+	c.HasBotToken = c.BotToken != ""
+	c.HasSMTPPassword = c.SMTPPassword != ""
+	c.WebhookURL = ""
+	c.BotToken = ""
+	c.SMTPPassword = ""
+	return c
+}
+
+// KeepSecretsFrom 更新渠道时未提交的密钥沿用原值（渠道类型不变，SMTP 密码还要求服务器和用户名不变）
+func (c *NotificationChannel) KeepSecretsFrom(old *NotificationChannel) {
+	c.HasWebhookURL = false
+	c.HasBotToken = false
+	c.HasSMTPPassword = false
+	if old == nil || old.Type != c.Type {
+		return
+	}
+	if c.WebhookURL == "" {
+		c.WebhookURL = old.WebhookURL
+	}
+	if c.BotToken == "" {
+		c.BotToken = old.BotToken
+	}
+	if c.SMTPPassword == "" && c.SMTPHost == old.SMTPHost && c.SMTPUsername == old.SMTPUsername {
+		c.SMTPPassword = old.SMTPPassword
+	}
+}
+
+// NotificationRule 通知路由规则：事件发生时发送到哪些渠道
+type NotificationRule struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Enabled    bool              `json:"enabled"`
+	Event      NotificationEvent `json:"event"`
+	ChannelIDs []string          `json:"channel_ids"`
+	// 只匹配指定脚本或定时任务的事件，为空时匹配全部
+	ScriptID  string    `json:"script_id,omitempty"`
+	TaskID    string    `json:"task_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Matches 规则是否匹配事件
+func (r *NotificationRule) Matches(event NotificationEvent, scriptID, taskID string) bool {
+	if !r.Enabled || r.Event != event {
+		return false
+	}
+	if r.ScriptID != "" && r.ScriptID != scriptID {
+		return false
+	}
+	if r.TaskID != "" && r.TaskID != taskID {
+		return false
+	}
+	return true
+}
+
+// DigestPeriod 执行报告摘要的周期
+type DigestPeriod string
+
+const (
+	DigestPeriodDaily  DigestPeriod = "daily"  // 每天
+	DigestPeriodWeekly DigestPeriod = "weekly" // 每周
+)
+
+// NotificationDigest 定期发送到通知渠道的执行报告摘要
+// 汇总上一个周期内的执行次数、失败和抓取数据，邮件渠道发送 HTML 报告，其他渠道发送文字摘要
+type NotificationDigest struct {
+	ID         string       `json:"id"`
+	Name       string       `json:"name"`
+	Enabled    bool         `json:"enabled"`
+	Period     DigestPeriod `json:"period"`
+	Hour       int          `json:"hour"`    // 发送时间（服务器本地时间的小时，0-23）
+	Weekday    time.Weekday `json:"weekday"` // 每周摘要的发送日，0 为周日
+	ChannelIDs []string     `json:"channel_ids"`
+	ScriptIDs  []string     `json:"script_ids,omitempty"` // 只统计这些脚本，为空时统计全部
+	LastSentAt *time.Time   `json:"last_sent_at,omitempty"`
+	CreatedAt  time.Time    `json:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+}
+
+// Interval 摘要周期的时长
+func (d *NotificationDigest) Interval() time.Duration {
+	if d.Period == DigestPeriodWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// LastScheduled 返回不晚于 now 的最近一个计划发送时间
+func (d *NotificationDigest) LastScheduled(now time.Time) time.Time {
+	at := time.Date(now.Year(), now.Month(), now.Day(), d.Hour, 0, 0, 0, now.Location())
+	if d.Period == DigestPeriodWeekly {
+		at = at.AddDate(0, 0, -int((now.Weekday()-d.Weekday+7)%7))
+	}
+	if at.After(now) {
+		if d.Period == DigestPeriodWeekly {
+			return at.AddDate(0, 0, -7)
+		}
+		return at.AddDate(0, 0, -1)
+	}
+	return at
+}
+
+// Due 摘要是否需要发送：最近的计划发送时间晚于上次发送（从未发送时以创建时间为准）
+// 服务停止期间错过的发送在启动后补发一次
+func (d *NotificationDigest) Due(now time.Time) bool {
+	if !d.Enabled {
+		return false
+	}
+	last := d.CreatedAt
+	if d.LastSentAt != nil {
+		last = *d.LastSentAt
+	}
+	return d.LastScheduled(now).After(last)
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNotificationDigestSchedule(t *testing.T) {
+	loc := time.UTC
+	// 2026-03-04 是周三
+	now := time.Date(2026, 3, 4, 10, 30, 0, 0, loc)
+
+	daily := &NotificationDigest{Enabled: true, Period: DigestPeriodDaily, Hour: 9}
+	if got, want := daily.LastScheduled(now), time.Date(2026, 3, 4, 9, 0, 0, 0, loc); !got.Equal(want) {
+		t.Errorf("daily at 9: got %v, want %v", got, want)
+	}
+	daily.Hour = 11
+	if got, want := daily.LastScheduled(now), time.Date(2026, 3, 3, 11, 0, 0, 0, loc); !got.Equal(want) {
+		t.Errorf("daily at 11: got %v, want %v", got, want)
+	}
+
+	weekly := &NotificationDigest{Enabled: true, Period: DigestPeriodWeekly, Hour: 8, Weekday: time.Monday}
+	if got, want := weekly.LastScheduled(now), time.Date(2026, 3, 2, 8, 0, 0, 0, loc); !got.Equal(want) {
+		t.Errorf("weekly monday: got %v, want %v", got, want)
+	}
+	weekly.Weekday, weekly.Hour = time.Wednesday, 12
+	if got, want := weekly.LastScheduled(now), time.Date(2026, 2, 25, 12, 0, 0, 0, loc); !got.Equal(want) {
+		t.Errorf("weekly wednesday noon: got %v, want %v", got, want)
+	}
+
+	daily.Hour = 9
+	daily.CreatedAt = time.Date(2026, 3, 4, 9, 30, 0, 0, loc)
+	if daily.Due(now) {
+		t.Error("digest created after today's send time should wait until tomorrow")
+	}
+	daily.CreatedAt = time.Date(2026, 3, 1, 0, 0, 0, 0, loc)
+	if !daily.Due(now) {
+		t.Error("digest never sent should be due")
+	}
+	sent := time.Date(2026, 3, 4, 9, 0, 5, 0, loc)
+	daily.LastSentAt = &sent
+	if daily.Due(now) {
+		t.Error("digest already sent today should not be due")
+	}
+}
+
+func TestNotificationChannelSecrets(t *testing.T) {
+	saved := &NotificationChannel{
+		Type:         NotificationChannelEmail,
+		SMTPHost:     "smtp.example.com",
+		SMTPUsername: "bot",
+		SMTPPassword: "secret",
+	}
+	redacted := saved.Redacted()
+	if redacted.SMTPPassword != "" || !redacted.HasSMTPPassword || redacted.HasBotToken {
+		t.Fatalf("unexpected redacted channel: %+v", redacted)
+	}
+	if saved.SMTPPassword != "secret" {
+		t.Fatal("Redacted modified the saved channel")
+	}
+
+	// 提交响应中的渠道时保留原密码
+	update := redacted
+	update.KeepSecretsFrom(saved)
+	if update.SMTPPassword != "secret" || update.HasSMTPPassword {
+		t.Errorf("expected the saved password to be kept, got %+v", update)
+	}
+	// 更换服务器时不沿用原密码
+	update = redacted
+	update.SMTPHost = "smtp.attacker.example"
+	update.KeepSecretsFrom(saved)
+	if update.SMTPPassword != "" {
+		t.Error("password should not be kept for a different server")
+	}
+
+	webhook := &NotificationChannel{Type: NotificationChannelSlack, WebhookURL: "https://hooks.slack.com/services/T/B/x"}
+	update = webhook.Redacted()
+	update.Type = NotificationChannelDiscord
+	update.KeepSecretsFrom(webhook)
+	if update.WebhookURL != "" {
+		t.Error("webhook url should not be kept when the channel type changes")
+	}
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// PageComponent 页面组件（Page Object）：某个站点上一组具名元素的定位器
+// 脚本步骤通过 "组件名.元素名"（如 login.username）引用元素，定位器只需在组件中修改一次，
+// 所有引用它的脚本在下次回放时都会使用新的定位器
+type PageComponent struct {
+	ID          string                      `json:"id"`
+	Name        string                      `json:"name"`        // 组件名，如 login（不能包含 "."）
+	Site        string                      `json:"site"`        // 适用的站点域名，如 example.com（同时匹配子域名），为空时适用于所有站点
+	Description string                      `json:"description"` // 组件描述
+	Elements    map[string]ComponentElement `json:"elements"`    // 元素名 -> 定位器
+	CreatedAt   time.Time                   `json:"created_at"`
+	UpdatedAt   time.Time                   `json:"updated_at"`
+}
+
+// ComponentElement 页面组件中的一个元素
+type ComponentElement struct {
+	Selector    string `json:"selector,omitempty"`    // CSS 选择器
+	XPath       string `json:"xpath,omitempty"`       // XPath（优先于 Selector）
+	Description string `json:"description,omitempty"` // 元素描述
+}
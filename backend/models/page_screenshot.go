@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// PageScreenshot 定时截图存档中的一张截图
+type PageScreenshot struct {
+	ID        string    `json:"id"`
+	TaskID    string    `json:"task_id"`   // 关联的定时任务 ID
+	URL       string    `json:"url"`       // 截图的页面 URL
+	Path      string    `json:"path"`      // 截图文件路径
+	FileName  string    `json:"file_name"` // 截图文件名
+	Size      int       `json:"size"`      // 文件大小（字节）
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -6,14 +6,25 @@ import (
 
 // RecordingConfig 录制配置
 type RecordingConfig struct {
-	ID        string    `json:"id"`         // 配置 ID（固定为 "default"）
-	Enabled   bool      `json:"enabled"`    // 是否启用录制
-	FrameRate int       `json:"frame_rate"` // 帧率（默认 15）
-	Quality   int       `json:"quality"`    // 质量 0-100（默认 70）
-	Format    string    `json:"format"`     // 输出格式（默认 "mp4"）
-	OutputDir string    `json:"output_dir"` // 输出目录（默认 "recordings"）
-	CreatedAt time.Time `json:"created_at"` // 创建时间
-	UpdatedAt time.Time `json:"updated_at"` // 更新时间
+	ID        string         `json:"id"`                   // 配置 ID（固定为 "default"）
+	Enabled   bool           `json:"enabled"`              // 是否启用录制
+	FrameRate int            `json:"frame_rate"`           // 帧率（默认 15）
+	Quality   int            `json:"quality"`              // 质量 0-100（默认 70）
+	Format    string         `json:"format"`               // 输出格式（默认 "mp4"）
+	OutputDir string         `json:"output_dir"`           // 输出目录（默认 "recordings"）
+	MaxWidth  int            `json:"max_width,omitempty"`  // 录制画面最大宽度（像素，0 表示不限制）
+	MaxHeight int            `json:"max_height,omitempty"` // 录制画面最大高度（像素，0 表示不限制）
+	Region    *CaptureRegion `json:"region,omitempty"`     // 录制区域（相对视口的 CSS 像素，为空时录制整个视口）
+	CreatedAt time.Time      `json:"created_at"`           // 创建时间
+	UpdatedAt time.Time      `json:"updated_at"`           // 更新时间
+}
+
+// CaptureRegion 录制区域
+type CaptureRegion struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
 }
 
 // GetDefaultRecordingConfig 获取默认录制配置
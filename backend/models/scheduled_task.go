@@ -17,8 +17,12 @@ const (
 type ExecutionType string
 
 const (
-	ExecutionTypeScript ExecutionType = "script" // 执行脚本
-	ExecutionTypeAgent  ExecutionType = "agent"  // 调用 agent
+	ExecutionTypeScript      ExecutionType = "script"       // 执行脚本
+	ExecutionTypeAgent       ExecutionType = "agent"        // 调用 agent
+	ExecutionTypeCrawl       ExecutionType = "crawl"        // 批量抓取（对 URL 列表或 sitemap 中的每个 URL 执行同一个脚本）
+	ExecutionTypeMonitor     ExecutionType = "monitor"      // 内容变化监控（对比前后两次内容，变化超过阈值时通知）
+	ExecutionTypeScreenshot  ExecutionType = "screenshot"   // 定时截图存档（为每个 URL 保留截图历史）
+	ExecutionTypeHealthCheck ExecutionType = "health_check" // 选择器健康检查（打开脚本的页面，检查录制的定位器是否仍能找到元素）
 )
 
 // ScheduledTask 定时任务
@@ -38,13 +42,14 @@ type ScheduledTask struct {
 	ScheduleConfig string `json:"schedule_config"`
 
 	// 执行配置
-	ExecutionType ExecutionType `json:"execution_type"` // script, agent
+	ExecutionType ExecutionType `json:"execution_type"` // script, agent, crawl, monitor, screenshot, health_check
 
-	// 脚本执行配置（当 execution_type 为 script 时使用）
+	// 脚本执行配置（当 execution_type 为 script 时使用；crawl 时作为每个 URL 的抓取模板）
 	ScriptID         string            `json:"script_id,omitempty"`          // 脚本 ID
 	ScriptName       string            `json:"script_name,omitempty"`        // 脚本名称（冗余字段，便于显示）
 	ScriptVariables  map[string]string `json:"script_variables,omitempty"`   // 脚本变量
 	BrowserInstanceID string           `json:"browser_instance_id,omitempty"` // 浏览器实例 ID（可选）
+	Environment       string           `json:"environment,omitempty"`         // 执行环境名称（可选，script 和 crawl 任务使用）
 
 	// Agent 执行配置（当 execution_type 为 agent 时使用）
 	AgentPrompt   string `json:"agent_prompt,omitempty"`    // Agent 提示词
@@ -52,6 +57,26 @@ type ScheduledTask struct {
 	AgentLLMName  string `json:"agent_llm_name,omitempty"`  // LLM 配置名称（冗余字段）
 	AgentSessionID string `json:"agent_session_id,omitempty"` // 关联的会话 ID（如果需要上下文）
 
+	// 批量抓取配置（当 execution_type 为 crawl 时使用）
+	CrawlURLs        []string `json:"crawl_urls,omitempty"`        // 种子 URL 列表
+	CrawlSitemapURL  string   `json:"crawl_sitemap_url,omitempty"` // sitemap.xml 地址（支持 sitemap 索引）
+	CrawlConcurrency int      `json:"crawl_concurrency,omitempty"` // 并发数（默认 2）
+	CrawlMaxURLs     int      `json:"crawl_max_urls,omitempty"`    // 最多抓取的 URL 数（默认 100）
+
+	// 内容监控配置（当 execution_type 为 monitor 时使用）
+	MonitorURL        string  `json:"monitor_url,omitempty"`         // 监控的页面 URL
+	MonitorSelector   string  `json:"monitor_selector,omitempty"`    // 监控区域的 CSS 选择器（为空时监控整个页面）
+	MonitorThreshold  float64 `json:"monitor_threshold,omitempty"`   // 触发通知的变化百分比（0-100，0 表示任何变化都通知）
+	MonitorWebhookURL string  `json:"monitor_webhook_url,omitempty"` // 内容变化时 POST 通知的 Webhook 地址
+
+	// 截图存档配置（当 execution_type 为 screenshot 时使用）
+	ScreenshotURLs      []string `json:"screenshot_urls,omitempty"`      // 需要截图的页面 URL 列表
+	ScreenshotMode      string   `json:"screenshot_mode,omitempty"`      // viewport 或 fullpage（默认 fullpage）
+	ScreenshotRetention int      `json:"screenshot_retention,omitempty"` // 每个 URL 保留的截图数量（默认 30）
+
+	// 选择器健康检查配置（当 execution_type 为 health_check 时使用）
+	HealthCheckScriptIDs []string `json:"health_check_script_ids,omitempty"` // 检查的脚本 ID 列表（为空时检查所有设置了起始 URL 的脚本）
+
 	// 执行状态
 	LastExecutionTime *time.Time `json:"last_execution_time,omitempty"` // 上次执行时间
 	NextExecutionTime *time.Time `json:"next_execution_time,omitempty"` // 下次执行时间
@@ -64,24 +89,42 @@ type ScheduledTask struct {
 // TaskExecution 定时任务执行记录
 type TaskExecution struct {
 	ID        string    `json:"id"`
-	TaskID    string    `json:"task_id"`    // 关联的定时任务 ID
-	TaskName  string    `json:"task_name"`  // 任务名称（冗余）
-	StartTime time.Time `json:"start_time"` // 开始时间
-	EndTime   time.Time `json:"end_time"`   // 结束时间
-	Duration  int64     `json:"duration"`   // 执行耗时（毫秒）
-	Success   bool      `json:"success"`    // 是否成功
-	Message   string    `json:"message"`    // 执行消息
-	ErrorMsg  string    `json:"error_msg"`  // 错误信息
+	TaskID    string    `json:"task_id"`              // 关联的定时任务 ID
+	TaskName  string    `json:"task_name"`            // 任务名称（冗余）
+	StartTime time.Time `json:"start_time"`           // 开始时间
+	EndTime   time.Time `json:"end_time"`             // 结束时间
+	Duration  int64     `json:"duration"`             // 执行耗时（毫秒）
+	Success   bool      `json:"success"`              // 是否成功
+	Message   string    `json:"message"`              // 执行消息
+	ErrorMsg  string    `json:"error_msg"`            // 错误信息
+	ErrorCode ErrorCode `json:"error_code,omitempty"` // 失败原因分类码
 
 	// 执行结果数据
 	// - 对于脚本执行：存储 PlayResult 的 ExtractedData
 	// - 对于 Agent 执行：存储 Agent 返回的内容
+	// - 对于批量抓取：存储汇总的数据集及每个 URL 的状态
+	// - 对于内容监控：存储变化百分比及新增/删除的内容行
+	// - 对于截图存档：存储本次生成的截图记录
 	ResultData map[string]interface{} `json:"result_data,omitempty"` // 执行结果数据
 
 	// 执行类型和关联信息
-	ExecutionType ExecutionType `json:"execution_type"` // script, agent
+	ExecutionType ExecutionType `json:"execution_type"` // script, agent, crawl, monitor, screenshot, health_check
 	ScriptID      string        `json:"script_id,omitempty"`
 	AgentSessionID string       `json:"agent_session_id,omitempty"`
 
 	CreatedAt time.Time `json:"created_at"` // 记录创建时间
 }
+
+// ScheduledRun 定时任务的一次计划执行，用于日历视图和 iCal 订阅
+type ScheduledRun struct {
+	TaskID        string        `json:"task_id"`
+	TaskName      string        `json:"task_name"`
+	Description   string        `json:"description,omitempty"`
+	ExecutionType ExecutionType `json:"execution_type"`
+	ScriptID      string        `json:"script_id,omitempty"`
+	ScriptName    string        `json:"script_name,omitempty"`
+	ScheduleType  ScheduleType  `json:"schedule_type"`
+	Schedule      string        `json:"schedule"`   // 调度配置（schedule_config）
+	StartTime     time.Time     `json:"start_time"` // 计划开始时间
+	EndTime       time.Time     `json:"end_time"`   // 预计结束时间（按最近几次执行的平均耗时估算）
+}
@@ -20,7 +20,7 @@ type ScriptAction struct {
 	// =========================
 	// 原有字段（保持不变）
 	// =========================
-	Type      string            `json:"type"`      // click, input, select, navigate, wait, sleep, extract_text, extract_attribute, extract_html, execute_js, upload_file, scroll, keyboard, open_tab, switch_tab, switch_active_tab, ai_control
+	Type      string            `json:"type"`      // click, input, select, navigate, wait, sleep, extract_text, extract_attribute, extract_html, execute_js, upload_file, scroll, keyboard, open_tab, switch_tab, switch_active_tab, wait_popup, wait_popup_close, capture_xhr, capture_response, hover_then_click, ai_control, a11y_scan, call_script
 	Timestamp int64             `json:"timestamp"` // 时间戳（毫秒）
 	Selector  string            `json:"selector"`  // CSS选择器
 	XPath     string            `json:"xpath"`     // XPath选择器（更可靠）
@@ -55,11 +55,25 @@ type ScriptAction struct {
 	ScrollX int `json:"scroll_x,omitempty"`
 	ScrollY int `json:"scroll_y,omitempty"`
 	
-	// XHR请求相关字段（用于 capture_xhr 类型）
+	// XHR请求相关字段（用于 capture_xhr 类型；capture_response 使用 URL 作为匹配模式，Method 可选）
 	Method string `json:"method,omitempty"` // HTTP方法: GET, POST, PUT, DELETE等
 	Status int    `json:"status,omitempty"` // HTTP状态码
 	XHRID  string `json:"xhr_id,omitempty"` // XHR请求唯一标识符
 
+	// 悬停菜单相关字段（用于 hover_then_click 类型：先悬停 Selector/XPath 指定的触发元素，再点击出现的目标元素；Duration 为等待目标出现的超时毫秒数）
+	TargetSelector string `json:"target_selector,omitempty"` // 目标元素 CSS 选择器
+	TargetXPath    string `json:"target_xpath,omitempty"`    // 目标元素 XPath
+
+	// 页面组件引用：如 "login.username"，回放时用页面组件中当前的定位器替换 Selector 和 XPath
+	Component string `json:"component,omitempty"`
+
+	// 子脚本调用相关字段（用于 call_script 类型：回放前把被调用脚本的步骤展开到当前位置，其抓取的变量对后续步骤可见）
+	ScriptID     string            `json:"script_id,omitempty"`     // 被调用的脚本 ID
+	ScriptParams map[string]string `json:"script_params,omitempty"` // 传给被调用脚本的参数，值中可使用 ${变量名} 引用当前脚本的变量
+
+	// 输入相关字段（用于 input 类型）
+	EditorStrategy string `json:"editor_strategy,omitempty"` // 富文本编辑器策略：auto（默认，自动识别）、input、contenteditable、draftjs、prosemirror、quill、ckeditor、tinymce
+
 	// 截图相关字段（用于 screenshot 类型）
 	ScreenshotMode   string `json:"screenshot_mode,omitempty"`   // viewport, fullpage, region
 	ScreenshotWidth  int    `json:"screenshot_width,omitempty"`  // 截图区域宽度（region模式）
@@ -70,8 +84,30 @@ type ScriptAction struct {
 	AIControlXPath       string `json:"ai_control_xpath,omitempty"`        // 可选的元素XPath（用于提示词上下文）
 	AIControlLLMConfigID string `json:"ai_control_llm_config_id,omitempty"` // AI控制使用的LLM配置ID（为空则使用默认）
 
+	// 可访问性扫描相关字段（用于 a11y_scan 类型：Selector 限定扫描区域，结果保存到 VariableName）
+	A11yTags          []string `json:"a11y_tags,omitempty"`           // 只运行带这些标签的 axe-core 规则，如 wcag2a、wcag2aa，为空时运行全部规则
+	A11yMinImpact     string   `json:"a11y_min_impact,omitempty"`     // 只统计不低于该等级的违规：minor、moderate、serious、critical
+	A11yMaxViolations *int     `json:"a11y_max_violations,omitempty"` // 允许的最大违规数量，超过时步骤失败（为空时只记录不失败）
+
+	// 增量抓取（用于抓取类步骤，需要设置 VariableName）：Dedup 为 true 时去掉之前的成功执行已输出过的条目，
+	// 数组按元素去重，条目键为对象元素的 DedupKey 字段（为空时为整个元素）；全部条目都输出过时不保存该变量
+	Dedup    bool   `json:"dedup,omitempty"`
+	DedupKey string `json:"dedup_key,omitempty"`
+	// UpdateCursor 为 true 时把抓取结果保存为脚本的游标，之后的执行通过 ${state.cursor} 引用
+	UpdateCursor bool `json:"update_cursor,omitempty"`
+
 	Condition *ActionCondition `json:"condition,omitempty"`
 
+	// 步骤开关（回放时判断）：Disabled 为 true 时始终跳过；RunOn 非空时只在执行标签命中其中之一时执行；
+	// SkipOn 命中任一执行标签时跳过。执行标签为脚本的执行环境（Script.Environment）加上执行标签（Script.RunTags），不区分大小写
+	Disabled bool     `json:"disabled,omitempty"`
+	RunOn    []string `json:"run_on,omitempty"`  // 如 ["staging"]
+	SkipOn   []string `json:"skip_on,omitempty"` // 如 ["production"]
+
+	// 录制时该步骤触发的 XHR/Fetch 请求（开始录制时设置 record_requests 才记录，也可以手动填写）
+	// 回放时步骤执行后等待这些请求再次发出，没有发出或状态码类别不同时步骤失败
+	ExpectedRequests []ExpectedRequest `json:"expected_requests,omitempty"`
+
 	// =========================
 	// 新增字段（v2，自愈核心）
 	// =========================
@@ -87,6 +123,9 @@ type ScriptAction struct {
 
 	// ④ 录制证据（debug / 自愈评分用）
 	Evidence *ActionEvidence `json:"evidence,omitempty"`
+
+	// ⑤ 元素结构指纹（回放时选择器失效或命中错误元素时，用于挑选最相似的当前元素）
+	Fingerprint *ElementFingerprint `json:"fingerprint,omitempty"`
 }
 
 func (a *ScriptAction) CopyWithoutSemanticInfo() *ScriptAction {
@@ -120,13 +159,26 @@ func (a *ScriptAction) CopyWithoutSemanticInfo() *ScriptAction {
 		Method:           a.Method,
 		Status:           a.Status,
 		XHRID:            a.XHRID,
+		TargetSelector:   a.TargetSelector,
+		TargetXPath:      a.TargetXPath,
+		Component:        a.Component,
+		ScriptID:         a.ScriptID,
+		ScriptParams:     a.ScriptParams,
+		EditorStrategy:   a.EditorStrategy,
 		ScreenshotMode:       a.ScreenshotMode,
 		ScreenshotWidth:      a.ScreenshotWidth,
 		ScreenshotHeight:     a.ScreenshotHeight,
 		AIControlPrompt:      a.AIControlPrompt,
 		AIControlXPath:       a.AIControlXPath,
 		AIControlLLMConfigID: a.AIControlLLMConfigID,
+		A11yTags:             a.A11yTags,
+		A11yMinImpact:        a.A11yMinImpact,
+		A11yMaxViolations:    a.A11yMaxViolations,
 		Condition:            a.Condition,
+		Disabled:             a.Disabled,
+		RunOn:                a.RunOn,
+		SkipOn:               a.SkipOn,
+		ExpectedRequests:     a.ExpectedRequests,
 	}
 }
 
@@ -138,6 +190,13 @@ type ActionCondition struct {
 	Enabled  bool   `json:"enabled,omitempty"` // 是否启用条件（默认false）
 }
 
+// ExpectedRequest 步骤应触发的请求
+type ExpectedRequest struct {
+	Method string `json:"method"`           // 请求方法，如 POST
+	URL    string `json:"url"`              // URL 模式，与 capture_response 相同：含 * 时按通配符匹配完整 URL，否则按子串匹配
+	Status int    `json:"status,omitempty"` // 录制时的状态码，回放时要求同一类别（如 2xx），为 0 时接受任何小于 400 的状态码
+}
+
 type ActionIntent struct {
 	Verb   string `json:"verb,omitempty"`   // click, input, select, submit
 	Object string `json:"object,omitempty"` // login button, email input
@@ -161,6 +220,15 @@ type ActionEvidence struct {
 	Confidence       float64 `json:"confidence,omitempty"` // 录制时匹配置信度
 }
 
+// ElementFingerprint 元素结构指纹，由录制器和回放共用的同一段脚本采集
+type ElementFingerprint struct {
+	Tag       string            `json:"tag"`
+	Attrs     map[string]string `json:"attrs,omitempty"`     // 稳定属性（id、name、role、aria-label、data-testid 等，已过滤动态值）
+	AttrHash  string            `json:"attr_hash,omitempty"` // Attrs 的哈希，完全一致时可直接判定属性匹配
+	Text      string            `json:"text,omitempty"`      // 归一化文本（小写、合并空白），用于 shingle 相似度
+	Ancestors []string          `json:"ancestors,omitempty"` // 祖先签名（由近及远，如 form#login、div[dialog]）
+}
+
 // Script 自动化脚本
 type Script struct {
 	ID          string         `json:"id"`
@@ -175,6 +243,7 @@ type Script struct {
 	Duration    int64          `json:"duration"`    // 录制时长（毫秒）
 	CanPublish  bool           `json:"can_publish"` // 是否可作为发布器使用
 	CanFetch    bool           `json:"can_fetch"`   // 是否可作为抓取器使用
+	Incognito   bool           `json:"incognito"`   // 是否在独立的无痕浏览器上下文中执行（不共享 Cookie，执行结束后自动销毁）
 
 	// 下载文件信息
 	DownloadedFiles []DownloadedFile `json:"downloaded_files,omitempty"` // 录制过程中下载的文件列表
@@ -187,6 +256,89 @@ type Script struct {
 
 	// 预设变量（可以在脚本中使用 ${变量名} 引用，也可以在外部调用时传入覆盖）
 	Variables map[string]string `json:"variables,omitempty"` // 预设变量，key 为变量名，value 为默认值
+
+	// 参数表单的展示和校验设置，key 为参数名；与 MCPInputSchema 一起生成 Web 界面和 MCP 客户端的参数表单
+	ParamUISchema map[string]ParamUIField `json:"param_ui_schema,omitempty"`
+
+	// 请求覆盖（回放期间对所有请求生效，值支持 ${变量名} 占位符）
+	Headers   map[string]string `json:"headers,omitempty"`    // 额外的 HTTP 请求头，例如 Authorization
+	UserAgent string            `json:"user_agent,omitempty"` // 覆盖 User-Agent
+
+	// 性能监控（回放时采集 Core Web Vitals / 性能 trace，结果写入执行记录）
+	Performance *PerformanceOptions `json:"performance,omitempty"`
+
+	// 回放时的超时设置，覆盖配置文件 [timeouts] 中的默认值（内网慢速应用调大，快速抓取调小）
+	Timeouts *ScriptTimeouts `json:"timeouts,omitempty"`
+
+	// 执行环境和执行标签（如 staging、production、smoke），用于匹配步骤的 RunOn/SkipOn，执行时可通过参数覆盖
+	Environment string   `json:"environment,omitempty"`
+	RunTags     []string `json:"run_tags,omitempty"`
+
+	// 抓取数据的后处理：回放成功后对抓取数据变形，结果替换 extractedData 后再保存执行记录和推送通知
+	Transform *DataTransform `json:"transform,omitempty"`
+	// 抓取数据（后处理之后）应符合的 JSON Schema，不符合时在执行记录中标记并发送 schema.drift 通知
+	OutputSchema map[string]interface{} `json:"output_schema,omitempty"`
+}
+
+// 参数表单的输入类型
+const (
+	ParamInputText     = "text"     // 单行文本（默认）
+	ParamInputTextarea = "textarea" // 多行文本
+	ParamInputPassword = "password" // 密码，输入内容不显示
+	ParamInputNumber   = "number"   // 数字
+	ParamInputEmail    = "email"    // 邮箱地址
+	ParamInputURL      = "url"      // URL
+	ParamInputDate     = "date"     // 日期（YYYY-MM-DD）
+	ParamInputSelect   = "select"   // 从 Options 中选择
+	ParamInputCheckbox = "checkbox" // 勾选框，值为 true 或 false
+)
+
+// ParamUIField 参数表单中单个参数的展示和校验设置
+type ParamUIField struct {
+	Label       string   `json:"label,omitempty"`       // 显示名称，默认为参数名
+	Placeholder string   `json:"placeholder,omitempty"` // 输入框占位提示
+	Help        string   `json:"help,omitempty"`        // 输入框下方的说明
+	InputType   string   `json:"input_type,omitempty"`  // 输入类型，见 ParamInput* 常量
+	Options     []string `json:"options,omitempty"`     // select 的可选值
+	Pattern     string   `json:"pattern,omitempty"`     // 校验正则，需要完整匹配；参数为空时不校验
+	// 不满足 Pattern 时的提示
+	PatternMessage string `json:"pattern_message,omitempty"`
+}
+
+// PerformanceOptions 回放时的性能采集选项
+type PerformanceOptions struct {
+	WebVitals     bool    `json:"web_vitals"`               // 采集 Core Web Vitals（LCP、CLS、INP、FCP、TTFB）
+	Trace         bool    `json:"trace"`                    // 录制 Chrome 性能 trace（可在 DevTools Performance 面板中打开）
+	CPUThrottling float64 `json:"cpu_throttling,omitempty"` // CPU 降速倍数（如 4 表示 4 倍降速，模拟低端设备），小于等于 1 表示不降速
+}
+
+// Enabled 是否需要采集性能数据
+func (o *PerformanceOptions) Enabled() bool {
+	return o != nil && (o.WebVitals || o.Trace || o.CPUThrottling > 1)
+}
+
+// ScriptTimeouts 脚本回放的超时设置（秒），不大于 0 的项使用配置文件中的默认值
+type ScriptTimeouts struct {
+	ElementSeconds    int `json:"element_seconds,omitempty"`    // 每个步骤等待元素出现的超时
+	NavigationSeconds int `json:"navigation_seconds,omitempty"` // 打开起始 URL 和 navigate 步骤的超时
+}
+
+// 抓取数据后处理的类型
+const (
+	TransformTemplate = "template" // Go 模板，输出需为 JSON
+	TransformJMESPath = "jmespath" // JMESPath 表达式
+	TransformJS       = "js"       // JavaScript 函数体，通过 data 访问抓取数据，return 新的数据；在隔离的空白页面中执行
+)
+
+// DataTransform 抓取数据的后处理设置
+type DataTransform struct {
+	Type       string `json:"type"`       // 见 Transform* 常量
+	Expression string `json:"expression"` // 模板、表达式或 JavaScript 代码，为空时不处理
+}
+
+// Enabled 是否需要处理抓取数据
+func (t *DataTransform) Enabled() bool {
+	return t != nil && t.Expression != ""
 }
 
 func (s *Script) GetActionsWithoutSemanticInfo() []ScriptAction {
@@ -220,6 +372,22 @@ func (s *Script) Copy() *Script {
 		variables[k] = v
 	}
 
+	var paramUISchema map[string]ParamUIField
+	if s.ParamUISchema != nil {
+		paramUISchema = make(map[string]ParamUIField, len(s.ParamUISchema))
+		for k, v := range s.ParamUISchema {
+			paramUISchema[k] = v
+		}
+	}
+
+	var headers map[string]string
+	if s.Headers != nil {
+		headers = make(map[string]string, len(s.Headers))
+		for k, v := range s.Headers {
+			headers[k] = v
+		}
+	}
+
 	return &Script{
 		ID:                    s.ID,
 		Name:                  s.Name,
@@ -233,12 +401,22 @@ func (s *Script) Copy() *Script {
 		Duration:              s.Duration,
 		CanPublish:            s.CanPublish,
 		CanFetch:              s.CanFetch,
+		Incognito:             s.Incognito,
 		DownloadedFiles:       downloadedFiles,
 		IsMCPCommand:          s.IsMCPCommand,
 		MCPCommandName:        s.MCPCommandName,
 		MCPCommandDescription: s.MCPCommandDescription,
 		MCPInputSchema:        s.MCPInputSchema,
 		Variables:             variables,
+		ParamUISchema:         paramUISchema,
+		Headers:               headers,
+		UserAgent:             s.UserAgent,
+		Performance:           s.Performance,
+		Timeouts:              s.Timeouts,
+		Environment:           s.Environment,
+		RunTags:               append([]string(nil), s.RunTags...),
+		Transform:             s.Transform,
+		OutputSchema:          s.OutputSchema,
 	}
 }
 
@@ -248,4 +426,10 @@ type PlayResult struct {
 	Message       string                 `json:"message"`        // 结果消息
 	ExtractedData map[string]interface{} `json:"extracted_data"` // 抓取到的数据，key 为变量名或 action 索引
 	Errors        []string               `json:"errors"`         // 错误信息列表
+
+	Performance *PerformanceMetrics `json:"performance,omitempty"` // 性能数据（脚本开启性能采集时）
+	// 抓取数据不符合脚本 output_schema 时的校验错误
+	SchemaError string `json:"schema_error,omitempty"`
+	// 与该脚本最近的成功执行相比，抓取结果的异常（如条目数远低于平常）
+	Anomalies []string `json:"anomalies,omitempty"`
 }
@@ -17,17 +17,68 @@ type ScriptExecution struct {
 	Success     bool      `json:"success"`      // 是否成功
 	Message     string    `json:"message"`      // 执行消息
 	ErrorMsg    string    `json:"error_msg"`    // 错误信息
+	ErrorCode   ErrorCode `json:"error_code,omitempty"` // 失败原因分类码
 	
 	// 步骤统计
 	TotalSteps   int `json:"total_steps"`   // 总步骤数
 	SuccessSteps int `json:"success_steps"` // 成功步骤数
 	FailedSteps  int `json:"failed_steps"`  // 失败步骤数
+
+	// 各步骤的结果和耗时（按执行顺序，提前停止时不包含未执行的步骤）
+	Steps []StepOutcome `json:"steps,omitempty"`
 	
 	// 抓取数据
 	ExtractedData map[string]interface{} `json:"extracted_data,omitempty"` // 抓取到的数据
 	
 	// 录制视频
 	VideoPath string `json:"video_path,omitempty"` // 录制视频路径
+
+	// 性能数据（脚本开启性能采集时）
+	Performance *PerformanceMetrics `json:"performance,omitempty"`
+
+	// 执行失败且配置保留时的临时工作目录（下载的上传文件、录屏帧等中间文件）
+	WorkDir string `json:"work_dir,omitempty"`
+
+	// 抓取数据不符合脚本 output_schema 时的校验错误（如缺少字段、类型错误），执行仍记为成功
+	SchemaError string `json:"schema_error,omitempty"`
+
+	// 抓取指标，及与该脚本最近的成功执行相比发现的异常（执行成功但结果可疑，如条目数远低于平常）
+	Metrics   *ExtractionMetrics `json:"metrics,omitempty"`
+	Anomalies []string           `json:"anomalies,omitempty"`
 	
 	CreatedAt time.Time `json:"created_at"` // 记录创建时间
 }
+
+// 步骤结果
+const (
+	StepStatusSuccess = "success"
+	StepStatusFailed  = "failed"
+	StepStatusSkipped = "skipped" // 被步骤开关或执行条件跳过
+)
+
+// StepOutcome 执行中单个步骤的结果
+type StepOutcome struct {
+	Index    int    `json:"index"` // 步骤序号，从 1 开始
+	Type     string `json:"type"`
+	Status   string `json:"status"` // 见 StepStatus* 常量
+	Error    string `json:"error,omitempty"`
+	Duration int64  `json:"duration"` // 耗时（毫秒）
+}
+
+// PerformanceMetrics 回放过程中采集的性能数据
+type PerformanceMetrics struct {
+	CPUThrottling float64      `json:"cpu_throttling,omitempty"` // 使用的 CPU 降速倍数
+	Pages         []PageVitals `json:"pages,omitempty"`          // 各页面的 Core Web Vitals（按访问顺序）
+	TracePath     string       `json:"trace_path,omitempty"`     // 性能 trace 文件路径
+	Errors        []string     `json:"errors,omitempty"`         // 采集过程中的错误
+}
+
+// PageVitals 单个页面的 Core Web Vitals（时间单位为毫秒，未采集到的指标为空）
+type PageVitals struct {
+	URL  string   `json:"url"`
+	LCP  *float64 `json:"lcp,omitempty"`  // Largest Contentful Paint
+	CLS  *float64 `json:"cls,omitempty"`  // Cumulative Layout Shift（无单位）
+	INP  *float64 `json:"inp,omitempty"`  // Interaction to Next Paint（取最慢的一次交互，没有交互时为空）
+	FCP  *float64 `json:"fcp,omitempty"`  // First Contentful Paint
+	TTFB *float64 `json:"ttfb,omitempty"` // Time to First Byte
+}
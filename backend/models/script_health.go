@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// 选择器检查结果
+const (
+	SelectorFound      = "found"      // 页面上能找到元素
+	SelectorMissing    = "missing"    // 页面加载后就应存在，但找不到元素
+	SelectorUnverified = "unverified" // 找不到，但元素可能要在前面的步骤操作页面后才出现（或步骤有执行条件），无法判断
+)
+
+// SelectorCheck 脚本中一个步骤的元素定位器检查结果
+type SelectorCheck struct {
+	Step     int    `json:"step"`               // 步骤序号（从 1 开始，展开子脚本后的序号）
+	Type     string `json:"type"`               // 步骤类型
+	Target   string `json:"target,omitempty"`   // hover_then_click 的目标元素为 "target"，其他为空
+	Selector string `json:"selector,omitempty"` // CSS 选择器
+	XPath    string `json:"xpath,omitempty"`    // XPath
+	PageURL  string `json:"page_url"`           // 检查时打开的页面
+	Status   string `json:"status"`             // found、missing 或 unverified
+	Reason   string `json:"reason,omitempty"`   // 未检查或无法判断的原因
+}
+
+// ScriptHealthReport 脚本的选择器健康报告：打开脚本的起始 URL（及 navigate 步骤的 URL），检查录制的定位器是否仍能找到元素
+// 每个脚本只保留最近一次的报告
+type ScriptHealthReport struct {
+	ScriptID   string          `json:"script_id"`
+	ScriptName string          `json:"script_name"`
+	TaskID     string          `json:"task_id,omitempty"` // 由定时任务检查时的任务 ID
+	Healthy    bool            `json:"healthy"`           // 没有 missing 的选择器，且页面都能正常打开
+	Found      int             `json:"found"`
+	Missing    int             `json:"missing"`
+	Unverified int             `json:"unverified"`
+	Errors     []string        `json:"errors,omitempty"` // 页面打开失败、被拦截或要求登录等
+	Checks     []SelectorCheck `json:"checks"`
+	CheckedAt  time.Time       `json:"checked_at"`
+	Duration   int64           `json:"duration"` // 检查耗时（毫秒）
+}
+
+// Tally 统计各状态的数量并计算 Healthy
+func (r *ScriptHealthReport) Tally() {
+	r.Found, r.Missing, r.Unverified = 0, 0, 0
+	for _, check := range r.Checks {
+		switch check.Status {
+		case SelectorFound:
+			r.Found++
+		case SelectorMissing:
+			r.Missing++
+		default:
+			r.Unverified++
+		}
+	}
+	r.Healthy = r.Missing == 0 && len(r.Errors) == 0
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// ScriptState 脚本的增量抓取状态，在成功的执行之间保留：
+// 已输出过的条目键（抓取步骤设置 dedup 时跳过这些条目）和游标（步骤设置 update_cursor 时更新，通过 ${state.cursor} 引用）
+type ScriptState struct {
+	ScriptID  string               `json:"script_id"`
+	SeenKeys  map[string]time.Time `json:"seen_keys,omitempty"`   // 已输出过的条目键及首次输出时间
+	Cursor    string               `json:"cursor,omitempty"`      // 游标，如最后一条的 ID 或时间戳
+	LastRunAt *time.Time           `json:"last_run_at,omitempty"` // 上次更新状态的执行的开始时间，通过 ${state.last_run_at} 引用
+	UpdatedAt time.Time            `json:"updated_at"`
+}
+
+// ScriptStateSummary 查询接口返回的状态摘要，不包含条目键列表
+type ScriptStateSummary struct {
+	ScriptID  string     `json:"script_id"`
+	SeenCount int        `json:"seen_count"` // 已输出过的条目数
+	Cursor    string     `json:"cursor"`
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"` // 从未保存过状态时为空
+}
+
+// Summary 返回状态摘要
+func (s *ScriptState) Summary() ScriptStateSummary {
+	summary := ScriptStateSummary{
+		ScriptID:  s.ScriptID,
+		SeenCount: len(s.SeenKeys),
+		Cursor:    s.Cursor,
+		LastRunAt: s.LastRunAt,
+	}
+	if !s.UpdatedAt.IsZero() {
+		updatedAt := s.UpdatedAt
+		summary.UpdatedAt = &updatedAt
+	}
+	return summary
+}
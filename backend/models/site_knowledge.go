@@ -0,0 +1,65 @@
+package models
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SiteKnowledge 站点知识包：某些网站的登录选择器、已知问题、访问频率限制等提示
+// Agent 打开匹配域名的页面时，把内容注入到 Agent 的上下文中
+type SiteKnowledge struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Domains   []string  `json:"domains"` // 匹配的域名，同时匹配其子域名，如 example.com 匹配 www.example.com
+	Content   string    `json:"content"` // 提示内容（Markdown）
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// 登录脚本：回放或 Agent 运行中在匹配的页面检测到已退出登录时执行，登录后回到原来的页面继续
+	LoginScriptID string `json:"login_script_id,omitempty"`
+}
+
+// NormalizeDomain 把用户输入的域名或 URL 规范为小写主机名，去掉协议、端口、路径和开头的 *. 或 .
+func NormalizeDomain(domain string) string {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	if strings.Contains(domain, "://") {
+		if u, err := url.Parse(domain); err == nil {
+			domain = u.Hostname()
+		}
+	}
+	if i := strings.IndexAny(domain, "/:"); i >= 0 {
+		domain = domain[:i]
+	}
+	domain = strings.TrimPrefix(domain, "*.")
+	return strings.Trim(domain, ".")
+}
+
+// LoginScriptFor 返回页面 URL 匹配的已启用知识包中指定的登录脚本 ID，没有时返回空
+func LoginScriptFor(packs []*SiteKnowledge, pageURL string) string {
+	for _, k := range packs {
+		if k.Enabled && k.LoginScriptID != "" && k.MatchesURL(pageURL) {
+			return k.LoginScriptID
+		}
+	}
+	return ""
+}
+
+// MatchesURL 页面 URL 的主机名是否为知识包的域名或其子域名
+func (k *SiteKnowledge) MatchesURL(pageURL string) bool {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return false
+	}
+	for _, domain := range k.Domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package models
+
+import "testing"
+
+func TestSiteKnowledgeMatchesURL(t *testing.T) {
+	for in, want := range map[string]string{
+		"Example.com":                     "example.com",
+		"*.example.com":                   "example.com",
+		"https://shop.example.com:8443/a": "shop.example.com",
+		"example.com/login":               "example.com",
+		" .example.com. ":                 "example.com",
+	} {
+		if got := NormalizeDomain(in); got != want {
+			t.Errorf("NormalizeDomain(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	k := &SiteKnowledge{Domains: []string{"example.com"}}
+	for pageURL, want := range map[string]bool{
+		"https://example.com/login":    true,
+		"https://www.Example.com/":     true,
+		"http://example.com:8080/cart": true,
+		"https://notexample.com/":      false,
+		"https://example.com.evil.io/": false,
+		"about:blank":                  false,
+	} {
+		if got := k.MatchesURL(pageURL); got != want {
+			t.Errorf("MatchesURL(%q) = %v, want %v", pageURL, got, want)
+		}
+	}
+}
+
+func TestLoginScriptFor(t *testing.T) {
+	packs := []*SiteKnowledge{
+		{Domains: []string{"shop.example.com"}, Enabled: true},
+		{Domains: []string{"example.com"}, LoginScriptID: "off"},
+		{Domains: []string{"example.com"}, Enabled: true, LoginScriptID: "login"},
+	}
+	if got := LoginScriptFor(packs, "https://shop.example.com/login"); got != "login" {
+		t.Errorf("expected the enabled pack with a login script, got %q", got)
+	}
+	if got := LoginScriptFor(packs, "https://other.io/login"); got != "" {
+		t.Errorf("expected no login script for other sites, got %q", got)
+	}
+}
@@ -0,0 +1,12 @@
+package models
+
+// ScriptTemplate 内置的参数化脚本模板，安装后成为普通脚本
+// 模板参数为脚本中的 ${变量名} 占位符，参数说明写在 Script.MCPInputSchema 中，默认值写在 Script.Variables 中
+type ScriptTemplate struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Category    string   `json:"category"` // search、login、crawl、form
+	Tags        []string `json:"tags,omitempty"`
+	Script      Script   `json:"script"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// UILocale 注入页面的界面（录制器、浮动按钮、回放指示器）的自定义语言或文本覆盖
+// 语言与内置语言相同时覆盖内置文本；其他语言中未翻译的文本使用 Base 语言的内置文本
+type UILocale struct {
+	Language    string            `json:"language"`               // 语言代码，如 fr、de、pt-BR
+	Name        string            `json:"name,omitempty"`         // 显示名称，如 Français
+	Base        string            `json:"base,omitempty"`         // 未翻译文本使用的内置语言，默认 en
+	Recorder    map[string]string `json:"recorder,omitempty"`     // 录制器文本
+	FloatButton map[string]string `json:"float_button,omitempty"` // 浮动录制按钮文本
+	Player      map[string]string `json:"player,omitempty"`       // 回放指示器文本
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// UploadHandlePrefix 上传文件句柄的前缀，脚本 upload_file 步骤的 file_paths 中用 "upload:<id>" 引用已上传的文件
+const UploadHandlePrefix = "upload:"
+
+// UploadedFile 通过上传接口保存到受管存储区的文件，远程触发执行的调用方无需事先把文件放到服务器上
+type UploadedFile struct {
+	ID        string    `json:"id"`
+	Handle    string    `json:"handle"` // 在 file_paths 中引用文件的句柄，即 "upload:<id>"
+	Name      string    `json:"name"`   // 原始文件名
+	Size      int64     `json:"size"`   // 文件大小（字节）
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -2,6 +2,8 @@ package models
 
 import (
 	"time"
+
+	"github.com/browserwing/browserwing/pkg/urlpolicy"
 )
 
 // User 用户模型
@@ -22,6 +24,9 @@ type ApiKey struct {
 	UserID      string    `json:"user_id"`     // 所属用户ID
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+
+	// 访问策略：限制使用该密钥的客户端（MCP/Agent/HTTP）可以访问的域名
+	URLPolicy *urlpolicy.Policy `json:"url_policy,omitempty"`
 }
 
 // LoginRequest 登录请求
@@ -50,6 +55,7 @@ type UpdatePasswordRequest struct {
 
 // CreateApiKeyRequest 创建API密钥请求
 type CreateApiKeyRequest struct {
-	Name        string `json:"name" binding:"required"`
-	Description string `json:"description"`
+	Name        string            `json:"name" binding:"required"`
+	Description string            `json:"description"`
+	URLPolicy   *urlpolicy.Policy `json:"url_policy,omitempty"` // 可选的域名访问策略
 }
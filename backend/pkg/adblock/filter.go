@@ -0,0 +1,401 @@
+// Package adblock 实现 Adblock Plus 过滤规则（EasyList、EasyPrivacy 等公开列表使用的语法）中网络请求规则的子集
+//
+// 支持的语法：
+//   - ||example.com^ 域名锚定、|http:// 起始锚定、结尾 | 锚定、* 通配符、^ 分隔符
+//   - @@ 例外规则
+//   - 选项 third-party / ~third-party、domain=a.com|~b.com 以及资源类型（script、image 等，支持 ~ 取反）
+//
+// 元素隐藏规则（##、#@# 等）、正则规则（/.../）以及包含未知选项的规则会被忽略，
+// 宁可少拦截也不误拦截
+package adblock
+
+import (
+	"bufio"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// Request 待匹配的请求
+type Request struct {
+	URL          string // 请求地址
+	DocumentURL  string // 发起请求的页面地址（用于 third-party 和 domain= 选项），未知时为空
+	ResourceType string // 资源类型：script、image、stylesheet、xmlhttprequest、subdocument、media、font、ping、websocket、other
+}
+
+// resourceTypes 支持的资源类型选项
+var resourceTypes = map[string]bool{
+	"script":         true,
+	"image":          true,
+	"stylesheet":     true,
+	"xmlhttprequest": true,
+	"subdocument":    true,
+	"media":          true,
+	"font":           true,
+	"ping":           true,
+	"websocket":      true,
+	"other":          true,
+}
+
+// rule 一条网络请求规则
+type rule struct {
+	pattern     string // 去除锚定符后的匹配模式
+	hostAnchor  bool   // ||：从域名边界开始匹配
+	startAnchor bool   // |：从地址开头匹配
+	endAnchor   bool   // 结尾 |：匹配到地址末尾
+
+	thirdParty   int             // 1: 仅第三方请求，-1: 仅同站请求，0: 不限
+	types        map[string]bool // 仅匹配这些资源类型，为空表示不限
+	excludeTypes map[string]bool // 不匹配这些资源类型
+	domains      []string        // 仅在这些站点的页面上生效
+	excludeDoms  []string        // 在这些站点的页面上不生效
+}
+
+// Matcher 过滤规则集合
+// 形如 ||example.com^ 的简单域名规则按域名建立索引，其余规则逐条匹配
+type Matcher struct {
+	blockHosts map[string][]*rule
+	allowHosts map[string][]*rule
+	blockRules []*rule
+	allowRules []*rule
+	ruleCount  int
+}
+
+// NewMatcher 创建空的规则集合
+func NewMatcher() *Matcher {
+	return &Matcher{
+		blockHosts: make(map[string][]*rule),
+		allowHosts: make(map[string][]*rule),
+	}
+}
+
+// Len 返回已加载的规则数量
+func (m *Matcher) Len() int {
+	if m == nil {
+		return 0
+	}
+	return m.ruleCount
+}
+
+// AddRules 从过滤列表中读取规则，返回成功加载的规则数量
+func (m *Matcher) AddRules(r io.Reader) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	added := 0
+	for scanner.Scan() {
+		if m.AddRule(scanner.Text()) {
+			added++
+		}
+	}
+	return added, scanner.Err()
+}
+
+// AddRule 添加一条规则，不支持或无效的规则返回 false
+func (m *Matcher) AddRule(line string) bool {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "!") || strings.HasPrefix(line, "[") {
+		return false
+	}
+	// 元素隐藏规则
+	if strings.Contains(line, "##") || strings.Contains(line, "#@#") || strings.Contains(line, "#?#") || strings.Contains(line, "#$#") {
+		return false
+	}
+
+	exception := strings.HasPrefix(line, "@@")
+	if exception {
+		line = line[2:]
+	}
+
+	r := &rule{}
+	if i := strings.LastIndex(line, "$"); i >= 0 {
+		if !r.parseOptions(line[i+1:]) {
+			return false
+		}
+		line = line[:i]
+	}
+
+	// 正则规则
+	if strings.HasPrefix(line, "/") && strings.HasSuffix(line, "/") && len(line) > 1 {
+		return false
+	}
+
+	switch {
+	case strings.HasPrefix(line, "||"):
+		r.hostAnchor = true
+		line = line[2:]
+	case strings.HasPrefix(line, "|"):
+		r.startAnchor = true
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "|") {
+		r.endAnchor = true
+		line = line[:len(line)-1]
+	}
+	line = strings.ToLower(line)
+	if strings.Trim(line, "*^") == "" {
+		// 匹配所有请求的规则过于宽泛，忽略
+		return false
+	}
+	r.pattern = line
+
+	if host, ok := r.simpleHost(); ok {
+		if exception {
+			m.allowHosts[host] = append(m.allowHosts[host], r)
+		} else {
+			m.blockHosts[host] = append(m.blockHosts[host], r)
+		}
+	} else if exception {
+		m.allowRules = append(m.allowRules, r)
+	} else {
+		m.blockRules = append(m.blockRules, r)
+	}
+	m.ruleCount++
+	return true
+}
+
+// parseOptions 解析 $ 之后的选项，包含不支持的选项时返回 false
+func (r *rule) parseOptions(options string) bool {
+	for _, opt := range strings.Split(strings.ToLower(options), ",") {
+		opt = strings.TrimSpace(opt)
+		negated := strings.HasPrefix(opt, "~")
+		name := strings.TrimPrefix(opt, "~")
+
+		switch {
+		case name == "third-party" || name == "3p":
+			r.thirdParty = 1
+			if negated {
+				r.thirdParty = -1
+			}
+		case name == "first-party" || name == "1p":
+			r.thirdParty = -1
+			if negated {
+				r.thirdParty = 1
+			}
+		case strings.HasPrefix(opt, "domain="):
+			for _, d := range strings.Split(strings.TrimPrefix(opt, "domain="), "|") {
+				if strings.HasPrefix(d, "~") {
+					r.excludeDoms = append(r.excludeDoms, d[1:])
+				} else if d != "" {
+					r.domains = append(r.domains, d)
+				}
+			}
+		case resourceTypes[name]:
+			if negated {
+				if r.excludeTypes == nil {
+					r.excludeTypes = make(map[string]bool)
+				}
+				r.excludeTypes[name] = true
+			} else {
+				if r.types == nil {
+					r.types = make(map[string]bool)
+				}
+				r.types[name] = true
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// simpleHost 判断规则是否为 ||host^ 形式的简单域名规则，是则返回域名
+func (r *rule) simpleHost() (string, bool) {
+	if !r.hostAnchor || r.endAnchor {
+		return "", false
+	}
+	host := strings.TrimSuffix(r.pattern, "^")
+	if host == "" || strings.ContainsAny(host, "*^/|:?=&") {
+		return "", false
+	}
+	return host, true
+}
+
+// Match 判断请求是否应被拦截（命中拦截规则且未命中例外规则）
+func (m *Matcher) Match(req Request) bool {
+	if m == nil || m.ruleCount == 0 {
+		return false
+	}
+	u, err := url.Parse(req.URL)
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+	ctx := newMatchContext(req, u)
+	if !m.matches(ctx, m.blockHosts, m.blockRules) {
+		return false
+	}
+	return !m.matches(ctx, m.allowHosts, m.allowRules)
+}
+
+func (m *Matcher) matches(ctx *matchContext, hosts map[string][]*rule, rules []*rule) bool {
+	for _, host := range ctx.hostSuffixes {
+		for _, r := range hosts[host] {
+			if r.matchOptions(ctx) {
+				return true
+			}
+		}
+	}
+	for _, r := range rules {
+		if r.matchOptions(ctx) && r.matchURL(ctx) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchContext 单次匹配中复用的请求信息
+type matchContext struct {
+	url          string   // 小写的完整地址
+	host         string   // 请求域名
+	hostStart    int      // 域名在地址中的起始位置
+	hostSuffixes []string // 域名及其各级父域名
+	docHost      string   // 页面域名
+	resourceType string
+}
+
+func newMatchContext(req Request, u *url.URL) *matchContext {
+	ctx := &matchContext{
+		url:          strings.ToLower(req.URL),
+		host:         strings.ToLower(u.Hostname()),
+		resourceType: req.ResourceType,
+	}
+	ctx.hostStart = strings.Index(ctx.url, ctx.host)
+	for h := ctx.host; h != ""; {
+		ctx.hostSuffixes = append(ctx.hostSuffixes, h)
+		i := strings.IndexByte(h, '.')
+		if i < 0 {
+			break
+		}
+		h = h[i+1:]
+	}
+	if req.DocumentURL != "" {
+		if du, err := url.Parse(req.DocumentURL); err == nil {
+			ctx.docHost = strings.ToLower(du.Hostname())
+		}
+	}
+	return ctx
+}
+
+// matchOptions 检查规则选项
+func (r *rule) matchOptions(ctx *matchContext) bool {
+	if len(r.types) > 0 && !r.types[ctx.resourceType] {
+		return false
+	}
+	if r.excludeTypes[ctx.resourceType] {
+		return false
+	}
+	if r.thirdParty != 0 {
+		// 页面未知时无法判断是否为第三方请求，不应用该规则
+		if ctx.docHost == "" {
+			return false
+		}
+		third := siteOf(ctx.host) != siteOf(ctx.docHost)
+		if (r.thirdParty == 1) != third {
+			return false
+		}
+	}
+	if len(r.domains) > 0 || len(r.excludeDoms) > 0 {
+		if ctx.docHost == "" {
+			return len(r.domains) == 0
+		}
+		for _, d := range r.excludeDoms {
+			if isSubdomain(ctx.docHost, d) {
+				return false
+			}
+		}
+		if len(r.domains) > 0 {
+			for _, d := range r.domains {
+				if isSubdomain(ctx.docHost, d) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return true
+}
+
+// matchURL 检查地址是否匹配规则模式
+func (r *rule) matchURL(ctx *matchContext) bool {
+	switch {
+	case r.hostAnchor:
+		if ctx.hostStart < 0 {
+			return false
+		}
+		// 从域名及每一级父域名的起始位置尝试匹配
+		for i := ctx.hostStart; i < ctx.hostStart+len(ctx.host); i++ {
+			if i != ctx.hostStart && ctx.url[i-1] != '.' {
+				continue
+			}
+			if globMatch(r.pattern, ctx.url[i:], r.endAnchor) {
+				return true
+			}
+		}
+		return false
+	case r.startAnchor:
+		return globMatch(r.pattern, ctx.url, r.endAnchor)
+	default:
+		for i := 0; i < len(ctx.url); i++ {
+			if globMatch(r.pattern, ctx.url[i:], r.endAnchor) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// globMatch 从 s 的开头匹配模式：* 匹配任意字符串，^ 匹配分隔符或地址末尾
+// full 为 true 时要求匹配到 s 的末尾，否则只需匹配 s 的前缀
+func globMatch(pattern, s string, full bool) bool {
+	p, i := 0, 0
+	starP, starI := -1, 0
+	for {
+		if p == len(pattern) {
+			if !full || i == len(s) {
+				return true
+			}
+		} else if pattern[p] == '*' {
+			starP, starI = p, i
+			p++
+			continue
+		} else if pattern[p] == '^' && i == len(s) {
+			p++
+			continue
+		} else if i < len(s) && (pattern[p] == s[i] || (pattern[p] == '^' && isSeparator(s[i]))) {
+			p++
+			i++
+			continue
+		}
+		// 回溯到上一个 *
+		if starP < 0 || starI >= len(s) {
+			return false
+		}
+		starI++
+		p, i = starP+1, starI
+	}
+}
+
+// isSeparator 判断字符是否为 ^ 可以匹配的分隔符（字母、数字以及 _ - . % 以外的字符）
+func isSeparator(c byte) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return false
+	case c == '_', c == '-', c == '.', c == '%':
+		return false
+	}
+	return true
+}
+
+// isSubdomain 判断 host 是否为 domain 或其子域名
+func isSubdomain(host, domain string) bool {
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// siteOf 返回域名的站点部分（最后两级），用于判断是否为第三方请求
+// 未使用公共后缀列表，对 co.uk 这类多级后缀只是近似判断
+func siteOf(host string) string {
+	parts := strings.Split(host, ".")
+	if len(parts) <= 2 {
+		return host
+	}
+	return strings.Join(parts[len(parts)-2:], ".")
+}
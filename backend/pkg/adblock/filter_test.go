@@ -0,0 +1,78 @@
+package adblock
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatcher(t *testing.T) {
+	m := NewMatcher()
+	list := `[Adblock Plus 2.0]
+! comment
+||ads.example.com^
+||tracker.net^$third-party
+/banner/*/ad_
+|https://exact.org/pixel.gif|
+||cdn.example.org/ads/$script
+@@||ads.example.com/allowed^
+example.com##.ad-slot
+||weird.com^$popup
+||scoped.com^$domain=news.com|~sub.news.com
+`
+	added, err := m.AddRules(strings.NewReader(list))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if added != 7 {
+		t.Errorf("added = %d, want 7", added)
+	}
+
+	cases := []struct {
+		name string
+		req  Request
+		want bool
+	}{
+		{"host rule", Request{URL: "https://ads.example.com/x.js"}, true},
+		{"host rule subdomain", Request{URL: "https://a.ads.example.com/x.js"}, true},
+		{"host rule other domain", Request{URL: "https://notads.example.com/x.js"}, false},
+		{"exception", Request{URL: "https://ads.example.com/allowed/x.js"}, false},
+		{"third-party", Request{URL: "https://tracker.net/t.js", DocumentURL: "https://shop.com/"}, true},
+		{"first-party", Request{URL: "https://tracker.net/t.js", DocumentURL: "https://www.tracker.net/"}, false},
+		{"unknown document", Request{URL: "https://tracker.net/t.js"}, false},
+		{"wildcard", Request{URL: "https://site.com/banner/123/ad_top.png"}, true},
+		{"start and end anchor", Request{URL: "https://exact.org/pixel.gif"}, true},
+		{"end anchor mismatch", Request{URL: "https://exact.org/pixel.gif?x=1"}, false},
+		{"type match", Request{URL: "https://cdn.example.org/ads/a.js", ResourceType: "script"}, true},
+		{"type mismatch", Request{URL: "https://cdn.example.org/ads/a.png", ResourceType: "image"}, false},
+		{"unsupported option ignored", Request{URL: "https://weird.com/"}, false},
+		{"domain option", Request{URL: "https://scoped.com/a", DocumentURL: "https://www.news.com/"}, true},
+		{"excluded domain", Request{URL: "https://scoped.com/a", DocumentURL: "https://sub.news.com/"}, false},
+		{"other domain", Request{URL: "https://scoped.com/a", DocumentURL: "https://blog.com/"}, false},
+	}
+	for _, tc := range cases {
+		if got := m.Match(tc.req); got != tc.want {
+			t.Errorf("%s: Match(%s) = %v, want %v", tc.name, tc.req.URL, got, tc.want)
+		}
+	}
+}
+
+func TestGlobMatch(t *testing.T) {
+	cases := []struct {
+		pattern, s string
+		full       bool
+		want       bool
+	}{
+		{"ads.com^", "ads.com/x", false, true},
+		{"ads.com^", "ads.com", false, true},
+		{"ads.com^", "ads.community", false, false},
+		{"a*c", "abbbc", true, true},
+		{"a*c", "abbbcd", true, false},
+		{"a*c", "abbbcd", false, true},
+		{"*^ad^", "x.com/ad?", false, true},
+	}
+	for _, tc := range cases {
+		if got := globMatch(tc.pattern, tc.s, tc.full); got != tc.want {
+			t.Errorf("globMatch(%q, %q, %v) = %v, want %v", tc.pattern, tc.s, tc.full, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package adblock
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxListSize 单个过滤列表的最大大小
+const maxListSize = 20 << 20
+
+// DefaultLists 默认使用的公开过滤列表
+var DefaultLists = []string{
+	"https://easylist.to/easylist/easylist.txt",
+	"https://easylist.to/easylist/easyprivacy.txt",
+}
+
+// Loader 过滤列表加载器
+// 远程列表缓存在 CacheDir 中，缓存未过期时不重新下载；下载失败时使用过期的缓存
+type Loader struct {
+	CacheDir string        // 缓存目录
+	MaxAge   time.Duration // 缓存有效期
+	Client   *http.Client  // 下载使用的 HTTP 客户端，为空时使用 60 秒超时的默认客户端
+}
+
+// Load 加载所有过滤列表（远程 URL 或本地文件路径）并合并为一个规则集合
+// 单个列表加载失败不影响其他列表，所有失败原因会合并返回
+func (l *Loader) Load(ctx context.Context, sources []string) (*Matcher, error) {
+	m := NewMatcher()
+	var errs []string
+	for _, source := range sources {
+		data, err := l.read(ctx, source)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", source, err))
+			continue
+		}
+		if _, err := m.AddRules(bytes.NewReader(data)); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", source, err))
+		}
+	}
+	if len(errs) > 0 {
+		return m, fmt.Errorf("failed to load filter lists: %s", strings.Join(errs, "; "))
+	}
+	return m, nil
+}
+
+// read 读取单个过滤列表
+func (l *Loader) read(ctx context.Context, source string) ([]byte, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		return readLimited(source)
+	}
+
+	cachePath := ""
+	if l.CacheDir != "" {
+		sum := sha1.Sum([]byte(source))
+		cachePath = filepath.Join(l.CacheDir, hex.EncodeToString(sum[:8])+".txt")
+		if info, err := os.Stat(cachePath); err == nil && time.Since(info.ModTime()) < l.MaxAge {
+			return readLimited(cachePath)
+		}
+	}
+
+	data, err := l.download(ctx, source)
+	if err != nil {
+		if cachePath != "" {
+			if cached, cacheErr := readLimited(cachePath); cacheErr == nil {
+				return cached, nil
+			}
+		}
+		return nil, err
+	}
+
+	if cachePath != "" {
+		if err := os.MkdirAll(l.CacheDir, 0o755); err == nil {
+			_ = os.WriteFile(cachePath, data, 0o644)
+		}
+	}
+	return data, nil
+}
+
+// download 下载远程过滤列表
+func (l *Loader) download(ctx context.Context, source string) ([]byte, error) {
+	client := l.Client
+	if client == nil {
+		client = &http.Client{Timeout: 60 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxListSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxListSize {
+		return nil, fmt.Errorf("filter list exceeds %d bytes", maxListSize)
+	}
+	return data, nil
+}
+
+// readLimited 读取本地文件，超过大小限制时返回错误
+func readLimited(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, maxListSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxListSize {
+		return nil, fmt.Errorf("filter list exceeds %d bytes", maxListSize)
+	}
+	return data, nil
+}
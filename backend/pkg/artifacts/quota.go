@@ -0,0 +1,142 @@
+package artifacts
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Usage 目录占用情况
+type Usage struct {
+	Dir        string `json:"dir"`
+	Bytes      int64  `json:"bytes"`
+	Files      int    `json:"files"`
+	QuotaBytes int64  `json:"quota_bytes"` // 0 表示不限制
+}
+
+// OverQuota 是否超出配额
+func (u *Usage) OverQuota() bool {
+	return u.QuotaBytes > 0 && u.Bytes > u.QuotaBytes
+}
+
+// Eviction 一次配额清理的结果
+type Eviction struct {
+	Removed    []string `json:"removed"`
+	FreedBytes int64    `json:"freed_bytes"`
+}
+
+type fileEntry struct {
+	path string
+	size int64
+	info fs.FileInfo
+}
+
+// listFiles 递归列出目录下的所有文件，目录不存在时返回空
+func listFiles(dir string) ([]fileEntry, error) {
+	files := make([]fileEntry, 0)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			// 文件可能在遍历过程中被删除
+			return nil
+		}
+		files = append(files, fileEntry{path: path, size: info.Size(), info: info})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", dir, err)
+	}
+	return files, nil
+}
+
+// DirUsage 统计目录（含子目录）中文件的总大小
+func DirUsage(dir string, quotaBytes int64) (*Usage, error) {
+	files, err := listFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	usage := &Usage{Dir: dir, Files: len(files), QuotaBytes: quotaBytes}
+	for _, f := range files {
+		usage.Bytes += f.size
+	}
+	return usage, nil
+}
+
+// EnforceQuota 目录超出配额时按修改时间从旧到新删除文件，直到总大小不超过配额
+// keep 中的文件（如正在写入的录像）不会被删除；清理后移除变空的子目录
+func EnforceQuota(dir string, quotaBytes int64, keep ...string) (*Eviction, error) {
+	eviction := &Eviction{Removed: make([]string, 0)}
+	if quotaBytes <= 0 {
+		return eviction, nil
+	}
+	files, err := listFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	for _, f := range files {
+		total += f.size
+	}
+	if total <= quotaBytes {
+		return eviction, nil
+	}
+
+	kept := make(map[string]bool, len(keep))
+	for _, k := range keep {
+		if abs, err := filepath.Abs(k); err == nil {
+			kept[abs] = true
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].info.ModTime().Before(files[j].info.ModTime())
+	})
+
+	dirs := make(map[string]bool)
+	for _, f := range files {
+		if total <= quotaBytes {
+			break
+		}
+		if abs, err := filepath.Abs(f.path); err == nil && kept[abs] {
+			continue
+		}
+		if err := os.Remove(f.path); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return eviction, fmt.Errorf("failed to remove %s: %w", f.path, err)
+		}
+		total -= f.size
+		eviction.FreedBytes += f.size
+		eviction.Removed = append(eviction.Removed, f.path)
+		dirs[filepath.Dir(f.path)] = true
+	}
+
+	removeEmptyDirs(dir, dirs)
+	return eviction, nil
+}
+
+// removeEmptyDirs 自下而上删除清理后变空的子目录（不删除根目录本身）
+func removeEmptyDirs(root string, dirs map[string]bool) {
+	root = filepath.Clean(root)
+	for dir := range dirs {
+		for d := filepath.Clean(dir); d != root && len(d) > len(root); d = filepath.Dir(d) {
+			// 目录非空时 Remove 会失败，停止向上清理
+			if err := os.Remove(d); err != nil {
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package artifacts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path string, size int, modTime time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, make([]byte, size), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEnforceQuota(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	oldest := filepath.Join(dir, "a", "2026-01-01", "old.gif")
+	middle := filepath.Join(dir, "middle.gif")
+	active := filepath.Join(dir, "active.gif")
+	newest := filepath.Join(dir, "new.gif")
+	writeFile(t, oldest, 400, now.Add(-3*time.Hour))
+	writeFile(t, active, 400, now.Add(-2*time.Hour))
+	writeFile(t, middle, 400, now.Add(-1*time.Hour))
+	writeFile(t, newest, 400, now)
+
+	eviction, err := EnforceQuota(dir, 1000, active)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(eviction.Removed) != 2 || eviction.FreedBytes != 800 {
+		t.Fatalf("unexpected eviction: %+v", eviction)
+	}
+	for _, path := range []string{oldest, middle} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s should be removed", path)
+		}
+	}
+	for _, path := range []string{active, newest} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s should be kept: %v", path, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a")); !os.IsNotExist(err) {
+		t.Errorf("empty subdirectories should be removed")
+	}
+
+	usage, err := DirUsage(dir, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.Bytes != 800 || usage.Files != 2 || usage.OverQuota() {
+		t.Errorf("unexpected usage: %+v", usage)
+	}
+}
+
+func TestDirUsageMissingDir(t *testing.T) {
+	usage, err := DirUsage(filepath.Join(t.TempDir(), "missing"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.Bytes != 0 || usage.Files != 0 {
+		t.Errorf("unexpected usage: %+v", usage)
+	}
+}
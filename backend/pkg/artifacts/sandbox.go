@@ -0,0 +1,155 @@
+package artifacts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Sandbox 限定产物（截图、下载文件等）只能写入指定根目录
+type Sandbox struct {
+	root string
+}
+
+// NewSandbox 创建以 root 为根目录的沙箱，root 会被转换为绝对路径
+func NewSandbox(root string) (*Sandbox, error) {
+	if strings.TrimSpace(root) == "" {
+		return nil, fmt.Errorf("artifact root directory is required")
+	}
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve artifact root %q: %w", root, err)
+	}
+	return &Sandbox{root: filepath.Clean(abs)}, nil
+}
+
+// Root 返回沙箱根目录（绝对路径）
+func (s *Sandbox) Root() string {
+	return s.root
+}
+
+// Resolve 将相对路径拼接到根目录下，拒绝绝对路径以及通过 ".." 跳出根目录的路径
+func (s *Sandbox) Resolve(elem ...string) (string, error) {
+	rel := filepath.Join(elem...)
+	if rel == "" {
+		return s.root, nil
+	}
+	if filepath.IsAbs(rel) || filepath.VolumeName(rel) != "" || strings.HasPrefix(rel, `\`) {
+		return "", fmt.Errorf("absolute path %q is not allowed", rel)
+	}
+	full := filepath.Join(s.root, rel)
+	within, err := filepath.Rel(s.root, full)
+	if err != nil || within == ".." || strings.HasPrefix(within, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q escapes artifact directory", rel)
+	}
+	return full, nil
+}
+
+// Contains 解析沙箱内已存在的文件：相对路径按根目录解析，绝对路径必须位于根目录下
+// 会解析符号链接，防止通过链接读取沙箱外的文件
+func (s *Sandbox) Contains(path string) (string, error) {
+	full := filepath.Clean(path)
+	if !filepath.IsAbs(full) {
+		resolved, err := s.Resolve(full)
+		if err != nil {
+			return "", err
+		}
+		full = resolved
+	}
+
+	root, err := filepath.EvalSymlinks(s.root)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve directory %s: %w", s.root, err)
+	}
+	real, err := filepath.EvalSymlinks(full)
+	if err != nil {
+		return "", fmt.Errorf("file not found: %s", path)
+	}
+	within, err := filepath.Rel(root, real)
+	if err != nil || within == ".." || strings.HasPrefix(within, ".."+string(filepath.Separator)) || filepath.IsAbs(within) {
+		return "", fmt.Errorf("path %q is outside of %s", path, s.root)
+	}
+	return real, nil
+}
+
+// Dir 解析并创建沙箱内的子目录
+func (s *Sandbox) Dir(elem ...string) (string, error) {
+	dir, err := s.Resolve(elem...)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+	return dir, nil
+}
+
+// SanitizeFileName 清理用户提供的文件名：去掉目录部分，替换路径分隔符与控制字符等非法字符
+// 结果为空或仅由点组成时返回 fallback
+func SanitizeFileName(name, fallback string) string {
+	// 同时按 / 和 \ 截取最后一段，避免 Windows 风格的路径绕过
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsControl(r):
+			return -1
+		case strings.ContainsRune(`<>:"|?*`, r):
+			return '_'
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if strings.Trim(name, ".") == "" {
+		return fallback
+	}
+	// 限制长度，保留扩展名
+	const maxLen = 200
+	if len(name) > maxLen {
+		ext := filepath.Ext(name)
+		if len(ext) > 20 {
+			ext = ""
+		}
+		name = strings.ToValidUTF8(name[:maxLen-len(ext)], "") + ext
+	}
+	return name
+}
+
+// TemplateVars 路径模板变量
+type TemplateVars struct {
+	Script    string    // 脚本名称
+	ScriptID  string    // 脚本 ID
+	Execution string    // 执行记录 ID
+	Task      string    // 定时任务 ID
+	Time      time.Time // 时间，为零值时使用当前时间
+}
+
+// ExpandTemplate 展开路径模板，支持 {script}、{script_id}、{date}、{time}、{execution}、{task}
+// 每个变量值都按单个路径段清理，值为空的路径段会被忽略；结果始终是相对路径
+func ExpandTemplate(tmpl string, vars TemplateVars) string {
+	if vars.Time.IsZero() {
+		vars.Time = time.Now()
+	}
+	replacer := strings.NewReplacer(
+		"{script}", SanitizeFileName(vars.Script, ""),
+		"{script_id}", SanitizeFileName(vars.ScriptID, ""),
+		"{date}", vars.Time.Format("2006-01-02"),
+		"{time}", vars.Time.Format("150405"),
+		"{execution}", SanitizeFileName(vars.Execution, ""),
+		"{task}", SanitizeFileName(vars.Task, ""),
+	)
+
+	segments := make([]string, 0)
+	for _, segment := range strings.FieldsFunc(tmpl, func(r rune) bool { return r == '/' || r == '\\' }) {
+		segment = SanitizeFileName(replacer.Replace(segment), "")
+		if segment == "" {
+			continue
+		}
+		segments = append(segments, segment)
+	}
+	return filepath.Join(segments...)
+}
@@ -0,0 +1,144 @@
+package artifacts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSandboxResolve(t *testing.T) {
+	root := t.TempDir()
+	s, err := NewSandbox(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		elem    []string
+		want    string
+		wantErr bool
+	}{
+		{elem: []string{"a.png"}, want: filepath.Join(root, "a.png")},
+		{elem: []string{"demo/2026-01-02", "a.png"}, want: filepath.Join(root, "demo", "2026-01-02", "a.png")},
+		{elem: []string{"x/../a.png"}, want: filepath.Join(root, "a.png")},
+		{elem: nil, want: root},
+		{elem: []string{"../a.png"}, wantErr: true},
+		{elem: []string{"x", "../../etc/passwd"}, wantErr: true},
+		{elem: []string{"/etc/passwd"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := s.Resolve(tt.elem...)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Resolve(%v) expected error, got %s", tt.elem, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Resolve(%v) unexpected error: %v", tt.elem, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Resolve(%v) = %s, want %s", tt.elem, got, tt.want)
+		}
+	}
+}
+
+func TestSandboxContains(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	s, err := NewSandbox(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inside := filepath.Join(root, "a.txt")
+	secret := filepath.Join(outside, "secret.txt")
+	for _, path := range []string{inside, secret} {
+		if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(secret, filepath.Join(root, "link.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{path: "a.txt"},
+		{path: inside},
+		{path: "../" + filepath.Base(outside) + "/secret.txt", wantErr: true},
+		{path: secret, wantErr: true},
+		{path: "/etc/passwd", wantErr: true},
+		{path: "link.txt", wantErr: true},
+		{path: "missing.txt", wantErr: true},
+	}
+	for _, tt := range tests {
+		_, err := s.Contains(tt.path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Contains(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSanitizeFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"report.pdf", "report.pdf"},
+		{"../../etc/passwd", "passwd"},
+		{`..\..\boot.ini`, "boot.ini"},
+		{"a:b*c?.txt", "a_b_c_.txt"},
+		{"..", "file"},
+		{"", "file"},
+		{"line\nbreak.txt", "linebreak.txt"},
+		{"报告 2026.xlsx", "报告 2026.xlsx"},
+	}
+
+	for _, tt := range tests {
+		if got := SanitizeFileName(tt.name, "file"); got != tt.want {
+			t.Errorf("SanitizeFileName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExpandTemplate(t *testing.T) {
+	at := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	tests := []struct {
+		tmpl string
+		vars TemplateVars
+		want string
+	}{
+		{
+			tmpl: "{script}/{date}/{execution}",
+			vars: TemplateVars{Script: "Daily Report", Execution: "abc-1", Time: at},
+			want: filepath.Join("Daily Report", "2026-03-04", "abc-1"),
+		},
+		{
+			tmpl: "{script}/{date}/{execution}",
+			vars: TemplateVars{Time: at},
+			want: "2026-03-04",
+		},
+		{
+			tmpl: "{script}/{time}",
+			vars: TemplateVars{Script: "../../evil", Time: at},
+			want: filepath.Join("evil", "050607"),
+		},
+		{
+			tmpl: "../{task}",
+			vars: TemplateVars{Task: "t1", Time: at},
+			want: "t1",
+		},
+		{tmpl: "", vars: TemplateVars{Script: "x"}, want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := ExpandTemplate(tt.tmpl, tt.vars); got != tt.want {
+			t.Errorf("ExpandTemplate(%q) = %q, want %q", tt.tmpl, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package artifacts
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// SignPath 计算产物路径在 expires 之前有效的签名（HMAC-SHA256）
+func SignPath(secret, path string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(path + "\n" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignedQuery 返回带过期时间和签名的查询参数，拼接在产物地址之后
+func SignedQuery(secret, path string, expires time.Time) string {
+	unix := expires.Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(unix, 10))
+	query.Set("sig", SignPath(secret, path, unix))
+	return query.Encode()
+}
+
+// VerifySignedPath 检查产物路径的签名是否有效且未过期
+func VerifySignedPath(secret, path, expires, sig string, now time.Time) bool {
+	if secret == "" || sig == "" {
+		return false
+	}
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || now.Unix() > unix {
+		return false
+	}
+	return hmac.Equal([]byte(sig), []byte(SignPath(secret, path, unix)))
+}
@@ -0,0 +1,35 @@
+package artifacts
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestSignedPath(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	query, err := url.ParseQuery(SignedQuery("secret", "recordings/a.gif", now.Add(time.Hour)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expires, sig := query.Get("expires"), query.Get("sig")
+
+	if !VerifySignedPath("secret", "recordings/a.gif", expires, sig, now) {
+		t.Error("valid signature rejected")
+	}
+	if VerifySignedPath("secret", "recordings/b.gif", expires, sig, now) {
+		t.Error("signature should be bound to the path")
+	}
+	if VerifySignedPath("other", "recordings/a.gif", expires, sig, now) {
+		t.Error("signature should be bound to the secret")
+	}
+	if VerifySignedPath("secret", "recordings/a.gif", expires, sig, now.Add(2*time.Hour)) {
+		t.Error("expired signature accepted")
+	}
+	if VerifySignedPath("secret", "recordings/a.gif", "1800000000", sig, now) {
+		t.Error("signature should be bound to the expiry")
+	}
+	if VerifySignedPath("", "recordings/a.gif", expires, SignPath("", "recordings/a.gif", now.Unix()+60), now) {
+		t.Error("empty secret should never verify")
+	}
+}
@@ -0,0 +1,282 @@
+// Package clientgen 根据 OpenAPI 文档生成 Python 和 TypeScript 客户端的类型定义和接口列表
+package clientgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// header 生成文件的文件头
+const header = "Code generated by gen-clients from /openapi.json. DO NOT EDIT."
+
+// Spec OpenAPI 文档中生成客户端需要的部分
+type Spec struct {
+	Info struct {
+		Version string `json:"version"`
+	} `json:"info"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+}
+
+// Operation 一个接口
+type Operation struct {
+	OperationID string   `json:"operationId"`
+	Summary     string   `json:"summary"`
+	Tags        []string `json:"tags"`
+
+	method string
+	path   string
+}
+
+// Schema JSON Schema（只包含 BrowserWing 文档用到的关键字）
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Format               string             `json:"format"`
+	Description          string             `json:"description"`
+	Items                *Schema            `json:"items"`
+	Properties           map[string]*Schema `json:"properties"`
+	AdditionalProperties *Schema            `json:"additionalProperties"`
+	Required             []string           `json:"required"`
+}
+
+// Parse 解析 OpenAPI 文档
+func Parse(data []byte) (*Spec, error) {
+	var spec Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+	return &spec, nil
+}
+
+// operations 按 operationId 排序的接口列表
+func (s *Spec) operations() []*Operation {
+	var ops []*Operation
+	for path, item := range s.Paths {
+		for method, op := range item {
+			op.method = strings.ToUpper(method)
+			op.path = path
+			ops = append(ops, op)
+		}
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i].OperationID < ops[j].OperationID })
+	return ops
+}
+
+// schemaNames 排序后的 schema 名称
+func (s *Spec) schemaNames() []string {
+	names := make([]string, 0, len(s.Components.Schemas))
+	for name := range s.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedKeys(m map[string]*Schema) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func refName(ref string) string {
+	return strings.TrimPrefix(ref, "#/components/schemas/")
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ================== TypeScript ==================
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// TypeScript 生成 models.ts：每个 schema 一个 interface，以及所有接口的请求方法和路径
+func TypeScript(spec *Spec) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// %s\n\n", header)
+	fmt.Fprintf(&b, "export const API_VERSION = %q;\n", spec.Info.Version)
+
+	for _, name := range spec.schemaNames() {
+		schema := spec.Components.Schemas[name]
+		b.WriteString("\n")
+		if schema.Type != "object" || schema.Properties == nil {
+			fmt.Fprintf(&b, "export type %s = %s;\n", name, tsType(schema, ""))
+			continue
+		}
+		fmt.Fprintf(&b, "export interface %s %s\n", name, tsObject(schema, ""))
+	}
+
+	b.WriteString("\n/** Method and path of every API operation, keyed by operationId. */\n")
+	b.WriteString("export const operations = {\n")
+	for _, op := range spec.operations() {
+		fmt.Fprintf(&b, "  %s: { method: %q, path: %q },\n", op.OperationID, op.method, op.path)
+	}
+	b.WriteString("} as const;\n\n")
+	b.WriteString("export type OperationId = keyof typeof operations;\n")
+	return b.Bytes()
+}
+
+func tsObject(schema *Schema, indent string) string {
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, key := range sortedKeys(schema.Properties) {
+		prop := schema.Properties[key]
+		if prop.Description != "" {
+			fmt.Fprintf(&b, "%s  /** %s */\n", indent, prop.Description)
+		}
+		name := key
+		if !tsIdentifier.MatchString(key) {
+			name = fmt.Sprintf("%q", key)
+		}
+		optional := "?"
+		if contains(schema.Required, key) {
+			optional = ""
+		}
+		fmt.Fprintf(&b, "%s  %s%s: %s;\n", indent, name, optional, tsType(prop, indent+"  "))
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+func tsType(schema *Schema, indent string) string {
+	if schema == nil {
+		return "unknown"
+	}
+	if schema.Ref != "" {
+		return refName(schema.Ref)
+	}
+	switch schema.Type {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		item := tsType(schema.Items, indent)
+		if strings.ContainsAny(item, " |{") {
+			return "Array<" + item + ">"
+		}
+		return item + "[]"
+	case "object":
+		if schema.Properties != nil {
+			return tsObject(schema, indent)
+		}
+		if schema.AdditionalProperties != nil {
+			return "Record<string, " + tsType(schema.AdditionalProperties, indent) + ">"
+		}
+		return "Record<string, unknown>"
+	}
+	return "unknown"
+}
+
+// ================== Python ==================
+
+var pyIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// pyKeywords 不能作为 TypedDict 类语法字段名的关键字
+var pyKeywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true, "assert": true, "async": true,
+	"await": true, "break": true, "class": true, "continue": true, "def": true, "del": true, "elif": true,
+	"else": true, "except": true, "finally": true, "for": true, "from": true, "global": true, "if": true,
+	"import": true, "in": true, "is": true, "lambda": true, "nonlocal": true, "not": true, "or": true,
+	"pass": true, "raise": true, "return": true, "try": true, "while": true, "with": true, "yield": true,
+}
+
+// Python 生成 models.py：每个 schema 一个 TypedDict（字段均可省略），以及所有接口的请求方法和路径
+func Python(spec *Spec) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# %s\n\n", header)
+	b.WriteString("from typing import Any, Dict, List, TypedDict\n\n")
+	fmt.Fprintf(&b, "API_VERSION = %q\n", spec.Info.Version)
+
+	// TypedDict 的字段类型在定义时求值，按依赖顺序输出，引用尚未定义的类型时使用字符串前向引用
+	defined := make(map[string]bool)
+	for _, name := range spec.schemaNames() {
+		schema := spec.Components.Schemas[name]
+		b.WriteString("\n\n")
+		if schema.Type != "object" || schema.Properties == nil {
+			fmt.Fprintf(&b, "%s = %s\n", name, pyType(schema, defined))
+			defined[name] = true
+			continue
+		}
+
+		keys := sortedKeys(schema.Properties)
+		classSyntax := true
+		for _, key := range keys {
+			if !pyIdentifier.MatchString(key) || pyKeywords[key] {
+				classSyntax = false
+			}
+		}
+		if classSyntax {
+			fmt.Fprintf(&b, "class %s(TypedDict, total=False):\n", name)
+			if len(keys) == 0 {
+				b.WriteString("    pass\n")
+			}
+			for _, key := range keys {
+				fmt.Fprintf(&b, "    %s: %s\n", key, pyType(schema.Properties[key], defined))
+			}
+		} else {
+			fmt.Fprintf(&b, "%s = TypedDict(\n    %q,\n    {\n", name, name)
+			for _, key := range keys {
+				fmt.Fprintf(&b, "        %q: %s,\n", key, pyType(schema.Properties[key], defined))
+			}
+			b.WriteString("    },\n    total=False,\n)\n")
+		}
+		defined[name] = true
+	}
+
+	b.WriteString("\n\n# Method and path of every API operation, keyed by operationId.\n")
+	b.WriteString("OPERATIONS: Dict[str, Dict[str, str]] = {\n")
+	for _, op := range spec.operations() {
+		fmt.Fprintf(&b, "    %q: {\"method\": %q, \"path\": %q},\n", op.OperationID, op.method, op.path)
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+func pyType(schema *Schema, defined map[string]bool) string {
+	if schema == nil {
+		return "Any"
+	}
+	if schema.Ref != "" {
+		name := refName(schema.Ref)
+		if defined[name] {
+			return name
+		}
+		return fmt.Sprintf("%q", name)
+	}
+	switch schema.Type {
+	case "string":
+		return "str"
+	case "integer":
+		return "int"
+	case "number":
+		return "float"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "List[" + pyType(schema.Items, defined) + "]"
+	case "object":
+		if schema.Properties == nil && schema.AdditionalProperties != nil {
+			return "Dict[str, " + pyType(schema.AdditionalProperties, defined) + "]"
+		}
+		return "Dict[str, Any]"
+	}
+	return "Any"
+}
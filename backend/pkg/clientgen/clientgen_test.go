@@ -0,0 +1,64 @@
+package clientgen
+
+import (
+	"strings"
+	"testing"
+)
+
+const testSpec = `{
+  "info": {"version": "v1"},
+  "paths": {
+    "/api/v1/scripts/{id}": {"get": {"operationId": "GetScript"}},
+    "/api/v1/scripts": {"post": {"operationId": "SaveScript"}}
+  },
+  "components": {"schemas": {
+    "Script": {"type": "object", "required": ["name"], "properties": {
+      "name": {"type": "string"},
+      "actions": {"type": "array", "items": {"$ref": "#/components/schemas/ScriptAction"}},
+      "variables": {"type": "object", "additionalProperties": {"type": "string"}},
+      "created_at": {"type": "string", "format": "date-time"}
+    }},
+    "ScriptAction": {"type": "object", "properties": {
+      "timeout": {"type": "integer"},
+      "from": {"type": "string"},
+      "extra": {}
+    }}
+  }}
+}`
+
+func TestTypeScript(t *testing.T) {
+	spec, err := Parse([]byte(testSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(TypeScript(spec))
+	for _, want := range []string{
+		"export interface Script {\n  actions?: ScriptAction[];\n  created_at?: string;\n  name: string;\n  variables?: Record<string, string>;\n}",
+		"  extra?: unknown;",
+		"  GetScript: { method: \"GET\", path: \"/api/v1/scripts/{id}\" },\n  SaveScript: { method: \"POST\", path: \"/api/v1/scripts\" },",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("TypeScript output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestPython(t *testing.T) {
+	spec, err := Parse([]byte(testSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(Python(spec))
+	for _, want := range []string{
+		// Script 在 ScriptAction 之前定义，使用前向引用
+		"class Script(TypedDict, total=False):\n    actions: List[\"ScriptAction\"]\n",
+		"    variables: Dict[str, str]\n",
+		// 字段名是关键字时使用函数语法
+		"ScriptAction = TypedDict(\n    \"ScriptAction\",\n    {\n        \"extra\": Any,\n        \"from\": str,\n        \"timeout\": int,\n    },\n    total=False,\n)",
+		"    \"GetScript\": {\"method\": \"GET\", \"path\": \"/api/v1/scripts/{id}\"},",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Python output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"strings"
 
 	"github.com/sirupsen/logrus"
-	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 type Logger interface {
@@ -72,12 +71,16 @@ func (l *logrusLogger) Debug(ctx context.Context, msg string, args ...any) {
 var defaultLogger Logger
 
 type LoggerConfig struct {
-	Level      string `json:"level,omitempty" yaml:"level,omitempty" toml:"level,omitempty"`
-	File       string `json:"file,omitempty" yaml:"file,omitempty" toml:"file,omitempty"`
-	MaxSize    int    `json:"max_size,omitempty" yaml:"max_size,omitempty" toml:"max_size,omitempty"`       // 单个日志文件最大大小(MB),默认100MB
-	MaxBackups int    `json:"max_backups,omitempty" yaml:"max_backups,omitempty" toml:"max_backups,omitempty"` // 保留的旧日志文件最大数量,默认3个
-	MaxAge     int    `json:"max_age,omitempty" yaml:"max_age,omitempty" toml:"max_age,omitempty"`          // 保留旧日志文件的最大天数,默认7天
-	Compress   bool   `json:"compress,omitempty" yaml:"compress,omitempty" toml:"compress,omitempty"`       // 是否压缩旧日志,默认false
+	Level       string `json:"level,omitempty" yaml:"level,omitempty" toml:"level,omitempty"`
+	File        string `json:"file,omitempty" yaml:"file,omitempty" toml:"file,omitempty"`
+	MaxSize     int    `json:"max_size,omitempty" yaml:"max_size,omitempty" toml:"max_size,omitempty"`             // 单个日志文件最大大小(MB),默认100MB
+	MaxBackups  int    `json:"max_backups,omitempty" yaml:"max_backups,omitempty" toml:"max_backups,omitempty"`    // 保留的旧日志文件最大数量,默认3个
+	MaxAge      int    `json:"max_age,omitempty" yaml:"max_age,omitempty" toml:"max_age,omitempty"`                // 保留旧日志文件的最大天数,默认7天
+	Compress    bool   `json:"compress,omitempty" yaml:"compress,omitempty" toml:"compress,omitempty"`             // 是否压缩旧日志,默认false
+	RotateHours int    `json:"rotate_hours,omitempty" yaml:"rotate_hours,omitempty" toml:"rotate_hours,omitempty"` // 每隔多少小时轮转一次（按 UTC 整点对齐，24 为每天），0 表示只按大小轮转
+
+	// 额外的日志输出（文件、syslog、Loki、HTTP），与主输出同时写入
+	Sinks []SinkConfig `json:"sinks,omitempty" yaml:"sinks,omitempty" toml:"sinks,omitempty"`
 }
 
 func InitLogger(cfg *LoggerConfig) {
@@ -87,36 +90,17 @@ func InitLogger(cfg *LoggerConfig) {
 		level = logrus.InfoLevel
 	}
 	log.SetLevel(level)
-	
+
 	// 配置 JSON 格式,方便提取 trace_id
 	log.SetFormatter(&logrus.JSONFormatter{
 		TimestampFormat: "2006-01-02 15:04:05",
 	})
-	
+
 	if cfg.File != "" {
-		// 设置默认值
-		maxSize := cfg.MaxSize
-		if maxSize <= 0 {
-			maxSize = 100 // 默认 100MB
-		}
-		maxBackups := cfg.MaxBackups
-		if maxBackups <= 0 {
-			maxBackups = 3 // 默认保留 3 个备份
-		}
-		maxAge := cfg.MaxAge
-		if maxAge <= 0 {
-			maxAge = 7 // 默认保留 7 天
-		}
-		
-		// 使用 lumberjack 实现日志轮转
-		log.SetOutput(&lumberjack.Logger{
-			Filename:   cfg.File,
-			MaxSize:    maxSize,
-			MaxBackups: maxBackups,
-			MaxAge:     maxAge,
-			Compress:   cfg.Compress,
-		})
+		// 使用 lumberjack 实现按大小轮转，配置了 rotate_hours 时同时按时间轮转
+		log.SetOutput(newRotatingFile(cfg.File, cfg.MaxSize, cfg.MaxBackups, cfg.MaxAge, cfg.Compress, cfg.RotateHours))
 	}
+	setupSinks(log, cfg.Sinks)
 
 	defaultLogger = &logrusLogger{logger: log}
 }
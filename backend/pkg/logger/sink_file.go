@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// rotatingFile 按大小（lumberjack）和时间轮转的日志文件
+type rotatingFile struct {
+	mu       sync.Mutex
+	out      *lumberjack.Logger
+	interval time.Duration // 按时间轮转的间隔，0 表示只按大小轮转
+	next     time.Time     // 下次按时间轮转的时间
+	now      func() time.Time
+}
+
+// newRotatingFile 创建日志文件，未设置的轮转参数使用默认值
+func newRotatingFile(file string, maxSize, maxBackups, maxAge int, compress bool, rotateHours int) *rotatingFile {
+	if maxSize <= 0 {
+		maxSize = 100 // 默认 100MB
+	}
+	if maxBackups <= 0 {
+		maxBackups = 3 // 默认保留 3 个备份
+	}
+	if maxAge <= 0 {
+		maxAge = 7 // 默认保留 7 天
+	}
+
+	f := &rotatingFile{
+		out: &lumberjack.Logger{
+			Filename:   file,
+			MaxSize:    maxSize,
+			MaxBackups: maxBackups,
+			MaxAge:     maxAge,
+			Compress:   compress,
+		},
+		now: time.Now,
+	}
+	if rotateHours > 0 {
+		f.interval = time.Duration(rotateHours) * time.Hour
+		f.next = f.now().Truncate(f.interval).Add(f.interval)
+	}
+	return f
+}
+
+// Write 写入日志，到达轮转时间时先轮转
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.interval > 0 {
+		if now := f.now(); !now.Before(f.next) {
+			if err := f.out.Rotate(); err != nil {
+				return 0, err
+			}
+			f.next = now.Truncate(f.interval).Add(f.interval)
+		}
+	}
+	return f.out.Write(p)
+}
+
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.out.Close()
+}
+
+// fileSink 写入日志文件的输出
+type fileSink struct {
+	*rotatingFile
+}
+
+func newFileSink(cfg SinkConfig) *fileSink {
+	return &fileSink{newRotatingFile(cfg.File, cfg.MaxSize, cfg.MaxBackups, cfg.MaxAge, cfg.Compress, cfg.RotateHours)}
+}
+
+func (s *fileSink) write(_ *logrus.Entry, line []byte) error {
+	_, err := s.Write(line)
+	return err
+}
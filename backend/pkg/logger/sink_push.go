@@ -0,0 +1,222 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	pushBufferSize   = 10000           // 等待推送的最大条数，超过时丢弃新日志，不阻塞业务代码
+	pushCloseTimeout = 5 * time.Second // 关闭时等待推送剩余日志的最长时间
+)
+
+// pushEntry 等待推送的一条日志
+type pushEntry struct {
+	at    time.Time
+	level string
+	line  string // JSON 格式的日志，不含换行
+}
+
+// pushSink 把日志分批 POST 到 HTTP 接口的输出（loki、http），推送失败的批次丢弃并在标准错误输出提示
+type pushSink struct {
+	name      string
+	url       string
+	headers   map[string]string
+	encode    func(batch []pushEntry) ([]byte, error)
+	batchSize int
+	interval  time.Duration
+	client    *http.Client
+
+	entries   chan pushEntry
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	dropped   atomic.Int64 // 缓冲已满时丢弃的条数
+}
+
+func newPushSink(name, url string, cfg SinkConfig, encode func(batch []pushEntry) ([]byte, error)) *pushSink {
+	s := &pushSink{
+		name:      name,
+		url:       url,
+		headers:   cfg.Headers,
+		encode:    encode,
+		batchSize: cfg.BatchSize,
+		interval:  time.Duration(cfg.FlushSeconds) * time.Second,
+		client:    &http.Client{Timeout: 10 * time.Second},
+		entries:   make(chan pushEntry, pushBufferSize),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	if s.batchSize <= 0 {
+		s.batchSize = 100
+	}
+	if s.interval <= 0 {
+		s.interval = 5 * time.Second
+	}
+	go s.run()
+	return s
+}
+
+// newLokiSink 推送到 Loki 的 push API，按日志级别分成不同的流
+func newLokiSink(cfg SinkConfig) *pushSink {
+	labels := map[string]string{"app": "browserwing"}
+	if len(cfg.Labels) > 0 {
+		labels = cfg.Labels
+	}
+	url := strings.TrimSuffix(cfg.URL, "/")
+	if !strings.HasSuffix(url, "/loki/api/v1/push") {
+		url += "/loki/api/v1/push"
+	}
+	return newPushSink("loki", url, cfg, func(batch []pushEntry) ([]byte, error) {
+		return encodeLokiBatch(labels, batch)
+	})
+}
+
+// newHTTPSink 把一批日志作为 JSON 数组 POST 到指定地址
+func newHTTPSink(cfg SinkConfig) *pushSink {
+	return newPushSink("http", cfg.URL, cfg, encodeHTTPBatch)
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// encodeLokiBatch 生成 Loki push API 的请求体：{"streams": [{"stream": {标签}, "values": [["纳秒时间戳", "日志"]]}]}
+func encodeLokiBatch(labels map[string]string, batch []pushEntry) ([]byte, error) {
+	var streams []*lokiStream
+	byLevel := make(map[string]*lokiStream)
+	for _, e := range batch {
+		stream, ok := byLevel[e.level]
+		if !ok {
+			streamLabels := make(map[string]string, len(labels)+1)
+			for k, v := range labels {
+				streamLabels[k] = v
+			}
+			streamLabels["level"] = e.level
+			stream = &lokiStream{Stream: streamLabels}
+			byLevel[e.level] = stream
+			streams = append(streams, stream)
+		}
+		stream.Values = append(stream.Values, [2]string{strconv.FormatInt(e.at.UnixNano(), 10), e.line})
+	}
+	return json.Marshal(map[string]interface{}{"streams": streams})
+}
+
+// encodeHTTPBatch 生成 JSON 数组，每个元素是一条 JSON 格式的日志
+func encodeHTTPBatch(batch []pushEntry) ([]byte, error) {
+	lines := make([]json.RawMessage, len(batch))
+	for i, e := range batch {
+		lines[i] = json.RawMessage(e.line)
+	}
+	return json.Marshal(lines)
+}
+
+func (s *pushSink) write(entry *logrus.Entry, line []byte) error {
+	select {
+	case <-s.stop:
+		return nil
+	default:
+	}
+	e := pushEntry{at: entry.Time, level: entry.Level.String(), line: strings.TrimSuffix(string(line), "\n")}
+	select {
+	case s.entries <- e:
+	default:
+		s.dropped.Add(1)
+	}
+	return nil
+}
+
+// run 攒够一批或到达推送间隔时推送，关闭时推送剩余日志
+func (s *pushSink) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	batch := make([]pushEntry, 0, s.batchSize)
+	flush := func() {
+		if dropped := s.dropped.Swap(0); dropped > 0 {
+			fmt.Fprintf(os.Stderr, "Log sink %s dropped %d entries because the buffer was full\n", s.name, dropped)
+		}
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.push(batch); err != nil {
+			fmt.Fprintf(os.Stderr, "Log sink %s failed to push %d entries: %v\n", s.name, len(batch), err)
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case e := <-s.entries:
+			batch = append(batch, e)
+			if len(batch) >= s.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-s.stop:
+			for {
+				select {
+				case e := <-s.entries:
+					batch = append(batch, e)
+					if len(batch) >= s.batchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// push 发送一批日志
+func (s *pushSink) push(batch []pushEntry) error {
+	body, err := s.encode(batch)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// Close 停止接收日志并推送剩余日志，最多等待 5 秒
+func (s *pushSink) Close() error {
+	s.closeOnce.Do(func() { close(s.stop) })
+	select {
+	case <-s.done:
+		return nil
+	case <-time.After(pushCloseTimeout):
+		return fmt.Errorf("timed out pushing remaining %s log entries", s.name)
+	}
+}
@@ -0,0 +1,47 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"log/syslog"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// syslogSink 写入 syslog 的输出，日志级别对应 syslog 的优先级
+type syslogSink struct {
+	w *syslog.Writer
+}
+
+func newSyslogSink(cfg SinkConfig) (sink, error) {
+	tag := cfg.Tag
+	if tag == "" {
+		tag = "browserwing"
+	}
+	w, err := syslog.Dial(cfg.Network, cfg.Address, syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &syslogSink{w: w}, nil
+}
+
+func (s *syslogSink) write(entry *logrus.Entry, line []byte) error {
+	msg := strings.TrimSuffix(string(line), "\n")
+	switch entry.Level {
+	case logrus.PanicLevel, logrus.FatalLevel:
+		return s.w.Crit(msg)
+	case logrus.ErrorLevel:
+		return s.w.Err(msg)
+	case logrus.WarnLevel:
+		return s.w.Warning(msg)
+	case logrus.InfoLevel:
+		return s.w.Info(msg)
+	default:
+		return s.w.Debug(msg)
+	}
+}
+
+func (s *syslogSink) Close() error {
+	return s.w.Close()
+}
@@ -0,0 +1,12 @@
+//go:build windows || plan9
+
+package logger
+
+import (
+	"fmt"
+	"runtime"
+)
+
+func newSyslogSink(cfg SinkConfig) (sink, error) {
+	return nil, fmt.Errorf("syslog is not supported on %s", runtime.GOOS)
+}
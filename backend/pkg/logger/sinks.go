@@ -0,0 +1,135 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SinkConfig 额外的日志输出，在主输出（file 或标准输出）之外同时写入
+type SinkConfig struct {
+	Type  string `json:"type" yaml:"type" toml:"type"`                                  // file、syslog、loki 或 http
+	Level string `json:"level,omitempty" yaml:"level,omitempty" toml:"level,omitempty"` // 写入该输出的最低级别，默认与全局 level 相同（不能比全局 level 更详细）
+
+	// file：按大小或时间轮转的日志文件
+	File        string `json:"file,omitempty" yaml:"file,omitempty" toml:"file,omitempty"`
+	MaxSize     int    `json:"max_size,omitempty" yaml:"max_size,omitempty" toml:"max_size,omitempty"`             // 单个日志文件最大大小(MB),默认100MB
+	MaxBackups  int    `json:"max_backups,omitempty" yaml:"max_backups,omitempty" toml:"max_backups,omitempty"`    // 保留的旧日志文件最大数量,默认3个
+	MaxAge      int    `json:"max_age,omitempty" yaml:"max_age,omitempty" toml:"max_age,omitempty"`                // 保留旧日志文件的最大天数,默认7天
+	Compress    bool   `json:"compress,omitempty" yaml:"compress,omitempty" toml:"compress,omitempty"`             // 是否压缩旧日志,默认false
+	RotateHours int    `json:"rotate_hours,omitempty" yaml:"rotate_hours,omitempty" toml:"rotate_hours,omitempty"` // 每隔多少小时轮转一次（按 UTC 整点对齐，24 为每天），0 表示只按大小轮转
+
+	// syslog：Network 和 Address 为空时写入本机 syslog
+	Network string `json:"network,omitempty" yaml:"network,omitempty" toml:"network,omitempty"` // udp 或 tcp
+	Address string `json:"address,omitempty" yaml:"address,omitempty" toml:"address,omitempty"` // 如 localhost:514
+	Tag     string `json:"tag,omitempty" yaml:"tag,omitempty" toml:"tag,omitempty"`             // 默认 browserwing
+
+	// loki、http：日志分批推送
+	URL          string            `json:"url,omitempty" yaml:"url,omitempty" toml:"url,omitempty"`                               // loki 为 Loki 地址（推送到 {url}/loki/api/v1/push），http 为接收 POST 的地址
+	Labels       map[string]string `json:"labels,omitempty" yaml:"labels,omitempty" toml:"labels,omitempty"`                      // loki 的流标签，默认 app=browserwing，另外按日志级别加 level 标签
+	Headers      map[string]string `json:"headers,omitempty" yaml:"headers,omitempty" toml:"headers,omitempty"`                   // 额外的请求头，如 Authorization、X-Scope-OrgID
+	BatchSize    int               `json:"batch_size,omitempty" yaml:"batch_size,omitempty" toml:"batch_size,omitempty"`          // 每批最多条数，默认 100
+	FlushSeconds int               `json:"flush_seconds,omitempty" yaml:"flush_seconds,omitempty" toml:"flush_seconds,omitempty"` // 最长推送间隔（秒），默认 5
+}
+
+// sink 日志输出，line 为格式化后的一行日志（JSON，以换行结尾）
+type sink interface {
+	write(entry *logrus.Entry, line []byte) error
+	Close() error
+}
+
+// sinkHook 把达到级别的日志写入 sink
+type sinkHook struct {
+	levels []logrus.Level
+	sink   sink
+}
+
+func (h *sinkHook) Levels() []logrus.Level {
+	return h.levels
+}
+
+func (h *sinkHook) Fire(entry *logrus.Entry) error {
+	line, err := entry.Bytes()
+	if err != nil {
+		return err
+	}
+	return h.sink.write(entry, line)
+}
+
+var (
+	sinksMu sync.Mutex
+	sinks   []sink
+)
+
+// newSink 按配置创建日志输出
+func newSink(cfg SinkConfig) (sink, error) {
+	switch strings.ToLower(cfg.Type) {
+	case "file":
+		if cfg.File == "" {
+			return nil, fmt.Errorf("file is required")
+		}
+		return newFileSink(cfg), nil
+	case "syslog":
+		return newSyslogSink(cfg)
+	case "loki":
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("url is required")
+		}
+		return newLokiSink(cfg), nil
+	case "http":
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("url is required")
+		}
+		return newHTTPSink(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown sink type %q", cfg.Type)
+	}
+}
+
+// sinkLevels 输出接收的日志级别：不超过 sink 的 level，未设置或无效时使用全局级别
+func sinkLevels(level string, global logrus.Level) []logrus.Level {
+	maxLevel := global
+	if parsed, err := logrus.ParseLevel(level); err == nil && parsed < global {
+		maxLevel = parsed
+	}
+	return logrus.AllLevels[:maxLevel+1]
+}
+
+// setupSinks 关闭之前的输出，按配置创建新输出并挂到 log 上，无法创建的输出记录警告后跳过
+func setupSinks(log *logrus.Logger, configs []SinkConfig) {
+	closeSinks()
+
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+	for _, cfg := range configs {
+		s, err := newSink(cfg)
+		if err != nil {
+			log.Warnf("Failed to set up %s log sink: %v", cfg.Type, err)
+			continue
+		}
+		log.AddHook(&sinkHook{levels: sinkLevels(cfg.Level, log.GetLevel()), sink: s})
+		sinks = append(sinks, s)
+	}
+}
+
+// closeSinks 关闭所有输出
+func closeSinks() {
+	sinksMu.Lock()
+	closing := sinks
+	sinks = nil
+	sinksMu.Unlock()
+
+	for _, s := range closing {
+		if err := s.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to close log sink: %v\n", err)
+		}
+	}
+}
+
+// Close 关闭额外的日志输出，推送缓冲中的日志，程序退出前调用
+func Close() {
+	closeSinks()
+}
@@ -0,0 +1,165 @@
+package logger
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestRotatingFileRotatesByTime(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 1, 2, 23, 30, 0, 0, time.UTC)
+	f := newRotatingFile(filepath.Join(dir, "app.log"), 0, 0, 0, false, 24)
+	f.now = func() time.Time { return now }
+	f.next = now.Truncate(f.interval).Add(f.interval)
+	defer f.Close()
+
+	write := func(line string) {
+		if _, err := f.Write([]byte(line + "\n")); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+	write("before midnight")
+	now = now.Add(40 * time.Minute)
+	write("after midnight")
+	write("same day")
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected the current file and one rotated file, got %d files", len(entries))
+	}
+	current, err := os.ReadFile(filepath.Join(dir, "app.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(current) != "after midnight\nsame day\n" {
+		t.Errorf("unexpected current file content: %q", current)
+	}
+}
+
+func TestSinkLevels(t *testing.T) {
+	if got := sinkLevels("", logrus.InfoLevel); len(got) != 5 {
+		t.Errorf("expected the global level to apply, got %v", got)
+	}
+	if got := sinkLevels("error", logrus.InfoLevel); len(got) != 3 || got[2] != logrus.ErrorLevel {
+		t.Errorf("expected panic, fatal and error, got %v", got)
+	}
+	// 比全局级别更详细的级别不生效
+	if got := sinkLevels("debug", logrus.WarnLevel); len(got) != 4 {
+		t.Errorf("expected the global level to cap the sink level, got %v", got)
+	}
+}
+
+// pushServer 记录收到的请求体
+func pushServer(t *testing.T) (*httptest.Server, func() [][]byte) {
+	var mu sync.Mutex
+	var bodies [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, body)
+		mu.Unlock()
+		if r.Header.Get("X-Scope-OrgID") != "tenant1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() [][]byte {
+		mu.Lock()
+		defer mu.Unlock()
+		return bodies
+	}
+}
+
+func newTestLogger(s sink) *logrus.Logger {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	log.SetFormatter(&logrus.JSONFormatter{})
+	log.AddHook(&sinkHook{levels: logrus.AllLevels, sink: s})
+	return log
+}
+
+func TestLokiSink(t *testing.T) {
+	server, bodies := pushServer(t)
+	s := newLokiSink(SinkConfig{
+		URL:     server.URL + "/",
+		Labels:  map[string]string{"app": "browserwing", "env": "test"},
+		Headers: map[string]string{"X-Scope-OrgID": "tenant1"},
+	})
+	log := newTestLogger(s)
+	log.Info("first")
+	log.Error("second")
+	log.Info("third")
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := bodies()
+	if len(got) != 1 {
+		t.Fatalf("expected one push on close, got %d", len(got))
+	}
+	var payload struct {
+		Streams []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(got[0], &payload); err != nil {
+		t.Fatalf("invalid payload %s: %v", got[0], err)
+	}
+	if len(payload.Streams) != 2 {
+		t.Fatalf("expected one stream per level, got %s", got[0])
+	}
+	info := payload.Streams[0]
+	if info.Stream["level"] != "info" || info.Stream["env"] != "test" || len(info.Values) != 2 {
+		t.Errorf("unexpected info stream: %+v", info)
+	}
+	var line map[string]interface{}
+	if err := json.Unmarshal([]byte(info.Values[1][1]), &line); err != nil || line["msg"] != "third" {
+		t.Errorf("expected the JSON log line, got %q", info.Values[1][1])
+	}
+}
+
+func TestHTTPSinkBatches(t *testing.T) {
+	server, bodies := pushServer(t)
+	s := newHTTPSink(SinkConfig{
+		URL:       server.URL,
+		Headers:   map[string]string{"X-Scope-OrgID": "tenant1"},
+		BatchSize: 2,
+	})
+	log := newTestLogger(s)
+	for _, msg := range []string{"a", "b", "c"} {
+		log.Warn(msg)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := bodies()
+	if len(got) != 2 {
+		t.Fatalf("expected a full batch and the rest on close, got %d pushes", len(got))
+	}
+	var batch []map[string]interface{}
+	if err := json.Unmarshal(got[0], &batch); err != nil || len(batch) != 2 || batch[0]["msg"] != "a" || batch[0]["level"] != "warning" {
+		t.Errorf("unexpected first batch %s: %v", got[0], err)
+	}
+
+	// 关闭后的日志丢弃
+	log.Warn("d")
+	if len(bodies()) != 2 {
+		t.Errorf("expected no pushes after close")
+	}
+}
@@ -0,0 +1,64 @@
+package ocr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxResponseSize OCR 服务响应的最大大小
+const maxResponseSize = 10 << 20
+
+// HTTPEngine 调用 HTTP OCR 服务识别文字
+// 请求：POST 图片（Content-Type: image/png），配置了 APIKey 时携带 Authorization: Bearer <key>
+// 响应：JSON {"text": "...", "words": [{"text", "confidence", "x", "y", "width", "height"}]}（words 可选），
+// 或 text/plain 纯文本
+type HTTPEngine struct {
+	URL     string
+	APIKey  string
+	Timeout time.Duration
+	Client  *http.Client // 为空时使用默认客户端
+}
+
+// Recognize 把图片发送到 OCR 服务并解析结果
+func (h *HTTPEngine) Recognize(ctx context.Context, png []byte) (*Result, error) {
+	client := h.Client
+	if client == nil {
+		client = &http.Client{Timeout: h.Timeout}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(png))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "image/png")
+	if h.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+h.APIKey)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ocr request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ocr response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ocr service returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain") {
+		return &Result{Text: strings.TrimSpace(string(body))}, nil
+	}
+	var result Result
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse ocr response: %w", err)
+	}
+	return &result, nil
+}
@@ -0,0 +1,61 @@
+// Package ocr 提供图片文字识别，支持本地 tesseract 命令和 HTTP OCR 服务两种后端
+package ocr
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/browserwing/browserwing/config"
+)
+
+// Word 识别出的单词及其在图片中的位置（像素）
+type Word struct {
+	Text       string  `json:"text"`
+	Confidence float64 `json:"confidence"` // 0-100
+	X          int     `json:"x"`
+	Y          int     `json:"y"`
+	Width      int     `json:"width"`
+	Height     int     `json:"height"`
+}
+
+// Result 识别结果
+type Result struct {
+	Text  string `json:"text"`            // 按行拼接的完整文本
+	Words []Word `json:"words,omitempty"` // 单词及位置（后端不支持时为空）
+}
+
+// Engine 文字识别后端
+type Engine interface {
+	// Recognize 识别 PNG 图片中的文字
+	Recognize(ctx context.Context, png []byte) (*Result, error)
+}
+
+// 识别后端类型
+const (
+	BackendTesseract = "tesseract"
+	BackendHTTP      = "http"
+)
+
+// New 根据配置创建识别后端，cfg 为 nil 时使用默认的 tesseract 后端
+func New(cfg *config.OCRConfig) (Engine, error) {
+	if cfg == nil {
+		cfg = &config.OCRConfig{}
+	}
+	timeout := 60 * time.Second
+	if cfg.TimeoutSeconds > 0 {
+		timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+
+	switch cfg.Backend {
+	case "", BackendTesseract:
+		return &Tesseract{Path: cfg.TesseractPath, Languages: cfg.Languages, Timeout: timeout}, nil
+	case BackendHTTP:
+		if cfg.APIURL == "" {
+			return nil, fmt.Errorf("ocr api_url is required for the http backend")
+		}
+		return &HTTPEngine{URL: cfg.APIURL, APIKey: cfg.APIKey, Timeout: timeout}, nil
+	default:
+		return nil, fmt.Errorf("unknown ocr backend: %s (supported: tesseract, http)", cfg.Backend)
+	}
+}
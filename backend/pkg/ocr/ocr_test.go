@@ -0,0 +1,69 @@
+package ocr
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/browserwing/browserwing/config"
+)
+
+func TestParseTesseractTSV(t *testing.T) {
+	tsv := "level\tpage_num\tblock_num\tpar_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext\n" +
+		"1\t1\t0\t0\t0\t0\t0\t0\t800\t600\t-1\t\n" +
+		"4\t1\t1\t1\t1\t0\t10\t10\t200\t20\t-1\t\n" +
+		"5\t1\t1\t1\t1\t1\t10\t10\t60\t20\t96.5\tRevenue\n" +
+		"5\t1\t1\t1\t1\t2\t80\t10\t40\t20\t91\t2024\n" +
+		"5\t1\t1\t1\t2\t1\t10\t40\t50\t20\t88\tQ1\n" +
+		"5\t1\t2\t1\t1\t1\t10\t100\t50\t20\t90\tTotal\n" +
+		"5\t1\t2\t1\t1\t2\t70\t100\t50\t20\t-1\t \n"
+
+	result := parseTesseractTSV([]byte(tsv))
+	if want := "Revenue 2024\nQ1\n\nTotal"; result.Text != want {
+		t.Errorf("text = %q, want %q", result.Text, want)
+	}
+	if len(result.Words) != 4 {
+		t.Fatalf("got %d words, want 4", len(result.Words))
+	}
+	if w := result.Words[0]; w.Text != "Revenue" || w.Confidence != 96.5 || w.X != 10 || w.Width != 60 {
+		t.Errorf("unexpected first word: %+v", w)
+	}
+}
+
+func TestHTTPEngine(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("Content-Type") != "image/png" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "png-bytes" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"text":"hello world","words":[{"text":"hello","confidence":99}]}`))
+	}))
+	defer server.Close()
+
+	engine, err := New(&config.OCRConfig{Backend: BackendHTTP, APIURL: server.URL, APIKey: "secret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := engine.Recognize(context.Background(), []byte("png-bytes"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Text != "hello world" || len(result.Words) != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	if _, err := New(&config.OCRConfig{Backend: BackendHTTP}); err == nil {
+		t.Error("expected error when api_url is missing")
+	}
+	if _, err := New(&config.OCRConfig{Backend: "paddle"}); err == nil {
+		t.Error("expected error for unknown backend")
+	}
+}
@@ -0,0 +1,115 @@
+package ocr
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tesseract 调用本地 tesseract 命令识别文字
+type Tesseract struct {
+	Path      string        // 可执行文件路径，默认 "tesseract"
+	Languages string        // 识别语言，如 "eng+chi_sim"，默认 eng
+	Timeout   time.Duration // 单次识别超时
+}
+
+// Recognize 通过标准输入传入图片，以 TSV 格式读取单词、置信度和位置
+func (t *Tesseract) Recognize(ctx context.Context, png []byte) (*Result, error) {
+	path := t.Path
+	if path == "" {
+		path = "tesseract"
+	}
+	langs := t.Languages
+	if langs == "" {
+		langs = "eng"
+	}
+	if t.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, path, "stdin", "stdout", "-l", langs, "--psm", "3", "tsv")
+	cmd.Stdin = bytes.NewReader(png)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, fmt.Errorf("tesseract not found (install tesseract or set ocr.tesseract_path): %w", err)
+		}
+		return nil, fmt.Errorf("tesseract failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return parseTesseractTSV(stdout.Bytes()), nil
+}
+
+// parseTesseractTSV 解析 tesseract 的 TSV 输出
+// 列：level page_num block_num par_num line_num word_num left top width height conf text
+// 同一行（block、par、line 相同）的单词以空格连接，段落之间空一行
+func parseTesseractTSV(data []byte) *Result {
+	result := &Result{}
+	var lines []string
+	var current []string
+	lastLine, lastPar := "", ""
+
+	flush := func() {
+		if len(current) > 0 {
+			lines = append(lines, strings.Join(current, " "))
+			current = nil
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	header := true
+	for scanner.Scan() {
+		if header {
+			header = false
+			continue
+		}
+		cols := strings.Split(scanner.Text(), "\t")
+		if len(cols) < 12 || cols[0] != "5" {
+			continue
+		}
+		text := strings.TrimSpace(cols[11])
+		if text == "" {
+			continue
+		}
+
+		par := cols[2] + "/" + cols[3]
+		line := par + "/" + cols[4]
+		if line != lastLine {
+			flush()
+			if lastPar != "" && par != lastPar {
+				lines = append(lines, "")
+			}
+			lastLine, lastPar = line, par
+		}
+		current = append(current, text)
+
+		conf, _ := strconv.ParseFloat(cols[10], 64)
+		result.Words = append(result.Words, Word{
+			Text:       text,
+			Confidence: conf,
+			X:          atoi(cols[6]),
+			Y:          atoi(cols[7]),
+			Width:      atoi(cols[8]),
+			Height:     atoi(cols[9]),
+		})
+	}
+	flush()
+
+	result.Text = strings.Join(lines, "\n")
+	return result
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,248 @@
+package urlpolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// 额外需要拦截的内部网段（net.IP 的内置判断未覆盖的部分）
+var extraInternalNets = mustParseCIDRs(
+	"0.0.0.0/8",     // 本网络
+	"100.64.0.0/10", // 运营商级 NAT
+	"192.0.0.0/24",  // IETF 协议分配
+	"198.18.0.0/15", // 网络基准测试
+)
+
+// 云厂商元数据服务等特殊主机名
+var internalHostnames = []string{
+	"localhost",
+	"metadata.google.internal",
+	"metadata.goog",
+}
+
+// IsInternalIP 判断 IP 是否属于内网、回环、链路本地或元数据地址
+func IsInternalIP(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return true
+	}
+	for _, n := range extraInternalNets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// NetworkGuard 内网访问防护（SSRF 防护）
+// 阻止访问 RFC1918、回环、链路本地（包括 169.254.169.254 元数据服务）等地址
+type NetworkGuard struct {
+	enabled    bool
+	allowHosts []string // 例外主机（域名规则或 IP/CIDR）
+	allowNets  []*net.IPNet
+
+	resolver *net.Resolver
+	cacheTTL time.Duration
+	mu       sync.Mutex
+	cache    map[string]guardCacheEntry
+}
+
+type guardCacheEntry struct {
+	internal bool
+	expires  time.Time
+}
+
+// NewNetworkGuard 创建内网访问防护
+// allow: 允许访问的内网例外，支持域名规则（同 Policy）、IP 或 CIDR
+func NewNetworkGuard(enabled bool, allow []string) *NetworkGuard {
+	g := &NetworkGuard{
+		enabled:  enabled,
+		resolver: net.DefaultResolver,
+		cacheTTL: 30 * time.Second,
+		cache:    make(map[string]guardCacheEntry),
+	}
+	for _, rule := range allow {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		if _, n, err := net.ParseCIDR(rule); err == nil {
+			g.allowNets = append(g.allowNets, n)
+		} else if ip := net.ParseIP(rule); ip != nil {
+			g.allowNets = append(g.allowNets, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+		} else {
+			g.allowHosts = append(g.allowHosts, rule)
+		}
+	}
+	return g
+}
+
+// Enabled 是否启用防护
+func (g *NetworkGuard) Enabled() bool {
+	return g != nil && g.enabled
+}
+
+// Check 检查 URL 是否指向内部网络，是则返回错误
+func (g *NetworkGuard) Check(ctx context.Context, rawURL string) error {
+	if !g.Enabled() {
+		return nil
+	}
+	scheme, host, err := parseURL(rawURL)
+	if err != nil {
+		return err
+	}
+	if host == "" {
+		// file: 会读取服务器本地文件，与访问内网同等对待
+		if scheme == "file" {
+			return fmt.Errorf("url not allowed: file urls access the local filesystem")
+		}
+		return nil
+	}
+	if g.IsInternalHost(ctx, host) {
+		return fmt.Errorf("url not allowed: %s points to an internal network address", host)
+	}
+	return nil
+}
+
+// IsInternalHost 判断主机名是否指向内部网络（会进行 DNS 解析并缓存结果）
+func (g *NetworkGuard) IsInternalHost(ctx context.Context, host string) bool {
+	if !g.Enabled() {
+		return false
+	}
+	host = strings.ToLower(strings.Trim(host, "[]"))
+
+	for _, rule := range g.allowHosts {
+		if MatchDomain(rule, host) {
+			return false
+		}
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		return g.isBlockedIP(ip)
+	}
+
+	for _, name := range internalHostnames {
+		if MatchDomain(name, host) {
+			return true
+		}
+	}
+
+	g.mu.Lock()
+	entry, ok := g.cache[host]
+	g.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.internal
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	addrs, err := g.resolver.LookupIPAddr(lookupCtx, host)
+	if err != nil {
+		// 解析失败时交给浏览器处理（浏览器同样无法访问）
+		return false
+	}
+
+	internal := false
+	for _, addr := range addrs {
+		if g.isBlockedIP(addr.IP) {
+			internal = true
+			break
+		}
+	}
+
+	g.mu.Lock()
+	g.cache[host] = guardCacheEntry{internal: internal, expires: time.Now().Add(g.cacheTTL)}
+	g.mu.Unlock()
+
+	return internal
+}
+
+// isBlockedIP 判断 IP 是否为内部地址且不在例外列表中
+func (g *NetworkGuard) isBlockedIP(ip net.IP) bool {
+	for _, n := range g.allowNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return IsInternalIP(ip)
+}
+
+// checkDialAddress 拨号前检查实际连接的 IP 是否为内网地址
+func (g *NetworkGuard) checkDialAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if g.isBlockedIP(net.ParseIP(host)) {
+		return fmt.Errorf("connection to %s not allowed: internal network address", host)
+	}
+	return nil
+}
+
+// Dialer 创建服务端主动发起的非 HTTP 连接（如 SMTP）使用的拨号器
+// 防护启用时先检查 host，再在连接建立前检查实际拨号的 IP（防止 DNS 重绑定）；host 按域名加入例外列表时不检查 IP
+func (g *NetworkGuard) Dialer(ctx context.Context, host string, timeout time.Duration) (*net.Dialer, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !g.Enabled() {
+		return dialer, nil
+	}
+	if g.IsInternalHost(ctx, host) {
+		return nil, fmt.Errorf("host not allowed: %s points to an internal network address", host)
+	}
+	for _, rule := range g.allowHosts {
+		if MatchDomain(rule, strings.ToLower(host)) {
+			return dialer, nil
+		}
+	}
+	dialer.Control = g.checkDialAddress
+	return dialer, nil
+}
+
+// HTTPClient 创建服务端主动发起请求（如 Webhook）使用的 HTTP 客户端
+// 防护启用时，连接建立前检查实际拨号的 IP（防止 DNS 重绑定），并拒绝重定向到内网地址
+func (g *NetworkGuard) HTTPClient(timeout time.Duration) *http.Client {
+	if !g.Enabled() {
+		return &http.Client{Timeout: timeout}
+	}
+
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: g.checkDialAddress,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil // 经过代理时无法检查实际目标地址
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return g.Check(req.Context(), req.URL.String())
+		},
+	}
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, c := range cidrs {
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}
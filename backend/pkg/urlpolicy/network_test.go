@@ -0,0 +1,138 @@
+package urlpolicy
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestIsInternalIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"127.0.0.1", true},
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"192.168.1.1", true},
+		{"169.254.169.254", true},
+		{"100.64.0.1", true},
+		{"0.0.0.0", true},
+		{"::1", true},
+		{"fd00::1", true},
+		{"8.8.8.8", false},
+		{"2606:4700::1111", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsInternalIP(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("IsInternalIP(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestNetworkGuardCheck(t *testing.T) {
+	guard := NewNetworkGuard(true, []string{"10.0.5.0/24", "192.168.1.10"})
+
+	tests := []struct {
+		name    string
+		guard   *NetworkGuard
+		url     string
+		wantErr bool
+	}{
+		{"nil guard", nil, "http://127.0.0.1", false},
+		{"disabled guard", NewNetworkGuard(false, nil), "http://127.0.0.1", false},
+		{"loopback", guard, "http://127.0.0.1:8080/admin", true},
+		{"localhost", guard, "localhost:3000", true},
+		{"metadata service", guard, "http://169.254.169.254/latest/meta-data", true},
+		{"metadata hostname", guard, "http://metadata.google.internal", true},
+		{"ipv6 loopback", guard, "http://[::1]/", true},
+		{"allowed cidr", guard, "http://10.0.5.20", false},
+		{"allowed ip", guard, "http://192.168.1.10", false},
+		{"other private ip", guard, "http://192.168.1.11", true},
+		{"public ip", guard, "https://1.1.1.1", false},
+		{"about blank", guard, "about:blank", false},
+		{"file url", guard, "file:///etc/passwd", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.guard.Check(context.Background(), tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Check(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNetworkGuardHTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	if _, err := NewNetworkGuard(true, nil).HTTPClient(time.Second).Get(server.URL); err == nil {
+		t.Error("expected request to loopback server to be blocked")
+	}
+
+	resp, err := NewNetworkGuard(true, []string{"127.0.0.1"}).HTTPClient(time.Second).Get(server.URL)
+	if err != nil {
+		t.Fatalf("allowed address was blocked: %v", err)
+	}
+	resp.Body.Close()
+
+	resp, err = NewNetworkGuard(false, nil).HTTPClient(time.Second).Get(server.URL)
+	if err != nil {
+		t.Fatalf("disabled guard blocked request: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestNetworkGuardDialer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	addr := listener.Addr().String()
+	ctx := context.Background()
+
+	if _, err := NewNetworkGuard(true, nil).Dialer(ctx, "127.0.0.1", time.Second); err == nil {
+		t.Error("expected loopback host to be blocked")
+	}
+	if _, err := NewNetworkGuard(true, nil).Dialer(ctx, "localhost", time.Second); err == nil {
+		t.Error("expected localhost to be blocked")
+	}
+	for _, guard := range []*NetworkGuard{NewNetworkGuard(true, []string{"127.0.0.1"}), NewNetworkGuard(false, nil)} {
+		dialer, err := guard.Dialer(ctx, "127.0.0.1", time.Second)
+		if err != nil {
+			t.Fatalf("allowed host was blocked: %v", err)
+		}
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			t.Fatalf("allowed address was blocked: %v", err)
+		}
+		conn.Close()
+	}
+
+	// 域名不在例外列表中时，拨号时仍检查实际连接的 IP
+	dialer, err := NewNetworkGuard(true, nil).Dialer(ctx, "smtp.example.com", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dialer.DialContext(ctx, "tcp", addr); err == nil {
+		t.Error("expected connection to loopback address to be blocked")
+	}
+}
@@ -0,0 +1,179 @@
+package urlpolicy
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Policy URL 访问策略（域名白名单/黑名单）
+// 规则格式：
+//   - example.com    匹配 example.com 及其所有子域名
+//   - *.example.com  仅匹配子域名，不匹配 example.com 本身
+//   - *              匹配所有域名
+//
+// Deny 优先于 Allow；Allow 为空表示不限制（仅应用 Deny）
+//
+// file:、javascript:、data:、chrome: 等非网络协议的 URL 无法按域名匹配，
+// 只有协议在 Schemes 中时才允许访问（Schemes 为空时使用 DefaultHostlessSchemes）
+type Policy struct {
+	Allow   []string `json:"allow,omitempty" toml:"allow,omitempty"`     // 允许访问的域名列表
+	Deny    []string `json:"deny,omitempty" toml:"deny,omitempty"`       // 禁止访问的域名列表
+	Schemes []string `json:"schemes,omitempty" toml:"schemes,omitempty"` // 允许访问的非网络协议，例如 "about"、"data"
+}
+
+// DefaultHostlessSchemes 未配置 Schemes 时允许的非网络协议（仅 about:blank 等空白页）
+var DefaultHostlessSchemes = []string{"about"}
+
+// IsEmpty 判断策略是否为空（不做任何限制）
+func (p *Policy) IsEmpty() bool {
+	return p == nil || (len(p.Allow) == 0 && len(p.Deny) == 0 && len(p.Schemes) == 0)
+}
+
+// Check 检查 URL 是否允许访问，不允许时返回错误
+func (p *Policy) Check(rawURL string) error {
+	if p.IsEmpty() {
+		return nil
+	}
+
+	scheme, host, err := parseURL(rawURL)
+	if err != nil {
+		return err
+	}
+	// 非网络协议或没有主机名的 URL 不能通过域名规则放行，只看协议是否在允许列表中
+	if host == "" || !isNetworkScheme(scheme) {
+		if p.allowsScheme(scheme) {
+			return nil
+		}
+		return fmt.Errorf("url not allowed: scheme %q is not in the allowed schemes", scheme)
+	}
+
+	for _, rule := range p.Deny {
+		if MatchDomain(rule, host) {
+			return fmt.Errorf("url not allowed: host %s is denied by rule %q", host, rule)
+		}
+	}
+
+	if len(p.Allow) == 0 {
+		return nil
+	}
+	for _, rule := range p.Allow {
+		if MatchDomain(rule, host) {
+			return nil
+		}
+	}
+	return fmt.Errorf("url not allowed: host %s is not in the allow list", host)
+}
+
+// MatchDomain 判断主机名是否匹配域名规则
+func MatchDomain(rule, host string) bool {
+	rule = strings.ToLower(strings.TrimSpace(rule))
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if rule == "" {
+		return false
+	}
+	if rule == "*" {
+		return true
+	}
+	if strings.HasPrefix(rule, "*.") {
+		return strings.HasSuffix(host, rule[1:])
+	}
+	return host == rule || strings.HasSuffix(host, "."+rule)
+}
+
+// Merge 合并多个策略（忽略空策略），任一策略拒绝即拒绝
+func Merge(policies ...*Policy) Chain {
+	chain := make(Chain, 0, len(policies))
+	for _, p := range policies {
+		if !p.IsEmpty() {
+			chain = append(chain, p)
+		}
+	}
+	return chain
+}
+
+// Chain 多个策略的组合，所有策略都通过才允许访问
+type Chain []*Policy
+
+// Check 依次检查所有策略
+func (c Chain) Check(rawURL string) error {
+	for _, p := range c {
+		if err := p.Check(rawURL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isNetworkScheme 判断协议是否按主机名访问网络（可以用域名规则匹配）
+func isNetworkScheme(scheme string) bool {
+	switch scheme {
+	case "http", "https", "ws", "wss":
+		return true
+	}
+	return false
+}
+
+// allowsScheme 判断非网络的协议是否允许访问
+func (p *Policy) allowsScheme(scheme string) bool {
+	schemes := p.Schemes
+	if len(schemes) == 0 {
+		schemes = DefaultHostlessSchemes
+	}
+	for _, s := range schemes {
+		if strings.EqualFold(strings.TrimSuffix(strings.TrimSpace(s), ":"), scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseURL 解析 URL 中的协议和主机名
+func parseURL(rawURL string) (string, string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	// 兼容未带协议的地址，例如 "example.com/path"、"localhost:8080"
+	if !strings.Contains(rawURL, "://") && !hasOpaqueScheme(rawURL) {
+		rawURL = "http://" + rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid url %q: %w", rawURL, err)
+	}
+	return strings.ToLower(u.Scheme), strings.ToLower(u.Hostname()), nil
+}
+
+// hasOpaqueScheme 判断是否为 about:、data: 等不含主机名的协议
+func hasOpaqueScheme(rawURL string) bool {
+	lower := strings.ToLower(rawURL)
+	for _, scheme := range []string{"about:", "data:", "javascript:", "blob:", "chrome:"} {
+		if strings.HasPrefix(lower, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// context key
+type contextKey string
+
+const policyKey contextKey = "url_policy"
+
+// WithPolicy 将调用方（如 API Key）的策略添加到 context
+func WithPolicy(ctx context.Context, p *Policy) context.Context {
+	if p.IsEmpty() {
+		return ctx
+	}
+	return context.WithValue(ctx, policyKey, p)
+}
+
+// FromContext 从 context 中获取调用方策略，不存在时返回 nil
+func FromContext(ctx context.Context) *Policy {
+	if ctx == nil {
+		return nil
+	}
+	if p, ok := ctx.Value(policyKey).(*Policy); ok {
+		return p
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package urlpolicy
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMatchDomain(t *testing.T) {
+	tests := []struct {
+		rule string
+		host string
+		want bool
+	}{
+		{"example.com", "example.com", true},
+		{"example.com", "www.example.com", true},
+		{"example.com", "badexample.com", false},
+		{"*.example.com", "example.com", false},
+		{"*.example.com", "a.b.example.com", true},
+		{"*", "anything.org", true},
+		{"Example.COM", "example.com.", true},
+		{"", "example.com", false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchDomain(tt.rule, tt.host); got != tt.want {
+			t.Errorf("MatchDomain(%q, %q) = %v, want %v", tt.rule, tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestPolicyCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *Policy
+		url     string
+		wantErr bool
+	}{
+		{"nil policy", nil, "http://10.0.0.1", false},
+		{"allowed host", &Policy{Allow: []string{"example.com"}}, "https://www.example.com/a", false},
+		{"not in allow list", &Policy{Allow: []string{"example.com"}}, "https://other.com", true},
+		{"deny wins over allow", &Policy{Allow: []string{"*"}, Deny: []string{"internal.corp"}}, "http://db.internal.corp", true},
+		{"deny only", &Policy{Deny: []string{"evil.com"}}, "https://good.com", false},
+		{"scheme-less url", &Policy{Allow: []string{"example.com"}}, "example.com/path", false},
+		{"host with port", &Policy{Deny: []string{"localhost"}}, "localhost:8080", true},
+		{"about blank allowed by default", &Policy{Allow: []string{"example.com"}}, "about:blank", false},
+		{"file url rejected", &Policy{Allow: []string{"example.com"}}, "file:///etc/passwd", true},
+		{"file url rejected by deny-only policy", &Policy{Deny: []string{"evil.com"}}, "file:///etc/passwd", true},
+		{"javascript url rejected", &Policy{Allow: []string{"*"}}, "javascript:alert(document.cookie)", true},
+		{"data url rejected", &Policy{Allow: []string{"example.com"}}, "data:text/html,<script>1</script>", true},
+		{"chrome url rejected", &Policy{Deny: []string{"evil.com"}}, "chrome://settings", true},
+		{"explicit scheme allowed", &Policy{Allow: []string{"example.com"}, Schemes: []string{"data"}}, "data:text/plain,hi", false},
+		{"explicit schemes replace default", &Policy{Allow: []string{"example.com"}, Schemes: []string{"data"}}, "about:blank", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Check(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Check(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMergeAndContext(t *testing.T) {
+	instance := &Policy{Allow: []string{"example.com", "partner.com"}}
+	key := &Policy{Allow: []string{"example.com"}}
+
+	ctx := WithPolicy(context.Background(), key)
+	chain := Merge(instance, FromContext(ctx), nil)
+
+	if err := chain.Check("https://example.com"); err != nil {
+		t.Errorf("expected example.com to be allowed, got %v", err)
+	}
+	if err := chain.Check("https://partner.com"); err == nil {
+		t.Errorf("expected partner.com to be denied by key policy")
+	}
+	if FromContext(context.Background()) != nil {
+		t.Errorf("expected nil policy from empty context")
+	}
+}
@@ -0,0 +1,158 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/storage"
+	"github.com/robfig/cron/v3"
+)
+
+// cronParser 与调度器使用的解析规则一致（cron.WithSeconds）
+var cronParser = cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+const (
+	// defaultRunDuration 没有执行记录时日历中使用的预计耗时
+	defaultRunDuration = time.Minute
+	// durationSamples 估算耗时时使用的最近执行次数
+	durationSamples = 10
+)
+
+// NextRuns 计算任务在 [from, to) 内的计划执行时间，最多返回 limit 个，第二个返回值表示是否因 limit 被截断
+// 固定间隔任务以调度器记录的下次执行时间为基准推算，未调度过时从 from 开始推算
+func NextRuns(task *models.ScheduledTask, from, to time.Time, limit int) ([]time.Time, bool, error) {
+	var runs []time.Time
+	add := func(t time.Time) bool {
+		if len(runs) == limit {
+			return false
+		}
+		runs = append(runs, t)
+		return true
+	}
+
+	switch task.ScheduleType {
+	case models.ScheduleTypeAt:
+		at, err := time.Parse(time.RFC3339, task.ScheduleConfig)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid at time format: %w", err)
+		}
+		if !at.Before(from) && at.Before(to) && !add(at) {
+			return runs, true, nil
+		}
+	case models.ScheduleTypeEvery:
+		interval, err := time.ParseDuration(task.ScheduleConfig)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid every duration format: %w", err)
+		}
+		if interval < time.Second {
+			return nil, false, fmt.Errorf("interval %s is too short", interval)
+		}
+		next := from
+		if task.NextExecutionTime != nil {
+			next = *task.NextExecutionTime
+			if next.Before(from) {
+				next = next.Add(from.Sub(next).Truncate(interval))
+				if next.Before(from) {
+					next = next.Add(interval)
+				}
+			}
+		}
+		for ; next.Before(to); next = next.Add(interval) {
+			if !add(next) {
+				return runs, true, nil
+			}
+		}
+	case models.ScheduleTypeCron:
+		schedule, err := cronParser.Parse(task.ScheduleConfig)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid cron expression: %w", err)
+		}
+		// Next 返回严格晚于参数的时间
+		for next := schedule.Next(from.Add(-time.Nanosecond)); !next.IsZero() && next.Before(to); next = schedule.Next(next) {
+			if !add(next) {
+				return runs, true, nil
+			}
+		}
+	default:
+		return nil, false, fmt.Errorf("unknown schedule type: %s", task.ScheduleType)
+	}
+	return runs, false, nil
+}
+
+// Calendar 列出已启用任务在 [from, to) 内的计划执行，按开始时间排序
+// taskID 不为空时只列出该任务；每个任务最多 limitPerTask 次，第二个返回值表示是否有任务被截断
+func Calendar(db *storage.BoltDB, taskID string, from, to time.Time, limitPerTask int) ([]models.ScheduledRun, bool, error) {
+	tasks, err := db.ListScheduledTasks()
+	if err != nil {
+		return nil, false, err
+	}
+	executions, err := db.ListTaskExecutions()
+	if err != nil {
+		return nil, false, err
+	}
+	durations := estimateDurations(executions)
+
+	runs := []models.ScheduledRun{}
+	truncated := false
+	for i := range tasks {
+		task := &tasks[i]
+		if !task.Enabled || (taskID != "" && task.ID != taskID) {
+			continue
+		}
+		times, cut, err := NextRuns(task, from, to, limitPerTask)
+		if err != nil {
+			// 配置无效的任务不会被调度，日历中也不显示
+			continue
+		}
+		truncated = truncated || cut
+
+		duration, ok := durations[task.ID]
+		if !ok {
+			duration = defaultRunDuration
+		}
+		for _, start := range times {
+			runs = append(runs, models.ScheduledRun{
+				TaskID:        task.ID,
+				TaskName:      task.Name,
+				Description:   task.Description,
+				ExecutionType: task.ExecutionType,
+				ScriptID:      task.ScriptID,
+				ScriptName:    task.ScriptName,
+				ScheduleType:  task.ScheduleType,
+				Schedule:      task.ScheduleConfig,
+				StartTime:     start,
+				EndTime:       start.Add(duration),
+			})
+		}
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].StartTime.Before(runs[j].StartTime)
+	})
+	return runs, truncated, nil
+}
+
+// estimateDurations 按每个任务最近几次执行的平均耗时估算执行时长（执行记录按开始时间降序）
+func estimateDurations(executions []models.TaskExecution) map[string]time.Duration {
+	totals := make(map[string]int64)
+	counts := make(map[string]int64)
+	for _, execution := range executions {
+		if counts[execution.TaskID] == durationSamples {
+			continue
+		}
+		totals[execution.TaskID] += execution.Duration
+		counts[execution.TaskID]++
+	}
+
+	durations := make(map[string]time.Duration, len(counts))
+	for taskID, count := range counts {
+		duration := time.Duration(totals[taskID]/count) * time.Millisecond
+		if duration < defaultRunDuration {
+			duration = defaultRunDuration
+		}
+		durations[taskID] = duration.Round(time.Second)
+	}
+	return durations
+}
@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+func TestNextRuns(t *testing.T) {
+	from := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	anchor := from.Add(-25 * time.Minute)
+
+	tests := []struct {
+		name      string
+		task      models.ScheduledTask
+		limit     int
+		first     time.Time
+		count     int
+		truncated bool
+	}{
+		{"at inside window", models.ScheduledTask{ScheduleType: models.ScheduleTypeAt, ScheduleConfig: "2026-03-04T12:00:00Z"}, 10, time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC), 1, false},
+		{"at outside window", models.ScheduledTask{ScheduleType: models.ScheduleTypeAt, ScheduleConfig: "2026-03-06T12:00:00Z"}, 10, time.Time{}, 0, false},
+		{"every from anchor", models.ScheduledTask{ScheduleType: models.ScheduleTypeEvery, ScheduleConfig: "1h", NextExecutionTime: &anchor}, 100, from.Add(35 * time.Minute), 24, false},
+		{"every limited", models.ScheduledTask{ScheduleType: models.ScheduleTypeEvery, ScheduleConfig: "10m"}, 5, from, 5, true},
+		{"cron with seconds", models.ScheduledTask{ScheduleType: models.ScheduleTypeCron, ScheduleConfig: "0 30 9 * * *"}, 10, time.Date(2026, 3, 5, 9, 30, 0, 0, time.UTC), 1, false},
+		{"cron at window start", models.ScheduledTask{ScheduleType: models.ScheduleTypeCron, ScheduleConfig: "0 0 */6 * * *"}, 10, from.Add(2 * time.Hour), 4, false},
+		{"cron descriptor", models.ScheduledTask{ScheduleType: models.ScheduleTypeCron, ScheduleConfig: "@hourly"}, 10, from, 10, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs, truncated, err := NextRuns(&tt.task, from, to, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(runs) != tt.count || truncated != tt.truncated {
+				t.Fatalf("got %d runs (truncated %v), want %d (truncated %v)", len(runs), truncated, tt.count, tt.truncated)
+			}
+			if tt.count > 0 && !runs[0].Equal(tt.first) {
+				t.Errorf("first run = %v, want %v", runs[0], tt.first)
+			}
+		})
+	}
+
+	if _, _, err := NextRuns(&models.ScheduledTask{ScheduleType: models.ScheduleTypeCron, ScheduleConfig: "* * *"}, from, to, 10); err == nil {
+		t.Error("expected an error for an invalid cron expression")
+	}
+}
+
+func TestEstimateDurations(t *testing.T) {
+	durations := estimateDurations([]models.TaskExecution{
+		{TaskID: "slow", Duration: 150000},
+		{TaskID: "slow", Duration: 90000},
+		{TaskID: "fast", Duration: 800},
+	})
+	if durations["slow"] != 2*time.Minute {
+		t.Errorf("slow = %v, want 2m", durations["slow"])
+	}
+	if durations["fast"] != defaultRunDuration {
+		t.Errorf("fast = %v, want the minimum duration", durations["fast"])
+	}
+}
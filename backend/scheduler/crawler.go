@@ -0,0 +1,289 @@
+package scheduler
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+const (
+	defaultCrawlConcurrency = 2
+	maxCrawlConcurrency     = 10
+	defaultCrawlMaxURLs     = 100
+	maxSitemapSize          = 50 << 20 // sitemap 协议规定单个文件不超过 50MB
+	maxSitemapDepth         = 2        // sitemap 索引最多展开的层数
+)
+
+// URLPolicyChecker URL 访问策略检查（由浏览器管理器提供）
+type URLPolicyChecker interface {
+	CheckURLPolicy(ctx context.Context, instanceID string, rawURL string) error
+}
+
+// CrawlPageResult 单个 URL 的抓取结果
+type CrawlPageResult struct {
+	URL      string                 `json:"url"`
+	Status   string                 `json:"status"` // success, failed
+	Error    string                 `json:"error,omitempty"`
+	Duration int64                  `json:"duration"` // 耗时（毫秒）
+	Data     map[string]interface{} `json:"data,omitempty"`
+}
+
+// ExecuteCrawl 执行批量抓取任务：对种子 URL 和 sitemap 中的每个 URL 执行同一个脚本，汇总为一个数据集
+func (e *DefaultTaskExecutor) ExecuteCrawl(ctx context.Context, task *models.ScheduledTask) (map[string]interface{}, error) {
+	if task.ScriptID == "" {
+		return nil, fmt.Errorf("script ID is empty")
+	}
+
+	urls, err := e.collectCrawlURLs(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no URLs to crawl")
+	}
+
+	concurrency := task.CrawlConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultCrawlConcurrency
+	}
+	if concurrency > maxCrawlConcurrency {
+		concurrency = maxCrawlConcurrency
+	}
+
+	log.Printf("[TaskExecutor] Crawling %d URLs with script %s (concurrency: %d)", len(urls), task.ScriptID, concurrency)
+
+	results := make([]CrawlPageResult, len(urls))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, u := range urls {
+		select {
+		case <-ctx.Done():
+			results[i] = CrawlPageResult{URL: u, Status: "failed", Error: ctx.Err().Error()}
+			continue
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(i int, u string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = e.crawlURL(ctx, task, u)
+		}(i, u)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, r := range results {
+		if r.Status == "success" {
+			succeeded++
+		}
+	}
+	failed := len(results) - succeeded
+
+	log.Printf("[TaskExecutor] Crawl task %s finished: %d succeeded, %d failed", task.Name, succeeded, failed)
+
+	resultData := map[string]interface{}{
+		"dataset":   results,
+		"total":     len(results),
+		"succeeded": succeeded,
+		"failed":    failed,
+	}
+	if succeeded == 0 {
+		return resultData, fmt.Errorf("all %d URLs failed", failed)
+	}
+	return resultData, nil
+}
+
+// crawlURL 对单个 URL 执行抓取脚本
+func (e *DefaultTaskExecutor) crawlURL(ctx context.Context, task *models.ScheduledTask, targetURL string) CrawlPageResult {
+	start := time.Now()
+	result := CrawlPageResult{URL: targetURL, Status: "failed"}
+
+	// 任务被取消或超时后不再启动新的回放
+	if err := ctx.Err(); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	playResult, err := e.playTaskScript(ctx, task, targetURL)
+	result.Duration = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		log.Printf("[TaskExecutor] Failed to crawl %s: %v", targetURL, err)
+		return result
+	}
+
+	result.Data = playResult.ExtractedData
+	if !playResult.Success {
+		result.Error = playResult.Message
+		return result
+	}
+	result.Status = "success"
+	return result
+}
+
+// collectCrawlURLs 汇总种子 URL 和 sitemap 中的 URL（去重，受最大数量限制）
+func (e *DefaultTaskExecutor) collectCrawlURLs(ctx context.Context, task *models.ScheduledTask) ([]string, error) {
+	maxURLs := task.CrawlMaxURLs
+	if maxURLs <= 0 {
+		maxURLs = defaultCrawlMaxURLs
+	}
+
+	seen := make(map[string]bool)
+	urls := make([]string, 0)
+	add := func(u string) {
+		u = strings.TrimSpace(u)
+		if u == "" || seen[u] || len(urls) >= maxURLs {
+			return
+		}
+		seen[u] = true
+		urls = append(urls, u)
+	}
+
+	for _, u := range task.CrawlURLs {
+		add(u)
+	}
+
+	if task.CrawlSitemapURL != "" && len(urls) < maxURLs {
+		checker, _ := e.scriptPlayer.(URLPolicyChecker)
+		sitemapURLs, err := fetchSitemapURLs(ctx, task.CrawlSitemapURL, maxURLs, func(u string) error {
+			if checker == nil {
+				return nil
+			}
+			return checker.CheckURLPolicy(ctx, task.BrowserInstanceID, u)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load sitemap: %w", err)
+		}
+		for _, u := range sitemapURLs {
+			add(u)
+		}
+	}
+
+	return urls, nil
+}
+
+// sitemapDocument sitemap.xml（urlset）或 sitemap 索引（sitemapindex）
+type sitemapDocument struct {
+	XMLName  xml.Name
+	URLs     []sitemapLoc `xml:"url"`
+	Sitemaps []sitemapLoc `xml:"sitemap"`
+}
+
+type sitemapLoc struct {
+	Loc string `xml:"loc"`
+}
+
+// parseSitemap 解析 sitemap，返回页面 URL 和子 sitemap URL
+func parseSitemap(data []byte) (pages []string, sitemaps []string, err error) {
+	var doc sitemapDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("invalid sitemap: %w", err)
+	}
+	for _, u := range doc.URLs {
+		if loc := strings.TrimSpace(u.Loc); loc != "" {
+			pages = append(pages, loc)
+		}
+	}
+	for _, s := range doc.Sitemaps {
+		if loc := strings.TrimSpace(s.Loc); loc != "" {
+			sitemaps = append(sitemaps, loc)
+		}
+	}
+	return pages, sitemaps, nil
+}
+
+// fetchSitemapURLs 下载 sitemap 并展开 sitemap 索引，最多返回 limit 个页面 URL
+// check 用于在下载前检查 sitemap 地址是否允许访问
+func fetchSitemapURLs(ctx context.Context, sitemapURL string, limit int, check func(string) error) ([]string, error) {
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return fmt.Errorf("too many redirects")
+			}
+			return check(req.URL.String())
+		},
+	}
+	var pages []string
+
+	var walk func(u string, depth int) error
+	walk = func(u string, depth int) error {
+		if err := check(u); err != nil {
+			return err
+		}
+
+		data, err := downloadSitemap(ctx, client, u)
+		if err != nil {
+			return err
+		}
+
+		found, children, err := parseSitemap(data)
+		if err != nil {
+			return err
+		}
+		for _, p := range found {
+			if len(pages) >= limit {
+				return nil
+			}
+			pages = append(pages, p)
+		}
+
+		if depth >= maxSitemapDepth {
+			return nil
+		}
+		for _, child := range children {
+			if len(pages) >= limit {
+				return nil
+			}
+			if err := walk(child, depth+1); err != nil {
+				log.Printf("[TaskExecutor] Failed to load child sitemap %s: %v", child, err)
+			}
+		}
+		return nil
+	}
+
+	if err := walk(sitemapURL, 0); err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+// downloadSitemap 下载 sitemap 内容，支持 .gz 压缩文件
+func downloadSitemap(ctx context.Context, client *http.Client, sitemapURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sitemapURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d for %s", resp.StatusCode, sitemapURL)
+	}
+
+	var reader io.Reader = resp.Body
+	if strings.HasSuffix(strings.ToLower(req.URL.Path), ".gz") {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress sitemap: %w", err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	return io.ReadAll(io.LimitReader(reader, maxSitemapSize))
+}
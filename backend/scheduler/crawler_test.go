@@ -0,0 +1,81 @@
+package scheduler
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+func TestParseSitemap(t *testing.T) {
+	urlset := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>https://example.com/a</loc><lastmod>2024-01-01</lastmod></url>
+  <url><loc> https://example.com/b </loc></url>
+</urlset>`)
+	pages, sitemaps, err := parseSitemap(urlset)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"https://example.com/a", "https://example.com/b"}; !reflect.DeepEqual(pages, want) {
+		t.Errorf("pages = %v, want %v", pages, want)
+	}
+	if len(sitemaps) != 0 {
+		t.Errorf("expected no child sitemaps, got %v", sitemaps)
+	}
+
+	index := []byte(`<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>https://example.com/sitemap-1.xml</loc></sitemap>
+</sitemapindex>`)
+	pages, sitemaps, err = parseSitemap(index)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pages) != 0 || !reflect.DeepEqual(sitemaps, []string{"https://example.com/sitemap-1.xml"}) {
+		t.Errorf("pages = %v, sitemaps = %v", pages, sitemaps)
+	}
+
+	if _, _, err := parseSitemap([]byte("not xml")); err == nil {
+		t.Errorf("expected error for invalid sitemap")
+	}
+}
+
+// cancellingPlayer 在第一次回放后取消任务上下文
+type cancellingPlayer struct {
+	cancel context.CancelFunc
+	played []string
+}
+
+func (p *cancellingPlayer) PlayScript(ctx context.Context, scriptID string, variables map[string]string, instanceID string) (*models.PlayResult, error) {
+	return p.PlayScriptOnURL(ctx, scriptID, "", variables, instanceID)
+}
+
+func (p *cancellingPlayer) PlayScriptOnURL(ctx context.Context, scriptID string, targetURL string, variables map[string]string, instanceID string) (*models.PlayResult, error) {
+	p.played = append(p.played, targetURL)
+	p.cancel()
+	return &models.PlayResult{Success: true}, nil
+}
+
+func TestExecuteCrawlStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	player := &cancellingPlayer{cancel: cancel}
+	e := NewDefaultTaskExecutor(nil, player, nil)
+
+	task := &models.ScheduledTask{
+		ScriptID:         "script",
+		CrawlURLs:        []string{"https://example.com/a", "https://example.com/b", "https://example.com/c"},
+		CrawlConcurrency: 1,
+	}
+	data, err := e.ExecuteCrawl(ctx, task)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(player.played) != 1 {
+		t.Errorf("played %v after cancellation, want only the first URL", player.played)
+	}
+	if data["failed"] != 2 {
+		t.Errorf("failed = %v, want 2", data["failed"])
+	}
+}
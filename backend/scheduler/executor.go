@@ -9,13 +9,21 @@ import (
 
 	"github.com/browserwing/browserwing/agent"
 	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/urlpolicy"
 	"github.com/browserwing/browserwing/storage"
 	"github.com/go-rod/rod"
 )
 
 // ScriptPlayer 脚本播放器接口
 type ScriptPlayer interface {
-	PlayScript(scriptID string, variables map[string]string, instanceID string) (*models.PlayResult, error)
+	PlayScript(ctx context.Context, scriptID string, variables map[string]string, instanceID string) (*models.PlayResult, error)
+	// PlayScriptOnURL 在指定 URL 上播放脚本（覆盖脚本的起始 URL，用于批量抓取）
+	PlayScriptOnURL(ctx context.Context, scriptID string, targetURL string, variables map[string]string, instanceID string) (*models.PlayResult, error)
+}
+
+// EnvironmentScriptPlayer 支持在指定执行环境中播放脚本的播放器
+type EnvironmentScriptPlayer interface {
+	PlayScriptInEnvironment(ctx context.Context, scriptID string, targetURL string, environment string, variables map[string]string, instanceID string) (*models.PlayResult, error)
 }
 
 // AgentExecutor Agent 执行器接口
@@ -48,16 +56,37 @@ func (e *DefaultTaskExecutor) ExecuteScript(ctx context.Context, task *models.Sc
 	log.Printf("[TaskExecutor] Executing script task: %s (script: %s)", task.Name, task.ScriptID)
 
 	// 执行脚本
-	result, err := e.scriptPlayer.PlayScript(task.ScriptID, task.ScriptVariables, task.BrowserInstanceID)
+	result, err := e.playTaskScript(ctx, task, "")
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute script: %w", err)
 	}
 
+	// 附带性能数据，便于性能监控任务查看每次执行的指标
+	data := result.ExtractedData
+	if result.Performance != nil {
+		if data == nil {
+			data = make(map[string]interface{})
+		}
+		data["performance"] = result.Performance
+	}
+
 	if !result.Success {
-		return result.ExtractedData, fmt.Errorf("script execution failed: %s", result.Message)
+		return data, fmt.Errorf("script execution failed: %s", result.Message)
 	}
 
-	return result.ExtractedData, nil
+	return data, nil
+}
+
+// playTaskScript 执行任务的脚本，targetURL 为空时使用脚本自身的 URL；任务指定了执行环境时在该环境中执行
+func (e *DefaultTaskExecutor) playTaskScript(ctx context.Context, task *models.ScheduledTask, targetURL string) (*models.PlayResult, error) {
+	if task.Environment == "" {
+		return e.scriptPlayer.PlayScriptOnURL(ctx, task.ScriptID, targetURL, task.ScriptVariables, task.BrowserInstanceID)
+	}
+	player, ok := e.scriptPlayer.(EnvironmentScriptPlayer)
+	if !ok {
+		return nil, fmt.Errorf("script player does not support execution environments")
+	}
+	return player.PlayScriptInEnvironment(ctx, task.ScriptID, targetURL, task.Environment, task.ScriptVariables, task.BrowserInstanceID)
 }
 
 // ExecuteAgent 执行 Agent 任务
@@ -115,7 +144,34 @@ func NewRealScriptPlayer(db *storage.BoltDB, browserManager interface{}) *RealSc
 }
 
 // PlayScript 播放脚本
-func (p *RealScriptPlayer) PlayScript(scriptID string, variables map[string]string, instanceID string) (result *models.PlayResult, err error) {
+func (p *RealScriptPlayer) PlayScript(ctx context.Context, scriptID string, variables map[string]string, instanceID string) (*models.PlayResult, error) {
+	return p.PlayScriptOnURL(ctx, scriptID, "", variables, instanceID)
+}
+
+// CheckURLPolicy 检查 URL 访问策略（浏览器管理器不支持时不做限制）
+func (p *RealScriptPlayer) CheckURLPolicy(ctx context.Context, instanceID string, rawURL string) error {
+	if checker, ok := p.browserManager.(URLPolicyChecker); ok {
+		return checker.CheckURLPolicy(ctx, instanceID, rawURL)
+	}
+	return nil
+}
+
+// NetworkGuard 获取浏览器管理器的内网访问防护（不支持时返回 nil，表示不限制）
+func (p *RealScriptPlayer) NetworkGuard() *urlpolicy.NetworkGuard {
+	if provider, ok := p.browserManager.(NetworkGuardProvider); ok {
+		return provider.NetworkGuard()
+	}
+	return nil
+}
+
+// PlayScriptOnURL 在指定 URL 上播放脚本，targetURL 为空时使用脚本自身的 URL
+func (p *RealScriptPlayer) PlayScriptOnURL(ctx context.Context, scriptID string, targetURL string, variables map[string]string, instanceID string) (*models.PlayResult, error) {
+	return p.PlayScriptInEnvironment(ctx, scriptID, targetURL, "", variables, instanceID)
+}
+
+// PlayScriptInEnvironment 在指定执行环境中播放脚本，environment 为空时不使用执行环境
+// 同名的执行环境存在时应用其基础 URL 和变量，否则环境名只作为执行标签
+func (p *RealScriptPlayer) PlayScriptInEnvironment(ctx context.Context, scriptID string, targetURL string, environment string, variables map[string]string, instanceID string) (result *models.PlayResult, err error) {
 	// 添加 recover 捕获 panic
 	defer func() {
 		if r := recover(); r != nil {
@@ -125,8 +181,6 @@ func (p *RealScriptPlayer) PlayScript(scriptID string, variables map[string]stri
 		}
 	}()
 
-	ctx := context.Background()
-
 	// 获取脚本
 	script, err := p.db.GetScript(scriptID)
 	if err != nil {
@@ -135,31 +189,19 @@ func (p *RealScriptPlayer) PlayScript(scriptID string, variables map[string]stri
 
 	log.Printf("[RealScriptPlayer] Playing script: %s (ID: %s)", script.Name, scriptID)
 
-	// 类型断言获取 browserManager（使用接口定义避免循环依赖）
-	type browserMgr interface {
-		IsRunning() bool
-		Start(ctx context.Context) error
-		PlayScript(ctx context.Context, script *models.Script, instanceID string) (*models.PlayResult, *rod.Page, error)
-		CloseActivePage(ctx context.Context, page *rod.Page) error
-	}
-
-	bm, ok := p.browserManager.(browserMgr)
-	if !ok {
-		// 记录详细错误信息帮助调试
-		log.Printf("[RealScriptPlayer] ERROR: Browser manager type assertion failed. Type: %T", p.browserManager)
-		return nil, fmt.Errorf("invalid browser manager type: %T", p.browserManager)
-	}
-
-	// 确保浏览器正在运行
-	if !bm.IsRunning() {
-		log.Printf("[RealScriptPlayer] Browser not running, starting...")
-		if err := bm.Start(ctx); err != nil {
-			return nil, fmt.Errorf("failed to start browser: %w", err)
-		}
-	}
-
 	// 创建脚本副本并替换变量
 	scriptToRun := script.Copy()
+	if environment != "" {
+		scriptToRun.Environment = environment
+		if env, err := p.db.GetEnvironmentByName(environment); err == nil {
+			if err := env.Apply(scriptToRun); err != nil {
+				return nil, fmt.Errorf("failed to apply environment %s: %w", environment, err)
+			}
+		}
+	}
+	if targetURL != "" {
+		scriptToRun.URL = targetURL
+	}
 
 	// 合并参数：先使用脚本预设变量，再用外部传入的参数覆盖
 	mergedParams := make(map[string]string)
@@ -175,12 +217,12 @@ func (p *RealScriptPlayer) PlayScript(scriptID string, variables map[string]stri
 	// 替换占位符
 	if len(mergedParams) > 0 {
 		scriptToRun.URL = replacePlaceholders(scriptToRun.URL, mergedParams)
-		scriptToRun.Actions = make([]models.ScriptAction, len(script.Actions))
-		copy(scriptToRun.Actions, script.Actions)
 
 		for i := range scriptToRun.Actions {
 			scriptToRun.Actions[i].Selector = replacePlaceholders(scriptToRun.Actions[i].Selector, mergedParams)
 			scriptToRun.Actions[i].XPath = replacePlaceholders(scriptToRun.Actions[i].XPath, mergedParams)
+			scriptToRun.Actions[i].TargetSelector = replacePlaceholders(scriptToRun.Actions[i].TargetSelector, mergedParams)
+			scriptToRun.Actions[i].TargetXPath = replacePlaceholders(scriptToRun.Actions[i].TargetXPath, mergedParams)
 			scriptToRun.Actions[i].Value = replacePlaceholders(scriptToRun.Actions[i].Value, mergedParams)
 			scriptToRun.Actions[i].URL = replacePlaceholders(scriptToRun.Actions[i].URL, mergedParams)
 			scriptToRun.Actions[i].JSCode = replacePlaceholders(scriptToRun.Actions[i].JSCode, mergedParams)
@@ -195,8 +237,98 @@ func (p *RealScriptPlayer) PlayScript(scriptID string, variables map[string]stri
 		}
 	}
 
+	return p.playScript(ctx, scriptToRun, instanceID)
+}
+
+// FetchContent 打开 URL 并读取选择器对应元素的文本，选择器为空时读取整个页面
+func (p *RealScriptPlayer) FetchContent(ctx context.Context, targetURL string, selector string, instanceID string) (content string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[RealScriptPlayer] Panic recovered: %v", r)
+			err = fmt.Errorf("content fetch panicked: %v", r)
+		}
+	}()
+
+	if selector == "" {
+		selector = "body"
+	}
+	script := &models.Script{
+		Name: "monitor",
+		URL:  targetURL,
+		Actions: []models.ScriptAction{
+			{Type: "extract_text", Selector: selector, VariableName: "content"},
+		},
+	}
+
+	result, err := p.playScript(ctx, script, instanceID)
+	if err != nil {
+		return "", err
+	}
+	if !result.Success {
+		return "", fmt.Errorf("failed to fetch content: %s", result.Message)
+	}
+	text, _ := result.ExtractedData["content"].(string)
+	return text, nil
+}
+
+// CaptureScreenshot 打开 URL 并截图，返回截图文件信息（path, fileName, size 等），name 作为截图文件名前缀
+func (p *RealScriptPlayer) CaptureScreenshot(ctx context.Context, targetURL string, mode string, name string, instanceID string) (shot map[string]interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[RealScriptPlayer] Panic recovered: %v", r)
+			err = fmt.Errorf("screenshot panicked: %v", r)
+		}
+	}()
+
+	script := &models.Script{
+		Name: "screenshot",
+		URL:  targetURL,
+		Actions: []models.ScriptAction{
+			{Type: "screenshot", ScreenshotMode: mode, VariableName: name},
+		},
+	}
+
+	result, err := p.playScript(ctx, script, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Success {
+		return nil, fmt.Errorf("failed to take screenshot: %s", result.Message)
+	}
+	shot, ok := result.ExtractedData[name].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("screenshot data not found")
+	}
+	return shot, nil
+}
+
+// playScript 在浏览器中执行脚本并关闭页面
+func (p *RealScriptPlayer) playScript(ctx context.Context, script *models.Script, instanceID string) (*models.PlayResult, error) {
+	// 类型断言获取 browserManager（使用接口定义避免循环依赖）
+	type browserMgr interface {
+		IsRunning() bool
+		Start(ctx context.Context) error
+		PlayScript(ctx context.Context, script *models.Script, instanceID string) (*models.PlayResult, *rod.Page, error)
+		CloseActivePage(ctx context.Context, page *rod.Page) error
+	}
+
+	bm, ok := p.browserManager.(browserMgr)
+	if !ok {
+		// 记录详细错误信息帮助调试
+		log.Printf("[RealScriptPlayer] ERROR: Browser manager type assertion failed. Type: %T", p.browserManager)
+		return nil, fmt.Errorf("invalid browser manager type: %T", p.browserManager)
+	}
+
+	// 确保浏览器正在运行
+	if !bm.IsRunning() {
+		log.Printf("[RealScriptPlayer] Browser not running, starting...")
+		if err := bm.Start(ctx); err != nil {
+			return nil, fmt.Errorf("failed to start browser: %w", err)
+		}
+	}
+
 	// 执行脚本
-	result, page, err := bm.PlayScript(ctx, scriptToRun, instanceID)
+	result, page, err := bm.PlayScript(ctx, script, instanceID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute script: %w", err)
 	}
@@ -311,7 +443,12 @@ func NewSimpleScriptPlayer(db *storage.BoltDB) *SimpleScriptPlayer {
 }
 
 // PlayScript 播放脚本
-func (p *SimpleScriptPlayer) PlayScript(scriptID string, variables map[string]string, instanceID string) (*models.PlayResult, error) {
+func (p *SimpleScriptPlayer) PlayScript(ctx context.Context, scriptID string, variables map[string]string, instanceID string) (*models.PlayResult, error) {
+	return p.PlayScriptOnURL(ctx, scriptID, "", variables, instanceID)
+}
+
+// PlayScriptOnURL 在指定 URL 上播放脚本
+func (p *SimpleScriptPlayer) PlayScriptOnURL(ctx context.Context, scriptID string, targetURL string, variables map[string]string, instanceID string) (*models.PlayResult, error) {
 	// 这是一个简化的实现，仅用于测试
 	script, err := p.db.GetScript(scriptID)
 	if err != nil {
@@ -327,6 +464,7 @@ func (p *SimpleScriptPlayer) PlayScript(scriptID string, variables map[string]st
 		ExtractedData: map[string]interface{}{
 			"script_id":   scriptID,
 			"script_name": script.Name,
+			"url":         targetURL,
 			"variables":   variables,
 		},
 	}, nil
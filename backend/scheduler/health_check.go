@@ -0,0 +1,129 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+// ScriptHealthChecker 检查脚本录制的定位器是否仍然有效（由脚本播放器提供）
+type ScriptHealthChecker interface {
+	CheckScriptHealth(ctx context.Context, scriptID string, instanceID string) (*models.ScriptHealthReport, error)
+}
+
+// ScriptHealthSummary 健康检查任务中单个脚本的结果（完整报告通过 /api/v1/scripts/:id/health 查看）
+type ScriptHealthSummary struct {
+	ScriptID   string `json:"script_id"`
+	ScriptName string `json:"script_name"`
+	Healthy    bool   `json:"healthy"`
+	Found      int    `json:"found"`
+	Missing    int    `json:"missing"`
+	Unverified int    `json:"unverified"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ExecuteHealthCheck 执行选择器健康检查任务：逐个检查脚本（未指定时为所有设置了起始 URL 的脚本）的定位器并保存报告
+// 有脚本不健康时任务失败，通过任务失败通知在定时执行失败之前发现失效的脚本
+func (e *DefaultTaskExecutor) ExecuteHealthCheck(ctx context.Context, task *models.ScheduledTask) (map[string]interface{}, error) {
+	checker, ok := e.scriptPlayer.(ScriptHealthChecker)
+	if !ok {
+		return nil, fmt.Errorf("script player does not support health checks")
+	}
+
+	scriptIDs := task.HealthCheckScriptIDs
+	if len(scriptIDs) == 0 {
+		scripts, err := e.db.ListScripts()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list scripts: %w", err)
+		}
+		for _, script := range scripts {
+			if script.URL != "" {
+				scriptIDs = append(scriptIDs, script.ID)
+			}
+		}
+	}
+	if len(scriptIDs) == 0 {
+		return nil, fmt.Errorf("no scripts to check")
+	}
+
+	results := make([]ScriptHealthSummary, 0, len(scriptIDs))
+	var unhealthy []string
+	for _, id := range scriptIDs {
+		summary := ScriptHealthSummary{ScriptID: id}
+		if ctx.Err() != nil {
+			summary.Error = ctx.Err().Error()
+		} else if report, err := checker.CheckScriptHealth(ctx, id, task.BrowserInstanceID); err != nil {
+			log.Printf("[TaskExecutor] Failed to check script %s: %v", id, err)
+			summary.Error = err.Error()
+		} else {
+			report.TaskID = task.ID
+			if err := e.db.SaveScriptHealthReport(report); err != nil {
+				log.Printf("[TaskExecutor] Failed to save health report of script %s: %v", id, err)
+			}
+			summary = ScriptHealthSummary{
+				ScriptID:   id,
+				ScriptName: report.ScriptName,
+				Healthy:    report.Healthy,
+				Found:      report.Found,
+				Missing:    report.Missing,
+				Unverified: report.Unverified,
+				Error:      strings.Join(report.Errors, "; "),
+			}
+		}
+		if !summary.Healthy {
+			name := summary.ScriptName
+			if name == "" {
+				name = id
+			}
+			unhealthy = append(unhealthy, name)
+		}
+		results = append(results, summary)
+	}
+
+	log.Printf("[TaskExecutor] Health check task %s finished: %d scripts checked, %d unhealthy", task.Name, len(results), len(unhealthy))
+
+	resultData := map[string]interface{}{
+		"scripts":   results,
+		"checked":   len(results),
+		"unhealthy": len(unhealthy),
+	}
+	if len(unhealthy) > 0 {
+		return resultData, fmt.Errorf("%d of %d scripts have broken selectors or pages: %s", len(unhealthy), len(results), strings.Join(unhealthy, ", "))
+	}
+	return resultData, nil
+}
+
+// CheckScriptHealth 检查脚本录制的定位器是否仍能找到元素
+func (p *RealScriptPlayer) CheckScriptHealth(ctx context.Context, scriptID string, instanceID string) (report *models.ScriptHealthReport, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[RealScriptPlayer] Panic recovered: %v", r)
+			err = fmt.Errorf("health check panicked: %v", r)
+		}
+	}()
+
+	type healthChecker interface {
+		IsRunning() bool
+		Start(ctx context.Context) error
+		CheckScriptHealth(ctx context.Context, script *models.Script, instanceID string) (*models.ScriptHealthReport, error)
+	}
+	bm, ok := p.browserManager.(healthChecker)
+	if !ok {
+		return nil, fmt.Errorf("invalid browser manager type: %T", p.browserManager)
+	}
+
+	script, err := p.db.GetScript(scriptID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get script: %w", err)
+	}
+	if !bm.IsRunning() {
+		log.Printf("[RealScriptPlayer] Browser not running, starting...")
+		if err := bm.Start(ctx); err != nil {
+			return nil, fmt.Errorf("failed to start browser: %w", err)
+		}
+	}
+	return bm.CheckScriptHealth(ctx, script, instanceID)
+}
@@ -0,0 +1,69 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/storage"
+)
+
+// healthPlayer 返回预设的健康报告
+type healthPlayer struct {
+	cancellingPlayer
+	reports map[string]*models.ScriptHealthReport
+}
+
+func (p *healthPlayer) CheckScriptHealth(ctx context.Context, scriptID string, instanceID string) (*models.ScriptHealthReport, error) {
+	report, ok := p.reports[scriptID]
+	if !ok {
+		return nil, fmt.Errorf("script not found")
+	}
+	report.Tally()
+	return report, nil
+}
+
+func TestExecuteHealthCheck(t *testing.T) {
+	db, err := storage.NewBoltDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+	for _, script := range []*models.Script{
+		{ID: "ok", Name: "Prices", URL: "https://example.com/"},
+		{ID: "broken", Name: "Orders", URL: "https://example.com/orders"},
+		{ID: "no-url", Name: "Helper"},
+	} {
+		if err := db.SaveScript(script); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	player := &healthPlayer{reports: map[string]*models.ScriptHealthReport{
+		"ok":     {ScriptID: "ok", ScriptName: "Prices", Checks: []models.SelectorCheck{{Status: models.SelectorFound}}},
+		"broken": {ScriptID: "broken", ScriptName: "Orders", Checks: []models.SelectorCheck{{Status: models.SelectorFound}, {Status: models.SelectorMissing}}},
+	}}
+	e := NewDefaultTaskExecutor(db, player, nil)
+
+	data, err := e.ExecuteHealthCheck(context.Background(), &models.ScheduledTask{ID: "nightly"})
+	if err == nil {
+		t.Fatal("expected the task to fail when a script is unhealthy")
+	}
+	if data["checked"] != 2 || data["unhealthy"] != 1 {
+		t.Errorf("checked = %v, unhealthy = %v; want 2 scripts without the one lacking a start URL, 1 unhealthy", data["checked"], data["unhealthy"])
+	}
+
+	report, err := db.GetScriptHealthReport("broken")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Healthy || report.Missing != 1 || report.TaskID != "nightly" {
+		t.Errorf("unexpected saved report: %+v", report)
+	}
+
+	if _, err := e.ExecuteHealthCheck(context.Background(), &models.ScheduledTask{HealthCheckScriptIDs: []string{"ok"}}); err != nil {
+		t.Errorf("expected the task to succeed for healthy scripts, got %v", err)
+	}
+}
@@ -0,0 +1,224 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/urlpolicy"
+)
+
+// 单次结果中最多返回的新增/删除行数
+const maxMonitorDiffLines = 100
+
+// ContentFetcher 页面内容读取（由脚本播放器提供）
+type ContentFetcher interface {
+	FetchContent(ctx context.Context, targetURL string, selector string, instanceID string) (string, error)
+}
+
+// NetworkGuardProvider 提供内网访问防护（由浏览器管理器提供），用于检查 Webhook 地址
+type NetworkGuardProvider interface {
+	NetworkGuard() *urlpolicy.NetworkGuard
+}
+
+// ContentDiff 两次监控内容之间的差异
+type ContentDiff struct {
+	Added         []string `json:"added"`          // 新增的行
+	Removed       []string `json:"removed"`        // 删除的行
+	ChangePercent float64  `json:"change_percent"` // 变化的行数占两次内容总行数的百分比
+}
+
+// ExecuteMonitor 执行内容监控任务：读取页面内容并与上次结果对比，变化超过阈值时发送 Webhook 通知
+func (e *DefaultTaskExecutor) ExecuteMonitor(ctx context.Context, task *models.ScheduledTask) (map[string]interface{}, error) {
+	if task.MonitorURL == "" {
+		return nil, fmt.Errorf("monitor URL is empty")
+	}
+
+	fetcher, ok := e.scriptPlayer.(ContentFetcher)
+	if !ok {
+		return nil, fmt.Errorf("script player does not support content fetching")
+	}
+
+	log.Printf("[TaskExecutor] Checking monitor task: %s (url: %s, selector: %s)", task.Name, task.MonitorURL, task.MonitorSelector)
+
+	raw, err := fetcher.FetchContent(ctx, task.MonitorURL, task.MonitorSelector, task.BrowserInstanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch content: %w", err)
+	}
+	content := normalizeContent(raw)
+	now := time.Now()
+
+	previous, err := e.db.GetMonitorSnapshot(task.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load monitor snapshot: %w", err)
+	}
+
+	snapshot := &models.MonitorSnapshot{
+		TaskID:    task.ID,
+		URL:       task.MonitorURL,
+		Selector:  task.MonitorSelector,
+		Content:   content,
+		CheckedAt: now,
+		ChangedAt: now,
+	}
+
+	// 首次运行或监控目标已修改：只记录基线
+	if previous == nil || previous.URL != task.MonitorURL || previous.Selector != task.MonitorSelector {
+		if err := e.db.SaveMonitorSnapshot(snapshot); err != nil {
+			return nil, fmt.Errorf("failed to save monitor snapshot: %w", err)
+		}
+		return map[string]interface{}{
+			"baseline": true,
+			"changed":  false,
+			"content":  content,
+		}, nil
+	}
+
+	diff := diffContent(previous.Content, content)
+	changed := len(diff.Added) > 0 || len(diff.Removed) > 0
+	if !changed {
+		snapshot.ChangedAt = previous.ChangedAt
+	}
+	if err := e.db.SaveMonitorSnapshot(snapshot); err != nil {
+		return nil, fmt.Errorf("failed to save monitor snapshot: %w", err)
+	}
+
+	triggered := changed && diff.ChangePercent >= task.MonitorThreshold
+	resultData := map[string]interface{}{
+		"baseline":       false,
+		"changed":        changed,
+		"triggered":      triggered,
+		"change_percent": diff.ChangePercent,
+		"added":          truncateLines(diff.Added, maxMonitorDiffLines),
+		"removed":        truncateLines(diff.Removed, maxMonitorDiffLines),
+		"content":        content,
+	}
+
+	if !triggered {
+		return resultData, nil
+	}
+
+	log.Printf("[TaskExecutor] Monitor task %s detected %.1f%% change", task.Name, diff.ChangePercent)
+
+	if task.MonitorWebhookURL != "" {
+		var guard *urlpolicy.NetworkGuard
+		if provider, ok := e.scriptPlayer.(NetworkGuardProvider); ok {
+			guard = provider.NetworkGuard()
+		}
+		if err := sendMonitorWebhook(ctx, guard, task, diff, now); err != nil {
+			resultData["webhook_error"] = err.Error()
+			return resultData, fmt.Errorf("content changed but webhook failed: %w", err)
+		}
+		resultData["webhook_sent"] = true
+	}
+
+	return resultData, nil
+}
+
+// normalizeContent 规范化内容：合并行内空白，去掉空行
+func normalizeContent(content string) string {
+	lines := strings.Split(content, "\n")
+	normalized := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.Join(strings.Fields(line), " ")
+		if line != "" {
+			normalized = append(normalized, line)
+		}
+	}
+	return strings.Join(normalized, "\n")
+}
+
+// diffContent 按行对比两次内容（不考虑行的顺序），返回新增和删除的行
+func diffContent(oldContent, newContent string) ContentDiff {
+	oldLines := splitLines(oldContent)
+	newLines := splitLines(newContent)
+
+	remaining := make(map[string]int, len(oldLines))
+	for _, line := range oldLines {
+		remaining[line]++
+	}
+
+	diff := ContentDiff{Added: []string{}, Removed: []string{}}
+	for _, line := range newLines {
+		if remaining[line] > 0 {
+			remaining[line]--
+			continue
+		}
+		diff.Added = append(diff.Added, line)
+	}
+	for _, line := range oldLines {
+		if remaining[line] > 0 {
+			remaining[line]--
+			diff.Removed = append(diff.Removed, line)
+		}
+	}
+
+	total := len(oldLines) + len(newLines)
+	if total > 0 {
+		diff.ChangePercent = float64(len(diff.Added)+len(diff.Removed)) * 100 / float64(total)
+	}
+	return diff
+}
+
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(content, "\n")
+}
+
+func truncateLines(lines []string, limit int) []string {
+	if len(lines) > limit {
+		return lines[:limit]
+	}
+	return lines
+}
+
+// sendMonitorWebhook 以 JSON 形式 POST 内容变化通知
+// 发送前再次检查 Webhook 地址（保存后 DNS 可能已变化），并使用拒绝连接和重定向到内网的客户端
+func sendMonitorWebhook(ctx context.Context, guard *urlpolicy.NetworkGuard, task *models.ScheduledTask, diff ContentDiff, checkedAt time.Time) error {
+	if err := guard.Check(ctx, task.MonitorWebhookURL); err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{
+		"event":          "monitor.changed",
+		"task_id":        task.ID,
+		"task_name":      task.Name,
+		"url":            task.MonitorURL,
+		"selector":       task.MonitorSelector,
+		"change_percent": diff.ChangePercent,
+		"added":          truncateLines(diff.Added, maxMonitorDiffLines),
+		"removed":        truncateLines(diff.Removed, maxMonitorDiffLines),
+		"checked_at":     checkedAt.Format(time.RFC3339),
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, task.MonitorWebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := guard.HTTPClient(15 * time.Second).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
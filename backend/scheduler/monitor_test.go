@@ -0,0 +1,41 @@
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeContent(t *testing.T) {
+	got := normalizeContent("  Price:\t $10 \n\n\n  In   stock  \n")
+	if want := "Price: $10\nIn stock"; got != want {
+		t.Errorf("normalizeContent = %q, want %q", got, want)
+	}
+}
+
+func TestDiffContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		old     string
+		new     string
+		added   []string
+		removed []string
+		percent float64
+	}{
+		{"unchanged", "a\nb", "a\nb", []string{}, []string{}, 0},
+		{"reordered", "a\nb", "b\na", []string{}, []string{}, 0},
+		{"price change", "item\n$10", "item\n$8", []string{"$8"}, []string{"$10"}, 50},
+		{"new posting", "job1\njob2", "job1\njob2\njob3", []string{"job3"}, []string{}, 20},
+		{"from empty", "", "a", []string{"a"}, []string{}, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := diffContent(tt.old, tt.new)
+			if !reflect.DeepEqual(diff.Added, tt.added) || !reflect.DeepEqual(diff.Removed, tt.removed) {
+				t.Errorf("added = %v, removed = %v, want %v, %v", diff.Added, diff.Removed, tt.added, tt.removed)
+			}
+			if diff.ChangePercent != tt.percent {
+				t.Errorf("change percent = %v, want %v", diff.ChangePercent, tt.percent)
+			}
+		})
+	}
+}
@@ -17,12 +17,22 @@ import (
 type TaskExecutor interface {
 	ExecuteScript(ctx context.Context, task *models.ScheduledTask) (map[string]interface{}, error)
 	ExecuteAgent(ctx context.Context, task *models.ScheduledTask) (map[string]interface{}, error)
+	ExecuteCrawl(ctx context.Context, task *models.ScheduledTask) (map[string]interface{}, error)
+	ExecuteMonitor(ctx context.Context, task *models.ScheduledTask) (map[string]interface{}, error)
+	ExecuteScreenshot(ctx context.Context, task *models.ScheduledTask) (map[string]interface{}, error)
+	ExecuteHealthCheck(ctx context.Context, task *models.ScheduledTask) (map[string]interface{}, error)
+}
+
+// TaskNotifier 定时任务执行结束的通知
+type TaskNotifier interface {
+	TaskExecutionFinished(ctx context.Context, task *models.ScheduledTask, execution *models.TaskExecution)
 }
 
 // Scheduler 定时任务调度器
 type Scheduler struct {
 	db       *storage.BoltDB
 	executor TaskExecutor
+	notifier TaskNotifier // 执行结束通知（可能为 nil）
 	cron     *cron.Cron
 	mu       sync.RWMutex
 	tasks    map[string]cron.EntryID // taskID -> cronEntryID
@@ -49,6 +59,11 @@ func NewScheduler(db *storage.BoltDB, executor TaskExecutor) *Scheduler {
 	}
 }
 
+// SetNotifier 设置任务执行结束的通知
+func (s *Scheduler) SetNotifier(notifier TaskNotifier) {
+	s.notifier = notifier
+}
+
 // Start 启动调度器
 func (s *Scheduler) Start() error {
 	log.Println("[Scheduler] Starting scheduler...")
@@ -269,7 +284,11 @@ func (s *Scheduler) executeTask(task *models.ScheduledTask) {
 	var err error
 
 	// 执行任务
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute) // 5分钟超时
+	timeout := 5 * time.Minute // 5分钟超时
+	if task.ExecutionType == models.ExecutionTypeCrawl || task.ExecutionType == models.ExecutionTypeScreenshot || task.ExecutionType == models.ExecutionTypeHealthCheck {
+		timeout = time.Hour // 批量抓取、截图和健康检查需要逐个访问 URL，允许更长时间
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	switch task.ExecutionType {
@@ -279,6 +298,15 @@ func (s *Scheduler) executeTask(task *models.ScheduledTask) {
 	case models.ExecutionTypeAgent:
 		execution.AgentSessionID = task.AgentSessionID
 		resultData, err = s.executor.ExecuteAgent(ctx, task)
+	case models.ExecutionTypeCrawl:
+		execution.ScriptID = task.ScriptID
+		resultData, err = s.executor.ExecuteCrawl(ctx, task)
+	case models.ExecutionTypeMonitor:
+		resultData, err = s.executor.ExecuteMonitor(ctx, task)
+	case models.ExecutionTypeScreenshot:
+		resultData, err = s.executor.ExecuteScreenshot(ctx, task)
+	case models.ExecutionTypeHealthCheck:
+		resultData, err = s.executor.ExecuteHealthCheck(ctx, task)
 	default:
 		err = fmt.Errorf("unknown execution type: %s", task.ExecutionType)
 	}
@@ -291,6 +319,7 @@ func (s *Scheduler) executeTask(task *models.ScheduledTask) {
 
 	if err != nil {
 		execution.ErrorMsg = err.Error()
+		execution.ErrorCode = models.ErrorCodeOf(err)
 		execution.Message = fmt.Sprintf("task.messages.failed: %v", err)
 		log.Printf("[Scheduler] Task %s failed: %v", task.Name, err)
 	} else {
@@ -306,6 +335,10 @@ func (s *Scheduler) executeTask(task *models.ScheduledTask) {
 	// 更新任务统计
 	s.updateTaskStats(task, execution.Success)
 
+	if s.notifier != nil {
+		s.notifier.TaskExecutionFinished(ctx, task, execution)
+	}
+
 	// 更新下次执行时间（对于重复任务）
 	s.updateNextExecutionTime(task)
 }
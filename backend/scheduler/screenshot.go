@@ -0,0 +1,145 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+const defaultScreenshotRetention = 30
+
+// ScreenshotTaker 页面截图（由脚本播放器提供）
+type ScreenshotTaker interface {
+	CaptureScreenshot(ctx context.Context, targetURL string, mode string, name string, instanceID string) (map[string]interface{}, error)
+}
+
+// ExecuteScreenshot 执行截图存档任务：依次为每个 URL 截图并保存记录，超出保留数量的旧截图会被删除
+func (e *DefaultTaskExecutor) ExecuteScreenshot(ctx context.Context, task *models.ScheduledTask) (map[string]interface{}, error) {
+	if len(task.ScreenshotURLs) == 0 {
+		return nil, fmt.Errorf("no URLs to screenshot")
+	}
+
+	taker, ok := e.scriptPlayer.(ScreenshotTaker)
+	if !ok {
+		return nil, fmt.Errorf("script player does not support screenshots")
+	}
+
+	mode := task.ScreenshotMode
+	if mode == "" {
+		mode = "fullpage"
+	}
+	retention := task.ScreenshotRetention
+	if retention <= 0 {
+		retention = defaultScreenshotRetention
+	}
+
+	shots := make([]models.PageScreenshot, 0, len(task.ScreenshotURLs))
+	failures := make(map[string]string)
+
+	for _, u := range task.ScreenshotURLs {
+		u = strings.TrimSpace(u)
+		if u == "" {
+			continue
+		}
+		if ctx.Err() != nil {
+			failures[u] = ctx.Err().Error()
+			continue
+		}
+
+		shot, err := e.archiveScreenshot(ctx, taker, task, u, mode)
+		if err != nil {
+			log.Printf("[TaskExecutor] Failed to screenshot %s: %v", u, err)
+			failures[u] = err.Error()
+			continue
+		}
+		shots = append(shots, *shot)
+		e.pruneScreenshots(task.ID, u, retention)
+	}
+
+	log.Printf("[TaskExecutor] Screenshot task %s finished: %d succeeded, %d failed", task.Name, len(shots), len(failures))
+
+	resultData := map[string]interface{}{
+		"screenshots": shots,
+		"succeeded":   len(shots),
+		"failed":      len(failures),
+	}
+	if len(failures) > 0 {
+		resultData["errors"] = failures
+	}
+	if len(shots) == 0 {
+		return resultData, fmt.Errorf("all %d screenshots failed", len(failures))
+	}
+	return resultData, nil
+}
+
+// archiveScreenshot 为单个 URL 截图并保存存档记录
+func (e *DefaultTaskExecutor) archiveScreenshot(ctx context.Context, taker ScreenshotTaker, task *models.ScheduledTask, targetURL string, mode string) (*models.PageScreenshot, error) {
+	// 文件名带上任务 ID，便于区分不同任务的存档
+	data, err := taker.CaptureScreenshot(ctx, targetURL, mode, "archive_"+task.ID, task.BrowserInstanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	path, _ := data["path"].(string)
+	if path == "" {
+		return nil, fmt.Errorf("screenshot path is empty")
+	}
+	fileName, _ := data["fileName"].(string)
+	size, _ := data["size"].(int)
+
+	shot := &models.PageScreenshot{
+		ID:        generateID(),
+		TaskID:    task.ID,
+		URL:       targetURL,
+		Path:      path,
+		FileName:  fileName,
+		Size:      size,
+		CreatedAt: time.Now(),
+	}
+	if err := e.db.CreatePageScreenshot(shot); err != nil {
+		return nil, fmt.Errorf("failed to save screenshot record: %w", err)
+	}
+	return shot, nil
+}
+
+// pruneScreenshots 删除超出保留数量的旧截图（记录和文件）
+func (e *DefaultTaskExecutor) pruneScreenshots(taskID string, url string, retention int) {
+	shots, err := e.db.ListPageScreenshots(taskID, url)
+	if err != nil {
+		log.Printf("[TaskExecutor] Failed to list screenshots for pruning: %v", err)
+		return
+	}
+	for _, shot := range expiredScreenshots(shots, retention) {
+		if err := RemovePageScreenshot(e.db, &shot); err != nil {
+			log.Printf("[TaskExecutor] Failed to prune screenshot %s: %v", shot.ID, err)
+		}
+	}
+}
+
+// expiredScreenshots 返回超出保留数量的截图（shots 按创建时间降序）
+func expiredScreenshots(shots []models.PageScreenshot, retention int) []models.PageScreenshot {
+	if retention <= 0 || len(shots) <= retention {
+		return nil
+	}
+	return shots[retention:]
+}
+
+// screenshotStore 截图存档记录的存储
+type screenshotStore interface {
+	DeletePageScreenshot(id string) error
+}
+
+// RemovePageScreenshot 删除截图文件及其存档记录
+func RemovePageScreenshot(db screenshotStore, shot *models.PageScreenshot) error {
+	if shot.Path != "" {
+		if err := os.Remove(shot.Path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return db.DeletePageScreenshot(shot.ID)
+}
@@ -0,0 +1,19 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+func TestExpiredScreenshots(t *testing.T) {
+	shots := []models.PageScreenshot{{ID: "3"}, {ID: "2"}, {ID: "1"}}
+
+	if got := expiredScreenshots(shots, 5); len(got) != 0 {
+		t.Errorf("expected nothing to expire, got %v", got)
+	}
+	got := expiredScreenshots(shots, 1)
+	if len(got) != 2 || got[0].ID != "2" || got[1].ID != "1" {
+		t.Errorf("expected oldest two to expire, got %v", got)
+	}
+}
@@ -208,6 +208,9 @@ func (c *Client) initBrowserManager() error {
 	}
 
 	c.browserManager = browser.NewManager(cfg, c.db, c.llmManager)
+	if err := c.browserManager.EnsureDefaultInstance(context.Background()); err != nil {
+		log.Printf("Warning: Failed to initialize default browser instance: %v", err)
+	}
 	log.Println("✓ Browser manager initialized")
 	return nil
 }
@@ -283,12 +286,10 @@ func (c *Client) Close() error {
 		c.mcpServer.Stop()
 	}
 
-	// 停止浏览器
-	if c.browserManager != nil && c.browserManager.IsRunning() {
+	// 停止所有浏览器实例
+	if c.browserManager != nil && len(c.browserManager.ListRunningInstances()) > 0 {
 		log.Println("Stopping browser...")
-		if err := c.browserManager.Stop(); err != nil {
-			log.Printf("Warning: Failed to stop browser: %v", err)
-		}
+		c.browserManager.StopAll(context.Background())
 	}
 
 	// 关闭数据库
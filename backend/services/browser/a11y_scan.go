@@ -0,0 +1,325 @@
+package browser
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// DefaultAxeCoreSource 未配置 axe_core 时使用的 axe-core 脚本地址
+const DefaultAxeCoreSource = "https://cdn.jsdelivr.net/npm/axe-core@4.10.2/axe.min.js"
+
+// axeCoreCacheDir 远程 axe-core 脚本的缓存目录
+const axeCoreCacheDir = "./data/axe-core"
+
+// maxAxeCoreSize axe-core 脚本的最大大小
+const maxAxeCoreSize = 5 << 20
+
+// a11yWorldName 运行 axe-core 的隔离环境名称
+// axe-core 注入隔离环境，页面定义的 window.axe 无法冒充扫描结果，也看不到注入的 axe-core
+const a11yWorldName = "BrowserWing axe-core"
+
+// a11yImpactLevels axe-core 违规影响等级（由低到高）
+var a11yImpactLevels = map[string]int{
+	"minor":    1,
+	"moderate": 2,
+	"serious":  3,
+	"critical": 4,
+}
+
+// A11yScanOptions 可访问性扫描选项
+type A11yScanOptions struct {
+	Tags      []string // 只运行带这些标签的规则，如 wcag2a、wcag2aa、wcag21aa、best-practice，为空时运行全部规则
+	Include   string   // 只扫描该 CSS 选择器匹配的区域，为空时扫描整个页面
+	MinImpact string   // 只返回不低于该影响等级的违规：minor、moderate、serious、critical
+	MaxNodes  int      // 每条违规最多返回的元素数量，默认 10
+}
+
+// A11yViolationNode 违规的元素
+type A11yViolationNode struct {
+	Target         []string `json:"target"` // 元素选择器
+	HTML           string   `json:"html"`
+	FailureSummary string   `json:"failure_summary,omitempty"`
+}
+
+// A11yViolation 一条 WCAG 违规
+type A11yViolation struct {
+	ID          string              `json:"id"`
+	Impact      string              `json:"impact"`
+	Description string              `json:"description"`
+	Help        string              `json:"help"`
+	HelpURL     string              `json:"help_url"`
+	Tags        []string            `json:"tags"`
+	NodeCount   int                 `json:"node_count"` // 违规元素总数（Nodes 可能被截断）
+	Nodes       []A11yViolationNode `json:"nodes"`
+}
+
+// A11yScanResult 可访问性扫描结果
+type A11yScanResult struct {
+	URL        string          `json:"url"`
+	Engine     string          `json:"engine"` // axe-core 版本
+	Violations []A11yViolation `json:"violations"`
+	Passes     int             `json:"passes"`     // 通过的规则数
+	Incomplete int             `json:"incomplete"` // 需要人工确认的规则数
+}
+
+// a11yScanFunc 可访问性扫描函数（回放时为 Manager.ScanAccessibility）
+type a11yScanFunc func(ctx context.Context, page *rod.Page, opts *A11yScanOptions) (*A11yScanResult, error)
+
+// axeRunScript 运行 axe-core 并整理结果
+const axeRunScript = `async (opts) => {
+	const options = { resultTypes: ['violations'] };
+	if (opts.tags && opts.tags.length) options.runOnly = { type: 'tag', values: opts.tags };
+	const res = await window.axe.run(opts.include || document, options);
+	return {
+		url: res.url,
+		engine: (res.testEngine && res.testEngine.version) || '',
+		passes: res.passes.length,
+		incomplete: res.incomplete.length,
+		violations: res.violations.map(v => ({
+			id: v.id,
+			impact: v.impact || '',
+			description: v.description,
+			help: v.help,
+			help_url: v.helpUrl,
+			tags: v.tags,
+			node_count: v.nodes.length,
+			nodes: v.nodes.slice(0, opts.maxNodes).map(n => ({
+				target: n.target.map(t => Array.isArray(t) ? t.join(' ') : String(t)),
+				html: n.html,
+				failure_summary: n.failureSummary || '',
+			})),
+		})),
+	};
+}`
+
+// ScanAccessibility 在页面中注入 axe-core 并返回 WCAG 违规
+// 只扫描主文档，iframe 内的内容不在扫描范围内
+func (m *Manager) ScanAccessibility(ctx context.Context, page *rod.Page, opts *A11yScanOptions) (*A11yScanResult, error) {
+	if page == nil {
+		return nil, fmt.Errorf("no active page")
+	}
+	if opts == nil {
+		opts = &A11yScanOptions{}
+	}
+	if opts.MinImpact != "" {
+		if _, ok := a11yImpactLevels[opts.MinImpact]; !ok {
+			return nil, fmt.Errorf("unknown impact level: %s (supported: minor, moderate, serious, critical)", opts.MinImpact)
+		}
+	}
+	maxNodes := opts.MaxNodes
+	if maxNodes <= 0 {
+		maxNodes = 10
+	}
+	page = page.Context(ctx)
+
+	script, err := m.axeCoreScript(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// 每次扫描创建新的隔离环境，通过 Runtime.evaluate 注入，不受页面 CSP 限制
+	world, err := proto.PageCreateIsolatedWorld{FrameID: page.FrameID, WorldName: a11yWorldName}.Call(page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create isolated world for axe-core: %w", err)
+	}
+	injected, err := proto.RuntimeEvaluate{
+		Expression: script + "\n;globalThis",
+		ContextID:  world.ExecutionContextID,
+	}.Call(page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inject axe-core: %w", err)
+	}
+	if injected.ExceptionDetails != nil {
+		return nil, fmt.Errorf("failed to inject axe-core: %s", injected.ExceptionDetails.Text)
+	}
+
+	tags := opts.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	res, err := page.Evaluate(rod.Eval(axeRunScript, map[string]interface{}{
+		"tags":     tags,
+		"include":  opts.Include,
+		"maxNodes": maxNodes,
+	}).ByPromise().This(injected.Result))
+	if err != nil {
+		return nil, fmt.Errorf("axe-core scan failed: %w", err)
+	}
+
+	var result A11yScanResult
+	if err := res.Value.Unmarshal(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse axe-core result: %w", err)
+	}
+	result.Violations = filterA11yViolations(result.Violations, opts.MinImpact)
+	return &result, nil
+}
+
+// filterA11yViolations 过滤低于指定影响等级的违规，并按影响等级从高到低排序
+func filterA11yViolations(violations []A11yViolation, minImpact string) []A11yViolation {
+	minLevel := a11yImpactLevels[minImpact]
+	filtered := make([]A11yViolation, 0, len(violations))
+	for level := 4; level >= 0; level-- {
+		if level < minLevel {
+			break
+		}
+		for _, v := range violations {
+			if a11yImpactLevels[v.Impact] == level {
+				filtered = append(filtered, v)
+			}
+		}
+	}
+	return filtered
+}
+
+// axeCoreScript 获取 axe-core 脚本（进程内只加载一次）
+// 远程脚本按地址缓存到磁盘，地址中固定了版本号，因此缓存不会过期；读取缓存时同样校验哈希
+func (m *Manager) axeCoreScript(ctx context.Context) (string, error) {
+	m.axeMu.Lock()
+	defer m.axeMu.Unlock()
+	if m.axeScript != "" {
+		return m.axeScript, nil
+	}
+
+	source := m.config.Browser.AxeCoreSource()
+	if source == "" {
+		source = DefaultAxeCoreSource
+	}
+
+	integrity := m.config.Browser.AxeCoreIntegrity()
+	var data []byte
+	var err error
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		if integrity == "" {
+			return "", fmt.Errorf("browser.axe_core_sha384 is required to load axe-core from %s; set it to the script's SRI hash, or download axe.min.js and set browser.axe_core to its local path", source)
+		}
+		data, err = downloadAxeCore(ctx, m.netGuard.HTTPClient(60*time.Second), source, integrity)
+	} else {
+		data, err = readAxeCore(source)
+		if err == nil && integrity != "" {
+			err = checkAxeCoreIntegrity(data, integrity)
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to load axe-core from %s: %w", source, err)
+	}
+	m.axeScript = string(data)
+	logger.Info(ctx, "✓ axe-core loaded from %s (%d bytes)", source, len(data))
+	return m.axeScript, nil
+}
+
+// downloadAxeCore 通过内网访问防护下载 axe-core 脚本并校验哈希，已缓存且哈希一致时直接使用缓存
+func downloadAxeCore(ctx context.Context, client *http.Client, source, integrity string) ([]byte, error) {
+	sum := sha1.Sum([]byte(source))
+	cachePath := filepath.Join(axeCoreCacheDir, hex.EncodeToString(sum[:8])+".js")
+	if data, err := readAxeCore(cachePath); err == nil && checkAxeCoreIntegrity(data, integrity) == nil {
+		return data, nil
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxAxeCoreSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxAxeCoreSize {
+		return nil, fmt.Errorf("script exceeds %d bytes", maxAxeCoreSize)
+	}
+	if err := checkAxeCoreIntegrity(data, integrity); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(axeCoreCacheDir, 0o755); err == nil {
+		_ = os.WriteFile(cachePath, data, 0o644)
+	}
+	return data, nil
+}
+
+// readAxeCore 读取本地 axe-core 脚本
+func readAxeCore(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, maxAxeCoreSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxAxeCoreSize {
+		return nil, fmt.Errorf("script exceeds %d bytes", maxAxeCoreSize)
+	}
+	return data, nil
+}
+
+// executeA11yScan 执行可访问性扫描，结果保存到变量中
+// 设置了 A11yMaxViolations 时，违规数量超过该值则步骤失败，可用于把录制的流程作为可访问性回归检查
+func (p *Player) executeA11yScan(ctx context.Context, page *rod.Page, action models.ScriptAction) error {
+	if p.a11yScanner == nil {
+		return fmt.Errorf("accessibility scan is not available")
+	}
+
+	result, err := p.a11yScanner(ctx, page, &A11yScanOptions{
+		Tags:      action.A11yTags,
+		Include:   action.Selector,
+		MinImpact: action.A11yMinImpact,
+	})
+	if err != nil {
+		return err
+	}
+
+	varName := action.VariableName
+	if varName == "" {
+		varName = fmt.Sprintf("a11y_scan_%d", len(p.extractedData))
+	}
+	p.extractedData[varName] = result
+
+	logger.Info(ctx, "✓ Accessibility scan completed: %s, %d violations", result.URL, len(result.Violations))
+	if action.A11yMaxViolations != nil && len(result.Violations) > *action.A11yMaxViolations {
+		ids := make([]string, 0, len(result.Violations))
+		for _, v := range result.Violations {
+			ids = append(ids, fmt.Sprintf("%s(%s)", v.ID, v.Impact))
+		}
+		return fmt.Errorf("accessibility scan found %d violations, more than the allowed %d: %s",
+			len(result.Violations), *action.A11yMaxViolations, strings.Join(ids, ", "))
+	}
+	return nil
+}
+
+// checkAxeCoreIntegrity 校验脚本的 SRI 哈希（sha384-<base64>）
+func checkAxeCoreIntegrity(data []byte, integrity string) error {
+	expected, ok := strings.CutPrefix(integrity, "sha384-")
+	if !ok {
+		return fmt.Errorf("invalid axe_core_sha384 %q: expected sha384-<base64>", integrity)
+	}
+	sum := sha512.Sum384(data)
+	if actual := base64.StdEncoding.EncodeToString(sum[:]); actual != expected {
+		return fmt.Errorf("integrity check failed: got sha384-%s", actual)
+	}
+	return nil
+}
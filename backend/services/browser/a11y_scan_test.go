@@ -0,0 +1,89 @@
+package browser
+
+import (
+	"context"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+)
+
+func TestFilterA11yViolations(t *testing.T) {
+	violations := []A11yViolation{
+		{ID: "region", Impact: "moderate"},
+		{ID: "image-alt", Impact: "critical"},
+		{ID: "color-contrast", Impact: "serious"},
+		{ID: "landmark-one-main", Impact: "minor"},
+	}
+
+	got := filterA11yViolations(violations, "")
+	var ids []string
+	for _, v := range got {
+		ids = append(ids, v.ID)
+	}
+	if want := "image-alt,color-contrast,region,landmark-one-main"; strings.Join(ids, ",") != want {
+		t.Errorf("order = %s, want %s", strings.Join(ids, ","), want)
+	}
+
+	if got := filterA11yViolations(violations, "serious"); len(got) != 2 {
+		t.Errorf("serious filter returned %d violations, want 2", len(got))
+	}
+}
+
+func TestCheckAxeCoreIntegrity(t *testing.T) {
+	data := []byte("window.axe = {};")
+	sum := sha512.Sum384(data)
+	integrity := "sha384-" + base64.StdEncoding.EncodeToString(sum[:])
+
+	if err := checkAxeCoreIntegrity(data, integrity); err != nil {
+		t.Errorf("expected matching hash to pass, got %v", err)
+	}
+	if err := checkAxeCoreIntegrity([]byte("window.axe = {run: steal};"), integrity); err == nil {
+		t.Error("expected modified script to fail the integrity check")
+	}
+	if err := checkAxeCoreIntegrity(data, hex.EncodeToString(sum[:])); err == nil {
+		t.Error("expected a hash without the sha384- prefix to be rejected")
+	}
+}
+
+func TestExecuteA11yScanThreshold(t *testing.T) {
+	logger.InitLogger(&logger.LoggerConfig{Level: "error"})
+	p := NewPlayer("en")
+	var gotOpts *A11yScanOptions
+	p.a11yScanner = func(ctx context.Context, page *rod.Page, opts *A11yScanOptions) (*A11yScanResult, error) {
+		gotOpts = opts
+		return &A11yScanResult{
+			URL:        "https://example.com/",
+			Violations: []A11yViolation{{ID: "image-alt", Impact: "critical"}},
+		}, nil
+	}
+
+	allowed := 0
+	action := models.ScriptAction{
+		Type:              "a11y_scan",
+		Selector:          "main",
+		VariableName:      "a11y",
+		A11yTags:          []string{"wcag2aa"},
+		A11yMaxViolations: &allowed,
+	}
+	err := p.executeA11yScan(context.Background(), nil, action)
+	if err == nil || !strings.Contains(err.Error(), "image-alt(critical)") {
+		t.Errorf("expected threshold error mentioning the violation, got %v", err)
+	}
+	if gotOpts.Include != "main" || len(gotOpts.Tags) != 1 {
+		t.Errorf("unexpected scan options: %+v", gotOpts)
+	}
+	if _, ok := p.extractedData["a11y"]; !ok {
+		t.Error("scan result should be stored even when the step fails")
+	}
+
+	allowed = 1
+	if err := p.executeA11yScan(context.Background(), nil, action); err != nil {
+		t.Errorf("unexpected error within threshold: %v", err)
+	}
+}
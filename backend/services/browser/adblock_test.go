@@ -0,0 +1,41 @@
+package browser
+
+import (
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+func TestAdBlockEnabledFor(t *testing.T) {
+	on, off := true, false
+	configs := []models.BrowserConfig{
+		{IsDefault: true, BlockAds: &on},
+		{URLPattern: `^https://shop\.example\.com/`, BlockAds: &off},
+		{URLPattern: `^https://news\.example\.com/`},
+		{URLPattern: `(invalid`, BlockAds: &off},
+	}
+
+	if !anyAdBlocking(configs) {
+		t.Fatal("expected ad blocking to be enabled by the default config")
+	}
+	rules := newAdBlockRules(configs)
+	if !rules.enabledFor("https://other.com/") {
+		t.Error("default config should enable ad blocking for unmatched pages")
+	}
+	if rules.enabledFor("https://shop.example.com/cart") {
+		t.Error("site config should disable ad blocking for its pages")
+	}
+	if !rules.enabledFor("https://news.example.com/a") {
+		t.Error("site config without block_ads should inherit the default")
+	}
+
+	configs[0].BlockAds = nil
+	configs[2].BlockAds = &on
+	rules = newAdBlockRules(configs)
+	if rules.enabledFor("https://other.com/") {
+		t.Error("ad blocking should be off by default")
+	}
+	if !rules.enabledFor("https://news.example.com/a") {
+		t.Error("site config should enable ad blocking for its pages")
+	}
+}
@@ -0,0 +1,172 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+)
+
+const (
+	anomalyBaselineRuns = 20 // 计算基线使用的最近成功执行数
+	anomalyMinBaseline  = 5  // 至少有这么多次成功执行的指标后才开始检测
+	anomalySigmas       = 3  // 偏离基线超过多少个稳健标准差（按中位数绝对偏差估计）视为异常
+
+	anomalyMinItemChange       = 0.5 // 条目数还需偏离基线中位数的比例，避免波动很小的脚本因轻微变化被标记
+	anomalyMinNullRateIncrease = 0.2 // 空值比例还需比基线中位数升高的幅度
+)
+
+// measureExtraction 统计抓取结果的条目数和空值比例
+func measureExtraction(data map[string]interface{}) *models.ExtractionMetrics {
+	metrics := &models.ExtractionMetrics{}
+	cells, nulls := 0, 0
+	countCell := func(value interface{}) {
+		cells++
+		if isNullValue(value) {
+			nulls++
+		}
+	}
+	// 对象条目按字段统计空值，其他条目整体统计
+	countItem := func(item interface{}) {
+		if obj, ok := item.(map[string]interface{}); ok {
+			for _, field := range obj {
+				countCell(field)
+			}
+			return
+		}
+		countCell(item)
+	}
+
+	for _, value := range data {
+		switch v := normalizeExtracted(value).(type) {
+		case []interface{}:
+			metrics.ItemCount += len(v)
+			for _, item := range v {
+				countItem(item)
+			}
+		default:
+			if !isNullValue(v) {
+				metrics.ItemCount++
+			}
+			countItem(v)
+		}
+	}
+	if cells > 0 {
+		metrics.NullRate = float64(nulls) / float64(cells)
+	}
+	return metrics
+}
+
+// isNullValue 是否为空值（null 或空白字符串）
+func isNullValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	s, ok := value.(string)
+	return ok && strings.TrimSpace(s) == ""
+}
+
+// detectAnomalies 把本次指标与最近成功执行的指标比较，返回异常说明；历史执行不足时不检测。
+// 使用中位数和中位数绝对偏差作为基线，个别异常的历史执行不会拉偏基线；checkItems 为 false 时不检查条目数
+func detectAnomalies(current models.ExtractionMetrics, baseline []models.ExtractionMetrics, checkItems bool) []string {
+	if len(baseline) < anomalyMinBaseline {
+		return nil
+	}
+	var anomalies []string
+
+	if checkItems {
+		counts := make([]float64, len(baseline))
+		for i, metrics := range baseline {
+			counts[i] = float64(metrics.ItemCount)
+		}
+		median, sigma := robustStats(counts)
+		threshold := math.Max(math.Max(anomalySigmas*sigma, anomalyMinItemChange*median), 1)
+		if diff := float64(current.ItemCount) - median; math.Abs(diff) > threshold {
+			direction := "below"
+			if diff > 0 {
+				direction = "above"
+			}
+			anomalies = append(anomalies, fmt.Sprintf("item count %d is far %s the usual %g (median of the last %d runs)",
+				current.ItemCount, direction, median, len(baseline)))
+		}
+	}
+
+	rates := make([]float64, len(baseline))
+	for i, metrics := range baseline {
+		rates[i] = metrics.NullRate
+	}
+	median, sigma := robustStats(rates)
+	threshold := math.Max(anomalySigmas*sigma, anomalyMinNullRateIncrease)
+	if current.NullRate-median > threshold {
+		anomalies = append(anomalies, fmt.Sprintf("null rate %.0f%% is far above the usual %.0f%% (median of the last %d runs)",
+			current.NullRate*100, median*100, len(baseline)))
+	}
+	return anomalies
+}
+
+// robustStats 返回中位数和按中位数绝对偏差估计的标准差
+func robustStats(values []float64) (median, sigma float64) {
+	median = medianOf(values)
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - median)
+	}
+	// 1.4826 把正态分布的中位数绝对偏差换算为标准差
+	return median, 1.4826 * medianOf(deviations)
+}
+
+// medianOf 中位数，不修改传入的切片
+func medianOf(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// hasDedupSteps 脚本是否有去重的抓取步骤，这类脚本每次只输出新条目，条目数本身就会大幅波动
+func hasDedupSteps(script *models.Script) bool {
+	for _, action := range script.Actions {
+		if action.Dedup {
+			return true
+		}
+	}
+	return false
+}
+
+// checkExtractionAnomalies 计算本次执行的抓取指标，与该脚本最近的成功执行比较，标记可疑的结果
+func (m *Manager) checkExtractionAnomalies(ctx context.Context, script *models.Script, execution *models.ScriptExecution) {
+	execution.Metrics = measureExtraction(execution.ExtractedData)
+	if m.db == nil {
+		return
+	}
+
+	recent, err := m.db.RecentScriptExecutions(script.ID, 0)
+	if err != nil {
+		logger.Warn(ctx, "Failed to load recent executions for anomaly detection: %v", err)
+		return
+	}
+	baseline := make([]models.ExtractionMetrics, 0, anomalyBaselineRuns)
+	for _, previous := range recent {
+		if previous.Success && previous.Metrics != nil {
+			baseline = append(baseline, *previous.Metrics)
+			if len(baseline) == anomalyBaselineRuns {
+				break
+			}
+		}
+	}
+
+	execution.Anomalies = detectAnomalies(*execution.Metrics, baseline, !hasDedupSteps(script))
+	for _, anomaly := range execution.Anomalies {
+		logger.Warn(ctx, "Suspect extraction result: %s", anomaly)
+	}
+}
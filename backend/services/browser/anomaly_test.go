@@ -0,0 +1,110 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/storage"
+)
+
+func TestMeasureExtraction(t *testing.T) {
+	metrics := measureExtraction(map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"title": "a", "price": nil},
+			map[string]interface{}{"title": " ", "price": 3},
+		},
+		"page":  "1",
+		"total": nil,
+	})
+	// 条目：2 个数组元素 + page；空值：price、title、total（共 6 个值）
+	if metrics.ItemCount != 3 || metrics.NullRate != 0.5 {
+		t.Errorf("unexpected metrics %+v", metrics)
+	}
+	if empty := measureExtraction(nil); empty.ItemCount != 0 || empty.NullRate != 0 {
+		t.Errorf("unexpected metrics for no data %+v", empty)
+	}
+}
+
+func TestDetectAnomalies(t *testing.T) {
+	baseline := []models.ExtractionMetrics{}
+	for _, count := range []int{198, 200, 205, 201, 199, 200} {
+		baseline = append(baseline, models.ExtractionMetrics{ItemCount: count, NullRate: 0.02})
+	}
+
+	for _, tc := range []struct {
+		name    string
+		current models.ExtractionMetrics
+		want    int
+	}{
+		{"normal", models.ExtractionMetrics{ItemCount: 190, NullRate: 0.05}, 0},
+		{"no items", models.ExtractionMetrics{ItemCount: 0}, 1},
+		{"far more items", models.ExtractionMetrics{ItemCount: 1000, NullRate: 0.02}, 1},
+		{"mostly empty", models.ExtractionMetrics{ItemCount: 200, NullRate: 0.6}, 1},
+	} {
+		if got := detectAnomalies(tc.current, baseline, true); len(got) != tc.want {
+			t.Errorf("%s: expected %d anomalies, got %v", tc.name, tc.want, got)
+		}
+	}
+
+	if got := detectAnomalies(models.ExtractionMetrics{}, baseline, false); len(got) != 0 {
+		t.Errorf("item count should not be checked, got %v", got)
+	}
+	if got := detectAnomalies(models.ExtractionMetrics{}, baseline[:anomalyMinBaseline-1], true); len(got) != 0 {
+		t.Errorf("too few runs for a baseline, got %v", got)
+	}
+}
+
+func TestCheckExtractionAnomalies(t *testing.T) {
+	logger.InitLogger(&logger.LoggerConfig{Level: "error"})
+	db, err := storage.NewBoltDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+	m := &Manager{db: db}
+
+	start := time.Now().Add(-time.Hour)
+	save := func(scriptID string, i int, success bool, count int) {
+		execution := &models.ScriptExecution{
+			ID:        fmt.Sprintf("%s-%d", scriptID, start.Add(time.Duration(i)*time.Minute).UnixNano()),
+			ScriptID:  scriptID,
+			StartTime: start.Add(time.Duration(i) * time.Minute),
+			Success:   success,
+			Metrics:   &models.ExtractionMetrics{ItemCount: count},
+		}
+		if err := db.SaveScriptExecution(execution); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < anomalyMinBaseline; i++ {
+		save("s1", i, true, 200)
+		// ID 以 "s1-" 开头的其他脚本和失败的执行不计入基线
+		save("s1-other", i, true, 0)
+		save("s1", i+100, false, 0)
+	}
+
+	recent, err := db.RecentScriptExecutions("s1", 3)
+	if err != nil || len(recent) != 3 || recent[0].Success || !recent[0].StartTime.After(recent[1].StartTime) {
+		t.Fatalf("expected the newest executions of s1 first, got %v, %v", recent, err)
+	}
+
+	script := &models.Script{ID: "s1"}
+	execution := &models.ScriptExecution{ScriptID: "s1", ExtractedData: map[string]interface{}{"items": []interface{}{}}}
+	m.checkExtractionAnomalies(context.Background(), script, execution)
+	if execution.Metrics == nil || execution.Metrics.ItemCount != 0 || len(execution.Anomalies) != 1 {
+		t.Errorf("expected an empty result to be flagged, got %+v %v", execution.Metrics, execution.Anomalies)
+	}
+
+	// 去重脚本每次只输出新条目，不检查条目数
+	script.Actions = []models.ScriptAction{{Type: "execute_js", VariableName: "items", Dedup: true}}
+	execution.Anomalies = nil
+	m.checkExtractionAnomalies(context.Background(), script, execution)
+	if len(execution.Anomalies) != 0 {
+		t.Errorf("dedup scripts should not be flagged for item count, got %v", execution.Anomalies)
+	}
+}
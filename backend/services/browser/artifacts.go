@@ -0,0 +1,159 @@
+package browser
+
+import (
+	"context"
+
+	"github.com/browserwing/browserwing/config"
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/artifacts"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// storageConfig 获取产物存储配置
+func (m *Manager) storageConfig() *config.StorageConfig {
+	if m.config == nil {
+		return nil
+	}
+	return m.config.Storage
+}
+
+// DownloadSandbox 获取下载文件沙箱
+func (m *Manager) DownloadSandbox() (*artifacts.Sandbox, error) {
+	return artifacts.NewSandbox(m.storageConfig().DownloadsRoot())
+}
+
+// ScreenshotSandbox 获取截图沙箱
+func (m *Manager) ScreenshotSandbox() (*artifacts.Sandbox, error) {
+	return artifacts.NewSandbox(m.storageConfig().ScreenshotsRoot())
+}
+
+// UploadSandbox 获取上传文件沙箱，拖放和上传操作只能读取其中的文件
+func (m *Manager) UploadSandbox() (*artifacts.Sandbox, error) {
+	return artifacts.NewSandbox(m.storageConfig().UploadsRoot())
+}
+
+// ArtifactSubdir 按配置的路径模板生成产物子目录（相对路径），未配置模板时返回空
+func (m *Manager) ArtifactSubdir(vars artifacts.TemplateVars) string {
+	return artifacts.ExpandTemplate(m.storageConfig().SubdirTemplate(), vars)
+}
+
+// downloadRoot 获取下载根目录的绝对路径
+func (m *Manager) downloadRoot(ctx context.Context) string {
+	sandbox, err := m.DownloadSandbox()
+	if err != nil {
+		logger.Warn(ctx, "Failed to resolve download directory: %v", err)
+		return m.storageConfig().DownloadsRoot()
+	}
+	return sandbox.Root()
+}
+
+// prepareExecutionDownloads 为一次脚本回放准备下载目录：配置了路径模板时使用独立子目录，
+// 并将浏览器上下文的下载行为切换到该目录；返回下载目录及恢复默认下载目录的函数
+func (m *Manager) prepareExecutionDownloads(ctx context.Context, browser *rod.Browser, execution *models.ScriptExecution) (string, func()) {
+	noop := func() {}
+	if m.downloadPath == "" {
+		return "", noop
+	}
+	subdir := m.ArtifactSubdir(artifacts.TemplateVars{
+		Script:    execution.ScriptName,
+		ScriptID:  execution.ScriptID,
+		Execution: execution.ID,
+		Time:      execution.StartTime,
+	})
+	if subdir == "" {
+		return m.downloadPath, noop
+	}
+
+	sandbox, err := m.DownloadSandbox()
+	if err != nil {
+		logger.Warn(ctx, "Failed to resolve download directory: %v", err)
+		return m.downloadPath, noop
+	}
+	dir, err := sandbox.Dir(subdir)
+	if err != nil {
+		logger.Warn(ctx, "Failed to create execution download directory: %v", err)
+		return m.downloadPath, noop
+	}
+
+	if err := setDownloadDir(browser, dir); err != nil {
+		logger.Warn(ctx, "Failed to set download behavior for execution: %v", err)
+		return m.downloadPath, noop
+	}
+	logger.Info(ctx, "Execution downloads will be saved to: %s", dir)
+
+	return dir, func() {
+		if err := setDownloadDir(browser, m.downloadPath); err != nil {
+			logger.Warn(ctx, "Failed to restore download behavior: %v", err)
+		}
+	}
+}
+
+// setDownloadDir 设置浏览器上下文的下载目录
+func setDownloadDir(browser *rod.Browser, dir string) error {
+	return proto.BrowserSetDownloadBehavior{
+		Behavior:         proto.BrowserSetDownloadBehaviorBehaviorAllow,
+		BrowserContextID: browser.BrowserContextID,
+		DownloadPath:     dir,
+		EventsEnabled:    true,
+	}.Call(browser)
+}
+
+// recordingsDir 获取录像输出目录
+func (m *Manager) recordingsDir() string {
+	if m.db != nil {
+		if cfg := m.db.GetDefaultRecordingConfig(); cfg != nil && cfg.OutputDir != "" {
+			return cfg.OutputDir
+		}
+	}
+	return "recordings"
+}
+
+// artifactDirs 获取各类产物目录
+func (m *Manager) artifactDirs(ctx context.Context) map[string]string {
+	dirs := map[string]string{
+		config.ArtifactDownloads:  m.downloadRoot(ctx),
+		config.ArtifactRecordings: m.recordingsDir(),
+	}
+	if sandbox, err := m.ScreenshotSandbox(); err == nil {
+		dirs[config.ArtifactScreenshots] = sandbox.Root()
+	} else {
+		dirs[config.ArtifactScreenshots] = m.storageConfig().ScreenshotsRoot()
+	}
+	return dirs
+}
+
+// ArtifactUsage 获取各产物目录的占用情况及配额
+func (m *Manager) ArtifactUsage(ctx context.Context) (map[string]*artifacts.Usage, error) {
+	usage := make(map[string]*artifacts.Usage)
+	for kind, dir := range m.artifactDirs(ctx) {
+		u, err := artifacts.DirUsage(dir, m.storageConfig().QuotaBytes(kind))
+		if err != nil {
+			return nil, err
+		}
+		usage[kind] = u
+	}
+	return usage, nil
+}
+
+// EnforceArtifactQuotas 清理超出配额的产物目录，keep 中的文件（如本次执行刚生成的文件）不会被删除
+func (m *Manager) EnforceArtifactQuotas(ctx context.Context, keep ...string) map[string]*artifacts.Eviction {
+	evictions := make(map[string]*artifacts.Eviction)
+	for kind, dir := range m.artifactDirs(ctx) {
+		quota := m.storageConfig().QuotaBytes(kind)
+		if quota <= 0 {
+			continue
+		}
+		eviction, err := artifacts.EnforceQuota(dir, quota, keep...)
+		if err != nil {
+			logger.Warn(ctx, "Failed to enforce %s quota: %v", kind, err)
+		}
+		if eviction == nil || len(eviction.Removed) == 0 {
+			continue
+		}
+		logger.Info(ctx, "Evicted %d old %s files (%d bytes) to stay within quota", len(eviction.Removed), kind, eviction.FreedBytes)
+		evictions[kind] = eviction
+	}
+	return evictions
+}
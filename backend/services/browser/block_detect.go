@@ -0,0 +1,96 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/go-rod/rod"
+)
+
+// blockDetectTimeout 检测拦截页面的超时时间
+const blockDetectTimeout = 3 * time.Second
+
+// BlockPage 验证码或反爬拦截页面
+type BlockPage struct {
+	Provider string `json:"provider"` // cloudflare、recaptcha、hcaptcha、turnstile、google、perimeterx、datadome、generic
+	Reason   string `json:"reason"`   // 命中的特征
+}
+
+// Err 返回标注为 CAPTCHA_DETECTED 的错误
+func (b *BlockPage) Err() error {
+	return models.WithErrorCode(models.ErrorCodeCaptchaDetected,
+		fmt.Errorf("page is blocked by a %s challenge (%s)", b.Provider, b.Reason))
+}
+
+// blockPageScript 识别常见的拦截页面：Cloudflare 质询、Google "unusual traffic"、
+// 以验证码为主体的页面（reCAPTCHA、hCaptcha、Turnstile、PerimeterX、DataDome）
+// 普通页面中嵌入的验证码（如登录表单）不算拦截，只有页面几乎没有其他内容时才判定
+const blockPageScript = `() => {
+	const title = (document.title || '').trim();
+	const text = document.body ? (document.body.innerText || '').trim() : '';
+	const sparse = text.length < 1000;
+	const has = (selector) => !!document.querySelector(selector);
+	const frame = (pattern) => Array.from(document.querySelectorAll('iframe')).some(f => pattern.test(f.src || ''));
+
+	if (/^(just a moment|attention required!? \| cloudflare|please wait\.*\s*\| cloudflare)/i.test(title) ||
+		window._cf_chl_opt || has('#challenge-form') || has('#challenge-running') || has('#cf-challenge-running')) {
+		return { provider: 'cloudflare', reason: 'challenge page: ' + title };
+	}
+	if ((/(^|\.)google\.[a-z.]+$/.test(location.hostname) && location.pathname.startsWith('/sorry/')) ||
+		/our systems have detected unusual traffic/i.test(text)) {
+		return { provider: 'google', reason: 'unusual traffic page' };
+	}
+	if (has('#px-captcha')) {
+		return { provider: 'perimeterx', reason: 'press and hold challenge' };
+	}
+	if (frame(/captcha-delivery\.com/)) {
+		return { provider: 'datadome', reason: 'captcha interstitial' };
+	}
+	if (!sparse) {
+		return null;
+	}
+	if (frame(/google\.com\/recaptcha|recaptcha\.net\/recaptcha/)) {
+		return { provider: 'recaptcha', reason: 'captcha interstitial' };
+	}
+	if (frame(/hcaptcha\.com/)) {
+		return { provider: 'hcaptcha', reason: 'captcha interstitial' };
+	}
+	if (frame(/challenges\.cloudflare\.com/)) {
+		return { provider: 'turnstile', reason: 'captcha interstitial' };
+	}
+	const generic = /unusual traffic|verify (that )?you are (a )?human|are you a robot|not a robot|automated (queries|requests|access)/i;
+	if (generic.test(title) || generic.test(text)) {
+		return { provider: 'generic', reason: 'bot check: ' + (title || text.slice(0, 80)) };
+	}
+	return null;
+}`
+
+// DetectBlockPage 检查页面是否为验证码或反爬拦截页面，不是时返回 nil
+func DetectBlockPage(ctx context.Context, page *rod.Page) (*BlockPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, blockDetectTimeout)
+	defer cancel()
+
+	res, err := page.Context(ctx).Eval(blockPageScript)
+	if err != nil {
+		return nil, err
+	}
+	if res.Value.Nil() {
+		return nil, nil
+	}
+	var block BlockPage
+	if err := res.Value.Unmarshal(&block); err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+// checkBlockPage 页面是拦截页面时返回 CAPTCHA_DETECTED 错误，检测失败时不影响回放
+func checkBlockPage(ctx context.Context, page *rod.Page) error {
+	block, err := DetectBlockPage(ctx, page)
+	if err != nil || block == nil {
+		return nil
+	}
+	return block.Err()
+}
@@ -0,0 +1,170 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/browserwing/browserwing/config"
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/storage"
+)
+
+// DefaultInstanceID 默认浏览器实例的 ID，没有当前实例时使用该实例（未运行时自动启动）
+const DefaultInstanceID = "default"
+
+// EnsureDefaultInstance 根据配置文件创建或更新默认浏览器实例（没有当前实例时使用），启动时在 NewManager 之后调用
+func (m *Manager) EnsureDefaultInstance(ctx context.Context) error {
+	if m.db == nil {
+		return nil
+	}
+	return ensureDefaultInstance(ctx, m.db, m.config)
+}
+
+// ensureDefaultInstance 确保数据库中存在默认浏览器实例，并同步配置文件中的浏览器配置（远程地址、浏览器路径、用户数据目录）
+func ensureDefaultInstance(ctx context.Context, db *storage.BoltDB, cfg *config.Config) error {
+	// 检查是否已存在默认实例
+	defaultInstance, err := db.GetDefaultBrowserInstance()
+	if err == nil && defaultInstance != nil {
+		logger.Info(ctx, "Default browser instance already exists: %s (ID: %s)", defaultInstance.Name, defaultInstance.ID)
+
+		// 同步配置文件中的浏览器配置到默认实例
+		if cfg.Browser != nil {
+			needUpdate := false
+
+			// 检查并更新 ControlURL
+			if cfg.Browser.ControlURL != "" && defaultInstance.ControlURL != cfg.Browser.ControlURL {
+				logger.Info(ctx, "Syncing control URL from config: %s -> %s", defaultInstance.ControlURL, cfg.Browser.ControlURL)
+				defaultInstance.ControlURL = cfg.Browser.ControlURL
+				// 如果配置了远程 URL，切换为 remote 类型
+				if defaultInstance.Type != "remote" {
+					defaultInstance.Type = "remote"
+					logger.Info(ctx, "Switching instance type to remote due to control URL")
+				}
+				needUpdate = true
+			} else if cfg.Browser.ControlURL == "" && defaultInstance.Type == "remote" {
+				// 如果配置中移除了 ControlURL，但实例仍是 remote 类型，切换回 local
+				logger.Info(ctx, "Control URL removed from config, switching to local mode")
+				defaultInstance.Type = "local"
+				defaultInstance.ControlURL = ""
+				needUpdate = true
+			}
+
+			// 检查并更新 BinPath（仅 local 模式）
+			if defaultInstance.Type == "local" && cfg.Browser.BinPath != "" && defaultInstance.BinPath != cfg.Browser.BinPath {
+				logger.Info(ctx, "Syncing bin path from config: %s -> %s", defaultInstance.BinPath, cfg.Browser.BinPath)
+				defaultInstance.BinPath = cfg.Browser.BinPath
+				needUpdate = true
+			}
+
+			// 检查并更新 UserDataDir（仅 local 模式）
+			if defaultInstance.Type == "local" && cfg.Browser.UserDataDir != "" && defaultInstance.UserDataDir != cfg.Browser.UserDataDir {
+				logger.Info(ctx, "Syncing user data dir from config: %s -> %s", defaultInstance.UserDataDir, cfg.Browser.UserDataDir)
+				defaultInstance.UserDataDir = cfg.Browser.UserDataDir
+				needUpdate = true
+			}
+
+			// 如果有配置变化，保存实例
+			if needUpdate {
+				logger.Info(ctx, "Updating default browser instance with config changes")
+				return db.SaveBrowserInstance(defaultInstance)
+			}
+		}
+
+		return nil
+	}
+
+	// 查找默认 Chrome 路径
+	var binPath string
+	var userDataDir string
+
+	// 创建默认实例
+	useStealth := true
+	headless := false
+
+	// 根据环境自动设置 headless
+	display := os.Getenv("DISPLAY")
+	waylandDisplay := os.Getenv("WAYLAND_DISPLAY")
+	if runtime.GOOS == "linux" && display == "" && waylandDisplay == "" {
+		headless = true
+		logger.Info(ctx, "Detected headless environment, enabling headless mode for default instance")
+	}
+
+	browserType := "local"
+	controlURL := ""
+
+	if cfg.Browser != nil && cfg.Browser.ControlURL != "" {
+		browserType = "remote"
+		controlURL = cfg.Browser.ControlURL
+	} else {
+
+		// 获取默认浏览器路径（参考 config.go 的逻辑）
+		commonPaths := []string{
+			"/usr/bin/google-chrome",
+			"/usr/bin/chromium-browser",
+			"/usr/bin/chromium",
+			"/usr/bin/google-chrome-stable",
+			"/Applications/Google Chrome.app/Contents/MacOS/Google Chrome",
+			"C:\\Program Files\\Google\\Chrome\\Application\\chrome.exe",
+			"C:\\Program Files (x86)\\Google\\Chrome\\Application\\chrome.exe",
+		}
+
+		for _, path := range commonPaths {
+			if _, err := os.Stat(path); err == nil {
+				binPath = path
+				logger.Info(ctx, "Found browser at: %s", binPath)
+				break
+			}
+		}
+
+		// 如果配置中有指定路径，优先使用配置的路径
+		if cfg.Browser != nil && cfg.Browser.BinPath != "" {
+			binPath = cfg.Browser.BinPath
+			logger.Info(ctx, "Using browser path from config: %s", binPath)
+		}
+
+		// 设置默认用户数据目录
+		homeDir, _ := os.UserHomeDir()
+		if homeDir != "" {
+			userDataDir = filepath.Join(homeDir, ".browserwing", "default-profile")
+		}
+	}
+
+	instance := &models.BrowserInstance{
+		ID:          DefaultInstanceID,
+		Name:        "默认浏览器",
+		Description: "系统默认浏览器实例",
+		Type:        browserType,
+		ControlURL:  controlURL,
+		BinPath:     binPath,
+		UserDataDir: userDataDir,
+		UserAgent:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36",
+		UseStealth:  &useStealth,
+		Headless:    &headless,
+		LaunchArgs: []string{
+			"disable-blink-features=AutomationControlled",
+			"excludeSwitches=enable-automation",
+			"no-first-run",
+			"no-default-browser-check",
+			"window-size=1920,1080",
+			"start-maximized",
+		},
+		IsDefault: true,
+		IsActive:  false,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	// 保存到数据库
+	if err := db.SaveBrowserInstance(instance); err != nil {
+		return fmt.Errorf("failed to save default browser instance: %w", err)
+	}
+
+	logger.Info(ctx, "Created default browser instance: %s (BinPath: %s, UserDataDir: %s)",
+		instance.Name, instance.BinPath, instance.UserDataDir)
+	return nil
+}
@@ -0,0 +1,52 @@
+package browser
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/browserwing/browserwing/config"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/storage"
+)
+
+func TestEnsureDefaultInstance(t *testing.T) {
+	logger.InitLogger(&logger.LoggerConfig{Level: "error"})
+	db, err := storage.NewBoltDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	cfg := &config.Config{Browser: &config.BrowserConfig{}}
+	if err := ensureDefaultInstance(ctx, db, cfg); err != nil {
+		t.Fatal(err)
+	}
+	instance, err := db.GetBrowserInstance(DefaultInstanceID)
+	if err != nil || !instance.IsDefault || instance.Type != "local" {
+		t.Fatalf("expected a local default instance, got %+v (%v)", instance, err)
+	}
+
+	// 配置文件改为远程浏览器后同步到已有的默认实例
+	cfg.Browser.ControlURL = "http://127.0.0.1:9222"
+	if err := ensureDefaultInstance(ctx, db, cfg); err != nil {
+		t.Fatal(err)
+	}
+	instance, _ = db.GetBrowserInstance(DefaultInstanceID)
+	if instance.Type != "remote" || instance.ControlURL != cfg.Browser.ControlURL {
+		t.Errorf("control URL should be synced from config, got %+v", instance)
+	}
+
+	// 新建的 Manager 没有运行中的实例
+	m := NewManager(cfg, db, nil)
+	if m.IsRunning() || m.GetActivePage() != nil {
+		t.Error("new manager should not report a running browser")
+	}
+	if status := m.Status(); status["is_running"] != false {
+		t.Errorf("unexpected status: %v", status)
+	}
+	if err := m.Stop(); err == nil {
+		t.Error("stopping without a running instance should fail")
+	}
+}
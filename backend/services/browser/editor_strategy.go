@@ -0,0 +1,366 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/input"
+)
+
+// EditorStrategy 富文本编辑器输入策略：识别编辑器并以编辑器能正确响应的方式写入文本
+type EditorStrategy interface {
+	// Name 策略名称，用于脚本操作的 editor_strategy 字段
+	Name() string
+	// Detect 判断元素是否属于该编辑器
+	Detect(ctx context.Context, elem *rod.Element) bool
+	// Fill 清空编辑器并写入文本
+	Fill(ctx context.Context, page *rod.Page, elem *rod.Element, text string) error
+}
+
+// 普通输入框（input/textarea）不使用编辑器策略
+const editorStrategyInput = "input"
+
+var (
+	editorStrategiesMu sync.RWMutex
+	// 按顺序检测，专用编辑器优先于通用 contenteditable
+	editorStrategies = []EditorStrategy{
+		&draftJSStrategy{},
+		&proseMirrorStrategy{},
+		&quillStrategy{},
+		&ckEditorStrategy{},
+		&tinyMCEStrategy{},
+		&contentEditableStrategy{},
+	}
+)
+
+// RegisterEditorStrategy 注册自定义编辑器策略，优先于内置策略检测；同名策略会被替换
+func RegisterEditorStrategy(strategy EditorStrategy) {
+	editorStrategiesMu.Lock()
+	defer editorStrategiesMu.Unlock()
+
+	strategies := []EditorStrategy{strategy}
+	for _, s := range editorStrategies {
+		if s.Name() != strategy.Name() {
+			strategies = append(strategies, s)
+		}
+	}
+	editorStrategies = strategies
+}
+
+// resolveEditorStrategy 根据脚本中指定的策略名称或自动检测选择编辑器策略
+// 返回 nil 表示按普通输入框处理
+func resolveEditorStrategy(ctx context.Context, elem *rod.Element, name string) (EditorStrategy, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == editorStrategyInput {
+		return nil, nil
+	}
+
+	editorStrategiesMu.RLock()
+	strategies := append([]EditorStrategy(nil), editorStrategies...)
+	editorStrategiesMu.RUnlock()
+
+	if name != "" && name != "auto" {
+		for _, s := range strategies {
+			if s.Name() == name {
+				return s, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown editor strategy: %s", name)
+	}
+
+	for _, s := range strategies {
+		if s.Detect(ctx, elem) {
+			return s, nil
+		}
+	}
+	return nil, nil
+}
+
+// evalBool 在元素上执行返回布尔值的 JS 函数，出错时返回 false
+func evalBool(elem *rod.Element, js string, args ...interface{}) bool {
+	res, err := elem.Eval(js, args...)
+	if err != nil {
+		return false
+	}
+	return res.Value.Bool()
+}
+
+// editableTarget 返回元素自身或其内部实际可编辑的 contenteditable 元素
+func editableTarget(elem *rod.Element, selector string) *rod.Element {
+	target, err := elem.ElementByJS(rod.Eval(`function (selector) {
+		if (this.isContentEditable && (!selector || this.matches(selector))) return this;
+		return (selector && this.querySelector(selector)) ||
+			this.querySelector('[contenteditable=true], [contenteditable=""]') || this;
+	}`, selector))
+	if err != nil {
+		return elem
+	}
+	return target
+}
+
+// typeIntoEditable 通过键盘全选删除后插入文本，依赖 beforeinput/input 事件的编辑器可以正确更新内部状态
+func typeIntoEditable(ctx context.Context, page *rod.Page, elem *rod.Element, text string) error {
+	if err := elem.Focus(); err != nil {
+		logger.Warn(ctx, "Failed to focus element: %v", err)
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	// contenteditable 元素不支持 SelectAllText，使用 Ctrl+A 全选后 Backspace 清空
+	if err := page.KeyActions().Press(input.ControlLeft).Type('a').Release(input.ControlLeft).Do(); err != nil {
+		logger.Warn(ctx, "Failed to select all: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if err := page.KeyActions().Press(input.Backspace).Do(); err != nil {
+		logger.Warn(ctx, "Failed to clear content: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	// InsertText 支持 Unicode 字符，并触发 beforeinput 和 input 事件
+	if err := page.InsertText(text); err != nil {
+		logger.Warn(ctx, "InsertText failed, trying character-by-character input: %v", err)
+		// 回退方案：逐字符输入（只对 ASCII 字符有效）
+		for _, char := range text {
+			if char < 128 {
+				if err := page.KeyActions().Type(input.Key(char)).Do(); err != nil {
+					return fmt.Errorf("failed to type text: %w", err)
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+		}
+	}
+
+	// 等待编辑器状态更新
+	time.Sleep(300 * time.Millisecond)
+	return nil
+}
+
+// draftJSStrategy Draft.js 编辑器：依赖键盘和 beforeinput 事件维护 EditorState，只能模拟真实输入
+type draftJSStrategy struct{}
+
+func (s *draftJSStrategy) Name() string { return "draftjs" }
+
+func (s *draftJSStrategy) Detect(ctx context.Context, elem *rod.Element) bool {
+	return evalBool(elem, `function () {
+		return !!(this.closest('.DraftEditor-root') || this.querySelector('.public-DraftEditor-content'));
+	}`)
+}
+
+func (s *draftJSStrategy) Fill(ctx context.Context, page *rod.Page, elem *rod.Element, text string) error {
+	return typeIntoEditable(ctx, page, editableTarget(elem, ".public-DraftEditor-content"), text)
+}
+
+// proseMirrorStrategy ProseMirror 编辑器（包括 Tiptap、Remirror 等）：通过 DOM 观察同步输入，模拟真实输入即可
+type proseMirrorStrategy struct{}
+
+func (s *proseMirrorStrategy) Name() string { return "prosemirror" }
+
+func (s *proseMirrorStrategy) Detect(ctx context.Context, elem *rod.Element) bool {
+	return evalBool(elem, `function () {
+		return !!(this.closest('.ProseMirror') || this.querySelector('.ProseMirror'));
+	}`)
+}
+
+func (s *proseMirrorStrategy) Fill(ctx context.Context, page *rod.Page, elem *rod.Element, text string) error {
+	return typeIntoEditable(ctx, page, editableTarget(elem, ".ProseMirror"), text)
+}
+
+// quillStrategy Quill 编辑器：优先使用 Quill 实例的 setText，找不到实例时模拟输入
+type quillStrategy struct{}
+
+func (s *quillStrategy) Name() string { return "quill" }
+
+func (s *quillStrategy) Detect(ctx context.Context, elem *rod.Element) bool {
+	return evalBool(elem, `function () {
+		return !!(this.closest('.ql-container') || this.querySelector('.ql-editor'));
+	}`)
+}
+
+func (s *quillStrategy) Fill(ctx context.Context, page *rod.Page, elem *rod.Element, text string) error {
+	if evalBool(elem, `function (text) {
+		const container = this.closest('.ql-container') || this.querySelector('.ql-container') ||
+			(this.querySelector('.ql-editor') && this.querySelector('.ql-editor').parentElement);
+		if (!container) return false;
+		const quill = container.__quill || (window.Quill && window.Quill.find && window.Quill.find(container));
+		if (!quill || typeof quill.setText !== 'function') return false;
+		quill.setText(text, 'user');
+		quill.setSelection(quill.getLength(), 0, 'user');
+		return true;
+	}`, text) {
+		return nil
+	}
+	logger.Warn(ctx, "Quill instance not found, falling back to keyboard input")
+	return typeIntoEditable(ctx, page, editableTarget(elem, ".ql-editor"), text)
+}
+
+// ckEditorStrategy CKEditor 4/5：通过编辑器实例的 setData 写入，找不到实例时模拟输入
+type ckEditorStrategy struct{}
+
+func (s *ckEditorStrategy) Name() string { return "ckeditor" }
+
+func (s *ckEditorStrategy) Detect(ctx context.Context, elem *rod.Element) bool {
+	return evalBool(elem, `function () {
+		return !!(this.closest('.ck-editor, .ck-editor__editable, .cke_editable, .cke') ||
+			this.querySelector('.ck-editor__editable, .cke_editable'));
+	}`)
+}
+
+func (s *ckEditorStrategy) Fill(ctx context.Context, page *rod.Page, elem *rod.Element, text string) error {
+	if evalBool(elem, `function (text) {
+		const escape = (s) => s.replace(/&/g, '&amp;').replace(/</g, '&lt;').replace(/>/g, '&gt;');
+		const html = text.split('\n').map((line) => '<p>' + escape(line) + '</p>').join('');
+		// CKEditor 5：可编辑元素上挂载了 ckeditorInstance
+		const editable = this.closest('.ck-editor__editable') || this.querySelector('.ck-editor__editable') ||
+			(this.closest('.ck-editor') && this.closest('.ck-editor').querySelector('.ck-editor__editable'));
+		if (editable && editable.ckeditorInstance) {
+			editable.ckeditorInstance.setData(html);
+			return true;
+		}
+		// CKEditor 4：通过全局 CKEDITOR 查找实例
+		if (window.CKEDITOR && window.CKEDITOR.instances) {
+			const instance = Object.values(window.CKEDITOR.instances).find((ed) => {
+				const container = ed.container && ed.container.$;
+				const body = ed.editable && ed.editable() && ed.editable().$;
+				return (container && (container === this || container.contains(this))) || body === this;
+			});
+			if (instance) {
+				instance.setData(html);
+				instance.fire('change');
+				return true;
+			}
+		}
+		return false;
+	}`, text) {
+		return nil
+	}
+	logger.Warn(ctx, "CKEditor instance not found, falling back to keyboard input")
+	return typeIntoEditable(ctx, page, editableTarget(elem, ".ck-editor__editable, .cke_editable"), text)
+}
+
+// tinyMCEStrategy TinyMCE：通过编辑器实例的 setContent 写入（支持 iframe 模式），找不到实例时模拟输入
+type tinyMCEStrategy struct{}
+
+func (s *tinyMCEStrategy) Name() string { return "tinymce" }
+
+func (s *tinyMCEStrategy) Detect(ctx context.Context, elem *rod.Element) bool {
+	return evalBool(elem, `function () {
+		if (this.closest('.tox-tinymce, .mce-content-body') || this.querySelector('.tox-tinymce')) return true;
+		const tinymce = window.tinymce || (window.parent && window.parent !== window && window.parent.tinymce);
+		return !!(tinymce && this.id && tinymce.get(this.id));
+	}`)
+}
+
+func (s *tinyMCEStrategy) Fill(ctx context.Context, page *rod.Page, elem *rod.Element, text string) error {
+	if evalBool(elem, `function (text) {
+		let tinymce = window.tinymce;
+		try {
+			if (!tinymce && window.parent !== window) tinymce = window.parent.tinymce;
+		} catch (e) {}
+		if (!tinymce) return false;
+		const editors = tinymce.get ? [].concat(tinymce.get()) : tinymce.editors || [];
+		const editor = (this.id && tinymce.get(this.id)) || editors.find((ed) => {
+			const container = ed.getContainer && ed.getContainer();
+			return ed.getBody() === this || (container && container.contains(this));
+		});
+		if (!editor) return false;
+		const escape = (s) => s.replace(/&/g, '&amp;').replace(/</g, '&lt;').replace(/>/g, '&gt;');
+		editor.setContent(text.split('\n').map((line) => '<p>' + escape(line) + '</p>').join(''));
+		editor.save();
+		editor.fire('change');
+		editor.fire('input');
+		return true;
+	}`, text) {
+		return nil
+	}
+	logger.Warn(ctx, "TinyMCE instance not found, falling back to keyboard input")
+	return typeIntoEditable(ctx, page, editableTarget(elem, ""), text)
+}
+
+// contentEditableStrategy 通用 contenteditable 元素：模拟输入后补发编辑器常用的更新事件
+type contentEditableStrategy struct{}
+
+func (s *contentEditableStrategy) Name() string { return "contenteditable" }
+
+func (s *contentEditableStrategy) Detect(ctx context.Context, elem *rod.Element) bool {
+	return evalBool(elem, `function () { return this.isContentEditable; }`)
+}
+
+func (s *contentEditableStrategy) Fill(ctx context.Context, page *rod.Page, elem *rod.Element, text string) error {
+	target := editableTarget(elem, "")
+	if err := typeIntoEditable(ctx, page, target, text); err != nil {
+		return err
+	}
+	triggerEditorUpdateEvents(ctx, target, text)
+	return nil
+}
+
+// triggerEditorUpdateEvents 触发额外的事件，确保编辑器（如 CSDN）识别内容变化
+// contenteditable 元素内容与预期不一致时强制写入文本
+func triggerEditorUpdateEvents(ctx context.Context, elem *rod.Element, text string) {
+	time.Sleep(200 * time.Millisecond)
+
+	_, err := elem.Eval(`function (val) {
+		const element = this;
+
+		// 1. 触发标准事件序列
+		['input', 'change', 'keyup'].forEach((eventType) => {
+			try {
+				element.dispatchEvent(new Event(eventType, { bubbles: true, cancelable: true }));
+			} catch (e) {
+				console.warn('Failed to dispatch ' + eventType, e);
+			}
+		});
+
+		// 2. 对于 contenteditable，强制设置内容并触发更多事件
+		if (!element.isContentEditable) return true;
+		try {
+			const currentContent = element.textContent || element.innerText || '';
+			if (currentContent !== val && val) {
+				console.log('[BrowserWing] Force setting content:', val.substring(0, 50));
+				element.textContent = val;
+			}
+
+			// 触发 focus 确保编辑器激活
+			element.focus();
+
+			// 触发 InputEvent（现代编辑器依赖此事件）
+			try {
+				element.dispatchEvent(new InputEvent('input', { bubbles: true, cancelable: true, inputType: 'insertText', data: val }));
+			} catch (e) {
+				console.warn('InputEvent failed', e);
+			}
+
+			// 触发 compositionend（某些亚洲语言输入法编辑器需要）
+			try {
+				element.dispatchEvent(new CompositionEvent('compositionend', { bubbles: true, cancelable: true, data: val }));
+			} catch (e) {
+				console.warn('CompositionEvent failed', e);
+			}
+
+			// 短暂失焦再聚焦，触发编辑器的验证逻辑
+			setTimeout(() => {
+				element.blur();
+				element.dispatchEvent(new Event('blur', { bubbles: true }));
+				setTimeout(() => {
+					element.focus();
+					element.dispatchEvent(new Event('focus', { bubbles: true }));
+				}, 50);
+			}, 100);
+		} catch (e) {
+			console.warn('Failed to update contenteditable', e);
+		}
+		return true;
+	}`, text)
+	if err != nil {
+		logger.Warn(ctx, "Failed to trigger editor update event: %v", err)
+	} else {
+		logger.Info(ctx, "✓ Editor content update event triggered")
+	}
+
+	// 等待编辑器完全响应
+	time.Sleep(500 * time.Millisecond)
+}
@@ -0,0 +1,50 @@
+package browser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-rod/rod"
+)
+
+type fakeEditorStrategy struct{ name string }
+
+func (s *fakeEditorStrategy) Name() string { return s.name }
+
+func (s *fakeEditorStrategy) Detect(ctx context.Context, elem *rod.Element) bool { return false }
+
+func (s *fakeEditorStrategy) Fill(ctx context.Context, page *rod.Page, elem *rod.Element, text string) error {
+	return nil
+}
+
+func TestResolveEditorStrategyByName(t *testing.T) {
+	ctx := context.Background()
+
+	if s, err := resolveEditorStrategy(ctx, nil, "input"); err != nil || s != nil {
+		t.Errorf("input: got %v, %v", s, err)
+	}
+	if s, err := resolveEditorStrategy(ctx, nil, " Quill "); err != nil || s == nil || s.Name() != "quill" {
+		t.Errorf("quill: got %v, %v", s, err)
+	}
+	if _, err := resolveEditorStrategy(ctx, nil, "monaco"); err == nil {
+		t.Errorf("expected error for unknown strategy")
+	}
+}
+
+func TestRegisterEditorStrategy(t *testing.T) {
+	original := editorStrategies
+	defer func() { editorStrategies = original }()
+
+	custom := &fakeEditorStrategy{name: "quill"}
+	RegisterEditorStrategy(custom)
+
+	if editorStrategies[0] != custom {
+		t.Errorf("custom strategy should be detected first")
+	}
+	if len(editorStrategies) != len(original) {
+		t.Errorf("strategy with the same name should be replaced, got %d strategies", len(editorStrategies))
+	}
+	if s, _ := resolveEditorStrategy(context.Background(), nil, "quill"); s != custom {
+		t.Errorf("resolve should return the registered strategy")
+	}
+}
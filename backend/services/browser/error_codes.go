@@ -0,0 +1,82 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/cdp"
+)
+
+// sessionLostMessages 页面或浏览器连接已失效时 CDP 返回的错误信息
+var sessionLostMessages = []string{
+	"Session with given id not found",
+	"Session closed",
+	"Target closed",
+	"No target with given id found",
+	"Not attached to an active page",
+	"use of closed network connection",
+	"websocket: close",
+}
+
+// blockedNavigationReasons 请求被拦截时的网络错误
+var blockedNavigationReasons = []string{
+	"net::ERR_BLOCKED_BY_CLIENT",
+	"net::ERR_BLOCKED_BY_ADMINISTRATOR",
+	"net::ERR_BLOCKED_BY_RESPONSE",
+	"net::ERR_BLOCKED_BY_CSP",
+	"net::ERR_ACCESS_DENIED",
+}
+
+// ClassifyError 返回错误的分类码，无法归类时返回空
+// 显式标注的分类码优先（如 URL 访问策略拦截），其次按 rod/CDP 的错误类型和超时判断
+func ClassifyError(err error) models.ErrorCode {
+	if err == nil {
+		return ""
+	}
+	if code := models.ErrorCodeOf(err); code != "" {
+		return code
+	}
+
+	var notFound *rod.ElementNotFoundError
+	if errors.As(err, &notFound) {
+		return models.ErrorCodeElementNotFound
+	}
+
+	var navErr *rod.NavigationError
+	if errors.As(err, &navErr) {
+		for _, reason := range blockedNavigationReasons {
+			if navErr.Reason == reason {
+				return models.ErrorCodeNavigationBlocked
+			}
+		}
+	}
+
+	var pageNotFound *rod.PageNotFoundError
+	var cdpErr *cdp.Error
+	switch {
+	case errors.As(err, &pageNotFound):
+		return models.ErrorCodeSessionLost
+	case errors.As(err, &cdpErr) && cdpErr.Code == cdp.ErrSessionNotFound.Code:
+		return models.ErrorCodeSessionLost
+	}
+	msg := err.Error()
+	for _, m := range sessionLostMessages {
+		if strings.Contains(msg, m) {
+			return models.ErrorCodeSessionLost
+		}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return models.ErrorCodeTimeout
+	}
+	return ""
+}
+
+// elementNotFoundError 返回标注为 ELEMENT_NOT_FOUND 的错误（等待元素出现超时也属于找不到元素）
+func elementNotFoundError(format string, args ...interface{}) error {
+	return models.WithErrorCode(models.ErrorCodeElementNotFound, fmt.Errorf(format, args...))
+}
@@ -0,0 +1,37 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/cdp"
+)
+
+func TestClassifyError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want models.ErrorCode
+	}{
+		{"nil", nil, ""},
+		{"unclassified", errors.New("boom"), ""},
+		{"explicit code", fmt.Errorf("step 3: %w", elementNotFoundError("element not found: %s", "#submit")), models.ErrorCodeElementNotFound},
+		{"rod element not found", fmt.Errorf("click: %w", &rod.ElementNotFoundError{}), models.ErrorCodeElementNotFound},
+		{"blocked by client", &rod.NavigationError{Reason: "net::ERR_BLOCKED_BY_CLIENT"}, models.ErrorCodeNavigationBlocked},
+		{"other navigation error", &rod.NavigationError{Reason: "net::ERR_NAME_NOT_RESOLVED"}, ""},
+		{"page not found", &rod.PageNotFoundError{}, models.ErrorCodeSessionLost},
+		{"cdp session not found", &cdp.Error{Code: -32001, Message: "Session with given id not found."}, models.ErrorCodeSessionLost},
+		{"target closed", errors.New("Target closed"), models.ErrorCodeSessionLost},
+		{"deadline", fmt.Errorf("wait for element: %w", context.DeadlineExceeded), models.ErrorCodeTimeout},
+		{"block page", fmt.Errorf("step 1: %w", (&BlockPage{Provider: "cloudflare", Reason: "challenge page"}).Err()), models.ErrorCodeCaptchaDetected},
+	}
+	for _, c := range cases {
+		if got := ClassifyError(c.err); got != c.want {
+			t.Errorf("%s: got %q, want %q", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package browser
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// BrowserEventType 浏览器事件类型
+type BrowserEventType string
+
+const (
+	EventTargetCreated     BrowserEventType = "target_created"      // 打开了标签页、弹出窗口或其他 target
+	EventTargetChanged     BrowserEventType = "target_changed"      // target 信息变化（地址、标题等）
+	EventTargetDestroyed   BrowserEventType = "target_destroyed"    // target 关闭
+	EventTargetCrashed     BrowserEventType = "target_crashed"      // target 崩溃（渲染进程退出）
+	EventDialogOpening     BrowserEventType = "dialog_opening"      // 页面弹出 alert/confirm/prompt/beforeunload 对话框
+	EventDownloadWillBegin BrowserEventType = "download_will_begin" // 下载开始
+	EventDownloadProgress  BrowserEventType = "download_progress"   // 下载进度（含完成、取消）
+	EventBindingCalled     BrowserEventType = "binding_called"      // 页面调用了 Runtime.addBinding 注册的函数
+)
+
+// BrowserEvent 事件总线分发的浏览器事件，按类型填充对应字段
+type BrowserEvent struct {
+	Type      BrowserEventType
+	TargetID  proto.TargetTargetID  // 事件所属的 target（下载事件为空）
+	SessionID proto.TargetSessionID // 页面级事件（对话框、binding 调用）来源的会话
+
+	Target           *proto.TargetTargetInfo            // EventTargetCreated、EventTargetChanged
+	Crash            *proto.TargetTargetCrashed         // EventTargetCrashed
+	Dialog           *proto.PageJavascriptDialogOpening // EventDialogOpening
+	DownloadBegin    *proto.BrowserDownloadWillBegin    // EventDownloadWillBegin
+	DownloadProgress *proto.BrowserDownloadProgress     // EventDownloadProgress
+	Binding          *proto.RuntimeBindingCalled        // EventBindingCalled
+}
+
+// eventSubscriber 事件订阅者，types 为空表示接收全部类型
+type eventSubscriber struct {
+	handler func(BrowserEvent)
+	types   map[BrowserEventType]bool
+}
+
+// EventBus 浏览器实例的事件总线
+// 每个实例只有一个浏览器级的事件监听，Recorder、Player、Executor 和实例看护通过订阅接收事件，
+// 不再各自启动无法结束的 EachEvent 监听；实例停止时关闭总线，所有订阅随之失效
+// 处理函数在总线的分发协程中依次调用，不能阻塞（耗时操作应另起协程）
+type EventBus struct {
+	mu       sync.Mutex
+	subs     map[int]*eventSubscriber
+	nextID   int
+	targets  map[proto.TargetTargetID]*proto.TargetTargetInfo // 当前打开的 target
+	sessions map[proto.TargetSessionID]proto.TargetTargetID   // 已连接的会话 -> target
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// newEventBus 创建未连接浏览器的事件总线
+func newEventBus() *EventBus {
+	return &EventBus{
+		subs:     make(map[int]*eventSubscriber),
+		targets:  make(map[proto.TargetTargetID]*proto.TargetTargetInfo),
+		sessions: make(map[proto.TargetSessionID]proto.TargetTargetID),
+		cancel:   func() {},
+		done:     make(chan struct{}),
+	}
+}
+
+// NewEventBus 为浏览器创建事件总线并开始监听，总线不随 ctx 取消，需调用 Close 关闭
+func NewEventBus(ctx context.Context, browser *rod.Browser) *EventBus {
+	b := newEventBus()
+	busCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	b.cancel = cancel
+
+	wait := browser.Context(busCtx).EachEvent(
+		func(e *proto.TargetTargetCreated) {
+			b.publish(BrowserEvent{Type: EventTargetCreated, TargetID: e.TargetInfo.TargetID, Target: e.TargetInfo})
+		},
+		func(e *proto.TargetTargetInfoChanged) {
+			b.publish(BrowserEvent{Type: EventTargetChanged, TargetID: e.TargetInfo.TargetID, Target: e.TargetInfo})
+		},
+		func(e *proto.TargetTargetDestroyed) {
+			b.publish(BrowserEvent{Type: EventTargetDestroyed, TargetID: e.TargetID})
+		},
+		func(e *proto.TargetTargetCrashed) {
+			b.publish(BrowserEvent{Type: EventTargetCrashed, TargetID: e.TargetID, Crash: e})
+		},
+		func(e *proto.TargetAttachedToTarget) {
+			b.attach(e.SessionID, e.TargetInfo)
+		},
+		func(e *proto.TargetDetachedFromTarget) {
+			b.detach(e.SessionID)
+		},
+		func(e *proto.PageJavascriptDialogOpening, sessionID proto.TargetSessionID) {
+			b.publish(BrowserEvent{Type: EventDialogOpening, TargetID: b.sessionTarget(sessionID), SessionID: sessionID, Dialog: e})
+		},
+		func(e *proto.RuntimeBindingCalled, sessionID proto.TargetSessionID) {
+			b.publish(BrowserEvent{Type: EventBindingCalled, TargetID: b.sessionTarget(sessionID), SessionID: sessionID, Binding: e})
+		},
+		func(e *proto.BrowserDownloadWillBegin) {
+			b.publish(BrowserEvent{Type: EventDownloadWillBegin, DownloadBegin: e})
+		},
+		func(e *proto.BrowserDownloadProgress) {
+			b.publish(BrowserEvent{Type: EventDownloadProgress, DownloadProgress: e})
+		},
+	)
+	go func() {
+		defer close(b.done)
+		wait()
+	}()
+
+	// 监听开始前已打开的 target
+	if targets, err := (proto.TargetGetTargets{}).Call(browser); err == nil {
+		b.mu.Lock()
+		for _, info := range targets.TargetInfos {
+			if _, known := b.targets[info.TargetID]; !known {
+				b.targets[info.TargetID] = info
+			}
+		}
+		b.mu.Unlock()
+	}
+	return b
+}
+
+// Subscribe 订阅指定类型的事件（不指定类型时接收全部事件），返回取消订阅的函数
+func (b *EventBus) Subscribe(handler func(BrowserEvent), types ...BrowserEventType) (unsubscribe func()) {
+	sub := &eventSubscriber{handler: handler}
+	if len(types) > 0 {
+		sub.types = make(map[BrowserEventType]bool, len(types))
+		for _, t := range types {
+			sub.types[t] = true
+		}
+	}
+
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.subs[id] = sub
+	b.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, id)
+			b.mu.Unlock()
+		})
+	}
+}
+
+// Targets 返回当前打开的 target
+func (b *EventBus) Targets() []*proto.TargetTargetInfo {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	targets := make([]*proto.TargetTargetInfo, 0, len(b.targets))
+	for _, info := range b.targets {
+		targets = append(targets, info)
+	}
+	return targets
+}
+
+// HasTarget 判断 target 是否属于该总线所在的浏览器且仍然打开
+func (b *EventBus) HasTarget(targetID proto.TargetTargetID) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, ok := b.targets[targetID]
+	return ok
+}
+
+// Done 返回总线停止时关闭的通道（调用了 Close 或浏览器连接断开）
+func (b *EventBus) Done() <-chan struct{} {
+	return b.done
+}
+
+// Close 停止监听并移除所有订阅，不等待正在执行的处理函数
+func (b *EventBus) Close() {
+	b.cancel()
+	b.mu.Lock()
+	b.subs = make(map[int]*eventSubscriber)
+	b.mu.Unlock()
+}
+
+// publish 更新 target 列表并把事件分发给订阅者
+func (b *EventBus) publish(e BrowserEvent) {
+	b.mu.Lock()
+	switch e.Type {
+	case EventTargetCreated, EventTargetChanged:
+		b.targets[e.TargetID] = e.Target
+	case EventTargetDestroyed:
+		delete(b.targets, e.TargetID)
+	}
+	ids := make([]int, 0, len(b.subs))
+	for id := range b.subs {
+		ids = append(ids, id)
+	}
+	b.mu.Unlock()
+
+	// 按订阅顺序分发，订阅者可以在处理函数中取消订阅
+	sort.Ints(ids)
+	for _, id := range ids {
+		b.mu.Lock()
+		sub, ok := b.subs[id]
+		b.mu.Unlock()
+		if !ok || (sub.types != nil && !sub.types[e.Type]) {
+			continue
+		}
+		sub.handler(e)
+	}
+}
+
+// trackPage 记录页面的会话（总线创建前就已连接的页面不会收到 attachedToTarget 事件）
+func (b *EventBus) trackPage(page *rod.Page) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sessions[page.SessionID] = page.TargetID
+}
+
+// attach 记录会话所属的 target，用于定位页面级事件（对话框、binding 调用）的来源
+func (b *EventBus) attach(sessionID proto.TargetSessionID, info *proto.TargetTargetInfo) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sessions[sessionID] = info.TargetID
+}
+
+func (b *EventBus) detach(sessionID proto.TargetSessionID) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.sessions, sessionID)
+}
+
+func (b *EventBus) sessionTarget(sessionID proto.TargetSessionID) proto.TargetTargetID {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.sessions[sessionID]
+}
@@ -0,0 +1,62 @@
+package browser
+
+import (
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+func TestEventBusDispatch(t *testing.T) {
+	b := newEventBus()
+
+	var all, downloads []BrowserEventType
+	b.Subscribe(func(e BrowserEvent) { all = append(all, e.Type) })
+	unsubscribe := b.Subscribe(func(e BrowserEvent) { downloads = append(downloads, e.Type) }, EventDownloadWillBegin, EventDownloadProgress)
+
+	popup := &proto.TargetTargetInfo{TargetID: "popup", Type: proto.TargetTargetInfoTypePage}
+	b.publish(BrowserEvent{Type: EventTargetCreated, TargetID: "popup", Target: popup})
+	b.publish(BrowserEvent{Type: EventDownloadWillBegin, DownloadBegin: &proto.BrowserDownloadWillBegin{GUID: "1"}})
+	if len(all) != 2 || len(downloads) != 1 {
+		t.Fatalf("unexpected dispatch: all=%v downloads=%v", all, downloads)
+	}
+	if !b.HasTarget("popup") || len(b.Targets()) != 1 {
+		t.Fatalf("popup target not tracked")
+	}
+
+	// 取消订阅后不再收到事件
+	unsubscribe()
+	unsubscribe()
+	b.publish(BrowserEvent{Type: EventDownloadProgress, DownloadProgress: &proto.BrowserDownloadProgress{GUID: "1"}})
+	if len(downloads) != 1 {
+		t.Fatalf("received event after unsubscribe: %v", downloads)
+	}
+
+	b.publish(BrowserEvent{Type: EventTargetDestroyed, TargetID: "popup"})
+	if b.HasTarget("popup") {
+		t.Fatalf("destroyed target still tracked")
+	}
+
+	// 关闭后移除所有订阅
+	b.Close()
+	b.publish(BrowserEvent{Type: EventTargetCrashed, TargetID: "main", Crash: &proto.TargetTargetCrashed{}})
+	if len(all) != 4 {
+		t.Fatalf("received event after close: %v", all)
+	}
+}
+
+func TestEventBusUnsubscribeInHandler(t *testing.T) {
+	b := newEventBus()
+
+	calls := 0
+	var unsubscribe func()
+	unsubscribe = b.Subscribe(func(e BrowserEvent) {
+		calls++
+		unsubscribe()
+	}, EventDialogOpening)
+
+	b.publish(BrowserEvent{Type: EventDialogOpening, Dialog: &proto.PageJavascriptDialogOpening{}})
+	b.publish(BrowserEvent{Type: EventDialogOpening, Dialog: &proto.PageJavascriptDialogOpening{}})
+	if calls != 1 {
+		t.Fatalf("handler called %d times", calls)
+	}
+}
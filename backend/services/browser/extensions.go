@@ -0,0 +1,144 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/launcher/flags"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// 旧版 Headless 模式不支持扩展，Chrome 112 起才提供支持扩展的 --headless=new
+const minHeadlessExtensionChrome = 112
+
+// Chrome 137 起品牌版默认禁用 --load-extension，需要关闭该特性才能加载解压扩展
+const disableLoadExtensionSwitchFeature = "DisableLoadExtensionCommandLineSwitch"
+
+// ValidateExtensionDir 检查解压扩展目录：必须是包含有效 manifest.json 的目录
+func ValidateExtensionDir(path string) error {
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("extension path must be absolute: %s", path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("extension path not found: %s", path)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("extension path is not a directory (packed .crx files are not supported): %s", path)
+	}
+	data, err := os.ReadFile(filepath.Join(path, "manifest.json"))
+	if err != nil {
+		return fmt.Errorf("manifest.json not found in extension directory: %s", path)
+	}
+	var manifest struct {
+		ManifestVersion int    `json:"manifest_version"`
+		Name            string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("invalid manifest.json in %s: %w", path, err)
+	}
+	if manifest.ManifestVersion == 0 || manifest.Name == "" {
+		return fmt.Errorf("manifest.json in %s is missing manifest_version or name", path)
+	}
+	return nil
+}
+
+// extensionDirs 返回实例配置中可加载的扩展目录，无效目录记录警告后跳过
+func extensionDirs(ctx context.Context, paths []string) []string {
+	var dirs []string
+	for _, path := range paths {
+		path = filepath.Clean(strings.TrimSpace(path))
+		if err := ValidateExtensionDir(path); err != nil {
+			logger.Warn(ctx, "Skipping extension: %v", err)
+			continue
+		}
+		// --load-extension 使用逗号分隔，包含逗号的路径无法加载
+		if strings.Contains(path, ",") {
+			logger.Warn(ctx, "Skipping extension, path must not contain commas: %s", path)
+			continue
+		}
+		dirs = append(dirs, path)
+	}
+	return dirs
+}
+
+// applyExtensionFlags 设置加载解压扩展的启动参数；Headless 模式切换为支持扩展的 --headless=new
+func applyExtensionFlags(l *launcher.Launcher, dirs []string, headless bool) *launcher.Launcher {
+	list := strings.Join(dirs, ",")
+	l = l.Delete(flags.Flag("disable-extensions")).
+		Set(flags.Flag("load-extension"), list).
+		Set(flags.Flag("disable-extensions-except"), list)
+
+	features, _ := l.GetFlags(flags.Flag("disable-features"))
+	l = l.Set(flags.Flag("disable-features"), append(features, disableLoadExtensionSwitchFeature)...)
+
+	if headless {
+		l = l.HeadlessNew(true)
+	}
+	return l
+}
+
+// checkExtensionSupport 浏览器启动后检查扩展是否真正加载：版本是否支持、扩展后台是否已运行
+func checkExtensionSupport(ctx context.Context, browser *rod.Browser, dirs []string, headless bool) {
+	version, err := proto.BrowserGetVersion{}.Call(browser)
+	if err != nil {
+		logger.Warn(ctx, "Failed to get browser version for extension check: %v", err)
+		return
+	}
+	if major := chromeMajorVersion(version.Product); headless && major > 0 && major < minHeadlessExtensionChrome {
+		logger.Warn(ctx, "%s does not support extensions in headless mode (requires Chrome %d+), extensions will not load",
+			version.Product, minHeadlessExtensionChrome)
+		return
+	}
+
+	targets, err := proto.TargetGetTargets{}.Call(browser)
+	if err != nil {
+		logger.Warn(ctx, "Failed to list targets for extension check: %v", err)
+		return
+	}
+	loaded := map[string]bool{}
+	for _, info := range targets.TargetInfos {
+		if id := extensionIDFromURL(info.URL); id != "" {
+			loaded[id] = true
+		}
+	}
+	// 只有带后台脚本的扩展才会出现在目标列表中，数量不足时仅提示
+	if len(loaded) < len(dirs) {
+		logger.Warn(ctx, "Requested %d extensions, %d running with a background context (extensions without background scripts are not listed)",
+			len(dirs), len(loaded))
+	} else {
+		logger.Info(ctx, "✓ Loaded %d extensions", len(loaded))
+	}
+}
+
+// chromeMajorVersion 从 "HeadlessChrome/120.0.6099.109" 这类产品名中解析主版本号
+func chromeMajorVersion(product string) int {
+	_, version, ok := strings.Cut(product, "/")
+	if !ok {
+		return 0
+	}
+	major, _, _ := strings.Cut(version, ".")
+	n, err := strconv.Atoi(major)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// extensionIDFromURL 返回 chrome-extension:// 地址中的扩展 ID
+func extensionIDFromURL(url string) string {
+	rest, ok := strings.CutPrefix(url, "chrome-extension://")
+	if !ok {
+		return ""
+	}
+	id, _, _ := strings.Cut(rest, "/")
+	return id
+}
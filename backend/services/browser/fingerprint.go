@@ -0,0 +1,246 @@
+package browser
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+)
+
+//go:embed scripts/fingerprint.js
+var fingerprintScript string
+
+// fingerprintPlaceholder recorder.js 中指纹采集函数的占位符
+const fingerprintPlaceholder = "__FINGERPRINT_COLLECTOR__"
+
+func init() {
+	// 录制器与回放共用同一份指纹采集函数，保证两端特征一致
+	recorderScript = strings.Replace(recorderScript, fingerprintPlaceholder, fingerprintScript, 1)
+}
+
+const (
+	// fingerprintAcceptScore 选择器命中的元素达到该相似度时直接采用，不再搜索其他候选
+	fingerprintAcceptScore = 0.8
+	// fingerprintMinScore 候选元素被采用所需的最低相似度
+	fingerprintMinScore = 0.6
+	// fingerprintMinGain 选择器命中元素相似度偏低时，候选需要高出这么多才替换它
+	fingerprintMinGain = 0.15
+	// fingerprintMaxCandidates 单次搜索最多比较的候选元素数量
+	fingerprintMaxCandidates = 2000
+)
+
+// 指纹各部分的权重，合计为 1
+const (
+	fingerprintTagWeight      = 0.15
+	fingerprintAttrWeight     = 0.4
+	fingerprintTextWeight     = 0.3
+	fingerprintAncestorWeight = 0.15
+)
+
+// fingerprintAttrWeights 更能唯一标识元素的属性在属性相似度中的权重，未列出的为 1
+var fingerprintAttrWeights = map[string]float64{
+	"id":          3,
+	"data-testid": 3,
+	"data-test":   3,
+	"data-qa":     3,
+	"data-cy":     3,
+	"name":        2,
+	"aria-label":  2,
+	"href":        2,
+}
+
+// scoreFingerprint 计算录制指纹与当前元素指纹的相似度（0-1）
+func scoreFingerprint(want, got *models.ElementFingerprint) float64 {
+	if want == nil || got == nil {
+		return 0
+	}
+
+	score := 0.0
+	if want.Tag == got.Tag {
+		score += fingerprintTagWeight
+	}
+	score += fingerprintAttrWeight * attrSimilarity(want, got)
+	score += fingerprintTextWeight * shingleSimilarity(want.Text, got.Text)
+	score += fingerprintAncestorWeight * ancestorSimilarity(want.Ancestors, got.Ancestors)
+	return score
+}
+
+// attrSimilarity 加权计算稳定属性的重合程度，哈希一致时为 1
+func attrSimilarity(want, got *models.ElementFingerprint) float64 {
+	if want.AttrHash != "" && want.AttrHash == got.AttrHash {
+		return 1
+	}
+	if len(want.Attrs) == 0 && len(got.Attrs) == 0 {
+		return 1
+	}
+
+	weight := func(name string) float64 {
+		if w, ok := fingerprintAttrWeights[name]; ok {
+			return w
+		}
+		return 1
+	}
+	var matched, total float64
+	for name, value := range want.Attrs {
+		total += weight(name)
+		if got.Attrs[name] == value {
+			matched += weight(name)
+		}
+	}
+	for name := range got.Attrs {
+		if _, ok := want.Attrs[name]; !ok {
+			total += weight(name)
+		}
+	}
+	return matched / total
+}
+
+// textShingles 把文本切分为字符 3-gram 集合，短于 3 个字符的文本整体作为一个 shingle
+func textShingles(text string) map[string]struct{} {
+	runes := []rune(text)
+	shingles := make(map[string]struct{})
+	if len(runes) < 3 {
+		if len(runes) > 0 {
+			shingles[text] = struct{}{}
+		}
+		return shingles
+	}
+	for i := 0; i+3 <= len(runes); i++ {
+		shingles[string(runes[i:i+3])] = struct{}{}
+	}
+	return shingles
+}
+
+// shingleSimilarity 文本 shingle 集合的 Jaccard 相似度，两者都为空时为 1
+func shingleSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	sa, sb := textShingles(a), textShingles(b)
+	if len(sa) == 0 || len(sb) == 0 {
+		return 0
+	}
+	intersection := 0
+	for s := range sa {
+		if _, ok := sb[s]; ok {
+			intersection++
+		}
+	}
+	return float64(intersection) / float64(len(sa)+len(sb)-intersection)
+}
+
+// ancestorSimilarity 按层级比较祖先签名，越近的层级权重越高
+func ancestorSimilarity(want, got []string) float64 {
+	n := max(len(want), len(got))
+	if n == 0 {
+		return 1
+	}
+	var matched, total float64
+	for i := 0; i < n; i++ {
+		w := float64(n - i)
+		total += w
+		if i < len(want) && i < len(got) && want[i] == got[i] {
+			matched += w
+		}
+	}
+	return matched / total
+}
+
+// bestFingerprintMatch 返回相似度最高的候选下标和分数，没有候选时下标为 -1
+// 分数相同时取文档顺序靠前的候选，保证结果确定
+func bestFingerprintMatch(want *models.ElementFingerprint, candidates []*models.ElementFingerprint) (int, float64) {
+	best, bestScore := -1, 0.0
+	for i, candidate := range candidates {
+		if score := scoreFingerprint(want, candidate); score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best, bestScore
+}
+
+// elementFingerprint 采集页面中某个元素的指纹
+func elementFingerprint(element *rod.Element) (*models.ElementFingerprint, error) {
+	res, err := element.Eval(fmt.Sprintf(`function () { return (%s)(this); }`, fingerprintScript))
+	if err != nil {
+		return nil, err
+	}
+	var fp models.ElementFingerprint
+	if err := res.Value.Unmarshal(&fp); err != nil {
+		return nil, err
+	}
+	return &fp, nil
+}
+
+// findByFingerprint 在页面中搜索与录制指纹最相似的元素，相似度低于 minScore 时返回错误
+func findByFingerprint(page *rod.Page, want *models.ElementFingerprint, minScore float64) (*rod.Element, float64, error) {
+	// 同标签的元素优先；录制时没有标签信息时比较全部元素
+	selector := want.Tag
+	if selector == "" {
+		selector = "*"
+	}
+	res, err := page.Eval(fmt.Sprintf(`(selector, limit) => {
+		const collect = %s;
+		return Array.from(document.querySelectorAll(selector)).slice(0, limit).map(el => collect(el));
+	}`, fingerprintScript), selector, fingerprintMaxCandidates)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to collect candidates: %w", err)
+	}
+	var candidates []*models.ElementFingerprint
+	if err := res.Value.Unmarshal(&candidates); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse candidates: %w", err)
+	}
+
+	index, score := bestFingerprintMatch(want, candidates)
+	if index < 0 || score < minScore {
+		return nil, score, fmt.Errorf("no element matches the recorded fingerprint (best score %.2f)", score)
+	}
+	element, err := page.ElementByJS(rod.Eval(`(selector, index) => document.querySelectorAll(selector)[index]`, selector, index))
+	if err != nil {
+		return nil, score, fmt.Errorf("failed to get matched element: %w", err)
+	}
+	return element, score, nil
+}
+
+// resolveByFingerprint 用录制指纹校正或补救选择器定位的结果
+// 选择器命中且相似度足够时直接采用；命中元素相似度偏低（如列表重排后 nth 指向了别的元素）时，
+// 若有明显更相似的候选则替换；选择器未命中时返回最相似的候选
+func (p *Player) resolveByFingerprint(ctx context.Context, page *rod.Page, action models.ScriptAction, element *rod.Element) (*rod.Element, error) {
+	want := action.Fingerprint
+
+	matchedScore := -1.0
+	if element != nil {
+		fp, err := elementFingerprint(element)
+		if err != nil {
+			return element, nil
+		}
+		matchedScore = scoreFingerprint(want, fp)
+		if matchedScore >= fingerprintAcceptScore {
+			return element, nil
+		}
+	}
+
+	minScore := fingerprintMinScore
+	if element != nil {
+		minScore = max(minScore, matchedScore+fingerprintMinGain)
+	}
+	candidate, score, err := findByFingerprint(page.Timeout(5*time.Second), want, minScore)
+	if err != nil {
+		if element != nil {
+			logger.Warn(ctx, "Selector matched an element with fingerprint score %.2f, keeping it: %v", matchedScore, err)
+			return element, nil
+		}
+		return nil, err
+	}
+
+	if element != nil {
+		logger.Warn(ctx, "Selector matched an element with fingerprint score %.2f, switched to a better match (score %.2f)", matchedScore, score)
+	} else {
+		logger.Info(ctx, "✓ Found element by fingerprint (score %.2f)", score)
+	}
+	return candidate, nil
+}
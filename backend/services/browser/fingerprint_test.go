@@ -0,0 +1,74 @@
+package browser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+func TestRecorderScriptIncludesFingerprintCollector(t *testing.T) {
+	if strings.Contains(recorderScript, fingerprintPlaceholder) {
+		t.Fatal("fingerprint placeholder was not replaced in recorder script")
+	}
+	if !strings.Contains(recorderScript, "var collectFingerprint = function (el)") {
+		t.Error("recorder script does not contain the shared fingerprint collector")
+	}
+}
+
+func TestScoreFingerprint(t *testing.T) {
+	recorded := &models.ElementFingerprint{
+		Tag:       "button",
+		Attrs:     map[string]string{"data-testid": "checkout", "class": "btn primary"},
+		AttrHash:  "1a2b3c4d",
+		Text:      "proceed to checkout",
+		Ancestors: []string{"div", "form#cart"},
+	}
+
+	identical := *recorded
+	if score := scoreFingerprint(recorded, &identical); score < 0.999 {
+		t.Errorf("identical fingerprint scored %.3f, want 1", score)
+	}
+
+	// 类名和文案有小改动，但 data-testid 和结构不变
+	restyled := &models.ElementFingerprint{
+		Tag:       "button",
+		Attrs:     map[string]string{"data-testid": "checkout", "class": "btn btn-lg primary"},
+		Text:      "proceed to checkout now",
+		Ancestors: []string{"div", "form#cart"},
+	}
+	other := &models.ElementFingerprint{
+		Tag:       "button",
+		Attrs:     map[string]string{"class": "btn"},
+		Text:      "continue shopping",
+		Ancestors: []string{"div", "form#cart"},
+	}
+
+	restyledScore := scoreFingerprint(recorded, restyled)
+	if restyledScore < fingerprintMinScore {
+		t.Errorf("restyled element scored %.3f, below the acceptance threshold", restyledScore)
+	}
+	if otherScore := scoreFingerprint(recorded, other); otherScore >= restyledScore || otherScore >= fingerprintMinScore {
+		t.Errorf("unrelated button scored %.3f (restyled %.3f)", otherScore, restyledScore)
+	}
+
+	index, _ := bestFingerprintMatch(recorded, []*models.ElementFingerprint{other, restyled, other})
+	if index != 1 {
+		t.Errorf("best match index = %d, want 1", index)
+	}
+	if index, _ := bestFingerprintMatch(recorded, nil); index != -1 {
+		t.Errorf("best match without candidates = %d, want -1", index)
+	}
+}
+
+func TestShingleSimilarity(t *testing.T) {
+	if s := shingleSimilarity("", ""); s != 1 {
+		t.Errorf("empty texts similarity = %.2f, want 1", s)
+	}
+	if s := shingleSimilarity("ok", ""); s != 0 {
+		t.Errorf("text vs empty similarity = %.2f, want 0", s)
+	}
+	if s := shingleSimilarity("sign in", "sign up"); s <= 0 || s >= 1 {
+		t.Errorf("partial overlap similarity = %.2f, want between 0 and 1", s)
+	}
+}
@@ -0,0 +1,385 @@
+package browser
+
+import (
+	"bufio"
+	"compress/lzw"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/jpeg"
+	"io"
+	"math"
+	"os"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+)
+
+// gifMaxWidth GIF 输出的最大宽度，超过时按比例缩小
+const gifMaxWidth = 800
+
+// gifPalette GIF 使用的调色板
+var gifPalette color.Palette = palette.Plan9
+
+// gifFrameResult 单帧处理结果
+type gifFrameResult struct {
+	img  *image.Paletted
+	path string
+	err  error
+}
+
+// encodeGIFFrames 并行解码、裁剪、缩放、量化帧序列，并按原顺序流式写入 GIF
+// 同时处理中的帧数量受 workers 限制，长时间录制也不会把所有帧保留在内存中；返回写入的帧数
+func encodeGIFFrames(ctx context.Context, out io.Writer, files []string, crop image.Rectangle, delay int, workers int) (int, error) {
+	if len(files) == 0 {
+		return 0, fmt.Errorf("no frame files found")
+	}
+	if workers <= 0 {
+		workers = 1
+	}
+
+	// 使用第一个可解码的帧确定画布尺寸
+	var width, height int
+	var err error
+	for _, path := range files {
+		if width, height, err = gifCanvasSize(path, crop); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+	gw, err := newGIFStreamWriter(out, width, height, gifPalette)
+	if err != nil {
+		return 0, err
+	}
+
+	// 按帧顺序排队的结果通道，容量限制了已处理但尚未写入的帧数
+	queue := make(chan chan gifFrameResult, workers*2)
+	sem := make(chan struct{}, workers)
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		defer close(queue)
+		for _, path := range files {
+			result := make(chan gifFrameResult, 1)
+			select {
+			case queue <- result:
+			case <-done:
+				return
+			}
+			select {
+			case sem <- struct{}{}:
+			case <-done:
+				return
+			}
+			go func(path string) {
+				defer func() { <-sem }()
+				img, err := processGIFFrame(path, crop, width, height)
+				result <- gifFrameResult{img: img, path: path, err: err}
+			}(path)
+		}
+	}()
+
+	written := 0
+	for result := range queue {
+		frame := <-result
+		if frame.err != nil {
+			logger.Warn(ctx, "Failed to process frame %s: %v", frame.path, frame.err)
+			continue
+		}
+		if err := gw.WriteFrame(frame.img, delay); err != nil {
+			return written, err
+		}
+		written++
+		if written%10 == 0 {
+			logger.Info(ctx, "Processed %d/%d frames", written, len(files))
+		}
+	}
+
+	if written == 0 {
+		return 0, fmt.Errorf("no frames were processed successfully")
+	}
+	return written, gw.Close()
+}
+
+// gifCanvasSize 根据第一帧（或裁剪区域）的尺寸计算 GIF 画布尺寸（宽度不超过 gifMaxWidth，保持宽高比）
+func gifCanvasSize(path string, crop image.Rectangle) (int, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to open frame file: %w", err)
+	}
+	defer f.Close()
+
+	cfg, err := jpeg.DecodeConfig(f)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to decode frame: %w", err)
+	}
+	frame := image.Rect(0, 0, cfg.Width, cfg.Height)
+	if !crop.Empty() {
+		frame = frame.Intersect(crop)
+	}
+	if frame.Empty() {
+		return 0, 0, fmt.Errorf("invalid frame size %dx%d", frame.Dx(), frame.Dy())
+	}
+
+	width := frame.Dx()
+	if width > gifMaxWidth {
+		width = gifMaxWidth
+	}
+	height := frame.Dy() * width / frame.Dx()
+	if height < 1 {
+		height = 1
+	}
+	return width, height, nil
+}
+
+// processGIFFrame 解码 JPEG 帧，裁剪并缩放到画布尺寸，再用 Floyd-Steinberg 抖动转换为调色板图片
+func processGIFFrame(path string, crop image.Rectangle, width, height int) (*image.Paletted, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	img, err := jpeg.Decode(f)
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if !crop.Empty() {
+		if sub, ok := img.(interface {
+			SubImage(r image.Rectangle) image.Image
+		}); ok {
+			img = sub.SubImage(crop.Intersect(img.Bounds()))
+		}
+	}
+
+	scaled := scaleImage(img, width, height)
+	paletted := image.NewPaletted(scaled.Bounds(), gifPalette)
+	draw.FloydSteinberg.Draw(paletted, scaled.Bounds(), scaled, image.Point{})
+	return paletted, nil
+}
+
+// regionToFrameRect 将录制区域（CSS 像素）按缩放比例换算为帧像素范围，并限制在帧尺寸内
+func regionToFrameRect(region *models.CaptureRegion, scale float64, frameWidth, frameHeight int) image.Rectangle {
+	if region == nil || scale <= 0 {
+		return image.Rectangle{}
+	}
+	rect := image.Rect(
+		int(math.Floor(region.X*scale)),
+		int(math.Floor(region.Y*scale)),
+		int(math.Ceil((region.X+region.Width)*scale)),
+		int(math.Ceil((region.Y+region.Height)*scale)),
+	)
+	return rect.Intersect(image.Rect(0, 0, frameWidth, frameHeight))
+}
+
+// scaleImage 使用区域平均（box filter）将图片缩放到指定尺寸，缩小时每个目标像素取对应源区域的平均值
+func scaleImage(src image.Image, width, height int) *image.RGBA {
+	b := src.Bounds()
+	rgba, ok := src.(*image.RGBA)
+	if !ok || b.Min != (image.Point{}) {
+		rgba = image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+		draw.Draw(rgba, rgba.Bounds(), src, b.Min, draw.Src)
+	}
+	srcW, srcH := b.Dx(), b.Dy()
+	if srcW == width && srcH == height {
+		return rgba
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := y * srcH / height
+		y1 := (y + 1) * srcH / height
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < width; x++ {
+			x0 := x * srcW / width
+			x1 := (x + 1) * srcW / width
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, bl, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				row := rgba.Pix[sy*rgba.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += uint32(p[0])
+					g += uint32(p[1])
+					bl += uint32(p[2])
+					a += uint32(p[3])
+					n++
+				}
+			}
+			d := dst.Pix[y*dst.Stride+x*4 : y*dst.Stride+x*4+4]
+			d[0] = uint8(r / n)
+			d[1] = uint8(g / n)
+			d[2] = uint8(bl / n)
+			d[3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// gifStreamWriter 逐帧写入 GIF89a 文件，所有帧共用全局调色板
+type gifStreamWriter struct {
+	w        *bufio.Writer
+	width    int
+	height   int
+	litWidth int
+	closed   bool
+}
+
+// newGIFStreamWriter 写入文件头、全局调色板和循环播放扩展
+func newGIFStreamWriter(out io.Writer, width, height int, pal color.Palette) (*gifStreamWriter, error) {
+	if width <= 0 || height <= 0 || width > 0xffff || height > 0xffff {
+		return nil, fmt.Errorf("invalid GIF size %dx%d", width, height)
+	}
+	if len(pal) == 0 || len(pal) > 256 {
+		return nil, fmt.Errorf("invalid GIF palette size %d", len(pal))
+	}
+
+	// 调色板大小必须是 2 的幂（2^(sizeBits+1)）
+	sizeBits := 0
+	for 1<<(sizeBits+1) < len(pal) {
+		sizeBits++
+	}
+	litWidth := sizeBits + 1
+	if litWidth < 2 {
+		litWidth = 2
+	}
+
+	gw := &gifStreamWriter{w: bufio.NewWriter(out), width: width, height: height, litWidth: litWidth}
+	w := gw.w
+	w.WriteString("GIF89a")
+	writeUint16(w, width)
+	writeUint16(w, height)
+	w.WriteByte(0x80 | 0x70 | byte(sizeBits)) // 全局调色板、8 位色深
+	w.WriteByte(0)                            // 背景色索引
+	w.WriteByte(0)                            // 像素宽高比
+	for i := 0; i < 1<<(sizeBits+1); i++ {
+		var r, g, b uint32
+		if i < len(pal) {
+			r, g, b, _ = pal[i].RGBA()
+		}
+		w.Write([]byte{byte(r >> 8), byte(g >> 8), byte(b >> 8)})
+	}
+
+	// NETSCAPE2.0 扩展：无限循环播放
+	w.Write([]byte{0x21, 0xff, 0x0b})
+	w.WriteString("NETSCAPE2.0")
+	w.Write([]byte{0x03, 0x01, 0x00, 0x00, 0x00})
+
+	if err := w.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to write GIF header: %w", err)
+	}
+	return gw, nil
+}
+
+// WriteFrame 写入一帧，图片尺寸必须与画布一致，delay 单位为 1/100 秒
+func (g *gifStreamWriter) WriteFrame(img *image.Paletted, delay int) error {
+	if g.closed {
+		return fmt.Errorf("GIF writer is closed")
+	}
+	b := img.Bounds()
+	if b.Dx() != g.width || b.Dy() != g.height {
+		return fmt.Errorf("frame size %dx%d does not match GIF size %dx%d", b.Dx(), b.Dy(), g.width, g.height)
+	}
+
+	w := g.w
+	// 图形控制扩展：帧延迟
+	w.Write([]byte{0x21, 0xf9, 0x04, 0x00})
+	writeUint16(w, delay)
+	w.Write([]byte{0x00, 0x00})
+
+	// 图像描述符：使用全局调色板
+	w.WriteByte(0x2c)
+	writeUint16(w, 0)
+	writeUint16(w, 0)
+	writeUint16(w, g.width)
+	writeUint16(w, g.height)
+	w.WriteByte(0x00)
+
+	// LZW 压缩的图像数据，按最多 255 字节的数据子块写入
+	w.WriteByte(byte(g.litWidth))
+	blocks := &gifBlockWriter{w: w}
+	lw := lzw.NewWriter(blocks, lzw.LSB, g.litWidth)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		start := img.PixOffset(b.Min.X, y)
+		if _, err := lw.Write(img.Pix[start : start+g.width]); err != nil {
+			lw.Close()
+			return fmt.Errorf("failed to encode GIF frame: %w", err)
+		}
+	}
+	if err := lw.Close(); err != nil {
+		return fmt.Errorf("failed to encode GIF frame: %w", err)
+	}
+	if err := blocks.Close(); err != nil {
+		return fmt.Errorf("failed to write GIF frame: %w", err)
+	}
+	return w.Flush()
+}
+
+// Close 写入文件结束标记
+func (g *gifStreamWriter) Close() error {
+	if g.closed {
+		return nil
+	}
+	g.closed = true
+	g.w.WriteByte(0x3b)
+	return g.w.Flush()
+}
+
+func writeUint16(w *bufio.Writer, v int) {
+	w.WriteByte(byte(v))
+	w.WriteByte(byte(v >> 8))
+}
+
+// gifBlockWriter 将数据拆分为 GIF 数据子块（每块最多 255 字节）
+type gifBlockWriter struct {
+	w   *bufio.Writer
+	buf [255]byte
+	n   int
+}
+
+func (b *gifBlockWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		c := copy(b.buf[b.n:], p)
+		b.n += c
+		p = p[c:]
+		written += c
+		if b.n == len(b.buf) {
+			if err := b.flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+func (b *gifBlockWriter) flush() error {
+	if b.n == 0 {
+		return nil
+	}
+	if err := b.w.WriteByte(byte(b.n)); err != nil {
+		return err
+	}
+	_, err := b.w.Write(b.buf[:b.n])
+	b.n = 0
+	return err
+}
+
+// Close 写出剩余数据并写入块结束标记
+func (b *gifBlockWriter) Close() error {
+	if err := b.flush(); err != nil {
+		return err
+	}
+	return b.w.WriteByte(0x00)
+}
@@ -0,0 +1,113 @@
+package browser
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+)
+
+func TestEncodeGIFFrames(t *testing.T) {
+	logger.InitLogger(&logger.LoggerConfig{Level: "error"})
+	dir := t.TempDir()
+	files := make([]string, 0)
+	for i := 0; i < 6; i++ {
+		img := image.NewRGBA(image.Rect(0, 0, 900, 300))
+		fill := color.RGBA{R: uint8(i * 40), G: 100, B: 200, A: 255}
+		for p := 0; p < len(img.Pix); p += 4 {
+			img.Pix[p], img.Pix[p+1], img.Pix[p+2], img.Pix[p+3] = fill.R, fill.G, fill.B, fill.A
+		}
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, img, nil); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, fmt.Sprintf("frame_%05d.jpg", i))
+		if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, path)
+	}
+	// 损坏的帧会被跳过
+	broken := filepath.Join(dir, "frame_99999.jpg")
+	if err := os.WriteFile(broken, []byte("not a jpeg"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	files = append(files, broken)
+
+	var out bytes.Buffer
+	written, err := encodeGIFFrames(context.Background(), &out, files, image.Rectangle{}, 7, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written != 6 {
+		t.Fatalf("written = %d, want 6", written)
+	}
+
+	decoded, err := gif.DecodeAll(&out)
+	if err != nil {
+		t.Fatalf("decode GIF: %v", err)
+	}
+	if len(decoded.Image) != 6 {
+		t.Fatalf("frames = %d, want 6", len(decoded.Image))
+	}
+	if decoded.Config.Width != gifMaxWidth || decoded.Config.Height != 266 {
+		t.Errorf("size = %dx%d, want %dx266", decoded.Config.Width, decoded.Config.Height, gifMaxWidth)
+	}
+	for i, d := range decoded.Delay {
+		if d != 7 {
+			t.Errorf("frame %d delay = %d, want 7", i, d)
+		}
+	}
+	// 帧顺序与输入一致：红色分量递增
+	var lastRed uint32
+	for i, frame := range decoded.Image {
+		r, _, _, _ := frame.At(10, 10).RGBA()
+		if i > 0 && r < lastRed {
+			t.Errorf("frame %d out of order", i)
+		}
+		lastRed = r
+	}
+}
+
+func TestScaleImageAverages(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	src.Set(0, 0, color.RGBA{R: 255, A: 255})
+	src.Set(1, 0, color.RGBA{R: 255, A: 255})
+	src.Set(0, 1, color.RGBA{A: 255})
+	src.Set(1, 1, color.RGBA{A: 255})
+
+	got := scaleImage(src, 1, 1).RGBAAt(0, 0)
+	if got.R != 127 || got.A != 255 {
+		t.Errorf("scaleImage average = %+v, want R=127", got)
+	}
+}
+
+func TestRegionToFrameRect(t *testing.T) {
+	tests := []struct {
+		region *models.CaptureRegion
+		scale  float64
+		want   image.Rectangle
+	}{
+		{region: &models.CaptureRegion{X: 10, Y: 20, Width: 100, Height: 50}, scale: 1, want: image.Rect(10, 20, 110, 70)},
+		{region: &models.CaptureRegion{X: 10, Y: 20, Width: 100, Height: 50}, scale: 0.5, want: image.Rect(5, 10, 55, 35)},
+		{region: &models.CaptureRegion{X: 150, Y: 0, Width: 100, Height: 50}, scale: 1, want: image.Rect(150, 0, 200, 50)},
+		{region: &models.CaptureRegion{X: 300, Y: 0, Width: 10, Height: 10}, scale: 1, want: image.Rectangle{}},
+		{region: nil, scale: 1, want: image.Rectangle{}},
+	}
+
+	for _, tt := range tests {
+		got := regionToFrameRect(tt.region, tt.scale, 200, 100)
+		if got != tt.want && !(got.Empty() && tt.want.Empty()) {
+			t.Errorf("regionToFrameRect(%+v, %v) = %v, want %v", tt.region, tt.scale, got, tt.want)
+		}
+	}
+}
@@ -1,6 +1,21 @@
 package browser
 
-import "strings"
+import (
+	"encoding/json"
+	"maps"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+// 注入页面的界面组件，用于查找内置文本和运行时注册的文本
+const (
+	UIRecorder    = "recorder"     // 录制器
+	UIFloatButton = "float_button" // 浮动录制按钮
+	UIPlayer      = "player"       // 回放指示器
+)
 
 // RecorderI18n 录制器界面的多语言文本映射
 var RecorderI18n = map[string]map[string]string{
@@ -702,25 +717,231 @@ var FloatButtonI18n = map[string]map[string]string{
 	},
 }
 
-// ReplaceI18nPlaceholders 替换脚本中的占位符为对应语言的文本
-func ReplaceI18nPlaceholders(script string, language string, i18nMap map[string]map[string]string) string {
-	// 默认语言为简体中文
-	if language == "" {
-		language = "zh-CN"
+// PlayerI18n 回放指示器（AI 控制状态和步骤名称）的多语言文本映射，语言不存在时使用英文
+var PlayerI18n = map[string]map[string]string{
+	"zh-CN": {
+		// AI 控制指示器
+		"ai.control.title":     "Browserwing AI 控制中",
+		"ai.control.script":    "执行脚本:",
+		"ai.control.ready":     "准备执行脚本...",
+		"ai.control.step":      "步骤",
+		"ai.control.completed": "✓ 完成",
+		"ai.control.success":   "成功",
+		"ai.control.failed":    "失败",
+		// 操作类型
+		"action.click":             "点击元素",
+		"action.input":             "输入文本",
+		"action.select":            "选择选项",
+		"action.navigate":          "页面导航",
+		"action.wait":              "等待加载",
+		"action.sleep":             "延迟等待",
+		"action.extract_text":      "提取文本",
+		"action.extract_html":      "提取HTML",
+		"action.extract_attribute": "提取属性",
+		"action.execute_js":        "执行JS",
+		"action.upload_file":       "上传文件",
+		"action.scroll":            "滚动页面",
+		"action.keyboard":          "键盘事件",
+		"action.screenshot":        "截图",
+		"action.open_tab":          "打开新标签页",
+		"action.switch_tab":        "切换标签页",
+		"action.switch_active_tab": "切换到活跃标签页",
+		"action.capture_xhr":       "捕获XHR请求",
+		"action.ai_control":        "AI控制",
+	},
+	"zh-TW": {
+		// AI 控制指示器
+		"ai.control.title":     "Browserwing AI 控制中",
+		"ai.control.script":    "執行腳本:",
+		"ai.control.ready":     "準備執行腳本...",
+		"ai.control.step":      "步驟",
+		"ai.control.completed": "✓ 完成",
+		"ai.control.success":   "成功",
+		"ai.control.failed":    "失敗",
+		// 操作類型
+		"action.click":             "點擊元素",
+		"action.input":             "輸入文字",
+		"action.select":            "選擇選項",
+		"action.navigate":          "頁面導航",
+		"action.wait":              "等待載入",
+		"action.sleep":             "延遲等待",
+		"action.extract_text":      "提取文字",
+		"action.extract_html":      "提取HTML",
+		"action.extract_attribute": "提取屬性",
+		"action.execute_js":        "執行JS",
+		"action.upload_file":       "上傳檔案",
+		"action.scroll":            "滾動頁面",
+		"action.keyboard":          "鍵盤事件",
+		"action.screenshot":        "截圖",
+		"action.open_tab":          "打開新標籤頁",
+		"action.switch_tab":        "切換標籤頁",
+		"action.switch_active_tab": "切換到活躍標籤頁",
+		"action.capture_xhr":       "捕獲XHR請求",
+		"action.ai_control":        "AI控制",
+	},
+	"en": {
+		// AI Control Indicator
+		"ai.control.title":     "Browserwing AI Control",
+		"ai.control.script":    "Executing Script:",
+		"ai.control.ready":     "Preparing to execute script...",
+		"ai.control.step":      "Step",
+		"ai.control.completed": "✓ Completed",
+		"ai.control.success":   "Success",
+		"ai.control.failed":    "Failed",
+		// Action Types
+		"action.click":             "Click Element",
+		"action.input":             "Input Text",
+		"action.select":            "Select Option",
+		"action.navigate":          "Navigate Page",
+		"action.wait":              "Wait for Load",
+		"action.sleep":             "Sleep",
+		"action.extract_text":      "Extract Text",
+		"action.extract_html":      "Extract HTML",
+		"action.extract_attribute": "Extract Attribute",
+		"action.execute_js":        "Execute JS",
+		"action.upload_file":       "Upload File",
+		"action.scroll":            "Scroll Page",
+		"action.keyboard":          "Keyboard Event",
+		"action.screenshot":        "Screenshot",
+		"action.open_tab":          "Open New Tab",
+		"action.switch_tab":        "Switch Tab",
+		"action.switch_active_tab": "Switch to Active Tab",
+		"action.capture_xhr":       "Capture XHR Request",
+		"action.ai_control":        "AI Control",
+	},
+}
+
+// uiLocales 运行时注册的自定义语言和文本覆盖，按语言代码索引
+var uiLocales = struct {
+	sync.RWMutex
+	byLanguage map[string]*models.UILocale
+}{byLanguage: make(map[string]*models.UILocale)}
+
+// builtinUITexts 返回组件的内置文本和语言不存在时使用的默认语言
+func builtinUITexts(component string) (map[string]map[string]string, string) {
+	switch component {
+	case UIRecorder:
+		return RecorderI18n, "zh-CN"
+	case UIFloatButton:
+		return FloatButtonI18n, "zh-CN"
+	case UIPlayer:
+		return PlayerI18n, "en"
 	}
+	return nil, ""
+}
 
-	// 获取对应语言的文本映射,如果不存在则使用简体中文
-	texts, ok := i18nMap[language]
+// localeTexts 返回自定义语言中组件的文本
+func localeTexts(locale *models.UILocale, component string) map[string]string {
+	switch component {
+	case UIRecorder:
+		return locale.Recorder
+	case UIFloatButton:
+		return locale.FloatButton
+	case UIPlayer:
+		return locale.Player
+	}
+	return nil
+}
+
+// IsBuiltinUILanguage 是否为内置的界面语言
+func IsBuiltinUILanguage(language string) bool {
+	_, ok := RecorderI18n[language]
+	return ok
+}
+
+// UITextKeys 返回组件的全部文本键（按字母排序），用于校验自定义文本
+func UITextKeys(component string) []string {
+	builtin, _ := builtinUITexts(component)
+	seen := make(map[string]bool)
+	for _, texts := range builtin {
+		for key := range texts {
+			seen[key] = true
+		}
+	}
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SetUILocales 替换运行时注册的全部自定义语言（启动时从数据库加载）
+func SetUILocales(locales []*models.UILocale) {
+	uiLocales.Lock()
+	defer uiLocales.Unlock()
+	uiLocales.byLanguage = make(map[string]*models.UILocale, len(locales))
+	for _, locale := range locales {
+		uiLocales.byLanguage[locale.Language] = locale
+	}
+}
+
+// RegisterUILocale 注册或替换自定义语言，之后注入的界面立即使用新文本
+func RegisterUILocale(locale *models.UILocale) {
+	uiLocales.Lock()
+	defer uiLocales.Unlock()
+	uiLocales.byLanguage[locale.Language] = locale
+}
+
+// UnregisterUILocale 删除自定义语言，内置语言恢复为内置文本
+func UnregisterUILocale(language string) {
+	uiLocales.Lock()
+	defer uiLocales.Unlock()
+	delete(uiLocales.byLanguage, language)
+}
+
+// UITexts 返回组件在指定语言下的全部文本：内置文本叠加运行时注册的文本
+// 非内置语言以自定义语言的 Base（默认 en）为基础；未注册的非内置语言使用组件的默认语言
+func UITexts(component, language string) map[string]string {
+	builtin, fallback := builtinUITexts(component)
+	uiLocales.RLock()
+	locale := uiLocales.byLanguage[language]
+	uiLocales.RUnlock()
+
+	base, ok := builtin[language]
 	if !ok {
-		texts = i18nMap["zh-CN"]
+		if locale != nil {
+			fallback = "en"
+			if _, exists := builtin[locale.Base]; exists {
+				fallback = locale.Base
+			}
+		}
+		base = builtin[fallback]
+	}
+	texts := maps.Clone(base)
+	if locale != nil {
+		maps.Copy(texts, localeTexts(locale, component))
+	}
+	return texts
+}
+
+// ReplaceI18nPlaceholders 替换脚本中的占位符为组件在对应语言下的文本
+// 占位符都位于 JS 字符串字面量中，文本先按 JS 字符串转义，自定义语言的文本含引号或 </script> 时不会破坏注入的脚本
+func ReplaceI18nPlaceholders(script string, language string, component string) string {
+	// 默认语言为简体中文
+	if language == "" {
+		language = "zh-CN"
 	}
 
 	// 替换所有占位符
 	result := script
-	for key, value := range texts {
+	for key, value := range UITexts(component, language) {
 		placeholder := "{{" + key + "}}"
-		result = strings.ReplaceAll(result, placeholder, value)
+		result = strings.ReplaceAll(result, placeholder, escapeJSLiteral(value))
 	}
 
 	return result
 }
+
+// jsStringEscaper 转义 JSON 编码没有处理的单引号、反引号和模板字符串插值
+var jsStringEscaper = strings.NewReplacer("'", `\'`, "`", "\\`", "${", `\${`)
+
+// escapeJSLiteral 转义文本，使其可以放在单引号、双引号或反引号字符串字面量中
+// JSON 编码转义双引号、反斜杠、换行和 <、>、&（避免 </script> 提前结束脚本）
+func escapeJSLiteral(s string) string {
+	encoded, err := json.Marshal(s)
+	if err != nil {
+		return ""
+	}
+	return jsStringEscaper.Replace(string(encoded[1 : len(encoded)-1]))
+}
@@ -0,0 +1,75 @@
+package browser
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+func TestUITextsWithCustomLocales(t *testing.T) {
+	t.Cleanup(func() { SetUILocales(nil) })
+
+	// 未注册的语言使用组件的默认语言
+	if got := UITexts(UIFloatButton, "fr")["START_RECORD"]; got != FloatButtonI18n["zh-CN"]["START_RECORD"] {
+		t.Fatalf("unregistered fr = %q", got)
+	}
+	if got := getI18nText("ai.control.success", "fr"); got != "Success" {
+		t.Fatalf("player fallback = %q", got)
+	}
+
+	SetUILocales([]*models.UILocale{
+		{Language: "fr", FloatButton: map[string]string{"START_RECORD": "Démarrer l'enregistrement"}, Player: map[string]string{"ai.control.success": "Réussi"}},
+		{Language: "en", Recorder: map[string]string{"STOP_RECORDING": "Finish"}},
+	})
+
+	script := ReplaceI18nPlaceholders("<b>{{TITLE}}</b>{{START_RECORD}}", "fr", UIFloatButton)
+	if script != `<b>Browserwing</b>Démarrer l\'enregistrement` {
+		t.Fatalf("fr float button = %q", script)
+	}
+	// 未翻译的文本使用英文
+	if got := UITexts(UIRecorder, "fr")["STOP_RECORDING"]; got != RecorderI18n["en"]["STOP_RECORDING"] {
+		t.Fatalf("fr recorder fallback = %q", got)
+	}
+	if got := getI18nText("ai.control.success", "fr"); got != "Réussi" {
+		t.Fatalf("fr player = %q", got)
+	}
+
+	// 覆盖内置语言的部分文本
+	en := UITexts(UIRecorder, "en")
+	if en["STOP_RECORDING"] != "Finish" || en["EMPTY_STEPS"] != RecorderI18n["en"]["EMPTY_STEPS"] {
+		t.Fatalf("en overrides = %q, %q", en["STOP_RECORDING"], en["EMPTY_STEPS"])
+	}
+	if RecorderI18n["en"]["STOP_RECORDING"] == "Finish" {
+		t.Fatal("built-in texts modified")
+	}
+
+	UnregisterUILocale("en")
+	if got := UITexts(UIRecorder, "en")["STOP_RECORDING"]; got != RecorderI18n["en"]["STOP_RECORDING"] {
+		t.Fatalf("after unregister = %q", got)
+	}
+}
+
+func TestReplaceI18nPlaceholdersEscapesTexts(t *testing.T) {
+	t.Cleanup(func() { SetUILocales(nil) })
+
+	SetUILocales([]*models.UILocale{
+		{Language: "fr", FloatButton: map[string]string{"START_RECORD": "it's C:\\temp</script>`${x}`"}},
+	})
+
+	script := ReplaceI18nPlaceholders("var a = '{{START_RECORD}}';", "fr", UIFloatButton)
+	want := "var a = 'it\\'s C:\\\\temp\\u003c/script\\u003e\\`\\${x}\\`';"
+	if script != want {
+		t.Fatalf("escaped script = %s", script)
+	}
+}
+
+func TestUITextKeys(t *testing.T) {
+	keys := UITextKeys(UIFloatButton)
+	if !slices.Equal(keys, []string{"START_RECORD", "TITLE"}) {
+		t.Fatalf("float button keys = %v", keys)
+	}
+	if !slices.Contains(UITextKeys(UIPlayer), "action.click") || !IsBuiltinUILanguage("ja") || IsBuiltinUILanguage("fr") {
+		t.Fatal("unexpected built-in languages or keys")
+	}
+}
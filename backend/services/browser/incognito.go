@@ -0,0 +1,70 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// newEphemeralContext 创建一次执行专用的无痕浏览器上下文
+// 上下文与用户配置文件及其他执行互不共享 Cookie、存储，执行结束后销毁
+func (m *Manager) newEphemeralContext(ctx context.Context, browser *rod.Browser) (*rod.Browser, error) {
+	incognito, err := browser.Incognito()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create incognito browser context: %w", err)
+	}
+
+	// 新上下文需要单独设置下载行为
+	m.mu.RLock()
+	downloadPath := m.downloadPath
+	m.mu.RUnlock()
+	if downloadPath != "" {
+		downloadBehavior := &proto.BrowserSetDownloadBehavior{
+			Behavior:         proto.BrowserSetDownloadBehaviorBehaviorAllow,
+			BrowserContextID: incognito.BrowserContextID,
+			DownloadPath:     downloadPath,
+			EventsEnabled:    true,
+		}
+		if err := downloadBehavior.Call(incognito); err != nil {
+			logger.Warn(ctx, "Failed to set download behavior for incognito context: %v", err)
+		}
+	}
+
+	logger.Info(ctx, "Created incognito browser context: %s", incognito.BrowserContextID)
+	return incognito, nil
+}
+
+// trackEphemeralContext 记录页面所属的无痕上下文，关闭页面时一并销毁
+func (m *Manager) trackEphemeralContext(page *rod.Page, incognito *rod.Browser) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.ephemeralContexts == nil {
+		m.ephemeralContexts = make(map[proto.TargetTargetID]*rod.Browser)
+	}
+	m.ephemeralContexts[page.TargetID] = incognito
+}
+
+// disposeEphemeralContext 销毁页面所属的无痕上下文（包括其中打开的其他页面）
+// 只在摘除记录时持有锁，销毁上下文的浏览器调用在锁外进行
+func (m *Manager) disposeEphemeralContext(ctx context.Context, page *rod.Page) {
+	if page == nil {
+		return
+	}
+	m.mu.Lock()
+	incognito, ok := m.ephemeralContexts[page.TargetID]
+	delete(m.ephemeralContexts, page.TargetID)
+	m.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	if err := incognito.Close(); err != nil {
+		logger.Warn(ctx, "Failed to dispose incognito browser context %s: %v", incognito.BrowserContextID, err)
+		return
+	}
+	logger.Info(ctx, "Disposed incognito browser context: %s", incognito.BrowserContextID)
+}
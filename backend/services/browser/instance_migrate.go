@@ -0,0 +1,183 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// HeadlessMigration 切换 Headless 模式的结果
+type HeadlessMigration struct {
+	InstanceID      string   `json:"instance_id"`
+	Headless        bool     `json:"headless"`
+	UserDataDir     string   `json:"user_data_dir"`
+	CookiesRestored int      `json:"cookies_restored"`
+	ReopenedPages   []string `json:"reopened_pages"`
+	Warnings        []string `json:"warnings,omitempty"`
+}
+
+// SetInstanceHeadless 重启运行中的本地实例并切换 Headless 模式
+// 用户数据目录保持不变；重启前导出的 Cookie 会重新写入（进程被强制结束时 Cookie 可能来不及落盘），
+// 之前打开的页面也会重新打开。适用于先以有界面模式手动登录，再切换到 Headless 在服务器上运行的场景
+func (m *Manager) SetInstanceHeadless(ctx context.Context, instanceID string, headless bool) (*HeadlessMigration, error) {
+	// 重启期间持有实例的生命周期锁，其他实例的操作和状态查询不受影响
+	lock := m.instanceLock(instanceID)
+	lock.Lock()
+
+	m.mu.RLock()
+	runtime, exists := m.instances[instanceID]
+	wasCurrent := m.currentInstanceID == instanceID
+	language := m.currentLanguage
+	m.mu.RUnlock()
+
+	if !exists || runtime == nil {
+		lock.Unlock()
+		return nil, fmt.Errorf("instance %s is not running", instanceID)
+	}
+	if runtime.instance.Type == "remote" {
+		lock.Unlock()
+		return nil, fmt.Errorf("instance %s is remote, headless mode can only be switched for local instances", instanceID)
+	}
+
+	wasHeadless := runtime.instance.Headless != nil && *runtime.instance.Headless
+	result := &HeadlessMigration{
+		InstanceID:  instanceID,
+		Headless:    headless,
+		UserDataDir: runtime.instance.UserDataDir,
+	}
+	if wasHeadless == headless {
+		lock.Unlock()
+		result.Warnings = append(result.Warnings, "instance is already in the requested mode")
+		return result, nil
+	}
+	if runtime.instance.UserDataDir == "" {
+		result.Warnings = append(result.Warnings, "instance has no user_data_dir, only cookies are carried over (local storage is lost)")
+	}
+
+	// 导出 Cookie 和已打开的页面
+	cookies, err := runtime.browser.GetCookies()
+	if err != nil {
+		logger.Warn(ctx, "Failed to export cookies before restart: %v", err)
+		result.Warnings = append(result.Warnings, fmt.Sprintf("failed to export cookies: %v", err))
+	}
+	urls := openPageURLs(runtime)
+
+	logger.Info(ctx, "Restarting instance %s with headless=%v (%d cookies, %d pages)", runtime.instance.Name, headless, len(cookies), len(urls))
+
+	if err := m.stopInstanceInternal(ctx, instanceID); err != nil {
+		lock.Unlock()
+		return nil, fmt.Errorf("failed to stop instance: %w", err)
+	}
+
+	if err := m.saveInstanceHeadless(instanceID, headless); err != nil {
+		lock.Unlock()
+		return nil, err
+	}
+
+	if err := m.startInstanceInternal(ctx, instanceID); err != nil {
+		// 启动失败时恢复原来的模式，避免实例停留在停止状态
+		logger.Error(ctx, "Failed to restart instance with headless=%v: %v, rolling back", headless, err)
+		if rbErr := m.saveInstanceHeadless(instanceID, wasHeadless); rbErr != nil {
+			lock.Unlock()
+			return nil, fmt.Errorf("failed to restart instance: %w (rollback failed: %v)", err, rbErr)
+		}
+		if rbErr := m.startInstanceInternal(ctx, instanceID); rbErr != nil {
+			lock.Unlock()
+			return nil, fmt.Errorf("failed to restart instance: %w (restart in previous mode also failed: %v, instance is stopped)", err, rbErr)
+		}
+		if wasCurrent {
+			m.mu.Lock()
+			m.currentInstanceID = instanceID
+			m.mu.Unlock()
+		}
+		lock.Unlock()
+		return nil, fmt.Errorf("failed to restart instance (rolled back to previous mode): %w", err)
+	}
+
+	m.mu.RLock()
+	restarted := m.instances[instanceID]
+	m.mu.RUnlock()
+	if restarted == nil {
+		lock.Unlock()
+		return nil, fmt.Errorf("instance %s is not running after restart", instanceID)
+	}
+	if len(cookies) > 0 {
+		if err := restarted.browser.SetCookies(proto.CookiesToParams(cookies)); err != nil {
+			logger.Warn(ctx, "Failed to restore cookies: %v", err)
+			result.Warnings = append(result.Warnings, fmt.Sprintf("failed to restore cookies: %v", err))
+		} else {
+			result.CookiesRestored = len(cookies)
+		}
+	}
+
+	// 恢复为当前实例
+	if wasCurrent {
+		m.mu.Lock()
+		m.currentInstanceID = instanceID
+		m.mu.Unlock()
+	}
+	lock.Unlock()
+
+	// 在释放生命周期锁之后重新打开页面（default 实例的 OpenPage 可能需要获取生命周期锁）
+	for _, url := range urls {
+		if err := m.OpenPage(url, language, instanceID, true); err != nil {
+			logger.Warn(ctx, "Failed to reopen page %s: %v", url, err)
+			result.Warnings = append(result.Warnings, fmt.Sprintf("failed to reopen %s: %v", url, err))
+			continue
+		}
+		result.ReopenedPages = append(result.ReopenedPages, url)
+	}
+
+	logger.Info(ctx, "✓ Instance %s restarted with headless=%v", instanceID, headless)
+	return result, nil
+}
+
+// saveInstanceHeadless 持久化实例的 Headless 设置
+func (m *Manager) saveInstanceHeadless(instanceID string, headless bool) error {
+	instance, err := m.db.GetBrowserInstance(instanceID)
+	if err != nil {
+		return fmt.Errorf("failed to load instance: %w", err)
+	}
+	instance.Headless = &headless
+	instance.UpdatedAt = time.Now()
+	if err := m.db.SaveBrowserInstance(instance); err != nil {
+		return fmt.Errorf("failed to save instance: %w", err)
+	}
+	return nil
+}
+
+// openPageURLs 返回实例中已打开的普通网页地址（忽略空白页和浏览器内部页面），活动页面排在最后以便重开后仍为活动页面
+func openPageURLs(runtime *BrowserInstanceRuntime) []string {
+	pages, err := runtime.browser.Pages()
+	if err != nil {
+		return nil
+	}
+	var activeURL string
+	if runtime.activePage != nil {
+		if info, err := runtime.activePage.Info(); err == nil {
+			activeURL = info.URL
+		}
+	}
+
+	var urls []string
+	for _, page := range pages {
+		info, err := page.Info()
+		if err != nil || !isRestorableURL(info.URL) || info.URL == activeURL {
+			continue
+		}
+		urls = append(urls, info.URL)
+	}
+	if isRestorableURL(activeURL) {
+		urls = append(urls, activeURL)
+	}
+	return urls
+}
+
+// isRestorableURL 判断页面地址是否需要在重启后重新打开
+func isRestorableURL(url string) bool {
+	return strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") || strings.HasPrefix(url, "file://")
+}
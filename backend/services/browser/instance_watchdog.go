@@ -0,0 +1,129 @@
+package browser
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// browserEvents 返回运行中实例的事件总线（调用方需持有 m.mu，读锁即可）
+func (m *Manager) browserEvents(instance *models.BrowserInstance) *EventBus {
+	if instance == nil {
+		return nil
+	}
+	if runtime, ok := m.instances[instance.ID]; ok && runtime != nil {
+		return runtime.events
+	}
+	return nil
+}
+
+// PageEvents 返回页面所在浏览器实例的事件总线，页面不属于任何运行中的实例时返回 nil
+func (m *Manager) PageEvents(page *rod.Page) *EventBus {
+	if page == nil {
+		return nil
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, runtime := range m.instances {
+		if runtime != nil && runtime.events != nil && runtime.events.HasTarget(page.TargetID) {
+			return runtime.events
+		}
+	}
+	return nil
+}
+
+// watchInstance 实例看护：活动页面崩溃或被关闭后清除实例的活动页面，避免后续操作继续使用已失效的页面
+func (m *Manager) watchInstance(ctx context.Context, instanceID string, events *EventBus) {
+	ctx = context.WithoutCancel(ctx)
+	events.Subscribe(func(e BrowserEvent) {
+		if e.Type == EventTargetCrashed {
+			logger.Error(ctx, "Page %s in instance %s crashed (status: %s, code: %d)", e.TargetID, instanceID, e.Crash.Status, e.Crash.ErrorCode)
+		}
+		// 处理函数不能阻塞事件分发，事件可能在持有 m.mu 时产生
+		go m.dropActivePage(ctx, instanceID, e.TargetID)
+	}, EventTargetCrashed, EventTargetDestroyed)
+}
+
+// dropActivePage 实例的活动页面是 targetID 时清除
+func (m *Manager) dropActivePage(ctx context.Context, instanceID string, targetID proto.TargetTargetID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	runtime, ok := m.instances[instanceID]
+	if !ok || runtime == nil || runtime.activePage == nil || runtime.activePage.TargetID != targetID {
+		return
+	}
+	runtime.activePage = nil
+	logger.Warn(ctx, "Active page %s of instance %s is gone, cleared active page", targetID, instanceID)
+}
+
+// watchForNewPagesXHR 为实例中新打开的页面自动注入XHR拦截器
+// 这确保了用户在点击"开始录制"之前打开的所有页面都能捕获XHR请求
+func (m *Manager) watchForNewPagesXHR(ctx context.Context, browser *rod.Browser, instanceID string, events *EventBus) {
+	ctx = context.WithoutCancel(ctx)
+	logger.Info(ctx, "Starting XHR interceptor watcher for instance: %s", instanceID)
+
+	// 记录已处理的页面
+	var mu sync.Mutex
+	processedPages := make(map[proto.TargetTargetID]bool)
+
+	inject := func(info *proto.TargetTargetInfo) {
+		if info.Type != proto.TargetTargetInfoTypePage {
+			return
+		}
+		mu.Lock()
+		if processedPages[info.TargetID] {
+			mu.Unlock()
+			return
+		}
+		// 新标签页先以空白页打开，等导航到普通网页后再注入
+		if info.URL == "" || strings.HasPrefix(info.URL, "about:") {
+			mu.Unlock()
+			return
+		}
+		processedPages[info.TargetID] = true
+		mu.Unlock()
+
+		// 跳过浏览器内部页面
+		if strings.HasPrefix(info.URL, "chrome://") ||
+			strings.HasPrefix(info.URL, "chrome-extension://") ||
+			strings.HasPrefix(info.URL, "devtools://") {
+			return
+		}
+
+		go func() {
+			targetID := info.TargetID
+			page, err := browser.PageFromTarget(targetID)
+			if err != nil {
+				logger.Warn(ctx, "Failed to attach to page %s: %v", targetID, err)
+				return
+			}
+			logger.Info(ctx, "Injecting XHR interceptor into new page: %s (URL: %s)", targetID, info.URL)
+
+			// 为新页面设置EvalOnNewDocument（影响该页面内的iframe和导航）
+			if _, err := page.EvalOnNewDocument(xhrInterceptorScriptForManager); err != nil {
+				logger.Warn(ctx, "Failed to set EvalOnNewDocument for page %s: %v", targetID, err)
+			}
+
+			// 立即在页面注入（以防页面已经加载）
+			if _, err := page.Eval(`() => { ` + xhrInterceptorScriptForManager + ` return true; }`); err != nil {
+				logger.Warn(ctx, "Failed to inject XHR interceptor into page %s: %v", targetID, err)
+			} else {
+				logger.Info(ctx, "✓ XHR interceptor injected into page: %s", targetID)
+			}
+		}()
+	}
+
+	events.Subscribe(func(e BrowserEvent) {
+		inject(e.Target)
+	}, EventTargetCreated, EventTargetChanged)
+	for _, info := range events.Targets() {
+		inject(info)
+	}
+}
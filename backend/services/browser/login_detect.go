@@ -0,0 +1,64 @@
+package browser
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-rod/rod"
+)
+
+// loginDetectTimeout 检测登录状态的超时时间
+const loginDetectTimeout = 3 * time.Second
+
+// LoggedOutPage 已退出登录的页面：出现登录表单或返回 401
+type LoggedOutPage struct {
+	Status int    `json:"status,omitempty"` // 文档的 HTTP 状态码，返回 401 时设置
+	Reason string `json:"reason"`           // 命中的特征
+}
+
+// loggedOutScript 识别需要登录的页面：文档返回 401，或页面上只有一个可见的密码框，
+// 且地址是登录页（如 /login、/signin）或表单、标题中有登录字样
+// 有多个密码框的通常是注册或修改密码表单，不算退出登录
+const loggedOutScript = `() => {
+	const nav = performance.getEntriesByType('navigation')[0];
+	if (nav && nav.responseStatus === 401) {
+		return { status: 401, reason: 'HTTP 401' };
+	}
+	const visible = (el) => {
+		const rect = el.getBoundingClientRect();
+		const style = getComputedStyle(el);
+		return rect.width > 0 && rect.height > 0 && style.visibility !== 'hidden' && style.display !== 'none';
+	};
+	const passwords = Array.from(document.querySelectorAll('input[type=password]')).filter(visible);
+	if (passwords.length !== 1) {
+		return null;
+	}
+	if (/(^|[\/._-])(login|log-in|signin|sign-in|sign_in|logon|auth|sso)([\/._-]|$)/i.test(location.pathname)) {
+		return { reason: 'login page: ' + location.pathname };
+	}
+	const form = passwords[0].form;
+	const text = ((form && form.innerText) || '') + ' ' + (document.title || '');
+	if (/log ?in|sign ?in|登录|登入|ログイン|iniciar sesi[oó]n/i.test(text)) {
+		return { reason: 'login form: ' + (document.title || location.pathname) };
+	}
+	return null;
+}`
+
+// DetectLoggedOut 检查页面是否要求登录，不是时返回 nil
+func DetectLoggedOut(ctx context.Context, page *rod.Page) (*LoggedOutPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, loginDetectTimeout)
+	defer cancel()
+
+	res, err := page.Context(ctx).Eval(loggedOutScript)
+	if err != nil {
+		return nil, err
+	}
+	if res.Value.Nil() {
+		return nil, nil
+	}
+	var loggedOut LoggedOutPage
+	if err := res.Value.Unmarshal(&loggedOut); err != nil {
+		return nil, err
+	}
+	return &loggedOut, nil
+}
@@ -14,18 +14,20 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/browserwing/browserwing/config"
 	"github.com/browserwing/browserwing/llm"
 	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/adblock"
 	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/pkg/urlpolicy"
 	"github.com/browserwing/browserwing/storage"
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/launcher"
 	"github.com/go-rod/rod/lib/launcher/flags"
 	"github.com/go-rod/rod/lib/proto"
-	"github.com/go-rod/stealth"
 )
 
 //go:embed scripts/float_button.js
@@ -107,12 +109,20 @@ type AgentManagerInterface interface {
 	SendMessageInterface(ctx context.Context, sessionID, userMessage string, streamChan chan<- any, llmConfigID string) error
 }
 
+// ExecutionNotifier 脚本执行结束的通知（如失败告警）
+// 避免直接依赖通知模块
+type ExecutionNotifier interface {
+	ScriptExecutionFinished(ctx context.Context, execution *models.ScriptExecution)
+}
+
 // BrowserInstanceRuntime 浏览器实例运行时信息
 type BrowserInstanceRuntime struct {
 	instance   *models.BrowserInstance // 实例配置
 	browser    *rod.Browser            // 浏览器对象
 	launcher   *launcher.Launcher      // 启动器（仅本地模式）
 	activePage *rod.Page               // 当前活动页面
+	events     *EventBus               // 浏览器事件总线
+	remote     *remoteClient           // 自动重连的连接（仅远程模式）
 	startTime  time.Time               // 启动时间
 }
 
@@ -122,9 +132,18 @@ type Manager struct {
 	db           *storage.BoltDB
 	llmManager   *llm.Manager
 	agentManager AgentManagerInterface // Agent 管理器接口（用于 AI 控制功能）
-	mu           sync.Mutex
+	notifier     ExecutionNotifier     // 执行结束通知（可能为 nil）
 	recorder     *Recorder
 
+	// mu 保护实例表、当前实例和共享配置等字段，只在读写字段时短暂持有，不能跨越浏览器调用（启动、导航等）
+	// 读多写少，Status、IsRunning 等只读查询使用读锁
+	mu sync.RWMutex
+
+	// 实例生命周期锁：同一实例的启动、停止和重启互斥，耗时的浏览器操作在其中进行，不同实例互不阻塞
+	// 加锁顺序固定为先生命周期锁、后 m.mu，持有 m.mu 时不能获取生命周期锁
+	lifecycleMu    sync.Mutex
+	lifecycleLocks map[string]*sync.Mutex
+
 	// 多实例管理
 	instances         map[string]*BrowserInstanceRuntime // 实例 ID -> 运行时信息
 	currentInstanceID string                             // 当前活动实例 ID
@@ -138,13 +157,21 @@ type Manager struct {
 	inPageRecordingStopped bool                    // 标记是否是页面内停止的录制
 	currentLanguage        string                  // 当前前端语言设置
 	downloadPath           string                  // 下载目录路径
+	netGuard               *urlpolicy.NetworkGuard // 内网访问防护
 
-	// 向后兼容（废弃）
-	browser    *rod.Browser
-	launcher   *launcher.Launcher
-	isRunning  bool
-	startTime  time.Time
-	activePage *rod.Page
+	// 广告/跟踪器拦截的过滤规则（后台加载完成前为 nil）
+	adMatcher   atomic.Pointer[adblock.Matcher]
+	adBlockLoad sync.Once
+
+	// 可访问性扫描使用的 axe-core 脚本（首次扫描时加载）
+	axeMu     sync.Mutex
+	axeScript string
+
+	// 无痕执行的页面 -> 所属的临时浏览器上下文（关闭页面时销毁）
+	ephemeralContexts map[proto.TargetTargetID]*rod.Browser
+
+	// 合并增量抓取状态时串行读写，同一脚本的并发执行不会互相覆盖
+	scriptStateMu sync.Mutex
 }
 
 // NewManager 创建浏览器管理器
@@ -165,456 +192,185 @@ func NewManager(cfg *config.Config, db *storage.BoltDB, llmManager *llm.Manager)
 		recorder.SetDB(db)
 	}
 
+	// 录制脚本和浮动按钮默认注入隔离环境
+	SetMainWorldInjection(cfg.Browser != nil && cfg.Browser.MainWorldInjection)
+
+	var netGuard *urlpolicy.NetworkGuard
+	if cfg.Security.IsPrivateNetworkBlocked() {
+		netGuard = urlpolicy.NewNetworkGuard(true, cfg.Security.PrivateHostAllowlist())
+	}
+
 	return &Manager{
 		config:     cfg,
 		db:         db,
 		llmManager: llmManager,
 		recorder:   recorder,
 		instances:  make(map[string]*BrowserInstanceRuntime),
+		netGuard:   netGuard,
 	}
 }
 
-// SetAgentManager 设置 Agent 管理器
-func (m *Manager) SetAgentManager(agentManager AgentManagerInterface) {
-	m.agentManager = agentManager
-}
-
-// Start 启动浏览器
-func (m *Manager) Start(ctx context.Context) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	if m.isRunning {
-		return fmt.Errorf("browser is already running")
-	}
-
-	logger.Info(ctx, "Starting browser...")
-
-	// 加载默认配置
-	defaultConfig, err := m.db.GetDefaultBrowserConfig()
-	if err != nil {
-		logger.Warn(ctx, "Default browser configuration not found, using system defaults")
-		defaultConfig = m.getDefaultBrowserConfig()
-	}
-	m.defaultBrowserConfig = defaultConfig
-
-	// 加载所有网站特定配置
-	allConfigs, err := m.db.ListBrowserConfigs()
-	if err != nil {
-		logger.Warn(ctx, "Failed to load site configurations: %v", err)
-		m.siteConfigs = []*models.BrowserConfig{}
-	} else {
-		// 过滤出有URL模式的配置
-		m.siteConfigs = []*models.BrowserConfig{}
-		for i := range allConfigs {
-			if allConfigs[i].URLPattern != "" && !allConfigs[i].IsDefault {
-				m.siteConfigs = append(m.siteConfigs, &allConfigs[i])
-			}
-		}
-		logger.Info(ctx, "Loaded %d site-specific configurations", len(m.siteConfigs))
-	}
-
-	logger.Info(ctx, fmt.Sprintf("Using default configuration: %s", defaultConfig.Name))
-
-	var url string
-	var browser *rod.Browser
-	var proxyUsername, proxyPassword string // 代理认证信息
-
-	// 检查是否配置了远程 Chrome URL
-	if m.config.Browser != nil && m.config.Browser.ControlURL != "" {
-		// 使用远程 Chrome
-		controlURL := m.config.Browser.ControlURL
-		logger.Info(ctx, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-		logger.Info(ctx, "Using remote Chrome browser")
-		logger.Info(ctx, fmt.Sprintf("Control URL: %s", controlURL))
-		logger.Info(ctx, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-
-		// 解析 WebSocket URL
-		wsURL, err := resolveWebSocketURL(controlURL)
-		if err != nil {
-			return fmt.Errorf("failed to resolve WebSocket URL from %s: %w", controlURL, err)
-		}
-		logger.Info(ctx, fmt.Sprintf("Resolved WebSocket URL: %s", wsURL))
-		url = wsURL
-
-		// 连接到远程浏览器
-		browser = rod.New().ControlURL(url)
-	} else {
-		// 启动本地浏览器
-		logger.Info(ctx, "Starting local Chrome browser...")
-
-		// 创建启动器
-		// 根据配置决定是否使用 headless 模式
-		headless := false // 默认不使用 headless
-		if defaultConfig.Headless != nil {
-			headless = *defaultConfig.Headless
-		}
-		logger.Info(ctx, fmt.Sprintf("Headless mode: %v", headless))
-
-		l := launcher.New().
-			Headless(headless).
-			Devtools(false).
-			Leakless(false)
-
-		// 代理配置
-		if defaultConfig.Proxy != "" {
-			// 解析代理 URL，提取认证信息
-			proxyAddr, username, password, err := parseProxyURL(defaultConfig.Proxy)
-			if err != nil {
-				logger.Warn(ctx, "Failed to parse proxy URL: %v", err)
-			} else {
-				// 设置代理地址（不包含用户名密码）
-				l = l.Proxy(proxyAddr)
-				proxyUsername = username
-				proxyPassword = password
-
-				if username != "" {
-					logger.Info(ctx, fmt.Sprintf("Using proxy: %s (with authentication)", proxyAddr))
-				} else {
-					logger.Info(ctx, fmt.Sprintf("Using proxy: %s", proxyAddr))
-				}
-			}
-		}
-
-		// 打印启动参数
-		logger.Info(ctx, fmt.Sprintf("Number of launch arguments: %d", len(defaultConfig.LaunchArgs)))
-		for i, arg := range defaultConfig.LaunchArgs {
-			logger.Info(ctx, fmt.Sprintf("  [%d] %s", i+1, arg))
-		}
-
-		// 应用默认配置的启动参数
-		for _, arg := range defaultConfig.LaunchArgs {
-			// 移除前导的--如果存在
-			arg = strings.TrimPrefix(arg, "--")
-
-			// 检查是否是key=value格式
-			if strings.Contains(arg, "=") {
-				parts := strings.SplitN(arg, "=", 2)
-				l = l.Set(flags.Flag(parts[0]), parts[1])
-			} else {
-				// 单个flag
-				l = l.Set(flags.Flag(arg))
-			}
-		}
-
-		// 设置浏览器路径
-		if m.config.Browser != nil && m.config.Browser.BinPath != "" {
-			l = l.Bin(m.config.Browser.BinPath)
-			logger.Info(ctx, fmt.Sprintf("Using browser path: %s", m.config.Browser.BinPath))
-		}
-
-		// 设置用户数据目录 - 关键：这会保存登录状态
-		if m.config.Browser != nil && m.config.Browser.UserDataDir != "" {
-			userDataDir := m.config.Browser.UserDataDir
-
-			// 确保目录存在
-			if err := os.MkdirAll(userDataDir, 0o755); err != nil {
-				logger.Warn(ctx, fmt.Sprintf("Failed to create user data directory: %v", err))
-				logger.Warn(ctx, "Will not use user data directory")
-			} else {
-				// 检查目录是否可写
-				testFile := userDataDir + "/.test"
-				if err := os.WriteFile(testFile, []byte("test"), 0o644); err != nil {
-					logger.Warn(ctx, fmt.Sprintf("User data directory is not writable: %v", err))
-					logger.Warn(ctx, "Will not use user data directory, may cause startup failure")
-				} else {
-					os.Remove(testFile)
-
-					// 清理可能存在的锁文件（在启动前）
-					logger.Info(ctx, "Checking and cleaning up lock files before launch...")
-					if err := m.cleanupSingletonLock(ctx, userDataDir); err != nil {
-						logger.Warn(ctx, "Failed to cleanup singleton lock: %v", err)
-					}
-
-					l = l.UserDataDir(userDataDir)
-					logger.Info(ctx, fmt.Sprintf("✓ Using user data directory: %s", userDataDir))
-				}
-			}
-		} else {
-			logger.Warn(ctx, "User data directory not configured, login state will not be saved")
-		}
-
-		logger.Info(ctx, "Starting browser process...")
-		// 启动浏览器
-		var err error
-		url, err = l.Launch()
-		if err != nil {
-			errMsg := err.Error()
-			logger.Error(ctx, "Failed to start browser, detailed error: %v", err)
-
-			// 如果是 SingletonLock 错误，尝试清理并给出提示
-			if m.config.Browser != nil && m.config.Browser.UserDataDir != "" && strings.Contains(errMsg, "SingletonLock") {
-				logger.Error(ctx, "Browser launch failed due to SingletonLock, attempting cleanup...")
-				m.cleanupSingletonLock(ctx, m.config.Browser.UserDataDir)
-				return fmt.Errorf("failed to launch browser (SingletonLock issue): %w\nTip: The lock files have been cleaned up. Please try starting the browser again", err)
-			}
-
-			// 检查是否是因为 Chrome 已经在运行
-			if strings.Contains(errMsg, "会话") || strings.Contains(errMsg, "session") || strings.Contains(errMsg, "already") {
-				logger.Error(ctx, "")
-				logger.Error(ctx, "❌ Error reason: Chrome browser is already running with the same user data directory")
-				logger.Error(ctx, "")
-				logger.Error(ctx, "Solution:")
-				logger.Error(ctx, "  1. Close all Chrome browser windows")
-				logger.Error(ctx, "  2. Open Task Manager and end all chrome.exe processes")
-				logger.Error(ctx, "  3. Then click the 'Start Browser' button again")
-				logger.Error(ctx, "")
-				logger.Error(ctx, "Or modify user_data_dir in config.toml to another directory")
-				logger.Error(ctx, "")
-				return fmt.Errorf("Chrome is already running with the same user data directory, please close all Chrome windows and try again")
-			}
-
-			logger.Error(ctx, "Possible reasons:")
-			logger.Error(ctx, "  1. Incorrect Chrome path")
-			logger.Error(ctx, "  2. Insufficient permissions or invalid path for user data directory")
-			logger.Error(ctx, "  3. Chrome is being used by another process")
-			logger.Error(ctx, "  4. Blocked by system firewall or security software")
-			return fmt.Errorf("failed to start browser: %w", err)
-		}
-
-		logger.Info(ctx, fmt.Sprintf("Browser control URL: %s", url))
-
-		// 连接到浏览器
-		browser = rod.New().ControlURL(url)
-
-		// 保存 launcher 实例用于后续清理
-		m.launcher = l
-	}
-	if err := browser.Connect(); err != nil {
-		return fmt.Errorf("failed to connect browser: %w", err)
+// GetSecurityConfig 获取安全配置
+func (m *Manager) GetSecurityConfig() *config.SecurityConfig {
+	if m.config == nil {
+		return nil
 	}
+	return m.config.Security
+}
 
-	// 如果代理需要认证，启动认证处理
-	if proxyUsername != "" && proxyPassword != "" {
-		logger.Info(ctx, "Setting up proxy authentication handler...")
-		go browser.HandleAuth(proxyUsername, proxyPassword)()
+// GetOCRConfig 获取图片文字识别配置
+func (m *Manager) GetOCRConfig() *config.OCRConfig {
+	if m.config == nil {
+		return nil
 	}
+	return m.config.OCR
+}
 
-	// 获取并显示浏览器版本信息
-	version, err := browser.Version()
-	if err != nil {
-		logger.Warn(ctx, "Failed to get browser version: %v", err)
-	} else {
-		logger.Info(ctx, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-		logger.Info(ctx, "Browser version information:")
-		logger.Info(ctx, "  Product: %s", version.Product)
-		logger.Info(ctx, "  User-Agent: %s", version.UserAgent)
-		logger.Info(ctx, "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+// GetTimeouts 获取浏览器操作的默认超时配置（未配置时为 nil，其方法返回默认值）
+func (m *Manager) GetTimeouts() *config.TimeoutsConfig {
+	if m == nil || m.config == nil {
+		return nil
 	}
+	return m.config.Timeouts
+}
 
-	// 尝试从数据库加载保存的 Cookie
-	if m.db != nil {
-		cookieStore, err := m.db.GetCookies("browser")
-		if err == nil && cookieStore != nil && len(cookieStore.Cookies) > 0 {
-			// 将 NetworkCookie 转换为 NetworkCookieParam
-			cookieParams := make([]*proto.NetworkCookieParam, 0, len(cookieStore.Cookies))
-			for _, cookie := range cookieStore.Cookies {
-				cookieParams = append(cookieParams, &proto.NetworkCookieParam{
-					Name:     cookie.Name,
-					Value:    cookie.Value,
-					Domain:   cookie.Domain,
-					Path:     cookie.Path,
-					Secure:   cookie.Secure,
-					HTTPOnly: cookie.HTTPOnly,
-					SameSite: cookie.SameSite,
-					Expires:  cookie.Expires,
-				})
-			}
+// SetAgentManager 设置 Agent 管理器
+func (m *Manager) SetAgentManager(agentManager AgentManagerInterface) {
+	m.agentManager = agentManager
+}
 
-			// 设置 Cookie 到浏览器
-			if err := browser.SetCookies(cookieParams); err != nil {
-				logger.Warn(ctx, "Failed to set Cookie: %v", err)
-			} else {
-				logger.Info(ctx, "Loaded %d saved Cookies", len(cookieParams))
-			}
-		} else {
-			logger.Info(ctx, "No saved Cookies found")
-		}
-	}
+// SetNotifier 设置脚本执行结束的通知
+func (m *Manager) SetNotifier(notifier ExecutionNotifier) {
+	m.notifier = notifier
+}
 
-	downloadPath := "./downloads"
-	// 获取绝对路径
-	absDownloadPath, err := os.Getwd()
-	if err == nil {
-		downloadPath = absDownloadPath + "/downloads"
-	}
-	// 判断文件夹是否存在，不存在则创建
-	if _, err := os.Stat(downloadPath); os.IsNotExist(err) {
-		err := os.MkdirAll(downloadPath, 0o755)
-		if err != nil {
-			logger.Warn(ctx, "Failed to create download directory: %v", err)
-		} else {
-			logger.Info(ctx, "Download directory created: %s", downloadPath)
-		}
-	}
+// LLMManager 获取 LLM 管理器（可能为 nil）
+func (m *Manager) LLMManager() *llm.Manager {
+	return m.llmManager
+}
 
-	downloadBehavior := &proto.BrowserSetDownloadBehavior{
-		Behavior:      proto.BrowserSetDownloadBehaviorBehaviorAllow,
-		DownloadPath:  downloadPath, // ⚠ 必须是已存在目录
-		EventsEnabled: true,
-	}
-	err = downloadBehavior.Call(browser)
-	if err != nil {
-		logger.Warn(ctx, "Failed to set download behavior: %v", err)
-	} else {
-		logger.Info(ctx, "Download behavior set: %s, path: %s", downloadBehavior.Behavior, downloadBehavior.DownloadPath)
-	}
+// Start 启动当前实例，没有当前实例时启动 default 实例
+// 启动后恢复通过“保存 Cookie”保存的浏览器 Cookie
+func (m *Manager) Start(ctx context.Context) error {
+	instanceID := m.currentOrDefaultInstanceID()
 
-	// 保存下载路径到 Manager 和 Recorder
-	m.downloadPath = downloadPath
-	m.recorder.SetDownloadPath(downloadPath)
+	lock := m.instanceLock(instanceID)
+	lock.Lock()
+	defer lock.Unlock()
 
-	// 授予剪贴板权限，避免粘贴时弹出权限请求
-	grantPermissions := &proto.BrowserGrantPermissions{
-		Permissions: []proto.BrowserPermissionType{
-			proto.BrowserPermissionTypeClipboardReadWrite,
-			proto.BrowserPermissionTypeClipboardSanitizedWrite,
-		},
+	if m.IsInstanceRunning(instanceID) {
+		return fmt.Errorf("browser is already running")
 	}
-	err = grantPermissions.Call(browser)
-	if err != nil {
-		logger.Warn(ctx, "Failed to grant clipboard permissions: %v", err)
-	} else {
-		logger.Info(ctx, "✓ Clipboard permissions granted (read/write)")
+	if err := m.startInstanceInternal(ctx, instanceID); err != nil {
+		return err
 	}
 
-	m.browser = browser
-	m.isRunning = true
-	m.startTime = time.Now()
+	m.mu.Lock()
+	m.currentInstanceID = instanceID
+	browser, _, _, _ := m.runtimeStateLocked(instanceID)
+	m.mu.Unlock()
 
-	logger.Info(ctx, "Browser started successfully")
+	m.restoreSavedCookies(ctx, browser)
 	return nil
 }
 
-// Stop 停止浏览器
+// Stop 停止当前实例
 func (m *Manager) Stop() error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+	m.mu.RLock()
+	instanceID := m.currentInstanceID
+	running := m.isInstanceRunningLocked(instanceID)
+	m.mu.RUnlock()
 
-	if !m.isRunning {
+	if !running {
 		return fmt.Errorf("browser is not running")
 	}
+	return m.StopInstance(context.Background(), instanceID)
+}
 
-	ctx := context.Background()
-
-	// 检查是否是远程模式
-	isRemoteMode := m.config.Browser != nil && m.config.Browser.ControlURL != ""
-
-	if isRemoteMode {
-		logger.Info(ctx, "Disconnecting from remote browser...")
-	} else {
-		logger.Info(ctx, "Closing browser...")
-	}
-
-	// 1. 先关闭所有页面，让浏览器有机会保存数据
-	if m.browser != nil {
-		if !isRemoteMode {
-			// 仅在本地模式下关闭页面
-			pages, err := m.browser.Pages()
-			if err == nil {
-				for _, page := range pages {
-					_ = page.Close()
-				}
-				logger.Info(ctx, fmt.Sprintf("Closed %d pages", len(pages)))
-			}
-
-			// 2. 等待一下，让浏览器保存数据
-			time.Sleep(1 * time.Second)
-		}
-
-		// 3. 优雅关闭浏览器连接
-		if err := m.browser.Close(); err != nil {
-			logger.Warn(ctx, fmt.Sprintf("Error when closing browser connection: %v", err))
+// StopAll 停止所有运行中的实例（服务退出时调用）
+func (m *Manager) StopAll(ctx context.Context) {
+	for _, instance := range m.ListRunningInstances() {
+		if err := m.StopInstance(ctx, instance.ID); err != nil {
+			logger.Warn(ctx, "Failed to stop instance %s: %v", instance.ID, err)
 		}
 	}
+}
 
-	// 4. 仅在本地模式下关闭浏览器进程
-	if !isRemoteMode {
-		// 再等待一下，确保数据完全写入磁盘
-		time.Sleep(1 * time.Second)
-
-		// 5. ⚠️ 重要：不调用 launcher.Cleanup()，因为它会删除用户数据目录！
-		// 浏览器进程会在连接关闭后自动退出
-		// 如果需要强制杀死进程，可以调用 launcher.Kill() 而不是 Cleanup()
-		if m.launcher != nil {
-			// 只杀死进程，不清理目录
-			m.launcher.Kill()
-			logger.Info(ctx, "Browser process terminated")
-		}
-
-		// 6. 清理本地浏览器的锁文件
-		if m.config.Browser != nil && m.config.Browser.UserDataDir != "" {
-			// 等待浏览器完全退出
-			time.Sleep(500 * time.Millisecond)
+// currentOrDefaultInstanceID 返回当前实例 ID，没有当前实例时返回 default
+func (m *Manager) currentOrDefaultInstanceID() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 
-			if err := m.cleanupSingletonLock(ctx, m.config.Browser.UserDataDir); err != nil {
-				logger.Warn(ctx, "Failed to cleanup singleton lock after stop: %v", err)
-			} else {
-				logger.Info(ctx, "✓ Cleaned up singleton lock files")
-			}
-		}
+	if m.currentInstanceID != "" {
+		return m.currentInstanceID
 	}
+	return DefaultInstanceID
+}
 
-	m.browser = nil
-	m.launcher = nil
-	m.isRunning = false
-
-	if isRemoteMode {
-		logger.Info(ctx, "Disconnected from remote browser successfully")
-	} else {
-		logger.Info(ctx, "Browser fully closed, user data saved")
+// restoreSavedCookies 将数据库中保存的浏览器 Cookie 写入浏览器
+func (m *Manager) restoreSavedCookies(ctx context.Context, browser *rod.Browser) {
+	if m.db == nil || browser == nil {
+		return
 	}
-	return nil
+	cookieStore, err := m.db.GetCookies("browser")
+	if err != nil || cookieStore == nil || len(cookieStore.Cookies) == 0 {
+		logger.Info(ctx, "No saved Cookies found")
+		return
+	}
+	if err := browser.SetCookies(proto.CookiesToParams(cookieStore.Cookies)); err != nil {
+		logger.Warn(ctx, "Failed to set Cookie: %v", err)
+		return
+	}
+	logger.Info(ctx, "Loaded %d saved Cookies", len(cookieStore.Cookies))
 }
 
-// IsRunning 检查浏览器是否运行
+// IsRunning 检查当前实例是否运行
 func (m *Manager) IsRunning() bool {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	// 检查是否有当前实例ID
-	if m.currentInstanceID == "" {
-		return m.isRunning // 向后兼容：如果没有实例ID，使用旧逻辑
-	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 
-	return m.IsInstanceRunning(m.currentInstanceID)
+	return m.isInstanceRunningLocked("")
 }
 
+// IsInstanceRunning 检查指定实例是否运行，instanceID 为空时检查当前实例
 func (m *Manager) IsInstanceRunning(instanceID string) bool {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 
-	if instanceID == "" && m.currentInstanceID == "" {
-		return m.isRunning // 向后兼容：如果没有实例ID，使用旧逻辑
-	}
+	return m.isInstanceRunningLocked(instanceID)
+}
+
+// isInstanceRunningLocked 检查实例是否运行，instanceID 为空时检查当前实例，调用者必须已持有锁（读锁即可）
+func (m *Manager) isInstanceRunningLocked(instanceID string) bool {
 	if instanceID == "" {
 		instanceID = m.currentInstanceID
 	}
-
-	runtime, exists := m.instances[instanceID]
-	return exists && runtime != nil && runtime.browser != nil
+	browser, _, _, running := m.runtimeStateLocked(instanceID)
+	return running && browser != nil
 }
 
-// GetActivePage 获取当前活动页面
+// GetActivePage 获取当前实例的活动页面
 func (m *Manager) GetActivePage() *rod.Page {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	return m.activePage
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	_, page, _, _ := m.runtimeStateLocked(m.currentInstanceID)
+	return page
 }
 
-// SetActivePage 设置当前活动页面（用于脚本回放等场景）
+// SetActivePage 设置当前实例的活动页面（用于脚本回放等场景）
 func (m *Manager) SetActivePage(page *rod.Page) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.activePage = page
+
+	if runtime, ok := m.instances[m.currentInstanceID]; ok && runtime != nil {
+		runtime.activePage = page
+	}
 }
 
 // CloseActivePage 关闭当前活动页面
 func (m *Manager) CloseActivePage(ctx context.Context, page *rod.Page) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	if !m.isRunning || m.browser == nil {
+	if !m.IsRunning() {
 		return fmt.Errorf("browser is not running")
 	}
 
@@ -627,39 +383,53 @@ func (m *Manager) CloseActivePage(ctx context.Context, page *rod.Page) error {
 	if err := page.Close(); err != nil {
 		return fmt.Errorf("failed to close active page: %w", err)
 	}
+	m.disposeEphemeralContext(ctx, page)
 
 	logger.Info(ctx, "Active page closed")
 	return nil
 }
 
-// Status 获取浏览器状态
+// Status 获取当前实例的状态
 func (m *Manager) Status() map[string]interface{} {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+	m.mu.RLock()
+	instanceID := m.currentInstanceID
+	runtime := m.instances[instanceID]
+	m.mu.RUnlock()
 
+	running := runtime != nil && runtime.browser != nil
 	status := map[string]interface{}{
-		"is_running": m.isRunning,
+		"is_running": running,
+	}
+	if instanceID != "" {
+		status["instance_id"] = instanceID
 	}
 
-	if m.isRunning {
-		status["start_time"] = m.startTime.Format(time.RFC3339)
-		status["uptime"] = time.Since(m.startTime).String()
+	if running {
+		status["start_time"] = runtime.startTime.Format(time.RFC3339)
+		status["uptime"] = time.Since(runtime.startTime).String()
 
-		// 获取浏览器页面数量
-		if m.browser != nil {
-			pages, err := m.browser.Pages()
-			if err == nil {
-				status["pages_count"] = len(pages)
-			}
+		// 获取浏览器页面数量（浏览器调用不持有锁）
+		pages, err := runtime.browser.Pages()
+		if err == nil {
+			status["pages_count"] = len(pages)
 		}
 	}
 
 	return status
 }
 
-func (m *Manager) setPageWindow(page *rod.Page) {
+// setPageWindow 设置页面所在窗口的尺寸和 viewport；实例配置了窗口布局时按布局放置
+func (m *Manager) setPageWindow(page *rod.Page, instance *models.BrowserInstance) {
 	ctx := context.Background()
 
+	if placement := windowPlacementOf(instance); placement != nil {
+		err := m.applyWindowPlacement(ctx, page, placement)
+		if err == nil {
+			return
+		}
+		logger.Warn(ctx, "Failed to apply window placement, using default layout: %v", err)
+	}
+
 	// 获取屏幕尺寸
 	screenInfo, err := page.Eval(`() => ({
 		width: window.screen.availWidth,
@@ -715,10 +485,8 @@ func (m *Manager) setPageWindow(page *rod.Page) {
 
 // OpenPage 打开一个新页面
 // instanceID: 指定实例ID，空字符串表示使用当前实例
+// 导航可能耗时较长，期间不持有 m.mu，不影响状态查询和其他实例的操作
 func (m *Manager) OpenPage(url string, language string, instanceID string, norecord ...bool) (err error) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
 	// 捕获 panic 并转换为错误
 	defer func() {
 		if r := recover(); r != nil {
@@ -740,12 +508,7 @@ func (m *Manager) OpenPage(url string, language string, instanceID string, norec
 	}
 
 	// 使用实际的实例ID（可能从空字符串转换为 default）
-	if instance != nil {
-		instanceID = instance.ID
-	} else if instanceID == "" {
-		// 向后兼容：如果没有 instance 对象，使用 currentInstanceID
-		instanceID = m.currentInstanceID
-	}
+	instanceID = instance.ID
 
 	// 检查浏览器连接是否仍然有效
 	ctx := context.Background()
@@ -754,11 +517,18 @@ func (m *Manager) OpenPage(url string, language string, instanceID string, norec
 		return fmt.Errorf("browser connection is closed or invalid: %w", err)
 	}
 
+	// 检查实例的 URL 访问策略
+	if err := m.checkURLPolicy(ctx, instance, url); err != nil {
+		return err
+	}
+
 	// 保存当前语言设置,用于后续注入脚本时的文本替换
 	if language == "" {
 		language = "zh-CN" // 默认简体中文
 	}
+	m.mu.Lock()
 	m.currentLanguage = language
+	m.mu.Unlock()
 
 	// 根据URL匹配配置
 	config := m.getConfigForURL(url)
@@ -766,30 +536,26 @@ func (m *Manager) OpenPage(url string, language string, instanceID string, norec
 
 	var page *rod.Page
 
-	// 根据配置决定是否使用 stealth
-	useStealth := true // 默认使用stealth
-	if config.UseStealth != nil {
-		useStealth = *config.UseStealth
+	// 根据配置决定是否使用 stealth 及其伪装选项
+	stealthOpts := m.stealthOptions(config)
+	page, err = newStealthPage(browser, stealthOpts)
+	if err != nil {
+		return fmt.Errorf("failed to create page: %w", err)
 	}
-
-	if useStealth {
-		page = stealth.MustPage(browser)
-		logger.Info(ctx, "Using Stealth mode")
+	if stealthOpts != nil {
+		logger.Info(ctx, "Using Stealth mode (level: %s)", stealthOpts.Level)
 	} else {
-		page = browser.MustPage()
 		logger.Info(ctx, "Not using Stealth mode")
 	}
 
-	m.setPageWindow(page)
+	m.setPageWindow(page, instance)
 
 	// 设置 User Agent
 	userAgent := config.UserAgent
 	if userAgent == "" {
 		userAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36"
 	}
-	page = page.MustSetUserAgent(&proto.NetworkSetUserAgentOverride{
-		UserAgent: userAgent,
-	})
+	page = page.MustSetUserAgent(userAgentOverride(userAgent, stealthOpts))
 
 	// 导航到目标 URL（设置60秒超时）
 	if err := page.Timeout(60 * time.Second).Navigate(url); err != nil {
@@ -817,28 +583,37 @@ func (m *Manager) OpenPage(url string, language string, instanceID string, norec
 		}
 	}
 
-	if !noRecord {
+	floatButton := m.floatButtonOptions(config)
+	if !noRecord && floatButton.Disabled {
+		logger.Info(ctx, "Float recording button disabled by browser configuration: %s", config.Name)
+	} else if !noRecord {
+		// 注册页面内命令 binding，浮动按钮通过它通知后端开始录制
+		m.mu.RLock()
+		events := m.browserEvents(instance)
+		m.mu.RUnlock()
+		if err := installPageCommands(page, events); err != nil {
+			logger.Warn(ctx, "Failed to register in-page command binding: %v", err)
+		}
+
 		// 注入浮动录制按钮
 		time.Sleep(500 * time.Millisecond) // 等待页面稳定
-		// 替换浮动按钮脚本中的多语言占位符
-		localizedFloatButtonScript := ReplaceI18nPlaceholders(floatButtonScript, m.currentLanguage, FloatButtonI18n)
-		_, err := page.Eval(`() => { ` + localizedFloatButtonScript + ` return true; }`)
+		// 替换浮动按钮脚本中的多语言占位符，外观选项作为参数传入
+		localizedFloatButtonScript := ReplaceI18nPlaceholders(floatButtonScript, language, UIFloatButton)
+		_, err := uiEval(page, `(theme) => { `+localizedFloatButtonScript+` return true; }`, floatButton)
 		if err != nil {
 			logger.Warn(ctx, "Failed to inject float button script: %v", err)
 		} else {
-			logger.Info(ctx, "✓ Float recording button injected successfully (language: %s)", m.currentLanguage)
+			logger.Info(ctx, "✓ Float recording button injected successfully (language: %s)", language)
 
 			// 设置 API 端口信息
 			if m.config.Server != nil && m.config.Server.Port != "" {
 				apiPort := m.config.Server.Port
 				setPortScript := fmt.Sprintf(`() => { window.__browserwingAPIPort__ = "%s"; }`, apiPort)
-				if _, err := page.Eval(setPortScript); err != nil {
+				if _, err := uiEval(page, setPortScript); err != nil {
 					logger.Warn(ctx, "Failed to set API port: %v", err)
 				}
 			}
 		}
-		// 启动轮询检查页面内的录制请求
-		go m.checkInPageRecordingRequests(ctx, page)
 	}
 
 	// 保存当前活动页面到指定实例
@@ -850,13 +625,28 @@ func (m *Manager) OpenPage(url string, language string, instanceID string, norec
 	return nil
 }
 
+// floatButtonOptions 返回页面的浮动按钮选项：网站配置未设置时沿用默认配置
+func (m *Manager) floatButtonOptions(config *models.BrowserConfig) models.FloatButtonOptions {
+	if config != nil && config.FloatButton != nil {
+		return *config.FloatButton
+	}
+	m.mu.RLock()
+	defaultConfig := m.defaultBrowserConfig
+	m.mu.RUnlock()
+	if defaultConfig != nil && defaultConfig.FloatButton != nil {
+		return *defaultConfig.FloatButton
+	}
+	return models.FloatButtonOptions{}
+}
+
 // getConfigForURL 根据URL获取匹配的配置
 func (m *Manager) getConfigForURL(url string) *models.BrowserConfig {
 	ctx := context.Background()
-	logger.Info(ctx, fmt.Sprintf("Starting URL matching: %s, total %d site configurations", url, len(m.siteConfigs)))
+	defaultConfig, siteConfigs := m.browserConfigs(ctx)
+	logger.Info(ctx, fmt.Sprintf("Starting URL matching: %s, total %d site configurations", url, len(siteConfigs)))
 
 	// 遍历所有网站特定配置，找到第一个匹配的
-	for _, config := range m.siteConfigs {
+	for _, config := range siteConfigs {
 		if config.URLPattern != "" {
 			logger.Info(ctx, fmt.Sprintf("Trying to match pattern: %s (configuration: %s)", config.URLPattern, config.Name))
 			// 使用正则表达式匹配
@@ -874,47 +664,67 @@ func (m *Manager) getConfigForURL(url string) *models.BrowserConfig {
 
 	// 没有匹配的，返回默认配置
 	logger.Info(ctx, "No matching site configuration found, using default configuration")
+	return defaultConfig
+}
+
+// browserConfigs 返回默认配置和网站特定配置，默认配置未初始化时从数据库加载
+func (m *Manager) browserConfigs(ctx context.Context) (*models.BrowserConfig, []*models.BrowserConfig) {
+	m.mu.RLock()
+	defaultConfig, siteConfigs := m.defaultBrowserConfig, m.siteConfigs
+	m.mu.RUnlock()
+	if defaultConfig != nil {
+		return defaultConfig, siteConfigs
+	}
+
+	// 读取数据库时不持有锁，并发加载时以先写入的结果为准
+	logger.Info(ctx, "Default configuration not initialized, loading from database")
+	defaultConfig, siteConfigs = m.loadBrowserConfigs(ctx)
 
-	// 如果默认配置未初始化，尝试加载或创建一个
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.defaultBrowserConfig == nil {
-		logger.Info(ctx, "Default configuration not initialized, loading from database")
-		defaultConfig, err := m.db.GetDefaultBrowserConfig()
-		if err != nil {
-			logger.Warn(ctx, "Failed to load default configuration, using system defaults")
-			defaultConfig = m.getDefaultBrowserConfig()
-		}
 		m.defaultBrowserConfig = defaultConfig
+		m.siteConfigs = siteConfigs
+	}
+	return m.defaultBrowserConfig, m.siteConfigs
+}
 
-		// 同时加载网站特定配置
-		allConfigs, err := m.db.ListBrowserConfigs()
-		if err != nil {
-			logger.Warn(ctx, "Failed to load site configurations: %v", err)
-			m.siteConfigs = []*models.BrowserConfig{}
-		} else {
-			m.siteConfigs = []*models.BrowserConfig{}
-			for i := range allConfigs {
-				if allConfigs[i].URLPattern != "" && !allConfigs[i].IsDefault {
-					m.siteConfigs = append(m.siteConfigs, &allConfigs[i])
-				}
-			}
-			logger.Info(ctx, "Loaded %d site-specific configurations", len(m.siteConfigs))
-		}
+// loadBrowserConfigs 从数据库加载默认配置和有 URL 模式的网站特定配置
+func (m *Manager) loadBrowserConfigs(ctx context.Context) (*models.BrowserConfig, []*models.BrowserConfig) {
+	defaultConfig, err := m.db.GetDefaultBrowserConfig()
+	if err != nil {
+		logger.Warn(ctx, "Failed to load default configuration, using system defaults")
+		defaultConfig = m.getDefaultBrowserConfig()
 	}
 
-	return m.defaultBrowserConfig
+	siteConfigs := []*models.BrowserConfig{}
+	allConfigs, err := m.db.ListBrowserConfigs()
+	if err != nil {
+		logger.Warn(ctx, "Failed to load site configurations: %v", err)
+		return defaultConfig, siteConfigs
+	}
+	// 过滤出有URL模式的配置
+	for i := range allConfigs {
+		if allConfigs[i].URLPattern != "" && !allConfigs[i].IsDefault {
+			siteConfigs = append(siteConfigs, &allConfigs[i])
+		}
+	}
+	logger.Info(ctx, "Loaded %d site-specific configurations", len(siteConfigs))
+	return defaultConfig, siteConfigs
 }
 
 // GetCurrentPageCookies 获取当前活动页面的所有 Cookie
 func (m *Manager) GetCurrentPageCookies() (interface{}, error) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+	m.mu.RLock()
+	browser, _, _, running := m.runtimeStateLocked(m.currentInstanceID)
+	m.mu.RUnlock()
 
-	if !m.isRunning || m.browser == nil {
+	if !running || browser == nil {
 		return nil, fmt.Errorf("browser is not running")
 	}
 
 	// 获取浏览器的所有 Cookie
-	cookies, err := m.browser.GetCookies()
+	cookies, err := browser.GetCookies()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get cookies: %w", err)
 	}
@@ -925,19 +735,17 @@ func (m *Manager) GetCurrentPageCookies() (interface{}, error) {
 // StartRecording 开始录制操作
 // StartRecording 开始录制操作
 // instanceID: 指定实例ID，空字符串表示使用当前实例
-func (m *Manager) StartRecording(ctx context.Context, instanceID string) error {
-	m.mu.Lock()
+// recordRequests: 是否记录每个步骤触发的 XHR/Fetch 请求，回放时验证这些请求再次发出
+func (m *Manager) StartRecording(ctx context.Context, instanceID string, recordRequests bool) error {
+	m.mu.RLock()
 	currentLang := m.currentLanguage
+	m.mu.RUnlock()
 	if currentLang == "" {
 		currentLang = "zh-CN" // 默认简体中文
 	}
-	m.mu.Unlock()
-
-	m.mu.Lock()
-	defer m.mu.Unlock()
 
 	// 获取指定实例的浏览器和活动页面
-	_, activePage, _, err := m.getInstanceBrowser(instanceID)
+	_, activePage, instance, err := m.getInstanceBrowser(instanceID)
 	if err != nil {
 		return err
 	}
@@ -952,13 +760,18 @@ func (m *Manager) StartRecording(ctx context.Context, instanceID string) error {
 		return fmt.Errorf("failed to get page info: %w", err)
 	}
 
+	m.mu.RLock()
+	events := m.browserEvents(instance)
+	m.mu.RUnlock()
+	m.recorder.SetEventBus(events)
+	m.recorder.SetRecordRequests(recordRequests)
 	err = m.recorder.StartRecording(ctx, activePage, info.URL, currentLang)
 	if err != nil {
 		return err
 	}
 
 	// 启动录制后,显示录制UI面板
-	_, _ = activePage.Eval(`() => {
+	_, _ = uiEval(activePage, `() => {
 		window.__isRecordingActive__ = true;
 		if (typeof createRecorderUI === 'function') createRecorderUI();
 		if (typeof createHighlightElement === 'function') createHighlightElement();
@@ -969,9 +782,6 @@ func (m *Manager) StartRecording(ctx context.Context, instanceID string) error {
 
 // StopRecording 停止录制
 func (m *Manager) StopRecording(ctx context.Context) ([]models.ScriptAction, []models.DownloadedFile, error) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
 	actions, err := m.recorder.StopRecording(ctx)
 	if err != nil {
 		return nil, nil, err
@@ -993,7 +803,7 @@ func (m *Manager) GetRecordingInfo() map[string]interface{} {
 	info := m.recorder.GetRecordingInfo()
 
 	// 如果是页面内停止的录制,添加标记和actions
-	m.mu.Lock()
+	m.mu.RLock()
 	if m.inPageRecordingStopped {
 		info["in_page_stopped"] = true
 		info["actions"] = m.lastRecordedActions
@@ -1005,7 +815,7 @@ func (m *Manager) GetRecordingInfo() map[string]interface{} {
 		}
 		// 不要清除标记,让前端显示完保存对话框后主动调用清除
 	}
-	m.mu.Unlock()
+	m.mu.RUnlock()
 
 	return info
 }
@@ -1046,15 +856,8 @@ func (m *Manager) PlayScript(ctx context.Context, script *models.Script, instanc
 	}
 
 	// 确定使用的实例ID（从 instance 对象获取，可能从空字符串转换为 default）
-	usedInstanceID := instanceID
-	instanceName := ""
-	if instance != nil {
-		usedInstanceID = instance.ID
-		instanceName = instance.Name
-	} else if usedInstanceID == "" {
-		// 向后兼容：如果没有 instance 对象，使用 currentInstanceID
-		usedInstanceID = m.currentInstanceID
-	}
+	usedInstanceID := instance.ID
+	instanceName := instance.Name
 
 	// 创建执行记录
 	executionID := fmt.Sprintf("%s-%d", script.ID, time.Now().UnixNano())
@@ -1076,39 +879,95 @@ func (m *Manager) PlayScript(ctx context.Context, script *models.Script, instanc
 		scriptURL = script.Actions[0].URL
 	}
 
+	// 检查实例及调用方的 URL 访问策略
+	if scriptURL != "" {
+		if err := m.checkURLPolicy(ctx, instance, scriptURL); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// 展开 call_script 调用的子脚本（需在解析页面组件引用之前，子脚本中也可能引用组件）
+	if m.db != nil && hasSubScriptCalls(script) {
+		var err error
+		if script, err = expandSubScripts(script, m.db.GetScript); err != nil {
+			return nil, nil, err
+		}
+		execution.TotalSteps = len(script.Actions)
+	}
+
+	// 把页面组件引用替换为组件中当前的定位器
+	if m.db != nil && hasComponentRefs(script) {
+		components, err := m.db.ListPageComponents()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load page components: %w", err)
+		}
+		if script, err = resolveComponentRefs(script, components); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// 读取增量抓取状态，替换 ${state.cursor} 等占位符
+	var scrape *scrapeState
+	if script, scrape, err = m.loadScrapeState(script); err != nil {
+		return nil, nil, err
+	}
+
 	config := m.getConfigForURL(scriptURL)
 	logger.Info(ctx, fmt.Sprintf("Replay script URL: %s, using configuration: %s", scriptURL, config.Name))
 
-	// 创建新页面用于回放
-	// 根据配置决定是否使用 stealth
-	useStealth := true // 默认使用stealth
-	if config.UseStealth != nil {
-		useStealth = *config.UseStealth
+	// 无痕执行：在全新的浏览器上下文中回放，不共享用户配置文件的 Cookie
+	// 上下文记录到页面之前出错（包括 panic）时，由 defer 销毁上下文，避免泄漏
+	var pendingContext *rod.Browser
+	defer func() {
+		if pendingContext != nil {
+			if closeErr := pendingContext.Close(); closeErr != nil {
+				logger.Warn(ctx, "Failed to dispose incognito browser context %s: %v", pendingContext.BrowserContextID, closeErr)
+			}
+		}
+	}()
+	if script.Incognito {
+		incognito, err := m.newEphemeralContext(ctx, browser)
+		if err != nil {
+			return nil, nil, err
+		}
+		browser = incognito
+		pendingContext = incognito
 	}
 
-	if useStealth {
-		page = stealth.MustPage(browser)
-		logger.Info(ctx, "Replay using Stealth mode")
+	// 创建新页面用于回放
+	// 根据配置决定是否使用 stealth 及其伪装选项
+	stealthOpts := m.stealthOptions(config)
+	page, err = newStealthPage(browser, stealthOpts)
+	if stealthOpts != nil {
+		logger.Info(ctx, "Replay using Stealth mode (level: %s)", stealthOpts.Level)
 	} else {
-		page = browser.MustPage()
 		logger.Info(ctx, "Replay not using Stealth mode")
 	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create replay page: %w", err)
+	}
 
-	m.setPageWindow(page)
+	if script.Incognito {
+		m.trackEphemeralContext(page, browser)
+		pendingContext = nil
+	}
+	m.setPageWindow(page, instance)
 
 	// 设置 User Agent
 	userAgent := config.UserAgent
 	if userAgent == "" {
 		userAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/142.0.0.0 Safari/537.36"
 	}
-	page = page.MustSetUserAgent(&proto.NetworkSetUserAgentOverride{
-		UserAgent: userAgent,
-	})
+	if script.UserAgent != "" {
+		userAgent = script.UserAgent
+	}
+	page = page.MustSetUserAgent(userAgentOverride(userAgent, stealthOpts))
 
 	// 为回放页面授予剪贴板权限
 	if scriptURL != "" {
 		grantPlayPermissions := &proto.BrowserGrantPermissions{
-			Origin: scriptURL,
+			BrowserContextID: browser.BrowserContextID,
+			Origin:           scriptURL,
 			Permissions: []proto.BrowserPermissionType{
 				proto.BrowserPermissionTypeClipboardReadWrite,
 				proto.BrowserPermissionTypeClipboardSanitizedWrite,
@@ -1122,19 +981,41 @@ func (m *Manager) PlayScript(ctx context.Context, script *models.Script, instanc
 	}
 
 	// 创建播放器，传入当前语言设置
+	m.mu.RLock()
 	currentLang := m.currentLanguage
+	m.mu.RUnlock()
 	if currentLang == "" {
 		currentLang = "zh-CN" // 默认简体中文
 	}
 	player := NewPlayer(currentLang)
 	player.agentManager = m.agentManager     // 设置 Agent 管理器用于 AI 控制功能
 	player.browserManager = m                // 设置 Browser 管理器用于同步活跃页面
-
-	// 设置下载路径并启动下载监听
-	if m.downloadPath != "" {
-		player.SetDownloadPath(m.downloadPath)
-		player.StartDownloadListener(ctx, browser)
-		logger.Info(ctx, "Download tracking enabled for playback, path: %s", m.downloadPath)
+	player.urlChecker = func(ctx context.Context, rawURL string) error {
+		return m.checkURLPolicy(ctx, instance, rawURL)
+	}
+	player.a11yScanner = m.ScanAccessibility
+	player.uploadResolver = m.ResolveUploadPaths
+	player.loginResolver = func(ctx context.Context, pageURL string) (*models.Script, error) {
+		return m.loginScriptFor(pageURL, script.ID)
+	}
+	player.scrapeState = scrape
+	player.elementWait, player.navigationTimeout = playbackTimeouts(m.GetTimeouts(), script)
+
+	// 本次执行的临时工作目录，提前返回时按失败处理
+	workdir := m.newExecutionWorkdir(ctx, executionID)
+	defer workdir.release(ctx, true)
+	player.SetWorkDir(workdir.Dir())
+
+	// 设置下载路径并启动下载监听（配置了路径模板时使用本次执行的子目录）
+	downloadPath, restoreDownloads := m.prepareExecutionDownloads(ctx, browser, execution)
+	defer restoreDownloads()
+	if downloadPath != "" {
+		player.SetDownloadPath(downloadPath)
+		m.mu.RLock()
+		events := m.browserEvents(instance)
+		m.mu.RUnlock()
+		player.StartDownloadListener(ctx, events)
+		logger.Info(ctx, "Download tracking enabled for playback, path: %s", downloadPath)
 	}
 
 	// 检查是否需要录制视频
@@ -1165,18 +1046,35 @@ func (m *Manager) PlayScript(ctx context.Context, script *models.Script, instanc
 			}
 
 			logger.Info(ctx, "Starting video recording: %s (frame rate: %d, quality: %d)", videoPath, frameRate, quality)
-			if err := player.StartVideoRecording(page, videoPath, frameRate, quality); err != nil {
+			if err := player.StartVideoRecording(page, videoPath, VideoRecordingOptions{
+				FrameRate: frameRate,
+				Quality:   quality,
+				MaxWidth:  recordingConfig.MaxWidth,
+				MaxHeight: recordingConfig.MaxHeight,
+				Region:    recordingConfig.Region,
+			}); err != nil {
 				logger.Warn(ctx, "Failed to start video recording: %v", err)
 				videoPath = "" // 清空路径，表示录制失败
 			}
 		}
 	}
 
+	// 性能采集（CPU 降速、Web Vitals、性能 trace）
+	perf := startPerformanceCapture(ctx, page, script.Performance)
+
 	// 执行回放
-	playErr := player.PlayScript(ctx, page, script, m.currentLanguage)
+	playErr := player.PlayScript(ctx, page, script, currentLang)
+
+	if perf != nil {
+		tracePath := ""
+		if script.Performance.Trace {
+			tracePath = m.performanceTracePath(ctx, execution)
+		}
+		execution.Performance = perf.stop(ctx, tracePath)
+	}
 
 	// 停止下载监听
-	if m.downloadPath != "" {
+	if downloadPath != "" {
 		player.StopDownloadListener(ctx)
 	}
 
@@ -1198,12 +1096,40 @@ func (m *Manager) PlayScript(ctx context.Context, script *models.Script, instanc
 	// 记录统计信息
 	execution.SuccessSteps = player.GetSuccessCount()
 	execution.FailedSteps = player.GetFailCount()
+	execution.Steps = player.GetStepOutcomes()
 	execution.ExtractedData = player.GetExtractedData()
 
+	// 抓取数据后处理，失败时按执行失败处理，执行记录中保留原始数据
+	if playErr == nil && script.Transform.Enabled() {
+		if transformed, err := transformExtractedData(ctx, browser, script.Transform, execution.ExtractedData); err != nil {
+			logger.Warn(ctx, "Failed to transform extracted data: %v", err)
+			playErr = err
+		} else {
+			execution.ExtractedData = transformed
+		}
+	}
+
+	// 校验抓取数据的结构，不符合时标记执行记录并通知，执行仍为成功
+	if playErr == nil && len(script.OutputSchema) > 0 {
+		if err := checkOutputSchema(script.OutputSchema, execution.ExtractedData); err != nil {
+			logger.Warn(ctx, "Extracted data does not match the output schema: %v", err)
+			execution.SchemaError = err.Error()
+		}
+	}
+
+	// 计算抓取指标，与该脚本最近的成功执行比较，标记可疑的结果
+	if playErr == nil {
+		m.checkExtractionAnomalies(ctx, script, execution)
+	}
+
+	// 清理临时工作目录（录屏帧已在停止录制时合成），配置了保留时失败的执行保留现场
+	execution.WorkDir = workdir.release(ctx, playErr != nil)
+
 	// 判断是否成功
 	if playErr != nil {
 		execution.Success = false
 		execution.ErrorMsg = playErr.Error()
+		execution.ErrorCode = ClassifyError(playErr)
 		execution.Message = "Script execution failed"
 	} else {
 		execution.Success = true
@@ -1218,18 +1144,38 @@ func (m *Manager) PlayScript(ctx context.Context, script *models.Script, instanc
 			logger.Info(ctx, "Script execution record saved: %s", executionID)
 		}
 	}
+	if m.notifier != nil {
+		m.notifier.ScriptExecutionFinished(ctx, execution)
+	}
+
+	// 执行成功时保存本次输出的条目和游标，失败的执行不影响下次的增量结果
+	if scrape != nil && playErr == nil {
+		m.commitScrapeState(ctx, script.ID, scrape, execution.StartTime)
+	}
+
+	// 检查产物目录配额，保留本次执行生成的文件
+	keep := append([]string{execution.VideoPath}, player.GetDownloadedFiles()...)
+	if execution.Performance != nil {
+		keep = append(keep, execution.Performance.TracePath)
+	}
+	m.EnforceArtifactQuotas(ctx, keep...)
 
 	// 如果执行失败，返回错误
 	if playErr != nil {
+		// 调用方在失败时不会关闭页面，直接销毁无痕上下文
+		if script.Incognito {
+			m.disposeEphemeralContext(ctx, page)
+		}
 		return &models.PlayResult{
-			Success: false,
-			Message: playErr.Error(),
-			Errors:  []string{playErr.Error()},
+			Success:     false,
+			Message:     playErr.Error(),
+			Errors:      []string{playErr.Error()},
+			Performance: execution.Performance,
 		}, page, playErr
 	}
 
 	// 返回回放结果，包含抓取的数据
-	extractedData := player.GetExtractedData()
+	extractedData := execution.ExtractedData
 	logger.Info(ctx, "[PlayScript] Extracted data length: %d", len(extractedData))
 	if len(extractedData) > 0 {
 		keys := make([]string, 0, len(extractedData))
@@ -1253,143 +1199,12 @@ func (m *Manager) PlayScript(ctx context.Context, script *models.Script, instanc
 		Success:       true,
 		Message:       "Script replay completed",
 		ExtractedData: extractedData,
+		Performance:   execution.Performance,
+		SchemaError:   execution.SchemaError,
+		Anomalies:     execution.Anomalies,
 	}, page, nil
 }
 
-// checkInPageRecordingRequests 检查页面内的录制控制请求
-func (m *Manager) checkInPageRecordingRequests(ctx context.Context, page *rod.Page) {
-	ticker := time.NewTicker(500 * time.Millisecond)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ticker.C:
-			// 检查是否有录制开始请求
-			result, err := page.Eval(`() => {
-				if (window.__startRecordingRequest__) {
-					var req = window.__startRecordingRequest__;
-					delete window.__startRecordingRequest__;
-					return { type: 'start_recording', data: req };
-				}
-				if (window.__screenshotRequest__) {
-					var req = window.__screenshotRequest__;
-					delete window.__screenshotRequest__;
-					return { type: 'screenshot', data: req };
-				}
-				return null;
-			}`)
-
-			if err == nil && result != nil && !result.Value.Nil() {
-				// 解析请求类型
-				resultMap, ok := result.Value.Val().(map[string]interface{})
-				if !ok {
-					continue
-				}
-
-				reqType, _ := resultMap["type"].(string)
-
-				if reqType == "start_recording" {
-					logger.Info(ctx, "Detected in-page recording start request")
-
-					// 获取当前页面URL
-					info, err := page.Info()
-					if err == nil {
-						// 获取当前语言设置
-						currentLang := m.currentLanguage
-						if currentLang == "" {
-							currentLang = "zh-CN"
-						}
-						// 开始录制
-						if err := m.recorder.StartRecording(ctx, page, info.URL, currentLang); err != nil {
-							logger.Error(ctx, "Failed to start recording from in-page request: %v", err)
-						} else {
-							logger.Info(ctx, "✓ Recording started from in-page button")
-							// 通知页面显示录制UI
-							_, _ = page.Eval(`() => {
-								window.__isRecordingActive__ = true;
-								if (typeof createRecorderUI === 'function') createRecorderUI();
-								if (typeof createHighlightElement === 'function') createHighlightElement();
-							}`)
-						}
-					} else {
-						logger.Error(ctx, "Failed to get page info for in-page recording start: %v", err)
-					}
-				} else if reqType == "screenshot" {
-					logger.Info(ctx, "Detected in-page screenshot request")
-
-					// 提取截图请求数据（仅用于日志）
-					data, _ := resultMap["data"].(map[string]interface{})
-					mode, _ := data["mode"].(string)
-
-					if mode == "" {
-						mode = "viewport"
-					}
-
-					// 注意：不在后端添加 action，因为前端已经通过 recordAction() 添加了
-					// 停止录制时会从前端的 window.__recordedActions__ 同步过来
-					// 这样避免重复添加截图操作
-					logger.Info(ctx, "Screenshot action will be synced from frontend: mode=%s", mode)
-				}
-			}
-
-			// 检查是否有录制停止请求
-			stopResult, err := page.Eval(`() => {
-				if (window.__stopRecordingRequest__) {
-					var req = window.__stopRecordingRequest__;
-					delete window.__stopRecordingRequest__;
-					return req;
-				}
-				return null;
-			}`)
-
-			if err == nil && stopResult != nil && !stopResult.Value.Nil() {
-				logger.Info(ctx, "Detected in-page recording stop request")
-
-				// 获取录制信息(包含start_url)
-				recInfo := m.recorder.GetRecordingInfo()
-
-				// 停止录制并获取下载文件信息
-				actions, err := m.recorder.StopRecording(ctx)
-				downloadedFiles := m.recorder.GetDownloadedFiles()
-
-				if err != nil {
-					logger.Error(ctx, "Failed to stop recording from in-page request: %v", err)
-				} else {
-					logger.Info(ctx, "✓ Recording stopped from in-page button, %d actions recorded, %d files downloaded",
-						len(actions), len(downloadedFiles))
-					// 保存录制结果、下载文件和URL,供前端获取
-					m.mu.Lock()
-					m.lastRecordedActions = actions
-					m.lastDownloadedFiles = downloadedFiles
-					m.inPageRecordingStopped = true
-					// 保存录制时的URL到持久化字段
-					if startURL, ok := recInfo["start_url"].(string); ok && startURL != "" {
-						m.lastRecordedStartURL = startURL
-						logger.Info(ctx, "Saved start URL: %s", startURL)
-					}
-					m.mu.Unlock()
-
-					// 通知页面:录制已停止
-					_, _ = page.Eval(`() => {
-						window.__recordingStoppedByInPage__ = true;
-					}`)
-				}
-			}
-
-		case <-ctx.Done():
-			return
-		}
-
-		// 如果页面不再是活动页面,停止轮询
-		m.mu.Lock()
-		isActive := m.activePage == page
-		m.mu.Unlock()
-		if !isActive {
-			return
-		}
-	}
-}
-
 // isHeadlessEnvironment 检测当前环境是否为无GUI环境
 func isHeadlessEnvironment() bool {
 	// 1. 优先检查是否在 Docker 容器中
@@ -1552,60 +1367,96 @@ func (m *Manager) cleanupSingletonLock(ctx context.Context, userDataDir string)
 // getInstanceBrowser 获取指定实例的浏览器和活动页面
 // 如果 instanceID 为空，则使用当前实例
 // 如果 default 实例未运行，会自动启动它
+// 调用者不能持有 m.mu（自动启动需要获取实例的生命周期锁）
 // 返回: browser, activePage, instance, error
 func (m *Manager) getInstanceBrowser(instanceID string) (*rod.Browser, *rod.Page, *models.BrowserInstance, error) {
+	m.mu.RLock()
 	// 如果没有指定实例ID，使用当前实例
 	if instanceID == "" {
 		instanceID = m.currentInstanceID
 	}
 
-	// 如果还是空，说明没有运行中的实例
+	// 如果还是空，说明没有运行中的实例，尝试使用 default 实例
 	if instanceID == "" {
-		// 向后兼容：检查旧的 browser 字段
-		if m.isRunning && m.browser != nil {
-			return m.browser, m.activePage, nil, nil
-		}
-
-		// 尝试使用 default 实例
-		instanceID = "default"
+		instanceID = DefaultInstanceID
 		ctx := context.Background()
 		logger.Info(ctx, "No current instance, attempting to use default instance")
 	}
 
 	// 获取实例运行时信息
-	runtime, exists := m.instances[instanceID]
-	if !exists || runtime == nil {
-		// 如果是 default 实例且未运行，尝试自动启动
-		if instanceID == "default" {
-			ctx := context.Background()
-			logger.Info(ctx, "Default instance not running, attempting to auto-start...")
+	browser, activePage, instance, running := m.runtimeStateLocked(instanceID)
+	m.mu.RUnlock()
+	if running {
+		return browser, activePage, instance, nil
+	}
 
-			// 调用内部启动函数（调用者已持有锁）
-			err := m.startInstanceInternal(ctx, "default")
-			if err != nil {
-				logger.Error(ctx, "Failed to auto-start default instance: %v", err)
-				return nil, nil, nil, fmt.Errorf("default instance not running and failed to start: %w", err)
-			}
+	// 如果是 default 实例且未运行，尝试自动启动
+	if instanceID != DefaultInstanceID {
+		return nil, nil, nil, fmt.Errorf("instance %s is not running", instanceID)
+	}
 
-			logger.Info(ctx, "✓ Default instance auto-started successfully")
+	lock := m.instanceLock(DefaultInstanceID)
+	lock.Lock()
+	defer lock.Unlock()
 
-			// 重新获取运行时信息
-			runtime, exists = m.instances["default"]
-			if !exists || runtime == nil {
-				return nil, nil, nil, fmt.Errorf("default instance started but runtime not found")
-			}
+	// 等待生命周期锁期间可能已被其他请求启动
+	m.mu.RLock()
+	browser, activePage, instance, running = m.runtimeStateLocked(DefaultInstanceID)
+	m.mu.RUnlock()
+	if running {
+		return browser, activePage, instance, nil
+	}
 
-			return runtime.browser, runtime.activePage, runtime.instance, nil
-		}
+	ctx := context.Background()
+	logger.Info(ctx, "Default instance not running, attempting to auto-start...")
 
-		return nil, nil, nil, fmt.Errorf("instance %s is not running", instanceID)
+	if err := m.startInstanceInternal(ctx, DefaultInstanceID); err != nil {
+		logger.Error(ctx, "Failed to auto-start default instance: %v", err)
+		return nil, nil, nil, fmt.Errorf("default instance not running and failed to start: %w", err)
 	}
 
-	return runtime.browser, runtime.activePage, runtime.instance, nil
+	logger.Info(ctx, "✓ Default instance auto-started successfully")
+
+	// 重新获取运行时信息
+	m.mu.RLock()
+	browser, activePage, instance, running = m.runtimeStateLocked(DefaultInstanceID)
+	m.mu.RUnlock()
+	if !running {
+		return nil, nil, nil, fmt.Errorf("default instance started but runtime not found")
+	}
+	return browser, activePage, instance, nil
+}
+
+// runtimeStateLocked 读取运行中实例的浏览器、活动页面和配置，调用者必须已持有锁（读锁即可）
+func (m *Manager) runtimeStateLocked(instanceID string) (*rod.Browser, *rod.Page, *models.BrowserInstance, bool) {
+	runtime, exists := m.instances[instanceID]
+	if !exists || runtime == nil {
+		return nil, nil, nil, false
+	}
+	return runtime.browser, runtime.activePage, runtime.instance, true
+}
+
+// instanceLock 返回实例的生命周期锁，不存在时创建
+func (m *Manager) instanceLock(instanceID string) *sync.Mutex {
+	m.lifecycleMu.Lock()
+	defer m.lifecycleMu.Unlock()
+
+	if m.lifecycleLocks == nil {
+		m.lifecycleLocks = make(map[string]*sync.Mutex)
+	}
+	lock, ok := m.lifecycleLocks[instanceID]
+	if !ok {
+		lock = &sync.Mutex{}
+		m.lifecycleLocks[instanceID] = lock
+	}
+	return lock
 }
 
 // setInstanceActivePage 设置指定实例的活动页面
 func (m *Manager) setInstanceActivePage(instanceID string, page *rod.Page) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	// 如果没有指定实例ID，使用当前实例
 	if instanceID == "" {
 		instanceID = m.currentInstanceID
@@ -1613,11 +1464,6 @@ func (m *Manager) setInstanceActivePage(instanceID string, page *rod.Page) error
 
 	// 如果还是空，说明没有运行中的实例
 	if instanceID == "" {
-		// 向后兼容：设置旧的 activePage 字段
-		if m.isRunning && m.browser != nil {
-			m.activePage = page
-			return nil
-		}
 		return fmt.Errorf("no running instance available")
 	}
 
@@ -1628,27 +1474,27 @@ func (m *Manager) setInstanceActivePage(instanceID string, page *rod.Page) error
 	}
 
 	runtime.activePage = page
-
-	// 如果是当前实例，也更新向后兼容的字段
-	if instanceID == m.currentInstanceID {
-		m.activePage = page
-	}
-
 	return nil
 }
 
 // StartInstance 启动指定浏览器实例
+// 只持有该实例的生命周期锁，启动期间其他实例的操作和状态查询不受影响
 func (m *Manager) StartInstance(ctx context.Context, instanceID string) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+	lock := m.instanceLock(instanceID)
+	lock.Lock()
+	defer lock.Unlock()
 
 	return m.startInstanceInternal(ctx, instanceID)
 }
 
-// startInstanceInternal 内部启动函数，调用者必须已持有锁
+// startInstanceInternal 内部启动函数，调用者必须已持有实例的生命周期锁，且不能持有 m.mu
+// 启动浏览器期间不持有 m.mu，启动完成后才登记运行时信息
 func (m *Manager) startInstanceInternal(ctx context.Context, instanceID string) error {
 	// 检查实例是否已启动
-	if runtime, exists := m.instances[instanceID]; exists && runtime != nil {
+	m.mu.RLock()
+	_, _, _, running := m.runtimeStateLocked(instanceID)
+	m.mu.RUnlock()
+	if running {
 		return fmt.Errorf("instance %s is already running", instanceID)
 	}
 
@@ -1662,8 +1508,10 @@ func (m *Manager) startInstanceInternal(ctx context.Context, instanceID string)
 
 	var browser *rod.Browser
 	var launcherObj *launcher.Launcher
+	var remote *remoteClient
 	var url string
 	var proxyUsername, proxyPassword string // 代理认证信息
+	var extensions []string                 // 实际加载的扩展目录（仅本地模式）
 
 	if instance.Type == "remote" {
 		// 远程模式
@@ -1681,7 +1529,12 @@ func (m *Manager) startInstanceInternal(ctx context.Context, instanceID string)
 		logger.Info(ctx, "Resolved WebSocket URL: %s", wsURL)
 		url = wsURL
 
-		browser = rod.New().ControlURL(url)
+		// 网络短暂中断时自动重连并恢复页面会话，不影响正在执行的脚本
+		remote, err = newRemoteClient(ctx, instance.Name, controlURL, wsURL)
+		if err != nil {
+			return fmt.Errorf("failed to connect to remote browser %s: %w", wsURL, err)
+		}
+		browser = rod.New().Client(remote)
 	} else {
 		// 本地模式
 		logger.Info(ctx, "Starting local browser instance...")
@@ -1698,13 +1551,15 @@ func (m *Manager) startInstanceInternal(ctx context.Context, instanceID string)
 			Leakless(false)
 
 		// 设置代理
+		var proxyAddr string
 		if instance.Proxy != "" {
 			// 解析代理 URL，提取认证信息
-			proxyAddr, username, password, err := parseProxyURL(instance.Proxy)
+			addr, username, password, err := parseProxyURL(instance.Proxy)
 			if err != nil {
 				logger.Warn(ctx, "Failed to parse proxy URL: %v", err)
 			} else {
 				// 设置代理地址（不包含用户名密码）
+				proxyAddr = addr
 				l = l.Proxy(proxyAddr)
 				proxyUsername = username
 				proxyPassword = password
@@ -1741,6 +1596,26 @@ func (m *Manager) startInstanceInternal(ctx context.Context, instanceID string)
 			}
 		}
 
+		// 客户端证书自动选择（通过受管策略下发）
+		m.applyClientCertificatePolicy(ctx)
+
+		// 窗口布局
+		if placement := windowPlacementOf(instance); placement != nil {
+			l = applyWindowLaunchFlags(l, placement)
+		}
+
+		// 代理泄露防护（WebRTC、DNS）
+		l = applyLeakProtectionFlags(ctx, l, instance.LeakProtection, proxyAddr)
+
+		// 加载解压扩展
+		if len(instance.Extensions) > 0 {
+			extensions = extensionDirs(ctx, instance.Extensions)
+			if len(extensions) > 0 {
+				l = applyExtensionFlags(l, extensions, headless)
+				logger.Info(ctx, "Loading %d extensions", len(extensions))
+			}
+		}
+
 		// 设置浏览器路径
 		binPath := instance.BinPath
 		if binPath == "" {
@@ -1816,14 +1691,14 @@ func (m *Manager) startInstanceInternal(ctx context.Context, instanceID string)
 		if launcherObj != nil {
 			launcherObj.Kill()
 		}
+		if remote != nil {
+			remote.Close()
+		}
 		return fmt.Errorf("failed to connect browser: %w", err)
 	}
 
-	// 如果代理需要认证，启动认证处理
-	if proxyUsername != "" && proxyPassword != "" {
-		logger.Info(ctx, "Setting up proxy authentication handler...")
-		go browser.HandleAuth(proxyUsername, proxyPassword)()
-	}
+	// 内网访问防护，以及代理认证和站点 HTTP 认证
+	m.interceptBrowserRequests(ctx, browser, proxyUsername, proxyPassword)
 
 	// 关键：在浏览器连接后立即设置XHR拦截器，确保所有页面（包括后续打开的）都会自动监听XHR
 	// 这样用户在点击"开始录制"之前打开的页面，也能捕获到XHR请求
@@ -1857,20 +1732,21 @@ func (m *Manager) startInstanceInternal(ctx context.Context, instanceID string)
 	logger.Info(ctx, "✓ XHR interceptor setup completed")
 
 	// 设置下载行为
-	if m.downloadPath == "" {
-		downloadPath := "./downloads"
-		absDownloadPath, err := os.Getwd()
-		if err == nil {
-			downloadPath = absDownloadPath + "/downloads"
-		}
+	m.mu.RLock()
+	downloadPath := m.downloadPath
+	m.mu.RUnlock()
+	if downloadPath == "" {
+		downloadPath = m.downloadRoot(ctx)
 		os.MkdirAll(downloadPath, 0o755)
+		m.mu.Lock()
 		m.downloadPath = downloadPath
+		m.mu.Unlock()
 		m.recorder.SetDownloadPath(downloadPath)
 	}
 
 	downloadBehavior := &proto.BrowserSetDownloadBehavior{
 		Behavior:      proto.BrowserSetDownloadBehaviorBehaviorAllow,
-		DownloadPath:  m.downloadPath,
+		DownloadPath:  downloadPath,
 		EventsEnabled: true,
 	}
 	if err := downloadBehavior.Call(browser); err != nil {
@@ -1888,16 +1764,27 @@ func (m *Manager) startInstanceInternal(ctx context.Context, instanceID string)
 		logger.Warn(ctx, "Failed to grant clipboard permissions: %v", err)
 	}
 
+	// 检查扩展是否加载成功
+	if len(extensions) > 0 {
+		headless := instance.Headless != nil && *instance.Headless
+		checkExtensionSupport(ctx, browser, extensions, headless)
+	}
+
+	// 调整窗口布局（仅本地实例，远程浏览器的窗口不由我们管理）
+	if placement := windowPlacementOf(instance); placement != nil && launcherObj != nil {
+		m.setupInstanceWindow(ctx, browser, placement, launcherObj.PID())
+	}
+
 	// 创建运行时信息
 	runtime := &BrowserInstanceRuntime{
 		instance:  instance,
 		browser:   browser,
 		launcher:  launcherObj,
+		events:    NewEventBus(ctx, browser),
+		remote:    remote,
 		startTime: time.Now(),
 	}
 
-	m.instances[instanceID] = runtime
-
 	// 更新实例状态为运行中
 	instance.IsActive = true
 	instance.UpdatedAt = time.Now()
@@ -1905,126 +1792,54 @@ func (m *Manager) startInstanceInternal(ctx context.Context, instanceID string)
 		logger.Warn(ctx, "Failed to update instance status: %v", err)
 	}
 
+	m.mu.Lock()
+	m.instances[instanceID] = runtime
+
 	// 如果是第一个启动的实例或者是默认实例，设置为当前实例
 	if m.currentInstanceID == "" || instance.IsDefault {
 		m.currentInstanceID = instanceID
 	}
+	m.mu.Unlock()
 
-	// 如果启动的是当前实例，更新向后兼容的旧字段
-	if m.currentInstanceID == instanceID {
-		m.browser = browser
-		m.launcher = launcherObj
-		m.isRunning = true
-		m.startTime = runtime.startTime
-	}
-
-	// 启动新页面监听，自动为新打开的页面注入XHR拦截器
-	go m.watchForNewPagesXHR(ctx, browser, instanceID)
+	// 实例看护和新页面监听（自动为新打开的页面注入XHR拦截器）
+	m.watchInstance(ctx, instanceID, runtime.events)
+	m.watchPageCommands(ctx, browser, runtime.events)
+	m.watchForNewPagesXHR(ctx, browser, instanceID, runtime.events)
 
 	logger.Info(ctx, "✓ Browser instance started: %s", instance.Name)
 	return nil
 }
 
-// watchForNewPagesXHR 监听新页面创建并自动注入XHR拦截器
-// 这确保了用户在点击"开始录制"之前打开的所有页面都能捕获XHR请求
-func (m *Manager) watchForNewPagesXHR(ctx context.Context, browser *rod.Browser, instanceID string) {
-	logger.Info(ctx, "Starting XHR interceptor watcher for instance: %s", instanceID)
-
-	// 记录已处理的页面
-	processedPages := make(map[string]bool)
-
-	// 定时检查新页面（每秒检查一次）
-	ticker := time.NewTicker(1 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ticker.C:
-			// 检查实例是否还在运行
-			m.mu.Lock()
-			runtime, exists := m.instances[instanceID]
-			m.mu.Unlock()
-
-			if !exists || runtime == nil {
-				logger.Info(ctx, "Instance %s stopped, stopping XHR watcher", instanceID)
-				return
-			}
-
-			// 获取所有页面
-			pages, err := browser.Pages()
-			if err != nil {
-				continue
-			}
-
-			// 为每个新页面注入XHR拦截器
-			for _, page := range pages {
-				if page == nil {
-					continue
-				}
-
-				pageInfo, err := page.Info()
-				if err != nil {
-					continue
-				}
-
-				targetID := string(pageInfo.TargetID)
-
-				// 跳过已处理的页面
-				if processedPages[targetID] {
-					continue
-				}
-
-				// 跳过特殊页面
-				if strings.HasPrefix(pageInfo.URL, "chrome://") ||
-					strings.HasPrefix(pageInfo.URL, "chrome-extension://") ||
-					strings.HasPrefix(pageInfo.URL, "devtools://") ||
-					strings.HasPrefix(pageInfo.URL, "about:") {
-					processedPages[targetID] = true
-					continue
-				}
-
-				// 标记为已处理
-				processedPages[targetID] = true
-
-				logger.Info(ctx, "Injecting XHR interceptor into new page: %s (URL: %s)", targetID, pageInfo.URL)
-
-				// 为新页面设置EvalOnNewDocument（影响该页面内的iframe和导航）
-				_, evalErr := page.EvalOnNewDocument(xhrInterceptorScriptForManager)
-				if evalErr != nil {
-					logger.Warn(ctx, "Failed to set EvalOnNewDocument for page %s: %v", targetID, evalErr)
-				}
+// StopInstance 停止指定浏览器实例
+func (m *Manager) StopInstance(ctx context.Context, instanceID string) error {
+	lock := m.instanceLock(instanceID)
+	lock.Lock()
+	defer lock.Unlock()
 
-				// 立即在页面注入（以防页面已经加载）
-				go func(p *rod.Page, tid string) {
-					_, injectErr := p.Eval(`() => { ` + xhrInterceptorScriptForManager + ` return true; }`)
-					if injectErr != nil {
-						logger.Warn(ctx, "Failed to inject XHR interceptor into page %s: %v", tid, injectErr)
-					} else {
-						logger.Info(ctx, "✓ XHR interceptor injected into page: %s", tid)
-					}
-				}(page, targetID)
-			}
-		case <-ctx.Done():
-			logger.Info(ctx, "Context cancelled, stopping XHR watcher for instance: %s", instanceID)
-			return
-		}
-	}
+	return m.stopInstanceInternal(ctx, instanceID)
 }
 
-// StopInstance 停止指定浏览器实例
-func (m *Manager) StopInstance(ctx context.Context, instanceID string) error {
+// stopInstanceInternal 内部停止函数，调用者必须已持有实例的生命周期锁，且不能持有 m.mu
+// 先摘除运行时信息（关闭过程中的查询直接看到实例已停止），再在锁外关闭浏览器
+func (m *Manager) stopInstanceInternal(ctx context.Context, instanceID string) error {
 	m.mu.Lock()
-	defer m.mu.Unlock()
-
 	runtime, exists := m.instances[instanceID]
 	if !exists || runtime == nil {
+		m.mu.Unlock()
 		return fmt.Errorf("instance %s is not running", instanceID)
 	}
+	m.detachRuntimeLocked(instanceID)
+	m.mu.Unlock()
 
 	logger.Info(ctx, "Stopping browser instance: %s", runtime.instance.Name)
 
 	isRemote := runtime.instance.Type == "remote"
 
+	// 关闭事件总线，所有订阅随之结束
+	if runtime.events != nil {
+		runtime.events.Close()
+	}
+
 	// 关闭浏览器
 	if runtime.browser != nil {
 		if !isRemote {
@@ -2042,6 +1857,11 @@ func (m *Manager) StopInstance(ctx context.Context, instanceID string) error {
 		}
 	}
 
+	// 停止远程连接的保活和重连
+	if runtime.remote != nil {
+		runtime.remote.Close()
+	}
+
 	// 终止本地浏览器进程
 	if !isRemote && runtime.launcher != nil {
 		time.Sleep(1 * time.Second)
@@ -2068,33 +1888,22 @@ func (m *Manager) StopInstance(ctx context.Context, instanceID string) error {
 		logger.Warn(ctx, "Failed to update instance status: %v", err)
 	}
 
-	// 删除运行时信息
+	logger.Info(ctx, "✓ Browser instance stopped: %s", runtime.instance.Name)
+	return nil
+}
+
+// detachRuntimeLocked 删除实例的运行时信息，停止的是当前实例时切换到其他运行中的实例，调用者必须已持有锁
+func (m *Manager) detachRuntimeLocked(instanceID string) {
 	delete(m.instances, instanceID)
 
-	// 如果停止的是当前实例，清空当前实例 ID
+	// 如果停止的是当前实例，清空当前实例 ID，并尝试切换到第一个运行中的实例
 	if m.currentInstanceID == instanceID {
 		m.currentInstanceID = ""
-
-		// 向后兼容：清空旧字段
-		m.browser = nil
-		m.launcher = nil
-		m.isRunning = false
-		m.activePage = nil
-
-		// 尝试切换到第一个运行中的实例
 		for id := range m.instances {
 			m.currentInstanceID = id
-			runtime := m.instances[id]
-			m.browser = runtime.browser
-			m.launcher = runtime.launcher
-			m.isRunning = true
-			m.startTime = runtime.startTime
 			break
 		}
 	}
-
-	logger.Info(ctx, "✓ Browser instance stopped: %s", runtime.instance.Name)
-	return nil
 }
 
 // SwitchInstance 切换当前活动实例
@@ -2112,22 +1921,9 @@ func (m *Manager) SwitchInstance(ctx context.Context, instanceID string) error {
 	m.currentInstanceID = instanceID
 
 	// 检查实例是否运行
-	runtime, exists := m.instances[instanceID]
-	if exists && runtime != nil {
-		// 实例正在运行，更新旧字段以保持向后兼容
-		m.browser = runtime.browser
-		m.launcher = runtime.launcher
-		m.isRunning = true
-		m.startTime = runtime.startTime
-		m.activePage = runtime.activePage
+	if m.isInstanceRunningLocked(instanceID) {
 		logger.Info(ctx, "Switched to running instance: %s", instance.Name)
 	} else {
-		// 实例未运行，清空旧字段
-		m.browser = nil
-		m.launcher = nil
-		m.isRunning = false
-		m.startTime = time.Time{}
-		m.activePage = nil
 		logger.Info(ctx, "Switched to stopped instance: %s (not running)", instance.Name)
 	}
 
@@ -2136,8 +1932,8 @@ func (m *Manager) SwitchInstance(ctx context.Context, instanceID string) error {
 
 // GetCurrentInstance 获取当前活动实例
 func (m *Manager) GetCurrentInstance() *models.BrowserInstance {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 
 	if m.currentInstanceID == "" {
 		return nil
@@ -2160,8 +1956,8 @@ func (m *Manager) GetCurrentInstance() *models.BrowserInstance {
 
 // GetInstanceRuntime 获取指定实例的运行时信息
 func (m *Manager) GetInstanceRuntime(instanceID string) (*BrowserInstanceRuntime, error) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 
 	runtime, exists := m.instances[instanceID]
 	if !exists || runtime == nil {
@@ -2173,8 +1969,8 @@ func (m *Manager) GetInstanceRuntime(instanceID string) (*BrowserInstanceRuntime
 
 // ListRunningInstances 列出所有运行中的实例
 func (m *Manager) ListRunningInstances() []*models.BrowserInstance {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 
 	var instances []*models.BrowserInstance
 	for _, runtime := range m.instances {
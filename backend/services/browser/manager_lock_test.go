@@ -0,0 +1,59 @@
+package browser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/go-rod/rod"
+)
+
+// withinTimeout 在限定时间内执行 fn，超时说明发生了死锁或被长操作阻塞
+func withinTimeout(t *testing.T, name string, fn func()) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		fn()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("%s blocked", name)
+	}
+}
+
+func TestManagerReadsDoNotBlock(t *testing.T) {
+	m := &Manager{instances: map[string]*BrowserInstanceRuntime{
+		"a": {instance: &models.BrowserInstance{ID: "a"}, browser: &rod.Browser{}},
+	}}
+	m.currentInstanceID = "a"
+
+	// 当前实例存在时 IsRunning 不能重复加锁
+	withinTimeout(t, "IsRunning", func() {
+		if !m.IsRunning() {
+			t.Error("current instance should be running")
+		}
+	})
+
+	// 实例启动、停止期间持有生命周期锁，只读查询不受影响
+	lock := m.instanceLock("a")
+	lock.Lock()
+	defer lock.Unlock()
+	withinTimeout(t, "reads during lifecycle operation", func() {
+		m.IsInstanceRunning("a")
+		m.GetCurrentInstance()
+		m.ListRunningInstances()
+		m.GetActivePage()
+	})
+
+	// 其他实例的生命周期锁互不影响
+	if m.instanceLock("b") == lock || m.instanceLock("a") != lock {
+		t.Error("lifecycle locks should be per instance")
+	}
+	withinTimeout(t, "other instance lock", func() {
+		other := m.instanceLock("b")
+		other.Lock()
+		other.Unlock()
+	})
+}
@@ -0,0 +1,65 @@
+package browser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// compileOutputSchema 解析脚本的 output_schema；忽略 $schema，统一按 JSON Schema 2020-12 校验
+func compileOutputSchema(schema map[string]interface{}) (*jsonschema.Resolved, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	var s jsonschema.Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	s.Schema = ""
+	return s.Resolve(nil)
+}
+
+// ValidateOutputSchema 检查 output_schema 能否解析，未设置时返回 nil
+func ValidateOutputSchema(schema map[string]interface{}) error {
+	if len(schema) == 0 {
+		return nil
+	}
+	_, err := compileOutputSchema(schema)
+	return err
+}
+
+// checkOutputSchema 按 output_schema 校验抓取数据，返回第一处不符合的说明（如缺少字段、类型错误）
+func checkOutputSchema(schema map[string]interface{}, extracted map[string]interface{}) error {
+	resolved, err := compileOutputSchema(schema)
+	if err != nil {
+		return fmt.Errorf("invalid output schema: %w", err)
+	}
+	if extracted == nil {
+		extracted = map[string]interface{}{}
+	}
+	if err := resolved.Validate(normalizeExtracted(extracted)); err != nil {
+		return errors.New(schemaErrorMessage(err))
+	}
+	return nil
+}
+
+// schemaErrorMessage 去掉校验错误中逐层嵌套的 "validating ...: " 前缀，只保留最内层的位置和原因
+func schemaErrorMessage(err error) string {
+	msg, location := err.Error(), ""
+	for strings.HasPrefix(msg, "validating ") {
+		rest := strings.TrimPrefix(msg, "validating ")
+		i := strings.Index(rest, ": ")
+		if i < 0 {
+			break
+		}
+		location, msg = rest[:i], rest[i+2:]
+	}
+	if location == "" || location == "root" {
+		return msg
+	}
+	return location + ": " + msg
+}
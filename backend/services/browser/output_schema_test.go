@@ -0,0 +1,56 @@
+package browser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckOutputSchema(t *testing.T) {
+	schema := map[string]interface{}{
+		"$schema":  "http://json-schema.org/draft-07/schema#",
+		"type":     "object",
+		"required": []interface{}{"items"},
+		"properties": map[string]interface{}{
+			"items": map[string]interface{}{
+				"type":     "array",
+				"minItems": 1,
+				"items": map[string]interface{}{
+					"type":     "object",
+					"required": []interface{}{"title", "price"},
+					"properties": map[string]interface{}{
+						"title": map[string]interface{}{"type": "string"},
+						"price": map[string]interface{}{"type": "number"},
+					},
+				},
+			},
+		},
+	}
+	if err := ValidateOutputSchema(schema); err != nil {
+		t.Fatalf("schema should be valid: %v", err)
+	}
+
+	ok := map[string]interface{}{"items": []map[string]interface{}{{"title": "a", "price": 9.9}}}
+	if err := checkOutputSchema(schema, ok); err != nil {
+		t.Errorf("expected valid data, got %v", err)
+	}
+
+	for name, data := range map[string]map[string]interface{}{
+		"no data":       nil,
+		"empty list":    {"items": []interface{}{}},
+		"missing field": {"items": []interface{}{map[string]interface{}{"title": "a"}}},
+		"wrong type":    {"items": []interface{}{map[string]interface{}{"title": "a", "price": "9.90"}}},
+	} {
+		if err := checkOutputSchema(schema, data); err == nil {
+			t.Errorf("%s: expected a schema error", name)
+		}
+	}
+
+	err := checkOutputSchema(schema, map[string]interface{}{"items": []interface{}{map[string]interface{}{"title": "a", "price": "9.90"}}})
+	if err == nil || !strings.HasPrefix(err.Error(), "/properties/items/items/properties/price: type:") {
+		t.Errorf("expected the innermost location of the error, got %v", err)
+	}
+
+	if err := ValidateOutputSchema(map[string]interface{}{"type": 1}); err == nil {
+		t.Error("invalid schema should be rejected")
+	}
+}
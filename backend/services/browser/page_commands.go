@@ -0,0 +1,153 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// pageCommandBinding 页面内 UI（浮动按钮、录制面板）向后端发送命令的 binding 名称
+// 页面调用 __browserwingCommand__(JSON.stringify({type, data}))，命令经事件总线立即送达，无需轮询
+const pageCommandBinding = "__browserwingCommand__"
+
+// pageCommand 页面内 UI 发送的命令
+type pageCommand struct {
+	Type string          `json:"type"` // start_recording、stop_recording、screenshot
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// installPageCommands 在页面上注册命令 binding
+// 默认只暴露给注入 UI 的隔离环境，页面自身的脚本无法调用；主环境注入模式下暴露给页面主环境
+// binding 在页面导航后仍然有效，重复注册没有副作用；events 为页面所在实例的事件总线，调用经总线送达
+func installPageCommands(page *rod.Page, events *EventBus) error {
+	if events != nil {
+		events.trackPage(page)
+	}
+	if err := (proto.RuntimeEnable{}).Call(page); err != nil {
+		return err
+	}
+	binding := proto.RuntimeAddBinding{Name: pageCommandBinding}
+	if !mainWorldInjection.Load() {
+		binding.ExecutionContextName = uiWorldName
+	}
+	return binding.Call(page)
+}
+
+// parsePageCommand 解析页面通过 binding 发送的命令，不是命令 binding 的调用返回 false
+func parsePageCommand(e *proto.RuntimeBindingCalled) (pageCommand, bool) {
+	var cmd pageCommand
+	if e == nil || e.Name != pageCommandBinding {
+		return cmd, false
+	}
+	if err := json.Unmarshal([]byte(e.Payload), &cmd); err != nil || cmd.Type == "" {
+		return cmd, false
+	}
+	return cmd, true
+}
+
+// watchPageCommands 处理实例中各页面发送的命令（开始/停止录制、截图）
+func (m *Manager) watchPageCommands(ctx context.Context, browser *rod.Browser, events *EventBus) {
+	ctx = context.WithoutCancel(ctx)
+	events.Subscribe(func(e BrowserEvent) {
+		cmd, ok := parsePageCommand(e.Binding)
+		if !ok || e.TargetID == "" {
+			return
+		}
+		// 处理命令需要加锁并调用 CDP，不能阻塞事件分发
+		go func() {
+			page, err := browser.PageFromTarget(e.TargetID)
+			if err != nil {
+				logger.Warn(ctx, "Failed to attach to page %s for in-page command %s: %v", e.TargetID, cmd.Type, err)
+				return
+			}
+			m.handlePageCommand(ctx, page, events, cmd)
+		}()
+	}, EventBindingCalled)
+}
+
+// handlePageCommand 执行页面发送的命令
+func (m *Manager) handlePageCommand(ctx context.Context, page *rod.Page, events *EventBus, cmd pageCommand) {
+	switch cmd.Type {
+	case "start_recording":
+		logger.Info(ctx, "Received in-page recording start request")
+
+		// 获取当前页面URL
+		info, err := page.Info()
+		if err != nil {
+			logger.Error(ctx, "Failed to get page info for in-page recording start: %v", err)
+			return
+		}
+		// 获取当前语言设置
+		m.mu.RLock()
+		currentLang := m.currentLanguage
+		m.mu.RUnlock()
+		if currentLang == "" {
+			currentLang = "zh-CN"
+		}
+		// 开始录制
+		m.recorder.SetEventBus(events)
+		m.recorder.SetRecordRequests(false)
+		if err := m.recorder.StartRecording(ctx, page, info.URL, currentLang); err != nil {
+			logger.Error(ctx, "Failed to start recording from in-page request: %v", err)
+			return
+		}
+		logger.Info(ctx, "✓ Recording started from in-page button")
+		// 通知页面显示录制UI
+		_, _ = uiEval(page, `() => {
+			window.__isRecordingActive__ = true;
+			if (typeof createRecorderUI === 'function') createRecorderUI();
+			if (typeof createHighlightElement === 'function') createHighlightElement();
+		}`)
+
+	case "stop_recording":
+		logger.Info(ctx, "Received in-page recording stop request")
+
+		// 获取录制信息(包含start_url)
+		recInfo := m.recorder.GetRecordingInfo()
+
+		// 停止录制并获取下载文件信息
+		actions, err := m.recorder.StopRecording(ctx)
+		if err != nil {
+			logger.Error(ctx, "Failed to stop recording from in-page request: %v", err)
+			return
+		}
+		downloadedFiles := m.recorder.GetDownloadedFiles()
+		logger.Info(ctx, "✓ Recording stopped from in-page button, %d actions recorded, %d files downloaded",
+			len(actions), len(downloadedFiles))
+
+		// 保存录制结果、下载文件和URL,供前端获取
+		m.mu.Lock()
+		m.lastRecordedActions = actions
+		m.lastDownloadedFiles = downloadedFiles
+		m.inPageRecordingStopped = true
+		// 保存录制时的URL到持久化字段
+		if startURL, ok := recInfo["start_url"].(string); ok && startURL != "" {
+			m.lastRecordedStartURL = startURL
+			logger.Info(ctx, "Saved start URL: %s", startURL)
+		}
+		m.mu.Unlock()
+
+		// 通知页面:录制已停止
+		_, _ = uiEval(page, `() => {
+			window.__recordingStoppedByInPage__ = true;
+		}`)
+
+	case "screenshot":
+		// 注意：不在后端添加 action，因为前端已经通过 recordAction() 添加了
+		// 停止录制时会从前端的 window.__recordedActions__ 同步过来，这样避免重复添加截图操作
+		var data struct {
+			Mode string `json:"mode"`
+		}
+		_ = json.Unmarshal(cmd.Data, &data)
+		if data.Mode == "" {
+			data.Mode = "viewport"
+		}
+		logger.Info(ctx, "Screenshot action will be synced from frontend: mode=%s", data.Mode)
+
+	default:
+		logger.Warn(ctx, "Unknown in-page command: %s", cmd.Type)
+	}
+}
@@ -0,0 +1,29 @@
+package browser
+
+import (
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+func TestParsePageCommand(t *testing.T) {
+	cmd, ok := parsePageCommand(&proto.RuntimeBindingCalled{
+		Name:    pageCommandBinding,
+		Payload: `{"type":"screenshot","data":{"mode":"region","x":10}}`,
+	})
+	if !ok || cmd.Type != "screenshot" || len(cmd.Data) == 0 {
+		t.Fatalf("unexpected command: %+v, %v", cmd, ok)
+	}
+
+	// 其他 binding（如 Web Vitals 采集）和无效的负载不是命令
+	for _, e := range []*proto.RuntimeBindingCalled{
+		nil,
+		{Name: vitalsBinding, Payload: `{"type":"start_recording"}`},
+		{Name: pageCommandBinding, Payload: `not json`},
+		{Name: pageCommandBinding, Payload: `{"data":{}}`},
+	} {
+		if _, ok := parsePageCommand(e); ok {
+			t.Fatalf("expected %+v to be ignored", e)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package browser
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+// resolveComponentRefs 把脚本步骤中的页面组件引用（如 login.username）替换为组件中当前的定位器
+// 返回脚本副本，不修改原脚本；站点按步骤执行时所在页面的域名匹配（脚本 URL，之后随 navigate 步骤变化）
+func resolveComponentRefs(script *models.Script, components []*models.PageComponent) (*models.Script, error) {
+	resolved := *script
+	resolved.Actions = make([]models.ScriptAction, len(script.Actions))
+	host := urlHost(script.URL)
+	for i, action := range script.Actions {
+		if action.Type == "navigate" && action.URL != "" {
+			if h := urlHost(action.URL); h != "" {
+				host = h
+			}
+		}
+		if action.Component != "" {
+			element, err := lookupComponentElement(components, action.Component, host)
+			if err != nil {
+				return nil, fmt.Errorf("step %d: %w", i+1, err)
+			}
+			action.Selector = element.Selector
+			action.XPath = element.XPath
+			// 录制时的指纹描述的是旧元素，组件定位器被修改后不能再用它校正结果
+			action.Fingerprint = nil
+		}
+		resolved.Actions[i] = action
+	}
+	return &resolved, nil
+}
+
+// hasComponentRefs 判断脚本是否引用了页面组件
+func hasComponentRefs(script *models.Script) bool {
+	for _, action := range script.Actions {
+		if action.Component != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupComponentElement 按 "组件名.元素名" 查找元素
+// 同名组件中优先使用与域名匹配且站点最具体的组件，其次使用不限站点的组件
+func lookupComponentElement(components []*models.PageComponent, ref, host string) (*models.ComponentElement, error) {
+	name, elementName, ok := strings.Cut(ref, ".")
+	if !ok || name == "" || elementName == "" {
+		return nil, fmt.Errorf("invalid component reference %q (expected component.element)", ref)
+	}
+
+	var best *models.PageComponent
+	for _, component := range components {
+		if component.Name != name || !siteMatches(component.Site, host) {
+			continue
+		}
+		if best == nil || len(component.Site) > len(best.Site) {
+			best = component
+		}
+	}
+	if best == nil {
+		if host != "" {
+			return nil, fmt.Errorf("page component %q not found for %s", name, host)
+		}
+		return nil, fmt.Errorf("page component %q not found", name)
+	}
+
+	element, ok := best.Elements[elementName]
+	if !ok {
+		return nil, fmt.Errorf("element %q not found in page component %q", elementName, name)
+	}
+	if element.Selector == "" && element.XPath == "" {
+		return nil, fmt.Errorf("element %q in page component %q has no selector", elementName, name)
+	}
+	return &element, nil
+}
+
+// siteMatches 判断组件站点是否适用于域名：站点为空时适用于所有域名，否则匹配该域名及其子域名
+func siteMatches(site, host string) bool {
+	site = strings.ToLower(strings.TrimPrefix(site, "."))
+	if site == "" {
+		return true
+	}
+	host = strings.ToLower(host)
+	return host == site || strings.HasSuffix(host, "."+site)
+}
+
+// urlHost 提取 URL 的域名，无法解析（如包含未替换的变量）时返回空字符串
+func urlHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
@@ -0,0 +1,102 @@
+package browser
+
+import (
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+var testPageComponents = []*models.PageComponent{
+	{Name: "login", Elements: map[string]models.ComponentElement{
+		"username": {Selector: "input[name=username]"},
+		"submit":   {Selector: "button[type=submit]"},
+	}},
+	{Name: "login", Site: "example.com", Elements: map[string]models.ComponentElement{
+		"username": {Selector: "#user"},
+		"submit":   {XPath: "//button[@id='sign-in']"},
+	}},
+	{Name: "login", Site: "other.org", Elements: map[string]models.ComponentElement{
+		"username": {Selector: "#email"},
+	}},
+}
+
+func TestResolveComponentRefs(t *testing.T) {
+	script := &models.Script{
+		URL: "https://app.example.com/login",
+		Actions: []models.ScriptAction{
+			{Type: "input", Component: "login.username", Selector: "#old", Fingerprint: &models.ElementFingerprint{Tag: "input"}},
+			{Type: "click", Component: "login.submit"},
+			{Type: "navigate", URL: "https://other.org/"},
+			{Type: "input", Component: "login.username"},
+			{Type: "navigate", URL: "https://unknown.net/"},
+			{Type: "click", Component: "login.submit"},
+			{Type: "click", Selector: "#plain"},
+		},
+	}
+
+	resolved, err := resolveComponentRefs(script, testPageComponents)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []struct{ selector, xpath string }{
+		{"#user", ""},
+		{"", "//button[@id='sign-in']"},
+		{"", ""},
+		{"#email", ""},
+		{"", ""},
+		{"button[type=submit]", ""},
+		{"#plain", ""},
+	}
+	for i, w := range want {
+		if a := resolved.Actions[i]; a.Selector != w.selector || a.XPath != w.xpath {
+			t.Errorf("step %d: selector=%q xpath=%q, want %q %q", i+1, a.Selector, a.XPath, w.selector, w.xpath)
+		}
+	}
+	if resolved.Actions[0].Fingerprint != nil {
+		t.Error("fingerprint of a component step should be dropped")
+	}
+	if script.Actions[0].Selector != "#old" || script.Actions[0].Fingerprint == nil {
+		t.Error("original script must not be modified")
+	}
+}
+
+func TestResolveComponentRefsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		ref  string
+	}{
+		{"missing component", "https://example.com/", "signup.email"},
+		{"missing element", "https://example.com/", "login.password"},
+		// 站点专用组件优先，不会回退到通用组件中的同名元素
+		{"element missing in site component", "https://other.org/", "login.submit"},
+		{"invalid reference", "https://example.com/", "login"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script := &models.Script{URL: tt.url, Actions: []models.ScriptAction{{Type: "click", Component: tt.ref}}}
+			if _, err := resolveComponentRefs(script, testPageComponents); err == nil {
+				t.Errorf("expected error for %q", tt.ref)
+			}
+		})
+	}
+}
+
+func TestSiteMatches(t *testing.T) {
+	tests := []struct {
+		site, host string
+		want       bool
+	}{
+		{"", "anything.com", true},
+		{"example.com", "example.com", true},
+		{"example.com", "app.EXAMPLE.com", true},
+		{".example.com", "app.example.com", true},
+		{"example.com", "badexample.com", false},
+		{"example.com", "", false},
+	}
+	for _, tt := range tests {
+		if got := siteMatches(tt.site, tt.host); got != tt.want {
+			t.Errorf("siteMatches(%q, %q) = %v, want %v", tt.site, tt.host, got, tt.want)
+		}
+	}
+}
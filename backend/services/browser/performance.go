@@ -0,0 +1,274 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/artifacts"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// vitalsBinding 页面上报 Web Vitals 使用的 CDP binding 名称（跨页面导航保留）
+const vitalsBinding = "__browserwingReportVital"
+
+// traceCategories 性能 trace 录制的类别（与 DevTools Performance 面板一致）
+var traceCategories = []string{
+	"-*",
+	"devtools.timeline",
+	"disabled-by-default-devtools.timeline",
+	"disabled-by-default-devtools.timeline.frame",
+	"disabled-by-default-devtools.screenshot",
+	"toplevel",
+	"blink.console",
+	"blink.user_timing",
+	"latencyInfo",
+	"loading",
+	"v8.execute",
+	"disabled-by-default-v8.cpu_profiler",
+}
+
+// webVitalsScript 通过 PerformanceObserver 采集 Core Web Vitals，每次指标更新时通过 binding 上报
+// CLS 按会话窗口（间隔 1 秒、最长 5 秒）取最大值，INP 取最慢的一次交互
+const webVitalsScript = `(() => {
+	if (window.__browserwingVitalsInstalled__) return;
+	window.__browserwingVitalsInstalled__ = true;
+	const report = (name, value) => {
+		try {
+			window.` + vitalsBinding + `(JSON.stringify({ url: location.href, name, value }));
+		} catch (e) {}
+	};
+	const observe = (type, callback, options) => {
+		try {
+			new PerformanceObserver(list => list.getEntries().forEach(callback))
+				.observe(Object.assign({ type, buffered: true }, options || {}));
+		} catch (e) {}
+	};
+	observe('largest-contentful-paint', e => report('lcp', e.startTime));
+	observe('paint', e => { if (e.name === 'first-contentful-paint') report('fcp', e.startTime); });
+	observe('navigation', e => report('ttfb', e.responseStart));
+	let cls = 0, sessionValue = 0, sessionStart = 0, lastShift = 0;
+	observe('layout-shift', e => {
+		if (e.hadRecentInput) return;
+		if (sessionValue && (e.startTime - lastShift > 1000 || e.startTime - sessionStart > 5000)) sessionValue = 0;
+		if (!sessionValue) sessionStart = e.startTime;
+		sessionValue += e.value;
+		lastShift = e.startTime;
+		if (sessionValue > cls) { cls = sessionValue; report('cls', cls); }
+	});
+	let inp = 0;
+	observe('event', e => {
+		if (e.interactionId && e.duration > inp) { inp = e.duration; report('inp', inp); }
+	}, { durationThreshold: 16 });
+})();`
+
+// performanceCapture 一次回放的性能采集
+type performanceCapture struct {
+	page    *rod.Page
+	options *models.PerformanceOptions
+	cancel  context.CancelFunc
+
+	mu      sync.Mutex
+	pages   []*models.PageVitals
+	byURL   map[string]*models.PageVitals
+	tracing bool
+	errors  []string
+}
+
+// startPerformanceCapture 按脚本配置开启 CPU 降速、Web Vitals 采集和性能 trace 录制
+// 单项开启失败只记录错误，不影响回放
+func startPerformanceCapture(ctx context.Context, page *rod.Page, options *models.PerformanceOptions) *performanceCapture {
+	if !options.Enabled() {
+		return nil
+	}
+	c := &performanceCapture{
+		page:    page,
+		options: options,
+		byURL:   make(map[string]*models.PageVitals),
+	}
+
+	if options.CPUThrottling > 1 {
+		if err := (proto.EmulationSetCPUThrottlingRate{Rate: options.CPUThrottling}).Call(page); err != nil {
+			c.addError("cpu throttling", err)
+		} else {
+			logger.Info(ctx, "CPU throttling enabled for playback: %.1fx", options.CPUThrottling)
+		}
+	}
+
+	if options.WebVitals {
+		if err := c.startVitals(ctx); err != nil {
+			c.addError("web vitals", err)
+		}
+	}
+
+	if options.Trace {
+		err := proto.TracingStart{
+			TransferMode: proto.TracingStartTransferModeReturnAsStream,
+			TraceConfig:  &proto.TracingTraceConfig{IncludedCategories: traceCategories},
+		}.Call(page)
+		if err != nil {
+			c.addError("trace", err)
+		} else {
+			c.tracing = true
+			logger.Info(ctx, "Performance trace recording started")
+		}
+	}
+	return c
+}
+
+// startVitals 注册 binding 并在每个新文档中注入 Web Vitals 采集脚本
+func (c *performanceCapture) startVitals(ctx context.Context) error {
+	if err := (proto.RuntimeAddBinding{Name: vitalsBinding}).Call(c.page); err != nil {
+		return err
+	}
+	if _, err := c.page.EvalOnNewDocument(webVitalsScript); err != nil {
+		return err
+	}
+
+	eventCtx, cancel := context.WithCancel(ctx)
+	c.cancel = cancel
+	go c.page.Context(eventCtx).EachEvent(func(e *proto.RuntimeBindingCalled) {
+		if e.Name == vitalsBinding {
+			c.recordVital(e.Payload)
+		}
+	})()
+	return nil
+}
+
+// recordVital 记录页面上报的指标（同一 URL 的指标取最新值）
+func (c *performanceCapture) recordVital(payload string) {
+	var vital struct {
+		URL   string  `json:"url"`
+		Name  string  `json:"name"`
+		Value float64 `json:"value"`
+	}
+	if err := json.Unmarshal([]byte(payload), &vital); err != nil || vital.URL == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	pv, ok := c.byURL[vital.URL]
+	if !ok {
+		pv = &models.PageVitals{URL: vital.URL}
+		c.byURL[vital.URL] = pv
+		c.pages = append(c.pages, pv)
+	}
+	value := vital.Value
+	switch vital.Name {
+	case "lcp":
+		pv.LCP = &value
+	case "cls":
+		pv.CLS = &value
+	case "inp":
+		pv.INP = &value
+	case "fcp":
+		pv.FCP = &value
+	case "ttfb":
+		pv.TTFB = &value
+	}
+}
+
+func (c *performanceCapture) addError(what string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.errors = append(c.errors, fmt.Sprintf("%s: %v", what, err))
+}
+
+// stop 结束采集并返回性能数据，trace 保存到 tracePath（为空时不保存）
+func (c *performanceCapture) stop(ctx context.Context, tracePath string) *models.PerformanceMetrics {
+	if c == nil {
+		return nil
+	}
+
+	metrics := &models.PerformanceMetrics{}
+	if c.options.CPUThrottling > 1 {
+		metrics.CPUThrottling = c.options.CPUThrottling
+		_ = proto.EmulationSetCPUThrottlingRate{Rate: 1}.Call(c.page)
+	}
+
+	if c.tracing {
+		if err := c.saveTrace(ctx, tracePath); err != nil {
+			c.addError("trace", err)
+		} else {
+			metrics.TracePath = tracePath
+			logger.Info(ctx, "Performance trace saved: %s", tracePath)
+		}
+	}
+
+	// 等待页面上报最后的指标
+	if c.cancel != nil {
+		time.Sleep(200 * time.Millisecond)
+		c.cancel()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, pv := range c.pages {
+		metrics.Pages = append(metrics.Pages, *pv)
+	}
+	metrics.Errors = c.errors
+	return metrics
+}
+
+// saveTrace 结束 trace 录制并把 trace 数据写入文件
+func (c *performanceCapture) saveTrace(ctx context.Context, tracePath string) error {
+	waitCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	var complete proto.TracingTracingComplete
+	wait := c.page.Context(waitCtx).WaitEvent(&complete)
+	if err := (proto.TracingEnd{}).Call(c.page); err != nil {
+		return err
+	}
+	wait()
+	if complete.Stream == "" {
+		return fmt.Errorf("trace stream not available")
+	}
+	defer func() { _ = proto.IOClose{Handle: complete.Stream}.Call(c.page) }()
+
+	if tracePath == "" {
+		return fmt.Errorf("trace path not set")
+	}
+	f, err := os.Create(tracePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, rod.NewStreamReader(c.page, complete.Stream)); err != nil {
+		return fmt.Errorf("failed to read trace stream: %w", err)
+	}
+	return nil
+}
+
+// performanceTracePath 生成执行记录对应的 trace 文件路径（保存在下载目录中）
+func (m *Manager) performanceTracePath(ctx context.Context, execution *models.ScriptExecution) string {
+	sandbox, err := m.DownloadSandbox()
+	if err != nil {
+		logger.Warn(ctx, "Failed to resolve download directory for trace: %v", err)
+		return ""
+	}
+	subdir := m.ArtifactSubdir(artifacts.TemplateVars{
+		Script:    execution.ScriptName,
+		ScriptID:  execution.ScriptID,
+		Execution: execution.ID,
+		Time:      execution.StartTime,
+	})
+	if _, err := sandbox.Dir(subdir); err != nil {
+		logger.Warn(ctx, "Failed to create trace directory: %v", err)
+		return ""
+	}
+	path, err := sandbox.Resolve(subdir, artifacts.SanitizeFileName("trace_"+execution.ID+".json", "trace.json"))
+	if err != nil {
+		logger.Warn(ctx, "Failed to resolve trace path: %v", err)
+		return ""
+	}
+	return path
+}
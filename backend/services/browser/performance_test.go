@@ -0,0 +1,39 @@
+package browser
+
+import (
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+func TestPerformanceCaptureRecordVital(t *testing.T) {
+	c := &performanceCapture{byURL: make(map[string]*models.PageVitals)}
+
+	c.recordVital(`{"url":"https://a.com/","name":"lcp","value":1200}`)
+	c.recordVital(`{"url":"https://a.com/","name":"lcp","value":1500}`)
+	c.recordVital(`{"url":"https://a.com/","name":"cls","value":0.05}`)
+	c.recordVital(`{"url":"https://b.com/","name":"inp","value":80}`)
+	c.recordVital(`not json`)
+	c.recordVital(`{"name":"lcp","value":1}`)
+
+	if len(c.pages) != 2 {
+		t.Fatalf("pages = %d, want 2", len(c.pages))
+	}
+	a := c.pages[0]
+	if a.URL != "https://a.com/" || a.LCP == nil || *a.LCP != 1500 || a.CLS == nil || *a.CLS != 0.05 {
+		t.Errorf("unexpected vitals for a.com: %+v", a)
+	}
+	if a.INP != nil {
+		t.Errorf("a.com should have no INP, got %v", *a.INP)
+	}
+	if b := c.pages[1]; b.INP == nil || *b.INP != 80 {
+		t.Errorf("unexpected vitals for b.com: %+v", b)
+	}
+
+	if (&models.PerformanceOptions{CPUThrottling: 1}).Enabled() {
+		t.Error("1x CPU throttling alone should not enable capture")
+	}
+	if !(&models.PerformanceOptions{CPUThrottling: 4}).Enabled() {
+		t.Error("4x CPU throttling should enable capture")
+	}
+}
@@ -0,0 +1,50 @@
+package browser
+
+import (
+	"context"
+	"time"
+
+	"github.com/browserwing/browserwing/config"
+	"github.com/browserwing/browserwing/models"
+	"github.com/go-rod/rod"
+)
+
+// defaultElementWait 回放时每次查找元素的默认等待时间（失败的步骤会重试）
+const defaultElementWait = 5 * time.Second
+
+// playbackTimeouts 计算脚本回放的超时：脚本的 timeouts 设置优先，其次是配置文件的 [timeouts]
+// 配置文件未设置元素超时时，回放每次查找元素等待 5 秒，不使用 API 和 MCP 操作默认的 10 秒
+func playbackTimeouts(cfg *config.TimeoutsConfig, script *models.Script) (element, navigation time.Duration) {
+	element = defaultElementWait
+	if cfg != nil && cfg.ElementSeconds > 0 {
+		element = cfg.Element()
+	}
+	navigation = cfg.Navigation()
+
+	if t := script.Timeouts; t != nil {
+		if t.ElementSeconds > 0 {
+			element = time.Duration(t.ElementSeconds) * time.Second
+		}
+		if t.NavigationSeconds > 0 {
+			navigation = time.Duration(t.NavigationSeconds) * time.Second
+		}
+	}
+	return element, navigation
+}
+
+// elementTimeout 每次查找元素的等待时间
+func (p *Player) elementTimeout() time.Duration {
+	if p.elementWait > 0 {
+		return p.elementWait
+	}
+	return defaultElementWait
+}
+
+// withNavigationTimeout 返回带导航超时的页面，用于 Navigate 和 WaitLoad；未设置超时时返回原页面
+func (p *Player) withNavigationTimeout(ctx context.Context, page *rod.Page) (*rod.Page, context.CancelFunc) {
+	if p.navigationTimeout <= 0 {
+		return page, func() {}
+	}
+	navCtx, cancel := context.WithTimeout(ctx, p.navigationTimeout)
+	return page.Context(navCtx), cancel
+}
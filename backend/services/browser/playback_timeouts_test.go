@@ -0,0 +1,31 @@
+package browser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/browserwing/browserwing/config"
+	"github.com/browserwing/browserwing/models"
+)
+
+func TestPlaybackTimeouts(t *testing.T) {
+	cases := []struct {
+		name           string
+		cfg            *config.TimeoutsConfig
+		script         *models.ScriptTimeouts
+		wantElement    time.Duration
+		wantNavigation time.Duration
+	}{
+		{"defaults", nil, nil, 5 * time.Second, 60 * time.Second},
+		{"only snapshot configured", &config.TimeoutsConfig{SnapshotSeconds: 30}, nil, 5 * time.Second, 60 * time.Second},
+		{"config", &config.TimeoutsConfig{ElementSeconds: 20, NavigationSeconds: 120}, nil, 20 * time.Second, 120 * time.Second},
+		{"script overrides config", &config.TimeoutsConfig{ElementSeconds: 20, NavigationSeconds: 120}, &models.ScriptTimeouts{ElementSeconds: 2}, 2 * time.Second, 120 * time.Second},
+		{"script overrides defaults", nil, &models.ScriptTimeouts{NavigationSeconds: 300}, 5 * time.Second, 300 * time.Second},
+	}
+	for _, tc := range cases {
+		element, navigation := playbackTimeouts(tc.cfg, &models.Script{Timeouts: tc.script})
+		if element != tc.wantElement || navigation != tc.wantNavigation {
+			t.Errorf("%s: got element %v, navigation %v, want %v, %v", tc.name, element, navigation, tc.wantElement, tc.wantNavigation)
+		}
+	}
+}
@@ -1,14 +1,12 @@
 package browser
 
 import (
+	"bytes"
 	"context"
 	_ "embed"
 	"encoding/json"
 	"fmt"
 	"image"
-	"image/color/palette"
-	"image/draw"
-	"image/gif"
 	"image/jpeg"
 	"io"
 	"net/http"
@@ -17,13 +15,16 @@ import (
 	"runtime"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/artifacts"
 	"github.com/browserwing/browserwing/pkg/logger"
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/input"
 	"github.com/go-rod/rod/lib/proto"
+	"github.com/google/uuid"
 )
 
 //go:embed scripts/indicator.js
@@ -38,25 +39,48 @@ type BrowserManagerInterface interface {
 }
 
 type Player struct {
-	extractedData     map[string]interface{}          // 存储抓取的数据
-	successCount      int                             // 成功步骤数
-	failCount         int                             // 失败步骤数
-	recordingPage     *rod.Page                       // 录制的页面
-	recordingOutputs  chan *proto.PageScreencastFrame // 录制帧通道
-	recordingDone     chan bool                       // 录制完成信号
-	pages             map[int]*rod.Page               // 多标签页支持 (key: tab index)
-	currentPage       *rod.Page                       // 当前活动页面
-	tabCounter        int                             // 标签页计数器
-	downloadedFiles   []string                        // 下载的文件路径列表
-	downloadPath      string                          // 下载目录路径
-	downloadCtx       context.Context                 // 下载监听上下文
-	downloadCancel    context.CancelFunc              // 取消下载监听
-	currentScriptName string                          // 当前执行的脚本名称
-	currentLang       string                          // 当前语言设置
-	currentActions    []models.ScriptAction           // 当前执行的脚本动作列表
-	currentStepIndex  int                             // 当前执行到的步骤索引
-	agentManager      AgentManagerInterface           // Agent 管理器（用于 AI 控制功能）
-	browserManager    BrowserManagerInterface         // Browser 管理器（用于同步活跃页面）
+	extractedData     map[string]interface{}                         // 存储抓取的数据
+	successCount      int                                            // 成功步骤数
+	failCount         int                                            // 失败步骤数
+	firstStepErr      error                                          // 第一个失败步骤的错误（用于整体失败的分类码）
+	stepOutcomes      []models.StepOutcome                           // 各步骤的结果和耗时（写入执行记录）
+	recordingPage     *rod.Page                                      // 录制的页面
+	recordingOutputs  chan *proto.PageScreencastFrame                // 录制帧通道
+	recordingDone     chan bool                                      // 录制完成信号
+	recordingOpts     VideoRecordingOptions                          // 当前录制的选项
+	recordingMu       sync.Mutex                                     // 保护 recordingCrop 和 recordingSteps
+	recordingCrop     image.Rectangle                                // 录制区域对应的帧像素范围（为空时不裁剪）
+	recordingStart    time.Time                                      // 录制开始时间
+	recordingSteps    []recordingStep                                // 录制期间执行的步骤（用于字幕和元数据轨道）
+	pages             map[int]*rod.Page                              // 多标签页支持 (key: tab index)
+	currentPage       *rod.Page                                      // 当前活动页面
+	tabCounter        int                                            // 标签页计数器
+	popups            []popupTab                                     // wait_popup 打开的弹出窗口栈，wait_popup_close 后回到打开者
+	downloadedFiles   []string                                       // 下载的文件路径列表
+	downloadPath      string                                         // 下载目录路径
+	workDir           string                                         // 本次执行的临时工作目录（为空时使用系统临时目录）
+	downloadCancel    context.CancelFunc                             // 取消下载监听
+	currentScriptName string                                         // 当前执行的脚本名称
+	currentLang       string                                         // 当前语言设置
+	currentActions    []models.ScriptAction                          // 当前执行的脚本动作列表
+	currentStepIndex  int                                            // 当前执行到的步骤索引
+	agentManager      AgentManagerInterface                          // Agent 管理器（用于 AI 控制功能）
+	browserManager    BrowserManagerInterface                        // Browser 管理器（用于同步活跃页面）
+	extraHeaders      map[string]string                              // 回放期间附加的 HTTP 请求头
+	userAgent         string                                         // 回放期间覆盖的 User-Agent
+	responseCapture   *ResponseCapture                               // capture_response 的响应捕获器
+	requestLog        *requestLog                                    // 验证步骤 ExpectedRequests 的请求日志（脚本不使用时为 nil）
+	elementWait       time.Duration                                  // 每次查找元素的等待时间（为 0 时使用默认的 5 秒）
+	navigationTimeout time.Duration                                  // 打开页面的超时（为 0 时不限制）
+	urlChecker        func(ctx context.Context, rawURL string) error // 导航前的 URL 访问策略检查
+	a11yScanner       a11yScanFunc                                   // a11y_scan 使用的可访问性扫描
+	uploadResolver    func(paths []string) ([]string, error)         // 将 upload_file 中的上传文件句柄解析为本地路径
+	scrapeState       *scrapeState                                   // 增量抓取状态（脚本不使用时为 nil）
+
+	loginResolver func(ctx context.Context, pageURL string) (*models.Script, error) // 返回页面所在域名指定的登录脚本（为 nil 时不自动重新登录）
+	loggingIn     bool                                                              // 正在执行登录脚本
+	reauthed      map[string]bool                                                   // 本次回放已执行过的登录脚本 ID
+	resumeURL     string                                                            // 最近导航到的页面，重新登录后回到这里
 }
 
 // highlightElement 高亮显示元素
@@ -330,110 +354,10 @@ func (p *Player) markStepCompleted(ctx context.Context, page *rod.Page, stepInde
 	}
 }
 
-// getI18nText 获取国际化文本
+// getI18nText 获取回放指示器的国际化文本
 func getI18nText(key, lang string) string {
-	// 翻译映射表
-	translations := map[string]map[string]string{
-		"zh-CN": {
-			// AI 控制指示器
-			"ai.control.title":     "Browserwing AI 控制中",
-			"ai.control.script":    "执行脚本:",
-			"ai.control.ready":     "准备执行脚本...",
-			"ai.control.step":      "步骤",
-			"ai.control.completed": "✓ 完成",
-			"ai.control.success":   "成功",
-			"ai.control.failed":    "失败",
-			// 操作类型
-			"action.click":             "点击元素",
-			"action.input":             "输入文本",
-			"action.select":            "选择选项",
-			"action.navigate":          "页面导航",
-			"action.wait":              "等待加载",
-			"action.sleep":             "延迟等待",
-			"action.extract_text":      "提取文本",
-			"action.extract_html":      "提取HTML",
-			"action.extract_attribute": "提取属性",
-			"action.execute_js":        "执行JS",
-			"action.upload_file":       "上传文件",
-			"action.scroll":            "滚动页面",
-			"action.keyboard":          "键盘事件",
-			"action.screenshot":        "截图",
-			"action.open_tab":          "打开新标签页",
-			"action.switch_tab":        "切换标签页",
-			"action.switch_active_tab": "切换到活跃标签页",
-			"action.capture_xhr":       "捕获XHR请求",
-			"action.ai_control":        "AI控制",
-		},
-		"zh-TW": {
-			// AI 控制指示器
-			"ai.control.title":     "Browserwing AI 控制中",
-			"ai.control.script":    "執行腳本:",
-			"ai.control.ready":     "準備執行腳本...",
-			"ai.control.step":      "步驟",
-			"ai.control.completed": "✓ 完成",
-			"ai.control.success":   "成功",
-			"ai.control.failed":    "失敗",
-			// 操作類型
-			"action.click":             "點擊元素",
-			"action.input":             "輸入文字",
-			"action.select":            "選擇選項",
-			"action.navigate":          "頁面導航",
-			"action.wait":              "等待載入",
-			"action.sleep":             "延遲等待",
-			"action.extract_text":      "提取文字",
-			"action.extract_html":      "提取HTML",
-			"action.extract_attribute": "提取屬性",
-			"action.execute_js":        "執行JS",
-			"action.upload_file":       "上傳檔案",
-			"action.scroll":            "滾動頁面",
-			"action.keyboard":          "鍵盤事件",
-			"action.screenshot":        "截圖",
-			"action.open_tab":          "打開新標籤頁",
-			"action.switch_tab":        "切換標籤頁",
-			"action.switch_active_tab": "切換到活躍標籤頁",
-			"action.capture_xhr":       "捕獲XHR請求",
-			"action.ai_control":        "AI控制",
-		},
-		"en": {
-			// AI Control Indicator
-			"ai.control.title":     "Browserwing AI Control",
-			"ai.control.script":    "Executing Script:",
-			"ai.control.ready":     "Preparing to execute script...",
-			"ai.control.step":      "Step",
-			"ai.control.completed": "✓ Completed",
-			"ai.control.success":   "Success",
-			"ai.control.failed":    "Failed",
-			// Action Types
-			"action.click":             "Click Element",
-			"action.input":             "Input Text",
-			"action.select":            "Select Option",
-			"action.navigate":          "Navigate Page",
-			"action.wait":              "Wait for Load",
-			"action.sleep":             "Sleep",
-			"action.extract_text":      "Extract Text",
-			"action.extract_html":      "Extract HTML",
-			"action.extract_attribute": "Extract Attribute",
-			"action.execute_js":        "Execute JS",
-			"action.upload_file":       "Upload File",
-			"action.scroll":            "Scroll Page",
-			"action.keyboard":          "Keyboard Event",
-			"action.screenshot":        "Screenshot",
-			"action.open_tab":          "Open New Tab",
-			"action.switch_tab":        "Switch Tab",
-			"action.switch_active_tab": "Switch to Active Tab",
-			"action.capture_xhr":       "Capture XHR Request",
-			"action.ai_control":        "AI Control",
-		},
-	}
-
-	// 如果语言不存在，默认使用英文
-	langMap, exists := translations[lang]
-	if !exists {
-		langMap = translations["en"]
-	}
-
 	// 返回翻译文本，如果不存在则返回 key
-	if text, exists := langMap[key]; exists {
+	if text, exists := UITexts(UIPlayer, lang)[key]; exists {
 		return text
 	}
 	return key
@@ -476,30 +400,53 @@ func (p *Player) SetDownloadPath(downloadPath string) {
 	p.downloadPath = downloadPath
 }
 
-// StartDownloadListener 启动下载事件监听
-func (p *Player) StartDownloadListener(ctx context.Context, browser *rod.Browser) {
+// SetWorkDir 设置本次执行的临时工作目录，录屏帧和上传前下载的文件都放在其中
+func (p *Player) SetWorkDir(workDir string) {
+	p.workDir = workDir
+}
+
+// framesDir 返回录屏帧的保存目录：有工作目录时放在工作目录下，否则放在 GIF 文件旁边
+func (p *Player) framesDir(outputPath string) string {
+	if p.workDir != "" {
+		return filepath.Join(p.workDir, "frames")
+	}
+	return strings.TrimSuffix(outputPath, ".gif") + "_frames"
+}
+
+// tempDir 返回存放中间文件的目录
+func (p *Player) tempDir() string {
+	if p.workDir != "" {
+		return p.workDir
+	}
+	return os.TempDir()
+}
+
+// StartDownloadListener 订阅浏览器实例事件总线上的下载事件
+func (p *Player) StartDownloadListener(ctx context.Context, events *EventBus) {
 	if p.downloadPath == "" {
 		logger.Warn(ctx, "Download path not set, skipping download listener")
 		return
 	}
-
-	// 创建可取消的上下文
-	p.downloadCtx, p.downloadCancel = context.WithCancel(ctx)
+	if events == nil {
+		logger.Warn(ctx, "Browser event bus not available, skipping download listener")
+		return
+	}
 
 	logger.Info(ctx, "Starting download event listener for path: %s", p.downloadPath)
 
-	// 记录每个下载的 GUID 到文件名的映射
+	// 记录每个下载的 GUID 到文件名的映射（事件在总线的分发协程中依次处理）
 	downloadMap := make(map[string]string)
 
-	// 监听下载开始事件 (BrowserDownloadWillBegin)
-	go browser.Context(p.downloadCtx).EachEvent(func(e *proto.BrowserDownloadWillBegin) {
-		// 记录 GUID 和建议的文件名
-		downloadMap[e.GUID] = e.SuggestedFilename
-		logger.Info(ctx, "📥 Download will begin: %s (GUID: %s)", e.SuggestedFilename, e.GUID)
-	})()
+	p.downloadCancel = events.Subscribe(func(be BrowserEvent) {
+		if be.Type == EventDownloadWillBegin {
+			// 记录 GUID 和建议的文件名
+			e := be.DownloadBegin
+			downloadMap[e.GUID] = e.SuggestedFilename
+			logger.Info(ctx, "📥 Download will begin: %s (GUID: %s)", e.SuggestedFilename, e.GUID)
+			return
+		}
 
-	// 监听下载进度事件 (BrowserDownloadProgress)
-	go browser.Context(p.downloadCtx).EachEvent(func(e *proto.BrowserDownloadProgress) {
+		e := be.DownloadProgress
 		if e.State == proto.BrowserDownloadProgressStateCompleted {
 			// 下载完成，从映射中获取文件名
 			fileName, exists := downloadMap[e.GUID]
@@ -512,7 +459,8 @@ func (p *Player) StartDownloadListener(ctx context.Context, browser *rod.Browser
 				return
 			}
 
-			// 构建完整路径
+			// 构建完整路径（建议文件名来自页面，清理后避免跳出下载目录）
+			fileName = artifacts.SanitizeFileName(fileName, "download")
 			fullPath := filepath.Join(p.downloadPath, fileName)
 
 			// 检查文件是否实际存在（可能浏览器自动重命名了）
@@ -547,7 +495,7 @@ func (p *Player) StartDownloadListener(ctx context.Context, browser *rod.Browser
 			logger.Warn(ctx, "Download canceled (GUID: %s)", e.GUID)
 			delete(downloadMap, e.GUID)
 		}
-	})()
+	}, EventDownloadWillBegin, EventDownloadProgress)
 
 	logger.Info(ctx, "Download event listener started")
 }
@@ -613,6 +561,25 @@ func (p *Player) GetExtractedData() map[string]interface{} {
 	return p.extractedData
 }
 
+// recordStepOutcome 记录步骤的结果和耗时
+func (p *Player) recordStepOutcome(index int, action models.ScriptAction, status string, stepErr error, start time.Time) {
+	outcome := models.StepOutcome{
+		Index:    index,
+		Type:     action.Type,
+		Status:   status,
+		Duration: time.Since(start).Milliseconds(),
+	}
+	if stepErr != nil {
+		outcome.Error = stepErr.Error()
+	}
+	p.stepOutcomes = append(p.stepOutcomes, outcome)
+}
+
+// GetStepOutcomes 获取各步骤的结果和耗时
+func (p *Player) GetStepOutcomes() []models.StepOutcome {
+	return p.stepOutcomes
+}
+
 // GetSuccessCount 获取成功步骤数
 func (p *Player) GetSuccessCount() int {
 	return p.successCount
@@ -627,29 +594,49 @@ func (p *Player) GetFailCount() int {
 func (p *Player) ResetStats() {
 	p.successCount = 0
 	p.failCount = 0
+	p.firstStepErr = nil
+	p.stepOutcomes = nil
 	p.extractedData = make(map[string]interface{})
 	// 注意：不清空录制相关字段，因为录制可能在 PlayScript 之前就已经启动
 	// 录制字段只在 StopVideoRecording 中清空
 }
 
+// VideoRecordingOptions 视频录制选项
+type VideoRecordingOptions struct {
+	FrameRate int                   // 帧率，超出的帧会被丢弃（默认 15）
+	Quality   int                   // JPEG 质量 0-100（默认 70）
+	MaxWidth  int                   // 画面最大宽度（像素，0 表示不限制）
+	MaxHeight int                   // 画面最大高度（像素，0 表示不限制）
+	Region    *models.CaptureRegion // 录制区域（相对视口的 CSS 像素），为空时录制整个视口
+}
+
 // StartVideoRecording 开始视频录制（使用 Chrome DevTools Protocol）
-func (p *Player) StartVideoRecording(page *rod.Page, outputPath string, frameRate, quality int) error {
+func (p *Player) StartVideoRecording(page *rod.Page, outputPath string, opts VideoRecordingOptions) error {
 	if page == nil {
 		return fmt.Errorf("page is empty, cannot start recording")
 	}
-
-	p.recordingPage = page
-	p.recordingOutputs = make(chan *proto.PageScreencastFrame, 100)
-	p.recordingDone = make(chan bool)
+	if r := opts.Region; r != nil && (r.Width <= 0 || r.Height <= 0 || r.X < 0 || r.Y < 0) {
+		return fmt.Errorf("invalid capture region: %+v", *r)
+	}
 
 	// 启动 screencast
-	if frameRate <= 0 {
-		frameRate = 15
+	if opts.FrameRate <= 0 {
+		opts.FrameRate = 15
 	}
-	if quality <= 0 || quality > 100 {
-		quality = 70
+	if opts.Quality <= 0 || opts.Quality > 100 {
+		opts.Quality = 70
 	}
 
+	p.recordingPage = page
+	p.recordingOutputs = make(chan *proto.PageScreencastFrame, 100)
+	p.recordingDone = make(chan bool)
+	p.recordingOpts = opts
+	p.recordingStart = time.Now()
+	p.recordingMu.Lock()
+	p.recordingCrop = image.Rectangle{}
+	p.recordingSteps = nil
+	p.recordingMu.Unlock()
+
 	ctx := page.GetContext()
 
 	// 在启动 screencast 之前就开始监听事件，避免丢失帧
@@ -661,17 +648,23 @@ func (p *Player) StartVideoRecording(page *rod.Page, outputPath string, frameRat
 	time.Sleep(100 * time.Millisecond)
 
 	// 启动屏幕录制
-	format := proto.PageStartScreencastFormatJpeg
-	err := proto.PageStartScreencast{
-		Format:  format,
-		Quality: &quality,
-	}.Call(page)
-	if err != nil {
+	screencast := proto.PageStartScreencast{
+		Format:  proto.PageStartScreencastFormatJpeg,
+		Quality: &opts.Quality,
+	}
+	if opts.MaxWidth > 0 {
+		screencast.MaxWidth = &opts.MaxWidth
+	}
+	if opts.MaxHeight > 0 {
+		screencast.MaxHeight = &opts.MaxHeight
+	}
+	if err := screencast.Call(page); err != nil {
 		close(p.recordingDone) // 清理
 		return fmt.Errorf("failed to start screencast: %w", err)
 	}
 
-	logger.Info(ctx, "Video recording started: frame rate=%d, quality=%d", frameRate, quality)
+	logger.Info(ctx, "Video recording started: frame rate=%d, quality=%d, max size=%dx%d, region=%v",
+		opts.FrameRate, opts.Quality, opts.MaxWidth, opts.MaxHeight, opts.Region)
 	return nil
 }
 
@@ -683,7 +676,7 @@ func (p *Player) saveScreencastFrames(ctx context.Context, page *rod.Page, outpu
 	}
 
 	// 创建输出目录
-	baseDir := strings.TrimSuffix(outputPath, ".gif") + "_frames"
+	baseDir := p.framesDir(outputPath)
 	if err := os.MkdirAll(baseDir, 0o755); err != nil {
 		logger.Warn(ctx, "Failed to create output directory: %v", err)
 		return
@@ -692,10 +685,36 @@ func (p *Player) saveScreencastFrames(ctx context.Context, page *rod.Page, outpu
 	logger.Info(ctx, "Start listening to recording frames, output directory: %s", baseDir)
 
 	frameIndex := 0
+	dropped := 0
+	opts := p.recordingOpts
+	// Chrome 会在页面变化时尽可能快地推送帧，按帧率丢弃间隔过短的帧
+	minInterval := time.Second / time.Duration(opts.FrameRate)
+	var lastFrame time.Time
 
 	// 监听 screencast 帧事件
 	// 注意：不要再嵌套 goroutine，这个函数本身就在 goroutine 中运行
 	page.EachEvent(func(e *proto.PageScreencastFrame) {
+		// 确认帧已处理，否则 Chrome 不会继续推送
+		defer func() {
+			_ = proto.PageScreencastFrameAck{
+				SessionID: e.SessionID,
+			}.Call(page)
+		}()
+
+		frameTime := time.Now()
+		if e.Metadata != nil && e.Metadata.Timestamp > 0 {
+			frameTime = e.Metadata.Timestamp.Time()
+		}
+		if !lastFrame.IsZero() && frameTime.Sub(lastFrame) < minInterval {
+			dropped++
+			return
+		}
+		lastFrame = frameTime
+
+		if frameIndex == 0 && opts.Region != nil {
+			p.setRecordingCrop(ctx, opts.Region, e)
+		}
+
 		// 保存帧数据
 		framePath := fmt.Sprintf("%s/frame_%05d.jpg", baseDir, frameIndex)
 		data := []byte(e.Data)
@@ -707,17 +726,30 @@ func (p *Player) saveScreencastFrames(ctx context.Context, page *rod.Page, outpu
 			}
 		}
 
-		// 确认帧已处理
-		_ = proto.PageScreencastFrameAck{
-			SessionID: e.SessionID,
-		}.Call(page)
-
 		frameIndex++
 	})()
 
 	// 等待录制完成信号
 	<-p.recordingDone
-	logger.Info(ctx, "Recording completed, recorded %d frames, saved in: %s", frameIndex, baseDir)
+	logger.Info(ctx, "Recording completed, recorded %d frames (%d dropped by frame rate limit), saved in: %s", frameIndex, dropped, baseDir)
+}
+
+// setRecordingCrop 根据第一帧的尺寸，将录制区域（CSS 像素）换算为帧像素范围
+func (p *Player) setRecordingCrop(ctx context.Context, region *models.CaptureRegion, e *proto.PageScreencastFrame) {
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(e.Data))
+	if err != nil || e.Metadata == nil || e.Metadata.DeviceWidth <= 0 {
+		logger.Warn(ctx, "Failed to determine frame scale, capture region ignored: %v", err)
+		return
+	}
+	crop := regionToFrameRect(region, float64(cfg.Width)/e.Metadata.DeviceWidth, cfg.Width, cfg.Height)
+	if crop.Empty() {
+		logger.Warn(ctx, "Capture region %+v is outside the viewport, ignored", *region)
+		return
+	}
+	p.recordingMu.Lock()
+	p.recordingCrop = crop
+	p.recordingMu.Unlock()
+	logger.Info(ctx, "Recording region %+v mapped to frame pixels %v", *region, crop)
 }
 
 // StopVideoRecording 停止视频录制
@@ -736,8 +768,9 @@ func (p *Player) StopVideoRecording(outputPath string, frameRate int) error {
 	}
 	logger.Info(ctx, "Stopping video recording...")
 
-	// 先停止 screencast
+	// 先停止 screencast，并移除录制时显示的步骤说明
 	if page != nil {
+		_, _ = page.Eval(`() => document.getElementById('browserwing-step-caption')?.remove()`)
 		err := proto.PageStopScreencast{}.Call(page)
 		if err != nil {
 			logger.Warn(ctx, "Failed to stop screencast: %v", err)
@@ -760,22 +793,37 @@ func (p *Player) StopVideoRecording(outputPath string, frameRate int) error {
 	p.recordingPage = nil
 	p.recordingOutputs = nil
 	p.recordingDone = nil
+	p.recordingMu.Lock()
+	crop := p.recordingCrop
+	steps := p.recordingSteps
+	p.recordingCrop = image.Rectangle{}
+	p.recordingSteps = nil
+	p.recordingMu.Unlock()
+	duration := time.Since(p.recordingStart).Seconds()
 
 	// 将帧序列转换为 GIF
 	if outputPath != "" {
-		if err := p.convertFramesToGIF(ctx, outputPath, frameRate); err != nil {
+		if err := p.convertFramesToGIF(ctx, outputPath, frameRate, crop); err != nil {
 			logger.Warn(ctx, "Failed to convert frames to GIF: %v", err)
 			return err
 		}
+
+		// 生成步骤字幕和元数据轨道
+		files, err := writeRecordingTrack(outputPath, steps, duration)
+		if err != nil {
+			logger.Warn(ctx, "Failed to write recording step track: %v", err)
+		} else if len(files) > 0 {
+			logger.Info(ctx, "Recording step track saved: %s", strings.Join(files, ", "))
+		}
 	}
 
 	logger.Info(ctx, "Video recording stopped")
 	return nil
 }
 
-// convertFramesToGIF 将帧序列转换为 GIF 动画
-func (p *Player) convertFramesToGIF(ctx context.Context, outputPath string, frameRate int) error {
-	baseDir := strings.TrimSuffix(outputPath, ".gif") + "_frames"
+// convertFramesToGIF 将帧序列转换为 GIF 动画，crop 不为空时只保留该区域
+func (p *Player) convertFramesToGIF(ctx context.Context, outputPath string, frameRate int, crop image.Rectangle) error {
+	baseDir := p.framesDir(outputPath)
 
 	// 检查帧目录是否存在
 	if _, err := os.Stat(baseDir); os.IsNotExist(err) {
@@ -818,88 +866,31 @@ func (p *Player) convertFramesToGIF(ctx context.Context, outputPath string, fram
 		logger.Info(ctx, "To control file size, sample 1 frame every %d frames", skipFrames)
 	}
 
-	// 准备 GIF 数据结构
-	gifData := &gif.GIF{}
-	delay := 100 / frameRate // 每帧延迟时间（单位：1/100秒）
-
-	// 处理每一帧
-	processedFrames := 0
+	// 跳帧时按比例延长每帧的显示时间，保持播放速度与实际一致
+	delay := 100 * skipFrames / frameRate // 每帧延迟时间（单位：1/100秒）
+	sampled := make([]string, 0, (len(files)+skipFrames-1)/skipFrames)
 	for i, framePath := range files {
-		// 跳帧处理
-		if i%skipFrames != 0 {
-			continue
-		}
-
-		// 读取 JPEG 帧
-		frameFile, err := os.Open(framePath)
-		if err != nil {
-			logger.Warn(ctx, "Failed to open frame file: %v", err)
-			continue
-		}
-
-		// 解码 JPEG
-		img, err := jpeg.Decode(frameFile)
-		frameFile.Close()
-		if err != nil {
-			logger.Warn(ctx, "Failed to decode frame: %v", err)
-			continue
-		}
-
-		// 为了减小 GIF 体积，缩小图片尺寸
-		// 将宽度缩放到 800px（保持宽高比）
-		bounds := img.Bounds()
-		origWidth := bounds.Dx()
-		origHeight := bounds.Dy()
-
-		targetWidth := 800
-		if origWidth < targetWidth {
-			targetWidth = origWidth
-		}
-		targetHeight := origHeight * targetWidth / origWidth
-
-		// 创建缩小后的图片
-		resized := image.NewRGBA(image.Rect(0, 0, targetWidth, targetHeight))
-
-		// 简单的最近邻缩放
-		for y := range targetHeight {
-			for x := 0; x < targetWidth; x++ {
-				srcX := x * origWidth / targetWidth
-				srcY := y * origHeight / targetHeight
-				resized.Set(x, y, img.At(srcX, srcY))
-			}
-		}
-
-		// 转换为调色板图片（GIF 需要）
-		palettedImg := image.NewPaletted(resized.Bounds(), palette.Plan9)
-		draw.FloydSteinberg.Draw(palettedImg, resized.Bounds(), resized, image.Point{})
-
-		// 添加到 GIF
-		gifData.Image = append(gifData.Image, palettedImg)
-		gifData.Delay = append(gifData.Delay, delay)
-
-		processedFrames++
-		if processedFrames%10 == 0 {
-			logger.Info(ctx, "Processed %d/%d frames", processedFrames, (len(files)+skipFrames-1)/skipFrames)
+		if i%skipFrames == 0 {
+			sampled = append(sampled, framePath)
 		}
 	}
 
-	if len(gifData.Image) == 0 {
-		return fmt.Errorf("no frames were processed successfully")
-	}
-
-	logger.Info(ctx, "Processed %d frames in total, saving GIF...", len(gifData.Image))
-
-	// 保存 GIF 文件
+	// 保存 GIF 文件：多个 worker 并行处理帧，按顺序流式写入
 	outFile, err := os.Create(outputPath)
 	if err != nil {
 		return fmt.Errorf("failed to create output file: %w", err)
 	}
-	defer outFile.Close()
-
-	err = gif.EncodeAll(outFile, gifData)
+	workers := runtime.NumCPU()
+	logger.Info(ctx, "Encoding %d frames with %d workers...", len(sampled), workers)
+	written, err := encodeGIFFrames(ctx, outFile, sampled, crop, delay, workers)
+	if closeErr := outFile.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write GIF file: %w", closeErr)
+	}
 	if err != nil {
+		os.Remove(outputPath)
 		return fmt.Errorf("failed to encode GIF: %w", err)
 	}
+	logger.Info(ctx, "Processed %d frames in total", written)
 
 	logger.Info(ctx, "✓ GIF conversion completed: %s", outputPath)
 
@@ -937,6 +928,8 @@ func (p *Player) PlayScript(ctx context.Context, page *rod.Page, script *models.
 
 	// 重置统计和抓取数据
 	p.ResetStats()
+	p.reauthed = nil
+	p.resumeURL = script.URL
 
 	// 初始化变量上下文（包含脚本预设变量）
 	variables := make(map[string]string)
@@ -952,16 +945,43 @@ func (p *Player) PlayScript(ctx context.Context, page *rod.Page, script *models.
 	p.tabCounter = 0
 	p.pages[p.tabCounter] = page
 	p.currentPage = page
+	p.popups = nil
+
+	// 应用脚本声明的请求头和 User-Agent 覆盖（需在导航前设置）
+	p.setRequestOverrides(script, variables)
+	p.applyRequestOverrides(ctx, page)
+
+	// 开始捕获 capture_response 需要的响应（需在导航前开始监听）
+	p.startResponseCapture(ctx, page, script.Actions)
+	defer p.stopResponseCapture()
+
+	// 记录步骤发出的请求，用于验证 ExpectedRequests
+	p.startRequestLog(ctx, page, script.Actions)
+	defer p.stopRequestLog()
 
 	// 导航到起始URL
 	if script.URL != "" {
 		logger.Info(ctx, "Navigate to: %s", script.URL)
-		if err := page.Navigate(script.URL); err != nil {
+		if err := p.checkURL(ctx, script.URL); err != nil {
+			return err
+		}
+		navPage, cancelNav := p.withNavigationTimeout(ctx, page)
+		if err := navPage.Navigate(script.URL); err != nil {
+			cancelNav()
 			return fmt.Errorf("navigation failed: %w", err)
 		}
-		if err := page.WaitLoad(); err != nil {
+		if err := navPage.WaitLoad(); err != nil {
 			logger.Warn(ctx, "Failed to wait for page to load: %v", err)
 		}
+		cancelNav()
+		// 被验证码或反爬页面拦截时不执行后续步骤
+		if err := checkBlockPage(ctx, page); err != nil {
+			return err
+		}
+		// 已退出登录时先执行站点的登录脚本
+		if _, err := p.reauthenticate(ctx, page, script.URL); err != nil {
+			return err
+		}
 		// 等待页面稳定
 		time.Sleep(2 * time.Second)
 
@@ -988,12 +1008,27 @@ func (p *Player) PlayScript(ctx context.Context, page *rod.Page, script *models.
 	}
 
 	// 执行每个操作
+	labels := runLabels(script)
+	if len(labels) > 0 {
+		logger.Info(ctx, "Run environment: %s, tags: %v", script.Environment, script.RunTags)
+	}
 	for i, action := range script.Actions {
 		p.currentStepIndex = i
+		stepStart := time.Now()
 		logger.Info(ctx, "[%d/%d] Execute action: %s", i+1, len(script.Actions), action.Type)
 
 		// 更新 AI 控制状态显示（标记为执行中）
 		p.updateAIControlStatus(ctx, page, i+1, len(script.Actions), action.Type)
+		p.beginRecordingStep(ctx, page, i+1, len(script.Actions), action)
+
+		// 检查步骤开关（禁用、只在/不在某些环境执行）
+		if reason := stepSkipReason(action, labels); reason != "" {
+			logger.Info(ctx, "Skipping action: %s", reason)
+			p.markStepCompleted(ctx, page, i+1, true)
+			p.endRecordingStep(ctx, page, recordingStepSkipped, nil)
+			p.recordStepOutcome(i+1, action, models.StepStatusSkipped, nil, stepStart)
+			continue
+		}
 
 		// 检查条件执行
 		if action.Condition != nil && action.Condition.Enabled {
@@ -1005,22 +1040,50 @@ func (p *Player) PlayScript(ctx context.Context, page *rod.Page, script *models.
 					action.Condition.Variable, action.Condition.Operator, action.Condition.Value)
 				// 标记为跳过（视为成功）
 				p.markStepCompleted(ctx, page, i+1, true)
+				p.endRecordingStep(ctx, page, recordingStepSkipped, nil)
+				p.recordStepOutcome(i+1, action, models.StepStatusSkipped, nil, stepStart)
 				continue
 			}
 			logger.Info(ctx, "Condition met, executing action: %s %s %s",
 				action.Condition.Variable, action.Condition.Operator, action.Condition.Value)
 		}
 
-		if err := p.executeAction(ctx, page, action); err != nil {
+		err := p.executeAndVerify(ctx, page, action)
+		// 步骤失败可能是会话过期被带到了登录页：重新登录后重试一次
+		if code := models.ErrorCodeOf(err); err != nil && code != models.ErrorCodeCaptchaDetected && code != models.ErrorCodeLoginRequired {
+			if ok, reauthErr := p.reauthenticate(ctx, page, ""); reauthErr != nil {
+				err = reauthErr
+			} else if ok {
+				logger.Info(ctx, "Retrying action after logging in again: %s", action.Type)
+				err = p.executeAndVerify(ctx, page, action)
+			}
+		}
+		if err != nil {
 			logger.Warn(ctx, "Action execution failed (continuing with subsequent steps): %v", err)
 			p.failCount++
+			if p.firstStepErr == nil {
+				p.firstStepErr = err
+			}
 			// 标记步骤为失败
 			p.markStepCompleted(ctx, page, i+1, false)
+			p.endRecordingStep(ctx, page, recordingStepFailed, err)
+			p.recordStepOutcome(i+1, action, models.StepStatusFailed, err, stepStart)
+			// 被验证码或反爬页面拦截、或无法重新登录时后续步骤都会失败，立即停止
+			if code := models.ErrorCodeOf(err); code == models.ErrorCodeCaptchaDetected || code == models.ErrorCodeLoginRequired {
+				return err
+			}
 			// 不要中断，继续执行下一步
 		} else {
 			p.successCount++
 			// 标记步骤为成功
 			p.markStepCompleted(ctx, page, i+1, true)
+			p.endRecordingStep(ctx, page, recordingStepSuccess, nil)
+			p.recordStepOutcome(i+1, action, models.StepStatusSuccess, nil, stepStart)
+
+			// 增量抓取：去掉之前已输出过的条目，记录游标
+			if p.scrapeState != nil && action.VariableName != "" && (action.Dedup || action.UpdateCursor) {
+				p.applyScrapeState(ctx, action)
+			}
 
 			// 如果 action 提取了数据，更新变量上下文
 			if action.VariableName != "" && p.extractedData[action.VariableName] != nil {
@@ -1035,9 +1098,9 @@ func (p *Player) PlayScript(ctx context.Context, page *rod.Page, script *models.
 		logger.Info(ctx, "Extracted %d data items", len(p.extractedData))
 	}
 
-	// 如果所有操作都失败了，返回错误
+	// 如果所有操作都失败了，返回错误（分类码取自第一个失败的步骤）
 	if p.failCount > 0 && p.successCount == 0 {
-		return fmt.Errorf("all operations failed")
+		return models.WithErrorCode(ClassifyError(p.firstStepErr), fmt.Errorf("all operations failed"))
 	}
 
 	return nil
@@ -1172,6 +1235,10 @@ func (p *Player) executeAction(ctx context.Context, page *rod.Page, action model
 		return p.executeSwitchActiveTab(ctx)
 	case "switch_tab":
 		return p.executeSwitchTab(ctx, action)
+	case "wait_popup":
+		return p.executeWaitPopup(ctx, action)
+	case "wait_popup_close":
+		return p.executeWaitPopupClose(ctx, action)
 	case "click":
 		return p.executeClick(ctx, activePage, action)
 	case "input":
@@ -1202,8 +1269,17 @@ func (p *Player) executeAction(ctx context.Context, page *rod.Page, action model
 		return p.executeScreenshot(ctx, activePage, action)
 	case "capture_xhr":
 		return p.executeCaptureXHR(ctx, activePage, action)
+	case "capture_response":
+		return p.executeCaptureResponse(ctx, action)
+	case "hover_then_click":
+		return p.executeHoverThenClick(ctx, activePage, action)
 	case "ai_control":
 		return p.executeAIControl(ctx, activePage, action)
+	case "a11y_scan":
+		return p.executeA11yScan(ctx, activePage, action)
+	case "call_script":
+		// 子脚本在回放前由 BrowserManager 展开，执行到这里说明调用方跳过了展开
+		return fmt.Errorf("call_script %s was not expanded before playback", action.ScriptID)
 	default:
 		logger.Warn(ctx, "Unknown action type: %s", action.Type)
 		return nil
@@ -1239,7 +1315,7 @@ func (p *Player) executeClick(ctx context.Context, page *rod.Page, action models
 				logger.Warn(ctx, "Element not found, waiting and retrying: %v", err)
 				continue
 			}
-			return fmt.Errorf("element not found: %w", err)
+			return elementNotFoundError("element not found: %w", err)
 		}
 
 		// 从上下文中提取元素
@@ -1258,7 +1334,9 @@ func (p *Player) executeClick(ctx context.Context, page *rod.Page, action models
 
 		// 高亮显示元素
 		p.highlightElement(ctx, element)
-		defer p.unhighlightElement(ctx, element) // 检查元素是否可点击（pointer-events 不为 none）
+		defer p.unhighlightElement(ctx, element)
+		p.markRecordingClick(ctx, element)
+		// 检查元素是否可点击（pointer-events 不为 none）
 		isClickable, _ := element.Eval(`() => {
 			const style = window.getComputedStyle(this);
 			return style.pointerEvents !== 'none' && style.display !== 'none' && style.visibility !== 'hidden';
@@ -1302,6 +1380,65 @@ func (p *Player) executeClick(ctx context.Context, page *rod.Page, action models
 	return fmt.Errorf("click operation failed")
 }
 
+// executeHoverThenClick 悬停触发元素，等待目标元素出现后点击（悬停菜单）
+// 悬停和点击在同一步中完成，目标未出现时移开鼠标重新悬停
+func (p *Player) executeHoverThenClick(ctx context.Context, page *rod.Page, action models.ScriptAction) error {
+	if action.TargetSelector == "" && action.TargetXPath == "" {
+		return fmt.Errorf("hover_then_click action requires target selector")
+	}
+	logger.Info(ctx, "Hover %s then click %s%s", action.Selector+action.XPath, action.TargetSelector, action.TargetXPath)
+
+	timeout := time.Duration(action.Duration) * time.Millisecond
+	if timeout <= 0 {
+		timeout = p.elementTimeout()
+	}
+	targetAction := models.ScriptAction{Selector: action.TargetSelector, XPath: action.TargetXPath}
+
+	var lastErr error
+	maxRetries := 3
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		if attempt > 1 {
+			logger.Warn(ctx, "Hover then click attempt %d failed, retrying: %v", attempt-1, lastErr)
+			// 移开鼠标让菜单收起
+			_ = page.Mouse.MoveTo(proto.Point{X: 0, Y: 0})
+			time.Sleep(500 * time.Millisecond)
+		}
+
+		triggerCtx, err := p.findElementWithContext(ctx, page, action)
+		if err != nil {
+			lastErr = elementNotFoundError("trigger not found: %w", err)
+			continue
+		}
+		if err := triggerCtx.element.ScrollIntoView(); err != nil {
+			logger.Warn(ctx, "Failed to scroll to trigger: %v", err)
+		}
+		if err := triggerCtx.element.Hover(); err != nil {
+			lastErr = fmt.Errorf("failed to hover trigger: %w", err)
+			continue
+		}
+
+		targetCtx, err := p.findElementWithContext(ctx, page, targetAction)
+		if err != nil {
+			lastErr = fmt.Errorf("target not revealed: %w", err)
+			continue
+		}
+		target := targetCtx.element
+		if err := target.Timeout(timeout).WaitVisible(); err != nil {
+			lastErr = fmt.Errorf("target not visible: %w", err)
+			continue
+		}
+		if err := target.Click(proto.InputMouseButtonLeft, 1); err != nil {
+			lastErr = fmt.Errorf("failed to click target: %w", err)
+			continue
+		}
+
+		logger.Info(ctx, "✓ Hover then click successful")
+		return nil
+	}
+
+	return fmt.Errorf("hover then click failed after %d attempts: %w", maxRetries, lastErr)
+}
+
 // executeInput 执行输入操作
 func (p *Player) executeInput(ctx context.Context, page *rod.Page, action models.ScriptAction) error {
 	selector := action.Selector
@@ -1315,7 +1452,7 @@ func (p *Player) executeInput(ctx context.Context, page *rod.Page, action models
 	// 使用新的 findElement 方法（支持 iframe）
 	elementInfo, err := p.findElementWithContext(ctx, page, action)
 	if err != nil {
-		return fmt.Errorf("input box not found: %w", err)
+		return elementNotFoundError("input box not found: %w", err)
 	}
 
 	element := elementInfo.element
@@ -1359,222 +1496,60 @@ func (p *Player) executeInput(ctx context.Context, page *rod.Page, action models
 	}
 	time.Sleep(200 * time.Millisecond)
 
-	// 检查是否是 contenteditable 元素
-	isContentEditable := false
-	contentEditableResult, _ := element.Eval(`() => this.contentEditable`)
-
-	if contentEditableResult != nil && contentEditableResult.Value.String() == "true" {
-		isContentEditable = true
-		logger.Info(ctx, "Detected contenteditable element")
+	// 富文本编辑器使用对应的编辑器策略（可通过 editor_strategy 指定）
+	strategy, err := resolveEditorStrategy(ctx, element, action.EditorStrategy)
+	if err != nil {
+		return err
 	}
-
-	if isContentEditable {
-		// 对于 contenteditable 元素，需要使用真实的键盘事件
-		// 因为 Draft.js 等编辑器依赖键盘事件来更新内部状态
-		logger.Info(ctx, "Using keyboard input to simulate contenteditable element")
-
-		// 确保元素已获得焦点
-		if err := element.Focus(); err != nil {
-			logger.Warn(ctx, "Failed to focus element: %v", err)
+	if strategy != nil {
+		logger.Info(ctx, "Using %s editor strategy", strategy.Name())
+		if err := strategy.Fill(ctx, targetPage, element, action.Value); err != nil {
+			return fmt.Errorf("failed to input text with %s editor strategy: %w", strategy.Name(), err)
 		}
-		time.Sleep(200 * time.Millisecond)
+		logger.Info(ctx, "✓ Input successful")
+		return nil
+	}
 
-		// contenteditable 元素不支持 SelectAllText，直接使用快捷键清空
-		// 使用 Ctrl+A 全选现有内容
-		targetPage.KeyActions().Press(input.ControlLeft).Type('a').Release(input.ControlLeft).MustDo()
-		time.Sleep(100 * time.Millisecond)
+	// 传统输入框：先尝试清空内容，然后输入
+	logger.Info(ctx, "Processing traditional input element")
 
-		// 按 Backspace 清空
-		targetPage.KeyActions().Press(input.Backspace).MustDo()
-		time.Sleep(100 * time.Millisecond)
+	// 尝试全选文本（如果失败，使用其他方法清空）
+	selectErr := element.SelectAllText()
+	if selectErr != nil {
+		logger.Warn(ctx, "SelectAllText failed: %v, trying other clearing methods", selectErr)
 
-		// 使用 targetPage.InsertText 方法输入文本（支持 Unicode 字符）
-		// InsertText 会触发 beforeinput 和 input 事件，Draft.js 能正确响应
-		err := targetPage.InsertText(action.Value)
-		if err != nil {
-			logger.Warn(ctx, "InsertText failed, trying character-by-character input: %v", err)
-			// 回退方案：逐字符输入（只对 ASCII 字符有效）
-			for _, char := range action.Value {
-				if char < 128 {
-					targetPage.KeyActions().Type(input.Key(char)).MustDo()
-					time.Sleep(5 * time.Millisecond)
-				}
-			}
+		// 方法1: 使用 JavaScript 清空
+		_, jsErr := element.Eval(`() => { this.value = ''; this.textContent = ''; }`)
+		if jsErr != nil {
+			logger.Warn(ctx, "JavaScript clearing failed: %v", jsErr)
 		}
 
-		logger.Info(ctx, "✓ Keyboard input completed")
-
-		// 等待一下让编辑器状态更新
-		time.Sleep(300 * time.Millisecond)
-
+		// 方法2: 使用快捷键清空
+		targetPage.KeyActions().Press(input.ControlLeft).Type('a').Release(input.ControlLeft).MustDo()
+		time.Sleep(50 * time.Millisecond)
+		targetPage.KeyActions().Press(input.Backspace).MustDo()
+		time.Sleep(50 * time.Millisecond)
 	} else {
-		// 传统输入框：先尝试清空内容，然后输入
-		logger.Info(ctx, "Processing traditional input element")
-
-		// 尝试全选文本（如果失败，使用其他方法清空）
-		selectErr := element.SelectAllText()
-		if selectErr != nil {
-			logger.Warn(ctx, "SelectAllText failed: %v, trying other clearing methods", selectErr)
-
-			// 方法1: 使用 JavaScript 清空
-			_, jsErr := element.Eval(`() => { this.value = ''; this.textContent = ''; }`)
-			if jsErr != nil {
-				logger.Warn(ctx, "JavaScript clearing failed: %v", jsErr)
-			}
-
-			// 方法2: 使用快捷键清空
-			targetPage.KeyActions().Press(input.ControlLeft).Type('a').Release(input.ControlLeft).MustDo()
-			time.Sleep(50 * time.Millisecond)
-			targetPage.KeyActions().Press(input.Backspace).MustDo()
-			time.Sleep(50 * time.Millisecond)
-		} else {
-			logger.Info(ctx, "✓ Text selection successful")
-		}
-
-		// 尝试输入文本
-		inputErr := element.Input(action.Value)
-		if inputErr != nil {
-			logger.Warn(ctx, "element.Input failed: %v, trying InsertText", inputErr)
-
-			// 回退到 InsertText 方法
-			insertErr := targetPage.InsertText(action.Value)
-			if insertErr != nil {
-				return fmt.Errorf("failed to input text (Input: %v, InsertText: %v)", inputErr, insertErr)
-			}
-			logger.Info(ctx, "✓ Input successful using InsertText")
-		} else {
-			logger.Info(ctx, "✓ Input successful using element.Input")
-		}
+		logger.Info(ctx, "✓ Text selection successful")
 	}
 
-	// 触发额外的事件来确保编辑器识别内容变化
-	// 这对富文本编辑器（如 CSDN）特别重要
-	time.Sleep(200 * time.Millisecond)
-
-	// 构建选择器参数（去掉 iframe 前缀，因为我们已经在正确的上下文中）
-	elemSelector := action.Selector
-	elemXPath := action.XPath
-
-	// 如果是 iframe 元素，移除 "iframe " 前缀和 "//iframe" 前缀
-	if len(elemSelector) > 7 && elemSelector[:7] == "iframe " {
-		elemSelector = elemSelector[7:]
-	}
-	if len(elemXPath) > 8 && elemXPath[:8] == "//iframe" {
-		elemXPath = elemXPath[8:]
-	}
+	// 尝试输入文本
+	inputErr := element.Input(action.Value)
+	if inputErr != nil {
+		logger.Warn(ctx, "element.Input failed: %v, trying InsertText", inputErr)
 
-	_, triggerErr := targetPage.Eval(`(sel, xp, val) => {
-		// 尝试找到元素
-		let element = null;
-		if (xp) {
-			try {
-				element = document.evaluate(xp, document, null, XPathResult.FIRST_ORDERED_NODE_TYPE, null).singleNodeValue;
-			} catch (e) {}
+		// 回退到 InsertText 方法
+		insertErr := targetPage.InsertText(action.Value)
+		if insertErr != nil {
+			return fmt.Errorf("failed to input text (Input: %v, InsertText: %v)", inputErr, insertErr)
 		}
-		if (!element && sel && sel !== 'unknown') {
-			try {
-				element = document.querySelector(sel);
-			} catch (e) {}
-		}
-		
-		if (!element) {
-			console.warn('[BrowserWing] Could not find element for event trigger');
-			return false;
-		}
-		
-		console.log('[BrowserWing] Triggering editor update events for:', element.tagName);
-		
-		// 1. 触发标准事件序列
-		const events = ['input', 'change', 'keyup'];
-		events.forEach(eventType => {
-			try {
-				const event = new Event(eventType, { bubbles: true, cancelable: true });
-				element.dispatchEvent(event);
-			} catch (e) {
-				console.warn('Failed to dispatch ' + eventType, e);
-			}
-		});
-		
-		// 2. 对于 contenteditable，强制设置内容并触发更多事件
-		if (element.contentEditable === 'true' || element.isContentEditable) {
-			try {
-				// 保存当前内容
-				const currentContent = element.textContent || element.innerText || '';
-				
-				// 如果当前内容与预期不同，强制设置
-				if (currentContent !== val && val) {
-					console.log('[BrowserWing] Force setting content:', val.substring(0, 50));
-					element.textContent = val;
-				}
-				
-				// 触发 focus 确保编辑器激活
-				element.focus();
-				
-				// 触发 InputEvent（现代编辑器依赖此事件）
-				try {
-					const inputEvent = new InputEvent('input', {
-						bubbles: true,
-						cancelable: true,
-						inputType: 'insertText',
-						data: val
-					});
-					element.dispatchEvent(inputEvent);
-				} catch (e) {
-					console.warn('InputEvent failed', e);
-				}
-				
-				// 触发 compositionend（某些亚洲语言输入法编辑器需要）
-				try {
-					const compositionEvent = new CompositionEvent('compositionend', {
-						bubbles: true,
-						cancelable: true,
-						data: val
-					});
-					element.dispatchEvent(compositionEvent);
-				} catch (e) {
-					console.warn('CompositionEvent failed', e);
-				}
-				
-				// 触发 DOMCharacterDataModified（旧版编辑器可能需要）
-				try {
-					const mutationEvent = document.createEvent('MutationEvent');
-					mutationEvent.initMutationEvent('DOMCharacterDataModified', true, false, element, '', val, '', 0);
-					element.dispatchEvent(mutationEvent);
-				} catch (e) {
-					// DOMCharacterDataModified 已废弃，某些浏览器可能不支持
-				}
-				
-				// 短暂失焦再聚焦，触发编辑器的验证逻辑
-				setTimeout(() => {
-					element.blur();
-					const blurEvent = new Event('blur', { bubbles: true });
-					element.dispatchEvent(blurEvent);
-					
-					setTimeout(() => {
-						element.focus();
-						const focusEvent = new Event('focus', { bubbles: true });
-						element.dispatchEvent(focusEvent);
-					}, 50);
-				}, 100);
-				
-				console.log('[BrowserWing] Editor update events triggered successfully');
-				
-			} catch (e) {
-				console.warn('Failed to update contenteditable', e);
-			}
-		}
-		
-		return true;
-	}`, elemSelector, elemXPath, action.Value)
-
-	if triggerErr != nil {
-		logger.Warn(ctx, "Failed to trigger editor update event: %v", triggerErr)
+		logger.Info(ctx, "✓ Input successful using InsertText")
 	} else {
-		logger.Info(ctx, "✓ Editor content update event triggered")
+		logger.Info(ctx, "✓ Input successful using element.Input")
 	}
 
-	// 再等待一下确保编辑器完全响应
-	time.Sleep(500 * time.Millisecond)
+	// 触发额外的事件来确保编辑器识别内容变化
+	triggerEditorUpdateEvents(ctx, element, action.Value)
 
 	logger.Info(ctx, "✓ Input successful")
 	return nil
@@ -1593,7 +1568,7 @@ func (p *Player) executeSelect(ctx context.Context, page *rod.Page, action model
 	// 使用新的 findElementWithContext 方法（支持 iframe）
 	elemCtx, err := p.findElementWithContext(ctx, page, action)
 	if err != nil {
-		return fmt.Errorf("select box not found: %w", err)
+		return elementNotFoundError("select box not found: %w", err)
 	}
 
 	// 从上下文中提取元素
@@ -1627,19 +1602,41 @@ func (p *Player) executeSelect(ctx context.Context, page *rod.Page, action model
 func (p *Player) executeNavigate(ctx context.Context, page *rod.Page, action models.ScriptAction) error {
 	logger.Info(ctx, "Navigate to: %s", action.URL)
 
-	if err := page.Navigate(action.URL); err != nil {
+	if err := p.checkURL(ctx, action.URL); err != nil {
+		return err
+	}
+	navPage, cancelNav := p.withNavigationTimeout(ctx, page)
+	defer cancelNav()
+	if err := navPage.Navigate(action.URL); err != nil {
 		return fmt.Errorf("navigation failed: %w", err)
 	}
 
-	if err := page.WaitLoad(); err != nil {
+	if err := navPage.WaitLoad(); err != nil {
 		return fmt.Errorf("failed to wait for page to load: %w", err)
 	}
+	if err := checkBlockPage(ctx, page); err != nil {
+		return err
+	}
+	if !p.loggingIn {
+		p.resumeURL = action.URL
+		if _, err := p.reauthenticate(ctx, page, action.URL); err != nil {
+			return err
+		}
+	}
 
 	p.ensureAIControlIndicator(ctx, page)
 
 	return nil
 }
 
+// checkURL 检查回放中的导航地址是否满足 URL 访问策略（未设置检查器时不做限制）
+func (p *Player) checkURL(ctx context.Context, rawURL string) error {
+	if p.urlChecker == nil {
+		return nil
+	}
+	return p.urlChecker(ctx, rawURL)
+}
+
 // executeWait 执行等待操作
 func (p *Player) executeWait(ctx context.Context, action models.ScriptAction) error {
 	duration := time.Duration(action.Timestamp) * time.Millisecond
@@ -1744,7 +1741,7 @@ func (p *Player) findElementWithContext(ctx context.Context, page *rod.Page, act
 			logger.Warn(ctx, "Element not found in iframe #%d: %v", i, findErr)
 		}
 
-		return nil, fmt.Errorf("element not found in any iframe")
+		return nil, elementNotFoundError("element not found in any iframe")
 	}
 
 	// 普通元素（非 iframe）
@@ -1752,17 +1749,33 @@ func (p *Player) findElementWithContext(ctx context.Context, page *rod.Page, act
 	var err error
 
 	if xpath != "" {
-		element, err = page.Timeout(5 * time.Second).ElementX(xpath)
+		element, err = page.Timeout(p.elementTimeout()).ElementX(xpath)
 		if err != nil && selector != "" && selector != "unknown" {
 			logger.Warn(ctx, "XPath lookup failed, trying CSS: %v", err)
-			element, err = page.Timeout(5 * time.Second).Element(selector)
+			element, err = page.Timeout(p.elementTimeout()).Element(selector)
 		}
 	} else if selector != "" && selector != "unknown" {
-		element, err = page.Timeout(5 * time.Second).Element(selector)
-	} else {
+		element, err = page.Timeout(p.elementTimeout()).Element(selector)
+	} else if action.Fingerprint == nil {
 		return nil, fmt.Errorf("missing valid selector")
 	}
 
+	// 有录制指纹时校正选择器结果，选择器未命中时按指纹查找最相似的元素
+	if action.Fingerprint != nil {
+		if err != nil {
+			logger.Warn(ctx, "Selector lookup failed, trying fingerprint: %v", err)
+			element = nil
+		}
+		resolved, fpErr := p.resolveByFingerprint(ctx, page, action, element)
+		if fpErr != nil {
+			if err != nil {
+				return nil, fmt.Errorf("%w (fingerprint fallback: %v)", err, fpErr)
+			}
+			return nil, fpErr
+		}
+		element, err = resolved, nil
+	}
+
 	if err != nil {
 		return nil, err
 	}
@@ -1780,7 +1793,7 @@ func (p *Player) executeExtractText(ctx context.Context, page *rod.Page, action
 
 	elemCtx, err := p.findElementWithContext(ctx, page, action)
 	if err != nil {
-		return fmt.Errorf("element not found: %w", err)
+		return elementNotFoundError("element not found: %w", err)
 	}
 
 	element := elemCtx.element
@@ -1817,7 +1830,7 @@ func (p *Player) executeExtractHTML(ctx context.Context, page *rod.Page, action
 
 	elemCtx, err := p.findElementWithContext(ctx, page, action)
 	if err != nil {
-		return fmt.Errorf("element not found: %w", err)
+		return elementNotFoundError("element not found: %w", err)
 	}
 
 	element := elemCtx.element
@@ -1858,7 +1871,7 @@ func (p *Player) executeExtractAttribute(ctx context.Context, page *rod.Page, ac
 
 	elemCtx, err := p.findElementWithContext(ctx, page, action)
 	if err != nil {
-		return fmt.Errorf("element not found: %w", err)
+		return elementNotFoundError("element not found: %w", err)
 	}
 
 	element := elemCtx.element
@@ -1996,6 +2009,7 @@ func (p *Player) executeScroll(ctx context.Context, page *rod.Page, action model
 }
 
 // downloadFileFromURL 从 HTTP(S) URL 下载文件到临时目录
+// 每个文件放在单独的子目录中，保留原文件名的同时避免同名文件互相覆盖
 func (p *Player) downloadFileFromURL(ctx context.Context, url string) (string, error) {
 	logger.Info(ctx, "Downloading file from URL: %s", url)
 
@@ -2042,12 +2056,19 @@ func (p *Player) downloadFileFromURL(ctx context.Context, url string) (string, e
 	}
 
 	// 创建临时文件
-	tempDir := os.TempDir()
+	if err := os.MkdirAll(p.tempDir(), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	tempDir, err := os.MkdirTemp(p.tempDir(), "upload-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
 	tempFile := filepath.Join(tempDir, fileName)
 
 	// 创建目标文件
 	out, err := os.Create(tempFile)
 	if err != nil {
+		os.RemoveAll(tempDir)
 		return "", fmt.Errorf("failed to create temp file: %w", err)
 	}
 	defer out.Close()
@@ -2055,7 +2076,7 @@ func (p *Player) downloadFileFromURL(ctx context.Context, url string) (string, e
 	// 复制内容到文件
 	_, err = io.Copy(out, resp.Body)
 	if err != nil {
-		os.Remove(tempFile) // 清理失败的文件
+		os.RemoveAll(tempDir) // 清理失败的文件
 		return "", fmt.Errorf("failed to save file: %w", err)
 	}
 
@@ -2084,11 +2105,21 @@ func (p *Player) executeUploadFile(ctx context.Context, page *rod.Page, action m
 
 	logger.Info(ctx, "Preparing to upload %d files: %v", len(action.FilePaths), action.FilePaths)
 
+	// 通过上传接口保存的文件以句柄引用，解析为上传目录中的路径
+	filePaths := action.FilePaths
+	if p.uploadResolver != nil {
+		resolved, err := p.uploadResolver(filePaths)
+		if err != nil {
+			return err
+		}
+		filePaths = resolved
+	}
+
 	// 处理 HTTP(S) 链接，先下载到本地
-	localFilePaths := make([]string, 0, len(action.FilePaths))
+	localFilePaths := make([]string, 0, len(filePaths))
 	downloadedFiles := make([]string, 0) // 记录需要清理的临时文件
 
-	for _, filePath := range action.FilePaths {
+	for _, filePath := range filePaths {
 		// 检查是否是 HTTP(S) 链接
 		if strings.HasPrefix(strings.ToLower(filePath), "http://") ||
 			strings.HasPrefix(strings.ToLower(filePath), "https://") {
@@ -2097,7 +2128,7 @@ func (p *Player) executeUploadFile(ctx context.Context, page *rod.Page, action m
 			if err != nil {
 				// 清理已下载的临时文件
 				for _, tmpFile := range downloadedFiles {
-					os.Remove(tmpFile)
+					os.RemoveAll(filepath.Dir(tmpFile))
 				}
 				return fmt.Errorf("failed to download file from %s: %w", filePath, err)
 			}
@@ -2112,7 +2143,7 @@ func (p *Player) executeUploadFile(ctx context.Context, page *rod.Page, action m
 	// 延迟清理下载的临时文件
 	defer func() {
 		for _, tmpFile := range downloadedFiles {
-			if err := os.Remove(tmpFile); err != nil {
+			if err := os.RemoveAll(filepath.Dir(tmpFile)); err != nil {
 				logger.Warn(ctx, "Failed to cleanup temp file %s: %v", tmpFile, err)
 			} else {
 				logger.Info(ctx, "Cleaned up temp file: %s", tmpFile)
@@ -2137,7 +2168,7 @@ func (p *Player) executeUploadFile(ctx context.Context, page *rod.Page, action m
 				logger.Warn(ctx, "Element not found, waiting and retrying: %v", err)
 				continue
 			}
-			return fmt.Errorf("element not found: %w", err)
+			return elementNotFoundError("element not found: %w", err)
 		}
 
 		element := elemCtx.element
@@ -2635,11 +2666,13 @@ func (p *Player) executeScreenshot(ctx context.Context, page *rod.Page, action m
 	// 等待页面稳定
 	time.Sleep(500 * time.Millisecond)
 
-	// 截图前隐藏AI控制指示器，避免被截入图片
+	// 截图前隐藏AI控制指示器和录制步骤说明，避免被截入图片
 	_, _ = page.Eval(`() => {
-		const indicator = document.getElementById('browserwing-ai-indicator');
-		if (indicator) {
-			indicator.style.display = 'none';
+		for (const id of ['browserwing-ai-indicator', 'browserwing-step-caption']) {
+			const el = document.getElementById(id);
+			if (el) {
+				el.style.display = 'none';
+			}
 		}
 	}`)
 
@@ -2689,12 +2722,16 @@ func (p *Player) executeScreenshot(ctx context.Context, page *rod.Page, action m
 		return fmt.Errorf("unsupported screenshot mode: %s", mode)
 	}
 
-	// 截图完成后恢复显示AI控制指示器
+	// 截图完成后恢复显示AI控制指示器和录制步骤说明
 	_, _ = page.Eval(`() => {
 		const indicator = document.getElementById('browserwing-ai-indicator');
 		if (indicator) {
 			indicator.style.display = 'block';
 		}
+		const caption = document.getElementById('browserwing-step-caption');
+		if (caption) {
+			caption.style.display = '';
+		}
 	}`)
 
 	// 确保下载目录存在
@@ -2707,8 +2744,8 @@ func (p *Player) executeScreenshot(ctx context.Context, page *rod.Page, action m
 		return fmt.Errorf("failed to create download directory: %w", err)
 	}
 
-	// 生成唯一的文件名
-	timestamp := time.Now().Format("20060102_150405")
+	// 生成唯一的文件名（秒级时间戳加随机后缀，避免并发任务同一秒内互相覆盖）
+	timestamp := fmt.Sprintf("%s_%s", time.Now().Format("20060102_150405"), uuid.NewString()[:8])
 	fileName := fmt.Sprintf("browserwing_screenshot_%s_%s.png", mode, timestamp)
 
 	// 如果有自定义变量名，使用它作为文件名前缀
@@ -2760,18 +2797,29 @@ func (p *Player) executeOpenTab(ctx context.Context, page *rod.Page, action mode
 	}
 
 	logger.Info(ctx, "Opening new tab with URL: %s", url)
+	if err := p.checkURL(ctx, url); err != nil {
+		return err
+	}
 
 	// 获取浏览器实例
 	browser := page.Browser()
 
-	// 创建新页面（新标签页）
-	newPage, err := browser.Page(proto.TargetCreateTarget{URL: url})
+	// 创建新页面（新标签页），先应用请求覆盖再导航
+	newPage, err := browser.Page(proto.TargetCreateTarget{})
 	if err != nil {
 		return fmt.Errorf("failed to create new tab: %w", err)
 	}
+	p.applyRequestOverrides(ctx, newPage)
+	p.watchResponses(ctx, newPage)
+	p.watchRequests(ctx, newPage)
+	navPage, cancelNav := p.withNavigationTimeout(ctx, newPage)
+	defer cancelNav()
+	if err := navPage.Navigate(url); err != nil {
+		return fmt.Errorf("failed to navigate new tab: %w", err)
+	}
 
 	// 等待新页面加载
-	if err := newPage.WaitLoad(); err != nil {
+	if err := navPage.WaitLoad(); err != nil {
 		logger.Warn(ctx, "Failed to wait for new tab to load: %v", err)
 	}
 
@@ -2875,6 +2923,9 @@ func (p *Player) executeSwitchActiveTab(ctx context.Context) error {
 		// 如果活跃页面不在 pages map 中，添加它
 		p.tabCounter++
 		p.pages[p.tabCounter] = activePage
+		p.applyRequestOverrides(ctx, activePage)
+		p.watchResponses(ctx, activePage)
+		p.watchRequests(ctx, activePage)
 		logger.Info(ctx, "Added active page to pages map with index: %d", p.tabCounter)
 	}
 
@@ -2906,6 +2957,10 @@ func (p *Player) executeSwitchTab(ctx context.Context, action models.ScriptActio
 	}
 
 	targetPage, exists := p.pages[tabIndex]
+	if !exists {
+		// 录制时由页面打开的弹出窗口：等待前面的步骤打开的窗口出现
+		targetPage, exists = p.waitForPopupTab(ctx, tabIndex, popupTabTimeout)
+	}
 	if !exists {
 		return fmt.Errorf("tab index %d does not exist", tabIndex)
 	}
@@ -0,0 +1,210 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+const (
+	// popupTabTimeout switch_tab 等待弹出窗口出现的超时
+	popupTabTimeout = 10 * time.Second
+	// popupOpenTimeout wait_popup 默认等待弹出窗口出现的超时
+	popupOpenTimeout = 30 * time.Second
+	// popupCloseTimeout wait_popup_close 默认等待弹出窗口关闭的超时
+	popupCloseTimeout = 60 * time.Second
+)
+
+// popupTab wait_popup 切换到的弹出窗口及其打开者
+type popupTab struct {
+	popup  *rod.Page
+	opener *rod.Page
+}
+
+// adoptPopupTabs 把已知标签页打开的弹出窗口加入标签页列表，按发现顺序分配索引（与录制时的编号方式一致）
+func (p *Player) adoptPopupTabs(ctx context.Context) {
+	if p.currentPage == nil {
+		return
+	}
+	browser := p.currentPage.Browser()
+	targets, err := proto.TargetGetTargets{}.Call(browser)
+	if err != nil {
+		logger.Warn(ctx, "Failed to list browser targets: %v", err)
+		return
+	}
+
+	known := make(map[proto.TargetTargetID]bool, len(p.pages))
+	for _, page := range p.pages {
+		known[page.TargetID] = true
+	}
+	for _, info := range targets.TargetInfos {
+		if info.Type != proto.TargetTargetInfoTypePage || known[info.TargetID] || !known[info.OpenerID] {
+			continue
+		}
+		page, err := browser.PageFromTarget(info.TargetID)
+		if err != nil {
+			logger.Warn(ctx, "Failed to attach to popup %s: %v", info.TargetID, err)
+			continue
+		}
+		p.tabCounter++
+		p.pages[p.tabCounter] = page
+		p.applyRequestOverrides(ctx, page)
+		p.watchResponses(ctx, page)
+		p.watchRequests(ctx, page)
+		known[info.TargetID] = true
+		logger.Info(ctx, "Popup window added as tab %d: %s", p.tabCounter, info.URL)
+	}
+}
+
+// waitForPopupTab 等待索引为 tabIndex 的弹出窗口出现
+func (p *Player) waitForPopupTab(ctx context.Context, tabIndex int, timeout time.Duration) (*rod.Page, bool) {
+	if tabIndex <= p.tabCounter {
+		return nil, false
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		p.adoptPopupTabs(ctx)
+		if page, ok := p.pages[tabIndex]; ok {
+			return page, true
+		}
+		if time.Now().After(deadline) {
+			return nil, false
+		}
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
+
+// executeWaitPopup 等待当前页面打开弹出窗口（如 OAuth 登录窗口）并切换过去，后续步骤在弹出窗口中执行
+// URL 非空时只匹配地址符合该模式的窗口（含 * 时按通配符匹配，否则按子串匹配），Duration 为超时毫秒数
+func (p *Player) executeWaitPopup(ctx context.Context, action models.ScriptAction) error {
+	opener := p.currentPage
+	if opener == nil {
+		return fmt.Errorf("wait_popup requires an open page")
+	}
+	timeout := popupOpenTimeout
+	if action.Duration > 0 {
+		timeout = time.Duration(action.Duration) * time.Millisecond
+	}
+
+	logger.Info(ctx, "Waiting for a popup window (url: %q, timeout: %s)", action.URL, timeout)
+	deadline := time.Now().Add(timeout)
+	var popup *rod.Page
+	for {
+		var err error
+		if popup, err = p.findPopup(opener, action.URL); err != nil {
+			return err
+		}
+		if popup != nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("no popup window was opened within %s", timeout)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+
+	p.tabCounter++
+	p.pages[p.tabCounter] = popup
+	p.applyRequestOverrides(ctx, popup)
+	p.watchResponses(ctx, popup)
+	p.watchRequests(ctx, popup)
+	p.popups = append(p.popups, popupTab{popup: popup, opener: opener})
+	p.currentPage = popup
+
+	if err := popup.WaitLoad(); err != nil {
+		logger.Warn(ctx, "Failed to wait for popup to load: %v", err)
+	}
+	logger.Info(ctx, "✓ Switched to popup window (tab index: %d)", p.tabCounter)
+	return nil
+}
+
+// findPopup 查找 opener 打开的、尚未加入标签页列表的弹出窗口
+func (p *Player) findPopup(opener *rod.Page, pattern string) (*rod.Page, error) {
+	browser := opener.Browser()
+	targets, err := proto.TargetGetTargets{}.Call(browser)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list browser targets: %w", err)
+	}
+	known := make(map[proto.TargetTargetID]bool, len(p.pages))
+	for _, page := range p.pages {
+		known[page.TargetID] = true
+	}
+	for _, info := range targets.TargetInfos {
+		if info.Type != proto.TargetTargetInfoTypePage || info.OpenerID != opener.TargetID || known[info.TargetID] {
+			continue
+		}
+		if pattern != "" && !MatchURLPattern(pattern, info.URL) {
+			continue
+		}
+		return browser.PageFromTarget(info.TargetID)
+	}
+	return nil, nil
+}
+
+// executeWaitPopupClose 等待 wait_popup 切换到的弹出窗口关闭（如 OAuth 授权完成后窗口自动关闭），然后回到打开它的页面
+// Duration 为超时毫秒数
+func (p *Player) executeWaitPopupClose(ctx context.Context, action models.ScriptAction) error {
+	if len(p.popups) == 0 {
+		return fmt.Errorf("wait_popup_close requires a preceding wait_popup step")
+	}
+	current := p.popups[len(p.popups)-1]
+	timeout := popupCloseTimeout
+	if action.Duration > 0 {
+		timeout = time.Duration(action.Duration) * time.Millisecond
+	}
+
+	logger.Info(ctx, "Waiting for the popup window to close (timeout: %s)", timeout)
+	deadline := time.Now().Add(timeout)
+	for {
+		open, err := targetExists(current.popup)
+		if err != nil {
+			return err
+		}
+		if !open {
+			break
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("popup window was not closed within %s", timeout)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+
+	p.popups = p.popups[:len(p.popups)-1]
+	p.currentPage = current.opener
+	if _, err := current.opener.Activate(); err != nil {
+		logger.Warn(ctx, "Failed to activate opener page: %v", err)
+	}
+	logger.Info(ctx, "✓ Popup window closed, resumed on the opener page")
+	return nil
+}
+
+// targetExists 页面对应的标签页是否仍然打开
+func targetExists(page *rod.Page) (bool, error) {
+	targets, err := proto.TargetGetTargets{}.Call(page.Browser())
+	if err != nil {
+		return false, fmt.Errorf("failed to list browser targets: %w", err)
+	}
+	for _, info := range targets.TargetInfos {
+		if info.TargetID == page.TargetID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
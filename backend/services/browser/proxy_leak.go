@@ -0,0 +1,34 @@
+package browser
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/launcher/flags"
+)
+
+// applyLeakProtectionFlags 按实例的泄露防护设置添加启动参数，proxyAddr 为空（未使用代理）时不生效
+func applyLeakProtectionFlags(ctx context.Context, l *launcher.Launcher, p *models.ProxyLeakProtection, proxyAddr string) *launcher.Launcher {
+	if p == nil || proxyAddr == "" {
+		return l
+	}
+	if p.BlockWebRTC {
+		// WebRTC 只通过代理建立连接，不再直接发出 UDP 请求
+		l = l.Set(flags.Flag("force-webrtc-ip-handling-policy"), "disable_non_proxied_udp")
+		logger.Info(ctx, "WebRTC leak protection enabled")
+	}
+	if p.ProxyDNS {
+		// 本地解析除代理地址外的所有域名都返回失败，浏览器只能把域名交给代理解析
+		rules := "MAP * ~NOTFOUND"
+		if u, err := url.Parse(proxyAddr); err == nil && u.Hostname() != "" {
+			rules += " , EXCLUDE " + u.Hostname()
+		}
+		l = l.Set(flags.Flag("host-resolver-rules"), rules)
+		l = l.Set(flags.Flag("dns-prefetch-disable"))
+		logger.Info(ctx, "DNS leak protection enabled (%s)", rules)
+	}
+	return l
+}
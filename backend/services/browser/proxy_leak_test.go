@@ -0,0 +1,41 @@
+package browser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/launcher/flags"
+)
+
+func TestApplyLeakProtectionFlags(t *testing.T) {
+	logger.InitLogger(&logger.LoggerConfig{Level: "error"})
+	ctx := context.Background()
+	protection := &models.ProxyLeakProtection{BlockWebRTC: true, ProxyDNS: true}
+
+	// 未使用代理时不生效
+	l := applyLeakProtectionFlags(ctx, launcher.New(), protection, "")
+	if l.Has(flags.Flag("host-resolver-rules")) || l.Has(flags.Flag("force-webrtc-ip-handling-policy")) {
+		t.Fatalf("leak protection applied without a proxy")
+	}
+
+	l = applyLeakProtectionFlags(ctx, launcher.New(), protection, "socks5://proxy.example.com:1080")
+	if got := l.Get(flags.Flag("force-webrtc-ip-handling-policy")); got != "disable_non_proxied_udp" {
+		t.Errorf("unexpected WebRTC policy %q", got)
+	}
+	if got := l.Get(flags.Flag("host-resolver-rules")); got != "MAP * ~NOTFOUND , EXCLUDE proxy.example.com" {
+		t.Errorf("unexpected host resolver rules %q", got)
+	}
+	if !l.Has(flags.Flag("dns-prefetch-disable")) {
+		t.Errorf("DNS prefetch not disabled")
+	}
+
+	if err := protection.Validate("socks4://proxy.example.com:1080"); err == nil {
+		t.Errorf("proxy_dns should be rejected for SOCKS4 proxies")
+	}
+	if err := protection.Validate("http://proxy.example.com:3128"); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+)
+
+// loginScriptFor 页面所在域名的站点知识包中指定的登录脚本，已展开子脚本并替换预设变量
+// 没有指定登录脚本、或正在执行的就是登录脚本时返回 nil
+func (m *Manager) loginScriptFor(pageURL, runningScriptID string) (*models.Script, error) {
+	if m.db == nil {
+		return nil, nil
+	}
+	packs, err := m.db.ListSiteKnowledge()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load site knowledge: %w", err)
+	}
+	id := models.LoginScriptFor(packs, pageURL)
+	if id == "" || id == runningScriptID {
+		return nil, nil
+	}
+
+	login, err := m.db.GetScript(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load login script %s: %w", id, err)
+	}
+	if hasSubScriptCalls(login) {
+		if login, err = expandSubScripts(login, m.db.GetScript); err != nil {
+			return nil, fmt.Errorf("login script %s: %w", id, err)
+		}
+	} else {
+		login = login.Copy()
+	}
+	login.URL = expandVariables(login.URL, login.Variables)
+	for i := range login.Actions {
+		login.Actions[i] = expandActionVariables(login.Actions[i], login.Variables)
+	}
+	return login, nil
+}
+
+// loggedOutAt 页面要求登录时返回检测结果。target 是刚导航到的地址：页面仍是该地址时说明脚本有意打开登录页，
+// 只有被重定向到其他页面或返回 401 才算退出登录；target 为空时（步骤失败后）只看页面本身
+func loggedOutAt(ctx context.Context, page *rod.Page, target string) (*LoggedOutPage, string) {
+	loggedOut, err := DetectLoggedOut(ctx, page)
+	if err != nil || loggedOut == nil {
+		return nil, ""
+	}
+	info, err := page.Info()
+	if err != nil {
+		return nil, ""
+	}
+	if target != "" && loggedOut.Status != 401 && samePage(target, info.URL) {
+		return nil, ""
+	}
+	return loggedOut, info.URL
+}
+
+// samePage 两个 URL 的域名和路径是否相同
+func samePage(a, b string) bool {
+	ua, errA := url.Parse(a)
+	ub, errB := url.Parse(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return strings.EqualFold(ua.Host, ub.Host) && strings.TrimSuffix(ua.Path, "/") == strings.TrimSuffix(ub.Path, "/")
+}
+
+// reauthenticate 页面要求登录且域名指定了登录脚本时，执行登录脚本，再重新打开 target（为空时打开回放最近导航到的页面）。
+// 返回是否重新登录；同一个登录脚本在一次回放中只执行一次，执行失败或登录后仍要求登录时返回 LOGIN_REQUIRED 错误
+func (p *Player) reauthenticate(ctx context.Context, page *rod.Page, target string) (bool, error) {
+	if p.loginResolver == nil || p.loggingIn {
+		return false, nil
+	}
+	loggedOut, pageURL := loggedOutAt(ctx, page, target)
+	if loggedOut == nil {
+		return false, nil
+	}
+	login, err := p.loginResolver(ctx, pageURL)
+	if err != nil {
+		return false, models.WithErrorCode(models.ErrorCodeLoginRequired, fmt.Errorf("logged out at %s (%s): %w", pageURL, loggedOut.Reason, err))
+	}
+	if login == nil {
+		return false, nil
+	}
+	if p.reauthed[login.ID] {
+		return false, models.WithErrorCode(models.ErrorCodeLoginRequired,
+			fmt.Errorf("still logged out at %s (%s) after running login script %s", pageURL, loggedOut.Reason, login.Name))
+	}
+	if p.reauthed == nil {
+		p.reauthed = make(map[string]bool)
+	}
+	p.reauthed[login.ID] = true
+
+	logger.Info(ctx, "Logged out at %s (%s), running login script: %s", pageURL, loggedOut.Reason, login.Name)
+	p.loggingIn = true
+	err = p.playLoginScript(ctx, page, login)
+	p.loggingIn = false
+	if err != nil {
+		return false, models.WithErrorCode(models.ErrorCodeLoginRequired, fmt.Errorf("login script %s failed: %w", login.Name, err))
+	}
+
+	resumeURL := target
+	if resumeURL == "" {
+		resumeURL = p.resumeURL
+	}
+	if resumeURL == "" {
+		resumeURL = pageURL
+	}
+	logger.Info(ctx, "✓ Logged in with %s, resuming at %s", login.Name, resumeURL)
+	if err := page.Navigate(resumeURL); err != nil {
+		return false, fmt.Errorf("navigation failed: %w", err)
+	}
+	if err := page.WaitLoad(); err != nil {
+		logger.Warn(ctx, "Failed to wait for page to load: %v", err)
+	}
+	if loggedOut, _ := loggedOutAt(ctx, page, resumeURL); loggedOut != nil {
+		return false, models.WithErrorCode(models.ErrorCodeLoginRequired,
+			fmt.Errorf("still logged out at %s (%s) after running login script %s", resumeURL, loggedOut.Reason, login.Name))
+	}
+	return true, nil
+}
+
+// playLoginScript 在当前页面执行登录脚本的步骤，任一步骤失败即停止
+func (p *Player) playLoginScript(ctx context.Context, page *rod.Page, login *models.Script) error {
+	if login.URL != "" {
+		if err := p.executeNavigate(ctx, page, models.ScriptAction{Type: "navigate", URL: login.URL}); err != nil {
+			return err
+		}
+	}
+	labels := runLabels(login)
+	for i, action := range login.Actions {
+		if stepSkipReason(action, labels) != "" {
+			continue
+		}
+		if err := p.executeAction(ctx, page, action); err != nil {
+			return fmt.Errorf("step %d (%s): %w", i+1, action.Type, err)
+		}
+	}
+	return nil
+}
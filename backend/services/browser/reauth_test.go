@@ -0,0 +1,66 @@
+package browser
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/storage"
+)
+
+func TestLoginScriptFor(t *testing.T) {
+	db, err := storage.NewBoltDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+	m := &Manager{db: db}
+
+	login := &models.Script{
+		ID:        "login",
+		Name:      "Log in",
+		URL:       "https://shop.example.com/login",
+		Variables: map[string]string{"user": "alice"},
+		Actions: []models.ScriptAction{
+			{Type: "input", Selector: "#email", Value: "${user}@example.com"},
+			{Type: "click", Selector: "#submit"},
+		},
+	}
+	if err := db.SaveScript(login); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveSiteKnowledge(&models.SiteKnowledge{ID: "shop", Domains: []string{"shop.example.com"}, Enabled: true, LoginScriptID: "login"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := m.loginScriptFor("https://shop.example.com/orders", "orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.Actions[0].Value != "alice@example.com" {
+		t.Fatalf("expected the login script with its variables expanded, got %+v", got)
+	}
+	if stored, _ := db.GetScript("login"); stored.Actions[0].Value != "${user}@example.com" {
+		t.Errorf("the stored script should not be changed, got %q", stored.Actions[0].Value)
+	}
+
+	// 正在执行的就是登录脚本、或其他站点时不重新登录
+	if got, _ := m.loginScriptFor("https://shop.example.com/login", "login"); got != nil {
+		t.Errorf("the login script should not log itself in again, got %+v", got)
+	}
+	if got, _ := m.loginScriptFor("https://other.io/", "orders"); got != nil {
+		t.Errorf("expected no login script for other sites, got %+v", got)
+	}
+
+	for target, pageURL := range map[string]string{
+		"https://shop.example.com/login/": "https://shop.example.com/login?next=/",
+		"https://Shop.example.com/orders": "https://shop.example.com/orders",
+	} {
+		if !samePage(target, pageURL) {
+			t.Errorf("samePage(%q, %q) = false", target, pageURL)
+		}
+	}
+	if samePage("https://shop.example.com/orders", "https://shop.example.com/login") {
+		t.Error("a redirect to the login page is not the same page")
+	}
+}
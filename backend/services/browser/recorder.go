@@ -5,6 +5,7 @@ import (
 	_ "embed"
 	"encoding/json"
 	"fmt"
+	"path/filepath"
 	"sort"
 	"strings"
 	"sync"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/browserwing/browserwing/llm"
 	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/artifacts"
 	"github.com/browserwing/browserwing/pkg/logger"
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/proto"
@@ -53,6 +55,13 @@ type Recorder struct {
 	downloadedFiles []models.DownloadedFile // 录制过程中下载的文件
 	downloadPath    string                  // 下载目录路径
 	downloadCancel  context.CancelFunc      // 取消下载监听
+	events          *EventBus               // 录制所在浏览器实例的事件总线
+	tabs            map[string]int          // 录制中的标签页在回放时的索引 (key: page target ID，主页面为 0)
+	tabOpeners      map[string]string       // 弹出窗口的打开者 (key: 弹出窗口 target ID)
+	tabCounter      int                     // 标签页计数器，与回放时的编号方式一致
+	currentTab      string                  // 当前操作的标签页 target ID
+	recordRequests  bool                    // 是否记录步骤触发的 XHR/Fetch 请求
+	requestLog      *requestLog             // 录制期间的请求日志（recordRequests 为 false 时为 nil）
 }
 
 // NewRecorder 创建录制器
@@ -78,6 +87,13 @@ func (r *Recorder) SetDB(db DBInterface) {
 	r.db = db
 }
 
+// SetRecordRequests 设置下次录制是否记录步骤触发的 XHR/Fetch 请求（保存为步骤的 ExpectedRequests）
+func (r *Recorder) SetRecordRequests(enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.recordRequests = enabled
+}
+
 // SetAPIServerPort 设置 API 服务器端口
 func (r *Recorder) SetAPIServerPort(port string) {
 	r.mu.Lock()
@@ -111,8 +127,12 @@ func (r *Recorder) StartRecording(ctx context.Context, page *rod.Page, url strin
 	// 添加主页面到 pages map
 	pageInfo := page.MustInfo()
 	r.pages[string(pageInfo.TargetID)] = page
+	r.tabs = map[string]int{string(pageInfo.TargetID): 0}
+	r.tabOpeners = make(map[string]string)
+	r.tabCounter = 0
+	r.currentTab = string(pageInfo.TargetID)
 
-	// 记录所有现有的页面（但不注入脚本），避免 watchForNewPages 把它们当作新页面
+	// 记录所有现有的页面（但不注入脚本），避免把它们当作录制中打开的标签页
 	browser := page.Browser()
 	existingPages, existingPagesErr := browser.Pages()
 	if existingPagesErr == nil {
@@ -136,6 +156,18 @@ func (r *Recorder) StartRecording(ctx context.Context, page *rod.Page, url strin
 		}
 	}
 
+	// 记录步骤触发的请求（录制可能由 HTTP 请求启动，监听不随请求结束）
+	if r.requestLog != nil {
+		r.requestLog.Stop()
+		r.requestLog = nil
+	}
+	if r.recordRequests {
+		r.requestLog = newRequestLog()
+		if err := r.requestLog.Watch(context.WithoutCancel(ctx), page); err != nil {
+			logger.Warn(ctx, "Failed to record requests: %v", err)
+		}
+	}
+
 	logger.Info(ctx, "Preparing to inject recording script into page (language: %s)...", language)
 	
 	// 首先设置 EvalOnNewDocument，确保所有新文档（包括iframe和新页面）都会自动注入XHR拦截器
@@ -181,8 +213,13 @@ func (r *Recorder) StartRecording(ctx context.Context, page *rod.Page, url strin
 		logger.Info(ctx, "✓ XHR interceptor injected into current page")
 	}
 	
+	// 注册页面内命令 binding，录制面板通过它通知后端停止录制
+	if err := installPageCommands(page, r.events); err != nil {
+		logger.Warn(ctx, "Failed to register in-page command binding: %v", err)
+	}
+
 	// 设置录制模式标志,让脚本知道这是录制模式
-	_, err = page.Eval(`() => { window.__browserwingRecordingMode__ = true; }`)
+	_, err = uiEval(page, `() => { window.__browserwingRecordingMode__ = true; }`)
 	if err != nil {
 		logger.Warn(ctx, "Failed to set recording mode flag: %v", err)
 	}
@@ -200,7 +237,7 @@ func (r *Recorder) StartRecording(ctx context.Context, page *rod.Page, url strin
 				configsJSONStr = strings.ReplaceAll(configsJSONStr, "`", "\\`")
 				configsJSONStr = strings.ReplaceAll(configsJSONStr, "$", "\\$")
 				
-				_, evalErr := page.Eval(fmt.Sprintf(`() => { window.__llmConfigs__ = %s; }`, configsJSONStr))
+				_, evalErr := uiEval(page, fmt.Sprintf(`() => { window.__llmConfigs__ = %s; }`, configsJSONStr))
 				if evalErr != nil {
 					logger.Warn(ctx, "Failed to inject LLM configs: %v", evalErr)
 				} else {
@@ -211,10 +248,10 @@ func (r *Recorder) StartRecording(ctx context.Context, page *rod.Page, url strin
 	}
 
 	// 替换录制脚本中的多语言占位符
-	localizedRecorderScript := ReplaceI18nPlaceholders(recorderScript, r.language, RecorderI18n)
+	localizedRecorderScript := ReplaceI18nPlaceholders(recorderScript, r.language, UIRecorder)
 
 	// 注入录制脚本 - 使用立即执行函数表达式
-	_, err = page.Eval(`() => { ` + localizedRecorderScript + ` return true; }`)
+	_, err = uiEval(page, `() => { ` + localizedRecorderScript + ` return true; }`)
 	if err != nil {
 		r.isRecording = false
 		logger.Error(ctx, "Failed to inject script, error details: %v", err)
@@ -231,13 +268,13 @@ func (r *Recorder) StartRecording(ctx context.Context, page *rod.Page, url strin
 	logger.Info(ctx, "✓ Recording script injected successfully (language: %s)", r.language)
 
 	// 验证注入是否成功
-	checkResult, checkErr := page.Eval(`() => window.__browserwingRecorder__`)
+	checkResult, checkErr := uiEval(page, `() => window.__browserwingRecorder__`)
 	if checkErr == nil && checkResult != nil {
 		logger.Info(ctx, "✓ Recorder status verified: %v", checkResult.Value)
 	}
 
 	// 注入 iframe 消息监听器
-	_, err = page.Eval(`() => { ` + iframeMessageListenerScript + ` return true; }`)
+	_, err = uiEval(page, `() => { ` + iframeMessageListenerScript + ` return true; }`)
 	if err != nil {
 		logger.Warn(ctx, "Failed to inject iframe message listener: %v", err)
 	} else {
@@ -254,7 +291,7 @@ func (r *Recorder) StartRecording(ctx context.Context, page *rod.Page, url strin
 	go r.watchForPageNavigation(ctx, page)
 
 	// 监听新标签页的创建
-	go r.watchForNewPages(ctx, page)
+	go r.watchForNewTabs(ctx, page, r.events)
 
 	logger.Info(ctx, "Starting recording operation, URL: %s", url)
 
@@ -266,7 +303,7 @@ func (r *Recorder) StartRecording(ctx context.Context, page *rod.Page, url strin
 	go r.syncActionsFromBrowser(ctx)
 
 	// 启动下载事件监听
-	go r.watchDownloadEvents(ctx, page)
+	r.watchDownloadEvents(ctx, r.events)
 
 	return nil
 }
@@ -282,35 +319,6 @@ func (r *Recorder) syncActionsFromBrowser(ctx context.Context) {
 				return
 			}
 
-			// 检查是否有停止录制请求（从任何页面）
-			hasStopRequest := false
-			for _, pg := range r.pages {
-				if pg != nil {
-					stopResult, _ := pg.Eval(`() => {
-						if (window.__stopRecordingRequest__) {
-							return true;
-						}
-						return false;
-					}`)
-					if stopResult != nil && stopResult.Value.Bool() {
-						hasStopRequest = true
-						logger.Info(ctx, "[syncActionsFromBrowser] Detected stop request from a tab page")
-						break
-					}
-				}
-			}
-
-			// 如果检测到停止请求,通知主页面
-			if hasStopRequest {
-				// 在主页面设置停止标志,让 manager 的监听循环能检测到
-				if r.page != nil {
-					_, _ = r.page.Eval(`() => {
-						window.__stopRecordingRequest__ = true;
-					}`)
-					logger.Info(ctx, "[syncActionsFromBrowser] Forwarded stop request to main page")
-				}
-			}
-
 			// 检查是否有 AI 提取请求（从所有页面）
 			for _, pg := range r.pages {
 				if pg != nil {
@@ -326,7 +334,7 @@ func (r *Recorder) syncActionsFromBrowser(ctx context.Context) {
 				}
 
 				// 从浏览器获取当前录制的所有操作（优先从 sessionStorage 读取，因为它能跨页面保存）
-				result, err := pg.Eval(`() => {
+				result, err := uiEval(pg, `() => {
 					try {
 						// 先尝试从 sessionStorage 获取（跨页面持久化）
 						var saved = sessionStorage.getItem('__browserwing_actions__');
@@ -393,7 +401,7 @@ func (r *Recorder) checkAndProcessAIRequestOnPage(ctx context.Context, page *rod
 	}
 
 	// 检查是否有待处理的 AI 请求
-	result, err := page.Eval(`() => {
+	result, err := uiEval(page, `() => {
 		if (window.__aiExtractionRequest__) {
 			var req = window.__aiExtractionRequest__;
 			delete window.__aiExtractionRequest__; // 立即清除请求，避免重复处理
@@ -465,10 +473,8 @@ func (r *Recorder) checkAndProcessAIRequestOnPage(ctx context.Context, page *rod
 	if userPrompt != "" {
 		// 获取 USER_REQUIREMENTS 的本地化文本
 		userReqText := "User requirements: "
-		if langTexts, ok := RecorderI18n[r.language]; ok {
-			if text, exists := langTexts["USER_REQUIREMENTS"]; exists {
-				userReqText = text
-			}
+		if text, exists := UITexts(UIRecorder, r.language)["USER_REQUIREMENTS"]; exists {
+			userReqText = text
 		}
 		finalDescription = description + "\n\n" + userReqText + userPrompt
 		logger.Info(ctx, "User added custom prompt: %s", userPrompt)
@@ -488,7 +494,7 @@ func (r *Recorder) checkAndProcessAIRequestOnPage(ctx context.Context, page *rod
 	extractor, err := r.llmManager.GetDefault()
 	if err != nil {
 		logger.Error(ctx, "Failed to get default LLM: %v", err)
-		_, _ = r.page.Eval(fmt.Sprintf(`() => {
+		_, _ = uiEval(r.page, fmt.Sprintf(`() => {
 			window.__aiExtractionResponse__ = {
 				success: false,
 				error: %q
@@ -505,7 +511,7 @@ func (r *Recorder) checkAndProcessAIRequestOnPage(ctx context.Context, page *rod
 	if err != nil {
 		logger.Error(ctx, "AI code generation failed: %v", err)
 		// 将错误返回给页面
-		_, _ = page.Eval(fmt.Sprintf(`() => {
+		_, _ = uiEval(page, fmt.Sprintf(`() => {
 			window.__aiExtractionResponse__ = {
 				success: false,
 				error: %q
@@ -521,7 +527,7 @@ func (r *Recorder) checkAndProcessAIRequestOnPage(ctx context.Context, page *rod
 	// 转义 JavaScript 代码中的特殊字符
 	jsCode = escapeJSString(jsCode)
 
-	_, _ = page.Eval(fmt.Sprintf(`() => {
+	_, _ = uiEval(page, fmt.Sprintf(`() => {
 		window.__aiExtractionResponse__ = {
 			success: true,
 			javascript: %q,
@@ -544,7 +550,7 @@ func (r *Recorder) handleFormFillRequest(ctx context.Context, page *rod.Page, ht
 	extractor, err := r.llmManager.GetDefault()
 	if err != nil {
 		logger.Error(ctx, "Failed to get default LLM: %v", err)
-		_, _ = page.Eval(fmt.Sprintf(`() => {
+		_, _ = uiEval(page, fmt.Sprintf(`() => {
 			window.__aiFormFillResponse__ = {
 				success: false,
 				error: %q
@@ -560,7 +566,7 @@ func (r *Recorder) handleFormFillRequest(ctx context.Context, page *rod.Page, ht
 	})
 	if err != nil {
 		logger.Error(ctx, "AI form fill code generation failed: %v", err)
-		_, _ = page.Eval(fmt.Sprintf(`() => {
+		_, _ = uiEval(page, fmt.Sprintf(`() => {
 			window.__aiFormFillResponse__ = {
 				success: false,
 				error: %q
@@ -575,7 +581,7 @@ func (r *Recorder) handleFormFillRequest(ctx context.Context, page *rod.Page, ht
 	jsCode := fillResult.JavaScript
 	jsCode = escapeJSString(jsCode)
 
-	_, _ = page.Eval(fmt.Sprintf(`() => {
+	_, _ = uiEval(page, fmt.Sprintf(`() => {
 		window.__aiFormFillResponse__ = {
 			success: true,
 			javascript: %q,
@@ -626,7 +632,7 @@ func (r *Recorder) StopRecording(ctx context.Context) ([]models.ScriptAction, er
 		logger.Info(ctx, "Syncing from page: %s", targetID)
 
 		// 先检查录制器是否还存在
-		checkResult, _ := pg.Eval(`() => {
+		checkResult, _ := uiEval(pg, `() => {
 			var savedCount = 0;
 			try {
 				var saved = sessionStorage.getItem('__browserwing_actions__');
@@ -646,7 +652,7 @@ func (r *Recorder) StopRecording(ctx context.Context) ([]models.ScriptAction, er
 			logger.Info(ctx, "Recorder status check on page %s: %+v", targetID, checkResult.Value)
 		}
 
-		result, err := pg.Eval(`() => {
+		result, err := uiEval(pg, `() => {
 			try {
 				// 优先从 sessionStorage 获取完整数据
 				var saved = sessionStorage.getItem('__browserwing_actions__');
@@ -687,7 +693,7 @@ func (r *Recorder) StopRecording(ctx context.Context) ([]models.ScriptAction, er
 		// 使用超时避免卡住
 		cleanupCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
 
-		_, _ = pg.Context(cleanupCtx).Eval(`() => { 
+		_, _ = uiEval(pg.Context(cleanupCtx), `() => { 
 			// 移除录制器 UI 面板
 			if (window.__recorderUI__ && window.__recorderUI__.panel) {
 				try {
@@ -746,6 +752,18 @@ func (r *Recorder) StopRecording(ctx context.Context) ([]models.ScriptAction, er
 
 	logger.Info(ctx, "✓ CSP restrictions restored")
 
+	// 录制器直接记录的步骤（open_tab、navigate）与从页面同步的步骤按时间顺序排列
+	sort.SliceStable(r.actions, func(i, j int) bool {
+		return r.actions[i].Timestamp < r.actions[j].Timestamp
+	})
+
+	// 把录制期间的请求记录到触发它们的步骤上
+	if r.requestLog != nil {
+		r.requestLog.Stop()
+		attributeRequests(r.actions, r.requestLog.all())
+		r.requestLog = nil
+	}
+
 	r.isRecording = false
 	actions := r.actions
 	downloadedFiles := r.downloadedFiles
@@ -796,8 +814,8 @@ func (r *Recorder) injectIframeRecorders(ctx context.Context, page *rod.Page) {
 		}
 
 		// 在 iframe 的页面上下文中注入录制脚本（使用本地化版本）
-		localizedIframeScript := ReplaceI18nPlaceholders(iframeRecorderScript, r.language, RecorderI18n)
-		_, err = frame.Eval(`() => { ` + localizedIframeScript + ` return true; }`)
+		localizedIframeScript := ReplaceI18nPlaceholders(iframeRecorderScript, r.language, UIRecorder)
+		_, err = uiEval(frame, `() => { ` + localizedIframeScript + ` return true; }`)
 		if err != nil {
 			logger.Warn(ctx, "Failed to inject script into iframe #%d: %v", i, err)
 		} else {
@@ -850,8 +868,8 @@ func (r *Recorder) watchForNewIframes(ctx context.Context, page *rod.Page) {
 					}
 
 					// 在 iframe 的页面上下文中注入录制脚本（使用本地化版本）
-					localizedIframeScript := ReplaceI18nPlaceholders(iframeRecorderScript, r.language, RecorderI18n)
-					_, err = frame.Eval(`() => { ` + localizedIframeScript + ` return true; }`)
+					localizedIframeScript := ReplaceI18nPlaceholders(iframeRecorderScript, r.language, UIRecorder)
+					_, err = uiEval(frame, `() => { ` + localizedIframeScript + ` return true; }`)
 					if err != nil {
 						logger.Warn(ctx, "Failed to inject script into new iframe #%d: %v", i, err)
 					} else {
@@ -868,107 +886,6 @@ func (r *Recorder) watchForNewIframes(ctx context.Context, page *rod.Page) {
 	}
 }
 
-// watchForPageNavigation 监听页面导航事件，在新页面自动重新注入录制脚本
-func (r *Recorder) watchForPageNavigation(ctx context.Context, page *rod.Page) {
-	// 记录上一次的 URL
-	var lastURL string
-	result, err := page.Eval(`() => window.location.href`)
-	if err == nil && result != nil && result.Value.Str() != "" {
-		lastURL = result.Value.Str()
-	}
-
-	logger.Info(ctx, "Started watching for page navigation, initial URL: %s", lastURL)
-
-	// 使用定时轮询检测 URL 变化（每 300ms 检查一次）
-	ticker := time.NewTicker(300 * time.Millisecond)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ticker.C:
-			// 检查录制是否还在进行
-			if !r.IsRecording() {
-				return
-			}
-
-			// 获取当前 URL
-			result, err := page.Eval(`() => window.location.href`)
-			if err != nil {
-				continue
-			}
-
-			if result == nil || result.Value.Str() == "" {
-				continue
-			}
-
-			currentURL := result.Value.Str()
-
-			// 检测到 URL 变化（页面导航/跳转）
-			if currentURL != lastURL {
-				logger.Info(ctx, "Page navigation detected: %s -> %s", lastURL, currentURL)
-				lastURL = currentURL
-
-				// 等待新页面加载稳定
-				time.Sleep(800 * time.Millisecond)
-
-				// 检查录制模式标志是否存在
-				checkResult, _ := page.Eval(`() => window.__browserwingRecordingMode__`)
-				needsReinjection := false
-
-				if checkResult == nil || !checkResult.Value.Bool() {
-					logger.Info(ctx, "Recording mode flag missing after navigation, will reinject")
-					needsReinjection = true
-				}
-
-				// 检查录制器是否存在
-				recorderCheck, _ := page.Eval(`() => window.__browserwingRecorder__`)
-				if recorderCheck == nil || !recorderCheck.Value.Bool() {
-					logger.Info(ctx, "Recorder script missing after navigation, will reinject")
-					needsReinjection = true
-				}
-
-				// 如果需要重新注入
-				if needsReinjection {
-					// 禁用 CSP
-					err := proto.PageSetBypassCSP{Enabled: true}.Call(page)
-					if err != nil {
-						logger.Warn(ctx, "Failed to disable CSP after navigation: %v", err)
-					}
-
-					// 重新设置录制模式标志
-					_, err = page.Eval(`() => { window.__browserwingRecordingMode__ = true; }`)
-					if err != nil {
-						logger.Warn(ctx, "Failed to set recording mode flag after navigation: %v", err)
-					}
-
-					// 重新注入录制脚本（使用本地化版本）
-					localizedScript := ReplaceI18nPlaceholders(recorderScript, r.language, RecorderI18n)
-					_, err = page.Eval(`() => { ` + localizedScript + ` return true; }`)
-					if err != nil {
-						logger.Error(ctx, "Failed to reinject recording script after navigation: %v", err)
-					} else {
-						logger.Info(ctx, "✓ Recording script reinjected successfully after navigation")
-					}
-
-					// 重新注入 iframe 消息监听器
-					_, err = page.Eval(`() => { ` + iframeMessageListenerScript + ` return true; }`)
-					if err != nil {
-						logger.Warn(ctx, "Failed to reinject iframe message listener: %v", err)
-					}
-
-					// 为新页面的 iframe 注入录制脚本
-					r.injectIframeRecorders(ctx, page)
-				} else {
-					logger.Info(ctx, "Recording script still active after navigation, no reinjection needed")
-				}
-			}
-
-		case <-ctx.Done():
-			return
-		}
-	}
-}
-
 // IsRecording 检查是否正在录制
 func (r *Recorder) IsRecording() bool {
 	r.mu.Lock()
@@ -1001,76 +918,6 @@ func (r *Recorder) GetStartURL() string {
 	return r.startURL
 }
 
-// watchForNewPages 监听新标签页的创建并自动注入录制脚本
-func (r *Recorder) watchForNewPages(ctx context.Context, mainPage *rod.Page) {
-	// 获取浏览器实例
-	browser := mainPage.Browser()
-
-	// 使用定时轮询检测新标签页（每秒检查一次）
-	ticker := time.NewTicker(1 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ticker.C:
-			// 检查录制是否还在进行
-			if !r.IsRecording() {
-				return
-			}
-
-			r.mu.Lock()
-
-			// 获取当前所有页面
-			pages, err := browser.Pages()
-			if err != nil {
-				r.mu.Unlock()
-				continue
-			}
-
-			// 检查是否有新页面
-			for _, page := range pages {
-				pageInfo, err := page.Info()
-				if err != nil {
-					continue
-				}
-
-				targetID := string(pageInfo.TargetID)
-
-				// 过滤掉特殊页面：devtools、chrome内部页面、about页面等
-				if !isValidRecordingURL(pageInfo.URL) {
-					continue
-				}
-
-				// 如果这是一个新页面（不在我们的 map 中）
-				if _, exists := r.pages[targetID]; !exists {
-					logger.Info(ctx, "Detected new tab/page: %s, URL: %s", targetID, pageInfo.URL)
-
-					// 将新页面添加到 map
-					r.pages[targetID] = page
-
-					// 在新页面注入录制脚本
-					go r.injectRecordingScriptToPage(ctx, page, targetID)
-
-					// 记录打开新标签页的操作
-					action := models.ScriptAction{
-						Type:      "open_tab",
-						Timestamp: time.Now().UnixMilli(),
-						URL:       pageInfo.URL,
-						Text:      fmt.Sprintf("Open new tab: %s", pageInfo.URL),
-					}
-					r.actions = append(r.actions, action)
-					logger.Info(ctx, "Recorded 'open_tab' action for new page: %s", pageInfo.URL)
-				}
-			}
-
-			r.mu.Unlock()
-
-		case <-ctx.Done():
-			return
-		}
-	}
-}
-
 // injectRecordingScriptToPage 向指定页面注入录制脚本和UI面板
 func (r *Recorder) injectRecordingScriptToPage(ctx context.Context, page *rod.Page, targetID string) {
 	// 等待页面加载
@@ -1089,17 +936,25 @@ func (r *Recorder) injectRecordingScriptToPage(ctx context.Context, page *rod.Pa
 		logger.Info(ctx, "✓ CSP restrictions disabled on new page %s", targetID)
 	}
 
+	// 注册页面内命令 binding，标签页中的录制面板同样可以停止录制
+	r.mu.Lock()
+	events := r.events
+	r.mu.Unlock()
+	if err := installPageCommands(page, events); err != nil {
+		logger.Warn(ctx, "Failed to register in-page command binding on new page %s: %v", targetID, err)
+	}
+
 	// 设置录制模式标志
-	_, err = page.Eval(`() => { window.__browserwingRecordingMode__ = true; }`)
+	_, err = uiEval(page, `() => { window.__browserwingRecordingMode__ = true; }`)
 	if err != nil {
 		logger.Warn(ctx, "Failed to set recording mode flag on new page %s: %v", targetID, err)
 	}
 
 	// 替换录制脚本中的多语言占位符
-	localizedRecorderScript := ReplaceI18nPlaceholders(recorderScript, r.language, RecorderI18n)
+	localizedRecorderScript := ReplaceI18nPlaceholders(recorderScript, r.language, UIRecorder)
 
 	// 注入录制脚本
-	_, err = page.Eval(`() => { ` + localizedRecorderScript + ` return true; }`)
+	_, err = uiEval(page, `() => { ` + localizedRecorderScript + ` return true; }`)
 	if err != nil {
 		logger.Error(ctx, "Failed to inject recording script to new page %s: %v", targetID, err)
 		return
@@ -1108,7 +963,7 @@ func (r *Recorder) injectRecordingScriptToPage(ctx context.Context, page *rod.Pa
 	logger.Info(ctx, "✓ Recording script injected to new page %s successfully", targetID)
 
 	// 注入 iframe 消息监听器
-	_, err = page.Eval(`() => { ` + iframeMessageListenerScript + ` return true; }`)
+	_, err = uiEval(page, `() => { ` + iframeMessageListenerScript + ` return true; }`)
 	if err != nil {
 		logger.Warn(ctx, "Failed to inject iframe message listener to new page %s: %v", targetID, err)
 	} else {
@@ -1122,19 +977,14 @@ func (r *Recorder) injectRecordingScriptToPage(ctx context.Context, page *rod.Pa
 	go r.watchForPageNavigation(ctx, page)
 }
 
-// watchDownloadEvents 监听下载事件并记录下载的文件信息
-func (r *Recorder) watchDownloadEvents(ctx context.Context, page *rod.Page) {
-	// 创建可取消的上下文
-	downloadCtx, cancel := context.WithCancel(ctx)
-	r.downloadCancel = cancel
-
-	// 获取浏览器实例
-	browser := page.Browser()
-
-	logger.Info(ctx, "Started watching for download events...")
+// watchDownloadEvents 订阅浏览器的下载事件并记录下载的文件信息，调用者必须已持有锁
+func (r *Recorder) watchDownloadEvents(ctx context.Context, events *EventBus) {
+	if events == nil {
+		logger.Warn(ctx, "Browser event bus not available, downloads will not be recorded")
+		return
+	}
 
-	// 监听下载开始事件
-	go browser.Context(downloadCtx).EachEvent(func(e *proto.BrowserDownloadWillBegin) {
+	r.downloadCancel = events.Subscribe(func(e BrowserEvent) {
 		r.mu.Lock()
 		defer r.mu.Unlock()
 
@@ -1142,53 +992,56 @@ func (r *Recorder) watchDownloadEvents(ctx context.Context, page *rod.Page) {
 			return
 		}
 
-		// 记录下载文件信息
-		downloadFile := models.DownloadedFile{
-			FileName:     e.SuggestedFilename,
-			URL:          e.URL,
-			DownloadTime: time.Now(),
-		}
+		switch e.Type {
+		case EventDownloadWillBegin:
+			download := e.DownloadBegin
 
-		// 如果有下载路径配置，构建完整的文件路径
-		if r.downloadPath != "" {
-			downloadFile.FilePath = r.downloadPath + "/" + e.SuggestedFilename
-		}
+			// 记录下载文件信息
+			downloadFile := models.DownloadedFile{
+				FileName:     download.SuggestedFilename,
+				URL:          download.URL,
+				DownloadTime: time.Now(),
+			}
 
-		logger.Info(ctx, "📥 Download detected: %s from %s", e.SuggestedFilename, e.URL)
+			// 如果有下载路径配置，构建完整的文件路径
+			if r.downloadPath != "" {
+				downloadFile.FilePath = filepath.Join(r.downloadPath, artifacts.SanitizeFileName(download.SuggestedFilename, "download"))
+			}
 
-		r.downloadedFiles = append(r.downloadedFiles, downloadFile)
-	})()
+			logger.Info(ctx, "📥 Download detected: %s from %s", download.SuggestedFilename, download.URL)
 
-	// 监听下载进度事件（可选，用于获取更多信息如文件大小）
-	go browser.Context(downloadCtx).EachEvent(func(e *proto.BrowserDownloadProgress) {
-		r.mu.Lock()
-		defer r.mu.Unlock()
+			r.downloadedFiles = append(r.downloadedFiles, downloadFile)
 
-		if !r.isRecording {
-			return
-		}
+		case EventDownloadProgress:
+			progress := e.DownloadProgress
 
-		// 如果下载完成，更新文件大小信息
-		if e.State == proto.BrowserDownloadProgressStateCompleted {
-			// 查找对应的下载文件记录并更新
-			for i := range r.downloadedFiles {
-				if r.downloadedFiles[i].FileName == "" {
-					// 通过 GUID 匹配（如果需要更精确的匹配可以添加 GUID 字段）
-					r.downloadedFiles[i].Size = int64(e.TotalBytes)
-					logger.Info(ctx, "✓ Download completed: %s (%.2f MB)",
-						r.downloadedFiles[i].FileName,
-						float64(e.TotalBytes)/(1024*1024))
-					break
+			// 如果下载完成，更新文件大小信息
+			if progress.State == proto.BrowserDownloadProgressStateCompleted {
+				// 查找对应的下载文件记录并更新
+				for i := range r.downloadedFiles {
+					if r.downloadedFiles[i].FileName == "" {
+						// 通过 GUID 匹配（如果需要更精确的匹配可以添加 GUID 字段）
+						r.downloadedFiles[i].Size = int64(progress.TotalBytes)
+						logger.Info(ctx, "✓ Download completed: %s (%.2f MB)",
+							r.downloadedFiles[i].FileName,
+							float64(progress.TotalBytes)/(1024*1024))
+						break
+					}
 				}
+			} else if progress.State == proto.BrowserDownloadProgressStateCanceled {
+				logger.Info(ctx, "Download canceled: GUID %s", progress.GUID)
 			}
-		} else if e.State == proto.BrowserDownloadProgressStateCanceled {
-			logger.Info(ctx, "Download canceled: GUID %s", e.GUID)
 		}
-	})()
+	}, EventDownloadWillBegin, EventDownloadProgress)
+
+	logger.Info(ctx, "Started watching for download events...")
+}
 
-	// 等待上下文取消
-	<-downloadCtx.Done()
-	logger.Info(ctx, "Stopped watching download events")
+// SetEventBus 设置录制所在浏览器实例的事件总线（从 Manager 传入）
+func (r *Recorder) SetEventBus(events *EventBus) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = events
 }
 
 // SetDownloadPath 设置下载路径（从 Manager 传入）
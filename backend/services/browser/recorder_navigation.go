@@ -0,0 +1,224 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// navigationCoalesceWindow 间隔小于该时长的连续页面内导航合并为一个步骤（如输入搜索词时页面不断 replaceState）
+const navigationCoalesceWindow = time.Second
+
+// replayedTransitions 回放时需要显式导航的跳转类型（地址栏输入、书签、刷新等）
+// 其余跳转（点击链接、提交表单、脚本跳转）由前一个录制步骤触发，回放时会自然发生
+var replayedTransitions = map[proto.PageTransitionType]bool{
+	proto.PageTransitionTypeTyped:            true,
+	proto.PageTransitionTypeAddressBar:       true,
+	proto.PageTransitionTypeAutoBookmark:     true,
+	proto.PageTransitionTypeGenerated:        true,
+	proto.PageTransitionTypeKeyword:          true,
+	proto.PageTransitionTypeKeywordGenerated: true,
+	proto.PageTransitionTypeReload:           true,
+}
+
+// navigationEvent 主 frame 的一次导航
+type navigationEvent struct {
+	url          string
+	at           int64 // 导航时间（毫秒）
+	sameDocument bool  // 页面内导航：SPA 路由切换、history.pushState/replaceState、锚点跳转
+}
+
+// watchForPageNavigation 监听页面主 frame 的导航事件（Page.frameNavigated、Page.navigatedWithinDocument），
+// 导航后自动恢复录制脚本，并把导航记录为 navigate 步骤
+func (r *Recorder) watchForPageNavigation(ctx context.Context, page *rod.Page) {
+	// 录制可能由 HTTP 请求启动，监听不随请求结束，录制停止时退出
+	ctx = context.WithoutCancel(ctx)
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	events := make(chan navigationEvent, 32)
+	send := func(e navigationEvent) {
+		select {
+		case events <- e:
+		default: // 不阻塞 CDP 事件分发
+		}
+	}
+	wait := page.Context(watchCtx).EachEvent(
+		func(e *proto.PageFrameNavigated) {
+			if e.Frame.ParentID == "" {
+				send(navigationEvent{url: e.Frame.URL, at: time.Now().UnixMilli()})
+			}
+		},
+		func(e *proto.PageNavigatedWithinDocument) {
+			if e.FrameID == page.FrameID {
+				send(navigationEvent{url: e.URL, at: time.Now().UnixMilli(), sameDocument: true})
+			}
+		},
+	)
+	done := make(chan struct{})
+	go func() {
+		wait()
+		close(done)
+	}()
+
+	logger.Info(ctx, "Started watching for page navigation: %s", page.TargetID)
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case e := <-events:
+			if !r.IsRecording() {
+				return
+			}
+			r.handleNavigation(ctx, page, e)
+
+		case <-ticker.C:
+			if !r.IsRecording() {
+				return
+			}
+
+		case <-done:
+			return
+		}
+	}
+}
+
+// handleNavigation 处理一次导航：页面内导航后检查录制器 UI 是否还在页面上，
+// 跨文档导航后等待新文档加载并重新注入录制脚本
+func (r *Recorder) handleNavigation(ctx context.Context, page *rod.Page, e navigationEvent) {
+	if !isValidRecordingURL(e.url) {
+		return
+	}
+
+	if e.sameDocument {
+		logger.Info(ctx, "In-page navigation detected: %s", e.url)
+		r.recordNavigation(ctx, e, false)
+		r.restoreRecorderUI(ctx, page)
+		return
+	}
+
+	logger.Info(ctx, "Page navigation detected: %s", e.url)
+	r.recordNavigation(ctx, e, navigationReplayed(page))
+
+	if err := page.Timeout(10 * time.Second).WaitLoad(); err != nil {
+		logger.Warn(ctx, "Failed to wait for page to load after navigation: %v", err)
+	}
+	r.reinjectRecorder(ctx, page)
+}
+
+// navigationReplayed 当前导航是否需要在回放时显式执行（根据导航历史中当前条目的跳转类型判断）
+func navigationReplayed(page *rod.Page) bool {
+	history, err := proto.PageGetNavigationHistory{}.Call(page)
+	if err != nil || history.CurrentIndex < 0 || history.CurrentIndex >= len(history.Entries) {
+		return false
+	}
+	return replayedTransitions[history.Entries[history.CurrentIndex].TransitionType]
+}
+
+// recordNavigation 把导航记录为 navigate 步骤
+// 由前一个步骤触发的导航（点击链接、提交表单、SPA 路由切换）记录为禁用的步骤，回放时不会重复加载页面，
+// 只用来标明后续步骤所在的页面；需要时可以在编辑器中启用
+func (r *Recorder) recordNavigation(ctx context.Context, e navigationEvent, replay bool) {
+	action := models.ScriptAction{
+		Type:      "navigate",
+		Timestamp: e.at,
+		URL:       e.url,
+		Disabled:  !replay,
+	}
+	if replay {
+		action.Description = fmt.Sprintf("Navigate to %s", e.url)
+	} else {
+		action.Description = fmt.Sprintf("Navigated to %s by the previous step", e.url)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.isRecording {
+		return
+	}
+	before := len(r.actions)
+	r.actions = mergeNavigation(r.actions, action)
+	if len(r.actions) > before {
+		logger.Info(ctx, "Recorded 'navigate' action: %s (replay: %v)", e.url, replay)
+	}
+}
+
+// mergeNavigation 把导航步骤加入操作列表：与上一个步骤是同一地址的导航时忽略；
+// 上一个步骤也是由页面触发的导航且间隔很短时合并为一步
+func mergeNavigation(actions []models.ScriptAction, nav models.ScriptAction) []models.ScriptAction {
+	if len(actions) > 0 {
+		last := &actions[len(actions)-1]
+		if last.Type == "navigate" {
+			if last.URL == nav.URL {
+				return actions
+			}
+			if last.Disabled && nav.Disabled && nav.Timestamp-last.Timestamp < navigationCoalesceWindow.Milliseconds() {
+				last.URL = nav.URL
+				last.Timestamp = nav.Timestamp
+				last.Description = nav.Description
+				return actions
+			}
+		}
+	}
+	return append(actions, nav)
+}
+
+// reinjectRecorder 跨文档导航后，新文档中缺少录制脚本时重新注入
+func (r *Recorder) reinjectRecorder(ctx context.Context, page *rod.Page) {
+	checkResult, _ := uiEval(page, `() => window.__browserwingRecordingMode__ === true && !!window.__browserwingRecorder__`)
+	if checkResult != nil && checkResult.Value.Bool() {
+		logger.Info(ctx, "Recording script still active after navigation, no reinjection needed")
+		return
+	}
+
+	// 禁用 CSP
+	if err := (proto.PageSetBypassCSP{Enabled: true}).Call(page); err != nil {
+		logger.Warn(ctx, "Failed to disable CSP after navigation: %v", err)
+	}
+
+	// 重新设置录制模式标志
+	if _, err := uiEval(page, `() => { window.__browserwingRecordingMode__ = true; }`); err != nil {
+		logger.Warn(ctx, "Failed to set recording mode flag after navigation: %v", err)
+	}
+
+	// 重新注入录制脚本（使用本地化版本）
+	localizedScript := ReplaceI18nPlaceholders(recorderScript, r.language, UIRecorder)
+	if _, err := uiEval(page, `() => { `+localizedScript+` return true; }`); err != nil {
+		logger.Error(ctx, "Failed to reinject recording script after navigation: %v", err)
+		return
+	}
+	logger.Info(ctx, "✓ Recording script reinjected successfully after navigation")
+
+	// 重新注入 iframe 消息监听器
+	if _, err := uiEval(page, `() => { `+iframeMessageListenerScript+` return true; }`); err != nil {
+		logger.Warn(ctx, "Failed to reinject iframe message listener: %v", err)
+	}
+
+	// 为新页面的 iframe 注入录制脚本
+	r.injectIframeRecorders(ctx, page)
+}
+
+// restoreRecorderUI 页面内导航后，页面替换了 body（如 Turbo、部分 SPA 框架）导致录制器 UI 被移除时重新挂载
+func (r *Recorder) restoreRecorderUI(ctx context.Context, page *rod.Page) {
+	result, err := uiEval(page, `() => {
+		if (!window.__browserwingRecorder__) return 'missing';
+		return window.__browserwingEnsureUI__ && window.__browserwingEnsureUI__() ? 'restored' : 'ok';
+	}`)
+	if err != nil {
+		logger.Warn(ctx, "Failed to check recorder after in-page navigation: %v", err)
+		return
+	}
+	switch result.Value.Str() {
+	case "missing":
+		r.reinjectRecorder(ctx, page)
+	case "restored":
+		logger.Info(ctx, "✓ Recorder UI restored after in-page navigation")
+	}
+}
@@ -0,0 +1,43 @@
+package browser
+
+import (
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+func TestMergeNavigation(t *testing.T) {
+	click := models.ScriptAction{Type: "click", Timestamp: 1000, Selector: "a.next"}
+	actions := []models.ScriptAction{click}
+
+	// 点击触发的导航记录为禁用的步骤
+	actions = mergeNavigation(actions, models.ScriptAction{Type: "navigate", Timestamp: 1200, URL: "https://example.com/a", Disabled: true})
+	if len(actions) != 2 || actions[1].URL != "https://example.com/a" {
+		t.Fatalf("navigate not appended: %+v", actions)
+	}
+
+	// 同一地址的重复导航被忽略
+	actions = mergeNavigation(actions, models.ScriptAction{Type: "navigate", Timestamp: 1300, URL: "https://example.com/a", Disabled: true})
+	if len(actions) != 2 {
+		t.Fatalf("duplicate navigate appended: %+v", actions)
+	}
+
+	// 短时间内连续的页面内导航合并为一步
+	actions = mergeNavigation(actions, models.ScriptAction{Type: "navigate", Timestamp: 1500, URL: "https://example.com/a?q=b", Disabled: true})
+	if len(actions) != 2 || actions[1].URL != "https://example.com/a?q=b" || actions[1].Timestamp != 1500 {
+		t.Fatalf("navigations not coalesced: %+v", actions)
+	}
+
+	// 用户在地址栏输入的导航不与之前的导航合并
+	actions = mergeNavigation(actions, models.ScriptAction{Type: "navigate", Timestamp: 1600, URL: "https://example.com/b"})
+	if len(actions) != 3 || actions[2].Disabled {
+		t.Fatalf("typed navigate not appended: %+v", actions)
+	}
+
+	// 间隔较长的页面内导航单独记录
+	actions = mergeNavigation(actions, models.ScriptAction{Type: "navigate", Timestamp: 1700, URL: "https://example.com/b#top", Disabled: true})
+	actions = mergeNavigation(actions, models.ScriptAction{Type: "navigate", Timestamp: 5000, URL: "https://example.com/c", Disabled: true})
+	if len(actions) != 5 {
+		t.Fatalf("expected 5 actions, got %+v", actions)
+	}
+}
@@ -0,0 +1,191 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// tabEvent 录制期间的标签页事件
+type tabEvent struct {
+	info      *proto.TargetTargetInfo
+	destroyed proto.TargetTargetID // 非空时表示该标签页已关闭
+	at        int64                // 事件时间（毫秒）
+}
+
+// watchForNewTabs 通过实例的事件总线监听录制期间打开和关闭的标签页
+//   - 页面打开的弹出窗口（window.open、target="_blank" 链接，如 OAuth 登录窗口）记录为 wait_popup，
+//     回放时等待前一个步骤打开的窗口并切换过去；窗口关闭时记录 wait_popup_close，回放时等待关闭后回到打开者
+//   - 用户自己打开的标签页等到地址有效后记录为 open_tab，关闭后记录 switch_tab 回到主页面
+func (r *Recorder) watchForNewTabs(ctx context.Context, mainPage *rod.Page, events *EventBus) {
+	if events == nil {
+		logger.Warn(ctx, "Browser event bus not available, new tabs will not be recorded")
+		return
+	}
+	// 录制可能由 HTTP 请求启动，监听不随请求结束，录制停止时退出
+	ctx = context.WithoutCancel(ctx)
+
+	tabEvents := make(chan tabEvent, 64)
+	unsubscribe := events.Subscribe(func(e BrowserEvent) {
+		te := tabEvent{at: time.Now().UnixMilli()}
+		if e.Type == EventTargetDestroyed {
+			te.destroyed = e.TargetID
+		} else {
+			te.info = e.Target
+		}
+		select {
+		case tabEvents <- te:
+		default: // 不阻塞事件分发
+		}
+	}, EventTargetCreated, EventTargetChanged, EventTargetDestroyed)
+	defer unsubscribe()
+
+	browser := mainPage.Browser()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case e := <-tabEvents:
+			if !r.IsRecording() {
+				return
+			}
+			if e.destroyed != "" {
+				r.handleTabClosed(ctx, string(e.destroyed), e.at)
+			} else if e.info.Type == proto.TargetTargetInfoTypePage {
+				r.handleTabOpened(ctx, browser, e.info, e.at)
+			}
+
+		case <-ticker.C:
+			if !r.IsRecording() {
+				return
+			}
+
+		case <-events.Done():
+			return
+		}
+	}
+}
+
+// handleTabOpened 为新标签页分配索引、注入录制脚本并记录切换或打开标签页的步骤
+func (r *Recorder) handleTabOpened(ctx context.Context, browser *rod.Browser, info *proto.TargetTargetInfo, at int64) {
+	targetID := string(info.TargetID)
+
+	r.mu.Lock()
+	if !r.isRecording {
+		r.mu.Unlock()
+		return
+	}
+	if _, known := r.tabs[targetID]; known {
+		r.mu.Unlock()
+		return
+	}
+	_, fromRecordedTab := r.tabs[string(info.OpenerID)]
+	popup := info.OpenerID != "" && fromRecordedTab
+	// 用户打开的标签页在导航到有效地址（离开新标签页、about:blank）后才开始录制
+	if !popup && !isValidRecordingURL(info.URL) {
+		r.mu.Unlock()
+		return
+	}
+	// 录制开始前已打开的标签页不录制
+	if _, existing := r.pages[targetID]; existing {
+		r.mu.Unlock()
+		return
+	}
+	r.mu.Unlock()
+
+	page, err := browser.PageFromTarget(info.TargetID)
+	if err != nil {
+		logger.Warn(ctx, "Failed to attach to new tab %s: %v", targetID, err)
+		return
+	}
+
+	r.mu.Lock()
+	r.tabCounter++
+	index := r.tabCounter
+	r.tabs[targetID] = index
+	r.pages[targetID] = page
+	r.currentTab = targetID
+
+	var action models.ScriptAction
+	if popup {
+		r.tabOpeners[targetID] = string(info.OpenerID)
+		action = models.ScriptAction{
+			Type:        "wait_popup",
+			Timestamp:   at,
+			Description: "Wait for the popup window opened by the previous step",
+		}
+	} else {
+		action = models.ScriptAction{
+			Type:      "open_tab",
+			Timestamp: at,
+			URL:       info.URL,
+			Text:      fmt.Sprintf("Open new tab: %s", info.URL),
+		}
+	}
+	r.actions = append(r.actions, action)
+	requests := r.requestLog
+	r.mu.Unlock()
+
+	logger.Info(ctx, "Recorded '%s' action for new tab %s (index %d, popup: %v): %s", action.Type, targetID, index, popup, info.URL)
+
+	if requests != nil {
+		if err := requests.Watch(context.WithoutCancel(ctx), page); err != nil {
+			logger.Warn(ctx, "Failed to record requests of new tab %s: %v", targetID, err)
+		}
+	}
+
+	go r.injectRecordingScriptToPage(ctx, page, targetID)
+}
+
+// handleTabClosed 当前操作的标签页关闭后，记录回到打开它的标签页（未知时回到主页面）
+func (r *Recorder) handleTabClosed(ctx context.Context, targetID string, at int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.isRecording {
+		return
+	}
+	if _, known := r.tabs[targetID]; !known {
+		return
+	}
+	delete(r.tabs, targetID)
+	delete(r.pages, targetID)
+
+	if r.currentTab != targetID {
+		return
+	}
+	// 弹出窗口关闭后回放器自动回到打开者
+	if opener, popup := r.tabOpeners[targetID]; popup {
+		if _, open := r.tabs[opener]; open {
+			r.currentTab = opener
+			r.actions = append(r.actions, models.ScriptAction{
+				Type:        "wait_popup_close",
+				Timestamp:   at,
+				Description: "Wait for the popup window to close",
+			})
+			logger.Info(ctx, "Recorded 'wait_popup_close' action after popup %s closed", targetID)
+			return
+		}
+	}
+
+	back := string(r.page.TargetID)
+	index, open := r.tabs[back]
+	if !open {
+		return
+	}
+	r.currentTab = back
+	r.actions = append(r.actions, models.ScriptAction{
+		Type:        "switch_tab",
+		Timestamp:   at,
+		Value:       strconv.Itoa(index),
+		Description: fmt.Sprintf("Return to tab %d after the tab closed", index),
+	})
+	logger.Info(ctx, "Recorded 'switch_tab' action back to tab %d after tab %s closed", index, targetID)
+}
@@ -0,0 +1,45 @@
+package browser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+)
+
+func TestHandleTabClosed(t *testing.T) {
+	logger.InitLogger(&logger.LoggerConfig{Level: "error"})
+
+	r := NewRecorder()
+	r.isRecording = true
+	r.page = &rod.Page{TargetID: "main"}
+	r.tabs = map[string]int{"main": 0, "oauth": 1, "other": 2}
+	r.tabOpeners = map[string]string{"oauth": "main"}
+	r.currentTab = "oauth"
+
+	// 关闭的不是当前标签页时不记录切换
+	r.handleTabClosed(context.Background(), "other", 1000)
+	if len(r.actions) != 0 {
+		t.Fatalf("unexpected actions: %+v", r.actions)
+	}
+
+	r.handleTabClosed(context.Background(), "oauth", 2000)
+	if len(r.actions) != 1 || r.actions[0].Type != "wait_popup_close" || r.actions[0].Timestamp != 2000 {
+		t.Fatalf("expected wait_popup_close, got %+v", r.actions)
+	}
+	if r.currentTab != "main" {
+		t.Fatalf("current tab = %q", r.currentTab)
+	}
+
+	// 用户打开的标签页关闭后切换回主页面
+	r.tabs["docs"] = 3
+	r.currentTab = "docs"
+	r.handleTabClosed(context.Background(), "docs", 3000)
+	if len(r.actions) != 2 {
+		t.Fatalf("expected switch_tab, got %+v", r.actions)
+	}
+	if got := r.actions[1]; got.Type != "switch_tab" || got.Value != "0" {
+		t.Fatalf("unexpected action: %+v", got)
+	}
+}
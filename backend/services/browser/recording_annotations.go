@@ -0,0 +1,236 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+)
+
+// 步骤执行结果
+const (
+	recordingStepSuccess = "success"
+	recordingStepFailed  = "failed"
+	recordingStepSkipped = "skipped"
+)
+
+// recordingStep 录制期间执行的步骤，用于生成字幕与元数据轨道
+type recordingStep struct {
+	Index  int             `json:"index"`
+	Total  int             `json:"total"`
+	Type   string          `json:"type"`
+	Label  string          `json:"label"`
+	Start  float64         `json:"start"` // 相对录制开始的秒数
+	End    float64         `json:"end"`
+	Status string          `json:"status,omitempty"`
+	Error  string          `json:"error,omitempty"`
+	Clicks []recordedClick `json:"clicks,omitempty"`
+}
+
+// recordedClick 点击位置（视口 CSS 像素）
+type recordedClick struct {
+	X  float64 `json:"x"`
+	Y  float64 `json:"y"`
+	At float64 `json:"at"` // 相对录制开始的秒数
+}
+
+// stepOverlayScript 在页面底部显示当前步骤说明，screencast 会把它录进画面
+const stepOverlayScript = `(text, failed) => {
+	let el = document.getElementById('browserwing-step-caption');
+	if (!el) {
+		el = document.createElement('div');
+		el.id = 'browserwing-step-caption';
+		el.className = '__browserwing-protected__';
+		el.style.cssText = 'position: fixed !important; left: 50% !important; bottom: 24px !important; transform: translateX(-50%) !important; z-index: 2147483647 !important; max-width: 80vw !important; padding: 8px 16px !important; border-radius: 8px !important; color: white !important; font: 600 14px/1.4 -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif !important; white-space: nowrap !important; overflow: hidden !important; text-overflow: ellipsis !important; pointer-events: none !important; box-shadow: 0 4px 12px rgba(0,0,0,0.3) !important;';
+		(document.body || document.documentElement).appendChild(el);
+	}
+	el.style.setProperty('background', failed ? 'rgba(220, 38, 38, 0.9)' : 'rgba(15, 23, 42, 0.85)', 'important');
+	el.textContent = text;
+}`
+
+// clickMarkerScript 在元素中心显示点击标记并返回点击坐标
+const clickMarkerScript = `function () {
+	const rect = this.getBoundingClientRect();
+	const x = rect.left + rect.width / 2;
+	const y = rect.top + rect.height / 2;
+	const marker = document.createElement('div');
+	marker.className = '__browserwing-protected__';
+	marker.style.cssText = 'position: fixed !important; left: ' + (x - 14) + 'px !important; top: ' + (y - 14) + 'px !important; width: 28px !important; height: 28px !important; border-radius: 50% !important; border: 3px solid #ef4444 !important; background: rgba(239, 68, 68, 0.3) !important; z-index: 2147483647 !important; pointer-events: none !important; transition: transform 0.6s ease-out, opacity 0.6s ease-out !important;';
+	(document.body || document.documentElement).appendChild(marker);
+	requestAnimationFrame(() => {
+		marker.style.setProperty('transform', 'scale(1.8)', 'important');
+		marker.style.setProperty('opacity', '0', 'important');
+	});
+	setTimeout(() => marker.remove(), 1200);
+	return { x, y };
+}`
+
+// isVideoRecording 是否正在录制视频
+func (p *Player) isVideoRecording() bool {
+	return p.recordingPage != nil
+}
+
+// recordingOffset 当前时间相对录制开始的秒数
+func (p *Player) recordingOffset() float64 {
+	return time.Since(p.recordingStart).Seconds()
+}
+
+// stepLabel 生成步骤说明文本，如 "Step 3/10 · Click · #submit"
+func stepLabel(index, total int, action models.ScriptAction, lang string) string {
+	label := fmt.Sprintf("%s %d/%d · %s", getI18nText("ai.control.step", lang), index, total, getActionDisplayText(action.Type, lang))
+	detail := action.Selector
+	if action.XPath != "" && detail == "" {
+		detail = action.XPath
+	}
+	if action.Type == "navigate" || action.Type == "open_tab" {
+		detail = action.URL
+	}
+	if detail != "" {
+		if runes := []rune(detail); len(runes) > 60 {
+			detail = string(runes[:60]) + "…"
+		}
+		label += " · " + detail
+	}
+	return label
+}
+
+// beginRecordingStep 录制期间开始一个步骤：记录开始时间并在页面上显示步骤说明
+func (p *Player) beginRecordingStep(ctx context.Context, page *rod.Page, index, total int, action models.ScriptAction) {
+	if !p.isVideoRecording() {
+		return
+	}
+	label := stepLabel(index, total, action, p.currentLang)
+
+	p.recordingMu.Lock()
+	p.recordingSteps = append(p.recordingSteps, recordingStep{
+		Index: index,
+		Total: total,
+		Type:  action.Type,
+		Label: label,
+		Start: p.recordingOffset(),
+	})
+	p.recordingMu.Unlock()
+
+	if _, err := page.Eval(stepOverlayScript, label, false); err != nil {
+		logger.Warn(ctx, "Failed to show step overlay: %v", err)
+	}
+}
+
+// endRecordingStep 录制期间结束当前步骤，失败时将步骤说明标记为红色
+func (p *Player) endRecordingStep(ctx context.Context, page *rod.Page, status string, stepErr error) {
+	if !p.isVideoRecording() {
+		return
+	}
+
+	p.recordingMu.Lock()
+	var label string
+	if n := len(p.recordingSteps); n > 0 {
+		step := &p.recordingSteps[n-1]
+		step.End = p.recordingOffset()
+		step.Status = status
+		if stepErr != nil {
+			step.Error = stepErr.Error()
+		}
+		label = step.Label
+	}
+	p.recordingMu.Unlock()
+
+	if status == recordingStepFailed && label != "" {
+		if _, err := page.Eval(stepOverlayScript, label+" ✗", true); err != nil {
+			logger.Warn(ctx, "Failed to update step overlay: %v", err)
+		}
+	}
+}
+
+// markRecordingClick 录制期间在点击位置显示标记，并记录到当前步骤
+func (p *Player) markRecordingClick(ctx context.Context, element *rod.Element) {
+	if !p.isVideoRecording() || element == nil {
+		return
+	}
+	res, err := element.Eval(clickMarkerScript)
+	if err != nil {
+		logger.Warn(ctx, "Failed to show click marker: %v", err)
+		return
+	}
+	click := recordedClick{
+		X:  res.Value.Get("x").Num(),
+		Y:  res.Value.Get("y").Num(),
+		At: p.recordingOffset(),
+	}
+
+	p.recordingMu.Lock()
+	if n := len(p.recordingSteps); n > 0 {
+		p.recordingSteps[n-1].Clicks = append(p.recordingSteps[n-1].Clicks, click)
+	}
+	p.recordingMu.Unlock()
+}
+
+// writeRecordingTrack 在录像旁生成 WebVTT 字幕（.vtt）和步骤元数据（.steps.json）
+func writeRecordingTrack(outputPath string, steps []recordingStep, duration float64) ([]string, error) {
+	if len(steps) == 0 {
+		return nil, nil
+	}
+	base := strings.TrimSuffix(outputPath, filepath.Ext(outputPath))
+
+	vttPath := base + ".vtt"
+	if err := os.WriteFile(vttPath, []byte(buildWebVTT(steps, duration)), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write subtitle track: %w", err)
+	}
+
+	data, err := json.MarshalIndent(map[string]interface{}{
+		"video":    filepath.Base(outputPath),
+		"duration": duration,
+		"steps":    steps,
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	jsonPath := base + ".steps.json"
+	if err := os.WriteFile(jsonPath, data, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write step metadata: %w", err)
+	}
+	return []string{vttPath, jsonPath}, nil
+}
+
+// buildWebVTT 生成步骤字幕，每个步骤持续到下一步骤开始（最后一步持续到录制结束）
+func buildWebVTT(steps []recordingStep, duration float64) string {
+	var sb strings.Builder
+	sb.WriteString("WEBVTT\n")
+	for i, step := range steps {
+		end := duration
+		if i+1 < len(steps) {
+			end = steps[i+1].Start
+		}
+		if end <= step.Start {
+			end = step.Start + 0.5
+		}
+		text := step.Label
+		switch step.Status {
+		case recordingStepFailed:
+			text += " ✗"
+			if step.Error != "" {
+				text += "\n" + strings.ReplaceAll(step.Error, "\n", " ")
+			}
+		case recordingStepSkipped:
+			text += " (skipped)"
+		}
+		fmt.Fprintf(&sb, "\n%d\n%s --> %s\n%s\n", i+1, formatVTTTime(step.Start), formatVTTTime(end), text)
+	}
+	return sb.String()
+}
+
+// formatVTTTime 格式化为 WebVTT 时间戳 HH:MM:SS.mmm
+func formatVTTTime(seconds float64) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+	ms := int64(seconds*1000 + 0.5)
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
@@ -0,0 +1,32 @@
+package browser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+func TestBuildWebVTT(t *testing.T) {
+	steps := []recordingStep{
+		{Index: 1, Label: "Step 1/2 · Navigate", Start: 0.2, End: 1.5, Status: recordingStepSuccess},
+		{Index: 2, Label: "Step 2/2 · Click · #submit", Start: 1.5, End: 2, Status: recordingStepFailed, Error: "element not found"},
+	}
+	got := buildWebVTT(steps, 3661.25)
+	want := "WEBVTT\n" +
+		"\n1\n00:00:00.200 --> 00:00:01.500\nStep 1/2 · Navigate\n" +
+		"\n2\n00:00:01.500 --> 01:01:01.250\nStep 2/2 · Click · #submit ✗\nelement not found\n"
+	if got != want {
+		t.Errorf("buildWebVTT =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestStepLabel(t *testing.T) {
+	label := stepLabel(3, 10, models.ScriptAction{Type: "click", Selector: "#" + strings.Repeat("a", 80)}, "en")
+	if !strings.HasPrefix(label, "Step 3/10 · ") {
+		t.Errorf("unexpected label prefix: %s", label)
+	}
+	if !strings.HasSuffix(label, "…") {
+		t.Errorf("long selector should be truncated: %s", label)
+	}
+}
@@ -0,0 +1,592 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod/lib/cdp"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// 远程浏览器连接的保活和重连参数
+const (
+	remoteKeepAliveInterval = 15 * time.Second       // 保活探测间隔，同时避免中间代理因空闲断开连接
+	remotePingTimeout       = 10 * time.Second       // 探测和建立连接的超时时间，超时视为连接已断开
+	remoteReconnectMinDelay = 500 * time.Millisecond // 第一次重连前的等待时间
+	remoteReconnectMaxDelay = 30 * time.Second       // 重连等待时间的上限
+	remoteReconnectWait     = 60 * time.Second       // 调用等待重连的最长时间，超过后返回 SESSION_LOST
+	remoteRestoreTimeout    = 30 * time.Second       // 重连后恢复页面会话的超时时间
+	remoteCallAttempts      = 3                      // 连接断开时单个调用的最多尝试次数
+)
+
+// remoteAccumulatedMethods 每次调用都会新增状态的方法，重连后全部重放；其他方法只重放最后一次
+var remoteAccumulatedMethods = map[string]bool{
+	"Page.addScriptToEvaluateOnNewDocument": true,
+	"Runtime.addBinding":                    true,
+	"Browser.grantPermissions":              true,
+}
+
+// remoteReplayedMethods 除各个域的 enable 外，重连后需要重放的会话设置
+var remoteReplayedMethods = map[string]bool{
+	"Page.addScriptToEvaluateOnNewDocument": true,
+	"Page.setLifecycleEventsEnabled":        true,
+	"Page.setBypassCSP":                     true,
+	"Runtime.addBinding":                    true,
+	"Network.setUserAgentOverride":          true,
+	"Network.setExtraHTTPHeaders":           true,
+	"Emulation.setDeviceMetricsOverride":    true,
+	"Emulation.setUserAgentOverride":        true,
+	"Emulation.setTouchEmulationEnabled":    true,
+	"Browser.setDownloadBehavior":           true,
+	"Browser.grantPermissions":              true,
+	"Target.setDiscoverTargets":             true,
+}
+
+// reconnectBackoff 指数退避，等待时间从 min 开始每次翻倍，不超过 max
+type reconnectBackoff struct {
+	min, max time.Duration
+	attempt  int
+}
+
+// Next 返回下一次重连前的等待时间
+func (b *reconnectBackoff) Next() time.Duration {
+	d := b.min << b.attempt
+	if d <= 0 || d >= b.max {
+		return b.max
+	}
+	b.attempt++
+	return d
+}
+
+// remoteCall 重连后需要重放的调用
+type remoteCall struct {
+	method string
+	params interface{}
+}
+
+// recordRemoteCall 记录需要重放的调用，disable 时移除对应的 enable
+func recordRemoteCall(calls []remoteCall, method string, params interface{}) []remoteCall {
+	replaced := method
+	if domain, ok := strings.CutSuffix(method, ".disable"); ok {
+		replaced = domain + ".enable"
+	} else if remoteAccumulatedMethods[method] {
+		return append(calls, remoteCall{method, params})
+	}
+
+	kept := calls[:0:0]
+	for _, call := range calls {
+		if call.method != replaced {
+			kept = append(kept, call)
+		}
+	}
+	if replaced != method {
+		return kept
+	}
+	return append(kept, remoteCall{method, params})
+}
+
+// isRemoteReplayed 是否为重连后需要重放的调用（disable 需要记录以撤销对应的 enable）
+func isRemoteReplayed(method string) bool {
+	return remoteReplayedMethods[method] || strings.HasSuffix(method, ".enable") || strings.HasSuffix(method, ".disable")
+}
+
+// remoteRetryableMethods 连接断开时可以在重连后重试的只读调用
+var remoteRetryableMethods = map[string]bool{
+	"Browser.getVersion":             true,
+	"Browser.getWindowForTarget":     true,
+	"Target.getTargets":              true,
+	"Target.getTargetInfo":           true,
+	"Target.attachToTarget":          true, // 旧连接上的会话随连接一起失效
+	"Page.getFrameTree":              true,
+	"Page.getResourceTree":           true,
+	"Page.getNavigationHistory":      true,
+	"Page.getLayoutMetrics":          true,
+	"Page.captureScreenshot":         true,
+	"DOM.getDocument":                true,
+	"DOM.describeNode":               true,
+	"DOM.querySelector":              true,
+	"DOM.querySelectorAll":           true,
+	"DOM.getBoxModel":                true,
+	"DOM.getContentQuads":            true,
+	"DOM.getOuterHTML":               true,
+	"DOM.getAttributes":              true,
+	"DOM.getFrameOwner":              true,
+	"DOM.getNodeForLocation":         true,
+	"DOM.resolveNode":                true,
+	"DOM.requestNode":                true,
+	"DOMSnapshot.captureSnapshot":    true,
+	"CSS.getComputedStyleForNode":    true,
+	"Accessibility.getFullAXTree":    true,
+	"Accessibility.getPartialAXTree": true,
+	"Runtime.getProperties":          true,
+	"Network.getCookies":             true,
+	"Network.getAllCookies":          true,
+	"Storage.getCookies":             true,
+	"Network.getResponseBody":        true,
+}
+
+// isRemoteRetryable 连接断开时调用是否可以在重连后重试
+// 无法确定浏览器是否已执行了断开前发出的调用，只重试只读调用和重连后本来就会重放的会话设置；
+// 导航、执行脚本、输入事件、创建标签页等重复执行会造成副作用，返回 SESSION_LOST 由调用方决定是否重试
+func isRemoteRetryable(method string) bool {
+	return remoteRetryableMethods[method] || isRemoteReplayed(method)
+}
+
+// isConnectionError 是否为连接断开导致的错误（浏览器返回的错误和调用方取消不算）
+func isConnectionError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var cdpErr *cdp.Error
+	return !errors.As(err, &cdpErr)
+}
+
+// remoteSession 通过远程连接附加的页面会话
+type remoteSession struct {
+	targetID proto.TargetTargetID
+	current  string       // 当前连接上的会话 ID，重连后会变化
+	setup    []remoteCall // 重新附加后需要重放的调用
+}
+
+// remoteConn 一次 WebSocket 连接
+type remoteConn struct {
+	ws     *cdp.WebSocket
+	client *cdp.Client
+}
+
+// dialRemote 建立到远程浏览器的 WebSocket 连接
+func dialRemote(ctx context.Context, wsURL string) (*remoteConn, error) {
+	ctx, cancel := context.WithTimeout(ctx, remotePingTimeout)
+	defer cancel()
+
+	ws := &cdp.WebSocket{}
+	if err := ws.Connect(ctx, wsURL, nil); err != nil {
+		return nil, err
+	}
+	return &remoteConn{ws: ws, client: cdp.New().Start(ws)}, nil
+}
+
+// remoteClient 远程浏览器的自动重连 CDP 客户端
+// 定期探测连接，断开后按指数退避重连，重新附加已打开的页面并重放会话设置（启用的域、注入脚本、UA 等）
+// 页面会话 ID 在重连后会变化，客户端在调用和事件中自动转换，rod 的页面对象不需要重新获取
+type remoteClient struct {
+	ctx        context.Context
+	cancel     context.CancelFunc
+	name       string
+	controlURL string
+	events     chan *cdp.Event
+	pumps      sync.WaitGroup
+
+	mu       sync.Mutex
+	wsURL    string
+	conn     *remoteConn   // 重连期间为 nil
+	ready    chan struct{} // 连接可用时关闭
+	closing  bool          // 已发送 Browser.close，连接断开后不再重连
+	closed   bool
+	sessions map[string]*remoteSession // rod 看到的会话 ID -> 会话
+	actual   map[string]string         // 当前连接上的会话 ID -> rod 看到的会话 ID
+	setup    []remoteCall              // 浏览器级别需要重放的调用
+}
+
+// newRemoteClient 连接远程浏览器，连接建立后自动保活和重连
+func newRemoteClient(ctx context.Context, name, controlURL, wsURL string) (*remoteClient, error) {
+	conn, err := dialRemote(ctx, wsURL)
+	if err != nil {
+		return nil, err
+	}
+
+	clientCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	c := &remoteClient{
+		ctx:        clientCtx,
+		cancel:     cancel,
+		name:       name,
+		controlURL: controlURL,
+		events:     make(chan *cdp.Event),
+		wsURL:      wsURL,
+		conn:       conn,
+		ready:      make(chan struct{}),
+		sessions:   map[string]*remoteSession{},
+		actual:     map[string]string{},
+	}
+	close(c.ready)
+	c.startPump(conn)
+	go c.keepAlive()
+	return c, nil
+}
+
+// Event 实现 rod.CDPClient，重连前后的事件都从同一个通道发出
+func (c *remoteClient) Event() <-chan *cdp.Event {
+	return c.events
+}
+
+// Call 实现 rod.CDPClient，连接断开时等待重连后重试
+func (c *remoteClient) Call(ctx context.Context, sessionID, method string, params interface{}) ([]byte, error) {
+	if method == "Browser.close" {
+		c.mu.Lock()
+		c.closing = true
+		c.mu.Unlock()
+	}
+
+	for attempt := 1; ; attempt++ {
+		conn, err := c.waitConn(ctx)
+		if err != nil {
+			return nil, err
+		}
+		res, err := conn.client.Call(ctx, c.actualSession(sessionID), method, params)
+		if err == nil {
+			c.track(sessionID, method, params, res)
+			return res, nil
+		}
+		if !isConnectionError(ctx, err) {
+			return nil, err
+		}
+		c.disconnected(conn, err)
+		if !isRemoteRetryable(method) {
+			return nil, models.WithErrorCode(models.ErrorCodeSessionLost,
+				fmt.Errorf("connection to remote browser %s was lost during %s, which may have already run and is not retried: %w", c.controlURL, method, err))
+		}
+		if attempt >= remoteCallAttempts {
+			return nil, models.WithErrorCode(models.ErrorCodeSessionLost,
+				fmt.Errorf("connection to remote browser %s was lost %d times during %s: %w", c.controlURL, attempt, method, err))
+		}
+	}
+}
+
+// Close 停止保活和重连，关闭连接
+func (c *remoteClient) Close() {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return
+	}
+	c.closed = true
+	conn := c.conn
+	c.conn = nil
+	c.mu.Unlock()
+
+	c.cancel()
+	if conn != nil {
+		_ = conn.ws.Close()
+	}
+	go func() {
+		c.pumps.Wait()
+		close(c.events)
+	}()
+}
+
+// waitConn 返回当前连接，重连期间等待重连完成
+func (c *remoteClient) waitConn(ctx context.Context) (*remoteConn, error) {
+	timer := time.NewTimer(remoteReconnectWait)
+	defer timer.Stop()
+
+	for {
+		c.mu.Lock()
+		conn, ready := c.conn, c.ready
+		c.mu.Unlock()
+		if conn != nil {
+			return conn, nil
+		}
+
+		select {
+		case <-ready:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-c.ctx.Done():
+			return nil, models.WithErrorCode(models.ErrorCodeSessionLost,
+				fmt.Errorf("connection to remote browser %s is closed", c.controlURL))
+		case <-timer.C:
+			return nil, models.WithErrorCode(models.ErrorCodeSessionLost,
+				fmt.Errorf("remote browser %s is unreachable (still reconnecting after %s)", c.controlURL, remoteReconnectWait))
+		}
+	}
+}
+
+// actualSession 把 rod 看到的会话 ID 转换为当前连接上的会话 ID
+func (c *remoteClient) actualSession(sessionID string) string {
+	if sessionID == "" {
+		return ""
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if s, ok := c.sessions[sessionID]; ok {
+		return s.current
+	}
+	return sessionID
+}
+
+// track 记录附加的页面会话和需要重放的设置
+func (c *remoteClient) track(sessionID, method string, params interface{}, res []byte) {
+	switch {
+	case method == "Target.attachToTarget":
+		var req proto.TargetAttachToTarget
+		var attached proto.TargetAttachToTargetResult
+		data, _ := json.Marshal(params)
+		if json.Unmarshal(data, &req) != nil || json.Unmarshal(res, &attached) != nil || attached.SessionID == "" {
+			return
+		}
+		id := string(attached.SessionID)
+		c.mu.Lock()
+		c.sessions[id] = &remoteSession{targetID: req.TargetID, current: id}
+		c.actual[id] = id
+		c.mu.Unlock()
+	case method == "Target.detachFromTarget":
+		var req proto.TargetDetachFromTarget
+		data, _ := json.Marshal(params)
+		if json.Unmarshal(data, &req) == nil {
+			c.mu.Lock()
+			c.dropSession(string(req.SessionID))
+			c.mu.Unlock()
+		}
+	case isRemoteReplayed(method):
+		c.mu.Lock()
+		if sessionID == "" {
+			c.setup = recordRemoteCall(c.setup, method, params)
+		} else if s, ok := c.sessions[sessionID]; ok {
+			s.setup = recordRemoteCall(s.setup, method, params)
+		}
+		c.mu.Unlock()
+	}
+}
+
+// dropSession 移除页面会话，调用者必须已持有锁
+func (c *remoteClient) dropSession(sessionID string) {
+	if s, ok := c.sessions[sessionID]; ok {
+		delete(c.actual, s.current)
+		delete(c.sessions, sessionID)
+	}
+}
+
+// translateEvent 把事件中当前连接的会话 ID 转换为 rod 看到的会话 ID
+func (c *remoteClient) translateEvent(e *cdp.Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if id, ok := c.actual[e.SessionID]; ok {
+		e.SessionID = id
+	}
+	if e.Method != "Target.attachedToTarget" && e.Method != "Target.detachedFromTarget" {
+		return
+	}
+
+	var params map[string]json.RawMessage
+	var sessionID string
+	if json.Unmarshal(e.Params, &params) != nil || json.Unmarshal(params["sessionId"], &sessionID) != nil {
+		return
+	}
+	id, ok := c.actual[sessionID]
+	if !ok {
+		return
+	}
+	if e.Method == "Target.detachedFromTarget" {
+		c.dropSession(id)
+	}
+	if id != sessionID {
+		params["sessionId"], _ = json.Marshal(id)
+		e.Params, _ = json.Marshal(params)
+	}
+}
+
+// startPump 转发连接上的事件，连接断开时触发重连；客户端已关闭时返回 false
+func (c *remoteClient) startPump(conn *remoteConn) bool {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return false
+	}
+	c.pumps.Add(1)
+	c.mu.Unlock()
+
+	go func() {
+		defer c.pumps.Done()
+		for e := range conn.client.Event() {
+			c.translateEvent(e)
+			select {
+			case c.events <- e:
+			case <-c.ctx.Done():
+				// 继续读完已关闭连接上的事件，避免底层连接阻塞
+				go func() {
+					for range conn.client.Event() {
+					}
+				}()
+				return
+			}
+		}
+		c.disconnected(conn, errors.New("websocket: closed by remote"))
+	}()
+	return true
+}
+
+// disconnected 连接断开，只有第一次发现断开的调用方会启动重连
+func (c *remoteClient) disconnected(conn *remoteConn, cause error) {
+	c.mu.Lock()
+	if c.conn != conn {
+		c.mu.Unlock()
+		return
+	}
+	c.conn = nil
+	c.ready = make(chan struct{})
+	closing := c.closing
+	c.mu.Unlock()
+
+	_ = conn.ws.Close()
+	if closing || c.ctx.Err() != nil {
+		c.Close()
+		return
+	}
+	logger.Warn(c.ctx, "Lost connection to remote browser %s: %v, reconnecting...", c.name, cause)
+	go c.reconnect()
+}
+
+// reconnect 按指数退避重连，直到连接恢复或实例停止
+func (c *remoteClient) reconnect() {
+	backoff := reconnectBackoff{min: remoteReconnectMinDelay, max: remoteReconnectMaxDelay}
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-time.After(backoff.Next()):
+		case <-c.ctx.Done():
+			return
+		}
+
+		conn, err := c.redial()
+		if err == nil {
+			if !c.startPump(conn) {
+				_ = conn.ws.Close()
+				return
+			}
+			if err = c.restore(conn); err == nil {
+				c.mu.Lock()
+				c.conn = conn
+				close(c.ready)
+				c.mu.Unlock()
+				logger.Info(c.ctx, "✓ Reconnected to remote browser %s after %d attempt(s)", c.name, attempt)
+				return
+			}
+			_ = conn.ws.Close()
+		}
+		logger.Warn(c.ctx, "Reconnect to remote browser %s failed (attempt %d): %v", c.name, attempt, err)
+	}
+}
+
+// redial 重新建立连接，原地址连不上时重新查询 control URL（远程浏览器重启后地址会变化）
+func (c *remoteClient) redial() (*remoteConn, error) {
+	c.mu.Lock()
+	wsURL := c.wsURL
+	c.mu.Unlock()
+
+	conn, err := dialRemote(c.ctx, wsURL)
+	if err == nil || c.controlURL == wsURL {
+		return conn, err
+	}
+	resolved, resolveErr := resolveWebSocketURL(c.controlURL)
+	if resolveErr != nil || resolved == wsURL {
+		return nil, err
+	}
+	if conn, err = dialRemote(c.ctx, resolved); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.wsURL = resolved
+	c.mu.Unlock()
+	return conn, nil
+}
+
+// restore 在新连接上重放浏览器级别的设置，重新附加页面并重放会话设置
+// 已关闭的页面不再恢复，之后对它的调用返回 SESSION_LOST
+func (c *remoteClient) restore(conn *remoteConn) error {
+	ctx, cancel := context.WithTimeout(c.ctx, remoteRestoreTimeout)
+	defer cancel()
+
+	c.mu.Lock()
+	setup := append([]remoteCall(nil), c.setup...)
+	sessions := make(map[string]*remoteSession, len(c.sessions))
+	for id, s := range c.sessions {
+		sessions[id] = s
+	}
+	c.mu.Unlock()
+
+	replay := func(sessionID string, calls []remoteCall) error {
+		for _, call := range calls {
+			if _, err := conn.client.Call(ctx, sessionID, call.method, call.params); err != nil {
+				if isConnectionError(ctx, err) || ctx.Err() != nil {
+					return err
+				}
+				logger.Warn(c.ctx, "Failed to restore %s on remote browser %s: %v", call.method, c.name, err)
+			}
+		}
+		return nil
+	}
+
+	if err := replay("", setup); err != nil {
+		return err
+	}
+
+	restored := 0
+	for id, s := range sessions {
+		res, err := conn.client.Call(ctx, "", "Target.attachToTarget",
+			proto.TargetAttachToTarget{TargetID: s.targetID, Flatten: true})
+		if err != nil {
+			if isConnectionError(ctx, err) || ctx.Err() != nil {
+				return err
+			}
+			c.mu.Lock()
+			c.dropSession(id)
+			c.mu.Unlock()
+			continue
+		}
+		var attached proto.TargetAttachToTargetResult
+		if err := json.Unmarshal(res, &attached); err != nil {
+			return err
+		}
+
+		c.mu.Lock()
+		delete(c.actual, s.current)
+		s.current = string(attached.SessionID)
+		c.actual[s.current] = id
+		calls := append([]remoteCall(nil), s.setup...)
+		c.mu.Unlock()
+
+		if err := replay(s.current, calls); err != nil {
+			return err
+		}
+		restored++
+	}
+	logger.Info(c.ctx, "Restored %d/%d page session(s) on remote browser %s", restored, len(sessions), c.name)
+	return nil
+}
+
+// keepAlive 定期探测连接，没有响应时主动断开并重连（半开连接不会自己报错）
+func (c *remoteClient) keepAlive() {
+	ticker := time.NewTicker(remoteKeepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		c.mu.Lock()
+		conn := c.conn
+		c.mu.Unlock()
+		if conn == nil {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(c.ctx, remotePingTimeout)
+		_, err := conn.client.Call(ctx, "", "Browser.getVersion", nil)
+		cancel()
+		var cdpErr *cdp.Error
+		if err == nil || c.ctx.Err() != nil || errors.As(err, &cdpErr) {
+			continue
+		}
+		c.disconnected(conn, fmt.Errorf("keep-alive ping failed: %w", err))
+	}
+}
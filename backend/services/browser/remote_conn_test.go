@@ -0,0 +1,121 @@
+package browser
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod/lib/cdp"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+func TestReconnectBackoff(t *testing.T) {
+	b := reconnectBackoff{min: 500 * time.Millisecond, max: 3 * time.Second}
+	want := []time.Duration{
+		500 * time.Millisecond, time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second,
+	}
+	for i, w := range want {
+		if got := b.Next(); got != w {
+			t.Errorf("attempt %d: got %s, want %s", i+1, got, w)
+		}
+	}
+}
+
+func TestRecordRemoteCall(t *testing.T) {
+	var calls []remoteCall
+	calls = recordRemoteCall(calls, "Page.enable", nil)
+	calls = recordRemoteCall(calls, "Network.enable", nil)
+	calls = recordRemoteCall(calls, "Network.setUserAgentOverride", "a")
+	calls = recordRemoteCall(calls, "Network.setUserAgentOverride", "b")
+	calls = recordRemoteCall(calls, "Page.addScriptToEvaluateOnNewDocument", "1")
+	calls = recordRemoteCall(calls, "Page.addScriptToEvaluateOnNewDocument", "2")
+	calls = recordRemoteCall(calls, "Network.disable", nil)
+
+	var got []string
+	for _, c := range calls {
+		if s, ok := c.params.(string); ok {
+			got = append(got, c.method+":"+s)
+		} else {
+			got = append(got, c.method)
+		}
+	}
+	want := []string{
+		"Page.enable",
+		"Network.setUserAgentOverride:b",
+		"Page.addScriptToEvaluateOnNewDocument:1",
+		"Page.addScriptToEvaluateOnNewDocument:2",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestIsRemoteRetryable(t *testing.T) {
+	for method, want := range map[string]bool{
+		"Runtime.callFunctionOn":    false,
+		"Runtime.evaluate":          false,
+		"Page.navigate":             false,
+		"Page.reload":               false,
+		"Input.dispatchMouseEvent":  false,
+		"Target.createTarget":       false,
+		"Browser.close":             false,
+		"DOM.setFileInputFiles":     false,
+		"DOM.getDocument":           true,
+		"DOM.describeNode":          true,
+		"Page.getFrameTree":         true,
+		"Runtime.getProperties":     true,
+		"Target.setDiscoverTargets": true,
+		"Network.enable":            true,
+	} {
+		if got := isRemoteRetryable(method); got != want {
+			t.Errorf("%s: got %v, want %v", method, got, want)
+		}
+	}
+}
+
+func TestRemoteClientSessionTranslation(t *testing.T) {
+	c := &remoteClient{sessions: map[string]*remoteSession{}, actual: map[string]string{}}
+
+	res, _ := json.Marshal(proto.TargetAttachToTargetResult{SessionID: "old"})
+	c.track("", "Target.attachToTarget", proto.TargetAttachToTarget{TargetID: "T1", Flatten: true}, res)
+	c.track("old", "Page.enable", nil, nil)
+	c.track("", "Browser.setDownloadBehavior", nil, nil)
+
+	s := c.sessions["old"]
+	if s == nil || s.targetID != "T1" || len(s.setup) != 1 || len(c.setup) != 1 {
+		t.Fatalf("session not tracked: %+v, browser setup %v", s, c.setup)
+	}
+
+	// 重连后会话重新附加，rod 仍使用原来的会话 ID
+	delete(c.actual, s.current)
+	s.current = "new"
+	c.actual["new"] = "old"
+	if got := c.actualSession("old"); got != "new" {
+		t.Errorf("calls should go to the new session, got %q", got)
+	}
+	if got := c.actualSession("other"); got != "other" {
+		t.Errorf("unknown sessions should pass through, got %q", got)
+	}
+
+	e := &cdp.Event{SessionID: "new", Method: "Page.loadEventFired"}
+	c.translateEvent(e)
+	if e.SessionID != "old" {
+		t.Errorf("event session should be translated back, got %q", e.SessionID)
+	}
+
+	params, _ := json.Marshal(map[string]string{"sessionId": "new", "targetId": "T1"})
+	e = &cdp.Event{Method: "Target.detachedFromTarget", Params: params}
+	c.translateEvent(e)
+	var detached proto.TargetDetachedFromTarget
+	if err := json.Unmarshal(e.Params, &detached); err != nil || detached.SessionID != "old" {
+		t.Errorf("detached event should carry the original session, got %s", e.Params)
+	}
+	if len(c.sessions) != 0 || len(c.actual) != 0 {
+		t.Errorf("detached session should be dropped: %v %v", c.sessions, c.actual)
+	}
+}
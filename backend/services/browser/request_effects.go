@@ -0,0 +1,392 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+const (
+	maxLoggedRequests      = 2000             // 请求日志最多保留的请求数，超出时丢弃最早的
+	requestAttributeWindow = 3 * time.Second  // 录制时步骤之后多长时间内发出的请求算作该步骤触发的
+	maxExpectedRequests    = 5                // 每个步骤最多记录的请求数
+	expectedRequestTimeout = 10 * time.Second // 回放时步骤执行后等待请求的最长时间
+)
+
+// requestTriggerSteps 会触发请求的录制步骤类型，只为这些步骤记录请求
+var requestTriggerSteps = map[string]bool{
+	"click":            true,
+	"input":            true,
+	"select":           true,
+	"keyboard":         true,
+	"hover_then_click": true,
+	"upload_file":      true,
+}
+
+// idSegmentPattern 看起来像记录 ID 的路径段（纯数字、UUID 或长十六进制串），生成 URL 模式时替换为 *
+var idSegmentPattern = regexp.MustCompile(`^(\d+|[0-9a-fA-F-]{16,})$`)
+
+// loggedRequest 页面发出的一个 XHR/Fetch 请求
+type loggedRequest struct {
+	method string
+	url    string
+	status int       // 响应状态码，尚未收到响应或请求失败时为 0
+	failed bool      // 请求失败（网络错误、被取消）
+	at     time.Time // 发出时间（浏览器时钟，与录制步骤的时间戳一致）
+}
+
+// requestKey 请求在浏览器中的唯一标识
+type requestKey struct {
+	target proto.TargetTargetID
+	id     proto.NetworkRequestID
+}
+
+// requestLog 按发出顺序记录页面的 XHR/Fetch 请求，录制时用于记录步骤触发的请求，回放时用于验证
+type requestLog struct {
+	mu       sync.Mutex
+	requests []*loggedRequest
+	dropped  int // 因超出数量上限被丢弃的请求数，mark 为 dropped + 下标
+	byKey    map[requestKey]*loggedRequest
+	notify   chan struct{}
+	cancels  []context.CancelFunc
+	watching map[proto.TargetTargetID]bool
+}
+
+func newRequestLog() *requestLog {
+	return &requestLog{
+		byKey:    make(map[requestKey]*loggedRequest),
+		notify:   make(chan struct{}),
+		watching: make(map[proto.TargetTargetID]bool),
+	}
+}
+
+// Watch 开始记录页面的请求，同一页面只会记录一次
+func (l *requestLog) Watch(ctx context.Context, page *rod.Page) error {
+	if page == nil {
+		return fmt.Errorf("page is nil")
+	}
+
+	l.mu.Lock()
+	if l.watching[page.TargetID] {
+		l.mu.Unlock()
+		return nil
+	}
+	l.watching[page.TargetID] = true
+	l.mu.Unlock()
+
+	if err := (proto.NetworkEnable{}).Call(page); err != nil {
+		return fmt.Errorf("failed to enable network monitoring: %w", err)
+	}
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	l.mu.Lock()
+	l.cancels = append(l.cancels, cancel)
+	l.mu.Unlock()
+
+	target := page.TargetID
+	go page.Context(watchCtx).EachEvent(
+		func(e *proto.NetworkRequestWillBeSent) {
+			if e.Request == nil || (e.Type != proto.NetworkResourceTypeXHR && e.Type != proto.NetworkResourceTypeFetch) {
+				return
+			}
+			at := time.Now()
+			if e.WallTime > 0 {
+				at = e.WallTime.Time()
+			}
+			l.add(requestKey{target, e.RequestID}, &loggedRequest{method: e.Request.Method, url: e.Request.URL, at: at})
+		},
+		func(e *proto.NetworkResponseReceived) {
+			if e.Response != nil {
+				l.update(requestKey{target, e.RequestID}, e.Response.Status, false)
+			}
+		},
+		func(e *proto.NetworkLoadingFailed) {
+			l.update(requestKey{target, e.RequestID}, 0, true)
+		},
+	)()
+
+	return nil
+}
+
+// add 记录新发出的请求，超出数量上限时丢弃最早的
+func (l *requestLog) add(key requestKey, req *loggedRequest) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.requests) >= maxLoggedRequests {
+		oldest := l.requests[0]
+		for k, r := range l.byKey {
+			if r == oldest {
+				delete(l.byKey, k)
+			}
+		}
+		l.requests[0] = nil
+		l.requests = l.requests[1:]
+		l.dropped++
+	}
+	l.requests = append(l.requests, req)
+	l.byKey[key] = req
+	l.signal()
+}
+
+// update 记录请求的响应状态码或失败
+func (l *requestLog) update(key requestKey, status int, failed bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	req, ok := l.byKey[key]
+	if !ok {
+		return
+	}
+	delete(l.byKey, key)
+	req.status = status
+	req.failed = failed
+	l.signal()
+}
+
+// signal 通知等待中的 wait（调用方持有锁）
+func (l *requestLog) signal() {
+	close(l.notify)
+	l.notify = make(chan struct{})
+}
+
+// mark 返回当前位置，之后发出的请求通过 since 取出
+func (l *requestLog) mark() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.dropped + len(l.requests)
+}
+
+// since 返回 mark 之后发出的请求的副本
+func (l *requestLog) since(mark int) []loggedRequest {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	start := mark - l.dropped
+	if start < 0 {
+		start = 0
+	}
+	if start > len(l.requests) {
+		return nil
+	}
+	requests := make([]loggedRequest, 0, len(l.requests)-start)
+	for _, req := range l.requests[start:] {
+		requests = append(requests, *req)
+	}
+	return requests
+}
+
+// all 返回全部请求的副本
+func (l *requestLog) all() []loggedRequest {
+	l.mu.Lock()
+	mark := l.dropped
+	l.mu.Unlock()
+	return l.since(mark)
+}
+
+// changed 返回请求日志下次变化时关闭的通道
+func (l *requestLog) changed() <-chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.notify
+}
+
+// Stop 停止记录
+func (l *requestLog) Stop() {
+	l.mu.Lock()
+	cancels := l.cancels
+	l.cancels = nil
+	l.watching = make(map[proto.TargetTargetID]bool)
+	l.mu.Unlock()
+
+	for _, cancel := range cancels {
+		cancel()
+	}
+}
+
+// requestURLPattern 把请求 URL 转换为回放时匹配的模式：去掉查询参数，ID 路径段替换为 *
+func requestURLPattern(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+	segments := strings.Split(u.Path, "/")
+	for i, segment := range segments {
+		if idSegmentPattern.MatchString(segment) && strings.ContainsAny(segment, "0123456789") {
+			segments[i] = "*"
+		}
+	}
+	pattern := u.Scheme + "://" + u.Host + strings.Join(segments, "/")
+	if !strings.HasSuffix(pattern, "*") {
+		pattern += "*"
+	}
+	return pattern
+}
+
+// attributeRequests 把录制期间的请求记录到触发它们的步骤上：请求属于在它之前最近的步骤（需在 requestAttributeWindow 内）
+// 录制该步骤之前已经出现过的请求视为页面的后台请求（轮询、埋点），不记录；失败或状态码不小于 400 的请求不记录
+func attributeRequests(actions []models.ScriptAction, requests []loggedRequest) {
+	sort.SliceStable(requests, func(i, j int) bool { return requests[i].at.Before(requests[j].at) })
+
+	seen := make(map[string]bool) // 已出现过的 方法 + URL 模式
+	next := 0
+	for i := range actions {
+		stepAt := time.UnixMilli(actions[i].Timestamp)
+		for ; next < len(requests) && requests[next].at.Before(stepAt); next++ {
+			seen[requests[next].method+" "+requestURLPattern(requests[next].url)] = true
+		}
+		if !requestTriggerSteps[actions[i].Type] {
+			continue
+		}
+
+		end := stepAt.Add(requestAttributeWindow)
+		if i+1 < len(actions) {
+			if nextAt := time.UnixMilli(actions[i+1].Timestamp); nextAt.Before(end) {
+				end = nextAt
+			}
+		}
+		var expected []models.ExpectedRequest
+		for j := next; j < len(requests) && requests[j].at.Before(end); j++ {
+			req := requests[j]
+			key := req.method + " " + requestURLPattern(req.url)
+			if seen[key] || req.failed || req.status == 0 || req.status >= 400 || len(expected) >= maxExpectedRequests {
+				continue
+			}
+			seen[key] = true
+			expected = append(expected, models.ExpectedRequest{Method: req.method, URL: requestURLPattern(req.url), Status: req.status})
+		}
+		actions[i].ExpectedRequests = expected
+	}
+}
+
+// startRequestLog 脚本中有步骤需要验证请求时开始记录请求（需在导航前调用）
+func (p *Player) startRequestLog(ctx context.Context, page *rod.Page, actions []models.ScriptAction) {
+	p.stopRequestLog()
+	for _, action := range actions {
+		if len(action.ExpectedRequests) > 0 {
+			p.requestLog = newRequestLog()
+			p.watchRequests(ctx, page)
+			return
+		}
+	}
+}
+
+// watchRequests 在页面上记录请求（新标签页同样需要调用）
+func (p *Player) watchRequests(ctx context.Context, page *rod.Page) {
+	if p.requestLog == nil || page == nil {
+		return
+	}
+	if err := p.requestLog.Watch(ctx, page); err != nil {
+		logger.Warn(ctx, "Failed to watch requests: %v", err)
+	}
+}
+
+// stopRequestLog 停止记录请求
+func (p *Player) stopRequestLog() {
+	if p.requestLog != nil {
+		p.requestLog.Stop()
+		p.requestLog = nil
+	}
+}
+
+// executeAndVerify 执行操作，有 ExpectedRequests 时验证步骤发出了录制时的请求
+func (p *Player) executeAndVerify(ctx context.Context, page *rod.Page, action models.ScriptAction) error {
+	if len(action.ExpectedRequests) == 0 || p.requestLog == nil {
+		return p.executeAction(ctx, page, action)
+	}
+	mark := p.requestLog.mark()
+	if err := p.executeAction(ctx, page, action); err != nil {
+		return err
+	}
+	return waitExpectedRequests(ctx, p.requestLog, mark, action.ExpectedRequests, expectedRequestTimeout)
+}
+
+// waitExpectedRequests 等待 mark 之后发出了每个期望的请求，超时后返回 REQUEST_MISSING 错误
+func waitExpectedRequests(ctx context.Context, log *requestLog, mark int, expected []models.ExpectedRequest, timeout time.Duration) error {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+		changed := log.changed()
+		missing := missingRequests(expected, log.since(mark))
+		if len(missing) == 0 {
+			logger.Info(ctx, "✓ Step sent all %d expected requests", len(expected))
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-deadline.C:
+			return models.WithErrorCode(models.ErrorCodeRequestMissing,
+				fmt.Errorf("the step did not send the expected requests: %s", strings.Join(missing, "; ")))
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// missingRequests 返回未满足的期望请求的说明
+func missingRequests(expected []models.ExpectedRequest, requests []loggedRequest) []string {
+	var missing []string
+	for _, exp := range expected {
+		var statuses []string
+		found := false
+		for _, req := range requests {
+			if (exp.Method != "" && !strings.EqualFold(exp.Method, req.method)) || !MatchURLPattern(exp.URL, req.url) {
+				continue
+			}
+			if req.status == 0 && !req.failed {
+				continue // 尚未收到响应
+			}
+			if statusMatches(exp.Status, req.status) {
+				found = true
+				break
+			}
+			if req.failed {
+				statuses = append(statuses, "failed")
+			} else {
+				statuses = append(statuses, fmt.Sprint(req.status))
+			}
+		}
+		if found {
+			continue
+		}
+		desc := strings.TrimSpace(exp.Method + " " + exp.URL)
+		if len(statuses) > 0 {
+			desc += fmt.Sprintf(" (got %s, want %s)", strings.Join(statuses, ", "), statusClass(exp.Status))
+		} else {
+			desc += " (not sent)"
+		}
+		missing = append(missing, desc)
+	}
+	return missing
+}
+
+// statusMatches 判断状态码是否满足期望：与录制时的状态码同一类别，未记录时接受任何小于 400 的状态码
+func statusMatches(expected, status int) bool {
+	if status <= 0 {
+		return false
+	}
+	if expected <= 0 {
+		return status < 400
+	}
+	return status/100 == expected/100
+}
+
+// statusClass 返回期望状态码的说明，如 2xx
+func statusClass(expected int) string {
+	if expected <= 0 {
+		return "< 400"
+	}
+	return fmt.Sprintf("%dxx", expected/100)
+}
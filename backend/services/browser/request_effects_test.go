@@ -0,0 +1,95 @@
+package browser
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+)
+
+func TestRequestURLPattern(t *testing.T) {
+	for rawURL, want := range map[string]string{
+		"https://shop.example.com/api/cart?ts=1700000000":                         "https://shop.example.com/api/cart*",
+		"https://shop.example.com/api/orders/12345/items":                         "https://shop.example.com/api/orders/*/items*",
+		"https://shop.example.com/api/users/3f2b8c1e-9d4a-4e7b-a1c2-5d6e7f8a9b0c": "https://shop.example.com/api/users/*",
+		"https://shop.example.com/api/feature-flags":                              "https://shop.example.com/api/feature-flags*",
+	} {
+		if got := requestURLPattern(rawURL); got != want {
+			t.Errorf("requestURLPattern(%q) = %q, want %q", rawURL, got, want)
+		}
+		if !MatchURLPattern(requestURLPattern(rawURL), rawURL) {
+			t.Errorf("the pattern of %q does not match the URL itself", rawURL)
+		}
+	}
+}
+
+func TestAttributeRequests(t *testing.T) {
+	base := time.UnixMilli(1_700_000_000_000)
+	at := func(ms int) time.Time { return base.Add(time.Duration(ms) * time.Millisecond) }
+	actions := []models.ScriptAction{
+		{Type: "input", Timestamp: at(0).UnixMilli()},
+		{Type: "click", Timestamp: at(1000).UnixMilli()},
+		{Type: "extract_text", Timestamp: at(2000).UnixMilli()},
+		{Type: "click", Timestamp: at(10000).UnixMilli()},
+	}
+	requests := []loggedRequest{
+		{method: "GET", url: "https://shop.example.com/api/poll?n=1", status: 200, at: at(-500)},
+		{method: "GET", url: "https://shop.example.com/api/suggest?q=a", status: 200, at: at(300)},
+		{method: "POST", url: "https://shop.example.com/api/orders", status: 201, at: at(1200)},
+		{method: "GET", url: "https://shop.example.com/api/poll?n=2", status: 200, at: at(1300)},
+		{method: "POST", url: "https://shop.example.com/api/track", status: 0, failed: true, at: at(1400)},
+		{method: "GET", url: "https://shop.example.com/api/orders/42", status: 200, at: at(2500)},
+		{method: "POST", url: "https://shop.example.com/api/orders/42/cancel", status: 500, at: at(10100)},
+	}
+
+	attributeRequests(actions, requests)
+
+	if got := actions[0].ExpectedRequests; len(got) != 1 || got[0].URL != "https://shop.example.com/api/suggest*" {
+		t.Errorf("input: expected the suggest request, got %+v", got)
+	}
+	// 之前出现过的轮询请求和失败的请求不记录
+	if got := actions[1].ExpectedRequests; len(got) != 1 || got[0].Method != "POST" || got[0].Status != 201 {
+		t.Errorf("click: expected only the order request, got %+v", got)
+	}
+	// 抓取步骤不会触发请求，之后的请求不记录到任何步骤
+	if got := actions[2].ExpectedRequests; got != nil {
+		t.Errorf("extract_text: expected no requests, got %+v", got)
+	}
+	// 状态码不小于 400 的请求不记录
+	if got := actions[3].ExpectedRequests; got != nil {
+		t.Errorf("click: expected no requests for a failed response, got %+v", got)
+	}
+}
+
+func TestWaitExpectedRequests(t *testing.T) {
+	logger.InitLogger(&logger.LoggerConfig{Level: "error"})
+	log := newRequestLog()
+	log.add(requestKey{id: "1"}, &loggedRequest{method: "POST", url: "https://shop.example.com/api/orders", at: time.Now()})
+	log.update(requestKey{id: "1"}, 201, false)
+	mark := log.mark()
+
+	expected := []models.ExpectedRequest{{Method: "POST", URL: "https://shop.example.com/api/orders*", Status: 201}}
+	err := waitExpectedRequests(context.Background(), log, mark, expected, 50*time.Millisecond)
+	if models.ErrorCodeOf(err) != models.ErrorCodeRequestMissing {
+		t.Fatalf("requests sent before the step should not count, got %v", err)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		log.add(requestKey{id: "2"}, &loggedRequest{method: "POST", url: "https://shop.example.com/api/orders", at: time.Now()})
+		log.update(requestKey{id: "2"}, 200, false)
+	}()
+	if err := waitExpectedRequests(context.Background(), log, mark, expected, time.Second); err != nil {
+		t.Errorf("expected a 2xx response to match, got %v", err)
+	}
+
+	mark = log.mark()
+	log.add(requestKey{id: "3"}, &loggedRequest{method: "POST", url: "https://shop.example.com/api/orders", at: time.Now()})
+	log.update(requestKey{id: "3"}, 500, false)
+	err = waitExpectedRequests(context.Background(), log, mark, expected, 50*time.Millisecond)
+	if err == nil || err.Error() != "the step did not send the expected requests: POST https://shop.example.com/api/orders* (got 500, want 2xx)" {
+		t.Errorf("unexpected error for a 500 response: %v", err)
+	}
+}
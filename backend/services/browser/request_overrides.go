@@ -0,0 +1,66 @@
+package browser
+
+import (
+	"context"
+	"strings"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// setRequestOverrides 根据脚本配置准备本次回放的请求头和 User-Agent 覆盖
+// 值中的 ${变量名} 会替换为脚本变量
+func (p *Player) setRequestOverrides(script *models.Script, variables map[string]string) {
+	p.extraHeaders = nil
+	p.userAgent = expandVariables(script.UserAgent, variables)
+
+	if len(script.Headers) == 0 {
+		return
+	}
+	p.extraHeaders = make(map[string]string, len(script.Headers))
+	for name, value := range script.Headers {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		p.extraHeaders[name] = expandVariables(value, variables)
+	}
+}
+
+// applyRequestOverrides 将请求头和 User-Agent 覆盖应用到页面（Network.setExtraHTTPHeaders）
+func (p *Player) applyRequestOverrides(ctx context.Context, page *rod.Page) {
+	if page == nil {
+		return
+	}
+
+	if len(p.extraHeaders) > 0 {
+		dict := make([]string, 0, len(p.extraHeaders)*2)
+		for name, value := range p.extraHeaders {
+			dict = append(dict, name, value)
+		}
+		if _, err := page.SetExtraHeaders(dict); err != nil {
+			logger.Warn(ctx, "Failed to set extra HTTP headers: %v", err)
+		} else {
+			logger.Info(ctx, "Applied %d extra HTTP headers", len(p.extraHeaders))
+		}
+	}
+
+	if p.userAgent != "" {
+		if err := page.SetUserAgent(&proto.NetworkSetUserAgentOverride{UserAgent: p.userAgent}); err != nil {
+			logger.Warn(ctx, "Failed to override user agent: %v", err)
+		}
+	}
+}
+
+// expandVariables 替换文本中的 ${变量名} 占位符
+func expandVariables(text string, variables map[string]string) string {
+	if text == "" || len(variables) == 0 || !strings.Contains(text, "${") {
+		return text
+	}
+	for key, value := range variables {
+		text = strings.ReplaceAll(text, "${"+key+"}", value)
+	}
+	return text
+}
@@ -0,0 +1,357 @@
+package browser
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// 响应捕获的默认上限：最多保留的未取出响应数，以及单个响应体的最大字节数
+const (
+	defaultMaxCapturedResponses = 100
+	defaultMaxCapturedBodyBytes = 2 << 20
+)
+
+// CapturedResponse 捕获到的响应
+type CapturedResponse struct {
+	URL       string      `json:"url"`
+	Method    string      `json:"method"`
+	Status    int         `json:"status"`
+	MimeType  string      `json:"mime_type"`
+	Body      interface{} `json:"body"`                // JSON 响应解析为对象，其余为文本
+	Truncated bool        `json:"truncated,omitempty"` // 响应体超过大小上限，只保留了开头部分（不再解析 JSON）
+}
+
+// ResponseCapture 捕获页面中 URL 匹配的响应体
+// 在触发请求的操作之前开始监听，之后通过 Wait 按顺序取出匹配的响应
+// 未取出的响应最多保留 maxEntries 个，超出时丢弃最早的；响应体超过 maxBodyBytes 时截断
+type ResponseCapture struct {
+	mu           sync.Mutex
+	patterns     []string
+	pending      map[proto.NetworkRequestID]*CapturedResponse // 已收到响应头、等待加载完成
+	captured     []*CapturedResponse                          // 尚未被 Wait 取出的响应
+	dropped      int                                          // 因超出数量上限被丢弃的响应数
+	maxEntries   int
+	maxBodyBytes int
+	notify       chan struct{}
+	cancels      []context.CancelFunc
+	watchings    map[proto.TargetTargetID]bool
+}
+
+// NewResponseCapture 创建响应捕获器
+// 模式支持 * 通配符（匹配完整 URL），不含通配符时按子串匹配
+func NewResponseCapture(patterns ...string) *ResponseCapture {
+	c := &ResponseCapture{
+		pending:      make(map[proto.NetworkRequestID]*CapturedResponse),
+		maxEntries:   defaultMaxCapturedResponses,
+		maxBodyBytes: defaultMaxCapturedBodyBytes,
+		notify:       make(chan struct{}),
+		watchings:    make(map[proto.TargetTargetID]bool),
+	}
+	for _, p := range patterns {
+		c.AddPattern(p)
+	}
+	return c
+}
+
+// AddPattern 添加需要捕获的 URL 模式
+func (c *ResponseCapture) AddPattern(pattern string) {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, p := range c.patterns {
+		if p == pattern {
+			return
+		}
+	}
+	c.patterns = append(c.patterns, pattern)
+}
+
+// Watch 开始监听页面的网络响应，同一页面只会监听一次
+func (c *ResponseCapture) Watch(ctx context.Context, page *rod.Page) error {
+	if page == nil {
+		return fmt.Errorf("page is nil")
+	}
+
+	c.mu.Lock()
+	if c.watchings[page.TargetID] {
+		c.mu.Unlock()
+		return nil
+	}
+	c.watchings[page.TargetID] = true
+	c.mu.Unlock()
+
+	if err := (proto.NetworkEnable{}).Call(page); err != nil {
+		return fmt.Errorf("failed to enable network monitoring: %w", err)
+	}
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	c.mu.Lock()
+	c.cancels = append(c.cancels, cancel)
+	c.mu.Unlock()
+
+	go page.Context(watchCtx).EachEvent(
+		func(e *proto.NetworkResponseReceived) {
+			if e.Response == nil || !c.matches(e.Response.URL) {
+				return
+			}
+			c.mu.Lock()
+			c.pending[e.RequestID] = &CapturedResponse{
+				URL:      e.Response.URL,
+				Status:   e.Response.Status,
+				MimeType: e.Response.MIMEType,
+			}
+			c.mu.Unlock()
+		},
+		func(e *proto.NetworkRequestWillBeSent) {
+			// 记录请求方法（响应事件中不包含）
+			if e.Request == nil || !c.matches(e.Request.URL) {
+				return
+			}
+			c.mu.Lock()
+			if resp, ok := c.pending[e.RequestID]; ok {
+				resp.Method = e.Request.Method
+			} else {
+				c.pending[e.RequestID] = &CapturedResponse{URL: e.Request.URL, Method: e.Request.Method}
+			}
+			c.mu.Unlock()
+		},
+		func(e *proto.NetworkLoadingFinished) {
+			c.mu.Lock()
+			resp, ok := c.pending[e.RequestID]
+			delete(c.pending, e.RequestID)
+			c.mu.Unlock()
+			if !ok || resp.Status == 0 {
+				return
+			}
+
+			// 传输大小已远超上限的响应不再读取响应体，避免一次性占用大量内存
+			if e.EncodedDataLength > float64(4*c.maxBodyBytes) {
+				resp.Truncated = true
+			} else {
+				body, err := proto.NetworkGetResponseBody{RequestID: e.RequestID}.Call(page)
+				if err != nil {
+					logger.Warn(ctx, "Failed to get response body of %s: %v", resp.URL, err)
+					return
+				}
+				resp.Body, resp.Truncated = decodeResponseBody(body, resp.MimeType, c.maxBodyBytes)
+			}
+
+			c.add(resp)
+			logger.Info(ctx, "Captured response: %s %s (%d, truncated: %v)", resp.Method, resp.URL, resp.Status, resp.Truncated)
+		},
+		func(e *proto.NetworkLoadingFailed) {
+			c.mu.Lock()
+			delete(c.pending, e.RequestID)
+			c.mu.Unlock()
+		},
+	)()
+
+	return nil
+}
+
+// add 保存捕获到的响应，超出数量上限时丢弃最早的未取出响应
+func (c *ResponseCapture) add(resp *CapturedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.maxEntries > 0 && len(c.captured) >= c.maxEntries {
+		overflow := len(c.captured) - c.maxEntries + 1
+		for i := 0; i < overflow; i++ {
+			c.captured[i] = nil
+		}
+		c.captured = c.captured[overflow:]
+		c.dropped += overflow
+	}
+	c.captured = append(c.captured, resp)
+	close(c.notify)
+	c.notify = make(chan struct{})
+}
+
+// Dropped 返回因超出数量上限而被丢弃的响应数
+func (c *ResponseCapture) Dropped() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.dropped
+}
+
+// Wait 取出下一个与模式（和方法）匹配且未被取出的响应，没有时等待直到超时
+// pattern 为空时匹配任意已捕获的响应
+func (c *ResponseCapture) Wait(ctx context.Context, pattern, method string, timeout time.Duration) (*CapturedResponse, error) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+		c.mu.Lock()
+		for i, resp := range c.captured {
+			if pattern != "" && !MatchURLPattern(pattern, resp.URL) {
+				continue
+			}
+			if method != "" && !strings.EqualFold(method, resp.Method) {
+				continue
+			}
+			// 取出后不再保留，释放响应体占用的内存
+			c.captured = append(c.captured[:i], c.captured[i+1:]...)
+			c.mu.Unlock()
+			return resp, nil
+		}
+		notify := c.notify
+		c.mu.Unlock()
+
+		select {
+		case <-notify:
+		case <-deadline.C:
+			return nil, fmt.Errorf("timeout waiting for response matching %s", pattern)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Stop 停止监听
+func (c *ResponseCapture) Stop() {
+	c.mu.Lock()
+	cancels := c.cancels
+	c.cancels = nil
+	c.watchings = make(map[proto.TargetTargetID]bool)
+	c.mu.Unlock()
+
+	for _, cancel := range cancels {
+		cancel()
+	}
+}
+
+// matches 判断 URL 是否匹配任一捕获模式
+func (c *ResponseCapture) matches(url string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, p := range c.patterns {
+		if MatchURLPattern(p, url) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchURLPattern 判断 URL 是否匹配模式：含 * 时按通配符匹配完整 URL，否则按子串匹配
+func MatchURLPattern(pattern, url string) bool {
+	if pattern == "" {
+		return false
+	}
+	if !strings.Contains(pattern, "*") {
+		return strings.Contains(url, pattern)
+	}
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+	matched, err := regexp.MatchString(expr, url)
+	return err == nil && matched
+}
+
+// decodeResponseBody 解码响应体，JSON 内容解析为对象
+// 超过 maxBytes（> 0 时生效）的响应体截断为文本，并返回 truncated = true
+func decodeResponseBody(body *proto.NetworkGetResponseBodyResult, mimeType string, maxBytes int) (interface{}, bool) {
+	text := body.Body
+	if body.Base64Encoded {
+		data, err := base64.StdEncoding.DecodeString(body.Body)
+		if err != nil {
+			text = body.Body
+		} else {
+			text = string(data)
+		}
+	}
+
+	if maxBytes > 0 && len(text) > maxBytes {
+		return strings.ToValidUTF8(text[:maxBytes], ""), true
+	}
+
+	trimmed := strings.TrimSpace(text)
+	if strings.Contains(mimeType, "json") || strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		var value interface{}
+		if err := json.Unmarshal([]byte(trimmed), &value); err == nil {
+			return value, false
+		}
+	}
+	return text, false
+}
+
+// startResponseCapture 根据脚本中的 capture_response 操作开始监听响应（需在导航前调用，避免漏掉早期请求）
+func (p *Player) startResponseCapture(ctx context.Context, page *rod.Page, actions []models.ScriptAction) {
+	p.stopResponseCapture()
+
+	var patterns []string
+	for _, action := range actions {
+		if action.Type == "capture_response" && action.URL != "" {
+			patterns = append(patterns, action.URL)
+		}
+	}
+	if len(patterns) == 0 {
+		return
+	}
+
+	logger.Info(ctx, "Capturing responses for %d URL patterns", len(patterns))
+	p.responseCapture = NewResponseCapture(patterns...)
+	p.watchResponses(ctx, page)
+}
+
+// watchResponses 在页面上监听需要捕获的响应（新标签页同样需要调用）
+func (p *Player) watchResponses(ctx context.Context, page *rod.Page) {
+	if p.responseCapture == nil || page == nil {
+		return
+	}
+	if err := p.responseCapture.Watch(ctx, page); err != nil {
+		logger.Warn(ctx, "Failed to capture responses: %v", err)
+	}
+}
+
+// stopResponseCapture 停止响应监听
+func (p *Player) stopResponseCapture() {
+	if p.responseCapture != nil {
+		p.responseCapture.Stop()
+		p.responseCapture = nil
+	}
+}
+
+// executeCaptureResponse 取出匹配 URL 模式的响应体并存入抓取数据
+func (p *Player) executeCaptureResponse(ctx context.Context, action models.ScriptAction) error {
+	if action.URL == "" {
+		return fmt.Errorf("capture_response action requires url pattern")
+	}
+	if p.responseCapture == nil {
+		return fmt.Errorf("response capture is not started")
+	}
+
+	timeout := 30 * time.Second
+	if action.Duration > 0 {
+		timeout = time.Duration(action.Duration) * time.Millisecond
+	}
+
+	logger.Info(ctx, "Waiting for response matching: %s", action.URL)
+	resp, err := p.responseCapture.Wait(ctx, action.URL, action.Method, timeout)
+	if err != nil {
+		return err
+	}
+
+	varName := action.VariableName
+	if varName == "" {
+		varName = fmt.Sprintf("response_data_%d", len(p.extractedData))
+	}
+	p.extractedData[varName] = resp.Body
+	if resp.Truncated {
+		logger.Warn(ctx, "Response body of %s exceeds the capture limit and was truncated", resp.URL)
+	}
+
+	logger.Info(ctx, "✓ Response captured successfully: %s = %s (%d)", varName, resp.URL, resp.Status)
+	return nil
+}
@@ -0,0 +1,66 @@
+package browser
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+func TestMatchURLPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		url     string
+		want    bool
+	}{
+		{"/api/items", "https://example.com/api/items?page=2", true},
+		{"/api/items", "https://example.com/static/app.js", false},
+		{"https://example.com/api/*", "https://example.com/api/v1/users", true},
+		{"https://example.com/api/*", "https://cdn.example.com/api/v1/users", false},
+		{"*/graphql?*", "https://example.com/graphql?op=Search", true},
+		{"", "https://example.com", false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchURLPattern(tt.pattern, tt.url); got != tt.want {
+			t.Errorf("MatchURLPattern(%q, %q) = %v, want %v", tt.pattern, tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestResponseCaptureDropsOldest(t *testing.T) {
+	c := NewResponseCapture("/api")
+	c.maxEntries = 2
+	for _, url := range []string{"/api/1", "/api/2", "/api/3"} {
+		c.add(&CapturedResponse{URL: url})
+	}
+
+	if got := c.Dropped(); got != 1 {
+		t.Errorf("Dropped() = %d, want 1", got)
+	}
+	resp, err := c.Wait(context.Background(), "", "", time.Millisecond)
+	if err != nil || resp.URL != "/api/2" {
+		t.Fatalf("Wait() = %+v, %v; want /api/2", resp, err)
+	}
+	if len(c.captured) != 1 {
+		t.Errorf("consumed response should be released, %d left", len(c.captured))
+	}
+}
+
+func TestDecodeResponseBodyTruncates(t *testing.T) {
+	body := &proto.NetworkGetResponseBodyResult{Body: `{"items":[1,2,3]}`}
+
+	value, truncated := decodeResponseBody(body, "application/json", 0)
+	if truncated {
+		t.Error("unexpected truncation without limit")
+	}
+	if _, ok := value.(map[string]interface{}); !ok {
+		t.Errorf("expected JSON object, got %T", value)
+	}
+
+	value, truncated = decodeResponseBody(body, "application/json", 8)
+	if !truncated || value != `{"items"` {
+		t.Errorf("decodeResponseBody() = %v, %v; want truncated text", value, truncated)
+	}
+}
@@ -0,0 +1,208 @@
+package browser
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+// 脚本检查问题的严重程度
+const (
+	LintError   = "error"   // 回放时必然失败或行为错误
+	LintWarning = "warning" // 可能导致回放失败，需要确认
+)
+
+// LintIssue 脚本静态检查发现的问题
+type LintIssue struct {
+	Step     int    `json:"step"`     // 步骤序号（从 1 开始），0 表示脚本级别的问题
+	Severity string `json:"severity"` // error, warning
+	Code     string `json:"code"`     // 问题代码，如 missing_locator
+	Message  string `json:"message"`
+}
+
+// knownActionTypes 回放器支持的步骤类型
+var knownActionTypes = map[string]bool{
+	"click": true, "input": true, "select": true, "navigate": true, "wait": true, "sleep": true,
+	"extract_text": true, "extract_html": true, "extract_attribute": true, "execute_js": true,
+	"upload_file": true, "scroll": true, "keyboard": true, "screenshot": true,
+	"open_tab": true, "switch_tab": true, "switch_active_tab": true, "wait_popup": true, "wait_popup_close": true,
+	"capture_xhr": true, "capture_response": true, "hover_then_click": true,
+	"ai_control": true, "a11y_scan": true, "call_script": true,
+}
+
+// elementActionTypes 需要定位元素的步骤类型
+var elementActionTypes = map[string]bool{
+	"click": true, "input": true, "select": true, "upload_file": true, "hover_then_click": true,
+	"extract_text": true, "extract_html": true, "extract_attribute": true,
+}
+
+// placeholderPattern 匹配 ${变量名} 占位符
+var placeholderPattern = regexp.MustCompile(`\$\{([^{}]+)\}`)
+
+// LintScript 静态检查脚本，返回按步骤排序的问题列表，没有问题时返回空列表
+func LintScript(script *models.Script) []LintIssue {
+	issues := []LintIssue{}
+	add := func(step int, severity, code, format string, args ...interface{}) {
+		issues = append(issues, LintIssue{Step: step, Severity: severity, Code: code, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(script.Actions) == 0 {
+		add(0, LintWarning, "no_actions", "script has no steps")
+	}
+
+	// 引用了未定义的变量：占位符在回放前按预设变量和执行参数替换，未定义的变量需要在执行时传入
+	undefined := make(map[string][]int)
+	checkPlaceholders := func(step int, texts ...string) {
+		for _, text := range texts {
+			for _, match := range placeholderPattern.FindAllStringSubmatch(text, -1) {
+				name := match[1]
+				if _, ok := script.Variables[name]; ok || IsStatePlaceholder(name) {
+					continue
+				}
+				if steps := undefined[name]; len(steps) == 0 || steps[len(steps)-1] != step {
+					undefined[name] = append(steps, step)
+				}
+			}
+		}
+	}
+	checkPlaceholders(0, script.URL, script.UserAgent)
+	for _, value := range script.Headers {
+		checkPlaceholders(0, value)
+	}
+
+	// 条件中可以使用之前步骤抓取的变量
+	extracted := make(map[string]bool)
+	// wait_popup 打开、尚未等待关闭的弹出窗口数
+	openPopups := 0
+
+	for i, action := range script.Actions {
+		step := i + 1
+
+		if !knownActionTypes[action.Type] {
+			add(step, LintError, "unknown_action", "unknown action type %q", action.Type)
+			continue
+		}
+
+		if elementActionTypes[action.Type] && action.Component == "" &&
+			strings.TrimSpace(action.Selector) == "" && strings.TrimSpace(action.XPath) == "" {
+			add(step, LintError, "missing_locator", "%s step has neither a selector nor an xpath", action.Type)
+		}
+		if action.Type == "hover_then_click" &&
+			strings.TrimSpace(action.TargetSelector) == "" && strings.TrimSpace(action.TargetXPath) == "" {
+			add(step, LintError, "missing_locator", "hover_then_click step has no target selector or xpath")
+		}
+
+		switch action.Type {
+		case "navigate":
+			if strings.TrimSpace(action.URL) == "" {
+				add(step, LintError, "missing_url", "navigate step has no URL, the following steps would run on the wrong page")
+			} else if next := nextEnabledAction(script.Actions, i); next != nil && next.Type == "navigate" &&
+				!isConditional(action) && !isConditional(*next) {
+				// 紧接着的导航会立即离开该页面，中间没有任何操作
+				add(step, LintWarning, "unreachable_page", "the page opened by this step is left immediately by the next navigate step")
+			}
+		case "execute_js":
+			if strings.TrimSpace(action.JSCode) == "" {
+				add(step, LintError, "missing_js_code", "execute_js step has no code")
+			}
+		case "call_script":
+			if action.ScriptID == "" {
+				add(step, LintError, "missing_script_id", "call_script step has no script_id")
+			}
+		case "wait_popup":
+			if !action.Disabled {
+				openPopups++
+			}
+		case "wait_popup_close":
+			if action.Disabled {
+				break
+			}
+			if openPopups == 0 {
+				add(step, LintError, "unmatched_popup_close", "wait_popup_close step has no preceding wait_popup step")
+			} else {
+				openPopups--
+			}
+		case "extract_attribute":
+			if action.AttributeName == "" {
+				add(step, LintError, "missing_attribute_name", "extract_attribute step has no attribute name")
+			}
+		}
+
+		// 增量抓取按变量处理抓取结果
+		if (action.Dedup || action.UpdateCursor) && action.VariableName == "" {
+			add(step, LintError, "missing_variable_name", "dedup and update_cursor need the step to store its result in variable_name")
+		}
+
+		if c := action.Condition; c != nil && c.Enabled && c.Operator != "exists" && c.Operator != "not_exists" {
+			if _, ok := script.Variables[c.Variable]; !ok && !extracted[c.Variable] {
+				add(step, LintWarning, "undefined_condition_variable",
+					"condition variable %q is not a script variable and is not extracted by an earlier step", c.Variable)
+			}
+		}
+
+		checkPlaceholders(step, action.Selector, action.XPath, action.TargetSelector, action.TargetXPath,
+			action.Value, action.URL, action.JSCode)
+		checkPlaceholders(step, action.FilePaths...)
+		for _, value := range action.ScriptParams {
+			checkPlaceholders(step, value)
+		}
+
+		if action.VariableName != "" {
+			extracted[action.VariableName] = true
+		}
+	}
+
+	for name, steps := range undefined {
+		for _, step := range steps {
+			add(step, LintWarning, "undefined_variable",
+				"${%s} is not defined in the script variables and must be passed as a parameter at run time", name)
+		}
+	}
+
+	// 参数表单的校验正则无效时执行前的参数校验总会失败
+	for name, field := range script.ParamUISchema {
+		if field.Pattern == "" {
+			continue
+		}
+		if _, err := regexp.Compile(anchoredPattern(field.Pattern)); err != nil {
+			add(0, LintError, "invalid_param_pattern", "pattern of parameter %s is not a valid regular expression: %v", name, err)
+		}
+	}
+
+	// 后处理设置无效时每次执行都会失败
+	if err := ValidateTransform(script.Transform); err != nil {
+		add(0, LintError, "invalid_transform", "transform is invalid: %v", err)
+	}
+	if err := ValidateOutputSchema(script.OutputSchema); err != nil {
+		add(0, LintError, "invalid_output_schema", "output_schema is not a valid JSON Schema: %v", err)
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Step != issues[j].Step {
+			return issues[i].Step < issues[j].Step
+		}
+		if issues[i].Code != issues[j].Code {
+			return issues[i].Code < issues[j].Code
+		}
+		return issues[i].Message < issues[j].Message
+	})
+	return issues
+}
+
+// nextEnabledAction 返回 index 之后第一个没有被禁用的步骤
+func nextEnabledAction(actions []models.ScriptAction, index int) *models.ScriptAction {
+	for i := index + 1; i < len(actions); i++ {
+		if !actions[i].Disabled {
+			return &actions[i]
+		}
+	}
+	return nil
+}
+
+// isConditional 步骤是否只在部分情况下执行（条件或环境开关）
+func isConditional(action models.ScriptAction) bool {
+	return (action.Condition != nil && action.Condition.Enabled) || len(action.RunOn) > 0 || len(action.SkipOn) > 0
+}
@@ -0,0 +1,75 @@
+package browser
+
+import (
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+func TestLintScript(t *testing.T) {
+	script := &models.Script{
+		URL:       "https://example.com/${region}",
+		Variables: map[string]string{"keyword": "shoes"},
+		Actions: []models.ScriptAction{
+			{Type: "click"},
+			{Type: "input", Selector: "#q", Value: "${keyword} ${size}"},
+			{Type: "navigate", URL: "https://example.com/a"},
+			{Type: "navigate", URL: "https://example.com/b"},
+			{Type: "extract_text", Selector: ".price", VariableName: "price"},
+			{Type: "click", Selector: "#buy", Condition: &models.ActionCondition{Variable: "price", Operator: "<", Value: "100", Enabled: true}},
+			{Type: "click", Selector: "#next", Condition: &models.ActionCondition{Variable: "stock", Operator: "=", Value: "yes", Enabled: true}},
+			{Type: "input", Component: "login.username", Value: "x"},
+			{Type: "hover_then_click", Selector: "#menu"},
+			{Type: "dance"},
+			{Type: "wait_popup_close"},
+			{Type: "execute_js", JSCode: "return []", Dedup: true},
+		},
+		ParamUISchema: map[string]models.ParamUIField{"size": {Pattern: "[0-9"}},
+		Transform:     &models.DataTransform{Type: "jq", Expression: ".items"},
+		OutputSchema:  map[string]interface{}{"required": "items"},
+	}
+
+	got := map[int][]string{}
+	for _, issue := range LintScript(script) {
+		got[issue.Step] = append(got[issue.Step], issue.Code)
+	}
+	want := map[int][]string{
+		0:  {"invalid_output_schema", "invalid_param_pattern", "invalid_transform", "undefined_variable"},
+		1:  {"missing_locator"},
+		2:  {"undefined_variable"},
+		3:  {"unreachable_page"},
+		7:  {"undefined_condition_variable"},
+		9:  {"missing_locator"},
+		10: {"unknown_action"},
+		11: {"unmatched_popup_close"},
+		12: {"missing_variable_name"},
+	}
+	if len(got) != len(want) {
+		t.Errorf("issues by step = %v, want %v", got, want)
+	}
+	for step, codes := range want {
+		if len(got[step]) != len(codes) || got[step][0] != codes[0] {
+			t.Errorf("step %d: codes = %v, want %v", step, got[step], codes)
+		}
+	}
+}
+
+func TestLintScriptClean(t *testing.T) {
+	script := &models.Script{
+		URL: "https://example.com",
+		Actions: []models.ScriptAction{
+			{Type: "navigate", URL: "https://example.com/a?since=${state.cursor}"},
+			{Type: "execute_js", JSCode: "return []", VariableName: "items", Dedup: true, DedupKey: "id"},
+			{Type: "navigate", URL: "https://example.com/b", RunOn: []string{"staging"}},
+			{Type: "click", XPath: "//button"},
+			{Type: "wait_popup", URL: "accounts.example.com"},
+			{Type: "click", Selector: "#allow"},
+			{Type: "wait_popup_close"},
+		},
+		Transform:    &models.DataTransform{Type: models.TransformJMESPath, Expression: "items[?price < `10`].id"},
+		OutputSchema: map[string]interface{}{"type": "object", "required": []interface{}{"result"}},
+	}
+	if issues := LintScript(script); len(issues) != 0 {
+		t.Errorf("expected no issues, got %v", issues)
+	}
+}
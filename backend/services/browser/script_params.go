@@ -0,0 +1,211 @@
+package browser
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+// ScriptParameterSchema 返回脚本执行参数的 JSON Schema，供低代码平台等调用方生成参数表单
+// 参数来自脚本的 MCP 输入定义、预设变量（作为默认值）和脚本中引用的 ${变量名} 占位符；
+// 没有默认值的占位符为必填参数。url 参数总是可用，用于覆盖脚本的起始 URL。
+// 参数表单设置（ParamUISchema）转换为 title、pattern、enum 等关键字，原始设置保留在 x-ui 中
+func ScriptParameterSchema(script *models.Script) map[string]interface{} {
+	properties := make(map[string]interface{})
+	required := make(map[string]bool)
+
+	for _, name := range scriptPlaceholders(script) {
+		// ${state.*} 引用增量抓取状态，回放时替换，不是执行参数
+		if IsStatePlaceholder(name) {
+			continue
+		}
+		properties[name] = map[string]interface{}{"type": "string"}
+		required[name] = true
+	}
+	for name, value := range script.Variables {
+		properties[name] = map[string]interface{}{"type": "string", "default": value}
+		delete(required, name)
+	}
+
+	// MCP 输入定义包含参数说明和类型，优先使用
+	if props, ok := script.MCPInputSchema["properties"].(map[string]interface{}); ok {
+		for name, def := range props {
+			prop, ok := def.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			merged := make(map[string]interface{}, len(prop)+1)
+			if existing, ok := properties[name].(map[string]interface{}); ok {
+				for k, v := range existing {
+					merged[k] = v
+				}
+			}
+			for k, v := range prop {
+				merged[k] = v
+			}
+			properties[name] = merged
+		}
+	}
+	if names, ok := script.MCPInputSchema["required"].([]interface{}); ok {
+		for _, name := range names {
+			if s, ok := name.(string); ok {
+				if _, defined := script.Variables[s]; !defined {
+					required[s] = true
+				}
+			}
+		}
+	}
+
+	for name, field := range script.ParamUISchema {
+		prop, ok := properties[name].(map[string]interface{})
+		if !ok {
+			prop = map[string]interface{}{"type": "string"}
+			properties[name] = prop
+		}
+		for k, v := range ParamUIKeywords(field) {
+			prop[k] = v
+		}
+	}
+
+	if _, ok := properties["url"]; !ok {
+		properties["url"] = map[string]interface{}{
+			"type":        "string",
+			"description": "Override the start URL of the script",
+		}
+	}
+	delete(required, "url")
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		names := make([]string, 0, len(required))
+		for name := range required {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		schema["required"] = names
+	}
+	return schema
+}
+
+// scriptPlaceholders 返回脚本中引用的占位符变量名（按首次出现的顺序，不重复），范围与执行参数替换相同
+func scriptPlaceholders(script *models.Script) []string {
+	var names []string
+	seen := make(map[string]bool)
+	collect := func(texts ...string) {
+		for _, text := range texts {
+			for _, match := range placeholderPattern.FindAllStringSubmatch(text, -1) {
+				if !seen[match[1]] {
+					seen[match[1]] = true
+					names = append(names, match[1])
+				}
+			}
+		}
+	}
+
+	collect(script.URL, script.UserAgent)
+	for _, value := range script.Headers {
+		collect(value)
+	}
+	for _, action := range script.Actions {
+		if action.Disabled {
+			continue
+		}
+		collect(action.Selector, action.XPath, action.TargetSelector, action.TargetXPath,
+			action.Value, action.URL, action.JSCode)
+		collect(action.FilePaths...)
+		for _, value := range action.ScriptParams {
+			collect(value)
+		}
+	}
+	return names
+}
+
+// ParamUIKeywords 将参数表单设置转换为 JSON Schema 关键字，MCP 客户端据此渲染表单并校验输入
+func ParamUIKeywords(field models.ParamUIField) map[string]interface{} {
+	keywords := map[string]interface{}{"x-ui": field}
+	if field.Label != "" {
+		keywords["title"] = field.Label
+	}
+	if field.Help != "" {
+		keywords["description"] = field.Help
+	}
+	if field.Placeholder != "" {
+		keywords["examples"] = []string{field.Placeholder}
+	}
+	if field.Pattern != "" {
+		keywords["pattern"] = anchoredPattern(field.Pattern)
+	}
+	switch field.InputType {
+	case models.ParamInputSelect:
+		if len(field.Options) > 0 {
+			keywords["enum"] = field.Options
+		}
+	case models.ParamInputCheckbox:
+		keywords["enum"] = []string{"true", "false"}
+	case models.ParamInputEmail:
+		keywords["format"] = "email"
+	case models.ParamInputURL:
+		keywords["format"] = "uri"
+	case models.ParamInputDate:
+		keywords["format"] = "date"
+	case models.ParamInputPassword:
+		keywords["format"] = "password"
+	}
+	return keywords
+}
+
+// anchoredPattern 要求完整匹配（JSON Schema 的 pattern 默认是部分匹配）
+func anchoredPattern(pattern string) string {
+	return "^(?:" + pattern + ")$"
+}
+
+// ValidateScriptParams 按参数表单设置校验执行参数：非空的值需要完整匹配 Pattern，
+// select 的值必须是可选值之一，checkbox 的值必须是 true 或 false
+func ValidateScriptParams(script *models.Script, params map[string]string) error {
+	names := make([]string, 0, len(script.ParamUISchema))
+	for name := range script.ParamUISchema {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		field := script.ParamUISchema[name]
+		value, ok := params[name]
+		if !ok || value == "" {
+			continue
+		}
+		label := field.Label
+		if label == "" {
+			label = name
+		}
+		if field.Pattern != "" {
+			re, err := regexp.Compile(anchoredPattern(field.Pattern))
+			if err != nil {
+				return fmt.Errorf("invalid pattern for parameter %s: %w", name, err)
+			}
+			if !re.MatchString(value) {
+				if field.PatternMessage != "" {
+					return fmt.Errorf("%s: %s", label, field.PatternMessage)
+				}
+				return fmt.Errorf("%s does not match pattern %s", label, field.Pattern)
+			}
+		}
+		switch field.InputType {
+		case models.ParamInputSelect:
+			if len(field.Options) > 0 && !slices.Contains(field.Options, value) {
+				return fmt.Errorf("%s must be one of %v", label, field.Options)
+			}
+		case models.ParamInputCheckbox:
+			if value != "true" && value != "false" {
+				return fmt.Errorf("%s must be true or false", label)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package browser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+func TestScriptParameterSchema(t *testing.T) {
+	script := &models.Script{
+		URL:       "https://example.com/${region}",
+		Variables: map[string]string{"keyword": "shoes"},
+		Actions: []models.ScriptAction{
+			{Type: "input", Selector: "#q", Value: "${keyword} ${size}"},
+			{Type: "input", Selector: "#skip", Value: "${unused}", Disabled: true},
+		},
+		MCPInputSchema: map[string]interface{}{
+			"properties": map[string]interface{}{
+				"size": map[string]interface{}{"type": "string", "description": "Shoe size"},
+			},
+			"required": []interface{}{"keyword"},
+		},
+	}
+
+	schema := ScriptParameterSchema(script)
+	props := schema["properties"].(map[string]interface{})
+
+	if got := props["keyword"]; !reflect.DeepEqual(got, map[string]interface{}{"type": "string", "default": "shoes"}) {
+		t.Errorf("keyword = %v", got)
+	}
+	if got := props["size"].(map[string]interface{})["description"]; got != "Shoe size" {
+		t.Errorf("size description = %v", got)
+	}
+	if _, ok := props["url"]; !ok {
+		t.Error("url override missing")
+	}
+	if _, ok := props["unused"]; ok {
+		t.Error("placeholder of a disabled step should not be a parameter")
+	}
+	// keyword 有默认值，不是必填
+	if got := schema["required"]; !reflect.DeepEqual(got, []string{"region", "size"}) {
+		t.Errorf("required = %v", got)
+	}
+}
+
+func TestParamUISchema(t *testing.T) {
+	script := &models.Script{
+		Actions: []models.ScriptAction{{Type: "input", Selector: "#zip", Value: "${zip} ${size}"}},
+		ParamUISchema: map[string]models.ParamUIField{
+			"zip":  {Label: "ZIP code", Placeholder: "10115", Pattern: `\d{5}`, PatternMessage: "enter 5 digits"},
+			"size": {InputType: models.ParamInputSelect, Options: []string{"S", "M", "L"}},
+		},
+	}
+
+	props := ScriptParameterSchema(script)["properties"].(map[string]interface{})
+	zip := props["zip"].(map[string]interface{})
+	if zip["title"] != "ZIP code" || zip["pattern"] != `^(?:\d{5})$` || !reflect.DeepEqual(zip["examples"], []string{"10115"}) {
+		t.Errorf("zip = %v", zip)
+	}
+	if got := props["size"].(map[string]interface{})["enum"]; !reflect.DeepEqual(got, []string{"S", "M", "L"}) {
+		t.Errorf("size enum = %v", got)
+	}
+
+	for _, tc := range []struct {
+		params  map[string]string
+		wantErr string
+	}{
+		{params: map[string]string{"zip": "10115", "size": "M"}},
+		{params: map[string]string{"zip": ""}}, // 空值不校验，是否必填由参数定义决定
+		{params: map[string]string{"zip": "10115-1"}, wantErr: "ZIP code: enter 5 digits"},
+		{params: map[string]string{"size": "XL"}, wantErr: "size must be one of [S M L]"},
+	} {
+		err := ValidateScriptParams(script, tc.params)
+		if (err == nil) != (tc.wantErr == "") || (err != nil && err.Error() != tc.wantErr) {
+			t.Errorf("ValidateScriptParams(%v) = %v, want %q", tc.params, err, tc.wantErr)
+		}
+	}
+}
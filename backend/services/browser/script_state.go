@@ -0,0 +1,258 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+)
+
+const (
+	// statePlaceholderPrefix 引用脚本增量抓取状态的占位符前缀，如 ${state.cursor}，回放时替换，不是执行参数
+	statePlaceholderPrefix = "state."
+	// scriptStateMaxSeenKeys 每个脚本最多保留的已输出条目键，超出时丢弃最早的
+	scriptStateMaxSeenKeys = 100000
+)
+
+// IsStatePlaceholder 占位符（不含 ${}）是否引用脚本的增量抓取状态
+func IsStatePlaceholder(name string) bool {
+	return strings.HasPrefix(name, statePlaceholderPrefix)
+}
+
+// usesScriptState 脚本是否使用增量抓取状态（去重、游标或引用状态的占位符）
+func usesScriptState(script *models.Script) bool {
+	for _, action := range script.Actions {
+		if action.Dedup || action.UpdateCursor {
+			return true
+		}
+	}
+	for _, name := range scriptPlaceholders(script) {
+		if IsStatePlaceholder(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// scrapeState 一次执行中的增量抓取状态：去重时参考之前已输出的条目键，记录本次新输出的条目和游标，执行成功后写回
+type scrapeState struct {
+	seen      map[string]time.Time // 之前的执行已输出过的条目键
+	added     map[string]bool      // 本次执行新输出的条目键
+	cursor    string
+	cursorSet bool
+}
+
+func newScrapeState(state *models.ScriptState) *scrapeState {
+	return &scrapeState{seen: state.SeenKeys, added: make(map[string]bool)}
+}
+
+// apply 按步骤的增量抓取设置处理抓取结果，返回去重后的值；ok 为 false 表示没有新条目，不保存该变量
+func (s *scrapeState) apply(action models.ScriptAction, value interface{}) (result interface{}, ok bool) {
+	result, ok = value, true
+	if action.Dedup {
+		result, ok = s.dedup(normalizeExtracted(value), action.DedupKey)
+	}
+	if action.UpdateCursor && ok {
+		// 去重后没有新条目时保留原来的游标
+		if cursor := normalizeExtracted(result); cursor != nil && !isEmptyList(cursor) {
+			s.cursor = cursorString(cursor)
+			s.cursorSet = true
+		}
+	}
+	return result, ok
+}
+
+// dedup 去掉已输出过的条目：数组逐个元素判断，其他值整体判断
+func (s *scrapeState) dedup(value interface{}, field string) (interface{}, bool) {
+	items, isList := value.([]interface{})
+	if !isList {
+		key, hasKey := dedupItemKey(value, field)
+		if hasKey && !s.markNew(key) {
+			return nil, false
+		}
+		return value, true
+	}
+
+	fresh := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		// 缺少条目键的元素无法判断，保留
+		if key, hasKey := dedupItemKey(item, field); hasKey && !s.markNew(key) {
+			continue
+		}
+		fresh = append(fresh, item)
+	}
+	return fresh, true
+}
+
+// markNew 记录条目键，条目之前（包括本次执行中）已输出过时返回 false
+func (s *scrapeState) markNew(key string) bool {
+	if _, ok := s.seen[key]; ok || s.added[key] {
+		return false
+	}
+	s.added[key] = true
+	return true
+}
+
+// applyScrapeState 按步骤的增量抓取设置处理该步骤抓取的变量，没有新条目时移除变量
+func (p *Player) applyScrapeState(ctx context.Context, action models.ScriptAction) {
+	value, ok := p.extractedData[action.VariableName]
+	if !ok {
+		return
+	}
+	result, keep := p.scrapeState.apply(action, value)
+	if !keep {
+		delete(p.extractedData, action.VariableName)
+		logger.Info(ctx, "No new items in %s, already emitted by an earlier run", action.VariableName)
+		return
+	}
+	p.extractedData[action.VariableName] = result
+}
+
+// isEmptyList 值是否为空数组
+func isEmptyList(value interface{}) bool {
+	items, ok := value.([]interface{})
+	return ok && len(items) == 0
+}
+
+// dedupItemKey 条目键：设置了字段时取对象元素的该字段，否则为整个元素（字符串原样，其他值为 JSON）
+func dedupItemKey(item interface{}, field string) (string, bool) {
+	if field != "" {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		if item, ok = obj[field]; !ok || item == nil {
+			return "", false
+		}
+	}
+	return cursorString(item), true
+}
+
+// cursorString 把值转换为保存的字符串：字符串原样，其他值为 JSON
+func cursorString(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// normalizeExtracted 把抓取结果（如 JavaScript 返回的 gson.JSON）转换为通用的 JSON 值，便于按数组和对象处理
+func normalizeExtracted(value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return value
+	}
+	return normalized
+}
+
+// replaceStatePlaceholders 把脚本中的 ${state.cursor}、${state.last_run_at} 替换为保存的状态，范围与执行参数替换相同
+func replaceStatePlaceholders(script *models.Script, state *models.ScriptState) {
+	lastRunAt := ""
+	if state.LastRunAt != nil {
+		lastRunAt = state.LastRunAt.Format(time.RFC3339)
+	}
+	replacer := strings.NewReplacer(
+		"${"+statePlaceholderPrefix+"cursor}", state.Cursor,
+		"${"+statePlaceholderPrefix+"last_run_at}", lastRunAt,
+	)
+
+	script.URL = replacer.Replace(script.URL)
+	for i := range script.Actions {
+		action := &script.Actions[i]
+		action.Selector = replacer.Replace(action.Selector)
+		action.XPath = replacer.Replace(action.XPath)
+		action.TargetSelector = replacer.Replace(action.TargetSelector)
+		action.TargetXPath = replacer.Replace(action.TargetXPath)
+		action.Value = replacer.Replace(action.Value)
+		action.URL = replacer.Replace(action.URL)
+		action.JSCode = replacer.Replace(action.JSCode)
+		// 步骤是浅拷贝，文件路径替换到新的切片中
+		if len(action.FilePaths) > 0 {
+			filePaths := make([]string, len(action.FilePaths))
+			for j, path := range action.FilePaths {
+				filePaths[j] = replacer.Replace(path)
+			}
+			action.FilePaths = filePaths
+		}
+	}
+}
+
+// loadScrapeState 读取脚本的增量抓取状态并替换脚本中引用状态的占位符，脚本不使用状态时返回 nil
+func (m *Manager) loadScrapeState(script *models.Script) (*models.Script, *scrapeState, error) {
+	if m.db == nil || !usesScriptState(script) {
+		return script, nil, nil
+	}
+	state, err := m.db.GetScriptState(script.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load script state: %w", err)
+	}
+	script = script.Copy()
+	replaceStatePlaceholders(script, state)
+	return script, newScrapeState(state), nil
+}
+
+// commitScrapeState 把成功执行中新输出的条目键和游标合并到保存的状态（并发执行同一脚本时依次合并）
+func (m *Manager) commitScrapeState(ctx context.Context, scriptID string, s *scrapeState, startedAt time.Time) {
+	m.scriptStateMu.Lock()
+	defer m.scriptStateMu.Unlock()
+
+	state, err := m.db.GetScriptState(scriptID)
+	if err != nil {
+		logger.Warn(ctx, "Failed to load script state: %v", err)
+		return
+	}
+	if len(s.added) > 0 && state.SeenKeys == nil {
+		state.SeenKeys = make(map[string]time.Time, len(s.added))
+	}
+	now := time.Now()
+	for key := range s.added {
+		if _, ok := state.SeenKeys[key]; !ok {
+			state.SeenKeys[key] = now
+		}
+	}
+	trimSeenKeys(state.SeenKeys, scriptStateMaxSeenKeys)
+	if s.cursorSet {
+		state.Cursor = s.cursor
+	}
+	state.LastRunAt = &startedAt
+
+	if err := m.db.SaveScriptState(state); err != nil {
+		logger.Warn(ctx, "Failed to save script state: %v", err)
+		return
+	}
+	logger.Info(ctx, "Script state saved: %d new items, %d seen", len(s.added), len(state.SeenKeys))
+}
+
+// trimSeenKeys 条目键超出上限时丢弃最早输出的
+func trimSeenKeys(seen map[string]time.Time, limit int) {
+	excess := len(seen) - limit
+	if excess <= 0 {
+		return
+	}
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if seen[keys[i]].Equal(seen[keys[j]]) {
+			return keys[i] < keys[j]
+		}
+		return seen[keys[i]].Before(seen[keys[j]])
+	})
+	for _, key := range keys[:excess] {
+		delete(seen, key)
+	}
+}
@@ -0,0 +1,102 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/storage"
+)
+
+func TestScrapeStateDedup(t *testing.T) {
+	logger.InitLogger(&logger.LoggerConfig{Level: "error"})
+	db, err := storage.NewBoltDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	defer db.Close()
+	m := &Manager{db: db}
+	ctx := context.Background()
+
+	script := &models.Script{
+		ID:  "s1",
+		URL: "https://example.com/feed?since=${state.cursor}&q=${keyword}",
+		Actions: []models.ScriptAction{
+			{Type: "execute_js", JSCode: "return items()", VariableName: "items", Dedup: true, DedupKey: "id"},
+			{Type: "execute_js", JSCode: "return latest()", VariableName: "latest", UpdateCursor: true},
+		},
+	}
+	// ${state.*} 不是执行参数
+	if _, ok := ScriptParameterSchema(script)["properties"].(map[string]interface{})["state.cursor"]; ok {
+		t.Error("state placeholders should not be listed as parameters")
+	}
+
+	run := func(items string, latest string) (*models.Script, map[string]interface{}) {
+		toRun, scrape, err := m.loadScrapeState(script)
+		if err != nil || scrape == nil {
+			t.Fatalf("expected script state, got %v", err)
+		}
+		// execute_js 的结果为 gson.JSON，这里用同样按 JSON 序列化的原始数据代替
+		p := &Player{scrapeState: scrape, extractedData: map[string]interface{}{
+			"items":  json.RawMessage(items),
+			"latest": latest,
+		}}
+		for _, action := range toRun.Actions {
+			p.applyScrapeState(ctx, action)
+		}
+		m.commitScrapeState(ctx, script.ID, scrape, time.Now())
+		return toRun, p.extractedData
+	}
+
+	first, data := run(`[{"id":1,"t":"a"},{"id":2,"t":"b"},{"id":2,"t":"dup"}]`, "2")
+	if first.URL != "https://example.com/feed?since=&q=${keyword}" {
+		t.Errorf("unexpected URL before the first run: %s", first.URL)
+	}
+	if items := data["items"].([]interface{}); len(items) != 2 {
+		t.Errorf("expected 2 new items, got %v", items)
+	}
+
+	second, data := run(`[{"id":2,"t":"b"},{"id":3,"t":"c"},{"t":"no id"}]`, "3")
+	if second.URL != "https://example.com/feed?since=2&q=${keyword}" {
+		t.Errorf("cursor should be substituted, got %s", second.URL)
+	}
+	want := []interface{}{map[string]interface{}{"id": float64(3), "t": "c"}, map[string]interface{}{"t": "no id"}}
+	if !reflect.DeepEqual(data["items"], want) {
+		t.Errorf("expected only new items, got %v", data["items"])
+	}
+	if script.URL != "https://example.com/feed?since=${state.cursor}&q=${keyword}" {
+		t.Error("the stored script must not be modified")
+	}
+
+	state, _ := db.GetScriptState(script.ID)
+	if len(state.SeenKeys) != 3 || state.Cursor != "3" || state.LastRunAt == nil {
+		t.Errorf("unexpected state %+v", state.Summary())
+	}
+
+	// 单个值整体去重，已输出过时不保存变量
+	scrape := newScrapeState(state)
+	if _, keep := scrape.apply(models.ScriptAction{Dedup: true}, float64(3)); keep {
+		t.Error("a seen scalar should be dropped")
+	}
+	if _, keep := scrape.apply(models.ScriptAction{Dedup: true}, "fresh"); !keep {
+		t.Error("a new scalar should be kept")
+	}
+}
+
+func TestTrimSeenKeys(t *testing.T) {
+	start := time.Now()
+	seen := map[string]time.Time{}
+	for i := 0; i < 5; i++ {
+		seen[fmt.Sprint(i)] = start.Add(time.Duration(i) * time.Second)
+	}
+	trimSeenKeys(seen, 3)
+	if _, ok := seen["1"]; ok || len(seen) != 3 {
+		t.Errorf("expected the oldest keys to be dropped, got %v", seen)
+	}
+}
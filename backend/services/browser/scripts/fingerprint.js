@@ -0,0 +1,63 @@
+function (el) {
+	// 元素结构指纹：录制器和回放共用这一份实现，保证两端采集的特征一致
+	if (!el || el.nodeType !== 1) return null;
+
+	var STABLE_ATTRS = ['id', 'name', 'type', 'role', 'aria-label', 'placeholder', 'href', 'title', 'alt', 'for',
+		'data-testid', 'data-test', 'data-qa', 'data-cy'];
+
+	// 过滤框架生成的动态值（长数字串、css-xxxx 等）
+	var isStable = function(value) {
+		return !!value && value.length <= 200 && !/\d{4,}/.test(value) && !/^(css|jss|sc|ember|react)-/.test(value);
+	};
+
+	var normalize = function(text) {
+		return (text || '').replace(/\s+/g, ' ').trim().toLowerCase().substring(0, 100);
+	};
+
+	// FNV-1a 32 位哈希
+	var hash = function(str) {
+		var h = 0x811c9dc5;
+		for (var i = 0; i < str.length; i++) {
+			h ^= str.charCodeAt(i);
+			h = (h + ((h << 1) + (h << 4) + (h << 7) + (h << 8) + (h << 24))) >>> 0;
+		}
+		return ('0000000' + h.toString(16)).slice(-8);
+	};
+
+	var attrs = {};
+	for (var i = 0; i < STABLE_ATTRS.length; i++) {
+		var value = el.getAttribute(STABLE_ATTRS[i]);
+		if (isStable(value)) attrs[STABLE_ATTRS[i]] = value;
+	}
+	if (typeof el.className === 'string') {
+		var classes = el.className.split(/\s+/).filter(isStable).sort();
+		if (classes.length > 0) attrs['class'] = classes.join(' ');
+	}
+
+	var keys = Object.keys(attrs).sort();
+	var attrString = '';
+	for (var k = 0; k < keys.length; k++) {
+		attrString += keys[k] + '=' + attrs[keys[k]] + '\n';
+	}
+
+	var tag = el.tagName.toLowerCase();
+	var text = tag === 'input' || tag === 'textarea' || tag === 'select' ? '' : normalize(el.innerText || el.textContent);
+
+	// 祖先签名（由近及远，最多 4 层）：标签 + 稳定 id + role
+	var ancestors = [];
+	for (var p = el.parentElement; p && ancestors.length < 4 && p.tagName.toLowerCase() !== 'body'; p = p.parentElement) {
+		var sig = p.tagName.toLowerCase();
+		if (isStable(p.id)) sig += '#' + p.id;
+		var role = p.getAttribute('role');
+		if (role) sig += '[' + role + ']';
+		ancestors.push(sig);
+	}
+
+	return {
+		tag: tag,
+		attrs: attrs,
+		attr_hash: keys.length > 0 ? hash(attrString) : '',
+		text: text,
+		ancestors: ancestors
+	};
+}
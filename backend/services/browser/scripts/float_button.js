@@ -1,22 +1,34 @@
 // 浮动录制按钮脚本 - 只在OpenPage时注入
+// theme 为浏览器配置中的浮动按钮选项（position、offset_x、offset_y、accent_color、background_color、text_color）
 if (!window.__browserwingFloatButton__) {
-	createFloatingRecordButton()
+	createFloatingRecordButton(theme || {})
 }
 
-function createFloatingRecordButton() {
+function createFloatingRecordButton(theme) {
     window.__browserwingFloatButton__ = true;
 	
+	// 位置和颜色，未配置时使用默认外观
+	var position = theme.position || 'top-right';
+	var vertical = position.indexOf('bottom') === 0 ? 'bottom' : 'top';
+	var horizontal = position.indexOf('left') > 0 ? 'left' : 'right';
+	var offsetX = (theme.offset_x || 20) + 'px';
+	var offsetY = (theme.offset_y || 20) + 'px';
+	var panelBackground = theme.background_color || 'linear-gradient(135deg, #ffffff 0%, #fafbfc 100%)';
+	var titleColor = theme.text_color || '#0f172a';
+	var buttonBackground = theme.accent_color || 'linear-gradient(135deg, #ef4444 0%, #dc2626 100%)';
+	var buttonHoverBackground = theme.accent_color || 'linear-gradient(135deg, #dc2626 0%, #b91c1c 100%)';
+	
 	// 创建主面板 - 现代化设计风格
 	var panel = document.createElement('div');
 	panel.id = '__browserwing_float_panel__';
-	panel.style.cssText = 'position: fixed !important;top: 20px !important;right: 20px !important;z-index: 2147483647 !important;font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", "SF Pro Display", Helvetica, Arial, sans-serif !important;width: 320px !important;background: linear-gradient(135deg, #ffffff 0%, #fafbfc 100%) !important;border-radius: 16px !important;box-shadow: 0 8px 32px rgba(0, 0, 0, 0.08), 0 2px 8px rgba(0, 0, 0, 0.04) !important;border: 1px solid rgba(0, 0, 0, 0.06) !important;overflow: hidden !important;opacity: 1 !important;visibility: visible !important;backdrop-filter: blur(10px) !important;';
+	panel.style.cssText = 'position: fixed !important;' + vertical + ': ' + offsetY + ' !important;' + horizontal + ': ' + offsetX + ' !important;z-index: 2147483647 !important;font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", "SF Pro Display", Helvetica, Arial, sans-serif !important;width: 320px !important;background: ' + panelBackground + ' !important;border-radius: 16px !important;box-shadow: 0 8px 32px rgba(0, 0, 0, 0.08), 0 2px 8px rgba(0, 0, 0, 0.04) !important;border: 1px solid rgba(0, 0, 0, 0.06) !important;overflow: hidden !important;opacity: 1 !important;visibility: visible !important;backdrop-filter: blur(10px) !important;';
 	
 	// 创建头部区域（可拖动）
 	var header = document.createElement('div');
 	header.style.cssText = 'padding: 20px 24px 16px !important;background: transparent !important;cursor: move !important;user-select: none !important;display: flex !important;align-items: center !important;justify-content: center !important;border-bottom: 1px solid rgba(0, 0, 0, 0.05) !important;';
 	
 	var title = document.createElement('div');
-	title.style.cssText = 'color: #0f172a !important;font-size: 15px !important;font-weight: 600 !important;letter-spacing: -0.01em !important;opacity: 1 !important;visibility: visible !important;';
+	title.style.cssText = 'color: ' + titleColor + ' !important;font-size: 15px !important;font-weight: 600 !important;letter-spacing: -0.01em !important;opacity: 1 !important;visibility: visible !important;';
 	title.textContent = '{{TITLE}}';
 	
 	header.appendChild(title);
@@ -28,7 +40,7 @@ function createFloatingRecordButton() {
 	// 开始录制按钮
 	var startBtn = document.createElement('button');
 	startBtn.id = '__browserwing_start_record_btn__';
-	startBtn.style.cssText = 'width: 100% !important;padding: 14px 20px !important;background: linear-gradient(135deg, #ef4444 0%, #dc2626 100%) !important;color: white !important;border: none !important;border-radius: 12px !important;cursor: pointer !important;font-size: 14px !important;font-weight: 600 !important;letter-spacing: -0.01em !important;transition: all 0.25s cubic-bezier(0.4, 0, 0.2, 1) !important;display: flex !important;align-items: center !important;justify-content: center !important;gap: 10px !important;opacity: 1 !important;visibility: visible !important;box-shadow: 0 4px 12px rgba(239, 68, 68, 0.25), 0 2px 4px rgba(0, 0, 0, 0.1) !important;';
+	startBtn.style.cssText = 'width: 100% !important;padding: 14px 20px !important;background: ' + buttonBackground + ' !important;color: white !important;border: none !important;border-radius: 12px !important;cursor: pointer !important;font-size: 14px !important;font-weight: 600 !important;letter-spacing: -0.01em !important;transition: all 0.25s cubic-bezier(0.4, 0, 0.2, 1) !important;display: flex !important;align-items: center !important;justify-content: center !important;gap: 10px !important;opacity: 1 !important;visibility: visible !important;box-shadow: 0 4px 12px rgba(239, 68, 68, 0.25), 0 2px 4px rgba(0, 0, 0, 0.1) !important;';
 	
 	// 录制图标
 	var icon = document.createElement('div');
@@ -43,12 +55,12 @@ function createFloatingRecordButton() {
 	
 	// 悬停效果
 	startBtn.onmouseover = function() {
-		this.style.background = 'linear-gradient(135deg, #dc2626 0%, #b91c1c 100%)';
+		this.style.background = buttonHoverBackground;
 		this.style.transform = 'translateY(-2px)';
 		this.style.boxShadow = '0 6px 20px rgba(239, 68, 68, 0.35), 0 4px 8px rgba(0, 0, 0, 0.15)';
 	};
 	startBtn.onmouseout = function() {
-		this.style.background = 'linear-gradient(135deg, #ef4444 0%, #dc2626 100%)';
+		this.style.background = buttonBackground;
 		this.style.transform = 'translateY(0)';
 		this.style.boxShadow = '0 4px 12px rgba(239, 68, 68, 0.25), 0 2px 4px rgba(0, 0, 0, 0.1)';
 	};
@@ -56,12 +68,13 @@ function createFloatingRecordButton() {
 	// 点击事件
 	startBtn.onclick = function() {
 		if (!panel.__isDragging) {
-			// 使用轮询方式通知后端,而不是直接调用API
-			window.__startRecordingRequest__ = {
-				timestamp: Date.now(),
-				action: 'start'
-			};
-			console.log('[BrowserWing] Recording start request set');
+			// 通过后端注册的 binding 通知开始录制
+			if (typeof window.__browserwingCommand__ !== 'function') {
+				console.warn('[BrowserWing] Command binding not available, cannot start recording');
+				return;
+			}
+			window.__browserwingCommand__(JSON.stringify({ type: 'start_recording', data: { timestamp: Date.now() } }));
+			console.log('[BrowserWing] Recording start request sent');
 			
 			// 隐藏面板
 			panel.style.display = 'none';
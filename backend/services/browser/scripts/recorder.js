@@ -27,11 +27,45 @@ if (window.__browserwingRecorder__) {
 	window.__capturedXHRs__ = []; // 捕获的XHR请求列表
 	window.__xhrDialogOpen__ = false; // XHR对话框是否打开
 	window.__recordingStateBeforeXHRDialog__ = false; // XHR对话框打开前的录制状态
+
+	// 通过后端注册的 binding 发送命令（停止录制、截图），后端立即收到，无需轮询
+	var sendBackendCommand = function(type, data) {
+		if (typeof window.__browserwingCommand__ !== 'function') {
+			console.warn('[BrowserWing] Command binding not available, cannot send:', type);
+			return false;
+		}
+		window.__browserwingCommand__(JSON.stringify({ type: type, data: data || {} }));
+		return true;
+	};
 	
 	// ============= XHR/Fetch 监听拦截 =============
 	
 	// 初始化XHR和Fetch拦截
 	var initXHRInterceptor = function() {
+		// 运行在隔离环境中时，拦截器只能安装在页面主环境（xhr_interceptor.js），
+		// 捕获的请求通过 DOM 事件转发过来
+		if (window.__browserwingIsolatedWorld__) {
+			document.addEventListener('__browserwing_xhr_captured__', function(e) {
+				var info;
+				try {
+					info = JSON.parse(e.detail);
+				} catch (err) {
+					return;
+				}
+				var exists = window.__capturedXHRs__.some(function(xhr) {
+					return xhr.id === info.id;
+				});
+				if (!exists) {
+					window.__capturedXHRs__.push(info);
+					updateXHRButtonBadge();
+				}
+			});
+			// 同步注入前已捕获的请求
+			document.dispatchEvent(new CustomEvent('__browserwing_xhr_sync__'));
+			console.log('[BrowserWing] Receiving captured XHR/Fetch requests from the page');
+			return;
+		}
+
 		// 检查是否已经通过xhr_interceptor.js安装过拦截器
 		if (window.__browserwingXHRInterceptor__) {
 			console.log('[BrowserWing] XHR interceptor already installed by xhr_interceptor.js');
@@ -503,12 +537,9 @@ if (window.__browserwingRecorder__) {
 		this.style.background = 'linear-gradient(135deg,#ef4444 0%,#dc2626 100%)'; this.style.transform = 'translateY(0)'; this.style.boxShadow = '0 4px 12px rgba(239,68,68,0.25), 0 2px 4px rgba(0,0,0,0.1)';
 	};
 	stopRecordingBtn.onclick = async function() {
-		// 使用轮询方式通知后端停止录制,而不是直接调用API
-		window.__stopRecordingRequest__ = {
-			timestamp: Date.now(),
-			action: 'stop'
-		};
-		console.log('[BrowserWing] Recording stop request set');
+		// 通过 binding 通知后端停止录制
+		sendBackendCommand('stop_recording', { timestamp: Date.now() });
+		console.log('[BrowserWing] Recording stop request sent');
 		
 		// 禁用按钮,防止重复点击
 		this.disabled = true;
@@ -649,10 +680,10 @@ if (window.__browserwingRecorder__) {
 			} else {
 				// 直接触发截图
 				var timestamp = Date.now();
-				window.__screenshotRequest__ = {
+				sendBackendCommand('screenshot', {
 					timestamp: timestamp,
 					mode: mode
-				};
+				});
 				console.log('[BrowserWing] Screenshot request:', mode);
 				
 				// 立即在前端记录这个操作
@@ -749,14 +780,14 @@ if (window.__browserwingRecorder__) {
 				
 				// 发送截图请求
 				var timestamp = Date.now();
-				window.__screenshotRequest__ = {
+				sendBackendCommand('screenshot', {
 					timestamp: timestamp,
 					mode: 'region',
 					x: left,
 					y: top,
 					width: width,
 					height: height
-				};
+				});
 				console.log('[BrowserWing] Region screenshot request:', { x: left, y: top, width: width, height: height });
 				
 				// 立即在前端记录这个操作
@@ -3776,7 +3807,10 @@ if (window.__browserwingRecorder__) {
 	
 	// ============= 结束：语义信息提取辅助函数 =============
 	
-	// 为操作添加语义信息（Intent, Accessibility, Context, Evidence）
+	// 元素结构指纹采集函数（由 Go 端注入 scripts/fingerprint.js，与回放时使用的实现相同）
+	var collectFingerprint = __FINGERPRINT_COLLECTOR__;
+	
+	// 为操作添加语义信息（Intent, Accessibility, Context, Evidence, Fingerprint）
 	var enrichActionWithSemantics = function(action, element, eventType) {
 		if (!element) return action;
 		
@@ -3814,6 +3848,12 @@ if (window.__browserwingRecorder__) {
 				confidence: calculateConfidence(element, selectors || {css: action.selector, xpath: action.xpath})
 			};
 			
+			// 5. 填充 Fingerprint（结构指纹，回放时选择器失效后用于挑选最相似的元素）
+			var fingerprint = collectFingerprint(element);
+			if (fingerprint) {
+				action.fingerprint = fingerprint;
+			}
+			
 		} catch (e) {
 			console.error('[BrowserWing] Failed to enrich action with semantics:', e);
 		}
@@ -4001,7 +4041,21 @@ if (window.__browserwingRecorder__) {
 		// 注意: 浮动按钮由float_button.js单独注入,这里不需要创建
 		console.log('[BrowserWing] Not in recording mode, floating button should be present.');
 	}
-	
+
+	// 页面内导航（SPA 路由切换）时页面可能替换整个 body，把被移除的录制器 UI 重新挂载回来
+	window.__browserwingEnsureUI__ = function() {
+		if (!window.__isRecordingActive__ || !window.__recorderUI__ || !document.body) return false;
+		var ui = window.__recorderUI__;
+		var restored = false;
+		[ui.panel, ui.menu, ui.screenshotMenu, ui.aiModeMenu, window.__highlightElement__, window.__highlightLabel__].forEach(function(el) {
+			if (el && !el.isConnected) {
+				document.body.appendChild(el);
+				restored = true;
+			}
+		});
+		return restored;
+	};
+
 	// 鼠标悬停事件 - 高亮元素（仅在录制模式下）
 	document.addEventListener('mouseover', function(e) {
 		if (!window.__isRecordingActive__) return;
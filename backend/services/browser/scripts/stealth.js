@@ -0,0 +1,74 @@
+function (options) {
+	// 按网站配置的反检测补丁，在页面脚本执行前注入
+	// options: { webdriver, basic, languages, blockWebRTC }
+	var define = function(target, key, value) {
+		try {
+			Object.defineProperty(target, key, { get: function() { return value; }, configurable: true });
+		} catch (e) {}
+	};
+
+	// 隐藏 navigator.webdriver（正常浏览器中为 false）
+	if (options.webdriver) {
+		define(Navigator.prototype, 'webdriver', false);
+	}
+
+	if (options.basic) {
+		// 无头模式下插件列表为空，伪装为正常 Chrome 的内置 PDF 插件
+		if (navigator.plugins && navigator.plugins.length === 0 && window.PluginArray) {
+			var mimeType = { type: 'application/pdf', suffixes: 'pdf', description: 'Portable Document Format' };
+			var plugins = ['PDF Viewer', 'Chrome PDF Viewer', 'Chromium PDF Viewer', 'Microsoft Edge PDF Viewer', 'WebKit built-in PDF'].map(function(name) {
+				return { name: name, filename: 'internal-pdf-viewer', description: 'Portable Document Format', length: 1, 0: mimeType };
+			});
+			var list = Object.create(PluginArray.prototype);
+			plugins.forEach(function(plugin, i) {
+				Object.defineProperty(list, i, { value: plugin, enumerable: true });
+			});
+			Object.defineProperties(list, {
+				length: { value: plugins.length },
+				item: { value: function(i) { return plugins[i] || null; } },
+				namedItem: { value: function(name) { return plugins.find(function(p) { return p.name === name; }) || null; } },
+				refresh: { value: function() {} }
+			});
+			define(Navigator.prototype, 'plugins', list);
+		}
+		// 无头模式下没有 window.chrome
+		if (!window.chrome) {
+			Object.defineProperty(window, 'chrome', {
+				value: { runtime: {}, app: { isInstalled: false } },
+				writable: true,
+				configurable: true
+			});
+		}
+	}
+
+	// 语言：与 Accept-Language 请求头保持一致
+	if (options.languages && options.languages.length) {
+		var languages = Object.freeze(options.languages.slice());
+		define(Navigator.prototype, 'languages', languages);
+		define(Navigator.prototype, 'language', languages[0]);
+	}
+
+	// WebRTC 只使用中继（TURN）地址，不再收集本机和 STUN 反射地址，避免绕过代理泄露真实 IP
+	if (options.blockWebRTC && window.RTCPeerConnection) {
+		var Original = window.RTCPeerConnection;
+		var relayOnly = function(config) {
+			return Object.assign({}, config, { iceTransportPolicy: 'relay' });
+		};
+		var Patched = function RTCPeerConnection(config) {
+			var args = Array.prototype.slice.call(arguments, 1);
+			return new (Function.prototype.bind.apply(Original, [null, relayOnly(config)].concat(args)))();
+		};
+		Patched.prototype = Original.prototype;
+		Object.setPrototypeOf(Patched, Original);
+		var setConfiguration = Original.prototype.setConfiguration;
+		if (setConfiguration) {
+			Original.prototype.setConfiguration = function(config) {
+				return setConfiguration.call(this, relayOnly(config));
+			};
+		}
+		window.RTCPeerConnection = Patched;
+		if (window.webkitRTCPeerConnection) {
+			window.webkitRTCPeerConnection = Patched;
+		}
+	}
+}
@@ -15,6 +15,32 @@
 	
 	console.log('[BrowserWing XHR] Interceptor initialized at:', new Date().toISOString());
 	
+	// 通知录制脚本有新的请求被捕获
+	// 录制脚本运行在隔离环境中，无法读取本环境的全局变量，通过 DOM 事件转发（事件在各环境间共享）
+	var notifyCaptured = function(info) {
+		try {
+			document.dispatchEvent(new CustomEvent('__browserwing_xhr_captured__', { detail: JSON.stringify(info) }));
+		} catch (e) {
+			console.warn('[BrowserWing XHR] Failed to forward captured request:', e);
+		}
+	};
+	
+	// 添加到捕获列表并通知录制脚本
+	var publishCaptured = function(info) {
+		window.__capturedXHRs__.push(info);
+		notifyCaptured(info);
+		
+		// 如果录制UI已加载，更新角标
+		if (window.updateXHRButtonBadge && typeof window.updateXHRButtonBadge === 'function') {
+			window.updateXHRButtonBadge();
+		}
+	};
+	
+	// 录制脚本注入时请求同步已捕获的请求
+	document.addEventListener('__browserwing_xhr_sync__', function() {
+		window.__capturedXHRs__.forEach(notifyCaptured);
+	});
+	
 	// 拦截 XMLHttpRequest
 	var originalXHROpen = XMLHttpRequest.prototype.open;
 	var originalXHRSend = XMLHttpRequest.prototype.send;
@@ -91,13 +117,8 @@
 					}
 					
 					// 添加到捕获列表
-					window.__capturedXHRs__.push(xhrInfo);
+					publishCaptured(xhrInfo);
 					console.log('[BrowserWing XHR] Captured:', xhrInfo.method, xhrInfo.url, 'Status:', xhrInfo.status);
-					
-					// 如果录制UI已加载，更新角标
-					if (window.updateXHRButtonBadge && typeof window.updateXHRButtonBadge === 'function') {
-						window.updateXHRButtonBadge();
-					}
 				}
 			});
 		}
@@ -151,13 +172,8 @@
 				clonedResponse.json().then(function(data) {
 					fetchInfo.response = data;
 					fetchInfo.responseSize = JSON.stringify(data).length;
-					window.__capturedXHRs__.push(fetchInfo);
+					publishCaptured(fetchInfo);
 					console.log('[BrowserWing Fetch] Captured:', fetchInfo.method, fetchInfo.url, 'Status:', fetchInfo.status);
-					
-					// 如果录制UI已加载，更新角标
-					if (window.updateXHRButtonBadge && typeof window.updateXHRButtonBadge === 'function') {
-						window.updateXHRButtonBadge();
-					}
 				}).catch(function(e) {
 					console.warn('[BrowserWing Fetch] Failed to parse JSON response:', e);
 				});
@@ -165,26 +181,16 @@
 				clonedResponse.text().then(function(text) {
 					fetchInfo.response = text;
 					fetchInfo.responseSize = text.length;
-					window.__capturedXHRs__.push(fetchInfo);
+					publishCaptured(fetchInfo);
 					console.log('[BrowserWing Fetch] Captured:', fetchInfo.method, fetchInfo.url, 'Status:', fetchInfo.status);
-					
-					// 如果录制UI已加载，更新角标
-					if (window.updateXHRButtonBadge && typeof window.updateXHRButtonBadge === 'function') {
-						window.updateXHRButtonBadge();
-					}
 				}).catch(function(e) {
 					console.warn('[BrowserWing Fetch] Failed to read text response:', e);
 				});
 			} else {
 				fetchInfo.response = '[Binary or unknown content type]';
 				fetchInfo.responseSize = 0;
-				window.__capturedXHRs__.push(fetchInfo);
+				publishCaptured(fetchInfo);
 				console.log('[BrowserWing Fetch] Captured:', fetchInfo.method, fetchInfo.url, 'Status:', fetchInfo.status);
-				
-				// 如果录制UI已加载，更新角标
-				if (window.updateXHRButtonBadge && typeof window.updateXHRButtonBadge === 'function') {
-					window.updateXHRButtonBadge();
-				}
 			}
 			
 			return response;
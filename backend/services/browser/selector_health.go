@@ -0,0 +1,324 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+)
+
+// healthReadOnlySteps 不会改变页面的步骤类型：页面上在这些步骤之后定位的元素，页面加载后就应该存在
+var healthReadOnlySteps = map[string]bool{
+	"extract_text":      true,
+	"extract_attribute": true,
+	"extract_html":      true,
+	"wait":              true,
+	"sleep":             true,
+	"scroll":            true,
+	"capture_xhr":       true,
+	"capture_response":  true,
+	"screenshot":        true,
+	"a11y_scan":         true,
+}
+
+// healthSkippedSteps 不通过 Selector/XPath 定位页面元素的步骤类型
+var healthSkippedSteps = map[string]bool{
+	"navigate":          true,
+	"sleep":             true,
+	"execute_js":        true,
+	"capture_xhr":       true,
+	"capture_response":  true,
+	"open_tab":          true,
+	"switch_tab":        true,
+	"switch_active_tab": true,
+	"ai_control":        true,
+}
+
+// healthProbe 页面上要检查的一个定位器
+type healthProbe struct {
+	check    int    // 在报告 Checks 中的下标
+	lenient  bool   // 前面有可能改变页面的步骤、步骤有执行条件或是悬停后才出现的目标元素，找不到时记为 unverified
+	selector string // CSS 选择器
+	xpath    string // XPath
+}
+
+// healthPage 脚本打开的一个页面（起始 URL 或 navigate 步骤的 URL）及其上的定位器
+type healthPage struct {
+	url    string
+	skip   string // 无法打开该页面的原因
+	probes []healthProbe
+}
+
+// selectorHealthPlan 按 navigate 步骤把脚本分成若干页面，列出每个页面上要检查的定位器
+// 跳过禁用或不在当前执行标签下执行的步骤；使用运行时变量的定位器记为 unverified，不检查
+func selectorHealthPlan(script *models.Script) ([]healthPage, []models.SelectorCheck) {
+	labels := runLabels(script)
+	checks := []models.SelectorCheck{}
+	pages := []healthPage{{url: script.URL}}
+	changed := false // 当前页面上是否已执行过可能改变页面的步骤
+
+	for i, action := range script.Actions {
+		if stepSkipReason(action, labels) != "" {
+			continue
+		}
+		if action.Type == "navigate" {
+			pages = append(pages, healthPage{url: action.URL})
+			changed = false
+			continue
+		}
+
+		if !healthSkippedSteps[action.Type] {
+			targets := [][3]string{{"", action.Selector, action.XPath}}
+			if action.Type == "hover_then_click" {
+				targets = append(targets, [3]string{"target", action.TargetSelector, action.TargetXPath})
+			}
+			page := &pages[len(pages)-1]
+			for _, target := range targets {
+				if target[1] == "" && target[2] == "" {
+					continue
+				}
+				check := models.SelectorCheck{
+					Step:     i + 1,
+					Type:     action.Type,
+					Target:   target[0],
+					Selector: target[1],
+					XPath:    target[2],
+					PageURL:  page.url,
+				}
+				if strings.Contains(target[1]+target[2], "${") {
+					check.Status = models.SelectorUnverified
+					check.Reason = "the locator uses a variable set at run time"
+					checks = append(checks, check)
+					continue
+				}
+				page.probes = append(page.probes, healthProbe{
+					check:    len(checks),
+					lenient:  changed || target[0] != "" || (action.Condition != nil && action.Condition.Enabled),
+					selector: target[1],
+					xpath:    target[2],
+				})
+				checks = append(checks, check)
+			}
+		}
+		if !healthReadOnlySteps[action.Type] {
+			changed = true
+		}
+	}
+
+	for i := range pages {
+		switch {
+		case pages[i].url == "":
+			pages[i].skip = "the script has no start URL"
+		case strings.Contains(pages[i].url, "${"):
+			pages[i].skip = "the page URL uses a variable set at run time"
+		}
+	}
+	return pages, checks
+}
+
+// selectorProbeScript 在页面上查找定位器，直到全部找到或超时，返回每个定位器的结果：found、missing 或 cross_origin
+// 与回放相同：XPath 以 //iframe 开头或 CSS 以 "iframe " 开头的元素在 iframe 中查找
+const selectorProbeScript = `(locators, timeout) => new Promise((resolve) => {
+	const byXPath = (doc, xpath) => {
+		try {
+			return !!doc.evaluate(xpath, doc, null, XPathResult.FIRST_ORDERED_NODE_TYPE, null).singleNodeValue;
+		} catch (e) {
+			return false;
+		}
+	};
+	const byCSS = (doc, selector) => {
+		try {
+			return !!doc.querySelector(selector);
+		} catch (e) {
+			return false;
+		}
+	};
+	const find = (loc) => {
+		let xpath = loc.xpath || '';
+		let selector = loc.selector || '';
+		if (!xpath.startsWith('//iframe') && !selector.startsWith('iframe ')) {
+			return (xpath && byXPath(document, xpath)) || (selector && byCSS(document, selector)) ? 'found' : 'missing';
+		}
+		if (xpath.startsWith('//iframe')) {
+			xpath = xpath.slice(8);
+			if (!xpath.startsWith('/')) {
+				xpath = '//' + xpath;
+			}
+		} else {
+			xpath = '';
+		}
+		selector = selector.startsWith('iframe ') ? selector.slice(7) : '';
+		let blocked = false;
+		for (const frame of document.querySelectorAll('iframe')) {
+			let doc = null;
+			try {
+				doc = frame.contentDocument;
+			} catch (e) {}
+			if (!doc) {
+				blocked = true;
+				continue;
+			}
+			if ((xpath && byXPath(doc, xpath)) || (selector && byCSS(doc, selector))) {
+				return 'found';
+			}
+		}
+		return blocked ? 'cross_origin' : 'missing';
+	};
+	const deadline = Date.now() + timeout;
+	const check = () => {
+		const results = locators.map(find);
+		if (results.every((r) => r === 'found') || Date.now() >= deadline) {
+			resolve(results);
+			return;
+		}
+		setTimeout(check, 500);
+	};
+	check();
+})`
+
+// CheckScriptHealth 检查脚本录制的定位器是否仍能找到元素：在新页面中依次打开脚本的起始 URL 和 navigate 步骤的 URL，
+// 在每个页面上查找之后步骤的定位器，不执行点击、输入等步骤
+// instanceID 为空时使用当前实例
+func (m *Manager) CheckScriptHealth(ctx context.Context, script *models.Script, instanceID string) (*models.ScriptHealthReport, error) {
+	start := time.Now()
+	report := &models.ScriptHealthReport{
+		ScriptID:   script.ID,
+		ScriptName: script.Name,
+		CheckedAt:  start,
+	}
+
+	prepared, err := m.prepareHealthScript(script)
+	if err != nil {
+		return nil, err
+	}
+	pages, checks := selectorHealthPlan(prepared)
+	report.Checks = checks
+	// 页面加载后等待元素出现的时间和打开页面的超时与回放相同
+	elementWait, navigationTimeout := playbackTimeouts(m.GetTimeouts(), prepared)
+
+	var page *rod.Page
+	var instance *models.BrowserInstance
+	for _, hp := range pages {
+		if len(hp.probes) == 0 {
+			continue
+		}
+		if hp.skip != "" {
+			markUnverified(report, hp, hp.skip)
+			continue
+		}
+		if page == nil {
+			browser, _, inst, err := m.getInstanceBrowser(instanceID)
+			if err != nil {
+				return nil, err
+			}
+			m.mu.RLock()
+			defaultConfig := m.defaultBrowserConfig
+			m.mu.RUnlock()
+			if page, err = newStealthPage(browser, m.stealthOptions(defaultConfig)); err != nil {
+				return nil, fmt.Errorf("failed to create page: %w", err)
+			}
+			defer page.Close()
+			instance = inst
+		}
+		if err := m.probeHealthPage(ctx, page, instance, hp, report, elementWait, navigationTimeout); err != nil {
+			logger.Warn(ctx, "Selector health check of %s failed at %s: %v", script.Name, hp.url, err)
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", hp.url, err))
+			markUnverified(report, hp, "the page could not be checked")
+		}
+	}
+
+	report.Tally()
+	report.Duration = time.Since(start).Milliseconds()
+	logger.Info(ctx, "Selector health of %s: %d found, %d missing, %d unverified", script.Name, report.Found, report.Missing, report.Unverified)
+	return report, nil
+}
+
+// prepareHealthScript 展开子脚本、页面组件引用和脚本的预设变量，与回放时看到的定位器一致
+func (m *Manager) prepareHealthScript(script *models.Script) (*models.Script, error) {
+	prepared := script.Copy()
+	if m.db != nil && hasSubScriptCalls(prepared) {
+		var err error
+		if prepared, err = expandSubScripts(prepared, m.db.GetScript); err != nil {
+			return nil, err
+		}
+	}
+	if m.db != nil && hasComponentRefs(prepared) {
+		components, err := m.db.ListPageComponents()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load page components: %w", err)
+		}
+		if prepared, err = resolveComponentRefs(prepared, components); err != nil {
+			return nil, err
+		}
+	}
+	prepared.URL = expandVariables(prepared.URL, prepared.Variables)
+	for i := range prepared.Actions {
+		prepared.Actions[i] = expandActionVariables(prepared.Actions[i], prepared.Variables)
+	}
+	return prepared, nil
+}
+
+// probeHealthPage 打开页面并查找其上的定位器，把结果写入报告
+func (m *Manager) probeHealthPage(ctx context.Context, page *rod.Page, instance *models.BrowserInstance, hp healthPage, report *models.ScriptHealthReport, elementWait, navigationTimeout time.Duration) error {
+	if err := m.checkURLPolicy(ctx, instance, hp.url); err != nil {
+		return err
+	}
+	navCtx, cancel := context.WithTimeout(ctx, navigationTimeout)
+	defer cancel()
+	if err := page.Context(navCtx).Navigate(hp.url); err != nil {
+		return fmt.Errorf("navigation failed: %w", err)
+	}
+	if err := page.Context(navCtx).WaitLoad(); err != nil {
+		logger.Warn(ctx, "Failed to wait for page to load: %v", err)
+	}
+	if err := checkBlockPage(ctx, page); err != nil {
+		return err
+	}
+	if loggedOut, err := DetectLoggedOut(ctx, page); err == nil && loggedOut != nil {
+		return models.WithErrorCode(models.ErrorCodeLoginRequired, fmt.Errorf("the page asks to log in (%s)", loggedOut.Reason))
+	}
+
+	locators := make([]map[string]string, len(hp.probes))
+	for i, probe := range hp.probes {
+		locators[i] = map[string]string{"selector": probe.selector, "xpath": probe.xpath}
+	}
+	evalCtx, cancelEval := context.WithTimeout(ctx, elementWait+10*time.Second)
+	defer cancelEval()
+	res, err := page.Context(evalCtx).Eval(selectorProbeScript, locators, elementWait.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("failed to look up selectors: %w", err)
+	}
+	var results []string
+	if err := res.Value.Unmarshal(&results); err != nil || len(results) != len(hp.probes) {
+		return fmt.Errorf("unexpected selector lookup result: %s", res.Value.String())
+	}
+
+	for i, probe := range hp.probes {
+		check := &report.Checks[probe.check]
+		switch {
+		case results[i] == "found":
+			check.Status = models.SelectorFound
+		case results[i] == "cross_origin":
+			check.Status = models.SelectorUnverified
+			check.Reason = "the element is inside a cross-origin iframe"
+		case probe.lenient:
+			check.Status = models.SelectorUnverified
+			check.Reason = "not on the page after loading, it may appear after an earlier step or only when the step's condition is met"
+		default:
+			check.Status = models.SelectorMissing
+		}
+	}
+	return nil
+}
+
+// markUnverified 把页面上的定位器记为 unverified
+func markUnverified(report *models.ScriptHealthReport, hp healthPage, reason string) {
+	for _, probe := range hp.probes {
+		report.Checks[probe.check].Status = models.SelectorUnverified
+		report.Checks[probe.check].Reason = reason
+	}
+}
@@ -0,0 +1,54 @@
+package browser
+
+import (
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+func TestSelectorHealthPlan(t *testing.T) {
+	script := &models.Script{
+		URL: "https://shop.example.com/",
+		Actions: []models.ScriptAction{
+			{Type: "extract_text", Selector: ".price"},
+			{Type: "click", Selector: "#open-menu"},
+			{Type: "click", XPath: "//a[@id='orders']"},
+			{Type: "click", Selector: "#legacy", Disabled: true},
+			{Type: "navigate", URL: "https://shop.example.com/cart"},
+			{Type: "hover_then_click", Selector: "#menu", TargetSelector: "#checkout"},
+			{Type: "input", Selector: "#row-${id}"},
+			{Type: "navigate", URL: "https://shop.example.com/${path}"},
+			{Type: "click", Selector: "#buy"},
+		},
+	}
+
+	pages, checks := selectorHealthPlan(script)
+	if len(pages) != 3 {
+		t.Fatalf("expected 3 pages, got %d", len(pages))
+	}
+	if len(checks) != 7 {
+		t.Fatalf("expected 7 checks (disabled step skipped), got %+v", checks)
+	}
+
+	// 起始页面：第一次点击之前的定位器必须存在，之后的可能由点击打开
+	start := pages[0]
+	if len(start.probes) != 3 || start.probes[0].lenient || start.probes[1].lenient || !start.probes[2].lenient {
+		t.Errorf("unexpected probes on the start page: %+v", start.probes)
+	}
+
+	// navigate 之后重新开始计算页面是否被改变
+	cart := pages[1]
+	if cart.url != "https://shop.example.com/cart" || len(cart.probes) != 2 || cart.probes[0].lenient || !cart.probes[1].lenient {
+		t.Errorf("unexpected cart page: %+v", cart)
+	}
+	if target := checks[cart.probes[1].check]; target.Target != "target" || target.Selector != "#checkout" {
+		t.Errorf("expected the hover_then_click target to be checked, got %+v", target)
+	}
+	if variable := checks[5]; variable.Status != models.SelectorUnverified || variable.Step != 7 {
+		t.Errorf("expected the locator with a variable to be unverified, got %+v", variable)
+	}
+
+	if pages[2].skip == "" || len(pages[2].probes) != 1 {
+		t.Errorf("expected the page with a variable URL to be skipped, got %+v", pages[2])
+	}
+}
@@ -0,0 +1,85 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/browserwing/browserwing/config"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+)
+
+// GetSessionIsolation 获取 MCP 会话隔离模式
+func (m *Manager) GetSessionIsolation() string {
+	if m.config == nil {
+		return config.SessionIsolationShared
+	}
+	return m.config.Browser.SessionIsolationMode()
+}
+
+// GetBrowser 获取当前实例的浏览器
+func (m *Manager) GetBrowser() (*rod.Browser, error) {
+	browser, _, _, err := m.getInstanceBrowser("")
+	if err != nil {
+		return nil, err
+	}
+	return browser, nil
+}
+
+// GetInstanceBrowser 获取指定运行中实例的浏览器和活动页面，instanceID 为空时使用当前实例
+func (m *Manager) GetInstanceBrowser(instanceID string) (*rod.Browser, *rod.Page, error) {
+	if instanceID != "" && !m.IsInstanceRunning(instanceID) {
+		return nil, nil, fmt.Errorf("instance %s is not running", instanceID)
+	}
+	browser, page, _, err := m.getInstanceBrowser(instanceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return browser, page, nil
+}
+
+// NewBrowserContext 在当前实例中创建独立的浏览器上下文（Cookie、存储互相隔离）
+// 使用完毕后调用返回值的 Close 方法销毁
+func (m *Manager) NewBrowserContext(ctx context.Context) (*rod.Browser, error) {
+	browser, err := m.GetBrowser()
+	if err != nil {
+		return nil, err
+	}
+
+	incognito, err := browser.Incognito()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create browser context: %w", err)
+	}
+	logger.Info(ctx, "Created browser context %s", incognito.BrowserContextID)
+	return incognito, nil
+}
+
+// NewIsolatedPage 创建不影响当前活动页面的新页面（用于会话隔离）
+// browserCtx: 页面所属的浏览器上下文，nil 表示使用当前实例的默认上下文
+func (m *Manager) NewIsolatedPage(ctx context.Context, browserCtx *rod.Browser) (*rod.Page, error) {
+	if browserCtx == nil {
+		browser, err := m.GetBrowser()
+		if err != nil {
+			return nil, err
+		}
+		browserCtx = browser
+	}
+
+	if err := checkBrowserConnection(browserCtx); err != nil {
+		return nil, fmt.Errorf("browser connection is closed or invalid: %w", err)
+	}
+
+	m.mu.RLock()
+	defaultConfig := m.defaultBrowserConfig
+	m.mu.RUnlock()
+	stealthOpts := m.stealthOptions(defaultConfig)
+	page, err := newStealthPage(browserCtx, stealthOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create page: %w", err)
+	}
+
+	m.setPageWindow(page, m.GetCurrentInstance())
+
+	logger.Info(ctx, "Created isolated page %s", page.TargetID)
+	return page, nil
+}
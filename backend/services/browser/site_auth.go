@@ -0,0 +1,204 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// 客户端证书自动选择只能通过企业策略 AutoSelectCertificateForUrls 配置（没有对应的命令行参数）
+// Linux 下 Chrome/Chromium 从以下目录读取受管策略文件
+var managedPolicyDirs = []string{
+	"/etc/opt/chrome/policies/managed",
+	"/etc/chromium/policies/managed",
+}
+
+// clientCertPolicyFile 写入受管策略目录的策略文件名
+const clientCertPolicyFile = "browserwing_client_certificates.json"
+
+// loadSiteConfigs 从数据库加载网站特定配置
+func (m *Manager) loadSiteConfigs() []models.BrowserConfig {
+	if m.db == nil {
+		return nil
+	}
+	configs, err := m.db.ListBrowserConfigs()
+	if err != nil {
+		logger.Warn(context.Background(), "Failed to load site configurations: %v", err)
+		return nil
+	}
+	return configs
+}
+
+// hasSiteCredentials 是否有站点配置了 HTTP 认证凭据
+func hasSiteCredentials(configs []models.BrowserConfig) bool {
+	for _, cfg := range configs {
+		if cfg.HTTPAuth != nil && cfg.HTTPAuth.Username != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// siteCredentialsFor 查找 URL 匹配的站点 HTTP 认证凭据
+func siteCredentialsFor(configs []models.BrowserConfig, url string) *models.HTTPAuthCredentials {
+	for _, cfg := range configs {
+		if cfg.HTTPAuth == nil || cfg.HTTPAuth.Username == "" || cfg.URLPattern == "" {
+			continue
+		}
+		if matched, err := regexp.MatchString(cfg.URLPattern, url); err == nil && matched {
+			return cfg.HTTPAuth
+		}
+	}
+	return nil
+}
+
+// clientCertificateRules 根据站点配置生成客户端证书自动选择规则
+// 返回 AutoSelectCertificateForUrls 策略的值：每条规则是一个 JSON 字符串
+func clientCertificateRules(configs []models.BrowserConfig) []string {
+	type certFilter struct {
+		Issuer  map[string]string `json:"ISSUER,omitempty"`
+		Subject map[string]string `json:"SUBJECT,omitempty"`
+	}
+	type certRule struct {
+		Pattern string     `json:"pattern"`
+		Filter  certFilter `json:"filter"`
+	}
+
+	var rules []string
+	for _, cfg := range configs {
+		cert := cfg.ClientCert
+		if cert == nil || cert.Pattern == "" {
+			continue
+		}
+		rule := certRule{Pattern: cert.Pattern}
+		if cert.IssuerCN != "" {
+			rule.Filter.Issuer = map[string]string{"CN": cert.IssuerCN}
+		}
+		if cert.SubjectCN != "" {
+			rule.Filter.Subject = map[string]string{"CN": cert.SubjectCN}
+		}
+		data, err := json.Marshal(rule)
+		if err != nil {
+			continue
+		}
+		rules = append(rules, string(data))
+	}
+	return rules
+}
+
+// applyClientCertificatePolicy 将站点的客户端证书规则写入 Chrome 受管策略文件，没有规则时删除旧文件
+// 仅 Linux 支持通过文件下发策略；其他平台需要管理员通过注册表或配置描述文件设置 AutoSelectCertificateForUrls
+func (m *Manager) applyClientCertificatePolicy(ctx context.Context) {
+	rules := clientCertificateRules(m.loadSiteConfigs())
+
+	if runtime.GOOS != "linux" {
+		if len(rules) > 0 {
+			logger.Warn(ctx, "Client certificate auto-selection requires the AutoSelectCertificateForUrls enterprise policy on %s, configure it manually: %s",
+				runtime.GOOS, strings.Join(rules, ", "))
+		}
+		return
+	}
+
+	var data []byte
+	if len(rules) > 0 {
+		var err error
+		data, err = json.MarshalIndent(map[string][]string{"AutoSelectCertificateForUrls": rules}, "", "  ")
+		if err != nil {
+			logger.Warn(ctx, "Failed to encode client certificate policy: %v", err)
+			return
+		}
+	}
+
+	for _, dir := range managedPolicyDirs {
+		path := filepath.Join(dir, clientCertPolicyFile)
+		if len(rules) == 0 {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				logger.Warn(ctx, "Failed to remove client certificate policy %s: %v", path, err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			logger.Warn(ctx, "Cannot write client certificate policy to %s (requires root): %v", dir, err)
+			continue
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			logger.Warn(ctx, "Cannot write client certificate policy to %s (requires root): %v", path, err)
+			continue
+		}
+		logger.Info(ctx, "Client certificate policy written to %s (%d rules)", path, len(rules))
+	}
+}
+
+// authChallengeHandler 处理浏览器的认证请求
+// 代理认证使用代理凭据，站点 HTTP Basic/Digest 认证使用匹配站点配置的凭据
+type authChallengeHandler struct {
+	m             *Manager
+	proxyUsername string
+	proxyPassword string
+
+	mu       sync.Mutex
+	attempts map[proto.FetchRequestID]int // 每个请求已提供凭据的次数，避免凭据错误时无限重试
+}
+
+// newAuthChallengeHandler 创建认证处理器；没有代理凭据也没有站点凭据时返回 nil
+func (m *Manager) newAuthChallengeHandler(proxyUsername, proxyPassword string) *authChallengeHandler {
+	hasProxyAuth := proxyUsername != "" && proxyPassword != ""
+	if !hasProxyAuth && !hasSiteCredentials(m.loadSiteConfigs()) {
+		return nil
+	}
+	if !hasProxyAuth {
+		proxyUsername, proxyPassword = "", ""
+	}
+	return &authChallengeHandler{
+		m:             m,
+		proxyUsername: proxyUsername,
+		proxyPassword: proxyPassword,
+		attempts:      make(map[proto.FetchRequestID]int),
+	}
+}
+
+// respond 根据认证来源生成认证应答
+func (h *authChallengeHandler) respond(ctx context.Context, e *proto.FetchAuthRequired) *proto.FetchAuthChallengeResponse {
+	response := &proto.FetchAuthChallengeResponse{
+		Response: proto.FetchAuthChallengeResponseResponseCancelAuth,
+	}
+
+	h.mu.Lock()
+	h.attempts[e.RequestID]++
+	attempt := h.attempts[e.RequestID]
+	if attempt > 1 {
+		delete(h.attempts, e.RequestID)
+	}
+	h.mu.Unlock()
+
+	if attempt > 1 {
+		logger.Warn(ctx, "Credentials rejected by %s, cancelling authentication", e.AuthChallenge.Origin)
+		return response
+	}
+
+	if e.AuthChallenge.Source == proto.FetchAuthChallengeSourceProxy {
+		if h.proxyUsername != "" {
+			response.Response = proto.FetchAuthChallengeResponseResponseProvideCredentials
+			response.Username = h.proxyUsername
+			response.Password = h.proxyPassword
+		}
+	} else if creds := siteCredentialsFor(h.m.loadSiteConfigs(), e.Request.URL); creds != nil {
+		logger.Info(ctx, "Providing site credentials for %s", e.AuthChallenge.Origin)
+		response.Response = proto.FetchAuthChallengeResponseResponseProvideCredentials
+		response.Username = creds.Username
+		response.Password = creds.Password
+	} else {
+		// 没有匹配的凭据，交由浏览器默认处理
+		response.Response = proto.FetchAuthChallengeResponseResponseDefault
+	}
+	return response
+}
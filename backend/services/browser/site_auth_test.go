@@ -0,0 +1,44 @@
+package browser
+
+import (
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+func TestSiteCredentialsFor(t *testing.T) {
+	configs := []models.BrowserConfig{
+		{URLPattern: `^https://intranet\.example\.com/`, HTTPAuth: &models.HTTPAuthCredentials{Username: "alice", Password: "secret"}},
+		{URLPattern: `.*`},
+	}
+
+	if creds := siteCredentialsFor(configs, "https://intranet.example.com/wiki"); creds == nil || creds.Username != "alice" {
+		t.Errorf("expected credentials for intranet, got %+v", creds)
+	}
+	if creds := siteCredentialsFor(configs, "https://other.com/"); creds != nil {
+		t.Errorf("expected no credentials for other.com, got %+v", creds)
+	}
+}
+
+func TestClientCertificateRules(t *testing.T) {
+	if rules := clientCertificateRules([]models.BrowserConfig{{URLPattern: ".*"}}); len(rules) != 0 {
+		t.Errorf("expected no rules without client certificates, got %v", rules)
+	}
+
+	rules := clientCertificateRules([]models.BrowserConfig{
+		{ClientCert: &models.ClientCertificate{Pattern: "https://[*.]example.com", IssuerCN: "Example CA"}},
+		{ClientCert: &models.ClientCertificate{Pattern: "https://intranet.corp", SubjectCN: "alice"}},
+	})
+	want := []string{
+		`{"pattern":"https://[*.]example.com","filter":{"ISSUER":{"CN":"Example CA"}}}`,
+		`{"pattern":"https://intranet.corp","filter":{"SUBJECT":{"CN":"alice"}}}`,
+	}
+	if len(rules) != len(want) {
+		t.Fatalf("clientCertificateRules() = %v, want %v", rules, want)
+	}
+	for i := range want {
+		if rules[i] != want[i] {
+			t.Errorf("rule %d = %q, want %q", i, rules[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package browser
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/go-rod/stealth"
+)
+
+//go:embed scripts/stealth.js
+var stealthScript string
+
+// stealthOptions 返回页面使用的反检测选项，配置关闭 Stealth 模式时返回 nil
+// 配置未设置选项时沿用默认配置的设置，都未设置时使用 full
+func (m *Manager) stealthOptions(config *models.BrowserConfig) *models.StealthOptions {
+	if config != nil && config.UseStealth != nil && !*config.UseStealth {
+		return nil
+	}
+	if config != nil && config.Stealth != nil {
+		return config.Stealth
+	}
+	m.mu.RLock()
+	defaultConfig := m.defaultBrowserConfig
+	m.mu.RUnlock()
+	if defaultConfig != nil && defaultConfig.Stealth != nil {
+		return defaultConfig.Stealth
+	}
+	return &models.StealthOptions{}
+}
+
+// stealthPageScript 组合反检测选项对应的页面脚本
+func stealthPageScript(opts *models.StealthOptions) string {
+	params := map[string]interface{}{
+		"languages":   opts.Languages,
+		"blockWebRTC": opts.BlockWebRTC,
+	}
+	var prefix string
+	switch opts.Level {
+	case models.StealthLevelWebdriver:
+		params["webdriver"] = true
+	case models.StealthLevelBasic:
+		params["webdriver"] = true
+		params["basic"] = true
+	default:
+		// go-rod/stealth 已包含 webdriver、插件等全部补丁
+		prefix = stealth.JS + "\n"
+	}
+	data, _ := json.Marshal(params)
+	return prefix + ";(" + stealthScript + ")(" + string(data) + ");"
+}
+
+// newStealthPage 创建页面并在页面脚本执行前注入反检测补丁，opts 为 nil 时创建普通页面
+func newStealthPage(browser *rod.Browser, opts *models.StealthOptions) (*rod.Page, error) {
+	page, err := browser.Page(proto.TargetCreateTarget{})
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		return page, nil
+	}
+	if _, err := page.EvalOnNewDocument(stealthPageScript(opts)); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// userAgentOverride 页面的 User-Agent 覆盖，配置了伪装语言时同时设置 Accept-Language
+func userAgentOverride(userAgent string, opts *models.StealthOptions) *proto.NetworkSetUserAgentOverride {
+	override := &proto.NetworkSetUserAgentOverride{UserAgent: userAgent}
+	if opts != nil && len(opts.Languages) > 0 {
+		override.AcceptLanguage = strings.Join(opts.Languages, ",")
+	}
+	return override
+}
@@ -0,0 +1,51 @@
+package browser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/go-rod/stealth"
+)
+
+func TestStealthOptions(t *testing.T) {
+	off, on := false, true
+	m := &Manager{}
+	if opts := m.stealthOptions(&models.BrowserConfig{UseStealth: &off, Stealth: &models.StealthOptions{Level: models.StealthLevelBasic}}); opts != nil {
+		t.Errorf("stealth disabled but got options %+v", opts)
+	}
+	if opts := m.stealthOptions(&models.BrowserConfig{UseStealth: &on}); opts == nil || opts.Level != "" {
+		t.Errorf("expected default full options, got %+v", opts)
+	}
+
+	// 网站配置未设置时沿用默认配置
+	m.defaultBrowserConfig = &models.BrowserConfig{Stealth: &models.StealthOptions{Level: models.StealthLevelWebdriver}}
+	if opts := m.stealthOptions(&models.BrowserConfig{}); opts == nil || opts.Level != models.StealthLevelWebdriver {
+		t.Errorf("expected default configuration options, got %+v", opts)
+	}
+}
+
+func TestStealthPageScript(t *testing.T) {
+	full := stealthPageScript(&models.StealthOptions{BlockWebRTC: true})
+	if !strings.HasPrefix(full, stealth.JS) || !strings.Contains(full, `"blockWebRTC":true`) {
+		t.Errorf("full level should include go-rod/stealth and the WebRTC patch")
+	}
+
+	basic := stealthPageScript(&models.StealthOptions{Level: models.StealthLevelBasic, Languages: []string{"de-DE", "de"}})
+	if strings.Contains(basic, stealth.JS) {
+		t.Errorf("basic level should not include go-rod/stealth")
+	}
+	for _, want := range []string{`"basic":true`, `"webdriver":true`, `"languages":["de-DE","de"]`} {
+		if !strings.Contains(basic, want) {
+			t.Errorf("basic script missing %s", want)
+		}
+	}
+
+	override := userAgentOverride("UA", &models.StealthOptions{Languages: []string{"de-DE", "de"}})
+	if override.AcceptLanguage != "de-DE,de" {
+		t.Errorf("unexpected Accept-Language %q", override.AcceptLanguage)
+	}
+	if override := userAgentOverride("UA", nil); override.AcceptLanguage != "" {
+		t.Errorf("Accept-Language should not be set without stealth languages")
+	}
+}
@@ -0,0 +1,46 @@
+package browser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+// runLabels 返回脚本本次执行的标签：执行环境加上执行标签，统一转为小写
+func runLabels(script *models.Script) map[string]bool {
+	labels := make(map[string]bool, len(script.RunTags)+1)
+	if env := strings.TrimSpace(script.Environment); env != "" {
+		labels[strings.ToLower(env)] = true
+	}
+	for _, tag := range script.RunTags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			labels[strings.ToLower(tag)] = true
+		}
+	}
+	return labels
+}
+
+// matchLabels 返回 candidates 中第一个命中执行标签的值，没有命中时返回空字符串
+func matchLabels(candidates []string, labels map[string]bool) string {
+	for _, candidate := range candidates {
+		if labels[strings.ToLower(strings.TrimSpace(candidate))] {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// stepSkipReason 根据步骤开关判断是否跳过该步骤，返回跳过原因；需要执行时返回空字符串
+func stepSkipReason(action models.ScriptAction, labels map[string]bool) string {
+	if action.Disabled {
+		return "step is disabled"
+	}
+	if len(action.RunOn) > 0 && matchLabels(action.RunOn, labels) == "" {
+		return fmt.Sprintf("only runs on %s", strings.Join(action.RunOn, ", "))
+	}
+	if label := matchLabels(action.SkipOn, labels); label != "" {
+		return fmt.Sprintf("skipped on %s", label)
+	}
+	return ""
+}
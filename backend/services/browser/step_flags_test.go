@@ -0,0 +1,52 @@
+package browser
+
+import (
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+func TestStepSkipReason(t *testing.T) {
+	staging := runLabels(&models.Script{Environment: "Staging", RunTags: []string{"smoke"}})
+	none := runLabels(&models.Script{})
+
+	tests := []struct {
+		name   string
+		action models.ScriptAction
+		labels map[string]bool
+		skip   bool
+	}{
+		{"no flags", models.ScriptAction{}, staging, false},
+		{"disabled", models.ScriptAction{Disabled: true}, staging, true},
+		{"run on matching environment", models.ScriptAction{RunOn: []string{"staging"}}, staging, false},
+		{"run on matching tag", models.ScriptAction{RunOn: []string{"production", "SMOKE"}}, staging, false},
+		{"run on other environment", models.ScriptAction{RunOn: []string{"production"}}, staging, true},
+		{"run on without environment", models.ScriptAction{RunOn: []string{"staging"}}, none, true},
+		{"skip on matching environment", models.ScriptAction{SkipOn: []string{"staging"}}, staging, true},
+		{"skip on other environment", models.ScriptAction{SkipOn: []string{"production"}}, staging, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stepSkipReason(tt.action, tt.labels) != ""; got != tt.skip {
+				t.Errorf("skip = %v, want %v", got, tt.skip)
+			}
+		})
+	}
+}
+
+func TestExpandSubScriptsSkipsFlaggedCalls(t *testing.T) {
+	sub := &models.Script{ID: "sub", Actions: []models.ScriptAction{{Type: "click"}}}
+	parent := &models.Script{ID: "parent", Environment: "production", Actions: []models.ScriptAction{
+		{Type: "call_script", ScriptID: "sub", RunOn: []string{"staging"}},
+		{Type: "call_script", ScriptID: "missing", Disabled: true},
+		{Type: "call_script", ScriptID: "sub"},
+	}}
+
+	resolved, err := expandSubScripts(parent, scriptLookup(sub))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resolved.Actions) != 1 {
+		t.Errorf("expected only the unflagged call to be expanded, got %d steps", len(resolved.Actions))
+	}
+}
@@ -0,0 +1,133 @@
+package browser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+// maxSubScriptDepth call_script 允许的最大嵌套层数
+const maxSubScriptDepth = 8
+
+// hasSubScriptCalls 判断脚本是否包含 call_script 步骤
+func hasSubScriptCalls(script *models.Script) bool {
+	for _, action := range script.Actions {
+		if action.Type == "call_script" {
+			return true
+		}
+	}
+	return false
+}
+
+// expandSubScripts 把 call_script 步骤展开为被调用脚本的步骤，返回脚本副本，不修改原脚本
+// 子脚本在同一个页面和变量上下文中执行，其抓取的变量对调用方后续步骤可见；
+// 子脚本有 URL 时先导航到该 URL。调用链中出现重复脚本或嵌套过深时返回错误
+func expandSubScripts(script *models.Script, getScript func(id string) (*models.Script, error)) (*models.Script, error) {
+	resolved := script.Copy()
+	actions, err := expandActions(resolved, script.Actions, resolved.Variables, []string{script.ID}, getScript)
+	if err != nil {
+		return nil, err
+	}
+	resolved.Actions = actions
+	return resolved, nil
+}
+
+// expandActions 递归展开步骤列表，stack 为当前调用链上的脚本 ID
+// 子脚本的变量（默认值被调用参数覆盖后）补充到 root.Variables，供条件判断使用，不覆盖调用方已有的变量
+func expandActions(root *models.Script, actions []models.ScriptAction, variables map[string]string, stack []string, getScript func(id string) (*models.Script, error)) ([]models.ScriptAction, error) {
+	expanded := make([]models.ScriptAction, 0, len(actions))
+	for i, action := range actions {
+		if action.Type != "call_script" {
+			expanded = append(expanded, action)
+			continue
+		}
+
+		// 执行环境在展开前已确定，被跳过的调用直接去掉，不加载子脚本
+		if stepSkipReason(action, runLabels(root)) != "" {
+			continue
+		}
+		if action.ScriptID == "" {
+			return nil, fmt.Errorf("step %d: call_script requires script_id", i+1)
+		}
+		for _, id := range stack {
+			if id == action.ScriptID {
+				return nil, fmt.Errorf("step %d: recursive script call: %s -> %s", i+1, strings.Join(stack, " -> "), action.ScriptID)
+			}
+		}
+		if len(stack) >= maxSubScriptDepth {
+			return nil, fmt.Errorf("step %d: script calls nested deeper than %d levels", i+1, maxSubScriptDepth)
+		}
+
+		sub, err := getScript(action.ScriptID)
+		if err != nil {
+			return nil, fmt.Errorf("step %d: failed to load script %s: %w", i+1, action.ScriptID, err)
+		}
+
+		// 子脚本变量：预设变量被调用参数覆盖，参数值中的占位符按调用方变量替换
+		subVariables := make(map[string]string, len(sub.Variables)+len(action.ScriptParams))
+		for k, v := range sub.Variables {
+			subVariables[k] = v
+		}
+		for k, v := range action.ScriptParams {
+			subVariables[k] = expandVariables(v, variables)
+		}
+		for k, v := range subVariables {
+			if _, exists := root.Variables[k]; !exists {
+				root.Variables[k] = v
+			}
+		}
+
+		subActions := make([]models.ScriptAction, 0, len(sub.Actions)+1)
+		subURL := expandVariables(sub.URL, subVariables)
+		if u := subVariables["url"]; u != "" {
+			subURL = u
+		}
+		if subURL != "" {
+			subActions = append(subActions, models.ScriptAction{
+				Type:        "navigate",
+				URL:         subURL,
+				Description: fmt.Sprintf("Open %s (called script)", sub.Name),
+			})
+		}
+		for _, subAction := range sub.Actions {
+			subActions = append(subActions, expandActionVariables(subAction, subVariables))
+		}
+
+		subActions, err = expandActions(root, subActions, subVariables, append(stack, sub.ID), getScript)
+		if err != nil {
+			return nil, fmt.Errorf("step %d (%s): %w", i+1, sub.Name, err)
+		}
+
+		// 调用步骤上的条件作用于子脚本的每个步骤
+		if action.Condition != nil && action.Condition.Enabled {
+			for j := range subActions {
+				if subActions[j].Condition != nil && subActions[j].Condition.Enabled {
+					return nil, fmt.Errorf("step %d: conditional call_script cannot include steps that have their own condition (%s)", i+1, sub.Name)
+				}
+				subActions[j].Condition = action.Condition
+			}
+		}
+		expanded = append(expanded, subActions...)
+	}
+	return expanded, nil
+}
+
+// expandActionVariables 替换步骤中的 ${变量名} 占位符，替换范围与脚本执行参数相同
+func expandActionVariables(action models.ScriptAction, variables map[string]string) models.ScriptAction {
+	action.Selector = expandVariables(action.Selector, variables)
+	action.XPath = expandVariables(action.XPath, variables)
+	action.TargetSelector = expandVariables(action.TargetSelector, variables)
+	action.TargetXPath = expandVariables(action.TargetXPath, variables)
+	action.Value = expandVariables(action.Value, variables)
+	action.URL = expandVariables(action.URL, variables)
+	action.JSCode = expandVariables(action.JSCode, variables)
+	if len(action.FilePaths) > 0 {
+		filePaths := make([]string, len(action.FilePaths))
+		for i, path := range action.FilePaths {
+			filePaths[i] = expandVariables(path, variables)
+		}
+		action.FilePaths = filePaths
+	}
+	return action
+}
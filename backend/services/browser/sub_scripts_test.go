@@ -0,0 +1,110 @@
+package browser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+func scriptLookup(scripts ...*models.Script) func(id string) (*models.Script, error) {
+	return func(id string) (*models.Script, error) {
+		for _, s := range scripts {
+			if s.ID == id {
+				return s, nil
+			}
+		}
+		return nil, fmt.Errorf("script not found")
+	}
+}
+
+func TestExpandSubScripts(t *testing.T) {
+	login := &models.Script{
+		ID:        "login",
+		Name:      "Login",
+		URL:       "https://example.com/login",
+		Variables: map[string]string{"username": "default", "password": "secret"},
+		Actions: []models.ScriptAction{
+			{Type: "input", Selector: "#user", Value: "${username}"},
+			{Type: "input", Selector: "#pass", Value: "${password}"},
+			{Type: "extract_text", Selector: ".token", VariableName: "token"},
+		},
+	}
+	parent := &models.Script{
+		ID:        "parent",
+		Variables: map[string]string{"account": "alice"},
+		Actions: []models.ScriptAction{
+			{Type: "call_script", ScriptID: "login", ScriptParams: map[string]string{"username": "${account}"}},
+			{Type: "navigate", URL: "https://example.com/orders?token=${token}"},
+		},
+	}
+
+	resolved, err := expandSubScripts(parent, scriptLookup(login))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []string
+	for _, a := range resolved.Actions {
+		got = append(got, a.Type+" "+a.URL+a.Value)
+	}
+	want := []string{
+		"navigate https://example.com/login",
+		"input alice",
+		"input secret",
+		"extract_text ",
+		"navigate https://example.com/orders?token=${token}",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expanded actions:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if resolved.Variables["username"] != "alice" || resolved.Variables["account"] != "alice" {
+		t.Errorf("unexpected variables: %v", resolved.Variables)
+	}
+	if len(parent.Actions) != 2 || len(parent.Variables) != 1 {
+		t.Error("original script must not be modified")
+	}
+}
+
+func TestExpandSubScriptsRecursion(t *testing.T) {
+	a := &models.Script{ID: "a", Actions: []models.ScriptAction{{Type: "call_script", ScriptID: "b"}}}
+	b := &models.Script{ID: "b", Actions: []models.ScriptAction{{Type: "call_script", ScriptID: "a"}}}
+
+	_, err := expandSubScripts(a, scriptLookup(a, b))
+	if err == nil || !strings.Contains(err.Error(), "a -> b -> a") {
+		t.Errorf("expected recursion error, got %v", err)
+	}
+
+	// 同一个子脚本可以被多次调用
+	c := &models.Script{ID: "c", Actions: []models.ScriptAction{{Type: "click", Selector: "#ok"}}}
+	d := &models.Script{ID: "d", Actions: []models.ScriptAction{
+		{Type: "call_script", ScriptID: "c"},
+		{Type: "call_script", ScriptID: "c"},
+	}}
+	resolved, err := expandSubScripts(d, scriptLookup(c, d))
+	if err != nil || len(resolved.Actions) != 2 {
+		t.Errorf("repeated calls: actions=%v err=%v", resolved, err)
+	}
+}
+
+func TestExpandSubScriptsCondition(t *testing.T) {
+	cond := &models.ActionCondition{Variable: "logged_in", Operator: "not_exists", Enabled: true}
+	sub := &models.Script{ID: "sub", Actions: []models.ScriptAction{{Type: "click"}, {Type: "click"}}}
+	parent := &models.Script{ID: "parent", Actions: []models.ScriptAction{{Type: "call_script", ScriptID: "sub", Condition: cond}}}
+
+	resolved, err := expandSubScripts(parent, scriptLookup(sub))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, a := range resolved.Actions {
+		if a.Condition != cond {
+			t.Errorf("step %d should inherit the call condition", i+1)
+		}
+	}
+
+	sub.Actions[1].Condition = &models.ActionCondition{Variable: "x", Operator: "exists", Enabled: true}
+	if _, err := expandSubScripts(parent, scriptLookup(sub)); err == nil {
+		t.Error("expected error when both the call and a sub step have conditions")
+	}
+}
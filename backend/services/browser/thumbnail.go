@@ -0,0 +1,162 @@
+package browser
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// Thumbnail 实例活动页面的缩略图
+type Thumbnail struct {
+	InstanceID string    `json:"instance_id"`
+	URL        string    `json:"url"`
+	Title      string    `json:"title"`
+	Width      int       `json:"width"`
+	Height     int       `json:"height"`
+	Data       []byte    `json:"data"` // JPEG 图片（JSON 中为 base64）
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// ThumbnailOptions 缩略图选项
+type ThumbnailOptions struct {
+	MaxWidth int // 最大宽度（像素，默认 320）
+	Quality  int // JPEG 质量 1-100（默认 60）
+}
+
+// normalize 填充默认值并限制范围
+func (o *ThumbnailOptions) normalize() {
+	if o.MaxWidth <= 0 {
+		o.MaxWidth = 320
+	}
+	if o.MaxWidth > 1280 {
+		o.MaxWidth = 1280
+	}
+	if o.Quality <= 0 || o.Quality > 100 {
+		o.Quality = 60
+	}
+}
+
+// thumbnailPage 获取实例的活动页面，实例未运行时返回错误（不会自动启动实例）
+func (m *Manager) thumbnailPage(instanceID string) (*rod.Page, string, error) {
+	m.mu.RLock()
+	if instanceID == "" {
+		instanceID = m.currentInstanceID
+	}
+	runtime := m.instances[instanceID]
+	var page *rod.Page
+	var browser *rod.Browser
+	if runtime != nil {
+		page = runtime.activePage
+		browser = runtime.browser
+	}
+	m.mu.RUnlock()
+
+	if runtime == nil || browser == nil {
+		return nil, instanceID, fmt.Errorf("instance %s is not running", instanceID)
+	}
+	if page != nil {
+		return page, instanceID, nil
+	}
+
+	pages, err := browser.Pages()
+	if err != nil {
+		return nil, instanceID, fmt.Errorf("failed to list pages: %w", err)
+	}
+	if len(pages) == 0 {
+		return nil, instanceID, fmt.Errorf("instance %s has no open page", instanceID)
+	}
+	return pages.First(), instanceID, nil
+}
+
+// CaptureThumbnail 截取实例活动页面当前视口的缩略图，由浏览器直接按比例缩小后编码为 JPEG
+func (m *Manager) CaptureThumbnail(ctx context.Context, instanceID string, opts ThumbnailOptions) (*Thumbnail, error) {
+	opts.normalize()
+	page, instanceID, err := m.thumbnailPage(instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 避免后台标签页截图长时间无响应
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	page = page.Context(ctx)
+
+	metrics, err := proto.PageGetLayoutMetrics{}.Call(page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get layout metrics: %w", err)
+	}
+	viewport := metrics.CSSVisualViewport
+	if viewport == nil || viewport.ClientWidth <= 0 || viewport.ClientHeight <= 0 {
+		return nil, fmt.Errorf("page viewport is not available")
+	}
+
+	scale := float64(opts.MaxWidth) / viewport.ClientWidth
+	if scale > 1 {
+		scale = 1
+	}
+	shot, err := proto.PageCaptureScreenshot{
+		Format:  proto.PageCaptureScreenshotFormatJpeg,
+		Quality: &opts.Quality,
+		Clip: &proto.PageViewport{
+			X:      viewport.PageX,
+			Y:      viewport.PageY,
+			Width:  viewport.ClientWidth,
+			Height: viewport.ClientHeight,
+			Scale:  scale,
+		},
+	}.Call(page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture thumbnail: %w", err)
+	}
+
+	thumb := &Thumbnail{
+		InstanceID: instanceID,
+		Width:      int(viewport.ClientWidth * scale),
+		Height:     int(viewport.ClientHeight * scale),
+		Data:       shot.Data,
+		Timestamp:  time.Now(),
+	}
+	if info, err := page.Info(); err == nil {
+		thumb.URL = info.URL
+		thumb.Title = info.Title
+	}
+	return thumb, nil
+}
+
+// StreamThumbnails 按固定间隔截取缩略图并通过 send 推送，画面未变化时跳过
+// 直到 ctx 取消、send 返回错误或实例停止运行
+func (m *Manager) StreamThumbnails(ctx context.Context, instanceID string, interval time.Duration, opts ThumbnailOptions, send func(*Thumbnail) error) error {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last *Thumbnail
+	for {
+		thumb, err := m.CaptureThumbnail(ctx, instanceID, opts)
+		if err != nil {
+			if _, _, pageErr := m.thumbnailPage(instanceID); pageErr != nil {
+				return pageErr
+			}
+			// 页面跳转等情况下截图可能暂时失败，下一次继续
+			logger.Debug(ctx, "Failed to capture thumbnail for instance %s: %v", instanceID, err)
+		} else if last == nil || !bytes.Equal(last.Data, thumb.Data) || last.URL != thumb.URL || last.Title != thumb.Title {
+			if err := send(thumb); err != nil {
+				return err
+			}
+			last = thumb
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
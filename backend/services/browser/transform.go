@@ -0,0 +1,119 @@
+package browser
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/jmespath/go-jmespath"
+)
+
+const (
+	// transformTimeout 后处理 JavaScript 的最长执行时间
+	transformTimeout = 30 * time.Second
+	// transformResultKey 后处理结果不是对象时，放在抓取数据的这个字段中
+	transformResultKey = "result"
+)
+
+// transformTemplateFuncs 后处理模板可用的函数，json 把值输出为 JSON
+var transformTemplateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// ValidateTransform 检查后处理设置：类型是否支持，模板和 JMESPath 表达式能否解析
+func ValidateTransform(t *models.DataTransform) error {
+	if !t.Enabled() {
+		return nil
+	}
+	switch t.Type {
+	case models.TransformTemplate:
+		_, err := template.New("transform").Funcs(transformTemplateFuncs).Parse(t.Expression)
+		return err
+	case models.TransformJMESPath:
+		_, err := jmespath.Compile(t.Expression)
+		return err
+	case models.TransformJS:
+		return nil
+	default:
+		return fmt.Errorf("unsupported transform type %q", t.Type)
+	}
+}
+
+// evalTransform 执行模板或 JMESPath 后处理，data 为转换为通用 JSON 值的抓取数据
+func evalTransform(t *models.DataTransform, data interface{}) (interface{}, error) {
+	switch t.Type {
+	case models.TransformTemplate:
+		tmpl, err := template.New("transform").Funcs(transformTemplateFuncs).Parse(t.Expression)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, err
+		}
+		var result interface{}
+		if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+			return nil, fmt.Errorf("template output is not valid JSON: %w", err)
+		}
+		return result, nil
+	case models.TransformJMESPath:
+		return jmespath.Search(t.Expression, data)
+	default:
+		return nil, fmt.Errorf("unsupported transform type %q", t.Type)
+	}
+}
+
+// evalJSTransform 在新建的空白页面中执行后处理 JavaScript，页面禁止网络请求，不能访问被抓取站点的 Cookie 和页面
+func evalJSTransform(ctx context.Context, browser *rod.Browser, code string, data interface{}) (interface{}, error) {
+	page, err := browser.Page(proto.TargetCreateTarget{URL: "about:blank"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transform page: %w", err)
+	}
+	defer page.Close()
+
+	if err := (proto.NetworkEnable{}).Call(page); err != nil {
+		return nil, fmt.Errorf("failed to isolate transform page: %w", err)
+	}
+	if err := page.SetBlockedURLs([]string{"*"}); err != nil {
+		return nil, fmt.Errorf("failed to isolate transform page: %w", err)
+	}
+
+	res, err := page.Context(ctx).Timeout(transformTimeout).Eval("(data) => { "+code+" }", data)
+	if err != nil {
+		return nil, err
+	}
+	return normalizeExtracted(res.Value), nil
+}
+
+// transformExtractedData 按脚本的后处理设置变形抓取数据，结果不是对象时放在 result 字段中
+func transformExtractedData(ctx context.Context, browser *rod.Browser, t *models.DataTransform, extracted map[string]interface{}) (map[string]interface{}, error) {
+	if extracted == nil {
+		extracted = map[string]interface{}{}
+	}
+	data := normalizeExtracted(extracted)
+
+	var result interface{}
+	var err error
+	if t.Type == models.TransformJS {
+		result, err = evalJSTransform(ctx, browser, t.Expression, data)
+	} else {
+		result, err = evalTransform(t, data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("transform (%s) failed: %w", t.Type, err)
+	}
+
+	if obj, ok := result.(map[string]interface{}); ok {
+		return obj, nil
+	}
+	return map[string]interface{}{transformResultKey: result}, nil
+}
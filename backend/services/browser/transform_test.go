@@ -0,0 +1,67 @@
+package browser
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+func TestTransformExtractedData(t *testing.T) {
+	extracted := map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"id": 1, "title": "a", "price": 5},
+			map[string]interface{}{"id": 2, "title": "b", "price": 20},
+		},
+		"page": "1",
+	}
+
+	for _, tc := range []struct {
+		name      string
+		transform models.DataTransform
+		want      map[string]interface{}
+	}{
+		{
+			name:      "jmespath object",
+			transform: models.DataTransform{Type: models.TransformJMESPath, Expression: "{cheap: items[?price < `10`].title, page: page}"},
+			want:      map[string]interface{}{"cheap": []interface{}{"a"}, "page": "1"},
+		},
+		{
+			name:      "jmespath list is wrapped",
+			transform: models.DataTransform{Type: models.TransformJMESPath, Expression: "items[*].id"},
+			want:      map[string]interface{}{"result": []interface{}{float64(1), float64(2)}},
+		},
+		{
+			name:      "template",
+			transform: models.DataTransform{Type: models.TransformTemplate, Expression: `{"titles": [{{range $i, $item := .items}}{{if $i}},{{end}}{{json $item.title}}{{end}}], "count": {{len .items}}}`},
+			want:      map[string]interface{}{"titles": []interface{}{"a", "b"}, "count": float64(2)},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := ValidateTransform(&tc.transform); err != nil {
+				t.Fatalf("unexpected validation error: %v", err)
+			}
+			got, err := transformExtractedData(context.Background(), nil, &tc.transform, extracted)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+
+	if _, err := transformExtractedData(context.Background(), nil, &models.DataTransform{Type: models.TransformTemplate, Expression: "{{.page}}x"}, extracted); err == nil {
+		t.Error("template output that is not JSON should fail")
+	}
+	for _, invalid := range []models.DataTransform{
+		{Type: models.TransformJMESPath, Expression: "items[?"},
+		{Type: models.TransformTemplate, Expression: "{{.items"},
+		{Type: "jq", Expression: "."},
+	} {
+		if err := ValidateTransform(&invalid); err == nil {
+			t.Errorf("transform %+v should be rejected", invalid)
+		}
+	}
+}
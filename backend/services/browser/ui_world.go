@@ -0,0 +1,105 @@
+package browser
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/cdp"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// uiWorldName 录制脚本和浮动按钮所在隔离环境的名称（在 DevTools 的上下文列表中可见）
+const uiWorldName = "BrowserWing"
+
+// maxUIWorlds 缓存的隔离环境数量上限，超出时丢弃最早创建的
+const maxUIWorlds = 256
+
+// mainWorldInjection 为 true 时录制脚本和浮动按钮注入页面主环境
+var mainWorldInjection atomic.Bool
+
+// SetMainWorldInjection 设置录制脚本和浮动按钮是否注入页面主环境
+func SetMainWorldInjection(enabled bool) {
+	mainWorldInjection.Store(enabled)
+}
+
+// uiWorlds 各 frame 的隔离环境全局对象
+// 隔离环境与页面共享 DOM，但拥有独立的 JavaScript 全局对象：页面的全局变量、对原生 API 的改写
+// 和录制脚本互不可见。页面导航后隔离环境随文档销毁，下次执行时重新创建
+var uiWorlds = struct {
+	sync.Mutex
+	byFrame map[proto.PageFrameID]*proto.RuntimeRemoteObject
+	order   []proto.PageFrameID
+}{byFrame: make(map[proto.PageFrameID]*proto.RuntimeRemoteObject)}
+
+// uiWorld 获取 frame 的隔离环境全局对象，fresh 为 true 时重新创建
+func uiWorld(page *rod.Page, fresh bool) (*proto.RuntimeRemoteObject, error) {
+	uiWorlds.Lock()
+	defer uiWorlds.Unlock()
+
+	if world, ok := uiWorlds.byFrame[page.FrameID]; ok && !fresh {
+		return world, nil
+	}
+
+	created, err := proto.PageCreateIsolatedWorld{
+		FrameID:             page.FrameID,
+		WorldName:           uiWorldName,
+		GrantUniveralAccess: true,
+	}.Call(page)
+	if err != nil {
+		return nil, err
+	}
+	// 标记隔离环境，录制脚本据此改为通过 DOM 事件接收主环境拦截到的 XHR 请求
+	res, err := proto.RuntimeEvaluate{
+		Expression: `globalThis.__browserwingIsolatedWorld__ = true, globalThis`,
+		ContextID:  created.ExecutionContextID,
+	}.Call(page)
+	if err != nil {
+		return nil, err
+	}
+	if res.ExceptionDetails != nil {
+		return nil, &rod.EvalError{RuntimeExceptionDetails: res.ExceptionDetails}
+	}
+
+	if _, ok := uiWorlds.byFrame[page.FrameID]; !ok {
+		uiWorlds.order = append(uiWorlds.order, page.FrameID)
+		if len(uiWorlds.order) > maxUIWorlds {
+			delete(uiWorlds.byFrame, uiWorlds.order[0])
+			uiWorlds.order = uiWorlds.order[1:]
+		}
+	}
+	uiWorlds.byFrame[page.FrameID] = res.Result
+	return res.Result, nil
+}
+
+// isStaleWorldErr 判断错误是否由隔离环境已销毁（页面导航、frame 移除）导致
+func isStaleWorldErr(err error) bool {
+	var notFound *rod.ObjectNotFoundError
+	return errors.As(err, &notFound) ||
+		errors.Is(err, cdp.ErrObjNotFound) ||
+		errors.Is(err, cdp.ErrCtxNotFound) ||
+		errors.Is(err, cdp.ErrCtxDestroyed)
+}
+
+// uiEval 在页面的隔离环境中执行录制器和浮动按钮相关的脚本，用法与 page.Eval 相同
+// 隔离环境已销毁时重新创建并重试一次；无法创建隔离环境（例如跨进程 iframe）或配置了
+// main_world_injection 时在页面主环境中执行
+func uiEval(page *rod.Page, js string, args ...interface{}) (*proto.RuntimeRemoteObject, error) {
+	if mainWorldInjection.Load() {
+		return page.Eval(js, args...)
+	}
+
+	world, err := uiWorld(page, false)
+	if err != nil {
+		return page.Eval(js, args...)
+	}
+	res, err := page.Evaluate(rod.Eval(js, args...).ByPromise().This(world))
+	if err != nil && isStaleWorldErr(err) {
+		if world, err = uiWorld(page, true); err != nil {
+			return page.Eval(js, args...)
+		}
+		res, err = page.Evaluate(rod.Eval(js, args...).ByPromise().This(world))
+	}
+	return res, err
+}
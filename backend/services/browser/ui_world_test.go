@@ -0,0 +1,37 @@
+package browser
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/cdp"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+func TestIsStaleWorldErr(t *testing.T) {
+	stale := []error{
+		&rod.ObjectNotFoundError{RuntimeRemoteObject: &proto.RuntimeRemoteObject{}},
+		fmt.Errorf("eval: %w", &cdp.Error{Code: -32000, Message: "Could not find object with given id"}),
+		&cdp.Error{Code: -32000, Message: "Cannot find context with specified id"},
+		&cdp.Error{Code: -32000, Message: "Execution context was destroyed."},
+	}
+	for _, err := range stale {
+		if !isStaleWorldErr(err) {
+			t.Errorf("expected stale: %v", err)
+		}
+	}
+
+	// 脚本本身抛出的异常不应触发重建隔离环境后重试
+	notStale := []error{
+		&rod.EvalError{RuntimeExceptionDetails: &proto.RuntimeExceptionDetails{Text: "Uncaught"}},
+		&cdp.Error{Code: -32000, Message: "No node found at given location"},
+		errors.New("timeout"),
+	}
+	for _, err := range notStale {
+		if isStaleWorldErr(err) {
+			t.Errorf("expected not stale: %v", err)
+		}
+	}
+}
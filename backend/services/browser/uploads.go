@@ -0,0 +1,145 @@
+package browser
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/artifacts"
+	"github.com/google/uuid"
+)
+
+// uploadHandlesDir 通过上传接口保存的文件在上传目录中的子目录，每个文件放在以 ID 命名的目录下
+const uploadHandlesDir = "handles"
+
+// SaveUpload 将上传的文件保存到上传目录，返回可在 file_paths 中引用的句柄
+func (m *Manager) SaveUpload(name string, r io.Reader) (*models.UploadedFile, error) {
+	sandbox, err := m.UploadSandbox()
+	if err != nil {
+		return nil, err
+	}
+	id := uuid.New().String()
+	dir, err := sandbox.Dir(uploadHandlesDir, id)
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(dir, artifacts.SanitizeFileName(name, "file"))
+	out, err := os.Create(path)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to create upload file: %w", err)
+	}
+	_, err = io.Copy(out, r)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to save upload file: %w", err)
+	}
+	return uploadedFile(id, path)
+}
+
+// ListUploads 列出通过上传接口保存的文件，最新的在前
+func (m *Manager) ListUploads() ([]*models.UploadedFile, error) {
+	sandbox, err := m.UploadSandbox()
+	if err != nil {
+		return nil, err
+	}
+	root, err := sandbox.Resolve(uploadHandlesDir)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return []*models.UploadedFile{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list uploads: %w", err)
+	}
+
+	files := make([]*models.UploadedFile, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		path, err := m.uploadPath(entry.Name())
+		if err != nil {
+			continue
+		}
+		if file, err := uploadedFile(entry.Name(), path); err == nil {
+			files = append(files, file)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].CreatedAt.After(files[j].CreatedAt)
+	})
+	return files, nil
+}
+
+// DeleteUpload 删除上传的文件
+func (m *Manager) DeleteUpload(id string) error {
+	path, err := m.uploadPath(id)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(filepath.Dir(path))
+}
+
+// ResolveUploadPaths 将 file_paths 中的上传文件句柄（upload:<id>）解析为本地路径，其他路径原样返回
+func (m *Manager) ResolveUploadPaths(paths []string) ([]string, error) {
+	resolved := make([]string, len(paths))
+	for i, path := range paths {
+		id, ok := strings.CutPrefix(path, models.UploadHandlePrefix)
+		if !ok {
+			resolved[i] = path
+			continue
+		}
+		local, err := m.uploadPath(id)
+		if err != nil {
+			return nil, err
+		}
+		resolved[i] = local
+	}
+	return resolved, nil
+}
+
+// uploadPath 根据 ID 找到上传的文件
+func (m *Manager) uploadPath(id string) (string, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return "", fmt.Errorf("invalid upload handle %q", models.UploadHandlePrefix+id)
+	}
+	sandbox, err := m.UploadSandbox()
+	if err != nil {
+		return "", err
+	}
+	dir, err := sandbox.Resolve(uploadHandlesDir, id)
+	if err != nil {
+		return "", err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 || entries[0].IsDir() {
+		return "", fmt.Errorf("upload %q not found", models.UploadHandlePrefix+id)
+	}
+	return filepath.Join(dir, entries[0].Name()), nil
+}
+
+// uploadedFile 根据保存的文件生成上传文件信息
+func uploadedFile(id, path string) (*models.UploadedFile, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat upload file: %w", err)
+	}
+	return &models.UploadedFile{
+		ID:        id,
+		Handle:    models.UploadHandlePrefix + id,
+		Name:      info.Name(),
+		Size:      info.Size(),
+		CreatedAt: info.ModTime(),
+	}, nil
+}
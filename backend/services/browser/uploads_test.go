@@ -0,0 +1,59 @@
+package browser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/browserwing/browserwing/config"
+)
+
+func TestUploads(t *testing.T) {
+	root := t.TempDir()
+	m := &Manager{config: &config.Config{Storage: &config.StorageConfig{UploadsDir: root}}}
+
+	// 同名文件各自保存，文件名去掉目录部分
+	a, err := m.SaveUpload("../invoice.pdf", strings.NewReader("first"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := m.SaveUpload("invoice.pdf", strings.NewReader("second"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Handle != "upload:"+a.ID || a.Name != "invoice.pdf" || a.Size != 5 || a.ID == b.ID {
+		t.Fatalf("unexpected uploads %+v %+v", a, b)
+	}
+
+	// 句柄解析为上传目录中的文件，其他路径原样返回
+	paths, err := m.ResolveUploadPaths([]string{b.Handle, "/data/local.txt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := os.ReadFile(paths[0]); string(content) != "second" || paths[1] != "/data/local.txt" {
+		t.Errorf("unexpected resolved paths %v", paths)
+	}
+	if !strings.HasPrefix(paths[0], filepath.Join(root, uploadHandlesDir)) {
+		t.Errorf("upload should be stored under the uploads root, got %s", paths[0])
+	}
+	for _, handle := range []string{"upload:../../etc", "upload:00000000-0000-0000-0000-000000000000"} {
+		if _, err := m.ResolveUploadPaths([]string{handle}); err == nil {
+			t.Errorf("handle %s should not resolve", handle)
+		}
+	}
+
+	files, err := m.ListUploads()
+	if err != nil || len(files) != 2 {
+		t.Fatalf("expected 2 uploads, got %v (%v)", files, err)
+	}
+	if err := m.DeleteUpload(a.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.ResolveUploadPaths([]string{a.Handle}); err == nil {
+		t.Error("deleted upload should not resolve")
+	}
+	if files, _ := m.ListUploads(); len(files) != 1 || files[0].ID != b.ID {
+		t.Errorf("expected only the second upload, got %v", files)
+	}
+}
@@ -0,0 +1,139 @@
+package browser
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/pkg/urlpolicy"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// CheckURLPolicy 检查 URL 是否同时满足实例策略和调用方（context 中）的策略
+// instanceID: 指定实例ID，空字符串表示使用当前实例
+func (m *Manager) CheckURLPolicy(ctx context.Context, instanceID string, rawURL string) error {
+	m.mu.RLock()
+	instance := m.lookupInstanceLocked(instanceID)
+	m.mu.RUnlock()
+
+	return m.checkURLPolicy(ctx, instance, rawURL)
+}
+
+// NetworkGuard 获取内网访问防护（用于检查服务端发起的请求，例如 Webhook）
+func (m *Manager) NetworkGuard() *urlpolicy.NetworkGuard {
+	return m.netGuard
+}
+
+// checkURLPolicy 检查 URL 访问策略，不需要持有锁
+func (m *Manager) checkURLPolicy(ctx context.Context, instance *models.BrowserInstance, rawURL string) error {
+	var instancePolicy *urlpolicy.Policy
+	if instance != nil {
+		instancePolicy = instance.URLPolicy
+	}
+
+	if err := urlpolicy.Merge(instancePolicy, urlpolicy.FromContext(ctx)).Check(rawURL); err != nil {
+		logger.Warn(ctx, "Blocked access to %s: %v", rawURL, err)
+		return models.WithErrorCode(models.ErrorCodeNavigationBlocked, err)
+	}
+
+	// 内网访问防护
+	if err := m.netGuard.Check(ctx, rawURL); err != nil {
+		logger.Warn(ctx, "Blocked access to %s: %v", rawURL, err)
+		return models.WithErrorCode(models.ErrorCodeNavigationBlocked, err)
+	}
+	return nil
+}
+
+// interceptBrowserRequests 在浏览器级别启用 Fetch 拦截，由同一个处理器完成内网访问防护、广告拦截和认证应答
+// 浏览器级别的拦截覆盖所有页面，包括弹出窗口、新标签页以及页面内脚本（包括 Evaluate 执行的代码）
+// 发起的 fetch/XHR、子资源和跳转；每个暂停的请求只会被应答一次
+func (m *Manager) interceptBrowserRequests(ctx context.Context, browser *rod.Browser, proxyUsername, proxyPassword string) {
+	guard := m.netGuard.Enabled()
+	auth := m.newAuthChallengeHandler(proxyUsername, proxyPassword)
+
+	// 广告拦截按浏览器配置开启，配置在浏览器启动时读取
+	var adRules *adBlockRules
+	if configs := m.loadSiteConfigs(); anyAdBlocking(configs) {
+		adRules = newAdBlockRules(configs)
+		m.loadAdBlocker(ctx)
+	}
+	if !guard && auth == nil && adRules == nil {
+		return
+	}
+
+	enable := proto.FetchEnable{HandleAuthRequests: auth != nil}
+	if guard || adRules != nil {
+		enable.Patterns = []*proto.FetchRequestPattern{{URLPattern: "*"}}
+	}
+	if err := enable.Call(browser); err != nil {
+		logger.Warn(ctx, "Failed to enable request interception: %v", err)
+		return
+	}
+	logger.Info(ctx, "Request interception enabled (network guard: %v, ad blocking: %v, auth handling: %v)", guard, adRules != nil, auth != nil)
+
+	go browser.EachEvent(
+		func(e *proto.FetchRequestPaused) {
+			go m.handlePausedRequest(ctx, browser, e, adRules)
+		},
+		func(e *proto.FetchAuthRequired) {
+			response := &proto.FetchAuthChallengeResponse{
+				Response: proto.FetchAuthChallengeResponseResponseDefault,
+			}
+			if auth != nil {
+				response = auth.respond(ctx, e)
+			}
+			_ = proto.FetchContinueWithAuth{
+				RequestID:             e.RequestID,
+				AuthChallengeResponse: response,
+			}.Call(browser)
+		},
+	)()
+}
+
+// handlePausedRequest 应答一个暂停的请求：指向内网的请求和命中过滤规则的广告请求直接失败，其余请求放行
+// adRules 为 nil 表示未开启广告拦截
+func (m *Manager) handlePausedRequest(ctx context.Context, browser *rod.Browser, e *proto.FetchRequestPaused, adRules *adBlockRules) {
+	if u, err := url.Parse(e.Request.URL); err == nil && m.netGuard.IsInternalHost(ctx, u.Hostname()) {
+		logger.Warn(ctx, "Blocked request to internal network: %s", e.Request.URL)
+		m.failPausedRequest(browser, e)
+		return
+	}
+	if adRules != nil && m.shouldBlockAd(adRules, e) {
+		m.failPausedRequest(browser, e)
+		return
+	}
+	_ = proto.FetchContinueRequest{RequestID: e.RequestID}.Call(browser)
+}
+
+// failPausedRequest 以“被客户端拦截”的原因结束暂停的请求
+func (m *Manager) failPausedRequest(browser *rod.Browser, e *proto.FetchRequestPaused) {
+	_ = proto.FetchFailRequest{
+		RequestID:   e.RequestID,
+		ErrorReason: proto.NetworkErrorReasonBlockedByClient,
+	}.Call(browser)
+}
+
+// lookupInstanceLocked 获取实例配置（优先使用运行时信息），调用者必须已持有锁（读锁即可）
+func (m *Manager) lookupInstanceLocked(instanceID string) *models.BrowserInstance {
+	if instanceID == "" {
+		instanceID = m.currentInstanceID
+	}
+	if instanceID == "" {
+		return nil
+	}
+
+	if runtime, exists := m.instances[instanceID]; exists && runtime != nil {
+		return runtime.instance
+	}
+
+	if m.db == nil {
+		return nil
+	}
+	instance, err := m.db.GetBrowserInstance(instanceID)
+	if err != nil {
+		return nil
+	}
+	return instance
+}
@@ -0,0 +1,239 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/launcher/flags"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// screenRect 显示器的可用区域（屏幕坐标）
+type screenRect struct {
+	Left   int `json:"left"`
+	Top    int `json:"top"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// detectScreensJS 获取所有显示器的可用区域，不支持 Window Management API 时只返回当前显示器
+const detectScreensJS = `async () => {
+	const current = [{ left: screen.availLeft || 0, top: screen.availTop || 0, width: screen.availWidth, height: screen.availHeight }];
+	if (!('getScreenDetails' in window)) return { screens: current, error: 'Window Management API not supported' };
+	try {
+		const details = await window.getScreenDetails();
+		const screens = details.screens.map((s) => ({ left: s.availLeft, top: s.availTop, width: s.availWidth, height: s.availHeight, primary: s.isPrimary }));
+		screens.sort((a, b) => (b.primary ? 1 : 0) - (a.primary ? 1 : 0));
+		return { screens };
+	} catch (e) {
+		return { screens: current, error: String(e) };
+	}
+}`
+
+// windowPlacementOf 返回实例的窗口布局配置，Headless 模式下窗口布局无意义
+func windowPlacementOf(instance *models.BrowserInstance) *models.WindowPlacement {
+	if instance == nil || instance.Window == nil {
+		return nil
+	}
+	if instance.Headless != nil && *instance.Headless {
+		return nil
+	}
+	return instance.Window
+}
+
+// applyWindowLaunchFlags 根据窗口布局设置启动参数：去掉默认的最大化，设置初始位置、尺寸和 Kiosk 模式
+// 初始位置基于主显示器，其它显示器的偏移在浏览器启动后通过 CDP 调整
+func applyWindowLaunchFlags(l *launcher.Launcher, w *models.WindowPlacement) *launcher.Launcher {
+	l = l.Delete(flags.Flag("start-maximized"))
+	if w.Width > 0 && w.Height > 0 {
+		l = l.Set(flags.Flag("window-size"), fmt.Sprintf("%d,%d", w.Width, w.Height))
+	}
+	if w.X != nil || w.Y != nil {
+		l = l.Set(flags.Flag("window-position"), fmt.Sprintf("%d,%d", intValue(w.X), intValue(w.Y)))
+	}
+	if w.Kiosk {
+		l = l.Set(flags.Flag("kiosk"))
+	}
+	return l
+}
+
+// pickScreen 按序号选择显示器，序号越界时回退到主显示器
+func pickScreen(screens []screenRect, index int) (screenRect, bool) {
+	if len(screens) == 0 {
+		return screenRect{Width: 1400, Height: 900}, false
+	}
+	if index < 0 || index >= len(screens) {
+		return screens[0], false
+	}
+	return screens[index], true
+}
+
+// windowBounds 计算窗口在屏幕坐标系中的位置和尺寸
+func windowBounds(w *models.WindowPlacement, screen screenRect) (left, top, width, height int) {
+	width, height = w.Width, w.Height
+	if width <= 0 {
+		width = int(float64(screen.Width) * 0.9)
+	}
+	if height <= 0 {
+		height = int(float64(screen.Height) * 0.9)
+	}
+	return screen.Left + intValue(w.X), screen.Top + intValue(w.Y), width, height
+}
+
+// detectScreens 获取所有显示器的可用区域
+func detectScreens(ctx context.Context, page *rod.Page) []screenRect {
+	res, err := page.Eval(detectScreensJS)
+	if err != nil {
+		logger.Warn(ctx, "Failed to detect screens: %v", err)
+		return nil
+	}
+	var info struct {
+		Screens []screenRect `json:"screens"`
+		Error   string       `json:"error"`
+	}
+	if err := res.Value.Unmarshal(&info); err != nil {
+		logger.Warn(ctx, "Failed to parse screen info: %v", err)
+		return nil
+	}
+	if info.Error != "" {
+		logger.Warn(ctx, "Multi-monitor detection unavailable, using current screen only: %s", info.Error)
+	}
+	return info.Screens
+}
+
+// applyWindowPlacement 将窗口移动到指定显示器并设置位置和尺寸
+func (m *Manager) applyWindowPlacement(ctx context.Context, page *rod.Page, w *models.WindowPlacement) error {
+	screen, ok := pickScreen(detectScreens(ctx, page), w.Monitor)
+	if !ok && w.Monitor > 0 {
+		logger.Warn(ctx, "Monitor %d not found, falling back to primary monitor", w.Monitor)
+	}
+	left, top, width, height := windowBounds(w, screen)
+
+	if w.Kiosk {
+		// Kiosk 窗口始终全屏，只需移动到目标显示器
+		if screen.Left == 0 && screen.Top == 0 {
+			return nil
+		}
+		if err := page.SetWindow(&proto.BrowserBounds{WindowState: proto.BrowserWindowStateNormal}); err != nil {
+			return fmt.Errorf("failed to restore window: %w", err)
+		}
+		if err := page.SetWindow(&proto.BrowserBounds{Left: &screen.Left, Top: &screen.Top}); err != nil {
+			return fmt.Errorf("failed to move window: %w", err)
+		}
+		return page.SetWindow(&proto.BrowserBounds{WindowState: proto.BrowserWindowStateFullscreen})
+	}
+
+	if err := page.SetWindow(&proto.BrowserBounds{
+		Left:        &left,
+		Top:         &top,
+		Width:       &width,
+		Height:      &height,
+		WindowState: proto.BrowserWindowStateNormal,
+	}); err != nil {
+		return fmt.Errorf("failed to set window bounds: %w", err)
+	}
+	logger.Info(ctx, "Placed window at (%d, %d) size %dx%d on monitor %d", left, top, width, height, w.Monitor)
+
+	// viewport 大小为窗口大小减去浏览器边框和工具栏
+	return page.SetViewport(&proto.EmulationSetDeviceMetricsOverride{
+		Width:             width - 120,
+		Height:            height - 100,
+		DeviceScaleFactor: 1,
+	})
+}
+
+// setupInstanceWindow 实例启动后调整初始窗口的布局，并按需置顶
+func (m *Manager) setupInstanceWindow(ctx context.Context, browser *rod.Browser, w *models.WindowPlacement, pid int) {
+	// 获取多显示器信息需要窗口管理权限
+	grant := &proto.BrowserGrantPermissions{
+		Permissions: []proto.BrowserPermissionType{proto.BrowserPermissionTypeWindowManagement},
+	}
+	if err := grant.Call(browser); err != nil {
+		logger.Warn(ctx, "Failed to grant window management permission: %v", err)
+	}
+
+	if pages, err := browser.Pages(); err == nil && len(pages) > 0 {
+		if err := m.applyWindowPlacement(ctx, pages.First(), w); err != nil {
+			logger.Warn(ctx, "Failed to apply window placement: %v", err)
+		}
+	}
+
+	if w.AlwaysOnTop && pid > 0 {
+		go func() {
+			// 等待窗口创建完成
+			time.Sleep(time.Second)
+			if err := setWindowAlwaysOnTop(pid); err != nil {
+				logger.Warn(ctx, "Failed to keep window on top: %v", err)
+			} else {
+				logger.Info(ctx, "Browser window (pid %d) set to always on top", pid)
+			}
+		}()
+	}
+}
+
+// setWindowAlwaysOnTop 将进程的窗口置顶。CDP 不支持窗口置顶，只能借助系统工具：
+// Linux 使用 wmctrl，Windows 使用 PowerShell 调用 SetWindowPos
+func setWindowAlwaysOnTop(pid int) error {
+	switch runtime.GOOS {
+	case "linux":
+		if _, err := exec.LookPath("wmctrl"); err != nil {
+			return fmt.Errorf("wmctrl is required for always-on-top on Linux")
+		}
+		out, err := exec.Command("wmctrl", "-l", "-p").Output()
+		if err != nil {
+			return fmt.Errorf("failed to list windows: %w", err)
+		}
+		ids := windowIDsForPID(string(out), pid)
+		if len(ids) == 0 {
+			return fmt.Errorf("no window found for pid %d", pid)
+		}
+		for _, id := range ids {
+			if err := exec.Command("wmctrl", "-i", "-r", id, "-b", "add,above").Run(); err != nil {
+				return fmt.Errorf("failed to set window %s on top: %w", id, err)
+			}
+		}
+		return nil
+	case "windows":
+		script := `Add-Type -Name W -Namespace N -MemberDefinition '[DllImport("user32.dll")] public static extern bool SetWindowPos(IntPtr h, IntPtr a, int x, int y, int cx, int cy, uint f);';` +
+			`$h = (Get-Process -Id ` + strconv.Itoa(pid) + `).MainWindowHandle;` +
+			`if ($h -eq 0) { exit 1 };` +
+			`[N.W]::SetWindowPos($h, [IntPtr](-1), 0, 0, 0, 0, 3) | Out-Null`
+		if err := exec.Command("powershell", "-NoProfile", "-Command", script).Run(); err != nil {
+			return fmt.Errorf("failed to set window on top: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("always-on-top is not supported on %s", runtime.GOOS)
+	}
+}
+
+// windowIDsForPID 从 `wmctrl -l -p` 的输出中找出属于指定进程的窗口
+func windowIDsForPID(output string, pid int) []string {
+	var ids []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		if p, err := strconv.Atoi(fields[2]); err == nil && p == pid {
+			ids = append(ids, fields[0])
+		}
+	}
+	return ids
+}
+
+func intValue(v *int) int {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
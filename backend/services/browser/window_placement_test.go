@@ -0,0 +1,43 @@
+package browser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+func TestWindowBounds(t *testing.T) {
+	x, y := 10, 20
+	screens := []screenRect{
+		{Left: 0, Top: 0, Width: 1920, Height: 1040},
+		{Left: 1920, Top: 0, Width: 2560, Height: 1400},
+	}
+	tests := []struct {
+		placement models.WindowPlacement
+		want      [4]int
+	}{
+		{placement: models.WindowPlacement{}, want: [4]int{0, 0, 1728, 936}},
+		{placement: models.WindowPlacement{X: &x, Y: &y, Width: 800, Height: 600}, want: [4]int{10, 20, 800, 600}},
+		{placement: models.WindowPlacement{X: &x, Width: 960, Height: 1040, Monitor: 1}, want: [4]int{1930, 0, 960, 1040}},
+		// 显示器不存在时回退到主显示器
+		{placement: models.WindowPlacement{Width: 800, Height: 600, Monitor: 5}, want: [4]int{0, 0, 800, 600}},
+	}
+
+	for _, tt := range tests {
+		screen, _ := pickScreen(screens, tt.placement.Monitor)
+		left, top, width, height := windowBounds(&tt.placement, screen)
+		if got := [4]int{left, top, width, height}; got != tt.want {
+			t.Errorf("windowBounds(%+v) = %v, want %v", tt.placement, got, tt.want)
+		}
+	}
+}
+
+func TestWindowIDsForPID(t *testing.T) {
+	output := "0x03a00003  0 1234   host Google Chrome\n0x03a00010  0 999    host Terminal\n0x03a00020  1 1234   host DevTools\n"
+	got := windowIDsForPID(output, 1234)
+	want := []string{"0x03a00003", "0x03a00020"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("windowIDsForPID = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,58 @@
+package browser
+
+import (
+	"context"
+	"os"
+
+	"github.com/browserwing/browserwing/pkg/artifacts"
+	"github.com/browserwing/browserwing/pkg/logger"
+)
+
+// executionWorkdir 一次脚本执行的临时工作目录，存放上传前下载的文件、录屏帧等中间文件
+type executionWorkdir struct {
+	dir           string
+	keepOnFailure bool
+	released      bool
+}
+
+// newExecutionWorkdir 在工作目录根目录下为本次执行创建临时目录
+// 创建失败时返回空目录，中间文件回退到系统临时目录
+func (m *Manager) newExecutionWorkdir(ctx context.Context, executionID string) *executionWorkdir {
+	storage := m.storageConfig()
+	w := &executionWorkdir{keepOnFailure: storage != nil && storage.KeepWorkDirOnFailure}
+
+	root := storage.WorkRoot()
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		logger.Warn(ctx, "Failed to create work directory root %s: %v", root, err)
+		return w
+	}
+	dir, err := os.MkdirTemp(root, "exec-"+artifacts.SanitizeFileName(executionID, "run")+"-*")
+	if err != nil {
+		logger.Warn(ctx, "Failed to create execution work directory: %v", err)
+		return w
+	}
+	w.dir = dir
+	return w
+}
+
+// Dir 返回工作目录路径（创建失败时为空）
+func (w *executionWorkdir) Dir() string {
+	return w.dir
+}
+
+// release 删除工作目录；执行失败且配置了保留时保留目录并返回其路径，重复调用不做任何事
+func (w *executionWorkdir) release(ctx context.Context, failed bool) string {
+	if w.released || w.dir == "" {
+		return ""
+	}
+	w.released = true
+
+	if failed && w.keepOnFailure {
+		logger.Info(ctx, "Keeping work directory of failed execution: %s", w.dir)
+		return w.dir
+	}
+	if err := os.RemoveAll(w.dir); err != nil {
+		logger.Warn(ctx, "Failed to remove work directory %s: %v", w.dir, err)
+	}
+	return ""
+}
@@ -0,0 +1,52 @@
+package browser
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/browserwing/browserwing/config"
+	"github.com/browserwing/browserwing/pkg/logger"
+)
+
+func TestExecutionWorkdir(t *testing.T) {
+	logger.InitLogger(&logger.LoggerConfig{Level: "error"})
+	ctx := context.Background()
+	root := t.TempDir()
+	m := &Manager{config: &config.Config{Storage: &config.StorageConfig{WorkDir: root}}}
+
+	// 每次执行使用独立的目录，成功后删除
+	a := m.newExecutionWorkdir(ctx, "script/1-100")
+	b := m.newExecutionWorkdir(ctx, "script/1-100")
+	if a.Dir() == "" || a.Dir() == b.Dir() || filepath.Dir(a.Dir()) != root {
+		t.Fatalf("expected distinct directories under %s, got %q and %q", root, a.Dir(), b.Dir())
+	}
+	p := &Player{}
+	p.SetWorkDir(a.Dir())
+	if got := p.framesDir("recordings/x.gif"); got != filepath.Join(a.Dir(), "frames") {
+		t.Errorf("frames should be written into the work directory, got %s", got)
+	}
+	if kept := a.release(ctx, false); kept != "" {
+		t.Errorf("successful execution should not keep its directory, got %s", kept)
+	}
+	if _, err := os.Stat(a.Dir()); !os.IsNotExist(err) {
+		t.Error("work directory should be removed")
+	}
+
+	// 未配置保留时失败的执行同样删除
+	if kept := b.release(ctx, true); kept != "" {
+		t.Errorf("failed execution should not be kept by default, got %s", kept)
+	}
+
+	// 配置保留后失败的执行保留目录，之后的 release 不再删除
+	m.config.Storage.KeepWorkDirOnFailure = true
+	c := m.newExecutionWorkdir(ctx, "script-2")
+	if kept := c.release(ctx, true); kept != c.Dir() {
+		t.Errorf("failed execution should keep %s, got %q", c.Dir(), kept)
+	}
+	c.release(ctx, true)
+	if _, err := os.Stat(c.Dir()); err != nil {
+		t.Errorf("kept work directory should still exist: %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+// Package marketplace 脚本市场：从仓库浏览和安装社区脚本包，把本实例的脚本打包、签名并发布到仓库
+//
+// 仓库是一个静态或动态的 HTTP 服务：
+//   - GET  {registry}/index.json 返回 {"bundles": [BundleIndexEntry...]}
+//   - GET  {entry.url} 返回 SignedBundle
+//   - POST {registry}/bundles 接收 SignedBundle（Authorization: Bearer {publish_token}）
+package marketplace
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+// bundleIDPattern 脚本包 ID 的格式，用作 URL 路径参数，不允许斜杠
+var bundleIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,99}$`)
+
+// ErrUntrusted 脚本包未签名或签名者不在可信公钥列表中
+var ErrUntrusted = errors.New("bundle is not signed by a trusted key")
+
+// PackOptions 打包选项
+type PackOptions struct {
+	ID               string   `json:"id"`
+	Name             string   `json:"name"`
+	Version          string   `json:"version"`
+	Description      string   `json:"description,omitempty"`
+	Author           string   `json:"author,omitempty"`
+	Tags             []string `json:"tags,omitempty"`
+	IncludeVariables bool     `json:"include_variables,omitempty"` // 是否包含预设变量的默认值（可能含账号等私有数据）
+}
+
+// ValidateID 检查脚本包 ID 的格式
+func ValidateID(id string) error {
+	if !bundleIDPattern.MatchString(id) {
+		return fmt.Errorf("invalid bundle id %q: use lowercase letters, digits, '.', '_' and '-'", id)
+	}
+	return nil
+}
+
+// Pack 把脚本打包为脚本包，去掉 ID、时间戳、下载文件记录和请求头等与本实例相关或可能包含凭据的字段
+func Pack(scripts []*models.Script, opts PackOptions) (*models.ScriptBundle, error) {
+	if err := ValidateID(opts.ID); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(opts.Name) == "" || strings.TrimSpace(opts.Version) == "" {
+		return nil, fmt.Errorf("name and version are required")
+	}
+	if len(scripts) == 0 {
+		return nil, fmt.Errorf("at least one script is required")
+	}
+
+	bundle := &models.ScriptBundle{
+		ID:          opts.ID,
+		Name:        strings.TrimSpace(opts.Name),
+		Version:     strings.TrimSpace(opts.Version),
+		Description: opts.Description,
+		Author:      opts.Author,
+		Tags:        opts.Tags,
+		CreatedAt:   time.Now().UTC(),
+	}
+	for _, script := range scripts {
+		s := *script
+		s.ID = ""
+		s.CreatedAt = time.Time{}
+		s.UpdatedAt = time.Time{}
+		s.DownloadedFiles = nil
+		s.Headers = nil
+		s.IsMCPCommand = false
+		if !opts.IncludeVariables {
+			s.Variables = nil
+		}
+		bundle.Scripts = append(bundle.Scripts, s)
+	}
+	return bundle, nil
+}
+
+// Sign 序列化脚本包并用私钥签名，key 为空时返回未签名的脚本包
+func Sign(bundle *models.ScriptBundle, key ed25519.PrivateKey) (*models.SignedBundle, error) {
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, err
+	}
+	signed := &models.SignedBundle{Bundle: data}
+	if key != nil {
+		signed.PublicKey = base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))
+		signed.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, data))
+	}
+	return signed, nil
+}
+
+// Verification 签名校验结果
+type Verification struct {
+	Bundle    *models.ScriptBundle
+	PublicKey string // 签名者公钥，未签名时为空
+	Trusted   bool   // 签名有效且签名者在可信公钥列表中
+}
+
+// Verify 解析脚本包并校验签名：签名无效时返回错误；未签名或签名者不可信时 Trusted 为 false
+func Verify(signed *models.SignedBundle, trustedKeys []string) (*Verification, error) {
+	var bundle models.ScriptBundle
+	if err := json.Unmarshal(signed.Bundle, &bundle); err != nil {
+		return nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+	if err := ValidateID(bundle.ID); err != nil {
+		return nil, err
+	}
+	if bundle.Name == "" || len(bundle.Scripts) == 0 {
+		return nil, fmt.Errorf("invalid bundle: name and scripts are required")
+	}
+	result := &Verification{Bundle: &bundle}
+
+	if signed.Signature == "" {
+		return result, nil
+	}
+	publicKey, err := decodeKey(signed.PublicKey, ed25519.PublicKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(signed.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+	if !ed25519.Verify(publicKey, signed.Bundle, signature) {
+		return nil, fmt.Errorf("signature verification failed: the bundle has been modified or signed with another key")
+	}
+	result.PublicKey = signed.PublicKey
+	result.Trusted = slices.ContainsFunc(trustedKeys, func(key string) bool {
+		return strings.TrimSpace(key) == signed.PublicKey
+	})
+	return result, nil
+}
+
+// ParseSigningKey 解析 base64 编码的 Ed25519 私钥，支持 32 字节种子和 64 字节完整私钥，为空时返回 nil
+func ParseSigningKey(value string) (ed25519.PrivateKey, error) {
+	if value == "" {
+		return nil, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing key: %w", err)
+	}
+	switch len(data) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(data), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(data), nil
+	default:
+		return nil, fmt.Errorf("invalid signing key: expected %d or %d bytes, got %d", ed25519.SeedSize, ed25519.PrivateKeySize, len(data))
+	}
+}
+
+// decodeKey 解码 base64 编码的定长密钥
+func decodeKey(value string, size int) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != size {
+		return nil, fmt.Errorf("expected %d bytes, got %d", size, len(data))
+	}
+	return data, nil
+}
@@ -0,0 +1,110 @@
+package marketplace
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/storage"
+	"github.com/google/uuid"
+)
+
+// Install 安装校验过的脚本包
+// 重复安装同一个脚本包时按脚本名称更新已安装的脚本（保留 ID，定时任务等引用不受影响），
+// 并删除新版本中已不存在的脚本；脚本不会自动注册为 MCP 命令
+func Install(db *storage.BoltDB, v *Verification, source string) (*models.InstalledBundle, error) {
+	bundle := v.Bundle
+	now := time.Now()
+
+	existing, err := db.GetInstalledBundle(bundle.ID)
+	if err != nil {
+		existing = nil
+	}
+	previous := make(map[string]*models.Script) // 上次安装的脚本，按名称索引
+	if existing != nil {
+		for _, id := range existing.ScriptIDs {
+			if script, err := db.GetScript(id); err == nil {
+				previous[script.Name] = script
+			}
+		}
+	}
+
+	installed := &models.InstalledBundle{
+		ID:          bundle.ID,
+		Name:        bundle.Name,
+		Version:     bundle.Version,
+		Author:      bundle.Author,
+		Source:      source,
+		PublicKey:   v.PublicKey,
+		Verified:    v.Trusted,
+		ScriptIDs:   []string{},
+		InstalledAt: now,
+		UpdatedAt:   now,
+	}
+	if existing != nil {
+		installed.InstalledAt = existing.InstalledAt
+	}
+
+	for i := range bundle.Scripts {
+		script := bundle.Scripts[i]
+		if script.Name == "" {
+			return nil, fmt.Errorf("script %d has no name", i+1)
+		}
+		script.ID = uuid.New().String()
+		script.CreatedAt = now
+		script.IsMCPCommand = false
+		script.DownloadedFiles = nil
+		if script.Group == "" {
+			script.Group = bundle.Name
+		}
+		// 升级时保留用户对脚本的分组和 MCP 命令设置
+		if old, ok := previous[script.Name]; ok {
+			script.ID = old.ID
+			script.CreatedAt = old.CreatedAt
+			script.Group = old.Group
+			script.IsMCPCommand = old.IsMCPCommand
+			delete(previous, script.Name)
+		}
+		script.UpdatedAt = now
+		if err := db.SaveScript(&script); err != nil {
+			return nil, fmt.Errorf("failed to save script %s: %w", script.Name, err)
+		}
+		installed.ScriptIDs = append(installed.ScriptIDs, script.ID)
+	}
+
+	for _, script := range previous {
+		if err := deleteScript(db, script.ID); err != nil {
+			return nil, fmt.Errorf("failed to remove script %s: %w", script.Name, err)
+		}
+	}
+
+	if err := db.SaveInstalledBundle(installed); err != nil {
+		return nil, err
+	}
+	return installed, nil
+}
+
+// Uninstall 删除脚本包安装的脚本和安装记录
+func Uninstall(db *storage.BoltDB, id string) error {
+	installed, err := db.GetInstalledBundle(id)
+	if err != nil {
+		return err
+	}
+	for _, scriptID := range installed.ScriptIDs {
+		if _, err := db.GetScript(scriptID); err != nil {
+			continue
+		}
+		if err := deleteScript(db, scriptID); err != nil {
+			return fmt.Errorf("failed to remove script %s: %w", scriptID, err)
+		}
+	}
+	return db.DeleteInstalledBundle(id)
+}
+
+// deleteScript 删除脚本及其工具配置
+func deleteScript(db *storage.BoltDB, id string) error {
+	if err := db.DeleteScript(id); err != nil {
+		return err
+	}
+	return db.DeleteToolConfigByScriptID(id)
+}
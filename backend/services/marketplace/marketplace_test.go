@@ -0,0 +1,227 @@
+package marketplace
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/storage"
+)
+
+func newTestDB(t *testing.T) *storage.BoltDB {
+	t.Helper()
+	db, err := storage.NewBoltDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func newKey(t *testing.T) (ed25519.PrivateKey, string) {
+	t.Helper()
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return privateKey, base64.StdEncoding.EncodeToString(publicKey)
+}
+
+func testScripts() []*models.Script {
+	return []*models.Script{
+		{ID: "s1", Name: "search", URL: "https://example.com", Headers: map[string]string{"Authorization": "secret"}, Variables: map[string]string{"user": "me"}, IsMCPCommand: true},
+		{ID: "s2", Name: "login", URL: "https://example.com/login"},
+	}
+}
+
+func TestPackSignVerify(t *testing.T) {
+	bundle, err := Pack(testScripts(), PackOptions{ID: "example.tools", Name: "Example", Version: "1.0.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := bundle.Scripts[0]
+	if first.ID != "" || first.Headers != nil || first.Variables != nil || first.IsMCPCommand {
+		t.Fatalf("private fields not stripped: %+v", first)
+	}
+
+	key, publicKey := newKey(t)
+	signed, err := Sign(bundle, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := Verify(signed, []string{publicKey})
+	if err != nil || !v.Trusted || v.Bundle.ID != "example.tools" || len(v.Bundle.Scripts) != 2 {
+		t.Fatalf("Verify() = %+v, %v", v, err)
+	}
+	if v, err := Verify(signed, nil); err != nil || v.Trusted {
+		t.Fatalf("Verify() with no trusted keys = %+v, %v", v, err)
+	}
+
+	// 篡改内容后签名失效
+	tampered := *signed
+	tampered.Bundle = json.RawMessage(strings.Replace(string(signed.Bundle), "https://example.com/login", "https://evil.example.com", 1))
+	if _, err := Verify(&tampered, []string{publicKey}); err == nil {
+		t.Fatal("expected tampered bundle to fail verification")
+	}
+
+	unsigned, _ := Sign(bundle, nil)
+	if v, err := Verify(unsigned, []string{publicKey}); err != nil || v.Trusted || v.PublicKey != "" {
+		t.Fatalf("Verify() unsigned = %+v, %v", v, err)
+	}
+
+	if _, err := Pack(testScripts(), PackOptions{ID: "../evil", Name: "x", Version: "1"}); err == nil {
+		t.Fatal("expected invalid id to be rejected")
+	}
+}
+
+func TestParseSigningKey(t *testing.T) {
+	key, _ := newKey(t)
+	for _, value := range []string{
+		base64.StdEncoding.EncodeToString(key.Seed()),
+		base64.StdEncoding.EncodeToString(key),
+	} {
+		parsed, err := ParseSigningKey(value)
+		if err != nil || !parsed.Equal(key) {
+			t.Fatalf("ParseSigningKey(%q) = %v, %v", value, parsed, err)
+		}
+	}
+	if key, err := ParseSigningKey(""); key != nil || err != nil {
+		t.Fatalf("ParseSigningKey(\"\") = %v, %v", key, err)
+	}
+	if _, err := ParseSigningKey("c2hvcnQ="); err == nil {
+		t.Fatal("expected short key to be rejected")
+	}
+}
+
+func TestInstallUpgradeUninstall(t *testing.T) {
+	db := newTestDB(t)
+	bundle, _ := Pack(testScripts(), PackOptions{ID: "example", Name: "Example", Version: "1.0.0"})
+	signed, _ := Sign(bundle, nil)
+	v, err := Verify(signed, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	installed, err := Install(db, v, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(installed.ScriptIDs) != 2 || installed.Verified {
+		t.Fatalf("installed = %+v", installed)
+	}
+	search, err := db.GetScript(installed.ScriptIDs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if search.Group != "Example" || search.IsMCPCommand {
+		t.Fatalf("installed script = %+v", search)
+	}
+	// 用户修改分组后升级应保留
+	search.Group = "mine"
+	if err := db.SaveScript(search); err != nil {
+		t.Fatal(err)
+	}
+
+	// 新版本去掉 login，新增 export
+	scripts := testScripts()[:1]
+	scripts = append(scripts, &models.Script{Name: "export", URL: "https://example.com/export"})
+	bundle, _ = Pack(scripts, PackOptions{ID: "example", Name: "Example", Version: "2.0.0"})
+	signed, _ = Sign(bundle, nil)
+	v, _ = Verify(signed, nil)
+	upgraded, err := Install(db, v, "https://registry.example.com/example.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if upgraded.Version != "2.0.0" || upgraded.ScriptIDs[0] != search.ID || !upgraded.InstalledAt.Equal(installed.InstalledAt) {
+		t.Fatalf("upgraded = %+v", upgraded)
+	}
+	if script, _ := db.GetScript(search.ID); script == nil || script.Group != "mine" {
+		t.Fatalf("upgrade lost user changes: %+v", script)
+	}
+	if _, err := db.GetScript(installed.ScriptIDs[1]); err == nil {
+		t.Fatal("script removed from the bundle should be deleted")
+	}
+
+	if err := Uninstall(db, "example"); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range upgraded.ScriptIDs {
+		if _, err := db.GetScript(id); err == nil {
+			t.Fatalf("script %s not deleted", id)
+		}
+	}
+	if _, err := db.GetInstalledBundle("example"); err == nil {
+		t.Fatal("installed bundle record not deleted")
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	bundle, _ := Pack(testScripts(), PackOptions{ID: "example", Name: "Example", Version: "1.0.0"})
+	signed, _ := Sign(bundle, nil)
+	var published *models.SignedBundle
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repo/index.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"bundles": []models.BundleIndexEntry{{ID: "example", Name: "Example", Version: "1.0.0", URL: "bundles/example.json"}},
+		})
+	})
+	mux.HandleFunc("/repo/bundles/example.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(signed)
+	})
+	mux.HandleFunc("/repo/bundles", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		published = &models.SignedBundle{}
+		json.NewDecoder(r.Body).Decode(published)
+		w.WriteHeader(http.StatusCreated)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	registry, err := NewRegistry(server.URL+"/repo", "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	entry, err := registry.Find(ctx, "example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	downloaded, source, err := registry.Download(ctx, entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if source != server.URL+"/repo/bundles/example.json" || string(downloaded.Bundle) != string(signed.Bundle) {
+		t.Fatalf("Download() = %s, %s", downloaded.Bundle, source)
+	}
+	if _, err := registry.Find(ctx, "missing"); err == nil {
+		t.Fatal("expected missing bundle error")
+	}
+
+	if err := registry.Publish(ctx, signed); err != nil {
+		t.Fatal(err)
+	}
+	if published == nil || string(published.Bundle) != string(signed.Bundle) {
+		t.Fatalf("published = %+v", published)
+	}
+	anonymous, _ := NewRegistry(server.URL+"/repo", "")
+	if err := anonymous.Publish(ctx, signed); err == nil {
+		t.Fatal("expected publish without token to fail")
+	}
+
+	if _, err := NewRegistry("ftp://example.com", ""); err == nil {
+		t.Fatal("expected invalid registry url to be rejected")
+	}
+}
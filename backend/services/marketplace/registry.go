@@ -0,0 +1,164 @@
+package marketplace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+)
+
+const (
+	// registryTimeout 访问仓库的超时
+	registryTimeout = 30 * time.Second
+	// maxBundleSize 脚本包和索引的大小上限
+	maxBundleSize = 10 << 20
+)
+
+// Registry 脚本市场仓库客户端
+type Registry struct {
+	baseURL      *url.URL
+	publishToken string
+	client       *http.Client
+}
+
+// NewRegistry 创建仓库客户端，registryURL 为仓库根地址
+func NewRegistry(registryURL, publishToken string) (*Registry, error) {
+	u, err := url.Parse(strings.TrimSpace(registryURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid registry url %q", registryURL)
+	}
+	// 以 / 结尾，相对地址基于仓库根目录解析
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	return &Registry{
+		baseURL:      u,
+		publishToken: publishToken,
+		client:       &http.Client{Timeout: registryTimeout},
+	}, nil
+}
+
+// URL 仓库根地址
+func (r *Registry) URL() string {
+	return r.baseURL.String()
+}
+
+// resolve 基于仓库根地址解析相对地址
+func (r *Registry) resolve(ref string) (string, error) {
+	u, err := r.baseURL.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("unsupported url %q", ref)
+	}
+	return u.String(), nil
+}
+
+// Index 获取仓库索引
+func (r *Registry) Index(ctx context.Context) ([]models.BundleIndexEntry, error) {
+	indexURL, _ := r.resolve("index.json")
+	var index struct {
+		Bundles []models.BundleIndexEntry `json:"bundles"`
+	}
+	if err := r.getJSON(ctx, indexURL, &index); err != nil {
+		return nil, err
+	}
+	if index.Bundles == nil {
+		index.Bundles = []models.BundleIndexEntry{}
+	}
+	return index.Bundles, nil
+}
+
+// Find 在索引中查找脚本包
+func (r *Registry) Find(ctx context.Context, id string) (*models.BundleIndexEntry, error) {
+	entries, err := r.Index(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		if entries[i].ID == id {
+			return &entries[i], nil
+		}
+	}
+	return nil, fmt.Errorf("bundle %s not found in registry", id)
+}
+
+// Download 下载索引项对应的脚本包，返回脚本包和解析后的下载地址
+func (r *Registry) Download(ctx context.Context, entry *models.BundleIndexEntry) (*models.SignedBundle, string, error) {
+	bundleURL, err := r.resolve(entry.URL)
+	if err != nil {
+		return nil, "", err
+	}
+	var signed models.SignedBundle
+	if err := r.getJSON(ctx, bundleURL, &signed); err != nil {
+		return nil, "", err
+	}
+	return &signed, bundleURL, nil
+}
+
+// Publish 把脚本包发布到仓库
+func (r *Registry) Publish(ctx context.Context, signed *models.SignedBundle) error {
+	if r.publishToken == "" {
+		return fmt.Errorf("publish_token is not configured")
+	}
+	publishURL, _ := r.resolve("bundles")
+	body, err := json.Marshal(signed)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, publishURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+r.publishToken)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+		return fmt.Errorf("registry returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
+
+// getJSON GET 请求并解码 JSON 响应
+func (r *Registry) getJSON(ctx context.Context, u string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", u, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBundleSize+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > maxBundleSize {
+		return fmt.Errorf("%s is larger than %d bytes", u, maxBundleSize)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid response from %s: %w", u, err)
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/urlpolicy"
+)
+
+// telegramAPIBase Telegram Bot API 地址（测试时替换）
+var telegramAPIBase = "https://api.telegram.org"
+
+// discordMaxContent Discord 消息内容的长度上限
+const discordMaxContent = 2000
+
+// Validate 检查渠道配置是否完整，不访问网络
+func Validate(channel *models.NotificationChannel) error {
+	switch channel.Type {
+	case models.NotificationChannelSlack, models.NotificationChannelDiscord:
+		if !strings.HasPrefix(channel.WebhookURL, "https://") && !strings.HasPrefix(channel.WebhookURL, "http://") {
+			return fmt.Errorf("webhook_url must start with http:// or https://")
+		}
+	case models.NotificationChannelTelegram:
+		if channel.BotToken == "" || channel.ChatID == "" {
+			return fmt.Errorf("bot_token and chat_id are required")
+		}
+	case models.NotificationChannelEmail:
+		if channel.SMTPHost == "" {
+			return fmt.Errorf("smtp_host is required")
+		}
+		if channel.SMTPPort < 0 || channel.SMTPPort > 65535 {
+			return fmt.Errorf("invalid smtp_port %d", channel.SMTPPort)
+		}
+		if _, err := mail.ParseAddress(channel.From); err != nil {
+			return fmt.Errorf("invalid from address: %w", err)
+		}
+		if len(channel.To) == 0 {
+			return fmt.Errorf("at least one recipient is required")
+		}
+		for _, to := range channel.To {
+			if _, err := mail.ParseAddress(to); err != nil {
+				return fmt.Errorf("invalid recipient %q: %w", to, err)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown channel type %q", channel.Type)
+	}
+	return nil
+}
+
+// Send 把消息发送到单个渠道
+func Send(ctx context.Context, guard *urlpolicy.NetworkGuard, channel *models.NotificationChannel, msg Message) error {
+	switch channel.Type {
+	case models.NotificationChannelSlack:
+		return postJSON(ctx, guard, channel.WebhookURL, map[string]interface{}{
+			"text": "*" + msg.Title + "*\n" + msg.Text(),
+		})
+	case models.NotificationChannelDiscord:
+		content := "**" + msg.Title + "**\n" + msg.Text()
+		if runes := []rune(content); len(runes) > discordMaxContent {
+			content = string(runes[:discordMaxContent-1]) + "…"
+		}
+		return postJSON(ctx, guard, channel.WebhookURL, map[string]interface{}{"content": content})
+	case models.NotificationChannelTelegram:
+		return sendTelegram(ctx, guard, channel, msg)
+	case models.NotificationChannelEmail:
+		return sendEmail(ctx, guard, channel, msg)
+	default:
+		return fmt.Errorf("unknown channel type %q", channel.Type)
+	}
+}
+
+// postJSON 以 JSON POST 到 Webhook 地址，非 2xx 响应视为失败
+func postJSON(ctx context.Context, guard *urlpolicy.NetworkGuard, url string, payload interface{}) error {
+	if err := guard.Check(ctx, url); err != nil {
+		return err
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := guard.HTTPClient(sendTimeout).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
+
+// sendTelegram 通过 Bot API 的 sendMessage 发送纯文本消息
+func sendTelegram(ctx context.Context, guard *urlpolicy.NetworkGuard, channel *models.NotificationChannel, msg Message) error {
+	url := fmt.Sprintf("%s/bot%s/sendMessage", telegramAPIBase, channel.BotToken)
+	err := postJSON(ctx, guard, url, map[string]interface{}{
+		"chat_id":                  channel.ChatID,
+		"text":                     msg.Title + "\n\n" + msg.Text(),
+		"disable_web_page_preview": true,
+	})
+	if err != nil {
+		// 错误信息中不能包含 Bot Token
+		return fmt.Errorf("%s", strings.ReplaceAll(err.Error(), channel.BotToken, "***"))
+	}
+	return nil
+}
+
+// sendEmail 通过 SMTP 发送邮件
+// 465 端口使用 TLS 直连，其他端口在服务器支持时升级为 STARTTLS
+// 与 Webhook 一样受内网访问防护限制，内网 SMTP 服务器需加入 security.allowed_private_hosts
+func sendEmail(ctx context.Context, guard *urlpolicy.NetworkGuard, channel *models.NotificationChannel, msg Message) error {
+	port := channel.SMTPPort
+	if port == 0 {
+		port = 587
+	}
+	addr := net.JoinHostPort(channel.SMTPHost, strconv.Itoa(port))
+	tlsConfig := &tls.Config{ServerName: channel.SMTPHost}
+
+	dialer, err := guard.Dialer(ctx, channel.SMTPHost, sendTimeout)
+	if err != nil {
+		return err
+	}
+	var conn net.Conn
+	if port == 465 {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, channel.SMTPHost)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if port != 465 {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return err
+			}
+		}
+	}
+	if channel.SMTPUsername != "" {
+		if err := client.Auth(smtp.PlainAuth("", channel.SMTPUsername, channel.SMTPPassword, channel.SMTPHost)); err != nil {
+			return err
+		}
+	}
+
+	from, err := mail.ParseAddress(channel.From)
+	if err != nil {
+		return err
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	for _, to := range channel.To {
+		addr, err := mail.ParseAddress(to)
+		if err != nil {
+			return err
+		}
+		if err := client.Rcpt(addr.Address); err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(buildEmail(channel, msg, time.Now())); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// buildEmail 生成 UTF-8 邮件；消息带 HTML 正文时生成纯文本和 HTML 两个版本的 multipart/alternative 邮件
+func buildEmail(channel *models.NotificationChannel, msg Message, date time.Time) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + channel.From + "\r\n")
+	b.WriteString("To: " + strings.Join(channel.To, ", ") + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", "[BrowserWing] "+msg.Title) + "\r\n")
+	b.WriteString("Date: " + date.Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	text := strings.ReplaceAll(msg.Text(), "\n", "\r\n") + "\r\n"
+
+	if msg.HTML == "" {
+		b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+		b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+		b.WriteString("\r\n")
+		b.WriteString(text)
+		return []byte(b.String())
+	}
+
+	// HTML 的行可能超过 SMTP 的行长度限制，两个部分都使用 quoted-printable 编码
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=UTF-8", text},
+		{"text/html; charset=UTF-8", msg.HTML},
+	} {
+		w, _ := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		qw := quotedprintable.NewWriter(w)
+		qw.Write([]byte(part.content))
+		qw.Close()
+	}
+	mw.Close()
+
+	b.WriteString("Content-Type: multipart/alternative; boundary=\"" + mw.Boundary() + "\"\r\n")
+	b.WriteString("\r\n")
+	b.Write(body.Bytes())
+	return []byte(b.String())
+}
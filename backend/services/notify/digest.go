@@ -0,0 +1,446 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/pkg/urlpolicy"
+	"github.com/browserwing/browserwing/storage"
+)
+
+// 摘要中各列表的长度上限
+const (
+	digestMaxFailures    = 10  // 最近的失败记录
+	digestMaxExtractions = 10  // 展示抓取数据的脚本
+	digestMaxFields      = 5   // 每个脚本展示的抓取字段
+	digestMaxValueLength = 200 // 抓取字段值的长度
+)
+
+// digestCheckInterval 检查摘要是否到期的间隔
+const digestCheckInterval = time.Minute
+
+// DigestReport 一个周期内的执行汇总
+type DigestReport struct {
+	Name   string
+	Period models.DigestPeriod
+	From   time.Time
+	To     time.Time
+
+	ScriptRuns     int
+	ScriptFailures int
+	TaskRuns       int
+	TaskFailures   int
+
+	Scripts     []DigestScriptStats // 按执行次数降序
+	Failures    []DigestFailure     // 最近的失败，最新的在前
+	Extractions []DigestExtraction  // 数据有变化的脚本在前
+}
+
+// DigestScriptStats 单个脚本的执行统计
+type DigestScriptStats struct {
+	ScriptID    string
+	ScriptName  string
+	Runs        int
+	Failures    int
+	AvgDuration time.Duration
+	LastError   string
+}
+
+// DigestFailure 一次失败的执行
+type DigestFailure struct {
+	ScriptName string
+	Time       time.Time
+	Error      string
+}
+
+// DigestExtraction 脚本在周期内最新一次成功执行抓取到的数据
+type DigestExtraction struct {
+	ScriptName string
+	Time       time.Time
+	Changed    bool // 与周期内第一次成功执行的数据不同
+	Fields     []DigestField
+	MoreFields int // 未展示的字段数
+}
+
+// DigestField 抓取数据的一个字段
+type DigestField struct {
+	Key   string
+	Value string
+}
+
+// SuccessRate 脚本执行成功率（百分比），没有执行时为 100
+func (r *DigestReport) SuccessRate() float64 {
+	if r.ScriptRuns == 0 {
+		return 100
+	}
+	return float64(r.ScriptRuns-r.ScriptFailures) * 100 / float64(r.ScriptRuns)
+}
+
+// Title 摘要标题
+func (r *DigestReport) Title() string {
+	period := "Daily"
+	if r.Period == models.DigestPeriodWeekly {
+		period = "Weekly"
+	}
+	return fmt.Sprintf("%s digest: %s", period, r.Name)
+}
+
+// BuildDigest 汇总 [to-周期, to) 内的脚本执行和定时任务执行
+func BuildDigest(db *storage.BoltDB, digest *models.NotificationDigest, to time.Time) (*DigestReport, error) {
+	report := &DigestReport{
+		Name:   digest.Name,
+		Period: digest.Period,
+		From:   to.Add(-digest.Interval()),
+		To:     to,
+	}
+	included := func(scriptID string) bool {
+		return len(digest.ScriptIDs) == 0 || slices.Contains(digest.ScriptIDs, scriptID)
+	}
+	inWindow := func(t time.Time) bool {
+		return !t.Before(report.From) && t.Before(report.To)
+	}
+
+	executions, err := db.ListScriptExecutions("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load script executions: %w", err)
+	}
+
+	// 执行记录按开始时间降序排列
+	stats := make(map[string]*DigestScriptStats)
+	durations := make(map[string]int64)
+	latest := make(map[string]*models.ScriptExecution)   // 最新一次有抓取数据的成功执行
+	earliest := make(map[string]*models.ScriptExecution) // 最早一次有抓取数据的成功执行
+	for _, execution := range executions {
+		if !inWindow(execution.StartTime) || !included(execution.ScriptID) {
+			continue
+		}
+		report.ScriptRuns++
+
+		s, ok := stats[execution.ScriptID]
+		if !ok {
+			s = &DigestScriptStats{ScriptID: execution.ScriptID, ScriptName: execution.ScriptName}
+			stats[execution.ScriptID] = s
+		}
+		s.Runs++
+		durations[execution.ScriptID] += execution.Duration
+
+		if !execution.Success {
+			report.ScriptFailures++
+			s.Failures++
+			if s.LastError == "" {
+				s.LastError = execution.ErrorMsg
+			}
+			if len(report.Failures) < digestMaxFailures {
+				report.Failures = append(report.Failures, DigestFailure{
+					ScriptName: execution.ScriptName,
+					Time:       execution.StartTime,
+					Error:      execution.ErrorMsg,
+				})
+			}
+			continue
+		}
+		if len(execution.ExtractedData) > 0 {
+			if _, ok := latest[execution.ScriptID]; !ok {
+				latest[execution.ScriptID] = execution
+			}
+			earliest[execution.ScriptID] = execution
+		}
+	}
+
+	for id, s := range stats {
+		s.AvgDuration = time.Duration(durations[id]/int64(s.Runs)) * time.Millisecond
+		report.Scripts = append(report.Scripts, *s)
+	}
+	sort.Slice(report.Scripts, func(i, j int) bool {
+		a, b := report.Scripts[i], report.Scripts[j]
+		if a.Runs != b.Runs {
+			return a.Runs > b.Runs
+		}
+		return a.ScriptName < b.ScriptName
+	})
+
+	for id, execution := range latest {
+		report.Extractions = append(report.Extractions, digestExtraction(execution, earliest[id]))
+	}
+	sort.Slice(report.Extractions, func(i, j int) bool {
+		a, b := report.Extractions[i], report.Extractions[j]
+		if a.Changed != b.Changed {
+			return a.Changed
+		}
+		return a.ScriptName < b.ScriptName
+	})
+	if len(report.Extractions) > digestMaxExtractions {
+		report.Extractions = report.Extractions[:digestMaxExtractions]
+	}
+
+	taskExecutions, err := db.ListTaskExecutions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load task executions: %w", err)
+	}
+	for _, execution := range taskExecutions {
+		if !inWindow(execution.StartTime) {
+			continue
+		}
+		if len(digest.ScriptIDs) > 0 && !included(execution.ScriptID) {
+			continue
+		}
+		report.TaskRuns++
+		if !execution.Success {
+			report.TaskFailures++
+		}
+	}
+
+	return report, nil
+}
+
+// digestExtraction 取最新一次执行的抓取数据，并与周期内最早一次比较是否有变化
+func digestExtraction(latest, earliest *models.ScriptExecution) DigestExtraction {
+	extraction := DigestExtraction{
+		ScriptName: latest.ScriptName,
+		Time:       latest.StartTime,
+		Changed:    earliest != latest && !reflect.DeepEqual(earliest.ExtractedData, latest.ExtractedData),
+	}
+
+	keys := make([]string, 0, len(latest.ExtractedData))
+	for key := range latest.ExtractedData {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if len(keys) > digestMaxFields {
+		extraction.MoreFields = len(keys) - digestMaxFields
+		keys = keys[:digestMaxFields]
+	}
+	for _, key := range keys {
+		extraction.Fields = append(extraction.Fields, DigestField{Key: key, Value: digestValue(latest.ExtractedData[key])})
+	}
+	return extraction
+}
+
+// digestValue 把抓取到的值转换为单行文本并截断
+func digestValue(value interface{}) string {
+	text, ok := value.(string)
+	if !ok {
+		data, err := json.Marshal(value)
+		if err != nil {
+			text = fmt.Sprint(value)
+		} else {
+			text = string(data)
+		}
+	}
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > digestMaxValueLength {
+		text = string(runes[:digestMaxValueLength-1]) + "…"
+	}
+	return text
+}
+
+// Message 生成摘要通知：聊天渠道使用纯文本，邮件渠道附带 HTML 报告
+func (r *DigestReport) Message() (Message, error) {
+	lines := []string{
+		fmt.Sprintf("Period: %s – %s", r.From.Format("2006-01-02 15:04"), r.To.Format("2006-01-02 15:04")),
+		fmt.Sprintf("Script runs: %d (%d failed, %.1f%% success)", r.ScriptRuns, r.ScriptFailures, r.SuccessRate()),
+		fmt.Sprintf("Task runs: %d (%d failed)", r.TaskRuns, r.TaskFailures),
+	}
+
+	var failing []string
+	for _, s := range r.Scripts {
+		if s.Failures > 0 {
+			failing = append(failing, fmt.Sprintf("- %s: %d/%d failed", s.ScriptName, s.Failures, s.Runs))
+		}
+	}
+	if len(failing) > 0 {
+		lines = append(lines, "Failing scripts:")
+		if len(failing) > maxMessageLines {
+			failing = append(failing[:maxMessageLines], fmt.Sprintf("… %d more", len(failing)-maxMessageLines))
+		}
+		lines = append(lines, failing...)
+	}
+
+	var changed []string
+	for _, e := range r.Extractions {
+		if e.Changed {
+			changed = append(changed, "- "+e.ScriptName)
+		}
+	}
+	if len(changed) > 0 {
+		lines = append(lines, "Changed extractions:")
+		lines = append(lines, changed...)
+	}
+
+	html, err := r.HTML()
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{Title: r.Title(), Lines: lines, HTML: html}, nil
+}
+
+// HTML 渲染 HTML 报告，样式全部内联以兼容邮件客户端
+func (r *DigestReport) HTML() (string, error) {
+	var buf bytes.Buffer
+	if err := digestTemplate.Execute(&buf, r); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+var digestTemplate = template.Must(template.New("digest").Funcs(template.FuncMap{
+	"time":     func(t time.Time) string { return t.Format("2006-01-02 15:04") },
+	"duration": func(d time.Duration) string { return d.Round(100 * time.Millisecond).String() },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Title}}</title></head>
+<body style="margin:0;padding:24px;background:#f5f5f5;font-family:-apple-system,'Segoe UI',Helvetica,Arial,sans-serif;color:#1f2937;">
+<div style="max-width:720px;margin:0 auto;background:#ffffff;border-radius:8px;padding:24px;">
+<h1 style="font-size:20px;margin:0 0 4px;">{{.Title}}</h1>
+<p style="margin:0 0 20px;color:#6b7280;font-size:13px;">{{time .From}} – {{time .To}}</p>
+
+<table style="width:100%;border-collapse:collapse;margin-bottom:24px;text-align:center;">
+<tr>
+<td style="padding:12px;border:1px solid #e5e7eb;"><div style="font-size:24px;font-weight:600;">{{.ScriptRuns}}</div><div style="font-size:12px;color:#6b7280;">script runs</div></td>
+<td style="padding:12px;border:1px solid #e5e7eb;"><div style="font-size:24px;font-weight:600;{{if .ScriptFailures}}color:#dc2626;{{end}}">{{.ScriptFailures}}</div><div style="font-size:12px;color:#6b7280;">failed</div></td>
+<td style="padding:12px;border:1px solid #e5e7eb;"><div style="font-size:24px;font-weight:600;">{{printf "%.1f" .SuccessRate}}%</div><div style="font-size:12px;color:#6b7280;">success rate</div></td>
+<td style="padding:12px;border:1px solid #e5e7eb;"><div style="font-size:24px;font-weight:600;">{{.TaskRuns}}</div><div style="font-size:12px;color:#6b7280;">task runs ({{.TaskFailures}} failed)</div></td>
+</tr>
+</table>
+
+{{if .Scripts}}
+<h2 style="font-size:16px;margin:0 0 8px;">Scripts</h2>
+<table style="width:100%;border-collapse:collapse;margin-bottom:24px;font-size:13px;">
+<tr style="background:#f9fafb;text-align:left;">
+<th style="padding:6px 8px;border-bottom:1px solid #e5e7eb;">Script</th>
+<th style="padding:6px 8px;border-bottom:1px solid #e5e7eb;text-align:right;">Runs</th>
+<th style="padding:6px 8px;border-bottom:1px solid #e5e7eb;text-align:right;">Failed</th>
+<th style="padding:6px 8px;border-bottom:1px solid #e5e7eb;text-align:right;">Avg duration</th>
+</tr>
+{{range .Scripts}}<tr>
+<td style="padding:6px 8px;border-bottom:1px solid #f3f4f6;">{{.ScriptName}}</td>
+<td style="padding:6px 8px;border-bottom:1px solid #f3f4f6;text-align:right;">{{.Runs}}</td>
+<td style="padding:6px 8px;border-bottom:1px solid #f3f4f6;text-align:right;{{if .Failures}}color:#dc2626;{{end}}">{{.Failures}}</td>
+<td style="padding:6px 8px;border-bottom:1px solid #f3f4f6;text-align:right;">{{duration .AvgDuration}}</td>
+</tr>
+{{end}}</table>
+{{else}}
+<p style="color:#6b7280;">No script runs in this period.</p>
+{{end}}
+
+{{if .Failures}}
+<h2 style="font-size:16px;margin:0 0 8px;">Recent failures</h2>
+<table style="width:100%;border-collapse:collapse;margin-bottom:24px;font-size:13px;">
+{{range .Failures}}<tr>
+<td style="padding:6px 8px;border-bottom:1px solid #f3f4f6;white-space:nowrap;color:#6b7280;vertical-align:top;">{{time .Time}}</td>
+<td style="padding:6px 8px;border-bottom:1px solid #f3f4f6;vertical-align:top;"><strong>{{.ScriptName}}</strong><br><span style="color:#dc2626;">{{.Error}}</span></td>
+</tr>
+{{end}}</table>
+{{end}}
+
+{{if .Extractions}}
+<h2 style="font-size:16px;margin:0 0 8px;">Extracted data</h2>
+{{range .Extractions}}<div style="border:1px solid #e5e7eb;border-radius:6px;padding:10px 12px;margin-bottom:12px;font-size:13px;">
+<div style="margin-bottom:6px;"><strong>{{.ScriptName}}</strong>{{if .Changed}} <span style="background:#fef3c7;color:#92400e;border-radius:4px;padding:1px 6px;font-size:11px;">changed</span>{{end}} <span style="color:#6b7280;">{{time .Time}}</span></div>
+<table style="width:100%;border-collapse:collapse;">
+{{range .Fields}}<tr><td style="padding:2px 8px 2px 0;color:#6b7280;vertical-align:top;white-space:nowrap;">{{.Key}}</td><td style="padding:2px 0;word-break:break-all;">{{.Value}}</td></tr>
+{{end}}</table>
+{{if .MoreFields}}<div style="color:#6b7280;margin-top:4px;">… {{.MoreFields}} more fields</div>{{end}}
+</div>
+{{end}}
+{{end}}
+
+<p style="margin:24px 0 0;color:#9ca3af;font-size:12px;">Sent by BrowserWing</p>
+</div>
+</body>
+</html>
+`))
+
+// SendDigest 把摘要报告发送到摘要配置的所有已启用渠道，返回所有发送失败的错误
+func SendDigest(ctx context.Context, db *storage.BoltDB, guard *urlpolicy.NetworkGuard, digest *models.NotificationDigest, report *DigestReport) error {
+	msg, err := report.Message()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, channelID := range digest.ChannelIDs {
+		channel, err := db.GetNotificationChannel(channelID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("channel %s not found", channelID))
+			continue
+		}
+		if !channel.Enabled {
+			continue
+		}
+		sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+		if err := Send(sendCtx, guard, channel, msg); err != nil {
+			errs = append(errs, fmt.Errorf("channel %s: %w", channel.Name, err))
+		}
+		cancel()
+	}
+	return errors.Join(errs...)
+}
+
+// StartDigests 启动后台协程，每分钟检查一次到期的摘要并发送
+func (s *Service) StartDigests() {
+	s.stopDigests = make(chan struct{})
+	go func(stop <-chan struct{}) {
+		ticker := time.NewTicker(digestCheckInterval)
+		defer ticker.Stop()
+
+		s.sendDueDigests(context.Background(), time.Now())
+		for {
+			select {
+			case <-stop:
+				return
+			case now := <-ticker.C:
+				s.sendDueDigests(context.Background(), now)
+			}
+		}
+	}(s.stopDigests)
+}
+
+// StopDigests 停止摘要发送协程
+func (s *Service) StopDigests() {
+	if s.stopDigests != nil {
+		close(s.stopDigests)
+		s.stopDigests = nil
+	}
+}
+
+// sendDueDigests 发送所有到期的摘要；发送失败也记录为已发送，避免每分钟重复发送
+func (s *Service) sendDueDigests(ctx context.Context, now time.Time) {
+	digests, err := s.db.ListNotificationDigests()
+	if err != nil {
+		logger.Warn(ctx, "Failed to load notification digests: %v", err)
+		return
+	}
+
+	for _, digest := range digests {
+		if !digest.Due(now) {
+			continue
+		}
+		report, err := BuildDigest(s.db, digest, digest.LastScheduled(now))
+		if err == nil {
+			err = SendDigest(ctx, s.db, s.guard, digest, report)
+		}
+		if err != nil {
+			logger.Warn(ctx, "Failed to send digest %s: %v", digest.Name, err)
+		}
+
+		// 重新读取配置，避免覆盖发送期间通过 API 做的修改
+		if latest, err := s.db.GetNotificationDigest(digest.ID); err == nil {
+			latest.LastSentAt = &now
+			if err := s.db.SaveNotificationDigest(latest); err != nil {
+				logger.Warn(ctx, "Failed to save digest %s: %v", digest.Name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package notify
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/storage"
+)
+
+func newDigestDB(t *testing.T) *storage.BoltDB {
+	t.Helper()
+	db, err := storage.NewBoltDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestBuildDigest(t *testing.T) {
+	db := newDigestDB(t)
+	to := time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC)
+	executions := []*models.ScriptExecution{
+		{ID: "1", ScriptID: "price", ScriptName: "price", StartTime: to.Add(-20 * time.Hour), Duration: 1000, Success: true, ExtractedData: map[string]interface{}{"price": "10"}},
+		{ID: "2", ScriptID: "price", ScriptName: "price", StartTime: to.Add(-2 * time.Hour), Duration: 3000, Success: true, ExtractedData: map[string]interface{}{"price": "12"}},
+		{ID: "3", ScriptID: "login", ScriptName: "login", StartTime: to.Add(-3 * time.Hour), Duration: 500, Success: false, ErrorMsg: "older error"},
+		{ID: "4", ScriptID: "login", ScriptName: "login", StartTime: to.Add(-1 * time.Hour), Duration: 500, Success: false, ErrorMsg: "<b>timeout</b>"},
+		{ID: "5", ScriptID: "stock", ScriptName: "stock", StartTime: to.Add(-5 * time.Hour), Duration: 800, Success: true, ExtractedData: map[string]interface{}{"count": 3.0, "items": []interface{}{"a", "b"}}},
+		// 周期之外
+		{ID: "6", ScriptID: "price", ScriptName: "price", StartTime: to.Add(-25 * time.Hour), Success: false},
+		{ID: "7", ScriptID: "price", ScriptName: "price", StartTime: to, Success: false},
+	}
+	for _, execution := range executions {
+		if err := db.SaveScriptExecution(execution); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, execution := range []*models.TaskExecution{
+		{ID: "t1", ScriptID: "price", StartTime: to.Add(-time.Hour), Success: true},
+		{ID: "t2", ScriptID: "login", StartTime: to.Add(-time.Hour), Success: false},
+	} {
+		if err := db.CreateTaskExecution(execution); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	digest := &models.NotificationDigest{Name: "ops", Period: models.DigestPeriodDaily}
+	report, err := BuildDigest(db, digest, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.ScriptRuns != 5 || report.ScriptFailures != 2 || report.TaskRuns != 2 || report.TaskFailures != 1 {
+		t.Errorf("unexpected totals %+v", report)
+	}
+	if report.SuccessRate() != 60 {
+		t.Errorf("unexpected success rate %v", report.SuccessRate())
+	}
+	if s := report.Scripts[0]; s.ScriptName != "login" || s.Runs != 2 || s.Failures != 2 || s.LastError != "<b>timeout</b>" {
+		t.Errorf("unexpected first script %+v", s)
+	}
+	if s := report.Scripts[1]; s.ScriptName != "price" || s.AvgDuration != 2*time.Second {
+		t.Errorf("unexpected second script %+v", s)
+	}
+	if len(report.Failures) != 2 || report.Failures[0].Error != "<b>timeout</b>" {
+		t.Errorf("unexpected failures %+v", report.Failures)
+	}
+	if len(report.Extractions) != 2 {
+		t.Fatalf("unexpected extractions %+v", report.Extractions)
+	}
+	if e := report.Extractions[0]; e.ScriptName != "price" || !e.Changed || e.Fields[0].Value != "12" {
+		t.Errorf("expected the changed price extraction first, got %+v", e)
+	}
+	if e := report.Extractions[1]; e.Changed || e.Fields[1].Value != `["a","b"]` {
+		t.Errorf("unexpected stock extraction %+v", e)
+	}
+
+	// 只统计指定脚本
+	digest.ScriptIDs = []string{"price"}
+	report, err = BuildDigest(db, digest, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.ScriptRuns != 2 || report.ScriptFailures != 0 || report.TaskRuns != 1 {
+		t.Errorf("unexpected filtered totals %+v", report)
+	}
+}
+
+func TestDigestMessage(t *testing.T) {
+	report := &DigestReport{
+		Name:           "ops",
+		Period:         models.DigestPeriodWeekly,
+		From:           time.Date(2026, 2, 25, 9, 0, 0, 0, time.UTC),
+		To:             time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC),
+		ScriptRuns:     4,
+		ScriptFailures: 1,
+		Scripts:        []DigestScriptStats{{ScriptName: "login", Runs: 2, Failures: 1}, {ScriptName: "price", Runs: 2}},
+		Failures:       []DigestFailure{{ScriptName: "login", Error: "<script>alert(1)</script>"}},
+		Extractions:    []DigestExtraction{{ScriptName: "price", Changed: true, Fields: []DigestField{{Key: "price", Value: "12"}}}},
+	}
+	msg, err := report.Message()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Title != "Weekly digest: ops" {
+		t.Errorf("unexpected title %q", msg.Title)
+	}
+	text := msg.Text()
+	for _, want := range []string{"Script runs: 4 (1 failed, 75.0% success)", "Failing scripts:\n- login: 1/2 failed", "Changed extractions:\n- price"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(msg.HTML, "<script>") || !strings.Contains(msg.HTML, "&lt;script&gt;") {
+		t.Error("expected the error message to be escaped in HTML")
+	}
+	if !strings.Contains(msg.HTML, "changed</span>") {
+		t.Error("expected the changed badge in HTML")
+	}
+
+	channel := &models.NotificationChannel{From: "bot@example.com", To: []string{"ops@example.com"}}
+	mail := string(buildEmail(channel, msg, time.Unix(0, 0).UTC()))
+	for _, want := range []string{"Content-Type: multipart/alternative; boundary=", "Content-Type: text/plain; charset=UTF-8", "Content-Type: text/html; charset=UTF-8"} {
+		if !strings.Contains(mail, want) {
+			t.Errorf("email missing %q", want)
+		}
+	}
+}
+
+func TestSendDueDigests(t *testing.T) {
+	db := newDigestDB(t)
+	var rec recorder
+	srv := rec.server(t)
+	if err := db.SaveNotificationChannel(&models.NotificationChannel{ID: "ops", Name: "ops", Type: models.NotificationChannelSlack, Enabled: true, WebhookURL: srv.URL + "/ops"}); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2026, 3, 4, 9, 30, 0, 0, time.UTC)
+	for _, digest := range []*models.NotificationDigest{
+		{ID: "due", Name: "due", Enabled: true, Period: models.DigestPeriodDaily, Hour: 9, ChannelIDs: []string{"ops"}, CreatedAt: now.AddDate(0, 0, -2)},
+		{ID: "later", Name: "later", Enabled: true, Period: models.DigestPeriodDaily, Hour: 10, ChannelIDs: []string{"ops"}, CreatedAt: now.Add(-time.Hour)},
+		{ID: "off", Name: "off", Enabled: false, Period: models.DigestPeriodDaily, Hour: 9, ChannelIDs: []string{"ops"}, CreatedAt: now.AddDate(0, 0, -2)},
+	} {
+		if err := db.SaveNotificationDigest(digest); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewService(db, nil)
+	s.sendDueDigests(context.Background(), now)
+	s.sendDueDigests(context.Background(), now.Add(time.Minute))
+
+	if len(rec.paths) != 1 || !strings.HasPrefix(rec.payloads[0]["text"].(string), "*Daily digest: due*") {
+		t.Errorf("expected one digest delivery, got %v %v", rec.paths, rec.payloads)
+	}
+	digest, err := db.GetNotificationDigest("due")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if digest.LastSentAt == nil || !digest.LastSentAt.Equal(now) {
+		t.Errorf("unexpected last_sent_at %v", digest.LastSentAt)
+	}
+}
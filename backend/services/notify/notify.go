@@ -0,0 +1,227 @@
+// Package notify 把脚本失败、定时任务完成、内容变化等事件按通知规则发送到
+// Slack、Discord、Telegram 和邮件渠道，并定期发送执行报告摘要
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/pkg/logger"
+	"github.com/browserwing/browserwing/pkg/urlpolicy"
+	"github.com/browserwing/browserwing/storage"
+)
+
+// sendTimeout 单个渠道的发送超时
+const sendTimeout = 30 * time.Second
+
+// maxMessageLines 变化内容等列表在通知中最多展示的行数
+const maxMessageLines = 10
+
+// Message 一条通知
+type Message struct {
+	Event models.NotificationEvent
+	Title string
+	Lines []string // 正文，每行一项
+	HTML  string   // 可选的 HTML 正文，只用于邮件渠道
+	// 用于匹配规则的脚本和定时任务
+	ScriptID string
+	TaskID   string
+}
+
+// Text 纯文本正文
+func (m Message) Text() string {
+	return strings.Join(m.Lines, "\n")
+}
+
+// Service 通知服务
+type Service struct {
+	db          *storage.BoltDB
+	guard       *urlpolicy.NetworkGuard
+	stopDigests chan struct{}
+}
+
+// NewService 创建通知服务，guard 用于检查 Webhook 地址是否指向内网
+func NewService(db *storage.BoltDB, guard *urlpolicy.NetworkGuard) *Service {
+	return &Service{db: db, guard: guard}
+}
+
+// Notify 把消息发送到匹配规则的所有渠道；在后台发送，不阻塞调用方
+func (s *Service) Notify(msg Message) {
+	go s.dispatch(context.Background(), msg)
+}
+
+// dispatch 按规则查找渠道并逐个发送，同一渠道只发送一次，发送失败只记录日志
+func (s *Service) dispatch(ctx context.Context, msg Message) {
+	rules, err := s.db.ListNotificationRules()
+	if err != nil {
+		logger.Warn(ctx, "Failed to load notification rules: %v", err)
+		return
+	}
+
+	sent := make(map[string]bool)
+	for _, rule := range rules {
+		if !rule.Matches(msg.Event, msg.ScriptID, msg.TaskID) {
+			continue
+		}
+		for _, channelID := range rule.ChannelIDs {
+			if sent[channelID] {
+				continue
+			}
+			sent[channelID] = true
+
+			channel, err := s.db.GetNotificationChannel(channelID)
+			if err != nil {
+				logger.Warn(ctx, "Notification rule %s references missing channel %s", rule.Name, channelID)
+				continue
+			}
+			if !channel.Enabled {
+				continue
+			}
+			sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+			if err := Send(sendCtx, s.guard, channel, msg); err != nil {
+				logger.Warn(ctx, "Failed to send %s notification to channel %s: %v", msg.Event, channel.Name, err)
+			}
+			cancel()
+		}
+	}
+}
+
+// ScriptExecutionFinished 脚本执行结束，失败时发送 script.failed 通知，
+// 被验证码或反爬页面拦截时另外发送 page.blocked，抓取数据不符合 output_schema 时发送 schema.drift，
+// 抓取结果与历史执行相比异常时发送 data.anomaly
+func (s *Service) ScriptExecutionFinished(ctx context.Context, execution *models.ScriptExecution) {
+	if len(execution.Anomalies) > 0 {
+		lines := make([]string, 0, len(execution.Anomalies)+2)
+		for _, anomaly := range execution.Anomalies {
+			lines = append(lines, "Anomaly: "+anomaly)
+		}
+		lines = append(lines, "Execution: "+execution.ID, "Started: "+execution.StartTime.Format(time.RFC3339))
+		s.Notify(Message{
+			Event:    models.NotificationEventAnomaly,
+			Title:    "Suspect extraction result: " + execution.ScriptName,
+			Lines:    lines,
+			ScriptID: execution.ScriptID,
+		})
+	}
+	if execution.SchemaError != "" {
+		s.Notify(Message{
+			Event: models.NotificationEventSchemaDrift,
+			Title: "Extracted data does not match the schema: " + execution.ScriptName,
+			Lines: []string{
+				"Error: " + execution.SchemaError,
+				"Execution: " + execution.ID,
+				"Started: " + execution.StartTime.Format(time.RFC3339),
+			},
+			ScriptID: execution.ScriptID,
+		})
+	}
+	if execution.Success {
+		return
+	}
+	lines := []string{
+		"Error: " + execution.ErrorMsg,
+		fmt.Sprintf("Steps: %d succeeded, %d failed", execution.SuccessSteps, execution.FailedSteps),
+	}
+	if execution.InstanceName != "" {
+		lines = append(lines, "Instance: "+execution.InstanceName)
+	}
+	lines = append(lines, "Started: "+execution.StartTime.Format(time.RFC3339))
+	msg := Message{
+		Event:    models.NotificationEventScriptFailed,
+		Title:    "Script failed: " + execution.ScriptName,
+		Lines:    lines,
+		ScriptID: execution.ScriptID,
+	}
+	s.Notify(msg)
+	if execution.ErrorCode == models.ErrorCodeCaptchaDetected {
+		msg.Event = models.NotificationEventBlocked
+		msg.Title = "Script blocked by a CAPTCHA: " + execution.ScriptName
+		s.Notify(msg)
+	}
+}
+
+// TaskExecutionFinished 定时任务执行结束，发送 task.completed 通知，
+// 失败时另外发送 task.failed，被拦截时发送 page.blocked，监控任务检测到变化时发送 monitor.changed
+func (s *Service) TaskExecutionFinished(ctx context.Context, task *models.ScheduledTask, execution *models.TaskExecution) {
+	status := "succeeded"
+	if !execution.Success {
+		status = "failed"
+	}
+	lines := []string{
+		"Type: " + string(execution.ExecutionType),
+		"Duration: " + (time.Duration(execution.Duration) * time.Millisecond).String(),
+	}
+	if execution.ErrorMsg != "" {
+		lines = append(lines, "Error: "+execution.ErrorMsg)
+	}
+	msg := Message{
+		Event:    models.NotificationEventTaskCompleted,
+		Title:    fmt.Sprintf("Task %s: %s", status, task.Name),
+		Lines:    lines,
+		ScriptID: task.ScriptID,
+		TaskID:   task.ID,
+	}
+	s.Notify(msg)
+	if !execution.Success {
+		msg.Event = models.NotificationEventTaskFailed
+		s.Notify(msg)
+	}
+	if execution.ErrorCode == models.ErrorCodeCaptchaDetected {
+		msg.Event = models.NotificationEventBlocked
+		msg.Title = "Task blocked by a CAPTCHA: " + task.Name
+		s.Notify(msg)
+	}
+
+	if triggered, _ := execution.ResultData["triggered"].(bool); execution.ExecutionType == models.ExecutionTypeMonitor && triggered {
+		s.Notify(monitorMessage(task, execution.ResultData))
+	}
+}
+
+// monitorMessage 内容变化通知，列出变化比例和部分新增/删除的行
+func monitorMessage(task *models.ScheduledTask, result map[string]interface{}) Message {
+	lines := []string{"URL: " + task.MonitorURL}
+	if percent, ok := result["change_percent"].(float64); ok {
+		lines = append(lines, fmt.Sprintf("Change: %.1f%%", percent))
+	}
+	for _, section := range []struct{ key, label, prefix string }{
+		{"added", "Added:", "+ "},
+		{"removed", "Removed:", "- "},
+	} {
+		items := stringList(result[section.key])
+		if len(items) == 0 {
+			continue
+		}
+		lines = append(lines, section.label)
+		for i, item := range items {
+			if i == maxMessageLines {
+				lines = append(lines, fmt.Sprintf("… %d more", len(items)-maxMessageLines))
+				break
+			}
+			lines = append(lines, section.prefix+item)
+		}
+	}
+	return Message{
+		Event:  models.NotificationEventMonitorChanged,
+		Title:  "Content changed: " + task.Name,
+		Lines:  lines,
+		TaskID: task.ID,
+	}
+}
+
+// stringList 把 []string 或 JSON 解码得到的 []interface{} 转换为字符串列表
+func stringList(value interface{}) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, fmt.Sprint(item))
+		}
+		return items
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/storage"
+)
+
+// recorder 记录收到的 Webhook 请求
+type recorder struct {
+	mu       sync.Mutex
+	paths    []string
+	payloads []map[string]interface{}
+}
+
+func (r *recorder) server(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var payload map[string]interface{}
+		if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+		r.mu.Lock()
+		r.paths = append(r.paths, req.URL.Path)
+		r.payloads = append(r.payloads, payload)
+		r.mu.Unlock()
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSend(t *testing.T) {
+	var rec recorder
+	srv := rec.server(t)
+	telegramAPIBase = srv.URL
+	defer func() { telegramAPIBase = "https://api.telegram.org" }()
+
+	msg := Message{Title: "Script failed: login", Lines: []string{"Error: timeout", "Steps: 2 succeeded, 1 failed"}}
+	channels := []*models.NotificationChannel{
+		{Type: models.NotificationChannelSlack, WebhookURL: srv.URL + "/slack"},
+		{Type: models.NotificationChannelDiscord, WebhookURL: srv.URL + "/discord"},
+		{Type: models.NotificationChannelTelegram, BotToken: "123:abc", ChatID: "42"},
+	}
+	for _, channel := range channels {
+		if err := Send(context.Background(), nil, channel, msg); err != nil {
+			t.Fatalf("%s: %v", channel.Type, err)
+		}
+	}
+
+	want := []struct {
+		path, key, value string
+	}{
+		{"/slack", "text", "*Script failed: login*\nError: timeout\nSteps: 2 succeeded, 1 failed"},
+		{"/discord", "content", "**Script failed: login**\nError: timeout\nSteps: 2 succeeded, 1 failed"},
+		{"/bot123:abc/sendMessage", "text", "Script failed: login\n\nError: timeout\nSteps: 2 succeeded, 1 failed"},
+	}
+	for i, w := range want {
+		if rec.paths[i] != w.path || rec.payloads[i][w.key] != w.value {
+			t.Errorf("request %d: got %s %v", i, rec.paths[i], rec.payloads[i])
+		}
+	}
+	if rec.payloads[2]["chat_id"] != "42" {
+		t.Errorf("unexpected telegram chat_id %v", rec.payloads[2]["chat_id"])
+	}
+}
+
+func TestSendHidesTelegramToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"ok":false}`, http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+	telegramAPIBase = srv.URL
+	defer func() { telegramAPIBase = "https://api.telegram.org" }()
+
+	channel := &models.NotificationChannel{Type: models.NotificationChannelTelegram, BotToken: "secret-token", ChatID: "1"}
+	err := Send(context.Background(), nil, channel, Message{Title: "x"})
+	if err == nil || strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("expected an error without the token, got %v", err)
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	db, err := storage.NewBoltDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var rec recorder
+	srv := rec.server(t)
+	for _, channel := range []*models.NotificationChannel{
+		{ID: "ops", Name: "ops", Type: models.NotificationChannelSlack, Enabled: true, WebhookURL: srv.URL + "/ops"},
+		{ID: "team", Name: "team", Type: models.NotificationChannelDiscord, Enabled: true, WebhookURL: srv.URL + "/team"},
+		{ID: "off", Name: "off", Type: models.NotificationChannelSlack, Enabled: false, WebhookURL: srv.URL + "/off"},
+	} {
+		if err := db.SaveNotificationChannel(channel); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, rule := range []*models.NotificationRule{
+		{ID: "1", Name: "all failures", Enabled: true, Event: models.NotificationEventScriptFailed, ChannelIDs: []string{"ops", "off"}},
+		{ID: "2", Name: "login failures", Enabled: true, Event: models.NotificationEventScriptFailed, ScriptID: "login", ChannelIDs: []string{"ops", "team"}},
+		{ID: "3", Name: "disabled", Enabled: false, Event: models.NotificationEventScriptFailed, ChannelIDs: []string{"team"}},
+		{ID: "4", Name: "monitor", Enabled: true, Event: models.NotificationEventMonitorChanged, ChannelIDs: []string{"team"}},
+	} {
+		if err := db.SaveNotificationRule(rule); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewService(db, nil)
+	s.dispatch(context.Background(), Message{Event: models.NotificationEventScriptFailed, Title: "a", ScriptID: "checkout"})
+	s.dispatch(context.Background(), Message{Event: models.NotificationEventScriptFailed, Title: "b", ScriptID: "login"})
+
+	// checkout 只匹配规则 1；login 匹配规则 1 和 2，ops 只发送一次；禁用的渠道和规则不发送
+	got := strings.Join(rec.paths, ",")
+	if got != "/ops,/ops,/team" {
+		t.Errorf("unexpected deliveries %s", got)
+	}
+}
+
+func TestMonitorMessage(t *testing.T) {
+	task := &models.ScheduledTask{ID: "t1", Name: "price watch", MonitorURL: "https://example.com"}
+	added := make([]string, maxMessageLines+2)
+	for i := range added {
+		added[i] = "line"
+	}
+	msg := monitorMessage(task, map[string]interface{}{
+		"triggered":      true,
+		"change_percent": 12.5,
+		"added":          added,
+		"removed":        []interface{}{"old"},
+	})
+	if msg.Event != models.NotificationEventMonitorChanged || msg.TaskID != "t1" {
+		t.Errorf("unexpected message %+v", msg)
+	}
+	text := msg.Text()
+	for _, want := range []string{"URL: https://example.com", "Change: 12.5%", "… 2 more", "Removed:\n- old"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("message missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestValidateAndBuildEmail(t *testing.T) {
+	channel := &models.NotificationChannel{
+		Type:     models.NotificationChannelEmail,
+		SMTPHost: "smtp.example.com",
+		From:     "BrowserWing <bot@example.com>",
+		To:       []string{"ops@example.com"},
+	}
+	if err := Validate(channel); err != nil {
+		t.Fatal(err)
+	}
+	if err := Validate(&models.NotificationChannel{Type: models.NotificationChannelEmail, SMTPHost: "h", From: "bot@example.com", To: []string{"not an address"}}); err == nil {
+		t.Error("expected invalid recipient error")
+	}
+	if err := Validate(&models.NotificationChannel{Type: models.NotificationChannelSlack, WebhookURL: "ftp://x"}); err == nil {
+		t.Error("expected invalid webhook error")
+	}
+
+	mail := string(buildEmail(channel, Message{Title: "任务失败", Lines: []string{"a", "b"}}, time.Unix(0, 0).UTC()))
+	for _, want := range []string{
+		"To: ops@example.com\r\n",
+		"Subject: =?utf-8?q?[BrowserWing]_=E4=BB=BB=E5=8A=A1=E5=A4=B1=E8=B4=A5?=\r\n",
+		"\r\n\r\na\r\nb\r\n",
+	} {
+		if !strings.Contains(mail, want) {
+			t.Errorf("email missing %q:\n%s", want, mail)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package templates
+
+import "github.com/browserwing/browserwing/models"
+
+// param 模板参数说明（写入 MCP 输入定义，执行参数表单和 MCP 命令都会使用）
+func param(description string) map[string]interface{} {
+	return map[string]interface{}{"type": "string", "description": description}
+}
+
+// inputSchema 模板参数的 MCP 输入定义
+func inputSchema(properties map[string]interface{}, required ...string) map[string]interface{} {
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		names := make([]interface{}, len(required))
+		for i, name := range required {
+			names[i] = name
+		}
+		schema["required"] = names
+	}
+	return schema
+}
+
+// 模板中的 JavaScript 不能使用模板字符串插值语法，${...} 会被当作脚本变量替换
+
+// googleSearchJS 等待搜索结果出现后提取标题、链接和摘要
+const googleSearchJS = `() => (async () => {
+  for (let i = 0; i < 50 && !document.querySelector('#search'); i++) {
+    await new Promise((resolve) => setTimeout(resolve, 200));
+  }
+  const limit = Number('${limit}') || 10;
+  const results = [];
+  for (const heading of document.querySelectorAll('#search a h3')) {
+    const link = heading.closest('a');
+    const block = link.closest('[data-hveid]') || link.parentElement;
+    const snippet = block && block.querySelector('[data-sncf], .VwiC3b');
+    results.push({
+      title: heading.innerText.trim(),
+      url: link.href,
+      snippet: snippet ? snippet.innerText.trim() : '',
+    });
+    if (results.length >= limit) break;
+  }
+  return results;
+})()`
+
+// loginCheckJS 提交后检查是否仍停留在登录表单
+const loginCheckJS = `() => {
+  const field = document.querySelector('input[type="password"]');
+  return {
+    url: location.href,
+    title: document.title,
+    password_field_visible: !!(field && field.offsetParent),
+  };
+}`
+
+// sitemapCrawlJS 读取站点地图（支持站点地图索引），再逐个抓取页面的标题和描述
+const sitemapCrawlJS = `() => (async () => {
+  const limit = Number('${max_pages}') || 50;
+  const parser = new DOMParser();
+  const texts = (doc, selector) => [...doc.querySelectorAll(selector)].map((node) => node.textContent.trim());
+
+  const queue = [location.href];
+  const visited = new Set();
+  const urls = [];
+  while (queue.length && urls.length < limit) {
+    const sitemap = queue.shift();
+    if (visited.has(sitemap)) continue;
+    visited.add(sitemap);
+    const response = await fetch(sitemap);
+    const xml = parser.parseFromString(await response.text(), 'application/xml');
+    queue.push(...texts(xml, 'sitemap > loc'));
+    urls.push(...texts(xml, 'url > loc'));
+  }
+
+  const pages = [];
+  for (const url of urls.slice(0, limit)) {
+    try {
+      const response = await fetch(url);
+      const doc = parser.parseFromString(await response.text(), 'text/html');
+      const meta = doc.querySelector('meta[name="description"]');
+      pages.push({
+        url,
+        status: response.status,
+        title: doc.title.trim(),
+        description: meta ? meta.content.trim() : '',
+      });
+    } catch (error) {
+      pages.push({ url, error: String(error) });
+    }
+  }
+  return pages;
+})()`
+
+// formFillJS 按字段名、id、placeholder、aria-label、标签文本或 CSS 选择器查找表单字段并填写
+const formFillJS = `() => {
+  const fields = ${fields};
+  const find = (key) => {
+    const quoted = '"' + CSS.escape(key) + '"';
+    const field = document.querySelector('[name=' + quoted + '], [id=' + quoted + '], [placeholder=' + quoted + '], [aria-label=' + quoted + ']');
+    if (field) return field;
+    for (const label of document.querySelectorAll('label')) {
+      if (label.control && label.textContent.trim().toLowerCase() === key.toLowerCase()) return label.control;
+    }
+    try {
+      return document.querySelector(key);
+    } catch (error) {
+      return null;
+    }
+  };
+  const setValue = (field, value) => {
+    if (field.type === 'radio') {
+      const options = document.querySelectorAll('input[type="radio"][name="' + CSS.escape(field.name) + '"]');
+      field = [...options].find((option) => option.value === String(value)) || field;
+      field.checked = true;
+    } else if (field.type === 'checkbox') {
+      field.checked = value === true || value === 'true' || value === field.value;
+    } else {
+      const prototype = Object.getPrototypeOf(field);
+      const setter = Object.getOwnPropertyDescriptor(prototype, 'value');
+      setter && setter.set ? setter.set.call(field, String(value)) : (field.value = String(value));
+    }
+    field.dispatchEvent(new Event('input', { bubbles: true }));
+    field.dispatchEvent(new Event('change', { bubbles: true }));
+  };
+
+  const result = { filled: [], missing: [] };
+  for (const [key, value] of Object.entries(fields)) {
+    const field = find(key);
+    if (!field) {
+      result.missing.push(key);
+      continue;
+    }
+    setValue(field, value);
+    result.filled.push(key);
+  }
+  return result;
+}`
+
+// gallery 内置模板
+var gallery = []models.ScriptTemplate{
+	{
+		ID:          "google-search",
+		Name:        "Google search results",
+		Description: "Search Google and extract the title, URL and snippet of the top results.",
+		Category:    "search",
+		Tags:        []string{"google", "serp", "extract"},
+		Script: models.Script{
+			Name:        "Google search results",
+			Description: "Extracts the top Google results for a query into the `results` variable.",
+			URL:         "https://www.google.com/search?q=${query}&hl=${language}",
+			CanFetch:    true,
+			Actions: []models.ScriptAction{
+				{Type: "execute_js", JSCode: googleSearchJS, VariableName: "results", Description: "Extract search results"},
+			},
+			Variables: map[string]string{"language": "en", "limit": "10"},
+			MCPInputSchema: inputSchema(map[string]interface{}{
+				"query":    param("Search query"),
+				"language": param("Interface language code, e.g. en or zh-CN"),
+				"limit":    param("Maximum number of results to extract"),
+			}, "query"),
+		},
+	},
+	{
+		ID:          "generic-login",
+		Name:        "Generic login",
+		Description: "Fill a username/password form and submit it, so later scripts reuse the logged-in session. Pass the password at run time instead of saving it as a default.",
+		Category:    "login",
+		Tags:        []string{"login", "session"},
+		Script: models.Script{
+			Name:        "Generic login",
+			Description: "Logs in with a username and password. The `login` variable reports the page reached after submitting and whether the password field is still visible.",
+			URL:         "${login_url}",
+			Actions: []models.ScriptAction{
+				{Type: "input", Selector: "${username_selector}", Value: "${username}", Description: "Enter username"},
+				{Type: "input", Selector: "${password_selector}", Value: "${password}", Description: "Enter password"},
+				{Type: "click", Selector: "${submit_selector}", Description: "Submit the login form"},
+				{Type: "sleep", Duration: 3000},
+				{Type: "execute_js", JSCode: loginCheckJS, VariableName: "login", Description: "Check the login result"},
+			},
+			Variables: map[string]string{
+				"username_selector": `input[type="email"], input[name="username"], input[name="email"], input[autocomplete="username"]`,
+				"password_selector": `input[type="password"]`,
+				"submit_selector":   `button[type="submit"], input[type="submit"]`,
+			},
+			MCPInputSchema: inputSchema(map[string]interface{}{
+				"login_url":         param("URL of the login page"),
+				"username":          param("Username or email"),
+				"password":          param("Password"),
+				"username_selector": param("CSS selector of the username field"),
+				"password_selector": param("CSS selector of the password field"),
+				"submit_selector":   param("CSS selector of the submit button"),
+			}, "login_url", "username", "password"),
+		},
+	},
+	{
+		ID:          "sitemap-crawl",
+		Name:        "Sitemap crawl",
+		Description: "Read a sitemap (including sitemap indexes) and collect the status, title and meta description of each page.",
+		Category:    "crawl",
+		Tags:        []string{"sitemap", "seo", "crawl"},
+		Script: models.Script{
+			Name:        "Sitemap crawl",
+			Description: "Crawls the pages listed in a sitemap into the `pages` variable. Pages on other origins are reported with an error.",
+			URL:         "${sitemap_url}",
+			CanFetch:    true,
+			Actions: []models.ScriptAction{
+				{Type: "execute_js", JSCode: sitemapCrawlJS, VariableName: "pages", Description: "Crawl sitemap pages"},
+			},
+			Variables: map[string]string{"max_pages": "50"},
+			MCPInputSchema: inputSchema(map[string]interface{}{
+				"sitemap_url": param("URL of the sitemap, e.g. https://example.com/sitemap.xml"),
+				"max_pages":   param("Maximum number of pages to crawl"),
+			}, "sitemap_url"),
+		},
+	},
+	{
+		ID:          "form-fill",
+		Name:        "Form fill",
+		Description: "Fill a web form from a JSON object of field values and optionally submit it.",
+		Category:    "form",
+		Tags:        []string{"form", "input"},
+		Script: models.Script{
+			Name:        "Form fill",
+			Description: "Fills the fields of a form. Keys of `fields` match a field's name, id, placeholder, aria-label, label text or a CSS selector. The `form` variable lists filled and missing fields.",
+			URL:         "${form_url}",
+			Actions: []models.ScriptAction{
+				{Type: "execute_js", JSCode: formFillJS, VariableName: "form", Description: "Fill form fields"},
+				{
+					Type:        "click",
+					Selector:    "${submit_selector}",
+					Description: "Submit the form",
+					Condition:   &models.ActionCondition{Variable: "submit", Operator: "=", Value: "true", Enabled: true},
+				},
+			},
+			Variables: map[string]string{
+				"fields":          "{}",
+				"submit":          "false",
+				"submit_selector": `button[type="submit"], input[type="submit"]`,
+			},
+			MCPInputSchema: inputSchema(map[string]interface{}{
+				"form_url":        param("URL of the page with the form"),
+				"fields":          param(`JSON object of field values, e.g. {"email": "me@example.com", "Country": "Japan"}`),
+				"submit":          param("Set to true to click the submit button after filling"),
+				"submit_selector": param("CSS selector of the submit button"),
+			}, "form_url"),
+		},
+	},
+}
@@ -0,0 +1,82 @@
+// Package templates 内置的脚本模板库：常见自动化场景的参数化脚本，安装后成为用户的普通脚本
+package templates
+
+import (
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/browserwing/browserwing/models"
+	"github.com/browserwing/browserwing/services/browser"
+	"github.com/google/uuid"
+)
+
+// defaultGroup 安装模板时未指定分组使用的分组
+const defaultGroup = "Templates"
+
+// InstallOptions 安装模板的选项
+type InstallOptions struct {
+	Name   string            `json:"name"`   // 脚本名称，为空时使用模板名称
+	Group  string            `json:"group"`  // 脚本分组，为空时为 Templates
+	Params map[string]string `json:"params"` // 参数值，保存为脚本的预设变量；未指定的必填参数在每次执行时传入
+}
+
+// List 返回全部模板，按分类和名称排序
+func List() []models.ScriptTemplate {
+	list := make([]models.ScriptTemplate, 0, len(gallery))
+	for _, tpl := range gallery {
+		list = append(list, tpl)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Category != list[j].Category {
+			return list[i].Category < list[j].Category
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// Get 按 ID 获取模板
+func Get(id string) (*models.ScriptTemplate, bool) {
+	for i := range gallery {
+		if gallery[i].ID == id {
+			tpl := gallery[i]
+			return &tpl, true
+		}
+	}
+	return nil, false
+}
+
+// Parameters 模板参数的 JSON Schema，与自动化接口中脚本的参数定义一致
+func Parameters(tpl *models.ScriptTemplate) map[string]interface{} {
+	return browser.ScriptParameterSchema(&tpl.Script)
+}
+
+// Instantiate 用模板创建新脚本（不保存），参数值合并到模板默认值中
+func Instantiate(tpl *models.ScriptTemplate, opts InstallOptions) (*models.Script, error) {
+	properties, _ := Parameters(tpl)["properties"].(map[string]interface{})
+	for name := range opts.Params {
+		if _, ok := properties[name]; !ok || name == "url" {
+			return nil, fmt.Errorf("unknown parameter %q", name)
+		}
+	}
+
+	script := tpl.Script.Copy()
+	now := time.Now()
+	script.ID = uuid.New().String()
+	script.Name = strings.TrimSpace(opts.Name)
+	if script.Name == "" {
+		script.Name = tpl.Name
+	}
+	script.Group = strings.TrimSpace(opts.Group)
+	if script.Group == "" {
+		script.Group = defaultGroup
+	}
+	script.Tags = append(script.Tags, "template:"+tpl.ID)
+	maps.Copy(script.Variables, opts.Params)
+	script.CreatedAt = now
+	script.UpdatedAt = now
+	return script, nil
+}
@@ -0,0 +1,71 @@
+package templates
+
+import (
+	"testing"
+)
+
+func TestGalleryParametersDocumented(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, tpl := range List() {
+		if seen[tpl.ID] {
+			t.Fatalf("duplicate template id %s", tpl.ID)
+		}
+		seen[tpl.ID] = true
+
+		// 每个占位符都要有说明，避免 JavaScript 中误用 ${...} 产生意外的参数
+		properties := Parameters(&tpl)["properties"].(map[string]interface{})
+		for name, def := range properties {
+			if name == "url" {
+				continue
+			}
+			if _, ok := def.(map[string]interface{})["description"]; !ok {
+				t.Errorf("%s: parameter %s has no description", tpl.ID, name)
+			}
+		}
+		for name := range tpl.Script.Variables {
+			if _, ok := properties[name]; !ok {
+				t.Errorf("%s: default for unknown parameter %s", tpl.ID, name)
+			}
+		}
+	}
+	if len(seen) < 4 {
+		t.Fatalf("expected at least 4 templates, got %d", len(seen))
+	}
+}
+
+func TestInstantiate(t *testing.T) {
+	tpl, ok := Get("google-search")
+	if !ok {
+		t.Fatal("google-search template not found")
+	}
+
+	script, err := Instantiate(tpl, InstallOptions{Params: map[string]string{"query": "browserwing", "limit": "5"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if script.ID == "" || script.Name != tpl.Name || script.Group != defaultGroup {
+		t.Fatalf("script = %+v", script)
+	}
+	if script.Variables["query"] != "browserwing" || script.Variables["limit"] != "5" || script.Variables["language"] != "en" {
+		t.Fatalf("variables = %v", script.Variables)
+	}
+	// 不修改模板本身
+	if _, ok := tpl.Script.Variables["query"]; ok {
+		t.Fatal("template defaults modified")
+	}
+	if again, _ := Get("google-search"); again.Script.Variables["limit"] != "10" {
+		t.Fatal("gallery modified")
+	}
+
+	named, err := Instantiate(tpl, InstallOptions{Name: "My search", Group: "research"})
+	if err != nil || named.Name != "My search" || named.Group != "research" {
+		t.Fatalf("Instantiate() = %+v, %v", named, err)
+	}
+
+	if _, err := Instantiate(tpl, InstallOptions{Params: map[string]string{"qeury": "typo"}}); err == nil {
+		t.Fatal("expected unknown parameter to be rejected")
+	}
+	if _, ok := Get("missing"); ok {
+		t.Fatal("expected missing template")
+	}
+}
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"path/filepath"
@@ -30,6 +31,19 @@ var (
 	apiKeysBucket           = []byte("api_keys")
 	scheduledTasksBucket    = []byte("scheduled_tasks")
 	taskExecutionsBucket    = []byte("task_executions")
+	monitorSnapshotsBucket  = []byte("monitor_snapshots")
+	pageScreenshotsBucket   = []byte("page_screenshots")
+	pageComponentsBucket    = []byte("page_components")
+	environmentsBucket      = []byte("environments")
+	notifyChannelsBucket    = []byte("notification_channels")
+	notifyRulesBucket       = []byte("notification_rules")
+	notifyDigestsBucket     = []byte("notification_digests")
+	installedBundlesBucket  = []byte("installed_bundles")
+	uiLocalesBucket         = []byte("ui_locales")
+	scriptStatesBucket      = []byte("script_states")
+	llmRoutingBucket        = []byte("llm_routing")
+	siteKnowledgeBucket     = []byte("site_knowledge")
+	scriptHealthBucket      = []byte("script_health")
 )
 
 type BoltDB struct {
@@ -110,6 +124,58 @@ func NewBoltDB(dbPath string) (*BoltDB, error) {
 			return err
 		}
 		_, err = tx.CreateBucketIfNotExists(taskExecutionsBucket)
+		if err != nil {
+			return err
+		}
+		_, err = tx.CreateBucketIfNotExists(monitorSnapshotsBucket)
+		if err != nil {
+			return err
+		}
+		_, err = tx.CreateBucketIfNotExists(pageScreenshotsBucket)
+		if err != nil {
+			return err
+		}
+		_, err = tx.CreateBucketIfNotExists(pageComponentsBucket)
+		if err != nil {
+			return err
+		}
+		_, err = tx.CreateBucketIfNotExists(environmentsBucket)
+		if err != nil {
+			return err
+		}
+		_, err = tx.CreateBucketIfNotExists(notifyChannelsBucket)
+		if err != nil {
+			return err
+		}
+		_, err = tx.CreateBucketIfNotExists(notifyRulesBucket)
+		if err != nil {
+			return err
+		}
+		_, err = tx.CreateBucketIfNotExists(notifyDigestsBucket)
+		if err != nil {
+			return err
+		}
+		_, err = tx.CreateBucketIfNotExists(installedBundlesBucket)
+		if err != nil {
+			return err
+		}
+		_, err = tx.CreateBucketIfNotExists(uiLocalesBucket)
+		if err != nil {
+			return err
+		}
+		_, err = tx.CreateBucketIfNotExists(scriptStatesBucket)
+		if err != nil {
+			return err
+		}
+		_, err = tx.CreateBucketIfNotExists(llmRoutingBucket)
+		if err != nil {
+			return err
+		}
+		_, err = tx.CreateBucketIfNotExists(siteKnowledgeBucket)
+		if err != nil {
+			return err
+		}
+		_, err = tx.CreateBucketIfNotExists(scriptHealthBucket)
 		return err
 	})
 	if err != nil {
@@ -237,7 +303,11 @@ func (b *BoltDB) UpdateScript(script *models.Script) error {
 func (b *BoltDB) DeleteScript(id string) error {
 	return b.db.Update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(scriptsBucket)
-		return bucket.Delete([]byte(id))
+		if err := bucket.Delete([]byte(id)); err != nil {
+			return err
+		}
+		// 同时删除脚本的增量抓取状态
+		return tx.Bucket(scriptStatesBucket).Delete([]byte(id))
 	})
 }
 
@@ -712,6 +782,38 @@ func (b *BoltDB) ListScriptExecutions(scriptID string) ([]*models.ScriptExecutio
 	return executions, nil
 }
 
+// RecentScriptExecutions 返回脚本的执行记录，按开始时间倒序，limit 大于 0 时最多返回 limit 条；
+// 执行记录 ID 以 "脚本ID-" 开头，按前缀查找，不遍历其他脚本的记录
+func (b *BoltDB) RecentScriptExecutions(scriptID string, limit int) ([]*models.ScriptExecution, error) {
+	var executions []*models.ScriptExecution
+	prefix := []byte(scriptID + "-")
+	err := b.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(scriptExecutionsBucket).Cursor()
+		for k, v := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = cursor.Next() {
+			var execution models.ScriptExecution
+			if err := json.Unmarshal(v, &execution); err != nil {
+				return err
+			}
+			// 其他脚本的 ID 可能以该脚本 ID 加 "-" 开头
+			if execution.ScriptID == scriptID {
+				executions = append(executions, &execution)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(executions, func(i, j int) bool {
+		return executions[i].StartTime.After(executions[j].StartTime)
+	})
+	if limit > 0 && len(executions) > limit {
+		executions = executions[:limit]
+	}
+	return executions, nil
+}
+
 // DeleteScriptExecution 删除脚本执行记录
 func (b *BoltDB) DeleteScriptExecution(id string) error {
 	return b.db.Update(func(tx *bolt.Tx) error {
@@ -1791,3 +1893,723 @@ func (db *BoltDB) BatchDeleteTaskExecutions(ids []string) error {
 		return nil
 	})
 }
+
+// ================== Monitor Snapshots ==================
+
+// GetMonitorSnapshot 获取内容监控任务的最近快照，不存在时返回 nil
+func (db *BoltDB) GetMonitorSnapshot(taskID string) (*models.MonitorSnapshot, error) {
+	var snapshot *models.MonitorSnapshot
+	err := db.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(monitorSnapshotsBucket)
+		data := bucket.Get([]byte(taskID))
+		if data == nil {
+			return nil
+		}
+		snapshot = &models.MonitorSnapshot{}
+		return json.Unmarshal(data, snapshot)
+	})
+	return snapshot, err
+}
+
+// SaveMonitorSnapshot 保存内容监控任务的快照
+func (db *BoltDB) SaveMonitorSnapshot(snapshot *models.MonitorSnapshot) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(monitorSnapshotsBucket)
+		data, err := json.Marshal(snapshot)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(snapshot.TaskID), data)
+	})
+}
+
+// DeleteMonitorSnapshot 删除内容监控任务的快照
+func (db *BoltDB) DeleteMonitorSnapshot(taskID string) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(monitorSnapshotsBucket)
+		return bucket.Delete([]byte(taskID))
+	})
+}
+
+// ================== Page Screenshots ==================
+
+// CreatePageScreenshot 保存截图存档记录
+func (db *BoltDB) CreatePageScreenshot(shot *models.PageScreenshot) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(pageScreenshotsBucket)
+		data, err := json.Marshal(shot)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(shot.ID), data)
+	})
+}
+
+// GetPageScreenshot 获取截图存档记录
+func (db *BoltDB) GetPageScreenshot(id string) (*models.PageScreenshot, error) {
+	var shot models.PageScreenshot
+	err := db.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(pageScreenshotsBucket)
+		data := bucket.Get([]byte(id))
+		if data == nil {
+			return fmt.Errorf("page screenshot not found")
+		}
+		return json.Unmarshal(data, &shot)
+	})
+	return &shot, err
+}
+
+// ListPageScreenshots 列出任务的截图存档记录（url 为空时不过滤），按创建时间降序
+func (db *BoltDB) ListPageScreenshots(taskID string, url string) ([]models.PageScreenshot, error) {
+	shots := []models.PageScreenshot{}
+	err := db.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(pageScreenshotsBucket)
+		return bucket.ForEach(func(k, v []byte) error {
+			var shot models.PageScreenshot
+			if err := json.Unmarshal(v, &shot); err != nil {
+				return err
+			}
+			if shot.TaskID != taskID || (url != "" && shot.URL != url) {
+				return nil
+			}
+			shots = append(shots, shot)
+			return nil
+		})
+	})
+
+	sort.Slice(shots, func(i, j int) bool {
+		return shots[i].CreatedAt.After(shots[j].CreatedAt)
+	})
+
+	return shots, err
+}
+
+// DeletePageScreenshot 删除截图存档记录
+func (db *BoltDB) DeletePageScreenshot(id string) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(pageScreenshotsBucket)
+		return bucket.Delete([]byte(id))
+	})
+}
+
+// ================== Page Components ==================
+
+// SavePageComponent 保存页面组件
+func (db *BoltDB) SavePageComponent(component *models.PageComponent) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(pageComponentsBucket)
+		data, err := json.Marshal(component)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(component.ID), data)
+	})
+}
+
+// GetPageComponent 获取页面组件
+func (db *BoltDB) GetPageComponent(id string) (*models.PageComponent, error) {
+	var component models.PageComponent
+	err := db.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(pageComponentsBucket)
+		data := bucket.Get([]byte(id))
+		if data == nil {
+			return fmt.Errorf("page component not found")
+		}
+		return json.Unmarshal(data, &component)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &component, nil
+}
+
+// ListPageComponents 列出所有页面组件，按站点和名称排序
+func (db *BoltDB) ListPageComponents() ([]*models.PageComponent, error) {
+	components := []*models.PageComponent{}
+	err := db.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(pageComponentsBucket)
+		return bucket.ForEach(func(k, v []byte) error {
+			var component models.PageComponent
+			if err := json.Unmarshal(v, &component); err != nil {
+				return err
+			}
+			components = append(components, &component)
+			return nil
+		})
+	})
+
+	sort.Slice(components, func(i, j int) bool {
+		if components[i].Site != components[j].Site {
+			return components[i].Site < components[j].Site
+		}
+		return components[i].Name < components[j].Name
+	})
+
+	return components, err
+}
+
+// DeletePageComponent 删除页面组件
+func (db *BoltDB) DeletePageComponent(id string) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(pageComponentsBucket)
+		return bucket.Delete([]byte(id))
+	})
+}
+
+// ================== Environments ==================
+
+// SaveEnvironment 保存执行环境
+func (db *BoltDB) SaveEnvironment(env *models.Environment) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(environmentsBucket)
+		data, err := json.Marshal(env)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(env.ID), data)
+	})
+}
+
+// GetEnvironment 获取执行环境
+func (db *BoltDB) GetEnvironment(id string) (*models.Environment, error) {
+	var env models.Environment
+	err := db.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(environmentsBucket)
+		data := bucket.Get([]byte(id))
+		if data == nil {
+			return fmt.Errorf("environment not found")
+		}
+		return json.Unmarshal(data, &env)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &env, nil
+}
+
+// GetEnvironmentByName 按名称获取执行环境（不区分大小写）
+func (db *BoltDB) GetEnvironmentByName(name string) (*models.Environment, error) {
+	envs, err := db.ListEnvironments()
+	if err != nil {
+		return nil, err
+	}
+	for _, env := range envs {
+		if strings.EqualFold(env.Name, name) {
+			return env, nil
+		}
+	}
+	return nil, fmt.Errorf("environment not found")
+}
+
+// ListEnvironments 列出所有执行环境，按名称排序
+func (db *BoltDB) ListEnvironments() ([]*models.Environment, error) {
+	envs := []*models.Environment{}
+	err := db.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(environmentsBucket)
+		return bucket.ForEach(func(k, v []byte) error {
+			var env models.Environment
+			if err := json.Unmarshal(v, &env); err != nil {
+				return err
+			}
+			envs = append(envs, &env)
+			return nil
+		})
+	})
+
+	sort.Slice(envs, func(i, j int) bool {
+		return envs[i].Name < envs[j].Name
+	})
+
+	return envs, err
+}
+
+// DeleteEnvironment 删除执行环境
+func (db *BoltDB) DeleteEnvironment(id string) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(environmentsBucket)
+		return bucket.Delete([]byte(id))
+	})
+}
+
+// ================== Notifications ==================
+
+// SaveNotificationChannel 保存通知渠道
+func (db *BoltDB) SaveNotificationChannel(channel *models.NotificationChannel) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(notifyChannelsBucket)
+		data, err := json.Marshal(channel)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(channel.ID), data)
+	})
+}
+
+// GetNotificationChannel 获取通知渠道
+func (db *BoltDB) GetNotificationChannel(id string) (*models.NotificationChannel, error) {
+	var channel models.NotificationChannel
+	err := db.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(notifyChannelsBucket)
+		data := bucket.Get([]byte(id))
+		if data == nil {
+			return fmt.Errorf("notification channel not found")
+		}
+		return json.Unmarshal(data, &channel)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &channel, nil
+}
+
+// ListNotificationChannels 列出所有通知渠道，按名称排序
+func (db *BoltDB) ListNotificationChannels() ([]*models.NotificationChannel, error) {
+	channels := []*models.NotificationChannel{}
+	err := db.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(notifyChannelsBucket)
+		return bucket.ForEach(func(k, v []byte) error {
+			var channel models.NotificationChannel
+			if err := json.Unmarshal(v, &channel); err != nil {
+				return err
+			}
+			channels = append(channels, &channel)
+			return nil
+		})
+	})
+
+	sort.Slice(channels, func(i, j int) bool {
+		return channels[i].Name < channels[j].Name
+	})
+
+	return channels, err
+}
+
+// DeleteNotificationChannel 删除通知渠道
+func (db *BoltDB) DeleteNotificationChannel(id string) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(notifyChannelsBucket)
+		return bucket.Delete([]byte(id))
+	})
+}
+
+// SaveNotificationRule 保存通知规则
+func (db *BoltDB) SaveNotificationRule(rule *models.NotificationRule) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(notifyRulesBucket)
+		data, err := json.Marshal(rule)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(rule.ID), data)
+	})
+}
+
+// GetNotificationRule 获取通知规则
+func (db *BoltDB) GetNotificationRule(id string) (*models.NotificationRule, error) {
+	var rule models.NotificationRule
+	err := db.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(notifyRulesBucket)
+		data := bucket.Get([]byte(id))
+		if data == nil {
+			return fmt.Errorf("notification rule not found")
+		}
+		return json.Unmarshal(data, &rule)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// ListNotificationRules 列出所有通知规则，按名称排序
+func (db *BoltDB) ListNotificationRules() ([]*models.NotificationRule, error) {
+	rules := []*models.NotificationRule{}
+	err := db.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(notifyRulesBucket)
+		return bucket.ForEach(func(k, v []byte) error {
+			var rule models.NotificationRule
+			if err := json.Unmarshal(v, &rule); err != nil {
+				return err
+			}
+			rules = append(rules, &rule)
+			return nil
+		})
+	})
+
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].Name < rules[j].Name
+	})
+
+	return rules, err
+}
+
+// DeleteNotificationRule 删除通知规则
+func (db *BoltDB) DeleteNotificationRule(id string) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(notifyRulesBucket)
+		return bucket.Delete([]byte(id))
+	})
+}
+
+// SaveNotificationDigest 保存执行报告摘要配置
+func (db *BoltDB) SaveNotificationDigest(digest *models.NotificationDigest) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(notifyDigestsBucket)
+		data, err := json.Marshal(digest)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(digest.ID), data)
+	})
+}
+
+// GetNotificationDigest 获取执行报告摘要配置
+func (db *BoltDB) GetNotificationDigest(id string) (*models.NotificationDigest, error) {
+	var digest models.NotificationDigest
+	err := db.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(notifyDigestsBucket)
+		data := bucket.Get([]byte(id))
+		if data == nil {
+			return fmt.Errorf("notification digest not found")
+		}
+		return json.Unmarshal(data, &digest)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &digest, nil
+}
+
+// ListNotificationDigests 列出所有执行报告摘要配置，按名称排序
+func (db *BoltDB) ListNotificationDigests() ([]*models.NotificationDigest, error) {
+	digests := []*models.NotificationDigest{}
+	err := db.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(notifyDigestsBucket)
+		return bucket.ForEach(func(k, v []byte) error {
+			var digest models.NotificationDigest
+			if err := json.Unmarshal(v, &digest); err != nil {
+				return err
+			}
+			digests = append(digests, &digest)
+			return nil
+		})
+	})
+
+	sort.Slice(digests, func(i, j int) bool {
+		return digests[i].Name < digests[j].Name
+	})
+
+	return digests, err
+}
+
+// DeleteNotificationDigest 删除执行报告摘要配置
+func (db *BoltDB) DeleteNotificationDigest(id string) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(notifyDigestsBucket)
+		return bucket.Delete([]byte(id))
+	})
+}
+
+// SaveInstalledBundle 保存已安装的脚本包
+func (db *BoltDB) SaveInstalledBundle(bundle *models.InstalledBundle) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(installedBundlesBucket)
+		data, err := json.Marshal(bundle)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(bundle.ID), data)
+	})
+}
+
+// GetInstalledBundle 获取已安装的脚本包
+func (db *BoltDB) GetInstalledBundle(id string) (*models.InstalledBundle, error) {
+	var bundle models.InstalledBundle
+	err := db.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(installedBundlesBucket)
+		data := bucket.Get([]byte(id))
+		if data == nil {
+			return fmt.Errorf("installed bundle not found")
+		}
+		return json.Unmarshal(data, &bundle)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+// ListInstalledBundles 列出所有已安装的脚本包，按名称排序
+func (db *BoltDB) ListInstalledBundles() ([]*models.InstalledBundle, error) {
+	bundles := []*models.InstalledBundle{}
+	err := db.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(installedBundlesBucket)
+		return bucket.ForEach(func(k, v []byte) error {
+			var bundle models.InstalledBundle
+			if err := json.Unmarshal(v, &bundle); err != nil {
+				return err
+			}
+			bundles = append(bundles, &bundle)
+			return nil
+		})
+	})
+
+	sort.Slice(bundles, func(i, j int) bool {
+		return bundles[i].Name < bundles[j].Name
+	})
+
+	return bundles, err
+}
+
+// DeleteInstalledBundle 删除已安装脚本包的记录（不删除脚本）
+func (db *BoltDB) DeleteInstalledBundle(id string) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(installedBundlesBucket)
+		return bucket.Delete([]byte(id))
+	})
+}
+
+// SaveUILocale 保存界面语言
+func (db *BoltDB) SaveUILocale(locale *models.UILocale) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(uiLocalesBucket)
+		data, err := json.Marshal(locale)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(locale.Language), data)
+	})
+}
+
+// GetUILocale 获取界面语言
+func (db *BoltDB) GetUILocale(language string) (*models.UILocale, error) {
+	var locale models.UILocale
+	err := db.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(uiLocalesBucket)
+		data := bucket.Get([]byte(language))
+		if data == nil {
+			return fmt.Errorf("ui locale not found")
+		}
+		return json.Unmarshal(data, &locale)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &locale, nil
+}
+
+// ListUILocales 列出全部界面语言，按语言代码排序
+func (db *BoltDB) ListUILocales() ([]*models.UILocale, error) {
+	locales := []*models.UILocale{}
+	err := db.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(uiLocalesBucket)
+		return bucket.ForEach(func(k, v []byte) error {
+			var locale models.UILocale
+			if err := json.Unmarshal(v, &locale); err != nil {
+				return err
+			}
+			locales = append(locales, &locale)
+			return nil
+		})
+	})
+	return locales, err
+}
+
+// DeleteUILocale 删除界面语言
+func (db *BoltDB) DeleteUILocale(language string) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(uiLocalesBucket)
+		return bucket.Delete([]byte(language))
+	})
+}
+
+// GetScriptState 获取脚本的增量抓取状态，从未保存过时返回空状态
+func (db *BoltDB) GetScriptState(scriptID string) (*models.ScriptState, error) {
+	state := &models.ScriptState{ScriptID: scriptID}
+	err := db.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(scriptStatesBucket).Get([]byte(scriptID))
+		if data == nil {
+			return nil
+		}
+		return json.Unmarshal(data, state)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// SaveScriptState 保存脚本的增量抓取状态
+func (db *BoltDB) SaveScriptState(state *models.ScriptState) error {
+	state.UpdatedAt = time.Now()
+	return db.db.Update(func(tx *bolt.Tx) error {
+		data, err := json.Marshal(state)
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(scriptStatesBucket).Put([]byte(state.ScriptID), data)
+	})
+}
+
+// DeleteScriptState 清空脚本的增量抓取状态
+func (db *BoltDB) DeleteScriptState(scriptID string) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(scriptStatesBucket).Delete([]byte(scriptID))
+	})
+}
+
+// llmRoutingKey LLM 路由策略在 bucket 中的键（只有一份全局策略）
+var llmRoutingKey = []byte("default")
+
+// GetLLMRoutingPolicy 获取 LLM 路由策略，从未保存过时返回不路由的空策略
+func (db *BoltDB) GetLLMRoutingPolicy() (*models.LLMRoutingPolicy, error) {
+	policy := &models.LLMRoutingPolicy{CheapSteps: []string{}}
+	err := db.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(llmRoutingBucket).Get(llmRoutingKey)
+		if data == nil {
+			return nil
+		}
+		return json.Unmarshal(data, policy)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// SaveLLMRoutingPolicy 保存 LLM 路由策略
+func (db *BoltDB) SaveLLMRoutingPolicy(policy *models.LLMRoutingPolicy) error {
+	policy.UpdatedAt = time.Now()
+	return db.db.Update(func(tx *bolt.Tx) error {
+		data, err := json.Marshal(policy)
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(llmRoutingBucket).Put(llmRoutingKey, data)
+	})
+}
+
+// ================== Site Knowledge ==================
+
+// SaveSiteKnowledge 保存站点知识包
+func (db *BoltDB) SaveSiteKnowledge(k *models.SiteKnowledge) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(siteKnowledgeBucket)
+		data, err := json.Marshal(k)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(k.ID), data)
+	})
+}
+
+// GetSiteKnowledge 获取站点知识包
+func (db *BoltDB) GetSiteKnowledge(id string) (*models.SiteKnowledge, error) {
+	var k models.SiteKnowledge
+	err := db.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(siteKnowledgeBucket)
+		data := bucket.Get([]byte(id))
+		if data == nil {
+			return fmt.Errorf("site knowledge not found")
+		}
+		return json.Unmarshal(data, &k)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+// ListSiteKnowledge 列出所有站点知识包，按名称排序
+func (db *BoltDB) ListSiteKnowledge() ([]*models.SiteKnowledge, error) {
+	packs := []*models.SiteKnowledge{}
+	err := db.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(siteKnowledgeBucket)
+		return bucket.ForEach(func(k, v []byte) error {
+			var pack models.SiteKnowledge
+			if err := json.Unmarshal(v, &pack); err != nil {
+				return err
+			}
+			packs = append(packs, &pack)
+			return nil
+		})
+	})
+
+	sort.Slice(packs, func(i, j int) bool {
+		return packs[i].Name < packs[j].Name
+	})
+
+	return packs, err
+}
+
+// DeleteSiteKnowledge 删除站点知识包
+func (db *BoltDB) DeleteSiteKnowledge(id string) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(siteKnowledgeBucket)
+		return bucket.Delete([]byte(id))
+	})
+}
+
+// ================== Script Health ==================
+
+// SaveScriptHealthReport 保存脚本的选择器健康报告（覆盖该脚本之前的报告）
+func (db *BoltDB) SaveScriptHealthReport(report *models.ScriptHealthReport) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(scriptHealthBucket)
+		data, err := json.Marshal(report)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(report.ScriptID), data)
+	})
+}
+
+// GetScriptHealthReport 获取脚本最近一次的选择器健康报告
+func (db *BoltDB) GetScriptHealthReport(scriptID string) (*models.ScriptHealthReport, error) {
+	var report models.ScriptHealthReport
+	err := db.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(scriptHealthBucket)
+		data := bucket.Get([]byte(scriptID))
+		if data == nil {
+			return fmt.Errorf("script health report not found")
+		}
+		return json.Unmarshal(data, &report)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// ListScriptHealthReports 列出所有脚本的选择器健康报告，不健康的在前，其余按脚本名称排序
+func (db *BoltDB) ListScriptHealthReports() ([]*models.ScriptHealthReport, error) {
+	reports := []*models.ScriptHealthReport{}
+	err := db.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(scriptHealthBucket)
+		return bucket.ForEach(func(k, v []byte) error {
+			var report models.ScriptHealthReport
+			if err := json.Unmarshal(v, &report); err != nil {
+				return err
+			}
+			reports = append(reports, &report)
+			return nil
+		})
+	})
+
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].Healthy != reports[j].Healthy {
+			return !reports[i].Healthy
+		}
+		return reports[i].ScriptName < reports[j].ScriptName
+	})
+
+	return reports, err
+}
+
+// DeleteScriptHealthReport 删除脚本的选择器健康报告
+func (db *BoltDB) DeleteScriptHealthReport(scriptID string) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(scriptHealthBucket)
+		return bucket.Delete([]byte(scriptID))
+	})
+}
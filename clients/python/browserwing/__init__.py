@@ -0,0 +1,7 @@
+"""Python client for the BrowserWing REST API."""
+
+from . import models
+from .client import BrowserWing, BrowserWingError, iter_sse
+
+__all__ = ["BrowserWing", "BrowserWingError", "iter_sse", "models"]
+__version__ = "0.1.0"
@@ -0,0 +1,395 @@
+"""Thin client for the BrowserWing REST API.
+
+Only the standard library is used. Types in ``models`` and the operation
+table ``OPERATIONS`` are generated from the server's ``/openapi.json``; the
+helpers below cover the common flows (running scripts, fetching extracted
+data, driving the executor API) and the streaming agent endpoint.
+"""
+
+import json
+import os
+import time
+import uuid
+import urllib.error
+import urllib.parse
+import urllib.request
+from typing import Any, Dict, Iterator, List, Optional
+
+from .models import (
+    OPERATIONS,
+    AutomationRun,
+    AutomationScript,
+    BulkRun,
+    Environment,
+    LintIssue,
+    OperationResult,
+    PlayResult,
+    ScheduledTask,
+    Script,
+    ScriptExecution,
+    UploadedFile,
+)
+
+
+class BrowserWingError(Exception):
+    """Raised when the server answers with a non-2xx status.
+
+    ``code`` is the server error code (e.g. ``error.scriptNotFound``) and
+    ``body`` the decoded response, which for a failed playback also contains
+    the partial ``result``.
+    """
+
+    def __init__(self, status: int, code: str, detail: str = "", body: Any = None):
+        message = f"{status} {code}"
+        if detail:
+            message += f": {detail}"
+        super().__init__(message)
+        self.status = status
+        self.code = code
+        self.detail = detail
+        self.body = body
+
+
+class BrowserWing:
+    """Client for a BrowserWing server.
+
+    Authenticate with an API key (``X-BrowserWing-Key``) for script playback
+    and the executor API, or with a JWT from :meth:`login` for everything else.
+    """
+
+    def __init__(
+        self,
+        base_url: str = "http://localhost:8080",
+        api_key: Optional[str] = None,
+        token: Optional[str] = None,
+        timeout: float = 300,
+    ):
+        self.base_url = base_url.rstrip("/")
+        self.api_key = api_key
+        self.token = token
+        self.timeout = timeout
+
+    # ------------------------------------------------------------------ core
+
+    def _headers(self, extra: Optional[Dict[str, str]] = None) -> Dict[str, str]:
+        headers = {"Accept": "application/json"}
+        if self.api_key:
+            headers["X-BrowserWing-Key"] = self.api_key
+        if self.token:
+            headers["Authorization"] = f"Bearer {self.token}"
+        if extra:
+            headers.update(extra)
+        return headers
+
+    def _open(self, method: str, path: str, body: Any = None, query: Optional[Dict[str, Any]] = None,
+              headers: Optional[Dict[str, str]] = None):
+        url = self.base_url + path
+        params = {k: _query_value(v) for k, v in (query or {}).items() if v is not None}
+        if params:
+            url += "?" + urllib.parse.urlencode(params)
+        data = None
+        headers = self._headers(headers)
+        if isinstance(body, bytes):
+            # pre-encoded body (e.g. multipart), the caller sets Content-Type
+            data = body
+        elif body is not None:
+            data = json.dumps(body).encode("utf-8")
+            headers["Content-Type"] = "application/json"
+        req = urllib.request.Request(url, data=data, method=method, headers=headers)
+        try:
+            return urllib.request.urlopen(req, timeout=self.timeout)
+        except urllib.error.HTTPError as e:
+            raw = e.read()
+            try:
+                decoded = json.loads(raw)
+            except ValueError:
+                decoded = raw.decode("utf-8", "replace")
+            if isinstance(decoded, dict):
+                raise BrowserWingError(e.code, str(decoded.get("error", "")), str(decoded.get("detail", "")), decoded) from None
+            raise BrowserWingError(e.code, str(decoded), body=decoded) from None
+
+    def request(self, method: str, path: str, body: Any = None, query: Optional[Dict[str, Any]] = None,
+                headers: Optional[Dict[str, str]] = None) -> Any:
+        """Send a request and return the decoded JSON response."""
+        with self._open(method, path, body, query, headers) as resp:
+            raw = resp.read()
+        if not raw:
+            return None
+        return json.loads(raw)
+
+    def call(self, operation_id: str, path_params: Optional[Dict[str, str]] = None, body: Any = None,
+             query: Optional[Dict[str, Any]] = None) -> Any:
+        """Call any operation by its OpenAPI operationId, e.g. ``call("GetScript", {"id": "..."})``."""
+        op = OPERATIONS[operation_id]
+        path = op["path"]
+        for name, value in (path_params or {}).items():
+            path = path.replace("{" + name + "}", urllib.parse.quote(str(value), safe=""))
+        return self.request(op["method"], path, body, query)
+
+    def login(self, username: str, password: str) -> str:
+        """Log in with a user account and use the returned JWT for later requests."""
+        resp = self.request("POST", "/api/v1/auth/login", {"username": username, "password": password})
+        self.token = resp["token"]
+        return self.token
+
+    # --------------------------------------------------------------- scripts
+
+    def list_scripts(self, group: Optional[str] = None, tag: Optional[str] = None) -> List[Script]:
+        """Return all scripts, optionally filtered by group or tag."""
+        scripts: List[Script] = []
+        page = 1
+        while True:
+            resp = self.request("GET", "/api/v1/scripts",
+                                query={"page": page, "page_size": 100, "group": group, "tag": tag})
+            scripts.extend(resp.get("scripts") or [])
+            if len(scripts) >= resp.get("total", 0) or not resp.get("scripts"):
+                return scripts
+            page += 1
+
+    def get_script(self, script_id: str) -> Script:
+        return self.request("GET", f"/api/v1/scripts/{_quote(script_id)}")
+
+    def lint_script(self, script_id: str) -> List[LintIssue]:
+        return self.request("GET", f"/api/v1/scripts/{_quote(script_id)}/lint")["data"]
+
+    def play_script(
+        self,
+        script_id: str,
+        params: Optional[Dict[str, str]] = None,
+        *,
+        instance_id: Optional[str] = None,
+        environment: Optional[str] = None,
+        run_tags: Optional[List[str]] = None,
+        incognito: Optional[bool] = None,
+    ) -> PlayResult:
+        """Run a script and wait for it to finish.
+
+        ``params`` fill the script's ``${name}`` placeholders. The returned
+        result holds the extracted data in ``extracted_data``. A failed
+        playback raises :class:`BrowserWingError` with the result in
+        ``error.body["result"]``.
+        """
+        body: Dict[str, Any] = {"params": params or {}}
+        if instance_id is not None:
+            body["instance_id"] = instance_id
+        if environment is not None:
+            body["environment"] = environment
+        if run_tags is not None:
+            body["run_tags"] = run_tags
+        if incognito is not None:
+            body["incognito"] = incognito
+        return self.request("POST", f"/api/v1/scripts/{_quote(script_id)}/play", body)["result"]
+
+    def extract(self, script_id: str, params: Optional[Dict[str, str]] = None, **kwargs: Any) -> Dict[str, Any]:
+        """Run a script and return only the data it extracted."""
+        return self.play_script(script_id, params, **kwargs).get("extracted_data") or {}
+
+    def get_play_result(self) -> Dict[str, Any]:
+        """Return the data extracted by the most recent playback."""
+        return self.request("GET", "/api/v1/scripts/play/result")["data"]
+
+    def list_executions(self, script_id: Optional[str] = None, success: Optional[bool] = None,
+                        page: int = 1, page_size: int = 20) -> List[ScriptExecution]:
+        resp = self.request("GET", "/api/v1/script-executions", query={
+            "script_id": script_id, "success": success, "page": page, "page_size": page_size,
+        })
+        return resp.get("executions") or []
+
+    def get_execution(self, execution_id: str) -> ScriptExecution:
+        return self.request("GET", f"/api/v1/script-executions/{_quote(execution_id)}")
+
+    # ------------------------------------------------------------ automation
+
+    def list_automation_scripts(self, group: Optional[str] = None, tag: Optional[str] = None) -> List[AutomationScript]:
+        """Return the scripts with their parameters as a JSON Schema (``parameters``)."""
+        return self.request("GET", "/api/v1/automation/scripts", query={"group": group, "tag": tag})["data"]
+
+    def start_run(
+        self,
+        script_id: str,
+        params: Optional[Dict[str, str]] = None,
+        *,
+        environment: Optional[str] = None,
+        run_tags: Optional[List[str]] = None,
+        instance_id: Optional[str] = None,
+        callback_url: Optional[str] = None,
+    ) -> AutomationRun:
+        """Start a script in the background and return the run without waiting.
+
+        Poll it with :meth:`get_run` / :meth:`wait_for_run`, or pass
+        ``callback_url`` to receive the finished run as a POST.
+        """
+        body: Dict[str, Any] = {"params": params or {}}
+        if environment is not None:
+            body["environment"] = environment
+        if run_tags is not None:
+            body["run_tags"] = run_tags
+        if instance_id is not None:
+            body["instance_id"] = instance_id
+        if callback_url is not None:
+            body["callback_url"] = callback_url
+        return self.request("POST", f"/api/v1/automation/scripts/{_quote(script_id)}/runs", body)["data"]
+
+    def get_run(self, run_id: str) -> AutomationRun:
+        return self.request("GET", f"/api/v1/automation/runs/{_quote(run_id)}")["data"]
+
+    def wait_for_run(self, run_id: str, interval: float = 2, timeout: Optional[float] = None) -> AutomationRun:
+        """Poll a run until its status is ``succeeded`` or ``failed``."""
+        deadline = None if timeout is None else time.monotonic() + timeout
+        while True:
+            run = self.get_run(run_id)
+            if run["status"] != "running":
+                return run
+            if deadline is not None and time.monotonic() >= deadline:
+                raise TimeoutError(f"run {run_id} still running after {timeout}s")
+            time.sleep(interval)
+
+    def start_bulk_run(
+        self,
+        script_id: str,
+        rows: List[Dict[str, Any]],
+        *,
+        concurrency: Optional[int] = None,
+        environment: Optional[str] = None,
+        run_tags: Optional[List[str]] = None,
+        instance_id: Optional[str] = None,
+    ) -> BulkRun:
+        """Run a script once per parameter row in the background.
+
+        ``concurrency`` rows run at the same time (default 1, max 10). Poll
+        with :meth:`get_bulk_run` / :meth:`wait_for_bulk_run` and fetch the
+        combined results with :meth:`bulk_run_results_csv`.
+        """
+        body: Dict[str, Any] = {"rows": rows}
+        if concurrency is not None:
+            body["concurrency"] = concurrency
+        if environment is not None:
+            body["environment"] = environment
+        if run_tags is not None:
+            body["run_tags"] = run_tags
+        if instance_id is not None:
+            body["instance_id"] = instance_id
+        return self.request("POST", f"/api/v1/automation/scripts/{_quote(script_id)}/bulk-runs", body)["data"]
+
+    def get_bulk_run(self, bulk_run_id: str) -> BulkRun:
+        return self.request("GET", f"/api/v1/automation/bulk-runs/{_quote(bulk_run_id)}")["data"]
+
+    def wait_for_bulk_run(self, bulk_run_id: str, interval: float = 5, timeout: Optional[float] = None) -> BulkRun:
+        """Poll a bulk run until all of its rows have finished or were cancelled."""
+        deadline = None if timeout is None else time.monotonic() + timeout
+        while True:
+            run = self.get_bulk_run(bulk_run_id)
+            if run.get("finished_at"):
+                return run
+            if deadline is not None and time.monotonic() >= deadline:
+                raise TimeoutError(f"bulk run {bulk_run_id} still running after {timeout}s")
+            time.sleep(interval)
+
+    def cancel_bulk_run(self, bulk_run_id: str) -> BulkRun:
+        """Skip the rows that have not started; running rows finish."""
+        return self.request("POST", f"/api/v1/automation/bulk-runs/{_quote(bulk_run_id)}/cancel")["data"]
+
+    def bulk_run_results_csv(self, bulk_run_id: str) -> str:
+        """Return one CSV line per row: status, error, parameters and extracted data."""
+        with self._open("GET", f"/api/v1/automation/bulk-runs/{_quote(bulk_run_id)}/results") as resp:
+            return resp.read().decode("utf-8")
+
+    def upload_file(self, path: str, content: Optional[bytes] = None) -> UploadedFile:
+        """Upload a file to the server's managed upload area.
+
+        Use the returned ``handle`` (``upload:<id>``) in the ``file_paths`` of
+        an ``upload_file`` step, so the file doesn't have to exist on the
+        server beforehand. ``content`` is read from ``path`` when omitted.
+        """
+        if content is None:
+            with open(path, "rb") as f:
+                content = f.read()
+        name = os.path.basename(path).replace('"', "_")
+        boundary = uuid.uuid4().hex
+        body = (
+            f"--{boundary}\r\n"
+            f'Content-Disposition: form-data; name="file"; filename="{name}"\r\n'
+            "Content-Type: application/octet-stream\r\n\r\n"
+        ).encode("utf-8") + content + f"\r\n--{boundary}--\r\n".encode("utf-8")
+        headers = {"Content-Type": f"multipart/form-data; boundary={boundary}"}
+        return self.request("POST", "/api/v1/uploads", body, headers=headers)["data"]
+
+    def delete_upload(self, upload_id: str) -> None:
+        self.request("DELETE", f"/api/v1/uploads/{_quote(upload_id)}")
+
+    # ------------------------------------------------------ tasks & settings
+
+    def list_scheduled_tasks(self) -> List[ScheduledTask]:
+        return self.request("GET", "/api/v1/scheduled-tasks", query={"page_size": 100}).get("tasks") or []
+
+    def list_environments(self) -> List[Environment]:
+        return self.request("GET", "/api/v1/environments")["data"]
+
+    # -------------------------------------------------------------- executor
+
+    def execute(
+        self,
+        command: str,
+        body: Optional[Dict[str, Any]] = None,
+        *,
+        instance_id: Optional[str] = None,
+        tab_id: Optional[str] = None,
+        session_id: Optional[str] = None,
+    ) -> OperationResult:
+        """Run one executor command, e.g. ``execute("navigate", {"url": "https://example.com"})``.
+
+        GET commands (``snapshot``, ``page-info``...) send ``body`` as query
+        parameters. Requests on the same instance/tab/session run one at a time.
+        """
+        path = "/api/v1/executor/" + command
+        method = next((op["method"] for op in OPERATIONS.values() if op["path"] == path), "POST")
+        query: Dict[str, Any] = {"instance_id": instance_id, "tab_id": tab_id, "session_id": session_id}
+        if method == "GET":
+            query.update(body or {})
+            return self.request(method, path, query=query)
+        return self.request(method, path, body or {}, query)
+
+    # ----------------------------------------------------------------- agent
+
+    def create_agent_session(self, llm_config_id: Optional[str] = None) -> Dict[str, Any]:
+        body = {"llm_config_id": llm_config_id} if llm_config_id else {}
+        return self.request("POST", "/api/v1/agent/sessions", body)["session"]
+
+    def stream_agent_message(self, session_id: str, message: str,
+                             llm_config_id: Optional[str] = None) -> Iterator[Dict[str, Any]]:
+        """Send a message to an agent session and yield the streamed chunks.
+
+        Each chunk has a ``type`` of ``message``, ``tool_call``, ``done`` or
+        ``error``. The agent API requires a JWT (see :meth:`login`).
+        """
+        body: Dict[str, Any] = {"message": message}
+        if llm_config_id:
+            body["llm_config_id"] = llm_config_id
+        resp = self._open("POST", f"/api/v1/agent/sessions/{_quote(session_id)}/messages", body,
+                          headers={"Accept": "text/event-stream"})
+        with resp:
+            yield from iter_sse(resp)
+
+
+def iter_sse(stream) -> Iterator[Dict[str, Any]]:
+    """Decode a ``text/event-stream`` response into JSON events."""
+    data: List[str] = []
+    for raw in stream:
+        line = raw.decode("utf-8").rstrip("\r\n")
+        if line.startswith("data:"):
+            data.append(line[5:].lstrip(" "))
+        elif line == "" and data:
+            yield json.loads("\n".join(data))
+            data = []
+    if data:
+        yield json.loads("\n".join(data))
+
+
+def _quote(value: str) -> str:
+    return urllib.parse.quote(value, safe="")
+
+
+def _query_value(value: Any) -> str:
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    return str(value)
@@ -0,0 +1,1317 @@
+# Code generated by gen-clients from /openapi.json. DO NOT EDIT.
+
+from typing import Any, Dict, List, TypedDict
+
+API_VERSION = "v1"
+
+
+class AccessibilityInfo(TypedDict, total=False):
+    name: str
+    role: str
+    value: str
+
+
+class ActionCondition(TypedDict, total=False):
+    enabled: bool
+    operator: str
+    value: str
+    variable: str
+
+
+class ActionContext(TypedDict, total=False):
+    ancestor_tags: List[str]
+    form_hint: str
+    nearby_text: List[str]
+
+
+class ActionEvidence(TypedDict, total=False):
+    ax_node_id: str
+    backend_dom_node_id: int
+    confidence: float
+
+
+class ActionIntent(TypedDict, total=False):
+    object: str
+    verb: str
+
+
+class ActionVerification(TypedDict, total=False):
+    checked_at: str
+    error: str
+    reason: str
+    screenshot: str
+    verified: bool
+
+
+class ApiKey(TypedDict, total=False):
+    created_at: str
+    description: str
+    id: str
+    key: str
+    name: str
+    updated_at: str
+    url_policy: "Policy"
+    user_id: str
+
+
+class AutomationRun(TypedDict, total=False):
+    callback_error: str
+    callback_url: str
+    environment: str
+    error: str
+    finished_at: str
+    id: str
+    params: Dict[str, str]
+    result: "PlayResult"
+    script_id: str
+    script_name: str
+    started_at: str
+    status: str
+
+
+class AutomationRunRequest(TypedDict, total=False):
+    callback_url: str
+    environment: str
+    instance_id: str
+    params: Dict[str, str]
+    run_tags: List[str]
+
+
+class AutomationScript(TypedDict, total=False):
+    description: str
+    group: str
+    id: str
+    name: str
+    parameters: Dict[str, Any]
+    tags: List[str]
+    url: str
+
+
+class BrowserConfig(TypedDict, total=False):
+    block_ads: bool
+    client_cert: "ClientCertificate"
+    created_at: str
+    description: str
+    float_button: "FloatButtonOptions"
+    headless: bool
+    http_auth: "HTTPAuthCredentials"
+    id: str
+    is_default: bool
+    launch_args: List[str]
+    name: str
+    proxy: str
+    stealth: "StealthOptions"
+    updated_at: str
+    url_pattern: str
+    use_stealth: bool
+    user_agent: str
+
+
+class BrowserInstance(TypedDict, total=False):
+    bin_path: str
+    control_url: str
+    created_at: str
+    description: str
+    extensions: List[str]
+    headless: bool
+    id: str
+    is_active: bool
+    is_default: bool
+    launch_args: List[str]
+    leak_protection: "ProxyLeakProtection"
+    name: str
+    proxy: str
+    type: str
+    updated_at: str
+    url_policy: "Policy"
+    use_stealth: bool
+    user_agent: str
+    user_data_dir: str
+    window: "WindowPlacement"
+
+
+class BulkRun(TypedDict, total=False):
+    cancelled: bool
+    columns: List[str]
+    concurrency: int
+    environment: str
+    failed: int
+    finished_at: str
+    id: str
+    rows: List["BulkRunRow"]
+    script_id: str
+    script_name: str
+    started_at: str
+    succeeded: int
+    total: int
+
+
+class BulkRunRequest(TypedDict, total=False):
+    concurrency: int
+    environment: str
+    instance_id: str
+    rows: List[Dict[str, str]]
+    run_tags: List[str]
+
+
+class BulkRunRow(TypedDict, total=False):
+    error: str
+    finished_at: str
+    index: int
+    params: Dict[str, str]
+    result: "PlayResult"
+    started_at: str
+    status: str
+
+
+class CaptureRegion(TypedDict, total=False):
+    height: float
+    width: float
+    x: float
+    y: float
+
+
+class ChatMessage(TypedDict, total=False):
+    content: str
+    id: str
+    role: str
+    timestamp: str
+    tool_calls: List["ToolCall"]
+
+
+class ChatSession(TypedDict, total=False):
+    approval_categories: List[str]
+    created_at: str
+    id: str
+    llm_config_id: str
+    messages: List[ChatMessage]
+    read_only: bool
+    system_prompt_id: str
+    updated_at: str
+    verify_actions: bool
+
+
+class CheckScriptHealthRequest(TypedDict, total=False):
+    instance_id: str
+
+
+class ClientCertificate(TypedDict, total=False):
+    issuer_cn: str
+    pattern: str
+    subject_cn: str
+
+
+class ComponentElement(TypedDict, total=False):
+    description: str
+    selector: str
+    xpath: str
+
+
+class CreateApiKeyRequest(TypedDict, total=False):
+    description: str
+    name: str
+    url_policy: "Policy"
+
+
+class CreateUserRequest(TypedDict, total=False):
+    password: str
+    username: str
+
+
+class DataChange(TypedDict, total=False):
+    base: Any
+    change: str
+    path: str
+    target: Any
+
+
+class DataTransform(TypedDict, total=False):
+    expression: str
+    type: str
+
+
+class DownloadedFile(TypedDict, total=False):
+    download_time: str
+    file_name: str
+    file_path: str
+    mime_type: str
+    size: int
+    url: str
+
+
+class ElementFingerprint(TypedDict, total=False):
+    ancestors: List[str]
+    attr_hash: str
+    attrs: Dict[str, str]
+    tag: str
+    text: str
+
+
+class Environment(TypedDict, total=False):
+    base_url: str
+    created_at: str
+    credentials: Dict[str, str]
+    description: str
+    id: str
+    name: str
+    updated_at: str
+    variables: Dict[str, str]
+
+
+class EnvironmentRequest(TypedDict, total=False):
+    base_url: str
+    credentials: Dict[str, str]
+    description: str
+    name: str
+    variables: Dict[str, str]
+
+
+class Error(TypedDict, total=False):
+    detail: str
+    error: str
+
+
+class ExecutionBrief(TypedDict, total=False):
+    duration: int
+    error_msg: str
+    failed_steps: int
+    id: str
+    item_count: int
+    start_time: str
+    success: bool
+    success_steps: int
+
+
+class ExecutionComparison(TypedDict, total=False):
+    base: ExecutionBrief
+    data: List[DataChange]
+    data_truncated: bool
+    screenshots: List["ScreenshotComparison"]
+    script_id: str
+    steps: List["StepComparison"]
+    summary: List[str]
+    target: ExecutionBrief
+
+
+class ExpectedRequest(TypedDict, total=False):
+    method: str
+    status: int
+    url: str
+
+
+class ExtractionMetrics(TypedDict, total=False):
+    item_count: int
+    null_rate: float
+
+
+class FloatButtonOptions(TypedDict, total=False):
+    accent_color: str
+    background_color: str
+    disabled: bool
+    offset_x: int
+    offset_y: int
+    position: str
+    text_color: str
+
+
+class HTTPAuthCredentials(TypedDict, total=False):
+    has_password: bool
+    password: str
+    username: str
+
+
+class InstallBundleRequest(TypedDict, total=False):
+    bundle: "SignedBundle"
+    id: str
+
+
+class InstallOptions(TypedDict, total=False):
+    group: str
+    name: str
+    params: Dict[str, str]
+
+
+class InstalledBundle(TypedDict, total=False):
+    author: str
+    id: str
+    installed_at: str
+    name: str
+    public_key: str
+    script_ids: List[str]
+    source: str
+    updated_at: str
+    verified: bool
+    version: str
+
+
+class LLMConfigModel(TypedDict, total=False):
+    api_key: str
+    base_url: str
+    created_at: str
+    id: str
+    is_active: bool
+    is_default: bool
+    model: str
+    name: str
+    provider: str
+    updated_at: str
+
+
+class LLMRoutingPolicy(TypedDict, total=False):
+    cheap_config_id: str
+    cheap_steps: List[str]
+    embedding_config_id: str
+    embedding_model: str
+    updated_at: str
+
+
+class LintIssue(TypedDict, total=False):
+    code: str
+    message: str
+    severity: str
+    step: int
+
+
+class LoginRequest(TypedDict, total=False):
+    password: str
+    username: str
+
+
+class LoginResponse(TypedDict, total=False):
+    token: str
+    user: "User"
+
+
+class MCPService(TypedDict, total=False):
+    args: List[str]
+    command: str
+    created_at: str
+    description: str
+    enabled: bool
+    env: Dict[str, str]
+    id: str
+    last_error: str
+    name: str
+    status: str
+    tool_count: int
+    type: str
+    updated_at: str
+    url: str
+
+
+class MarketplaceBundle(TypedDict, total=False):
+    author: str
+    description: str
+    id: str
+    installed_version: str
+    name: str
+    public_key: str
+    scripts: int
+    tags: List[str]
+    updated_at: str
+    url: str
+    version: str
+
+
+class MarketplaceInfo(TypedDict, total=False):
+    allow_unsigned: bool
+    can_publish: bool
+    registry_url: str
+    signing_public_key: str
+    trusted_keys: List[str]
+
+
+NotificationChannel = TypedDict(
+    "NotificationChannel",
+    {
+        "bot_token": str,
+        "chat_id": str,
+        "created_at": str,
+        "enabled": bool,
+        "from": str,
+        "has_bot_token": bool,
+        "has_smtp_password": bool,
+        "has_webhook_url": bool,
+        "id": str,
+        "name": str,
+        "smtp_host": str,
+        "smtp_password": str,
+        "smtp_port": int,
+        "smtp_username": str,
+        "to": List[str],
+        "type": str,
+        "updated_at": str,
+        "webhook_url": str,
+    },
+    total=False,
+)
+
+
+class NotificationDigest(TypedDict, total=False):
+    channel_ids: List[str]
+    created_at: str
+    enabled: bool
+    hour: int
+    id: str
+    last_sent_at: str
+    name: str
+    period: str
+    script_ids: List[str]
+    updated_at: str
+    weekday: int
+
+
+class NotificationRule(TypedDict, total=False):
+    channel_ids: List[str]
+    created_at: str
+    enabled: bool
+    event: str
+    id: str
+    name: str
+    script_id: str
+    task_id: str
+    updated_at: str
+
+
+class OperationResult(TypedDict, total=False):
+    data: Dict[str, Any]
+    error: str
+    error_code: str
+    message: str
+    success: bool
+    timestamp: str
+
+
+class PackBundleRequest(TypedDict, total=False):
+    author: str
+    description: str
+    id: str
+    include_variables: bool
+    name: str
+    script_ids: List[str]
+    tags: List[str]
+    version: str
+
+
+class PageComponent(TypedDict, total=False):
+    created_at: str
+    description: str
+    elements: Dict[str, ComponentElement]
+    id: str
+    name: str
+    site: str
+    updated_at: str
+
+
+class PageComponentRequest(TypedDict, total=False):
+    description: str
+    elements: Dict[str, ComponentElement]
+    name: str
+    site: str
+
+
+class PageVitals(TypedDict, total=False):
+    cls: float
+    fcp: float
+    inp: float
+    lcp: float
+    ttfb: float
+    url: str
+
+
+class ParamUIField(TypedDict, total=False):
+    help: str
+    input_type: str
+    label: str
+    options: List[str]
+    pattern: str
+    pattern_message: str
+    placeholder: str
+
+
+class PendingApproval(TypedDict, total=False):
+    arguments: Dict[str, Any]
+    category: str
+    created_at: str
+    expires_at: str
+    id: str
+    reason: str
+    session_id: str
+    tool_name: str
+
+
+class PerformanceMetrics(TypedDict, total=False):
+    cpu_throttling: float
+    errors: List[str]
+    pages: List[PageVitals]
+    trace_path: str
+
+
+class PerformanceOptions(TypedDict, total=False):
+    cpu_throttling: float
+    trace: bool
+    web_vitals: bool
+
+
+class PlayResult(TypedDict, total=False):
+    anomalies: List[str]
+    errors: List[str]
+    extracted_data: Dict[str, Any]
+    message: str
+    performance: PerformanceMetrics
+    schema_error: str
+    success: bool
+
+
+class PlayScriptRequest(TypedDict, total=False):
+    environment: str
+    incognito: bool
+    instance_id: str
+    params: Dict[str, str]
+    performance: PerformanceOptions
+    run_tags: List[str]
+
+
+class Policy(TypedDict, total=False):
+    allow: List[str]
+    deny: List[str]
+    schemes: List[str]
+
+
+class Prompt(TypedDict, total=False):
+    content: str
+    created_at: str
+    description: str
+    id: str
+    name: str
+    type: str
+    updated_at: str
+    version: int
+
+
+class PromptRequest(TypedDict, total=False):
+    content: str
+    description: str
+    name: str
+
+
+class ProxyLeakProtection(TypedDict, total=False):
+    block_webrtc: bool
+    proxy_dns: bool
+
+
+class RecordingConfig(TypedDict, total=False):
+    created_at: str
+    enabled: bool
+    format: str
+    frame_rate: int
+    id: str
+    max_height: int
+    max_width: int
+    output_dir: str
+    quality: int
+    region: CaptureRegion
+    updated_at: str
+
+
+class ReplayReport(TypedDict, total=False):
+    changed: int
+    diverged: int
+    duration: int
+    matched: int
+    missing: int
+    session_id: str
+    skipped: int
+    start_time: str
+    steps: List["ReplayStep"]
+    total: int
+
+
+class ReplayStep(TypedDict, total=False):
+    arguments: Dict[str, Any]
+    duration: int
+    error: str
+    message: int
+    outcome: str
+    recorded_result: str
+    recorded_status: str
+    result: str
+    tool_call: int
+    tool_name: str
+
+
+class ScheduledRun(TypedDict, total=False):
+    description: str
+    end_time: str
+    execution_type: str
+    schedule: str
+    schedule_type: str
+    script_id: str
+    script_name: str
+    start_time: str
+    task_id: str
+    task_name: str
+
+
+ScheduledRunsResponse = TypedDict(
+    "ScheduledRunsResponse",
+    {
+        "from": str,
+        "runs": List[ScheduledRun],
+        "to": str,
+        "truncated": bool,
+    },
+    total=False,
+)
+
+
+class ScheduledTask(TypedDict, total=False):
+    agent_llm_id: str
+    agent_llm_name: str
+    agent_prompt: str
+    agent_session_id: str
+    browser_instance_id: str
+    crawl_concurrency: int
+    crawl_max_urls: int
+    crawl_sitemap_url: str
+    crawl_urls: List[str]
+    created_at: str
+    description: str
+    enabled: bool
+    environment: str
+    execution_count: int
+    execution_type: str
+    failed_count: int
+    health_check_script_ids: List[str]
+    id: str
+    last_execution_status: str
+    last_execution_time: str
+    monitor_selector: str
+    monitor_threshold: float
+    monitor_url: str
+    monitor_webhook_url: str
+    name: str
+    next_execution_time: str
+    schedule_config: str
+    schedule_type: str
+    screenshot_mode: str
+    screenshot_retention: int
+    screenshot_urls: List[str]
+    script_id: str
+    script_name: str
+    script_variables: Dict[str, str]
+    success_count: int
+    updated_at: str
+
+
+class ScreenshotComparison(TypedDict, total=False):
+    base_path: str
+    identical: bool
+    target_path: str
+    variable: str
+
+
+class Script(TypedDict, total=False):
+    actions: List["ScriptAction"]
+    can_fetch: bool
+    can_publish: bool
+    created_at: str
+    description: str
+    downloaded_files: List[DownloadedFile]
+    duration: int
+    environment: str
+    group: str
+    headers: Dict[str, str]
+    id: str
+    incognito: bool
+    is_mcp_command: bool
+    mcp_command_description: str
+    mcp_command_name: str
+    mcp_input_schema: Dict[str, Any]
+    name: str
+    output_schema: Dict[str, Any]
+    param_ui_schema: Dict[str, ParamUIField]
+    performance: PerformanceOptions
+    run_tags: List[str]
+    tags: List[str]
+    timeouts: "ScriptTimeouts"
+    transform: DataTransform
+    updated_at: str
+    url: str
+    user_agent: str
+    variables: Dict[str, str]
+
+
+class ScriptAction(TypedDict, total=False):
+    a11y_max_violations: int
+    a11y_min_impact: str
+    a11y_tags: List[str]
+    accept: str
+    accessibility: AccessibilityInfo
+    ai_control_llm_config_id: str
+    ai_control_prompt: str
+    ai_control_xpath: str
+    attribute_name: str
+    attrs: Dict[str, str]
+    component: str
+    condition: ActionCondition
+    context: ActionContext
+    dedup: bool
+    dedup_key: str
+    description: str
+    disabled: bool
+    duration: int
+    editor_strategy: str
+    evidence: ActionEvidence
+    expected_requests: List[ExpectedRequest]
+    extract_type: str
+    extracted_data: str
+    file_names: List[str]
+    file_paths: List[str]
+    fingerprint: ElementFingerprint
+    intent: ActionIntent
+    js_code: str
+    key: str
+    method: str
+    multiple: bool
+    remark: str
+    run_on: List[str]
+    screenshot_height: int
+    screenshot_mode: str
+    screenshot_width: int
+    script_id: str
+    script_params: Dict[str, str]
+    scroll_x: int
+    scroll_y: int
+    selector: str
+    skip_on: List[str]
+    status: int
+    tag_name: str
+    target_selector: str
+    target_xpath: str
+    text: str
+    timestamp: int
+    type: str
+    update_cursor: bool
+    url: str
+    value: str
+    variable_name: str
+    x: int
+    xhr_id: str
+    xpath: str
+    y: int
+
+
+class ScriptExecution(TypedDict, total=False):
+    anomalies: List[str]
+    created_at: str
+    duration: int
+    end_time: str
+    error_code: str
+    error_msg: str
+    extracted_data: Dict[str, Any]
+    failed_steps: int
+    id: str
+    instance_id: str
+    instance_name: str
+    message: str
+    metrics: ExtractionMetrics
+    performance: PerformanceMetrics
+    schema_error: str
+    script_id: str
+    script_name: str
+    start_time: str
+    steps: List["StepOutcome"]
+    success: bool
+    success_steps: int
+    total_steps: int
+    video_path: str
+    work_dir: str
+
+
+class ScriptHealthReport(TypedDict, total=False):
+    checked_at: str
+    checks: List["SelectorCheck"]
+    duration: int
+    errors: List[str]
+    found: int
+    healthy: bool
+    missing: int
+    script_id: str
+    script_name: str
+    task_id: str
+    unverified: int
+
+
+class ScriptMetricsPoint(TypedDict, total=False):
+    anomalies: List[str]
+    execution_id: str
+    metrics: ExtractionMetrics
+    start_time: str
+    success: bool
+
+
+class ScriptStateSummary(TypedDict, total=False):
+    cursor: str
+    last_run_at: str
+    script_id: str
+    seen_count: int
+    updated_at: str
+
+
+class ScriptTemplate(TypedDict, total=False):
+    category: str
+    description: str
+    id: str
+    name: str
+    parameters: Dict[str, Any]
+    script: Script
+    tags: List[str]
+
+
+class ScriptTimeouts(TypedDict, total=False):
+    element_seconds: int
+    navigation_seconds: int
+
+
+class SelectorCheck(TypedDict, total=False):
+    page_url: str
+    reason: str
+    selector: str
+    status: str
+    step: int
+    target: str
+    type: str
+    xpath: str
+
+
+class SessionTranscript(TypedDict, total=False):
+    created_at: str
+    exported_at: str
+    llm_config_id: str
+    messages: List[ChatMessage]
+    screenshots: List["TranscriptScreenshot"]
+    session_id: str
+    version: int
+
+
+class SignedBundle(TypedDict, total=False):
+    bundle: Any
+    public_key: str
+    signature: str
+
+
+class SiteKnowledge(TypedDict, total=False):
+    content: str
+    created_at: str
+    domains: List[str]
+    enabled: bool
+    id: str
+    login_script_id: str
+    name: str
+    updated_at: str
+
+
+class SiteKnowledgeRequest(TypedDict, total=False):
+    content: str
+    domains: List[str]
+    enabled: bool
+    login_script_id: str
+    name: str
+
+
+class StealthOptions(TypedDict, total=False):
+    block_webrtc: bool
+    languages: List[str]
+    level: str
+
+
+class StepComparison(TypedDict, total=False):
+    base_duration: int
+    base_status: str
+    changed: bool
+    index: int
+    target_duration: int
+    target_error: str
+    target_status: str
+    type: str
+
+
+class StepOutcome(TypedDict, total=False):
+    duration: int
+    error: str
+    index: int
+    status: str
+    type: str
+
+
+class TaskExecution(TypedDict, total=False):
+    agent_session_id: str
+    created_at: str
+    duration: int
+    end_time: str
+    error_code: str
+    error_msg: str
+    execution_type: str
+    id: str
+    message: str
+    result_data: Dict[str, Any]
+    script_id: str
+    start_time: str
+    success: bool
+    task_id: str
+    task_name: str
+
+
+class ToolCall(TypedDict, total=False):
+    arguments: Dict[str, Any]
+    instructions: str
+    message: str
+    result: str
+    status: str
+    timestamp: str
+    tool_name: str
+    verification: ActionVerification
+
+
+class ToolConfig(TypedDict, total=False):
+    created_at: str
+    description: str
+    enabled: bool
+    id: str
+    name: str
+    parameters: Dict[str, Any]
+    script_id: str
+    type: str
+    updated_at: str
+
+
+class TranscriptScreenshot(TypedDict, total=False):
+    data: str
+    error: str
+    message: int
+    mime_type: str
+    path: str
+    tool_call: int
+
+
+class UILocale(TypedDict, total=False):
+    base: str
+    created_at: str
+    float_button: Dict[str, str]
+    language: str
+    name: str
+    player: Dict[str, str]
+    recorder: Dict[str, str]
+    updated_at: str
+
+
+class UiLocaleTexts(TypedDict, total=False):
+    float_button: Dict[str, str]
+    language: str
+    player: Dict[str, str]
+    recorder: Dict[str, str]
+
+
+class UpdatePasswordRequest(TypedDict, total=False):
+    new_password: str
+    old_password: str
+
+
+class UpdateScriptRequest(TypedDict, total=False):
+    actions: List[ScriptAction]
+    description: str
+    environment: str
+    headers: Dict[str, str]
+    incognito: bool
+    is_mcp_command: bool
+    mcp_command_description: str
+    mcp_command_name: str
+    mcp_input_schema: Dict[str, Any]
+    name: str
+    output_schema: Dict[str, Any]
+    param_ui_schema: Dict[str, ParamUIField]
+    performance: PerformanceOptions
+    run_tags: List[str]
+    tags: List[str]
+    timeouts: ScriptTimeouts
+    transform: DataTransform
+    url: str
+    user_agent: str
+    variables: Dict[str, str]
+
+
+class UpdateScriptStateRequest(TypedDict, total=False):
+    clear_seen: bool
+    cursor: str
+
+
+class UploadedFile(TypedDict, total=False):
+    created_at: str
+    handle: str
+    id: str
+    name: str
+    size: int
+
+
+class User(TypedDict, total=False):
+    created_at: str
+    id: str
+    password: str
+    updated_at: str
+    username: str
+
+
+class WindowPlacement(TypedDict, total=False):
+    always_on_top: bool
+    height: int
+    kiosk: bool
+    monitor: int
+    width: int
+    x: int
+    y: int
+
+
+# Method and path of every API operation, keyed by operationId.
+OPERATIONS: Dict[str, Dict[str, str]] = {
+    "BatchAddTags": {"method": "POST", "path": "/api/v1/scripts/batch/tags"},
+    "BatchDeleteCookies": {"method": "POST", "path": "/api/v1/browser/cookies/batch/delete"},
+    "BatchDeleteScriptExecutions": {"method": "POST", "path": "/api/v1/script-executions/batch/delete"},
+    "BatchDeleteScripts": {"method": "POST", "path": "/api/v1/scripts/batch/delete"},
+    "BatchDeleteTaskExecutions": {"method": "POST", "path": "/api/v1/task-executions/batch/delete"},
+    "BatchSetGroup": {"method": "POST", "path": "/api/v1/scripts/batch/group"},
+    "BrowserStatus": {"method": "GET", "path": "/api/v1/browser/status"},
+    "CancelBulkRun": {"method": "POST", "path": "/api/v1/automation/bulk-runs/{id}/cancel"},
+    "CheckAuth": {"method": "GET", "path": "/api/v1/auth/check"},
+    "CheckScriptHealth": {"method": "POST", "path": "/api/v1/scripts/{id}/health"},
+    "CleanupStorage": {"method": "POST", "path": "/api/v1/storage/cleanup"},
+    "ClearInPageRecordingState": {"method": "POST", "path": "/api/v1/browser/record/clear-state"},
+    "CompareScriptExecutions": {"method": "GET", "path": "/api/v1/script-executions/{id}/compare"},
+    "CreateApiKey": {"method": "POST", "path": "/api/v1/api-keys"},
+    "CreateBrowserConfig": {"method": "POST", "path": "/api/v1/browser-configs"},
+    "CreateBrowserInstance": {"method": "POST", "path": "/api/v1/browser/instances"},
+    "CreateEnvironment": {"method": "POST", "path": "/api/v1/environments"},
+    "CreateLLMConfig": {"method": "POST", "path": "/api/v1/llm-configs"},
+    "CreateMCPService": {"method": "POST", "path": "/api/v1/mcp-services"},
+    "CreateNotificationChannel": {"method": "POST", "path": "/api/v1/notifications/channels"},
+    "CreateNotificationDigest": {"method": "POST", "path": "/api/v1/notifications/digests"},
+    "CreateNotificationRule": {"method": "POST", "path": "/api/v1/notifications/rules"},
+    "CreatePageComponent": {"method": "POST", "path": "/api/v1/page-components"},
+    "CreatePrompt": {"method": "POST", "path": "/api/v1/prompts"},
+    "CreateScheduledTask": {"method": "POST", "path": "/api/v1/scheduled-tasks"},
+    "CreateSession": {"method": "POST", "path": "/api/v1/agent/sessions"},
+    "CreateSiteKnowledge": {"method": "POST", "path": "/api/v1/site-knowledge"},
+    "CreateUser": {"method": "POST", "path": "/api/v1/users"},
+    "DeleteApiKey": {"method": "DELETE", "path": "/api/v1/api-keys/{id}"},
+    "DeleteBrowserConfig": {"method": "DELETE", "path": "/api/v1/browser-configs/{id}"},
+    "DeleteBrowserInstance": {"method": "DELETE", "path": "/api/v1/browser/instances/{id}"},
+    "DeleteCookie": {"method": "POST", "path": "/api/v1/browser/cookies/delete"},
+    "DeleteEnvironment": {"method": "DELETE", "path": "/api/v1/environments/{id}"},
+    "DeleteLLMConfig": {"method": "DELETE", "path": "/api/v1/llm-configs/{id}"},
+    "DeleteMCPService": {"method": "DELETE", "path": "/api/v1/mcp-services/{id}"},
+    "DeleteNotificationChannel": {"method": "DELETE", "path": "/api/v1/notifications/channels/{id}"},
+    "DeleteNotificationDigest": {"method": "DELETE", "path": "/api/v1/notifications/digests/{id}"},
+    "DeleteNotificationRule": {"method": "DELETE", "path": "/api/v1/notifications/rules/{id}"},
+    "DeletePageComponent": {"method": "DELETE", "path": "/api/v1/page-components/{id}"},
+    "DeletePrompt": {"method": "DELETE", "path": "/api/v1/prompts/{id}"},
+    "DeleteScheduledTask": {"method": "DELETE", "path": "/api/v1/scheduled-tasks/{id}"},
+    "DeleteScript": {"method": "DELETE", "path": "/api/v1/scripts/{id}"},
+    "DeleteScriptExecution": {"method": "DELETE", "path": "/api/v1/script-executions/{id}"},
+    "DeleteSession": {"method": "DELETE", "path": "/api/v1/agent/sessions/{id}"},
+    "DeleteSiteKnowledge": {"method": "DELETE", "path": "/api/v1/site-knowledge/{id}"},
+    "DeleteTaskExecution": {"method": "DELETE", "path": "/api/v1/task-executions/{id}"},
+    "DeleteUILocale": {"method": "DELETE", "path": "/api/v1/ui-locales/{language}"},
+    "DeleteUpload": {"method": "DELETE", "path": "/api/v1/uploads/{id}"},
+    "DeleteUser": {"method": "DELETE", "path": "/api/v1/users/{id}"},
+    "DiscoverMCPServiceTools": {"method": "POST", "path": "/api/v1/mcp-services/{id}/discover"},
+    "ExecutorA11yScan": {"method": "POST", "path": "/api/v1/executor/a11y-scan"},
+    "ExecutorAudit": {"method": "POST", "path": "/api/v1/executor/audit"},
+    "ExecutorBatch": {"method": "POST", "path": "/api/v1/executor/batch"},
+    "ExecutorCheckLinks": {"method": "POST", "path": "/api/v1/executor/check-links"},
+    "ExecutorChooseOption": {"method": "POST", "path": "/api/v1/executor/choose-option"},
+    "ExecutorClick": {"method": "POST", "path": "/api/v1/executor/click"},
+    "ExecutorClickAt": {"method": "POST", "path": "/api/v1/executor/click-at"},
+    "ExecutorClosePage": {"method": "POST", "path": "/api/v1/executor/close-page"},
+    "ExecutorCollect": {"method": "POST", "path": "/api/v1/executor/collect"},
+    "ExecutorConsoleMessages": {"method": "GET", "path": "/api/v1/executor/console-messages"},
+    "ExecutorDrag": {"method": "POST", "path": "/api/v1/executor/drag"},
+    "ExecutorDragBox": {"method": "POST", "path": "/api/v1/executor/drag-box"},
+    "ExecutorEvaluate": {"method": "POST", "path": "/api/v1/executor/evaluate"},
+    "ExecutorExtract": {"method": "POST", "path": "/api/v1/executor/extract"},
+    "ExecutorFileUpload": {"method": "POST", "path": "/api/v1/executor/file-upload"},
+    "ExecutorFillDate": {"method": "POST", "path": "/api/v1/executor/fill-date"},
+    "ExecutorFillForm": {"method": "POST", "path": "/api/v1/executor/fill-form"},
+    "ExecutorGetAccessibilitySnapshot": {"method": "GET", "path": "/api/v1/executor/semantic-tree"},
+    "ExecutorGetClickableElements": {"method": "GET", "path": "/api/v1/executor/clickable-elements"},
+    "ExecutorGetInputElements": {"method": "GET", "path": "/api/v1/executor/input-elements"},
+    "ExecutorGetPageContent": {"method": "GET", "path": "/api/v1/executor/page-content"},
+    "ExecutorGetPageInfo": {"method": "GET", "path": "/api/v1/executor/page-info"},
+    "ExecutorGetPageText": {"method": "GET", "path": "/api/v1/executor/page-text"},
+    "ExecutorGetText": {"method": "POST", "path": "/api/v1/executor/get-text"},
+    "ExecutorGetValue": {"method": "POST", "path": "/api/v1/executor/get-value"},
+    "ExecutorGoBack": {"method": "POST", "path": "/api/v1/executor/go-back"},
+    "ExecutorGoForward": {"method": "POST", "path": "/api/v1/executor/go-forward"},
+    "ExecutorHandleDialog": {"method": "POST", "path": "/api/v1/executor/handle-dialog"},
+    "ExecutorHelp": {"method": "GET", "path": "/api/v1/executor/help"},
+    "ExecutorHover": {"method": "POST", "path": "/api/v1/executor/hover"},
+    "ExecutorHoverThenClick": {"method": "POST", "path": "/api/v1/executor/hover-then-click"},
+    "ExecutorInspectElement": {"method": "POST", "path": "/api/v1/executor/inspect"},
+    "ExecutorNavigate": {"method": "POST", "path": "/api/v1/executor/navigate"},
+    "ExecutorNetworkRequests": {"method": "GET", "path": "/api/v1/executor/network-requests"},
+    "ExecutorPressKey": {"method": "POST", "path": "/api/v1/executor/press-key"},
+    "ExecutorReadImageText": {"method": "POST", "path": "/api/v1/executor/read-image-text"},
+    "ExecutorReload": {"method": "POST", "path": "/api/v1/executor/reload"},
+    "ExecutorResize": {"method": "POST", "path": "/api/v1/executor/resize"},
+    "ExecutorScreenshot": {"method": "POST", "path": "/api/v1/executor/screenshot"},
+    "ExecutorScroll": {"method": "POST", "path": "/api/v1/executor/scroll"},
+    "ExecutorScrollToBottom": {"method": "POST", "path": "/api/v1/executor/scroll-to-bottom"},
+    "ExecutorSelect": {"method": "POST", "path": "/api/v1/executor/select"},
+    "ExecutorTabs": {"method": "POST", "path": "/api/v1/executor/tabs"},
+    "ExecutorTranslatePage": {"method": "POST", "path": "/api/v1/executor/translate"},
+    "ExecutorType": {"method": "POST", "path": "/api/v1/executor/type"},
+    "ExecutorWaitFor": {"method": "POST", "path": "/api/v1/executor/wait"},
+    "ExportBulkRunResults": {"method": "GET", "path": "/api/v1/automation/bulk-runs/{id}/results"},
+    "ExportBundle": {"method": "POST", "path": "/api/v1/marketplace/export"},
+    "ExportExecutorSkill": {"method": "GET", "path": "/api/v1/executor/export/skill"},
+    "ExportScriptsSkill": {"method": "POST", "path": "/api/v1/scripts/export/skill"},
+    "ExportSession": {"method": "GET", "path": "/api/v1/agent/sessions/{id}/export"},
+    "GenerateMCPConfig": {"method": "POST", "path": "/api/v1/scripts/{id}/mcp/generate"},
+    "GetApiKey": {"method": "GET", "path": "/api/v1/api-keys/{id}"},
+    "GetAutomationRun": {"method": "GET", "path": "/api/v1/automation/runs/{id}"},
+    "GetAutomationScript": {"method": "GET", "path": "/api/v1/automation/scripts/{id}"},
+    "GetBrowserConfig": {"method": "GET", "path": "/api/v1/browser-configs/{id}"},
+    "GetBrowserInstance": {"method": "GET", "path": "/api/v1/browser/instances/{id}"},
+    "GetBulkRun": {"method": "GET", "path": "/api/v1/automation/bulk-runs/{id}"},
+    "GetCookies": {"method": "GET", "path": "/api/v1/cookies/{id}"},
+    "GetCurrentBrowserInstance": {"method": "GET", "path": "/api/v1/browser/instances/current"},
+    "GetEnvironment": {"method": "GET", "path": "/api/v1/environments/{id}"},
+    "GetInstanceThumbnail": {"method": "GET", "path": "/api/v1/browser/instances/{id}/thumbnail"},
+    "GetLLMConfig": {"method": "GET", "path": "/api/v1/llm-configs/{id}"},
+    "GetLLMRouting": {"method": "GET", "path": "/api/v1/llm-routing"},
+    "GetMCPService": {"method": "GET", "path": "/api/v1/mcp-services/{id}"},
+    "GetMCPServiceTools": {"method": "GET", "path": "/api/v1/mcp-services/{id}/tools"},
+    "GetMCPStatus": {"method": "GET", "path": "/api/v1/agent/mcp/status"},
+    "GetMarketplaceInfo": {"method": "GET", "path": "/api/v1/marketplace/info"},
+    "GetNotificationChannel": {"method": "GET", "path": "/api/v1/notifications/channels/{id}"},
+    "GetNotificationDigest": {"method": "GET", "path": "/api/v1/notifications/digests/{id}"},
+    "GetNotificationRule": {"method": "GET", "path": "/api/v1/notifications/rules/{id}"},
+    "GetPageComponent": {"method": "GET", "path": "/api/v1/page-components/{id}"},
+    "GetPageComponentUsages": {"method": "GET", "path": "/api/v1/page-components/{id}/usages"},
+    "GetPlayResult": {"method": "GET", "path": "/api/v1/scripts/play/result"},
+    "GetPrompt": {"method": "GET", "path": "/api/v1/prompts/{id}"},
+    "GetRecordingConfig": {"method": "GET", "path": "/api/v1/recording-config"},
+    "GetRecordingStatus": {"method": "GET", "path": "/api/v1/browser/record/status"},
+    "GetScheduledTask": {"method": "GET", "path": "/api/v1/scheduled-tasks/{id}"},
+    "GetScript": {"method": "GET", "path": "/api/v1/scripts/{id}"},
+    "GetScriptExecution": {"method": "GET", "path": "/api/v1/script-executions/{id}"},
+    "GetScriptHealth": {"method": "GET", "path": "/api/v1/scripts/{id}/health"},
+    "GetScriptMetrics": {"method": "GET", "path": "/api/v1/scripts/{id}/metrics"},
+    "GetScriptState": {"method": "GET", "path": "/api/v1/scripts/{id}/state"},
+    "GetScriptTemplate": {"method": "GET", "path": "/api/v1/templates/{id}"},
+    "GetScriptsSummary": {"method": "GET", "path": "/api/v1/scripts/summary"},
+    "GetSession": {"method": "GET", "path": "/api/v1/agent/sessions/{id}"},
+    "GetSiteKnowledge": {"method": "GET", "path": "/api/v1/site-knowledge/{id}"},
+    "GetStorageUsage": {"method": "GET", "path": "/api/v1/storage/usage"},
+    "GetTaskExecution": {"method": "GET", "path": "/api/v1/task-executions/{id}"},
+    "GetTaskScreenshotImage": {"method": "GET", "path": "/api/v1/scheduled-tasks/{id}/screenshots/{shot_id}/image"},
+    "GetToolConfig": {"method": "GET", "path": "/api/v1/tool-configs/{id}"},
+    "GetUILocale": {"method": "GET", "path": "/api/v1/ui-locales/{language}"},
+    "GetUILocaleTexts": {"method": "GET", "path": "/api/v1/ui-locales/{language}/texts"},
+    "GetUser": {"method": "GET", "path": "/api/v1/users/{id}"},
+    "ImportBrowserCookies": {"method": "POST", "path": "/api/v1/browser/cookies/import"},
+    "InstallMarketplaceBundle": {"method": "POST", "path": "/api/v1/marketplace/install"},
+    "InstallScriptTemplate": {"method": "POST", "path": "/api/v1/templates/{id}/install"},
+    "LintScript": {"method": "GET", "path": "/api/v1/scripts/{id}/lint"},
+    "LintScriptDraft": {"method": "POST", "path": "/api/v1/scripts/lint"},
+    "ListApiKeys": {"method": "GET", "path": "/api/v1/api-keys"},
+    "ListApprovals": {"method": "GET", "path": "/api/v1/agent/sessions/{id}/approvals"},
+    "ListAutomationScripts": {"method": "GET", "path": "/api/v1/automation/scripts"},
+    "ListBrowserConfigs": {"method": "GET", "path": "/api/v1/browser-configs"},
+    "ListBrowserInstances": {"method": "GET", "path": "/api/v1/browser/instances"},
+    "ListEnvironments": {"method": "GET", "path": "/api/v1/environments"},
+    "ListInstalledBundles": {"method": "GET", "path": "/api/v1/marketplace/installed"},
+    "ListLLMConfigs": {"method": "GET", "path": "/api/v1/llm-configs"},
+    "ListMCPCommands": {"method": "GET", "path": "/api/v1/mcp/commands"},
+    "ListMCPCommandsAll": {"method": "GET", "path": "/api/v1/mcp/commands_all"},
+    "ListMCPServices": {"method": "GET", "path": "/api/v1/mcp-services"},
+    "ListMarketplaceBundles": {"method": "GET", "path": "/api/v1/marketplace/bundles"},
+    "ListNotificationChannels": {"method": "GET", "path": "/api/v1/notifications/channels"},
+    "ListNotificationDigests": {"method": "GET", "path": "/api/v1/notifications/digests"},
+    "ListNotificationRules": {"method": "GET", "path": "/api/v1/notifications/rules"},
+    "ListPageComponents": {"method": "GET", "path": "/api/v1/page-components"},
+    "ListPrompts": {"method": "GET", "path": "/api/v1/prompts"},
+    "ListScheduledRuns": {"method": "GET", "path": "/api/v1/calendar/runs"},
+    "ListScheduledTasks": {"method": "GET", "path": "/api/v1/scheduled-tasks"},
+    "ListScriptExecutions": {"method": "GET", "path": "/api/v1/script-executions"},
+    "ListScriptHealth": {"method": "GET", "path": "/api/v1/scripts/health"},
+    "ListScriptTemplates": {"method": "GET", "path": "/api/v1/templates"},
+    "ListScripts": {"method": "GET", "path": "/api/v1/scripts"},
+    "ListSessions": {"method": "GET", "path": "/api/v1/agent/sessions"},
+    "ListSiteKnowledge": {"method": "GET", "path": "/api/v1/site-knowledge"},
+    "ListTaskExecutions": {"method": "GET", "path": "/api/v1/task-executions"},
+    "ListTaskScreenshots": {"method": "GET", "path": "/api/v1/scheduled-tasks/{id}/screenshots"},
+    "ListToolConfigs": {"method": "GET", "path": "/api/v1/tool-configs"},
+    "ListUILocales": {"method": "GET", "path": "/api/v1/ui-locales"},
+    "ListUploads": {"method": "GET", "path": "/api/v1/uploads"},
+    "ListUsers": {"method": "GET", "path": "/api/v1/users"},
+    "Login": {"method": "POST", "path": "/api/v1/auth/login"},
+    "OpenBrowserPage": {"method": "POST", "path": "/api/v1/browser/open"},
+    "PlayScript": {"method": "POST", "path": "/api/v1/scripts/{id}/play"},
+    "PreviewNotificationDigest": {"method": "GET", "path": "/api/v1/notifications/digests/{id}/preview"},
+    "PublishBundle": {"method": "POST", "path": "/api/v1/marketplace/publish"},
+    "ReloadLLM": {"method": "POST", "path": "/api/v1/agent/llm/reload"},
+    "ReplayTranscript": {"method": "POST", "path": "/api/v1/agent/transcripts/replay"},
+    "ResetPrompt": {"method": "POST", "path": "/api/v1/prompts/{id}/reset"},
+    "ResetScriptState": {"method": "DELETE", "path": "/api/v1/scripts/{id}/state"},
+    "ResolveApproval": {"method": "POST", "path": "/api/v1/agent/sessions/{id}/approvals/{approvalId}"},
+    "SaveBrowserCookies": {"method": "POST", "path": "/api/v1/browser/cookies/save"},
+    "SaveScript": {"method": "POST", "path": "/api/v1/scripts"},
+    "SaveUILocale": {"method": "PUT", "path": "/api/v1/ui-locales/{language}"},
+    "ScheduledRunsICal": {"method": "GET", "path": "/api/v1/calendar/runs.ics"},
+    "SendMessage": {"method": "POST", "path": "/api/v1/agent/sessions/{id}/messages"},
+    "SendNotificationDigest": {"method": "POST", "path": "/api/v1/notifications/digests/{id}/send"},
+    "SetApprovalSettings": {"method": "PUT", "path": "/api/v1/agent/sessions/{id}/approval-settings"},
+    "SetBrowserInstanceHeadless": {"method": "POST", "path": "/api/v1/browser/instances/{id}/headless"},
+    "SetLLMConfig": {"method": "POST", "path": "/api/v1/agent/llm/set"},
+    "SetVerificationSettings": {"method": "PUT", "path": "/api/v1/agent/sessions/{id}/verification"},
+    "StartAutomationRun": {"method": "POST", "path": "/api/v1/automation/scripts/{id}/runs"},
+    "StartBrowser": {"method": "POST", "path": "/api/v1/browser/start"},
+    "StartBrowserInstance": {"method": "POST", "path": "/api/v1/browser/instances/{id}/start"},
+    "StartBulkRun": {"method": "POST", "path": "/api/v1/automation/scripts/{id}/bulk-runs"},
+    "StartRecording": {"method": "POST", "path": "/api/v1/browser/record/start"},
+    "StopBrowser": {"method": "POST", "path": "/api/v1/browser/stop"},
+    "StopBrowserInstance": {"method": "POST", "path": "/api/v1/browser/instances/{id}/stop"},
+    "StopRecording": {"method": "POST", "path": "/api/v1/browser/record/stop"},
+    "StreamInstanceThumbnails": {"method": "GET", "path": "/api/v1/browser/instances/{id}/thumbnails/ws"},
+    "SwitchBrowserInstance": {"method": "POST", "path": "/api/v1/browser/instances/{id}/switch"},
+    "SyncToolConfigs": {"method": "POST", "path": "/api/v1/tool-configs/sync"},
+    "TestLLMConfig": {"method": "POST", "path": "/api/v1/llm-configs/test"},
+    "TestNotificationChannel": {"method": "POST", "path": "/api/v1/notifications/channels/{id}/test"},
+    "ToggleMCPService": {"method": "POST", "path": "/api/v1/mcp-services/{id}/toggle"},
+    "ToggleScheduledTask": {"method": "POST", "path": "/api/v1/scheduled-tasks/{id}/toggle"},
+    "ToggleScriptMCPCommand": {"method": "POST", "path": "/api/v1/scripts/{id}/mcp"},
+    "UninstallBundle": {"method": "DELETE", "path": "/api/v1/marketplace/installed/{id}"},
+    "UpdateBrowserConfig": {"method": "PUT", "path": "/api/v1/browser-configs/{id}"},
+    "UpdateBrowserInstance": {"method": "PUT", "path": "/api/v1/browser/instances/{id}"},
+    "UpdateEnvironment": {"method": "PUT", "path": "/api/v1/environments/{id}"},
+    "UpdateLLMConfig": {"method": "PUT", "path": "/api/v1/llm-configs/{id}"},
+    "UpdateLLMRouting": {"method": "PUT", "path": "/api/v1/llm-routing"},
+    "UpdateMCPService": {"method": "PUT", "path": "/api/v1/mcp-services/{id}"},
+    "UpdateMCPServiceToolEnabled": {"method": "PUT", "path": "/api/v1/mcp-services/{id}/tools/{toolName}"},
+    "UpdateNotificationChannel": {"method": "PUT", "path": "/api/v1/notifications/channels/{id}"},
+    "UpdateNotificationDigest": {"method": "PUT", "path": "/api/v1/notifications/digests/{id}"},
+    "UpdateNotificationRule": {"method": "PUT", "path": "/api/v1/notifications/rules/{id}"},
+    "UpdatePageComponent": {"method": "PUT", "path": "/api/v1/page-components/{id}"},
+    "UpdatePassword": {"method": "PUT", "path": "/api/v1/users/{id}/password"},
+    "UpdatePrompt": {"method": "PUT", "path": "/api/v1/prompts/{id}"},
+    "UpdateRecordingConfig": {"method": "PUT", "path": "/api/v1/recording-config"},
+    "UpdateScheduledTask": {"method": "PUT", "path": "/api/v1/scheduled-tasks/{id}"},
+    "UpdateScript": {"method": "PUT", "path": "/api/v1/scripts/{id}"},
+    "UpdateScriptState": {"method": "PUT", "path": "/api/v1/scripts/{id}/state"},
+    "UpdateSiteKnowledge": {"method": "PUT", "path": "/api/v1/site-knowledge/{id}"},
+    "UpdateToolConfig": {"method": "PUT", "path": "/api/v1/tool-configs/{id}"},
+    "UploadFile": {"method": "POST", "path": "/api/v1/uploads"},
+    "getExecutorSnapshot": {"method": "GET", "path": "/api/v1/executor/snapshot"},
+    "getHealth": {"method": "GET", "path": "/health"},
+    "getMcpStatus": {"method": "GET", "path": "/api/v1/mcp/status"},
+}
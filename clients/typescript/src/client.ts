@@ -0,0 +1,395 @@
+/**
+ * Thin client for the BrowserWing REST API.
+ *
+ * Types and the operation table in `models.ts` are generated from the
+ * server's `/openapi.json`; the helpers below cover the common flows
+ * (running scripts, fetching extracted data, driving the executor API)
+ * and the streaming agent endpoint. Requires a global `fetch` (Node 18+).
+ */
+import {
+  operations,
+  type AutomationRun,
+  type AutomationRunRequest,
+  type AutomationScript,
+  type BulkRun,
+  type BulkRunRequest,
+  type Environment,
+  type LintIssue,
+  type OperationId,
+  type OperationResult,
+  type PlayResult,
+  type PlayScriptRequest,
+  type ScheduledTask,
+  type Script,
+  type ScriptExecution,
+  type UploadedFile,
+} from "./models.js";
+
+export interface BrowserWingOptions {
+  /** Server URL, default http://localhost:8080 */
+  baseUrl?: string;
+  /** API key sent as X-BrowserWing-Key (script playback and executor API) */
+  apiKey?: string;
+  /** JWT from login(), required by the other endpoints when auth is enabled */
+  token?: string;
+  /** Request timeout in milliseconds, default 300000 */
+  timeoutMs?: number;
+}
+
+/** Target of an executor command; requests on the same target run one at a time. */
+export interface ExecutorTarget {
+  instanceId?: string;
+  tabId?: string;
+  sessionId?: string;
+}
+
+/** Chunk streamed by the agent message endpoint. */
+export interface AgentChunk {
+  type: "message" | "tool_call" | "done" | "error";
+  content?: string;
+  tool_call?: { tool_name: string; status: string; message?: string };
+  error?: string;
+  message_id?: string;
+}
+
+type Query = Record<string, string | number | boolean | undefined | null>;
+
+/**
+ * Thrown when the server answers with a non-2xx status. `code` is the server
+ * error code (e.g. error.scriptNotFound); `body` is the decoded response, which
+ * for a failed playback also contains the partial `result`.
+ */
+export class BrowserWingError extends Error {
+  constructor(
+    public readonly status: number,
+    public readonly code: string,
+    public readonly detail: string = "",
+    public readonly body: unknown = undefined,
+  ) {
+    super(detail ? `${status} ${code}: ${detail}` : `${status} ${code}`);
+    this.name = "BrowserWingError";
+  }
+}
+
+export class BrowserWing {
+  readonly baseUrl: string;
+  apiKey?: string;
+  token?: string;
+  timeoutMs: number;
+
+  constructor(options: BrowserWingOptions = {}) {
+    this.baseUrl = (options.baseUrl ?? "http://localhost:8080").replace(/\/+$/, "");
+    this.apiKey = options.apiKey;
+    this.token = options.token;
+    this.timeoutMs = options.timeoutMs ?? 300_000;
+  }
+
+  // ---------------------------------------------------------------- core
+
+  private async open(method: string, path: string, body?: unknown, query?: Query, accept = "application/json"): Promise<Response> {
+    const url = new URL(this.baseUrl + path);
+    for (const [key, value] of Object.entries(query ?? {})) {
+      if (value !== undefined && value !== null) {
+        url.searchParams.set(key, String(value));
+      }
+    }
+    const headers: Record<string, string> = { Accept: accept };
+    if (this.apiKey) headers["X-BrowserWing-Key"] = this.apiKey;
+    if (this.token) headers["Authorization"] = `Bearer ${this.token}`;
+    // FormData bodies are sent as multipart and set their own Content-Type
+    const form = body instanceof FormData;
+    if (body !== undefined && !form) headers["Content-Type"] = "application/json";
+
+    const resp = await fetch(url, {
+      method,
+      headers,
+      body: body === undefined ? undefined : form ? body : JSON.stringify(body),
+      signal: AbortSignal.timeout(this.timeoutMs),
+    });
+    if (!resp.ok) {
+      const text = await resp.text();
+      let decoded: unknown = text;
+      try {
+        decoded = JSON.parse(text);
+      } catch {
+        // not JSON, keep the raw text
+      }
+      if (decoded && typeof decoded === "object") {
+        const { error, detail } = decoded as { error?: unknown; detail?: unknown };
+        throw new BrowserWingError(resp.status, String(error ?? ""), detail ? String(detail) : "", decoded);
+      }
+      throw new BrowserWingError(resp.status, text, "", decoded);
+    }
+    return resp;
+  }
+
+  /** Send a request and return the decoded JSON response. */
+  async request<T = any>(method: string, path: string, body?: unknown, query?: Query): Promise<T> {
+    const resp = await this.open(method, path, body, query);
+    const text = await resp.text();
+    return (text ? JSON.parse(text) : undefined) as T;
+  }
+
+  /** Call any operation by its OpenAPI operationId, e.g. call("GetScript", { id }). */
+  async call<T = any>(operationId: OperationId, pathParams: Record<string, string> = {}, body?: unknown, query?: Query): Promise<T> {
+    const op = operations[operationId];
+    let path: string = op.path;
+    for (const [name, value] of Object.entries(pathParams)) {
+      path = path.replace(`{${name}}`, encodeURIComponent(value));
+    }
+    return this.request<T>(op.method, path, body, query);
+  }
+
+  /** Log in with a user account and use the returned JWT for later requests. */
+  async login(username: string, password: string): Promise<string> {
+    const resp = await this.request<{ token: string }>("POST", "/api/v1/auth/login", { username, password });
+    this.token = resp.token;
+    return resp.token;
+  }
+
+  // ------------------------------------------------------------- scripts
+
+  /** Return all scripts, optionally filtered by group or tag. */
+  async listScripts(filter: { group?: string; tag?: string } = {}): Promise<Script[]> {
+    const scripts: Script[] = [];
+    for (let page = 1; ; page++) {
+      const resp = await this.request<{ scripts: Script[] | null; total: number }>("GET", "/api/v1/scripts", undefined, {
+        page,
+        page_size: 100,
+        group: filter.group,
+        tag: filter.tag,
+      });
+      scripts.push(...(resp.scripts ?? []));
+      if (!resp.scripts?.length || scripts.length >= resp.total) {
+        return scripts;
+      }
+    }
+  }
+
+  getScript(scriptId: string): Promise<Script> {
+    return this.request("GET", `/api/v1/scripts/${encodeURIComponent(scriptId)}`);
+  }
+
+  async lintScript(scriptId: string): Promise<LintIssue[]> {
+    const resp = await this.request<{ data: LintIssue[] }>("GET", `/api/v1/scripts/${encodeURIComponent(scriptId)}/lint`);
+    return resp.data;
+  }
+
+  /**
+   * Run a script and wait for it to finish. `params` fill the script's ${name}
+   * placeholders; the extracted data is in `result.extracted_data`. A failed
+   * playback throws BrowserWingError with the result in `error.body.result`.
+   */
+  async playScript(scriptId: string, params: Record<string, string> = {}, options: Omit<PlayScriptRequest, "params"> = {}): Promise<PlayResult> {
+    const resp = await this.request<{ result: PlayResult }>("POST", `/api/v1/scripts/${encodeURIComponent(scriptId)}/play`, {
+      ...options,
+      params,
+    });
+    return resp.result;
+  }
+
+  /** Run a script and return only the data it extracted. */
+  async extract(scriptId: string, params: Record<string, string> = {}, options: Omit<PlayScriptRequest, "params"> = {}): Promise<Record<string, unknown>> {
+    const result = await this.playScript(scriptId, params, options);
+    return result.extracted_data ?? {};
+  }
+
+  /** Return the data extracted by the most recent playback. */
+  async getPlayResult(): Promise<Record<string, unknown>> {
+    const resp = await this.request<{ data: Record<string, unknown> }>("GET", "/api/v1/scripts/play/result");
+    return resp.data;
+  }
+
+  async listExecutions(filter: { scriptId?: string; success?: boolean; page?: number; pageSize?: number } = {}): Promise<ScriptExecution[]> {
+    const resp = await this.request<{ executions: ScriptExecution[] | null }>("GET", "/api/v1/script-executions", undefined, {
+      script_id: filter.scriptId,
+      success: filter.success,
+      page: filter.page ?? 1,
+      page_size: filter.pageSize ?? 20,
+    });
+    return resp.executions ?? [];
+  }
+
+  getExecution(executionId: string): Promise<ScriptExecution> {
+    return this.request("GET", `/api/v1/script-executions/${encodeURIComponent(executionId)}`);
+  }
+
+  // ---------------------------------------------------------- automation
+
+  /** Return the scripts with their parameters as a JSON Schema (`parameters`). */
+  async listAutomationScripts(filter: { group?: string; tag?: string } = {}): Promise<AutomationScript[]> {
+    const resp = await this.request<{ data: AutomationScript[] }>("GET", "/api/v1/automation/scripts", undefined, filter);
+    return resp.data;
+  }
+
+  /**
+   * Start a script in the background and return the run without waiting.
+   * Poll it with getRun()/waitForRun(), or pass `callback_url` to receive the
+   * finished run as a POST.
+   */
+  async startRun(scriptId: string, params: Record<string, string> = {}, options: Omit<AutomationRunRequest, "params"> = {}): Promise<AutomationRun> {
+    const resp = await this.request<{ data: AutomationRun }>("POST", `/api/v1/automation/scripts/${encodeURIComponent(scriptId)}/runs`, {
+      ...options,
+      params,
+    });
+    return resp.data;
+  }
+
+  async getRun(runId: string): Promise<AutomationRun> {
+    const resp = await this.request<{ data: AutomationRun }>("GET", `/api/v1/automation/runs/${encodeURIComponent(runId)}`);
+    return resp.data;
+  }
+
+  /** Poll a run until its status is `succeeded` or `failed`. */
+  async waitForRun(runId: string, options: { intervalMs?: number; timeoutMs?: number } = {}): Promise<AutomationRun> {
+    const deadline = options.timeoutMs === undefined ? Infinity : Date.now() + options.timeoutMs;
+    for (;;) {
+      const run = await this.getRun(runId);
+      if (run.status !== "running") {
+        return run;
+      }
+      if (Date.now() >= deadline) {
+        throw new Error(`run ${runId} still running after ${options.timeoutMs}ms`);
+      }
+      await new Promise((resolve) => setTimeout(resolve, options.intervalMs ?? 2000));
+    }
+  }
+
+  /**
+   * Run a script once per parameter row in the background. `concurrency`
+   * rows run at the same time (default 1, max 10). Poll with
+   * getBulkRun()/waitForBulkRun() and fetch the combined results with
+   * bulkRunResultsCSV().
+   */
+  async startBulkRun(scriptId: string, rows: Array<Record<string, string>>, options: Omit<BulkRunRequest, "rows"> = {}): Promise<BulkRun> {
+    const resp = await this.request<{ data: BulkRun }>("POST", `/api/v1/automation/scripts/${encodeURIComponent(scriptId)}/bulk-runs`, {
+      ...options,
+      rows,
+    });
+    return resp.data;
+  }
+
+  async getBulkRun(bulkRunId: string): Promise<BulkRun> {
+    const resp = await this.request<{ data: BulkRun }>("GET", `/api/v1/automation/bulk-runs/${encodeURIComponent(bulkRunId)}`);
+    return resp.data;
+  }
+
+  /** Poll a bulk run until all of its rows have finished or were cancelled. */
+  async waitForBulkRun(bulkRunId: string, options: { intervalMs?: number; timeoutMs?: number } = {}): Promise<BulkRun> {
+    const deadline = options.timeoutMs === undefined ? Infinity : Date.now() + options.timeoutMs;
+    for (;;) {
+      const run = await this.getBulkRun(bulkRunId);
+      if (run.finished_at) {
+        return run;
+      }
+      if (Date.now() >= deadline) {
+        throw new Error(`bulk run ${bulkRunId} still running after ${options.timeoutMs}ms`);
+      }
+      await new Promise((resolve) => setTimeout(resolve, options.intervalMs ?? 5000));
+    }
+  }
+
+  /** Skip the rows that have not started; running rows finish. */
+  async cancelBulkRun(bulkRunId: string): Promise<BulkRun> {
+    const resp = await this.request<{ data: BulkRun }>("POST", `/api/v1/automation/bulk-runs/${encodeURIComponent(bulkRunId)}/cancel`);
+    return resp.data;
+  }
+
+  /** One CSV line per row: status, error, parameters and extracted data. */
+  async bulkRunResultsCSV(bulkRunId: string): Promise<string> {
+    const resp = await this.open("GET", `/api/v1/automation/bulk-runs/${encodeURIComponent(bulkRunId)}/results`, undefined, undefined, "text/csv");
+    return resp.text();
+  }
+
+  /**
+   * Upload a file to the server's managed upload area. Use the returned
+   * `handle` (upload:<id>) in the file_paths of an upload_file step, so the
+   * file doesn't have to exist on the server beforehand.
+   */
+  async uploadFile(file: Blob, name: string): Promise<UploadedFile> {
+    const form = new FormData();
+    form.append("file", file, name);
+    const resp = await this.request<{ data: UploadedFile }>("POST", "/api/v1/uploads", form);
+    return resp.data;
+  }
+
+  async deleteUpload(uploadId: string): Promise<void> {
+    await this.request("DELETE", `/api/v1/uploads/${encodeURIComponent(uploadId)}`);
+  }
+
+  // --------------------------------------------------- tasks & settings
+
+  async listScheduledTasks(): Promise<ScheduledTask[]> {
+    const resp = await this.request<{ tasks: ScheduledTask[] | null }>("GET", "/api/v1/scheduled-tasks", undefined, { page_size: 100 });
+    return resp.tasks ?? [];
+  }
+
+  async listEnvironments(): Promise<Environment[]> {
+    const resp = await this.request<{ data: Environment[] }>("GET", "/api/v1/environments");
+    return resp.data;
+  }
+
+  // ------------------------------------------------------------ executor
+
+  /**
+   * Run one executor command, e.g. execute("navigate", { url: "https://example.com" }).
+   * GET commands (snapshot, page-info...) send `body` as query parameters.
+   */
+  execute<T = OperationResult>(command: string, body: Record<string, unknown> = {}, target: ExecutorTarget = {}): Promise<T> {
+    const path = `/api/v1/executor/${command}`;
+    const op = Object.values(operations).find((o) => o.path === path);
+    const query: Query = { instance_id: target.instanceId, tab_id: target.tabId, session_id: target.sessionId };
+    if (op?.method === "GET") {
+      for (const [key, value] of Object.entries(body)) {
+        query[key] = value as Query[string];
+      }
+      return this.request<T>("GET", path, undefined, query);
+    }
+    return this.request<T>(op?.method ?? "POST", path, body, query);
+  }
+
+  // --------------------------------------------------------------- agent
+
+  async createAgentSession(llmConfigId?: string): Promise<Record<string, unknown>> {
+    const resp = await this.request<{ session: Record<string, unknown> }>("POST", "/api/v1/agent/sessions", llmConfigId ? { llm_config_id: llmConfigId } : {});
+    return resp.session;
+  }
+
+  /**
+   * Send a message to an agent session and yield the streamed chunks.
+   * The agent API requires a JWT (see login()).
+   */
+  async *streamAgentMessage(sessionId: string, message: string, llmConfigId?: string): AsyncGenerator<AgentChunk> {
+    const body: Record<string, string> = { message };
+    if (llmConfigId) body.llm_config_id = llmConfigId;
+    const resp = await this.open("POST", `/api/v1/agent/sessions/${encodeURIComponent(sessionId)}/messages`, body, undefined, "text/event-stream");
+    yield* parseSSE<AgentChunk>(resp);
+  }
+}
+
+/** Decode a text/event-stream response into JSON events. */
+export async function* parseSSE<T = unknown>(resp: Response): AsyncGenerator<T> {
+  if (!resp.body) return;
+  const reader = resp.body.pipeThrough(new TextDecoderStream()).getReader();
+  let buffer = "";
+  let data: string[] = [];
+  for (;;) {
+    const { value, done } = await reader.read();
+    if (done) break;
+    buffer += value;
+    let newline: number;
+    while ((newline = buffer.indexOf("\n")) >= 0) {
+      const line = buffer.slice(0, newline).replace(/\r$/, "");
+      buffer = buffer.slice(newline + 1);
+      if (line.startsWith("data:")) {
+        data.push(line.slice(5).replace(/^ /, ""));
+      } else if (line === "" && data.length > 0) {
+        yield JSON.parse(data.join("\n")) as T;
+        data = [];
+      }
+    }
+  }
+  if (data.length > 0) {
+    yield JSON.parse(data.join("\n")) as T;
+  }
+}
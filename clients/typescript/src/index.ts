@@ -0,0 +1,3 @@
+export { BrowserWing, BrowserWingError, parseSSE } from "./client.js";
+export type { AgentChunk, BrowserWingOptions, ExecutorTarget } from "./client.js";
+export * from "./models.js";
@@ -0,0 +1,1307 @@
+// Code generated by gen-clients from /openapi.json. DO NOT EDIT.
+
+export const API_VERSION = "v1";
+
+export interface AccessibilityInfo {
+  name?: string;
+  role?: string;
+  value?: string;
+}
+
+export interface ActionCondition {
+  enabled?: boolean;
+  operator?: string;
+  value?: string;
+  variable?: string;
+}
+
+export interface ActionContext {
+  ancestor_tags?: string[];
+  form_hint?: string;
+  nearby_text?: string[];
+}
+
+export interface ActionEvidence {
+  ax_node_id?: string;
+  backend_dom_node_id?: number;
+  confidence?: number;
+}
+
+export interface ActionIntent {
+  object?: string;
+  verb?: string;
+}
+
+export interface ActionVerification {
+  checked_at?: string;
+  error?: string;
+  reason?: string;
+  screenshot?: string;
+  verified?: boolean;
+}
+
+export interface ApiKey {
+  created_at?: string;
+  description?: string;
+  id?: string;
+  key?: string;
+  name?: string;
+  updated_at?: string;
+  url_policy?: Policy;
+  user_id?: string;
+}
+
+export interface AutomationRun {
+  callback_error?: string;
+  callback_url?: string;
+  environment?: string;
+  error?: string;
+  finished_at?: string;
+  id?: string;
+  params?: Record<string, string>;
+  result?: PlayResult;
+  script_id?: string;
+  script_name?: string;
+  started_at?: string;
+  status?: string;
+}
+
+export interface AutomationRunRequest {
+  callback_url?: string;
+  environment?: string;
+  instance_id?: string;
+  params?: Record<string, string>;
+  run_tags?: string[];
+}
+
+export interface AutomationScript {
+  description?: string;
+  group?: string;
+  id?: string;
+  name?: string;
+  parameters?: Record<string, unknown>;
+  tags?: string[];
+  url?: string;
+}
+
+export interface BrowserConfig {
+  block_ads?: boolean;
+  client_cert?: ClientCertificate;
+  created_at?: string;
+  description?: string;
+  float_button?: FloatButtonOptions;
+  headless?: boolean;
+  http_auth?: HTTPAuthCredentials;
+  id?: string;
+  is_default?: boolean;
+  launch_args?: string[];
+  name?: string;
+  proxy?: string;
+  stealth?: StealthOptions;
+  updated_at?: string;
+  url_pattern?: string;
+  use_stealth?: boolean;
+  user_agent?: string;
+}
+
+export interface BrowserInstance {
+  bin_path?: string;
+  control_url?: string;
+  created_at?: string;
+  description?: string;
+  extensions?: string[];
+  headless?: boolean;
+  id?: string;
+  is_active?: boolean;
+  is_default?: boolean;
+  launch_args?: string[];
+  leak_protection?: ProxyLeakProtection;
+  name?: string;
+  proxy?: string;
+  type?: string;
+  updated_at?: string;
+  url_policy?: Policy;
+  use_stealth?: boolean;
+  user_agent?: string;
+  user_data_dir?: string;
+  window?: WindowPlacement;
+}
+
+export interface BulkRun {
+  cancelled?: boolean;
+  columns?: string[];
+  concurrency?: number;
+  environment?: string;
+  failed?: number;
+  finished_at?: string;
+  id?: string;
+  rows?: BulkRunRow[];
+  script_id?: string;
+  script_name?: string;
+  started_at?: string;
+  succeeded?: number;
+  total?: number;
+}
+
+export interface BulkRunRequest {
+  concurrency?: number;
+  environment?: string;
+  instance_id?: string;
+  rows?: Array<Record<string, string>>;
+  run_tags?: string[];
+}
+
+export interface BulkRunRow {
+  error?: string;
+  finished_at?: string;
+  index?: number;
+  params?: Record<string, string>;
+  result?: PlayResult;
+  started_at?: string;
+  status?: string;
+}
+
+export interface CaptureRegion {
+  height?: number;
+  width?: number;
+  x?: number;
+  y?: number;
+}
+
+export interface ChatMessage {
+  content?: string;
+  id?: string;
+  role?: string;
+  timestamp?: string;
+  tool_calls?: ToolCall[];
+}
+
+export interface ChatSession {
+  approval_categories?: string[];
+  created_at?: string;
+  id?: string;
+  llm_config_id?: string;
+  messages?: ChatMessage[];
+  read_only?: boolean;
+  system_prompt_id?: string;
+  updated_at?: string;
+  verify_actions?: boolean;
+}
+
+export interface CheckScriptHealthRequest {
+  instance_id?: string;
+}
+
+export interface ClientCertificate {
+  issuer_cn?: string;
+  pattern?: string;
+  subject_cn?: string;
+}
+
+export interface ComponentElement {
+  description?: string;
+  selector?: string;
+  xpath?: string;
+}
+
+export interface CreateApiKeyRequest {
+  description?: string;
+  name: string;
+  url_policy?: Policy;
+}
+
+export interface CreateUserRequest {
+  password: string;
+  username: string;
+}
+
+export interface DataChange {
+  base?: unknown;
+  change?: string;
+  path?: string;
+  target?: unknown;
+}
+
+export interface DataTransform {
+  expression?: string;
+  type?: string;
+}
+
+export interface DownloadedFile {
+  download_time?: string;
+  file_name?: string;
+  file_path?: string;
+  mime_type?: string;
+  size?: number;
+  url?: string;
+}
+
+export interface ElementFingerprint {
+  ancestors?: string[];
+  attr_hash?: string;
+  attrs?: Record<string, string>;
+  tag?: string;
+  text?: string;
+}
+
+export interface Environment {
+  base_url?: string;
+  created_at?: string;
+  credentials?: Record<string, string>;
+  description?: string;
+  id?: string;
+  name?: string;
+  updated_at?: string;
+  variables?: Record<string, string>;
+}
+
+export interface EnvironmentRequest {
+  base_url?: string;
+  credentials?: Record<string, string>;
+  description?: string;
+  name: string;
+  variables?: Record<string, string>;
+}
+
+export interface Error {
+  detail?: string;
+  /** Error code, e.g. error.scriptNotFound */
+  error?: string;
+}
+
+export interface ExecutionBrief {
+  duration?: number;
+  error_msg?: string;
+  failed_steps?: number;
+  id?: string;
+  item_count?: number;
+  start_time?: string;
+  success?: boolean;
+  success_steps?: number;
+}
+
+export interface ExecutionComparison {
+  base?: ExecutionBrief;
+  data?: DataChange[];
+  data_truncated?: boolean;
+  screenshots?: ScreenshotComparison[];
+  script_id?: string;
+  steps?: StepComparison[];
+  summary?: string[];
+  target?: ExecutionBrief;
+}
+
+export interface ExpectedRequest {
+  method?: string;
+  status?: number;
+  url?: string;
+}
+
+export interface ExtractionMetrics {
+  item_count?: number;
+  null_rate?: number;
+}
+
+export interface FloatButtonOptions {
+  accent_color?: string;
+  background_color?: string;
+  disabled?: boolean;
+  offset_x?: number;
+  offset_y?: number;
+  position?: string;
+  text_color?: string;
+}
+
+export interface HTTPAuthCredentials {
+  has_password?: boolean;
+  password?: string;
+  username?: string;
+}
+
+export interface InstallBundleRequest {
+  bundle?: SignedBundle;
+  id?: string;
+}
+
+export interface InstallOptions {
+  group?: string;
+  name?: string;
+  params?: Record<string, string>;
+}
+
+export interface InstalledBundle {
+  author?: string;
+  id?: string;
+  installed_at?: string;
+  name?: string;
+  public_key?: string;
+  script_ids?: string[];
+  source?: string;
+  updated_at?: string;
+  verified?: boolean;
+  version?: string;
+}
+
+export interface LLMConfigModel {
+  api_key?: string;
+  base_url?: string;
+  created_at?: string;
+  id?: string;
+  is_active?: boolean;
+  is_default?: boolean;
+  model?: string;
+  name?: string;
+  provider?: string;
+  updated_at?: string;
+}
+
+export interface LLMRoutingPolicy {
+  cheap_config_id?: string;
+  cheap_steps?: string[];
+  embedding_config_id?: string;
+  embedding_model?: string;
+  updated_at?: string;
+}
+
+export interface LintIssue {
+  code?: string;
+  message?: string;
+  severity?: string;
+  step?: number;
+}
+
+export interface LoginRequest {
+  password: string;
+  username: string;
+}
+
+export interface LoginResponse {
+  token?: string;
+  user?: User;
+}
+
+export interface MCPService {
+  args?: string[];
+  command?: string;
+  created_at?: string;
+  description?: string;
+  enabled?: boolean;
+  env?: Record<string, string>;
+  id?: string;
+  last_error?: string;
+  name?: string;
+  status?: string;
+  tool_count?: number;
+  type?: string;
+  updated_at?: string;
+  url?: string;
+}
+
+export interface MarketplaceBundle {
+  author?: string;
+  description?: string;
+  id?: string;
+  installed_version?: string;
+  name?: string;
+  public_key?: string;
+  scripts?: number;
+  tags?: string[];
+  updated_at?: string;
+  url?: string;
+  version?: string;
+}
+
+export interface MarketplaceInfo {
+  allow_unsigned?: boolean;
+  can_publish?: boolean;
+  registry_url?: string;
+  signing_public_key?: string;
+  trusted_keys?: string[];
+}
+
+export interface NotificationChannel {
+  bot_token?: string;
+  chat_id?: string;
+  created_at?: string;
+  enabled?: boolean;
+  from?: string;
+  has_bot_token?: boolean;
+  has_smtp_password?: boolean;
+  has_webhook_url?: boolean;
+  id?: string;
+  name?: string;
+  smtp_host?: string;
+  smtp_password?: string;
+  smtp_port?: number;
+  smtp_username?: string;
+  to?: string[];
+  type?: string;
+  updated_at?: string;
+  webhook_url?: string;
+}
+
+export interface NotificationDigest {
+  channel_ids?: string[];
+  created_at?: string;
+  enabled?: boolean;
+  hour?: number;
+  id?: string;
+  last_sent_at?: string;
+  name?: string;
+  period?: string;
+  script_ids?: string[];
+  updated_at?: string;
+  weekday?: number;
+}
+
+export interface NotificationRule {
+  channel_ids?: string[];
+  created_at?: string;
+  enabled?: boolean;
+  event?: string;
+  id?: string;
+  name?: string;
+  script_id?: string;
+  task_id?: string;
+  updated_at?: string;
+}
+
+export interface OperationResult {
+  data?: Record<string, unknown>;
+  error?: string;
+  error_code?: string;
+  message?: string;
+  success?: boolean;
+  timestamp?: string;
+}
+
+export interface PackBundleRequest {
+  author?: string;
+  description?: string;
+  id?: string;
+  include_variables?: boolean;
+  name?: string;
+  script_ids: string[];
+  tags?: string[];
+  version?: string;
+}
+
+export interface PageComponent {
+  created_at?: string;
+  description?: string;
+  elements?: Record<string, ComponentElement>;
+  id?: string;
+  name?: string;
+  site?: string;
+  updated_at?: string;
+}
+
+export interface PageComponentRequest {
+  description?: string;
+  elements?: Record<string, ComponentElement>;
+  name: string;
+  site?: string;
+}
+
+export interface PageVitals {
+  cls?: number;
+  fcp?: number;
+  inp?: number;
+  lcp?: number;
+  ttfb?: number;
+  url?: string;
+}
+
+export interface ParamUIField {
+  help?: string;
+  input_type?: string;
+  label?: string;
+  options?: string[];
+  pattern?: string;
+  pattern_message?: string;
+  placeholder?: string;
+}
+
+export interface PendingApproval {
+  arguments?: Record<string, unknown>;
+  category?: string;
+  created_at?: string;
+  expires_at?: string;
+  id?: string;
+  reason?: string;
+  session_id?: string;
+  tool_name?: string;
+}
+
+export interface PerformanceMetrics {
+  cpu_throttling?: number;
+  errors?: string[];
+  pages?: PageVitals[];
+  trace_path?: string;
+}
+
+export interface PerformanceOptions {
+  cpu_throttling?: number;
+  trace?: boolean;
+  web_vitals?: boolean;
+}
+
+export interface PlayResult {
+  anomalies?: string[];
+  errors?: string[];
+  extracted_data?: Record<string, unknown>;
+  message?: string;
+  performance?: PerformanceMetrics;
+  schema_error?: string;
+  success?: boolean;
+}
+
+export interface PlayScriptRequest {
+  environment?: string;
+  incognito?: boolean;
+  instance_id?: string;
+  params?: Record<string, string>;
+  performance?: PerformanceOptions;
+  run_tags?: string[];
+}
+
+export interface Policy {
+  allow?: string[];
+  deny?: string[];
+  schemes?: string[];
+}
+
+export interface Prompt {
+  content?: string;
+  created_at?: string;
+  description?: string;
+  id?: string;
+  name?: string;
+  type?: string;
+  updated_at?: string;
+  version?: number;
+}
+
+export interface PromptRequest {
+  content: string;
+  description?: string;
+  name: string;
+}
+
+export interface ProxyLeakProtection {
+  block_webrtc?: boolean;
+  proxy_dns?: boolean;
+}
+
+export interface RecordingConfig {
+  created_at?: string;
+  enabled?: boolean;
+  format?: string;
+  frame_rate?: number;
+  id?: string;
+  max_height?: number;
+  max_width?: number;
+  output_dir?: string;
+  quality?: number;
+  region?: CaptureRegion;
+  updated_at?: string;
+}
+
+export interface ReplayReport {
+  changed?: number;
+  diverged?: number;
+  duration?: number;
+  matched?: number;
+  missing?: number;
+  session_id?: string;
+  skipped?: number;
+  start_time?: string;
+  steps?: ReplayStep[];
+  total?: number;
+}
+
+export interface ReplayStep {
+  arguments?: Record<string, unknown>;
+  duration?: number;
+  error?: string;
+  message?: number;
+  outcome?: string;
+  recorded_result?: string;
+  recorded_status?: string;
+  result?: string;
+  tool_call?: number;
+  tool_name?: string;
+}
+
+export interface ScheduledRun {
+  description?: string;
+  end_time?: string;
+  execution_type?: string;
+  schedule?: string;
+  schedule_type?: string;
+  script_id?: string;
+  script_name?: string;
+  start_time?: string;
+  task_id?: string;
+  task_name?: string;
+}
+
+export interface ScheduledRunsResponse {
+  from?: string;
+  runs?: ScheduledRun[];
+  to?: string;
+  truncated?: boolean;
+}
+
+export interface ScheduledTask {
+  agent_llm_id?: string;
+  agent_llm_name?: string;
+  agent_prompt?: string;
+  agent_session_id?: string;
+  browser_instance_id?: string;
+  crawl_concurrency?: number;
+  crawl_max_urls?: number;
+  crawl_sitemap_url?: string;
+  crawl_urls?: string[];
+  created_at?: string;
+  description?: string;
+  enabled?: boolean;
+  environment?: string;
+  execution_count?: number;
+  execution_type?: string;
+  failed_count?: number;
+  health_check_script_ids?: string[];
+  id?: string;
+  last_execution_status?: string;
+  last_execution_time?: string;
+  monitor_selector?: string;
+  monitor_threshold?: number;
+  monitor_url?: string;
+  monitor_webhook_url?: string;
+  name?: string;
+  next_execution_time?: string;
+  schedule_config?: string;
+  schedule_type?: string;
+  screenshot_mode?: string;
+  screenshot_retention?: number;
+  screenshot_urls?: string[];
+  script_id?: string;
+  script_name?: string;
+  script_variables?: Record<string, string>;
+  success_count?: number;
+  updated_at?: string;
+}
+
+export interface ScreenshotComparison {
+  base_path?: string;
+  identical?: boolean;
+  target_path?: string;
+  variable?: string;
+}
+
+export interface Script {
+  actions?: ScriptAction[];
+  can_fetch?: boolean;
+  can_publish?: boolean;
+  created_at?: string;
+  description?: string;
+  downloaded_files?: DownloadedFile[];
+  duration?: number;
+  environment?: string;
+  group?: string;
+  headers?: Record<string, string>;
+  id?: string;
+  incognito?: boolean;
+  is_mcp_command?: boolean;
+  mcp_command_description?: string;
+  mcp_command_name?: string;
+  mcp_input_schema?: Record<string, unknown>;
+  name?: string;
+  output_schema?: Record<string, unknown>;
+  param_ui_schema?: Record<string, ParamUIField>;
+  performance?: PerformanceOptions;
+  run_tags?: string[];
+  tags?: string[];
+  timeouts?: ScriptTimeouts;
+  transform?: DataTransform;
+  updated_at?: string;
+  url?: string;
+  user_agent?: string;
+  variables?: Record<string, string>;
+}
+
+export interface ScriptAction {
+  a11y_max_violations?: number;
+  a11y_min_impact?: string;
+  a11y_tags?: string[];
+  accept?: string;
+  accessibility?: AccessibilityInfo;
+  ai_control_llm_config_id?: string;
+  ai_control_prompt?: string;
+  ai_control_xpath?: string;
+  attribute_name?: string;
+  attrs?: Record<string, string>;
+  component?: string;
+  condition?: ActionCondition;
+  context?: ActionContext;
+  dedup?: boolean;
+  dedup_key?: string;
+  description?: string;
+  disabled?: boolean;
+  duration?: number;
+  editor_strategy?: string;
+  evidence?: ActionEvidence;
+  expected_requests?: ExpectedRequest[];
+  extract_type?: string;
+  extracted_data?: string;
+  file_names?: string[];
+  file_paths?: string[];
+  fingerprint?: ElementFingerprint;
+  intent?: ActionIntent;
+  js_code?: string;
+  key?: string;
+  method?: string;
+  multiple?: boolean;
+  remark?: string;
+  run_on?: string[];
+  screenshot_height?: number;
+  screenshot_mode?: string;
+  screenshot_width?: number;
+  script_id?: string;
+  script_params?: Record<string, string>;
+  scroll_x?: number;
+  scroll_y?: number;
+  selector?: string;
+  skip_on?: string[];
+  status?: number;
+  tag_name?: string;
+  target_selector?: string;
+  target_xpath?: string;
+  text?: string;
+  timestamp?: number;
+  type?: string;
+  update_cursor?: boolean;
+  url?: string;
+  value?: string;
+  variable_name?: string;
+  x?: number;
+  xhr_id?: string;
+  xpath?: string;
+  y?: number;
+}
+
+export interface ScriptExecution {
+  anomalies?: string[];
+  created_at?: string;
+  duration?: number;
+  end_time?: string;
+  error_code?: string;
+  error_msg?: string;
+  extracted_data?: Record<string, unknown>;
+  failed_steps?: number;
+  id?: string;
+  instance_id?: string;
+  instance_name?: string;
+  message?: string;
+  metrics?: ExtractionMetrics;
+  performance?: PerformanceMetrics;
+  schema_error?: string;
+  script_id?: string;
+  script_name?: string;
+  start_time?: string;
+  steps?: StepOutcome[];
+  success?: boolean;
+  success_steps?: number;
+  total_steps?: number;
+  video_path?: string;
+  work_dir?: string;
+}
+
+export interface ScriptHealthReport {
+  checked_at?: string;
+  checks?: SelectorCheck[];
+  duration?: number;
+  errors?: string[];
+  found?: number;
+  healthy?: boolean;
+  missing?: number;
+  script_id?: string;
+  script_name?: string;
+  task_id?: string;
+  unverified?: number;
+}
+
+export interface ScriptMetricsPoint {
+  anomalies?: string[];
+  execution_id?: string;
+  metrics?: ExtractionMetrics;
+  start_time?: string;
+  success?: boolean;
+}
+
+export interface ScriptStateSummary {
+  cursor?: string;
+  last_run_at?: string;
+  script_id?: string;
+  seen_count?: number;
+  updated_at?: string;
+}
+
+export interface ScriptTemplate {
+  category?: string;
+  description?: string;
+  id?: string;
+  name?: string;
+  parameters?: Record<string, unknown>;
+  script?: Script;
+  tags?: string[];
+}
+
+export interface ScriptTimeouts {
+  element_seconds?: number;
+  navigation_seconds?: number;
+}
+
+export interface SelectorCheck {
+  page_url?: string;
+  reason?: string;
+  selector?: string;
+  status?: string;
+  step?: number;
+  target?: string;
+  type?: string;
+  xpath?: string;
+}
+
+export interface SessionTranscript {
+  created_at?: string;
+  exported_at?: string;
+  llm_config_id?: string;
+  messages?: ChatMessage[];
+  screenshots?: TranscriptScreenshot[];
+  session_id?: string;
+  version?: number;
+}
+
+export interface SignedBundle {
+  bundle?: unknown;
+  public_key?: string;
+  signature?: string;
+}
+
+export interface SiteKnowledge {
+  content?: string;
+  created_at?: string;
+  domains?: string[];
+  enabled?: boolean;
+  id?: string;
+  login_script_id?: string;
+  name?: string;
+  updated_at?: string;
+}
+
+export interface SiteKnowledgeRequest {
+  content?: string;
+  domains: string[];
+  enabled?: boolean;
+  login_script_id?: string;
+  name: string;
+}
+
+export interface StealthOptions {
+  block_webrtc?: boolean;
+  languages?: string[];
+  level?: string;
+}
+
+export interface StepComparison {
+  base_duration?: number;
+  base_status?: string;
+  changed?: boolean;
+  index?: number;
+  target_duration?: number;
+  target_error?: string;
+  target_status?: string;
+  type?: string;
+}
+
+export interface StepOutcome {
+  duration?: number;
+  error?: string;
+  index?: number;
+  status?: string;
+  type?: string;
+}
+
+export interface TaskExecution {
+  agent_session_id?: string;
+  created_at?: string;
+  duration?: number;
+  end_time?: string;
+  error_code?: string;
+  error_msg?: string;
+  execution_type?: string;
+  id?: string;
+  message?: string;
+  result_data?: Record<string, unknown>;
+  script_id?: string;
+  start_time?: string;
+  success?: boolean;
+  task_id?: string;
+  task_name?: string;
+}
+
+export interface ToolCall {
+  arguments?: Record<string, unknown>;
+  instructions?: string;
+  message?: string;
+  result?: string;
+  status?: string;
+  timestamp?: string;
+  tool_name?: string;
+  verification?: ActionVerification;
+}
+
+export interface ToolConfig {
+  created_at?: string;
+  description?: string;
+  enabled?: boolean;
+  id?: string;
+  name?: string;
+  parameters?: Record<string, unknown>;
+  script_id?: string;
+  type?: string;
+  updated_at?: string;
+}
+
+export interface TranscriptScreenshot {
+  data?: string;
+  error?: string;
+  message?: number;
+  mime_type?: string;
+  path?: string;
+  tool_call?: number;
+}
+
+export interface UILocale {
+  base?: string;
+  created_at?: string;
+  float_button?: Record<string, string>;
+  language?: string;
+  name?: string;
+  player?: Record<string, string>;
+  recorder?: Record<string, string>;
+  updated_at?: string;
+}
+
+export interface UiLocaleTexts {
+  float_button?: Record<string, string>;
+  language?: string;
+  player?: Record<string, string>;
+  recorder?: Record<string, string>;
+}
+
+export interface UpdatePasswordRequest {
+  new_password: string;
+  old_password: string;
+}
+
+export interface UpdateScriptRequest {
+  actions?: ScriptAction[];
+  description?: string;
+  environment?: string;
+  headers?: Record<string, string>;
+  incognito?: boolean;
+  is_mcp_command?: boolean;
+  mcp_command_description?: string;
+  mcp_command_name?: string;
+  mcp_input_schema?: Record<string, unknown>;
+  name?: string;
+  output_schema?: Record<string, unknown>;
+  param_ui_schema?: Record<string, ParamUIField>;
+  performance?: PerformanceOptions;
+  run_tags?: string[];
+  tags?: string[];
+  timeouts?: ScriptTimeouts;
+  transform?: DataTransform;
+  url?: string;
+  user_agent?: string;
+  variables?: Record<string, string>;
+}
+
+export interface UpdateScriptStateRequest {
+  clear_seen?: boolean;
+  cursor?: string;
+}
+
+export interface UploadedFile {
+  created_at?: string;
+  handle?: string;
+  id?: string;
+  name?: string;
+  size?: number;
+}
+
+export interface User {
+  created_at?: string;
+  id?: string;
+  password?: string;
+  updated_at?: string;
+  username?: string;
+}
+
+export interface WindowPlacement {
+  always_on_top?: boolean;
+  height?: number;
+  kiosk?: boolean;
+  monitor?: number;
+  width?: number;
+  x?: number;
+  y?: number;
+}
+
+/** Method and path of every API operation, keyed by operationId. */
+export const operations = {
+  BatchAddTags: { method: "POST", path: "/api/v1/scripts/batch/tags" },
+  BatchDeleteCookies: { method: "POST", path: "/api/v1/browser/cookies/batch/delete" },
+  BatchDeleteScriptExecutions: { method: "POST", path: "/api/v1/script-executions/batch/delete" },
+  BatchDeleteScripts: { method: "POST", path: "/api/v1/scripts/batch/delete" },
+  BatchDeleteTaskExecutions: { method: "POST", path: "/api/v1/task-executions/batch/delete" },
+  BatchSetGroup: { method: "POST", path: "/api/v1/scripts/batch/group" },
+  BrowserStatus: { method: "GET", path: "/api/v1/browser/status" },
+  CancelBulkRun: { method: "POST", path: "/api/v1/automation/bulk-runs/{id}/cancel" },
+  CheckAuth: { method: "GET", path: "/api/v1/auth/check" },
+  CheckScriptHealth: { method: "POST", path: "/api/v1/scripts/{id}/health" },
+  CleanupStorage: { method: "POST", path: "/api/v1/storage/cleanup" },
+  ClearInPageRecordingState: { method: "POST", path: "/api/v1/browser/record/clear-state" },
+  CompareScriptExecutions: { method: "GET", path: "/api/v1/script-executions/{id}/compare" },
+  CreateApiKey: { method: "POST", path: "/api/v1/api-keys" },
+  CreateBrowserConfig: { method: "POST", path: "/api/v1/browser-configs" },
+  CreateBrowserInstance: { method: "POST", path: "/api/v1/browser/instances" },
+  CreateEnvironment: { method: "POST", path: "/api/v1/environments" },
+  CreateLLMConfig: { method: "POST", path: "/api/v1/llm-configs" },
+  CreateMCPService: { method: "POST", path: "/api/v1/mcp-services" },
+  CreateNotificationChannel: { method: "POST", path: "/api/v1/notifications/channels" },
+  CreateNotificationDigest: { method: "POST", path: "/api/v1/notifications/digests" },
+  CreateNotificationRule: { method: "POST", path: "/api/v1/notifications/rules" },
+  CreatePageComponent: { method: "POST", path: "/api/v1/page-components" },
+  CreatePrompt: { method: "POST", path: "/api/v1/prompts" },
+  CreateScheduledTask: { method: "POST", path: "/api/v1/scheduled-tasks" },
+  CreateSession: { method: "POST", path: "/api/v1/agent/sessions" },
+  CreateSiteKnowledge: { method: "POST", path: "/api/v1/site-knowledge" },
+  CreateUser: { method: "POST", path: "/api/v1/users" },
+  DeleteApiKey: { method: "DELETE", path: "/api/v1/api-keys/{id}" },
+  DeleteBrowserConfig: { method: "DELETE", path: "/api/v1/browser-configs/{id}" },
+  DeleteBrowserInstance: { method: "DELETE", path: "/api/v1/browser/instances/{id}" },
+  DeleteCookie: { method: "POST", path: "/api/v1/browser/cookies/delete" },
+  DeleteEnvironment: { method: "DELETE", path: "/api/v1/environments/{id}" },
+  DeleteLLMConfig: { method: "DELETE", path: "/api/v1/llm-configs/{id}" },
+  DeleteMCPService: { method: "DELETE", path: "/api/v1/mcp-services/{id}" },
+  DeleteNotificationChannel: { method: "DELETE", path: "/api/v1/notifications/channels/{id}" },
+  DeleteNotificationDigest: { method: "DELETE", path: "/api/v1/notifications/digests/{id}" },
+  DeleteNotificationRule: { method: "DELETE", path: "/api/v1/notifications/rules/{id}" },
+  DeletePageComponent: { method: "DELETE", path: "/api/v1/page-components/{id}" },
+  DeletePrompt: { method: "DELETE", path: "/api/v1/prompts/{id}" },
+  DeleteScheduledTask: { method: "DELETE", path: "/api/v1/scheduled-tasks/{id}" },
+  DeleteScript: { method: "DELETE", path: "/api/v1/scripts/{id}" },
+  DeleteScriptExecution: { method: "DELETE", path: "/api/v1/script-executions/{id}" },
+  DeleteSession: { method: "DELETE", path: "/api/v1/agent/sessions/{id}" },
+  DeleteSiteKnowledge: { method: "DELETE", path: "/api/v1/site-knowledge/{id}" },
+  DeleteTaskExecution: { method: "DELETE", path: "/api/v1/task-executions/{id}" },
+  DeleteUILocale: { method: "DELETE", path: "/api/v1/ui-locales/{language}" },
+  DeleteUpload: { method: "DELETE", path: "/api/v1/uploads/{id}" },
+  DeleteUser: { method: "DELETE", path: "/api/v1/users/{id}" },
+  DiscoverMCPServiceTools: { method: "POST", path: "/api/v1/mcp-services/{id}/discover" },
+  ExecutorA11yScan: { method: "POST", path: "/api/v1/executor/a11y-scan" },
+  ExecutorAudit: { method: "POST", path: "/api/v1/executor/audit" },
+  ExecutorBatch: { method: "POST", path: "/api/v1/executor/batch" },
+  ExecutorCheckLinks: { method: "POST", path: "/api/v1/executor/check-links" },
+  ExecutorChooseOption: { method: "POST", path: "/api/v1/executor/choose-option" },
+  ExecutorClick: { method: "POST", path: "/api/v1/executor/click" },
+  ExecutorClickAt: { method: "POST", path: "/api/v1/executor/click-at" },
+  ExecutorClosePage: { method: "POST", path: "/api/v1/executor/close-page" },
+  ExecutorCollect: { method: "POST", path: "/api/v1/executor/collect" },
+  ExecutorConsoleMessages: { method: "GET", path: "/api/v1/executor/console-messages" },
+  ExecutorDrag: { method: "POST", path: "/api/v1/executor/drag" },
+  ExecutorDragBox: { method: "POST", path: "/api/v1/executor/drag-box" },
+  ExecutorEvaluate: { method: "POST", path: "/api/v1/executor/evaluate" },
+  ExecutorExtract: { method: "POST", path: "/api/v1/executor/extract" },
+  ExecutorFileUpload: { method: "POST", path: "/api/v1/executor/file-upload" },
+  ExecutorFillDate: { method: "POST", path: "/api/v1/executor/fill-date" },
+  ExecutorFillForm: { method: "POST", path: "/api/v1/executor/fill-form" },
+  ExecutorGetAccessibilitySnapshot: { method: "GET", path: "/api/v1/executor/semantic-tree" },
+  ExecutorGetClickableElements: { method: "GET", path: "/api/v1/executor/clickable-elements" },
+  ExecutorGetInputElements: { method: "GET", path: "/api/v1/executor/input-elements" },
+  ExecutorGetPageContent: { method: "GET", path: "/api/v1/executor/page-content" },
+  ExecutorGetPageInfo: { method: "GET", path: "/api/v1/executor/page-info" },
+  ExecutorGetPageText: { method: "GET", path: "/api/v1/executor/page-text" },
+  ExecutorGetText: { method: "POST", path: "/api/v1/executor/get-text" },
+  ExecutorGetValue: { method: "POST", path: "/api/v1/executor/get-value" },
+  ExecutorGoBack: { method: "POST", path: "/api/v1/executor/go-back" },
+  ExecutorGoForward: { method: "POST", path: "/api/v1/executor/go-forward" },
+  ExecutorHandleDialog: { method: "POST", path: "/api/v1/executor/handle-dialog" },
+  ExecutorHelp: { method: "GET", path: "/api/v1/executor/help" },
+  ExecutorHover: { method: "POST", path: "/api/v1/executor/hover" },
+  ExecutorHoverThenClick: { method: "POST", path: "/api/v1/executor/hover-then-click" },
+  ExecutorInspectElement: { method: "POST", path: "/api/v1/executor/inspect" },
+  ExecutorNavigate: { method: "POST", path: "/api/v1/executor/navigate" },
+  ExecutorNetworkRequests: { method: "GET", path: "/api/v1/executor/network-requests" },
+  ExecutorPressKey: { method: "POST", path: "/api/v1/executor/press-key" },
+  ExecutorReadImageText: { method: "POST", path: "/api/v1/executor/read-image-text" },
+  ExecutorReload: { method: "POST", path: "/api/v1/executor/reload" },
+  ExecutorResize: { method: "POST", path: "/api/v1/executor/resize" },
+  ExecutorScreenshot: { method: "POST", path: "/api/v1/executor/screenshot" },
+  ExecutorScroll: { method: "POST", path: "/api/v1/executor/scroll" },
+  ExecutorScrollToBottom: { method: "POST", path: "/api/v1/executor/scroll-to-bottom" },
+  ExecutorSelect: { method: "POST", path: "/api/v1/executor/select" },
+  ExecutorTabs: { method: "POST", path: "/api/v1/executor/tabs" },
+  ExecutorTranslatePage: { method: "POST", path: "/api/v1/executor/translate" },
+  ExecutorType: { method: "POST", path: "/api/v1/executor/type" },
+  ExecutorWaitFor: { method: "POST", path: "/api/v1/executor/wait" },
+  ExportBulkRunResults: { method: "GET", path: "/api/v1/automation/bulk-runs/{id}/results" },
+  ExportBundle: { method: "POST", path: "/api/v1/marketplace/export" },
+  ExportExecutorSkill: { method: "GET", path: "/api/v1/executor/export/skill" },
+  ExportScriptsSkill: { method: "POST", path: "/api/v1/scripts/export/skill" },
+  ExportSession: { method: "GET", path: "/api/v1/agent/sessions/{id}/export" },
+  GenerateMCPConfig: { method: "POST", path: "/api/v1/scripts/{id}/mcp/generate" },
+  GetApiKey: { method: "GET", path: "/api/v1/api-keys/{id}" },
+  GetAutomationRun: { method: "GET", path: "/api/v1/automation/runs/{id}" },
+  GetAutomationScript: { method: "GET", path: "/api/v1/automation/scripts/{id}" },
+  GetBrowserConfig: { method: "GET", path: "/api/v1/browser-configs/{id}" },
+  GetBrowserInstance: { method: "GET", path: "/api/v1/browser/instances/{id}" },
+  GetBulkRun: { method: "GET", path: "/api/v1/automation/bulk-runs/{id}" },
+  GetCookies: { method: "GET", path: "/api/v1/cookies/{id}" },
+  GetCurrentBrowserInstance: { method: "GET", path: "/api/v1/browser/instances/current" },
+  GetEnvironment: { method: "GET", path: "/api/v1/environments/{id}" },
+  GetInstanceThumbnail: { method: "GET", path: "/api/v1/browser/instances/{id}/thumbnail" },
+  GetLLMConfig: { method: "GET", path: "/api/v1/llm-configs/{id}" },
+  GetLLMRouting: { method: "GET", path: "/api/v1/llm-routing" },
+  GetMCPService: { method: "GET", path: "/api/v1/mcp-services/{id}" },
+  GetMCPServiceTools: { method: "GET", path: "/api/v1/mcp-services/{id}/tools" },
+  GetMCPStatus: { method: "GET", path: "/api/v1/agent/mcp/status" },
+  GetMarketplaceInfo: { method: "GET", path: "/api/v1/marketplace/info" },
+  GetNotificationChannel: { method: "GET", path: "/api/v1/notifications/channels/{id}" },
+  GetNotificationDigest: { method: "GET", path: "/api/v1/notifications/digests/{id}" },
+  GetNotificationRule: { method: "GET", path: "/api/v1/notifications/rules/{id}" },
+  GetPageComponent: { method: "GET", path: "/api/v1/page-components/{id}" },
+  GetPageComponentUsages: { method: "GET", path: "/api/v1/page-components/{id}/usages" },
+  GetPlayResult: { method: "GET", path: "/api/v1/scripts/play/result" },
+  GetPrompt: { method: "GET", path: "/api/v1/prompts/{id}" },
+  GetRecordingConfig: { method: "GET", path: "/api/v1/recording-config" },
+  GetRecordingStatus: { method: "GET", path: "/api/v1/browser/record/status" },
+  GetScheduledTask: { method: "GET", path: "/api/v1/scheduled-tasks/{id}" },
+  GetScript: { method: "GET", path: "/api/v1/scripts/{id}" },
+  GetScriptExecution: { method: "GET", path: "/api/v1/script-executions/{id}" },
+  GetScriptHealth: { method: "GET", path: "/api/v1/scripts/{id}/health" },
+  GetScriptMetrics: { method: "GET", path: "/api/v1/scripts/{id}/metrics" },
+  GetScriptState: { method: "GET", path: "/api/v1/scripts/{id}/state" },
+  GetScriptTemplate: { method: "GET", path: "/api/v1/templates/{id}" },
+  GetScriptsSummary: { method: "GET", path: "/api/v1/scripts/summary" },
+  GetSession: { method: "GET", path: "/api/v1/agent/sessions/{id}" },
+  GetSiteKnowledge: { method: "GET", path: "/api/v1/site-knowledge/{id}" },
+  GetStorageUsage: { method: "GET", path: "/api/v1/storage/usage" },
+  GetTaskExecution: { method: "GET", path: "/api/v1/task-executions/{id}" },
+  GetTaskScreenshotImage: { method: "GET", path: "/api/v1/scheduled-tasks/{id}/screenshots/{shot_id}/image" },
+  GetToolConfig: { method: "GET", path: "/api/v1/tool-configs/{id}" },
+  GetUILocale: { method: "GET", path: "/api/v1/ui-locales/{language}" },
+  GetUILocaleTexts: { method: "GET", path: "/api/v1/ui-locales/{language}/texts" },
+  GetUser: { method: "GET", path: "/api/v1/users/{id}" },
+  ImportBrowserCookies: { method: "POST", path: "/api/v1/browser/cookies/import" },
+  InstallMarketplaceBundle: { method: "POST", path: "/api/v1/marketplace/install" },
+  InstallScriptTemplate: { method: "POST", path: "/api/v1/templates/{id}/install" },
+  LintScript: { method: "GET", path: "/api/v1/scripts/{id}/lint" },
+  LintScriptDraft: { method: "POST", path: "/api/v1/scripts/lint" },
+  ListApiKeys: { method: "GET", path: "/api/v1/api-keys" },
+  ListApprovals: { method: "GET", path: "/api/v1/agent/sessions/{id}/approvals" },
+  ListAutomationScripts: { method: "GET", path: "/api/v1/automation/scripts" },
+  ListBrowserConfigs: { method: "GET", path: "/api/v1/browser-configs" },
+  ListBrowserInstances: { method: "GET", path: "/api/v1/browser/instances" },
+  ListEnvironments: { method: "GET", path: "/api/v1/environments" },
+  ListInstalledBundles: { method: "GET", path: "/api/v1/marketplace/installed" },
+  ListLLMConfigs: { method: "GET", path: "/api/v1/llm-configs" },
+  ListMCPCommands: { method: "GET", path: "/api/v1/mcp/commands" },
+  ListMCPCommandsAll: { method: "GET", path: "/api/v1/mcp/commands_all" },
+  ListMCPServices: { method: "GET", path: "/api/v1/mcp-services" },
+  ListMarketplaceBundles: { method: "GET", path: "/api/v1/marketplace/bundles" },
+  ListNotificationChannels: { method: "GET", path: "/api/v1/notifications/channels" },
+  ListNotificationDigests: { method: "GET", path: "/api/v1/notifications/digests" },
+  ListNotificationRules: { method: "GET", path: "/api/v1/notifications/rules" },
+  ListPageComponents: { method: "GET", path: "/api/v1/page-components" },
+  ListPrompts: { method: "GET", path: "/api/v1/prompts" },
+  ListScheduledRuns: { method: "GET", path: "/api/v1/calendar/runs" },
+  ListScheduledTasks: { method: "GET", path: "/api/v1/scheduled-tasks" },
+  ListScriptExecutions: { method: "GET", path: "/api/v1/script-executions" },
+  ListScriptHealth: { method: "GET", path: "/api/v1/scripts/health" },
+  ListScriptTemplates: { method: "GET", path: "/api/v1/templates" },
+  ListScripts: { method: "GET", path: "/api/v1/scripts" },
+  ListSessions: { method: "GET", path: "/api/v1/agent/sessions" },
+  ListSiteKnowledge: { method: "GET", path: "/api/v1/site-knowledge" },
+  ListTaskExecutions: { method: "GET", path: "/api/v1/task-executions" },
+  ListTaskScreenshots: { method: "GET", path: "/api/v1/scheduled-tasks/{id}/screenshots" },
+  ListToolConfigs: { method: "GET", path: "/api/v1/tool-configs" },
+  ListUILocales: { method: "GET", path: "/api/v1/ui-locales" },
+  ListUploads: { method: "GET", path: "/api/v1/uploads" },
+  ListUsers: { method: "GET", path: "/api/v1/users" },
+  Login: { method: "POST", path: "/api/v1/auth/login" },
+  OpenBrowserPage: { method: "POST", path: "/api/v1/browser/open" },
+  PlayScript: { method: "POST", path: "/api/v1/scripts/{id}/play" },
+  PreviewNotificationDigest: { method: "GET", path: "/api/v1/notifications/digests/{id}/preview" },
+  PublishBundle: { method: "POST", path: "/api/v1/marketplace/publish" },
+  ReloadLLM: { method: "POST", path: "/api/v1/agent/llm/reload" },
+  ReplayTranscript: { method: "POST", path: "/api/v1/agent/transcripts/replay" },
+  ResetPrompt: { method: "POST", path: "/api/v1/prompts/{id}/reset" },
+  ResetScriptState: { method: "DELETE", path: "/api/v1/scripts/{id}/state" },
+  ResolveApproval: { method: "POST", path: "/api/v1/agent/sessions/{id}/approvals/{approvalId}" },
+  SaveBrowserCookies: { method: "POST", path: "/api/v1/browser/cookies/save" },
+  SaveScript: { method: "POST", path: "/api/v1/scripts" },
+  SaveUILocale: { method: "PUT", path: "/api/v1/ui-locales/{language}" },
+  ScheduledRunsICal: { method: "GET", path: "/api/v1/calendar/runs.ics" },
+  SendMessage: { method: "POST", path: "/api/v1/agent/sessions/{id}/messages" },
+  SendNotificationDigest: { method: "POST", path: "/api/v1/notifications/digests/{id}/send" },
+  SetApprovalSettings: { method: "PUT", path: "/api/v1/agent/sessions/{id}/approval-settings" },
+  SetBrowserInstanceHeadless: { method: "POST", path: "/api/v1/browser/instances/{id}/headless" },
+  SetLLMConfig: { method: "POST", path: "/api/v1/agent/llm/set" },
+  SetVerificationSettings: { method: "PUT", path: "/api/v1/agent/sessions/{id}/verification" },
+  StartAutomationRun: { method: "POST", path: "/api/v1/automation/scripts/{id}/runs" },
+  StartBrowser: { method: "POST", path: "/api/v1/browser/start" },
+  StartBrowserInstance: { method: "POST", path: "/api/v1/browser/instances/{id}/start" },
+  StartBulkRun: { method: "POST", path: "/api/v1/automation/scripts/{id}/bulk-runs" },
+  StartRecording: { method: "POST", path: "/api/v1/browser/record/start" },
+  StopBrowser: { method: "POST", path: "/api/v1/browser/stop" },
+  StopBrowserInstance: { method: "POST", path: "/api/v1/browser/instances/{id}/stop" },
+  StopRecording: { method: "POST", path: "/api/v1/browser/record/stop" },
+  StreamInstanceThumbnails: { method: "GET", path: "/api/v1/browser/instances/{id}/thumbnails/ws" },
+  SwitchBrowserInstance: { method: "POST", path: "/api/v1/browser/instances/{id}/switch" },
+  SyncToolConfigs: { method: "POST", path: "/api/v1/tool-configs/sync" },
+  TestLLMConfig: { method: "POST", path: "/api/v1/llm-configs/test" },
+  TestNotificationChannel: { method: "POST", path: "/api/v1/notifications/channels/{id}/test" },
+  ToggleMCPService: { method: "POST", path: "/api/v1/mcp-services/{id}/toggle" },
+  ToggleScheduledTask: { method: "POST", path: "/api/v1/scheduled-tasks/{id}/toggle" },
+  ToggleScriptMCPCommand: { method: "POST", path: "/api/v1/scripts/{id}/mcp" },
+  UninstallBundle: { method: "DELETE", path: "/api/v1/marketplace/installed/{id}" },
+  UpdateBrowserConfig: { method: "PUT", path: "/api/v1/browser-configs/{id}" },
+  UpdateBrowserInstance: { method: "PUT", path: "/api/v1/browser/instances/{id}" },
+  UpdateEnvironment: { method: "PUT", path: "/api/v1/environments/{id}" },
+  UpdateLLMConfig: { method: "PUT", path: "/api/v1/llm-configs/{id}" },
+  UpdateLLMRouting: { method: "PUT", path: "/api/v1/llm-routing" },
+  UpdateMCPService: { method: "PUT", path: "/api/v1/mcp-services/{id}" },
+  UpdateMCPServiceToolEnabled: { method: "PUT", path: "/api/v1/mcp-services/{id}/tools/{toolName}" },
+  UpdateNotificationChannel: { method: "PUT", path: "/api/v1/notifications/channels/{id}" },
+  UpdateNotificationDigest: { method: "PUT", path: "/api/v1/notifications/digests/{id}" },
+  UpdateNotificationRule: { method: "PUT", path: "/api/v1/notifications/rules/{id}" },
+  UpdatePageComponent: { method: "PUT", path: "/api/v1/page-components/{id}" },
+  UpdatePassword: { method: "PUT", path: "/api/v1/users/{id}/password" },
+  UpdatePrompt: { method: "PUT", path: "/api/v1/prompts/{id}" },
+  UpdateRecordingConfig: { method: "PUT", path: "/api/v1/recording-config" },
+  UpdateScheduledTask: { method: "PUT", path: "/api/v1/scheduled-tasks/{id}" },
+  UpdateScript: { method: "PUT", path: "/api/v1/scripts/{id}" },
+  UpdateScriptState: { method: "PUT", path: "/api/v1/scripts/{id}/state" },
+  UpdateSiteKnowledge: { method: "PUT", path: "/api/v1/site-knowledge/{id}" },
+  UpdateToolConfig: { method: "PUT", path: "/api/v1/tool-configs/{id}" },
+  UploadFile: { method: "POST", path: "/api/v1/uploads" },
+  getExecutorSnapshot: { method: "GET", path: "/api/v1/executor/snapshot" },
+  getHealth: { method: "GET", path: "/health" },
+  getMcpStatus: { method: "GET", path: "/api/v1/mcp/status" },
+} as const;
+
+export type OperationId = keyof typeof operations;
@@ -304,6 +304,17 @@ export interface ScriptAction {
   }
 }
 
+// 脚本参数的表单元数据，用于渲染执行参数表单
+export interface ParamUIField {
+  label?: string
+  placeholder?: string
+  help?: string
+  input_type?: 'text' | 'textarea' | 'password' | 'number' | 'email' | 'url' | 'date' | 'select' | 'checkbox'
+  options?: string[]          // select 的可选值
+  pattern?: string            // 校验正则（整体匹配）
+  pattern_message?: string    // 校验失败时的提示
+}
+
 export interface Script {
   id: string
   name: string
@@ -321,6 +332,7 @@ export interface Script {
   mcp_command_name?: string
   mcp_command_description?: string
   mcp_input_schema?: Record<string, any>
+  param_ui_schema?: Record<string, ParamUIField>  // 参数表单元数据
   variables?: Record<string, string>  // 预设变量
 }
 
@@ -358,6 +370,9 @@ export interface ScriptExecution {
   failed_steps: number
   extracted_data?: Record<string, any>
   video_path?: string  // 录制视频路径
+  schema_error?: string  // 抓取数据不符合脚本 output_schema 时的校验错误
+  metrics?: { item_count: number; null_rate: number }  // 抓取指标
+  anomalies?: string[]  // 与历史执行相比的异常（条目数、空值比例）
   created_at: string
 }
 
@@ -512,6 +527,15 @@ export interface UpdateLLMConfigRequest {
   is_active?: boolean
 }
 
+// LLM 路由策略：把简单的步骤交给便宜的小模型
+export interface LLMRoutingPolicy {
+  cheap_config_id: string  // 小模型的 LLM 配置 ID，为空表示不路由
+  cheap_steps: string[]
+  updated_at?: string
+  embedding_config_id?: string  // 脚本推荐使用的 LLM 配置 ID，为空表示不推荐
+  embedding_model?: string
+}
+
 export interface TestLLMConfigRequest {
   name: string
   provider: string
@@ -736,6 +760,13 @@ export const api = {
   testLLMConfig: (data: TestLLMConfigRequest) =>
     client.post<{ success: boolean; message: string }>('/llm-configs/test', data),
 
+  // LLM 路由策略
+  getLLMRouting: () =>
+    client.get<{ policy: LLMRoutingPolicy; steps: string[] }>('/llm-routing'),
+
+  updateLLMRouting: (data: LLMRoutingPolicy) =>
+    client.put<{ message: string; policy: LLMRoutingPolicy }>('/llm-routing', data),
+
   // 浏览器配置管理
   getBrowserConfigs: () =>
     client.get<{ configs: BrowserConfig[]; count: number }>('/browser-configs'),
@@ -758,6 +789,7 @@ export const api = {
     mcp_command_name: string
     mcp_command_description: string
     mcp_input_schema?: Record<string, any>
+    param_ui_schema?: Record<string, ParamUIField>
   }) =>
     client.post<{ message: string; script: Script }>(`/scripts/${scriptId}/mcp`, data),
 
@@ -0,0 +1,116 @@
+import { useState } from 'react'
+import { ShieldCheck, ShieldAlert, ChevronDown, ChevronUp } from 'lucide-react'
+import { useLanguage } from '../i18n'
+
+// 需要人工批准的工具调用类别，与后端 models.ApprovalCategories 一致
+export const APPROVAL_CATEGORIES = ['navigation', 'form_submit', 'download', 'click'] as const
+
+export interface PendingApproval {
+  id: string
+  session_id: string
+  category: string
+  tool_name: string
+  arguments?: Record<string, any>
+  reason: string
+  created_at: string
+  expires_at: string
+}
+
+interface AgentApprovalPanelProps {
+  categories: string[]
+  pending: PendingApproval[]
+  onCategoriesChange: (categories: string[]) => void
+  onResolve: (approvalId: string, approved: boolean) => void
+  verifyActions: boolean
+  onVerifyActionsChange: (enabled: boolean) => void
+}
+
+// 会话的工具调用批准、操作校验设置和等待批准的调用
+export default function AgentApprovalPanel({ categories, pending, onCategoriesChange, onResolve, verifyActions, onVerifyActionsChange }: AgentApprovalPanelProps) {
+  const { t } = useLanguage()
+  const [showSettings, setShowSettings] = useState(false)
+
+  const toggleCategory = (category: string) => {
+    if (categories.includes(category)) {
+      onCategoriesChange(categories.filter(c => c !== category))
+    } else {
+      onCategoriesChange([...categories, category])
+    }
+  }
+
+  return (
+    <div className="mb-2 space-y-2">
+      {pending.map(approval => (
+        <div
+          key={approval.id}
+          className="flex items-start gap-3 px-4 py-3 rounded-xl border border-amber-300 dark:border-amber-700 bg-amber-50 dark:bg-amber-900/20"
+        >
+          <ShieldAlert className="w-5 h-5 mt-0.5 flex-shrink-0 text-amber-600 dark:text-amber-400" />
+          <div className="flex-1 min-w-0">
+            <div className="text-sm font-medium text-amber-900 dark:text-amber-200">
+              {t('agentChat.approval.required', { category: t(`agentChat.approval.category.${approval.category}`) })}
+            </div>
+            <div className="text-sm text-amber-800 dark:text-amber-300 break-all">{approval.reason}</div>
+            {approval.arguments && Object.keys(approval.arguments).length > 0 && (
+              <pre className="mt-1 text-xs text-amber-700 dark:text-amber-400 whitespace-pre-wrap break-all">
+                {approval.tool_name} {JSON.stringify(approval.arguments)}
+              </pre>
+            )}
+          </div>
+          <div className="flex gap-2 flex-shrink-0">
+            <button
+              onClick={() => onResolve(approval.id, false)}
+              className="px-3 py-1 text-sm border border-gray-300 dark:border-gray-600 text-gray-700 dark:text-gray-300 rounded-lg hover:bg-gray-50 dark:hover:bg-gray-700"
+            >
+              {t('agentChat.approval.deny')}
+            </button>
+            <button
+              onClick={() => onResolve(approval.id, true)}
+              className="px-3 py-1 text-sm bg-gray-900 dark:bg-gray-700 text-white rounded-lg hover:bg-gray-800 dark:hover:bg-gray-600"
+            >
+              {t('agentChat.approval.approve')}
+            </button>
+          </div>
+        </div>
+      ))}
+
+      <div>
+        <button
+          onClick={() => setShowSettings(!showSettings)}
+          className="flex items-center gap-1 text-xs text-gray-500 dark:text-gray-400 hover:text-gray-700 dark:hover:text-gray-200"
+        >
+          <ShieldCheck className="w-3.5 h-3.5" />
+          {categories.length > 0
+            ? t('agentChat.approval.enabled', { count: categories.length })
+            : t('agentChat.approval.disabled')}
+          {showSettings ? <ChevronUp className="w-3.5 h-3.5" /> : <ChevronDown className="w-3.5 h-3.5" />}
+        </button>
+        {showSettings && (
+          <div className="mt-2 flex flex-wrap gap-x-4 gap-y-1">
+            {APPROVAL_CATEGORIES.map(category => (
+              <label key={category} className="flex items-center gap-1.5 text-xs text-gray-700 dark:text-gray-300 cursor-pointer">
+                <input
+                  type="checkbox"
+                  checked={categories.includes(category)}
+                  onChange={() => toggleCategory(category)}
+                />
+                {t(`agentChat.approval.category.${category}`)}
+              </label>
+            ))}
+            <label
+              className="flex items-center gap-1.5 text-xs text-gray-700 dark:text-gray-300 cursor-pointer"
+              title={t('agentChat.verification.desc')}
+            >
+              <input
+                type="checkbox"
+                checked={verifyActions}
+                onChange={(e) => onVerifyActionsChange(e.target.checked)}
+              />
+              {t('agentChat.verification.label')}
+            </label>
+          </div>
+        )}
+      </div>
+    </div>
+  )
+}
@@ -1,6 +1,7 @@
 import { useState, useEffect } from 'react'
 import { X } from 'lucide-react'
 import { useLanguage } from '../i18n'
+import type { ParamUIField } from '../api/client'
 
 interface ScriptParamsDialogProps {
   isOpen: boolean
@@ -8,6 +9,19 @@ interface ScriptParamsDialogProps {
   onConfirm: (params: Record<string, string>) => void
   onCancel: () => void
   scriptName?: string
+  uiSchema?: Record<string, ParamUIField>  // 参数表单元数据
+}
+
+const inputClassName = 'w-full px-4 py-2.5 text-base border border-gray-300 dark:border-gray-600 rounded-lg focus:outline-none focus:ring-2 focus:ring-gray-900 dark:focus:ring-gray-500 focus:border-transparent bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100 placeholder-gray-400 dark:placeholder-gray-500 transition-colors'
+
+// 按参数的校验正则检查取值（与后端一致：整体匹配，空值不校验）
+const validateParam = (field: ParamUIField | undefined, value: string): boolean => {
+  if (!field?.pattern || value === '') return true
+  try {
+    return new RegExp(`^(?:${field.pattern})$`).test(value)
+  } catch {
+    return true
+  }
 }
 
 export default function ScriptParamsDialog({
@@ -15,27 +29,35 @@ export default function ScriptParamsDialog({
   parameters,
   onConfirm,
   onCancel,
-  scriptName
+  scriptName,
+  uiSchema
 }: ScriptParamsDialogProps) {
   const { t } = useLanguage()
   const [paramValues, setParamValues] = useState<Record<string, string>>({})
+  const [paramErrors, setParamErrors] = useState<Record<string, string>>({})
 
   // 初始化参数值
   useEffect(() => {
     if (isOpen && parameters.length > 0) {
       const initialValues: Record<string, string> = {}
       parameters.forEach(param => {
-        initialValues[param] = ''
+        initialValues[param] = uiSchema?.[param]?.input_type === 'checkbox' ? 'false' : ''
       })
       setParamValues(initialValues)
+      setParamErrors({})
     }
-  }, [isOpen, parameters])
+  }, [isOpen, parameters, uiSchema])
 
   const handleInputChange = (paramName: string, value: string) => {
     setParamValues(prev => ({
       ...prev,
       [paramName]: value
     }))
+    setParamErrors(prev => {
+      const next = { ...prev }
+      delete next[paramName]
+      return next
+    })
   }
 
   const handleSubmit = (e: React.FormEvent) => {
@@ -48,9 +70,75 @@ export default function ScriptParamsDialog({
     //   return
     // }
 
+    const errors: Record<string, string> = {}
+    parameters.forEach(param => {
+      const field = uiSchema?.[param]
+      if (!validateParam(field, paramValues[param] || '')) {
+        errors[param] = field?.pattern_message || `${t('script.params.patternMismatch')} ${field?.pattern}`
+      }
+    })
+    if (Object.keys(errors).length > 0) {
+      setParamErrors(errors)
+      return
+    }
+
     onConfirm(paramValues)
   }
 
+  const renderInput = (param: string) => {
+    const field = uiSchema?.[param]
+    const value = paramValues[param] || ''
+    const placeholder = field?.placeholder || `${t('script.params.enter') || '请输入'} ${field?.label || param}`
+
+    switch (field?.input_type) {
+      case 'textarea':
+        return (
+          <textarea
+            value={value}
+            onChange={(e) => handleInputChange(param, e.target.value)}
+            placeholder={placeholder}
+            rows={4}
+            className={inputClassName}
+          />
+        )
+      case 'select':
+        return (
+          <select
+            value={value}
+            onChange={(e) => handleInputChange(param, e.target.value)}
+            className={inputClassName}
+          >
+            <option value="">{placeholder}</option>
+            {(field?.options || []).map(option => (
+              <option key={option} value={option}>{option}</option>
+            ))}
+          </select>
+        )
+      case 'checkbox':
+        return (
+          <label className="inline-flex items-center gap-2 text-base text-gray-700 dark:text-gray-300">
+            <input
+              type="checkbox"
+              checked={value === 'true'}
+              onChange={(e) => handleInputChange(param, e.target.checked ? 'true' : 'false')}
+              className="w-4 h-4 rounded border-gray-300 dark:border-gray-600"
+            />
+            {field?.placeholder || field?.label || param}
+          </label>
+        )
+      default:
+        return (
+          <input
+            type={field?.input_type || 'text'}
+            value={value}
+            onChange={(e) => handleInputChange(param, e.target.value)}
+            placeholder={placeholder}
+            className={inputClassName}
+          />
+        )
+    }
+  }
+
   if (!isOpen) return null
 
   return (
@@ -92,18 +180,15 @@ export default function ScriptParamsDialog({
               {parameters.map((param, index) => (
                 <div key={index}>
                   <label className="block text-base font-medium text-gray-900 dark:text-gray-100 mb-2">
-                    {param}
+                    {uiSchema?.[param]?.label || param}
                   </label>
-                  <input
-                    type="text"
-                    value={paramValues[param] || ''}
-                    onChange={(e) => handleInputChange(param, e.target.value)}
-                    placeholder={`${t('script.params.enter') || '请输入'} ${param}`}
-                    className="w-full px-4 py-2.5 text-base border border-gray-300 dark:border-gray-600 rounded-lg 
-                             focus:outline-none focus:ring-2 focus:ring-gray-900 dark:focus:ring-gray-500 focus:border-transparent
-                             bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100 placeholder-gray-400 dark:placeholder-gray-500
-                             transition-colors"
-                  />
+                  {renderInput(param)}
+                  {uiSchema?.[param]?.help && (
+                    <p className="mt-1.5 text-sm text-gray-500 dark:text-gray-400">{uiSchema[param].help}</p>
+                  )}
+                  {paramErrors[param] && (
+                    <p className="mt-1.5 text-sm text-red-600 dark:text-red-400">{paramErrors[param]}</p>
+                  )}
                 </div>
               ))}
             </div>
@@ -61,6 +61,8 @@ export const translations = {
     'error.updateScriptFailed': '更新脚本失败',
     'error.playScriptFailed': '脚本播放失败',
     'error.getLLMConfigsFailed': '获取LLM配置失败',
+    'error.getLLMRoutingFailed': '获取LLM路由策略失败',
+    'error.invalidLLMRouting': 'LLM路由策略无效',
     'error.llmConfigNotFound': 'LLM配置未找到',
     'error.llmConfigRequiredFields': '名称、提供商和模型是必填的',
     'error.createLLMConfigFailed': '创建LLM配置失败',
@@ -90,6 +92,24 @@ export const translations = {
     'error.deletePromptFailed': '删除提示词失败',
     'error.getExecutionRecordsFailed': '获取执行记录失败',
     'error.executionRecordNotFound': '执行记录未找到',
+    'error.fileNotFound': '文件未找到',
+    'error.uploadTooLarge': '上传文件超过大小上限',
+    'error.uploadFailed': '上传文件失败',
+    'error.getScriptStateFailed': '获取增量抓取状态失败',
+    'error.saveScriptStateFailed': '保存增量抓取状态失败',
+    'error.getScriptMetricsFailed': '获取抓取指标失败',
+    'error.getScriptHealthFailed': '获取选择器健康报告失败',
+    'error.scriptHealthNotFound': '脚本还没有选择器健康报告',
+    'error.checkScriptHealthFailed': '检查脚本选择器失败',
+    'error.executionsFromDifferentScripts': '两次执行不属于同一个脚本',
+    'error.invalidTranscript': '会话记录格式无效',
+    'error.invalidTranscriptFormat': '导出格式只能是 json 或 markdown',
+    'error.createSessionFailed': '创建会话失败',
+    'error.invalidApprovalCategories': '无效的批准类别',
+    'error.approvalNotFound': '批准请求不存在或已失效',
+    'error.noBaseExecution': '没有可对比的更早的成功执行',
+    'error.compareExecutionsFailed': '对比执行记录失败',
+    'error.listUploadsFailed': '获取上传文件列表失败',
     'error.deleteExecutionRecordFailed': '删除执行记录失败',
     'error.selectExecutionRecords': '请选择要删除的执行记录',
     'error.taskNameRequired': '任务名称不能为空',
@@ -150,6 +170,7 @@ export const translations = {
     'success.llmConfigCreated': 'LLM配置已创建',
     'success.llmConfigUpdated': 'LLM配置已更新',
     'success.llmConfigDeleted': 'LLM配置已删除',
+    'success.llmRoutingSaved': 'LLM路由策略已保存',
     'success.taskCreated': '定时任务已创建',
     'success.taskUpdated': '定时任务已更新',
     'success.taskDeleted': '定时任务已删除',
@@ -162,11 +183,17 @@ export const translations = {
     'success.mcpCommandSet': '设置为MCP命令',
     'success.scriptSaved': '脚本已保存',
     'success.executionRecordDeleted': '执行记录已删除',
+    'success.uploadDeleted': '上传文件已删除',
+    'success.scriptStateReset': '增量抓取状态已清空',
     'success.recordingConfigUpdated': '录制配置已更新',
     // Agent相关
     'agent.sessionDeleted': '会话已删除',
     'agent.llmConfigSet': 'LLM配置已设置',
     'agent.llmConfigReloaded': 'LLM配置已重新加载',
+    'agent.approvalSettingsSaved': '批准设置已保存',
+    'agent.verificationSettingsSaved': '操作校验设置已保存',
+    'agent.toolCallApproved': '已批准工具调用',
+    'agent.toolCallDenied': '已拒绝工具调用',
 
     // 提示词
     'prompt.addNew': '新增提示词',
@@ -369,6 +396,7 @@ export const translations = {
     'script.params.enter': '请输入',
     'script.params.execute': '执行',
     'script.params.fillAllRequired': '请填写所有必需参数',
+    'script.params.patternMismatch': '不符合格式',
     'script.recordingConfig.title': '录制配置',
     'script.recordingConfig.enabled': '启用录制',
     'script.recordingConfig.enabledDesc': '回放脚本时自动录制为视频',
@@ -455,6 +483,9 @@ export const translations = {
     'script.mcp.commandDescription': 'MCP 命令描述',
     'script.mcp.commandDescriptionPlaceholder': '描述这个命令的功能和用途...',
     'script.mcp.inputSchemaHint': '💡 系统已自动根据脚本中的变量生成参数定义，您可以根据需要修改',
+    'script.mcp.paramUISchema': '参数表单（UI Schema）',
+    'script.mcp.paramUISchemaPlaceholder': '留空表示使用默认表单。示例:\n{\n  "email": {\n    "label": "邮箱",\n    "input_type": "email",\n    "placeholder": "name@example.com"\n  },\n  "code": {\n    "pattern": "[0-9]{6}",\n    "pattern_message": "请输入 6 位数字"\n  }\n}',
+    'script.mcp.paramUISchemaHint': '为每个参数设置标签、占位符、输入类型（text、textarea、password、number、email、url、date、select、checkbox）和校验正则，Web 执行表单和 MCP 工具参数都会使用',
     'script.mcp.inputSchemaPlaceholder': '留空表示无参数。示例 JSON Schema:\n{\n  "type": "object",\n  "properties": {\n    "username": {\n      "type": "string",\n      "description": "用户名"\n    },\n    "password": {\n      "type": "string",\n      "description": "密码"\n    }\n  },\n  "required": ["username"]\n}',
     'script.mcp.tipsTitle': '提示',
     'script.mcp.tip1': 'MCP 命令可以被外部 MCP 客户端调用',
@@ -615,6 +646,8 @@ export const translations = {
     'execution.details.endTime': '结束时间',
     'execution.details.message': '消息',
     'execution.details.errorInfo': '错误信息',
+    'execution.details.schemaError': '数据结构与 Schema 不一致',
+    'execution.details.anomalies': '抓取结果异常',
     'execution.details.extractedData': '抓取数据',
     'execution.details.executionVideo': '执行记录',
     'execution.deleteConfirm.title': '删除执行记录',
@@ -665,6 +698,20 @@ export const translations = {
     'llm.groupLocal': '本地模型',
     'llm.title': '大模型管理',
     'llm.subtitle': '支持热重载，管理和配置多个LLM模型',
+    'llm.routing.title': '模型路由',
+    'llm.routing.description': '把简单的步骤交给便宜的小模型，其余步骤使用默认模型或会话选择的模型',
+    'llm.routing.cheapModel': '小模型',
+    'llm.routing.off': '不路由（全部使用默认模型）',
+    'llm.routing.steps': '使用小模型的步骤',
+    'llm.routing.stepsHint': '不勾选任何步骤时，所有步骤都使用小模型',
+    'llm.routing.step.task_evaluation': '任务评估',
+    'llm.routing.step.action_verification': '操作校验',
+    'llm.routing.step.translation': '页面翻译',
+    'llm.routing.step.mcp_info': '生成 MCP 命令信息',
+    'llm.routing.scriptRecommend': '脚本推荐',
+    'llm.routing.scriptRecommendDesc': 'Agent 收到任务时，用嵌入模型按名称、描述和 URL 找出相似的脚本工具，并提示 Agent 优先调用脚本',
+    'llm.routing.scriptRecommendOff': '不推荐脚本',
+    'llm.routing.embeddingModelPlaceholder': '嵌入模型，如 text-embedding-3-small',
     'llm.nameRequired': '名称',
     'llm.namePlaceholder': '例如: gpt-4',
     'llm.provider': '提供商',
@@ -745,6 +792,33 @@ export const translations = {
     'agentChat.sessionDeleted': '会话已删除',
     'agentChat.sessionCreated': '新会话已创建',
     'agentChat.deleteSessionFailed': '删除会话失败',
+    'agentChat.exportSessionFailed': '导出会话失败',
+    'agentChat.exportTranscript': '导出会话记录（JSON，可重放）',
+    'agentChat.exportMarkdown': '导出为 Markdown',
+    'agentChat.readOnly': '只读',
+    'agentChat.readOnlyDesc': 'Agent 只能打开和读取页面（导航、快照、抓取、截图），不能点击、输入或提交。适合在已登录的敏感账号上做调研',
+    'agentChat.systemPrompt': '系统提示词',
+    'agentChat.defaultSystemPrompt': '默认 Agent 提示词',
+    'agentChat.systemPromptDesc': '在提示词管理中保存购物、调研、填表等场景的提示词，在这里选择作为会话的系统提示词。会话开始后不能更换',
+    'agentChat.approval.required': '工具调用需要批准：{category}',
+    'agentChat.approval.approve': '批准',
+    'agentChat.approval.deny': '拒绝',
+    'agentChat.approval.enabled': '工具调用批准：{count} 类',
+    'agentChat.approval.disabled': '工具调用批准：未开启',
+    'agentChat.approval.category.navigation': '打开新域名',
+    'agentChat.approval.category.form_submit': '提交表单',
+    'agentChat.approval.category.download': '下载文件',
+    'agentChat.approval.category.click': '所有点击',
+    'agentChat.approval.category.script': '执行脚本',
+    'agentChat.approval.saveFailed': '保存批准设置失败',
+    'agentChat.verification.label': '操作校验',
+    'agentChat.verification.desc': '点击、输入等改变页面的操作后截图，并让 LLM 确认预期的变化已发生，未发生时 Agent 会检查页面后重试',
+    'agentChat.verification.verified': '已校验',
+    'agentChat.verification.failed': '校验未通过',
+    'agentChat.verification.skipped': '未能校验',
+    'agentChat.verification.screenshot': '操作后截图',
+    'agentChat.verification.saveFailed': '保存操作校验设置失败',
+    'agentChat.approval.resolveFailed': '处理批准请求失败',
     'agentChat.createSessionFailed': '创建会话失败',
     'agentChat.thinking': '正在思考中',
     'agentChat.stopGeneration': '停止生成',
@@ -1262,6 +1336,8 @@ export const translations = {
     'error.updateScriptFailed': '更新腳本失敗',
     'error.playScriptFailed': '腳本播放失敗',
     'error.getLLMConfigsFailed': '取得LLM設定失敗',
+    'error.getLLMRoutingFailed': '取得LLM路由策略失敗',
+    'error.invalidLLMRouting': 'LLM路由策略無效',
     'error.llmConfigNotFound': 'LLM設定未找到',
     'error.llmConfigRequiredFields': '名稱、提供商和模型是必填的',
     'error.createLLMConfigFailed': '建立LLM設定失敗',
@@ -1286,6 +1362,24 @@ export const translations = {
     'error.deletePromptFailed': '刪除提示詞失敗',
     'error.getExecutionRecordsFailed': '取得執行記錄失敗',
     'error.executionRecordNotFound': '執行記錄未找到',
+    'error.fileNotFound': '檔案未找到',
+    'error.uploadTooLarge': '上傳檔案超過大小上限',
+    'error.uploadFailed': '上傳檔案失敗',
+    'error.getScriptStateFailed': '取得增量抓取狀態失敗',
+    'error.saveScriptStateFailed': '儲存增量抓取狀態失敗',
+    'error.getScriptMetricsFailed': '取得抓取指標失敗',
+    'error.getScriptHealthFailed': '取得選擇器健康報告失敗',
+    'error.scriptHealthNotFound': '腳本還沒有選擇器健康報告',
+    'error.checkScriptHealthFailed': '檢查腳本選擇器失敗',
+    'error.executionsFromDifferentScripts': '兩次執行不屬於同一個腳本',
+    'error.invalidTranscript': '會話記錄格式無效',
+    'error.invalidTranscriptFormat': '匯出格式只能是 json 或 markdown',
+    'error.createSessionFailed': '建立會話失敗',
+    'error.invalidApprovalCategories': '無效的批准類別',
+    'error.approvalNotFound': '批准請求不存在或已失效',
+    'error.noBaseExecution': '沒有可對比的更早的成功執行',
+    'error.compareExecutionsFailed': '比較執行記錄失敗',
+    'error.listUploadsFailed': '取得上傳檔案列表失敗',
     'error.deleteExecutionRecordFailed': '刪除執行記錄失敗',
     'error.selectExecutionRecords': '請選擇要刪除的執行記錄',
     'error.taskNameRequired': '任務名稱不能為空',
@@ -1346,6 +1440,7 @@ export const translations = {
     'success.llmConfigCreated': 'LLM設定已建立',
     'success.llmConfigUpdated': 'LLM設定已更新',
     'success.llmConfigDeleted': 'LLM設定已刪除',
+    'success.llmRoutingSaved': 'LLM路由策略已儲存',
     'success.taskCreated': '定時任務已建立',
     'success.taskUpdated': '定時任務已更新',
     'success.taskDeleted': '定時任務已刪除',
@@ -1356,6 +1451,8 @@ export const translations = {
     'success.recordingStopped': '錄製已停止',
     'success.scriptSaved': '腳本已儲存',
     'success.executionRecordDeleted': '執行記錄已刪除',
+    'success.uploadDeleted': '上傳檔案已刪除',
+    'success.scriptStateReset': '增量抓取狀態已清空',
     'success.recordingConfigUpdated': '錄製設定已更新',
 
     // 導航
@@ -1435,6 +1532,33 @@ export const translations = {
     'agentChat.sessionDeleted': '會話已刪除',
     'agentChat.sessionCreated': '新會話已建立',
     'agentChat.deleteSessionFailed': '刪除會話失敗',
+    'agentChat.exportSessionFailed': '匯出會話失敗',
+    'agentChat.exportTranscript': '匯出會話記錄（JSON，可重放）',
+    'agentChat.exportMarkdown': '匯出為 Markdown',
+    'agentChat.readOnly': '唯讀',
+    'agentChat.readOnlyDesc': 'Agent 只能開啟和讀取頁面（導覽、快照、擷取、截圖），不能點擊、輸入或提交。適合在已登入的敏感帳號上做調研',
+    'agentChat.systemPrompt': '系統提示詞',
+    'agentChat.defaultSystemPrompt': '預設 Agent 提示詞',
+    'agentChat.systemPromptDesc': '在提示詞管理中儲存購物、調研、填表等場景的提示詞，在這裡選擇作為會話的系統提示詞。會話開始後不能更換',
+    'agentChat.approval.required': '工具呼叫需要批准：{category}',
+    'agentChat.approval.approve': '批准',
+    'agentChat.approval.deny': '拒絕',
+    'agentChat.approval.enabled': '工具呼叫批准：{count} 類',
+    'agentChat.approval.disabled': '工具呼叫批准：未開啟',
+    'agentChat.approval.category.navigation': '開啟新網域',
+    'agentChat.approval.category.form_submit': '提交表單',
+    'agentChat.approval.category.download': '下載檔案',
+    'agentChat.approval.category.click': '所有點擊',
+    'agentChat.approval.category.script': '執行腳本',
+    'agentChat.approval.saveFailed': '儲存批准設定失敗',
+    'agentChat.verification.label': '操作校驗',
+    'agentChat.verification.desc': '點擊、輸入等改變頁面的操作後截圖，並讓 LLM 確認預期的變化已發生，未發生時 Agent 會檢查頁面後重試',
+    'agentChat.verification.verified': '已校驗',
+    'agentChat.verification.failed': '校驗未通過',
+    'agentChat.verification.skipped': '未能校驗',
+    'agentChat.verification.screenshot': '操作後截圖',
+    'agentChat.verification.saveFailed': '儲存操作校驗設定失敗',
+    'agentChat.approval.resolveFailed': '處理批准請求失敗',
     'agentChat.createSessionFailed': '建立會話失敗',
     'agentChat.thinking': '正在思考中',
     'agentChat.stopGeneration': '停止生成',
@@ -1665,6 +1789,9 @@ export const translations = {
     'script.mcp.commandDescription': 'MCP 命令描述',
     'script.mcp.commandDescriptionPlaceholder': '描述這個命令的功能和用途...',
     'script.mcp.inputSchemaHint': '💡 系統已自動根據腳本中的變量生成參數定義，您可以根據需要修改',
+    'script.mcp.paramUISchema': '參數表單（UI Schema）',
+    'script.mcp.paramUISchemaPlaceholder': '留空表示使用預設表單。示例:\n{\n  "email": {\n    "label": "郵箱",\n    "input_type": "email",\n    "placeholder": "name@example.com"\n  },\n  "code": {\n    "pattern": "[0-9]{6}",\n    "pattern_message": "請輸入 6 位數字"\n  }\n}',
+    'script.mcp.paramUISchemaHint': '為每個參數設定標籤、佔位符、輸入類型（text、textarea、password、number、email、url、date、select、checkbox）和校驗正則，Web 執行表單和 MCP 工具參數都會使用',
     'script.mcp.inputSchemaPlaceholder': '留空表示無參數。示例 JSON Schema:\n{\n  "type": "object",\n  "properties": {\n    "username": {\n      "type": "string",\n      "description": "用戶名"\n    },\n    "password": {\n      "type": "string",\n      "description": "密碼"\n    }\n  },\n  "required": ["username"]\n}',
     'script.mcp.tipsTitle': '提示',
     'script.mcp.tip1': 'MCP 命令可以被外部 MCP 客戶端調用',
@@ -1844,6 +1971,8 @@ export const translations = {
     'execution.details.endTime': '結束時間',
     'execution.details.message': '消息',
     'execution.details.errorInfo': '錯誤信息',
+    'execution.details.schemaError': '資料結構與 Schema 不一致',
+    'execution.details.anomalies': '抓取結果異常',
     'execution.details.extractedData': '抓取數據',
     'execution.details.executionVideo': '執行視頻',
     'execution.deleteConfirm.title': '刪除執行記錄',
@@ -1865,6 +1994,20 @@ export const translations = {
     // LLM 管理
     'llm.title': '大模型管理',
     'llm.subtitle': '管理和配置多個 LLM 模型,支援熱載入切換',
+    'llm.routing.title': '模型路由',
+    'llm.routing.description': '把簡單的步驟交給便宜的小模型，其餘步驟使用預設模型或會話選擇的模型',
+    'llm.routing.cheapModel': '小模型',
+    'llm.routing.off': '不路由（全部使用預設模型）',
+    'llm.routing.steps': '使用小模型的步驟',
+    'llm.routing.stepsHint': '未勾選任何步驟時，所有步驟都使用小模型',
+    'llm.routing.step.task_evaluation': '任務評估',
+    'llm.routing.step.action_verification': '操作校驗',
+    'llm.routing.step.translation': '頁面翻譯',
+    'llm.routing.step.mcp_info': '產生 MCP 命令資訊',
+    'llm.routing.scriptRecommend': '腳本推薦',
+    'llm.routing.scriptRecommendDesc': 'Agent 收到任務時，用嵌入模型依名稱、描述和 URL 找出相似的腳本工具，並提示 Agent 優先呼叫腳本',
+    'llm.routing.scriptRecommendOff': '不推薦腳本',
+    'llm.routing.embeddingModelPlaceholder': '嵌入模型，如 text-embedding-3-small',
     'llm.addConfig': '新增配置',
     'llm.addConfigTitle': '新增 LLM 配置',
     'llm.loading': '載入中...',
@@ -2283,6 +2426,10 @@ export const translations = {
     'agent.sessionDeleted': '會話已刪除',
     'agent.llmConfigSet': 'LLM配置已設置',
     'agent.llmConfigReloaded': 'LLM 設定已重新載入',
+    'agent.approvalSettingsSaved': '批准設定已儲存',
+    'agent.verificationSettingsSaved': '操作校驗設定已儲存',
+    'agent.toolCallApproved': '已批准工具呼叫',
+    'agent.toolCallDenied': '已拒絕工具呼叫',
 
     // 參數對話框
     'script.params.title': '填寫腳本參數',
@@ -2291,6 +2438,7 @@ export const translations = {
     'script.params.enter': '請輸入',
     'script.params.execute': '執行',
     'script.params.fillAllRequired': '請填寫所有必填參數',
+    'script.params.patternMismatch': '不符合格式',
 
     'llm.description': '描述',
     'llm.selectProvider': '選擇提供商',
@@ -2489,6 +2637,8 @@ export const translations = {
     'error.updateScriptFailed': 'Failed to update script',
     'error.playScriptFailed': 'Failed to play script',
     'error.getLLMConfigsFailed': 'Failed to get LLM configs',
+    'error.getLLMRoutingFailed': 'Failed to get LLM routing policy',
+    'error.invalidLLMRouting': 'Invalid LLM routing policy',
     'error.llmConfigNotFound': 'LLM config not found',
     'error.llmConfigRequiredFields': 'Name, provider, and model are required',
     'error.createLLMConfigFailed': 'Failed to create LLM config',
@@ -2518,6 +2668,24 @@ export const translations = {
     'error.deletePromptFailed': 'Failed to delete prompt',
     'error.getExecutionRecordsFailed': 'Failed to get execution records',
     'error.executionRecordNotFound': 'Execution record not found',
+    'error.fileNotFound': 'File not found',
+    'error.uploadTooLarge': 'Uploaded file exceeds the size limit',
+    'error.uploadFailed': 'Failed to upload file',
+    'error.getScriptStateFailed': 'Failed to get incremental scraping state',
+    'error.saveScriptStateFailed': 'Failed to save incremental scraping state',
+    'error.getScriptMetricsFailed': 'Failed to get extraction metrics',
+    'error.getScriptHealthFailed': 'Failed to get selector health reports',
+    'error.scriptHealthNotFound': 'The script has no selector health report yet',
+    'error.checkScriptHealthFailed': 'Failed to check script selectors',
+    'error.executionsFromDifferentScripts': 'The executions belong to different scripts',
+    'error.invalidTranscript': 'Invalid session transcript',
+    'error.invalidTranscriptFormat': 'Export format must be json or markdown',
+    'error.createSessionFailed': 'Failed to create session',
+    'error.invalidApprovalCategories': 'Invalid approval category',
+    'error.approvalNotFound': 'The approval request does not exist or has expired',
+    'error.noBaseExecution': 'No earlier successful execution to compare with',
+    'error.compareExecutionsFailed': 'Failed to compare executions',
+    'error.listUploadsFailed': 'Failed to list uploaded files',
     'error.deleteExecutionRecordFailed': 'Failed to delete execution record',
     'error.selectExecutionRecords': 'Please select execution records to delete',
     'error.taskNameRequired': 'Task name is required',
@@ -2578,6 +2746,7 @@ export const translations = {
     'success.llmConfigCreated': 'LLM config created',
     'success.llmConfigUpdated': 'LLM config updated',
     'success.llmConfigDeleted': 'LLM config deleted',
+    'success.llmRoutingSaved': 'LLM routing policy saved',
     'success.taskCreated': 'Task created successfully',
     'success.taskUpdated': 'Task updated successfully',
     'success.taskDeleted': 'Task deleted successfully',
@@ -2588,6 +2757,8 @@ export const translations = {
     'success.recordingStopped': 'Recording stopped',
     'success.scriptSaved': 'Script saved',
     'success.executionRecordDeleted': 'Execution record deleted',
+    'success.uploadDeleted': 'Uploaded file deleted',
+    'success.scriptStateReset': 'Incremental scraping state reset',
     'success.recordingConfigUpdated': 'Recording config updated',
 
     'success.mcpCommandDisabled': 'Disabled MCP command',
@@ -2860,6 +3031,9 @@ export const translations = {
     'script.mcp.commandDescription': 'MCP Command Description',
     'script.mcp.commandDescriptionPlaceholder': 'Describe the function and purpose of this command...',
     'script.mcp.inputSchemaHint': '💡 System has automatically generated parameter definitions based on script variables, you can modify as needed',
+    'script.mcp.paramUISchema': 'Parameter Form (UI Schema)',
+    'script.mcp.paramUISchemaPlaceholder': 'Leave empty to use the default form. Example:\n{\n  "email": {\n    "label": "Email",\n    "input_type": "email",\n    "placeholder": "name@example.com"\n  },\n  "code": {\n    "pattern": "[0-9]{6}",\n    "pattern_message": "Enter 6 digits"\n  }\n}',
+    'script.mcp.paramUISchemaHint': 'Set a label, placeholder, input type (text, textarea, password, number, email, url, date, select, checkbox) and validation regex for each parameter. Used by the web run form and MCP tool arguments',
     'script.mcp.inputSchemaPlaceholder': 'Leave empty for no parameters. Example JSON Schema:\n{\n  "type": "object",\n  "properties": {\n    "username": {\n      "type": "string",\n      "description": "Username"\n    },\n    "password": {\n      "type": "string",\n      "description": "Password"\n    }\n  },\n  "required": ["username"]\n}',
     'script.mcp.tipsTitle': 'Tips',
     'script.mcp.tip1': 'MCP commands can be invoked by external MCP clients',
@@ -3024,6 +3198,8 @@ export const translations = {
     'execution.details.endTime': 'End Time',
     'execution.details.message': 'Message',
     'execution.details.errorInfo': 'Error Info',
+    'execution.details.schemaError': 'Data does not match the schema',
+    'execution.details.anomalies': 'Suspect result',
     'execution.details.extractedData': 'Extracted Data',
     'execution.details.executionVideo': 'Execution Recording',
     'execution.deleteConfirm.title': 'Delete Execution Record',
@@ -3045,6 +3221,20 @@ export const translations = {
     // LLM Management
     'llm.title': 'LLM Management',
     'llm.subtitle': 'Manage and configure multiple LLM models with hot-reload support',
+    'llm.routing.title': 'Model routing',
+    'llm.routing.description': 'Send simple steps to a cheap small model. Other steps use the default model or the model chosen for the session.',
+    'llm.routing.cheapModel': 'Small model',
+    'llm.routing.off': 'Off (use the default model for everything)',
+    'llm.routing.steps': 'Steps that use the small model',
+    'llm.routing.stepsHint': 'If no step is selected, all steps use the small model.',
+    'llm.routing.step.task_evaluation': 'Task evaluation',
+    'llm.routing.step.action_verification': 'Action verification',
+    'llm.routing.step.translation': 'Page translation',
+    'llm.routing.step.mcp_info': 'MCP command info generation',
+    'llm.routing.scriptRecommend': 'Script recommendation',
+    'llm.routing.scriptRecommendDesc': 'When the agent gets a task, an embedding model finds script tools with a similar name, description or URL. The agent is told to call those scripts first.',
+    'llm.routing.scriptRecommendOff': 'Off',
+    'llm.routing.embeddingModelPlaceholder': 'Embedding model, e.g. text-embedding-3-small',
     'llm.addConfig': 'Add Configuration',
     'llm.addConfigTitle': 'Add LLM Configuration',
     'llm.loading': 'Loading...',
@@ -3151,6 +3341,33 @@ export const translations = {
     'agentChat.sessionDeleted': 'Session deleted',
     'agentChat.sessionCreated': 'New session created',
     'agentChat.deleteSessionFailed': 'Failed to delete session',
+    'agentChat.exportSessionFailed': 'Failed to export session',
+    'agentChat.exportTranscript': 'Export transcript (JSON, replayable)',
+    'agentChat.exportMarkdown': 'Export as Markdown',
+    'agentChat.readOnly': 'Read-only',
+    'agentChat.readOnlyDesc': 'The agent can only open and read pages (navigate, snapshot, extract, screenshot). It can\'t click, type or submit. Use this for research on sensitive logged-in accounts',
+    'agentChat.systemPrompt': 'System prompt',
+    'agentChat.defaultSystemPrompt': 'Default agent prompt',
+    'agentChat.systemPromptDesc': 'Save prompts for tasks like shopping, research or form filling under Prompts, then pick one here as the session\'s system prompt. It can\'t be changed after the session starts',
+    'agentChat.approval.required': 'Tool call needs approval: {category}',
+    'agentChat.approval.approve': 'Approve',
+    'agentChat.approval.deny': 'Deny',
+    'agentChat.approval.enabled': 'Tool call approval: {count} categories',
+    'agentChat.approval.disabled': 'Tool call approval: off',
+    'agentChat.approval.category.navigation': 'New domains',
+    'agentChat.approval.category.form_submit': 'Form submission',
+    'agentChat.approval.category.download': 'File downloads',
+    'agentChat.approval.category.click': 'All clicks',
+    'agentChat.approval.category.script': 'Run scripts',
+    'agentChat.approval.saveFailed': 'Failed to save approval settings',
+    'agentChat.verification.label': 'Verify actions',
+    'agentChat.verification.desc': 'After each click, input or other page-changing action, take a screenshot and ask the LLM whether the expected change occurred. If it did not, the agent checks the page and retries',
+    'agentChat.verification.verified': 'Verified',
+    'agentChat.verification.failed': 'Verification failed',
+    'agentChat.verification.skipped': 'Not verified',
+    'agentChat.verification.screenshot': 'Screenshot after the action',
+    'agentChat.verification.saveFailed': 'Failed to save verification settings',
+    'agentChat.approval.resolveFailed': 'Failed to answer the approval request',
     'agentChat.createSessionFailed': 'Failed to create session',
     'agentChat.thinking': 'Thinking',
     'agentChat.stopGeneration': 'Stop Generation',
@@ -3588,6 +3805,10 @@ export const translations = {
     'agent.sessionDeleted': 'Session deleted',
     'agent.llmConfigSet': 'LLM configuration set',
     'agent.llmConfigReloaded': 'LLM configuration reloaded',
+    'agent.approvalSettingsSaved': 'Approval settings saved',
+    'agent.verificationSettingsSaved': 'Verification settings saved',
+    'agent.toolCallApproved': 'Tool call approved',
+    'agent.toolCallDenied': 'Tool call denied',
 
     // Parameter dialog
     'script.params.title': 'Fill Script Parameters',
@@ -3596,6 +3817,7 @@ export const translations = {
     'script.params.enter': 'Please enter',
     'script.params.execute': 'Execute',
     'script.params.fillAllRequired': 'Please fill in all required parameters',
+    'script.params.patternMismatch': 'Does not match pattern',
 
     'llm.description': 'Description',
     'llm.selectProvider': 'Select Provider',
@@ -3692,6 +3914,8 @@ export const translations = {
     'error.updateScriptFailed': 'Error al actualizar el script',
     'error.playScriptFailed': 'Error al reproducir el script',
     'error.getLLMConfigsFailed': 'Error al obtener configuraciones LLM',
+    'error.getLLMRoutingFailed': 'Error al obtener la política de enrutamiento de LLM',
+    'error.invalidLLMRouting': 'Política de enrutamiento de LLM no válida',
     'error.llmConfigNotFound': 'Configuración LLM no encontrada',
     'error.llmConfigRequiredFields': 'Nombre, proveedor y modelo son obligatorios',
     'error.createLLMConfigFailed': 'Error al crear configuración LLM',
@@ -3716,6 +3940,24 @@ export const translations = {
     'error.deletePromptFailed': 'Error al eliminar el prompt',
     'error.getExecutionRecordsFailed': 'Error al obtener registros de ejecución',
     'error.executionRecordNotFound': 'Registro de ejecución no encontrado',
+    'error.fileNotFound': 'Archivo no encontrado',
+    'error.uploadTooLarge': 'El archivo supera el tamaño máximo',
+    'error.uploadFailed': 'Error al subir el archivo',
+    'error.getScriptStateFailed': 'Error al obtener el estado de extracción incremental',
+    'error.saveScriptStateFailed': 'Error al guardar el estado de extracción incremental',
+    'error.getScriptMetricsFailed': 'Error al obtener las métricas de extracción',
+    'error.getScriptHealthFailed': 'Error al obtener los informes de estado de los selectores',
+    'error.scriptHealthNotFound': 'El script aún no tiene un informe de estado de los selectores',
+    'error.checkScriptHealthFailed': 'Error al comprobar los selectores del script',
+    'error.executionsFromDifferentScripts': 'Las ejecuciones pertenecen a scripts diferentes',
+    'error.invalidTranscript': 'Transcripción de sesión no válida',
+    'error.invalidTranscriptFormat': 'El formato de exportación debe ser json o markdown',
+    'error.createSessionFailed': 'Error al crear la sesión',
+    'error.invalidApprovalCategories': 'Categoría de aprobación no válida',
+    'error.approvalNotFound': 'La solicitud de aprobación no existe o ha caducado',
+    'error.noBaseExecution': 'No hay una ejecución exitosa anterior con la que comparar',
+    'error.compareExecutionsFailed': 'Error al comparar las ejecuciones',
+    'error.listUploadsFailed': 'Error al obtener los archivos subidos',
     'error.deleteExecutionRecordFailed': 'Error al eliminar el registro de ejecución',
     'error.selectExecutionRecords': 'Por favor, seleccione los registros de ejecución para eliminar',
     'error.taskNameRequired': 'El nombre de la tarea es obligatorio',
@@ -3776,6 +4018,7 @@ export const translations = {
     'success.llmConfigCreated': 'Configuración LLM creada',
     'success.llmConfigUpdated': 'Configuración LLM actualizada',
     'success.llmConfigDeleted': 'Configuración LLM eliminada',
+    'success.llmRoutingSaved': 'Política de enrutamiento de LLM guardada',
     'success.taskCreated': 'Tarea creada exitosamente',
     'success.taskUpdated': 'Tarea actualizada exitosamente',
     'success.taskDeleted': 'Tarea eliminada exitosamente',
@@ -3786,6 +4029,8 @@ export const translations = {
     'success.recordingStopped': 'Grabación detenida',
     'success.scriptSaved': 'Script guardado',
     'success.executionRecordDeleted': 'Registro de ejecución eliminado',
+    'success.uploadDeleted': 'Archivo subido eliminado',
+    'success.scriptStateReset': 'Estado de extracción incremental restablecido',
     'success.recordingConfigUpdated': 'Configuración de grabación actualizada',
 
     'success.mcpCommandDisabled': 'Comando MCP deshabilitado',
@@ -4070,6 +4315,9 @@ export const translations = {
     'script.mcp.commandDescription': 'Descripción del Comando MCP',
     'script.mcp.commandDescriptionPlaceholder': 'Describe la función y propósito de este comando...',
     'script.mcp.inputSchemaHint': '💡 El sistema ha generado automáticamente definiciones de parámetros basadas en variables del script, puede modificar según necesite',
+    'script.mcp.paramUISchema': 'Formulario de parámetros (UI Schema)',
+    'script.mcp.paramUISchemaPlaceholder': 'Dejar vacío para usar el formulario predeterminado. Ejemplo:\n{\n  "email": {\n    "label": "Correo",\n    "input_type": "email",\n    "placeholder": "name@example.com"\n  },\n  "code": {\n    "pattern": "[0-9]{6}",\n    "pattern_message": "Introduzca 6 dígitos"\n  }\n}',
+    'script.mcp.paramUISchemaHint': 'Defina etiqueta, marcador, tipo de entrada (text, textarea, password, number, email, url, date, select, checkbox) y expresión regular de validación para cada parámetro. Se usa en el formulario de ejecución web y en los argumentos de herramientas MCP',
     'script.mcp.inputSchemaPlaceholder': 'Dejar vacío para sin parámetros. Ejemplo JSON Schema:\n{\n  "type": "object",\n  "properties": {\n    "username": {\n      "type": "string",\n      "description": "Nombre de usuario"\n    },\n    "password": {\n      "type": "string",\n      "description": "Contraseña"\n    }\n  },\n  "required": ["username"]\n}',
     'script.mcp.tipsTitle': 'Consejos',
     'script.mcp.tip1': 'Los comandos MCP pueden ser invocados por clientes MCP externos',
@@ -4234,6 +4482,8 @@ export const translations = {
     'execution.details.endTime': 'Hora de Fin',
     'execution.details.message': 'Mensaje',
     'execution.details.errorInfo': 'Información de Error',
+    'execution.details.schemaError': 'Los datos no coinciden con el esquema',
+    'execution.details.anomalies': 'Resultado sospechoso',
     'execution.details.extractedData': 'Datos Extraídos',
     'execution.details.executionVideo': 'Video de Ejecución',
     'execution.deleteConfirm.title': 'Eliminar Registro de Ejecución',
@@ -4255,6 +4505,20 @@ export const translations = {
     // Gestión LLM
     'llm.title': 'Gestión de LLM',
     'llm.subtitle': 'Gestionar y configurar múltiples modelos LLM con soporte de recarga en caliente',
+    'llm.routing.title': 'Enrutamiento de modelos',
+    'llm.routing.description': 'Envía los pasos simples a un modelo pequeño y barato. Los demás pasos usan el modelo predeterminado o el elegido para la sesión.',
+    'llm.routing.cheapModel': 'Modelo pequeño',
+    'llm.routing.off': 'Desactivado (usar el modelo predeterminado para todo)',
+    'llm.routing.steps': 'Pasos que usan el modelo pequeño',
+    'llm.routing.stepsHint': 'Si no se selecciona ningún paso, todos los pasos usan el modelo pequeño.',
+    'llm.routing.step.task_evaluation': 'Evaluación de tareas',
+    'llm.routing.step.action_verification': 'Verificación de acciones',
+    'llm.routing.step.translation': 'Traducción de páginas',
+    'llm.routing.step.mcp_info': 'Generación de información de comandos MCP',
+    'llm.routing.scriptRecommend': 'Recomendación de scripts',
+    'llm.routing.scriptRecommendDesc': 'Cuando el agente recibe una tarea, un modelo de embeddings busca herramientas de script con nombre, descripción o URL similares. Se indica al agente que llame primero a esos scripts.',
+    'llm.routing.scriptRecommendOff': 'Desactivada',
+    'llm.routing.embeddingModelPlaceholder': 'Modelo de embeddings, p. ej. text-embedding-3-small',
     'llm.addConfig': 'Añadir Configuración',
     'llm.addConfigTitle': 'Añadir Configuración LLM',
     'llm.loading': 'Cargando...',
@@ -4360,6 +4624,33 @@ export const translations = {
     'agentChat.sessionDeleted': 'Sesión eliminada',
     'agentChat.sessionCreated': 'Nueva sesión creada',
     'agentChat.deleteSessionFailed': 'Error al eliminar sesión',
+    'agentChat.exportSessionFailed': 'Error al exportar la sesión',
+    'agentChat.exportTranscript': 'Exportar transcripción (JSON, reproducible)',
+    'agentChat.exportMarkdown': 'Exportar como Markdown',
+    'agentChat.readOnly': 'Solo lectura',
+    'agentChat.readOnlyDesc': 'El agente solo puede abrir y leer páginas (navegar, capturar, extraer, hacer capturas de pantalla). No puede hacer clic, escribir ni enviar. Útil para investigar en cuentas sensibles con sesión iniciada',
+    'agentChat.systemPrompt': 'Prompt de sistema',
+    'agentChat.defaultSystemPrompt': 'Prompt del agente predeterminado',
+    'agentChat.systemPromptDesc': 'Guarda prompts para tareas como compras, investigación o rellenar formularios en Prompts y elige uno aquí como prompt de sistema de la sesión. No se puede cambiar una vez iniciada la sesión',
+    'agentChat.approval.required': 'La llamada a herramienta requiere aprobación: {category}',
+    'agentChat.approval.approve': 'Aprobar',
+    'agentChat.approval.deny': 'Rechazar',
+    'agentChat.approval.enabled': 'Aprobación de herramientas: {count} categorías',
+    'agentChat.approval.disabled': 'Aprobación de herramientas: desactivada',
+    'agentChat.approval.category.navigation': 'Dominios nuevos',
+    'agentChat.approval.category.form_submit': 'Envío de formularios',
+    'agentChat.approval.category.download': 'Descarga de archivos',
+    'agentChat.approval.category.click': 'Todos los clics',
+    'agentChat.approval.category.script': 'Ejecutar scripts',
+    'agentChat.approval.saveFailed': 'Error al guardar la configuración de aprobación',
+    'agentChat.verification.label': 'Verificar acciones',
+    'agentChat.verification.desc': 'Tras cada clic, escritura u otra acción que cambie la página, toma una captura y pregunta al LLM si ocurrió el cambio esperado. Si no, el agente revisa la página y lo reintenta',
+    'agentChat.verification.verified': 'Verificado',
+    'agentChat.verification.failed': 'Verificación fallida',
+    'agentChat.verification.skipped': 'Sin verificar',
+    'agentChat.verification.screenshot': 'Captura tras la acción',
+    'agentChat.verification.saveFailed': 'Error al guardar la configuración de verificación',
+    'agentChat.approval.resolveFailed': 'Error al responder la solicitud de aprobación',
     'agentChat.createSessionFailed': 'Error al crear sesión',
     'agentChat.thinking': 'Pensando',
     'agentChat.stopGeneration': 'Detener Generación',
@@ -4713,6 +5004,10 @@ export const translations = {
     'agent.sessionDeleted': 'La sesión ha sido eliminada',
     'agent.llmConfigSet': 'La configuración de LLM ha sido establecida',
     'agent.llmConfigReloaded': 'La configuración de LLM ha sido recargada',
+    'agent.approvalSettingsSaved': 'Configuración de aprobación guardada',
+    'agent.verificationSettingsSaved': 'Configuración de verificación guardada',
+    'agent.toolCallApproved': 'Llamada a herramienta aprobada',
+    'agent.toolCallDenied': 'Llamada a herramienta rechazada',
 
     'script.messages.recordingConfigUpdated': 'La configuración de grabación ha sido actualizada',
     'script.messages.recordingConfigError': 'No se pudo actualizar la configuración de grabación',
@@ -4724,6 +5019,7 @@ export const translations = {
     'script.params.enter': 'Por favor, introduzca',
     'script.params.execute': 'Ejecutar',
     'script.params.fillAllRequired': 'Por favor, complete todos los parámetros obligatorios',
+    'script.params.patternMismatch': 'No coincide con el patrón',
 
     'llm.description': 'Descripción',
     'llm.selectProvider': 'Seleccionar proveedor',
@@ -4911,6 +5207,8 @@ export const translations = {
     'error.updateScriptFailed': 'スクリプトの更新に失敗しました',
     'error.playScriptFailed': 'スクリプトの再生に失敗しました',
     'error.getLLMConfigsFailed': 'LLM設定の取得に失敗しました',
+    'error.getLLMRoutingFailed': 'LLMルーティングポリシーの取得に失敗しました',
+    'error.invalidLLMRouting': 'LLMルーティングポリシーが無効です',
     'error.llmConfigNotFound': 'LLM設定が見つかりません',
     'error.llmConfigRequiredFields': '名前、プロバイダー、モデルは必須です',
     'error.createLLMConfigFailed': 'LLM設定の作成に失敗しました',
@@ -4935,6 +5233,24 @@ export const translations = {
     'error.deletePromptFailed': 'プロンプトの削除に失敗しました',
     'error.getExecutionRecordsFailed': '実行記録の取得に失敗しました',
     'error.executionRecordNotFound': '実行記録が見つかりません',
+    'error.fileNotFound': 'ファイルが見つかりません',
+    'error.uploadTooLarge': 'アップロードファイルがサイズ上限を超えています',
+    'error.uploadFailed': 'ファイルのアップロードに失敗しました',
+    'error.getScriptStateFailed': '増分スクレイピングの状態の取得に失敗しました',
+    'error.saveScriptStateFailed': '増分スクレイピングの状態の保存に失敗しました',
+    'error.getScriptMetricsFailed': '抽出指標の取得に失敗しました',
+    'error.getScriptHealthFailed': 'セレクターのヘルスレポートの取得に失敗しました',
+    'error.scriptHealthNotFound': 'スクリプトにはまだセレクターのヘルスレポートがありません',
+    'error.checkScriptHealthFailed': 'スクリプトのセレクターの確認に失敗しました',
+    'error.executionsFromDifferentScripts': '実行が異なるスクリプトのものです',
+    'error.invalidTranscript': 'セッション記録の形式が無効です',
+    'error.invalidTranscriptFormat': 'エクスポート形式は json または markdown のみです',
+    'error.createSessionFailed': 'セッションの作成に失敗しました',
+    'error.invalidApprovalCategories': '無効な承認カテゴリです',
+    'error.approvalNotFound': '承認リクエストが存在しないか期限切れです',
+    'error.noBaseExecution': '比較できる以前の成功した実行がありません',
+    'error.compareExecutionsFailed': '実行の比較に失敗しました',
+    'error.listUploadsFailed': 'アップロードファイル一覧の取得に失敗しました',
     'error.deleteExecutionRecordFailed': '実行記録の削除に失敗しました',
     'error.selectExecutionRecords': '削除する実行記録を選択してください',
     'error.taskNameRequired': 'タスク名は必須です',
@@ -4995,6 +5311,7 @@ export const translations = {
     'success.llmConfigCreated': 'LLM設定が作成されました',
     'success.llmConfigUpdated': 'LLM設定が更新されました',
     'success.llmConfigDeleted': 'LLM設定が削除されました',
+    'success.llmRoutingSaved': 'LLMルーティングポリシーを保存しました',
     'success.taskCreated': 'タスクが作成されました',
     'success.taskUpdated': 'タスクが更新されました',
     'success.taskDeleted': 'タスクが削除されました',
@@ -5005,6 +5322,8 @@ export const translations = {
     'success.recordingStopped': '録画が停止されました',
     'success.scriptSaved': 'スクリプトが保存されました',
     'success.executionRecordDeleted': '実行記録が削除されました',
+    'success.uploadDeleted': 'アップロードファイルが削除されました',
+    'success.scriptStateReset': '増分スクレイピングの状態をリセットしました',
     'success.recordingConfigUpdated': '録画設定が更新されました',
 
     'success.mcpCommandDisabled': 'MCPコマンドが無効化されました',
@@ -5288,6 +5607,9 @@ export const translations = {
     'script.mcp.commandDescription': 'MCPコマンドの説明',
     'script.mcp.commandDescriptionPlaceholder': 'このコマンドの機能と目的を説明してください...',
     'script.mcp.inputSchemaHint': '💡 システムがスクリプトの変数に基づいてパラメータ定義を自動生成しました。必要に応じて変更できます',
+    'script.mcp.paramUISchema': 'パラメータフォーム（UI Schema）',
+    'script.mcp.paramUISchemaPlaceholder': '空のままにするとデフォルトのフォームを使用します。例:\n{\n  "email": {\n    "label": "メール",\n    "input_type": "email",\n    "placeholder": "name@example.com"\n  },\n  "code": {\n    "pattern": "[0-9]{6}",\n    "pattern_message": "6桁の数字を入力してください"\n  }\n}',
+    'script.mcp.paramUISchemaHint': '各パラメータのラベル、プレースホルダー、入力タイプ（text、textarea、password、number、email、url、date、select、checkbox）と検証用の正規表現を設定します。Web の実行フォームと MCP ツールの引数で使用されます',
     'script.mcp.inputSchemaPlaceholder': 'パラメータなしの場合は空のままにします。JSON Schemaの例:\n{\n  "type": "object",\n  "properties": {\n    "username": {\n      "type": "string",\n      "description": "ユーザー名"\n    },\n    "password": {\n      "type": "string",\n      "description": "パスワード"\n    }\n  },\n  "required": ["username"]\n}',
     'script.mcp.tipsTitle': 'ヒント',
     'script.mcp.tip1': 'MCPコマンドは外部MCPクライアントから呼び出すことができます',
@@ -5452,6 +5774,8 @@ export const translations = {
     'execution.details.endTime': '終了時刻',
     'execution.details.message': 'メッセージ',
     'execution.details.errorInfo': 'エラー情報',
+    'execution.details.schemaError': 'データがスキーマと一致しません',
+    'execution.details.anomalies': '疑わしい抽出結果',
     'execution.details.extractedData': '抽出データ',
     'execution.details.executionVideo': '実行ビデオ',
     'execution.deleteConfirm.title': '実行記録を削除',
@@ -5473,6 +5797,20 @@ export const translations = {
     // LLM管理
     'llm.title': '大規模モデル管理',
     'llm.subtitle': 'ホットリロード対応で複数のLLMモデルを管理・設定',
+    'llm.routing.title': 'モデルルーティング',
+    'llm.routing.description': '簡単なステップを安価な小型モデルに任せ、その他のステップはデフォルトモデルまたはセッションで選択したモデルを使用します',
+    'llm.routing.cheapModel': '小型モデル',
+    'llm.routing.off': 'オフ（すべてデフォルトモデルを使用）',
+    'llm.routing.steps': '小型モデルを使用するステップ',
+    'llm.routing.stepsHint': 'ステップを選択しない場合、すべてのステップで小型モデルを使用します',
+    'llm.routing.step.task_evaluation': 'タスク評価',
+    'llm.routing.step.action_verification': '操作の検証',
+    'llm.routing.step.translation': 'ページ翻訳',
+    'llm.routing.step.mcp_info': 'MCP コマンド情報の生成',
+    'llm.routing.scriptRecommend': 'スクリプトの推奨',
+    'llm.routing.scriptRecommendDesc': 'Agent がタスクを受け取ると、埋め込みモデルで名前・説明・URL が似ているスクリプトツールを探し、Agent にそのスクリプトを優先して呼び出すよう伝えます',
+    'llm.routing.scriptRecommendOff': 'オフ',
+    'llm.routing.embeddingModelPlaceholder': '埋め込みモデル（例: text-embedding-3-small）',
     'llm.addConfig': '設定を追加',
     'llm.addConfigTitle': 'LLM設定を追加',
     'llm.loading': '読み込み中...',
@@ -5578,6 +5916,33 @@ export const translations = {
     'agentChat.sessionDeleted': 'セッションが削除されました',
     'agentChat.sessionCreated': '新しいセッションが作成されました',
     'agentChat.deleteSessionFailed': 'セッションの削除に失敗しました',
+    'agentChat.exportSessionFailed': 'セッションのエクスポートに失敗しました',
+    'agentChat.exportTranscript': '記録をエクスポート（JSON、再実行可能）',
+    'agentChat.exportMarkdown': 'Markdown でエクスポート',
+    'agentChat.readOnly': '読み取り専用',
+    'agentChat.readOnlyDesc': 'エージェントはページを開いて読むこと（ナビゲーション、スナップショット、抽出、スクリーンショット）だけができます。クリック、入力、送信はできません。ログイン中の重要なアカウントでの調査に使います',
+    'agentChat.systemPrompt': 'システムプロンプト',
+    'agentChat.defaultSystemPrompt': 'デフォルトのエージェントプロンプト',
+    'agentChat.systemPromptDesc': '買い物、調査、フォーム入力などのプロンプトをプロンプト管理に保存し、ここでセッションのシステムプロンプトとして選択します。セッション開始後は変更できません',
+    'agentChat.approval.required': 'ツール呼び出しには承認が必要です：{category}',
+    'agentChat.approval.approve': '承認',
+    'agentChat.approval.deny': '拒否',
+    'agentChat.approval.enabled': 'ツール呼び出しの承認：{count} 種類',
+    'agentChat.approval.disabled': 'ツール呼び出しの承認：オフ',
+    'agentChat.approval.category.navigation': '新しいドメイン',
+    'agentChat.approval.category.form_submit': 'フォーム送信',
+    'agentChat.approval.category.download': 'ファイルのダウンロード',
+    'agentChat.approval.category.click': 'すべてのクリック',
+    'agentChat.approval.category.script': 'スクリプトの実行',
+    'agentChat.approval.saveFailed': '承認設定の保存に失敗しました',
+    'agentChat.verification.label': '操作の検証',
+    'agentChat.verification.desc': 'クリックや入力などページを変更する操作の後にスクリーンショットを撮り、期待した変化が起きたかを LLM に確認します。起きていない場合、エージェントはページを確認して再試行します',
+    'agentChat.verification.verified': '検証済み',
+    'agentChat.verification.failed': '検証失敗',
+    'agentChat.verification.skipped': '未検証',
+    'agentChat.verification.screenshot': '操作後のスクリーンショット',
+    'agentChat.verification.saveFailed': '検証設定の保存に失敗しました',
+    'agentChat.approval.resolveFailed': '承認リクエストの処理に失敗しました',
     'agentChat.createSessionFailed': 'セッションの作成に失敗しました',
     'agentChat.thinking': '考え中',
     'agentChat.stopGeneration': '生成を停止',
@@ -5931,6 +6296,10 @@ export const translations = {
     'agent.sessionDeleted': 'セッションが削除されました',
     'agent.llmConfigSet': 'LLM 設定が完了しました',
     'agent.llmConfigReloaded': 'LLM 設定が再読み込みされました',
+    'agent.approvalSettingsSaved': '承認設定を保存しました',
+    'agent.verificationSettingsSaved': '検証設定を保存しました',
+    'agent.toolCallApproved': 'ツール呼び出しを承認しました',
+    'agent.toolCallDenied': 'ツール呼び出しを拒否しました',
 
     'script.messages.recordingConfigUpdated': '録画設定が更新されました',
     'script.messages.recordingConfigError': '録画設定の更新に失敗しました',
@@ -5942,6 +6311,7 @@ export const translations = {
     'script.params.enter': '入力してください',
     'script.params.execute': '実行',
     'script.params.fillAllRequired': '必須パラメータをすべて入力してください',
+    'script.params.patternMismatch': '形式が一致しません',
 
     'llm.description': '説明',
     'llm.selectProvider': 'プロバイダーを選択',
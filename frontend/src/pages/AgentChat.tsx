@@ -1,8 +1,9 @@
 import { useState, useEffect, useRef } from 'react'
-import { Send, Loader2, Bot, Wrench, CheckCircle2, XCircle, Trash2, MessageSquarePlus, Copy, Check, ChevronDown, StopCircle, Maximize2, Minimize2, PanelLeftClose, PanelLeftOpen } from 'lucide-react'
+import { Send, Loader2, Bot, Wrench, CheckCircle2, XCircle, Trash2, MessageSquarePlus, Copy, Check, ChevronDown, StopCircle, Maximize2, Minimize2, PanelLeftClose, PanelLeftOpen, Download, FileText, ShieldCheck, AlertTriangle } from 'lucide-react'
 import { useNavigate } from 'react-router-dom'
 import Toast from '../components/Toast'
 import MarkdownRenderer from '../components/MarkdownRenderer'
+import AgentApprovalPanel, { PendingApproval } from '../components/AgentApprovalPanel'
 import { useLanguage } from '../i18n'
 
 // 创建带认证的 fetch wrapper
@@ -24,6 +25,16 @@ interface ToolCall {
   arguments?: Record<string, any>  // 工具调用参数
   result?: string  // 工具执行结果
   timestamp?: string  // 调用时间戳
+  verification?: ActionVerification  // 开启操作校验时的校验结果
+}
+
+// 操作后的校验结果
+interface ActionVerification {
+  verified: boolean
+  reason?: string
+  screenshot?: string  // 操作后的截图路径
+  error?: string  // 校验本身失败时的原因
+  checked_at: string
 }
 
 interface ChatMessage {
@@ -40,12 +51,16 @@ interface ChatSession {
   messages: ChatMessage[]
   created_at: string
   updated_at: string
+  approval_categories?: string[]  // 需要人工批准的工具调用类别
+  read_only?: boolean  // 只读会话，只能打开和读取页面
+  verify_actions?: boolean  // 改变页面的操作后截图并校验
 }
 
 interface StreamChunk {
-  type: 'message' | 'tool_call' | 'done' | 'error'
+  type: 'message' | 'tool_call' | 'approval_required' | 'done' | 'error'
   content?: string
   tool_call?: ToolCall
+  approval?: PendingApproval  // 等待批准的工具调用
   error?: string
   message_id?: string
 }
@@ -64,10 +79,14 @@ export default function AgentChat() {
   const [llmConfigs, setLlmConfigs] = useState<any[]>([])
   const [showNewSessionDialog, setShowNewSessionDialog] = useState(false)
   const [selectedLlmForNewSession, setSelectedLlmForNewSession] = useState<string>('')
+  const [readOnlyForNewSession, setReadOnlyForNewSession] = useState(false)
+  const [agentPrompts, setAgentPrompts] = useState<{ id: string; name: string }[]>([])
+  const [systemPromptForNewSession, setSystemPromptForNewSession] = useState('')
   const [copiedMessageId, setCopiedMessageId] = useState<string | null>(null)
   const [expandedToolCalls, setExpandedToolCalls] = useState<Set<string>>(new Set())
   const [isFullscreen, setIsFullscreen] = useState(false)
   const [isSidebarCollapsed, setIsSidebarCollapsed] = useState(false)
+  const [pendingApprovals, setPendingApprovals] = useState<PendingApproval[]>([])
   
   const messagesEndRef = useRef<HTMLDivElement>(null)
   const textareaRef = useRef<HTMLTextAreaElement>(null)
@@ -213,10 +232,22 @@ export default function AgentChat() {
     }
   }
 
+  // 加载可作为系统提示词的自定义提示词
+  const loadAgentPrompts = async () => {
+    try {
+      const response = await authFetch('/api/v1/prompts?exclude_system=true')
+      const data = await response.json()
+      setAgentPrompts(data.data || [])
+    } catch (error) {
+      console.error('加载提示词失败:', error)
+    }
+  }
+
   useEffect(() => {
     loadSessions()
     loadMCPStatus()
     loadLLMConfigs()
+    loadAgentPrompts()
     
     // 定期刷新 MCP 状态
     const interval = setInterval(loadMCPStatus, 5000)
@@ -267,6 +298,8 @@ export default function AgentChat() {
         },
         body: JSON.stringify({
           llm_config_id: llmConfigId,
+          system_prompt_id: systemPromptForNewSession || undefined,
+          read_only: readOnlyForNewSession,
         }),
       })
       const data = await response.json()
@@ -275,6 +308,8 @@ export default function AgentChat() {
       setSessions([newSession, ...sessions])
       setCurrentSession(newSession)
       setShowNewSessionDialog(false)
+      setReadOnlyForNewSession(false)
+      setSystemPromptForNewSession('')
       
       showToastMessage(t('agentChat.sessionCreated'), 'success')
     } catch (error) {
@@ -302,6 +337,107 @@ export default function AgentChat() {
     }
   }
 
+  // 导出会话记录（json 可用于重放，markdown 便于阅读）
+  const exportSession = async (sessionId: string, format: 'json' | 'markdown') => {
+    try {
+      const response = await authFetch(`/api/v1/agent/sessions/${sessionId}/export?format=${format}`)
+      if (!response.ok) {
+        throw new Error(`HTTP ${response.status}`)
+      }
+      const blob = await response.blob()
+      const url = URL.createObjectURL(blob)
+      const a = document.createElement('a')
+      a.href = url
+      a.download = `agent-session-${sessionId}.${format === 'json' ? 'json' : 'md'}`
+      document.body.appendChild(a)
+      a.click()
+      document.body.removeChild(a)
+      URL.revokeObjectURL(url)
+    } catch (error) {
+      console.error('导出会话失败:', error)
+      showToastMessage(t('agentChat.exportSessionFailed'), 'error')
+    }
+  }
+
+  // 切换会话时加载等待批准的工具调用
+  useEffect(() => {
+    setPendingApprovals([])
+    if (!currentSession?.id) return
+    authFetch(`/api/v1/agent/sessions/${currentSession.id}/approvals`)
+      .then(response => response.ok ? response.json() : null)
+      .then(data => {
+        if (data?.approvals) {
+          setPendingApprovals(data.approvals)
+        }
+      })
+      .catch(error => console.error('加载待批准的工具调用失败:', error))
+  }, [currentSession?.id])
+
+  // 设置需要人工批准的工具调用类别
+  const updateApprovalCategories = async (categories: string[]) => {
+    if (!currentSession) return
+    try {
+      const response = await authFetch(`/api/v1/agent/sessions/${currentSession.id}/approval-settings`, {
+        method: 'PUT',
+        headers: {
+          'Content-Type': 'application/json',
+        },
+        body: JSON.stringify({ categories }),
+      })
+      if (!response.ok) {
+        throw new Error(`HTTP ${response.status}`)
+      }
+      setCurrentSession(prev => prev ? { ...prev, approval_categories: categories } : prev)
+      setSessions(prev => prev.map(s => s.id === currentSession.id ? { ...s, approval_categories: categories } : s))
+    } catch (error) {
+      console.error('保存批准设置失败:', error)
+      showToastMessage(t('agentChat.approval.saveFailed'), 'error')
+    }
+  }
+
+  // 开启或关闭操作校验
+  const updateVerifyActions = async (enabled: boolean) => {
+    if (!currentSession) return
+    try {
+      const response = await authFetch(`/api/v1/agent/sessions/${currentSession.id}/verification`, {
+        method: 'PUT',
+        headers: {
+          'Content-Type': 'application/json',
+        },
+        body: JSON.stringify({ enabled }),
+      })
+      if (!response.ok) {
+        throw new Error(`HTTP ${response.status}`)
+      }
+      setCurrentSession(prev => prev ? { ...prev, verify_actions: enabled } : prev)
+      setSessions(prev => prev.map(s => s.id === currentSession.id ? { ...s, verify_actions: enabled } : s))
+    } catch (error) {
+      console.error('保存操作校验设置失败:', error)
+      showToastMessage(t('agentChat.verification.saveFailed'), 'error')
+    }
+  }
+
+  // 批准或拒绝工具调用
+  const resolveApproval = async (approvalId: string, approved: boolean) => {
+    if (!currentSession) return
+    setPendingApprovals(prev => prev.filter(a => a.id !== approvalId))
+    try {
+      const response = await authFetch(`/api/v1/agent/sessions/${currentSession.id}/approvals/${approvalId}`, {
+        method: 'POST',
+        headers: {
+          'Content-Type': 'application/json',
+        },
+        body: JSON.stringify({ approved }),
+      })
+      if (!response.ok) {
+        throw new Error(`HTTP ${response.status}`)
+      }
+    } catch (error) {
+      console.error('处理批准请求失败:', error)
+      showToastMessage(t('agentChat.approval.resolveFailed'), 'error')
+    }
+  }
+
   // 停止消息生成
   const stopGeneration = () => {
     if (abortControllerRef.current) {
@@ -509,6 +645,14 @@ export default function AgentChat() {
                 }
                 break
 
+              case 'approval_required':
+                // 工具调用等待用户批准
+                if (chunk.approval) {
+                  const approval = chunk.approval
+                  setPendingApprovals(prev => [...prev.filter(a => a.id !== approval.id), approval])
+                }
+                break
+
               case 'done':
                 // 完成 - 单个消息完成，但不关闭整个流式状态
                 // 流式状态会在整个连接结束时关闭
@@ -526,8 +670,9 @@ export default function AgentChat() {
         }
       }
 
-      // 流式传输完成，关闭流式状态
+      // 流式传输完成，关闭流式状态；未处理的批准请求已随对话结束失效
       setIsStreaming(false)
+      setPendingApprovals([])
 
       // 重新加载会话以获取完整数据
       console.log('[流式完成] 开始重新加载会话:', currentSession.id)
@@ -664,6 +809,19 @@ export default function AgentChat() {
             {toolCall.tool_name}
           </span>
           {statusIcons[toolCall.status]}
+          {toolCall.verification && !toolCall.verification.error && (
+            toolCall.verification.verified ? (
+              <span className="flex items-center gap-1 text-xs text-green-700 dark:text-green-400" title={toolCall.verification.reason}>
+                <ShieldCheck className="w-3.5 h-3.5" />
+                {t('agentChat.verification.verified')}
+              </span>
+            ) : (
+              <span className="flex items-center gap-1 text-xs text-amber-700 dark:text-amber-400" title={toolCall.verification.reason}>
+                <AlertTriangle className="w-3.5 h-3.5" />
+                {t('agentChat.verification.failed')}
+              </span>
+            )
+          )}
           <ChevronDown
             className={`w-4 h-4 text-gray-500 dark:text-gray-400 ml-auto flex-shrink-0 transition-transform ${isExpanded ? 'rotate-180' : ''
               }`}
@@ -697,6 +855,27 @@ export default function AgentChat() {
               </div>
             )}
 
+            {/* 操作校验 */}
+            {toolCall.verification && (
+              <div className="text-xs text-gray-600 dark:text-gray-400 space-y-0.5">
+                <div>
+                  <span className="font-semibold">
+                    {toolCall.verification.error
+                      ? t('agentChat.verification.skipped')
+                      : toolCall.verification.verified
+                        ? t('agentChat.verification.verified')
+                        : t('agentChat.verification.failed')}:
+                  </span>{' '}
+                  {toolCall.verification.error || toolCall.verification.reason}
+                </div>
+                {toolCall.verification.screenshot && (
+                  <div className="break-all">
+                    {t('agentChat.verification.screenshot')}: {toolCall.verification.screenshot}
+                  </div>
+                )}
+              </div>
+            )}
+
             {/* 状态消息 */}
             {toolCall.message && (
               <div className="text-xs text-gray-500 dark:text-gray-400">
@@ -807,8 +986,31 @@ export default function AgentChat() {
                           </div>
                           <div className="text-xs text-gray-500 dark:text-gray-400 mt-1">
                             {session.messages?.length || 0} {t('agentChat.messages')}
+                            {session.read_only && (
+                              <span className="ml-2 px-1.5 py-0.5 rounded bg-gray-200 dark:bg-gray-600">{t('agentChat.readOnly')}</span>
+                            )}
                           </div>
                         </div>
+                        <button
+                          onClick={(e) => {
+                            e.stopPropagation()
+                            exportSession(session.id, 'json')
+                          }}
+                          title={t('agentChat.exportTranscript')}
+                          className="opacity-0 group-hover:opacity-100 p-1 hover:bg-gray-200 dark:hover:bg-gray-600 rounded"
+                        >
+                          <Download className="w-4 h-4" />
+                        </button>
+                        <button
+                          onClick={(e) => {
+                            e.stopPropagation()
+                            exportSession(session.id, 'markdown')
+                          }}
+                          title={t('agentChat.exportMarkdown')}
+                          className="opacity-0 group-hover:opacity-100 p-1 hover:bg-gray-200 dark:hover:bg-gray-600 rounded"
+                        >
+                          <FileText className="w-4 h-4" />
+                        </button>
                         <button
                           onClick={(e) => {
                             e.stopPropagation()
@@ -934,6 +1136,14 @@ export default function AgentChat() {
                 {/* 输入区域 */}
                 <div className="px-6 py-4 bg-white dark:bg-gray-800 flex-shrink-0 border-t border-gray-200 dark:border-gray-700">
                   <div className="max-w-3xl mx-auto">
+                    <AgentApprovalPanel
+                      categories={currentSession.approval_categories || []}
+                      pending={pendingApprovals}
+                      onCategoriesChange={updateApprovalCategories}
+                      onResolve={resolveApproval}
+                      verifyActions={!!currentSession.verify_actions}
+                      onVerifyActionsChange={updateVerifyActions}
+                    />
                     <div className="flex items-end gap-3">
                       <div className="flex-1 flex items-end gap-3 bg-gray-50 dark:bg-gray-700 border border-gray-200 dark:border-gray-600 rounded-2xl px-4 py-2">
                         <textarea
@@ -1005,6 +1215,36 @@ export default function AgentChat() {
                   </button>
                 ))}
               </div>
+
+              <div className="mb-4">
+                <label className="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1">
+                  {t('agentChat.systemPrompt')}
+                </label>
+                <select
+                  value={systemPromptForNewSession}
+                  onChange={(e) => setSystemPromptForNewSession(e.target.value)}
+                  className="w-full px-3 py-2 text-sm border border-gray-300 dark:border-gray-600 rounded-lg bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100"
+                >
+                  <option value="">{t('agentChat.defaultSystemPrompt')}</option>
+                  {agentPrompts.map(prompt => (
+                    <option key={prompt.id} value={prompt.id}>{prompt.name}</option>
+                  ))}
+                </select>
+                <span className="block mt-1 text-xs text-gray-500 dark:text-gray-400">{t('agentChat.systemPromptDesc')}</span>
+              </div>
+
+              <label className="flex items-start gap-2 mb-6 text-sm text-gray-700 dark:text-gray-300 cursor-pointer">
+                <input
+                  type="checkbox"
+                  className="mt-0.5"
+                  checked={readOnlyForNewSession}
+                  onChange={(e) => setReadOnlyForNewSession(e.target.checked)}
+                />
+                <span>
+                  <span className="font-medium">{t('agentChat.readOnly')}</span>
+                  <span className="block text-xs text-gray-500 dark:text-gray-400">{t('agentChat.readOnlyDesc')}</span>
+                </span>
+              </label>
               
               <div className="flex gap-3">
                 <button
@@ -1154,8 +1394,31 @@ export default function AgentChat() {
                           </div>
                           <div className="text-sm text-gray-500 dark:text-gray-400 mt-1">
                             {session.messages?.length || 0} {t('agentChat.messages')}
+                            {session.read_only && (
+                              <span className="ml-2 px-1.5 py-0.5 rounded bg-gray-200 dark:bg-gray-600">{t('agentChat.readOnly')}</span>
+                            )}
                           </div>
                         </div>
+                        <button
+                          onClick={(e) => {
+                            e.stopPropagation()
+                            exportSession(session.id, 'json')
+                          }}
+                          title={t('agentChat.exportTranscript')}
+                          className="opacity-0 group-hover:opacity-100 p-1 hover:bg-gray-200 dark:hover:bg-gray-600 rounded"
+                        >
+                          <Download className="w-4 h-4" />
+                        </button>
+                        <button
+                          onClick={(e) => {
+                            e.stopPropagation()
+                            exportSession(session.id, 'markdown')
+                          }}
+                          title={t('agentChat.exportMarkdown')}
+                          className="opacity-0 group-hover:opacity-100 p-1 hover:bg-gray-200 dark:hover:bg-gray-600 rounded"
+                        >
+                          <FileText className="w-4 h-4" />
+                        </button>
                         <button
                           onClick={(e) => {
                             e.stopPropagation()
@@ -1293,6 +1556,14 @@ export default function AgentChat() {
               {/* 输入区域 - 固定在底部 */}
               <div className="px-6 py-3 bg-white dark:bg-gray-800 flex-shrink-0 shadow-[0_-4px_6px_-1px_rgba(0,0,0,0.1)] dark:shadow-[0_-4px_6px_-1px_rgba(0,0,0,0.3)]">
                 <div className="max-w-8xl mx-auto">
+                  <AgentApprovalPanel
+                    categories={currentSession.approval_categories || []}
+                    pending={pendingApprovals}
+                    onCategoriesChange={updateApprovalCategories}
+                    onResolve={resolveApproval}
+                    verifyActions={!!currentSession.verify_actions}
+                    onVerifyActionsChange={updateVerifyActions}
+                  />
                   <div className="flex items-end gap-3">
                     {/* 输入框 */}
                     <div className="flex-1 flex items-end gap-3 bg-gray-50 dark:bg-gray-700 border border-gray-200 dark:border-gray-600 rounded-2xl px-4 py-2">
@@ -1367,6 +1638,36 @@ export default function AgentChat() {
                 </button>
               ))}
             </div>
+
+            <div className="mb-4">
+              <label className="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1">
+                {t('agentChat.systemPrompt')}
+              </label>
+              <select
+                value={systemPromptForNewSession}
+                onChange={(e) => setSystemPromptForNewSession(e.target.value)}
+                className="w-full px-3 py-2 text-sm border border-gray-300 dark:border-gray-600 rounded-lg bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100"
+              >
+                <option value="">{t('agentChat.defaultSystemPrompt')}</option>
+                {agentPrompts.map(prompt => (
+                  <option key={prompt.id} value={prompt.id}>{prompt.name}</option>
+                ))}
+              </select>
+              <span className="block mt-1 text-xs text-gray-500 dark:text-gray-400">{t('agentChat.systemPromptDesc')}</span>
+            </div>
+
+            <label className="flex items-start gap-2 mb-6 text-sm text-gray-700 dark:text-gray-300 cursor-pointer">
+              <input
+                type="checkbox"
+                className="mt-0.5"
+                checked={readOnlyForNewSession}
+                onChange={(e) => setReadOnlyForNewSession(e.target.checked)}
+              />
+              <span>
+                <span className="font-medium">{t('agentChat.readOnly')}</span>
+                <span className="block text-xs text-gray-500 dark:text-gray-400">{t('agentChat.readOnlyDesc')}</span>
+              </span>
+            </label>
             
             <div className="flex gap-3">
               <button
@@ -1,7 +1,7 @@
 import { useState, useEffect } from 'react'
-import { Plus, Trash2, Edit, X, Star, TestTube, Loader, BookText } from 'lucide-react'
+import { Plus, Trash2, Edit, X, Star, TestTube, Loader, BookText, Route } from 'lucide-react'
 import { useNavigate } from 'react-router-dom'
-import { api, LLMConfig, CreateLLMConfigRequest } from '../api/client'
+import { api, LLMConfig, CreateLLMConfigRequest, LLMRoutingPolicy } from '../api/client'
 import Toast from '../components/Toast'
 import ConfirmDialog from '../components/ConfirmDialog'
 import { useLanguage } from '../i18n'
@@ -61,6 +61,9 @@ export default function LLMManager() {
   const [testingId, setTestingId] = useState<string | null>(null)
   const [toast, setToast] = useState<{ message: string; type: 'success' | 'error' | 'info' } | null>(null)
   const [deleteConfirm, setDeleteConfirm] = useState<{ show: boolean; configId: string | null }>({ show: false, configId: null })
+  const [routing, setRouting] = useState<LLMRoutingPolicy>({ cheap_config_id: '', cheap_steps: [] })
+  const [routingSteps, setRoutingSteps] = useState<string[]>([])
+  const [savingRouting, setSavingRouting] = useState(false)
 
   // 获取 provider 的显示名称
   const getProviderLabel = (value: string) => {
@@ -74,6 +77,7 @@ export default function LLMManager() {
 
   useEffect(() => {
     loadConfigs()
+    loadRouting()
   }, [])
 
   const loadConfigs = async () => {
@@ -88,6 +92,37 @@ export default function LLMManager() {
     }
   }
 
+  const loadRouting = async () => {
+    try {
+      const response = await api.getLLMRouting()
+      setRouting(response.data.policy)
+      setRoutingSteps(response.data.steps || [])
+    } catch (error) {
+      console.error('加载 LLM 路由策略失败:', error)
+    }
+  }
+
+  const toggleRoutingStep = (step: string) => {
+    const steps = routing.cheap_steps || []
+    setRouting({
+      ...routing,
+      cheap_steps: steps.includes(step) ? steps.filter(s => s !== step) : [...steps, step],
+    })
+  }
+
+  const saveRouting = async () => {
+    try {
+      setSavingRouting(true)
+      const response = await api.updateLLMRouting(routing)
+      setRouting(response.data.policy)
+      showToast(t(response.data.message), 'success')
+    } catch (error: any) {
+      showToast(t(error.response?.data?.error || error.message), 'error')
+    } finally {
+      setSavingRouting(false)
+    }
+  }
+
   const handleAdd = async () => {
     // Ollama 本地运行不需要 API Key
     const requiresApiKey = formData.provider !== 'ollama'
@@ -485,6 +520,85 @@ export default function LLMManager() {
         )}
       </div>
 
+      {/* Routing */}
+      {configs.length > 0 && (
+        <div className="bg-white dark:bg-gray-800 border border-gray-200 dark:border-gray-700 rounded-lg p-6 space-y-4">
+          <div>
+            <h2 className="flex items-center text-lg font-medium text-gray-900 dark:text-gray-100">
+              <Route className="w-5 h-5 mr-2" />
+              {t('llm.routing.title')}
+            </h2>
+            <p className="mt-1 text-sm text-gray-500">{t('llm.routing.description')}</p>
+          </div>
+          <div>
+            <label className="block text-sm font-medium text-gray-700 dark:text-gray-300 mb-1">
+              {t('llm.routing.cheapModel')}
+            </label>
+            <select
+              value={routing.cheap_config_id}
+              onChange={(e) => setRouting({ ...routing, cheap_config_id: e.target.value })}
+              className="w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-lg bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100 focus:outline-none focus:ring-2 focus:ring-gray-900"
+            >
+              <option value="">{t('llm.routing.off')}</option>
+              {configs.filter(c => c.is_active).map(c => (
+                <option key={c.id} value={c.id}>{c.name} ({c.model})</option>
+              ))}
+            </select>
+          </div>
+          {routing.cheap_config_id && (
+            <div className="space-y-2">
+              <div className="text-sm font-medium text-gray-700 dark:text-gray-300">{t('llm.routing.steps')}</div>
+              {routingSteps.map(step => (
+                <label key={step} className="flex items-center space-x-2 text-sm text-gray-700 dark:text-gray-300">
+                  <input
+                    type="checkbox"
+                    checked={(routing.cheap_steps || []).includes(step)}
+                    onChange={() => toggleRoutingStep(step)}
+                    className="rounded border-gray-300"
+                  />
+                  <span>{t(`llm.routing.step.${step}`)}</span>
+                </label>
+              ))}
+              <p className="text-xs text-gray-500">{t('llm.routing.stepsHint')}</p>
+            </div>
+          )}
+          <div className="pt-4 border-t border-gray-200 dark:border-gray-700 space-y-3">
+            <div>
+              <div className="text-sm font-medium text-gray-700 dark:text-gray-300">{t('llm.routing.scriptRecommend')}</div>
+              <p className="mt-1 text-xs text-gray-500">{t('llm.routing.scriptRecommendDesc')}</p>
+            </div>
+            <select
+              value={routing.embedding_config_id || ''}
+              onChange={(e) => setRouting({ ...routing, embedding_config_id: e.target.value })}
+              className="w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-lg bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100 focus:outline-none focus:ring-2 focus:ring-gray-900"
+            >
+              <option value="">{t('llm.routing.scriptRecommendOff')}</option>
+              {configs.filter(c => c.is_active && c.provider !== 'claude').map(c => (
+                <option key={c.id} value={c.id}>{c.name} ({getProviderLabel(c.provider)})</option>
+              ))}
+            </select>
+            {routing.embedding_config_id && (
+              <input
+                type="text"
+                value={routing.embedding_model || ''}
+                onChange={(e) => setRouting({ ...routing, embedding_model: e.target.value })}
+                placeholder={t('llm.routing.embeddingModelPlaceholder')}
+                className="w-full px-3 py-2 border border-gray-300 dark:border-gray-600 rounded-lg bg-white dark:bg-gray-700 text-gray-900 dark:text-gray-100 focus:outline-none focus:ring-2 focus:ring-gray-900"
+              />
+            )}
+          </div>
+          <div className="flex justify-end">
+            <button
+              onClick={saveRouting}
+              disabled={savingRouting}
+              className="px-4 py-2 bg-gray-900 text-white rounded-lg hover:bg-gray-800 transition-colors disabled:opacity-50"
+            >
+              {t('common.save')}
+            </button>
+          </div>
+        </div>
+      )}
+
       {/* Toast */}
       {toast && (
         <Toast
@@ -326,6 +326,28 @@ export default function ScriptExecutionHistory() {
                               </div>
                             )}
 
+                            {execution.schema_error && (
+                              <div>
+                                <h4 className="text-sm font-medium text-amber-700 mb-2">{t('execution.details.schemaError')}</h4>
+                                <div className="bg-amber-50 border border-amber-200 rounded-lg p-3">
+                                  <pre className="text-xs text-amber-800 whitespace-pre-wrap">{execution.schema_error}</pre>
+                                </div>
+                              </div>
+                            )}
+
+                            {execution.anomalies && execution.anomalies.length > 0 && (
+                              <div>
+                                <h4 className="text-sm font-medium text-amber-700 mb-2">{t('execution.details.anomalies')}</h4>
+                                <div className="bg-amber-50 border border-amber-200 rounded-lg p-3">
+                                  <ul className="text-xs text-amber-800 list-disc pl-4 space-y-1">
+                                    {execution.anomalies.map((anomaly, index) => (
+                                      <li key={index}>{anomaly}</li>
+                                    ))}
+                                  </ul>
+                                </div>
+                              </div>
+                            )}
+
                             {execution.extracted_data && Object.keys(execution.extracted_data).length > 0 && (
                               <div>
                                                 <h4 className="text-sm font-medium text-gray-700 mb-2">{t('execution.details.extractedData')}</h4>
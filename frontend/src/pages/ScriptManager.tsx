@@ -1,5 +1,5 @@
 import { useState, useEffect, useCallback, useRef } from 'react'
-import api, { Script, ScriptAction, RecordingConfig, ScriptExecution, BrowserInstance } from '../api/client'
+import api, { Script, ScriptAction, RecordingConfig, ScriptExecution, BrowserInstance, ParamUIField } from '../api/client'
 import { Lightbulb, RefreshCw, Play, Trash2, Clock, FileCode, ChevronDown, ChevronUp, Edit2, X, Check, ExternalLink, GripVertical, Download, Upload, CheckSquare, Square, Copy, Tag, Folder, HelpCircle, Clipboard, Plus, Variable } from 'lucide-react'
 import Toast from '../components/Toast'
 import ConfirmDialog from '../components/ConfirmDialog'
@@ -66,6 +66,7 @@ export default function ScriptManager() {
   const [mcpCommandName, setMCPCommandName] = useState('')
   const [mcpCommandDescription, setMCPCommandDescription] = useState('')
   const [mcpInputSchemaText, setMCPInputSchemaText] = useState('')
+  const [paramUISchemaText, setParamUISchemaText] = useState('')
 
   // Tutorial modal
   const [showTutorial, setShowTutorial] = useState(false)
@@ -412,6 +413,7 @@ export default function ScriptManager() {
     setMCPConfigScript(script)
     setMCPCommandName(script.mcp_command_name || '')
     setMCPCommandDescription(script.mcp_command_description || '')
+    setParamUISchemaText(script.param_ui_schema ? JSON.stringify(script.param_ui_schema, null, 2) : '')
 
     // 加载 input schema，如果存在则格式化为 JSON
     if (script.mcp_input_schema) {
@@ -426,7 +428,10 @@ export default function ScriptManager() {
         if (!text) return
         let match
         while ((match = placeholderPattern.exec(text)) !== null) {
-          inputVariables.add(match[1])
+          // ${state.*} 引用增量抓取状态，不是输入参数
+          if (!match[1].startsWith('state.')) {
+            inputVariables.add(match[1])
+          }
         }
       }
 
@@ -506,11 +511,24 @@ export default function ScriptManager() {
         }
       }
 
+      // 解析参数表单元数据 JSON，清空时保存为空对象以移除已有配置
+      let paramUISchema: Record<string, ParamUIField> = {}
+      if (paramUISchemaText.trim()) {
+        try {
+          paramUISchema = JSON.parse(paramUISchemaText)
+        } catch (err) {
+          showMessage(t('script.messages.mcpInvalidJSON'), 'error')
+          setLoading(false)
+          return
+        }
+      }
+
       const response = await api.toggleScriptMCPCommand(mcpConfigScript.id, {
         is_mcp_command: true,
         mcp_command_name: mcpCommandName,
         mcp_command_description: mcpCommandDescription,
         mcp_input_schema: inputSchema,
+        param_ui_schema: paramUISchema,
       })
       showMessage(t(response.data.message), 'success')
       await loadScripts()
@@ -1906,6 +1924,7 @@ export default function ScriptManager() {
         onConfirm={handleParamsDialogConfirm}
         onCancel={handleParamsDialogCancel}
         scriptName={paramsDialogScript?.name}
+        uiSchema={paramsDialogScript?.param_ui_schema}
       />
 
       {/* Extracted Data Display */}
@@ -2829,6 +2848,28 @@ export default function ScriptManager() {
                                   </div>
                                 )}
 
+                                {execution.schema_error && (
+                                  <div className="w-full overflow-hidden">
+                                    <h4 className="text-sm font-medium text-amber-700 mb-2">{t('execution.details.schemaError')}</h4>
+                                    <div className="bg-amber-50 border border-amber-200 rounded-lg p-3 max-h-48 overflow-auto w-full">
+                                      <pre className="text-xs text-amber-800 whitespace-pre-wrap break-all max-w-full" style={{ wordBreak: 'break-word' }}>{execution.schema_error}</pre>
+                                    </div>
+                                  </div>
+                                )}
+
+                                {execution.anomalies && execution.anomalies.length > 0 && (
+                                  <div className="w-full overflow-hidden">
+                                    <h4 className="text-sm font-medium text-amber-700 mb-2">{t('execution.details.anomalies')}</h4>
+                                    <div className="bg-amber-50 border border-amber-200 rounded-lg p-3 w-full">
+                                      <ul className="text-xs text-amber-800 list-disc pl-4 space-y-1 break-words">
+                                        {execution.anomalies.map((anomaly, index) => (
+                                          <li key={index}>{anomaly}</li>
+                                        ))}
+                                      </ul>
+                                    </div>
+                                  </div>
+                                )}
+
                                 {execution.extracted_data && Object.keys(execution.extracted_data).length > 0 && (
                                   <div className="w-full overflow-hidden">
                                     <h4 className="text-sm font-medium text-gray-700 dark:text-gray-300 mb-2">{t('execution.details.extractedData')}</h4>
@@ -3251,6 +3292,22 @@ export default function ScriptManager() {
                   </p>
                 </div>
 
+                <div>
+                  <label className="block text-base font-medium text-gray-700 dark:text-gray-300 mb-2">
+                    {t('script.mcp.paramUISchema')}
+                  </label>
+                  <textarea
+                    value={paramUISchemaText}
+                    onChange={(e) => setParamUISchemaText(e.target.value)}
+                    placeholder={t('script.mcp.paramUISchemaPlaceholder')}
+                    rows={8}
+                    className="w-full px-4 py-2.5 border border-gray-300 dark:border-gray-600 rounded-lg focus:ring-2 focus:ring-gray-500 dark:focus:ring-gray-400 focus:border-transparent font-mono text-sm leading-relaxed bg-white dark:bg-gray-800 text-gray-900 dark:text-gray-100"
+                  />
+                  <p className="mt-2 text-sm text-gray-500 dark:text-gray-400">
+                    {t('script.mcp.paramUISchemaHint')}
+                  </p>
+                </div>
+
                 <div className="bg-blue-50 dark:bg-blue-900/20 border border-blue-200 dark:border-blue-700 rounded-lg p-4">
                   <div className="flex items-start justify-between">
                     <div className="flex-1">
@@ -12,6 +12,8 @@ export function extractPlaceholdersFromText(text: string | undefined | null): st
   
   while ((match = placeholderPattern.exec(text)) !== null) {
     const placeholder = match[1]
+    // ${state.*} 引用脚本的增量抓取状态，回放时替换，不是执行参数
+    if (placeholder.startsWith('state.')) continue
     if (!placeholders.includes(placeholder)) {
       placeholders.push(placeholder)
     }
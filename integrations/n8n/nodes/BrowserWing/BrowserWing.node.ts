@@ -0,0 +1,283 @@
+import {
+	NodeApiError,
+	NodeOperationError,
+	sleep,
+	type IDataObject,
+	type IExecuteFunctions,
+	type IHttpRequestMethods,
+	type ILoadOptionsFunctions,
+	type INodeExecutionData,
+	type INodePropertyOptions,
+	type INodeType,
+	type INodeTypeDescription,
+	type JsonObject,
+	type ResourceMapperField,
+	type ResourceMapperFields,
+} from 'n8n-workflow';
+
+/** Script summary returned by GET /api/v1/automation/scripts. */
+interface AutomationScript {
+	id: string;
+	name: string;
+	description: string;
+	group: string;
+	parameters: {
+		properties?: Record<string, { type?: string; description?: string; default?: unknown }>;
+		required?: string[];
+	};
+}
+
+/** Run returned by the automation run endpoints. */
+interface AutomationRun extends IDataObject {
+	id: string;
+	status: 'running' | 'succeeded' | 'failed';
+	error?: string;
+}
+
+async function apiRequest(
+	this: IExecuteFunctions | ILoadOptionsFunctions,
+	method: IHttpRequestMethods,
+	path: string,
+	body?: IDataObject,
+): Promise<unknown> {
+	const credentials = await this.getCredentials('browserWingApi');
+	const baseUrl = String(credentials.baseUrl).replace(/\/+$/, '');
+	try {
+		const resp = (await this.helpers.httpRequestWithAuthentication.call(this, 'browserWingApi', {
+			method,
+			url: `${baseUrl}/api/v1/automation${path}`,
+			body,
+			json: true,
+		})) as { data: unknown };
+		return resp.data;
+	} catch (error) {
+		throw new NodeApiError(this.getNode(), error as JsonObject);
+	}
+}
+
+export class BrowserWing implements INodeType {
+	description: INodeTypeDescription = {
+		displayName: 'BrowserWing',
+		name: 'browserWing',
+		icon: 'file:browserwing.svg',
+		group: ['transform'],
+		version: 1,
+		subtitle: '={{$parameter["operation"]}}',
+		description: 'Run BrowserWing browser automation scripts',
+		defaults: { name: 'BrowserWing' },
+		inputs: ['main'],
+		outputs: ['main'],
+		credentials: [{ name: 'browserWingApi', required: true }],
+		properties: [
+			{
+				displayName: 'Operation',
+				name: 'operation',
+				type: 'options',
+				noDataExpression: true,
+				options: [
+					{ name: 'Run Script', value: 'run', action: 'Run a script' },
+					{ name: 'Get Run', value: 'getRun', action: 'Get the status and result of a run' },
+				],
+				default: 'run',
+			},
+			{
+				displayName: 'Script Name or ID',
+				name: 'scriptId',
+				type: 'options',
+				typeOptions: { loadOptionsMethod: 'getScripts' },
+				required: true,
+				default: '',
+				description:
+					'Choose from the list, or specify an ID using an <a href="https://docs.n8n.io/code/expressions/">expression</a>',
+				displayOptions: { show: { operation: ['run'] } },
+			},
+			{
+				displayName: 'Parameters',
+				name: 'parameters',
+				type: 'resourceMapper',
+				noDataExpression: true,
+				default: { mappingMode: 'defineBelow', value: null },
+				required: true,
+				typeOptions: {
+					loadOptionsDependsOn: ['scriptId'],
+					resourceMapper: {
+						resourceMapperMethod: 'getScriptParameters',
+						mode: 'add',
+						fieldWords: { singular: 'parameter', plural: 'parameters' },
+						addAllFields: true,
+						multiKeyMatch: false,
+					},
+				},
+				displayOptions: { show: { operation: ['run'] } },
+			},
+			{
+				displayName: 'Wait for Completion',
+				name: 'waitForCompletion',
+				type: 'boolean',
+				default: true,
+				description:
+					'Whether to poll until the script finishes and output the result. When off, the node outputs the started run immediately.',
+				displayOptions: { show: { operation: ['run'] } },
+			},
+			{
+				displayName: 'Options',
+				name: 'options',
+				type: 'collection',
+				placeholder: 'Add Option',
+				default: {},
+				displayOptions: { show: { operation: ['run'] } },
+				options: [
+					{
+						displayName: 'Callback URL',
+						name: 'callbackUrl',
+						type: 'string',
+						default: '',
+						description:
+							'URL that receives the finished run as a POST, e.g. {{$execution.resumeUrl}} for a following Wait node',
+					},
+					{
+						displayName: 'Environment',
+						name: 'environment',
+						type: 'string',
+						default: '',
+						description: 'Execution environment name or tag',
+					},
+					{
+						displayName: 'Instance ID',
+						name: 'instanceId',
+						type: 'string',
+						default: '',
+						description: 'Browser instance to run on, default: the current instance',
+					},
+					{
+						displayName: 'Poll Interval (Seconds)',
+						name: 'pollInterval',
+						type: 'number',
+						typeOptions: { minValue: 1 },
+						default: 2,
+					},
+					{
+						displayName: 'Timeout (Seconds)',
+						name: 'timeout',
+						type: 'number',
+						typeOptions: { minValue: 0 },
+						default: 600,
+						description: 'Maximum time to wait for completion, 0 for no limit',
+					},
+				],
+			},
+			{
+				displayName: 'Run ID',
+				name: 'runId',
+				type: 'string',
+				required: true,
+				default: '',
+				displayOptions: { show: { operation: ['getRun'] } },
+			},
+		],
+	};
+
+	methods = {
+		loadOptions: {
+			async getScripts(this: ILoadOptionsFunctions): Promise<INodePropertyOptions[]> {
+				const scripts = (await apiRequest.call(this, 'GET', '/scripts')) as AutomationScript[];
+				return scripts.map((script) => ({
+					name: script.group ? `${script.group} / ${script.name}` : script.name,
+					value: script.id,
+					description: script.description,
+				}));
+			},
+		},
+		resourceMapping: {
+			async getScriptParameters(this: ILoadOptionsFunctions): Promise<ResourceMapperFields> {
+				const scriptId = this.getNodeParameter('scriptId', '') as string;
+				if (!scriptId) {
+					return { fields: [] };
+				}
+				const path = `/scripts/${encodeURIComponent(scriptId)}`;
+				const script = (await apiRequest.call(this, 'GET', path)) as AutomationScript;
+				const required = new Set(script.parameters.required ?? []);
+				const fields: ResourceMapperField[] = Object.entries(script.parameters.properties ?? {})
+					.sort(([a], [b]) => Number(required.has(b)) - Number(required.has(a)) || a.localeCompare(b))
+					.map(([name, schema]) => ({
+						id: name,
+						displayName: schema.description ? `${name} (${schema.description})` : name,
+						required: required.has(name),
+						defaultMatch: false,
+						canBeUsedToMatch: false,
+						display: true,
+						type: 'string',
+					}));
+				return { fields };
+			},
+		},
+	};
+
+	async execute(this: IExecuteFunctions): Promise<INodeExecutionData[][]> {
+		const items = this.getInputData();
+		const output: INodeExecutionData[] = [];
+
+		for (let i = 0; i < items.length; i++) {
+			try {
+				const operation = this.getNodeParameter('operation', i) as string;
+				let run: AutomationRun;
+
+				if (operation === 'getRun') {
+					const runId = this.getNodeParameter('runId', i) as string;
+					run = (await apiRequest.call(this, 'GET', `/runs/${encodeURIComponent(runId)}`)) as AutomationRun;
+				} else {
+					const scriptId = this.getNodeParameter('scriptId', i) as string;
+					const mapped = this.getNodeParameter('parameters', i) as { value: IDataObject | null };
+					const waitForCompletion = this.getNodeParameter('waitForCompletion', i) as boolean;
+					const options = this.getNodeParameter('options', i) as IDataObject;
+
+					// Parameters are passed as strings; empty values fall back to the script defaults
+					const params: Record<string, string> = {};
+					for (const [name, value] of Object.entries(mapped.value ?? {})) {
+						if (value !== null && value !== undefined && value !== '') {
+							params[name] = typeof value === 'string' ? value : JSON.stringify(value);
+						}
+					}
+					const body: IDataObject = { params };
+					if (options.environment) body.environment = options.environment;
+					if (options.instanceId) body.instance_id = options.instanceId;
+					if (options.callbackUrl) body.callback_url = options.callbackUrl;
+
+					const path = `/scripts/${encodeURIComponent(scriptId)}/runs`;
+					run = (await apiRequest.call(this, 'POST', path, body)) as AutomationRun;
+
+					if (waitForCompletion) {
+						const interval = ((options.pollInterval as number) ?? 2) * 1000;
+						const timeout = ((options.timeout as number) ?? 600) * 1000;
+						const deadline = timeout > 0 ? Date.now() + timeout : Infinity;
+						while (run.status === 'running') {
+							if (Date.now() >= deadline) {
+								throw new NodeOperationError(this.getNode(), `Run ${run.id} is still running after ${timeout / 1000}s`, {
+									itemIndex: i,
+								});
+							}
+							await sleep(interval);
+							run = (await apiRequest.call(this, 'GET', `/runs/${encodeURIComponent(run.id)}`)) as AutomationRun;
+						}
+						if (run.status === 'failed') {
+							throw new NodeOperationError(this.getNode(), `Script run failed: ${run.error ?? 'unknown error'}`, {
+								itemIndex: i,
+								description: `Run ID: ${run.id}`,
+							});
+						}
+					}
+				}
+
+				output.push({ json: run, pairedItem: { item: i } });
+			} catch (error) {
+				if (this.continueOnFail()) {
+					output.push({ json: { error: (error as Error).message }, pairedItem: { item: i } });
+					continue;
+				}
+				throw error;
+			}
+		}
+
+		return [output];
+	}
+}
@@ -0,0 +1,141 @@
+/**
+ * Node-RED nodes for the BrowserWing automation trigger API.
+ *
+ * - browserwing-server: connection settings (URL and API key)
+ * - browserwing-run: starts a script for each incoming message, polls until it
+ *   finishes and sends the run on the first output (succeeded) or the second
+ *   output (failed).
+ */
+module.exports = function (RED) {
+  async function apiRequest(server, method, path, body) {
+    const headers = { Accept: "application/json" };
+    if (server.credentials && server.credentials.apiKey) {
+      headers["X-BrowserWing-Key"] = server.credentials.apiKey;
+    }
+    if (body !== undefined) {
+      headers["Content-Type"] = "application/json";
+    }
+    const resp = await fetch(server.baseUrl + "/api/v1/automation" + path, {
+      method,
+      headers,
+      body: body === undefined ? undefined : JSON.stringify(body),
+    });
+    const text = await resp.text();
+    let decoded;
+    try {
+      decoded = text ? JSON.parse(text) : {};
+    } catch (e) {
+      decoded = { error: text };
+    }
+    if (!resp.ok) {
+      const detail = decoded.detail ? `: ${decoded.detail}` : "";
+      throw new Error(`BrowserWing returned ${resp.status} ${decoded.error || ""}${detail}`);
+    }
+    return decoded.data;
+  }
+
+  function BrowserWingServerNode(config) {
+    RED.nodes.createNode(this, config);
+    this.baseUrl = (config.baseUrl || "http://localhost:8080").replace(/\/+$/, "");
+  }
+  RED.nodes.registerType("browserwing-server", BrowserWingServerNode, {
+    credentials: { apiKey: { type: "password" } },
+  });
+
+  function BrowserWingRunNode(config) {
+    RED.nodes.createNode(this, config);
+    const node = this;
+    const server = RED.nodes.getNode(config.server);
+    const pollInterval = Math.max(Number(config.pollInterval) || 2, 1) * 1000;
+    const timeout = (Number(config.timeout) || 0) * 1000;
+    const timers = new Set();
+
+    const sleep = (ms) =>
+      new Promise((resolve) => {
+        const timer = setTimeout(() => {
+          timers.delete(timer);
+          resolve();
+        }, ms);
+        timers.add(timer);
+      });
+
+    node.on("input", async (msg, send, done) => {
+      if (!server) {
+        done(new Error("BrowserWing server is not configured"));
+        return;
+      }
+      // msg.scriptId overrides the configured script; msg.payload (object) supplies parameters
+      const scriptId = msg.scriptId || config.scriptId;
+      if (!scriptId) {
+        done(new Error("No script: set one in the node or pass msg.scriptId"));
+        return;
+      }
+      try {
+        const params = {};
+        const defaults = config.params ? JSON.parse(config.params) : {};
+        const source = Object.assign({}, defaults, msg.params || (isObject(msg.payload) ? msg.payload : {}));
+        for (const [name, value] of Object.entries(source)) {
+          if (value !== null && value !== undefined) {
+            params[name] = typeof value === "string" ? value : JSON.stringify(value);
+          }
+        }
+        const body = { params };
+        if (msg.environment || config.environment) body.environment = msg.environment || config.environment;
+        if (msg.instanceId || config.instanceId) body.instance_id = msg.instanceId || config.instanceId;
+        if (msg.callbackUrl) body.callback_url = msg.callbackUrl;
+
+        node.status({ fill: "blue", shape: "dot", text: "starting" });
+        let run = await apiRequest(server, "POST", `/scripts/${encodeURIComponent(scriptId)}/runs`, body);
+        if (config.wait !== false) {
+          const deadline = timeout > 0 ? Date.now() + timeout : Infinity;
+          node.status({ fill: "blue", shape: "ring", text: "running" });
+          while (run.status === "running") {
+            if (Date.now() >= deadline) {
+              throw new Error(`run ${run.id} still running after ${timeout / 1000}s`);
+            }
+            await sleep(pollInterval);
+            run = await apiRequest(server, "GET", `/runs/${encodeURIComponent(run.id)}`);
+          }
+        }
+
+        msg.payload = run;
+        msg.runId = run.id;
+        if (run.status === "failed") {
+          node.status({ fill: "red", shape: "dot", text: "failed" });
+          send([null, msg]);
+        } else {
+          node.status({ fill: "green", shape: "dot", text: run.status });
+          send([msg, null]);
+        }
+        done();
+      } catch (err) {
+        node.status({ fill: "red", shape: "ring", text: "error" });
+        done(err);
+      }
+    });
+
+    node.on("close", () => {
+      for (const timer of timers) clearTimeout(timer);
+      timers.clear();
+    });
+  }
+  RED.nodes.registerType("browserwing-run", BrowserWingRunNode);
+
+  // Script list for the editor dropdown, fetched with the selected server's settings
+  RED.httpAdmin.get("/browserwing/:server/scripts", RED.auth.needsPermission("browserwing-run.read"), async (req, res) => {
+    const server = RED.nodes.getNode(req.params.server);
+    if (!server) {
+      res.status(404).json({ error: "server not deployed" });
+      return;
+    }
+    try {
+      res.json(await apiRequest(server, "GET", "/scripts"));
+    } catch (err) {
+      res.status(502).json({ error: err.message });
+    }
+  });
+
+  function isObject(value) {
+    return value !== null && typeof value === "object" && !Array.isArray(value);
+  }
+};